@@ -13,6 +13,7 @@ type Cache interface {
 	GetBudget() int
 	Insert(key string, value interface{}, weight int) error
 	Retrieve(key string) (interface{}, bool)
+	Delete(key string) bool
 	Clear()
 }
 
@@ -52,6 +53,9 @@ func (c *cache) SetVerbose(verbose bool) {
 
 // GetWeight gets the "weight" of a cache
 func (c *cache) GetWeight() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
 	return c.weight
 }
 
@@ -145,6 +149,34 @@ func (c *cache) Retrieve(key string) (interface{}, bool) {
 	return node.value, true
 }
 
+// Delete removes an object from the cache, returning whether it existed
+func (c *cache) Delete(key string) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	node, found := c.lookup[key]
+	if !found {
+		return false
+	}
+
+	if node.prev != nil {
+		node.prev.next = node.next
+	} else {
+		c.head = node.next
+	}
+
+	if node.next != nil {
+		node.next.prev = node.prev
+	} else {
+		c.tail = node.prev
+	}
+
+	delete(c.lookup, key)
+	c.weight -= node.weight
+
+	return true
+}
+
 // Clear removes all cache entries
 func (c *cache) Clear() {
 	c.mutex.Lock()
@@ -91,3 +91,52 @@ func TestClear(t *testing.T) {
 		t.Fatal("Still able to retrieve nodes after cache was cleared")
 	}
 }
+
+func TestCacheDelete(t *testing.T) {
+	cache := NewCache(3)
+	_ = cache.Insert("A", "", 1)
+	_ = cache.Insert("deleted", "", 1)
+	_ = cache.Insert("B", "", 1)
+
+	if !cache.Delete("deleted") {
+		t.Fatal("Cache delete of existing key did not report it existed")
+	}
+	if cache.Delete("deleted") {
+		t.Fatal("Cache delete of missing key reported it existed")
+	}
+	if cache.GetWeight() != 2 {
+		t.Fatal("Cache delete did not update weight")
+	}
+
+	_, found := cache.Retrieve("deleted")
+	if found {
+		t.Fatal("Still able to retrieve node after it was deleted")
+	}
+
+	// The head and tail must remain consistent after deleting them
+	cache.Delete("B")
+	cache.Delete("A")
+	_ = cache.Insert("C", "", 1)
+	_ = cache.Insert("D", "", 1)
+
+	_, foundC := cache.Retrieve("C")
+	_, foundD := cache.Retrieve("D")
+	if !foundC || !foundD {
+		t.Fatal("Cache did not retain nodes inserted after deleting the head and tail")
+	}
+}
+
+func TestCacheDuplicateAllowedAfterDelete(t *testing.T) {
+	cache := NewCache(2)
+	_ = cache.Insert("key", "old", 1)
+	cache.Delete("key")
+
+	if err := cache.Insert("key", "new", 1); err != nil {
+		t.Fatalf("Cache insert after delete resulted in unexpected error: %s", err)
+	}
+
+	value, _ := cache.Retrieve("key")
+	if value != "new" {
+		t.Fatal("Cache did not retrieve value inserted after delete")
+	}
+}
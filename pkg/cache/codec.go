@@ -0,0 +1,34 @@
+package cache
+
+import (
+	"encoding/json"
+)
+
+// Codec serializes values for caches that store them outside of the process
+type Codec interface {
+	Marshal(value interface{}) ([]byte, error)
+	Unmarshal(data []byte) (interface{}, error)
+}
+
+type jsonCodec[T any] struct{}
+
+// NewJSONCodec returns a Codec that serializes values of type T as JSON.
+// Unmarshalled values are always of type T, so callers can type assert values
+// retrieved from the cache the same way they would with an in-process cache.
+func NewJSONCodec[T any]() Codec {
+	return jsonCodec[T]{}
+}
+
+// Marshal implements Codec.Marshal
+func (c jsonCodec[T]) Marshal(value interface{}) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+// Unmarshal implements Codec.Unmarshal
+func (c jsonCodec[T]) Unmarshal(data []byte) (interface{}, error) {
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
@@ -0,0 +1,57 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/code-payments/code-server/pkg/cache"
+)
+
+const (
+	subscriberBufferSize = 1024
+)
+
+type bus struct {
+	mu          sync.Mutex
+	subscribers map[chan *cache.Invalidation]struct{}
+}
+
+// NewInvalidationBus returns a new in memory cache.InvalidationBus, which
+// stands in for a broadcast mechanism across servers in tests
+func NewInvalidationBus() cache.InvalidationBus {
+	return &bus{
+		subscribers: make(map[chan *cache.Invalidation]struct{}),
+	}
+}
+
+// Publish implements cache.InvalidationBus.Publish
+func (b *bus) Publish(_ context.Context, invalidation *cache.Invalidation) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for subscriber := range b.subscribers {
+		cloned := *invalidation
+		subscriber <- &cloned
+	}
+	return nil
+}
+
+// Subscribe implements cache.InvalidationBus.Subscribe
+func (b *bus) Subscribe(ctx context.Context) (<-chan *cache.Invalidation, error) {
+	subscriber := make(chan *cache.Invalidation, subscriberBufferSize)
+
+	b.mu.Lock()
+	b.subscribers[subscriber] = struct{}{}
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		b.mu.Lock()
+		delete(b.subscribers, subscriber)
+		close(subscriber)
+		b.mu.Unlock()
+	}()
+
+	return subscriber, nil
+}
@@ -0,0 +1,86 @@
+package memory
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/code-payments/code-server/pkg/cache"
+)
+
+type entry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+type store struct {
+	mu      sync.Mutex
+	entries map[string]*entry
+}
+
+// NewStore returns a new in memory cache.RemoteStore, which stands in for a
+// shared store in tests
+func NewStore() cache.RemoteStore {
+	return &store{
+		entries: make(map[string]*entry),
+	}
+}
+
+// Add implements cache.RemoteStore.Add
+func (s *store) Add(_ context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.entries[key]
+	if ok && time.Now().Before(existing.expiresAt) {
+		return false, nil
+	}
+
+	s.entries[key] = &entry{
+		value:     append([]byte(nil), value...),
+		expiresAt: time.Now().Add(ttl),
+	}
+	return true, nil
+}
+
+// Get implements cache.RemoteStore.Get
+func (s *store) Get(_ context.Context, key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.entries[key]
+	if !ok || !time.Now().Before(existing.expiresAt) {
+		return nil, false, nil
+	}
+	return append([]byte(nil), existing.value...), true, nil
+}
+
+// Delete implements cache.RemoteStore.Delete
+func (s *store) Delete(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+	return nil
+}
+
+// DeletePrefix implements cache.RemoteStore.DeletePrefix
+func (s *store) DeletePrefix(_ context.Context, prefix string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key := range s.entries {
+		if strings.HasPrefix(key, prefix) {
+			delete(s.entries, key)
+		}
+	}
+	return nil
+}
+
+func (s *store) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries = make(map[string]*entry)
+}
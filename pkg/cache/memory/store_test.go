@@ -0,0 +1,19 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/code-payments/code-server/pkg/cache/tests"
+)
+
+func TestCacheMemoryStore(t *testing.T) {
+	testStore := NewStore()
+	teardown := func() {
+		testStore.(*store).reset()
+	}
+	tests.RunStoreTests(t, testStore, teardown)
+}
+
+func TestCacheMemoryInvalidationBus(t *testing.T) {
+	tests.RunInvalidationBusTests(t, NewInvalidationBus())
+}
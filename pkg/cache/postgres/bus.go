@@ -0,0 +1,138 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/code-payments/code-server/pkg/cache"
+)
+
+const (
+	invalidationBatchSize = 1000
+	invalidationRetention = 5 * time.Minute
+	invalidationCleanup   = time.Minute
+
+	// invalidationLookback is how far back each poll looks for invalidations.
+	// Ids are assigned on insert, not on commit, so a lower id can become
+	// visible after a higher one. The window must cover the time between an
+	// invalidation being timestamped and committed, plus clock skew between
+	// servers.
+	invalidationLookback = 10 * time.Second
+)
+
+type bus struct {
+	db           *sqlx.DB
+	pollInterval time.Duration
+}
+
+// NewInvalidationBus returns a new postgres cache.InvalidationBus. Invalidations
+// are written to a table, which subscribers poll at the provided interval, so
+// the interval bounds how long other servers can serve a stale local copy.
+func NewInvalidationBus(db *sql.DB, pollInterval time.Duration) cache.InvalidationBus {
+	return &bus{
+		db:           sqlx.NewDb(db, "pgx"),
+		pollInterval: pollInterval,
+	}
+}
+
+// Publish implements cache.InvalidationBus.Publish
+func (b *bus) Publish(ctx context.Context, invalidation *cache.Invalidation) error {
+	return toInvalidationModel(invalidation).dbPublish(ctx, b.db)
+}
+
+// Subscribe implements cache.InvalidationBus.Subscribe
+func (b *bus) Subscribe(ctx context.Context) (<-chan *cache.Invalidation, error) {
+	// Everything already visible within the lookback window was published
+	// before subscribing, so it's marked as seen up front
+	seen := make(map[int64]time.Time)
+	err := b.forEachInvalidationSince(ctx, time.Now().Add(-invalidationLookback), func(model *invalidationModel) error {
+		seen[model.Id.Int64] = model.CreatedAt
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	invalidations := make(chan *cache.Invalidation)
+	go b.poll(ctx, seen, invalidations)
+	return invalidations, nil
+}
+
+func (b *bus) poll(ctx context.Context, seen map[int64]time.Time, invalidations chan<- *cache.Invalidation) {
+	defer close(invalidations)
+
+	lastCleanup := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(b.pollInterval):
+		}
+
+		since := time.Now().Add(-invalidationLookback)
+
+		// Invalidations outside the window are never returned again, so they
+		// no longer need to be deduped
+		for id, createdAt := range seen {
+			if createdAt.Before(since) {
+				delete(seen, id)
+			}
+		}
+
+		err := b.forEachInvalidationSince(ctx, since, func(model *invalidationModel) error {
+			if _, ok := seen[model.Id.Int64]; ok {
+				return nil
+			}
+
+			select {
+			case invalidations <- fromInvalidationModel(model):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			seen[model.Id.Int64] = model.CreatedAt
+			return nil
+		})
+		if ctx.Err() != nil {
+			return
+		} else if err != nil {
+			log.Printf("warning -- failed to poll cache invalidations: %v", err)
+		}
+
+		// Every subscriber cleans up, since deletes are idempotent and there's
+		// no designated owner of the table
+		if time.Since(lastCleanup) > invalidationCleanup {
+			err := dbDeleteInvalidationsBefore(ctx, b.db, time.Now().Add(-invalidationRetention))
+			if err != nil && ctx.Err() == nil {
+				log.Printf("warning -- failed to clean up cache invalidations: %v", err)
+			}
+			lastCleanup = time.Now()
+		}
+	}
+}
+
+// forEachInvalidationSince pages through all visible invalidations created at
+// or after the provided time in id order
+func (b *bus) forEachInvalidationSince(ctx context.Context, since time.Time, fn func(*invalidationModel) error) error {
+	var cursor int64
+	for {
+		models, err := dbGetInvalidationsSince(ctx, b.db, since, cursor, invalidationBatchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, model := range models {
+			if err := fn(model); err != nil {
+				return err
+			}
+			cursor = model.Id.Int64
+		}
+
+		if len(models) < invalidationBatchSize {
+			return nil
+		}
+	}
+}
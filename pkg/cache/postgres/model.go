@@ -0,0 +1,143 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/code-payments/code-server/pkg/cache"
+)
+
+const (
+	entryTableName        = "codewallet__core_cacheentry"
+	invalidationTableName = "codewallet__core_cacheinvalidation"
+)
+
+type invalidationModel struct {
+	Id sql.NullInt64 `db:"id"`
+
+	Origin    string `db:"origin"`
+	Namespace string `db:"namespace"`
+	Key       string `db:"cache_key"`
+
+	CreatedAt time.Time `db:"created_at"`
+}
+
+func toInvalidationModel(obj *cache.Invalidation) *invalidationModel {
+	return &invalidationModel{
+		Origin:    obj.Origin,
+		Namespace: obj.Namespace,
+		Key:       obj.Key,
+	}
+}
+
+func fromInvalidationModel(obj *invalidationModel) *cache.Invalidation {
+	return &cache.Invalidation{
+		Origin:    obj.Origin,
+		Namespace: obj.Namespace,
+		Key:       obj.Key,
+	}
+}
+
+func dbAdd(ctx context.Context, db *sqlx.DB, key string, value []byte, ttl time.Duration) (bool, error) {
+	now := time.Now().UTC()
+
+	// Expired entries are overwritten in place, since they may not have been
+	// cleaned up yet
+	query := `INSERT INTO ` + entryTableName + `
+		(cache_key, value, expires_at)
+		VALUES ($1, $2, $3)
+
+		ON CONFLICT (cache_key)
+		DO UPDATE
+			SET value = $2, expires_at = $3
+			WHERE ` + entryTableName + `.cache_key = $1 AND ` + entryTableName + `.expires_at <= $4`
+
+	res, err := db.ExecContext(ctx, query, key, value, now.Add(ttl), now)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+func dbGet(ctx context.Context, db *sqlx.DB, key string) ([]byte, bool, error) {
+	var value []byte
+
+	query := `SELECT value FROM ` + entryTableName + `
+		WHERE cache_key = $1 AND expires_at > $2`
+
+	err := db.GetContext(ctx, &value, query, key, time.Now().UTC())
+	if err == sql.ErrNoRows {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+func dbDelete(ctx context.Context, db *sqlx.DB, key string) error {
+	query := `DELETE FROM ` + entryTableName + `
+		WHERE cache_key = $1`
+
+	_, err := db.ExecContext(ctx, query, key)
+	return err
+}
+
+func dbDeletePrefix(ctx context.Context, db *sqlx.DB, prefix string) error {
+	// Avoids LIKE, so prefixes don't need their wildcards escaped
+	query := `DELETE FROM ` + entryTableName + `
+		WHERE left(cache_key, length($1)) = $1`
+
+	_, err := db.ExecContext(ctx, query, prefix)
+	return err
+}
+
+func (m *invalidationModel) dbPublish(ctx context.Context, db *sqlx.DB) error {
+	if m.CreatedAt.IsZero() {
+		m.CreatedAt = time.Now()
+	}
+
+	query := `INSERT INTO ` + invalidationTableName + `
+		(origin, namespace, cache_key, created_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, origin, namespace, cache_key, created_at`
+
+	return db.QueryRowxContext(
+		ctx,
+		query,
+		m.Origin,
+		m.Namespace,
+		m.Key,
+		m.CreatedAt.UTC(),
+	).StructScan(m)
+}
+
+func dbGetInvalidationsSince(ctx context.Context, db *sqlx.DB, since time.Time, cursor int64, limit uint64) ([]*invalidationModel, error) {
+	res := []*invalidationModel{}
+
+	query := `SELECT id, origin, namespace, cache_key, created_at FROM ` + invalidationTableName + `
+		WHERE created_at >= $1 AND id > $2
+		ORDER BY id ASC
+		LIMIT $3`
+
+	err := db.SelectContext(ctx, &res, query, since.UTC(), cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func dbDeleteInvalidationsBefore(ctx context.Context, db *sqlx.DB, before time.Time) error {
+	query := `DELETE FROM ` + invalidationTableName + `
+		WHERE created_at < $1`
+
+	_, err := db.ExecContext(ctx, query, before.UTC())
+	return err
+}
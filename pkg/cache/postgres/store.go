@@ -0,0 +1,43 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/code-payments/code-server/pkg/cache"
+)
+
+type store struct {
+	db *sqlx.DB
+}
+
+// NewStore returns a new postgres cache.RemoteStore. Expired entries are
+// filtered on read and overwritten on insert.
+func NewStore(db *sql.DB) cache.RemoteStore {
+	return &store{
+		db: sqlx.NewDb(db, "pgx"),
+	}
+}
+
+// Add implements cache.RemoteStore.Add
+func (s *store) Add(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	return dbAdd(ctx, s.db, key, value, ttl)
+}
+
+// Get implements cache.RemoteStore.Get
+func (s *store) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	return dbGet(ctx, s.db, key)
+}
+
+// Delete implements cache.RemoteStore.Delete
+func (s *store) Delete(ctx context.Context, key string) error {
+	return dbDelete(ctx, s.db, key)
+}
+
+// DeletePrefix implements cache.RemoteStore.DeletePrefix
+func (s *store) DeletePrefix(ctx context.Context, prefix string) error {
+	return dbDeletePrefix(ctx, s.db, prefix)
+}
@@ -0,0 +1,171 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ory/dockertest/v3"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/code-payments/code-server/pkg/cache"
+	"github.com/code-payments/code-server/pkg/cache/tests"
+
+	postgrestest "github.com/code-payments/code-server/pkg/database/postgres/test"
+
+	_ "github.com/jackc/pgx/v4/stdlib"
+)
+
+var (
+	testStore cache.RemoteStore
+	testBus   cache.InvalidationBus
+	testDb    *sql.DB
+	teardown  func()
+)
+
+const (
+	// Used for testing ONLY, the table and migrations are external to this repository
+	tableCreate = `
+	CREATE TABLE codewallet__core_cacheentry (
+		cache_key TEXT NOT NULL PRIMARY KEY,
+
+		value BYTEA NOT NULL,
+
+		expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+	);
+
+	CREATE TABLE codewallet__core_cacheinvalidation (
+		id BIGSERIAL NOT NULL PRIMARY KEY,
+
+		origin TEXT NOT NULL,
+		namespace TEXT NOT NULL,
+		cache_key TEXT NOT NULL,
+
+		created_at TIMESTAMP WITH TIME ZONE NOT NULL
+	);
+	`
+
+	// Used for testing ONLY, the table and migrations are external to this repository
+	tableDestroy = `
+		DROP TABLE codewallet__core_cacheentry;
+		DROP TABLE codewallet__core_cacheinvalidation;
+	`
+)
+
+func TestMain(m *testing.M) {
+	log := logrus.StandardLogger()
+
+	testPool, err := dockertest.NewPool("")
+	if err != nil {
+		log.WithError(err).Error("Error creating docker pool")
+		os.Exit(1)
+	}
+
+	var cleanUpFunc func()
+	db, cleanUpFunc, err := postgrestest.StartPostgresDB(testPool)
+	if err != nil {
+		log.WithError(err).Error("Error starting postgres image")
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	if err := createTestTables(db); err != nil {
+		logrus.StandardLogger().WithError(err).Error("Error creating test tables")
+		cleanUpFunc()
+		os.Exit(1)
+	}
+
+	testDb = db
+	testStore = NewStore(db)
+	testBus = NewInvalidationBus(db, 10*time.Millisecond)
+	teardown = func() {
+		if pc := recover(); pc != nil {
+			cleanUpFunc()
+			panic(pc)
+		}
+
+		if err := resetTestTables(db); err != nil {
+			logrus.StandardLogger().WithError(err).Error("Error resetting test tables")
+			cleanUpFunc()
+			os.Exit(1)
+		}
+	}
+
+	code := m.Run()
+	cleanUpFunc()
+	os.Exit(code)
+}
+
+func TestCachePostgresStore(t *testing.T) {
+	tests.RunStoreTests(t, testStore, teardown)
+}
+
+func TestCachePostgresInvalidationBus(t *testing.T) {
+	tests.RunInvalidationBusTests(t, testBus)
+	teardown()
+}
+
+func TestCachePostgresInvalidationBus_OutOfOrderCommits(t *testing.T) {
+	defer teardown()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	invalidations, err := testBus.Subscribe(ctx)
+	require.NoError(t, err)
+
+	// Simulate an invalidation with a lower id committing after one with a
+	// higher id has already been observed
+	insert := func(id int64, key string) {
+		_, err := testDb.Exec(
+			`INSERT INTO codewallet__core_cacheinvalidation (id, origin, namespace, cache_key, created_at) VALUES ($1, 'origin', 'namespace', $2, $3)`,
+			id, key, time.Now().UTC(),
+		)
+		require.NoError(t, err)
+	}
+
+	insert(1000, "later")
+	assertInvalidation(t, invalidations, "later")
+
+	insert(999, "earlier")
+	assertInvalidation(t, invalidations, "earlier")
+
+	// Invalidations within the lookback window aren't redelivered
+	select {
+	case invalidation := <-invalidations:
+		t.Fatalf("unexpected invalidation for %s", invalidation.Key)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func assertInvalidation(t *testing.T, invalidations <-chan *cache.Invalidation, key string) {
+	select {
+	case invalidation := <-invalidations:
+		assert.Equal(t, key, invalidation.Key)
+	case <-time.After(time.Second):
+		t.Fatalf("timed out waiting for invalidation for %s", key)
+	}
+}
+
+func createTestTables(db *sql.DB) error {
+	_, err := db.Exec(tableCreate)
+	if err != nil {
+		logrus.StandardLogger().WithError(err).Error("could not create test tables")
+		return err
+	}
+	return nil
+}
+
+func resetTestTables(db *sql.DB) error {
+	_, err := db.Exec(tableDestroy)
+	if err != nil {
+		logrus.StandardLogger().WithError(err).Error("could not drop test tables")
+		return err
+	}
+
+	return createTestTables(db)
+}
@@ -0,0 +1,144 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+)
+
+const (
+	remoteCallTimeout = time.Second
+)
+
+// RemoteStore is a key-value store with expiring entries that's shared across
+// servers, like Redis or Postgres.
+type RemoteStore interface {
+	// Add stores a value under a key, unless the key has a value that hasn't
+	// expired. It returns whether the value was stored.
+	Add(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error)
+
+	// Get gets the value for a key, if it exists and hasn't expired
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+
+	// Delete deletes the value for a key
+	Delete(ctx context.Context, key string) error
+
+	// DeletePrefix deletes the values for all keys with the prefix
+	DeletePrefix(ctx context.Context, prefix string) error
+}
+
+// remoteCache is a Cache backed by a RemoteStore. Entries expire after a TTL
+// instead of being evicted by weight, so weights are ignored.
+type remoteCache struct {
+	store     RemoteStore
+	codec     Codec
+	namespace string
+	ttl       time.Duration
+	verbose   bool
+}
+
+// NewRemoteCache returns a new Cache that's shared across servers via the
+// remote store. Keys are scoped to the namespace, and values are serialized
+// with the codec and expire after the TTL.
+//
+// Remote failures are treated as cache misses on retrieval, since the Cache
+// interface is used for best-effort caching.
+func NewRemoteCache(store RemoteStore, codec Codec, namespace string, ttl time.Duration) Cache {
+	return &remoteCache{
+		store:     store,
+		codec:     codec,
+		namespace: namespace,
+		ttl:       ttl,
+	}
+}
+
+// SetVerbose turns on verbose printing of remote failures
+func (c *remoteCache) SetVerbose(verbose bool) {
+	c.verbose = verbose
+}
+
+// GetWeight always returns 0, since remote caches aren't bounded by weight
+func (c *remoteCache) GetWeight() int {
+	return 0
+}
+
+// GetBudget always returns 0, since remote caches aren't bounded by weight
+func (c *remoteCache) GetBudget() int {
+	return 0
+}
+
+// Insert inserts an object into the cache
+func (c *remoteCache) Insert(key string, value interface{}, weight int) error {
+	data, err := c.codec.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), remoteCallTimeout)
+	defer cancel()
+
+	added, err := c.store.Add(ctx, c.getRemoteKey(key), data, c.ttl)
+	if err != nil {
+		return err
+	} else if !added {
+		return errors.New("key already exists in cache")
+	}
+	return nil
+}
+
+// Retrieve gets an object out of the cache
+func (c *remoteCache) Retrieve(key string) (interface{}, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), remoteCallTimeout)
+	defer cancel()
+
+	data, found, err := c.store.Get(ctx, c.getRemoteKey(key))
+	if err != nil {
+		c.warn("warning -- remote cache failed to get %s: %v", key, err)
+		return nil, false
+	} else if !found {
+		return nil, false
+	}
+
+	value, err := c.codec.Unmarshal(data)
+	if err != nil {
+		c.warn("warning -- remote cache failed to unmarshal %s: %v", key, err)
+		return nil, false
+	}
+	return value, true
+}
+
+// Delete removes an object from the cache. The remote store doesn't report
+// whether the key existed, so this returns whether the delete succeeded.
+func (c *remoteCache) Delete(key string) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), remoteCallTimeout)
+	defer cancel()
+
+	err := c.store.Delete(ctx, c.getRemoteKey(key))
+	if err != nil {
+		c.warn("warning -- remote cache failed to delete %s: %v", key, err)
+		return false
+	}
+	return true
+}
+
+// Clear removes all cache entries in the namespace
+func (c *remoteCache) Clear() {
+	ctx, cancel := context.WithTimeout(context.Background(), remoteCallTimeout)
+	defer cancel()
+
+	err := c.store.DeletePrefix(ctx, c.getRemoteKey(""))
+	if err != nil {
+		c.warn("warning -- remote cache failed to clear %s namespace: %v", c.namespace, err)
+	}
+}
+
+func (c *remoteCache) getRemoteKey(key string) string {
+	return c.namespace + ":" + key
+}
+
+func (c *remoteCache) warn(format string, args ...interface{}) {
+	if c.verbose {
+		log.Printf(format, args...)
+	}
+}
@@ -0,0 +1,92 @@
+package cache_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/code-payments/code-server/pkg/cache"
+	"github.com/code-payments/code-server/pkg/cache/memory"
+)
+
+type testValue struct {
+	Name  string
+	Count int
+}
+
+func TestRemoteCache_HappyPath(t *testing.T) {
+	c := cache.NewRemoteCache(memory.NewStore(), cache.NewJSONCodec[testValue](), "namespace", time.Minute)
+
+	_, ok := c.Retrieve("key")
+	assert.False(t, ok)
+
+	expected := testValue{Name: "name", Count: 42}
+	require.NoError(t, c.Insert("key", expected, 1))
+	assert.Error(t, c.Insert("key", expected, 1))
+
+	actual, ok := c.Retrieve("key")
+	require.True(t, ok)
+	assert.Equal(t, expected, actual.(testValue))
+
+	assert.Equal(t, 0, c.GetWeight())
+	assert.Equal(t, 0, c.GetBudget())
+
+	assert.True(t, c.Delete("key"))
+	_, ok = c.Retrieve("key")
+	assert.False(t, ok)
+
+	require.NoError(t, c.Insert("key", expected, 1))
+}
+
+func TestRemoteCache_TTL(t *testing.T) {
+	c := cache.NewRemoteCache(memory.NewStore(), cache.NewJSONCodec[testValue](), "namespace", 100*time.Millisecond)
+
+	require.NoError(t, c.Insert("key", testValue{Name: "name"}, 1))
+
+	_, ok := c.Retrieve("key")
+	assert.True(t, ok)
+
+	time.Sleep(200 * time.Millisecond)
+
+	_, ok = c.Retrieve("key")
+	assert.False(t, ok)
+
+	require.NoError(t, c.Insert("key", testValue{Name: "name"}, 1))
+}
+
+func TestRemoteCache_Namespaces(t *testing.T) {
+	store := memory.NewStore()
+	c1 := cache.NewRemoteCache(store, cache.NewJSONCodec[testValue](), "namespace1", time.Minute)
+	c2 := cache.NewRemoteCache(store, cache.NewJSONCodec[testValue](), "namespace2", time.Minute)
+
+	require.NoError(t, c1.Insert("key", testValue{Name: "value1"}, 1))
+	require.NoError(t, c2.Insert("key", testValue{Name: "value2"}, 1))
+
+	actual, ok := c1.Retrieve("key")
+	require.True(t, ok)
+	assert.Equal(t, "value1", actual.(testValue).Name)
+
+	c1.Clear()
+
+	_, ok = c1.Retrieve("key")
+	assert.False(t, ok)
+
+	actual, ok = c2.Retrieve("key")
+	require.True(t, ok)
+	assert.Equal(t, "value2", actual.(testValue).Name)
+}
+
+func TestRemoteCache_SharedAcrossServers(t *testing.T) {
+	store := memory.NewStore()
+	c1 := cache.NewRemoteCache(store, cache.NewJSONCodec[testValue](), "namespace", time.Minute)
+	c2 := cache.NewRemoteCache(store, cache.NewJSONCodec[testValue](), "namespace", time.Minute)
+
+	require.NoError(t, c1.Insert("key", testValue{Name: "name"}, 1))
+	assert.Error(t, c2.Insert("key", testValue{Name: "name"}, 1))
+
+	actual, ok := c2.Retrieve("key")
+	require.True(t, ok)
+	assert.Equal(t, "name", actual.(testValue).Name)
+}
@@ -0,0 +1,172 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/code-payments/code-server/pkg/cache"
+)
+
+func RunStoreTests(t *testing.T, s cache.RemoteStore, teardown func()) {
+	for _, tf := range []func(t *testing.T, s cache.RemoteStore){
+		testStoreHappyPath,
+		testStoreExpiry,
+		testStoreDeletePrefix,
+	} {
+		tf(t, s)
+		teardown()
+	}
+}
+
+func RunInvalidationBusTests(t *testing.T, b cache.InvalidationBus) {
+	for _, tf := range []func(t *testing.T, b cache.InvalidationBus){
+		testInvalidationBusHappyPath,
+	} {
+		tf(t, b)
+	}
+}
+
+func testStoreHappyPath(t *testing.T, s cache.RemoteStore) {
+	t.Run("testStoreHappyPath", func(t *testing.T) {
+		ctx := context.Background()
+
+		_, found, err := s.Get(ctx, "key")
+		require.NoError(t, err)
+		assert.False(t, found)
+
+		added, err := s.Add(ctx, "key", []byte("value1"), time.Minute)
+		require.NoError(t, err)
+		assert.True(t, added)
+
+		added, err = s.Add(ctx, "key", []byte("value2"), time.Minute)
+		require.NoError(t, err)
+		assert.False(t, added)
+
+		actual, found, err := s.Get(ctx, "key")
+		require.NoError(t, err)
+		require.True(t, found)
+		assert.Equal(t, []byte("value1"), actual)
+
+		require.NoError(t, s.Delete(ctx, "key"))
+		require.NoError(t, s.Delete(ctx, "key"))
+
+		_, found, err = s.Get(ctx, "key")
+		require.NoError(t, err)
+		assert.False(t, found)
+
+		added, err = s.Add(ctx, "key", []byte("value2"), time.Minute)
+		require.NoError(t, err)
+		assert.True(t, added)
+
+		actual, found, err = s.Get(ctx, "key")
+		require.NoError(t, err)
+		require.True(t, found)
+		assert.Equal(t, []byte("value2"), actual)
+	})
+}
+
+func testStoreExpiry(t *testing.T, s cache.RemoteStore) {
+	t.Run("testStoreExpiry", func(t *testing.T) {
+		ctx := context.Background()
+
+		added, err := s.Add(ctx, "key", []byte("value1"), 100*time.Millisecond)
+		require.NoError(t, err)
+		assert.True(t, added)
+
+		_, found, err := s.Get(ctx, "key")
+		require.NoError(t, err)
+		assert.True(t, found)
+
+		time.Sleep(200 * time.Millisecond)
+
+		_, found, err = s.Get(ctx, "key")
+		require.NoError(t, err)
+		assert.False(t, found)
+
+		added, err = s.Add(ctx, "key", []byte("value2"), time.Minute)
+		require.NoError(t, err)
+		assert.True(t, added)
+
+		actual, found, err := s.Get(ctx, "key")
+		require.NoError(t, err)
+		require.True(t, found)
+		assert.Equal(t, []byte("value2"), actual)
+	})
+}
+
+func testStoreDeletePrefix(t *testing.T, s cache.RemoteStore) {
+	t.Run("testStoreDeletePrefix", func(t *testing.T) {
+		ctx := context.Background()
+
+		for _, key := range []string{"a:1", "a:2", "b:1", "a_1"} {
+			added, err := s.Add(ctx, key, []byte(key), time.Minute)
+			require.NoError(t, err)
+			require.True(t, added)
+		}
+
+		require.NoError(t, s.DeletePrefix(ctx, "a:"))
+
+		for _, key := range []string{"a:1", "a:2"} {
+			_, found, err := s.Get(ctx, key)
+			require.NoError(t, err)
+			assert.False(t, found)
+		}
+
+		for _, key := range []string{"b:1", "a_1"} {
+			actual, found, err := s.Get(ctx, key)
+			require.NoError(t, err)
+			require.True(t, found)
+			assert.Equal(t, []byte(key), actual)
+		}
+	})
+}
+
+func testInvalidationBusHappyPath(t *testing.T, b cache.InvalidationBus) {
+	t.Run("testInvalidationBusHappyPath", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		require.NoError(t, b.Publish(ctx, &cache.Invalidation{Origin: "origin", Namespace: "namespace", Key: "before"}))
+
+		var subscriptions []<-chan *cache.Invalidation
+		for i := 0; i < 2; i++ {
+			subscription, err := b.Subscribe(ctx)
+			require.NoError(t, err)
+			subscriptions = append(subscriptions, subscription)
+		}
+
+		expected := []*cache.Invalidation{
+			{Origin: "origin", Namespace: "namespace", Key: "key1"},
+			{Origin: "origin", Namespace: "namespace", Key: "key2"},
+			{Origin: "origin", Namespace: "namespace"},
+		}
+		for _, invalidation := range expected {
+			require.NoError(t, b.Publish(ctx, invalidation))
+		}
+
+		for _, subscription := range subscriptions {
+			for _, invalidation := range expected {
+				select {
+				case actual := <-subscription:
+					assert.Equal(t, invalidation, actual)
+				case <-time.After(5 * time.Second):
+					require.Fail(t, "timed out waiting for invalidation")
+				}
+			}
+		}
+
+		cancel()
+
+		for _, subscription := range subscriptions {
+			select {
+			case _, ok := <-subscription:
+				assert.False(t, ok)
+			case <-time.After(5 * time.Second):
+				require.Fail(t, "timed out waiting for subscription to close")
+			}
+		}
+	})
+}
@@ -0,0 +1,176 @@
+package cache
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Invalidation tells caches on other servers to drop their local copies of an
+// entry, or of every entry in a namespace when the key is empty
+type Invalidation struct {
+	Origin    string
+	Namespace string
+	Key       string
+}
+
+// InvalidationBus broadcasts invalidations to every server
+type InvalidationBus interface {
+	// Publish broadcasts an invalidation to all subscribers, including the
+	// publisher
+	Publish(ctx context.Context, invalidation *Invalidation) error
+
+	// Subscribe returns a channel that receives invalidations published after
+	// the call, until the context is cancelled
+	Subscribe(ctx context.Context) (<-chan *Invalidation, error)
+}
+
+type tieredEntry struct {
+	value     interface{}
+	expiresAt time.Time
+}
+
+// tieredCache is a Cache with a local tier in front of a remote tier. Writes go
+// through to the remote tier and are broadcast as invalidations, so other
+// servers drop stale local copies.
+type tieredCache struct {
+	id        string
+	local     Cache
+	remote    Cache
+	bus       InvalidationBus
+	namespace string
+	localTtl  time.Duration
+	verbose   bool
+}
+
+// NewTieredCache returns a new Cache that serves reads from the local cache
+// when possible, and falls back to the remote cache. Local copies expire after
+// the local TTL, which bounds staleness if an invalidation is missed, and are
+// dropped when another server writes to the same key in the namespace.
+// Invalidations are processed until the context is cancelled.
+func NewTieredCache(ctx context.Context, local, remote Cache, bus InvalidationBus, namespace string, localTtl time.Duration) (Cache, error) {
+	invalidations, err := bus.Subscribe(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &tieredCache{
+		id:        uuid.New().String(),
+		local:     local,
+		remote:    remote,
+		bus:       bus,
+		namespace: namespace,
+		localTtl:  localTtl,
+	}
+
+	go c.processInvalidations(invalidations)
+
+	return c, nil
+}
+
+// SetVerbose turns on verbose printing for both tiers
+func (c *tieredCache) SetVerbose(verbose bool) {
+	c.verbose = verbose
+	c.local.SetVerbose(verbose)
+	c.remote.SetVerbose(verbose)
+}
+
+// GetWeight gets the "weight" of the local tier
+func (c *tieredCache) GetWeight() int {
+	return c.local.GetWeight()
+}
+
+// GetBudget gets the memory budget of the local tier
+func (c *tieredCache) GetBudget() int {
+	return c.local.GetBudget()
+}
+
+// Insert inserts an object into both tiers
+func (c *tieredCache) Insert(key string, value interface{}, weight int) error {
+	err := c.remote.Insert(key, value, weight)
+	if err != nil {
+		return err
+	}
+
+	// The insert only succeeds when the remote tier has no value for the key,
+	// so any local copies on other servers are of an old value
+	c.publish(key)
+
+	c.local.Delete(key)
+	return c.local.Insert(key, c.newLocalEntry(value), weight)
+}
+
+// Retrieve gets an object out of the local tier, falling back to the remote tier
+func (c *tieredCache) Retrieve(key string) (interface{}, bool) {
+	cached, ok := c.local.Retrieve(key)
+	if ok {
+		entry := cached.(*tieredEntry)
+		if time.Now().Before(entry.expiresAt) {
+			return entry.value, true
+		}
+		c.local.Delete(key)
+	}
+
+	value, ok := c.remote.Retrieve(key)
+	if !ok {
+		return nil, false
+	}
+
+	// Weights aren't known for values from the remote tier
+	c.local.Insert(key, c.newLocalEntry(value), 1)
+	return value, true
+}
+
+// Delete removes an object from both tiers, and from local tiers on other
+// servers
+func (c *tieredCache) Delete(key string) bool {
+	c.local.Delete(key)
+	deleted := c.remote.Delete(key)
+	c.publish(key)
+	return deleted
+}
+
+// Clear removes all cache entries from both tiers, and from local tiers on
+// other servers
+func (c *tieredCache) Clear() {
+	c.local.Clear()
+	c.remote.Clear()
+	c.publish("")
+}
+
+func (c *tieredCache) newLocalEntry(value interface{}) *tieredEntry {
+	return &tieredEntry{
+		value:     value,
+		expiresAt: time.Now().Add(c.localTtl),
+	}
+}
+
+func (c *tieredCache) publish(key string) {
+	ctx, cancel := context.WithTimeout(context.Background(), remoteCallTimeout)
+	defer cancel()
+
+	err := c.bus.Publish(ctx, &Invalidation{
+		Origin:    c.id,
+		Namespace: c.namespace,
+		Key:       key,
+	})
+	if err != nil && c.verbose {
+		log.Printf("warning -- tiered cache failed to publish invalidation for %s: %v", key, err)
+	}
+}
+
+func (c *tieredCache) processInvalidations(invalidations <-chan *Invalidation) {
+	for invalidation := range invalidations {
+		if invalidation.Origin == c.id || invalidation.Namespace != c.namespace {
+			continue
+		}
+
+		if len(invalidation.Key) == 0 {
+			c.local.Clear()
+		} else {
+			c.local.Delete(invalidation.Key)
+		}
+	}
+}
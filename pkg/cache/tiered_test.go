@@ -0,0 +1,154 @@
+package cache_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/code-payments/code-server/pkg/cache"
+	"github.com/code-payments/code-server/pkg/cache/memory"
+)
+
+type tieredTestEnv struct {
+	store  cache.RemoteStore
+	bus    cache.InvalidationBus
+	local1 cache.Cache
+	local2 cache.Cache
+	c1     cache.Cache
+	c2     cache.Cache
+}
+
+func setupTieredTestEnv(t *testing.T, localTtl time.Duration) *tieredTestEnv {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	env := &tieredTestEnv{
+		store:  memory.NewStore(),
+		bus:    memory.NewInvalidationBus(),
+		local1: cache.NewCache(100),
+		local2: cache.NewCache(100),
+	}
+
+	var err error
+	env.c1, err = cache.NewTieredCache(ctx, env.local1, env.newRemote("namespace"), env.bus, "namespace", localTtl)
+	require.NoError(t, err)
+	env.c2, err = cache.NewTieredCache(ctx, env.local2, env.newRemote("namespace"), env.bus, "namespace", localTtl)
+	require.NoError(t, err)
+
+	return env
+}
+
+func (e *tieredTestEnv) newRemote(namespace string) cache.Cache {
+	return cache.NewRemoteCache(e.store, cache.NewJSONCodec[testValue](), namespace, time.Minute)
+}
+
+func TestTieredCache_HappyPath(t *testing.T) {
+	env := setupTieredTestEnv(t, time.Minute)
+
+	_, ok := env.c1.Retrieve("key")
+	assert.False(t, ok)
+
+	expected := testValue{Name: "name", Count: 42}
+	require.NoError(t, env.c1.Insert("key", expected, 1))
+	assert.Error(t, env.c1.Insert("key", expected, 1))
+	assert.Error(t, env.c2.Insert("key", expected, 1))
+
+	// Served from the local tier on the inserting server
+	actual, ok := env.c1.Retrieve("key")
+	require.True(t, ok)
+	assert.Equal(t, expected, actual)
+	assert.Equal(t, 1, env.c1.GetWeight())
+
+	// Served from the remote tier, then cached locally, on other servers
+	assert.Equal(t, 0, env.c2.GetWeight())
+	actual, ok = env.c2.Retrieve("key")
+	require.True(t, ok)
+	assert.Equal(t, expected, actual)
+	assert.Equal(t, 1, env.c2.GetWeight())
+	assert.Equal(t, 100, env.c2.GetBudget())
+}
+
+func TestTieredCache_InvalidatedByOtherServers(t *testing.T) {
+	env := setupTieredTestEnv(t, time.Minute)
+
+	require.NoError(t, env.c1.Insert("key", testValue{Name: "value1"}, 1))
+	_, ok := env.c2.Retrieve("key")
+	require.True(t, ok)
+
+	// Delete on one server drops the local copy on the other
+	assert.True(t, env.c1.Delete("key"))
+	assert.Eventually(t, func() bool {
+		_, ok := env.local2.Retrieve("key")
+		return !ok
+	}, time.Second, 10*time.Millisecond)
+	_, ok = env.c2.Retrieve("key")
+	assert.False(t, ok)
+
+	// Insert on one server replaces the stale local copy on the other
+	require.NoError(t, env.c1.Insert("key", testValue{Name: "value2"}, 1))
+	_, ok = env.c2.Retrieve("key")
+	require.True(t, ok)
+	env.c2.Delete("key")
+	require.NoError(t, env.c2.Insert("key", testValue{Name: "value3"}, 1))
+	assert.Eventually(t, func() bool {
+		actual, ok := env.c1.Retrieve("key")
+		return ok && actual.(testValue).Name == "value3"
+	}, time.Second, 10*time.Millisecond)
+
+	// Clear on one server drops all local copies on the other
+	require.NoError(t, env.c1.Insert("other", testValue{Name: "other"}, 1))
+	_, ok = env.c2.Retrieve("other")
+	require.True(t, ok)
+	env.c1.Clear()
+	assert.Eventually(t, func() bool {
+		return env.local2.GetWeight() == 0
+	}, time.Second, 10*time.Millisecond)
+	for _, key := range []string{"key", "other"} {
+		_, ok = env.c2.Retrieve(key)
+		assert.False(t, ok)
+	}
+}
+
+func TestTieredCache_IgnoresOtherNamespaces(t *testing.T) {
+	env := setupTieredTestEnv(t, time.Minute)
+
+	other, err := cache.NewTieredCache(context.Background(), cache.NewCache(100), env.newRemote("other"), env.bus, "other", time.Minute)
+	require.NoError(t, err)
+
+	require.NoError(t, env.c1.Insert("key", testValue{Name: "name"}, 1))
+	other.Clear()
+	other.Delete("key")
+
+	time.Sleep(100 * time.Millisecond)
+
+	_, ok := env.local1.Retrieve("key")
+	assert.True(t, ok)
+	_, ok = env.c1.Retrieve("key")
+	assert.True(t, ok)
+}
+
+func TestTieredCache_LocalTTL(t *testing.T) {
+	env := setupTieredTestEnv(t, 100*time.Millisecond)
+
+	require.NoError(t, env.c1.Insert("key", testValue{Name: "value1"}, 1))
+	_, ok := env.c2.Retrieve("key")
+	require.True(t, ok)
+
+	// Simulate a missed invalidation by changing the remote tier directly
+	remote := env.newRemote("namespace")
+	require.True(t, remote.Delete("key"))
+	require.NoError(t, remote.Insert("key", testValue{Name: "value2"}, 1))
+
+	actual, ok := env.c2.Retrieve("key")
+	require.True(t, ok)
+	assert.Equal(t, "value1", actual.(testValue).Name)
+
+	time.Sleep(200 * time.Millisecond)
+
+	actual, ok = env.c2.Retrieve("key")
+	require.True(t, ok)
+	assert.Equal(t, "value2", actual.(testValue).Name)
+}
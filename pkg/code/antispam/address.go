@@ -1,11 +0,0 @@
-package antispam
-
-import (
-	"github.com/code-payments/code-server/pkg/code/common"
-)
-
-// todo: Put in a DB somehwere? Or make configurable?
-// todo: Needs tests
-func isExternalAddressBanned(account *common.Account) bool {
-	return false
-}
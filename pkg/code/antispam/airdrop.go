@@ -2,11 +2,13 @@ package antispam
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/sirupsen/logrus"
 
 	"github.com/code-payments/code-server/pkg/code/common"
+	"github.com/code-payments/code-server/pkg/code/data/campaign"
 	"github.com/code-payments/code-server/pkg/code/data/intent"
 	"github.com/code-payments/code-server/pkg/code/data/phone"
 	"github.com/code-payments/code-server/pkg/code/data/user/identity"
@@ -19,25 +21,48 @@ var (
 	deviceCheckV2ReleaseDate = time.Date(2023, time.October, 26, 0, 0, 0, 0, time.UTC)
 )
 
-// AllowWelcomeBonus determines whether a phone-verified owner account can receive
-// the welcome bonus. The objective here is to limit attacks against our airdropper's
-// Kin balance.
-//
-// todo: This needs tests
-func (g *Guard) AllowWelcomeBonus(ctx context.Context, owner *common.Account) (bool, error) {
-	tracer := metrics.TraceMethodCall(ctx, metricsStructName, "AllowWelcomeBonus")
+// AllowCampaignPayout determines whether a phone-verified owner account can receive
+// a payout from a campaign. On top of the campaign's eligibility rules, the objective
+// here is to limit attacks against our airdropper's Kin balance.
+func (g *Guard) AllowCampaignPayout(ctx context.Context, owner *common.Account, campaignRecord *campaign.Record) (bool, error) {
+	tracer := metrics.TraceMethodCall(ctx, metricsStructName, "AllowCampaignPayout")
 	defer tracer.End()
 
 	log := g.log.WithFields(logrus.Fields{
-		"method": "AllowWelcomeBonus",
-		"owner":  owner.PublicKey().ToBase58(),
+		"method":   "AllowCampaignPayout",
+		"owner":    owner.PublicKey().ToBase58(),
+		"campaign": campaignRecord.Name,
 	})
 	log = client.InjectLoggingMetadata(ctx, log)
 
+	// Deny payouts outside the campaign's date window, or when it's been disabled
+	if !campaignRecord.IsActive(time.Now()) {
+		log.Info("campaign is not active")
+		recordDenialEvent(ctx, actionCampaignPayout, "campaign inactive")
+		return false, nil
+	}
+
 	// Deny abusers from known IPs
-	if isIpBanned(ctx) {
+	isIpBanned, err := g.isIpBanned(ctx)
+	if err != nil {
+		tracer.OnError(err)
+		log.WithError(err).Warn("failure checking ip ban list")
+		return false, err
+	} else if isIpBanned {
 		log.Info("ip is banned")
-		recordDenialEvent(ctx, actionWelcomeBonus, "ip banned")
+		recordDenialEvent(ctx, actionCampaignPayout, "ip banned")
+		return false, nil
+	}
+
+	// Deny clients on datacenter and anonymizing networks
+	isHostingIp, err := g.isHostingIp(ctx)
+	if err != nil {
+		tracer.OnError(err)
+		log.WithError(err).Warn("failure checking ip metadata")
+		return false, err
+	} else if isHostingIp {
+		log.Info("ip is a hosting provider")
+		recordDenialEvent(ctx, actionCampaignPayout, "hosting ip")
 		return false, nil
 	}
 
@@ -45,7 +70,7 @@ func (g *Guard) AllowWelcomeBonus(ctx context.Context, owner *common.Account) (b
 	if err == phone.ErrVerificationNotFound {
 		// Owner account was never phone verified, so deny the action.
 		log.Info("owner account is not phone verified")
-		recordDenialEvent(ctx, actionWelcomeBonus, "not phone verified")
+		recordDenialEvent(ctx, actionCampaignPayout, "not phone verified")
 		return false, nil
 	} else if err != nil {
 		tracer.OnError(err)
@@ -55,26 +80,32 @@ func (g *Guard) AllowWelcomeBonus(ctx context.Context, owner *common.Account) (b
 
 	log = log.WithField("phone", verification.PhoneNumber)
 
-	if g.isSuspiciousWelcomeBonus(ctx, verification.PhoneNumber) {
-		log.Info("denying suspicious welcome bonus")
-		recordDenialEvent(ctx, actionWelcomeBonus, "suspicious welcome bonus")
+	if g.isSuspiciousCampaignPayout(ctx, verification.PhoneNumber) {
+		log.Info("denying suspicious campaign payout")
+		recordDenialEvent(ctx, actionCampaignPayout, "suspicious campaign payout")
 		return false, nil
 	}
 
 	// Deny abusers from known phone ranges
-	if hasBannedPhoneNumberPrefix(verification.PhoneNumber) {
+	isPhoneNumberBanned, err := g.isPhoneNumberBanned(ctx, verification.PhoneNumber)
+	if err != nil {
+		tracer.OnError(err)
+		log.WithError(err).Warn("failure checking phone ban list")
+		return false, err
+	} else if isPhoneNumberBanned {
 		log.Info("denying phone prefix")
-		recordDenialEvent(ctx, actionWelcomeBonus, "phone prefix banned")
+		recordDenialEvent(ctx, actionCampaignPayout, "phone prefix banned")
 		return false, nil
 	}
 
+	userCreatedAt := verification.CreatedAt
 	user, err := g.data.GetUserByPhoneView(ctx, verification.PhoneNumber)
 	switch err {
 	case nil:
 		// Deny banned users forever
 		if user.IsBanned {
 			log.Info("denying banned user")
-			recordDenialEvent(ctx, actionWelcomeBonus, "user banned")
+			recordDenialEvent(ctx, actionCampaignPayout, "user banned")
 			return false, nil
 		}
 
@@ -82,6 +113,8 @@ func (g *Guard) AllowWelcomeBonus(ctx context.Context, owner *common.Account) (b
 		if user.IsStaffUser {
 			return true, nil
 		}
+
+		userCreatedAt = user.CreatedAt
 	case identity.ErrNotFound:
 	default:
 		tracer.OnError(err)
@@ -89,6 +122,33 @@ func (g *Guard) AllowWelcomeBonus(ctx context.Context, owner *common.Account) (b
 		return false, err
 	}
 
+	rules := campaignRecord.EligibilityRules
+
+	if !strings.HasPrefix(verification.PhoneNumber, rules.PhonePrefix) {
+		log.Info("phone number is outside the campaign's region")
+		recordDenialEvent(ctx, actionCampaignPayout, "phone prefix ineligible")
+		return false, nil
+	}
+
+	if rules.MaxAccountAge > 0 && time.Since(userCreatedAt) > rules.MaxAccountAge {
+		log.Info("user is too old for the campaign")
+		recordDenialEvent(ctx, actionCampaignPayout, "account age ineligible")
+		return false, nil
+	}
+
+	count, err := g.data.GetCampaignPayoutCountByPhoneNumber(ctx, campaignRecord.Name, verification.PhoneNumber)
+	if err != nil {
+		tracer.OnError(err)
+		log.WithError(err).Warn("failure getting campaign payout count")
+		return false, err
+	}
+
+	if count >= rules.MaxPayoutsPerPhoneNumber {
+		log.Info("phone number has received the maximum number of campaign payouts")
+		recordDenialEvent(ctx, actionCampaignPayout, "phone payout limit exceeded")
+		return false, nil
+	}
+
 	phoneEvent, err := g.data.GetLatestPhoneEventForNumberByType(ctx, verification.PhoneNumber, phone.EventTypeVerificationCodeSent)
 	switch err {
 	case nil:
@@ -96,7 +156,7 @@ func (g *Guard) AllowWelcomeBonus(ctx context.Context, owner *common.Account) (b
 		if phoneEvent.PhoneMetadata.MobileCountryCode != nil {
 			if _, ok := g.conf.restrictedMobileCountryCodes[*phoneEvent.PhoneMetadata.MobileCountryCode]; ok {
 				log.WithField("region", *phoneEvent.PhoneMetadata.MobileCountryCode).Info("region is restricted")
-				recordDenialEvent(ctx, actionWelcomeBonus, "region restricted")
+				recordDenialEvent(ctx, actionCampaignPayout, "region restricted")
 				return false, nil
 			}
 		}
@@ -105,7 +165,7 @@ func (g *Guard) AllowWelcomeBonus(ctx context.Context, owner *common.Account) (b
 		if phoneEvent.PhoneMetadata.MobileNetworkCode != nil {
 			if _, ok := g.conf.restrictedMobileNetworkCodes[*phoneEvent.PhoneMetadata.MobileNetworkCode]; ok {
 				log.WithField("mobile_network", *phoneEvent.PhoneMetadata.MobileNetworkCode).Info("mobile network is restricted")
-				recordDenialEvent(ctx, actionWelcomeBonus, "mobile network restricted")
+				recordDenialEvent(ctx, actionCampaignPayout, "mobile network restricted")
 				return false, nil
 			}
 		}
@@ -119,7 +179,7 @@ func (g *Guard) AllowWelcomeBonus(ctx context.Context, owner *common.Account) (b
 }
 
 // Special rules based on observed behaviour
-func (g *Guard) isSuspiciousWelcomeBonus(ctx context.Context, phoneNumber string) bool {
+func (g *Guard) isSuspiciousCampaignPayout(ctx context.Context, phoneNumber string) bool {
 	return false
 }
 
@@ -149,7 +209,7 @@ func (g *Guard) AllowReferralBonus(
 	})
 	log = client.InjectLoggingMetadata(ctx, log)
 
-	if quarksGivenByReferrer < g.conf.minReferralAmount {
+	if quarksGivenByReferrer < g.conf.getMinReferralAmount(ctx) {
 		log.Info("insufficient quarks given by referrer")
 		recordDenialEvent(ctx, actionReferralBonus, "insufficient quarks given by referrer")
 		return false, nil
@@ -171,7 +231,12 @@ func (g *Guard) AllowReferralBonus(
 		log := log.WithField("phone", verification.PhoneNumber)
 
 		// Deny abusers from known phone ranges
-		if hasBannedPhoneNumberPrefix(verification.PhoneNumber) {
+		isPhoneNumberBanned, err := g.isPhoneNumberBanned(ctx, verification.PhoneNumber)
+		if err != nil {
+			tracer.OnError(err)
+			log.WithError(err).Warn("failure checking phone ban list")
+			return false, err
+		} else if isPhoneNumberBanned {
 			log.Info("denying phone prefix")
 			recordDenialEvent(ctx, actionReferralBonus, "phone prefix banned")
 			return false, nil
@@ -251,7 +316,7 @@ func (g *Guard) AllowReferralBonus(
 		return false, err
 	}
 
-	if count >= g.conf.maxReferralsPerDay {
+	if count >= g.conf.getMaxReferralsPerDay(ctx) {
 		log.Info("phone is rate limited by daily referral bonus count")
 		recordDenialEvent(ctx, actionReferralBonus, "daily limit exceeded")
 		return false, nil
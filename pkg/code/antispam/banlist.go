@@ -0,0 +1,237 @@
+package antispam
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/code-payments/code-server/pkg/code/common"
+	code_data "github.com/code-payments/code-server/pkg/code/data"
+	"github.com/code-payments/code-server/pkg/code/data/banlist"
+)
+
+// SanctionedPhonePrefixes are the phone number prefixes for sanctioned countries.
+// They're always banned, regardless of the contents of the ban list, which can
+// only add to them.
+//
+// todo: Probably doesn't belong in an antispam package, but it's just a
+//       convenient place for now
+var SanctionedPhonePrefixes = map[string]string{
+	"+7":   "Russia",
+	"+30":  "Greece (Balkans)",
+	"+40":  "Romania (Balkans)",
+	"+53":  "Cuba",
+	"+90":  "Turkey (Balkans)",
+	"+95":  "Myanmar (Burma)",
+	"+98":  "Iran",
+	"+225": "Ivory Coast",
+	"+231": "Liberia",
+	"+243": "Democratic Republic of Congo",
+	"+249": "Sudan",
+	"+263": "Zimbabwe",
+	"+355": "Albania (Balkans)",
+	"+359": "Bulgaria (Balkans)",
+	"+375": "Belarus",
+	"+381": "Serbia (Balkans)",
+	"+382": "Montenegro (Balkans)",
+	"+383": "Kosovo (Balkans)",
+	"+385": "Croatia (Balkans)",
+	"+386": "Slovenia (Balkans)",
+	"+387": "Bosnia and Herzegovina (Balkans)",
+	"+389": "North Macedonia (Balkans)",
+	"+850": "North Korea",
+	"+963": "Syria",
+	"+964": "Iraq",
+}
+
+type ipRangeEntry struct {
+	record *banlist.Record
+	ipNet  *net.IPNet
+}
+
+// banListReader is a cached reader for the ban list. Active entries are
+// periodically loaded in full, since the ban list is expected to be small and
+// is consulted on nearly every guarded action.
+type banListReader struct {
+	log             *logrus.Entry
+	data            code_data.Provider
+	refreshInterval time.Duration
+
+	// Serializes reloads, so readers aren't blocked on the DB
+	refreshMu sync.Mutex
+
+	mu            sync.Mutex
+	lastRefresh   time.Time
+	loaded        bool
+	phonePrefixes []*banlist.Record
+	ipRanges      []*ipRangeEntry
+	addresses     map[string]*banlist.Record
+}
+
+func newBanListReader(data code_data.Provider, refreshInterval time.Duration) *banListReader {
+	return &banListReader{
+		log:             logrus.StandardLogger().WithField("type", "antispam/banlist"),
+		data:            data,
+		refreshInterval: refreshInterval,
+		addresses:       make(map[string]*banlist.Record),
+	}
+}
+
+// isPhoneNumberBanned determines whether a phone number has a banned prefix
+func (g *Guard) isPhoneNumberBanned(ctx context.Context, phoneNumber string) (bool, error) {
+	for prefix := range SanctionedPhonePrefixes {
+		if strings.HasPrefix(phoneNumber, prefix) {
+			return true, nil
+		}
+	}
+
+	r := g.banList
+
+	err := r.maybeRefresh(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for _, entry := range r.phonePrefixes {
+		if !entry.IsExpired(now) && strings.HasPrefix(phoneNumber, entry.Value) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// isIpBanned determines whether the client IP is within a banned range
+func (g *Guard) isIpBanned(ctx context.Context) (bool, error) {
+	r := g.banList
+
+	// Nothing to check when the IP isn't known
+	ip, ok := getClientIp(ctx)
+	if !ok {
+		return false, nil
+	}
+
+	err := r.maybeRefresh(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for _, entry := range r.ipRanges {
+		if !entry.record.IsExpired(now) && entry.ipNet.Contains(ip) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// isExternalAddressBanned determines whether an external address is banned
+func (g *Guard) isExternalAddressBanned(ctx context.Context, account *common.Account) (bool, error) {
+	r := g.banList
+
+	err := r.maybeRefresh(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.addresses[account.PublicKey().ToBase58()]
+	return ok && !entry.IsExpired(time.Now()), nil
+}
+
+// maybeRefresh reloads the ban list when the refresh interval has elapsed. On
+// failure, the previously loaded ban list continues to be used, and an error
+// is only returned when nothing has been loaded.
+//
+// Entries are loaded outside the reader lock and swapped in afterwards. While
+// a reload is in progress, other callers use the previously loaded entries.
+func (r *banListReader) maybeRefresh(ctx context.Context) error {
+	r.mu.Lock()
+	isFresh := r.isFresh()
+	loaded := r.loaded
+	r.mu.Unlock()
+
+	if isFresh {
+		return nil
+	}
+
+	if loaded {
+		if !r.refreshMu.TryLock() {
+			return nil
+		}
+	} else {
+		r.refreshMu.Lock()
+	}
+	defer r.refreshMu.Unlock()
+
+	// Another caller may have completed a reload while we were waiting
+	r.mu.Lock()
+	isFresh = r.isFresh()
+	loaded = r.loaded
+	r.mu.Unlock()
+
+	if isFresh {
+		return nil
+	}
+
+	records, err := r.data.GetAllActiveBanListEntries(ctx, time.Now())
+	if err != nil && err != banlist.ErrEntryNotFound {
+		if !loaded {
+			return err
+		}
+
+		// Avoid retrying on every call
+		r.mu.Lock()
+		r.lastRefresh = time.Now()
+		r.mu.Unlock()
+
+		r.log.WithError(err).Warn("failure refreshing ban list, using stale entries")
+		return nil
+	}
+
+	var phonePrefixes []*banlist.Record
+	var ipRanges []*ipRangeEntry
+	addresses := make(map[string]*banlist.Record)
+	for _, record := range records {
+		switch record.EntryType {
+		case banlist.PhonePrefixEntryType:
+			phonePrefixes = append(phonePrefixes, record)
+		case banlist.IpRangeEntryType:
+			_, ipNet, err := net.ParseCIDR(record.Value)
+			if err != nil {
+				r.log.WithError(err).WithField("value", record.Value).Warn("ignoring invalid ip range")
+				continue
+			}
+			ipRanges = append(ipRanges, &ipRangeEntry{record: record, ipNet: ipNet})
+		case banlist.ExternalAddressEntryType:
+			addresses[record.Value] = record
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.phonePrefixes = phonePrefixes
+	r.ipRanges = ipRanges
+	r.addresses = addresses
+	r.lastRefresh = time.Now()
+	r.loaded = true
+	return nil
+}
+
+// isFresh must be called with mu held
+func (r *banListReader) isFresh() bool {
+	return r.loaded && time.Since(r.lastRefresh) < r.refreshInterval
+}
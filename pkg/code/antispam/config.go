@@ -1,8 +1,11 @@
 package antispam
 
 import (
+	"context"
+	"strconv"
 	"time"
 
+	"github.com/code-payments/code-server/pkg/config"
 	"github.com/code-payments/code-server/pkg/kin"
 )
 
@@ -22,8 +25,45 @@ const (
 
 	defaultMaxNewRelationshipsPerDay = 50
 
+	defaultMaxContactDiscoveryLookupsPerDay = 1024
+
 	defaultMinReferralAmount  = 100 * kin.QuarksPerKin
 	defaultMaxReferralsPerDay = 10
+
+	defaultSpamConfidenceThreshold = 0.8
+	defaultHighRiskEventWindow     = 24 * time.Hour
+	defaultBlockHighRiskEvents     = false
+
+	defaultBanListRefreshInterval = time.Minute
+
+	defaultDenyHostingIps = true
+)
+
+// Keys for limits that can be overridden live via WithDynamicConfigs
+const (
+	dynamicConfigPrefix = "ANTISPAM_"
+
+	PaymentsPerDayConfigKey         = dynamicConfigPrefix + "PAYMENTS_PER_DAY"
+	PaymentsPerHourConfigKey        = dynamicConfigPrefix + "PAYMENTS_PER_HOUR"
+	PaymentsPerFiveMinutesConfigKey = dynamicConfigPrefix + "PAYMENTS_PER_FIVE_MINUTES"
+
+	PhoneVerificationIntervalConfigKey       = dynamicConfigPrefix + "PHONE_VERIFICATION_INTERVAL"
+	PhoneVerificationsPerIntervalConfigKey   = dynamicConfigPrefix + "PHONE_VERIFICATIONS_PER_INTERVAL"
+	TimePerSmsVerificationCodeSendConfigKey  = dynamicConfigPrefix + "TIME_PER_SMS_VERIFICATION_CODE_SEND"
+	TimePerSmsVerificationCodeCheckConfigKey = dynamicConfigPrefix + "TIME_PER_SMS_VERIFICATION_CODE_CHECK"
+
+	MaxNewRelationshipsPerDayConfigKey = dynamicConfigPrefix + "MAX_NEW_RELATIONSHIPS_PER_DAY"
+
+	MaxContactDiscoveryLookupsPerDayConfigKey = dynamicConfigPrefix + "MAX_CONTACT_DISCOVERY_LOOKUPS_PER_DAY"
+
+	MinReferralAmountConfigKey  = dynamicConfigPrefix + "MIN_REFERRAL_AMOUNT"
+	MaxReferralsPerDayConfigKey = dynamicConfigPrefix + "MAX_REFERRALS_PER_DAY"
+
+	SpamConfidenceThresholdConfigKey = dynamicConfigPrefix + "SPAM_CONFIDENCE_THRESHOLD"
+	HighRiskEventWindowConfigKey     = dynamicConfigPrefix + "HIGH_RISK_EVENT_WINDOW"
+	BlockHighRiskEventsConfigKey     = dynamicConfigPrefix + "BLOCK_HIGH_RISK_EVENTS"
+
+	DenyHostingIpsConfigKey = dynamicConfigPrefix + "DENY_HOSTING_IPS"
 )
 
 type conf struct {
@@ -39,11 +79,24 @@ type conf struct {
 
 	maxNewRelationshipsPerDay uint64
 
+	maxContactDiscoveryLookupsPerDay uint64
+
 	minReferralAmount  uint64
 	maxReferralsPerDay uint64
 
+	eventScorer             EventScorer
+	spamConfidenceThreshold float64
+	highRiskEventWindow     time.Duration
+	blockHighRiskEvents     bool
+
+	banListRefreshInterval time.Duration
+
+	denyHostingIps bool
+
 	restrictedMobileCountryCodes map[int]struct{}
 	restrictedMobileNetworkCodes map[int]struct{}
+
+	dynamic config.Source
 }
 
 // Option configures a Guard with an overrided configuration value
@@ -128,6 +181,14 @@ func WithMaxNewRelationshipsPerDay(limit uint64) Option {
 	}
 }
 
+// WithMaxContactDiscoveryLookupsPerDay overrides the default maximum number of contact
+// discovery hash prefixes an owner account can look up per day.
+func WithMaxContactDiscoveryLookupsPerDay(limit uint64) Option {
+	return func(c *conf) {
+		c.maxContactDiscoveryLookupsPerDay = limit
+	}
+}
+
 // WithMinReferralAmount overrides the default minimum referral amount. The value specifies
 // the minimum amount that must be given to a new user to consider a referral bonus.
 func WithMinReferralAmount(amount uint64) Option {
@@ -144,6 +205,56 @@ func WithMaxReferralsPerDay(limit uint64) Option {
 	}
 }
 
+// WithEventScorer sets the scorer used to populate the spam confidence of events
+// prior to them being saved. Events aren't scored when no scorer is configured.
+func WithEventScorer(scorer EventScorer) Option {
+	return func(c *conf) {
+		c.eventScorer = scorer
+	}
+}
+
+// WithSpamConfidenceThreshold overrides the default spam confidence threshold. The
+// value specifies the minimum spam confidence at which an event is considered high
+// risk.
+func WithSpamConfidenceThreshold(threshold float64) Option {
+	return func(c *conf) {
+		c.spamConfidenceThreshold = threshold
+	}
+}
+
+// WithHighRiskEventWindow overrides the default high risk event window. The value
+// specifies how far back high risk events are considered when evaluating a phone
+// number.
+func WithHighRiskEventWindow(d time.Duration) Option {
+	return func(c *conf) {
+		c.highRiskEventWindow = d
+	}
+}
+
+// WithHighRiskEventBlocking overrides whether phone numbers with recent high risk
+// events are blocked. When disabled, they're only flagged for review.
+func WithHighRiskEventBlocking(enabled bool) Option {
+	return func(c *conf) {
+		c.blockHighRiskEvents = enabled
+	}
+}
+
+// WithBanListRefreshInterval overrides the default ban list refresh interval. The
+// value specifies how long ban list entries are cached before being reloaded.
+func WithBanListRefreshInterval(d time.Duration) Option {
+	return func(c *conf) {
+		c.banListRefreshInterval = d
+	}
+}
+
+// WithHostingIpDenial overrides whether account creation and welcome bonuses are
+// denied for clients on hosting provider, datacenter or anonymizing networks.
+func WithHostingIpDenial(enabled bool) Option {
+	return func(c *conf) {
+		c.denyHostingIps = enabled
+	}
+}
+
 // WithRestrictedMobileCountryCodes overrides the default set of restricted mobile country
 // codes. The values specify the mobile country codes with restricted access to prevent
 // spam waves from problematic regions.
@@ -171,6 +282,15 @@ func WithRestrictedMobileNetworkCodes(mncs ...int) Option {
 	}
 }
 
+// WithDynamicConfigs enables live overrides of limits via a dynamic config
+// source, so they can be tuned without a restart. Limits that aren't set in
+// the source, or are invalid, use the values configured by other options.
+func WithDynamicConfigs(source config.Source) Option {
+	return func(c *conf) {
+		c.dynamic = source
+	}
+}
+
 func applyOptions(opts ...Option) *conf {
 	defaultConfig := &conf{
 		paymentsPerDay:         defaultPaymentsPerDay,
@@ -185,9 +305,19 @@ func applyOptions(opts ...Option) *conf {
 
 		maxNewRelationshipsPerDay: defaultMaxNewRelationshipsPerDay,
 
+		maxContactDiscoveryLookupsPerDay: defaultMaxContactDiscoveryLookupsPerDay,
+
 		minReferralAmount:  defaultMinReferralAmount,
 		maxReferralsPerDay: defaultMaxReferralsPerDay,
 
+		spamConfidenceThreshold: defaultSpamConfidenceThreshold,
+		highRiskEventWindow:     defaultHighRiskEventWindow,
+		blockHighRiskEvents:     defaultBlockHighRiskEvents,
+
+		banListRefreshInterval: defaultBanListRefreshInterval,
+
+		denyHostingIps: defaultDenyHostingIps,
+
 		restrictedMobileCountryCodes: make(map[int]struct{}),
 		restrictedMobileNetworkCodes: make(map[int]struct{}),
 	}
@@ -198,3 +328,131 @@ func applyOptions(opts ...Option) *conf {
 
 	return defaultConfig
 }
+
+func (c *conf) getPaymentsPerDay(ctx context.Context) uint64 {
+	return c.getUint64(ctx, PaymentsPerDayConfigKey, c.paymentsPerDay)
+}
+
+func (c *conf) getPaymentsPerHour(ctx context.Context) uint64 {
+	return c.getUint64(ctx, PaymentsPerHourConfigKey, c.paymentsPerHour)
+}
+
+func (c *conf) getPaymentsPerFiveMinutes(ctx context.Context) uint64 {
+	return c.getUint64(ctx, PaymentsPerFiveMinutesConfigKey, c.paymentsPerFiveMinutes)
+}
+
+func (c *conf) getPhoneVerificationInterval(ctx context.Context) time.Duration {
+	return c.getDuration(ctx, PhoneVerificationIntervalConfigKey, c.phoneVerificationInterval)
+}
+
+func (c *conf) getPhoneVerificationsPerInterval(ctx context.Context) uint64 {
+	return c.getUint64(ctx, PhoneVerificationsPerIntervalConfigKey, c.phoneVerificationsPerInternval)
+}
+
+func (c *conf) getTimePerSmsVerificationCodeSend(ctx context.Context) time.Duration {
+	return c.getDuration(ctx, TimePerSmsVerificationCodeSendConfigKey, c.timePerSmsVerificationCodeSend)
+}
+
+func (c *conf) getTimePerSmsVerificationCodeCheck(ctx context.Context) time.Duration {
+	return c.getDuration(ctx, TimePerSmsVerificationCodeCheckConfigKey, c.timePerSmsVerificationCodeCheck)
+}
+
+func (c *conf) getMaxNewRelationshipsPerDay(ctx context.Context) uint64 {
+	return c.getUint64(ctx, MaxNewRelationshipsPerDayConfigKey, c.maxNewRelationshipsPerDay)
+}
+
+func (c *conf) getMaxContactDiscoveryLookupsPerDay(ctx context.Context) uint64 {
+	return c.getUint64(ctx, MaxContactDiscoveryLookupsPerDayConfigKey, c.maxContactDiscoveryLookupsPerDay)
+}
+
+func (c *conf) getMinReferralAmount(ctx context.Context) uint64 {
+	return c.getUint64(ctx, MinReferralAmountConfigKey, c.minReferralAmount)
+}
+
+func (c *conf) getMaxReferralsPerDay(ctx context.Context) uint64 {
+	return c.getUint64(ctx, MaxReferralsPerDayConfigKey, c.maxReferralsPerDay)
+}
+
+func (c *conf) getSpamConfidenceThreshold(ctx context.Context) float64 {
+	return c.getFloat64(ctx, SpamConfidenceThresholdConfigKey, c.spamConfidenceThreshold)
+}
+
+func (c *conf) getHighRiskEventWindow(ctx context.Context) time.Duration {
+	return c.getDuration(ctx, HighRiskEventWindowConfigKey, c.highRiskEventWindow)
+}
+
+func (c *conf) getBlockHighRiskEvents(ctx context.Context) bool {
+	return c.getBool(ctx, BlockHighRiskEventsConfigKey, c.blockHighRiskEvents)
+}
+
+func (c *conf) getDenyHostingIps(ctx context.Context) bool {
+	return c.getBool(ctx, DenyHostingIpsConfigKey, c.denyHostingIps)
+}
+
+func (c *conf) getUint64(ctx context.Context, key string, value uint64) uint64 {
+	if c.dynamic == nil {
+		return value
+	}
+
+	raw, err := c.dynamic.Get(ctx, key)
+	if err != nil {
+		return value
+	}
+
+	parsed, err := strconv.ParseUint(string(raw), 10, 64)
+	if err != nil {
+		return value
+	}
+	return parsed
+}
+
+func (c *conf) getDuration(ctx context.Context, key string, value time.Duration) time.Duration {
+	if c.dynamic == nil {
+		return value
+	}
+
+	raw, err := c.dynamic.Get(ctx, key)
+	if err != nil {
+		return value
+	}
+
+	parsed, err := time.ParseDuration(string(raw))
+	if err != nil {
+		return value
+	}
+	return parsed
+}
+
+func (c *conf) getFloat64(ctx context.Context, key string, value float64) float64 {
+	if c.dynamic == nil {
+		return value
+	}
+
+	raw, err := c.dynamic.Get(ctx, key)
+	if err != nil {
+		return value
+	}
+
+	parsed, err := strconv.ParseFloat(string(raw), 64)
+	if err != nil {
+		return value
+	}
+	return parsed
+}
+
+func (c *conf) getBool(ctx context.Context, key string, value bool) bool {
+	if c.dynamic == nil {
+		return value
+	}
+
+	raw, err := c.dynamic.Get(ctx, key)
+	if err != nil {
+		return value
+	}
+
+	parsed, err := strconv.ParseBool(string(raw))
+	if err != nil {
+		return value
+	}
+	return parsed
+}
@@ -0,0 +1,103 @@
+package antispam
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/code-payments/code-server/pkg/code/common"
+	"github.com/code-payments/code-server/pkg/code/data/phone"
+	"github.com/code-payments/code-server/pkg/code/data/user/identity"
+	"github.com/code-payments/code-server/pkg/grpc/client"
+	"github.com/code-payments/code-server/pkg/metrics"
+)
+
+// AllowContactDiscovery determines whether a phone-verified owner account can look
+// up a number of contact discovery hash prefixes. The objective here is to limit
+// enumeration of the hashed index of verified phone numbers.
+func (g *Guard) AllowContactDiscovery(ctx context.Context, owner *common.Account, prefixCount int) (bool, error) {
+	tracer := metrics.TraceMethodCall(ctx, metricsStructName, "AllowContactDiscovery")
+	defer tracer.End()
+
+	log := g.log.WithFields(logrus.Fields{
+		"method":       "AllowContactDiscovery",
+		"owner":        owner.PublicKey().ToBase58(),
+		"prefix_count": prefixCount,
+	})
+	log = client.InjectLoggingMetadata(ctx, log)
+
+	// Deny abusers from known IPs
+	isIpBanned, err := g.isIpBanned(ctx)
+	if err != nil {
+		tracer.OnError(err)
+		log.WithError(err).Warn("failure checking ip ban list")
+		return false, err
+	} else if isIpBanned {
+		log.Info("ip is banned")
+		recordDenialEvent(ctx, actionContactDiscovery, "ip banned")
+		return false, nil
+	}
+
+	verification, err := g.data.GetLatestPhoneVerificationForAccount(ctx, owner.PublicKey().ToBase58())
+	if err == phone.ErrVerificationNotFound {
+		// Owner account was never phone verified, so deny the action.
+		log.Info("owner account is not phone verified")
+		recordDenialEvent(ctx, actionContactDiscovery, "not phone verified")
+		return false, nil
+	} else if err != nil {
+		tracer.OnError(err)
+		log.WithError(err).Warn("failure getting phone verification record")
+		return false, err
+	}
+
+	log = log.WithField("phone", verification.PhoneNumber)
+
+	// Deny abusers from known phone ranges
+	isPhoneNumberBanned, err := g.isPhoneNumberBanned(ctx, verification.PhoneNumber)
+	if err != nil {
+		tracer.OnError(err)
+		log.WithError(err).Warn("failure checking phone ban list")
+		return false, err
+	} else if isPhoneNumberBanned {
+		log.Info("denying phone prefix")
+		recordDenialEvent(ctx, actionContactDiscovery, "phone prefix banned")
+		return false, nil
+	}
+
+	user, err := g.data.GetUserByPhoneView(ctx, verification.PhoneNumber)
+	switch err {
+	case nil:
+		// Deny banned users forever
+		if user.IsBanned {
+			log.Info("denying banned user")
+			recordDenialEvent(ctx, actionContactDiscovery, "user banned")
+			return false, nil
+		}
+	case identity.ErrNotFound:
+	default:
+		tracer.OnError(err)
+		log.WithError(err).Warn("failure getting user identity by phone view")
+		return false, err
+	}
+
+	// Basic rate limit across the last day
+	count, err := g.data.GetContactDiscoveryLookupCountSinceTimestamp(
+		ctx,
+		owner.PublicKey().ToBase58(),
+		time.Now().Add(-24*time.Hour),
+	)
+	if err != nil {
+		tracer.OnError(err)
+		log.WithError(err).Warn("failure getting contact discovery lookup count")
+		return false, err
+	}
+
+	if count+uint64(prefixCount) > g.conf.getMaxContactDiscoveryLookupsPerDay(ctx) {
+		log.Info("owner is rate limited by daily count")
+		recordDenialEvent(ctx, actionContactDiscovery, "daily limit exceeded")
+		return false, nil
+	}
+
+	return true, nil
+}
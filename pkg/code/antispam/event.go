@@ -0,0 +1,72 @@
+package antispam
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/code-payments/code-server/pkg/code/data/event"
+	"github.com/code-payments/code-server/pkg/metrics"
+)
+
+// EventScorer scores events for their likelihood of being spam
+type EventScorer interface {
+	// Score returns the spam confidence for the event, in the range [0, 1]
+	Score(ctx context.Context, record *event.Record) (float64, error)
+}
+
+// ScoreEvent populates the spam confidence of an event prior to it being saved.
+// Events at or above the spam confidence threshold are flagged for review, and
+// can be used to deny future actions by the source phone number.
+//
+// Scoring fails open, so an error never prevents an event from being saved.
+func (g *Guard) ScoreEvent(ctx context.Context, record *event.Record) {
+	if g.conf.eventScorer == nil {
+		return
+	}
+
+	tracer := metrics.TraceMethodCall(ctx, metricsStructName, "ScoreEvent")
+	defer tracer.End()
+
+	log := g.log.WithFields(logrus.Fields{
+		"method":     "ScoreEvent",
+		"event":      record.EventId,
+		"event_type": record.EventType.String(),
+		"phone":      record.SourceIdentity,
+	})
+
+	score, err := g.conf.eventScorer.Score(ctx, record)
+	if err != nil {
+		tracer.OnError(err)
+		log.WithError(err).Warn("failure scoring event")
+		return
+	}
+
+	record.SpamConfidence = score
+
+	if score >= g.conf.getSpamConfidenceThreshold(ctx) {
+		log.WithField("spam_confidence", score).Info("event flagged for review")
+		recordHighRiskEvent(ctx, record.EventType.String())
+	}
+}
+
+// hasRecentHighRiskEvents determines whether a phone number has been the source
+// of high risk events within the configured window. Events are only scored when
+// a scorer is configured, so there's nothing to look up otherwise.
+func (g *Guard) hasRecentHighRiskEvents(ctx context.Context, phoneNumber string) (bool, error) {
+	if g.conf.eventScorer == nil {
+		return false, nil
+	}
+
+	count, err := g.data.CountEventsBySourceIdentityAndMinSpamConfidenceSince(
+		ctx,
+		phoneNumber,
+		g.conf.getSpamConfidenceThreshold(ctx),
+		time.Now().Add(-g.conf.getHighRiskEventWindow(ctx)),
+	)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -1,7 +1,6 @@
 package antispam
 
 import (
-	"github.com/oschwald/maxminddb-golang"
 	"github.com/sirupsen/logrus"
 	xrate "golang.org/x/time/rate"
 
@@ -25,15 +24,14 @@ type Guard struct {
 	log            *logrus.Entry
 	data           code_data.Provider
 	deviceVerifier device.Verifier
-	maxmind        *maxminddb.Reader
 	limiter        *limiter
+	banList        *banListReader
 	conf           *conf
 }
 
 func NewGuard(
 	data code_data.Provider,
 	deviceVerifier device.Verifier,
-	maxmind *maxminddb.Reader,
 	opts ...Option,
 ) *Guard {
 	conf := applyOptions(opts...)
@@ -47,8 +45,8 @@ func NewGuard(
 		log:            logrus.StandardLogger().WithField("type", "antispam/guard"),
 		data:           data,
 		deviceVerifier: deviceVerifier,
-		maxmind:        maxmind,
 		limiter:        limiter,
+		banList:        newBanListReader(data, conf.banListRefreshInterval),
 		conf:           conf,
 	}
 }
@@ -3,20 +3,32 @@ package antispam
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
 
 	"github.com/code-payments/code-server/pkg/code/common"
 	code_data "github.com/code-payments/code-server/pkg/code/data"
+	datatest "github.com/code-payments/code-server/pkg/code/data/test"
+	"github.com/code-payments/code-server/pkg/code/data/banlist"
+	"github.com/code-payments/code-server/pkg/code/data/campaign"
+	"github.com/code-payments/code-server/pkg/code/data/event"
 	"github.com/code-payments/code-server/pkg/code/data/intent"
 	"github.com/code-payments/code-server/pkg/code/data/phone"
 	"github.com/code-payments/code-server/pkg/code/data/user"
 	"github.com/code-payments/code-server/pkg/code/data/user/identity"
+	file_config "github.com/code-payments/code-server/pkg/config/file"
 	"github.com/code-payments/code-server/pkg/currency"
 	memory_device_verifier "github.com/code-payments/code-server/pkg/device/memory"
+	"github.com/code-payments/code-server/pkg/geoip"
+	memory_geoip "github.com/code-payments/code-server/pkg/geoip/memory"
 	phone_lib "github.com/code-payments/code-server/pkg/phone"
 	"github.com/code-payments/code-server/pkg/pointer"
 	"github.com/code-payments/code-server/pkg/testutil"
@@ -30,16 +42,16 @@ type testEnv struct {
 
 func setup(t *testing.T) (env testEnv) {
 	env.ctx = context.Background()
-	env.data = code_data.NewTestDataProvider()
+	env.data = datatest.NewDataProvider()
 	env.guard = NewGuard(
 		env.data,
 		memory_device_verifier.NewMemoryDeviceVerifier(),
-		nil,
 
 		// Intent limits
 		WithDailyPaymentLimit(5),
 		WithPaymentRateLimit(time.Second),
 		WithMaxNewRelationshipsPerDay(5),
+		WithMaxContactDiscoveryLookupsPerDay(100),
 
 		// Phone verification limits
 		WithPhoneVerificationsPerInterval(3),
@@ -485,6 +497,51 @@ func TestAllowOpenAccounts_HappyPath(t *testing.T) {
 	}
 }
 
+func TestAllowOpenAccounts_HostingIp(t *testing.T) {
+	for _, denyHostingIps := range []bool{true, false} {
+		geoIP := memory_geoip.NewGeoIP()
+		require.NoError(t, geoIP.Set("3.0.0.0/8", &geoip.Metadata{
+			Country:   pointer.String("US"),
+			IsHosting: true,
+		}))
+		require.NoError(t, geoIP.Set("24.0.0.0/8", &geoip.Metadata{
+			Country: pointer.String("CA"),
+		}))
+
+		env := setup(t)
+		env.data = datatest.NewDataProviderWithGeoIP(geoIP)
+		env.guard = NewGuard(
+			env.data,
+			memory_device_verifier.NewMemoryDeviceVerifier(),
+			WithHostingIpDenial(denyHostingIps),
+		)
+
+		ownerAccount := testutil.NewRandomAccount(t)
+		require.NoError(t, env.data.SavePhoneVerification(env.ctx, &phone.Verification{
+			PhoneNumber:    "+18005550000",
+			OwnerAccount:   ownerAccount.PublicKey().ToBase58(),
+			CreatedAt:      time.Now(),
+			LastVerifiedAt: time.Now(),
+		}))
+
+		hostingIp := metadata.NewIncomingContext(env.ctx, metadata.Pairs("x-forwarded-for", "3.1.2.3"))
+		residentialIp := metadata.NewIncomingContext(env.ctx, metadata.Pairs("x-forwarded-for", "24.1.2.3"))
+
+		allow, _, err := env.guard.AllowOpenAccounts(hostingIp, ownerAccount, pointer.String(memory_device_verifier.ValidDeviceToken))
+		require.NoError(t, err)
+		assert.Equal(t, !denyHostingIps, allow)
+
+		allow, _, err = env.guard.AllowOpenAccounts(residentialIp, ownerAccount, pointer.String(memory_device_verifier.ValidDeviceToken))
+		require.NoError(t, err)
+		assert.True(t, allow)
+
+		// Clients without a known IP aren't denied
+		allow, _, err = env.guard.AllowOpenAccounts(env.ctx, ownerAccount, pointer.String(memory_device_verifier.ValidDeviceToken))
+		require.NoError(t, err)
+		assert.True(t, allow)
+	}
+}
+
 func TestAllowOpenAccounts_StaffUser(t *testing.T) {
 	env := setup(t)
 
@@ -630,6 +687,50 @@ func TestAllowEstablishNewRelationship_StaffUser(t *testing.T) {
 	}
 }
 
+func TestAllowContactDiscovery_HappyPath(t *testing.T) {
+	env := setup(t)
+
+	ownerAccount := testutil.NewRandomAccount(t)
+	otherOwnerAccount := testutil.NewRandomAccount(t)
+
+	// Account isn't phone verified, so it cannot discover contacts
+	allow, err := env.guard.AllowContactDiscovery(env.ctx, ownerAccount, 1)
+	require.NoError(t, err)
+	assert.False(t, allow)
+
+	for _, account := range []*common.Account{ownerAccount, otherOwnerAccount} {
+		verification := &phone.Verification{
+			PhoneNumber:    "+18005550000",
+			OwnerAccount:   account.PublicKey().ToBase58(),
+			CreatedAt:      time.Now(),
+			LastVerifiedAt: time.Now(),
+		}
+		require.NoError(t, env.guard.data.SavePhoneVerification(env.ctx, verification))
+	}
+
+	allow, err = env.guard.AllowContactDiscovery(env.ctx, ownerAccount, 100)
+	require.NoError(t, err)
+	assert.True(t, allow)
+
+	// Lookups outside the last day don't count towards the limit
+	require.NoError(t, env.data.RecordContactDiscoveryLookups(env.ctx, ownerAccount.PublicKey().ToBase58(), 100, time.Now().Add(-25*time.Hour)))
+	require.NoError(t, env.data.RecordContactDiscoveryLookups(env.ctx, ownerAccount.PublicKey().ToBase58(), 60, time.Now()))
+
+	allow, err = env.guard.AllowContactDiscovery(env.ctx, ownerAccount, 40)
+	require.NoError(t, err)
+	assert.True(t, allow)
+
+	// Requests that would exceed the daily limit are denied
+	allow, err = env.guard.AllowContactDiscovery(env.ctx, ownerAccount, 41)
+	require.NoError(t, err)
+	assert.False(t, allow)
+
+	// The limit is per owner account
+	allow, err = env.guard.AllowContactDiscovery(env.ctx, otherOwnerAccount, 100)
+	require.NoError(t, err)
+	assert.True(t, allow)
+}
+
 func TestAllowNewPhoneVerification_HappyPath(t *testing.T) {
 	env := setup(t)
 
@@ -784,6 +885,444 @@ func TestAllowCheckSmsVerificationCode(t *testing.T) {
 	}
 }
 
+func TestAllowCampaignPayout(t *testing.T) {
+	env := setup(t)
+
+	campaignRecord := &campaign.Record{
+		Name: "welcome_bonus",
+		Type: campaign.WelcomeBonusType,
+		Amounts: map[currency.Code]float64{
+			currency.USD: 1.0,
+		},
+		BudgetUsd: 100,
+		EligibilityRules: campaign.EligibilityRules{
+			PhonePrefix:              "+1",
+			MaxAccountAge:            24 * time.Hour,
+			MaxPayoutsPerPhoneNumber: 1,
+			MaxPayoutsPerOwner:       1,
+		},
+		StartsAt: time.Now().Add(-time.Hour),
+		EndsAt:   time.Now().Add(time.Hour),
+		State:    campaign.StateEnabled,
+	}
+	require.NoError(t, env.data.PutCampaign(env.ctx, campaignRecord))
+
+	ownerAccount1 := testutil.NewRandomAccount(t)
+	ownerAccount2 := testutil.NewRandomAccount(t)
+
+	// Account isn't phone verified, so it cannot receive a payout
+	allow, err := env.guard.AllowCampaignPayout(env.ctx, ownerAccount1, campaignRecord)
+	require.NoError(t, err)
+	assert.False(t, allow)
+
+	for _, ownerAccount := range []*common.Account{ownerAccount1, ownerAccount2} {
+		require.NoError(t, env.data.SavePhoneVerification(env.ctx, &phone.Verification{
+			PhoneNumber:    "+18005550000",
+			OwnerAccount:   ownerAccount.PublicKey().ToBase58(),
+			CreatedAt:      time.Now(),
+			LastVerifiedAt: time.Now(),
+		}))
+	}
+
+	allow, err = env.guard.AllowCampaignPayout(env.ctx, ownerAccount1, campaignRecord)
+	require.NoError(t, err)
+	assert.True(t, allow)
+
+	// Campaigns outside their date window, or disabled, don't allow payouts
+	for _, mutate := range []func(r *campaign.Record){
+		func(r *campaign.Record) { r.StartsAt = time.Now().Add(time.Minute) },
+		func(r *campaign.Record) { r.EndsAt = time.Now().Add(-time.Minute) },
+		func(r *campaign.Record) { r.State = campaign.StateDisabled },
+	} {
+		cloned := campaignRecord.Clone()
+		mutate(&cloned)
+
+		allow, err = env.guard.AllowCampaignPayout(env.ctx, ownerAccount1, &cloned)
+		require.NoError(t, err)
+		assert.False(t, allow)
+	}
+
+	// Phone numbers outside the campaign's region are denied
+	cloned := campaignRecord.Clone()
+	cloned.EligibilityRules.PhonePrefix = "+44"
+	allow, err = env.guard.AllowCampaignPayout(env.ctx, ownerAccount1, &cloned)
+	require.NoError(t, err)
+	assert.False(t, allow)
+
+	// Users older than the campaign's max account age are denied
+	require.NoError(t, env.data.PutUser(env.ctx, &identity.Record{
+		ID: user.NewUserID(),
+		View: &user.View{
+			PhoneNumber: pointer.String("+18005550000"),
+		},
+		CreatedAt: time.Now().Add(-48 * time.Hour),
+	}))
+
+	allow, err = env.guard.AllowCampaignPayout(env.ctx, ownerAccount1, campaignRecord)
+	require.NoError(t, err)
+	assert.False(t, allow)
+
+	cloned = campaignRecord.Clone()
+	cloned.EligibilityRules.MaxAccountAge = 0
+	allow, err = env.guard.AllowCampaignPayout(env.ctx, ownerAccount1, &cloned)
+	require.NoError(t, err)
+	assert.True(t, allow)
+
+	// Payouts are limited per phone number, regardless of owner account
+	require.NoError(t, env.data.CreateCampaignPayout(env.ctx, &campaign.Payout{
+		Campaign:         campaignRecord.Name,
+		IntentId:         "intent",
+		OwnerAccount:     ownerAccount1.PublicKey().ToBase58(),
+		PhoneNumber:      "+18005550000",
+		ExchangeCurrency: currency.USD,
+		ExchangeRate:     0.1,
+		NativeAmount:     1.0,
+		UsdValue:         1.0,
+		Quantity:         1,
+		State:            campaign.PayoutStatePending,
+	}))
+
+	for _, ownerAccount := range []*common.Account{ownerAccount1, ownerAccount2} {
+		allow, err = env.guard.AllowCampaignPayout(env.ctx, ownerAccount, &cloned)
+		require.NoError(t, err)
+		assert.False(t, allow)
+	}
+
+	cloned.EligibilityRules.MaxPayoutsPerPhoneNumber = 2
+	allow, err = env.guard.AllowCampaignPayout(env.ctx, ownerAccount2, &cloned)
+	require.NoError(t, err)
+	assert.True(t, allow)
+}
+
+func TestAllowCampaignPayout_StaffUser(t *testing.T) {
+	env := setup(t)
+
+	campaignRecord := &campaign.Record{
+		Name: "promotion",
+		Type: campaign.PromotionType,
+		Amounts: map[currency.Code]float64{
+			currency.USD: 1.0,
+		},
+		BudgetUsd: 100,
+		EligibilityRules: campaign.EligibilityRules{
+			PhonePrefix:              "+44",
+			MaxPayoutsPerPhoneNumber: 1,
+			MaxPayoutsPerOwner:       1,
+		},
+		StartsAt: time.Now().Add(-time.Hour),
+		EndsAt:   time.Now().Add(time.Hour),
+		State:    campaign.StateEnabled,
+	}
+
+	for i, isStaffUser := range []bool{true, false} {
+		phoneNumber := fmt.Sprintf("+1800555000%d", i)
+
+		ownerAccount := testutil.NewRandomAccount(t)
+
+		require.NoError(t, env.data.PutUser(env.ctx, &identity.Record{
+			ID: user.NewUserID(),
+			View: &user.View{
+				PhoneNumber: &phoneNumber,
+			},
+			IsStaffUser: isStaffUser,
+			CreatedAt:   time.Now(),
+		}))
+
+		require.NoError(t, env.data.SavePhoneVerification(env.ctx, &phone.Verification{
+			PhoneNumber:    phoneNumber,
+			OwnerAccount:   ownerAccount.PublicKey().ToBase58(),
+			CreatedAt:      time.Now(),
+			LastVerifiedAt: time.Now(),
+		}))
+
+		// Staff users aren't subject to campaign eligibility rules
+		allow, err := env.guard.AllowCampaignPayout(env.ctx, ownerAccount, campaignRecord)
+		require.NoError(t, err)
+		assert.Equal(t, isStaffUser, allow)
+	}
+}
+
+func TestScoreEvent_HighRiskEvents(t *testing.T) {
+	for _, block := range []bool{true, false} {
+		env := setup(t)
+
+		scorer := &staticEventScorer{}
+		env.guard = NewGuard(
+			env.data,
+			memory_device_verifier.NewMemoryDeviceVerifier(),
+			WithEventScorer(scorer),
+			WithSpamConfidenceThreshold(0.5),
+			WithHighRiskEventWindow(time.Hour),
+			WithHighRiskEventBlocking(block),
+		)
+
+		phoneNumber := "+12223334444"
+		ownerAccount := testutil.NewRandomAccount(t)
+		require.NoError(t, env.data.SavePhoneVerification(env.ctx, &phone.Verification{
+			PhoneNumber:    phoneNumber,
+			OwnerAccount:   ownerAccount.PublicKey().ToBase58(),
+			CreatedAt:      time.Now(),
+			LastVerifiedAt: time.Now(),
+		}))
+
+		// Scoring failures don't prevent the event from being saved
+		scorer.err = errors.New("failure")
+		record := newTestEvent("event1", phoneNumber, time.Now())
+		env.guard.ScoreEvent(env.ctx, record)
+		assert.EqualValues(t, 0, record.SpamConfidence)
+
+		// Low risk events don't impact payments
+		scorer.err = nil
+		scorer.score = 0.25
+		env.guard.ScoreEvent(env.ctx, record)
+		assert.EqualValues(t, 0.25, record.SpamConfidence)
+		require.NoError(t, env.data.SaveEvent(env.ctx, record))
+
+		allow, err := env.guard.AllowSendPayment(env.ctx, ownerAccount, false, testutil.NewRandomAccount(t))
+		require.NoError(t, err)
+		assert.True(t, allow)
+
+		// High risk events outside the window don't impact payments
+		scorer.score = 0.75
+		record = newTestEvent("event2", phoneNumber, time.Now().Add(-2*time.Hour))
+		env.guard.ScoreEvent(env.ctx, record)
+		assert.EqualValues(t, 0.75, record.SpamConfidence)
+		require.NoError(t, env.data.SaveEvent(env.ctx, record))
+
+		allow, err = env.guard.AllowSendPayment(env.ctx, ownerAccount, false, testutil.NewRandomAccount(t))
+		require.NoError(t, err)
+		assert.True(t, allow)
+
+		// High risk events within the window are blocked when configured,
+		// otherwise only flagged for review
+		record = newTestEvent("event3", phoneNumber, time.Now())
+		env.guard.ScoreEvent(env.ctx, record)
+		require.NoError(t, env.data.SaveEvent(env.ctx, record))
+
+		allow, err = env.guard.AllowSendPayment(env.ctx, ownerAccount, false, testutil.NewRandomAccount(t))
+		require.NoError(t, err)
+		assert.Equal(t, !block, allow)
+
+		// High risk events aren't checked when no scorer is configured
+		env.guard = NewGuard(
+			env.data,
+			memory_device_verifier.NewMemoryDeviceVerifier(),
+			WithSpamConfidenceThreshold(0.5),
+			WithHighRiskEventWindow(time.Hour),
+			WithHighRiskEventBlocking(block),
+		)
+
+		allow, err = env.guard.AllowSendPayment(env.ctx, ownerAccount, false, testutil.NewRandomAccount(t))
+		require.NoError(t, err)
+		assert.True(t, allow)
+	}
+}
+
+func TestBanList(t *testing.T) {
+	env := setup(t)
+	env.guard = NewGuard(
+		env.data,
+		memory_device_verifier.NewMemoryDeviceVerifier(),
+		WithBanListRefreshInterval(0),
+	)
+
+	deviceToken := pointer.String(memory_device_verifier.ValidDeviceToken)
+	bannedIp := metadata.NewIncomingContext(env.ctx, metadata.Pairs("x-forwarded-for", "10.1.2.3, 192.168.0.1"))
+	allowedIp := metadata.NewIncomingContext(env.ctx, metadata.Pairs("x-forwarded-for", "11.1.2.3"))
+
+	ownerAccount := testutil.NewRandomAccount(t)
+	bannedAddress := testutil.NewRandomAccount(t)
+	require.NoError(t, env.data.SavePhoneVerification(env.ctx, &phone.Verification{
+		PhoneNumber:    "+12223334444",
+		OwnerAccount:   ownerAccount.PublicKey().ToBase58(),
+		CreatedAt:      time.Now(),
+		LastVerifiedAt: time.Now(),
+	}))
+
+	// Nothing is in the ban list
+	allow, err := env.guard.AllowNewPhoneVerification(bannedIp, "+447700900000", deviceToken)
+	require.NoError(t, err)
+	assert.True(t, allow)
+
+	allow, err = env.guard.AllowSendPayment(env.ctx, ownerAccount, true, bannedAddress)
+	require.NoError(t, err)
+	assert.True(t, allow)
+
+	// Sanctioned phone prefixes are always banned
+	for prefix := range SanctionedPhonePrefixes {
+		allow, err = env.guard.AllowNewPhoneVerification(env.ctx, prefix+"9990001111", deviceToken)
+		require.NoError(t, err)
+		assert.False(t, allow)
+	}
+
+	// Banned phone prefixes
+	require.NoError(t, env.data.PutBanListEntry(env.ctx, &banlist.Record{
+		EntryType: banlist.PhonePrefixEntryType,
+		Value:     "+44",
+		Reason:    "abuse",
+	}))
+
+	allow, err = env.guard.AllowNewPhoneVerification(env.ctx, "+447700900000", deviceToken)
+	require.NoError(t, err)
+	assert.False(t, allow)
+
+	allow, err = env.guard.AllowNewPhoneVerification(env.ctx, "+18005550000", deviceToken)
+	require.NoError(t, err)
+	assert.True(t, allow)
+
+	// Removing a sanctioned prefix from the ban list doesn't unban it
+	require.NoError(t, env.data.PutBanListEntry(env.ctx, &banlist.Record{
+		EntryType: banlist.PhonePrefixEntryType,
+		Value:     "+98",
+		Reason:    "manual",
+	}))
+	require.NoError(t, env.data.DeleteBanListEntry(env.ctx, banlist.PhonePrefixEntryType, "+98"))
+
+	allow, err = env.guard.AllowNewPhoneVerification(env.ctx, "+989990001111", deviceToken)
+	require.NoError(t, err)
+	assert.False(t, allow)
+
+	// Banned IP ranges
+	require.NoError(t, env.data.PutBanListEntry(env.ctx, &banlist.Record{
+		EntryType: banlist.IpRangeEntryType,
+		Value:     "10.0.0.0/8",
+		Reason:    "abuse",
+	}))
+
+	allow, err = env.guard.AllowNewPhoneVerification(bannedIp, "+18005550000", deviceToken)
+	require.NoError(t, err)
+	assert.False(t, allow)
+
+	allow, err = env.guard.AllowNewPhoneVerification(allowedIp, "+18005550000", deviceToken)
+	require.NoError(t, err)
+	assert.True(t, allow)
+
+	// Banned external addresses, which only apply to public payments
+	require.NoError(t, env.data.PutBanListEntry(env.ctx, &banlist.Record{
+		EntryType: banlist.ExternalAddressEntryType,
+		Value:     bannedAddress.PublicKey().ToBase58(),
+		Reason:    "fraud",
+	}))
+
+	allow, err = env.guard.AllowSendPayment(env.ctx, ownerAccount, true, bannedAddress)
+	require.NoError(t, err)
+	assert.False(t, allow)
+
+	allow, err = env.guard.AllowSendPayment(env.ctx, ownerAccount, false, bannedAddress)
+	require.NoError(t, err)
+	assert.True(t, allow)
+
+	// Expired and removed entries no longer apply
+	expiredAt := time.Now().Add(-time.Minute)
+	require.NoError(t, env.data.PutBanListEntry(env.ctx, &banlist.Record{
+		EntryType: banlist.IpRangeEntryType,
+		Value:     "10.0.0.0/8",
+		Reason:    "abuse",
+		ExpiresAt: &expiredAt,
+	}))
+	require.NoError(t, env.data.DeleteBanListEntry(env.ctx, banlist.ExternalAddressEntryType, bannedAddress.PublicKey().ToBase58()))
+
+	allow, err = env.guard.AllowNewPhoneVerification(bannedIp, "+18005550000", deviceToken)
+	require.NoError(t, err)
+	assert.True(t, allow)
+
+	allow, err = env.guard.AllowSendPayment(env.ctx, ownerAccount, true, bannedAddress)
+	require.NoError(t, err)
+	assert.True(t, allow)
+}
+
+func TestBanList_CachedEntries(t *testing.T) {
+	env := setup(t)
+
+	// Loads the empty ban list into the cache
+	allow, err := env.guard.AllowNewPhoneVerification(env.ctx, "+447700900000", pointer.String(memory_device_verifier.ValidDeviceToken))
+	require.NoError(t, err)
+	assert.True(t, allow)
+
+	require.NoError(t, env.data.PutBanListEntry(env.ctx, &banlist.Record{
+		EntryType: banlist.PhonePrefixEntryType,
+		Value:     "+44",
+		Reason:    "abuse",
+	}))
+
+	// Cached entries are used until the refresh interval elapses
+	allow, err = env.guard.AllowNewPhoneVerification(env.ctx, "+447700900000", pointer.String(memory_device_verifier.ValidDeviceToken))
+	require.NoError(t, err)
+	assert.True(t, allow)
+
+	env.guard.banList.lastRefresh = time.Now().Add(-defaultBanListRefreshInterval)
+
+	allow, err = env.guard.AllowNewPhoneVerification(env.ctx, "+447700900000", pointer.String(memory_device_verifier.ValidDeviceToken))
+	require.NoError(t, err)
+	assert.False(t, allow)
+}
+
+func TestBanList_ConcurrentRefresh(t *testing.T) {
+	env := setup(t)
+	env.guard = NewGuard(
+		env.data,
+		memory_device_verifier.NewMemoryDeviceVerifier(),
+		WithBanListRefreshInterval(0),
+	)
+
+	require.NoError(t, env.data.PutBanListEntry(env.ctx, &banlist.Record{
+		EntryType: banlist.PhonePrefixEntryType,
+		Value:     "+44",
+		Reason:    "abuse",
+	}))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 32; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			isBanned, err := env.guard.isPhoneNumberBanned(env.ctx, "+447700900000")
+			require.NoError(t, err)
+			assert.True(t, isBanned)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestDynamicConfigs(t *testing.T) {
+	ctx := context.Background()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+ANTISPAM_PAYMENTS_PER_DAY: 1
+ANTISPAM_TIME_PER_SMS_VERIFICATION_CODE_SEND: invalid
+`), 0600))
+
+	source, err := file_config.NewSource(path)
+	require.NoError(t, err)
+	defer source.Shutdown()
+
+	conf := applyOptions(
+		WithDailyPaymentLimit(5),
+		WithHourlyPaymentLimit(3),
+		WithTimePerSmsVerificationCodeSend(time.Second),
+		WithDynamicConfigs(source),
+	)
+
+	// Limits use the dynamic value when it's set and valid
+	assert.EqualValues(t, 1, conf.getPaymentsPerDay(ctx))
+	assert.EqualValues(t, 3, conf.getPaymentsPerHour(ctx))
+	assert.Equal(t, time.Second, conf.getTimePerSmsVerificationCodeSend(ctx))
+
+	// Changes are picked up without recreating the guard
+	require.NoError(t, os.WriteFile(path, []byte(`
+ANTISPAM_PAYMENTS_PER_HOUR: 10
+ANTISPAM_TIME_PER_SMS_VERIFICATION_CODE_SEND: 1m
+`), 0600))
+	_, err = source.Reload()
+	require.NoError(t, err)
+
+	assert.EqualValues(t, 5, conf.getPaymentsPerDay(ctx))
+	assert.EqualValues(t, 10, conf.getPaymentsPerHour(ctx))
+	assert.Equal(t, time.Minute, conf.getTimePerSmsVerificationCodeSend(ctx))
+}
+
 func simulateSentPayment(t *testing.T, env testEnv, ownerAccount *common.Account, isPublic bool, state intent.State) {
 	verificationRecord, err := env.data.GetLatestPhoneVerificationForAccount(env.ctx, ownerAccount.PublicKey().ToBase58())
 	require.NoError(t, err)
@@ -929,3 +1468,22 @@ func simulateSmsCodeChecked(t *testing.T, env testEnv, phoneNumber, verification
 	}
 	require.NoError(t, env.data.PutPhoneEvent(env.ctx, event))
 }
+
+func newTestEvent(id, phoneNumber string, createdAt time.Time) *event.Record {
+	return &event.Record{
+		EventId:           id,
+		EventType:         event.RemoteSend,
+		SourceCodeAccount: "owner",
+		SourceIdentity:    phoneNumber,
+		CreatedAt:         createdAt,
+	}
+}
+
+type staticEventScorer struct {
+	score float64
+	err   error
+}
+
+func (s *staticEventScorer) Score(_ context.Context, _ *event.Record) (float64, error) {
+	return s.score, s.err
+}
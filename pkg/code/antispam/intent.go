@@ -28,12 +28,29 @@ func (g *Guard) AllowOpenAccounts(ctx context.Context, owner *common.Account, de
 	log = client.InjectLoggingMetadata(ctx, log)
 
 	// Deny abusers from known IPs
-	if isIpBanned(ctx) {
+	isIpBanned, err := g.isIpBanned(ctx)
+	if err != nil {
+		tracer.OnError(err)
+		log.WithError(err).Warn("failure checking ip ban list")
+		return false, nil, err
+	} else if isIpBanned {
 		log.Info("ip is banned")
 		recordDenialEvent(ctx, actionOpenAccounts, "ip banned")
 		return false, nil, nil
 	}
 
+	// Deny clients on datacenter and anonymizing networks
+	isHostingIp, err := g.isHostingIp(ctx)
+	if err != nil {
+		tracer.OnError(err)
+		log.WithError(err).Warn("failure checking ip metadata")
+		return false, nil, err
+	} else if isHostingIp {
+		log.Info("ip is a hosting provider")
+		recordDenialEvent(ctx, actionOpenAccounts, "hosting ip")
+		return false, nil, nil
+	}
+
 	verification, err := g.data.GetLatestPhoneVerificationForAccount(ctx, owner.PublicKey().ToBase58())
 	if err == phone.ErrVerificationNotFound {
 		// Owner account was never phone verified, so deny the action.
@@ -49,7 +66,12 @@ func (g *Guard) AllowOpenAccounts(ctx context.Context, owner *common.Account, de
 	log = log.WithField("phone", verification.PhoneNumber)
 
 	// Deny abusers from known phone ranges
-	if hasBannedPhoneNumberPrefix(verification.PhoneNumber) {
+	isPhoneNumberBanned, err := g.isPhoneNumberBanned(ctx, verification.PhoneNumber)
+	if err != nil {
+		tracer.OnError(err)
+		log.WithError(err).Warn("failure checking phone ban list")
+		return false, nil, err
+	} else if isPhoneNumberBanned {
 		log.Info("denying phone prefix")
 		recordDenialEvent(ctx, actionOpenAccounts, "phone prefix banned")
 		return false, nil, nil
@@ -146,16 +168,28 @@ func (g *Guard) AllowSendPayment(ctx context.Context, owner *common.Account, isP
 	log = client.InjectLoggingMetadata(ctx, log)
 
 	// Deny abusers from known IPs
-	if isIpBanned(ctx) {
+	isIpBanned, err := g.isIpBanned(ctx)
+	if err != nil {
+		tracer.OnError(err)
+		log.WithError(err).Warn("failure checking ip ban list")
+		return false, err
+	} else if isIpBanned {
 		log.Info("ip is banned")
 		recordDenialEvent(ctx, actionSendPayment, "ip banned")
 		return false, nil
 	}
 
-	if isPublic && isExternalAddressBanned(destination) {
-		log.WithField("address", destination.PublicKey().ToBase58()).Info("external address is banned")
-		recordDenialEvent(ctx, actionSendPayment, "external address banned")
-		return false, nil
+	if isPublic {
+		isExternalAddressBanned, err := g.isExternalAddressBanned(ctx, destination)
+		if err != nil {
+			tracer.OnError(err)
+			log.WithError(err).Warn("failure checking external address ban list")
+			return false, err
+		} else if isExternalAddressBanned {
+			log.WithField("address", destination.PublicKey().ToBase58()).Info("external address is banned")
+			recordDenialEvent(ctx, actionSendPayment, "external address banned")
+			return false, nil
+		}
 	}
 
 	verification, err := g.data.GetLatestPhoneVerificationForAccount(ctx, owner.PublicKey().ToBase58())
@@ -173,7 +207,12 @@ func (g *Guard) AllowSendPayment(ctx context.Context, owner *common.Account, isP
 	log = log.WithField("phone", verification.PhoneNumber)
 
 	// Deny abusers from known phone ranges
-	if hasBannedPhoneNumberPrefix(verification.PhoneNumber) {
+	isPhoneNumberBanned, err := g.isPhoneNumberBanned(ctx, verification.PhoneNumber)
+	if err != nil {
+		tracer.OnError(err)
+		log.WithError(err).Warn("failure checking phone ban list")
+		return false, err
+	} else if isPhoneNumberBanned {
 		log.Info("denying phone prefix")
 		recordDenialEvent(ctx, actionSendPayment, "phone prefix banned")
 		return false, nil
@@ -200,6 +239,22 @@ func (g *Guard) AllowSendPayment(ctx context.Context, owner *common.Account, isP
 		return false, err
 	}
 
+	// Phone numbers that are the source of high risk events are either blocked
+	// or flagged for review
+	hasHighRiskEvents, err := g.hasRecentHighRiskEvents(ctx, verification.PhoneNumber)
+	if err != nil {
+		tracer.OnError(err)
+		log.WithError(err).Warn("failure checking for high risk events")
+		return false, err
+	} else if hasHighRiskEvents {
+		if g.conf.getBlockHighRiskEvents(ctx) {
+			log.Info("denying phone with high risk events")
+			recordDenialEvent(ctx, actionSendPayment, "high risk events")
+			return false, nil
+		}
+		log.Info("phone with high risk events flagged for review")
+	}
+
 	// Time-based rate limit per phone number across all sends
 	rateLimited, err := g.limiter.denyPaymentByPhone(verification.PhoneNumber)
 	if err != nil {
@@ -233,7 +288,7 @@ func (g *Guard) AllowSendPayment(ctx context.Context, owner *common.Account, isP
 		return false, err
 	}
 
-	if count >= g.conf.paymentsPerFiveMinutes {
+	if count >= g.conf.getPaymentsPerFiveMinutes(ctx) {
 		log.Info("phone is rate limited by five minute payment count")
 		recordDenialEvent(ctx, actionSendPayment, "five minute limit exceeded")
 		return false, nil
@@ -253,7 +308,7 @@ func (g *Guard) AllowSendPayment(ctx context.Context, owner *common.Account, isP
 		return false, err
 	}
 
-	if count >= g.conf.paymentsPerHour {
+	if count >= g.conf.getPaymentsPerHour(ctx) {
 		log.Info("phone is rate limited by hourly payment count")
 		recordDenialEvent(ctx, actionSendPayment, "hourly limit exceeded")
 		return false, nil
@@ -273,7 +328,7 @@ func (g *Guard) AllowSendPayment(ctx context.Context, owner *common.Account, isP
 		return false, err
 	}
 
-	if count >= g.conf.paymentsPerDay {
+	if count >= g.conf.getPaymentsPerDay(ctx) {
 		log.Info("phone is rate limited by daily payment count")
 		recordDenialEvent(ctx, actionSendPayment, "daily limit exceeded")
 		return false, nil
@@ -296,7 +351,12 @@ func (g *Guard) AllowReceivePayments(ctx context.Context, owner *common.Account,
 	log = client.InjectLoggingMetadata(ctx, log)
 
 	// Deny abusers from known IPs
-	if isIpBanned(ctx) {
+	isIpBanned, err := g.isIpBanned(ctx)
+	if err != nil {
+		tracer.OnError(err)
+		log.WithError(err).Warn("failure checking ip ban list")
+		return false, err
+	} else if isIpBanned {
 		log.Info("ip is banned")
 		recordDenialEvent(ctx, actionReceivePayments, "ip banned")
 		return false, nil
@@ -317,7 +377,12 @@ func (g *Guard) AllowReceivePayments(ctx context.Context, owner *common.Account,
 	log = log.WithField("phone", verification.PhoneNumber)
 
 	// Deny abusers from known phone ranges
-	if hasBannedPhoneNumberPrefix(verification.PhoneNumber) {
+	isPhoneNumberBanned, err := g.isPhoneNumberBanned(ctx, verification.PhoneNumber)
+	if err != nil {
+		tracer.OnError(err)
+		log.WithError(err).Warn("failure checking phone ban list")
+		return false, err
+	} else if isPhoneNumberBanned {
 		log.Info("denying phone prefix")
 		recordDenialEvent(ctx, actionReceivePayments, "phone prefix banned")
 		return false, nil
@@ -377,7 +442,7 @@ func (g *Guard) AllowReceivePayments(ctx context.Context, owner *common.Account,
 		return false, err
 	}
 
-	if count >= g.conf.paymentsPerFiveMinutes {
+	if count >= g.conf.getPaymentsPerFiveMinutes(ctx) {
 		log.Info("phone is rate limited by five minute payment count")
 		recordDenialEvent(ctx, actionReceivePayments, "five minute limit exceeded")
 		return false, nil
@@ -397,7 +462,7 @@ func (g *Guard) AllowReceivePayments(ctx context.Context, owner *common.Account,
 		return false, err
 	}
 
-	if count >= g.conf.paymentsPerHour {
+	if count >= g.conf.getPaymentsPerHour(ctx) {
 		log.Info("phone is rate limited by hourly payment count")
 		recordDenialEvent(ctx, actionReceivePayments, "hourly limit exceeded")
 		return false, nil
@@ -417,7 +482,7 @@ func (g *Guard) AllowReceivePayments(ctx context.Context, owner *common.Account,
 		return false, err
 	}
 
-	if count >= g.conf.paymentsPerDay {
+	if count >= g.conf.getPaymentsPerDay(ctx) {
 		log.Info("phone is rate limited by daily payment count")
 		recordDenialEvent(ctx, actionReceivePayments, "daily limit exceeded")
 		return false, nil
@@ -440,7 +505,12 @@ func (g *Guard) AllowEstablishNewRelationship(ctx context.Context, owner *common
 	log = client.InjectLoggingMetadata(ctx, log)
 
 	// Deny abusers from known IPs
-	if isIpBanned(ctx) {
+	isIpBanned, err := g.isIpBanned(ctx)
+	if err != nil {
+		tracer.OnError(err)
+		log.WithError(err).Warn("failure checking ip ban list")
+		return false, err
+	} else if isIpBanned {
 		log.Info("ip is banned")
 		recordDenialEvent(ctx, actionEstablishNewRelationship, "ip banned")
 		return false, nil
@@ -461,7 +531,12 @@ func (g *Guard) AllowEstablishNewRelationship(ctx context.Context, owner *common
 	log = log.WithField("phone", verification.PhoneNumber)
 
 	// Deny abusers from known phone ranges
-	if hasBannedPhoneNumberPrefix(verification.PhoneNumber) {
+	isPhoneNumberBanned, err := g.isPhoneNumberBanned(ctx, verification.PhoneNumber)
+	if err != nil {
+		tracer.OnError(err)
+		log.WithError(err).Warn("failure checking phone ban list")
+		return false, err
+	} else if isPhoneNumberBanned {
 		log.Info("denying phone prefix")
 		recordDenialEvent(ctx, actionEstablishNewRelationship, "phone prefix banned")
 		return false, nil
@@ -502,7 +577,7 @@ func (g *Guard) AllowEstablishNewRelationship(ctx context.Context, owner *common
 		return false, err
 	}
 
-	if count >= g.conf.maxNewRelationshipsPerDay {
+	if count >= g.conf.getMaxNewRelationshipsPerDay(ctx) {
 		log.Info("phone is rate limited by daily count")
 		recordDenialEvent(ctx, actionEstablishNewRelationship, "daily limit exceeded")
 		return false, nil
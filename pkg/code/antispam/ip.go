@@ -2,10 +2,39 @@ package antispam
 
 import (
 	"context"
+	"net"
+	"strings"
+
+	"github.com/code-payments/code-server/pkg/grpc/client"
 )
 
-// This isn't generally very useful (at least for incentive spammers). Let's
-// keep it clean for now.
-func isIpBanned(ctx context.Context) bool {
-	return false
+// getClientIp gets the client's IP, if it's known
+func getClientIp(ctx context.Context) (net.IP, bool) {
+	ipAddr, err := client.GetIPAddr(ctx)
+	if err != nil {
+		return nil, false
+	}
+
+	// The header may contain a list of proxies, where the first is the client
+	ip := net.ParseIP(strings.TrimSpace(strings.Split(ipAddr, ",")[0]))
+	return ip, ip != nil
+}
+
+// isHostingIp determines whether the client IP belongs to a hosting provider,
+// datacenter or anonymizing service, which are typical of automated abuse
+func (g *Guard) isHostingIp(ctx context.Context) (bool, error) {
+	if !g.conf.getDenyHostingIps(ctx) {
+		return false, nil
+	}
+
+	ip, ok := getClientIp(ctx)
+	if !ok {
+		return false, nil
+	}
+
+	metadata, err := g.data.GetIpMetadata(ctx, ip.String())
+	if err != nil {
+		return false, err
+	}
+	return metadata.IsHosting, nil
 }
@@ -9,20 +9,22 @@ import (
 const (
 	metricsStructName = "antispam.guard"
 
-	eventName = "AntispamGuardDenial"
+	eventName         = "AntispamGuardDenial"
+	highRiskEventName = "AntispamHighRiskEvent"
 
 	actionOpenAccounts             = "OpenAccounts"
 	actionSendPayment              = "SendPayment"
 	actionReceivePayments          = "ReceivePayments"
 	actionEstablishNewRelationship = "EstablishNewRelationship"
+	actionContactDiscovery         = "ContactDiscovery"
 
 	actionNewPhoneVerification     = "NewPhoneVerification"
 	actionSendSmsVerificationCode  = "SendSmsVerificationCode"
 	actionCheckSmsVerificationCode = "CheckSmsVerificationCode"
 	actionLinkAccount              = "LinkAccount"
 
-	actionWelcomeBonus  = "WelcomeBonus"
-	actionReferralBonus = "ReferralBonus"
+	actionCampaignPayout = "CampaignPayout"
+	actionReferralBonus  = "ReferralBonus"
 )
 
 func recordDenialEvent(ctx context.Context, action, reason string) {
@@ -33,3 +35,11 @@ func recordDenialEvent(ctx context.Context, action, reason string) {
 	}
 	metrics.RecordEvent(ctx, eventName, kvPairs)
 }
+
+func recordHighRiskEvent(ctx context.Context, eventType string) {
+	kvPairs := map[string]interface{}{
+		"event_type": eventType,
+		"count":      1,
+	}
+	metrics.RecordEvent(ctx, highRiskEventName, kvPairs)
+}
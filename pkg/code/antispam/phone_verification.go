@@ -26,14 +26,24 @@ func (g *Guard) AllowNewPhoneVerification(ctx context.Context, phoneNumber strin
 	log = client.InjectLoggingMetadata(ctx, log)
 
 	// Deny abusers from known IPs
-	if isIpBanned(ctx) {
+	isIpBanned, err := g.isIpBanned(ctx)
+	if err != nil {
+		tracer.OnError(err)
+		log.WithError(err).Warn("failure checking ip ban list")
+		return false, err
+	} else if isIpBanned {
 		log.Info("ip is banned")
 		recordDenialEvent(ctx, actionNewPhoneVerification, "ip banned")
 		return false, nil
 	}
 
 	// Deny abusers from known phone ranges
-	if hasBannedPhoneNumberPrefix(phoneNumber) {
+	isPhoneNumberBanned, err := g.isPhoneNumberBanned(ctx, phoneNumber)
+	if err != nil {
+		tracer.OnError(err)
+		log.WithError(err).Warn("failure checking phone ban list")
+		return false, err
+	} else if isPhoneNumberBanned {
 		log.Info("denying phone prefix")
 		recordDenialEvent(ctx, actionNewPhoneVerification, "phone prefix banned")
 		return false, nil
@@ -77,7 +87,7 @@ func (g *Guard) AllowNewPhoneVerification(ctx context.Context, phoneNumber strin
 		return false, nil
 	}
 
-	since := time.Now().Add(-1 * g.conf.phoneVerificationInterval)
+	since := time.Now().Add(-1 * g.conf.getPhoneVerificationInterval(ctx))
 	count, err := g.data.GetUniquePhoneVerificationIdCountForNumberSinceTimestamp(ctx, phoneNumber, since)
 	if err != nil {
 		tracer.OnError(err)
@@ -85,7 +95,7 @@ func (g *Guard) AllowNewPhoneVerification(ctx context.Context, phoneNumber strin
 		return false, err
 	}
 
-	if count >= g.conf.phoneVerificationsPerInternval {
+	if count >= g.conf.getPhoneVerificationsPerInterval(ctx) {
 		log.Info("phone is rate limited")
 		recordDenialEvent(ctx, actionNewPhoneVerification, "rate limit exceeded")
 		return false, nil
@@ -104,7 +114,7 @@ func (g *Guard) AllowSendSmsVerificationCode(ctx context.Context, phoneNumber st
 		"phone_number": phoneNumber,
 	})
 
-	since := time.Now().Add(-1 * g.conf.timePerSmsVerificationCodeSend)
+	since := time.Now().Add(-1 * g.conf.getTimePerSmsVerificationCodeSend(ctx))
 	count, err := g.data.GetPhoneEventCountForNumberByTypeSinceTimestamp(ctx, phoneNumber, phone.EventTypeVerificationCodeSent, since)
 	if err != nil {
 		tracer.OnError(err)
@@ -132,7 +142,7 @@ func (g *Guard) AllowCheckSmsVerificationCode(ctx context.Context, phoneNumber s
 	})
 	log = client.InjectLoggingMetadata(ctx, log)
 
-	since := time.Now().Add(-1 * g.conf.timePerSmsVerificationCodeCheck)
+	since := time.Now().Add(-1 * g.conf.getTimePerSmsVerificationCodeCheck(ctx))
 	count, err := g.data.GetPhoneEventCountForNumberByTypeSinceTimestamp(ctx, phoneNumber, phone.EventTypeCheckVerificationCode, since)
 	if err != nil {
 		tracer.OnError(err)
@@ -307,6 +307,8 @@ func insertAutoReturnPaymentHistoryItem(ctx context.Context, data code_data.Prov
 		InitiatorOwnerAccount: giftCardIssuedIntent.InitiatorOwnerAccount,
 		InitiatorPhoneNumber:  giftCardIssuedIntent.InitiatorPhoneNumber,
 
+		Mint: giftCardIssuedIntent.Mint,
+
 		ReceivePaymentsPubliclyMetadata: &intent.ReceivePaymentsPubliclyMetadata{
 			Source:       giftCardIssuedIntent.SendPrivatePaymentMetadata.DestinationTokenAccount,
 			Quantity:     giftCardIssuedIntent.SendPrivatePaymentMetadata.Quantity,
@@ -0,0 +1,52 @@
+package async_account
+
+import (
+	"context"
+	"time"
+
+	"github.com/newrelic/go-agent/v3/newrelic"
+
+	"github.com/code-payments/code-server/pkg/metrics"
+	phoneutil "github.com/code-payments/code-server/pkg/phone"
+	"github.com/code-payments/code-server/pkg/retry"
+)
+
+const (
+	phoneNumberHashBackfillBatchSize = 1000
+)
+
+// phoneNumberHashBackfillWorker computes contact discovery hashes for phone
+// verifications saved before the hashed index existed. It's a no-op once all
+// verifications have been backfilled.
+func (p *service) phoneNumberHashBackfillWorker(serviceCtx context.Context, interval time.Duration) error {
+	delay := interval
+
+	err := retry.Loop(
+		func() (err error) {
+			time.Sleep(delay)
+
+			nr := serviceCtx.Value(metrics.NewRelicContextKey).(*newrelic.Application)
+			m := nr.StartTransaction("async__account_service__handle_phone_number_hash_backfill")
+			defer m.End()
+			tracedCtx := newrelic.NewContext(serviceCtx, m)
+
+			updated, err := p.data.BackfillPhoneNumberHashes(tracedCtx, phoneNumberHashBackfillBatchSize)
+			if err == phoneutil.ErrContactDiscoverySaltNotLoaded {
+				// Contact discovery isn't configured, so there's nothing to index
+				return nil
+			} else if err != nil {
+				m.NoticeError(err)
+				return err
+			}
+
+			if updated > 0 {
+				p.log.WithField("updated", updated).Debug("backfilled phone number hashes")
+			}
+
+			return nil
+		},
+		retry.NonRetriableErrors(context.Canceled),
+	)
+
+	return err
+}
@@ -35,6 +35,13 @@ func (p *service) Start(ctx context.Context, interval time.Duration) error {
 		}
 	}()
 
+	go func() {
+		err := p.phoneNumberHashBackfillWorker(ctx, interval)
+		if err != nil && err != context.Canceled {
+			p.log.WithError(err).Warn("phone number hash backfill processing loop terminated unexpectedly")
+		}
+	}()
+
 	go func() {
 		err := p.metricsGaugeWorker(ctx)
 		if err != nil && err != context.Canceled {
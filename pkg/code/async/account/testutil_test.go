@@ -19,6 +19,7 @@ import (
 	"github.com/code-payments/code-server/pkg/testutil"
 	"github.com/code-payments/code-server/pkg/code/common"
 	code_data "github.com/code-payments/code-server/pkg/code/data"
+	datatest "github.com/code-payments/code-server/pkg/code/data/test"
 	"github.com/code-payments/code-server/pkg/code/data/account"
 	"github.com/code-payments/code-server/pkg/code/data/action"
 	"github.com/code-payments/code-server/pkg/code/data/currency"
@@ -42,7 +43,7 @@ type testGiftCard struct {
 }
 
 func setup(t *testing.T) *testEnv {
-	data := code_data.NewTestDataProvider()
+	data := datatest.NewDataProvider()
 
 	require.NoError(t, common.InjectTestSubsidizer(context.Background(), data, testutil.NewRandomAccount(t)))
 
@@ -0,0 +1,54 @@
+package async_campaign
+
+import (
+	"github.com/code-payments/code-server/pkg/config"
+	"github.com/code-payments/code-server/pkg/config/env"
+	"github.com/code-payments/code-server/pkg/config/memory"
+	"github.com/code-payments/code-server/pkg/config/wrapper"
+)
+
+const (
+	envConfigPrefix = "CAMPAIGN_SERVICE_"
+
+	AirdropperOwnerPublicKeyConfigEnvName = envConfigPrefix + "AIRDROPPER_OWNER_PUBLIC_KEY"
+	defaultAirdropperOwnerPublicKey       = ""
+
+	PayoutBatchSizeConfigEnvName = envConfigPrefix + "PAYOUT_BATCH_SIZE"
+	defaultPayoutBatchSize       = 100
+)
+
+type conf struct {
+	airdropperOwnerPublicKey config.String
+	payoutBatchSize          config.Uint64
+}
+
+// ConfigProvider defines how config values are pulled
+type ConfigProvider func() *conf
+
+// WithEnvConfigs returns configuration pulled from environment variables
+func WithEnvConfigs() ConfigProvider {
+	return func() *conf {
+		return &conf{
+			airdropperOwnerPublicKey: env.NewStringConfig(AirdropperOwnerPublicKeyConfigEnvName, defaultAirdropperOwnerPublicKey),
+			payoutBatchSize:          env.NewUint64Config(PayoutBatchSizeConfigEnvName, defaultPayoutBatchSize),
+		}
+	}
+}
+
+type testOverrides struct {
+	airdropperOwnerPublicKey string
+	payoutBatchSize          uint64
+}
+
+func withManualTestOverrides(overrides *testOverrides) ConfigProvider {
+	if overrides.payoutBatchSize == 0 {
+		overrides.payoutBatchSize = defaultPayoutBatchSize
+	}
+
+	return func() *conf {
+		return &conf{
+			airdropperOwnerPublicKey: wrapper.NewStringConfig(memory.NewConfig(overrides.airdropperOwnerPublicKey), defaultAirdropperOwnerPublicKey),
+			payoutBatchSize:          wrapper.NewUint64Config(memory.NewConfig(overrides.payoutBatchSize), defaultPayoutBatchSize),
+		}
+	}
+}
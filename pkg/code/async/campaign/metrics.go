@@ -0,0 +1,88 @@
+package async_campaign
+
+import (
+	"context"
+	"time"
+
+	"github.com/code-payments/code-server/pkg/kin"
+	"github.com/code-payments/code-server/pkg/metrics"
+	"github.com/code-payments/code-server/pkg/code/data/campaign"
+)
+
+const (
+	campaignSpendEventName       = "CampaignSpendPollingCheck"
+	payoutSubmittedEventName     = "CampaignPayoutSubmitted"
+	payoutFailedEventName        = "CampaignPayoutFailed"
+	insufficientBalanceEventName = "CampaignInsufficientAirdropperBalance"
+)
+
+func (p *service) metricsGaugeWorker(ctx context.Context) error {
+	delay := time.Second
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+			start := time.Now()
+
+			campaignRecords, err := p.data.GetAllCampaigns(ctx)
+			if err != nil {
+				continue
+			}
+
+			for _, campaignRecord := range campaignRecords {
+				spend, err := p.data.GetCampaignSpend(ctx, campaignRecord.Name)
+				if err != nil {
+					continue
+				}
+				recordCampaignSpendEvent(ctx, campaignRecord, spend)
+			}
+
+			delay = time.Second - time.Since(start)
+		}
+	}
+}
+
+func recordCampaignSpendEvent(ctx context.Context, campaignRecord *campaign.Record, spend *campaign.Spend) {
+	var remaining float64
+	if spend.UsdValue < campaignRecord.BudgetUsd {
+		remaining = campaignRecord.BudgetUsd - spend.UsdValue
+	}
+
+	metrics.RecordEvent(ctx, campaignSpendEventName, map[string]interface{}{
+		"campaign":      campaignRecord.Name,
+		"campaign_type": campaignRecord.Type.String(),
+		"is_active":     campaignRecord.IsActive(time.Now()),
+		"payouts":       spend.Payouts,
+		"spent_usd":     spend.UsdValue,
+		"spent_kin":     kin.FromQuarks(spend.Quantity),
+		"budget_usd":    campaignRecord.BudgetUsd,
+		"remaining_usd": remaining,
+	})
+}
+
+func recordPayoutSubmittedEvent(ctx context.Context, payoutRecord *campaign.Payout) {
+	metrics.RecordEvent(ctx, payoutSubmittedEventName, map[string]interface{}{
+		"campaign":  payoutRecord.Campaign,
+		"owner":     payoutRecord.OwnerAccount,
+		"currency":  string(payoutRecord.ExchangeCurrency),
+		"usd_value": payoutRecord.UsdValue,
+		"amount":    kin.FromQuarks(payoutRecord.Quantity),
+	})
+}
+
+func recordPayoutFailedEvent(ctx context.Context, payoutRecord *campaign.Payout) {
+	metrics.RecordEvent(ctx, payoutFailedEventName, map[string]interface{}{
+		"campaign":  payoutRecord.Campaign,
+		"owner":     payoutRecord.OwnerAccount,
+		"usd_value": payoutRecord.UsdValue,
+	})
+}
+
+func recordInsufficientBalanceEvent(ctx context.Context, campaignName string, quarks uint64) {
+	metrics.RecordEvent(ctx, insufficientBalanceEventName, map[string]interface{}{
+		"campaign": campaignName,
+		"required": kin.FromQuarks(quarks),
+	})
+}
@@ -0,0 +1,375 @@
+package async_campaign
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/mr-tron/base58"
+	"github.com/newrelic/go-agent/v3/newrelic"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	chatpb "github.com/code-payments/code-protobuf-api/generated/go/chat/v1"
+	commonpb "github.com/code-payments/code-protobuf-api/generated/go/common/v1"
+
+	"github.com/code-payments/code-server/pkg/kin"
+	"github.com/code-payments/code-server/pkg/metrics"
+	"github.com/code-payments/code-server/pkg/pointer"
+	timelock_token_v1 "github.com/code-payments/code-server/pkg/solana/timelock/v1"
+	"github.com/code-payments/code-server/pkg/code/balance"
+	chat_util "github.com/code-payments/code-server/pkg/code/chat"
+	"github.com/code-payments/code-server/pkg/code/common"
+	"github.com/code-payments/code-server/pkg/code/data/account"
+	"github.com/code-payments/code-server/pkg/code/data/action"
+	"github.com/code-payments/code-server/pkg/code/data/campaign"
+	"github.com/code-payments/code-server/pkg/code/data/chat"
+	"github.com/code-payments/code-server/pkg/code/data/fulfillment"
+	"github.com/code-payments/code-server/pkg/code/data/intent"
+	"github.com/code-payments/code-server/pkg/code/data/nonce"
+	push_util "github.com/code-payments/code-server/pkg/code/push"
+	"github.com/code-payments/code-server/pkg/code/transaction"
+)
+
+var (
+	errInsufficientAirdropperBalance = errors.New("insufficient airdropper balance")
+)
+
+func (p *service) payoutWorker(serviceCtx context.Context, interval time.Duration) error {
+	delay := interval
+
+	for {
+		select {
+		case <-serviceCtx.Done():
+			return serviceCtx.Err()
+		case <-time.After(delay):
+			start := time.Now()
+
+			func() {
+				nr := serviceCtx.Value(metrics.NewRelicContextKey).(*newrelic.Application)
+				m := nr.StartTransaction("async__campaign_service__handle_payouts")
+				defer m.End()
+				tracedCtx := newrelic.NewContext(serviceCtx, m)
+
+				err := p.processPendingPayouts(tracedCtx)
+				if err != nil {
+					m.NoticeError(err)
+				}
+			}()
+
+			delay = interval - time.Since(start)
+		}
+	}
+}
+
+func (p *service) processPendingPayouts(ctx context.Context) error {
+	payoutRecords, err := p.data.GetAllCampaignPayoutsByState(ctx, campaign.PayoutStatePending, p.conf.payoutBatchSize.Get(ctx))
+	if err == campaign.ErrPayoutNotFound {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	var lastErr error
+	for _, payoutRecord := range payoutRecords {
+		// Failures are isolated to each payout, so a single bad payout doesn't
+		// block all others. It stays pending and is retried on the next run.
+		err := p.processPayout(ctx, payoutRecord)
+		if err == errInsufficientAirdropperBalance {
+			// Remaining payouts stay pending until the airdropper is funded
+			return lastErr
+		} else if err != nil {
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
+// processPayout creates the intent that fulfills a pending payout. Payouts are
+// sent from the airdropper, which acts like an internal Code account that always
+// publicly transfers the amount like a Code->Code withdrawal.
+//
+// Payouts that can never be fulfilled are marked as failed, which releases their
+// value back into the campaign's budget.
+func (p *service) processPayout(ctx context.Context, payoutRecord *campaign.Payout) error {
+	log := p.log.WithFields(logrus.Fields{
+		"method":   "processPayout",
+		"campaign": payoutRecord.Campaign,
+		"owner":    payoutRecord.OwnerAccount,
+		"intent":   payoutRecord.IntentId,
+	})
+
+	campaignRecord, err := p.data.GetCampaign(ctx, payoutRecord.Campaign)
+	if err != nil {
+		log.WithError(err).Warn("failure getting campaign")
+		return err
+	}
+
+	owner, err := common.NewAccountFromPublicKeyString(payoutRecord.OwnerAccount)
+	if err != nil {
+		log.WithError(err).Warn("invalid owner account")
+		return p.markPayoutFailed(ctx, payoutRecord)
+	}
+
+	// Find the destination account, which will be the user's primary account
+	primaryAccountInfoRecord, err := p.data.GetLatestAccountInfoByOwnerAddressAndType(ctx, owner.PublicKey().ToBase58(), commonpb.AccountType_PRIMARY)
+	if err == account.ErrAccountInfoNotFound {
+		log.Info("owner cannot receive payout")
+		return p.markPayoutFailed(ctx, payoutRecord)
+	} else if err != nil {
+		log.WithError(err).Warn("failure getting primary account info record")
+		return err
+	}
+	destination, err := common.NewAccountFromPublicKeyString(primaryAccountInfoRecord.TokenAccount)
+	if err != nil {
+		log.WithError(err).Warn("invalid destination account")
+		return err
+	}
+
+	airdropper, err := p.getAirdropper(ctx)
+	if err != nil {
+		log.WithError(err).Warn("failure loading airdropper")
+		return err
+	}
+
+	p.airdropperMu.Lock()
+	defer p.airdropperMu.Unlock()
+
+	// Do a balance check. If there's insufficient balance, the payout waits
+	// until we get more funding.
+	balance, err := balance.DefaultCalculation(ctx, p.data, airdropper.Vault)
+	if err != nil {
+		log.WithError(err).Warn("failure getting airdropper balance")
+		return err
+	} else if balance < payoutRecord.Quantity {
+		log.WithFields(logrus.Fields{
+			"balance":  balance,
+			"required": payoutRecord.Quantity,
+		}).Warn("airdropper has insufficient balance")
+		recordInsufficientBalanceEvent(ctx, payoutRecord.Campaign, payoutRecord.Quantity)
+		return errInsufficientAirdropperBalance
+	}
+
+	selectedNonce, err := transaction.SelectAvailableNonce(ctx, p.data, nonce.PurposeInternalServerProcess)
+	if err != nil {
+		log.WithError(err).Warn("failure selecting available nonce")
+		return err
+	}
+	defer func() {
+		selectedNonce.ReleaseIfNotReserved()
+		selectedNonce.Unlock()
+	}()
+
+	txn, err := transaction.MakeTransferWithAuthorityTransaction(
+		selectedNonce.Account,
+		selectedNonce.Blockhash,
+		airdropper,
+		destination,
+		payoutRecord.Quantity,
+	)
+	if err != nil {
+		log.WithError(err).Warn("failure making solana transaction")
+		return err
+	}
+
+	err = txn.Sign(common.GetSubsidizer().PrivateKey().ToBytes(), airdropper.VaultOwner.PrivateKey().ToBytes())
+	if err != nil {
+		log.WithError(err).Warn("failure signing solana transaction")
+		return err
+	}
+
+	intentRecord := &intent.Record{
+		IntentId:   payoutRecord.IntentId,
+		IntentType: intent.SendPublicPayment,
+
+		// Payouts are made from the airdropper's Kin timelock account
+		Mint: kin.Mint,
+
+		SendPublicPaymentMetadata: &intent.SendPublicPaymentMetadata{
+			DestinationOwnerAccount: owner.PublicKey().ToBase58(),
+			DestinationTokenAccount: destination.PublicKey().ToBase58(),
+			Quantity:                payoutRecord.Quantity,
+
+			ExchangeCurrency: payoutRecord.ExchangeCurrency,
+			ExchangeRate:     payoutRecord.ExchangeRate,
+			NativeAmount:     payoutRecord.NativeAmount,
+			UsdMarketValue:   payoutRecord.UsdValue,
+
+			IsWithdrawal: true,
+		},
+
+		InitiatorOwnerAccount: airdropper.VaultOwner.PublicKey().ToBase58(),
+
+		State: intent.StateUnknown,
+
+		CreatedAt: time.Now(),
+	}
+
+	actionRecord := &action.Record{
+		Intent:     intentRecord.IntentId,
+		IntentType: intentRecord.IntentType,
+
+		ActionId:   0,
+		ActionType: action.NoPrivacyTransfer,
+
+		Source:      airdropper.Vault.PublicKey().ToBase58(),
+		Destination: pointer.String(destination.PublicKey().ToBase58()),
+
+		Quantity: pointer.Uint64(payoutRecord.Quantity),
+
+		State: action.StatePending,
+
+		CreatedAt: time.Now(),
+	}
+
+	fulfillmentRecord := &fulfillment.Record{
+		Intent:     intentRecord.IntentId,
+		IntentType: intentRecord.IntentType,
+
+		ActionId:   actionRecord.ActionId,
+		ActionType: actionRecord.ActionType,
+
+		FulfillmentType: fulfillment.NoPrivacyTransferWithAuthority,
+		Data:            txn.Marshal(),
+		Signature:       pointer.String(base58.Encode(txn.Signature())),
+
+		Nonce:     pointer.String(selectedNonce.Account.PublicKey().ToBase58()),
+		Blockhash: pointer.String(base58.Encode(selectedNonce.Blockhash[:])),
+
+		Source:      actionRecord.Source,
+		Destination: pointer.StringCopy(actionRecord.Destination),
+
+		DisableActiveScheduling: false,
+
+		// IntentOrderingIndex unknown until intent record is saved
+		ActionOrderingIndex:      0,
+		FulfillmentOrderingIndex: 0,
+
+		State: fulfillment.StateUnknown,
+
+		CreatedAt: time.Now(),
+	}
+
+	chatMessage, err := toChatMessage(campaignRecord.Type, intentRecord)
+	if err != nil {
+		log.WithError(err).Warn("failure creating chat message")
+		return err
+	}
+
+	var canPushChatMessage bool
+	err = p.data.ExecuteInTx(ctx, sql.LevelDefault, func(ctx context.Context) error {
+		err := p.data.SaveIntent(ctx, intentRecord)
+		if err != nil {
+			return err
+		}
+
+		err = p.data.PutAllActions(ctx, actionRecord)
+		if err != nil {
+			return err
+		}
+
+		fulfillmentRecord.IntentOrderingIndex = intentRecord.Id
+		err = p.data.PutAllFulfillments(ctx, fulfillmentRecord)
+		if err != nil {
+			return err
+		}
+
+		err = selectedNonce.MarkReservedWithSignature(ctx, *fulfillmentRecord.Signature)
+		if err != nil {
+			return err
+		}
+
+		canPushChatMessage, err = chat_util.SendChatMessage(ctx, p.data, chat_util.CodeTeamName, chat.ChatTypeInternal, true, owner, chatMessage, false)
+		if err != nil {
+			return err
+		}
+
+		err = p.data.UpdateCampaignPayoutState(ctx, payoutRecord.IntentId, campaign.PayoutStateSubmitted)
+		if err != nil {
+			return err
+		}
+
+		// Intent is pending only after everything's been saved.
+		intentRecord.State = intent.StatePending
+		return p.data.SaveIntent(ctx, intentRecord)
+	})
+	if err != nil {
+		log.WithError(err).Warn("failure creating payout intent")
+		return err
+	}
+
+	if canPushChatMessage {
+		// Best-effort send a push
+		push_util.SendChatMessagePushNotification(
+			ctx,
+			p.data,
+			p.pusher,
+			chat_util.CodeTeamName,
+			owner,
+			chatMessage,
+		)
+	}
+
+	log.Debug("created payout intent")
+	recordPayoutSubmittedEvent(ctx, payoutRecord)
+
+	return nil
+}
+
+func (p *service) markPayoutFailed(ctx context.Context, payoutRecord *campaign.Payout) error {
+	err := p.data.UpdateCampaignPayoutState(ctx, payoutRecord.IntentId, campaign.PayoutStateFailed)
+	if err != nil {
+		return err
+	}
+
+	recordPayoutFailedEvent(ctx, payoutRecord)
+	return nil
+}
+
+func (p *service) getAirdropper(ctx context.Context) (*common.TimelockAccounts, error) {
+	publicKey := p.conf.airdropperOwnerPublicKey.Get(ctx)
+	if len(publicKey) == 0 {
+		return nil, errors.New("airdropper isn't configured")
+	}
+
+	p.airdropperMu.Lock()
+	defer p.airdropperMu.Unlock()
+
+	if p.airdropper != nil && p.airdropper.VaultOwner.PublicKey().ToBase58() == publicKey {
+		return p.airdropper, nil
+	}
+
+	vaultRecord, err := p.data.GetKey(ctx, publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	ownerAccount, err := common.NewAccountFromPrivateKeyString(vaultRecord.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if ownerAccount.PublicKey().ToBase58() != publicKey {
+		return nil, errors.New("airdropper public key mismatch")
+	}
+
+	timelockAccounts, err := ownerAccount.GetTimelockAccounts(timelock_token_v1.DataVersion1)
+	if err != nil {
+		return nil, err
+	}
+
+	p.airdropper = timelockAccounts
+	return timelockAccounts, nil
+}
+
+func toChatMessage(campaignType campaign.Type, intentRecord *intent.Record) (*chatpb.ChatMessage, error) {
+	switch campaignType {
+	case campaign.WelcomeBonusType:
+		return chat_util.ToWelcomeBonusMessage(intentRecord)
+	case campaign.ReferralBonusType:
+		return chat_util.ToReferralBonusMessage(intentRecord)
+	case campaign.PromotionType:
+		return chat_util.ToPromotionBonusMessage(intentRecord)
+	}
+	return nil, errors.Errorf("no chat message defined for %s campaign", campaignType.String())
+}
@@ -0,0 +1,148 @@
+package async_campaign
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	commonpb "github.com/code-payments/code-protobuf-api/generated/go/common/v1"
+
+	"github.com/code-payments/code-server/pkg/kin"
+	"github.com/code-payments/code-server/pkg/code/data/account"
+	"github.com/code-payments/code-server/pkg/code/data/campaign"
+)
+
+func TestProcessPendingPayouts_HappyPath(t *testing.T) {
+	for _, campaignType := range []campaign.Type{
+		campaign.WelcomeBonusType,
+		campaign.ReferralBonusType,
+		campaign.PromotionType,
+	} {
+		env := setup(t, &testOverrides{})
+		env.fundAirdropper(t, kin.ToQuarks(1_000))
+		env.generateAvailableNonces(t, 10)
+
+		campaignRecord := env.setupCampaign(t, campaignType)
+
+		var payoutRecords []*campaign.Payout
+		for i := 0; i < 3; i++ {
+			payoutRecords = append(payoutRecords, env.simulatePayout(t, campaignRecord, env.setupUser(t, true)))
+		}
+
+		require.NoError(t, env.worker.processPendingPayouts(env.ctx))
+
+		for _, payoutRecord := range payoutRecords {
+			env.assertPayoutIntentCreated(t, payoutRecord)
+		}
+
+		spend, err := env.data.GetCampaignSpend(env.ctx, campaignRecord.Name)
+		require.NoError(t, err)
+		assert.EqualValues(t, 3, spend.Payouts)
+		assert.Equal(t, kin.ToQuarks(33), spend.Quantity)
+
+		// Submitted payouts are never processed again
+		require.NoError(t, env.worker.processPendingPayouts(env.ctx))
+		for _, payoutRecord := range payoutRecords {
+			env.assertPayoutIntentCreated(t, payoutRecord)
+		}
+	}
+}
+
+func TestProcessPendingPayouts_MultipleReferralBonusesForOwner(t *testing.T) {
+	env := setup(t, &testOverrides{})
+	env.fundAirdropper(t, kin.ToQuarks(1_000))
+	env.generateAvailableNonces(t, 10)
+
+	campaignRecord := env.setupCampaign(t, campaign.ReferralBonusType)
+	campaignRecord.EligibilityRules.MaxPayoutsPerOwner = 3
+	require.NoError(t, env.data.PutCampaign(env.ctx, campaignRecord))
+
+	// A referrer receives a bonus for each user they refer
+	referrer := env.setupUser(t, true)
+	var payoutRecords []*campaign.Payout
+	for i := 0; i < 3; i++ {
+		payoutRecords = append(payoutRecords, env.simulatePayout(t, campaignRecord, referrer))
+	}
+
+	exceeding := payoutRecords[0].Clone()
+	exceeding.IntentId = "exceeding"
+	assert.Equal(t, campaign.ErrPayoutExists, env.data.CreateCampaignPayout(env.ctx, &exceeding))
+
+	require.NoError(t, env.worker.processPendingPayouts(env.ctx))
+
+	for _, payoutRecord := range payoutRecords {
+		env.assertPayoutIntentCreated(t, payoutRecord)
+	}
+
+	spend, err := env.data.GetCampaignSpend(env.ctx, campaignRecord.Name)
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, spend.Payouts)
+	assert.Equal(t, kin.ToQuarks(33), spend.Quantity)
+}
+
+func TestProcessPendingPayouts_NoPrimaryAccount(t *testing.T) {
+	env := setup(t, &testOverrides{})
+	env.fundAirdropper(t, kin.ToQuarks(1_000))
+	env.generateAvailableNonces(t, 10)
+
+	campaignRecord := env.setupCampaign(t, campaign.WelcomeBonusType)
+	payoutRecord := env.simulatePayout(t, campaignRecord, env.setupUser(t, false))
+
+	require.NoError(t, env.worker.processPendingPayouts(env.ctx))
+	env.assertPayoutState(t, payoutRecord, campaign.PayoutStateFailed)
+	env.assertNoPayoutIntentCreated(t, payoutRecord)
+
+	spend, err := env.data.GetCampaignSpend(env.ctx, campaignRecord.Name)
+	require.NoError(t, err)
+	assert.EqualValues(t, 0, spend.Payouts)
+	assert.EqualValues(t, 0, spend.UsdValue)
+}
+
+func TestProcessPendingPayouts_InsufficientAirdropperBalance(t *testing.T) {
+	env := setup(t, &testOverrides{})
+	env.fundAirdropper(t, kin.ToQuarks(15))
+	env.generateAvailableNonces(t, 10)
+
+	campaignRecord := env.setupCampaign(t, campaign.WelcomeBonusType)
+	funded := env.simulatePayout(t, campaignRecord, env.setupUser(t, true))
+	unfunded := env.simulatePayout(t, campaignRecord, env.setupUser(t, true))
+
+	require.NoError(t, env.worker.processPendingPayouts(env.ctx))
+	env.assertPayoutIntentCreated(t, funded)
+	env.assertPayoutState(t, unfunded, campaign.PayoutStatePending)
+
+	_, err := env.data.GetIntent(env.ctx, unfunded.IntentId)
+	assert.Error(t, err)
+
+	// Payouts resume once the airdropper is funded
+	env.fundAirdropper(t, kin.ToQuarks(1_000))
+	require.NoError(t, env.worker.processPendingPayouts(env.ctx))
+	env.assertPayoutIntentCreated(t, unfunded)
+}
+
+func TestProcessPendingPayouts_FailuresAreIsolated(t *testing.T) {
+	env := setup(t, &testOverrides{})
+	env.fundAirdropper(t, kin.ToQuarks(1_000))
+	env.generateAvailableNonces(t, 10)
+
+	campaignRecord := env.setupCampaign(t, campaign.WelcomeBonusType)
+
+	// The primary account is corrupted, so the payout can't be processed
+	brokenOwner := env.setupUser(t, false)
+	require.NoError(t, env.data.CreateAccountInfo(env.ctx, &account.Record{
+		OwnerAccount:     brokenOwner.PublicKey().ToBase58(),
+		AuthorityAccount: brokenOwner.PublicKey().ToBase58(),
+		TokenAccount:     "invalid",
+		AccountType:      commonpb.AccountType_PRIMARY,
+		CreatedAt:        time.Now(),
+	}))
+	broken := env.simulatePayout(t, campaignRecord, brokenOwner)
+
+	healthy := env.simulatePayout(t, campaignRecord, env.setupUser(t, true))
+
+	assert.Error(t, env.worker.processPendingPayouts(env.ctx))
+	env.assertPayoutState(t, broken, campaign.PayoutStatePending)
+	env.assertPayoutIntentCreated(t, healthy)
+}
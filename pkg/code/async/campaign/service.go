@@ -0,0 +1,56 @@
+package async_campaign
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/code-payments/code-server/pkg/code/async"
+	"github.com/code-payments/code-server/pkg/code/common"
+	code_data "github.com/code-payments/code-server/pkg/code/data"
+	push_lib "github.com/code-payments/code-server/pkg/push"
+)
+
+type service struct {
+	log    *logrus.Entry
+	conf   *conf
+	data   code_data.Provider
+	pusher push_lib.Provider
+
+	// Payouts are serialized against the airdropper, so balance checks account
+	// for all previously created payout intents
+	airdropperMu sync.Mutex
+	airdropper   *common.TimelockAccounts
+}
+
+func New(data code_data.Provider, pusher push_lib.Provider, configProvider ConfigProvider) async.Service {
+	return &service{
+		log:    logrus.StandardLogger().WithField("service", "campaign"),
+		conf:   configProvider(),
+		data:   data,
+		pusher: pusher,
+	}
+}
+
+func (p *service) Start(ctx context.Context, interval time.Duration) error {
+	go func() {
+		err := p.payoutWorker(ctx, interval)
+		if err != nil && err != context.Canceled {
+			p.log.WithError(err).Warn("campaign payout processing loop terminated unexpectedly")
+		}
+	}()
+
+	go func() {
+		err := p.metricsGaugeWorker(ctx)
+		if err != nil && err != context.Canceled {
+			p.log.WithError(err).Warn("campaign metrics gauge loop terminated unexpectedly")
+		}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,259 @@
+package async_campaign
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mr-tron/base58"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	commonpb "github.com/code-payments/code-protobuf-api/generated/go/common/v1"
+
+	currency_lib "github.com/code-payments/code-server/pkg/currency"
+	"github.com/code-payments/code-server/pkg/kin"
+	memory_push "github.com/code-payments/code-server/pkg/push/memory"
+	"github.com/code-payments/code-server/pkg/solana"
+	timelock_token_v1 "github.com/code-payments/code-server/pkg/solana/timelock/v1"
+	"github.com/code-payments/code-server/pkg/testutil"
+	chat_util "github.com/code-payments/code-server/pkg/code/chat"
+	"github.com/code-payments/code-server/pkg/code/common"
+	code_data "github.com/code-payments/code-server/pkg/code/data"
+	datatest "github.com/code-payments/code-server/pkg/code/data/test"
+	"github.com/code-payments/code-server/pkg/code/data/account"
+	"github.com/code-payments/code-server/pkg/code/data/action"
+	"github.com/code-payments/code-server/pkg/code/data/campaign"
+	"github.com/code-payments/code-server/pkg/code/data/chat"
+	"github.com/code-payments/code-server/pkg/code/data/deposit"
+	"github.com/code-payments/code-server/pkg/code/data/fulfillment"
+	"github.com/code-payments/code-server/pkg/code/data/intent"
+	"github.com/code-payments/code-server/pkg/code/data/nonce"
+	"github.com/code-payments/code-server/pkg/code/data/transaction"
+	"github.com/code-payments/code-server/pkg/code/data/vault"
+)
+
+type testEnv struct {
+	ctx        context.Context
+	data       code_data.Provider
+	worker     *service
+	subsidizer *common.Account
+	airdropper *common.TimelockAccounts
+}
+
+func setup(t *testing.T, testOverrides *testOverrides) *testEnv {
+	ctx := context.Background()
+
+	db := datatest.NewDataProvider()
+
+	subsidizer := testutil.SetupRandomSubsidizer(t, db)
+
+	airdropperOwner := testutil.NewRandomAccount(t)
+	require.NoError(t, db.SaveKey(ctx, &vault.Record{
+		PublicKey:  airdropperOwner.PublicKey().ToBase58(),
+		PrivateKey: airdropperOwner.PrivateKey().ToBase58(),
+		State:      vault.StateAvailable,
+		CreatedAt:  time.Now(),
+	}))
+
+	airdropper, err := airdropperOwner.GetTimelockAccounts(timelock_token_v1.DataVersion1)
+	require.NoError(t, err)
+	require.NoError(t, db.SaveTimelock(ctx, airdropper.ToDBRecord()))
+
+	testOverrides.airdropperOwnerPublicKey = airdropperOwner.PublicKey().ToBase58()
+
+	return &testEnv{
+		ctx:        ctx,
+		data:       db,
+		worker:     New(db, memory_push.NewPushProvider(), withManualTestOverrides(testOverrides)).(*service),
+		subsidizer: subsidizer,
+		airdropper: airdropper,
+	}
+}
+
+func (e *testEnv) fundAirdropper(t *testing.T, quarks uint64) {
+	depositRecord := &deposit.Record{
+		Signature:      fmt.Sprintf("txn%d", time.Now().UnixNano()),
+		Destination:    e.airdropper.Vault.PublicKey().ToBase58(),
+		Amount:         quarks,
+		UsdMarketValue: 0.1 * float64(quarks) / float64(kin.QuarksPerKin),
+
+		ConfirmationState: transaction.ConfirmationFinalized,
+		Slot:              12345,
+	}
+	require.NoError(t, e.data.SaveExternalDeposit(e.ctx, depositRecord))
+}
+
+func (e *testEnv) generateAvailableNonces(t *testing.T, count int) {
+	for i := 0; i < count; i++ {
+		nonceAccount := testutil.NewRandomAccount(t)
+
+		var bh solana.Blockhash
+		rand.Read(bh[:])
+
+		nonceKey := &vault.Record{
+			PublicKey:  nonceAccount.PublicKey().ToBase58(),
+			PrivateKey: nonceAccount.PrivateKey().ToBase58(),
+			State:      vault.StateAvailable,
+			CreatedAt:  time.Now(),
+		}
+		nonceRecord := &nonce.Record{
+			Address:   nonceAccount.PublicKey().ToBase58(),
+			Authority: e.subsidizer.PublicKey().ToBase58(),
+			Blockhash: base58.Encode(bh[:]),
+			Purpose:   nonce.PurposeInternalServerProcess,
+			State:     nonce.StateAvailable,
+		}
+		require.NoError(t, e.data.SaveKey(e.ctx, nonceKey))
+		require.NoError(t, e.data.SaveNonce(e.ctx, nonceRecord))
+	}
+}
+
+func (e *testEnv) setupCampaign(t *testing.T, campaignType campaign.Type) *campaign.Record {
+	campaignRecord := &campaign.Record{
+		Name: campaignType.String(),
+		Type: campaignType,
+
+		Amounts: map[currency_lib.Code]float64{
+			currency_lib.USD: 1.0,
+		},
+		BudgetUsd: 100,
+
+		EligibilityRules: campaign.EligibilityRules{
+			MaxPayoutsPerPhoneNumber: 1,
+			MaxPayoutsPerOwner:       1,
+		},
+
+		StartsAt: time.Now().Add(-time.Hour),
+		EndsAt:   time.Now().Add(time.Hour),
+
+		State: campaign.StateEnabled,
+
+		CreatedAt: time.Now(),
+	}
+	require.NoError(t, e.data.PutCampaign(e.ctx, campaignRecord))
+	return campaignRecord
+}
+
+func (e *testEnv) setupUser(t *testing.T, withPrimaryAccount bool) *common.Account {
+	owner := testutil.NewRandomAccount(t)
+
+	if withPrimaryAccount {
+		timelockAccounts, err := owner.GetTimelockAccounts(timelock_token_v1.DataVersion1)
+		require.NoError(t, err)
+
+		accountInfoRecord := &account.Record{
+			OwnerAccount:     owner.PublicKey().ToBase58(),
+			AuthorityAccount: owner.PublicKey().ToBase58(),
+			TokenAccount:     timelockAccounts.Vault.PublicKey().ToBase58(),
+
+			AccountType: commonpb.AccountType_PRIMARY,
+			Index:       0,
+
+			CreatedAt: time.Now(),
+		}
+		require.NoError(t, e.data.CreateAccountInfo(e.ctx, accountInfoRecord))
+	}
+
+	return owner
+}
+
+func (e *testEnv) simulatePayout(t *testing.T, campaignRecord *campaign.Record, owner *common.Account) *campaign.Payout {
+	payoutRecord := &campaign.Payout{
+		Campaign: campaignRecord.Name,
+
+		IntentId: testutil.NewRandomAccount(t).PublicKey().ToBase58(),
+
+		OwnerAccount: owner.PublicKey().ToBase58(),
+		PhoneNumber:  "+12223334444",
+
+		ExchangeCurrency: currency_lib.USD,
+		ExchangeRate:     0.1,
+		NativeAmount:     1.0,
+		UsdValue:         1.0,
+		Quantity:         kin.ToQuarks(11),
+
+		State: campaign.PayoutStatePending,
+
+		CreatedAt: time.Now(),
+	}
+	require.NoError(t, e.data.CreateCampaignPayout(e.ctx, payoutRecord))
+	return payoutRecord
+}
+
+func (e *testEnv) assertPayoutState(t *testing.T, payoutRecord *campaign.Payout, expected campaign.PayoutState) {
+	actual, err := e.data.GetCampaignPayoutByIntentId(e.ctx, payoutRecord.IntentId)
+	require.NoError(t, err)
+	assert.Equal(t, expected, actual.State)
+}
+
+func (e *testEnv) assertPayoutIntentCreated(t *testing.T, payoutRecord *campaign.Payout) {
+	e.assertPayoutState(t, payoutRecord, campaign.PayoutStateSubmitted)
+
+	destination, err := e.data.GetLatestAccountInfoByOwnerAddressAndType(e.ctx, payoutRecord.OwnerAccount, commonpb.AccountType_PRIMARY)
+	require.NoError(t, err)
+
+	intentRecord, err := e.data.GetIntent(e.ctx, payoutRecord.IntentId)
+	require.NoError(t, err)
+	assert.Equal(t, intent.SendPublicPayment, intentRecord.IntentType)
+	assert.Equal(t, kin.Mint, intentRecord.Mint)
+	assert.Equal(t, e.airdropper.VaultOwner.PublicKey().ToBase58(), intentRecord.InitiatorOwnerAccount)
+	assert.Equal(t, payoutRecord.OwnerAccount, intentRecord.SendPublicPaymentMetadata.DestinationOwnerAccount)
+	assert.Equal(t, destination.TokenAccount, intentRecord.SendPublicPaymentMetadata.DestinationTokenAccount)
+	assert.Equal(t, payoutRecord.Quantity, intentRecord.SendPublicPaymentMetadata.Quantity)
+	assert.Equal(t, payoutRecord.ExchangeCurrency, intentRecord.SendPublicPaymentMetadata.ExchangeCurrency)
+	assert.Equal(t, payoutRecord.ExchangeRate, intentRecord.SendPublicPaymentMetadata.ExchangeRate)
+	assert.Equal(t, payoutRecord.NativeAmount, intentRecord.SendPublicPaymentMetadata.NativeAmount)
+	assert.Equal(t, payoutRecord.UsdValue, intentRecord.SendPublicPaymentMetadata.UsdMarketValue)
+	assert.True(t, intentRecord.SendPublicPaymentMetadata.IsWithdrawal)
+	assert.Equal(t, intent.StatePending, intentRecord.State)
+
+	actionRecords, err := e.data.GetAllActionsByIntent(e.ctx, payoutRecord.IntentId)
+	require.NoError(t, err)
+	require.Len(t, actionRecords, 1)
+	assert.Equal(t, action.NoPrivacyTransfer, actionRecords[0].ActionType)
+	assert.Equal(t, e.airdropper.Vault.PublicKey().ToBase58(), actionRecords[0].Source)
+	assert.Equal(t, destination.TokenAccount, *actionRecords[0].Destination)
+	assert.Equal(t, payoutRecord.Quantity, *actionRecords[0].Quantity)
+	assert.Equal(t, action.StatePending, actionRecords[0].State)
+
+	fulfillmentRecords, err := e.data.GetAllFulfillmentsByAction(e.ctx, payoutRecord.IntentId, 0)
+	require.NoError(t, err)
+	require.Len(t, fulfillmentRecords, 1)
+	fulfillmentRecord := fulfillmentRecords[0]
+	assert.Equal(t, fulfillment.NoPrivacyTransferWithAuthority, fulfillmentRecord.FulfillmentType)
+	assert.Equal(t, e.airdropper.Vault.PublicKey().ToBase58(), fulfillmentRecord.Source)
+	assert.Equal(t, destination.TokenAccount, *fulfillmentRecord.Destination)
+	assert.Equal(t, intentRecord.Id, fulfillmentRecord.IntentOrderingIndex)
+	assert.Equal(t, fulfillment.StateUnknown, fulfillmentRecord.State)
+
+	nonceRecord, err := e.data.GetNonce(e.ctx, *fulfillmentRecord.Nonce)
+	require.NoError(t, err)
+	assert.Equal(t, nonce.PurposeInternalServerProcess, nonceRecord.Purpose)
+	assert.Equal(t, nonce.StateReserved, nonceRecord.State)
+	assert.Equal(t, *fulfillmentRecord.Signature, nonceRecord.Signature)
+
+	owner, err := common.NewAccountFromPublicKeyString(payoutRecord.OwnerAccount)
+	require.NoError(t, err)
+	messageRecords, err := e.data.GetAllChatMessages(e.ctx, chat.GetChatId(chat_util.CodeTeamName, owner.PublicKey().ToBase58(), true))
+	require.NoError(t, err)
+
+	var messagesForPayout int
+	for _, messageRecord := range messageRecords {
+		if messageRecord.MessageId == payoutRecord.IntentId {
+			messagesForPayout++
+		}
+	}
+	assert.Equal(t, 1, messagesForPayout)
+}
+
+func (e *testEnv) assertNoPayoutIntentCreated(t *testing.T, payoutRecord *campaign.Payout) {
+	_, err := e.data.GetIntent(e.ctx, payoutRecord.IntentId)
+	assert.Equal(t, intent.ErrIntentNotFound, err)
+
+	count, err := e.data.GetNonceCountByState(e.ctx, nonce.StateReserved)
+	require.NoError(t, err)
+	assert.EqualValues(t, 0, count)
+}
@@ -0,0 +1,46 @@
+package async_commitment
+
+import (
+	"github.com/code-payments/code-server/pkg/config"
+	"github.com/code-payments/code-server/pkg/config/env"
+	"github.com/code-payments/code-server/pkg/config/memory"
+	"github.com/code-payments/code-server/pkg/config/wrapper"
+)
+
+const (
+	envConfigPrefix = "COMMITMENT_SERVICE_"
+
+	// AddressLookupTableConfigEnvName is the address of the lookup table used
+	// to compile versioned proof upload transactions. When empty, legacy
+	// transactions are used.
+	AddressLookupTableConfigEnvName = envConfigPrefix + "ADDRESS_LOOKUP_TABLE"
+	defaultAddressLookupTable       = ""
+)
+
+type conf struct {
+	addressLookupTable config.String
+}
+
+// ConfigProvider defines how config values are pulled
+type ConfigProvider func() *conf
+
+// WithEnvConfigs returns configuration pulled from environment variables
+func WithEnvConfigs() ConfigProvider {
+	return func() *conf {
+		return &conf{
+			addressLookupTable: env.NewStringConfig(AddressLookupTableConfigEnvName, defaultAddressLookupTable),
+		}
+	}
+}
+
+type testOverrides struct {
+	addressLookupTable string
+}
+
+func withManualTestOverrides(overrides *testOverrides) ConfigProvider {
+	return func() *conf {
+		return &conf{
+			addressLookupTable: wrapper.NewStringConfig(memory.NewConfig(overrides.addressLookupTable), defaultAddressLookupTable),
+		}
+	}
+}
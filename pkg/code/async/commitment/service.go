@@ -7,19 +7,26 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/code-payments/code-server/pkg/code/async"
+	async_sharding "github.com/code-payments/code-server/pkg/code/async/sharding"
 	code_data "github.com/code-payments/code-server/pkg/code/data"
 	"github.com/code-payments/code-server/pkg/code/data/commitment"
 )
 
 type service struct {
-	log  *logrus.Entry
-	data code_data.Provider
+	log        *logrus.Entry
+	conf       *conf
+	data       code_data.Provider
+	membership *async_sharding.Membership
 }
 
-func New(data code_data.Provider) async.Service {
+// New returns a new commitment service. Commitments are sharded by intent, like
+// fulfillments in the sequencer, when a group membership is provided.
+func New(data code_data.Provider, configProvider ConfigProvider, membership *async_sharding.Membership) async.Service {
 	return &service{
-		log:  logrus.StandardLogger().WithField("service", "commitment"),
-		data: data,
+		log:        logrus.StandardLogger().WithField("service", "commitment"),
+		conf:       configProvider(),
+		data:       data,
+		membership: membership,
 	}
 }
 
@@ -24,6 +24,7 @@ import (
 	"github.com/code-payments/code-server/pkg/testutil"
 	"github.com/code-payments/code-server/pkg/code/common"
 	code_data "github.com/code-payments/code-server/pkg/code/data"
+	datatest "github.com/code-payments/code-server/pkg/code/data/test"
 	"github.com/code-payments/code-server/pkg/code/data/action"
 	"github.com/code-payments/code-server/pkg/code/data/commitment"
 	"github.com/code-payments/code-server/pkg/code/data/fulfillment"
@@ -46,7 +47,7 @@ type testEnv struct {
 func setup(t *testing.T) testEnv {
 	ctx := context.Background()
 
-	db := code_data.NewTestDataProvider()
+	db := datatest.NewDataProvider()
 
 	privacyUpgradeCandidateSelectionTimeout = 0
 
@@ -103,7 +104,7 @@ func setup(t *testing.T) testEnv {
 		data:         db,
 		treasuryPool: treasuryPool,
 		merkleTree:   merkleTree,
-		worker:       New(db).(*service),
+		worker:       New(db, withManualTestOverrides(&testOverrides{}), nil).(*service),
 		subsidizer:   subsidizer,
 	}
 }
@@ -141,6 +142,8 @@ func (e testEnv) simulateCommitment(t *testing.T, recentRoot string, state commi
 
 		InitiatorOwnerAccount: owner.PublicKey().ToBase58(),
 
+		Mint: kin.Mint,
+
 		SendPrivatePaymentMetadata: &intent.SendPrivatePaymentMetadata{
 			DestinationTokenAccount: testutil.NewRandomAccount(t).PublicKey().ToBase58(),
 			Quantity:                kin.ToQuarks(100),
@@ -8,18 +8,22 @@ import (
 
 	"github.com/mr-tron/base58"
 
-	"github.com/code-payments/code-server/pkg/kin"
+	"github.com/code-payments/code-server/pkg/mint"
 	"github.com/code-payments/code-server/pkg/solana"
+	address_lookup_table "github.com/code-payments/code-server/pkg/solana/addresslookuptable"
 	splitter_token "github.com/code-payments/code-server/pkg/solana/splitter"
 	"github.com/code-payments/code-server/pkg/code/common"
 	"github.com/code-payments/code-server/pkg/code/data/commitment"
 	"github.com/code-payments/code-server/pkg/code/data/merkletree"
+	"github.com/code-payments/code-server/pkg/code/transaction"
 )
 
 type commitmentManagementAccounts struct {
 	Commitment      ed25519.PublicKey
 	CommitmentVault ed25519.PublicKey
 
+	Mint ed25519.PublicKey
+
 	Pool      ed25519.PublicKey
 	PoolVault ed25519.PublicKey
 
@@ -44,6 +48,16 @@ func (p *service) getCommitmentManagementTxnAccountsAndArgs(
 		return nil, nil, err
 	}
 
+	intentRecord, err := p.data.GetIntent(ctx, commitmentRecord.Intent)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tokenMint, err := mint.Get(intentRecord.Mint)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	poolAddressBytes, err := base58.Decode(treasuryPoolRecord.Address)
 	if err != nil {
 		return nil, nil, err
@@ -125,6 +139,8 @@ func (p *service) getCommitmentManagementTxnAccountsAndArgs(
 		Commitment:      commitmentAddressBytes,
 		CommitmentVault: commitmentVaultAddressBytes,
 
+		Mint: tokenMint.Address,
+
 		Pool:      poolAddressBytes,
 		PoolVault: poolVaultAddressBytes,
 
@@ -186,6 +202,57 @@ func makeUploadPartialProofInstructions(accounts *commitmentManagementAccounts,
 	return []solana.Instruction{uploadProofInstruction}
 }
 
+// makeVersionedUploadPartialProofInstructions batches the upload of the merkle
+// proof into as few transactions as possible, given the accounts that can be
+// loaded from the provided lookup table.
+func makeVersionedUploadPartialProofInstructions(accounts *commitmentManagementAccounts, args *commitmentManagementArgs, lookupTable solana.AddressLookupTable) ([][]solana.Instruction, error) {
+	// Transaction size is independent of the nonce used, so we can use any
+	// account not in the lookup table to estimate it.
+	placeholderNonce, err := common.NewRandomAccount()
+	if err != nil {
+		return nil, err
+	}
+
+	getTxnSize := func(fromChunkInclusive, toChunkInclusive int) (int, error) {
+		txn, err := transaction.MakeNoncedVersionedTransaction(
+			placeholderNonce,
+			solana.Blockhash{},
+			[]solana.AddressLookupTable{lookupTable},
+			makeUploadPartialProofInstructions(accounts, args, fromChunkInclusive, toChunkInclusive)...,
+		)
+		if err != nil {
+			return 0, err
+		}
+		return len(txn.Marshal()), nil
+	}
+
+	var batches [][]solana.Instruction
+	for fromChunkInclusive := 0; fromChunkInclusive < len(args.MerkleProof); {
+		toChunkInclusive := fromChunkInclusive
+		for toChunkInclusive+1 < len(args.MerkleProof) {
+			txnSize, err := getTxnSize(fromChunkInclusive, toChunkInclusive+1)
+			if err != nil {
+				return nil, err
+			} else if txnSize > solana.MaxTransactionSize {
+				break
+			}
+			toChunkInclusive++
+		}
+
+		txnSize, err := getTxnSize(fromChunkInclusive, toChunkInclusive)
+		if err != nil {
+			return nil, err
+		} else if txnSize > solana.MaxTransactionSize {
+			return nil, errors.New("proof upload transaction exceeds max size")
+		}
+
+		batches = append(batches, makeUploadPartialProofInstructions(accounts, args, fromChunkInclusive, toChunkInclusive))
+		fromChunkInclusive = toChunkInclusive + 1
+	}
+
+	return batches, nil
+}
+
 func makeVerifyProofInstructions(accounts *commitmentManagementAccounts, args *commitmentManagementArgs) []solana.Instruction {
 	verifyProofInstruction := splitter_token.NewVerifyProofInstruction(
 		&splitter_token.VerifyProofInstructionAccounts{
@@ -209,7 +276,7 @@ func makeOpenCommitmentVaultInstructions(accounts *commitmentManagementAccounts,
 			Pool:            accounts.Pool,
 			Proof:           accounts.Proof,
 			CommitmentVault: accounts.CommitmentVault,
-			Mint:            kin.TokenMint,
+			Mint:            accounts.Mint,
 			Authority:       common.GetSubsidizer().PublicKey().ToBytes(),
 			Payer:           common.GetSubsidizer().PublicKey().ToBytes(),
 		},
@@ -254,3 +321,24 @@ func makeCloseCommitmentVaultInstructions(accounts *commitmentManagementAccounts
 
 	return []solana.Instruction{closeVaultInstruction, closeProofInstruction}
 }
+
+// getAddressLookupTable returns the configured address lookup table used to
+// compile versioned transactions, or nil when one isn't configured.
+func (p *service) getAddressLookupTable(ctx context.Context) (*solana.AddressLookupTable, error) {
+	address := p.conf.addressLookupTable.Get(ctx)
+	if len(address) == 0 {
+		return nil, nil
+	}
+
+	addressBytes, err := base58.Decode(address)
+	if err != nil {
+		return nil, err
+	}
+
+	accountInfo, err := p.data.GetBlockchainAccountInfo(ctx, address, solana.CommitmentFinalized)
+	if err != nil {
+		return nil, err
+	}
+
+	return address_lookup_table.GetLookupTableFromAccount(addressBytes, *accountInfo)
+}
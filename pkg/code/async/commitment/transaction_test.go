@@ -0,0 +1,80 @@
+package async_commitment
+
+import (
+	"context"
+	"crypto/ed25519"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/code-payments/code-server/pkg/code/common"
+	"github.com/code-payments/code-server/pkg/code/data/merkletree"
+	"github.com/code-payments/code-server/pkg/code/transaction"
+	"github.com/code-payments/code-server/pkg/solana"
+	"github.com/code-payments/code-server/pkg/solana/system"
+	"github.com/code-payments/code-server/pkg/testutil"
+)
+
+func TestMakeVersionedUploadPartialProofInstructions(t *testing.T) {
+	require.NoError(t, common.InjectTestSubsidizer(context.Background(), nil, testutil.NewRandomAccount(t)))
+
+	accounts := &commitmentManagementAccounts{
+		Commitment:      testutil.NewRandomAccount(t).PublicKey().ToBytes(),
+		CommitmentVault: testutil.NewRandomAccount(t).PublicKey().ToBytes(),
+		Pool:            testutil.NewRandomAccount(t).PublicKey().ToBytes(),
+		PoolVault:       testutil.NewRandomAccount(t).PublicKey().ToBytes(),
+		Proof:           testutil.NewRandomAccount(t).PublicKey().ToBytes(),
+	}
+
+	args := &commitmentManagementArgs{
+		PoolBump:  253,
+		ProofBump: 254,
+	}
+	for i := 0; i < 63; i++ {
+		args.MerkleProof = append(args.MerkleProof, merkletree.Hash(testutil.NewRandomAccount(t).PublicKey().ToBytes()))
+	}
+
+	lookupTable := solana.AddressLookupTable{
+		PublicKey: testutil.NewRandomAccount(t).PublicKey().ToBytes(),
+		Addresses: []ed25519.PublicKey{
+			accounts.Pool,
+			system.RecentBlockhashesSysVar,
+		},
+	}
+
+	batches, err := makeVersionedUploadPartialProofInstructions(accounts, args, lookupTable)
+	require.NoError(t, err)
+	require.Len(t, batches, 3)
+
+	var uploaded int
+	for _, batch := range batches {
+		require.Len(t, batch, 1)
+
+		txn, err := transaction.MakeNoncedVersionedTransaction(
+			testutil.NewRandomAccount(t),
+			solana.Blockhash{},
+			[]solana.AddressLookupTable{lookupTable},
+			batch...,
+		)
+		require.NoError(t, err)
+		assert.Equal(t, solana.MessageVersion0, txn.Message.Version)
+		assert.True(t, len(txn.Marshal()) <= solana.MaxTransactionSize)
+
+		// Data layout: discriminator (8), pool bump (1), proof bump (1), current
+		// size (1), data size (1), then the length prefixed proof hashes
+		data := batch[0].Data
+		require.True(t, len(data) > 12)
+		assert.EqualValues(t, uploaded, data[10])
+		numHashes := int(data[11])
+		assert.Len(t, data, 12+4+32*numHashes)
+
+		// Lookup tables should always fit more of the proof than the legacy
+		// transactions, which upload 21 hashes at a time.
+		if uploaded+numHashes < len(args.MerkleProof) {
+			assert.True(t, numHashes > 21)
+		}
+		uploaded += numHashes
+	}
+	assert.Equal(t, len(args.MerkleProof), uploaded)
+}
@@ -79,6 +79,10 @@ func (p *service) worker(serviceCtx context.Context, state commitment.State, int
 			// Process the batch of commitments in parallel
 			var wg sync.WaitGroup
 			for _, item := range items {
+				if !p.membership.Owns(item.Intent) {
+					continue
+				}
+
 				wg.Add(1)
 				go func(record *commitment.Record) {
 					defer wg.Done()
@@ -403,23 +407,57 @@ func (p *service) injectCommitmentVaultManagementFulfillments(ctx context.Contex
 		return err
 	}
 
-	// Construct all fulfillment records
-	var fulfillmentsToSave []*fulfillment.Record
-	var noncesToReserve []*transaction.SelectedNonce
-	for i, txnToMake := range []struct {
+	type fulfillmentTxn struct {
 		fulfillmentType fulfillment.Type
 		ixns            []solana.Instruction
-	}{
-		{fulfillment.InitializeCommitmentProof, makeInitializeProofInstructions(txnAccounts, txnArgs)},
-		{fulfillment.UploadCommitmentProof, makeUploadPartialProofInstructions(txnAccounts, txnArgs, 0, 20)},
-		{fulfillment.UploadCommitmentProof, makeUploadPartialProofInstructions(txnAccounts, txnArgs, 21, 41)},
-		{fulfillment.UploadCommitmentProof, makeUploadPartialProofInstructions(txnAccounts, txnArgs, 42, 62)}, // todo: Assumes merkle tree of depth 63
-		{fulfillment.OpenCommitmentVault, append(
+		lookupTables    []solana.AddressLookupTable
+	}
+
+	txnsToMake := []fulfillmentTxn{
+		{fulfillmentType: fulfillment.InitializeCommitmentProof, ixns: makeInitializeProofInstructions(txnAccounts, txnArgs)},
+	}
+
+	// When a lookup table is available, use versioned transactions to pack as
+	// much of the proof as possible into each upload transaction.
+	lookupTable, err := p.getAddressLookupTable(ctx)
+	if err != nil {
+		return err
+	}
+	if lookupTable != nil {
+		batches, err := makeVersionedUploadPartialProofInstructions(txnAccounts, txnArgs, *lookupTable)
+		if err != nil {
+			return err
+		}
+
+		for _, batch := range batches {
+			txnsToMake = append(txnsToMake, fulfillmentTxn{
+				fulfillmentType: fulfillment.UploadCommitmentProof,
+				ixns:            batch,
+				lookupTables:    []solana.AddressLookupTable{*lookupTable},
+			})
+		}
+	} else {
+		txnsToMake = append(
+			txnsToMake,
+			fulfillmentTxn{fulfillmentType: fulfillment.UploadCommitmentProof, ixns: makeUploadPartialProofInstructions(txnAccounts, txnArgs, 0, 20)},
+			fulfillmentTxn{fulfillmentType: fulfillment.UploadCommitmentProof, ixns: makeUploadPartialProofInstructions(txnAccounts, txnArgs, 21, 41)},
+			fulfillmentTxn{fulfillmentType: fulfillment.UploadCommitmentProof, ixns: makeUploadPartialProofInstructions(txnAccounts, txnArgs, 42, 62)}, // todo: Assumes merkle tree of depth 63
+		)
+	}
+
+	txnsToMake = append(
+		txnsToMake,
+		fulfillmentTxn{fulfillmentType: fulfillment.OpenCommitmentVault, ixns: append(
 			makeVerifyProofInstructions(txnAccounts, txnArgs),
 			makeOpenCommitmentVaultInstructions(txnAccounts, txnArgs)...,
 		)},
-		{fulfillment.CloseCommitmentVault, makeCloseCommitmentVaultInstructions(txnAccounts, txnArgs)},
-	} {
+		fulfillmentTxn{fulfillmentType: fulfillment.CloseCommitmentVault, ixns: makeCloseCommitmentVaultInstructions(txnAccounts, txnArgs)},
+	)
+
+	// Construct all fulfillment records
+	var fulfillmentsToSave []*fulfillment.Record
+	var noncesToReserve []*transaction.SelectedNonce
+	for i, txnToMake := range txnsToMake {
 		selectedNonce, err := transaction.SelectAvailableNonce(ctx, p.data, nonce.PurposeInternalServerProcess)
 		if err != nil {
 			return err
@@ -429,7 +467,12 @@ func (p *service) injectCommitmentVaultManagementFulfillments(ctx context.Contex
 			selectedNonce.Unlock()
 		}()
 
-		txn, err := transaction.MakeNoncedTransaction(selectedNonce.Account, selectedNonce.Blockhash, txnToMake.ixns...)
+		var txn solana.Transaction
+		if len(txnToMake.lookupTables) > 0 {
+			txn, err = transaction.MakeNoncedVersionedTransaction(selectedNonce.Account, selectedNonce.Blockhash, txnToMake.lookupTables, txnToMake.ixns...)
+		} else {
+			txn, err = transaction.MakeNoncedTransaction(selectedNonce.Account, selectedNonce.Blockhash, txnToMake.ixns...)
+		}
 		if err != nil {
 			return err
 		}
@@ -19,11 +19,12 @@ import (
 	"github.com/code-payments/code-server/pkg/code/data/deposit"
 	"github.com/code-payments/code-server/pkg/code/data/fulfillment"
 	"github.com/code-payments/code-server/pkg/code/data/intent"
+	"github.com/code-payments/code-server/pkg/code/data/timelock"
 	"github.com/code-payments/code-server/pkg/code/data/transaction"
+	exchange_rate_util "github.com/code-payments/code-server/pkg/code/exchangerate"
 	"github.com/code-payments/code-server/pkg/code/push"
-	currency_lib "github.com/code-payments/code-server/pkg/currency"
 	"github.com/code-payments/code-server/pkg/database/query"
-	"github.com/code-payments/code-server/pkg/kin"
+	"github.com/code-payments/code-server/pkg/mint"
 	push_lib "github.com/code-payments/code-server/pkg/push"
 	"github.com/code-payments/code-server/pkg/retry"
 	"github.com/code-payments/code-server/pkg/solana"
@@ -164,22 +165,35 @@ func processPotentialExternalDeposit(ctx context.Context, data code_data.Provide
 		return errors.Wrap(err, "error getting transaction token balances")
 	}
 
-	// Check whether the Code subsidizer was involved in this transaction. If it is, then
+	// Check whether a Code subsidizer was involved in this transaction. If it is, then
 	// it cannot be an external deposit.
 	for _, account := range tokenBalances.Accounts {
-		if account == common.GetSubsidizer().PublicKey().ToBase58() {
+		if common.IsSubsidizer(account) {
 			return nil
 		}
 	}
 
-	var preKinBalance, postKinBalance int64
+	// Kin vaults may predate timelock records, but vaults for any other mint
+	// always have one.
+	tokenMint := mint.Kin
+	timelockRecord, err := data.GetTimelockByVault(ctx, vault.PublicKey().ToBase58())
+	if err == nil {
+		tokenMint, err = mint.Get(timelockRecord.Mint)
+		if err != nil {
+			return errors.Wrap(err, "error getting timelock mint")
+		}
+	} else if err != timelock.ErrTimelockNotFound {
+		return errors.Wrap(err, "error getting timelock record")
+	}
+
+	var preBalance, postBalance int64
 	for _, tokenBalance := range tokenBalances.PreTokenBalances {
-		if tokenBalance.Mint != kin.Mint {
+		if tokenBalance.Mint != tokenMint.ToBase58() {
 			continue
 		}
 
 		if tokenBalances.Accounts[tokenBalance.AccountIndex] == vault.PublicKey().ToBase58() {
-			preKinBalance, err = strconv.ParseInt(tokenBalance.TokenAmount.Amount, 10, 64)
+			preBalance, err = strconv.ParseInt(tokenBalance.TokenAmount.Amount, 10, 64)
 			if err != nil {
 				return errors.Wrap(err, "error parsing pre token balance")
 			}
@@ -187,12 +201,12 @@ func processPotentialExternalDeposit(ctx context.Context, data code_data.Provide
 		}
 	}
 	for _, tokenBalance := range tokenBalances.PostTokenBalances {
-		if tokenBalance.Mint != kin.Mint {
+		if tokenBalance.Mint != tokenMint.ToBase58() {
 			continue
 		}
 
 		if tokenBalances.Accounts[tokenBalance.AccountIndex] == vault.PublicKey().ToBase58() {
-			postKinBalance, err = strconv.ParseInt(tokenBalance.TokenAmount.Amount, 10, 64)
+			postBalance, err = strconv.ParseInt(tokenBalance.TokenAmount.Amount, 10, 64)
 			if err != nil {
 				return errors.Wrap(err, "error parsing post token balance")
 			}
@@ -202,7 +216,7 @@ func processPotentialExternalDeposit(ctx context.Context, data code_data.Provide
 
 	// Transaction did not positively affect toke account balance, so no new funds
 	// were externally deposited into the account.
-	deltaQuarks := postKinBalance - preKinBalance
+	deltaQuarks := postBalance - preBalance
 	if deltaQuarks <= 0 {
 		return nil
 	}
@@ -215,27 +229,35 @@ func processPotentialExternalDeposit(ctx context.Context, data code_data.Provide
 		return nil
 	}
 
-	accountInfoRecord, err := data.GetAccountInfoByTokenAddress(ctx, vault.PublicKey().ToBase58())
-	if err != nil {
-		return errors.Wrap(err, "error getting account info record")
-	}
+	var destinationOwner string
+	if tokenMint.IsKin() {
+		accountInfoRecord, err := data.GetAccountInfoByTokenAddress(ctx, vault.PublicKey().ToBase58())
+		if err != nil {
+			return errors.Wrap(err, "error getting account info record")
+		}
 
-	// Mark anything other than primary account as synced and move on without
-	// saving anything. There's a potential someone could overutilize our treasury
-	// by depositing large sums into temporary or bucket accounts, which have
-	// more lenient checks ATM. We'll deal with these adhoc as they arise. It
-	// should be a rare case given anything other than primary isn't exposed to
-	// users.
-	if accountInfoRecord.AccountType != commonpb.AccountType_PRIMARY {
-		syncedDepositCache.Insert(cacheKey, true, 1)
-		return nil
+		// Mark anything other than primary account as synced and move on without
+		// saving anything. There's a potential someone could overutilize our treasury
+		// by depositing large sums into temporary or bucket accounts, which have
+		// more lenient checks ATM. We'll deal with these adhoc as they arise. It
+		// should be a rare case given anything other than primary isn't exposed to
+		// users.
+		if accountInfoRecord.AccountType != commonpb.AccountType_PRIMARY {
+			syncedDepositCache.Insert(cacheKey, true, 1)
+			return nil
+		}
+
+		destinationOwner = accountInfoRecord.OwnerAccount
+	} else {
+		// Vaults for other mints are only ever opened as the owner's primary
+		// account for that mint.
+		destinationOwner = timelockRecord.VaultOwner
 	}
 
-	usdExchangeRecord, err := data.GetExchangeRate(ctx, currency_lib.USD, time.Now())
+	usdMarketValue, err := exchange_rate_util.GetUsdMarketValue(ctx, data, tokenMint, uint64(deltaQuarks), time.Now())
 	if err != nil {
-		return errors.Wrap(err, "error getting usd rate")
+		return errors.Wrap(err, "error getting usd market value")
 	}
-	usdMarketValue := usdExchangeRecord.Rate * float64(deltaQuarks) / float64(kin.QuarksPerKin)
 
 	// For a consistent payment history list
 	//
@@ -246,8 +268,10 @@ func processPotentialExternalDeposit(ctx context.Context, data code_data.Provide
 
 		InitiatorOwnerAccount: tokenBalances.Accounts[0], // The fee payer
 
+		Mint: tokenMint.ToBase58(),
+
 		ExternalDepositMetadata: &intent.ExternalDepositMetadata{
-			DestinationOwnerAccount: accountInfoRecord.OwnerAccount,
+			DestinationOwnerAccount: destinationOwner,
 			DestinationTokenAccount: vault.PublicKey().ToBase58(),
 			Quantity:                uint64(deltaQuarks),
 			UsdMarketValue:          usdMarketValue,
@@ -261,9 +285,14 @@ func processPotentialExternalDeposit(ctx context.Context, data code_data.Provide
 		return errors.Wrap(err, "error saving intent record")
 	}
 
-	err = chat_util.SendCashTransactionsExchangeMessage(ctx, data, intentRecord)
-	if err != nil {
-		return errors.Wrap(err, "error updating cash transactions chat")
+	// todo: Chat exchange data and push notifications are denominated in Kin,
+	//       so they're only sent for Kin deposits until clients support other
+	//       mints.
+	if tokenMint.IsKin() {
+		err = chat_util.SendCashTransactionsExchangeMessage(ctx, data, intentRecord)
+		if err != nil {
+			return errors.Wrap(err, "error updating cash transactions chat")
+		}
 	}
 
 	// For tracking in balances
@@ -283,7 +312,9 @@ func processPotentialExternalDeposit(ctx context.Context, data code_data.Provide
 		return errors.Wrap(err, "error creating external deposit record")
 	}
 	syncedDepositCache.Insert(cacheKey, true, 1)
-	push.SendDepositPushNotification(ctx, data, pusher, vault, uint64(deltaQuarks))
+	if tokenMint.IsKin() {
+		push.SendDepositPushNotification(ctx, data, pusher, vault, uint64(deltaQuarks))
+	}
 
 	return nil
 }
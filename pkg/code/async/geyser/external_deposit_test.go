@@ -0,0 +1,166 @@
+package async_geyser
+
+import (
+	"context"
+	"crypto/ed25519"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mr-tron/base58"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	commonpb "github.com/code-payments/code-protobuf-api/generated/go/common/v1"
+
+	currency_lib "github.com/code-payments/code-server/pkg/currency"
+	"github.com/code-payments/code-server/pkg/mint"
+	"github.com/code-payments/code-server/pkg/solana"
+	"github.com/code-payments/code-server/pkg/solana/simulator"
+	timelock_token_v1 "github.com/code-payments/code-server/pkg/solana/timelock/v1"
+	"github.com/code-payments/code-server/pkg/solana/token"
+	"github.com/code-payments/code-server/pkg/testutil"
+	datatest "github.com/code-payments/code-server/pkg/code/data/test"
+	"github.com/code-payments/code-server/pkg/code/data/account"
+	"github.com/code-payments/code-server/pkg/code/data/currency"
+	"github.com/code-payments/code-server/pkg/code/data/intent"
+)
+
+func TestProcessPotentialExternalDeposit_SecondMint(t *testing.T) {
+	ctx := context.Background()
+	require.NoError(t, mint.Register(mint.Usdc))
+
+	ledger := simulator.NewLedger()
+	data := datatest.NewDataProviderWithLedger(ledger)
+	testutil.SetupRandomSubsidizer(t, data)
+
+	require.NoError(t, data.ImportExchangeRates(ctx, &currency.MultiRateRecord{
+		Time:  time.Now(),
+		Rates: map[string]float64{string(currency_lib.USD): 0.00002},
+	}))
+
+	owner := testutil.NewRandomAccount(t)
+	timelockAccounts, err := owner.GetTimelockAccountsForMint(timelock_token_v1.DataVersion1, mint.Usdc)
+	require.NoError(t, err)
+	require.NoError(t, data.SaveTimelock(ctx, timelockAccounts.ToDBRecord()))
+
+	_, payer, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	payerPublicKey := payer.Public().(ed25519.PublicKey)
+	_, err = ledger.RequestAirdrop(payerPublicKey, 1_000_000_000, solana.CommitmentFinalized)
+	require.NoError(t, err)
+
+	ledger.CreateMint(mint.Usdc.Address, payerPublicKey, mint.Usdc.Decimals)
+	require.NoError(t, ledger.CreateTokenAccount(timelockAccounts.Vault.PublicKey().ToBytes(), mint.Usdc.Address, timelockAccounts.Vault.PublicKey().ToBytes(), 0))
+
+	source := testutil.NewRandomAccount(t)
+	require.NoError(t, ledger.CreateTokenAccount(source.PublicKey().ToBytes(), mint.Usdc.Address, payerPublicKey, mint.Usdc.ToQuarks(100)))
+
+	bh, err := ledger.GetLatestBlockhash()
+	require.NoError(t, err)
+	txn := solana.NewTransaction(
+		payerPublicKey,
+		token.Transfer(source.PublicKey().ToBytes(), timelockAccounts.Vault.PublicKey().ToBytes(), payerPublicKey, 12_500_000),
+	)
+	txn.SetBlockhash(bh)
+	require.NoError(t, txn.Sign(payer))
+	sig, err := ledger.SubmitTransaction(txn, solana.CommitmentFinalized)
+	require.NoError(t, err)
+	signature := base58.Encode(sig[:])
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, processPotentialExternalDeposit(ctx, data, nil, signature, timelockAccounts.Vault))
+	}
+
+	intentRecord, err := data.GetIntent(ctx, fmt.Sprintf("%s-%s", signature, timelockAccounts.Vault.PublicKey().ToBase58()))
+	require.NoError(t, err)
+	assert.Equal(t, intent.ExternalDeposit, intentRecord.IntentType)
+	assert.Equal(t, mint.UsdcMint, intentRecord.Mint)
+	assert.Equal(t, base58.Encode(payerPublicKey), intentRecord.InitiatorOwnerAccount)
+	require.NotNil(t, intentRecord.ExternalDepositMetadata)
+	assert.Equal(t, owner.PublicKey().ToBase58(), intentRecord.ExternalDepositMetadata.DestinationOwnerAccount)
+	assert.Equal(t, timelockAccounts.Vault.PublicKey().ToBase58(), intentRecord.ExternalDepositMetadata.DestinationTokenAccount)
+	assert.EqualValues(t, 12_500_000, intentRecord.ExternalDepositMetadata.Quantity)
+	assert.InDelta(t, 12.5, intentRecord.ExternalDepositMetadata.UsdMarketValue, 0.000001)
+
+	depositRecord, err := data.GetExternalDeposit(ctx, signature, timelockAccounts.Vault.PublicKey().ToBase58())
+	require.NoError(t, err)
+	assert.EqualValues(t, 12_500_000, depositRecord.Amount)
+	assert.InDelta(t, 12.5, depositRecord.UsdMarketValue, 0.000001)
+
+	balance, err := data.GetBlockchainTokenAccountInfoForMint(ctx, timelockAccounts.Vault.PublicKey().ToBase58(), mint.UsdcMint, solana.CommitmentFinalized)
+	require.NoError(t, err)
+	assert.EqualValues(t, 12_500_000, balance.Amount)
+
+	_, err = data.GetBlockchainTokenAccountInfo(ctx, timelockAccounts.Vault.PublicKey().ToBase58(), solana.CommitmentFinalized)
+	assert.Equal(t, token.ErrInvalidTokenAccount, err)
+}
+
+func TestProcessPotentialExternalDeposit_Kin(t *testing.T) {
+	ctx := context.Background()
+
+	ledger := simulator.NewLedger()
+	data := datatest.NewDataProviderWithLedger(ledger)
+	testutil.SetupRandomSubsidizer(t, data)
+
+	require.NoError(t, data.ImportExchangeRates(ctx, &currency.MultiRateRecord{
+		Time:  time.Now(),
+		Rates: map[string]float64{string(currency_lib.USD): 0.5},
+	}))
+
+	owner := testutil.NewRandomAccount(t)
+	timelockAccounts, err := owner.GetTimelockAccountsForMint(timelock_token_v1.DataVersion1, mint.Kin)
+	require.NoError(t, err)
+	require.NoError(t, data.SaveTimelock(ctx, timelockAccounts.ToDBRecord()))
+	require.NoError(t, data.CreateAccountInfo(ctx, &account.Record{
+		OwnerAccount:     owner.PublicKey().ToBase58(),
+		AuthorityAccount: owner.PublicKey().ToBase58(),
+		TokenAccount:     timelockAccounts.Vault.PublicKey().ToBase58(),
+		AccountType:      commonpb.AccountType_PRIMARY,
+		CreatedAt:        time.Now(),
+	}))
+
+	_, payer, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	payerPublicKey := payer.Public().(ed25519.PublicKey)
+	_, err = ledger.RequestAirdrop(payerPublicKey, 1_000_000_000, solana.CommitmentFinalized)
+	require.NoError(t, err)
+
+	ledger.CreateMint(mint.Kin.Address, payerPublicKey, mint.Kin.Decimals)
+	require.NoError(t, ledger.CreateTokenAccount(timelockAccounts.Vault.PublicKey().ToBytes(), mint.Kin.Address, timelockAccounts.Vault.PublicKey().ToBytes(), 0))
+
+	source := testutil.NewRandomAccount(t)
+	require.NoError(t, ledger.CreateTokenAccount(source.PublicKey().ToBytes(), mint.Kin.Address, payerPublicKey, mint.Kin.ToQuarks(100)))
+
+	bh, err := ledger.GetLatestBlockhash()
+	require.NoError(t, err)
+	txn := solana.NewTransaction(
+		payerPublicKey,
+		token.Transfer(source.PublicKey().ToBytes(), timelockAccounts.Vault.PublicKey().ToBytes(), payerPublicKey, mint.Kin.ToQuarks(42)),
+	)
+	txn.SetBlockhash(bh)
+	require.NoError(t, txn.Sign(payer))
+	sig, err := ledger.SubmitTransaction(txn, solana.CommitmentFinalized)
+	require.NoError(t, err)
+	signature := base58.Encode(sig[:])
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, processPotentialExternalDeposit(ctx, data, nil, signature, timelockAccounts.Vault))
+	}
+
+	intentRecord, err := data.GetIntent(ctx, fmt.Sprintf("%s-%s", signature, timelockAccounts.Vault.PublicKey().ToBase58()))
+	require.NoError(t, err)
+	assert.Equal(t, intent.ExternalDeposit, intentRecord.IntentType)
+	require.NotNil(t, intentRecord.ExternalDepositMetadata)
+	assert.Equal(t, owner.PublicKey().ToBase58(), intentRecord.ExternalDepositMetadata.DestinationOwnerAccount)
+	assert.EqualValues(t, mint.Kin.ToQuarks(42), intentRecord.ExternalDepositMetadata.Quantity)
+	assert.InDelta(t, 21.0, intentRecord.ExternalDepositMetadata.UsdMarketValue, 0.000001)
+
+	depositRecord, err := data.GetExternalDeposit(ctx, signature, timelockAccounts.Vault.PublicKey().ToBase58())
+	require.NoError(t, err)
+	assert.EqualValues(t, mint.Kin.ToQuarks(42), depositRecord.Amount)
+
+	balance, err := data.GetBlockchainTokenAccountInfo(ctx, timelockAccounts.Vault.PublicKey().ToBase58(), solana.CommitmentFinalized)
+	require.NoError(t, err)
+	assert.EqualValues(t, mint.Kin.ToQuarks(42), balance.Amount)
+}
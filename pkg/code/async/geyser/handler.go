@@ -11,7 +11,7 @@ import (
 
 	"github.com/code-payments/code-server/pkg/code/common"
 	code_data "github.com/code-payments/code-server/pkg/code/data"
-	"github.com/code-payments/code-server/pkg/kin"
+	"github.com/code-payments/code-server/pkg/mint"
 	push_lib "github.com/code-payments/code-server/pkg/push"
 	splitter_token "github.com/code-payments/code-server/pkg/solana/splitter"
 	timelock_token_v1 "github.com/code-payments/code-server/pkg/solana/timelock/v1"
@@ -68,22 +68,22 @@ func (h *TokenProgramAccountHandler) Handle(ctx context.Context, update *geyserp
 		return nil
 	}
 
-	// Not a Kin account, so filter it out
-	if !bytes.Equal(unmarshalled.Mint, kin.TokenMint) {
+	// Not an account for a supported mint, so filter it out
+	if !mint.IsSupported(unmarshalled.Mint) {
 		return nil
 	}
 
-	kinTokenAccount, err := common.NewAccountFromPublicKeyBytes(update.Pubkey)
+	tokenAccount, err := common.NewAccountFromPublicKeyBytes(update.Pubkey)
 	if err != nil {
 		return errors.Wrap(err, "invalid token account")
 	}
 
-	if kinTokenAccount.PublicKey().ToBase58() == h.conf.messagingFeeCollectorPublicKey.Get(ctx) {
+	if tokenAccount.PublicKey().ToBase58() == h.conf.messagingFeeCollectorPublicKey.Get(ctx) {
 		return processPotentialBlockchainMessage(
 			ctx,
 			h.data,
 			h.pusher,
-			kinTokenAccount,
+			tokenAccount,
 			*update.TxSignature,
 		)
 	}
@@ -98,7 +98,7 @@ func (h *TokenProgramAccountHandler) Handle(ctx context.Context, update *geyserp
 		return nil
 	}
 
-	isCodeAccount, err := testForKnownCodeUserAccount(ctx, h.data, kinTokenAccount)
+	isCodeAccount, err := testForKnownCodeUserAccount(ctx, h.data, tokenAccount)
 	if err != nil {
 		return errors.Wrap(err, "error testing for known account")
 	} else if !isCodeAccount {
@@ -106,7 +106,7 @@ func (h *TokenProgramAccountHandler) Handle(ctx context.Context, update *geyserp
 		return nil
 	}
 
-	return processPotentialExternalDeposit(ctx, h.data, h.pusher, *update.TxSignature, kinTokenAccount)
+	return processPotentialExternalDeposit(ctx, h.data, h.pusher, *update.TxSignature, tokenAccount)
 }
 
 type TimelockV1ProgramAccountHandler struct {
@@ -131,13 +131,13 @@ func (h *TimelockV1ProgramAccountHandler) Handle(ctx context.Context, update *ge
 			return errors.Wrap(err, "error unmarshalling account data from update")
 		}
 
-		// Not a Kin account, so filter it out
-		if !bytes.Equal(unmarshalled.Mint, kin.TokenMint) {
+		// Not an account for a supported mint, so filter it out
+		if !mint.IsSupported(unmarshalled.Mint) {
 			return nil
 		}
 
 		// Not managed by Code, so filter it out
-		if !bytes.Equal(unmarshalled.TimeAuthority, common.GetSubsidizer().PublicKey().ToBytes()) {
+		if !common.IsSubsidizer(base58.Encode(unmarshalled.TimeAuthority)) {
 			return nil
 		}
 
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	code_data "github.com/code-payments/code-server/pkg/code/data"
+	datatest "github.com/code-payments/code-server/pkg/code/data/test"
 )
 
 // todo: implement me
@@ -22,7 +23,7 @@ type testEnv struct {
 }
 
 func setup(t *testing.T) *testEnv {
-	data := code_data.NewTestDataProvider()
+	data := datatest.NewDataProvider()
 	return &testEnv{
 		data:     data,
 		handlers: initializeProgramAccountUpdateHandlers(&conf{}, data, nil),
@@ -0,0 +1,70 @@
+package async_mandate
+
+import (
+	"context"
+	"time"
+
+	"github.com/newrelic/go-agent/v3/newrelic"
+	"github.com/sirupsen/logrus"
+
+	"github.com/code-payments/code-server/pkg/metrics"
+	"github.com/code-payments/code-server/pkg/code/data/mandate"
+	mandate_util "github.com/code-payments/code-server/pkg/code/mandate"
+)
+
+func (p *service) authorizationExpiryWorker(serviceCtx context.Context, interval time.Duration) error {
+	delay := interval
+
+	for {
+		select {
+		case <-serviceCtx.Done():
+			return serviceCtx.Err()
+		case <-time.After(delay):
+			start := time.Now()
+
+			func() {
+				nr := serviceCtx.Value(metrics.NewRelicContextKey).(*newrelic.Application)
+				m := nr.StartTransaction("async__mandate_service__handle_expired_authorizations")
+				defer m.End()
+				tracedCtx := newrelic.NewContext(serviceCtx, m)
+
+				err := p.processExpiredAuthorizations(tracedCtx)
+				if err != nil {
+					m.NoticeError(err)
+				}
+			}()
+
+			delay = interval - time.Since(start)
+		}
+	}
+}
+
+// processExpiredAuthorizations cancels mandates that were never authorized by
+// their owner, which releases the nonces reserved for their payments.
+func (p *service) processExpiredAuthorizations(ctx context.Context) error {
+	expiredBefore := time.Now().Add(-p.conf.authorizationTimeout.Get(ctx))
+
+	mandateRecords, err := p.data.GetAllMandatesByStateCreatedBefore(ctx, mandate.StatePendingAuthorization, expiredBefore, p.conf.batchSize.Get(ctx))
+	if err == mandate.ErrMandateNotFound {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	for _, mandateRecord := range mandateRecords {
+		log := p.log.WithFields(logrus.Fields{
+			"method":  "processExpiredAuthorizations",
+			"mandate": mandateRecord.MandateId,
+			"owner":   mandateRecord.OwnerAccount,
+		})
+
+		err := mandate_util.CancelMandate(ctx, p.data, mandateRecord)
+		if err != nil {
+			log.WithError(err).Warn("failure cancelling mandate with expired authorization")
+			return err
+		}
+
+		recordAuthorizationExpiredEvent(ctx, mandateRecord)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package async_mandate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/code-payments/code-server/pkg/code/data/mandate"
+)
+
+func TestProcessExpiredAuthorizations(t *testing.T) {
+	env := setup(t, &testOverrides{
+		authorizationTimeout: time.Millisecond,
+	})
+
+	owner := env.setupUser(t)
+	destination := env.setupUser(t)
+
+	pending := env.setupMandate(t, owner, destination, 3, false)
+	authorized := env.setupMandate(t, owner, destination, 3, true)
+
+	time.Sleep(10 * time.Millisecond)
+
+	require.NoError(t, env.worker.processExpiredAuthorizations(env.ctx))
+
+	env.assertMandateState(t, pending, mandate.StateCancelled, 0, 0, 0)
+	env.assertMandateState(t, authorized, mandate.StateActive, 0, 0, 0)
+	for i := uint32(0); i < 3; i++ {
+		env.assertPaymentRevoked(t, pending, i)
+		env.assertPaymentDormant(t, authorized, i)
+	}
+}
+
+func TestProcessExpiredAuthorizations_NotExpired(t *testing.T) {
+	env := setup(t, &testOverrides{})
+
+	owner := env.setupUser(t)
+	destination := env.setupUser(t)
+
+	pending := env.setupMandate(t, owner, destination, 3, false)
+
+	require.NoError(t, env.worker.processExpiredAuthorizations(env.ctx))
+
+	env.assertMandateState(t, pending, mandate.StatePendingAuthorization, 0, 0, 0)
+	for i := uint32(0); i < 3; i++ {
+		env.assertPaymentDormant(t, pending, i)
+	}
+}
@@ -0,0 +1,69 @@
+package async_mandate
+
+import (
+	"time"
+
+	"github.com/code-payments/code-server/pkg/config"
+	"github.com/code-payments/code-server/pkg/config/env"
+	"github.com/code-payments/code-server/pkg/config/memory"
+	"github.com/code-payments/code-server/pkg/config/wrapper"
+)
+
+const (
+	envConfigPrefix = "MANDATE_SERVICE_"
+
+	BatchSizeConfigEnvName = envConfigPrefix + "BATCH_SIZE"
+	defaultBatchSize       = 100
+
+	MaxConsecutiveFailuresConfigEnvName = envConfigPrefix + "MAX_CONSECUTIVE_FAILURES"
+	defaultMaxConsecutiveFailures       = 3
+
+	AuthorizationTimeoutConfigEnvName = envConfigPrefix + "AUTHORIZATION_TIMEOUT"
+	defaultAuthorizationTimeout       = 15 * time.Minute
+)
+
+type conf struct {
+	batchSize              config.Uint64
+	maxConsecutiveFailures config.Uint64
+	authorizationTimeout   config.Duration
+}
+
+// ConfigProvider defines how config values are pulled
+type ConfigProvider func() *conf
+
+// WithEnvConfigs returns configuration pulled from environment variables
+func WithEnvConfigs() ConfigProvider {
+	return func() *conf {
+		return &conf{
+			batchSize:              env.NewUint64Config(BatchSizeConfigEnvName, defaultBatchSize),
+			maxConsecutiveFailures: env.NewUint64Config(MaxConsecutiveFailuresConfigEnvName, defaultMaxConsecutiveFailures),
+			authorizationTimeout:   env.NewDurationConfig(AuthorizationTimeoutConfigEnvName, defaultAuthorizationTimeout),
+		}
+	}
+}
+
+type testOverrides struct {
+	batchSize              uint64
+	maxConsecutiveFailures uint64
+	authorizationTimeout   time.Duration
+}
+
+func withManualTestOverrides(overrides *testOverrides) ConfigProvider {
+	if overrides.batchSize == 0 {
+		overrides.batchSize = defaultBatchSize
+	}
+	if overrides.maxConsecutiveFailures == 0 {
+		overrides.maxConsecutiveFailures = defaultMaxConsecutiveFailures
+	}
+	if overrides.authorizationTimeout == 0 {
+		overrides.authorizationTimeout = defaultAuthorizationTimeout
+	}
+
+	return func() *conf {
+		return &conf{
+			batchSize:              wrapper.NewUint64Config(memory.NewConfig(overrides.batchSize), defaultBatchSize),
+			maxConsecutiveFailures: wrapper.NewUint64Config(memory.NewConfig(overrides.maxConsecutiveFailures), defaultMaxConsecutiveFailures),
+			authorizationTimeout:   wrapper.NewDurationConfig(memory.NewConfig(overrides.authorizationTimeout), defaultAuthorizationTimeout),
+		}
+	}
+}
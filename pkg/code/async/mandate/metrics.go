@@ -0,0 +1,42 @@
+package async_mandate
+
+import (
+	"context"
+
+	"github.com/code-payments/code-server/pkg/metrics"
+	"github.com/code-payments/code-server/pkg/code/data/mandate"
+)
+
+const (
+	paymentSubmittedEventName     = "MandatePaymentSubmitted"
+	paymentFailedEventName        = "MandatePaymentFailed"
+	authorizationExpiredEventName = "MandateAuthorizationExpired"
+)
+
+func recordPaymentSubmittedEvent(ctx context.Context, record *mandate.Record, index uint32, usdValue float64) {
+	metrics.RecordEvent(ctx, paymentSubmittedEventName, map[string]interface{}{
+		"mandate":   record.MandateId,
+		"owner":     record.OwnerAccount,
+		"index":     index,
+		"cadence":   record.Cadence.String(),
+		"currency":  string(record.ExchangeCurrency),
+		"usd_value": usdValue,
+	})
+}
+
+func recordPaymentFailedEvent(ctx context.Context, record *mandate.Record, index uint32, reason string) {
+	metrics.RecordEvent(ctx, paymentFailedEventName, map[string]interface{}{
+		"mandate":              record.MandateId,
+		"owner":                record.OwnerAccount,
+		"index":                index,
+		"consecutive_failures": record.ConsecutiveFailures,
+		"reason":               reason,
+	})
+}
+
+func recordAuthorizationExpiredEvent(ctx context.Context, record *mandate.Record) {
+	metrics.RecordEvent(ctx, authorizationExpiredEventName, map[string]interface{}{
+		"mandate": record.MandateId,
+		"owner":   record.OwnerAccount,
+	})
+}
@@ -0,0 +1,377 @@
+package async_mandate
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/newrelic/go-agent/v3/newrelic"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	chatpb "github.com/code-payments/code-protobuf-api/generated/go/chat/v1"
+
+	currency_lib "github.com/code-payments/code-server/pkg/currency"
+	"github.com/code-payments/code-server/pkg/kin"
+	"github.com/code-payments/code-server/pkg/metrics"
+	"github.com/code-payments/code-server/pkg/pointer"
+	"github.com/code-payments/code-server/pkg/code/balance"
+	chat_util "github.com/code-payments/code-server/pkg/code/chat"
+	"github.com/code-payments/code-server/pkg/code/common"
+	"github.com/code-payments/code-server/pkg/code/data/action"
+	"github.com/code-payments/code-server/pkg/code/data/chat"
+	"github.com/code-payments/code-server/pkg/code/data/fulfillment"
+	"github.com/code-payments/code-server/pkg/code/data/intent"
+	"github.com/code-payments/code-server/pkg/code/data/mandate"
+	"github.com/code-payments/code-server/pkg/code/data/phone"
+	exchange_rate_util "github.com/code-payments/code-server/pkg/code/exchangerate"
+	mandate_util "github.com/code-payments/code-server/pkg/code/mandate"
+	push_util "github.com/code-payments/code-server/pkg/code/push"
+)
+
+const (
+	denialReasonAntispam            = "antispam"
+	denialReasonAntiMoneyLaundering = "aml"
+	denialReasonSendLimit           = "send limit"
+	denialReasonInsufficientFunds   = "insufficient funds"
+)
+
+func (p *service) paymentWorker(serviceCtx context.Context, interval time.Duration) error {
+	delay := interval
+
+	for {
+		select {
+		case <-serviceCtx.Done():
+			return serviceCtx.Err()
+		case <-time.After(delay):
+			start := time.Now()
+
+			func() {
+				nr := serviceCtx.Value(metrics.NewRelicContextKey).(*newrelic.Application)
+				m := nr.StartTransaction("async__mandate_service__handle_payments")
+				defer m.End()
+				tracedCtx := newrelic.NewContext(serviceCtx, m)
+
+				err := p.processDuePayments(tracedCtx)
+				if err != nil {
+					m.NoticeError(err)
+				}
+			}()
+
+			delay = interval - time.Since(start)
+		}
+	}
+}
+
+func (p *service) processDuePayments(ctx context.Context) error {
+	mandateRecords, err := p.data.GetAllDueMandates(ctx, time.Now(), p.conf.batchSize.Get(ctx))
+	if err == mandate.ErrMandateNotFound {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	var lastErr error
+	for _, mandateRecord := range mandateRecords {
+		// Failures are isolated to each mandate, so a single bad mandate doesn't
+		// block payments for all others
+		err := p.processDuePayment(ctx, mandateRecord)
+		if err != nil {
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
+// processDuePayment makes the next payment for an active mandate by activating
+// its pre-signed transaction as a public payment intent. Every payment goes
+// through the same antispam, AML and limit checks as a client-initiated public
+// payment. Payments that don't pass are revoked and count as a failure.
+func (p *service) processDuePayment(ctx context.Context, mandateRecord *mandate.Record) error {
+	log := p.log.WithFields(logrus.Fields{
+		"method":  "processDuePayment",
+		"mandate": mandateRecord.MandateId,
+		"owner":   mandateRecord.OwnerAccount,
+		"index":   mandateRecord.PaymentsProcessed,
+	})
+
+	owner, err := common.NewAccountFromPublicKeyString(mandateRecord.OwnerAccount)
+	if err != nil {
+		log.WithError(err).Warn("invalid owner account")
+		return err
+	}
+
+	source, err := common.NewAccountFromPublicKeyString(mandateRecord.SourceTokenAccount)
+	if err != nil {
+		log.WithError(err).Warn("invalid source account")
+		return err
+	}
+
+	destination, err := common.NewAccountFromPublicKeyString(mandateRecord.DestinationTokenAccount)
+	if err != nil {
+		log.WithError(err).Warn("invalid destination account")
+		return err
+	}
+
+	usdExchangeRecord, err := p.data.GetExchangeRate(ctx, currency_lib.USD, exchange_rate_util.GetLatestExchangeRateTime())
+	if err != nil {
+		log.WithError(err).Warn("failure getting current usd exchange rate")
+		return err
+	}
+
+	intentRecord := &intent.Record{
+		IntentId:   mandate_util.GetPaymentIntentId(mandateRecord.MandateId, mandateRecord.PaymentsProcessed),
+		IntentType: intent.SendPublicPayment,
+
+		// Mandates are only authorized against Kin timelock accounts
+		Mint: kin.Mint,
+
+		SendPublicPaymentMetadata: &intent.SendPublicPaymentMetadata{
+			DestinationOwnerAccount: mandateRecord.DestinationOwnerAccount,
+			DestinationTokenAccount: mandateRecord.DestinationTokenAccount,
+			Quantity:                mandateRecord.Quantity,
+
+			ExchangeCurrency: mandateRecord.ExchangeCurrency,
+			ExchangeRate:     mandateRecord.ExchangeRate,
+			NativeAmount:     mandateRecord.NativeAmount,
+			UsdMarketValue:   usdExchangeRecord.Rate * float64(kin.FromQuarks(mandateRecord.Quantity)),
+
+			// Mandate payments are always Code->Code public transfers between
+			// primary accounts
+			IsWithdrawal: true,
+		},
+
+		InitiatorOwnerAccount: mandateRecord.OwnerAccount,
+
+		State: intent.StateUnknown,
+
+		CreatedAt: time.Now(),
+	}
+
+	verification, err := p.data.GetLatestPhoneVerificationForAccount(ctx, owner.PublicKey().ToBase58())
+	if err == nil {
+		intentRecord.InitiatorPhoneNumber = pointer.String(verification.PhoneNumber)
+	} else if err != phone.ErrVerificationNotFound {
+		log.WithError(err).Warn("failure getting phone verification")
+		return err
+	}
+
+	denialReason, err := p.checkPayment(ctx, mandateRecord, owner, source, destination, intentRecord)
+	if err != nil {
+		log.WithError(err).Warn("failure checking payment")
+		return err
+	} else if len(denialReason) > 0 {
+		log.WithField("reason", denialReason).Info("payment denied")
+		return p.onPaymentFailed(ctx, mandateRecord, owner, denialReason)
+	}
+
+	paymentIndex := mandateRecord.PaymentsProcessed
+
+	err = p.data.ExecuteInTx(ctx, sql.LevelDefault, func(ctx context.Context) error {
+		updated := mandateRecord.Clone()
+		updated.PaymentsProcessed++
+		updated.ConsecutiveFailures = 0
+		if updated.HasRemainingPayments() {
+			updated.NextPaymentAt = updated.GetPaymentTime(updated.PaymentsProcessed)
+		} else {
+			updated.State = mandate.StateCompleted
+		}
+		err := p.data.UpdateMandate(ctx, &updated)
+		if err != nil {
+			return err
+		}
+
+		err = p.data.SaveIntent(ctx, intentRecord)
+		if err != nil {
+			return err
+		}
+
+		actionRecord, err := p.data.GetActionById(ctx, intentRecord.IntentId, 0)
+		if err != nil {
+			return err
+		} else if actionRecord.State != action.StateUnknown {
+			return errors.New("payment action is in an unexpected state")
+		}
+
+		actionRecord.Quantity = pointer.Uint64(mandateRecord.Quantity)
+		actionRecord.State = action.StatePending
+		err = p.data.UpdateAction(ctx, actionRecord)
+		if err != nil {
+			return err
+		}
+
+		fulfillmentRecords, err := p.data.GetAllFulfillmentsByAction(ctx, intentRecord.IntentId, 0)
+		if err != nil {
+			return err
+		} else if len(fulfillmentRecords) != 1 {
+			return errors.New("expected exactly one payment fulfillment")
+		} else if fulfillmentRecords[0].State != fulfillment.StateUnknown {
+			return errors.New("payment fulfillment is in an unexpected state")
+		}
+
+		err = p.data.MarkFulfillmentAsActivelyScheduled(ctx, fulfillmentRecords[0].Id)
+		if err != nil {
+			return err
+		}
+
+		err = chat_util.SendCashTransactionsExchangeMessage(ctx, p.data, intentRecord)
+		if err != nil {
+			return err
+		}
+
+		// Intent is pending only after everything's been saved.
+		intentRecord.State = intent.StatePending
+		err = p.data.SaveIntent(ctx, intentRecord)
+		if err != nil {
+			return err
+		}
+
+		updated.CopyTo(mandateRecord)
+		return nil
+	})
+	if err != nil {
+		log.WithError(err).Warn("failure creating payment intent")
+		return err
+	}
+
+	log.Debug("created payment intent")
+	recordPaymentSubmittedEvent(ctx, mandateRecord, paymentIndex, intentRecord.SendPublicPaymentMetadata.UsdMarketValue)
+
+	return nil
+}
+
+// checkPayment runs a due payment through the same checks that apply to client
+// initiated public payments. A non-empty denial reason is returned when the
+// payment isn't allowed.
+func (p *service) checkPayment(
+	ctx context.Context,
+	mandateRecord *mandate.Record,
+	owner, source, destination *common.Account,
+	intentRecord *intent.Record,
+) (string, error) {
+	allow, err := p.antispamGuard.AllowSendPayment(ctx, owner, true, destination)
+	if err != nil {
+		return "", err
+	} else if !allow {
+		return denialReasonAntispam, nil
+	}
+
+	allow, err = p.amlGuard.AllowMoneyMovement(ctx, intentRecord)
+	if err != nil {
+		return "", err
+	} else if !allow {
+		return denialReasonAntiMoneyLaundering, nil
+	}
+
+	limits, err := p.limits.GetLimits(ctx, owner)
+	if err != nil {
+		return "", err
+	}
+	sendLimit, ok := limits.Send[mandateRecord.ExchangeCurrency]
+	if !ok || mandateRecord.NativeAmount > sendLimit.PerTransaction {
+		return denialReasonSendLimit, nil
+	}
+
+	balance, err := balance.DefaultCalculation(ctx, p.data, source)
+	if err != nil {
+		return "", err
+	} else if balance < mandateRecord.Quantity {
+		return denialReasonInsufficientFunds, nil
+	}
+
+	return "", nil
+}
+
+// onPaymentFailed revokes a payment that couldn't be made and notifies the
+// owner through the Code Team chat. Mandates that fail too many times in a row
+// are failed, which revokes all remaining payments.
+func (p *service) onPaymentFailed(ctx context.Context, mandateRecord *mandate.Record, owner *common.Account, reason string) error {
+	log := p.log.WithFields(logrus.Fields{
+		"method":  "onPaymentFailed",
+		"mandate": mandateRecord.MandateId,
+		"owner":   mandateRecord.OwnerAccount,
+		"index":   mandateRecord.PaymentsProcessed,
+	})
+
+	failedIndex := mandateRecord.PaymentsProcessed
+
+	updated := mandateRecord.Clone()
+	updated.PaymentsProcessed++
+	updated.PaymentsFailed++
+	updated.ConsecutiveFailures++
+	if uint64(updated.ConsecutiveFailures) >= p.conf.maxConsecutiveFailures.Get(ctx) {
+		updated.State = mandate.StateFailed
+	} else if updated.HasRemainingPayments() {
+		updated.NextPaymentAt = updated.GetPaymentTime(updated.PaymentsProcessed)
+	} else {
+		updated.State = mandate.StateCompleted
+	}
+
+	var chatMessage *chatpb.ChatMessage
+	var err error
+	if updated.State == mandate.StateFailed {
+		// There's no payment at the index after the last one, so its intent ID
+		// uniquely and deterministically identifies the mandate's failure
+		chatMessage, err = chat_util.ToRecurringPaymentCancelledMessage(
+			mandate_util.GetPaymentIntentId(mandateRecord.MandateId, mandateRecord.MaxPayments),
+			time.Now(),
+		)
+	} else {
+		chatMessage, err = chat_util.ToRecurringPaymentFailedMessage(
+			mandate_util.GetPaymentIntentId(mandateRecord.MandateId, failedIndex),
+			time.Now(),
+		)
+	}
+	if err != nil {
+		log.WithError(err).Warn("failure creating chat message")
+		return err
+	}
+
+	var canPushChatMessage bool
+	err = p.data.ExecuteInTx(ctx, sql.LevelDefault, func(ctx context.Context) error {
+		err := p.data.UpdateMandate(ctx, &updated)
+		if err != nil {
+			return err
+		}
+
+		err = mandate_util.RevokePayment(ctx, p.data, mandateRecord, failedIndex)
+		if err != nil {
+			return err
+		}
+
+		if updated.State == mandate.StateFailed {
+			err = mandate_util.RevokePayments(ctx, p.data, mandateRecord, updated.PaymentsProcessed)
+			if err != nil {
+				return err
+			}
+		}
+
+		canPushChatMessage, err = chat_util.SendChatMessage(ctx, p.data, chat_util.CodeTeamName, chat.ChatTypeInternal, true, owner, chatMessage, false)
+		if err != nil {
+			return err
+		}
+
+		updated.CopyTo(mandateRecord)
+		return nil
+	})
+	if err != nil {
+		log.WithError(err).Warn("failure revoking failed payment")
+		return err
+	}
+
+	if canPushChatMessage {
+		// Best-effort send a push
+		push_util.SendChatMessagePushNotification(
+			ctx,
+			p.data,
+			p.pusher,
+			chat_util.CodeTeamName,
+			owner,
+			chatMessage,
+		)
+	}
+
+	recordPaymentFailedEvent(ctx, mandateRecord, failedIndex, reason)
+
+	return nil
+}
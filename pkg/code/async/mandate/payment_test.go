@@ -0,0 +1,159 @@
+package async_mandate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/code-payments/code-server/pkg/kin"
+	"github.com/code-payments/code-server/pkg/code/data/mandate"
+	"github.com/code-payments/code-server/pkg/code/data/user"
+	"github.com/code-payments/code-server/pkg/code/data/user/identity"
+)
+
+func TestProcessDuePayments_HappyPath(t *testing.T) {
+	env := setup(t, &testOverrides{})
+
+	owner := env.setupUser(t)
+	destination := env.setupUser(t)
+	env.fundUser(t, owner, kin.ToQuarks(100))
+
+	record := env.setupMandate(t, owner, destination, 3, true)
+	for i := uint32(0); i < 3; i++ {
+		env.assertPaymentDormant(t, record, i)
+	}
+
+	for i := uint32(0); i < 3; i++ {
+		require.NoError(t, env.worker.processDuePayments(env.ctx))
+		env.assertPaymentMade(t, record, i)
+
+		expectedState := mandate.StateActive
+		if i == 2 {
+			expectedState = mandate.StateCompleted
+		}
+		actual := env.assertMandateState(t, record, expectedState, i+1, 0, 0)
+
+		if expectedState == mandate.StateActive {
+			env.assertPaymentDormant(t, record, i+1)
+			assert.Equal(t, actual.GetPaymentTime(i+1).Unix(), actual.NextPaymentAt.Unix())
+			assert.True(t, actual.NextPaymentAt.After(time.Now()))
+
+			// The next payment isn't due yet
+			require.NoError(t, env.worker.processDuePayments(env.ctx))
+			env.assertPaymentDormant(t, record, i+1)
+
+			env.makeDue(t, record)
+		}
+	}
+
+	// Completed mandates are never processed again
+	require.NoError(t, env.worker.processDuePayments(env.ctx))
+	env.assertMandateState(t, record, mandate.StateCompleted, 3, 0, 0)
+	env.assertCodeTeamMessageCount(t, owner, 0)
+}
+
+func TestProcessDuePayments_InsufficientFunds(t *testing.T) {
+	env := setup(t, &testOverrides{})
+
+	owner := env.setupUser(t)
+	destination := env.setupUser(t)
+	env.fundUser(t, owner, kin.ToQuarks(15))
+
+	record := env.setupMandate(t, owner, destination, 4, true)
+
+	// First payment succeeds
+	require.NoError(t, env.worker.processDuePayments(env.ctx))
+	env.assertPaymentMade(t, record, 0)
+	env.assertMandateState(t, record, mandate.StateActive, 1, 0, 0)
+
+	// Second payment fails due to insufficient funds
+	env.makeDue(t, record)
+	require.NoError(t, env.worker.processDuePayments(env.ctx))
+	env.assertPaymentRevoked(t, record, 1)
+	env.assertPaymentDormant(t, record, 2)
+	env.assertMandateState(t, record, mandate.StateActive, 2, 1, 1)
+	env.assertCodeTeamMessageCount(t, owner, 1)
+
+	// Third payment succeeds after funding, which resets consecutive failures
+	env.fundUser(t, owner, kin.ToQuarks(10))
+	env.makeDue(t, record)
+	require.NoError(t, env.worker.processDuePayments(env.ctx))
+	env.assertPaymentMade(t, record, 2)
+	env.assertMandateState(t, record, mandate.StateActive, 3, 1, 0)
+
+	// Last payment fails, which completes the mandate
+	env.makeDue(t, record)
+	require.NoError(t, env.worker.processDuePayments(env.ctx))
+	env.assertPaymentRevoked(t, record, 3)
+	env.assertMandateState(t, record, mandate.StateCompleted, 4, 2, 1)
+	env.assertCodeTeamMessageCount(t, owner, 2)
+}
+
+func TestProcessDuePayments_TooManyConsecutiveFailures(t *testing.T) {
+	env := setup(t, &testOverrides{
+		maxConsecutiveFailures: 2,
+	})
+
+	owner := env.setupUser(t)
+	destination := env.setupUser(t)
+
+	record := env.setupMandate(t, owner, destination, 5, true)
+
+	require.NoError(t, env.worker.processDuePayments(env.ctx))
+	env.assertPaymentRevoked(t, record, 0)
+	env.assertMandateState(t, record, mandate.StateActive, 1, 1, 1)
+
+	env.makeDue(t, record)
+	require.NoError(t, env.worker.processDuePayments(env.ctx))
+	env.assertMandateState(t, record, mandate.StateFailed, 2, 2, 2)
+	for i := uint32(0); i < 5; i++ {
+		env.assertPaymentRevoked(t, record, i)
+	}
+	env.assertCodeTeamMessageCount(t, owner, 2)
+
+	// Failed mandates are never processed again
+	env.fundUser(t, owner, kin.ToQuarks(100))
+	require.NoError(t, env.worker.processDuePayments(env.ctx))
+	env.assertMandateState(t, record, mandate.StateFailed, 2, 2, 2)
+}
+
+func TestProcessDuePayments_BannedUser(t *testing.T) {
+	env := setup(t, &testOverrides{})
+
+	owner := env.setupUser(t)
+	destination := env.setupUser(t)
+	env.fundUser(t, owner, kin.ToQuarks(100))
+
+	record := env.setupMandate(t, owner, destination, 2, true)
+
+	verification, err := env.data.GetLatestPhoneVerificationForAccount(env.ctx, owner.owner.PublicKey().ToBase58())
+	require.NoError(t, err)
+	require.NoError(t, env.data.PutUser(env.ctx, &identity.Record{
+		ID: user.NewUserID(),
+		View: &user.View{
+			PhoneNumber: &verification.PhoneNumber,
+		},
+		IsBanned:  true,
+		CreatedAt: time.Now(),
+	}))
+
+	require.NoError(t, env.worker.processDuePayments(env.ctx))
+	env.assertPaymentRevoked(t, record, 0)
+	env.assertMandateState(t, record, mandate.StateActive, 1, 1, 1)
+}
+
+func TestProcessDuePayments_PendingAuthorization(t *testing.T) {
+	env := setup(t, &testOverrides{})
+
+	owner := env.setupUser(t)
+	destination := env.setupUser(t)
+	env.fundUser(t, owner, kin.ToQuarks(100))
+
+	record := env.setupMandate(t, owner, destination, 2, false)
+
+	require.NoError(t, env.worker.processDuePayments(env.ctx))
+	env.assertPaymentDormant(t, record, 0)
+	env.assertMandateState(t, record, mandate.StatePendingAuthorization, 0, 0, 0)
+}
@@ -0,0 +1,58 @@
+package async_mandate
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	push_lib "github.com/code-payments/code-server/pkg/push"
+	"github.com/code-payments/code-server/pkg/code/antispam"
+	"github.com/code-payments/code-server/pkg/code/async"
+	code_data "github.com/code-payments/code-server/pkg/code/data"
+	"github.com/code-payments/code-server/pkg/code/lawenforcement"
+	limit_util "github.com/code-payments/code-server/pkg/code/limit"
+)
+
+type service struct {
+	log           *logrus.Entry
+	conf          *conf
+	data          code_data.Provider
+	pusher        push_lib.Provider
+	antispamGuard *antispam.Guard
+	amlGuard      *lawenforcement.AntiMoneyLaunderingGuard
+	limits        *limit_util.Resolver
+}
+
+func New(data code_data.Provider, pusher push_lib.Provider, antispamGuard *antispam.Guard, configProvider ConfigProvider) async.Service {
+	return &service{
+		log:           logrus.StandardLogger().WithField("service", "mandate"),
+		conf:          configProvider(),
+		data:          data,
+		pusher:        pusher,
+		antispamGuard: antispamGuard,
+		amlGuard:      lawenforcement.NewAntiMoneyLaunderingGuard(data),
+		limits:        limit_util.NewResolver(data),
+	}
+}
+
+func (p *service) Start(ctx context.Context, interval time.Duration) error {
+	go func() {
+		err := p.paymentWorker(ctx, interval)
+		if err != nil && err != context.Canceled {
+			p.log.WithError(err).Warn("mandate payment processing loop terminated unexpectedly")
+		}
+	}()
+
+	go func() {
+		err := p.authorizationExpiryWorker(ctx, interval)
+		if err != nil && err != context.Canceled {
+			p.log.WithError(err).Warn("mandate authorization expiry loop terminated unexpectedly")
+		}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
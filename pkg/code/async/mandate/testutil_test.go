@@ -0,0 +1,316 @@
+package async_mandate
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mr-tron/base58"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	commonpb "github.com/code-payments/code-protobuf-api/generated/go/common/v1"
+
+	currency_lib "github.com/code-payments/code-server/pkg/currency"
+	memory_device_verifier "github.com/code-payments/code-server/pkg/device/memory"
+	"github.com/code-payments/code-server/pkg/kin"
+	memory_push "github.com/code-payments/code-server/pkg/push/memory"
+	"github.com/code-payments/code-server/pkg/solana"
+	timelock_token_v1 "github.com/code-payments/code-server/pkg/solana/timelock/v1"
+	"github.com/code-payments/code-server/pkg/testutil"
+	"github.com/code-payments/code-server/pkg/code/antispam"
+	chat_util "github.com/code-payments/code-server/pkg/code/chat"
+	"github.com/code-payments/code-server/pkg/code/common"
+	code_data "github.com/code-payments/code-server/pkg/code/data"
+	datatest "github.com/code-payments/code-server/pkg/code/data/test"
+	"github.com/code-payments/code-server/pkg/code/data/account"
+	"github.com/code-payments/code-server/pkg/code/data/action"
+	"github.com/code-payments/code-server/pkg/code/data/chat"
+	"github.com/code-payments/code-server/pkg/code/data/currency"
+	"github.com/code-payments/code-server/pkg/code/data/deposit"
+	"github.com/code-payments/code-server/pkg/code/data/fulfillment"
+	"github.com/code-payments/code-server/pkg/code/data/intent"
+	"github.com/code-payments/code-server/pkg/code/data/mandate"
+	"github.com/code-payments/code-server/pkg/code/data/nonce"
+	"github.com/code-payments/code-server/pkg/code/data/phone"
+	"github.com/code-payments/code-server/pkg/code/data/transaction"
+	"github.com/code-payments/code-server/pkg/code/data/vault"
+	exchange_rate_util "github.com/code-payments/code-server/pkg/code/exchangerate"
+	mandate_util "github.com/code-payments/code-server/pkg/code/mandate"
+)
+
+type testEnv struct {
+	ctx        context.Context
+	data       code_data.Provider
+	worker     *service
+	subsidizer *common.Account
+}
+
+type testUser struct {
+	owner   *common.Account
+	primary *common.TimelockAccounts
+}
+
+func setup(t *testing.T, testOverrides *testOverrides) *testEnv {
+	ctx := context.Background()
+
+	db := datatest.NewDataProvider()
+
+	subsidizer := testutil.SetupRandomSubsidizer(t, db)
+
+	require.NoError(t, db.ImportExchangeRates(ctx, &currency.MultiRateRecord{
+		Time: exchange_rate_util.GetLatestExchangeRateTime(),
+		Rates: map[string]float64{
+			string(currency_lib.USD): 0.1,
+		},
+	}))
+
+	antispamGuard := antispam.NewGuard(
+		db,
+		memory_device_verifier.NewMemoryDeviceVerifier(),
+		antispam.WithPaymentRateLimit(time.Nanosecond),
+	)
+
+	env := &testEnv{
+		ctx:        ctx,
+		data:       db,
+		worker:     New(db, memory_push.NewPushProvider(), antispamGuard, withManualTestOverrides(testOverrides)).(*service),
+		subsidizer: subsidizer,
+	}
+	env.generateAvailableNonces(t, 50)
+	return env
+}
+
+func (e *testEnv) generateAvailableNonces(t *testing.T, count int) {
+	for i := 0; i < count; i++ {
+		nonceAccount := testutil.NewRandomAccount(t)
+
+		var bh solana.Blockhash
+		rand.Read(bh[:])
+
+		nonceKey := &vault.Record{
+			PublicKey:  nonceAccount.PublicKey().ToBase58(),
+			PrivateKey: nonceAccount.PrivateKey().ToBase58(),
+			State:      vault.StateAvailable,
+			CreatedAt:  time.Now(),
+		}
+		nonceRecord := &nonce.Record{
+			Address:   nonceAccount.PublicKey().ToBase58(),
+			Authority: e.subsidizer.PublicKey().ToBase58(),
+			Blockhash: base58.Encode(bh[:]),
+			Purpose:   nonce.PurposeMandatePayment,
+			State:     nonce.StateAvailable,
+		}
+		require.NoError(t, e.data.SaveKey(e.ctx, nonceKey))
+		require.NoError(t, e.data.SaveNonce(e.ctx, nonceRecord))
+	}
+}
+
+func (e *testEnv) setupUser(t *testing.T) *testUser {
+	owner := testutil.NewRandomAccount(t)
+
+	timelockAccounts, err := owner.GetTimelockAccounts(timelock_token_v1.DataVersion1)
+	require.NoError(t, err)
+	require.NoError(t, e.data.SaveTimelock(e.ctx, timelockAccounts.ToDBRecord()))
+
+	accountInfoRecord := &account.Record{
+		OwnerAccount:     owner.PublicKey().ToBase58(),
+		AuthorityAccount: owner.PublicKey().ToBase58(),
+		TokenAccount:     timelockAccounts.Vault.PublicKey().ToBase58(),
+
+		AccountType: commonpb.AccountType_PRIMARY,
+		Index:       0,
+
+		CreatedAt: time.Now(),
+	}
+	require.NoError(t, e.data.CreateAccountInfo(e.ctx, accountInfoRecord))
+
+	require.NoError(t, e.data.SavePhoneVerification(e.ctx, &phone.Verification{
+		PhoneNumber:    fmt.Sprintf("+1800555%04d", time.Now().UnixNano()%10000),
+		OwnerAccount:   owner.PublicKey().ToBase58(),
+		CreatedAt:      time.Now(),
+		LastVerifiedAt: time.Now(),
+	}))
+
+	return &testUser{
+		owner:   owner,
+		primary: timelockAccounts,
+	}
+}
+
+func (e *testEnv) fundUser(t *testing.T, user *testUser, quarks uint64) {
+	depositRecord := &deposit.Record{
+		Signature:      fmt.Sprintf("txn%d", time.Now().UnixNano()),
+		Destination:    user.primary.Vault.PublicKey().ToBase58(),
+		Amount:         quarks,
+		UsdMarketValue: 0.1 * float64(quarks) / float64(kin.QuarksPerKin),
+
+		ConfirmationState: transaction.ConfirmationFinalized,
+		Slot:              12345,
+	}
+	require.NoError(t, e.data.SaveExternalDeposit(e.ctx, depositRecord))
+}
+
+func (e *testEnv) setupMandate(t *testing.T, owner, destination *testUser, maxPayments uint32, authorize bool) *mandate.Record {
+	firstPaymentAt := time.Now().Add(-time.Minute)
+
+	record := &mandate.Record{
+		MandateId: testutil.NewRandomAccount(t).PublicKey().ToBase58(),
+
+		OwnerAccount:       owner.owner.PublicKey().ToBase58(),
+		SourceTokenAccount: owner.primary.Vault.PublicKey().ToBase58(),
+
+		DestinationOwnerAccount: destination.owner.PublicKey().ToBase58(),
+		DestinationTokenAccount: destination.primary.Vault.PublicKey().ToBase58(),
+
+		ExchangeCurrency: currency_lib.USD,
+		ExchangeRate:     0.1,
+		NativeAmount:     1.0,
+		Quantity:         kin.ToQuarks(10),
+
+		Cadence: mandate.CadenceDaily,
+
+		MaxPayments: maxPayments,
+
+		FirstPaymentAt: firstPaymentAt,
+		NextPaymentAt:  firstPaymentAt,
+
+		Signature: "signature",
+
+		State: mandate.StatePendingAuthorization,
+	}
+
+	txns, err := mandate_util.ReservePayments(e.ctx, e.data, record)
+	require.NoError(t, err)
+	require.Len(t, txns, int(maxPayments))
+
+	if authorize {
+		var signatures [][]byte
+		for _, txn := range txns {
+			signatures = append(signatures, ed25519.Sign(owner.owner.PrivateKey().ToBytes(), txn.Message.Marshal()))
+		}
+		require.NoError(t, mandate_util.AuthorizePayments(e.ctx, e.data, record, signatures))
+	}
+
+	return record
+}
+
+// makeDue moves a mandate's next payment into the past, as if time passed
+func (e *testEnv) makeDue(t *testing.T, record *mandate.Record) {
+	actual, err := e.data.GetMandate(e.ctx, record.MandateId)
+	require.NoError(t, err)
+
+	actual.NextPaymentAt = time.Now().Add(-time.Second)
+	require.NoError(t, e.data.UpdateMandate(e.ctx, actual))
+}
+
+func (e *testEnv) assertMandateState(t *testing.T, record *mandate.Record, expectedState mandate.State, expectedProcessed, expectedFailed, expectedConsecutiveFailures uint32) *mandate.Record {
+	actual, err := e.data.GetMandate(e.ctx, record.MandateId)
+	require.NoError(t, err)
+	assert.Equal(t, expectedState, actual.State)
+	assert.Equal(t, expectedProcessed, actual.PaymentsProcessed)
+	assert.Equal(t, expectedFailed, actual.PaymentsFailed)
+	assert.Equal(t, expectedConsecutiveFailures, actual.ConsecutiveFailures)
+	return actual
+}
+
+func (e *testEnv) assertPaymentMade(t *testing.T, record *mandate.Record, index uint32) {
+	intentId := mandate_util.GetPaymentIntentId(record.MandateId, index)
+
+	intentRecord, err := e.data.GetIntent(e.ctx, intentId)
+	require.NoError(t, err)
+	assert.Equal(t, intent.SendPublicPayment, intentRecord.IntentType)
+	assert.Equal(t, kin.Mint, intentRecord.Mint)
+	assert.Equal(t, record.OwnerAccount, intentRecord.InitiatorOwnerAccount)
+	assert.NotNil(t, intentRecord.InitiatorPhoneNumber)
+	assert.Equal(t, record.DestinationOwnerAccount, intentRecord.SendPublicPaymentMetadata.DestinationOwnerAccount)
+	assert.Equal(t, record.DestinationTokenAccount, intentRecord.SendPublicPaymentMetadata.DestinationTokenAccount)
+	assert.Equal(t, record.Quantity, intentRecord.SendPublicPaymentMetadata.Quantity)
+	assert.Equal(t, record.ExchangeCurrency, intentRecord.SendPublicPaymentMetadata.ExchangeCurrency)
+	assert.Equal(t, record.NativeAmount, intentRecord.SendPublicPaymentMetadata.NativeAmount)
+	assert.Equal(t, 1.0, intentRecord.SendPublicPaymentMetadata.UsdMarketValue)
+	assert.True(t, intentRecord.SendPublicPaymentMetadata.IsWithdrawal)
+	assert.Equal(t, intent.StatePending, intentRecord.State)
+
+	actionRecord, err := e.data.GetActionById(e.ctx, intentId, 0)
+	require.NoError(t, err)
+	assert.Equal(t, action.StatePending, actionRecord.State)
+	require.NotNil(t, actionRecord.Quantity)
+	assert.Equal(t, record.Quantity, *actionRecord.Quantity)
+
+	fulfillmentRecord := e.getPaymentFulfillment(t, record, index)
+	assert.Equal(t, fulfillment.StateUnknown, fulfillmentRecord.State)
+	assert.False(t, fulfillmentRecord.DisableActiveScheduling)
+
+	var txn solana.Transaction
+	require.NoError(t, txn.Unmarshal(fulfillmentRecord.Data))
+	for i := 0; i < int(txn.Message.Header.NumSignatures); i++ {
+		assert.True(t, ed25519.Verify(txn.Message.Accounts[i], txn.Message.Marshal(), txn.Signatures[i][:]))
+	}
+
+	nonceRecord, err := e.data.GetNonce(e.ctx, *fulfillmentRecord.Nonce)
+	require.NoError(t, err)
+	assert.Equal(t, nonce.StateReserved, nonceRecord.State)
+	assert.Equal(t, *fulfillmentRecord.Signature, nonceRecord.Signature)
+}
+
+func (e *testEnv) assertPaymentDormant(t *testing.T, record *mandate.Record, index uint32) {
+	intentId := mandate_util.GetPaymentIntentId(record.MandateId, index)
+
+	_, err := e.data.GetIntent(e.ctx, intentId)
+	assert.Equal(t, intent.ErrIntentNotFound, err)
+
+	actionRecord, err := e.data.GetActionById(e.ctx, intentId, 0)
+	require.NoError(t, err)
+	assert.Equal(t, action.StateUnknown, actionRecord.State)
+	assert.Nil(t, actionRecord.Quantity)
+
+	fulfillmentRecord := e.getPaymentFulfillment(t, record, index)
+	assert.Equal(t, fulfillment.StateUnknown, fulfillmentRecord.State)
+	assert.True(t, fulfillmentRecord.DisableActiveScheduling)
+
+	nonceRecord, err := e.data.GetNonce(e.ctx, *fulfillmentRecord.Nonce)
+	require.NoError(t, err)
+	assert.Equal(t, nonce.StateReserved, nonceRecord.State)
+	assert.Equal(t, *fulfillmentRecord.Signature, nonceRecord.Signature)
+}
+
+func (e *testEnv) assertPaymentRevoked(t *testing.T, record *mandate.Record, index uint32) {
+	intentId := mandate_util.GetPaymentIntentId(record.MandateId, index)
+
+	_, err := e.data.GetIntent(e.ctx, intentId)
+	assert.Equal(t, intent.ErrIntentNotFound, err)
+
+	actionRecord, err := e.data.GetActionById(e.ctx, intentId, 0)
+	require.NoError(t, err)
+	assert.Equal(t, action.StateRevoked, actionRecord.State)
+
+	fulfillmentRecord := e.getPaymentFulfillment(t, record, index)
+	assert.Equal(t, fulfillment.StateRevoked, fulfillmentRecord.State)
+	assert.Empty(t, fulfillmentRecord.Data)
+
+	nonceRecord, err := e.data.GetNonce(e.ctx, *fulfillmentRecord.Nonce)
+	require.NoError(t, err)
+	assert.Equal(t, nonce.StateAvailable, nonceRecord.State)
+	assert.Empty(t, nonceRecord.Signature)
+}
+
+func (e *testEnv) assertCodeTeamMessageCount(t *testing.T, user *testUser, expected int) {
+	messageRecords, err := e.data.GetAllChatMessages(e.ctx, chat.GetChatId(chat_util.CodeTeamName, user.owner.PublicKey().ToBase58(), true))
+	if expected == 0 {
+		assert.Equal(t, chat.ErrMessageNotFound, err)
+		return
+	}
+	require.NoError(t, err)
+	assert.Len(t, messageRecords, expected)
+}
+
+func (e *testEnv) getPaymentFulfillment(t *testing.T, record *mandate.Record, index uint32) *fulfillment.Record {
+	fulfillmentRecords, err := e.data.GetAllFulfillmentsByAction(e.ctx, mandate_util.GetPaymentIntentId(record.MandateId, index), 0)
+	require.NoError(t, err)
+	require.Len(t, fulfillmentRecords, 1)
+	return fulfillmentRecords[0]
+}
@@ -27,6 +27,7 @@ func (p *service) metricsGaugeWorker(ctx context.Context) error {
 				nonce.PurposeClientTransaction,
 				nonce.PurposeInternalServerProcess,
 				nonce.PurposeOnDemandTransaction,
+				nonce.PurposeMandatePayment,
 			} {
 				for _, state := range []nonce.State{
 					nonce.StateUnknown,
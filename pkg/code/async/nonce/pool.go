@@ -45,6 +45,10 @@ func (p *service) worker(serviceCtx context.Context, state nonce.State, interval
 			// Process the batch of nonce accounts in parallel
 			var wg sync.WaitGroup
 			for _, item := range items {
+				if !p.membership.Owns(item.Address) {
+					continue
+				}
+
 				wg.Add(1)
 
 				go func(record *nonce.Record) {
@@ -1 +1,82 @@
 package async_nonce
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mr-tron/base58"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/code-payments/code-server/pkg/solana"
+	"github.com/code-payments/code-server/pkg/solana/simulator"
+	"github.com/code-payments/code-server/pkg/solana/system"
+	"github.com/code-payments/code-server/pkg/testutil"
+	"github.com/code-payments/code-server/pkg/code/common"
+	datatest "github.com/code-payments/code-server/pkg/code/data/test"
+	"github.com/code-payments/code-server/pkg/code/data/nonce"
+	"github.com/code-payments/code-server/pkg/code/data/vault"
+)
+
+func TestNonceLifecycle_Simulator(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ledger := simulator.NewLedger()
+	data := datatest.NewDataProviderWithLedger(ledger)
+	subsidizer := testutil.SetupRandomSubsidizer(t, data)
+
+	key, err := vault.CreateKey()
+	require.NoError(t, err)
+	key.State = vault.StateAvailable
+	require.NoError(t, data.SaveKey(ctx, key))
+
+	p := New(data, nil, nil, nil).(*service)
+
+	record, err := p.createNonce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, key.PublicKey, record.Address)
+	assert.Equal(t, subsidizer.PublicKey().ToBase58(), record.Authority)
+	assert.Equal(t, subsidizer.PublicKey().ToBase58(), record.FeePayer)
+	assert.Equal(t, nonce.StateUnknown, record.State)
+
+	// Creation is broadcast in the background
+	require.Eventually(t, func() bool {
+		sig, err := base58.Decode(record.Signature)
+		require.NoError(t, err)
+
+		var signature solana.Signature
+		copy(signature[:], sig)
+		confirmed, err := ledger.GetConfirmationStatus(signature, solana.CommitmentFinalized)
+		return err == nil && confirmed
+	}, time.Second, 10*time.Millisecond)
+
+	// Unknown -> Released
+	record, err = data.GetNonce(ctx, record.Address)
+	require.NoError(t, err)
+	require.NoError(t, p.handle(ctx, record))
+
+	record, err = data.GetNonce(ctx, record.Address)
+	require.NoError(t, err)
+	assert.Equal(t, nonce.StateReleased, record.State)
+
+	// Released -> Available
+	require.NoError(t, p.handle(ctx, record))
+
+	record, err = data.GetNonce(ctx, record.Address)
+	require.NoError(t, err)
+	assert.Equal(t, nonce.StateAvailable, record.State)
+	assert.Empty(t, record.Signature)
+
+	nonceAccountPublicKey, err := record.GetPublicKey()
+	require.NoError(t, err)
+	accountInfo, err := ledger.GetAccountInfo(nonceAccountPublicKey, solana.CommitmentFinalized)
+	require.NoError(t, err)
+	assert.EqualValues(t, system.SystemAccount, accountInfo.Owner)
+
+	var nonceAccount system.NonceAccount
+	require.NoError(t, nonceAccount.Unmarshal(accountInfo.Data))
+	assert.Equal(t, base58.Encode(nonceAccount.Blockhash), record.Blockhash)
+	assert.EqualValues(t, common.GetSubsidizer().PublicKey().ToBytes(), nonceAccount.Authority)
+}
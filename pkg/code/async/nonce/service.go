@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
@@ -12,6 +13,8 @@ import (
 	"github.com/code-payments/code-server/pkg/code/data/nonce"
 
 	"github.com/code-payments/code-server/pkg/code/async"
+	async_sharding "github.com/code-payments/code-server/pkg/code/async/sharding"
+	"github.com/code-payments/code-server/pkg/code/common"
 	code_data "github.com/code-payments/code-server/pkg/code/data"
 )
 
@@ -35,17 +38,38 @@ type service struct {
 	log  *logrus.Entry
 	data code_data.Provider
 
+	feePayers              *common.FeePayerPool
+	feePayerReservationsMu sync.Mutex
+	feePayerReservations   map[string]*common.FeePayerReservation
+
+	membership *async_sharding.Membership
+
+	priorityFees common.PriorityFeeEstimator
+
 	rent   uint64
 	prefix string
 	size   int
 }
 
-func New(data code_data.Provider) async.Service {
+// New returns a new nonce service. When a fee payer pool is provided, it pays
+// for creating nonce accounts instead of the subsidizer, which remains the nonce
+// authority.
+//
+// Nonce accounts are sharded by address across the members of the provided
+// group membership. All nonce accounts are processed when it's nil.
+//
+// The provided priority fee estimator is used when projecting the subsidizer's
+// in flight spend, and should match the sequencer's priority fee policy.
+func New(data code_data.Provider, feePayers *common.FeePayerPool, membership *async_sharding.Membership, priorityFees common.PriorityFeeEstimator) async.Service {
 	return &service{
-		log:    logrus.StandardLogger().WithField("service", "nonce"),
-		data:   data,
-		prefix: nonceKeyPrefixDefault,
-		size:   noncePoolSizeDefault,
+		log:                  logrus.StandardLogger().WithField("service", "nonce"),
+		data:                 data,
+		feePayers:            feePayers,
+		feePayerReservations: make(map[string]*common.FeePayerReservation),
+		membership:           membership,
+		priorityFees:         priorityFees,
+		prefix:               nonceKeyPrefixDefault,
+		size:                 noncePoolSizeDefault,
 	}
 }
 
@@ -24,7 +24,13 @@ var (
 func (p *service) markReleased(ctx context.Context, record *nonce.Record) error {
 	// We know the nonce is ready but don't know the blockhash for it.
 	record.State = nonce.StateReleased
-	return p.data.SaveNonce(ctx, record)
+	err := p.data.SaveNonce(ctx, record)
+	if err != nil {
+		return err
+	}
+
+	p.releaseFeePayer(record.Address)
+	return nil
 }
 
 func (p *service) markAvailable(ctx context.Context, record *nonce.Record) error {
@@ -36,16 +42,36 @@ func (p *service) markAvailable(ctx context.Context, record *nonce.Record) error
 func (p *service) markInvalid(ctx context.Context, record *nonce.Record) error {
 	// We failed to create the nonce account (insufficient funds, etc).
 	record.State = nonce.StateInvalid
-	return p.data.SaveNonce(ctx, record)
+	err := p.data.SaveNonce(ctx, record)
+	if err != nil {
+		return err
+	}
+
+	p.releaseFeePayer(record.Address)
+	return nil
 }
 
-func (p *service) sign(tx *solana.Transaction, key *vault.Record) error {
+// releaseFeePayer releases the fee payer reservation for a nonce account that's
+// no longer being created. Reservations are only tracked in memory, so nonces
+// created before a restart won't have one.
+func (p *service) releaseFeePayer(address string) {
+	p.feePayerReservationsMu.Lock()
+	reservation, ok := p.feePayerReservations[address]
+	delete(p.feePayerReservations, address)
+	p.feePayerReservationsMu.Unlock()
+
+	if ok {
+		reservation.Release()
+	}
+}
+
+func (p *service) sign(tx *solana.Transaction, feePayer *common.Account, key *vault.Record) error {
 	priv, err := key.GetPrivateKey()
 	if err != nil {
 		return err
 	}
 
-	err = tx.Sign(common.GetSubsidizer().PrivateKey().ToBytes(), priv)
+	err = tx.Sign(feePayer.PrivateKey().ToBytes(), priv)
 	if err != nil {
 		return err
 	}
@@ -97,11 +123,41 @@ func (p *service) getRentAmount(ctx context.Context) (uint64, error) {
 }
 
 func (p *service) createNonce(ctx context.Context) (*nonce.Record, error) {
-	err := common.EnforceMinimumSubsidizerBalance(ctx, p.data)
+	feePayer := common.GetSubsidizer()
+
+	var reservation *common.FeePayerReservation
+	if p.feePayers != nil {
+		var err error
+		reservation, err = p.feePayers.ReserveForNonceAccount(ctx)
+		if err != nil {
+			return nil, err
+		}
+		feePayer = reservation.FeePayer()
+	} else {
+		err := common.EnforceMinimumSubsidizerBalance(ctx, p.data, p.priorityFees)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	res, err := p.createNonceWithFeePayer(ctx, feePayer)
 	if err != nil {
+		if reservation != nil {
+			reservation.Release()
+		}
 		return nil, err
 	}
 
+	if reservation != nil {
+		p.feePayerReservationsMu.Lock()
+		p.feePayerReservations[res.Address] = reservation
+		p.feePayerReservationsMu.Unlock()
+	}
+
+	return res, nil
+}
+
+func (p *service) createNonceWithFeePayer(ctx context.Context, feePayer *common.Account) (*nonce.Record, error) {
 	key, err := p.getVaultKey(ctx)
 	if err != nil {
 		return nil, err
@@ -112,13 +168,14 @@ func (p *service) createNonce(ctx context.Context) (*nonce.Record, error) {
 		Authority: common.GetSubsidizer().PublicKey().ToBase58(),
 		Purpose:   nonce.PurposeClientTransaction, // todo: intelligently set a purpose
 		State:     nonce.StateUnknown,
+		FeePayer:  feePayer.PublicKey().ToBase58(),
 	}
 
-	tx, err := p.createNonceAccountTx(ctx, &res)
+	tx, err := p.createNonceAccountTx(ctx, &res, feePayer)
 	if err != nil {
 		return nil, err
 	}
-	err = p.sign(tx, key)
+	err = p.sign(tx, feePayer, key)
 	if err != nil {
 		return nil, err
 	}
@@ -135,12 +192,13 @@ func (p *service) createNonce(ctx context.Context) (*nonce.Record, error) {
 	return &res, nil
 }
 
-func (p *service) createNonceAccountTx(ctx context.Context, nonce *nonce.Record) (*solana.Transaction, error) {
+func (p *service) createNonceAccountTx(ctx context.Context, nonce *nonce.Record, feePayer *common.Account) (*solana.Transaction, error) {
 	rent, err := p.getRentAmount(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	payerPub := feePayer.PublicKey().ToBytes()
 	subPub := common.GetSubsidizer().PublicKey().ToBytes()
 	noncePub, err := nonce.GetPublicKey()
 	if err != nil {
@@ -150,7 +208,7 @@ func (p *service) createNonceAccountTx(ctx context.Context, nonce *nonce.Record)
 	instructions := []solana.Instruction{
 		memo.Instruction(fmt.Sprintf("nonce:%d", nonce.Id)),
 		system.CreateAccount(
-			subPub,
+			payerPub,
 			noncePub,
 			system.SystemAccount,
 			rent,
@@ -162,7 +220,7 @@ func (p *service) createNonceAccountTx(ctx context.Context, nonce *nonce.Record)
 		),
 	}
 
-	tx := solana.NewTransaction(subPub, instructions...)
+	tx := solana.NewTransaction(payerPub, instructions...)
 
 	bh, err := p.getLatestBlockhash(ctx)
 	if err != nil {
@@ -213,6 +213,32 @@ func (h *SaveRecentRootActionHandler) OnFulfillmentStateChange(ctx context.Conte
 	return nil
 }
 
+type TreasuryPoolFundingActionHandler struct {
+	data code_data.Provider
+}
+
+func NewTreasuryPoolFundingActionHandler(data code_data.Provider) ActionHandler {
+	return &TreasuryPoolFundingActionHandler{
+		data: data,
+	}
+}
+
+func (h *TreasuryPoolFundingActionHandler) OnFulfillmentStateChange(ctx context.Context, fulfillmentRecord *fulfillment.Record, newState fulfillment.State) error {
+	if fulfillmentRecord.FulfillmentType != fulfillment.TransferToTreasuryPool {
+		return errors.New("unexpected fulfillment type")
+	}
+
+	if newState == fulfillment.StateConfirmed {
+		return markActionConfirmed(ctx, h.data, fulfillmentRecord.Intent, fulfillmentRecord.ActionId)
+	}
+
+	if newState == fulfillment.StateFailed {
+		return markActionFailed(ctx, h.data, fulfillmentRecord.Intent, fulfillmentRecord.ActionId)
+	}
+
+	return nil
+}
+
 func validateActionState(record *action.Record, states ...action.State) error {
 	for _, validState := range states {
 		if record.State == validState {
@@ -288,5 +314,6 @@ func getActionHandlers(data code_data.Provider) map[action.Type]ActionHandler {
 	handlersByType[action.NoPrivacyWithdraw] = NewNoPrivacyWithdrawActionHandler(data)
 	handlersByType[action.PrivateTransfer] = NewPrivateTransferActionHandler(data)
 	handlersByType[action.SaveRecentRoot] = NewSaveRecentRootActionHandler(data)
+	handlersByType[action.TreasuryPoolFunding] = NewTreasuryPoolFundingActionHandler(data)
 	return handlersByType
 }
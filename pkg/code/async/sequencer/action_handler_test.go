@@ -15,6 +15,7 @@ import (
 	splitter_token "github.com/code-payments/code-server/pkg/solana/splitter"
 	"github.com/code-payments/code-server/pkg/testutil"
 	code_data "github.com/code-payments/code-server/pkg/code/data"
+	datatest "github.com/code-payments/code-server/pkg/code/data/test"
 	"github.com/code-payments/code-server/pkg/code/data/action"
 	"github.com/code-payments/code-server/pkg/code/data/commitment"
 	"github.com/code-payments/code-server/pkg/code/data/fulfillment"
@@ -233,6 +234,34 @@ func TestSaveRecentRootActionHandler_TransitionToStateFailed(t *testing.T) {
 	env.assertActionState(t, fulfillmentRecord.Intent, fulfillmentRecord.ActionId, action.StateFailed)
 }
 
+func TestTreasuryPoolFundingActionHandler_TransitionToStateConfirmed(t *testing.T) {
+	env := setupActionHandlerTestEnv(t)
+
+	handler := env.handlersByType[action.TreasuryPoolFunding]
+	fulfillmentRecords := env.createIntent(t, intent.TreasuryPoolFunding)
+	fulfillmentRecord := getFirstFulfillmentOfType(t, fulfillmentRecords, fulfillment.TransferToTreasuryPool)
+
+	require.NoError(t, handler.OnFulfillmentStateChange(env.ctx, fulfillmentRecord, fulfillment.StatePending))
+	env.assertActionState(t, fulfillmentRecord.Intent, fulfillmentRecord.ActionId, action.StatePending)
+
+	require.NoError(t, handler.OnFulfillmentStateChange(env.ctx, fulfillmentRecord, fulfillment.StateConfirmed))
+	env.assertActionState(t, fulfillmentRecord.Intent, fulfillmentRecord.ActionId, action.StateConfirmed)
+}
+
+func TestTreasuryPoolFundingActionHandler_TransitionToStateFailed(t *testing.T) {
+	env := setupActionHandlerTestEnv(t)
+
+	handler := env.handlersByType[action.TreasuryPoolFunding]
+	fulfillmentRecords := env.createIntent(t, intent.TreasuryPoolFunding)
+	fulfillmentRecord := getFirstFulfillmentOfType(t, fulfillmentRecords, fulfillment.TransferToTreasuryPool)
+
+	require.NoError(t, handler.OnFulfillmentStateChange(env.ctx, fulfillmentRecord, fulfillment.StatePending))
+	env.assertActionState(t, fulfillmentRecord.Intent, fulfillmentRecord.ActionId, action.StatePending)
+
+	require.NoError(t, handler.OnFulfillmentStateChange(env.ctx, fulfillmentRecord, fulfillment.StateFailed))
+	env.assertActionState(t, fulfillmentRecord.Intent, fulfillmentRecord.ActionId, action.StateFailed)
+}
+
 type actionHandlerTestEnv struct {
 	ctx            context.Context
 	data           code_data.Provider
@@ -240,7 +269,7 @@ type actionHandlerTestEnv struct {
 }
 
 func setupActionHandlerTestEnv(t *testing.T) actionHandlerTestEnv {
-	db := code_data.NewTestDataProvider()
+	db := datatest.NewDataProvider()
 	testutil.SetupRandomSubsidizer(t, db)
 	return actionHandlerTestEnv{
 		ctx:            context.Background(),
@@ -469,6 +498,28 @@ func (e *actionHandlerTestEnv) createIntent(t *testing.T, intentType intent.Type
 		}
 		actionRecords = append(actionRecords, actionRecord)
 
+	case intent.TreasuryPoolFunding:
+		intentRecord.TreasuryPoolFundingMetadata = &intent.TreasuryPoolFundingMetadata{
+			TreasuryPool: "treasury",
+			Source:       "hot-wallet",
+			Quantity:     kin.ToQuarks(1_000_000),
+		}
+
+		actionRecord := &action.Record{
+			Intent:     intentRecord.IntentId,
+			IntentType: intentType,
+
+			ActionId:   0,
+			ActionType: action.TreasuryPoolFunding,
+
+			Source:      "hot-wallet",
+			Destination: pointer.String("treasury-vault"),
+			Quantity:    pointer.Uint64(kin.ToQuarks(1_000_000)),
+
+			State: action.StatePending,
+		}
+		actionRecords = append(actionRecords, actionRecord)
+
 	case intent.SendPublicPayment:
 		intentRecord.SendPublicPaymentMetadata = &intent.SendPublicPaymentMetadata{
 			DestinationTokenAccount: "destination",
@@ -625,6 +676,15 @@ func (e *actionHandlerTestEnv) createIntent(t *testing.T, intentType intent.Type
 					Source:          actionRecord.Source,
 				},
 			)
+		case action.TreasuryPoolFunding:
+			newFulfillmentRecords = append(
+				newFulfillmentRecords,
+				&fulfillment.Record{
+					FulfillmentType: fulfillment.TransferToTreasuryPool,
+					Source:          actionRecord.Source,
+					Destination:     actionRecord.Destination,
+				},
+			)
 		default:
 			require.Fail(t, "unhandled action type")
 		}
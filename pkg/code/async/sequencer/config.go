@@ -2,6 +2,7 @@ package async_sequencer
 
 import (
 	"github.com/code-payments/code-server/pkg/config"
+	"github.com/code-payments/code-server/pkg/config/dynamic"
 	"github.com/code-payments/code-server/pkg/config/env"
 	"github.com/code-payments/code-server/pkg/config/memory"
 	"github.com/code-payments/code-server/pkg/config/wrapper"
@@ -19,20 +20,52 @@ const (
 	MaxGlobalFailedFulfillmentsConfigEnvName = envConfigPrefix + "MAX_GLOBAL_FAILED_FULFILLMENTS"
 	defaultMaxGlobalFailedFulfillments       = 10
 
+	MaxFailedFulfillmentRetriesConfigEnvName = envConfigPrefix + "MAX_FAILED_FULFILLMENT_RETRIES"
+	defaultMaxFailedFulfillmentRetries       = 3
+
 	//FulfillmentBatchSizeConfigEnvName = envConfigPrefix + "WORKER_BATCH_SIZE"
 	//defaultFulfillmentBatchSize       = 100
 
 	EnableSubsidizerChecksConfigEnvName = envConfigPrefix + "ENABLE_SUBSIDIZER_CHECKS"
 	defaultEnableSubsidizerChecks       = true
+
+	PriorityFeePolicyConfigEnvName = envConfigPrefix + "PRIORITY_FEE_POLICY"
+	defaultPriorityFeePolicy       = PriorityFeePolicyNone
+
+	StaticPriorityFeeConfigEnvName = envConfigPrefix + "STATIC_PRIORITY_FEE"
+	defaultStaticPriorityFee       = 0
+
+	RecentPriorityFeePercentileConfigEnvName = envConfigPrefix + "RECENT_PRIORITY_FEE_PERCENTILE"
+	defaultRecentPriorityFeePercentile       = 0.75
+
+	MinPriorityFeeConfigEnvName = envConfigPrefix + "MIN_PRIORITY_FEE"
+	defaultMinPriorityFee       = 0
+
+	MaxPriorityFeeConfigEnvName = envConfigPrefix + "MAX_PRIORITY_FEE"
+	defaultMaxPriorityFee       = 100_000
+
+	PriorityFeeOverridesConfigEnvName = envConfigPrefix + "PRIORITY_FEE_OVERRIDES"
+	defaultPriorityFeeOverrides       = ""
+
+	ComputeUnitLimitOverridesConfigEnvName = envConfigPrefix + "COMPUTE_UNIT_LIMIT_OVERRIDES"
+	defaultComputeUnitLimitOverrides       = ""
 )
 
 type conf struct {
 	disableTransactionScheduling config.Bool
 	disableTransactionSubmission config.Bool
 	maxGlobalFailedFulfillments  config.Uint64
+	maxFailedFulfillmentRetries  config.Uint64
 	//fulfillmentBatchSize          config.Uint64
 	enableSubsidizerChecks        config.Bool
 	enableCachedTransactionLookup config.Bool
+	priorityFeePolicy             config.String
+	staticPriorityFee             config.Uint64
+	recentPriorityFeePercentile   config.Float64
+	minPriorityFee                config.Uint64
+	maxPriorityFee                config.Uint64
+	priorityFeeOverrides          config.String
+	computeUnitLimitOverrides     config.String
 }
 
 // ConfigProvider defines how config values are pulled
@@ -45,9 +78,41 @@ func WithEnvConfigs() ConfigProvider {
 			disableTransactionScheduling: env.NewBoolConfig(DisableTransactionSchedulingConfigEnvName, defaultDisableTransactionScheduling),
 			disableTransactionSubmission: env.NewBoolConfig(DisableTransactionSubmissionConfigEnvName, defaultDisableTransactionSubmission),
 			maxGlobalFailedFulfillments:  env.NewUint64Config(MaxGlobalFailedFulfillmentsConfigEnvName, defaultMaxGlobalFailedFulfillments),
+			maxFailedFulfillmentRetries:  env.NewUint64Config(MaxFailedFulfillmentRetriesConfigEnvName, defaultMaxFailedFulfillmentRetries),
 			//fulfillmentBatchSize:          env.NewUint64Config(FulfillmentBatchSizeConfigEnvName, defaultFulfillmentBatchSize),
 			enableSubsidizerChecks:        env.NewBoolConfig(EnableSubsidizerChecksConfigEnvName, defaultEnableSubsidizerChecks),
 			enableCachedTransactionLookup: wrapper.NewBoolConfig(memory.NewConfig(false), false),
+			priorityFeePolicy:             env.NewStringConfig(PriorityFeePolicyConfigEnvName, defaultPriorityFeePolicy),
+			staticPriorityFee:             env.NewUint64Config(StaticPriorityFeeConfigEnvName, defaultStaticPriorityFee),
+			recentPriorityFeePercentile:   env.NewFloat64Config(RecentPriorityFeePercentileConfigEnvName, defaultRecentPriorityFeePercentile),
+			minPriorityFee:                env.NewUint64Config(MinPriorityFeeConfigEnvName, defaultMinPriorityFee),
+			maxPriorityFee:                env.NewUint64Config(MaxPriorityFeeConfigEnvName, defaultMaxPriorityFee),
+			priorityFeeOverrides:          env.NewStringConfig(PriorityFeeOverridesConfigEnvName, defaultPriorityFeeOverrides),
+			computeUnitLimitOverrides:     env.NewStringConfig(ComputeUnitLimitOverridesConfigEnvName, defaultComputeUnitLimitOverrides),
+		}
+	}
+}
+
+// WithDynamicConfigs returns configuration pulled from a dynamic source, which
+// allows kill switches like transaction submission to be flipped live. Values
+// not set in the source fall back to environment variables.
+func WithDynamicConfigs(source config.Source) ConfigProvider {
+	return func() *conf {
+		return &conf{
+			disableTransactionScheduling: dynamic.NewBoolConfig(source, DisableTransactionSchedulingConfigEnvName, defaultDisableTransactionScheduling),
+			disableTransactionSubmission: dynamic.NewBoolConfig(source, DisableTransactionSubmissionConfigEnvName, defaultDisableTransactionSubmission),
+			maxGlobalFailedFulfillments:  dynamic.NewUint64Config(source, MaxGlobalFailedFulfillmentsConfigEnvName, defaultMaxGlobalFailedFulfillments),
+			maxFailedFulfillmentRetries:  dynamic.NewUint64Config(source, MaxFailedFulfillmentRetriesConfigEnvName, defaultMaxFailedFulfillmentRetries),
+			//fulfillmentBatchSize:          dynamic.NewUint64Config(source, FulfillmentBatchSizeConfigEnvName, defaultFulfillmentBatchSize),
+			enableSubsidizerChecks:        dynamic.NewBoolConfig(source, EnableSubsidizerChecksConfigEnvName, defaultEnableSubsidizerChecks),
+			enableCachedTransactionLookup: wrapper.NewBoolConfig(memory.NewConfig(false), false),
+			priorityFeePolicy:             dynamic.NewStringConfig(source, PriorityFeePolicyConfigEnvName, defaultPriorityFeePolicy),
+			staticPriorityFee:             dynamic.NewUint64Config(source, StaticPriorityFeeConfigEnvName, defaultStaticPriorityFee),
+			recentPriorityFeePercentile:   dynamic.NewFloat64Config(source, RecentPriorityFeePercentileConfigEnvName, defaultRecentPriorityFeePercentile),
+			minPriorityFee:                dynamic.NewUint64Config(source, MinPriorityFeeConfigEnvName, defaultMinPriorityFee),
+			maxPriorityFee:                dynamic.NewUint64Config(source, MaxPriorityFeeConfigEnvName, defaultMaxPriorityFee),
+			priorityFeeOverrides:          dynamic.NewStringConfig(source, PriorityFeeOverridesConfigEnvName, defaultPriorityFeeOverrides),
+			computeUnitLimitOverrides:     dynamic.NewStringConfig(source, ComputeUnitLimitOverridesConfigEnvName, defaultComputeUnitLimitOverrides),
 		}
 	}
 }
@@ -55,17 +120,30 @@ func WithEnvConfigs() ConfigProvider {
 type testOverrides struct {
 	disableTransactionScheduling bool
 	maxGlobalFailedFulfillments  uint64
+	maxFailedFulfillmentRetries  uint64
+
+	// Used by tests that run against a simulated blockchain
+	enableTransactionSubmission       bool
+	enableBlockchainTransactionLookup bool
 }
 
 func withManualTestOverrides(overrides *testOverrides) ConfigProvider {
 	return func() *conf {
 		return &conf{
 			disableTransactionScheduling: wrapper.NewBoolConfig(memory.NewConfig(overrides.disableTransactionScheduling), defaultDisableTransactionScheduling),
-			disableTransactionSubmission: wrapper.NewBoolConfig(memory.NewConfig(true), defaultDisableTransactionSubmission),
+			disableTransactionSubmission: wrapper.NewBoolConfig(memory.NewConfig(!overrides.enableTransactionSubmission), defaultDisableTransactionSubmission),
 			maxGlobalFailedFulfillments:  wrapper.NewUint64Config(memory.NewConfig(overrides.maxGlobalFailedFulfillments), defaultMaxGlobalFailedFulfillments),
+			maxFailedFulfillmentRetries:  wrapper.NewUint64Config(memory.NewConfig(overrides.maxFailedFulfillmentRetries), defaultMaxFailedFulfillmentRetries),
 			//fulfillmentBatchSize:          wrapper.NewUint64Config(memory.NewConfig(defaultFulfillmentBatchSize), defaultFulfillmentBatchSize),
 			enableSubsidizerChecks:        wrapper.NewBoolConfig(memory.NewConfig(false), defaultEnableSubsidizerChecks),
-			enableCachedTransactionLookup: wrapper.NewBoolConfig(memory.NewConfig(true), true),
+			enableCachedTransactionLookup: wrapper.NewBoolConfig(memory.NewConfig(!overrides.enableBlockchainTransactionLookup), true),
+			priorityFeePolicy:             wrapper.NewStringConfig(memory.NewConfig(defaultPriorityFeePolicy), defaultPriorityFeePolicy),
+			staticPriorityFee:             wrapper.NewUint64Config(memory.NewConfig(uint64(defaultStaticPriorityFee)), defaultStaticPriorityFee),
+			recentPriorityFeePercentile:   wrapper.NewFloat64Config(memory.NewConfig(defaultRecentPriorityFeePercentile), defaultRecentPriorityFeePercentile),
+			minPriorityFee:                wrapper.NewUint64Config(memory.NewConfig(uint64(defaultMinPriorityFee)), defaultMinPriorityFee),
+			maxPriorityFee:                wrapper.NewUint64Config(memory.NewConfig(uint64(defaultMaxPriorityFee)), defaultMaxPriorityFee),
+			priorityFeeOverrides:          wrapper.NewStringConfig(memory.NewConfig(defaultPriorityFeeOverrides), defaultPriorityFeeOverrides),
+			computeUnitLimitOverrides:     wrapper.NewStringConfig(memory.NewConfig(defaultComputeUnitLimitOverrides), defaultComputeUnitLimitOverrides),
 		}
 	}
 }
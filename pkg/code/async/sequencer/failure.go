@@ -0,0 +1,334 @@
+package async_sequencer
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/mr-tron/base58"
+	"github.com/sirupsen/logrus"
+
+	"github.com/code-payments/code-server/pkg/solana"
+	splitter_token "github.com/code-payments/code-server/pkg/solana/splitter"
+	"github.com/code-payments/code-server/pkg/solana/system"
+	timelock_token "github.com/code-payments/code-server/pkg/solana/timelock/v1"
+	"github.com/code-payments/code-server/pkg/solana/token"
+	code_data "github.com/code-payments/code-server/pkg/code/data"
+	"github.com/code-payments/code-server/pkg/code/data/action"
+	"github.com/code-payments/code-server/pkg/code/data/deadletter"
+	"github.com/code-payments/code-server/pkg/code/data/fulfillment"
+	"github.com/code-payments/code-server/pkg/code/data/intent"
+	"github.com/code-payments/code-server/pkg/code/data/transaction"
+)
+
+// The number of slots after a failure was observed before a transaction that
+// can't be found at finalized commitment is considered dropped. This exceeds the
+// maximum age of a recent blockhash, so the transaction can't land afterwards
+// unless it uses a nonce that was never advanced.
+const failedTransactionFinalizationWindow = 300
+
+// Indicates a failed transaction can't be classified until the finalization
+// window has elapsed
+var errFailureNotFinalized = errors.New("failure not finalized")
+
+// remediateFailedFulfillment classifies why a fulfillment's transaction failed.
+// Failures that are safe to retry are scheduled again with a new transaction
+// and nonce, and everything else is quarantined for a human to resolve.
+// Quarantined fulfillments remain in the failed state, and retried ones have a
+// dead letter in the retried state, so both continue to count towards the global
+// circuit breaker.
+func (p *service) remediateFailedFulfillment(ctx context.Context, fulfillmentRecord *fulfillment.Record) error {
+	log := p.log.WithFields(logrus.Fields{
+		"method":      "remediateFailedFulfillment",
+		"fulfillment": fulfillmentRecord.Id,
+		"intent":      fulfillmentRecord.Intent,
+		"type":        fulfillmentRecord.FulfillmentType.String(),
+	})
+
+	if fulfillmentRecord.Signature == nil {
+		return nil
+	}
+
+	deadLetterRecord, err := p.data.GetFulfillmentDeadLetter(ctx, fulfillmentRecord.Id)
+	switch err {
+	case nil:
+		// This failure was already remediated
+		if deadLetterRecord.Signature == *fulfillmentRecord.Signature {
+			return nil
+		}
+	case deadletter.ErrDeadLetterNotFound:
+		deadLetterRecord = &deadletter.Record{
+			FulfillmentId:   fulfillmentRecord.Id,
+			Intent:          fulfillmentRecord.Intent,
+			FulfillmentType: fulfillmentRecord.FulfillmentType,
+		}
+	default:
+		return err
+	}
+
+	category, reason, err := p.classifyFailedFulfillment(ctx, fulfillmentRecord)
+	if err == errFailureNotFinalized {
+		log.Debug("failed transaction isn't finalized")
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	deadLetterRecord.Signature = *fulfillmentRecord.Signature
+	deadLetterRecord.Category = category
+	deadLetterRecord.Reason = reason
+
+	log = log.WithFields(logrus.Fields{
+		"signature": deadLetterRecord.Signature,
+		"category":  category.String(),
+		"reason":    reason,
+	})
+
+	if p.canRetryFailedFulfillment(ctx, fulfillmentRecord, deadLetterRecord) {
+		deadLetterRecord.Retries++
+		deadLetterRecord.State = deadletter.StateRetried
+		err = RetryFailedFulfillment(ctx, p.data, fulfillmentRecord, deadLetterRecord)
+	} else {
+		deadLetterRecord.State = deadletter.StateQuarantined
+		err = p.data.SaveFulfillmentDeadLetter(ctx, deadLetterRecord)
+	}
+	if err != nil {
+		return err
+	}
+
+	log.WithField("retries", deadLetterRecord.Retries).Infof("failed fulfillment %s", deadLetterRecord.State.String())
+	recordFailedFulfillmentRemediatedEvent(ctx, deadLetterRecord)
+
+	return nil
+}
+
+func (p *service) canRetryFailedFulfillment(ctx context.Context, fulfillmentRecord *fulfillment.Record, deadLetterRecord *deadletter.Record) bool {
+	if !supportsFulfillmentRetry(p.fulfillmentHandlersByType, fulfillmentRecord.FulfillmentType) {
+		return false
+	}
+
+	switch deadLetterRecord.Category {
+	case deadletter.CategoryBlockhashOrNonce, deadletter.CategoryTransient:
+	default:
+		return false
+	}
+
+	return uint64(deadLetterRecord.Retries) < p.conf.maxFailedFulfillmentRetries.Get(ctx)
+}
+
+// SupportsFulfillmentRetry returns whether failed fulfillments of the provided
+// type can be retried with a new transaction
+func SupportsFulfillmentRetry(data code_data.Provider, fulfillmentType fulfillment.Type) bool {
+	return supportsFulfillmentRetry(getFulfillmentHandlers(data, WithEnvConfigs()), fulfillmentType)
+}
+
+func supportsFulfillmentRetry(handlersByType map[fulfillment.Type]FulfillmentHandler, fulfillmentType fulfillment.Type) bool {
+	// A new transaction can only be made when the server creates it on demand
+	handler, ok := handlersByType[fulfillmentType]
+	return ok && handler.SupportsOnDemandTransactions()
+}
+
+// RetryFailedFulfillment reverts the fulfillment, and its action and intent, back
+// to the pending state. Without a transaction, the sequencer will make a new one
+// on demand using a fresh nonce. The dead letter record, when provided, is saved
+// alongside the change.
+//
+// Callers are responsible for ensuring the fulfillment type supports retries.
+func RetryFailedFulfillment(ctx context.Context, data code_data.Provider, fulfillmentRecord *fulfillment.Record, deadLetterRecord *deadletter.Record) error {
+	if fulfillmentRecord.State != fulfillment.StateFailed {
+		return ErrInvalidFulfillmentStateTransition
+	}
+
+	return data.ExecuteInTx(ctx, sql.LevelDefault, func(ctx context.Context) error {
+		actionRecord, err := data.GetActionById(ctx, fulfillmentRecord.Intent, fulfillmentRecord.ActionId)
+		if err != nil {
+			return err
+		}
+
+		if actionRecord.State == action.StateFailed {
+			actionRecord.State = action.StatePending
+			err = data.UpdateAction(ctx, actionRecord)
+			if err != nil {
+				return err
+			}
+		}
+
+		intentRecord, err := data.GetIntent(ctx, fulfillmentRecord.Intent)
+		if err != nil {
+			return err
+		}
+
+		if intentRecord.State == intent.StateFailed {
+			intentRecord.State = intent.StatePending
+			err = data.SaveIntent(ctx, intentRecord)
+			if err != nil {
+				return err
+			}
+		}
+
+		if deadLetterRecord != nil {
+			err = data.SaveFulfillmentDeadLetter(ctx, deadLetterRecord)
+			if err != nil {
+				return err
+			}
+		}
+
+		fulfillmentRecord.Signature = nil
+		fulfillmentRecord.Nonce = nil
+		fulfillmentRecord.Blockhash = nil
+		fulfillmentRecord.Data = nil
+		fulfillmentRecord.State = fulfillment.StatePending
+		return data.UpdateFulfillment(ctx, fulfillmentRecord)
+	})
+}
+
+// classifyFailedFulfillment gets the error for the fulfillment's transaction
+// from the blockchain and classifies it
+func (p *service) classifyFailedFulfillment(ctx context.Context, fulfillmentRecord *fulfillment.Record) (deadletter.Category, string, error) {
+	confirmedTxn, err := p.data.GetBlockchainTransaction(ctx, *fulfillmentRecord.Signature, solana.CommitmentFinalized)
+	if err == solana.ErrSignatureNotFound {
+		return p.classifyDroppedFulfillment(ctx, fulfillmentRecord)
+	} else if err != nil {
+		return deadletter.CategoryUnknown, "", err
+	}
+
+	if confirmedTxn.Err == nil {
+		return deadletter.CategoryUnknown, "transaction has no error on the blockchain", nil
+	}
+
+	category := classifyTransactionError(&confirmedTxn.Transaction, confirmedTxn.Err, fulfillmentRecord.Nonce != nil)
+	return category, confirmedTxn.Err.Error(), nil
+}
+
+// classifyDroppedFulfillment classifies a failed fulfillment whose transaction
+// can't be found at finalized commitment. The transaction is retryable once
+// it's been missing for the finalization window and its nonce, if any, has been
+// advanced, since it can never land on the blockchain.
+func (p *service) classifyDroppedFulfillment(ctx context.Context, fulfillmentRecord *fulfillment.Record) (deadletter.Category, string, error) {
+	// The slot of the observed failure is required to know when the window
+	// has elapsed
+	txnRecord, err := p.data.GetTransaction(ctx, *fulfillmentRecord.Signature)
+	if err == transaction.ErrNotFound || (err == nil && txnRecord.Slot == 0) {
+		return deadletter.CategoryUnknown, "transaction not found on the blockchain", nil
+	} else if err != nil {
+		return deadletter.CategoryUnknown, "", err
+	}
+
+	finalizedSlot, err := p.data.GetBlockchainSlot(ctx, solana.CommitmentFinalized)
+	if err != nil {
+		return deadletter.CategoryUnknown, "", err
+	}
+
+	if finalizedSlot < txnRecord.Slot+failedTransactionFinalizationWindow {
+		return deadletter.CategoryUnknown, "", errFailureNotFinalized
+	}
+
+	if fulfillmentRecord.Nonce != nil {
+		accountInfo, err := p.data.GetBlockchainAccountInfo(ctx, *fulfillmentRecord.Nonce, solana.CommitmentFinalized)
+		if err == solana.ErrNoAccountInfo {
+			return deadletter.CategoryUnknown, "transaction not found on the blockchain, and its nonce account doesn't exist", nil
+		} else if err != nil {
+			return deadletter.CategoryUnknown, "", err
+		}
+
+		nonceValue, err := system.GetNonceValueFromAccount(*accountInfo)
+		if err != nil {
+			return deadletter.CategoryUnknown, "", err
+		}
+
+		if fulfillmentRecord.Blockhash == nil || base58.Encode(nonceValue[:]) == *fulfillmentRecord.Blockhash {
+			return deadletter.CategoryUnknown, "transaction not found on the blockchain, and its nonce wasn't advanced", nil
+		}
+	}
+
+	return deadletter.CategoryBlockhashOrNonce, "transaction not found on the blockchain after the finalization window", nil
+}
+
+// getGlobalFailedFulfillmentCount gets the number of failures counted by the
+// global circuit breaker. Failed fulfillments are counted along with those that
+// were retried, which are only excluded once an operator resets them. A retried
+// fulfillment that failed again may briefly be counted twice until it's
+// remediated.
+func getGlobalFailedFulfillmentCount(ctx context.Context, data code_data.Provider) (uint64, error) {
+	count, err := data.GetFulfillmentCountByState(ctx, fulfillment.StateFailed)
+	if err != nil {
+		return 0, err
+	}
+
+	retriedByCategory, err := data.GetFulfillmentDeadLetterCountByStateGroupedByCategory(ctx, deadletter.StateRetried)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, retried := range retriedByCategory {
+		count += retried
+	}
+	return count, nil
+}
+
+func classifyTransactionError(txn *solana.Transaction, txnErr *solana.TransactionError, isNonced bool) deadletter.Category {
+	switch txnErr.ErrorKey() {
+	case solana.TransactionErrorBlockhashNotFound:
+		return deadletter.CategoryBlockhashOrNonce
+	case solana.TransactionErrorAccountInUse,
+		solana.TransactionErrorClusterMaintenance,
+		solana.TransactionErrorWouldExceedMaxBlockCostLimit:
+		return deadletter.CategoryTransient
+	case solana.TransactionErrorInsufficientFundsForFee:
+		return deadletter.CategoryInsufficientFunds
+	case solana.TransactionErrorInstructionError:
+		instructionErr := txnErr.InstructionError()
+		if instructionErr == nil {
+			return deadletter.CategoryProgramError
+		}
+
+		// Nonced transactions always advance the nonce in the first instruction
+		if isNonced && instructionErr.Index == 0 {
+			return deadletter.CategoryBlockhashOrNonce
+		}
+
+		if instructionErr.ErrorKey() == solana.InstructionErrorInsufficientFunds {
+			return deadletter.CategoryInsufficientFunds
+		}
+
+		customErr := instructionErr.CustomError()
+		if customErr != nil && isInsufficientFundsError(getInstructionProgram(txn, instructionErr.Index), *customErr) {
+			return deadletter.CategoryInsufficientFunds
+		}
+
+		return deadletter.CategoryProgramError
+	}
+
+	return deadletter.CategoryUnknown
+}
+
+// isInsufficientFundsError determines whether a custom program error indicates
+// insufficient funds for the programs used by fulfillments
+func isInsufficientFundsError(program []byte, customErr solana.CustomError) bool {
+	switch {
+	case program == nil:
+		return false
+	case bytes.Equal(program, token.ProgramKey):
+		return customErr == token.ErrorInsufficientFunds
+	case bytes.Equal(program, timelock_token.PROGRAM_ID):
+		return customErr == solana.CustomError(timelock_token.ErrInsufficientVaultBalance)
+	case bytes.Equal(program, splitter_token.PROGRAM_ID):
+		return customErr == solana.CustomError(splitter_token.ErrInsufficientVaultBalance)
+	}
+	return false
+}
+
+func getInstructionProgram(txn *solana.Transaction, index int) []byte {
+	if index < 0 || index >= len(txn.Message.Instructions) {
+		return nil
+	}
+
+	// Programs can't be loaded from address lookup tables, so they're always
+	// in the static set of accounts
+	programIndex := int(txn.Message.Instructions[index].ProgramIndex)
+	if programIndex >= len(txn.Message.Accounts) {
+		return nil
+	}
+	return txn.Message.Accounts[programIndex]
+}
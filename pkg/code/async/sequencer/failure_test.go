@@ -0,0 +1,69 @@
+package async_sequencer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/code-payments/code-server/pkg/solana"
+	"github.com/code-payments/code-server/pkg/solana/memo"
+	splitter_token "github.com/code-payments/code-server/pkg/solana/splitter"
+	"github.com/code-payments/code-server/pkg/solana/system"
+	timelock_token "github.com/code-payments/code-server/pkg/solana/timelock/v1"
+	"github.com/code-payments/code-server/pkg/solana/token"
+	"github.com/code-payments/code-server/pkg/testutil"
+	"github.com/code-payments/code-server/pkg/code/data/deadletter"
+)
+
+func TestClassifyTransactionError(t *testing.T) {
+	payer := testutil.NewRandomAccount(t)
+
+	programs := [][]byte{
+		system.SystemAccount,
+		token.ProgramKey,
+		timelock_token.PROGRAM_ID,
+		splitter_token.PROGRAM_ID,
+	}
+
+	var instructions []solana.Instruction
+	for _, program := range programs {
+		instructions = append(instructions, solana.NewInstruction(program, []byte{}))
+	}
+	instructions = append(instructions, memo.Instruction("memo"))
+	txn := solana.NewTransaction(payer.PublicKey().ToBytes(), instructions...)
+
+	instructionError := func(index int, err error) *solana.TransactionError {
+		txnErr, err := solana.TransactionErrorFromInstructionError(&solana.InstructionError{Index: index, Err: err})
+		require.NoError(t, err)
+		return txnErr
+	}
+
+	for _, tc := range []struct {
+		txnErr   *solana.TransactionError
+		isNonced bool
+		expected deadletter.Category
+	}{
+		{solana.NewTransactionError(solana.TransactionErrorBlockhashNotFound), false, deadletter.CategoryBlockhashOrNonce},
+		{solana.NewTransactionError(solana.TransactionErrorAccountInUse), false, deadletter.CategoryTransient},
+		{solana.NewTransactionError(solana.TransactionErrorClusterMaintenance), false, deadletter.CategoryTransient},
+		{solana.NewTransactionError(solana.TransactionErrorWouldExceedMaxBlockCostLimit), false, deadletter.CategoryTransient},
+		{solana.NewTransactionError(solana.TransactionErrorInsufficientFundsForFee), false, deadletter.CategoryInsufficientFunds},
+		{solana.NewTransactionError(solana.TransactionErrorSignatureFailure), false, deadletter.CategoryUnknown},
+
+		{instructionError(0, solana.CustomError(7)), true, deadletter.CategoryBlockhashOrNonce},
+		{instructionError(0, solana.CustomError(7)), false, deadletter.CategoryProgramError},
+		{instructionError(0, errors.New(string(solana.InstructionErrorInsufficientFunds))), false, deadletter.CategoryInsufficientFunds},
+		{instructionError(1, token.ErrorInsufficientFunds), true, deadletter.CategoryInsufficientFunds},
+		{instructionError(1, token.ErrorOwnerMismatch), true, deadletter.CategoryProgramError},
+		{instructionError(2, solana.CustomError(timelock_token.ErrInsufficientVaultBalance)), true, deadletter.CategoryInsufficientFunds},
+		{instructionError(2, solana.CustomError(timelock_token.ErrInvalidVaultAccount)), true, deadletter.CategoryProgramError},
+		{instructionError(3, solana.CustomError(splitter_token.ErrInsufficientVaultBalance)), true, deadletter.CategoryInsufficientFunds},
+		{instructionError(3, solana.CustomError(splitter_token.ErrInvalidPoolState)), true, deadletter.CategoryProgramError},
+		{instructionError(4, token.ErrorInsufficientFunds), true, deadletter.CategoryProgramError},
+		{instructionError(10, token.ErrorInsufficientFunds), true, deadletter.CategoryProgramError},
+	} {
+		assert.Equal(t, tc.expected, classifyTransactionError(&txn, tc.txnErr, tc.isNonced), tc.txnErr.Error())
+	}
+}
@@ -13,12 +13,14 @@ import (
 	commitment_worker "github.com/code-payments/code-server/pkg/code/async/commitment"
 	"github.com/code-payments/code-server/pkg/code/common"
 	code_data "github.com/code-payments/code-server/pkg/code/data"
+	"github.com/code-payments/code-server/pkg/code/data/account"
 	"github.com/code-payments/code-server/pkg/code/data/commitment"
 	"github.com/code-payments/code-server/pkg/code/data/fulfillment"
 	"github.com/code-payments/code-server/pkg/code/data/timelock"
 	"github.com/code-payments/code-server/pkg/code/data/transaction"
 	"github.com/code-payments/code-server/pkg/code/data/treasury"
 	transaction_util "github.com/code-payments/code-server/pkg/code/transaction"
+	"github.com/code-payments/code-server/pkg/mint"
 	"github.com/code-payments/code-server/pkg/solana"
 	timelock_token "github.com/code-payments/code-server/pkg/solana/timelock/v1"
 	"github.com/code-payments/code-server/pkg/solana/token"
@@ -75,12 +77,14 @@ type FulfillmentHandler interface {
 }
 
 type InitializeLockedTimelockAccountFulfillmentHandler struct {
-	data code_data.Provider
+	data              code_data.Provider
+	priorityFeePolicy PriorityFeePolicy
 }
 
-func NewInitializeLockedTimelockAccountFulfillmentHandler(data code_data.Provider) FulfillmentHandler {
+func NewInitializeLockedTimelockAccountFulfillmentHandler(data code_data.Provider, priorityFeePolicy PriorityFeePolicy) FulfillmentHandler {
 	return &InitializeLockedTimelockAccountFulfillmentHandler{
-		data: data,
+		data:              data,
+		priorityFeePolicy: priorityFeePolicy,
 	}
 }
 
@@ -90,7 +94,23 @@ func (h *InitializeLockedTimelockAccountFulfillmentHandler) CanSubmitToBlockchai
 	}
 
 	accountInfoRecord, err := h.data.GetAccountInfoByTokenAddress(ctx, fulfillmentRecord.Source)
-	if err != nil {
+	if err == account.ErrAccountInfoNotFound {
+		// Account info is only tracked for Kin accounts. Accounts for any other
+		// mint are only ever opened as the owner's primary account for that mint,
+		// so they're scheduled immediately like Kin primary accounts.
+		timelockRecord, err := h.data.GetTimelockByVault(ctx, fulfillmentRecord.Source)
+		if err != nil {
+			return false, err
+		}
+
+		tokenMint, err := mint.Get(timelockRecord.Mint)
+		if err != nil {
+			return false, err
+		} else if tokenMint.IsKin() {
+			return false, errors.New("account info not found for kin account")
+		}
+		return true, nil
+	} else if err != nil {
 		return false, err
 	}
 
@@ -138,23 +158,36 @@ func (h *InitializeLockedTimelockAccountFulfillmentHandler) MakeOnDemandTransact
 		return nil, err
 	}
 
-	authorityAccount, err := common.NewAccountFromPublicKeyString(timelockRecord.VaultOwner)
+	if timelockRecord.DataVersion != timelock_token.DataVersion1 {
+		return nil, errors.New("timelock account must use data version 1")
+	}
+
+	timelockAccounts, err := common.GetTimelockAccountsForRecord(timelockRecord)
 	if err != nil {
 		return nil, err
 	}
 
-	// todo: a single function utility in the common package to do exactly how we're getting timelockAccounts
-	timelockAccounts, err := authorityAccount.GetTimelockAccounts(timelock_token.DataVersion1)
+	computeBudget, err := h.priorityFeePolicy.GetComputeBudget(
+		ctx,
+		fulfillmentRecord.FulfillmentType,
+		[]string{
+			selectedNonce.Account.PublicKey().ToBase58(),
+			timelockAccounts.State.PublicKey().ToBase58(),
+			timelockAccounts.Vault.PublicKey().ToBase58(),
+		},
+	)
 	if err != nil {
 		return nil, err
 	}
 
-	txn, err := transaction_util.MakeOpenAccountTransaction(selectedNonce.Account, selectedNonce.Blockhash, timelockAccounts)
+	txn, err := transaction_util.MakeOpenAccountTransaction(selectedNonce.Account, selectedNonce.Blockhash, timelockAccounts, computeBudget)
 	if err != nil {
 		return nil, err
 	}
 
-	err = txn.Sign(common.GetSubsidizer().PrivateKey().ToBytes())
+	// Accounts created before a subsidizer rotation require the retired subsidizer
+	// as the time authority
+	err = common.SignWithSubsidizers(&txn)
 	if err != nil {
 		return nil, err
 	}
@@ -607,12 +640,14 @@ func (h *PermanentPrivacyTransferWithAuthorityFulfillmentHandler) IsRevoked(ctx
 }
 
 type TransferWithCommitmentFulfillmentHandler struct {
-	data code_data.Provider
+	data              code_data.Provider
+	priorityFeePolicy PriorityFeePolicy
 }
 
-func NewTransferWithCommitmentFulfillmentHandler(data code_data.Provider) FulfillmentHandler {
+func NewTransferWithCommitmentFulfillmentHandler(data code_data.Provider, priorityFeePolicy PriorityFeePolicy) FulfillmentHandler {
 	return &TransferWithCommitmentFulfillmentHandler{
-		data: data,
+		data:              data,
+		priorityFeePolicy: priorityFeePolicy,
 	}
 }
 
@@ -725,6 +760,20 @@ func (h *TransferWithCommitmentFulfillmentHandler) MakeOnDemandTransaction(ctx c
 		return nil, err
 	}
 
+	computeBudget, err := h.priorityFeePolicy.GetComputeBudget(
+		ctx,
+		fulfillmentRecord.FulfillmentType,
+		[]string{
+			selectedNonce.Account.PublicKey().ToBase58(),
+			treasuryPoolVault.PublicKey().ToBase58(),
+			destination.PublicKey().ToBase58(),
+			commitment.PublicKey().ToBase58(),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	txn, err := transaction_util.MakeTreasuryAdvanceTransaction(
 		selectedNonce.Account,
 		selectedNonce.Blockhash,
@@ -737,6 +786,8 @@ func (h *TransferWithCommitmentFulfillmentHandler) MakeOnDemandTransaction(ctx c
 		commitmentRecord.Amount,
 		transcript,
 		recentRoot,
+
+		computeBudget,
 	)
 	if err != nil {
 		return nil, err
@@ -1416,6 +1467,60 @@ func (h *CloseCommitmentVaultFulfillmentHandler) IsRevoked(ctx context.Context,
 	return false, false, nil
 }
 
+type TransferToTreasuryPoolFulfillmentHandler struct {
+	data code_data.Provider
+}
+
+func NewTransferToTreasuryPoolFulfillmentHandler(data code_data.Provider) FulfillmentHandler {
+	return &TransferToTreasuryPoolFulfillmentHandler{
+		data: data,
+	}
+}
+
+// Funding comes from an external hot wallet, so there are no dependencies on
+// other fulfillments. The treasury worker is responsible for limiting funding.
+func (h *TransferToTreasuryPoolFulfillmentHandler) CanSubmitToBlockchain(ctx context.Context, fulfillmentRecord *fulfillment.Record) (scheduled bool, err error) {
+	if fulfillmentRecord.FulfillmentType != fulfillment.TransferToTreasuryPool {
+		return false, errors.New("invalid fulfillment type")
+	}
+
+	return true, nil
+}
+
+func (h *TransferToTreasuryPoolFulfillmentHandler) SupportsOnDemandTransactions() bool {
+	return false
+}
+
+func (h *TransferToTreasuryPoolFulfillmentHandler) MakeOnDemandTransaction(ctx context.Context, fulfillmentRecord *fulfillment.Record, selectedNonce *transaction_util.SelectedNonce) (*solana.Transaction, error) {
+	return nil, errors.New("not supported")
+}
+
+func (h *TransferToTreasuryPoolFulfillmentHandler) OnSuccess(ctx context.Context, fulfillmentRecord *fulfillment.Record, txnRecord *transaction.Record) error {
+	if fulfillmentRecord.FulfillmentType != fulfillment.TransferToTreasuryPool {
+		return errors.New("invalid fulfillment type")
+	}
+
+	return markTreasuryPoolFundingState(ctx, h.data, fulfillmentRecord, treasury.FundingStateConfirmed)
+}
+
+func (h *TransferToTreasuryPoolFulfillmentHandler) OnFailure(ctx context.Context, fulfillmentRecord *fulfillment.Record, txnRecord *transaction.Record) (recovered bool, err error) {
+	if fulfillmentRecord.FulfillmentType != fulfillment.TransferToTreasuryPool {
+		return false, errors.New("invalid fulfillment type")
+	}
+
+	// Don't attempt recovery. Marking the funding as failed allows the treasury
+	// worker to try again with a new intent, subject to its daily cap.
+	return false, markTreasuryPoolFundingState(ctx, h.data, fulfillmentRecord, treasury.FundingStateFailed)
+}
+
+func (h *TransferToTreasuryPoolFulfillmentHandler) IsRevoked(ctx context.Context, fulfillmentRecord *fulfillment.Record) (revoked bool, nonceUsed bool, err error) {
+	if fulfillmentRecord.FulfillmentType != fulfillment.TransferToTreasuryPool {
+		return false, false, errors.New("invalid fulfillment type")
+	}
+
+	return false, false, nil
+}
+
 func isTokenAccountOnBlockchain(ctx context.Context, data code_data.Provider, address string) (bool, error) {
 	// Optimization for external accounts managed by Code
 	switch address {
@@ -1498,14 +1603,33 @@ func estimateTreasuryPoolFundingLevels(ctx context.Context, data code_data.Provi
 	return total, used, nil
 }
 
+func markTreasuryPoolFundingState(ctx context.Context, data code_data.Provider, fulfillmentRecord *fulfillment.Record, state treasury.FundingState) error {
+	actionRecord, err := data.GetActionById(ctx, fulfillmentRecord.Intent, fulfillmentRecord.ActionId)
+	if err != nil {
+		return err
+	}
+
+	// The funding record is keyed by transaction, so this only updates the state
+	// of the record created alongside the intent
+	return data.SaveTreasuryPoolFunding(ctx, &treasury.FundingHistoryRecord{
+		Vault:         *fulfillmentRecord.Destination,
+		DeltaQuarks:   int64(*actionRecord.Quantity),
+		TransactionId: *fulfillmentRecord.Signature,
+		State:         state,
+		CreatedAt:     fulfillmentRecord.CreatedAt,
+	})
+}
+
 func getFulfillmentHandlers(data code_data.Provider, configProvider ConfigProvider) map[fulfillment.Type]FulfillmentHandler {
+	priorityFeePolicy := newConfiguredPriorityFeePolicy(data, configProvider)
+
 	handlersByType := make(map[fulfillment.Type]FulfillmentHandler)
-	handlersByType[fulfillment.InitializeLockedTimelockAccount] = NewInitializeLockedTimelockAccountFulfillmentHandler(data)
+	handlersByType[fulfillment.InitializeLockedTimelockAccount] = NewInitializeLockedTimelockAccountFulfillmentHandler(data, priorityFeePolicy)
 	handlersByType[fulfillment.NoPrivacyTransferWithAuthority] = NewNoPrivacyTransferWithAuthorityFulfillmentHandler(data)
 	handlersByType[fulfillment.NoPrivacyWithdraw] = NewNoPrivacyWithdrawFulfillmentHandler(data)
 	handlersByType[fulfillment.TemporaryPrivacyTransferWithAuthority] = NewTemporaryPrivacyTransferWithAuthorityFulfillmentHandler(data, configProvider)
 	handlersByType[fulfillment.PermanentPrivacyTransferWithAuthority] = NewPermanentPrivacyTransferWithAuthorityFulfillmentHandler(data, configProvider)
-	handlersByType[fulfillment.TransferWithCommitment] = NewTransferWithCommitmentFulfillmentHandler(data)
+	handlersByType[fulfillment.TransferWithCommitment] = NewTransferWithCommitmentFulfillmentHandler(data, priorityFeePolicy)
 	handlersByType[fulfillment.CloseEmptyTimelockAccount] = NewCloseEmptyTimelockAccountFulfillmentHandler(data)
 	handlersByType[fulfillment.CloseDormantTimelockAccount] = NewCloseDormantTimelockAccountFulfillmentHandler(data)
 	handlersByType[fulfillment.SaveRecentRoot] = NewSaveRecentRootFulfillmentHandler(data)
@@ -1514,5 +1638,6 @@ func getFulfillmentHandlers(data code_data.Provider, configProvider ConfigProvid
 	handlersByType[fulfillment.VerifyCommitmentProof] = NewVerifyCommitmentProofFulfillmentHandler(data)
 	handlersByType[fulfillment.OpenCommitmentVault] = NewOpenCommitmentVaultFulfillmentHandler(data)
 	handlersByType[fulfillment.CloseCommitmentVault] = NewCloseCommitmentVaultFulfillmentHandler(data)
+	handlersByType[fulfillment.TransferToTreasuryPool] = NewTransferToTreasuryPoolFulfillmentHandler(data)
 	return handlersByType
 }
@@ -15,7 +15,9 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	currency_lib "github.com/code-payments/code-server/pkg/currency"
 	"github.com/code-payments/code-server/pkg/kin"
+	"github.com/code-payments/code-server/pkg/mint"
 	"github.com/code-payments/code-server/pkg/pointer"
 	"github.com/code-payments/code-server/pkg/solana"
 	"github.com/code-payments/code-server/pkg/solana/memo"
@@ -25,6 +27,7 @@ import (
 	"github.com/code-payments/code-server/pkg/testutil"
 	"github.com/code-payments/code-server/pkg/code/common"
 	code_data "github.com/code-payments/code-server/pkg/code/data"
+	datatest "github.com/code-payments/code-server/pkg/code/data/test"
 	"github.com/code-payments/code-server/pkg/code/data/action"
 	"github.com/code-payments/code-server/pkg/code/data/commitment"
 	"github.com/code-payments/code-server/pkg/code/data/currency"
@@ -115,7 +118,49 @@ func TestInitializeLockedTimelockAccountFulfillmentHandler_OnDemandTransaction(t
 	require.NoError(t, err)
 	env.assertSignedTransaction(t, txn)
 	env.assertNoncedTransaction(t, txn, selectedNonce)
-	env.assertExpectedInitializeLockedTimelockAccountTransaction(t, txn, authority)
+	env.assertExpectedInitializeLockedTimelockAccountTransaction(t, txn, authority, mint.Kin)
+}
+
+func TestInitializeLockedTimelockAccountFulfillmentHandler_SecondMint(t *testing.T) {
+	env := setupFulfillmentHandlerTestEnv(t)
+	require.NoError(t, mint.Register(mint.Usdc))
+
+	authority := testutil.NewRandomAccount(t)
+	timelockRecord := env.setupTimelockRecordForMint(t, authority, mint.Usdc)
+
+	handler := env.handlersByType[fulfillment.InitializeLockedTimelockAccount]
+
+	fulfillmentRecord := &fulfillment.Record{
+		FulfillmentType: fulfillment.InitializeLockedTimelockAccount,
+		Source:          timelockRecord.VaultAddress,
+	}
+
+	// Accounts for other mints don't have account info, and are opened immediately
+	// like primary accounts
+	scheduled, err := handler.CanSubmitToBlockchain(env.ctx, fulfillmentRecord)
+	require.NoError(t, err)
+	assert.True(t, scheduled)
+
+	env.generateAvailableNonce(t)
+	selectedNonce, err := transaction_util.SelectAvailableNonce(env.ctx, env.data, nonce.PurposeOnDemandTransaction)
+	require.NoError(t, err)
+
+	txn, err := handler.MakeOnDemandTransaction(env.ctx, fulfillmentRecord, selectedNonce)
+	require.NoError(t, err)
+	env.assertSignedTransaction(t, txn)
+	env.assertNoncedTransaction(t, txn, selectedNonce)
+	env.assertExpectedInitializeLockedTimelockAccountTransaction(t, txn, authority, mint.Usdc)
+
+	require.NoError(t, handler.OnSuccess(env.ctx, fulfillmentRecord, &transaction.Record{Slot: 12345}))
+	env.assertTimelockRecordInState(t, fulfillmentRecord.Source, timelock_token_v1.StateLocked, 12345)
+
+	// Kin accounts must still have account info
+	kinTimelockRecord := env.setupTimelockRecord(t, authority)
+	_, err = handler.CanSubmitToBlockchain(env.ctx, &fulfillment.Record{
+		FulfillmentType: fulfillment.InitializeLockedTimelockAccount,
+		Source:          kinTimelockRecord.VaultAddress,
+	})
+	assert.Error(t, err)
 }
 
 func TestCloseEmptyTimelockAccountFulfillmentHandler_OnSuccess(t *testing.T) {
@@ -436,6 +481,24 @@ func TestTemporaryPrivacyTransferWithAuthorityFulfillmentHandler_IsRevoked(t *te
 	assert.True(t, strings.Contains(err.Error(), "too dangerous"))
 }
 
+func TestTemporaryPrivacyTransferWithAuthorityFulfillmentHandler_OnSuccess_SecondMint(t *testing.T) {
+	env := setupFulfillmentHandlerTestEnv(t)
+	require.NoError(t, mint.Register(mint.Usdc))
+
+	fulfillmentRecord := &fulfillment.Record{
+		FulfillmentType: fulfillment.TemporaryPrivacyTransferWithAuthority,
+	}
+	env.setupForPaymentWithMint(t, fulfillmentRecord, mint.Usdc)
+
+	txnRecord, err := env.data.GetTransaction(env.ctx, *fulfillmentRecord.Signature)
+	require.NoError(t, err)
+
+	handler := env.handlersByType[fulfillment.TemporaryPrivacyTransferWithAuthority]
+
+	require.NoError(t, handler.OnSuccess(env.ctx, fulfillmentRecord, txnRecord))
+	env.assertPaymentRecordSaved(t, fulfillmentRecord)
+}
+
 func TestPermanentPrivacyTransferWithAuthorityFulfillmentHandler_OnSuccess(t *testing.T) {
 	env := setupFulfillmentHandlerTestEnv(t)
 
@@ -601,6 +664,43 @@ func TestSaveRecentRootFulfillmentHandler_IsRevoked(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestTransferToTreasuryPoolFulfillmentHandler_OnSuccess(t *testing.T) {
+	env := setupFulfillmentHandlerTestEnv(t)
+
+	fulfillmentRecord, fundingRecord := env.createTreasuryPoolFunding(t)
+
+	handler := env.handlersByType[fulfillment.TransferToTreasuryPool]
+
+	require.NoError(t, handler.OnSuccess(env.ctx, fulfillmentRecord, nil))
+	env.assertTreasuryPoolFundingState(t, fundingRecord, treasury.FundingStateConfirmed)
+}
+
+func TestTransferToTreasuryPoolFulfillmentHandler_OnFailure(t *testing.T) {
+	env := setupFulfillmentHandlerTestEnv(t)
+
+	fulfillmentRecord, fundingRecord := env.createTreasuryPoolFunding(t)
+
+	handler := env.handlersByType[fulfillment.TransferToTreasuryPool]
+
+	recovered, err := handler.OnFailure(env.ctx, fulfillmentRecord, nil)
+	assert.False(t, recovered)
+	require.NoError(t, err)
+	env.assertTreasuryPoolFundingState(t, fundingRecord, treasury.FundingStateFailed)
+}
+
+func TestTransferToTreasuryPoolFulfillmentHandler_IsRevoked(t *testing.T) {
+	env := setupFulfillmentHandlerTestEnv(t)
+
+	fulfillmentRecord, _ := env.createTreasuryPoolFunding(t)
+
+	handler := env.handlersByType[fulfillment.TransferToTreasuryPool]
+
+	revoked, nonceUsed, err := handler.IsRevoked(env.ctx, fulfillmentRecord)
+	assert.False(t, revoked)
+	assert.False(t, nonceUsed)
+	require.NoError(t, err)
+}
+
 func TestInitializeCommitmentProofFulfillmentHandler_OnSuccess(t *testing.T) {
 	env := setupFulfillmentHandlerTestEnv(t)
 
@@ -895,7 +995,7 @@ type fulfillmentHandlerTestEnv struct {
 }
 
 func setupFulfillmentHandlerTestEnv(t *testing.T) *fulfillmentHandlerTestEnv {
-	db := code_data.NewTestDataProvider()
+	db := datatest.NewDataProvider()
 
 	subsidizer := testutil.SetupRandomSubsidizer(t, db)
 
@@ -937,12 +1037,25 @@ func (e *fulfillmentHandlerTestEnv) assertPaymentRecordSaved(t *testing.T, fulfi
 	txnRecord, err := e.data.GetTransaction(e.ctx, *fulfillmentRecord.Signature)
 	require.NoError(t, err)
 
+	intentRecord, err := e.data.GetIntent(e.ctx, fulfillmentRecord.Intent)
+	require.NoError(t, err)
+
+	tokenMint, err := mint.Get(intentRecord.Mint)
+	require.NoError(t, err)
+
+	usdRate := 1.0
+	if tokenMint.IsKin() {
+		usdRate = 0.1
+	}
+
 	assert.Equal(t, *fulfillmentRecord.Signature, paymentRecord.TransactionId)
 	assert.EqualValues(t, transactionIndex, paymentRecord.TransactionIndex)
 	assert.Equal(t, fulfillmentRecord.Intent, paymentRecord.Rendezvous)
 	assert.Equal(t, fulfillmentRecord.Source, paymentRecord.Source)
 	assert.Equal(t, *actionRecord.Quantity, paymentRecord.Quantity)
 	assert.Equal(t, *fulfillmentRecord.Destination, paymentRecord.Destination)
+	assert.Equal(t, tokenMint.Symbol, paymentRecord.ExchangeCurrency)
+	assert.InEpsilon(t, usdRate*tokenMint.FromQuarks(*actionRecord.Quantity), paymentRecord.UsdMarketValue, 0.000001)
 	assert.False(t, paymentRecord.IsExternal)
 	assert.Equal(t, txnRecord.Slot, paymentRecord.BlockId)
 	assert.Equal(t, txnRecord.BlockTime, paymentRecord.BlockTime)
@@ -956,7 +1069,11 @@ func (e *fulfillmentHandlerTestEnv) assertTimelockRecordInState(t *testing.T, va
 }
 
 func (e *fulfillmentHandlerTestEnv) setupTimelockRecord(t *testing.T, owner *common.Account) *timelock.Record {
-	timelockAccounts, err := owner.GetTimelockAccounts(timelock_token_v1.DataVersion1)
+	return e.setupTimelockRecordForMint(t, owner, mint.Kin)
+}
+
+func (e *fulfillmentHandlerTestEnv) setupTimelockRecordForMint(t *testing.T, owner *common.Account, m *mint.Mint) *timelock.Record {
+	timelockAccounts, err := owner.GetTimelockAccountsForMint(timelock_token_v1.DataVersion1, m)
 	require.NoError(t, err)
 	timelockRecord := timelockAccounts.ToDBRecord()
 	require.NoError(t, e.data.SaveTimelock(e.ctx, timelockRecord))
@@ -1000,6 +1117,10 @@ func (e *fulfillmentHandlerTestEnv) setupCommitmentInState(t *testing.T, intentI
 }
 
 func (e *fulfillmentHandlerTestEnv) setupForPayment(t *testing.T, fulfillmentRecord *fulfillment.Record) {
+	e.setupForPaymentWithMint(t, fulfillmentRecord, mint.Kin)
+}
+
+func (e *fulfillmentHandlerTestEnv) setupForPaymentWithMint(t *testing.T, fulfillmentRecord *fulfillment.Record, m *mint.Mint) {
 	if fulfillmentRecord.IntentType == intent.UnknownType {
 		switch fulfillmentRecord.FulfillmentType {
 		case fulfillment.TemporaryPrivacyTransferWithAuthority, fulfillment.PermanentPrivacyTransferWithAuthority, fulfillment.TransferWithCommitment, fulfillment.NoPrivacyWithdraw:
@@ -1044,6 +1165,44 @@ func (e *fulfillmentHandlerTestEnv) setupForPayment(t *testing.T, fulfillmentRec
 	fulfillmentRecord.Data = []byte("data")
 
 	quantity := rand.Uint64()
+
+	intentRecord := &intent.Record{
+		IntentId:   fulfillmentRecord.Intent,
+		IntentType: fulfillmentRecord.IntentType,
+
+		InitiatorOwnerAccount: testutil.NewRandomAccount(t).PublicKey().ToBase58(),
+
+		Mint: m.ToBase58(),
+
+		State: intent.StatePending,
+	}
+	switch fulfillmentRecord.IntentType {
+	case intent.SendPrivatePayment:
+		intentRecord.SendPrivatePaymentMetadata = &intent.SendPrivatePaymentMetadata{
+			DestinationTokenAccount: *fulfillmentRecord.Destination,
+			Quantity:                quantity,
+
+			ExchangeCurrency: currency_lib.Code(m.Symbol),
+			ExchangeRate:     1.0,
+			NativeAmount:     m.FromQuarks(quantity),
+			UsdMarketValue:   1,
+		}
+	case intent.SendPublicPayment:
+		intentRecord.SendPublicPaymentMetadata = &intent.SendPublicPaymentMetadata{
+			DestinationOwnerAccount: testutil.NewRandomAccount(t).PublicKey().ToBase58(),
+			DestinationTokenAccount: *fulfillmentRecord.Destination,
+			Quantity:                quantity,
+
+			ExchangeCurrency: currency_lib.Code(m.Symbol),
+			ExchangeRate:     1.0,
+			NativeAmount:     m.FromQuarks(quantity),
+			UsdMarketValue:   1,
+		}
+	default:
+		require.Fail(t, "unhandled intent type")
+	}
+	require.NoError(t, e.data.SaveIntent(e.ctx, intentRecord))
+
 	actionRecord := &action.Record{
 		Source:      fulfillmentRecord.Source,
 		Destination: fulfillmentRecord.Destination,
@@ -1212,13 +1371,13 @@ func (e *fulfillmentHandlerTestEnv) assertNoncedTransaction(t *testing.T, txn *s
 	assert.EqualValues(t, advanceNonceIxn.Authority, e.subsidizer.PublicKey().ToBytes())
 }
 
-func (e *fulfillmentHandlerTestEnv) assertExpectedInitializeLockedTimelockAccountTransaction(t *testing.T, txn *solana.Transaction, authority *common.Account) {
+func (e *fulfillmentHandlerTestEnv) assertExpectedInitializeLockedTimelockAccountTransaction(t *testing.T, txn *solana.Transaction, authority *common.Account, m *mint.Mint) {
 	require.Len(t, txn.Message.Instructions, 2)
 
 	_, err := system.DecompileAdvanceNonce(txn.Message, 0)
 	require.NoError(t, err)
 
-	timelockAccounts, err := authority.GetTimelockAccounts(timelock_token_v1.DataVersion1)
+	timelockAccounts, err := authority.GetTimelockAccountsForMint(timelock_token_v1.DataVersion1, m)
 	require.NoError(t, err)
 
 	initializeIxnArgs, initializeIxnAccounts, err := timelock_token_v1.InitializeInstructionFromLegacyInstruction(*txn, 1)
@@ -1229,7 +1388,7 @@ func (e *fulfillmentHandlerTestEnv) assertExpectedInitializeLockedTimelockAccoun
 	assert.EqualValues(t, timelockAccounts.State.PublicKey().ToBytes(), initializeIxnAccounts.Timelock)
 	assert.EqualValues(t, timelockAccounts.Vault.PublicKey().ToBytes(), initializeIxnAccounts.Vault)
 	assert.EqualValues(t, timelockAccounts.VaultOwner.PublicKey().ToBytes(), initializeIxnAccounts.VaultOwner)
-	assert.EqualValues(t, kin.TokenMint, initializeIxnAccounts.Mint)
+	assert.EqualValues(t, m.Address, initializeIxnAccounts.Mint)
 	assert.EqualValues(t, e.subsidizer.PublicKey().ToBytes(), initializeIxnAccounts.TimeAuthority)
 	assert.EqualValues(t, e.subsidizer.PublicKey().ToBytes(), initializeIxnAccounts.Payer)
 }
@@ -1268,3 +1427,76 @@ func assertExpectedKreMemoInstruction(t *testing.T, txn *solana.Transaction, ind
 	assert.EqualValues(t, kin.TransactionTypeP2P, kreMemo.TransactionType())
 	assert.EqualValues(t, transaction_util.KreAppIndex, kreMemo.AppIndex())
 }
+
+func (e *fulfillmentHandlerTestEnv) createTreasuryPoolFunding(t *testing.T) (*fulfillment.Record, *treasury.FundingHistoryRecord) {
+	quantity := kin.ToQuarks(1_000_000)
+
+	actionRecord := &action.Record{
+		Intent:     "intent",
+		IntentType: intent.TreasuryPoolFunding,
+
+		ActionId:   0,
+		ActionType: action.TreasuryPoolFunding,
+
+		Source:      "hot-wallet",
+		Destination: pointer.String("treasury-pool-vault"),
+		Quantity:    &quantity,
+
+		State: action.StatePending,
+	}
+	require.NoError(t, e.data.PutAllActions(e.ctx, actionRecord))
+
+	fulfillmentRecord := &fulfillment.Record{
+		Intent:     actionRecord.Intent,
+		IntentType: actionRecord.IntentType,
+
+		ActionId:   actionRecord.ActionId,
+		ActionType: actionRecord.ActionType,
+
+		FulfillmentType: fulfillment.TransferToTreasuryPool,
+		Data:            []byte("data"),
+		Signature:       pointer.String("signature"),
+
+		Nonce:     pointer.String("nonce"),
+		Blockhash: pointer.String("blockhash"),
+
+		Source:      actionRecord.Source,
+		Destination: actionRecord.Destination,
+
+		State: fulfillment.StatePending,
+
+		CreatedAt: time.Now(),
+	}
+
+	fundingRecord := &treasury.FundingHistoryRecord{
+		Vault:         *actionRecord.Destination,
+		DeltaQuarks:   int64(quantity),
+		TransactionId: *fulfillmentRecord.Signature,
+		State:         treasury.FundingStatePending,
+		CreatedAt:     fulfillmentRecord.CreatedAt,
+	}
+	require.NoError(t, e.data.SaveTreasuryPoolFunding(e.ctx, fundingRecord))
+
+	return fulfillmentRecord, fundingRecord
+}
+
+func (e *fulfillmentHandlerTestEnv) assertTreasuryPoolFundingState(t *testing.T, fundingRecord *treasury.FundingHistoryRecord, expected treasury.FundingState) {
+	count, err := e.data.GetTreasuryPoolFundingCountByVaultAndState(e.ctx, fundingRecord.Vault, expected)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+
+	for _, state := range []treasury.FundingState{
+		treasury.FundingStateUnknown,
+		treasury.FundingStatePending,
+		treasury.FundingStateConfirmed,
+		treasury.FundingStateFailed,
+	} {
+		if state == expected {
+			continue
+		}
+
+		count, err := e.data.GetTreasuryPoolFundingCountByVaultAndState(e.ctx, fundingRecord.Vault, state)
+		require.NoError(t, err)
+		assert.EqualValues(t, 0, count)
+	}
+}
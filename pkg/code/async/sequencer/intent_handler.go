@@ -349,6 +349,32 @@ func (h *SaveRecentRootIntentHandler) OnActionUpdated(ctx context.Context, inten
 	return nil
 }
 
+type TreasuryPoolFundingIntentHandler struct {
+	data code_data.Provider
+}
+
+func NewTreasuryPoolFundingIntentHandler(data code_data.Provider) IntentHandler {
+	return &TreasuryPoolFundingIntentHandler{
+		data: data,
+	}
+}
+
+func (h *TreasuryPoolFundingIntentHandler) OnActionUpdated(ctx context.Context, intentId string) error {
+	actionRecord, err := h.data.GetActionById(ctx, intentId, 0)
+	if err != nil {
+		return err
+	}
+
+	// Intent is confirmed/failed based on the state the single action
+	switch actionRecord.State {
+	case action.StateConfirmed:
+		return markIntentConfirmed(ctx, h.data, intentId)
+	case action.StateFailed:
+		return markIntentFailed(ctx, h.data, intentId)
+	}
+	return nil
+}
+
 type MigrateToPrivacy2022IntentHandler struct {
 	data code_data.Provider
 }
@@ -509,5 +535,6 @@ func getIntentHandlers(data code_data.Provider) map[intent.Type]IntentHandler {
 	handlersByType[intent.SendPublicPayment] = NewSendPublicPaymentIntentHandler(data)
 	handlersByType[intent.ReceivePaymentsPublicly] = NewReceivePaymentsPubliclyIntentHandler(data)
 	handlersByType[intent.EstablishRelationship] = NewEstablishRelationshipIntentHandler(data)
+	handlersByType[intent.TreasuryPoolFunding] = NewTreasuryPoolFundingIntentHandler(data)
 	return handlersByType
 }
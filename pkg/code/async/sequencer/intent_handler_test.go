@@ -14,6 +14,7 @@ import (
 	"github.com/code-payments/code-server/pkg/pointer"
 	"github.com/code-payments/code-server/pkg/testutil"
 	code_data "github.com/code-payments/code-server/pkg/code/data"
+	datatest "github.com/code-payments/code-server/pkg/code/data/test"
 	"github.com/code-payments/code-server/pkg/code/data/action"
 	"github.com/code-payments/code-server/pkg/code/data/fulfillment"
 	"github.com/code-payments/code-server/pkg/code/data/intent"
@@ -289,6 +290,34 @@ func TestSaveRecentRootIntentHandler_TransitionToStateFailed(t *testing.T) {
 	env.assertIntentState(t, intentRecord.IntentId, intent.StateFailed)
 }
 
+func TestTreasuryPoolFundingIntentHandler_TransitionToStateConfirmed(t *testing.T) {
+	env := setupIntentHandlerTestEnv(t)
+
+	intentHandler := env.handlersByType[intent.TreasuryPoolFunding]
+	intentRecord := env.createIntent(t, intent.TreasuryPoolFunding)
+
+	require.NoError(t, intentHandler.OnActionUpdated(env.ctx, intentRecord.IntentId))
+	env.assertIntentState(t, intentRecord.IntentId, intent.StatePending)
+
+	env.confirmFirstActionOfType(t, intentRecord.IntentId, action.TreasuryPoolFunding)
+	require.NoError(t, intentHandler.OnActionUpdated(env.ctx, intentRecord.IntentId))
+	env.assertIntentState(t, intentRecord.IntentId, intent.StateConfirmed)
+}
+
+func TestTreasuryPoolFundingIntentHandler_TransitionToStateFailed(t *testing.T) {
+	env := setupIntentHandlerTestEnv(t)
+
+	intentHandler := env.handlersByType[intent.TreasuryPoolFunding]
+	intentRecord := env.createIntent(t, intent.TreasuryPoolFunding)
+
+	require.NoError(t, intentHandler.OnActionUpdated(env.ctx, intentRecord.IntentId))
+	env.assertIntentState(t, intentRecord.IntentId, intent.StatePending)
+
+	env.failFirstActionOfType(t, intentRecord.IntentId, action.TreasuryPoolFunding)
+	require.NoError(t, intentHandler.OnActionUpdated(env.ctx, intentRecord.IntentId))
+	env.assertIntentState(t, intentRecord.IntentId, intent.StateFailed)
+}
+
 func TestMigrateToPrivacy2022IntentHandler_TransitionToStateConfirmed(t *testing.T) {
 	env := setupIntentHandlerTestEnv(t)
 
@@ -408,7 +437,7 @@ type intentHandlerTestEnv struct {
 }
 
 func setupIntentHandlerTestEnv(t *testing.T) *intentHandlerTestEnv {
-	db := code_data.NewTestDataProvider()
+	db := datatest.NewDataProvider()
 	return &intentHandlerTestEnv{
 		ctx:            context.Background(),
 		data:           db,
@@ -667,6 +696,28 @@ func (e *intentHandlerTestEnv) createIntent(t *testing.T, intentType intent.Type
 		}
 		actionRecords = append(actionRecords, actionRecord)
 
+	case intent.TreasuryPoolFunding:
+		intentRecord.TreasuryPoolFundingMetadata = &intent.TreasuryPoolFundingMetadata{
+			TreasuryPool: "treasury",
+			Source:       "hot-wallet",
+			Quantity:     kin.ToQuarks(1_000_000),
+		}
+
+		actionRecord := &action.Record{
+			Intent:     intentRecord.IntentId,
+			IntentType: intentType,
+
+			ActionId:   0,
+			ActionType: action.TreasuryPoolFunding,
+
+			Source:      "hot-wallet",
+			Destination: pointer.String("treasury-vault"),
+			Quantity:    pointer.Uint64(kin.ToQuarks(1_000_000)),
+
+			State: action.StatePending,
+		}
+		actionRecords = append(actionRecords, actionRecord)
+
 	case intent.MigrateToPrivacy2022:
 		intentRecord.MigrateToPrivacy2022Metadata = &intent.MigrateToPrivacy2022Metadata{
 			Quantity: 0,
@@ -849,6 +900,17 @@ func (e *intentHandlerTestEnv) createIntent(t *testing.T, intentType intent.Type
 				FulfillmentOrderingIndex: 0,
 			}
 
+			newFulfillmentRecords = append(newFulfillmentRecords, fulfillmentRecord)
+		case action.TreasuryPoolFunding:
+			fulfillmentRecord := &fulfillment.Record{
+				FulfillmentType: fulfillment.TransferToTreasuryPool,
+
+				Source:      actionRecord.Source,
+				Destination: actionRecord.Destination,
+
+				FulfillmentOrderingIndex: 0,
+			}
+
 			newFulfillmentRecords = append(newFulfillmentRecords, fulfillmentRecord)
 		default:
 			assert.Fail(t, "unsupported action type")
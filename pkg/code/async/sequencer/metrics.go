@@ -6,6 +6,7 @@ import (
 
 	"github.com/code-payments/code-server/pkg/metrics"
 	"github.com/code-payments/code-server/pkg/code/common"
+	"github.com/code-payments/code-server/pkg/code/data/deadletter"
 	"github.com/code-payments/code-server/pkg/code/data/fulfillment"
 )
 
@@ -13,6 +14,9 @@ const (
 	fulfillmentCountEventName                  = "FulfillmentCountPollingCheck"
 	subsidizerBalanceEventName                 = "SubsidizerBalancePollingCheck"
 	temporaryPrivateTransferScheduledEventName = "TemporaryPrivateTransferScheduled"
+	failedFulfillmentRemediatedEventName       = "FailedFulfillmentRemediated"
+	quarantinedFulfillmentCountEventName       = "QuarantinedFulfillmentCountPollingCheck"
+	failedFulfillmentCircuitBreakerEventName   = "FailedFulfillmentCircuitBreakerPollingCheck"
 )
 
 func (p *service) metricsGaugeWorker(ctx context.Context) error {
@@ -40,6 +44,20 @@ func (p *service) metricsGaugeWorker(ctx context.Context) error {
 				}
 			}
 
+			countByCategory, err := p.data.GetFulfillmentDeadLetterCountByStateGroupedByCategory(ctx, deadletter.StateQuarantined)
+			if err == nil {
+				for category, count := range countByCategory {
+					recordQuarantinedFulfillmentCountEvent(ctx, category, count)
+				}
+			}
+
+			// Quarantined and retried fulfillments keep the circuit breaker tripped
+			// until they're resolved or reset, or the threshold is raised
+			failedCount, err := getGlobalFailedFulfillmentCount(ctx, p.data)
+			if err == nil {
+				recordFailedFulfillmentCircuitBreakerEvent(ctx, failedCount, p.conf.maxGlobalFailedFulfillments.Get(ctx))
+			}
+
 			lamports, err := common.GetCurrentSubsidizerBalance(ctx, p.data)
 			if err == nil {
 				recordSubsidizerBalanceEvent(ctx, lamports)
@@ -69,3 +87,28 @@ func recordTemporaryPrivateTransferScheduledEvent(ctx context.Context, fulfillme
 		"signature": *fulfillmentRecord.Signature,
 	})
 }
+
+func recordFailedFulfillmentRemediatedEvent(ctx context.Context, deadLetterRecord *deadletter.Record) {
+	metrics.RecordEvent(ctx, failedFulfillmentRemediatedEventName, map[string]interface{}{
+		"fulfillment": deadLetterRecord.FulfillmentId,
+		"type":        deadLetterRecord.FulfillmentType.String(),
+		"category":    deadLetterRecord.Category.String(),
+		"outcome":     deadLetterRecord.State.String(),
+		"retries":     deadLetterRecord.Retries,
+	})
+}
+
+func recordQuarantinedFulfillmentCountEvent(ctx context.Context, category deadletter.Category, count uint64) {
+	metrics.RecordEvent(ctx, quarantinedFulfillmentCountEventName, map[string]interface{}{
+		"count":    count,
+		"category": category.String(),
+	})
+}
+
+func recordFailedFulfillmentCircuitBreakerEvent(ctx context.Context, failedCount, threshold uint64) {
+	metrics.RecordEvent(ctx, failedFulfillmentCircuitBreakerEventName, map[string]interface{}{
+		"failed":    failedCount,
+		"threshold": threshold,
+		"tripped":   failedCount > threshold,
+	})
+}
@@ -6,11 +6,12 @@ import (
 	"time"
 
 	currency_lib "github.com/code-payments/code-server/pkg/currency"
-	"github.com/code-payments/code-server/pkg/kin"
+	"github.com/code-payments/code-server/pkg/mint"
 	code_data "github.com/code-payments/code-server/pkg/code/data"
 	"github.com/code-payments/code-server/pkg/code/data/fulfillment"
 	"github.com/code-payments/code-server/pkg/code/data/payment"
 	"github.com/code-payments/code-server/pkg/code/data/transaction"
+	exchange_rate_util "github.com/code-payments/code-server/pkg/code/exchangerate"
 )
 
 func savePaymentRecord(ctx context.Context, data code_data.Provider, fulfillmentRecord *fulfillment.Record, txnRecord *transaction.Record) error {
@@ -40,7 +41,17 @@ func savePaymentRecord(ctx context.Context, data code_data.Provider, fulfillment
 		return errors.New("cannot save a payment that hasn't finalized")
 	}
 
-	usdExchangeRecord, err := data.GetExchangeRate(ctx, currency_lib.USD, time.Now())
+	intentRecord, err := data.GetIntent(ctx, fulfillmentRecord.Intent)
+	if err != nil {
+		return err
+	}
+
+	tokenMint, err := mint.Get(intentRecord.Mint)
+	if err != nil {
+		return err
+	}
+
+	usdMarketValue, err := exchange_rate_util.GetUsdMarketValue(ctx, data, tokenMint, *actionRecord.Quantity, time.Now())
 	if err != nil {
 		return err
 	}
@@ -54,12 +65,12 @@ func savePaymentRecord(ctx context.Context, data code_data.Provider, fulfillment
 		// todo: Assumes we don't split payments across multiple transactions in an action
 		Quantity: *actionRecord.Quantity,
 
-		// todo: Just filling this in with KIN currency and latest USD rate. I don't think
-		//       these make sense in payment records anymore. These details are captured by
-		//       intents.
-		ExchangeCurrency: string(currency_lib.KIN),
+		// todo: Just filling this in with the token's currency and latest USD rate. I don't
+		//       think these make sense in payment records anymore. These details are captured
+		//       by intents.
+		ExchangeCurrency: string(currency_lib.Code(tokenMint.Symbol)),
 		ExchangeRate:     1.0,
-		UsdMarketValue:   usdExchangeRecord.Rate * float64(kin.FromQuarks(*actionRecord.Quantity)),
+		UsdMarketValue:   usdMarketValue,
 
 		IsExternal: false,
 		Rendezvous: fulfillmentRecord.Intent,
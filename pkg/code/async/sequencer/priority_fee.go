@@ -0,0 +1,332 @@
+package async_sequencer
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/code-payments/code-server/pkg/code/common"
+	code_data "github.com/code-payments/code-server/pkg/code/data"
+	"github.com/code-payments/code-server/pkg/code/data/fulfillment"
+	transaction_util "github.com/code-payments/code-server/pkg/code/transaction"
+	compute_budget "github.com/code-payments/code-server/pkg/solana/computebudget"
+)
+
+const (
+	// PriorityFeePolicyNone doesn't include any compute budget instructions
+	PriorityFeePolicyNone = "none"
+
+	// PriorityFeePolicyStatic uses a fixed compute unit price
+	PriorityFeePolicyStatic = "static"
+
+	// PriorityFeePolicyRecent derives the compute unit price from recent
+	// prioritization fees paid for the accounts being written to
+	PriorityFeePolicyRecent = "recent"
+)
+
+// PriorityFeePolicy determines the compute budget for server-signed transactions
+// that are made on demand by fulfillment handlers.
+type PriorityFeePolicy interface {
+	// GetComputeBudget returns the compute budget for a transaction fulfilling the
+	// provided fulfillment type. The writable accounts are the set of accounts the
+	// transaction will lock for writing. A nil compute budget indicates no compute
+	// budget instructions should be added to the transaction.
+	GetComputeBudget(ctx context.Context, fulfillmentType fulfillment.Type, writableAccounts []string) (*transaction_util.ComputeBudget, error)
+}
+
+// instructionCountsByComputeBudgetedType is the number of instructions, excluding
+// compute budget instructions, in the transactions made by fulfillment handlers
+// that attach a compute budget. Transactions for all other fulfillment types
+// never pay a priority fee.
+var instructionCountsByComputeBudgetedType = map[fulfillment.Type]int{
+	// Initialize
+	fulfillment.InitializeLockedTimelockAccount: 1,
+
+	// Memo and transfer with commitment
+	fulfillment.TransferWithCommitment: 2,
+}
+
+type staticPriorityFeePolicy struct {
+	microLamports uint64
+	overrides     map[fulfillment.Type]uint64
+	unitLimits    map[fulfillment.Type]uint32
+}
+
+// NewStaticPriorityFeePolicy returns a PriorityFeePolicy that always pays the
+// provided compute unit price, unless overriden for a fulfillment type.
+func NewStaticPriorityFeePolicy(microLamports uint64, overrides map[fulfillment.Type]uint64, unitLimits map[fulfillment.Type]uint32) PriorityFeePolicy {
+	return &staticPriorityFeePolicy{
+		microLamports: microLamports,
+		overrides:     overrides,
+		unitLimits:    unitLimits,
+	}
+}
+
+// GetComputeBudget implements PriorityFeePolicy.GetComputeBudget
+func (p *staticPriorityFeePolicy) GetComputeBudget(_ context.Context, fulfillmentType fulfillment.Type, _ []string) (*transaction_util.ComputeBudget, error) {
+	unitPrice, ok := p.overrides[fulfillmentType]
+	if !ok {
+		unitPrice = p.microLamports
+	}
+
+	return newComputeBudget(unitPrice, p.unitLimits[fulfillmentType]), nil
+}
+
+type recentPriorityFeePolicy struct {
+	data       code_data.Provider
+	percentile float64
+	min        uint64
+	max        uint64
+	overrides  map[fulfillment.Type]uint64
+	unitLimits map[fulfillment.Type]uint32
+}
+
+// NewRecentPriorityFeePolicy returns a PriorityFeePolicy that pays the provided
+// percentile of recent prioritization fees for the set of writable accounts,
+// bounded by [min, max], unless overriden for a fulfillment type.
+func NewRecentPriorityFeePolicy(data code_data.Provider, percentile float64, min, max uint64, overrides map[fulfillment.Type]uint64, unitLimits map[fulfillment.Type]uint32) (PriorityFeePolicy, error) {
+	if percentile < 0 || percentile > 1 {
+		return nil, errors.New("percentile must be in the range [0, 1]")
+	}
+
+	if min > max {
+		return nil, errors.New("min priority fee exceeds max")
+	}
+
+	return &recentPriorityFeePolicy{
+		data:       data,
+		percentile: percentile,
+		min:        min,
+		max:        max,
+		overrides:  overrides,
+		unitLimits: unitLimits,
+	}, nil
+}
+
+// GetComputeBudget implements PriorityFeePolicy.GetComputeBudget
+func (p *recentPriorityFeePolicy) GetComputeBudget(ctx context.Context, fulfillmentType fulfillment.Type, writableAccounts []string) (*transaction_util.ComputeBudget, error) {
+	if unitPrice, ok := p.overrides[fulfillmentType]; ok {
+		return newComputeBudget(unitPrice, p.unitLimits[fulfillmentType]), nil
+	}
+
+	recentFees, err := p.data.GetBlockchainRecentPrioritizationFees(ctx, writableAccounts)
+	if err != nil {
+		return nil, err
+	}
+
+	var unitPrice uint64
+	if len(recentFees) > 0 {
+		values := make([]uint64, len(recentFees))
+		for i, recentFee := range recentFees {
+			values[i] = recentFee.MicroLamports
+		}
+		sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+
+		index := int(math.Ceil(p.percentile*float64(len(values)))) - 1
+		if index < 0 {
+			index = 0
+		}
+		unitPrice = values[index]
+	}
+
+	if unitPrice < p.min {
+		unitPrice = p.min
+	}
+	if unitPrice > p.max {
+		unitPrice = p.max
+	}
+
+	return newComputeBudget(unitPrice, p.unitLimits[fulfillmentType]), nil
+}
+
+// configuredPriorityFeePolicy selects and configures a PriorityFeePolicy using
+// the service config, which is evaluated on every call so the policy can be
+// changed without restarting.
+type configuredPriorityFeePolicy struct {
+	data code_data.Provider
+	conf *conf
+}
+
+func newConfiguredPriorityFeePolicy(data code_data.Provider, configProvider ConfigProvider) *configuredPriorityFeePolicy {
+	return &configuredPriorityFeePolicy{
+		data: data,
+		conf: configProvider(),
+	}
+}
+
+// GetComputeBudget implements PriorityFeePolicy.GetComputeBudget
+func (p *configuredPriorityFeePolicy) GetComputeBudget(ctx context.Context, fulfillmentType fulfillment.Type, writableAccounts []string) (*transaction_util.ComputeBudget, error) {
+	overrides, err := parsePriorityFeeOverrides(p.conf.priorityFeeOverrides.Get(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	unitLimits, err := parseComputeUnitLimitOverrides(p.conf.computeUnitLimitOverrides.Get(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	var policy PriorityFeePolicy
+	switch strings.ToLower(p.conf.priorityFeePolicy.Get(ctx)) {
+	case "", PriorityFeePolicyNone:
+		return nil, nil
+	case PriorityFeePolicyStatic:
+		policy = NewStaticPriorityFeePolicy(p.conf.staticPriorityFee.Get(ctx), overrides, unitLimits)
+	case PriorityFeePolicyRecent:
+		policy, err = NewRecentPriorityFeePolicy(
+			p.data,
+			p.conf.recentPriorityFeePercentile.Get(ctx),
+			p.conf.minPriorityFee.Get(ctx),
+			p.conf.maxPriorityFee.Get(ctx),
+			overrides,
+			unitLimits,
+		)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.Errorf("unsupported priority fee policy: %s", p.conf.priorityFeePolicy.Get(ctx))
+	}
+
+	return policy.GetComputeBudget(ctx, fulfillmentType, writableAccounts)
+}
+
+// GetMaxPriorityFee returns the maximum priority fee, in lamports, that a single
+// fulfillment of the provided type pays under the configured policy. Only
+// fulfillment types whose handlers attach a compute budget pay a priority fee.
+// Those without a compute unit limit override use the runtime default limit for
+// the instructions in their transactions.
+func (p *configuredPriorityFeePolicy) GetMaxPriorityFee(ctx context.Context, fulfillmentType fulfillment.Type) (uint64, error) {
+	numInstructions, ok := instructionCountsByComputeBudgetedType[fulfillmentType]
+	if !ok {
+		return 0, nil
+	}
+
+	overrides, err := parsePriorityFeeOverrides(p.conf.priorityFeeOverrides.Get(ctx))
+	if err != nil {
+		return 0, err
+	}
+
+	unitLimits, err := parseComputeUnitLimitOverrides(p.conf.computeUnitLimitOverrides.Get(ctx))
+	if err != nil {
+		return 0, err
+	}
+
+	unitPrice, ok := overrides[fulfillmentType]
+	if !ok {
+		switch strings.ToLower(p.conf.priorityFeePolicy.Get(ctx)) {
+		case "", PriorityFeePolicyNone:
+			return 0, nil
+		case PriorityFeePolicyStatic:
+			unitPrice = p.conf.staticPriorityFee.Get(ctx)
+		case PriorityFeePolicyRecent:
+			unitPrice = p.conf.maxPriorityFee.Get(ctx)
+		default:
+			return 0, errors.Errorf("unsupported priority fee policy: %s", p.conf.priorityFeePolicy.Get(ctx))
+		}
+	}
+
+	computeBudget := &transaction_util.ComputeBudget{
+		UnitLimit: unitLimits[fulfillmentType],
+		UnitPrice: unitPrice,
+	}
+	return computeBudget.GetPriorityFee(numInstructions), nil
+}
+
+// NewPriorityFeeEstimator returns a common.PriorityFeeEstimator for the priority
+// fees paid by fulfillments under the configured priority fee policy.
+func NewPriorityFeeEstimator(data code_data.Provider, configProvider ConfigProvider) common.PriorityFeeEstimator {
+	return newConfiguredPriorityFeePolicy(data, configProvider).GetMaxPriorityFee
+}
+
+func newComputeBudget(unitPrice uint64, unitLimit uint32) *transaction_util.ComputeBudget {
+	if unitPrice == 0 && unitLimit == 0 {
+		return nil
+	}
+
+	return &transaction_util.ComputeBudget{
+		UnitLimit: unitLimit,
+		UnitPrice: unitPrice,
+	}
+}
+
+// parsePriorityFeeOverrides parses overrides in the format of a comma separated
+// list of <fulfillment_type>=<micro_lamports> pairs (eg. "transfer_with_commitment=5000")
+func parsePriorityFeeOverrides(value string) (map[fulfillment.Type]uint64, error) {
+	res := make(map[fulfillment.Type]uint64)
+
+	err := parseFulfillmentTypeOverrides(value, func(fulfillmentType fulfillment.Type, rawValue string) error {
+		parsed, err := strconv.ParseUint(rawValue, 10, 64)
+		if err != nil {
+			return errors.Wrapf(err, "invalid priority fee override for %s", fulfillmentType)
+		}
+
+		res[fulfillmentType] = parsed
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// parseComputeUnitLimitOverrides parses overrides in the format of a comma
+// separated list of <fulfillment_type>=<units> pairs (eg. "initialize_locked_timelock_account=50000")
+func parseComputeUnitLimitOverrides(value string) (map[fulfillment.Type]uint32, error) {
+	res := make(map[fulfillment.Type]uint32)
+
+	err := parseFulfillmentTypeOverrides(value, func(fulfillmentType fulfillment.Type, rawValue string) error {
+		parsed, err := strconv.ParseUint(rawValue, 10, 32)
+		if err != nil {
+			return errors.Wrapf(err, "invalid compute unit limit override for %s", fulfillmentType)
+		}
+
+		if parsed > compute_budget.MaxComputeUnitLimit {
+			return errors.Errorf("compute unit limit override for %s exceeds max", fulfillmentType)
+		}
+
+		res[fulfillmentType] = uint32(parsed)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func parseFulfillmentTypeOverrides(value string, onOverride func(fulfillment.Type, string) error) error {
+	value = strings.TrimSpace(value)
+	if len(value) == 0 {
+		return nil
+	}
+
+	fulfillmentTypesByName := make(map[string]fulfillment.Type)
+	for fulfillmentType := fulfillment.InitializeLockedTimelockAccount; fulfillmentType <= fulfillment.TransferToTreasuryPool; fulfillmentType++ {
+		fulfillmentTypesByName[fulfillmentType.String()] = fulfillmentType
+	}
+
+	for _, pair := range strings.Split(value, ",") {
+		parts := strings.Split(strings.TrimSpace(pair), "=")
+		if len(parts) != 2 {
+			return errors.Errorf("invalid override: %s", pair)
+		}
+
+		fulfillmentType, ok := fulfillmentTypesByName[strings.TrimSpace(parts[0])]
+		if !ok {
+			return errors.Errorf("unknown fulfillment type: %s", parts[0])
+		}
+
+		err := onOverride(fulfillmentType, strings.TrimSpace(parts[1]))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,265 @@
+package async_sequencer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	code_data "github.com/code-payments/code-server/pkg/code/data"
+	"github.com/code-payments/code-server/pkg/code/data/fulfillment"
+	"github.com/code-payments/code-server/pkg/config/memory"
+	"github.com/code-payments/code-server/pkg/config/wrapper"
+	"github.com/code-payments/code-server/pkg/solana"
+	compute_budget "github.com/code-payments/code-server/pkg/solana/computebudget"
+)
+
+func TestStaticPriorityFeePolicy(t *testing.T) {
+	ctx := context.Background()
+
+	policy := NewStaticPriorityFeePolicy(
+		1_000,
+		map[fulfillment.Type]uint64{
+			fulfillment.InitializeLockedTimelockAccount: 0,
+			fulfillment.TransferWithCommitment:          5_000,
+		},
+		map[fulfillment.Type]uint32{
+			fulfillment.TransferWithCommitment: 100_000,
+		},
+	)
+
+	computeBudget, err := policy.GetComputeBudget(ctx, fulfillment.InitializeLockedTimelockAccount, nil)
+	require.NoError(t, err)
+	assert.Nil(t, computeBudget)
+
+	computeBudget, err = policy.GetComputeBudget(ctx, fulfillment.TransferWithCommitment, nil)
+	require.NoError(t, err)
+	require.NotNil(t, computeBudget)
+	assert.EqualValues(t, 5_000, computeBudget.UnitPrice)
+	assert.EqualValues(t, 100_000, computeBudget.UnitLimit)
+
+	computeBudget, err = policy.GetComputeBudget(ctx, fulfillment.SaveRecentRoot, nil)
+	require.NoError(t, err)
+	require.NotNil(t, computeBudget)
+	assert.EqualValues(t, 1_000, computeBudget.UnitPrice)
+	assert.EqualValues(t, 0, computeBudget.UnitLimit)
+}
+
+func TestRecentPriorityFeePolicy(t *testing.T) {
+	ctx := context.Background()
+
+	data := &mockPriorityFeeDataProvider{
+		fees: []solana.PrioritizationFee{
+			{Slot: 1, MicroLamports: 0},
+			{Slot: 2, MicroLamports: 400},
+			{Slot: 3, MicroLamports: 100},
+			{Slot: 4, MicroLamports: 300},
+			{Slot: 5, MicroLamports: 200},
+		},
+	}
+
+	_, err := NewRecentPriorityFeePolicy(data, 1.5, 0, 1_000, nil, nil)
+	assert.Error(t, err)
+
+	_, err = NewRecentPriorityFeePolicy(data, 0.5, 1_000, 0, nil, nil)
+	assert.Error(t, err)
+
+	for _, tc := range []struct {
+		percentile float64
+		min        uint64
+		max        uint64
+		expected   uint64
+	}{
+		{0.0, 0, 1_000, 0},
+		{0.5, 0, 1_000, 200},
+		{0.75, 0, 1_000, 300},
+		{1.0, 0, 1_000, 400},
+		{1.0, 0, 250, 250},
+		{0.0, 50, 1_000, 50},
+	} {
+		policy, err := NewRecentPriorityFeePolicy(data, tc.percentile, tc.min, tc.max, nil, nil)
+		require.NoError(t, err)
+
+		computeBudget, err := policy.GetComputeBudget(ctx, fulfillment.TransferWithCommitment, []string{"account"})
+		require.NoError(t, err)
+		if tc.expected == 0 {
+			assert.Nil(t, computeBudget)
+		} else {
+			require.NotNil(t, computeBudget)
+			assert.Equal(t, tc.expected, computeBudget.UnitPrice)
+		}
+	}
+	assert.Equal(t, []string{"account"}, data.lastAccounts)
+
+	policy, err := NewRecentPriorityFeePolicy(data, 1.0, 0, 1_000, map[fulfillment.Type]uint64{fulfillment.TransferWithCommitment: 10}, nil)
+	require.NoError(t, err)
+
+	computeBudget, err := policy.GetComputeBudget(ctx, fulfillment.TransferWithCommitment, nil)
+	require.NoError(t, err)
+	require.NotNil(t, computeBudget)
+	assert.EqualValues(t, 10, computeBudget.UnitPrice)
+
+	data.fees = nil
+	computeBudget, err = policy.GetComputeBudget(ctx, fulfillment.InitializeLockedTimelockAccount, nil)
+	require.NoError(t, err)
+	assert.Nil(t, computeBudget)
+}
+
+func TestConfiguredPriorityFeePolicy(t *testing.T) {
+	ctx := context.Background()
+
+	data := &mockPriorityFeeDataProvider{
+		fees: []solana.PrioritizationFee{
+			{Slot: 1, MicroLamports: 750},
+		},
+	}
+
+	policyConfig := memory.NewConfig(PriorityFeePolicyNone)
+	overridesConfig := memory.NewConfig("")
+	unitLimitsConfig := memory.NewConfig("")
+
+	configProvider := withManualTestOverrides(&testOverrides{})
+	testConf := configProvider()
+	testConf.priorityFeePolicy = wrapper.NewStringConfig(policyConfig, PriorityFeePolicyNone)
+	testConf.staticPriorityFee = wrapper.NewUint64Config(memory.NewConfig(uint64(2_000)), 0)
+	testConf.priorityFeeOverrides = wrapper.NewStringConfig(overridesConfig, "")
+	testConf.computeUnitLimitOverrides = wrapper.NewStringConfig(unitLimitsConfig, "")
+
+	policy := newConfiguredPriorityFeePolicy(data, func() *conf { return testConf })
+
+	computeBudget, err := policy.GetComputeBudget(ctx, fulfillment.TransferWithCommitment, nil)
+	require.NoError(t, err)
+	assert.Nil(t, computeBudget)
+
+	policyConfig.SetValue(PriorityFeePolicyStatic)
+	computeBudget, err = policy.GetComputeBudget(ctx, fulfillment.TransferWithCommitment, nil)
+	require.NoError(t, err)
+	require.NotNil(t, computeBudget)
+	assert.EqualValues(t, 2_000, computeBudget.UnitPrice)
+
+	policyConfig.SetValue(PriorityFeePolicyRecent)
+	computeBudget, err = policy.GetComputeBudget(ctx, fulfillment.TransferWithCommitment, nil)
+	require.NoError(t, err)
+	require.NotNil(t, computeBudget)
+	assert.EqualValues(t, 750, computeBudget.UnitPrice)
+
+	overridesConfig.SetValue("transfer_with_commitment=3000, save_recent_root=0")
+	unitLimitsConfig.SetValue("transfer_with_commitment=80000")
+	computeBudget, err = policy.GetComputeBudget(ctx, fulfillment.TransferWithCommitment, nil)
+	require.NoError(t, err)
+	require.NotNil(t, computeBudget)
+	assert.EqualValues(t, 3_000, computeBudget.UnitPrice)
+	assert.EqualValues(t, 80_000, computeBudget.UnitLimit)
+
+	for _, invalid := range []string{
+		"transfer_with_commitment",
+		"unknown_type=100",
+		"transfer_with_commitment=-1",
+	} {
+		overridesConfig.SetValue(invalid)
+		_, err = policy.GetComputeBudget(ctx, fulfillment.TransferWithCommitment, nil)
+		assert.Error(t, err)
+	}
+	overridesConfig.SetValue("")
+
+	unitLimitsConfig.SetValue("transfer_with_commitment=2000000")
+	_, err = policy.GetComputeBudget(ctx, fulfillment.TransferWithCommitment, nil)
+	assert.Error(t, err)
+	unitLimitsConfig.SetValue("")
+
+	policyConfig.SetValue("unknown")
+	_, err = policy.GetComputeBudget(ctx, fulfillment.TransferWithCommitment, nil)
+	assert.Error(t, err)
+}
+
+func TestConfiguredPriorityFeePolicy_MaxPriorityFee(t *testing.T) {
+	ctx := context.Background()
+
+	policyConfig := memory.NewConfig(PriorityFeePolicyNone)
+	overridesConfig := memory.NewConfig("")
+	unitLimitsConfig := memory.NewConfig("")
+
+	configProvider := withManualTestOverrides(&testOverrides{})
+	testConf := configProvider()
+	testConf.priorityFeePolicy = wrapper.NewStringConfig(policyConfig, PriorityFeePolicyNone)
+	testConf.staticPriorityFee = wrapper.NewUint64Config(memory.NewConfig(uint64(2_000)), 0)
+	testConf.maxPriorityFee = wrapper.NewUint64Config(memory.NewConfig(uint64(50_000)), 0)
+	testConf.priorityFeeOverrides = wrapper.NewStringConfig(overridesConfig, "")
+	testConf.computeUnitLimitOverrides = wrapper.NewStringConfig(unitLimitsConfig, "")
+
+	estimator := NewPriorityFeeEstimator(&mockPriorityFeeDataProvider{}, func() *conf { return testConf })
+
+	fee, err := estimator(ctx, fulfillment.TransferWithCommitment)
+	require.NoError(t, err)
+	assert.EqualValues(t, 0, fee)
+
+	policyConfig.SetValue(PriorityFeePolicyStatic)
+	fee, err = estimator(ctx, fulfillment.TransferWithCommitment)
+	require.NoError(t, err)
+	assert.EqualValues(t, compute_budget.GetPriorityFee(2*compute_budget.DefaultInstructionComputeUnitLimit, 2_000), fee)
+
+	policyConfig.SetValue(PriorityFeePolicyRecent)
+	fee, err = estimator(ctx, fulfillment.TransferWithCommitment)
+	require.NoError(t, err)
+	assert.EqualValues(t, compute_budget.GetPriorityFee(2*compute_budget.DefaultInstructionComputeUnitLimit, 50_000), fee)
+
+	overridesConfig.SetValue("transfer_with_commitment=3000")
+	unitLimitsConfig.SetValue("transfer_with_commitment=80000")
+	fee, err = estimator(ctx, fulfillment.TransferWithCommitment)
+	require.NoError(t, err)
+	assert.EqualValues(t, 240, fee)
+
+	policyConfig.SetValue("unknown")
+	_, err = estimator(ctx, fulfillment.InitializeLockedTimelockAccount)
+	assert.Error(t, err)
+}
+
+func TestConfiguredPriorityFeePolicy_MaxPriorityFeeByFulfillmentType(t *testing.T) {
+	ctx := context.Background()
+
+	configProvider := withManualTestOverrides(&testOverrides{})
+	testConf := configProvider()
+	testConf.priorityFeePolicy = wrapper.NewStringConfig(memory.NewConfig(PriorityFeePolicyStatic), PriorityFeePolicyNone)
+	testConf.staticPriorityFee = wrapper.NewUint64Config(memory.NewConfig(uint64(2_000)), 0)
+	testConf.priorityFeeOverrides = wrapper.NewStringConfig(memory.NewConfig(""), "")
+	testConf.computeUnitLimitOverrides = wrapper.NewStringConfig(memory.NewConfig("initialize_locked_timelock_account=30000"), "")
+
+	estimator := NewPriorityFeeEstimator(&mockPriorityFeeDataProvider{}, func() *conf { return testConf })
+
+	for _, tc := range []struct {
+		fulfillmentType fulfillment.Type
+		expected        uint64
+	}{
+		{fulfillment.InitializeLockedTimelockAccount, compute_budget.GetPriorityFee(30_000, 2_000)},
+		{fulfillment.NoPrivacyTransferWithAuthority, 0},
+		{fulfillment.NoPrivacyWithdraw, 0},
+		{fulfillment.TemporaryPrivacyTransferWithAuthority, 0},
+		{fulfillment.PermanentPrivacyTransferWithAuthority, 0},
+		{fulfillment.TransferWithCommitment, compute_budget.GetPriorityFee(2*compute_budget.DefaultInstructionComputeUnitLimit, 2_000)},
+		{fulfillment.CloseEmptyTimelockAccount, 0},
+		{fulfillment.CloseDormantTimelockAccount, 0},
+		{fulfillment.SaveRecentRoot, 0},
+		{fulfillment.InitializeCommitmentProof, 0},
+		{fulfillment.UploadCommitmentProof, 0},
+		{fulfillment.OpenCommitmentVault, 0},
+		{fulfillment.CloseCommitmentVault, 0},
+		{fulfillment.TransferToTreasuryPool, 0},
+	} {
+		fee, err := estimator(ctx, tc.fulfillmentType)
+		require.NoError(t, err)
+		assert.Equal(t, tc.expected, fee, tc.fulfillmentType.String())
+	}
+}
+
+type mockPriorityFeeDataProvider struct {
+	code_data.Provider
+
+	fees         []solana.PrioritizationFee
+	lastAccounts []string
+}
+
+func (p *mockPriorityFeeDataProvider) GetBlockchainRecentPrioritizationFees(_ context.Context, writableAccounts []string) ([]solana.PrioritizationFee, error) {
+	p.lastAccounts = writableAccounts
+	return p.fees, nil
+}
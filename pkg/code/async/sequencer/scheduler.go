@@ -25,11 +25,7 @@ type contextualScheduler struct {
 	data           code_data.Provider
 	conf           *conf
 	handlersByType map[fulfillment.Type]FulfillmentHandler
-
-	// Workaround config to allow tests to pass
-	//
-	// todo: Need a path forward to test things that call the blockchain directly.
-	includeSubsidizerChecks bool
+	priorityFees   common.PriorityFeeEstimator
 }
 
 // NewContextualScheduler returns a scheduler that utilizes the global, account,
@@ -51,11 +47,11 @@ type contextualScheduler struct {
 //     success before being created.
 func NewContextualScheduler(data code_data.Provider, configProvider ConfigProvider) Scheduler {
 	return &contextualScheduler{
-		log:                     logrus.StandardLogger().WithField("type", "sequencer/scheduler/contextual"),
-		data:                    data,
-		conf:                    configProvider(),
-		handlersByType:          getFulfillmentHandlers(data, configProvider),
-		includeSubsidizerChecks: true,
+		log:            logrus.StandardLogger().WithField("type", "sequencer/scheduler/contextual"),
+		data:           data,
+		conf:           configProvider(),
+		handlersByType: getFulfillmentHandlers(data, configProvider),
+		priorityFees:   NewPriorityFeeEstimator(data, configProvider),
 	}
 }
 
@@ -176,7 +172,7 @@ func (s *contextualScheduler) CanSubmitToBlockchain(ctx context.Context, fulfill
 	}
 
 	// Global circuit breaker based on the total failed fulfillment count
-	numFailedFulfillments, err = s.data.GetFulfillmentCountByState(ctx, fulfillment.StateFailed)
+	numFailedFulfillments, err = getGlobalFailedFulfillmentCount(ctx, s.data)
 	if err != nil {
 		log.WithError(err).Warn("failure getting globlal failed fulfillment count")
 		return false, err
@@ -206,24 +202,21 @@ func (s *contextualScheduler) CanSubmitToBlockchain(ctx context.Context, fulfill
 	// Part 5: Subsidizer checks
 	//
 
-	// todo: Need a path forward to test things that call the blockchain directly.
-	if s.includeSubsidizerChecks {
-		// Determine if there is sufficient balance in the subsidizer to cover fees
-		// for this fulfillment.
-		//
-		// todo: This is the most naive approach, isn't terribly performant, and won't
-		//       be guaranteed to work well beyond a single thread. It's better than
-		//       nothing for a quick first pass implementation.
-		// todo: We should really consider hardening before launch given sheer amount
-		//       of accounts and nonces required for privacy v3.
-		err = common.EnforceMinimumSubsidizerBalance(ctx, s.data)
-		if err == common.ErrSubsidizerRequiresFunding {
-			log.Warn("not scheduling fulfillment because the subsidizer requires additional funding")
-			return false, nil
-		} else if err != nil {
-			log.WithError(err).Warn("failure checking minimum subidizer balance")
-			return false, err
-		}
+	// Determine if there is sufficient balance in the subsidizer to cover fees
+	// for this fulfillment.
+	//
+	// todo: This is the most naive approach, isn't terribly performant, and won't
+	//       be guaranteed to work well beyond a single thread. It's better than
+	//       nothing for a quick first pass implementation.
+	// todo: We should really consider hardening before launch given sheer amount
+	//       of accounts and nonces required for privacy v3.
+	err = common.EnforceMinimumSubsidizerBalance(ctx, s.data, s.priorityFees)
+	if err == common.ErrSubsidizerRequiresFunding {
+		log.Warn("not scheduling fulfillment because the subsidizer requires additional funding")
+		return false, nil
+	} else if err != nil {
+		log.WithError(err).Warn("failure checking minimum subidizer balance")
+		return false, err
 	}
 
 	log.Trace("scheduling this fulfillment for submission to blockchain")
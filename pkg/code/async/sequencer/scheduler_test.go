@@ -25,10 +25,12 @@ import (
 	"github.com/code-payments/code-server/pkg/testutil"
 	"github.com/code-payments/code-server/pkg/code/common"
 	code_data "github.com/code-payments/code-server/pkg/code/data"
+	datatest "github.com/code-payments/code-server/pkg/code/data/test"
 	"github.com/code-payments/code-server/pkg/code/data/account"
 	"github.com/code-payments/code-server/pkg/code/data/action"
 	"github.com/code-payments/code-server/pkg/code/data/commitment"
 	"github.com/code-payments/code-server/pkg/code/data/currency"
+	"github.com/code-payments/code-server/pkg/code/data/deadletter"
 	"github.com/code-payments/code-server/pkg/code/data/fulfillment"
 	"github.com/code-payments/code-server/pkg/code/data/intent"
 	"github.com/code-payments/code-server/pkg/code/data/timelock"
@@ -310,6 +312,64 @@ func TestContextualScheduler_GenericFlow_GlobalCircuitBreaker(t *testing.T) {
 	}
 }
 
+func TestContextualScheduler_GenericFlow_GlobalCircuitBreaker_RetriedFailures(t *testing.T) {
+	maxFailures := 2
+
+	env := setupSchedulerEnv(t, &testOverrides{
+		maxGlobalFailedFulfillments: uint64(maxFailures),
+	})
+	for key := range env.scheduler.handlersByType {
+		env.scheduler.handlersByType[key] = &mockFulfillmentHandler{
+			isScheduled: true,
+		}
+	}
+
+	alice := "alice"
+	bob := "bob"
+	intentRecords := []*intent.Record{
+		{IntentType: intent.OpenAccounts, InitiatorOwnerAccount: alice, OpenAccountsMetadata: &intent.OpenAccountsMetadata{}},
+		{IntentType: intent.OpenAccounts, InitiatorOwnerAccount: bob, OpenAccountsMetadata: &intent.OpenAccountsMetadata{}},
+	}
+	fulfillmentRecords := env.setupSchedulerTest(t, intentRecords, schedulerTestOptions{})
+
+	var scheduledFulfillments []*fulfillment.Record
+	for _, fulfillmentRecord := range fulfillmentRecords {
+		// Closing dormant accounts are unscheduled by default and ignored for this test
+		if fulfillmentRecord.ActionType != action.CloseDormantAccount {
+			scheduledFulfillments = append(scheduledFulfillments, fulfillmentRecord)
+		}
+	}
+
+	// Retried failures are no longer in the failed state, but still count
+	var deadLetterRecords []*deadletter.Record
+	for i := 0; i <= maxFailures; i++ {
+		deadLetterRecord := &deadletter.Record{
+			FulfillmentId:   uint64(1000 + i),
+			Intent:          "retried-intent",
+			FulfillmentType: fulfillment.TransferWithCommitment,
+			Signature:       fmt.Sprintf("signature%d", i),
+			Category:        deadletter.CategoryBlockhashOrNonce,
+			Reason:          "BlockhashNotFound",
+			Retries:         1,
+			State:           deadletter.StateRetried,
+		}
+		require.NoError(t, env.data.SaveFulfillmentDeadLetter(env.ctx, deadLetterRecord))
+		deadLetterRecords = append(deadLetterRecords, deadLetterRecord)
+
+		for _, fulfillmentRecord := range scheduledFulfillments {
+			env.assertSchedulingState(t, fulfillmentRecord, i < maxFailures)
+		}
+	}
+
+	// Until an operator resets them
+	deadLetterRecords[0].State = deadletter.StateReset
+	require.NoError(t, env.data.SaveFulfillmentDeadLetter(env.ctx, deadLetterRecords[0]))
+
+	for _, fulfillmentRecord := range scheduledFulfillments {
+		env.assertSchedulingState(t, fulfillmentRecord, true)
+	}
+}
+
 func TestContextualScheduler_GenericFlow_SchedulingManuallyDisabled(t *testing.T) {
 	for _, configValue := range []bool{true, false} {
 		env := setupSchedulerEnv(t, &testOverrides{
@@ -2118,7 +2178,7 @@ func setupSchedulerEnv(t *testing.T, overrides *testOverrides) *schedulerTestEnv
 
 	configProvider := withManualTestOverrides(overrides)
 
-	data := code_data.NewTestDataProvider()
+	data := datatest.NewDataProvider()
 	env := &schedulerTestEnv{
 		ctx:            context.Background(),
 		data:           data,
@@ -2135,8 +2195,6 @@ func setupSchedulerEnv(t *testing.T, overrides *testOverrides) *schedulerTestEnv
 	}
 	require.NoError(t, env.data.ImportExchangeRates(env.ctx, usdRate))
 
-	env.scheduler.includeSubsidizerChecks = false
-
 	return env
 }
 
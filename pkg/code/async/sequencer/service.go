@@ -8,6 +8,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/code-payments/code-server/pkg/code/async"
+	async_sharding "github.com/code-payments/code-server/pkg/code/async/sharding"
 	code_data "github.com/code-payments/code-server/pkg/code/data"
 	"github.com/code-payments/code-server/pkg/code/data/action"
 	"github.com/code-payments/code-server/pkg/code/data/fulfillment"
@@ -28,9 +29,13 @@ type service struct {
 	fulfillmentHandlersByType map[fulfillment.Type]FulfillmentHandler
 	actionHandlersByType      map[action.Type]ActionHandler
 	intentHandlersByType      map[intent.Type]IntentHandler
+	membership                *async_sharding.Membership
 }
 
-func New(data code_data.Provider, scheduler Scheduler, configProvider ConfigProvider) async.Service {
+// New returns a new sequencer service. Fulfillments are sharded by intent across
+// the members of the provided group membership, or all are processed when it's
+// nil.
+func New(data code_data.Provider, scheduler Scheduler, configProvider ConfigProvider, membership *async_sharding.Membership) async.Service {
 	return &service{
 		log:                       logrus.StandardLogger().WithField("service", "sequencer"),
 		conf:                      configProvider(),
@@ -39,6 +44,7 @@ func New(data code_data.Provider, scheduler Scheduler, configProvider ConfigProv
 		fulfillmentHandlersByType: getFulfillmentHandlers(data, configProvider),
 		actionHandlersByType:      getActionHandlers(data),
 		intentHandlersByType:      getIntentHandlers(data),
+		membership:                membership,
 	}
 }
 
@@ -48,10 +54,10 @@ func (p *service) Start(ctx context.Context, interval time.Duration) error {
 	for _, item := range []fulfillment.State{
 		fulfillment.StateUnknown,
 		fulfillment.StatePending,
+		fulfillment.StateFailed,
 
 		// There's no executable logic for these states yet:
 		// fulfillment.StateConfirmed,
-		// fulfillment.StateFailed,
 		// fulfillment.StateRevoked,
 	} {
 		go func(state fulfillment.State) {
@@ -58,6 +58,11 @@ func (p *service) worker(serviceCtx context.Context, state fulfillment.State, in
 			// Process the batch of fulfillments in parallel
 			var wg sync.WaitGroup
 			for _, item := range items {
+				// Fulfillments for an intent are processed by a single node
+				if !p.membership.Owns(item.Intent) {
+					continue
+				}
+
 				wg.Add(1)
 
 				go func(record *fulfillment.Record) {
@@ -275,8 +280,7 @@ func (p *service) handleConfirmed(ctx context.Context, record *fulfillment.Recor
 }
 
 func (p *service) handleFailed(ctx context.Context, record *fulfillment.Record) error {
-	// Nothing to do here. We're done...
-	return nil
+	return p.remediateFailedFulfillment(ctx, record)
 }
 
 func (p *service) handleRevoked(ctx context.Context, record *fulfillment.Record) error {
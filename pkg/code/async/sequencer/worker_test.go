@@ -13,11 +13,14 @@ import (
 	"github.com/code-payments/code-server/pkg/pointer"
 	"github.com/code-payments/code-server/pkg/solana"
 	"github.com/code-payments/code-server/pkg/solana/memo"
+	"github.com/code-payments/code-server/pkg/solana/simulator"
 	"github.com/code-payments/code-server/pkg/solana/system"
 	"github.com/code-payments/code-server/pkg/testutil"
 	"github.com/code-payments/code-server/pkg/code/common"
 	code_data "github.com/code-payments/code-server/pkg/code/data"
+	datatest "github.com/code-payments/code-server/pkg/code/data/test"
 	"github.com/code-payments/code-server/pkg/code/data/action"
+	"github.com/code-payments/code-server/pkg/code/data/deadletter"
 	"github.com/code-payments/code-server/pkg/code/data/fulfillment"
 	"github.com/code-payments/code-server/pkg/code/data/intent"
 	"github.com/code-payments/code-server/pkg/code/data/nonce"
@@ -209,6 +212,217 @@ func TestFulfillmentWorker_StatePending_TransitionToStateFailed(t *testing.T) {
 	}
 }
 
+func TestFulfillmentWorker_StateFailed_RetrySafeFailure(t *testing.T) {
+	env := setupWorkerEnv(t)
+
+	env.fulfillmentHandler.supportsOnDemandTxnCreation = true
+
+	fulfillmentRecord := env.createAnyFulfillmentInState(t, fulfillment.StateFailed)
+	env.createIntentAndActionInState(t, fulfillmentRecord, intent.StateFailed, action.StateFailed)
+
+	for i := 0; i < defaultMaxFailedFulfillmentRetries; i++ {
+		// The nonce is advanced in the first instruction
+		signature := env.simulateFailedBlockchainTransaction(t, fulfillmentRecord, 0)
+
+		require.NoError(t, env.worker.handle(env.ctx, fulfillmentRecord))
+
+		updated, err := env.data.GetFulfillmentById(env.ctx, fulfillmentRecord.Id)
+		require.NoError(t, err)
+		assert.Equal(t, fulfillment.StatePending, updated.State)
+		assert.Nil(t, updated.Signature)
+		assert.Nil(t, updated.Nonce)
+		assert.Nil(t, updated.Blockhash)
+		assert.Empty(t, updated.Data)
+
+		env.assertIntentAndActionInState(t, fulfillmentRecord, intent.StatePending, action.StatePending)
+		env.assertDeadLetter(t, fulfillmentRecord.Id, signature, deadletter.StateRetried, deadletter.CategoryBlockhashOrNonce, uint32(i+1))
+
+		// Simulate the retry failing in the same way
+		fulfillmentRecord.Nonce = pointer.String(testutil.NewRandomAccount(t).PublicKey().ToBase58())
+		fulfillmentRecord.Blockhash = pointer.String("blockhash")
+		fulfillmentRecord.State = fulfillment.StateFailed
+	}
+
+	// Retries are exhausted
+	signature := env.simulateFailedBlockchainTransaction(t, fulfillmentRecord, 0)
+
+	require.NoError(t, env.worker.handle(env.ctx, fulfillmentRecord))
+	env.assertFulfillmentInState(t, signature, fulfillment.StateFailed)
+	env.assertDeadLetter(t, fulfillmentRecord.Id, signature, deadletter.StateQuarantined, deadletter.CategoryBlockhashOrNonce, defaultMaxFailedFulfillmentRetries)
+}
+
+func TestFulfillmentWorker_StateFailed_QuarantineUnsafeFailure(t *testing.T) {
+	for _, tc := range []struct {
+		supportsOnDemandTxnCreation bool
+		failedInstruction           int
+		expectedCategory            deadletter.Category
+	}{
+		{true, 1, deadletter.CategoryProgramError},
+		{false, 0, deadletter.CategoryBlockhashOrNonce},
+		{true, -1, deadletter.CategoryUnknown},
+	} {
+		env := setupWorkerEnv(t)
+
+		env.fulfillmentHandler.supportsOnDemandTxnCreation = tc.supportsOnDemandTxnCreation
+
+		fulfillmentRecord := env.createAnyFulfillmentInState(t, fulfillment.StateFailed)
+		fulfillmentRecord.Data = nil
+		require.NoError(t, env.data.UpdateFulfillment(env.ctx, fulfillmentRecord))
+		env.createIntentAndActionInState(t, fulfillmentRecord, intent.StateFailed, action.StateFailed)
+
+		signature := *fulfillmentRecord.Signature
+		if tc.failedInstruction >= 0 {
+			signature = env.simulateFailedBlockchainTransaction(t, fulfillmentRecord, tc.failedInstruction)
+		}
+
+		for i := 0; i < 2; i++ {
+			require.NoError(t, env.worker.handle(env.ctx, fulfillmentRecord))
+			env.assertFulfillmentInState(t, signature, fulfillment.StateFailed)
+			env.assertIntentAndActionInState(t, fulfillmentRecord, intent.StateFailed, action.StateFailed)
+			env.assertDeadLetter(t, fulfillmentRecord.Id, signature, deadletter.StateQuarantined, tc.expectedCategory, 0)
+		}
+	}
+}
+
+func TestFulfillmentWorker_StateFailed_TransactionNotFound(t *testing.T) {
+	for _, advanceNonce := range []bool{true, false} {
+		ledger := simulator.NewLedger()
+		env := setupWorkerEnvWithOverrides(t, datatest.NewDataProviderWithLedger(ledger), &testOverrides{
+			maxFailedFulfillmentRetries: defaultMaxFailedFulfillmentRetries,
+		})
+
+		env.fulfillmentHandler.supportsOnDemandTxnCreation = true
+
+		nonceAccount, nonceBlockhash := env.createNonceAccountOnBlockchain(t, ledger)
+
+		fulfillmentRecord := env.createAnyFulfillmentInState(t, fulfillment.StateFailed)
+		fulfillmentRecord.Nonce = pointer.String(nonceAccount.PublicKey().ToBase58())
+		fulfillmentRecord.Blockhash = pointer.String(base58.Encode(nonceBlockhash[:]))
+		fulfillmentRecord.Data = nil
+		require.NoError(t, env.data.UpdateFulfillment(env.ctx, fulfillmentRecord))
+		env.createIntentAndActionInState(t, fulfillmentRecord, intent.StateFailed, action.StateFailed)
+
+		// The failure was observed, but the transaction never made it to a
+		// finalized block
+		signature := *fulfillmentRecord.Signature
+		slot, err := ledger.GetSlot(solana.CommitmentFinalized)
+		require.NoError(t, err)
+		require.NoError(t, env.data.SaveTransaction(env.ctx, &transaction.Record{
+			Signature:         signature,
+			Slot:              slot,
+			HasErrors:         true,
+			ConfirmationState: transaction.ConfirmationFailed,
+		}))
+
+		// Nothing is done within the finalization window, since the transaction
+		// could still land
+		ledger.AdvanceSlots(failedTransactionFinalizationWindow - 1)
+
+		require.NoError(t, env.worker.handle(env.ctx, fulfillmentRecord))
+		env.assertFulfillmentInState(t, signature, fulfillment.StateFailed)
+		env.assertIntentAndActionInState(t, fulfillmentRecord, intent.StateFailed, action.StateFailed)
+		_, err = env.data.GetFulfillmentDeadLetter(env.ctx, fulfillmentRecord.Id)
+		assert.Equal(t, deadletter.ErrDeadLetterNotFound, err)
+
+		if advanceNonce {
+			txn := solana.NewTransaction(
+				env.subsidizer.PublicKey().ToBytes(),
+				system.AdvanceNonce(nonceAccount.PublicKey().ToBytes(), env.subsidizer.PublicKey().ToBytes()),
+			)
+			txn.SetBlockhash(nonceBlockhash)
+			require.NoError(t, txn.Sign(env.subsidizer.PrivateKey().ToBytes()))
+			_, err = ledger.SubmitTransaction(txn, solana.CommitmentFinalized)
+			require.NoError(t, err)
+		} else {
+			ledger.AdvanceSlots(1)
+		}
+
+		require.NoError(t, env.worker.handle(env.ctx, fulfillmentRecord))
+
+		// The transaction can't land once its nonce is advanced. Otherwise, it's
+		// unsafe to retry.
+		if advanceNonce {
+			updated, err := env.data.GetFulfillmentById(env.ctx, fulfillmentRecord.Id)
+			require.NoError(t, err)
+			assert.Equal(t, fulfillment.StatePending, updated.State)
+			assert.Nil(t, updated.Signature)
+
+			env.assertIntentAndActionInState(t, fulfillmentRecord, intent.StatePending, action.StatePending)
+			env.assertDeadLetter(t, fulfillmentRecord.Id, signature, deadletter.StateRetried, deadletter.CategoryBlockhashOrNonce, 1)
+		} else {
+			env.assertFulfillmentInState(t, signature, fulfillment.StateFailed)
+			env.assertIntentAndActionInState(t, fulfillmentRecord, intent.StateFailed, action.StateFailed)
+			env.assertDeadLetter(t, fulfillmentRecord.Id, signature, deadletter.StateQuarantined, deadletter.CategoryUnknown, 0)
+		}
+	}
+}
+
+func TestFulfillmentWorker_StatePending_SubmittedAndConfirmedOnSimulator(t *testing.T) {
+	ledger := simulator.NewLedger()
+	env := setupWorkerEnvWithOverrides(t, datatest.NewDataProviderWithLedger(ledger), &testOverrides{
+		maxFailedFulfillmentRetries:       defaultMaxFailedFulfillmentRetries,
+		enableTransactionSubmission:       true,
+		enableBlockchainTransactionLookup: true,
+	})
+
+	nonceAccount, nonceBlockhash := env.createNonceAccountOnBlockchain(t, ledger)
+
+	txn := solana.NewTransaction(
+		env.subsidizer.PublicKey().ToBytes(),
+		system.AdvanceNonce(nonceAccount.PublicKey().ToBytes(), env.subsidizer.PublicKey().ToBytes()),
+		memo.Instruction("fulfillment"),
+	)
+	txn.SetBlockhash(nonceBlockhash)
+	require.NoError(t, txn.Sign(env.subsidizer.PrivateKey().ToBytes()))
+
+	fulfillmentRecord := &fulfillment.Record{
+		Intent:          testutil.NewRandomAccount(t).PublicKey().ToBase58(),
+		IntentType:      intent.OpenAccounts,
+		ActionId:        0,
+		ActionType:      action.OpenAccount,
+		FulfillmentType: fulfillment.InitializeLockedTimelockAccount,
+		Data:            txn.Marshal(),
+		Signature:       pointer.String(base58.Encode(txn.Signature())),
+		Source:          "source",
+		Nonce:           pointer.String(nonceAccount.PublicKey().ToBase58()),
+		Blockhash:       pointer.String(base58.Encode(nonceBlockhash[:])),
+		State:           fulfillment.StatePending,
+	}
+	require.NoError(t, env.data.PutAllFulfillments(env.ctx, fulfillmentRecord))
+	require.NoError(t, env.data.SaveNonce(env.ctx, &nonce.Record{
+		Address:   *fulfillmentRecord.Nonce,
+		Authority: env.subsidizer.PublicKey().ToBase58(),
+		Blockhash: *fulfillmentRecord.Blockhash,
+		Purpose:   nonce.PurposeInternalServerProcess,
+		Signature: *fulfillmentRecord.Signature,
+		State:     nonce.StateReserved,
+	}))
+
+	// The transaction isn't on the blockchain yet, so it gets submitted
+	require.NoError(t, env.worker.handle(env.ctx, fulfillmentRecord))
+	env.assertFulfillmentInState(t, *fulfillmentRecord.Signature, fulfillment.StatePending)
+	assert.False(t, env.fulfillmentHandler.successCallbackExecuted)
+
+	confirmed, err := ledger.GetConfirmationStatus(txn.Signatures[0], solana.CommitmentFinalized)
+	require.NoError(t, err)
+	assert.True(t, confirmed)
+
+	accountInfo, err := ledger.GetAccountInfo(nonceAccount.PublicKey().ToBytes(), solana.CommitmentFinalized)
+	require.NoError(t, err)
+	var nonceAccountState system.NonceAccount
+	require.NoError(t, nonceAccountState.Unmarshal(accountInfo.Data))
+	assert.NotEqual(t, nonceBlockhash[:], []byte(nonceAccountState.Blockhash))
+
+	// The finalized transaction is picked up from the blockchain
+	require.NoError(t, env.worker.handle(env.ctx, fulfillmentRecord))
+	env.assertFulfillmentInState(t, *fulfillmentRecord.Signature, fulfillment.StateConfirmed)
+	env.assertNonceState(t, *fulfillmentRecord.Nonce, nonce.StateReleased, *fulfillmentRecord.Signature, *fulfillmentRecord.Blockhash)
+	assert.True(t, env.fulfillmentHandler.successCallbackExecuted)
+	assert.False(t, env.fulfillmentHandler.failureCallbackExecuted)
+	assert.Equal(t, fulfillment.StateConfirmed, env.actionHandler.reportedFulfillmentState)
+	assert.True(t, env.intentHandler.callbackExecuted)
+}
+
 type workerTestEnv struct {
 	ctx                context.Context
 	data               code_data.Provider
@@ -221,14 +435,18 @@ type workerTestEnv struct {
 }
 
 func setupWorkerEnv(t *testing.T) *workerTestEnv {
-	db := code_data.NewTestDataProvider()
+	return setupWorkerEnvWithOverrides(t, datatest.NewDataProvider(), &testOverrides{
+		maxFailedFulfillmentRetries: defaultMaxFailedFulfillmentRetries,
+	})
+}
 
+func setupWorkerEnvWithOverrides(t *testing.T, db code_data.Provider, overrides *testOverrides) *workerTestEnv {
 	scheduler := &mockScheduler{}
 	fulfillmentHandler := &mockFulfillmentHandler{}
 	actionHandler := &mockActionHandler{}
 	intentHandler := &mockIntentHandler{}
 
-	worker := New(db, scheduler, withManualTestOverrides(&testOverrides{})).(*service)
+	worker := New(db, scheduler, withManualTestOverrides(overrides), nil).(*service)
 	for key := range worker.fulfillmentHandlersByType {
 		worker.fulfillmentHandlersByType[key] = fulfillmentHandler
 	}
@@ -306,6 +524,77 @@ func (e *workerTestEnv) createAnyFulfillmentInState(t *testing.T, state fulfillm
 	return fulfillmentRecord
 }
 
+func (e *workerTestEnv) createIntentAndActionInState(t *testing.T, fulfillmentRecord *fulfillment.Record, intentState intent.State, actionState action.State) {
+	intentRecord := &intent.Record{
+		IntentId:              fulfillmentRecord.Intent,
+		IntentType:            fulfillmentRecord.IntentType,
+		InitiatorOwnerAccount: "owner",
+		OpenAccountsMetadata:  &intent.OpenAccountsMetadata{},
+		State:                 intentState,
+	}
+	require.NoError(t, e.data.SaveIntent(e.ctx, intentRecord))
+
+	actionRecord, err := e.data.GetActionById(e.ctx, fulfillmentRecord.Intent, fulfillmentRecord.ActionId)
+	require.NoError(t, err)
+	actionRecord.State = actionState
+	require.NoError(t, e.data.UpdateAction(e.ctx, actionRecord))
+}
+
+// simulateFailedBlockchainTransaction submits a transaction to the blockchain
+// that fails at the provided instruction index, and assigns it to the fulfillment
+func (e *workerTestEnv) simulateFailedBlockchainTransaction(t *testing.T, fulfillmentRecord *fulfillment.Record, failedInstruction int) string {
+	unfunded := testutil.NewRandomAccount(t)
+	created := testutil.NewRandomAccount(t)
+
+	var instructions []solana.Instruction
+	for i := 0; i < failedInstruction; i++ {
+		instructions = append(instructions, memo.Instruction("success"))
+	}
+	instructions = append(instructions, system.CreateAccount(
+		unfunded.PublicKey().ToBytes(),
+		created.PublicKey().ToBytes(),
+		system.SystemAccount,
+		1_000_000,
+		0,
+	))
+
+	blockhash, err := e.data.GetBlockchainLatestBlockhash(e.ctx)
+	require.NoError(t, err)
+
+	txn := solana.NewTransaction(e.subsidizer.PublicKey().ToBytes(), instructions...)
+	txn.SetBlockhash(blockhash)
+	require.NoError(t, txn.Sign(e.subsidizer.PrivateKey().ToBytes(), unfunded.PrivateKey().ToBytes(), created.PrivateKey().ToBytes()))
+
+	_, err = e.data.SubmitBlockchainTransaction(e.ctx, &txn)
+	require.NoError(t, err)
+
+	fulfillmentRecord.Signature = pointer.String(base58.Encode(txn.Signature()))
+	fulfillmentRecord.Data = nil
+	require.NoError(t, e.data.UpdateFulfillment(e.ctx, fulfillmentRecord))
+
+	return *fulfillmentRecord.Signature
+}
+
+func (e *workerTestEnv) assertIntentAndActionInState(t *testing.T, fulfillmentRecord *fulfillment.Record, intentState intent.State, actionState action.State) {
+	intentRecord, err := e.data.GetIntent(e.ctx, fulfillmentRecord.Intent)
+	require.NoError(t, err)
+	assert.Equal(t, intentState, intentRecord.State)
+
+	actionRecord, err := e.data.GetActionById(e.ctx, fulfillmentRecord.Intent, fulfillmentRecord.ActionId)
+	require.NoError(t, err)
+	assert.Equal(t, actionState, actionRecord.State)
+}
+
+func (e *workerTestEnv) assertDeadLetter(t *testing.T, fulfillmentId uint64, signature string, state deadletter.State, category deadletter.Category, retries uint32) {
+	deadLetterRecord, err := e.data.GetFulfillmentDeadLetter(e.ctx, fulfillmentId)
+	require.NoError(t, err)
+	assert.Equal(t, signature, deadLetterRecord.Signature)
+	assert.Equal(t, state, deadLetterRecord.State)
+	assert.Equal(t, category, deadLetterRecord.Category)
+	assert.Equal(t, retries, deadLetterRecord.Retries)
+	assert.NotEmpty(t, deadLetterRecord.Reason)
+}
+
 func (e *workerTestEnv) assertFulfillmentInState(t *testing.T, sig string, expected fulfillment.State) {
 	fulfillmentRecord, err := e.data.GetFulfillmentBySignature(e.ctx, sig)
 	require.NoError(t, err)
@@ -378,3 +667,33 @@ func (e *workerTestEnv) generateAvailableNonce(t *testing.T) *nonce.Record {
 	require.NoError(t, e.data.SaveNonce(e.ctx, nonceRecord))
 	return nonceRecord
 }
+
+func (e *workerTestEnv) createNonceAccountOnBlockchain(t *testing.T, ledger *simulator.Ledger) (*common.Account, solana.Blockhash) {
+	nonceAccount := testutil.NewRandomAccount(t)
+
+	rent, err := ledger.GetMinimumBalanceForRentExemption(system.NonceAccountSize)
+	require.NoError(t, err)
+
+	blockhash, err := ledger.GetLatestBlockhash()
+	require.NoError(t, err)
+
+	txn := solana.NewTransaction(
+		e.subsidizer.PublicKey().ToBytes(),
+		system.CreateAccount(e.subsidizer.PublicKey().ToBytes(), nonceAccount.PublicKey().ToBytes(), system.SystemAccount, rent, system.NonceAccountSize),
+		system.InitializeNonce(nonceAccount.PublicKey().ToBytes(), e.subsidizer.PublicKey().ToBytes()),
+	)
+	txn.SetBlockhash(blockhash)
+	require.NoError(t, txn.Sign(e.subsidizer.PrivateKey().ToBytes(), nonceAccount.PrivateKey().ToBytes()))
+	_, err = ledger.SubmitTransaction(txn, solana.CommitmentFinalized)
+	require.NoError(t, err)
+
+	accountInfo, err := ledger.GetAccountInfo(nonceAccount.PublicKey().ToBytes(), solana.CommitmentFinalized)
+	require.NoError(t, err)
+
+	var nonceAccountState system.NonceAccount
+	require.NoError(t, nonceAccountState.Unmarshal(accountInfo.Data))
+
+	var nonceBlockhash solana.Blockhash
+	copy(nonceBlockhash[:], nonceAccountState.Blockhash)
+	return nonceAccount, nonceBlockhash
+}
@@ -0,0 +1,209 @@
+package async_sharding
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	code_data "github.com/code-payments/code-server/pkg/code/data"
+	"github.com/code-payments/code-server/pkg/code/data/membership"
+	code_sync "github.com/code-payments/code-server/pkg/sync"
+)
+
+const (
+	// ringReplicationFactor is the number of entries each member has in the
+	// consistent hash ring
+	ringReplicationFactor = 200
+
+	// heartbeatTtlMultiplier is the number of heartbeat intervals a member can
+	// miss before it's no longer considered part of the group
+	heartbeatTtlMultiplier = 3
+)
+
+// Membership registers a node as a member of a group of workers, and shards
+// keys across the group's active members using a consistent hash ring. Each
+// worker in the group only processes the records whose keys it owns.
+//
+// Membership implements async.Service, where the interval is how often the
+// node heartbeats and refreshes the group's membership. The node owns no keys
+// until it has successfully joined the group, nor after it has failed to
+// heartbeat for long enough that other members consider it gone.
+//
+// Members refresh independently, so their views of the ring can briefly differ.
+// Keys a node gains in a membership change are only owned after a grace period
+// of one heartbeat TTL, by which point the previous owner has either observed
+// the change or stopped owning keys altogether. Keys a node loses are released
+// immediately. This guarantees a key has a single owner at any point in time,
+// but a node that takes longer than the grace period to process a record may
+// still overlap with the next owner, so record updates must remain conditional
+// on the state that was read.
+type Membership struct {
+	log    *logrus.Entry
+	data   code_data.Provider
+	group  string
+	nodeId string
+
+	mu              sync.RWMutex
+	ttl             time.Duration
+	lastHeartbeatAt time.Time
+	members         []string
+	ring            *code_sync.HashRing
+
+	// Until the grace period ends, only keys owned in both the stable ring and
+	// the current ring are owned
+	stableRing  *code_sync.HashRing
+	graceEndsAt time.Time
+}
+
+// NewMembership returns a new Membership for a node in a group of workers. The
+// node ID must be unique across all processes in the group, like a hostname.
+func NewMembership(data code_data.Provider, group, nodeId string) *Membership {
+	return &Membership{
+		log: logrus.StandardLogger().WithFields(logrus.Fields{
+			"type":  "async/sharding/membership",
+			"group": group,
+			"node":  nodeId,
+		}),
+		data:       data,
+		group:      group,
+		nodeId:     nodeId,
+		ring:       code_sync.NewHashRing(nil, ringReplicationFactor),
+		stableRing: code_sync.NewHashRing(nil, ringReplicationFactor),
+	}
+}
+
+// Start heartbeats and refreshes the group's membership until the context is
+// cancelled, at which point the node leaves the group.
+func (m *Membership) Start(ctx context.Context, interval time.Duration) error {
+	defer func() {
+		err := m.leave(context.Background())
+		if err != nil && err != membership.ErrMemberNotFound {
+			m.log.WithError(err).Warn("failure leaving group")
+		}
+	}()
+
+	for {
+		err := m.refresh(ctx, heartbeatTtlMultiplier*interval)
+		if err != nil {
+			m.log.WithError(err).Warn("failure refreshing group membership")
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// Owns returns whether the node owns the key. A nil Membership owns all keys,
+// which allows workers to run unsharded.
+func (m *Membership) Owns(key string) bool {
+	if m == nil {
+		return true
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	now := time.Now()
+	if m.lastHeartbeatAt.IsZero() || now.Sub(m.lastHeartbeatAt) > m.ttl {
+		return false
+	}
+
+	if m.ring.Owner([]byte(key)) != m.nodeId {
+		return false
+	}
+
+	if now.Before(m.graceEndsAt) {
+		return m.stableRing.Owner([]byte(key)) == m.nodeId
+	}
+	return true
+}
+
+// Members returns the node IDs of the group's active members, as of the last
+// refresh, in ascending order
+func (m *Membership) Members() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	res := make([]string, len(m.members))
+	copy(res, m.members)
+	return res
+}
+
+// refresh heartbeats and rebuilds the ring when the group's membership changes
+func (m *Membership) refresh(ctx context.Context, ttl time.Duration) error {
+	heartbeatAt := time.Now()
+	err := m.data.HeartbeatWorkerMember(ctx, m.group, m.nodeId)
+	if err != nil {
+		return err
+	}
+
+	records, err := m.data.GetAllActiveWorkerMembers(ctx, m.group, heartbeatAt.Add(-ttl))
+	if err != nil && err != membership.ErrMemberNotFound {
+		return err
+	}
+
+	members := make([]string, len(records))
+	for i, record := range records {
+		members[i] = record.NodeId
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Other members may have taken over our keys while we weren't heartbeating,
+	// even if our view of the group hasn't changed
+	wasActive := !m.lastHeartbeatAt.IsZero() && heartbeatAt.Sub(m.lastHeartbeatAt) <= m.ttl
+	isChanged := !isSameMembership(m.members, members)
+
+	if !wasActive {
+		m.stableRing = code_sync.NewHashRing(nil, ringReplicationFactor)
+		m.graceEndsAt = heartbeatAt.Add(ttl)
+	} else if isChanged {
+		// A change during an ongoing grace period keeps the original stable
+		// ring, since keys gained in the previous change aren't owned yet
+		if !heartbeatAt.Before(m.graceEndsAt) {
+			m.stableRing = m.ring
+		}
+		m.graceEndsAt = heartbeatAt.Add(ttl)
+	}
+
+	m.ttl = ttl
+	m.lastHeartbeatAt = heartbeatAt
+
+	if isChanged {
+		m.log.WithField("members", members).Info("group membership changed")
+
+		m.members = members
+		m.ring = code_sync.NewHashRing(members, ringReplicationFactor)
+	}
+
+	return nil
+}
+
+// leave removes the node from the group, so other members can take over its
+// keys without waiting for its heartbeat to expire
+func (m *Membership) leave(ctx context.Context) error {
+	m.mu.Lock()
+	m.lastHeartbeatAt = time.Time{}
+	m.mu.Unlock()
+
+	return m.data.RemoveWorkerMember(ctx, m.group, m.nodeId)
+}
+
+func isSameMembership(members1, members2 []string) bool {
+	if len(members1) != len(members2) {
+		return false
+	}
+
+	for i := range members1 {
+		if members1[i] != members2[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,256 @@
+package async_sharding
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	datatest "github.com/code-payments/code-server/pkg/code/data/test"
+)
+
+const (
+	testGroup = "test"
+	testTtl   = time.Minute
+)
+
+func TestMembership_RingHandoff(t *testing.T) {
+	ctx := context.Background()
+	data := datatest.NewDataProvider()
+
+	var members []*Membership
+	for i := 0; i < 3; i++ {
+		members = append(members, NewMembership(data, testGroup, fmt.Sprintf("node%d", i)))
+	}
+
+	// Members own nothing until they've joined
+	for _, m := range members {
+		assert.False(t, m.Owns("key"))
+	}
+
+	refreshAll(t, members...)
+	for _, m := range members {
+		assert.Equal(t, []string{"node0", "node1", "node2"}, m.Members())
+	}
+
+	// Members that just joined wait out the grace period before owning keys
+	for _, m := range members {
+		assert.False(t, m.Owns("key"))
+	}
+
+	expireGracePeriods(members...)
+
+	initialOwners := assertSingleOwner(t, members...)
+	assertAllOwnSomething(t, initialOwners, members...)
+
+	// A member leaves, and its keys are handed off to the remaining members
+	require.NoError(t, members[1].leave(ctx))
+	assert.False(t, members[1].Owns("key"))
+
+	remaining := []*Membership{members[0], members[2]}
+	refreshAll(t, remaining...)
+	for _, m := range remaining {
+		assert.Equal(t, []string{"node0", "node2"}, m.Members())
+	}
+
+	// Keys are handed off once the grace period ends
+	assertAtMostOneOwner(t, remaining...)
+	expireGracePeriods(remaining...)
+
+	owners := assertSingleOwner(t, remaining...)
+	for key, owner := range initialOwners {
+		if owner != "node1" {
+			assert.Equal(t, owner, owners[key])
+		}
+	}
+
+	// A new member joins, and only takes over keys from existing members
+	joined := NewMembership(data, testGroup, "node3")
+	members = append(remaining, joined)
+	refreshAll(t, members...)
+	for _, m := range members {
+		assert.Equal(t, []string{"node0", "node2", "node3"}, m.Members())
+	}
+
+	assertAtMostOneOwner(t, members...)
+	expireGracePeriods(members...)
+
+	newOwners := assertSingleOwner(t, members...)
+	assertAllOwnSomething(t, newOwners, members...)
+	for key, owner := range newOwners {
+		if owner != "node3" {
+			assert.Equal(t, owners[key], owner)
+		}
+	}
+}
+
+func TestMembership_ExpiredHeartbeat(t *testing.T) {
+	ctx := context.Background()
+	data := datatest.NewDataProvider()
+
+	m1 := NewMembership(data, testGroup, "node1")
+	m2 := NewMembership(data, testGroup, "node2")
+	refreshAll(t, m1, m2)
+
+	// Members with expired heartbeats are excluded from the ring
+	time.Sleep(50 * time.Millisecond)
+	require.NoError(t, m1.refresh(ctx, 25*time.Millisecond))
+	assert.Equal(t, []string{"node1"}, m1.Members())
+	expireGracePeriods(m1)
+	for i := 0; i < 100; i++ {
+		assert.True(t, m1.Owns(fmt.Sprintf("key%d", i)))
+	}
+
+	// Members stop owning keys when they've failed to heartbeat for too long
+	m1.mu.Lock()
+	m1.lastHeartbeatAt = time.Now().Add(-time.Second)
+	m1.mu.Unlock()
+	for i := 0; i < 100; i++ {
+		assert.False(t, m1.Owns(fmt.Sprintf("key%d", i)))
+	}
+}
+
+func TestMembership_OutOfStepRefreshes(t *testing.T) {
+	data := datatest.NewDataProvider()
+
+	m0 := NewMembership(data, testGroup, "node0")
+	m1 := NewMembership(data, testGroup, "node1")
+	refreshAll(t, m0, m1)
+	expireGracePeriods(m0, m1)
+	initialOwners := assertSingleOwner(t, m0, m1)
+
+	// A new member joins, but only node0 has observed it. node1 still uses the
+	// previous ring, and continues to own keys that will be handed off to node2.
+	m2 := NewMembership(data, testGroup, "node2")
+	require.NoError(t, m2.refresh(context.Background(), testTtl))
+	require.NoError(t, m0.refresh(context.Background(), testTtl))
+	assert.Equal(t, []string{"node0", "node1", "node2"}, m0.Members())
+	assert.Equal(t, []string{"node0", "node1", "node2"}, m2.Members())
+	assert.Equal(t, []string{"node0", "node1"}, m1.Members())
+
+	assertAtMostOneOwner(t, m0, m1, m2)
+	for key, owner := range initialOwners {
+		assert.False(t, m2.Owns(key))
+		if owner == "node1" {
+			assert.True(t, m1.Owns(key))
+		}
+	}
+
+	// node1 eventually observes the new member and releases its keys right away
+	require.NoError(t, m1.refresh(context.Background(), testTtl))
+	assertAtMostOneOwner(t, m0, m1, m2)
+
+	// A further change during the grace period doesn't shorten it for keys
+	// gained in the first change
+	require.NoError(t, m1.leave(context.Background()))
+	require.NoError(t, m0.refresh(context.Background(), testTtl))
+	require.NoError(t, m2.refresh(context.Background(), testTtl))
+	for key, owner := range initialOwners {
+		if owner == "node1" {
+			assert.False(t, m0.Owns(key))
+			assert.False(t, m2.Owns(key))
+		}
+	}
+	assertAtMostOneOwner(t, m0, m1, m2)
+
+	expireGracePeriods(m0, m2)
+	assertSingleOwner(t, m0, m2)
+}
+
+func TestMembership_Start(t *testing.T) {
+	data := datatest.NewDataProvider()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m := NewMembership(data, testGroup, "node1")
+
+	done := make(chan error)
+	go func() {
+		done <- m.Start(ctx, 10*time.Millisecond)
+	}()
+
+	require.Eventually(t, func() bool {
+		return m.Owns("key")
+	}, time.Second, 10*time.Millisecond)
+
+	records, err := data.GetAllActiveWorkerMembers(context.Background(), testGroup, time.Now().Add(-testTtl))
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "node1", records[0].NodeId)
+
+	// Stopping leaves the group
+	cancel()
+	assert.Equal(t, context.Canceled, <-done)
+	assert.False(t, m.Owns("key"))
+
+	_, err = data.GetAllActiveWorkerMembers(context.Background(), testGroup, time.Now().Add(-testTtl))
+	assert.Error(t, err)
+}
+
+func TestMembership_Nil(t *testing.T) {
+	var m *Membership
+	assert.True(t, m.Owns("key"))
+}
+
+// refreshAll refreshes every member twice, so each observes the heartbeats of
+// the others
+func refreshAll(t *testing.T, members ...*Membership) {
+	for i := 0; i < 2; i++ {
+		for _, m := range members {
+			require.NoError(t, m.refresh(context.Background(), testTtl))
+		}
+	}
+}
+
+// expireGracePeriods simulates the grace period passing for each member
+func expireGracePeriods(members ...*Membership) {
+	for _, m := range members {
+		m.mu.Lock()
+		m.graceEndsAt = time.Time{}
+		m.mu.Unlock()
+	}
+}
+
+func assertAtMostOneOwner(t *testing.T, members ...*Membership) {
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprintf("key%d", i)
+
+		var ownerCount int
+		for _, m := range members {
+			if m.Owns(key) {
+				ownerCount++
+			}
+		}
+		require.LessOrEqual(t, ownerCount, 1)
+	}
+}
+
+func assertSingleOwner(t *testing.T, members ...*Membership) map[string]string {
+	owners := make(map[string]string)
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprintf("key%d", i)
+
+		var ownerCount int
+		for _, m := range members {
+			if m.Owns(key) {
+				ownerCount++
+				owners[key] = m.nodeId
+			}
+		}
+		require.Equal(t, 1, ownerCount)
+	}
+	return owners
+}
+
+func assertAllOwnSomething(t *testing.T, owners map[string]string, members ...*Membership) {
+	counts := make(map[string]int)
+	for _, owner := range owners {
+		counts[owner]++
+	}
+
+	for _, m := range members {
+		assert.True(t, counts[m.nodeId] > 0)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/code-payments/code-server/pkg/config/env"
 	"github.com/code-payments/code-server/pkg/config/memory"
 	"github.com/code-payments/code-server/pkg/config/wrapper"
+	"github.com/code-payments/code-server/pkg/kin"
 )
 
 const (
@@ -17,11 +18,32 @@ const (
 
 	AdvanceCollectionTimeoutConfigEnvName = envConfigPrefix + "ADVANCE_COLLECTION_TIMEOUT"
 	defaultAdvanceCollectionTimeout       = 24 * time.Hour
+
+	EnableFundingConfigEnvName = envConfigPrefix + "ENABLE_FUNDING"
+	defaultEnableFunding       = false
+
+	FundingHotWalletOwnerPublicKeyConfigEnvName = envConfigPrefix + "FUNDING_HOT_WALLET_OWNER_PUBLIC_KEY"
+	defaultFundingHotWalletOwnerPublicKey       = ""
+
+	MinFundingLevelConfigEnvName = envConfigPrefix + "MIN_FUNDING_LEVEL" // In quarks
+	defaultMinFundingLevel       = 1_000_000 * kin.QuarksPerKin
+
+	TargetFundingLevelConfigEnvName = envConfigPrefix + "TARGET_FUNDING_LEVEL" // In quarks
+	defaultTargetFundingLevel       = 5_000_000 * kin.QuarksPerKin
+
+	DailyFundingCapConfigEnvName = envConfigPrefix + "DAILY_FUNDING_CAP" // In quarks
+	defaultDailyFundingCap       = 10_000_000 * kin.QuarksPerKin
 )
 
 type conf struct {
 	hideInCrowdPrivacyLevel  config.Uint64
 	advanceCollectionTimeout config.Duration
+
+	enableFunding                  config.Bool
+	fundingHotWalletOwnerPublicKey config.String
+	minFundingLevel                config.Uint64
+	targetFundingLevel             config.Uint64
+	dailyFundingCap                config.Uint64
 }
 
 // ConfigProvider defines how config values are pulled
@@ -33,6 +55,12 @@ func WithEnvConfigs() ConfigProvider {
 		return &conf{
 			hideInCrowdPrivacyLevel:  env.NewUint64Config(HideInCrowdPrivacyLevelConfigEnvName, defaultHideInCrowdPrivacylevel),
 			advanceCollectionTimeout: env.NewDurationConfig(AdvanceCollectionTimeoutConfigEnvName, defaultAdvanceCollectionTimeout),
+
+			enableFunding:                  env.NewBoolConfig(EnableFundingConfigEnvName, defaultEnableFunding),
+			fundingHotWalletOwnerPublicKey: env.NewStringConfig(FundingHotWalletOwnerPublicKeyConfigEnvName, defaultFundingHotWalletOwnerPublicKey),
+			minFundingLevel:                env.NewUint64Config(MinFundingLevelConfigEnvName, defaultMinFundingLevel),
+			targetFundingLevel:             env.NewUint64Config(TargetFundingLevelConfigEnvName, defaultTargetFundingLevel),
+			dailyFundingCap:                env.NewUint64Config(DailyFundingCapConfigEnvName, defaultDailyFundingCap),
 		}
 	}
 }
@@ -40,6 +68,12 @@ func WithEnvConfigs() ConfigProvider {
 type testOverrides struct {
 	hideInTheCrowdPrivacyLevel uint64
 	advanceCollectionTimeout   time.Duration
+
+	enableFunding                  bool
+	fundingHotWalletOwnerPublicKey string
+	minFundingLevel                uint64
+	targetFundingLevel             uint64
+	dailyFundingCap                uint64
 }
 
 func withManualTestOverrides(overrides *testOverrides) ConfigProvider {
@@ -51,10 +85,28 @@ func withManualTestOverrides(overrides *testOverrides) ConfigProvider {
 		overrides.advanceCollectionTimeout = defaultAdvanceCollectionTimeout
 	}
 
+	if overrides.minFundingLevel == 0 {
+		overrides.minFundingLevel = defaultMinFundingLevel
+	}
+
+	if overrides.targetFundingLevel == 0 {
+		overrides.targetFundingLevel = defaultTargetFundingLevel
+	}
+
+	if overrides.dailyFundingCap == 0 {
+		overrides.dailyFundingCap = defaultDailyFundingCap
+	}
+
 	return func() *conf {
 		return &conf{
 			hideInCrowdPrivacyLevel:  wrapper.NewUint64Config(memory.NewConfig(overrides.hideInTheCrowdPrivacyLevel), defaultHideInCrowdPrivacylevel),
 			advanceCollectionTimeout: wrapper.NewDurationConfig(memory.NewConfig(overrides.advanceCollectionTimeout), defaultAdvanceCollectionTimeout),
+
+			enableFunding:                  wrapper.NewBoolConfig(memory.NewConfig(overrides.enableFunding), defaultEnableFunding),
+			fundingHotWalletOwnerPublicKey: wrapper.NewStringConfig(memory.NewConfig(overrides.fundingHotWalletOwnerPublicKey), defaultFundingHotWalletOwnerPublicKey),
+			minFundingLevel:                wrapper.NewUint64Config(memory.NewConfig(overrides.minFundingLevel), defaultMinFundingLevel),
+			targetFundingLevel:             wrapper.NewUint64Config(memory.NewConfig(overrides.targetFundingLevel), defaultTargetFundingLevel),
+			dailyFundingCap:                wrapper.NewUint64Config(memory.NewConfig(overrides.dailyFundingCap), defaultDailyFundingCap),
 		}
 	}
 }
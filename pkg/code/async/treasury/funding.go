@@ -0,0 +1,394 @@
+package async_treasury
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/mr-tron/base58"
+	"github.com/newrelic/go-agent/v3/newrelic"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/code-payments/code-server/pkg/kin"
+	"github.com/code-payments/code-server/pkg/metrics"
+	"github.com/code-payments/code-server/pkg/pointer"
+	"github.com/code-payments/code-server/pkg/solana"
+	"github.com/code-payments/code-server/pkg/solana/token"
+	"github.com/code-payments/code-server/pkg/code/common"
+	"github.com/code-payments/code-server/pkg/code/data/action"
+	"github.com/code-payments/code-server/pkg/code/data/fulfillment"
+	"github.com/code-payments/code-server/pkg/code/data/intent"
+	"github.com/code-payments/code-server/pkg/code/data/nonce"
+	"github.com/code-payments/code-server/pkg/code/data/treasury"
+	"github.com/code-payments/code-server/pkg/code/transaction"
+)
+
+const (
+	fundingCapWindow = 24 * time.Hour
+)
+
+func (p *service) fundingWorker(serviceCtx context.Context, interval time.Duration) error {
+	delay := interval
+
+	for {
+		select {
+		case <-serviceCtx.Done():
+			return serviceCtx.Err()
+		case <-time.After(delay):
+			start := time.Now()
+
+			func() {
+				nr := serviceCtx.Value(metrics.NewRelicContextKey).(*newrelic.Application)
+				m := nr.StartTransaction("async__treasury_pool_service__handle_funding")
+				defer m.End()
+				tracedCtx := newrelic.NewContext(serviceCtx, m)
+
+				treasuryPoolRecords, err := p.data.GetAllTreasuryPoolsByState(tracedCtx, treasury.TreasuryPoolStateAvailable)
+				if err != nil && err != treasury.ErrTreasuryPoolNotFound {
+					m.NoticeError(err)
+					return
+				}
+
+				for _, treasuryPoolRecord := range treasuryPoolRecords {
+					if !p.membership.Owns(treasuryPoolRecord.Address) {
+						continue
+					}
+
+					err := p.maybeFundTreasuryPool(tracedCtx, treasuryPoolRecord)
+					if err != nil {
+						m.NoticeError(err)
+					}
+				}
+			}()
+
+			delay = interval - time.Since(start)
+		}
+	}
+}
+
+// maybeFundTreasuryPool tops up a treasury pool from the configured hot wallet
+// when its available funds drop below the minimum funding level. The amount
+// transferred brings the pool back up to the target funding level, and the total
+// amount funded across all pools over a rolling day is limited by a hard cap.
+//
+// At most one funding can be in flight per pool. The sequencer updates the state
+// of the funding history record when the transfer is finalized or fails.
+func (p *service) maybeFundTreasuryPool(ctx context.Context, treasuryPoolRecord *treasury.Record) error {
+	log := p.log.WithFields(logrus.Fields{
+		"method":   "maybeFundTreasuryPool",
+		"treasury": treasuryPoolRecord.Name,
+	})
+
+	if !p.conf.enableFunding.Get(ctx) {
+		return nil
+	}
+
+	minFundingLevel := p.conf.minFundingLevel.Get(ctx)
+	targetFundingLevel := p.conf.targetFundingLevel.Get(ctx)
+	if targetFundingLevel <= minFundingLevel {
+		log.Warn("target funding level must be greater than the minimum funding level")
+		return nil
+	}
+
+	treasuryPoolLock.Lock()
+	defer treasuryPoolLock.Unlock()
+
+	// Wait for any in flight funding to complete, so we have an accurate view
+	// of available funds
+	for _, state := range []treasury.FundingState{
+		treasury.FundingStateUnknown,
+		treasury.FundingStatePending,
+	} {
+		count, err := p.data.GetTreasuryPoolFundingCountByVaultAndState(ctx, treasuryPoolRecord.Vault, state)
+		if err != nil {
+			log.WithError(err).Warn("failure getting in flight funding count")
+			return err
+		}
+
+		if count > 0 {
+			log.Trace("treasury pool has funding in flight")
+			return nil
+		}
+	}
+
+	total, used, err := estimateTreasuryPoolFundingLevels(ctx, p.data, treasuryPoolRecord)
+	if err != nil {
+		log.WithError(err).Warn("failure estimating treasury pool funding levels")
+		return err
+	}
+
+	var available uint64
+	if used < total {
+		available = total - used
+	}
+
+	log = log.WithField("available", available)
+
+	if available >= minFundingLevel {
+		return nil
+	}
+
+	quarks := targetFundingLevel - available
+
+	fundedInWindow, err := p.getAmountFundedSince(ctx, time.Now().Add(-fundingCapWindow))
+	if err != nil {
+		log.WithError(err).Warn("failure getting amount funded within cap window")
+		return err
+	}
+
+	dailyFundingCap := p.conf.dailyFundingCap.Get(ctx)
+	if fundedInWindow >= dailyFundingCap {
+		log.Warn("daily funding cap reached")
+		recordFundingCapReachedEvent(ctx, treasuryPoolRecord.Name, quarks)
+		return nil
+	}
+
+	if fundedInWindow+quarks > dailyFundingCap {
+		log.Warn("funding amount reduced due to daily funding cap")
+		recordFundingCapReachedEvent(ctx, treasuryPoolRecord.Name, fundedInWindow+quarks-dailyFundingCap)
+		quarks = dailyFundingCap - fundedInWindow
+	}
+
+	log = log.WithField("quarks", quarks)
+
+	hotWallet, err := p.getFundingHotWallet(ctx)
+	if err != nil {
+		log.WithError(err).Warn("failure loading funding hot wallet")
+		return err
+	}
+
+	hotWalletAta, err := hotWallet.ToAssociatedTokenAccount()
+	if err != nil {
+		return err
+	}
+
+	treasuryPoolVault, err := common.NewAccountFromPublicKeyString(treasuryPoolRecord.Vault)
+	if err != nil {
+		return err
+	}
+
+	log.Trace("initiating process to fund treasury pool")
+
+	// Maintaining parity with how clients generate intent IDs
+	intentId, err := common.NewRandomAccount()
+	if err != nil {
+		return err
+	}
+
+	intentRecord := &intent.Record{
+		IntentId:   intentId.PublicKey().ToBase58(),
+		IntentType: intent.TreasuryPoolFunding,
+
+		InitiatorOwnerAccount: hotWallet.PublicKey().ToBase58(),
+
+		// Treasury pools only hold Kin
+		Mint: kin.Mint,
+
+		TreasuryPoolFundingMetadata: &intent.TreasuryPoolFundingMetadata{
+			TreasuryPool: treasuryPoolRecord.Address,
+			Source:       hotWalletAta.PublicKey().ToBase58(),
+			Quantity:     quarks,
+		},
+
+		State: intent.StateUnknown,
+	}
+
+	actionRecord := &action.Record{
+		Intent:     intentRecord.IntentId,
+		IntentType: intentRecord.IntentType,
+
+		ActionId:   0,
+		ActionType: action.TreasuryPoolFunding,
+
+		Source:      hotWalletAta.PublicKey().ToBase58(),
+		Destination: pointer.String(treasuryPoolRecord.Vault),
+		Quantity:    pointer.Uint64(quarks),
+
+		State: action.StatePending,
+	}
+
+	selectedNonce, err := transaction.SelectAvailableNonce(ctx, p.data, nonce.PurposeInternalServerProcess)
+	if err != nil {
+		log.WithError(err).Warn("failure selecting available nonce")
+		return err
+	}
+	defer selectedNonce.Unlock()
+
+	txn, err := makeTreasuryPoolFundingTransaction(selectedNonce, hotWallet, hotWalletAta, treasuryPoolVault, quarks)
+	if err != nil {
+		log.WithError(err).Warn("failure creating transaction")
+		return err
+	}
+
+	fulfillmentRecord := &fulfillment.Record{
+		Intent:     intentRecord.IntentId,
+		IntentType: intentRecord.IntentType,
+
+		ActionId:   actionRecord.ActionId,
+		ActionType: actionRecord.ActionType,
+
+		FulfillmentType: fulfillment.TransferToTreasuryPool,
+		Data:            txn.Marshal(),
+		Signature:       pointer.String(base58.Encode(txn.Signature())),
+
+		Nonce:     pointer.String(selectedNonce.Account.PublicKey().ToBase58()),
+		Blockhash: pointer.String(base58.Encode(selectedNonce.Blockhash[:])),
+
+		Source:      hotWalletAta.PublicKey().ToBase58(),
+		Destination: pointer.String(treasuryPoolRecord.Vault),
+
+		// IntentOrderingIndex unknown until intent record is saved
+		ActionOrderingIndex:      0,
+		FulfillmentOrderingIndex: 0,
+
+		State: fulfillment.StateUnknown,
+
+		CreatedAt: time.Now(),
+	}
+
+	fundingRecord := &treasury.FundingHistoryRecord{
+		Vault:         treasuryPoolRecord.Vault,
+		DeltaQuarks:   int64(quarks),
+		TransactionId: *fulfillmentRecord.Signature,
+		State:         treasury.FundingStatePending,
+		CreatedAt:     fulfillmentRecord.CreatedAt,
+	}
+
+	// Create the intent and funding history in one DB transaction
+	err = p.data.ExecuteInTx(ctx, sql.LevelDefault, func(ctx context.Context) error {
+		err = p.data.SaveIntent(ctx, intentRecord)
+		if err != nil {
+			log.WithError(err).Warn("failure saving intent record")
+			return err
+		}
+
+		err = p.data.PutAllActions(ctx, actionRecord)
+		if err != nil {
+			log.WithError(err).Warn("failure saving action record")
+			return err
+		}
+
+		fulfillmentRecord.IntentOrderingIndex = intentRecord.Id // Unknown until intent record is saved
+		err = p.data.PutAllFulfillments(ctx, fulfillmentRecord)
+		if err != nil {
+			log.WithError(err).Warn("failure saving fulfillment record")
+			return err
+		}
+
+		err = p.data.SaveTreasuryPoolFunding(ctx, fundingRecord)
+		if err != nil {
+			log.WithError(err).Warn("failure saving funding history record")
+			return err
+		}
+
+		err = selectedNonce.MarkReservedWithSignature(ctx, *fulfillmentRecord.Signature)
+		if err != nil {
+			log.WithError(err).Warn("failure marking nonce reserved with signature")
+			return err
+		}
+
+		// Intent is pending only after everything's been saved.
+		intentRecord.State = intent.StatePending
+		err = p.data.SaveIntent(ctx, intentRecord)
+		if err != nil {
+			log.WithError(err).Warn("failure marking intent as pending")
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Infof("created intent to fund treasury pool with %d kin", kin.FromQuarks(quarks))
+	recordFundingIntentCreatedEvent(ctx, treasuryPoolRecord.Name, quarks)
+
+	return nil
+}
+
+// getAmountFundedSince returns the total amount of funding, across all treasury
+// pools, that hasn't failed since the provided time
+func (p *service) getAmountFundedSince(ctx context.Context, since time.Time) (uint64, error) {
+	fundingRecords, err := p.data.GetAllTreasuryPoolFundingCreatedAfter(ctx, since)
+	if err == treasury.ErrFundingNotFound {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+
+	var res uint64
+	for _, fundingRecord := range fundingRecords {
+		if fundingRecord.DeltaQuarks <= 0 || fundingRecord.State == treasury.FundingStateFailed {
+			continue
+		}
+		res += uint64(fundingRecord.DeltaQuarks)
+	}
+	return res, nil
+}
+
+func (p *service) getFundingHotWallet(ctx context.Context) (*common.Account, error) {
+	publicKey := p.conf.fundingHotWalletOwnerPublicKey.Get(ctx)
+	if len(publicKey) == 0 {
+		return nil, errors.New("funding hot wallet isn't configured")
+	}
+
+	p.fundingHotWalletMu.Lock()
+	defer p.fundingHotWalletMu.Unlock()
+
+	if p.fundingHotWallet != nil && p.fundingHotWallet.PublicKey().ToBase58() == publicKey {
+		return p.fundingHotWallet, nil
+	}
+
+	vaultRecord, err := p.data.GetKey(ctx, publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := common.NewAccountFromPrivateKeyString(vaultRecord.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if account.PublicKey().ToBase58() != publicKey {
+		return nil, errors.New("funding hot wallet public key mismatch")
+	}
+
+	p.fundingHotWallet = account
+	return account, nil
+}
+
+func makeTreasuryPoolFundingTransaction(
+	selectedNonce *transaction.SelectedNonce,
+	hotWallet *common.Account,
+	hotWalletAta *common.Account,
+	treasuryPoolVault *common.Account,
+	quarks uint64,
+) (solana.Transaction, error) {
+	transferInstruction := token.Transfer(
+		hotWalletAta.PublicKey().ToBytes(),
+		treasuryPoolVault.PublicKey().ToBytes(),
+		hotWallet.PublicKey().ToBytes(),
+		quarks,
+	)
+
+	// Always use a nonce, so we never accidentally fund the pool more than once
+	// for a single intent
+	txn, err := transaction.MakeNoncedTransaction(
+		selectedNonce.Account,
+		selectedNonce.Blockhash,
+		transferInstruction,
+	)
+	if err != nil {
+		return solana.Transaction{}, err
+	}
+
+	err = txn.Sign(
+		common.GetSubsidizer().PrivateKey().ToBytes(),
+		hotWallet.PrivateKey().ToBytes(),
+	)
+	if err != nil {
+		return solana.Transaction{}, err
+	}
+
+	return txn, nil
+}
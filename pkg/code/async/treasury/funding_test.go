@@ -0,0 +1,119 @@
+package async_treasury
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/code-payments/code-server/pkg/kin"
+	"github.com/code-payments/code-server/pkg/testutil"
+	"github.com/code-payments/code-server/pkg/code/common"
+	"github.com/code-payments/code-server/pkg/code/data/commitment"
+	"github.com/code-payments/code-server/pkg/code/data/treasury"
+	"github.com/code-payments/code-server/pkg/code/data/vault"
+)
+
+func TestMaybeFundTreasuryPool_HappyPath(t *testing.T) {
+	env, hotWallet := setupFundingEnv(t, true, kin.ToQuarks(1000))
+	env.generateAvailableNonces(t, 2)
+
+	env.simulateTreasuryPoolFunding(t, kin.ToQuarks(20), treasury.FundingStateConfirmed)
+
+	// Available funds are above the minimum level
+	require.NoError(t, env.worker.maybeFundTreasuryPool(env.ctx, env.treasuryPool))
+	env.assertFundingIntentCount(t, hotWallet, 0)
+
+	// Available funds drop below the minimum level
+	env.simulateCommitments(t, 15, env.treasuryPool.GetMostRecentRoot(), commitment.StateReadyToOpen)
+
+	require.NoError(t, env.worker.maybeFundTreasuryPool(env.ctx, env.treasuryPool))
+	intentRecords := env.assertFundingIntentCount(t, hotWallet, 1)
+	env.assertFundingIntentCreated(t, intentRecords[0], hotWallet, kin.ToQuarks(45))
+
+	// Funding is in flight
+	require.NoError(t, env.worker.maybeFundTreasuryPool(env.ctx, env.treasuryPool))
+	env.assertFundingIntentCount(t, hotWallet, 1)
+
+	// Funding is confirmed, and the pool is at the target level
+	env.simulateFundingIntentOutcome(t, intentRecords[0], treasury.FundingStateConfirmed)
+
+	require.NoError(t, env.worker.maybeFundTreasuryPool(env.ctx, env.treasuryPool))
+	env.assertFundingIntentCount(t, hotWallet, 1)
+}
+
+func TestMaybeFundTreasuryPool_FailedFundingIsRetried(t *testing.T) {
+	env, hotWallet := setupFundingEnv(t, true, kin.ToQuarks(1000))
+	env.generateAvailableNonces(t, 2)
+
+	require.NoError(t, env.worker.maybeFundTreasuryPool(env.ctx, env.treasuryPool))
+	intentRecords := env.assertFundingIntentCount(t, hotWallet, 1)
+	env.assertFundingIntentCreated(t, intentRecords[0], hotWallet, kin.ToQuarks(50))
+
+	env.simulateFundingIntentOutcome(t, intentRecords[0], treasury.FundingStateFailed)
+
+	require.NoError(t, env.worker.maybeFundTreasuryPool(env.ctx, env.treasuryPool))
+	env.assertFundingIntentCount(t, hotWallet, 2)
+}
+
+func TestMaybeFundTreasuryPool_DailyCap(t *testing.T) {
+	env, hotWallet := setupFundingEnv(t, true, kin.ToQuarks(60))
+	env.generateAvailableNonces(t, 3)
+
+	// Funding from outside the cap window doesn't count towards the cap
+	require.NoError(t, env.data.SaveTreasuryPoolFunding(env.ctx, &treasury.FundingHistoryRecord{
+		Vault:         env.treasuryPool.Vault,
+		DeltaQuarks:   int64(kin.ToQuarks(100)),
+		TransactionId: "txn",
+		State:         treasury.FundingStateConfirmed,
+		CreatedAt:     time.Now().Add(-fundingCapWindow - time.Minute),
+	}))
+	env.simulateCommitments(t, 100, env.treasuryPool.GetMostRecentRoot(), commitment.StateReadyToOpen)
+
+	require.NoError(t, env.worker.maybeFundTreasuryPool(env.ctx, env.treasuryPool))
+	intentRecords := env.assertFundingIntentCount(t, hotWallet, 1)
+	env.assertFundingIntentCreated(t, intentRecords[0], hotWallet, kin.ToQuarks(50))
+
+	env.simulateFundingIntentOutcome(t, intentRecords[0], treasury.FundingStateConfirmed)
+	env.simulateCommitments(t, 50, env.treasuryPool.GetMostRecentRoot(), commitment.StateReadyToOpen)
+
+	// Funding amount is reduced to what remains under the cap
+	require.NoError(t, env.worker.maybeFundTreasuryPool(env.ctx, env.treasuryPool))
+	intentRecords = env.assertFundingIntentCount(t, hotWallet, 2)
+	env.assertFundingIntentCreated(t, intentRecords[1], hotWallet, kin.ToQuarks(10))
+
+	env.simulateFundingIntentOutcome(t, intentRecords[1], treasury.FundingStateConfirmed)
+
+	// Cap is reached, so no more funding
+	require.NoError(t, env.worker.maybeFundTreasuryPool(env.ctx, env.treasuryPool))
+	env.assertFundingIntentCount(t, hotWallet, 2)
+}
+
+func TestMaybeFundTreasuryPool_Disabled(t *testing.T) {
+	env, hotWallet := setupFundingEnv(t, false, kin.ToQuarks(1000))
+	env.generateAvailableNonces(t, 1)
+
+	require.NoError(t, env.worker.maybeFundTreasuryPool(env.ctx, env.treasuryPool))
+	env.assertFundingIntentCount(t, hotWallet, 0)
+}
+
+func setupFundingEnv(t *testing.T, enabled bool, dailyCap uint64) (*testEnv, *common.Account) {
+	hotWallet := testutil.NewRandomAccount(t)
+
+	env := setup(t, &testOverrides{
+		enableFunding:                  enabled,
+		fundingHotWalletOwnerPublicKey: hotWallet.PublicKey().ToBase58(),
+		minFundingLevel:                kin.ToQuarks(10),
+		targetFundingLevel:             kin.ToQuarks(50),
+		dailyFundingCap:                dailyCap,
+	})
+
+	require.NoError(t, env.data.SaveKey(env.ctx, &vault.Record{
+		PublicKey:  hotWallet.PublicKey().ToBase58(),
+		PrivateKey: hotWallet.PrivateKey().ToBase58(),
+		State:      vault.StateAvailable,
+		CreatedAt:  time.Now(),
+	}))
+
+	return env, hotWallet
+}
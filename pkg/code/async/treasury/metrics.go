@@ -14,6 +14,8 @@ const (
 	treasuryFundCheckEventName       = "TreasuryFundPollingCheck"
 	recentRootIntentCreatedEventName = "RecentRootIntentCreated"
 	merkleTreeSyncedEventName        = "MerkleTreeSynced"
+	fundingIntentCreatedEventName    = "TreasuryFundingIntentCreated"
+	fundingCapReachedEventName       = "TreasuryFundingCapReached"
 )
 
 func (p *service) metricsGaugeWorker(ctx context.Context) error {
@@ -82,3 +84,17 @@ func recordMerkleTreeSyncedEvent(ctx context.Context, treasuryPoolName string) {
 		"treasury": treasuryPoolName,
 	})
 }
+
+func recordFundingIntentCreatedEvent(ctx context.Context, treasuryPoolName string, quarks uint64) {
+	metrics.RecordEvent(ctx, fundingIntentCreatedEventName, map[string]interface{}{
+		"treasury": treasuryPoolName,
+		"amount":   kin.FromQuarks(quarks),
+	})
+}
+
+func recordFundingCapReachedEvent(ctx context.Context, treasuryPoolName string, shortfall uint64) {
+	metrics.RecordEvent(ctx, fundingCapReachedEventName, map[string]interface{}{
+		"treasury":  treasuryPoolName,
+		"shortfall": kin.FromQuarks(shortfall),
+	})
+}
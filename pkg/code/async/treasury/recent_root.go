@@ -10,6 +10,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/code-payments/code-server/pkg/database/query"
+	"github.com/code-payments/code-server/pkg/kin"
 	"github.com/code-payments/code-server/pkg/pointer"
 	"github.com/code-payments/code-server/pkg/solana"
 	splitter_token "github.com/code-payments/code-server/pkg/solana/splitter"
@@ -164,6 +165,9 @@ func (p *service) maybeSaveRecentRoot(ctx context.Context, treasuryPoolRecord *t
 
 		InitiatorOwnerAccount: common.GetSubsidizer().PublicKey().ToBase58(),
 
+		// Treasury pools only hold Kin
+		Mint: kin.Mint,
+
 		SaveRecentRootMetadata: &intent.SaveRecentRootMetadata{
 			TreasuryPool:           treasuryPoolRecord.Address,
 			PreviousMostRecentRoot: treasuryPoolRecord.GetMostRecentRoot(),
@@ -2,26 +2,36 @@ package async_treasury
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/sirupsen/logrus"
 
 	"github.com/code-payments/code-server/pkg/code/async"
+	async_sharding "github.com/code-payments/code-server/pkg/code/async/sharding"
+	"github.com/code-payments/code-server/pkg/code/common"
 	code_data "github.com/code-payments/code-server/pkg/code/data"
 	"github.com/code-payments/code-server/pkg/code/data/treasury"
 )
 
 type service struct {
-	log  *logrus.Entry
-	conf *conf
-	data code_data.Provider
+	log        *logrus.Entry
+	conf       *conf
+	data       code_data.Provider
+	membership *async_sharding.Membership
+
+	fundingHotWalletMu sync.Mutex
+	fundingHotWallet   *common.Account
 }
 
-func New(data code_data.Provider, configProvider ConfigProvider) async.Service {
+// New returns a new treasury service. When a group membership is provided, each
+// treasury pool is managed and funded by a single member of the group.
+func New(data code_data.Provider, configProvider ConfigProvider, membership *async_sharding.Membership) async.Service {
 	return &service{
-		log:  logrus.StandardLogger().WithField("service", "treasury"),
-		conf: configProvider(),
-		data: data,
+		log:        logrus.StandardLogger().WithField("service", "treasury"),
+		conf:       configProvider(),
+		data:       data,
+		membership: membership,
 	}
 }
 
@@ -44,6 +54,13 @@ func (p *service) Start(ctx context.Context, interval time.Duration) error {
 		}(item)
 	}
 
+	go func() {
+		err := p.fundingWorker(ctx, interval)
+		if err != nil && err != context.Canceled {
+			p.log.WithError(err).Warn("treasury funding loop terminated unexpectedly")
+		}
+	}()
+
 	go func() {
 		err := p.metricsGaugeWorker(ctx)
 		if err != nil && err != context.Canceled {
@@ -16,11 +16,14 @@ import (
 	"github.com/code-payments/code-server/pkg/kin"
 	"github.com/code-payments/code-server/pkg/pointer"
 	"github.com/code-payments/code-server/pkg/solana"
+	"github.com/code-payments/code-server/pkg/solana/simulator"
 	splitter_token "github.com/code-payments/code-server/pkg/solana/splitter"
 	"github.com/code-payments/code-server/pkg/solana/system"
+	"github.com/code-payments/code-server/pkg/solana/token"
 	"github.com/code-payments/code-server/pkg/testutil"
 	"github.com/code-payments/code-server/pkg/code/common"
 	code_data "github.com/code-payments/code-server/pkg/code/data"
+	datatest "github.com/code-payments/code-server/pkg/code/data/test"
 	"github.com/code-payments/code-server/pkg/code/data/action"
 	"github.com/code-payments/code-server/pkg/code/data/commitment"
 	"github.com/code-payments/code-server/pkg/code/data/fulfillment"
@@ -36,6 +39,7 @@ import (
 type testEnv struct {
 	ctx          context.Context
 	data         code_data.Provider
+	ledger       *simulator.Ledger
 	treasuryPool *treasury.Record
 	merkleTree   *merkletree.MerkleTree
 	worker       *service
@@ -46,7 +50,8 @@ type testEnv struct {
 func setup(t *testing.T, testOverrides *testOverrides) *testEnv {
 	ctx := context.Background()
 
-	db := code_data.NewTestDataProvider()
+	ledger := simulator.NewLedger()
+	db := datatest.NewDataProviderWithLedger(ledger)
 
 	subsidizer := testutil.SetupRandomSubsidizer(t, db)
 
@@ -96,9 +101,10 @@ func setup(t *testing.T, testOverrides *testOverrides) *testEnv {
 	return &testEnv{
 		ctx:          ctx,
 		data:         db,
+		ledger:       ledger,
 		treasuryPool: treasuryPool,
 		merkleTree:   merkleTree,
-		worker:       New(db, withManualTestOverrides(testOverrides)).(*service),
+		worker:       New(db, withManualTestOverrides(testOverrides), nil).(*service),
 		subsidizer:   subsidizer,
 		nextBlock:    1,
 	}
@@ -447,6 +453,110 @@ func (e *testEnv) generateAvailableNonce(t *testing.T) *nonce.Record {
 	return nonceRecord
 }
 
+func (e *testEnv) simulateTreasuryPoolFunding(t *testing.T, quarks uint64, state treasury.FundingState) *treasury.FundingHistoryRecord {
+	fundingRecord := &treasury.FundingHistoryRecord{
+		Vault:         e.treasuryPool.Vault,
+		DeltaQuarks:   int64(quarks),
+		TransactionId: fmt.Sprintf("txn%d", rand.Uint64()),
+		State:         state,
+		CreatedAt:     time.Now(),
+	}
+	require.NoError(t, e.data.SaveTreasuryPoolFunding(e.ctx, fundingRecord))
+	return fundingRecord
+}
+
+func (e *testEnv) simulateFundingIntentOutcome(t *testing.T, intentRecord *intent.Record, state treasury.FundingState) {
+	fulfillmentRecords, err := e.data.GetAllFulfillmentsByIntent(e.ctx, intentRecord.IntentId)
+	require.NoError(t, err)
+	require.Len(t, fulfillmentRecords, 1)
+
+	fundingRecord := &treasury.FundingHistoryRecord{
+		Vault:         e.treasuryPool.Vault,
+		DeltaQuarks:   int64(intentRecord.TreasuryPoolFundingMetadata.Quantity),
+		TransactionId: *fulfillmentRecords[0].Signature,
+		State:         state,
+		CreatedAt:     fulfillmentRecords[0].CreatedAt,
+	}
+	require.NoError(t, e.data.SaveTreasuryPoolFunding(e.ctx, fundingRecord))
+}
+
+func (e *testEnv) assertFundingIntentCount(t *testing.T, hotWallet *common.Account, expected int) []*intent.Record {
+	intentRecords, err := e.data.GetAllIntentsByOwner(e.ctx, hotWallet.PublicKey().ToBase58())
+	if err == intent.ErrIntentNotFound {
+		assert.Equal(t, 0, expected)
+		return nil
+	}
+	require.NoError(t, err)
+	require.Len(t, intentRecords, expected)
+	return intentRecords
+}
+
+func (e *testEnv) assertFundingIntentCreated(t *testing.T, intentRecord *intent.Record, hotWallet *common.Account, expectedQuarks uint64) {
+	hotWalletAta, err := hotWallet.ToAssociatedTokenAccount()
+	require.NoError(t, err)
+
+	assert.Equal(t, intent.TreasuryPoolFunding, intentRecord.IntentType)
+	assert.Equal(t, kin.Mint, intentRecord.Mint)
+	assert.Equal(t, hotWallet.PublicKey().ToBase58(), intentRecord.InitiatorOwnerAccount)
+	assert.Equal(t, intent.StatePending, intentRecord.State)
+	require.NotNil(t, intentRecord.TreasuryPoolFundingMetadata)
+	assert.Equal(t, e.treasuryPool.Address, intentRecord.TreasuryPoolFundingMetadata.TreasuryPool)
+	assert.Equal(t, hotWalletAta.PublicKey().ToBase58(), intentRecord.TreasuryPoolFundingMetadata.Source)
+	assert.Equal(t, expectedQuarks, intentRecord.TreasuryPoolFundingMetadata.Quantity)
+
+	actionRecords, err := e.data.GetAllActionsByIntent(e.ctx, intentRecord.IntentId)
+	require.NoError(t, err)
+	require.Len(t, actionRecords, 1)
+	actionRecord := actionRecords[0]
+	assert.Equal(t, action.TreasuryPoolFunding, actionRecord.ActionType)
+	assert.Equal(t, hotWalletAta.PublicKey().ToBase58(), actionRecord.Source)
+	require.NotNil(t, actionRecord.Destination)
+	assert.Equal(t, e.treasuryPool.Vault, *actionRecord.Destination)
+	require.NotNil(t, actionRecord.Quantity)
+	assert.Equal(t, expectedQuarks, *actionRecord.Quantity)
+	assert.Equal(t, action.StatePending, actionRecord.State)
+
+	fulfillmentRecords, err := e.data.GetAllFulfillmentsByIntent(e.ctx, intentRecord.IntentId)
+	require.NoError(t, err)
+	require.Len(t, fulfillmentRecords, 1)
+	fulfillmentRecord := fulfillmentRecords[0]
+	assert.Equal(t, fulfillment.TransferToTreasuryPool, fulfillmentRecord.FulfillmentType)
+	assert.Equal(t, hotWalletAta.PublicKey().ToBase58(), fulfillmentRecord.Source)
+	require.NotNil(t, fulfillmentRecord.Destination)
+	assert.Equal(t, e.treasuryPool.Vault, *fulfillmentRecord.Destination)
+	assert.Equal(t, intentRecord.Id, fulfillmentRecord.IntentOrderingIndex)
+	assert.Equal(t, fulfillment.StateUnknown, fulfillmentRecord.State)
+
+	var txn solana.Transaction
+	require.NoError(t, txn.Unmarshal(fulfillmentRecord.Data))
+	require.Len(t, txn.Message.Instructions, 2)
+	require.Len(t, txn.Signatures, 2)
+
+	assert.Equal(t, *fulfillmentRecord.Signature, base58.Encode(txn.Signature()))
+	assert.True(t, ed25519.Verify(e.subsidizer.PublicKey().ToBytes(), txn.Message.Marshal(), txn.Signatures[0][:]))
+	assert.True(t, ed25519.Verify(hotWallet.PublicKey().ToBytes(), txn.Message.Marshal(), txn.Signatures[1][:]))
+
+	advanceNonceIxn, err := system.DecompileAdvanceNonce(txn.Message, 0)
+	require.NoError(t, err)
+	assert.Equal(t, *fulfillmentRecord.Nonce, base58.Encode(advanceNonceIxn.Nonce))
+
+	transferIxn, err := token.DecompileTransfer(txn.Message, 1)
+	require.NoError(t, err)
+	assert.Equal(t, hotWalletAta.PublicKey().ToBase58(), base58.Encode(transferIxn.Source))
+	assert.Equal(t, e.treasuryPool.Vault, base58.Encode(transferIxn.Destination))
+	assert.Equal(t, hotWallet.PublicKey().ToBase58(), base58.Encode(transferIxn.Owner))
+	assert.Equal(t, expectedQuarks, transferIxn.Amount)
+
+	nonceRecord, err := e.data.GetNonce(e.ctx, *fulfillmentRecord.Nonce)
+	require.NoError(t, err)
+	assert.Equal(t, nonce.StateReserved, nonceRecord.State)
+	assert.Equal(t, *fulfillmentRecord.Signature, nonceRecord.Signature)
+
+	count, err := e.data.GetTreasuryPoolFundingCountByVaultAndState(e.ctx, e.treasuryPool.Vault, treasury.FundingStatePending)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+}
+
 func (e *testEnv) generateAvailableNonces(t *testing.T, count int) []*nonce.Record {
 	var nonces []*nonce.Record
 	for i := 0; i < count; i++ {
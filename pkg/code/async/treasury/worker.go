@@ -53,6 +53,10 @@ func (p *service) worker(serviceCtx context.Context, state treasury.TreasuryPool
 			// Process the batch of accounts in parallel
 			var wg sync.WaitGroup
 			for _, item := range items {
+				if !p.membership.Owns(item.Address) {
+					continue
+				}
+
 				wg.Add(1)
 				go func(record *treasury.Record) {
 					defer wg.Done()
@@ -0,0 +1,92 @@
+package async_treasury
+
+import (
+	"testing"
+
+	"github.com/mr-tron/base58"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	splitter_token "github.com/code-payments/code-server/pkg/solana/splitter"
+	"github.com/code-payments/code-server/pkg/testutil"
+	"github.com/code-payments/code-server/pkg/code/data/treasury"
+)
+
+func TestUpdateAccountState_Simulator(t *testing.T) {
+	env := setup(t, &testOverrides{})
+
+	poolAddress := testutil.NewRandomAccount(t).PublicKey().ToBytes()
+	vault, vaultBump, err := splitter_token.GetPoolVaultAddress(&splitter_token.GetPoolVaultAddressArgs{
+		Pool: poolAddress,
+	})
+	require.NoError(t, err)
+
+	historyList := make([]splitter_token.Hash, splitter_token.MaxHistory)
+	encodedHistoryList := make([]string, splitter_token.MaxHistory)
+	for i := range historyList {
+		historyList[i] = make(splitter_token.Hash, splitter_token.HashSize)
+		encodedHistoryList[i] = historyList[i].ToString()
+	}
+
+	record := &treasury.Record{
+		DataVersion: splitter_token.DataVersion1,
+
+		Name: "simulated-pool",
+
+		Address: base58.Encode(poolAddress),
+		Bump:    255,
+
+		Vault:     base58.Encode(vault),
+		VaultBump: vaultBump,
+
+		Authority: env.subsidizer.PublicKey().ToBase58(),
+
+		MerkleTreeLevels: 63,
+
+		CurrentIndex:    0,
+		HistoryListSize: splitter_token.MaxHistory,
+		HistoryList:     encodedHistoryList,
+
+		SolanaBlock: 1,
+
+		State: treasury.TreasuryPoolStateAvailable,
+	}
+	require.NoError(t, env.data.SaveTreasuryPool(env.ctx, record))
+
+	newRoot := splitter_token.Hash(testutil.NewRandomAccount(t).PublicKey().ToBytes())
+	historyList[1] = newRoot
+
+	zeroValues := make([]splitter_token.Hash, record.MerkleTreeLevels)
+	for i := range zeroValues {
+		zeroValues[i] = make(splitter_token.Hash, splitter_token.HashSize)
+	}
+	env.ledger.CreateSplitterPool(poolAddress, &splitter_token.PoolAccount{
+		DataVersion:  splitter_token.DataVersion1,
+		Authority:    env.subsidizer.PublicKey().ToBytes(),
+		Mint:         testutil.NewRandomAccount(t).PublicKey().ToBytes(),
+		Vault:        vault,
+		VaultBump:    vaultBump,
+		Name:         record.Name,
+		HistoryList:  historyList,
+		CurrentIndex: 1,
+		MerkleTree: &splitter_token.MerkleTree{
+			Levels:         record.MerkleTreeLevels,
+			Root:           newRoot,
+			FilledSubtrees: zeroValues,
+			ZeroValues:     zeroValues,
+		},
+	})
+	env.ledger.AdvanceSlots(10)
+
+	require.NoError(t, env.worker.updateAccountState(env.ctx, record))
+
+	updated, err := env.data.GetTreasuryPoolByAddress(env.ctx, record.Address)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, updated.CurrentIndex)
+	assert.Equal(t, newRoot.ToString(), updated.GetMostRecentRoot())
+	assert.True(t, updated.SolanaBlock > 1)
+
+	// No on-chain changes since the last sync
+	env.ledger.AdvanceSlots(1)
+	assert.Equal(t, treasury.ErrStaleTreasuryPoolState, env.worker.updateAccountState(env.ctx, updated))
+}
@@ -8,6 +8,8 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/code-payments/code-server/pkg/code/async"
+	async_sharding "github.com/code-payments/code-server/pkg/code/async/sharding"
+	"github.com/code-payments/code-server/pkg/code/common"
 	code_data "github.com/code-payments/code-server/pkg/code/data"
 	"github.com/code-payments/code-server/pkg/code/server/grpc/messaging"
 	sync_util "github.com/code-payments/code-server/pkg/sync"
@@ -18,20 +20,27 @@ type service struct {
 	conf            *conf
 	data            code_data.Provider
 	messagingClient messaging.InternalMessageClient
+	signingKey      *common.Account
 	webhookLocks    *sync_util.StripedLock // todo: distributed lock
+	membership      *async_sharding.Membership
 
 	metricsMu          sync.Mutex
 	successfulWebhooks int
 	failedWebhooks     int
 }
 
-func New(data code_data.Provider, messagingClient messaging.InternalMessageClient, configProvider ConfigProvider) async.Service {
+// New returns a new webhook service. Webhook request bodies are signed by the
+// provided signing key, which must not be a subsidizer. Webhooks are sharded by
+// ID across the members of the provided group membership, if any.
+func New(data code_data.Provider, messagingClient messaging.InternalMessageClient, signingKey *common.Account, configProvider ConfigProvider, membership *async_sharding.Membership) async.Service {
 	return &service{
 		log:             logrus.StandardLogger().WithField("service", "webhook"),
 		conf:            configProvider(),
 		data:            data,
 		messagingClient: messagingClient,
+		signingKey:      signingKey,
 		webhookLocks:    sync_util.NewStripedLock(1024),
+		membership:      membership,
 	}
 }
 
@@ -56,6 +56,10 @@ func (p *service) worker(serviceCtx context.Context, interval time.Duration) err
 
 			var wg sync.WaitGroup
 			for _, item := range items {
+				if !p.membership.Owns(item.WebhookId) {
+					continue
+				}
+
 				wg.Add(1)
 
 				go func(record *webhook.Record) {
@@ -113,6 +117,7 @@ func (p *service) handleCurrentAttempt(ctx context.Context, record *webhook.Reco
 		ctx,
 		p.data,
 		p.messagingClient,
+		p.signingKey,
 		record,
 		p.conf.webhookTimeout.Get(ctx),
 	)
@@ -11,6 +11,7 @@ import (
 
 	"github.com/code-payments/code-server/pkg/testutil"
 	code_data "github.com/code-payments/code-server/pkg/code/data"
+	datatest "github.com/code-payments/code-server/pkg/code/data/test"
 	"github.com/code-payments/code-server/pkg/code/data/webhook"
 	"github.com/code-payments/code-server/pkg/code/server/grpc/messaging"
 	webhook_util "github.com/code-payments/code-server/pkg/code/webhook"
@@ -126,7 +127,7 @@ type testEnv struct {
 }
 
 func setup(t *testing.T) *testEnv {
-	data := code_data.NewTestDataProvider()
+	data := datatest.NewDataProvider()
 	testutil.SetupRandomSubsidizer(t, data)
 	return &testEnv{
 		ctx:  context.Background(),
@@ -134,7 +135,9 @@ func setup(t *testing.T) *testEnv {
 		worker: New(
 			data,
 			messaging.NewMessagingClient(data),
+			testutil.NewRandomAccount(t),
 			withManualTestOverrides(&testOverrides{}),
+			nil,
 		).(*service),
 		webhook: webhook_util.NewTestWebhookEndpoint(t),
 	}
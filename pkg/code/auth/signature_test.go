@@ -15,6 +15,7 @@ import (
 	messagingpb "github.com/code-payments/code-protobuf-api/generated/go/messaging/v1"
 
 	code_data "github.com/code-payments/code-server/pkg/code/data"
+	datatest "github.com/code-payments/code-server/pkg/code/data/test"
 	"github.com/code-payments/code-server/pkg/code/data/user"
 	"github.com/code-payments/code-server/pkg/code/data/user/storage"
 
@@ -29,7 +30,7 @@ type testEnv struct {
 
 func setup(t *testing.T) (env testEnv) {
 	env.ctx = context.Background()
-	env.data = code_data.NewTestDataProvider()
+	env.data = datatest.NewDataProvider()
 	env.verifier = NewRPCSignatureVerifier(env.data)
 	return env
 }
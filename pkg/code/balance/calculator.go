@@ -9,6 +9,7 @@ import (
 	commonpb "github.com/code-payments/code-protobuf-api/generated/go/common/v1"
 
 	"github.com/code-payments/code-server/pkg/metrics"
+	"github.com/code-payments/code-server/pkg/mint"
 	timelock_token "github.com/code-payments/code-server/pkg/solana/timelock/v1"
 	"github.com/code-payments/code-server/pkg/code/common"
 	code_data "github.com/code-payments/code-server/pkg/code/data"
@@ -366,7 +367,7 @@ func FundingFromExternalDepositsBatch(ctx context.Context, data code_data.Provid
 	}
 }
 
-// GetTotalBalance gets an owner account's total balance
+// GetTotalBalance gets an owner account's total Kin balance
 //
 // todo: consolidate common logic with GetPrivateBalance
 func GetTotalBalance(ctx context.Context, data code_data.Provider, owner *common.Account) (uint64, error) {
@@ -410,6 +411,33 @@ func GetTotalBalance(ctx context.Context, data code_data.Provider, owner *common
 	return total, nil
 }
 
+// GetTotalBalanceForMint gets an owner account's total balance for the provided
+// mint, in quarks of that mint. Other mints are only supported for the owner's
+// primary account, so there's a single timelock account to consider.
+func GetTotalBalanceForMint(ctx context.Context, data code_data.Provider, owner *common.Account, m *mint.Mint) (uint64, error) {
+	if m.IsKin() {
+		return GetTotalBalance(ctx, data, owner)
+	}
+
+	tracer := metrics.TraceMethodCall(ctx, metricsPackageName, "GetTotalBalanceForMint")
+	tracer.AddAttribute("owner", owner.PublicKey().ToBase58())
+	tracer.AddAttribute("mint", m.ToBase58())
+	defer tracer.End()
+
+	timelockAccounts, err := owner.GetTimelockAccountsForMint(timelock_token.DataVersion1, m)
+	if err != nil {
+		tracer.OnError(err)
+		return 0, err
+	}
+
+	balance, err := DefaultCalculation(ctx, data, timelockAccounts.Vault)
+	if err != nil {
+		tracer.OnError(err)
+		return 0, err
+	}
+	return balance, nil
+}
+
 // GetPrivateBalance gets an owner account's total private balance (ie. everything
 // except the primary account).
 //
@@ -13,10 +13,12 @@ import (
 	commonpb "github.com/code-payments/code-protobuf-api/generated/go/common/v1"
 
 	"github.com/code-payments/code-server/pkg/currency"
+	"github.com/code-payments/code-server/pkg/mint"
 	timelock_token_v1 "github.com/code-payments/code-server/pkg/solana/timelock/v1"
 	"github.com/code-payments/code-server/pkg/testutil"
 	"github.com/code-payments/code-server/pkg/code/common"
 	code_data "github.com/code-payments/code-server/pkg/code/data"
+	datatest "github.com/code-payments/code-server/pkg/code/data/test"
 	"github.com/code-payments/code-server/pkg/code/data/account"
 	"github.com/code-payments/code-server/pkg/code/data/action"
 	"github.com/code-payments/code-server/pkg/code/data/deposit"
@@ -104,6 +106,50 @@ func TestDefaultCalculationMethods_DepositFromExternalWallet(t *testing.T) {
 	}
 }
 
+func TestGetTotalBalanceForMint(t *testing.T) {
+	env := setupBalanceTestEnv(t)
+	require.NoError(t, mint.Register(mint.Usdc))
+
+	owner := testutil.NewRandomAccount(t)
+	kinAccount, err := owner.ToTimelockVault(getTimelockDataVersion(false))
+	require.NoError(t, err)
+
+	usdcTimelockAccounts, err := owner.GetTimelockAccountsForMint(timelock_token_v1.DataVersion1, mint.Usdc)
+	require.NoError(t, err)
+	usdcTimelockRecord := usdcTimelockAccounts.ToDBRecord()
+	usdcTimelockRecord.VaultState = timelock_token_v1.StateLocked
+	usdcTimelockRecord.Block += 1
+	require.NoError(t, env.data.SaveTimelock(env.ctx, usdcTimelockRecord))
+
+	externalAccount := testutil.NewRandomAccount(t)
+
+	data := &balanceTestData{
+		codeUsers: []*common.Account{owner},
+		transactions: []balanceTestTransaction{
+			{source: externalAccount, destination: kinAccount, quantity: 1, transactionState: transaction.ConfirmationFinalized},
+			{source: externalAccount, destination: usdcTimelockAccounts.Vault, quantity: 10, transactionState: transaction.ConfirmationFinalized},
+			{source: externalAccount, destination: usdcTimelockAccounts.Vault, quantity: 100, transactionState: transaction.ConfirmationFinalized},
+		},
+	}
+	setupBalanceTestData(t, env, data, balanceTestDataConf{})
+
+	balance, err := GetTotalBalanceForMint(env.ctx, env.data, owner, mint.Kin)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, balance)
+
+	balance, err = GetTotalBalanceForMint(env.ctx, env.data, owner, mint.Usdc)
+	require.NoError(t, err)
+	assert.EqualValues(t, 110, balance)
+
+	// The second mint's account is never included in the Kin balance
+	balance, err = GetTotalBalance(env.ctx, env.data, owner)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, balance)
+
+	_, err = GetTotalBalanceForMint(env.ctx, env.data, testutil.NewRandomAccount(t), mint.Usdc)
+	assert.Equal(t, ErrNotManagedByCode, err)
+}
+
 func TestDefaultCalculationMethods_MultipleIntents(t *testing.T) {
 	for _, useLegacyIntents := range []bool{true, false} {
 		env := setupBalanceTestEnv(t)
@@ -480,7 +526,7 @@ type balanceTestTransaction struct {
 
 func setupBalanceTestEnv(t *testing.T) (env balanceTestEnv) {
 	env.ctx = context.Background()
-	env.data = code_data.NewTestDataProvider()
+	env.data = datatest.NewDataProvider()
 	testutil.SetupRandomSubsidizer(t, env.data)
 	return env
 }
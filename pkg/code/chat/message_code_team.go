@@ -1,6 +1,8 @@
 package chat
 
 import (
+	"time"
+
 	"github.com/pkg/errors"
 
 	chatpb "github.com/code-payments/code-protobuf-api/generated/go/chat/v1"
@@ -21,6 +23,38 @@ func ToReferralBonusMessage(intentRecord *intent.Record) (*chatpb.ChatMessage, e
 	return newIncentiveMessage(localization.ChatMessageReferralBonus, intentRecord)
 }
 
+// ToPromotionBonusMessage turns the intent record into a promotion bonus chat message
+// to be inserted into the Code Team chat.
+func ToPromotionBonusMessage(intentRecord *intent.Record) (*chatpb.ChatMessage, error) {
+	return newIncentiveMessage(localization.ChatMessagePromotionBonus, intentRecord)
+}
+
+// ToRecurringPaymentFailedMessage creates a Code Team chat message letting the user
+// know a scheduled payment for a recurring payment couldn't be sent.
+func ToRecurringPaymentFailedMessage(messageId string, ts time.Time) (*chatpb.ChatMessage, error) {
+	return newLocalizedTextMessage(messageId, localization.ChatMessageRecurringPaymentFailed, ts)
+}
+
+// ToRecurringPaymentCancelledMessage creates a Code Team chat message letting the
+// user know a recurring payment was cancelled after repeated failures.
+func ToRecurringPaymentCancelledMessage(messageId string, ts time.Time) (*chatpb.ChatMessage, error) {
+	return newLocalizedTextMessage(messageId, localization.ChatMessageRecurringPaymentCancelled, ts)
+}
+
+func newLocalizedTextMessage(messageId, localizedTextKey string, ts time.Time) (*chatpb.ChatMessage, error) {
+	content := []*chatpb.Content{
+		{
+			Type: &chatpb.Content_Localized{
+				Localized: &chatpb.LocalizedContent{
+					Key: localizedTextKey,
+				},
+			},
+		},
+	}
+
+	return newProtoChatMessage(messageId, content, ts)
+}
+
 func newIncentiveMessage(localizedTextKey string, intentRecord *intent.Record) (*chatpb.ChatMessage, error) {
 	exchangeData, ok := getExchangeDataFromIntent(intentRecord)
 	if !ok {
@@ -17,6 +17,7 @@ import (
 	"github.com/code-payments/code-server/pkg/testutil"
 	"github.com/code-payments/code-server/pkg/code/common"
 	code_data "github.com/code-payments/code-server/pkg/code/data"
+	datatest "github.com/code-payments/code-server/pkg/code/data/test"
 	"github.com/code-payments/code-server/pkg/code/data/badgecount"
 	"github.com/code-payments/code-server/pkg/code/data/chat"
 )
@@ -151,7 +152,7 @@ type testEnv struct {
 func setup(t *testing.T) *testEnv {
 	return &testEnv{
 		ctx:  context.Background(),
-		data: code_data.NewTestDataProvider(),
+		data: datatest.NewDataProvider(),
 	}
 }
 
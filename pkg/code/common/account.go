@@ -10,8 +10,8 @@ import (
 
 	commonpb "github.com/code-payments/code-protobuf-api/generated/go/common/v1"
 
-	"github.com/code-payments/code-server/pkg/kin"
 	"github.com/code-payments/code-server/pkg/metrics"
+	"github.com/code-payments/code-server/pkg/mint"
 	"github.com/code-payments/code-server/pkg/solana"
 	timelock_token_legacy "github.com/code-payments/code-server/pkg/solana/timelock/legacy_2022"
 	timelock_token_v1 "github.com/code-payments/code-server/pkg/solana/timelock/v1"
@@ -54,6 +54,8 @@ type Account struct {
 type TimelockAccounts struct {
 	DataVersion timelock_token_v1.TimelockDataVersion
 
+	Mint *Account
+
 	State     *Account
 	StateBump uint8
 
@@ -194,12 +196,18 @@ func (a *Account) ToTimelockVault(dataVersion timelock_token_v1.TimelockDataVers
 	return timelockAccounts.Vault, nil
 }
 
+// ToAssociatedTokenAccount gets the Kin associated token account
 func (a *Account) ToAssociatedTokenAccount() (*Account, error) {
+	return a.ToAssociatedTokenAccountForMint(mint.Kin)
+}
+
+// ToAssociatedTokenAccountForMint gets the associated token account for the mint
+func (a *Account) ToAssociatedTokenAccountForMint(m *mint.Mint) (*Account, error) {
 	if err := a.Validate(); err != nil {
 		return nil, errors.Wrap(err, "error validating owner account")
 	}
 
-	ata, err := token.GetAssociatedAccount(a.publicKey.bytesValue, kin.TokenMint)
+	ata, err := token.GetAssociatedAccount(a.publicKey.bytesValue, m.Address)
 	if err != nil {
 		return nil, err
 	}
@@ -207,17 +215,60 @@ func (a *Account) ToAssociatedTokenAccount() (*Account, error) {
 	return NewAccountFromPublicKeyBytes(ata)
 }
 
+// GetTimelockAccounts gets the Kin timelock accounts
 func (a *Account) GetTimelockAccounts(dataVersion timelock_token_v1.TimelockDataVersion) (*TimelockAccounts, error) {
+	return a.GetTimelockAccountsForMint(dataVersion, mint.Kin)
+}
+
+// GetTimelockAccountsForMint gets the timelock accounts for the mint. Only the
+// v1 timelock program supports mints other than Kin.
+func (a *Account) GetTimelockAccountsForMint(dataVersion timelock_token_v1.TimelockDataVersion, m *mint.Mint) (*TimelockAccounts, error) {
+	return a.getTimelockAccounts(dataVersion, m, GetSubsidizer())
+}
+
+// ResolveTimelockAccounts gets the Kin timelock accounts for the owner, which
+// may have been opened when a now retired subsidizer was current. Accounts are
+// derived for every subsidizer loaded in LoadSubsidizers, and the ones that
+// already exist are returned. Otherwise, accounts for the current subsidizer
+// are returned.
+func (a *Account) ResolveTimelockAccounts(ctx context.Context, data code_data.Provider, dataVersion timelock_token_v1.TimelockDataVersion) (*TimelockAccounts, error) {
+	var current *TimelockAccounts
+	for _, subsidizer := range getAllSubsidizers() {
+		timelockAccounts, err := a.getTimelockAccounts(dataVersion, mint.Kin, subsidizer)
+		if err != nil {
+			return nil, err
+		}
+
+		if current == nil {
+			current = timelockAccounts
+		}
+
+		_, err = data.GetTimelockByVault(ctx, timelockAccounts.Vault.publicKey.ToBase58())
+		if err == nil {
+			return timelockAccounts, nil
+		} else if err != timelock.ErrTimelockNotFound {
+			return nil, err
+		}
+	}
+	return current, nil
+}
+
+func (a *Account) getTimelockAccounts(dataVersion timelock_token_v1.TimelockDataVersion, m *mint.Mint, timeAuthority *Account) (*TimelockAccounts, error) {
 	if err := a.Validate(); err != nil {
 		return nil, errors.Wrap(err, "error validating owner account")
 	}
 
+	mintAccount, err := NewAccountFromPublicKeyBytes(m.Address)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid mint address")
+	}
+
 	var timelockAccounts *TimelockAccounts
 	switch dataVersion {
 	case timelock_token_v1.DataVersion1:
 		stateAddress, stateBump, err := timelock_token_v1.GetStateAddress(&timelock_token_v1.GetStateAddressArgs{
-			Mint:          kin.TokenMint,
-			TimeAuthority: GetSubsidizer().publicKey.ToBytes(),
+			Mint:          mintAccount.publicKey.ToBytes(),
+			TimeAuthority: timeAuthority.publicKey.ToBytes(),
 			VaultOwner:    a.publicKey.ToBytes(),
 			NumDaysLocked: timelock_token_v1.DefaultNumDaysLocked,
 		})
@@ -251,9 +302,13 @@ func (a *Account) GetTimelockAccounts(dataVersion timelock_token_v1.TimelockData
 			VaultBump: vaultBump,
 		}
 	case timelock_token_v1.DataVersionLegacy:
+		if !m.IsKin() {
+			return nil, errors.New("legacy timelock accounts only support kin")
+		}
+
 		stateAddress, stateBump, err := timelock_token_legacy.GetStateAddress(&timelock_token_legacy.GetStateAddressArgs{
-			Mint:           kin.TokenMint,
-			TimeAuthority:  GetSubsidizer().publicKey.ToBytes(),
+			Mint:           mintAccount.publicKey.ToBytes(),
+			TimeAuthority:  timeAuthority.publicKey.ToBytes(),
 			Nonce:          defaultTimelockNonceAccount.publicKey.ToBytes(),
 			VaultOwner:     a.publicKey.ToBytes(),
 			UnlockDuration: timelock_token_legacy.DefaultUnlockDuration,
@@ -291,9 +346,10 @@ func (a *Account) GetTimelockAccounts(dataVersion timelock_token_v1.TimelockData
 	}
 
 	timelockAccounts.DataVersion = dataVersion
+	timelockAccounts.Mint = mintAccount
 	timelockAccounts.VaultOwner = a
-	timelockAccounts.TimeAuthority = GetSubsidizer()
-	timelockAccounts.CloseAuthority = GetSubsidizer()
+	timelockAccounts.TimeAuthority = timeAuthority
+	timelockAccounts.CloseAuthority = timeAuthority
 	return timelockAccounts, nil
 }
 
@@ -365,14 +421,14 @@ func IsManagedByCode(ctx context.Context, timelockRecord *timelock.Record) bool
 	}
 
 	// This should never happen, but is a precautionary check.
-	if timelockRecord.TimeAuthority != GetSubsidizer().publicKey.ToBase58() {
+	if !IsSubsidizer(timelockRecord.TimeAuthority) {
 		metrics.RecordCount(ctx, dangerousTimelockAccessCountMetricName, 1)
 		log.Warn("detected a dangerous timelock account with a time authority that's not Code")
 		return false
 	}
 
 	// This should never happen, but is a precautionary check.
-	if timelockRecord.CloseAuthority != GetSubsidizer().publicKey.ToBase58() {
+	if !IsSubsidizer(timelockRecord.CloseAuthority) {
 		metrics.RecordCount(ctx, dangerousTimelockAccessCountMetricName, 1)
 		log.Warn("detected a dangerous timelock account with a close authority that's not Code")
 		return false
@@ -389,6 +445,8 @@ func (a *TimelockAccounts) ToDBRecord() *timelock.Record {
 	return &timelock.Record{
 		DataVersion: a.DataVersion,
 
+		Mint: a.Mint.publicKey.ToBase58(),
+
 		Address: a.State.publicKey.ToBase58(),
 		Bump:    a.StateBump,
 
@@ -407,6 +465,40 @@ func (a *TimelockAccounts) ToDBRecord() *timelock.Record {
 	}
 }
 
+// GetTimelockAccountsForRecord gets the TimelockAccounts for an existing
+// timelock.Record, using the record's mint and time authority, which may be
+// a retired subsidizer.
+func GetTimelockAccountsForRecord(record *timelock.Record) (*TimelockAccounts, error) {
+	vaultOwner, err := NewAccountFromPublicKeyString(record.VaultOwner)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid vault owner")
+	}
+
+	m, err := mint.Get(record.Mint)
+	if err != nil {
+		return nil, err
+	}
+
+	if record.CloseAuthority != record.TimeAuthority {
+		return nil, errors.New("timelock record has different time and close authorities")
+	}
+
+	timeAuthority, err := GetSubsidizerByPublicKey(record.TimeAuthority)
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting time authority")
+	}
+
+	timelockAccounts, err := vaultOwner.getTimelockAccounts(record.DataVersion, m, timeAuthority)
+	if err != nil {
+		return nil, err
+	}
+
+	if timelockAccounts.Vault.publicKey.ToBase58() != record.VaultAddress {
+		return nil, errors.New("timelock record doesn't match derived vault address")
+	}
+	return timelockAccounts, nil
+}
+
 // GetDBRecord fetches the equivalent timelock.Record for a TimelockAccounts from
 // the DB
 func (a *TimelockAccounts) GetDBRecord(ctx context.Context, data code_data.Provider) (*timelock.Record, error) {
@@ -422,7 +514,7 @@ func (a *TimelockAccounts) GetInitializeInstruction() (solana.Instruction, error
 				Timelock:      a.State.publicKey.ToBytes(),
 				Vault:         a.Vault.publicKey.ToBytes(),
 				VaultOwner:    a.VaultOwner.publicKey.ToBytes(),
-				Mint:          kin.TokenMint,
+				Mint:          a.Mint.publicKey.ToBytes(),
 				TimeAuthority: a.TimeAuthority.publicKey.ToBytes(),
 				Payer:         a.CloseAuthority.publicKey.ToBytes(),
 			},
@@ -518,7 +610,7 @@ func (a *TimelockAccounts) GetBurnDustWithAuthorityInstruction(maxQuarks uint64)
 				Vault:         a.Vault.publicKey.ToBytes(),
 				VaultOwner:    a.VaultOwner.publicKey.ToBytes(),
 				TimeAuthority: a.TimeAuthority.publicKey.ToBytes(),
-				Mint:          kin.TokenMint,
+				Mint:          a.Mint.publicKey.ToBytes(),
 				Payer:         GetSubsidizer().publicKey.ToBytes(),
 			},
 			&timelock_token_v1.BurnDustWithAuthorityInstructionArgs{
@@ -533,7 +625,7 @@ func (a *TimelockAccounts) GetBurnDustWithAuthorityInstruction(maxQuarks uint64)
 				Vault:         a.Vault.publicKey.ToBytes(),
 				VaultOwner:    a.VaultOwner.publicKey.ToBytes(),
 				TimeAuthority: a.TimeAuthority.publicKey.ToBytes(),
-				Mint:          kin.TokenMint,
+				Mint:          a.Mint.publicKey.ToBytes(),
 				Payer:         GetSubsidizer().publicKey.ToBytes(),
 			},
 			&timelock_token_legacy.BurnDustWithAuthorityInstructionArgs{
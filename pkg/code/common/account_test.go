@@ -12,11 +12,12 @@ import (
 	commonpb "github.com/code-payments/code-protobuf-api/generated/go/common/v1"
 
 	"github.com/code-payments/code-server/pkg/kin"
+	"github.com/code-payments/code-server/pkg/mint"
 	"github.com/code-payments/code-server/pkg/solana"
 	timelock_token_legacy "github.com/code-payments/code-server/pkg/solana/timelock/legacy_2022"
 	timelock_token_v1 "github.com/code-payments/code-server/pkg/solana/timelock/v1"
 	"github.com/code-payments/code-server/pkg/solana/token"
-	code_data "github.com/code-payments/code-server/pkg/code/data"
+	datatest "github.com/code-payments/code-server/pkg/code/data/test"
 )
 
 func TestAccountWithPublicKey(t *testing.T) {
@@ -155,11 +156,143 @@ func TestGetTimelockAccounts_V1Program(t *testing.T) {
 	assert.EqualValues(t, ownerAccount.PublicKey().ToBytes(), actual.VaultOwner.PublicKey().ToBytes())
 	assert.EqualValues(t, subsidizerAccount.PublicKey().ToBytes(), actual.TimeAuthority.PublicKey().ToBytes())
 	assert.EqualValues(t, subsidizerAccount.PublicKey().ToBytes(), actual.CloseAuthority.PublicKey().ToBytes())
+	assert.EqualValues(t, kin.TokenMint, actual.Mint.PublicKey().ToBytes())
+}
+
+func TestGetTimelockAccountsForMint_V1Program(t *testing.T) {
+	subsidizerAccount = newRandomTestAccount(t)
+	ownerAccount := newRandomTestAccount(t)
+
+	expectedStateAddress, expectedStateBump, err := timelock_token_v1.GetStateAddress(&timelock_token_v1.GetStateAddressArgs{
+		Mint:          mint.Usdc.Address,
+		TimeAuthority: subsidizerAccount.PublicKey().ToBytes(),
+		VaultOwner:    ownerAccount.PublicKey().ToBytes(),
+		NumDaysLocked: timelock_token_v1.DefaultNumDaysLocked,
+	})
+	require.NoError(t, err)
+
+	expectedVaultAddress, expectedVaultBump, err := timelock_token_v1.GetVaultAddress(&timelock_token_v1.GetVaultAddressArgs{
+		State:       expectedStateAddress,
+		DataVersion: timelock_token_v1.DataVersion1,
+	})
+	require.NoError(t, err)
+
+	actual, err := ownerAccount.GetTimelockAccountsForMint(timelock_token_v1.DataVersion1, mint.Usdc)
+	require.NoError(t, err)
+	assert.Equal(t, timelock_token_v1.DataVersion1, actual.DataVersion)
+	assert.EqualValues(t, mint.Usdc.Address, actual.Mint.PublicKey().ToBytes())
+	assert.EqualValues(t, expectedStateAddress, actual.State.PublicKey().ToBytes())
+	assert.Equal(t, expectedStateBump, actual.StateBump)
+	assert.EqualValues(t, expectedVaultAddress, actual.Vault.PublicKey().ToBytes())
+	assert.Equal(t, expectedVaultBump, actual.VaultBump)
+	assert.EqualValues(t, ownerAccount.PublicKey().ToBytes(), actual.VaultOwner.PublicKey().ToBytes())
+
+	// Each mint has its own vault for the same owner
+	kinTimelockAccounts, err := ownerAccount.GetTimelockAccounts(timelock_token_v1.DataVersion1)
+	require.NoError(t, err)
+	assert.NotEqual(t, kinTimelockAccounts.Vault.PublicKey().ToBase58(), actual.Vault.PublicKey().ToBase58())
+
+	record := actual.ToDBRecord()
+	assert.Equal(t, mint.UsdcMint, record.Mint)
+
+	ixn, err := actual.GetInitializeInstruction()
+	require.NoError(t, err)
+	txn := solana.NewTransaction(subsidizerAccount.PublicKey().ToBytes(), ixn)
+	_, initializeAccounts, err := timelock_token_v1.InitializeInstructionFromLegacyInstruction(txn, 0)
+	require.NoError(t, err)
+	assert.EqualValues(t, mint.Usdc.Address, initializeAccounts.Mint)
+
+	ixn, err = actual.GetBurnDustWithAuthorityInstruction(1)
+	require.NoError(t, err)
+	txn = solana.NewTransaction(subsidizerAccount.PublicKey().ToBytes(), ixn)
+	_, burnAccounts, err := timelock_token_v1.BurnDustWithAuthorityInstructionFromLegacyInstruction(txn, 0)
+	require.NoError(t, err)
+	assert.EqualValues(t, mint.Usdc.Address, burnAccounts.Mint)
+
+	_, err = ownerAccount.GetTimelockAccountsForMint(timelock_token_v1.DataVersionLegacy, mint.Usdc)
+	assert.Error(t, err)
+}
+
+func TestGetTimelockAccountsForRecord(t *testing.T) {
+	require.NoError(t, mint.Register(mint.Usdc))
+
+	subsidizerAccount = newRandomTestAccount(t)
+	ownerAccount := newRandomTestAccount(t)
+
+	for _, m := range []*mint.Mint{mint.Kin, mint.Usdc} {
+		expected, err := ownerAccount.GetTimelockAccountsForMint(timelock_token_v1.DataVersion1, m)
+		require.NoError(t, err)
+
+		actual, err := GetTimelockAccountsForRecord(expected.ToDBRecord())
+		require.NoError(t, err)
+		assert.Equal(t, expected.Mint.PublicKey().ToBase58(), actual.Mint.PublicKey().ToBase58())
+		assert.Equal(t, expected.State.PublicKey().ToBase58(), actual.State.PublicKey().ToBase58())
+		assert.Equal(t, expected.Vault.PublicKey().ToBase58(), actual.Vault.PublicKey().ToBase58())
+	}
+
+	// Records predating multi-mint support are Kin
+	expected, err := ownerAccount.GetTimelockAccounts(timelock_token_v1.DataVersionLegacy)
+	require.NoError(t, err)
+	record := expected.ToDBRecord()
+	record.Mint = ""
+	actual, err := GetTimelockAccountsForRecord(record)
+	require.NoError(t, err)
+	assert.Equal(t, expected.Vault.PublicKey().ToBase58(), actual.Vault.PublicKey().ToBase58())
+
+	// Records for the wrong vault are rejected
+	record.Mint = mint.Usdc.ToBase58()
+	record.DataVersion = timelock_token_v1.DataVersion1
+	_, err = GetTimelockAccountsForRecord(record)
+	assert.Error(t, err)
+}
+
+func TestGetTimelockAccountsForRecord_RetiredSubsidizer(t *testing.T) {
+	ctx := context.Background()
+	data := datatest.NewDataProvider()
+
+	retiredSubsidizer := newRandomTestAccount(t)
+	subsidizerAccount = retiredSubsidizer
+	retiredSubsidizerAccounts = nil
+	ownerAccount := newRandomTestAccount(t)
+
+	expected, err := ownerAccount.GetTimelockAccounts(timelock_token_v1.DataVersion1)
+	require.NoError(t, err)
+	record := expected.ToDBRecord()
+	require.NoError(t, data.SaveTimelock(ctx, record))
+
+	// Rotate the subsidizer, which changes the derived accounts for the owner
+	subsidizerAccount = newRandomTestAccount(t)
+	require.NoError(t, InjectTestRetiredSubsidizer(ctx, data, retiredSubsidizer))
+
+	rotated, err := ownerAccount.GetTimelockAccounts(timelock_token_v1.DataVersion1)
+	require.NoError(t, err)
+	assert.NotEqual(t, expected.Vault.PublicKey().ToBase58(), rotated.Vault.PublicKey().ToBase58())
+
+	// Existing accounts continue to use the retired subsidizer
+	actual, err := GetTimelockAccountsForRecord(record)
+	require.NoError(t, err)
+	assert.Equal(t, expected.Vault.PublicKey().ToBase58(), actual.Vault.PublicKey().ToBase58())
+	assert.Equal(t, retiredSubsidizer.PublicKey().ToBase58(), actual.TimeAuthority.PublicKey().ToBase58())
+	assert.Equal(t, retiredSubsidizer.PrivateKey().ToBase58(), actual.TimeAuthority.PrivateKey().ToBase58())
+
+	result, err := expected.Vault.IsManagedByCode(ctx, data)
+	require.NoError(t, err)
+	assert.True(t, result)
+
+	// Accounts for unknown subsidizers aren't supported
+	retiredSubsidizerAccounts = nil
+
+	_, err = GetTimelockAccountsForRecord(record)
+	assert.Error(t, err)
+
+	result, err = expected.Vault.IsManagedByCode(ctx, data)
+	require.NoError(t, err)
+	assert.False(t, result)
 }
 
 func TestIsAccountManagedByCode_TimelockState_V1Program(t *testing.T) {
 	ctx := context.Background()
-	data := code_data.NewTestDataProvider()
+	data := datatest.NewDataProvider()
 
 	ownerAccount := newRandomTestAccount(t)
 
@@ -208,7 +341,7 @@ func TestIsAccountManagedByCode_TimelockState_V1Program(t *testing.T) {
 
 func TestIsAccountManagedByCode_OtherAccounts_V1Program(t *testing.T) {
 	ctx := context.Background()
-	data := code_data.NewTestDataProvider()
+	data := datatest.NewDataProvider()
 
 	ownerAccount := newRandomTestAccount(t)
 
@@ -231,7 +364,7 @@ func TestIsAccountManagedByCode_OtherAccounts_V1Program(t *testing.T) {
 
 func TestIsAccountManagedByCode_TimeAuthority_V1Program(t *testing.T) {
 	ctx := context.Background()
-	data := code_data.NewTestDataProvider()
+	data := datatest.NewDataProvider()
 
 	subsidizerAccount = newRandomTestAccount(t)
 
@@ -256,7 +389,7 @@ func TestIsAccountManagedByCode_TimeAuthority_V1Program(t *testing.T) {
 
 func TestIsAccountManagedByCode_CloseAuthority_V1Program(t *testing.T) {
 	ctx := context.Background()
-	data := code_data.NewTestDataProvider()
+	data := datatest.NewDataProvider()
 
 	subsidizerAccount = newRandomTestAccount(t)
 
@@ -281,7 +414,7 @@ func TestIsAccountManagedByCode_CloseAuthority_V1Program(t *testing.T) {
 
 func TestIsAccountManagedByCode_DataVersionClosed_V1Program(t *testing.T) {
 	ctx := context.Background()
-	data := code_data.NewTestDataProvider()
+	data := datatest.NewDataProvider()
 
 	ownerAccount := newRandomTestAccount(t)
 	timelockAccounts, err := ownerAccount.GetTimelockAccounts(timelock_token_v1.DataVersion1)
@@ -526,7 +659,7 @@ func TestGetTimelockAccounts_Legacy2022Program(t *testing.T) {
 
 func TestIsAccountManagedByCode_TimelockState_Legacy2022Program(t *testing.T) {
 	ctx := context.Background()
-	data := code_data.NewTestDataProvider()
+	data := datatest.NewDataProvider()
 
 	ownerAccount := newRandomTestAccount(t)
 
@@ -575,7 +708,7 @@ func TestIsAccountManagedByCode_TimelockState_Legacy2022Program(t *testing.T) {
 
 func TestIsAccountManagedByCode_OtherAccounts_Legacy2022Program(t *testing.T) {
 	ctx := context.Background()
-	data := code_data.NewTestDataProvider()
+	data := datatest.NewDataProvider()
 
 	ownerAccount := newRandomTestAccount(t)
 
@@ -598,7 +731,7 @@ func TestIsAccountManagedByCode_OtherAccounts_Legacy2022Program(t *testing.T) {
 
 func TestIsAccountManagedByCode_TimeAuthority_Legacy2022Program(t *testing.T) {
 	ctx := context.Background()
-	data := code_data.NewTestDataProvider()
+	data := datatest.NewDataProvider()
 
 	subsidizerAccount = newRandomTestAccount(t)
 
@@ -623,7 +756,7 @@ func TestIsAccountManagedByCode_TimeAuthority_Legacy2022Program(t *testing.T) {
 
 func TestIsAccountManagedByCode_CloseAuthority_Legacy2022Program(t *testing.T) {
 	ctx := context.Background()
-	data := code_data.NewTestDataProvider()
+	data := datatest.NewDataProvider()
 
 	subsidizerAccount = newRandomTestAccount(t)
 
@@ -648,7 +781,7 @@ func TestIsAccountManagedByCode_CloseAuthority_Legacy2022Program(t *testing.T) {
 
 func TestIsAccountManagedByCode_DataVersionClosed_Legacy2022Program(t *testing.T) {
 	ctx := context.Background()
-	data := code_data.NewTestDataProvider()
+	data := datatest.NewDataProvider()
 
 	ownerAccount := newRandomTestAccount(t)
 	timelockAccounts, err := ownerAccount.GetTimelockAccounts(timelock_token_v1.DataVersionLegacy)
@@ -812,4 +945,12 @@ func TestConvertToAssociatedTokenAccount(t *testing.T) {
 	require.NoError(t, err)
 
 	assert.EqualValues(t, expected, actual.PublicKey().ToBytes())
+
+	expected, err = token.GetAssociatedAccount(ownerAccount.PublicKey().ToBytes(), mint.Usdc.Address)
+	require.NoError(t, err)
+
+	actual, err = ownerAccount.ToAssociatedTokenAccountForMint(mint.Usdc)
+	require.NoError(t, err)
+
+	assert.EqualValues(t, expected, actual.PublicKey().ToBytes())
 }
@@ -0,0 +1,314 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/code-payments/code-server/pkg/solana"
+	code_data "github.com/code-payments/code-server/pkg/code/data"
+)
+
+const (
+	// How long an observed fee payer balance is trusted before it's refreshed
+	// from the blockchain
+	feePayerBalanceRefreshInterval = 10 * time.Second
+)
+
+var (
+	ErrNoFeePayerAvailable = errors.New("no fee payer available")
+)
+
+// FeePayerBalance is a point-in-time view of a fee payer's balance within a
+// FeePayerPool
+type FeePayerBalance struct {
+	// The fee payer's public key
+	Account *Account
+
+	// The last observed on-chain balance, in lamports
+	Balance uint64
+
+	// The estimated number of lamports that will be used by in flight transactions
+	// paid for by this fee payer
+	Reserved uint64
+}
+
+// Available returns the estimated balance after in flight transactions are paid
+func (b *FeePayerBalance) Available() uint64 {
+	if b.Reserved >= b.Balance {
+		return 0
+	}
+	return b.Balance - b.Reserved
+}
+
+// FeePayerSelectionPolicy selects a fee payer from a set of candidates that all
+// have enough available balance
+type FeePayerSelectionPolicy interface {
+	Select(candidates []*FeePayerBalance) *FeePayerBalance
+}
+
+type roundRobinSelectionPolicy struct {
+	mu   sync.Mutex
+	next int
+}
+
+// NewRoundRobinSelectionPolicy returns a FeePayerSelectionPolicy that cycles
+// through candidates
+func NewRoundRobinSelectionPolicy() FeePayerSelectionPolicy {
+	return &roundRobinSelectionPolicy{}
+}
+
+// Select implements FeePayerSelectionPolicy.Select
+func (p *roundRobinSelectionPolicy) Select(candidates []*FeePayerBalance) *FeePayerBalance {
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	selected := candidates[p.next%len(candidates)]
+	p.next++
+	return selected
+}
+
+type highestAvailableBalanceSelectionPolicy struct {
+}
+
+// NewHighestAvailableBalanceSelectionPolicy returns a FeePayerSelectionPolicy
+// that selects the candidate with the highest available balance
+func NewHighestAvailableBalanceSelectionPolicy() FeePayerSelectionPolicy {
+	return &highestAvailableBalanceSelectionPolicy{}
+}
+
+// Select implements FeePayerSelectionPolicy.Select
+func (p *highestAvailableBalanceSelectionPolicy) Select(candidates []*FeePayerBalance) *FeePayerBalance {
+	var selected *FeePayerBalance
+	for _, candidate := range candidates {
+		if selected == nil || candidate.Available() > selected.Available() {
+			selected = candidate
+		}
+	}
+	return selected
+}
+
+type feePayerState struct {
+	account         *Account
+	balance         uint64
+	reserved        uint64
+	lastRefreshedAt time.Time
+}
+
+// FeePayerPool is a pool of accounts that pay fees and rent for server-initiated
+// transactions. Each fee payer's in flight costs are tracked using the same
+// estimates used for the subsidizer, and fee payers below the minimum balance
+// are never selected.
+//
+// Fee payers are only used where the subsidizer isn't required. The subsidizer
+// remains the fee payer for transactions involving timelock accounts, since it's
+// baked into their PDAs and client-signed transactions.
+type FeePayerPool struct {
+	data       code_data.Provider
+	policy     FeePayerSelectionPolicy
+	minBalance uint64
+
+	mu        sync.Mutex
+	feePayers []*feePayerState
+}
+
+// FeePayerReservation is an estimated amount of lamports reserved against a fee
+// payer for an in flight transaction. It must be released once the transaction
+// is finalized or abandoned.
+type FeePayerReservation struct {
+	pool     *FeePayerPool
+	state    *feePayerState
+	lamports uint64
+	once     sync.Once
+}
+
+// NewFeePayerPool returns a new FeePayerPool over the provided fee payers, which
+// must include private keys.
+func NewFeePayerPool(data code_data.Provider, policy FeePayerSelectionPolicy, minBalance uint64, feePayers ...*Account) (*FeePayerPool, error) {
+	if len(feePayers) == 0 {
+		return nil, errors.New("at least one fee payer is required")
+	}
+
+	seen := make(map[string]struct{})
+	var states []*feePayerState
+	for _, feePayer := range feePayers {
+		if err := feePayer.Validate(); err != nil {
+			return nil, err
+		}
+
+		if feePayer.PrivateKey() == nil {
+			return nil, errors.New("fee payer private key is required")
+		}
+
+		if IsSubsidizer(feePayer.PublicKey().ToBase58()) {
+			return nil, errors.New("subsidizer cannot be a pooled fee payer")
+		}
+
+		if _, ok := seen[feePayer.PublicKey().ToBase58()]; ok {
+			return nil, errors.New("duplicate fee payer")
+		}
+		seen[feePayer.PublicKey().ToBase58()] = struct{}{}
+
+		states = append(states, &feePayerState{
+			account: feePayer,
+		})
+	}
+
+	return &FeePayerPool{
+		data:       data,
+		policy:     policy,
+		minBalance: minBalance,
+		feePayers:  states,
+	}, nil
+}
+
+// LoadFeePayerPool loads fee payers by their public keys from the vault over
+// the provided data provider, and returns a new FeePayerPool for them.
+func LoadFeePayerPool(ctx context.Context, data code_data.Provider, policy FeePayerSelectionPolicy, minBalance uint64, publicKeys ...string) (*FeePayerPool, error) {
+	var feePayers []*Account
+	for _, publicKey := range publicKeys {
+		vaultRecord, err := data.GetKey(ctx, publicKey)
+		if err != nil {
+			return nil, err
+		}
+
+		feePayer, err := NewAccountFromPrivateKeyString(vaultRecord.PrivateKey)
+		if err != nil {
+			return nil, err
+		}
+
+		if feePayer.PublicKey().ToBase58() != publicKey {
+			return nil, errors.New("fee payer public key mismatch")
+		}
+
+		feePayers = append(feePayers, feePayer)
+	}
+
+	return NewFeePayerPool(data, policy, minBalance, feePayers...)
+}
+
+// Reserve selects a fee payer with enough available balance to stay above the
+// minimum balance after paying the provided number of lamports. ErrNoFeePayerAvailable
+// is returned when no fee payer qualifies.
+func (p *FeePayerPool) Reserve(ctx context.Context, lamports uint64) (*FeePayerReservation, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.refreshStaleBalances(ctx); err != nil {
+		return nil, err
+	}
+
+	var candidates []*FeePayerBalance
+	statesByCandidate := make(map[*FeePayerBalance]*feePayerState)
+	for _, state := range p.feePayers {
+		candidate := state.toBalance()
+		if candidate.Available() < lamports || candidate.Available()-lamports < p.minBalance {
+			continue
+		}
+
+		candidates = append(candidates, candidate)
+		statesByCandidate[candidate] = state
+	}
+
+	selected := p.policy.Select(candidates)
+	if selected == nil {
+		return nil, ErrNoFeePayerAvailable
+	}
+
+	state := statesByCandidate[selected]
+	state.reserved += lamports
+
+	return &FeePayerReservation{
+		pool:     p,
+		state:    state,
+		lamports: lamports,
+	}, nil
+}
+
+// ReserveForNonceAccount is like Reserve, but uses the estimated cost of creating
+// a nonce account
+func (p *FeePayerPool) ReserveForNonceAccount(ctx context.Context) (*FeePayerReservation, error) {
+	return p.Reserve(ctx, lamportsPerCreateNonceAccount)
+}
+
+// GetBalances gets the current view of all fee payer balances in the pool
+func (p *FeePayerPool) GetBalances(ctx context.Context) ([]*FeePayerBalance, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.refreshStaleBalances(ctx); err != nil {
+		return nil, err
+	}
+
+	res := make([]*FeePayerBalance, len(p.feePayers))
+	for i, state := range p.feePayers {
+		res[i] = state.toBalance()
+	}
+	return res, nil
+}
+
+func (p *FeePayerPool) refreshStaleBalances(ctx context.Context) error {
+	for _, state := range p.feePayers {
+		if time.Since(state.lastRefreshedAt) < feePayerBalanceRefreshInterval {
+			continue
+		}
+
+		accountInfo, err := p.data.GetBlockchainAccountInfo(ctx, state.account.PublicKey().ToBase58(), solana.CommitmentProcessed)
+		if err == solana.ErrNoAccountInfo {
+			// The fee payer hasn't been funded yet
+			state.balance = 0
+		} else if err != nil {
+			return err
+		} else {
+			state.balance = accountInfo.Lamports
+		}
+
+		state.lastRefreshedAt = time.Now()
+	}
+	return nil
+}
+
+// FeePayer gets the fee payer account, including its private key, that was
+// selected for the reservation
+func (r *FeePayerReservation) FeePayer() *Account {
+	copied, err := NewAccountFromPrivateKeyString(r.state.account.PrivateKey().ToBase58())
+	if err != nil {
+		panic(err)
+	}
+	return copied
+}
+
+// Release releases the reserved lamports. The fee payer's balance is refreshed
+// before its next use, so the actual cost of the transaction is accounted for.
+// Releasing more than once is a no-op.
+func (r *FeePayerReservation) Release() {
+	r.once.Do(func() {
+		r.pool.mu.Lock()
+		defer r.pool.mu.Unlock()
+
+		if r.state.reserved < r.lamports {
+			r.state.reserved = 0
+		} else {
+			r.state.reserved -= r.lamports
+		}
+		r.state.lastRefreshedAt = time.Time{}
+	})
+}
+
+func (s *feePayerState) toBalance() *FeePayerBalance {
+	publicKeyOnly, err := NewAccountFromPublicKey(s.account.PublicKey())
+	if err != nil {
+		panic(err)
+	}
+
+	return &FeePayerBalance{
+		Account:  publicKeyOnly,
+		Balance:  s.balance,
+		Reserved: s.reserved,
+	}
+}
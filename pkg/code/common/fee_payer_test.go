@@ -0,0 +1,146 @@
+package common
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	datatest "github.com/code-payments/code-server/pkg/code/data/test"
+	"github.com/code-payments/code-server/pkg/code/data/vault"
+)
+
+func TestFeePayerPool_HighestAvailableBalance(t *testing.T) {
+	ctx := context.Background()
+	data := datatest.NewDataProvider()
+
+	subsidizerAccount = newRandomTestAccount(t)
+	retiredSubsidizerAccounts = nil
+
+	minBalance := uint64(1_000_000)
+	feePayers := []*Account{
+		newRandomTestAccount(t),
+		newRandomTestAccount(t),
+		newRandomTestAccount(t),
+	}
+	for i, balance := range []uint64{
+		minBalance + 3*lamportsPerCreateNonceAccount,
+		minBalance + 2*lamportsPerCreateNonceAccount,
+		0,
+	} {
+		if balance == 0 {
+			continue
+		}
+		_, err := data.RequestBlockchainAirdrop(ctx, feePayers[i].PublicKey().ToBase58(), balance)
+		require.NoError(t, err)
+	}
+
+	pool, err := NewFeePayerPool(data, NewHighestAvailableBalanceSelectionPolicy(), minBalance, feePayers...)
+	require.NoError(t, err)
+
+	var reservations []*FeePayerReservation
+	for _, expected := range []*Account{feePayers[0], feePayers[0], feePayers[1], feePayers[0], feePayers[1]} {
+		reservation, err := pool.ReserveForNonceAccount(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, expected.PublicKey().ToBase58(), reservation.FeePayer().PublicKey().ToBase58())
+		assert.Equal(t, expected.PrivateKey().ToBase58(), reservation.FeePayer().PrivateKey().ToBase58())
+		reservations = append(reservations, reservation)
+	}
+
+	// All fee payers would go below the minimum balance
+	_, err = pool.ReserveForNonceAccount(ctx)
+	assert.Equal(t, ErrNoFeePayerAvailable, err)
+
+	balances, err := pool.GetBalances(ctx)
+	require.NoError(t, err)
+	require.Len(t, balances, 3)
+	for i, expectedReserved := range []uint64{3 * lamportsPerCreateNonceAccount, 2 * lamportsPerCreateNonceAccount, 0} {
+		assert.Equal(t, feePayers[i].PublicKey().ToBase58(), balances[i].Account.PublicKey().ToBase58())
+		assert.Nil(t, balances[i].Account.PrivateKey())
+		assert.Equal(t, expectedReserved, balances[i].Reserved)
+	}
+	assert.Equal(t, minBalance, balances[0].Available())
+	assert.Equal(t, minBalance, balances[1].Available())
+	assert.EqualValues(t, 0, balances[2].Available())
+
+	// Releasing frees up the fee payer, and releasing twice is a no-op
+	reservations[0].Release()
+	reservations[0].Release()
+
+	reservation, err := pool.ReserveForNonceAccount(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, feePayers[0].PublicKey().ToBase58(), reservation.FeePayer().PublicKey().ToBase58())
+
+	_, err = pool.ReserveForNonceAccount(ctx)
+	assert.Equal(t, ErrNoFeePayerAvailable, err)
+
+}
+
+func TestFeePayerPool_RoundRobin(t *testing.T) {
+	ctx := context.Background()
+	data := datatest.NewDataProvider()
+
+	subsidizerAccount = newRandomTestAccount(t)
+	retiredSubsidizerAccounts = nil
+
+	feePayers := []*Account{
+		newRandomTestAccount(t),
+		newRandomTestAccount(t),
+	}
+	for _, feePayer := range feePayers {
+		_, err := data.RequestBlockchainAirdrop(ctx, feePayer.PublicKey().ToBase58(), 1_000_000_000)
+		require.NoError(t, err)
+	}
+
+	pool, err := NewFeePayerPool(data, NewRoundRobinSelectionPolicy(), 0, feePayers...)
+	require.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		reservation, err := pool.ReserveForNonceAccount(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, feePayers[i%2].PublicKey().ToBase58(), reservation.FeePayer().PublicKey().ToBase58())
+	}
+}
+
+func TestFeePayerPool_Validation(t *testing.T) {
+	ctx := context.Background()
+	data := datatest.NewDataProvider()
+
+	subsidizerAccount = newRandomTestAccount(t)
+	retiredSubsidizerAccounts = nil
+
+	policy := NewRoundRobinSelectionPolicy()
+	feePayer := newRandomTestAccount(t)
+	publicKeyOnly, err := NewAccountFromPublicKey(feePayer.PublicKey())
+	require.NoError(t, err)
+
+	_, err = NewFeePayerPool(data, policy, 0)
+	assert.Error(t, err)
+
+	_, err = NewFeePayerPool(data, policy, 0, publicKeyOnly)
+	assert.Error(t, err)
+
+	_, err = NewFeePayerPool(data, policy, 0, feePayer, feePayer)
+	assert.Error(t, err)
+
+	_, err = NewFeePayerPool(data, policy, 0, subsidizerAccount)
+	assert.Error(t, err)
+
+	key, err := vault.CreateKey()
+	require.NoError(t, err)
+
+	_, err = LoadFeePayerPool(ctx, data, policy, 0, key.PublicKey)
+	assert.Error(t, err)
+
+	require.NoError(t, data.SaveKey(ctx, key))
+
+	pool, err := LoadFeePayerPool(ctx, data, policy, 0, key.PublicKey)
+	require.NoError(t, err)
+
+	balances, err := pool.GetBalances(ctx)
+	require.NoError(t, err)
+	require.Len(t, balances, 1)
+	assert.Equal(t, key.PublicKey, balances[0].Account.PublicKey().ToBase58())
+	assert.EqualValues(t, 0, balances[0].Balance)
+}
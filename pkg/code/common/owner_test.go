@@ -11,14 +11,14 @@ import (
 	commonpb "github.com/code-payments/code-protobuf-api/generated/go/common/v1"
 
 	timelock_token_v1 "github.com/code-payments/code-server/pkg/solana/timelock/v1"
-	code_data "github.com/code-payments/code-server/pkg/code/data"
+	datatest "github.com/code-payments/code-server/pkg/code/data/test"
 	"github.com/code-payments/code-server/pkg/code/data/account"
 	"github.com/code-payments/code-server/pkg/code/data/phone"
 )
 
 func TestGetOwnerMetadata_User12Words(t *testing.T) {
 	ctx := context.Background()
-	data := code_data.NewTestDataProvider()
+	data := datatest.NewDataProvider()
 
 	subsidizerAccount = newRandomTestAccount(t)
 
@@ -74,7 +74,7 @@ func TestGetOwnerMetadata_User12Words(t *testing.T) {
 
 func TestGetOwnerMetadata_RemoteSendGiftCard(t *testing.T) {
 	ctx := context.Background()
-	data := code_data.NewTestDataProvider()
+	data := datatest.NewDataProvider()
 
 	subsidizerAccount = newRandomTestAccount(t)
 
@@ -117,7 +117,7 @@ func TestGetOwnerMetadata_RemoteSendGiftCard(t *testing.T) {
 
 func TestGetLatestTokenAccountRecordsForOwner(t *testing.T) {
 	ctx := context.Background()
-	data := code_data.NewTestDataProvider()
+	data := datatest.NewDataProvider()
 
 	subsidizerAccount = newRandomTestAccount(t)
 
@@ -1,8 +1,11 @@
 package common
 
 import (
+	"bytes"
 	"context"
+	"crypto/ed25519"
 	"errors"
+	"sort"
 	"sync"
 
 	"github.com/newrelic/go-agent/v3/newrelic"
@@ -17,8 +20,8 @@ import (
 const (
 	// Important Note: Be very careful changing this value, as it will completely
 	// change timelock PDAs and have consequences with existing splitter treasuries.
-	//
-	// todo: configurable
+	// Use LoadSubsidizers to rotate to a new subsidizer, which keeps this one loaded
+	// as a retired subsidizer.
 	realSubsidizerPublicKey = "codeHy87wGD5oMRLG75qKqsSi1vWE3oxNyYmXo5F9YR"
 
 	// Ensure this is a large enough buffer. The enforcement of a min balance isn't
@@ -48,18 +51,26 @@ var (
 		fulfillment.VerifyCommitmentProof:                 5000,     // 0.000005 SOL (5000 lamports per signature)
 		fulfillment.OpenCommitmentVault:                   2050000,  // 0.00205 SOL
 		fulfillment.CloseCommitmentVault:                  5000,     // 0.000005 SOL (5000 lamports per signature)
+		fulfillment.TransferToTreasuryPool:                10000,    // 0.00001 SOL (5000 lamports per signature)
 	}
 	lamportsPerCreateNonceAccount uint64 = 1450000 // 0.00145 SOL
 )
 
 var (
-	subsidizerAccountLock sync.RWMutex
-	subsidizerAccount     *Account
+	subsidizerAccountLock     sync.RWMutex
+	subsidizerAccount         *Account
+	retiredSubsidizerAccounts map[string]*Account
 
 	ErrSubsidizerRequiresFunding = errors.New("subsidizer requires funding")
+	ErrSubsidizerNotFound        = errors.New("subsidizer not found")
 )
 
-// GetSubsidizer gets the subsidizer account, as initially loaded in LoadSubsidizer
+// PriorityFeeEstimator estimates the maximum priority fee, in lamports, that the
+// subsidizer pays for a single fulfillment of the provided type.
+type PriorityFeeEstimator func(ctx context.Context, fulfillmentType fulfillment.Type) (uint64, error)
+
+// GetSubsidizer gets the current subsidizer account, as initially loaded in
+// LoadSubsidizers. It's used as the time authority for new timelock accounts.
 func GetSubsidizer() *Account {
 	subsidizerAccountLock.RLock()
 	defer subsidizerAccountLock.RUnlock()
@@ -68,46 +79,139 @@ func GetSubsidizer() *Account {
 		panic("subsidizer wasn't loaded from db")
 	}
 
-	copied, err := NewAccountFromPrivateKeyString(subsidizerAccount.PrivateKey().ToBase58())
-	if err != nil {
-		panic(err)
+	return copySubsidizer(subsidizerAccount)
+}
+
+// GetSubsidizerByPublicKey gets the current or retired subsidizer account with
+// the provided public key. Existing timelock accounts keep the subsidizer that
+// was current when they were created as their time authority, so this must be
+// used when operating on them.
+func GetSubsidizerByPublicKey(publicKey string) (*Account, error) {
+	subsidizerAccountLock.RLock()
+	defer subsidizerAccountLock.RUnlock()
+
+	if subsidizerAccount == nil {
+		panic("subsidizer wasn't loaded from db")
 	}
 
-	return copied
+	if subsidizerAccount.PublicKey().ToBase58() == publicKey {
+		return copySubsidizer(subsidizerAccount), nil
+	}
+
+	retired, ok := retiredSubsidizerAccounts[publicKey]
+	if !ok {
+		return nil, ErrSubsidizerNotFound
+	}
+	return copySubsidizer(retired), nil
+}
+
+// IsSubsidizer returns whether the public key belongs to the current or a
+// retired subsidizer.
+func IsSubsidizer(publicKey string) bool {
+	_, err := GetSubsidizerByPublicKey(publicKey)
+	return err == nil
+}
+
+// getAllSubsidizers gets the current subsidizer, followed by all retired
+// subsidizers in a stable order
+func getAllSubsidizers() []*Account {
+	subsidizerAccountLock.RLock()
+	defer subsidizerAccountLock.RUnlock()
+
+	if subsidizerAccount == nil {
+		panic("subsidizer wasn't loaded from db")
+	}
+
+	var retired []string
+	for publicKey := range retiredSubsidizerAccounts {
+		retired = append(retired, publicKey)
+	}
+	sort.Strings(retired)
+
+	res := []*Account{copySubsidizer(subsidizerAccount)}
+	for _, publicKey := range retired {
+		res = append(res, copySubsidizer(retiredSubsidizerAccounts[publicKey]))
+	}
+	return res
 }
 
 // LoadProductionSubsidizer loads the production subsidizer account by it's public
 // key over the provided data provider. This should be done exactly once on app launch.
 // Use GetSubsidizer to get the Account struct.
 func LoadProductionSubsidizer(ctx context.Context, data code_data.Provider) error {
+	return LoadSubsidizers(ctx, data, realSubsidizerPublicKey)
+}
+
+// LoadSubsidizers loads the current subsidizer, along with any retired subsidizers,
+// by their public keys over the provided data provider. This should be done exactly
+// once on app launch.
+//
+// Rotating the subsidizer is done by loading the new subsidizer as the current one,
+// and moving the previous one to the set of retired subsidizers. New timelock accounts
+// use the current subsidizer, while existing accounts continue to be operated on using
+// the retired subsidizer stored as their time authority. Intent validation resolves
+// an owner's existing accounts against every loaded subsidizer. A retired subsidizer
+// must remain loaded until all of its timelock accounts are closed.
+func LoadSubsidizers(ctx context.Context, data code_data.Provider, current string, retired ...string) error {
 	subsidizerAccountLock.Lock()
 	defer subsidizerAccountLock.Unlock()
 
 	if subsidizerAccount != nil {
-		if subsidizerAccount.PublicKey().ToBase58() == realSubsidizerPublicKey {
+		if subsidizerAccount.PublicKey().ToBase58() == current {
 			return nil
 		}
 
 		return errors.New("unexpected subsidizer account already loaded")
 	}
 
-	vaultRecord, err := data.GetKey(ctx, realSubsidizerPublicKey)
+	currentAccount, err := loadSubsidizer(ctx, data, current)
 	if err != nil {
 		return err
 	}
 
+	retiredAccounts := make(map[string]*Account)
+	for _, publicKey := range retired {
+		if publicKey == current {
+			return errors.New("current subsidizer cannot be retired")
+		}
+
+		retiredAccount, err := loadSubsidizer(ctx, data, publicKey)
+		if err != nil {
+			return err
+		}
+		retiredAccounts[publicKey] = retiredAccount
+	}
+
+	subsidizerAccount = currentAccount
+	retiredSubsidizerAccounts = retiredAccounts
+
+	return nil
+}
+
+func loadSubsidizer(ctx context.Context, data code_data.Provider, publicKey string) (*Account, error) {
+	vaultRecord, err := data.GetKey(ctx, publicKey)
+	if err != nil {
+		return nil, err
+	}
+
 	account, err := NewAccountFromPrivateKeyString(vaultRecord.PrivateKey)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if account.PublicKey().ToBase58() != realSubsidizerPublicKey {
-		return errors.New("subsidizer public key mismatch")
+	if account.PublicKey().ToBase58() != publicKey {
+		return nil, errors.New("subsidizer public key mismatch")
 	}
 
-	subsidizerAccount = account
+	return account, nil
+}
 
-	return nil
+func copySubsidizer(account *Account) *Account {
+	copied, err := NewAccountFromPrivateKeyString(account.PrivateKey().ToBase58())
+	if err != nil {
+		panic(err)
+	}
+	return copied
 }
 
 // InjectTestSubsidizer injects a provided account as a subsidizer for testing
@@ -121,9 +225,59 @@ func InjectTestSubsidizer(ctx context.Context, data code_data.Provider, testAcco
 	}
 
 	subsidizerAccount = testAccount
+	retiredSubsidizerAccounts = nil
+	return nil
+}
+
+// InjectTestRetiredSubsidizer injects a provided account as a retired subsidizer
+// for testing purposes. Do not call this in a production setting.
+func InjectTestRetiredSubsidizer(ctx context.Context, data code_data.Provider, testAccount *Account) error {
+	subsidizerAccountLock.Lock()
+	defer subsidizerAccountLock.Unlock()
+
+	if subsidizerAccount != nil && subsidizerAccount.PublicKey().ToBase58() == realSubsidizerPublicKey {
+		return errors.New("attempted to inject test subsidizer in production environment")
+	}
+
+	if retiredSubsidizerAccounts == nil {
+		retiredSubsidizerAccounts = make(map[string]*Account)
+	}
+	retiredSubsidizerAccounts[testAccount.PublicKey().ToBase58()] = testAccount
 	return nil
 }
 
+// SignWithSubsidizers signs the transaction with every loaded subsidizer that's a
+// required signer. This covers transactions for timelock accounts whose time
+// authority is a retired subsidizer.
+func SignWithSubsidizers(txn *solana.Transaction, otherSigners ...ed25519.PrivateKey) error {
+	subsidizerAccountLock.RLock()
+	candidates := []*Account{subsidizerAccount}
+	for _, retired := range retiredSubsidizerAccounts {
+		candidates = append(candidates, retired)
+	}
+	subsidizerAccountLock.RUnlock()
+
+	var signers []ed25519.PrivateKey
+	for _, candidate := range candidates {
+		if candidate == nil {
+			continue
+		}
+
+		for i, account := range txn.Message.Accounts {
+			if i >= int(txn.Message.Header.NumSignatures) {
+				break
+			}
+
+			if bytes.Equal(account, candidate.PublicKey().ToBytes()) {
+				signers = append(signers, candidate.PrivateKey().ToBytes())
+				break
+			}
+		}
+	}
+
+	return txn.Sign(append(signers, otherSigners...)...)
+}
+
 // GetCurrentSubsidizerBalance returns the subsidizer's current balance in lamports.
 func GetCurrentSubsidizerBalance(ctx context.Context, data code_data.Provider) (uint64, error) {
 	accountInfo, err := data.GetBlockchainAccountInfo(ctx, GetSubsidizer().PublicKey().ToBase58(), solana.CommitmentProcessed)
@@ -134,10 +288,9 @@ func GetCurrentSubsidizerBalance(ctx context.Context, data code_data.Provider) (
 }
 
 // EstimateUsedSubsidizerBalance estimates the number of lamports that will be used
-// by in flight fulfillments.
-//
-// todo: This assumes fees are constant, which might not be the case in the future.
-func EstimateUsedSubsidizerBalance(ctx context.Context, data code_data.Provider) (uint64, error) {
+// by in flight fulfillments and nonce accounts being created by the subsidizer.
+// Priority fees are included when an estimator is provided.
+func EstimateUsedSubsidizerBalance(ctx context.Context, data code_data.Provider, priorityFees PriorityFeeEstimator) (uint64, error) {
 	var fees uint64
 
 	pendingFulfillmentsByType, err := data.GetPendingFulfillmentCountByType(ctx)
@@ -152,26 +305,38 @@ func EstimateUsedSubsidizerBalance(ctx context.Context, data code_data.Provider)
 		}
 
 		fees += uint64(count) * lamportsConsumed
+
+		if priorityFees != nil {
+			priorityFee, err := priorityFees(ctx, fulfillmentType)
+			if err != nil {
+				return 0, err
+			}
+			fees += uint64(count) * priorityFee
+		}
 	}
 
-	numNoncesBeingCreated, err := data.GetNonceCountByState(ctx, nonce.StateUnknown)
-	if err != nil {
-		return 0, err
+	// Only nonces funded by the subsidizer count. Nonces without a fee payer
+	// predate it being tracked, and were always funded by the subsidizer.
+	for _, feePayer := range []string{GetSubsidizer().PublicKey().ToBase58(), ""} {
+		numNoncesBeingCreated, err := data.GetNonceCountByStateAndFeePayer(ctx, nonce.StateUnknown, feePayer)
+		if err != nil {
+			return 0, err
+		}
+		fees += lamportsPerCreateNonceAccount * numNoncesBeingCreated
 	}
-	fees += lamportsPerCreateNonceAccount * numNoncesBeingCreated
 
 	return fees, nil
 }
 
 // EnforceMinimumSubsidizerBalance returns ErrSubsidizerRequiresFunding if the
 // subsidizer's estimated available balance breaks a given threshold.
-func EnforceMinimumSubsidizerBalance(ctx context.Context, data code_data.Provider) error {
+func EnforceMinimumSubsidizerBalance(ctx context.Context, data code_data.Provider, priorityFees PriorityFeeEstimator) error {
 	balance, err := GetCurrentSubsidizerBalance(ctx, data)
 	if err != nil {
 		return err
 	}
 
-	fees, err := EstimateUsedSubsidizerBalance(ctx, data)
+	fees, err := EstimateUsedSubsidizerBalance(ctx, data, priorityFees)
 	if err != nil {
 		return err
 	}
@@ -1,23 +1,31 @@
 package common
 
 import (
+	"bytes"
 	"context"
+	"crypto/ed25519"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/code-payments/code-server/pkg/pointer"
-	code_data "github.com/code-payments/code-server/pkg/code/data"
+	"github.com/code-payments/code-server/pkg/solana"
+	"github.com/code-payments/code-server/pkg/solana/memo"
+	datatest "github.com/code-payments/code-server/pkg/code/data/test"
 	"github.com/code-payments/code-server/pkg/code/data/action"
 	"github.com/code-payments/code-server/pkg/code/data/fulfillment"
 	"github.com/code-payments/code-server/pkg/code/data/intent"
 	"github.com/code-payments/code-server/pkg/code/data/nonce"
+	"github.com/code-payments/code-server/pkg/code/data/vault"
 )
 
 func TestEstimateUsedSubsidizerBalance(t *testing.T) {
 	ctx := context.Background()
-	data := code_data.NewTestDataProvider()
+	data := datatest.NewDataProvider()
+
+	subsidizerAccount = newRandomTestAccount(t)
+	feePayer := newRandomTestAccount(t)
 
 	fulfillmentRecords := []*fulfillment.Record{
 		// These records are included in fee calculation
@@ -36,11 +44,12 @@ func TestEstimateUsedSubsidizerBalance(t *testing.T) {
 	nonceRecords := []*nonce.Record{
 		// These records are included in fee calculation
 		{Address: "n1", State: nonce.StateUnknown},
-		{Address: "n2", State: nonce.StateUnknown},
-		{Address: "n3", State: nonce.StateUnknown},
+		{Address: "n2", State: nonce.StateUnknown, FeePayer: subsidizerAccount.PublicKey().ToBase58()},
+		{Address: "n3", State: nonce.StateUnknown, FeePayer: subsidizerAccount.PublicKey().ToBase58()},
 
 		// Theese records aren't included in fee calculation
 		{Address: "n4", State: nonce.StateInvalid},
+		{Address: "n7", State: nonce.StateUnknown, FeePayer: feePayer.PublicKey().ToBase58()},
 		{Address: "n5", State: nonce.StateReserved, Blockhash: "bh5"},
 		{Address: "n6", State: nonce.StateReleased, Blockhash: "bh6"},
 	}
@@ -50,11 +59,113 @@ func TestEstimateUsedSubsidizerBalance(t *testing.T) {
 		require.NoError(t, data.SaveNonce(ctx, nonceRecord))
 	}
 
-	fees, err := EstimateUsedSubsidizerBalance(ctx, data)
+	fees, err := EstimateUsedSubsidizerBalance(ctx, data, nil)
 	require.NoError(t, err)
 	assert.EqualValues(
 		t,
 		3*lamportsPerCreateNonceAccount+2*lamportsByFulfillment[fulfillment.PermanentPrivacyTransferWithAuthority]+lamportsByFulfillment[fulfillment.InitializeLockedTimelockAccount],
 		fees,
 	)
+
+	priorityFees := func(_ context.Context, fulfillmentType fulfillment.Type) (uint64, error) {
+		if fulfillmentType == fulfillment.PermanentPrivacyTransferWithAuthority {
+			return 1_400, nil
+		}
+		return 0, nil
+	}
+	fees, err = EstimateUsedSubsidizerBalance(ctx, data, priorityFees)
+	require.NoError(t, err)
+	assert.EqualValues(
+		t,
+		3*lamportsPerCreateNonceAccount+2*(lamportsByFulfillment[fulfillment.PermanentPrivacyTransferWithAuthority]+1_400)+lamportsByFulfillment[fulfillment.InitializeLockedTimelockAccount],
+		fees,
+	)
+}
+
+func TestLoadSubsidizers(t *testing.T) {
+	ctx := context.Background()
+	data := datatest.NewDataProvider()
+
+	subsidizerAccount = nil
+	retiredSubsidizerAccounts = nil
+	defer func() {
+		subsidizerAccount = nil
+		retiredSubsidizerAccounts = nil
+	}()
+
+	var keys []*vault.Record
+	for i := 0; i < 3; i++ {
+		key, err := vault.CreateKey()
+		require.NoError(t, err)
+		require.NoError(t, data.SaveKey(ctx, key))
+		keys = append(keys, key)
+	}
+
+	assert.Error(t, LoadSubsidizers(ctx, data, keys[0].PublicKey, keys[0].PublicKey))
+	assert.Error(t, LoadSubsidizers(ctx, data, keys[0].PublicKey, "unknown"))
+	assert.Nil(t, subsidizerAccount)
+
+	require.NoError(t, LoadSubsidizers(ctx, data, keys[0].PublicKey, keys[1].PublicKey))
+	require.NoError(t, LoadSubsidizers(ctx, data, keys[0].PublicKey, keys[1].PublicKey))
+	assert.Error(t, LoadSubsidizers(ctx, data, keys[1].PublicKey))
+
+	assert.Equal(t, keys[0].PublicKey, GetSubsidizer().PublicKey().ToBase58())
+
+	for _, key := range keys[:2] {
+		subsidizer, err := GetSubsidizerByPublicKey(key.PublicKey)
+		require.NoError(t, err)
+		assert.Equal(t, key.PrivateKey, subsidizer.PrivateKey().ToBase58())
+		assert.True(t, IsSubsidizer(key.PublicKey))
+	}
+
+	_, err := GetSubsidizerByPublicKey(keys[2].PublicKey)
+	assert.Equal(t, ErrSubsidizerNotFound, err)
+	assert.False(t, IsSubsidizer(keys[2].PublicKey))
+}
+
+func TestSignWithSubsidizers(t *testing.T) {
+	ctx := context.Background()
+
+	subsidizerAccount = newRandomTestAccount(t)
+	retiredSubsidizerAccounts = nil
+	retired := newRandomTestAccount(t)
+	require.NoError(t, InjectTestRetiredSubsidizer(ctx, nil, retired))
+
+	other := newRandomTestAccount(t)
+
+	for _, tc := range []struct {
+		signers []*Account
+	}{
+		{signers: []*Account{subsidizerAccount}},
+		{signers: []*Account{subsidizerAccount, retired}},
+		{signers: []*Account{subsidizerAccount, retired, other}},
+	} {
+		var instructions []solana.Instruction
+		for _, signer := range tc.signers {
+			instructions = append(instructions, memo.Instruction(signer.PublicKey().ToBase58()))
+			instructions[len(instructions)-1].Accounts = append(
+				instructions[len(instructions)-1].Accounts,
+				solana.NewReadonlyAccountMeta(signer.PublicKey().ToBytes(), true),
+			)
+		}
+		txn := solana.NewTransaction(subsidizerAccount.PublicKey().ToBytes(), instructions...)
+
+		var otherSigners []ed25519.PrivateKey
+		if len(tc.signers) > 2 {
+			otherSigners = append(otherSigners, other.PrivateKey().ToBytes())
+		}
+		require.NoError(t, SignWithSubsidizers(&txn, otherSigners...))
+
+		require.Len(t, txn.Signatures, len(tc.signers))
+		for i, signer := range tc.signers {
+			signerIndex := -1
+			for j, account := range txn.Message.Accounts {
+				if bytes.Equal(account, signer.PublicKey().ToBytes()) {
+					signerIndex = j
+				}
+			}
+			require.True(t, signerIndex >= 0, i)
+			assert.True(t, ed25519.Verify(signer.PublicKey().ToBytes(), txn.Message.Marshal(), txn.Signatures[signerIndex][:]))
+		}
+	}
 }
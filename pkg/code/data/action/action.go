@@ -19,6 +19,7 @@ const (
 	NoPrivacyWithdraw
 	PrivateTransfer // Incorprorates all client-side private movement of funds. Backend processes don't care about the distinction, yet.
 	SaveRecentRoot
+	TreasuryPoolFunding
 )
 
 type State uint8
@@ -167,3 +168,28 @@ func (s State) String() string {
 
 	return "unknown"
 }
+
+func (t Type) String() string {
+	switch t {
+	case UnknownType:
+		return "unknown"
+	case OpenAccount:
+		return "open_account"
+	case CloseEmptyAccount:
+		return "close_empty_account"
+	case CloseDormantAccount:
+		return "close_dormant_account"
+	case NoPrivacyTransfer:
+		return "no_privacy_transfer"
+	case NoPrivacyWithdraw:
+		return "no_privacy_withdraw"
+	case PrivateTransfer:
+		return "private_transfer"
+	case SaveRecentRoot:
+		return "save_recent_root"
+	case TreasuryPoolFunding:
+		return "treasury_pool_funding"
+	}
+
+	return "unknown"
+}
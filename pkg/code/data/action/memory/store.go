@@ -197,8 +197,14 @@ func (s *store) Update(ctx context.Context, record *action.Record) error {
 	defer s.mu.Unlock()
 
 	if item := s.find(record); item != nil {
-		if record.ActionType == action.CloseDormantAccount {
+		switch record.ActionType {
+		case action.CloseDormantAccount:
 			item.Quantity = pointer.Uint64Copy(record.Quantity)
+		case action.NoPrivacyTransfer:
+			// Deferred transfers can only have their quantity set once
+			if item.Quantity == nil {
+				item.Quantity = pointer.Uint64Copy(record.Quantity)
+			}
 		}
 		item.State = record.State
 		return nil
@@ -110,9 +110,14 @@ func (m *model) dbUpdate(ctx context.Context, db *sqlx.DB) error {
 			m.State,
 		}
 
-		if m.ActionType == uint(action.CloseDormantAccount) {
+		switch m.ActionType {
+		case uint(action.CloseDormantAccount):
 			quantityUpdateStmt = ", quantity = $4"
 			params = append(params, m.Quantity)
+		case uint(action.NoPrivacyTransfer):
+			// Deferred transfers can only have their quantity set once
+			quantityUpdateStmt = ", quantity = COALESCE(quantity, $4)"
+			params = append(params, m.Quantity)
 		}
 
 		query := fmt.Sprintf(`UPDATE `+tableName+`
@@ -80,6 +80,38 @@ func testRoundTrip(t *testing.T, s action.Store) {
 		actual, err = s.GetById(ctx, expected.Intent, expected.ActionId)
 		require.NoError(t, err)
 		assertEquivalentRecords(t, &cloned, actual)
+
+		deferred := &action.Record{
+			Intent:     "deferred_intent",
+			IntentType: intent.SendPublicPayment,
+
+			ActionId:   0,
+			ActionType: action.NoPrivacyTransfer,
+
+			Source:      "source",
+			Destination: pointer.String("destination"),
+			Quantity:    nil,
+
+			State: action.StateUnknown,
+		}
+		require.NoError(t, s.PutAll(ctx, deferred))
+
+		deferred.Quantity = pointer.Uint64(12345)
+		deferred.State = action.StatePending
+		cloned = deferred.Clone()
+		require.NoError(t, s.Update(ctx, deferred))
+
+		actual, err = s.GetById(ctx, deferred.Intent, deferred.ActionId)
+		require.NoError(t, err)
+		assertEquivalentRecords(t, &cloned, actual)
+
+		// Once set, the quantity for a transfer can't be updated
+		deferred.Quantity = pointer.Uint64(1)
+		require.NoError(t, s.Update(ctx, deferred))
+
+		actual, err = s.GetById(ctx, deferred.Intent, deferred.ActionId)
+		require.NoError(t, err)
+		assert.EqualValues(t, 12345, *actual.Quantity)
 	})
 }
 
@@ -0,0 +1,108 @@
+package adminaudit
+
+import (
+	"errors"
+	"time"
+)
+
+type Result uint8
+
+const (
+	ResultUnknown Result = iota
+	ResultOk
+	ResultNotFound
+	ResultInvalidState
+	ResultUnsupported
+)
+
+// Record is an action an operator attempted through the admin service. Every
+// attempt is recorded, including those that were rejected, so there's a
+// persistent history of manual changes to intent state.
+type Record struct {
+	Id uint64
+
+	Operator string
+	Action   string
+	Reason   string
+
+	// The intent and fulfillment the action targets, when known
+	Intent        string
+	FulfillmentId uint64
+
+	Result Result
+	Detail string
+
+	CreatedAt time.Time
+}
+
+func (r *Record) Validate() error {
+	if len(r.Operator) == 0 {
+		return errors.New("operator is required")
+	}
+
+	if len(r.Action) == 0 {
+		return errors.New("action is required")
+	}
+
+	if len(r.Reason) == 0 {
+		return errors.New("reason is required")
+	}
+
+	if len(r.Intent) == 0 && r.FulfillmentId == 0 {
+		return errors.New("intent or fulfillment id is required")
+	}
+
+	if r.Result == ResultUnknown {
+		return errors.New("result is required")
+	}
+
+	return nil
+}
+
+func (r *Record) Clone() Record {
+	return Record{
+		Id: r.Id,
+
+		Operator: r.Operator,
+		Action:   r.Action,
+		Reason:   r.Reason,
+
+		Intent:        r.Intent,
+		FulfillmentId: r.FulfillmentId,
+
+		Result: r.Result,
+		Detail: r.Detail,
+
+		CreatedAt: r.CreatedAt,
+	}
+}
+
+func (r *Record) CopyTo(dst *Record) {
+	dst.Id = r.Id
+
+	dst.Operator = r.Operator
+	dst.Action = r.Action
+	dst.Reason = r.Reason
+
+	dst.Intent = r.Intent
+	dst.FulfillmentId = r.FulfillmentId
+
+	dst.Result = r.Result
+	dst.Detail = r.Detail
+
+	dst.CreatedAt = r.CreatedAt
+}
+
+func (r Result) String() string {
+	switch r {
+	case ResultOk:
+		return "ok"
+	case ResultNotFound:
+		return "not_found"
+	case ResultInvalidState:
+		return "invalid_state"
+	case ResultUnsupported:
+		return "unsupported"
+	}
+	return "unknown"
+}
@@ -0,0 +1,78 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/code-payments/code-server/pkg/code/data/adminaudit"
+)
+
+type store struct {
+	mu      sync.Mutex
+	records []*adminaudit.Record
+	last    uint64
+}
+
+func New() adminaudit.Store {
+	return &store{
+		records: make([]*adminaudit.Record, 0),
+	}
+}
+
+func (s *store) reset() {
+	s.mu.Lock()
+	s.records = make([]*adminaudit.Record, 0)
+	s.last = 0
+	s.mu.Unlock()
+}
+
+// Put implements adminaudit.Store.Put
+func (s *store) Put(_ context.Context, data *adminaudit.Record) error {
+	if err := data.Validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.last++
+
+	data.Id = s.last
+	if data.CreatedAt.IsZero() {
+		data.CreatedAt = time.Now()
+	}
+
+	cloned := data.Clone()
+	s.records = append(s.records, &cloned)
+
+	return nil
+}
+
+// GetAllByIntent implements adminaudit.Store.GetAllByIntent
+func (s *store) GetAllByIntent(_ context.Context, intent string) ([]*adminaudit.Record, error) {
+	return s.filter(func(item *adminaudit.Record) bool {
+		return item.Intent == intent
+	}), nil
+}
+
+// GetAllByFulfillment implements adminaudit.Store.GetAllByFulfillment
+func (s *store) GetAllByFulfillment(_ context.Context, fulfillmentId uint64) ([]*adminaudit.Record, error) {
+	return s.filter(func(item *adminaudit.Record) bool {
+		return item.FulfillmentId == fulfillmentId
+	}), nil
+}
+
+func (s *store) filter(fn func(item *adminaudit.Record) bool) []*adminaudit.Record {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var res []*adminaudit.Record
+	for _, item := range s.records {
+		if fn(item) {
+			cloned := item.Clone()
+			res = append(res, &cloned)
+		}
+	}
+	return res
+}
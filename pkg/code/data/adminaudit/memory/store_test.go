@@ -0,0 +1,15 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/code-payments/code-server/pkg/code/data/adminaudit/tests"
+)
+
+func TestAdminAuditMemoryStore(t *testing.T) {
+	testStore := New()
+	teardown := func() {
+		testStore.(*store).reset()
+	}
+	tests.RunTests(t, testStore, teardown)
+}
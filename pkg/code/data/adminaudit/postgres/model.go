@@ -0,0 +1,120 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	pgutil "github.com/code-payments/code-server/pkg/database/postgres"
+	"github.com/code-payments/code-server/pkg/code/data/adminaudit"
+)
+
+const (
+	tableName = "codewallet__core_adminaudit"
+
+	allColumns = `id, operator, action, reason, intent, fulfillment_id, result, detail, created_at`
+)
+
+type model struct {
+	Id sql.NullInt64 `db:"id"`
+
+	Operator string `db:"operator"`
+	Action   string `db:"action"`
+	Reason   string `db:"reason"`
+
+	Intent        string `db:"intent"`
+	FulfillmentId uint64 `db:"fulfillment_id"`
+
+	Result uint8  `db:"result"`
+	Detail string `db:"detail"`
+
+	CreatedAt time.Time `db:"created_at"`
+}
+
+func toModel(obj *adminaudit.Record) (*model, error) {
+	if err := obj.Validate(); err != nil {
+		return nil, err
+	}
+
+	if obj.CreatedAt.IsZero() {
+		obj.CreatedAt = time.Now().UTC()
+	}
+
+	return &model{
+		Id:            sql.NullInt64{Int64: int64(obj.Id), Valid: true},
+		Operator:      obj.Operator,
+		Action:        obj.Action,
+		Reason:        obj.Reason,
+		Intent:        obj.Intent,
+		FulfillmentId: obj.FulfillmentId,
+		Result:        uint8(obj.Result),
+		Detail:        obj.Detail,
+		CreatedAt:     obj.CreatedAt,
+	}, nil
+}
+
+func fromModel(obj *model) *adminaudit.Record {
+	return &adminaudit.Record{
+		Id:            uint64(obj.Id.Int64),
+		Operator:      obj.Operator,
+		Action:        obj.Action,
+		Reason:        obj.Reason,
+		Intent:        obj.Intent,
+		FulfillmentId: obj.FulfillmentId,
+		Result:        adminaudit.Result(obj.Result),
+		Detail:        obj.Detail,
+		CreatedAt:     obj.CreatedAt,
+	}
+}
+
+func (m *model) dbPut(ctx context.Context, db *sqlx.DB) error {
+	return pgutil.ExecuteInTx(ctx, db, sql.LevelDefault, func(tx *sqlx.Tx) error {
+		query := `INSERT INTO ` + tableName + `
+			(operator, action, reason, intent, fulfillment_id, result, detail, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			RETURNING ` + allColumns
+
+		return tx.QueryRowxContext(
+			ctx,
+			query,
+			m.Operator,
+			m.Action,
+			m.Reason,
+			m.Intent,
+			m.FulfillmentId,
+			m.Result,
+			m.Detail,
+			m.CreatedAt,
+		).StructScan(m)
+	})
+}
+
+func dbGetAllByIntent(ctx context.Context, db *sqlx.DB, intent string) ([]*model, error) {
+	var res []*model
+
+	query := `SELECT ` + allColumns + ` FROM ` + tableName + `
+		WHERE intent = $1
+		ORDER BY id ASC`
+
+	err := db.SelectContext(ctx, &res, query, intent)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func dbGetAllByFulfillment(ctx context.Context, db *sqlx.DB, fulfillmentId uint64) ([]*model, error) {
+	var res []*model
+
+	query := `SELECT ` + allColumns + ` FROM ` + tableName + `
+		WHERE fulfillment_id = $1
+		ORDER BY id ASC`
+
+	err := db.SelectContext(ctx, &res, query, fulfillmentId)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,64 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/code-payments/code-server/pkg/code/data/adminaudit"
+)
+
+type store struct {
+	db *sqlx.DB
+}
+
+func New(db *sql.DB) adminaudit.Store {
+	return &store{
+		db: sqlx.NewDb(db, "pgx"),
+	}
+}
+
+// Put implements adminaudit.Store.Put
+func (s *store) Put(ctx context.Context, record *adminaudit.Record) error {
+	m, err := toModel(record)
+	if err != nil {
+		return err
+	}
+
+	err = m.dbPut(ctx, s.db)
+	if err != nil {
+		return err
+	}
+
+	res := fromModel(m)
+	res.CopyTo(record)
+
+	return nil
+}
+
+// GetAllByIntent implements adminaudit.Store.GetAllByIntent
+func (s *store) GetAllByIntent(ctx context.Context, intent string) ([]*adminaudit.Record, error) {
+	models, err := dbGetAllByIntent(ctx, s.db, intent)
+	if err != nil {
+		return nil, err
+	}
+	return fromModels(models), nil
+}
+
+// GetAllByFulfillment implements adminaudit.Store.GetAllByFulfillment
+func (s *store) GetAllByFulfillment(ctx context.Context, fulfillmentId uint64) ([]*adminaudit.Record, error) {
+	models, err := dbGetAllByFulfillment(ctx, s.db, fulfillmentId)
+	if err != nil {
+		return nil, err
+	}
+	return fromModels(models), nil
+}
+
+func fromModels(models []*model) []*adminaudit.Record {
+	res := make([]*adminaudit.Record, len(models))
+	for i, m := range models {
+		res[i] = fromModel(m)
+	}
+	return res
+}
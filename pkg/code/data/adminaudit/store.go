@@ -0,0 +1,18 @@
+package adminaudit
+
+import (
+	"context"
+)
+
+type Store interface {
+	// Put creates a new audit record
+	Put(ctx context.Context, record *Record) error
+
+	// GetAllByIntent gets all audit records for actions targeting an intent, in
+	// ascending order of creation
+	GetAllByIntent(ctx context.Context, intent string) ([]*Record, error)
+
+	// GetAllByFulfillment gets all audit records for actions targeting a
+	// fulfillment, in ascending order of creation
+	GetAllByFulfillment(ctx context.Context, fulfillmentId uint64) ([]*Record, error)
+}
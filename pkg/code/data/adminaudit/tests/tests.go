@@ -0,0 +1,125 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/code-payments/code-server/pkg/code/data/adminaudit"
+)
+
+func RunTests(t *testing.T, s adminaudit.Store, teardown func()) {
+	for _, tf := range []func(t *testing.T, s adminaudit.Store){
+		testRoundTrip,
+		testValidation,
+	} {
+		tf(t, s)
+		teardown()
+	}
+}
+
+func testRoundTrip(t *testing.T, s adminaudit.Store) {
+	t.Run("testRoundTrip", func(t *testing.T) {
+		ctx := context.Background()
+
+		records, err := s.GetAllByIntent(ctx, "intent1")
+		require.NoError(t, err)
+		assert.Empty(t, records)
+
+		records, err = s.GetAllByFulfillment(ctx, 1)
+		require.NoError(t, err)
+		assert.Empty(t, records)
+
+		var expected []adminaudit.Record
+		for i, result := range []adminaudit.Result{
+			adminaudit.ResultInvalidState,
+			adminaudit.ResultOk,
+		} {
+			record := newTestRecord("intent1", 1)
+			record.Result = result
+			record.Detail = "detail" + string(rune('a'+i))
+
+			require.NoError(t, s.Put(ctx, record))
+			assert.True(t, record.Id > 0)
+			assert.False(t, record.CreatedAt.IsZero())
+
+			expected = append(expected, record.Clone())
+		}
+
+		// Actions targeting only an intent aren't tied to a fulfillment
+		record := newTestRecord("intent1", 0)
+		record.Action = "revoke_intent"
+		require.NoError(t, s.Put(ctx, record))
+		intentOnly := record.Clone()
+
+		require.NoError(t, s.Put(ctx, newTestRecord("intent2", 2)))
+
+		records, err = s.GetAllByIntent(ctx, "intent1")
+		require.NoError(t, err)
+		require.Len(t, records, 3)
+		for i, actual := range expected {
+			assertEquivalentRecords(t, &actual, records[i])
+		}
+		assertEquivalentRecords(t, &intentOnly, records[2])
+
+		records, err = s.GetAllByFulfillment(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+		for i, actual := range expected {
+			assertEquivalentRecords(t, &actual, records[i])
+		}
+
+		records, err = s.GetAllByIntent(ctx, "intent3")
+		require.NoError(t, err)
+		assert.Empty(t, records)
+	})
+}
+
+func testValidation(t *testing.T, s adminaudit.Store) {
+	t.Run("testValidation", func(t *testing.T) {
+		ctx := context.Background()
+
+		for _, invalid := range []func(r *adminaudit.Record){
+			func(r *adminaudit.Record) { r.Operator = "" },
+			func(r *adminaudit.Record) { r.Action = "" },
+			func(r *adminaudit.Record) { r.Reason = "" },
+			func(r *adminaudit.Record) { r.Intent = ""; r.FulfillmentId = 0 },
+			func(r *adminaudit.Record) { r.Result = adminaudit.ResultUnknown },
+		} {
+			record := newTestRecord("intent1", 1)
+			invalid(record)
+			assert.Error(t, s.Put(ctx, record))
+		}
+
+		records, err := s.GetAllByIntent(ctx, "intent1")
+		require.NoError(t, err)
+		assert.Empty(t, records)
+	})
+}
+
+func newTestRecord(intent string, fulfillmentId uint64) *adminaudit.Record {
+	return &adminaudit.Record{
+		Operator:      "operator",
+		Action:        "retry_fulfillment",
+		Reason:        "stuck after rpc outage",
+		Intent:        intent,
+		FulfillmentId: fulfillmentId,
+		Result:        adminaudit.ResultOk,
+		CreatedAt:     time.Now(),
+	}
+}
+
+func assertEquivalentRecords(t *testing.T, obj1, obj2 *adminaudit.Record) {
+	assert.Equal(t, obj1.Id, obj2.Id)
+	assert.Equal(t, obj1.Operator, obj2.Operator)
+	assert.Equal(t, obj1.Action, obj2.Action)
+	assert.Equal(t, obj1.Reason, obj2.Reason)
+	assert.Equal(t, obj1.Intent, obj2.Intent)
+	assert.Equal(t, obj1.FulfillmentId, obj2.FulfillmentId)
+	assert.Equal(t, obj1.Result, obj2.Result)
+	assert.Equal(t, obj1.Detail, obj2.Detail)
+	assert.Equal(t, obj1.CreatedAt.Unix(), obj2.CreatedAt.Unix())
+}
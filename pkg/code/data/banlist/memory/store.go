@@ -0,0 +1,143 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/code-payments/code-server/pkg/code/data/banlist"
+)
+
+type store struct {
+	mu      sync.Mutex
+	records []*banlist.Record
+	last    uint64
+}
+
+func New() banlist.Store {
+	return &store{
+		records: make([]*banlist.Record, 0),
+		last:    0,
+	}
+}
+
+func (s *store) reset() {
+	s.mu.Lock()
+	s.records = make([]*banlist.Record, 0)
+	s.last = 0
+	s.mu.Unlock()
+}
+
+// Put implements banlist.Store.Put
+func (s *store) Put(_ context.Context, data *banlist.Record) error {
+	if err := data.Validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.last++
+	if item := s.find(data.EntryType, data.Value); item != nil {
+		cloned := data.Clone()
+		item.Reason = cloned.Reason
+		item.ExpiresAt = cloned.ExpiresAt
+
+		item.CopyTo(data)
+	} else {
+		if data.Id == 0 {
+			data.Id = s.last
+		}
+		if data.CreatedAt.IsZero() {
+			data.CreatedAt = time.Now()
+		}
+		c := data.Clone()
+		s.records = append(s.records, &c)
+	}
+
+	return nil
+}
+
+// Get implements banlist.Store.Get
+func (s *store) Get(_ context.Context, entryType banlist.EntryType, value string) (*banlist.Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item := s.find(entryType, value)
+	if item == nil {
+		return nil, banlist.ErrEntryNotFound
+	}
+
+	cloned := item.Clone()
+	return &cloned, nil
+}
+
+// Delete implements banlist.Store.Delete
+func (s *store) Delete(_ context.Context, entryType banlist.EntryType, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, item := range s.records {
+		if item.EntryType == entryType && item.Value == value {
+			s.records = append(s.records[:i], s.records[i+1:]...)
+			return nil
+		}
+	}
+	return banlist.ErrEntryNotFound
+}
+
+// GetAllByType implements banlist.Store.GetAllByType
+func (s *store) GetAllByType(_ context.Context, entryType banlist.EntryType) ([]*banlist.Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var res []*banlist.Record
+	for _, item := range s.records {
+		if item.EntryType == entryType {
+			cloned := item.Clone()
+			res = append(res, &cloned)
+		}
+	}
+
+	if len(res) == 0 {
+		return nil, banlist.ErrEntryNotFound
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Id < res[j].Id
+	})
+	return res, nil
+}
+
+// GetAllActive implements banlist.Store.GetAllActive
+func (s *store) GetAllActive(_ context.Context, at time.Time) ([]*banlist.Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var res []*banlist.Record
+	for _, item := range s.records {
+		if !item.IsExpired(at) {
+			cloned := item.Clone()
+			res = append(res, &cloned)
+		}
+	}
+
+	if len(res) == 0 {
+		return nil, banlist.ErrEntryNotFound
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Id < res[j].Id
+	})
+	return res, nil
+}
+
+func (s *store) find(entryType banlist.EntryType, value string) *banlist.Record {
+	for _, item := range s.records {
+		if item.EntryType == entryType && item.Value == value {
+			return item
+		}
+	}
+	return nil
+}
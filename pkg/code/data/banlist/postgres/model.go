@@ -0,0 +1,163 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	pgutil "github.com/code-payments/code-server/pkg/database/postgres"
+	"github.com/code-payments/code-server/pkg/code/data/banlist"
+)
+
+const (
+	tableName = "codewallet__core_banlist"
+)
+
+type model struct {
+	Id sql.NullInt64 `db:"id"`
+
+	EntryType uint8  `db:"entry_type"`
+	Value     string `db:"value"`
+
+	Reason string `db:"reason"`
+
+	ExpiresAt sql.NullTime `db:"expires_at"`
+
+	CreatedAt time.Time `db:"created_at"`
+}
+
+func toModel(obj *banlist.Record) (*model, error) {
+	if err := obj.Validate(); err != nil {
+		return nil, err
+	}
+
+	if obj.CreatedAt.IsZero() {
+		obj.CreatedAt = time.Now().UTC()
+	}
+
+	var expiresAt sql.NullTime
+	if obj.ExpiresAt != nil {
+		expiresAt.Valid = true
+		expiresAt.Time = obj.ExpiresAt.UTC()
+	}
+
+	return &model{
+		Id:        sql.NullInt64{Int64: int64(obj.Id), Valid: true},
+		EntryType: uint8(obj.EntryType),
+		Value:     obj.Value,
+		Reason:    obj.Reason,
+		ExpiresAt: expiresAt,
+		CreatedAt: obj.CreatedAt,
+	}, nil
+}
+
+func fromModel(obj *model) *banlist.Record {
+	var expiresAt *time.Time
+	if obj.ExpiresAt.Valid {
+		value := obj.ExpiresAt.Time
+		expiresAt = &value
+	}
+
+	return &banlist.Record{
+		Id:        uint64(obj.Id.Int64),
+		EntryType: banlist.EntryType(obj.EntryType),
+		Value:     obj.Value,
+		Reason:    obj.Reason,
+		ExpiresAt: expiresAt,
+		CreatedAt: obj.CreatedAt,
+	}
+}
+
+func (m *model) dbPut(ctx context.Context, db *sqlx.DB) error {
+	return pgutil.ExecuteInTx(ctx, db, sql.LevelDefault, func(tx *sqlx.Tx) error {
+		query := `INSERT INTO ` + tableName + `
+			(entry_type, value, reason, expires_at, created_at)
+			VALUES ($1, $2, $3, $4, $5)
+
+			ON CONFLICT (entry_type, value)
+			DO UPDATE
+				SET reason = $3, expires_at = $4
+				WHERE ` + tableName + `.entry_type = $1 AND ` + tableName + `.value = $2
+
+			RETURNING id, entry_type, value, reason, expires_at, created_at`
+
+		return tx.QueryRowxContext(
+			ctx,
+			query,
+			m.EntryType,
+			m.Value,
+			m.Reason,
+			m.ExpiresAt,
+			m.CreatedAt,
+		).StructScan(m)
+	})
+}
+
+func dbGet(ctx context.Context, db *sqlx.DB, entryType banlist.EntryType, value string) (*model, error) {
+	res := &model{}
+
+	query := `SELECT id, entry_type, value, reason, expires_at, created_at FROM ` + tableName + `
+		WHERE entry_type = $1 AND value = $2`
+
+	err := db.GetContext(ctx, res, query, entryType, value)
+	if err != nil {
+		return nil, pgutil.CheckNoRows(err, banlist.ErrEntryNotFound)
+	}
+	return res, nil
+}
+
+func dbDelete(ctx context.Context, db *sqlx.DB, entryType banlist.EntryType, value string) error {
+	query := `DELETE FROM ` + tableName + `
+		WHERE entry_type = $1 AND value = $2`
+
+	res, err := db.ExecContext(ctx, query, entryType, value)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	} else if rowsAffected == 0 {
+		return banlist.ErrEntryNotFound
+	}
+	return nil
+}
+
+func dbGetAllByType(ctx context.Context, db *sqlx.DB, entryType banlist.EntryType) ([]*model, error) {
+	res := []*model{}
+
+	query := `SELECT id, entry_type, value, reason, expires_at, created_at FROM ` + tableName + `
+		WHERE entry_type = $1
+		ORDER BY id ASC`
+
+	err := db.SelectContext(ctx, &res, query, entryType)
+	if err != nil {
+		return nil, pgutil.CheckNoRows(err, banlist.ErrEntryNotFound)
+	}
+
+	if len(res) == 0 {
+		return nil, banlist.ErrEntryNotFound
+	}
+	return res, nil
+}
+
+func dbGetAllActive(ctx context.Context, db *sqlx.DB, at time.Time) ([]*model, error) {
+	res := []*model{}
+
+	query := `SELECT id, entry_type, value, reason, expires_at, created_at FROM ` + tableName + `
+		WHERE expires_at IS NULL OR expires_at > $1
+		ORDER BY id ASC`
+
+	err := db.SelectContext(ctx, &res, query, at.UTC())
+	if err != nil {
+		return nil, pgutil.CheckNoRows(err, banlist.ErrEntryNotFound)
+	}
+
+	if len(res) == 0 {
+		return nil, banlist.ErrEntryNotFound
+	}
+	return res, nil
+}
@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/code-payments/code-server/pkg/code/data/banlist"
+)
+
+type store struct {
+	db *sqlx.DB
+}
+
+func New(db *sql.DB) banlist.Store {
+	return &store{
+		db: sqlx.NewDb(db, "pgx"),
+	}
+}
+
+// Put implements banlist.Store.Put
+func (s *store) Put(ctx context.Context, record *banlist.Record) error {
+	m, err := toModel(record)
+	if err != nil {
+		return err
+	}
+
+	err = m.dbPut(ctx, s.db)
+	if err != nil {
+		return err
+	}
+
+	res := fromModel(m)
+	res.CopyTo(record)
+
+	return nil
+}
+
+// Get implements banlist.Store.Get
+func (s *store) Get(ctx context.Context, entryType banlist.EntryType, value string) (*banlist.Record, error) {
+	m, err := dbGet(ctx, s.db, entryType, value)
+	if err != nil {
+		return nil, err
+	}
+	return fromModel(m), nil
+}
+
+// Delete implements banlist.Store.Delete
+func (s *store) Delete(ctx context.Context, entryType banlist.EntryType, value string) error {
+	return dbDelete(ctx, s.db, entryType, value)
+}
+
+// GetAllByType implements banlist.Store.GetAllByType
+func (s *store) GetAllByType(ctx context.Context, entryType banlist.EntryType) ([]*banlist.Record, error) {
+	models, err := dbGetAllByType(ctx, s.db, entryType)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*banlist.Record, len(models))
+	for i, m := range models {
+		res[i] = fromModel(m)
+	}
+	return res, nil
+}
+
+// GetAllActive implements banlist.Store.GetAllActive
+func (s *store) GetAllActive(ctx context.Context, at time.Time) ([]*banlist.Record, error) {
+	models, err := dbGetAllActive(ctx, s.db, at)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*banlist.Record, len(models))
+	for i, m := range models {
+		res[i] = fromModel(m)
+	}
+	return res, nil
+}
@@ -0,0 +1,137 @@
+package banlist
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/mr-tron/base58"
+)
+
+type EntryType uint8
+
+const (
+	UnknownEntryType EntryType = iota
+	PhonePrefixEntryType
+	IpRangeEntryType
+	ExternalAddressEntryType
+)
+
+type Record struct {
+	Id uint64
+
+	EntryType EntryType
+
+	// Value is the banned phone number prefix (eg. "+7"), CIDR range (eg.
+	// "10.0.0.0/8") or Solana address, depending on the entry type
+	Value string
+
+	Reason string
+
+	// ExpiresAt is when the ban is lifted. The ban is permanent when nil.
+	ExpiresAt *time.Time
+
+	CreatedAt time.Time
+}
+
+func (r *Record) IsExpired(at time.Time) bool {
+	return r.ExpiresAt != nil && !at.Before(*r.ExpiresAt)
+}
+
+func (r *Record) Validate() error {
+	switch r.EntryType {
+	case PhonePrefixEntryType:
+		if len(r.Value) < 2 || r.Value[0] != '+' {
+			return errors.New("phone prefix must start with +")
+		}
+		for _, c := range r.Value[1:] {
+			if c < '0' || c > '9' {
+				return errors.New("phone prefix must only contain digits")
+			}
+		}
+	case IpRangeEntryType:
+		_, ipNet, err := net.ParseCIDR(r.Value)
+		if err != nil {
+			return errors.New("ip range must be in cidr notation")
+		}
+		if ipNet.String() != r.Value {
+			return errors.New("ip range must be in canonical cidr notation")
+		}
+	case ExternalAddressEntryType:
+		decoded, err := base58.Decode(r.Value)
+		if err != nil || len(decoded) != 32 {
+			return errors.New("external address must be a base58 encoded public key")
+		}
+	default:
+		return errors.New("entry type is required")
+	}
+
+	if len(strings.TrimSpace(r.Reason)) == 0 {
+		return errors.New("reason is required")
+	}
+
+	if r.ExpiresAt != nil && r.ExpiresAt.IsZero() {
+		return errors.New("expiry cannot be zero")
+	}
+
+	return nil
+}
+
+func (r *Record) Clone() Record {
+	var expiresAt *time.Time
+	if r.ExpiresAt != nil {
+		value := *r.ExpiresAt
+		expiresAt = &value
+	}
+
+	return Record{
+		Id: r.Id,
+
+		EntryType: r.EntryType,
+		Value:     r.Value,
+
+		Reason: r.Reason,
+
+		ExpiresAt: expiresAt,
+
+		CreatedAt: r.CreatedAt,
+	}
+}
+
+func (r *Record) CopyTo(dst *Record) {
+	cloned := r.Clone()
+
+	dst.Id = cloned.Id
+
+	dst.EntryType = cloned.EntryType
+	dst.Value = cloned.Value
+
+	dst.Reason = cloned.Reason
+
+	dst.ExpiresAt = cloned.ExpiresAt
+
+	dst.CreatedAt = cloned.CreatedAt
+}
+
+func (t EntryType) String() string {
+	switch t {
+	case PhonePrefixEntryType:
+		return "phone_prefix"
+	case IpRangeEntryType:
+		return "ip_range"
+	case ExternalAddressEntryType:
+		return "external_address"
+	}
+	return "unknown"
+}
+
+// ParseEntryType parses an entry type from its string representation
+func ParseEntryType(value string) (EntryType, bool) {
+	for _, entryType := range []EntryType{PhonePrefixEntryType, IpRangeEntryType, ExternalAddressEntryType} {
+		if entryType.String() == value {
+			return entryType, true
+		}
+	}
+	return UnknownEntryType, false
+}
@@ -0,0 +1,31 @@
+package banlist
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrEntryNotFound = errors.New("ban list entry not found")
+)
+
+type Store interface {
+	// Put creates or updates the ban list entry for the record's entry type and
+	// value. The reason and expiry are updated for existing entries.
+	Put(ctx context.Context, record *Record) error
+
+	// Get gets the ban list entry for an entry type and value
+	Get(ctx context.Context, entryType EntryType, value string) (*Record, error)
+
+	// Delete deletes the ban list entry for an entry type and value
+	Delete(ctx context.Context, entryType EntryType, value string) error
+
+	// GetAllByType gets all ban list entries, including expired ones, of the
+	// provided entry type in ascending order of creation
+	GetAllByType(ctx context.Context, entryType EntryType) ([]*Record, error)
+
+	// GetAllActive gets all ban list entries, across entry types, that haven't
+	// expired at the provided time
+	GetAllActive(ctx context.Context, at time.Time) ([]*Record, error)
+}
@@ -0,0 +1,208 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/code-payments/code-server/pkg/code/data/banlist"
+)
+
+const (
+	testAddress1 = "codeHy87wGD5oMRLG75qKqsSi1vWE3oxNyYmXo5F9YR"
+	testAddress2 = "11111111111111111111111111111111"
+)
+
+func RunTests(t *testing.T, s banlist.Store, teardown func()) {
+	for _, tf := range []func(t *testing.T, s banlist.Store){
+		testRoundTrip,
+		testUpdate,
+		testDelete,
+		testGetAllQueries,
+		testValidation,
+	} {
+		tf(t, s)
+		teardown()
+	}
+}
+
+func testRoundTrip(t *testing.T, s banlist.Store) {
+	t.Run("testRoundTrip", func(t *testing.T) {
+		ctx := context.Background()
+
+		_, err := s.Get(ctx, banlist.PhonePrefixEntryType, "+7")
+		assert.Equal(t, banlist.ErrEntryNotFound, err)
+
+		expiresAt := time.Now().Add(time.Hour)
+		for _, expected := range []*banlist.Record{
+			{EntryType: banlist.PhonePrefixEntryType, Value: "+7", Reason: "sanctioned", CreatedAt: time.Now()},
+			{EntryType: banlist.IpRangeEntryType, Value: "10.0.0.0/8", Reason: "abuse", ExpiresAt: &expiresAt, CreatedAt: time.Now()},
+			{EntryType: banlist.ExternalAddressEntryType, Value: testAddress1, Reason: "fraud", CreatedAt: time.Now()},
+		} {
+			cloned := expected.Clone()
+			require.NoError(t, s.Put(ctx, expected))
+			assert.True(t, expected.Id > 0)
+
+			actual, err := s.Get(ctx, expected.EntryType, expected.Value)
+			require.NoError(t, err)
+			assertEquivalentRecords(t, &cloned, actual)
+			assert.Equal(t, expected.Id, actual.Id)
+		}
+
+		// Entries are unique by type and value
+		_, err = s.Get(ctx, banlist.IpRangeEntryType, "+7")
+		assert.Equal(t, banlist.ErrEntryNotFound, err)
+	})
+}
+
+func testUpdate(t *testing.T, s banlist.Store) {
+	t.Run("testUpdate", func(t *testing.T) {
+		ctx := context.Background()
+
+		record := &banlist.Record{
+			EntryType: banlist.ExternalAddressEntryType,
+			Value:     testAddress1,
+			Reason:    "fraud",
+			CreatedAt: time.Now(),
+		}
+		require.NoError(t, s.Put(ctx, record))
+		id := record.Id
+
+		expiresAt := time.Now().Add(time.Hour)
+		updated := &banlist.Record{
+			EntryType: banlist.ExternalAddressEntryType,
+			Value:     testAddress1,
+			Reason:    "temporary hold",
+			ExpiresAt: &expiresAt,
+			CreatedAt: time.Now().Add(time.Minute),
+		}
+		require.NoError(t, s.Put(ctx, updated))
+		assert.Equal(t, id, updated.Id)
+		assert.Equal(t, record.CreatedAt.Unix(), updated.CreatedAt.Unix())
+
+		actual, err := s.Get(ctx, banlist.ExternalAddressEntryType, testAddress1)
+		require.NoError(t, err)
+		assert.Equal(t, id, actual.Id)
+		assert.Equal(t, "temporary hold", actual.Reason)
+		require.NotNil(t, actual.ExpiresAt)
+		assert.Equal(t, expiresAt.Unix(), actual.ExpiresAt.Unix())
+		assert.Equal(t, record.CreatedAt.Unix(), actual.CreatedAt.Unix())
+
+		// Removing the expiry makes the ban permanent
+		updated.ExpiresAt = nil
+		require.NoError(t, s.Put(ctx, updated))
+
+		actual, err = s.Get(ctx, banlist.ExternalAddressEntryType, testAddress1)
+		require.NoError(t, err)
+		assert.Nil(t, actual.ExpiresAt)
+	})
+}
+
+func testDelete(t *testing.T, s banlist.Store) {
+	t.Run("testDelete", func(t *testing.T) {
+		ctx := context.Background()
+
+		assert.Equal(t, banlist.ErrEntryNotFound, s.Delete(ctx, banlist.PhonePrefixEntryType, "+7"))
+
+		require.NoError(t, s.Put(ctx, &banlist.Record{
+			EntryType: banlist.PhonePrefixEntryType,
+			Value:     "+7",
+			Reason:    "sanctioned",
+			CreatedAt: time.Now(),
+		}))
+
+		require.NoError(t, s.Delete(ctx, banlist.PhonePrefixEntryType, "+7"))
+
+		_, err := s.Get(ctx, banlist.PhonePrefixEntryType, "+7")
+		assert.Equal(t, banlist.ErrEntryNotFound, err)
+
+		assert.Equal(t, banlist.ErrEntryNotFound, s.Delete(ctx, banlist.PhonePrefixEntryType, "+7"))
+	})
+}
+
+func testGetAllQueries(t *testing.T, s banlist.Store) {
+	t.Run("testGetAllQueries", func(t *testing.T) {
+		ctx := context.Background()
+
+		now := time.Now()
+
+		_, err := s.GetAllByType(ctx, banlist.PhonePrefixEntryType)
+		assert.Equal(t, banlist.ErrEntryNotFound, err)
+
+		_, err = s.GetAllActive(ctx, now)
+		assert.Equal(t, banlist.ErrEntryNotFound, err)
+
+		expired := now.Add(-time.Minute)
+		expiring := now.Add(time.Minute)
+		records := []*banlist.Record{
+			{EntryType: banlist.PhonePrefixEntryType, Value: "+7", Reason: "sanctioned"},
+			{EntryType: banlist.PhonePrefixEntryType, Value: "+98", Reason: "sanctioned", ExpiresAt: &expired},
+			{EntryType: banlist.IpRangeEntryType, Value: "10.0.0.0/8", Reason: "abuse", ExpiresAt: &expiring},
+			{EntryType: banlist.ExternalAddressEntryType, Value: testAddress1, Reason: "fraud"},
+			{EntryType: banlist.ExternalAddressEntryType, Value: testAddress2, Reason: "fraud", ExpiresAt: &expired},
+		}
+		for _, record := range records {
+			record.CreatedAt = now
+			require.NoError(t, s.Put(ctx, record))
+		}
+
+		actual, err := s.GetAllByType(ctx, banlist.PhonePrefixEntryType)
+		require.NoError(t, err)
+		require.Len(t, actual, 2)
+		assertEquivalentRecords(t, records[0], actual[0])
+		assertEquivalentRecords(t, records[1], actual[1])
+
+		actual, err = s.GetAllByType(ctx, banlist.IpRangeEntryType)
+		require.NoError(t, err)
+		require.Len(t, actual, 1)
+		assertEquivalentRecords(t, records[2], actual[0])
+
+		actual, err = s.GetAllActive(ctx, now)
+		require.NoError(t, err)
+		require.Len(t, actual, 3)
+		assertEquivalentRecords(t, records[0], actual[0])
+		assertEquivalentRecords(t, records[2], actual[1])
+		assertEquivalentRecords(t, records[3], actual[2])
+
+		actual, err = s.GetAllActive(ctx, now.Add(2*time.Minute))
+		require.NoError(t, err)
+		require.Len(t, actual, 2)
+		assertEquivalentRecords(t, records[0], actual[0])
+		assertEquivalentRecords(t, records[3], actual[1])
+	})
+}
+
+func testValidation(t *testing.T, s banlist.Store) {
+	t.Run("testValidation", func(t *testing.T) {
+		ctx := context.Background()
+
+		for _, invalid := range []*banlist.Record{
+			{EntryType: banlist.UnknownEntryType, Value: "+7", Reason: "reason"},
+			{EntryType: banlist.PhonePrefixEntryType, Value: "7", Reason: "reason"},
+			{EntryType: banlist.PhonePrefixEntryType, Value: "+7a", Reason: "reason"},
+			{EntryType: banlist.PhonePrefixEntryType, Value: "+7", Reason: ""},
+			{EntryType: banlist.IpRangeEntryType, Value: "10.0.0.1", Reason: "reason"},
+			{EntryType: banlist.IpRangeEntryType, Value: "10.0.0.1/8", Reason: "reason"},
+			{EntryType: banlist.ExternalAddressEntryType, Value: "invalid", Reason: "reason"},
+		} {
+			assert.Error(t, s.Put(ctx, invalid))
+		}
+
+		_, err := s.GetAllActive(ctx, time.Now())
+		assert.Equal(t, banlist.ErrEntryNotFound, err)
+	})
+}
+
+func assertEquivalentRecords(t *testing.T, obj1, obj2 *banlist.Record) {
+	assert.Equal(t, obj1.EntryType, obj2.EntryType)
+	assert.Equal(t, obj1.Value, obj2.Value)
+	assert.Equal(t, obj1.Reason, obj2.Reason)
+	assert.Equal(t, obj1.ExpiresAt == nil, obj2.ExpiresAt == nil)
+	if obj1.ExpiresAt != nil && obj2.ExpiresAt != nil {
+		assert.Equal(t, obj1.ExpiresAt.Unix(), obj2.ExpiresAt.Unix())
+	}
+	assert.Equal(t, obj1.CreatedAt.Unix(), obj2.CreatedAt.Unix())
+}
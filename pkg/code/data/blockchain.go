@@ -3,12 +3,16 @@ package data
 import (
 	"context"
 	"crypto/ed25519"
+	"io"
+	"strings"
+	"sync"
 
 	"github.com/mr-tron/base58"
 
 	"github.com/code-payments/code-server/pkg/database/query"
 	"github.com/code-payments/code-server/pkg/kin"
 	"github.com/code-payments/code-server/pkg/metrics"
+	currency_mint "github.com/code-payments/code-server/pkg/mint"
 	"github.com/code-payments/code-server/pkg/solana"
 	"github.com/code-payments/code-server/pkg/solana/token"
 )
@@ -30,10 +34,13 @@ type BlockchainData interface {
 	GetBlockchainHistory(ctx context.Context, account string, commitment solana.Commitment, opts ...query.Option) ([]*solana.TransactionSignature, error)
 	GetBlockchainMinimumBalanceForRentExemption(ctx context.Context, size uint64) (uint64, error)
 	GetBlockchainLatestBlockhash(ctx context.Context) (solana.Blockhash, error)
+	GetBlockchainRecentPrioritizationFees(ctx context.Context, writableAccounts []string) ([]solana.PrioritizationFee, error)
 	GetBlockchainSignatureStatuses(ctx context.Context, signatures []solana.Signature) ([]*solana.SignatureStatus, error)
 	GetBlockchainSlot(ctx context.Context, commitment solana.Commitment) (uint64, error)
 	GetBlockchainTokenAccountInfo(ctx context.Context, account string, commitment solana.Commitment) (*token.Account, error)
+	GetBlockchainTokenAccountInfoForMint(ctx context.Context, account, mint string, commitment solana.Commitment) (*token.Account, error)
 	GetBlockchainTokenAccountsByOwner(ctx context.Context, account string) ([]ed25519.PublicKey, error)
+	GetBlockchainTokenAccountsByOwnerForMint(ctx context.Context, account, mint string) ([]ed25519.PublicKey, error)
 	GetBlockchainTransaction(ctx context.Context, sig string, commitment solana.Commitment) (*solana.ConfirmedTransaction, error)
 	GetBlockchainTransactionTokenBalances(ctx context.Context, sig string) (*solana.TransactionTokenBalances, error)
 	GetBlockchainFilteredProgramAccounts(ctx context.Context, program string, offset uint, filterValue []byte) ([]string, uint64, error)
@@ -41,19 +48,75 @@ type BlockchainData interface {
 
 type BlockchainProvider struct {
 	sc solana.Client
-	tc *token.Client
+
+	tokenClientsMu sync.Mutex
+	tokenClients   map[string]*token.Client
 }
 
-func NewBlockchainProvider(solanaEndpoint string) (BlockchainData, error) {
-	sc := solana.New(solanaEndpoint)
-	tc := token.NewClient(sc, kin.TokenMint)
+// NewBlockchainProvider returns a blockchain provider using the provided
+// endpoint. Multiple comma-separated endpoints may be provided, in which case
+// requests are load balanced across them with automatic failover. Providing
+// pool options, like rate limits, uses a pool for a single endpoint too. The
+// pool runs background health checks until the provider is closed.
+func NewBlockchainProvider(solanaEndpoint string, poolOpts ...solana.PoolOption) (BlockchainData, error) {
+	var endpoints []string
+	for _, endpoint := range strings.Split(solanaEndpoint, ",") {
+		endpoint = strings.TrimSpace(endpoint)
+		if len(endpoint) > 0 {
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+
+	if len(endpoints) <= 1 && len(poolOpts) == 0 {
+		return NewBlockchainProviderWithClient(solana.New(solanaEndpoint))
+	}
+
+	sc, err := solana.NewWithEndpoints(context.Background(), endpoints, poolOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return NewBlockchainProviderWithClient(sc)
+}
 
+// NewBlockchainProviderWithClient returns a blockchain provider backed by the
+// provided Solana client.
+func NewBlockchainProviderWithClient(sc solana.Client) (BlockchainData, error) {
 	return &BlockchainProvider{
 		sc: sc,
-		tc: tc,
+		tokenClients: map[string]*token.Client{
+			currency_mint.Kin.ToBase58(): token.NewClient(sc, currency_mint.Kin.Address),
+		},
 	}, nil
 }
 
+// Close releases resources held by the Solana client, like the background
+// health checks of an RPC pool.
+func (dp *BlockchainProvider) Close() error {
+	if closer, ok := dp.sc.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// getTokenClient gets the token client for a supported mint. An empty mint
+// refers to Kin.
+func (dp *BlockchainProvider) getTokenClient(mint string) (*token.Client, error) {
+	m, err := currency_mint.Get(mint)
+	if err != nil {
+		return nil, err
+	}
+
+	dp.tokenClientsMu.Lock()
+	defer dp.tokenClientsMu.Unlock()
+
+	tc, ok := dp.tokenClients[m.ToBase58()]
+	if !ok {
+		tc = token.NewClient(dp.sc, m.Address)
+		dp.tokenClients[m.ToBase58()] = tc
+	}
+	return tc, nil
+}
+
 // Solana
 // --------------------------------------------------------------------------------
 
@@ -121,33 +184,70 @@ func (dp *BlockchainProvider) GetBlockchainTokenAccountInfo(ctx context.Context,
 	tracer := metrics.TraceMethodCall(ctx, blockchainProviderMetricsName, "GetBlockchainTokenAccountInfo")
 	defer tracer.End()
 
-	accountId, err := base58.Decode(account)
+	res, err := dp.getTokenAccountInfo(account, kin.Mint, commitment)
+
 	if err != nil {
-		return nil, err
+		tracer.OnError(err)
 	}
-	res, err := dp.tc.GetAccount(accountId, commitment)
+	return res, err
+}
+func (dp *BlockchainProvider) GetBlockchainTokenAccountInfoForMint(ctx context.Context, account, mint string, commitment solana.Commitment) (*token.Account, error) {
+	tracer := metrics.TraceMethodCall(ctx, blockchainProviderMetricsName, "GetBlockchainTokenAccountInfoForMint")
+	defer tracer.End()
+
+	res, err := dp.getTokenAccountInfo(account, mint, commitment)
 
 	if err != nil {
 		tracer.OnError(err)
 	}
 	return res, err
 }
+func (dp *BlockchainProvider) getTokenAccountInfo(account, mint string, commitment solana.Commitment) (*token.Account, error) {
+	accountId, err := base58.Decode(account)
+	if err != nil {
+		return nil, err
+	}
+
+	tc, err := dp.getTokenClient(mint)
+	if err != nil {
+		return nil, err
+	}
+	return tc.GetAccount(accountId, commitment)
+}
 func (dp *BlockchainProvider) GetBlockchainTokenAccountsByOwner(ctx context.Context, account string) ([]ed25519.PublicKey, error) {
 	tracer := metrics.TraceMethodCall(ctx, blockchainProviderMetricsName, "GetBlockchainTokenAccountsByOwner")
 	defer tracer.End()
 
-	accountId, err := base58.Decode(account)
+	res, err := dp.getTokenAccountsByOwner(account, kin.Mint)
+
 	if err != nil {
-		return nil, err
+		tracer.OnError(err)
 	}
+	return res, err
+}
+func (dp *BlockchainProvider) GetBlockchainTokenAccountsByOwnerForMint(ctx context.Context, account, mint string) ([]ed25519.PublicKey, error) {
+	tracer := metrics.TraceMethodCall(ctx, blockchainProviderMetricsName, "GetBlockchainTokenAccountsByOwnerForMint")
+	defer tracer.End()
 
-	res, err := dp.sc.GetTokenAccountsByOwner(accountId, kin.TokenMint)
+	res, err := dp.getTokenAccountsByOwner(account, mint)
 
 	if err != nil {
 		tracer.OnError(err)
 	}
 	return res, err
 }
+func (dp *BlockchainProvider) getTokenAccountsByOwner(account, mint string) ([]ed25519.PublicKey, error) {
+	accountId, err := base58.Decode(account)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := currency_mint.Get(mint)
+	if err != nil {
+		return nil, err
+	}
+	return dp.sc.GetTokenAccountsByOwner(accountId, m.Address)
+}
 func (dp *BlockchainProvider) GetBlockchainSlot(ctx context.Context, commitment solana.Commitment) (uint64, error) {
 	tracer := metrics.TraceMethodCall(ctx, blockchainProviderMetricsName, "GetBlockchainSlot")
 	defer tracer.End()
@@ -272,6 +372,27 @@ func (dp *BlockchainProvider) GetBlockchainLatestBlockhash(ctx context.Context)
 	return res, err
 }
 
+func (dp *BlockchainProvider) GetBlockchainRecentPrioritizationFees(ctx context.Context, writableAccounts []string) ([]solana.PrioritizationFee, error) {
+	tracer := metrics.TraceMethodCall(ctx, blockchainProviderMetricsName, "GetBlockchainRecentPrioritizationFees")
+	defer tracer.End()
+
+	accountIds := make([]ed25519.PublicKey, len(writableAccounts))
+	for i, account := range writableAccounts {
+		decoded, err := base58.Decode(account)
+		if err != nil {
+			return nil, err
+		}
+		accountIds[i] = decoded
+	}
+
+	res, err := dp.sc.GetRecentPrioritizationFees(accountIds)
+
+	if err != nil {
+		tracer.OnError(err)
+	}
+	return res, err
+}
+
 func (dp *BlockchainProvider) GetBlockchainMinimumBalanceForRentExemption(ctx context.Context, size uint64) (uint64, error) {
 	tracer := metrics.TraceMethodCall(ctx, blockchainProviderMetricsName, "GetBlockchainMinimumBalanceForRentExemption")
 	defer tracer.End()
@@ -0,0 +1,220 @@
+package campaign
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/code-payments/code-server/pkg/currency"
+)
+
+type Type uint8
+
+const (
+	UnknownType Type = iota
+	WelcomeBonusType
+	ReferralBonusType
+	PromotionType
+)
+
+type State uint8
+
+const (
+	StateUnknown State = iota
+	StateEnabled
+	StateDisabled
+)
+
+type Record struct {
+	Id uint64
+
+	// Name uniquely identifies the campaign (eg. "welcome_bonus")
+	Name string
+
+	Type Type
+
+	// Amounts is the native amount paid out per currency. A USD amount is always
+	// required, and is used when the recipient's currency has no defined amount.
+	Amounts map[currency.Code]float64
+
+	// BudgetUsd is the maximum USD value across all non-failed payouts
+	BudgetUsd float64
+
+	EligibilityRules EligibilityRules
+
+	StartsAt time.Time
+	EndsAt   time.Time
+
+	State State
+
+	CreatedAt time.Time
+}
+
+// EligibilityRules are campaign-specific rules enforced by the antispam guard on
+// top of its general checks.
+type EligibilityRules struct {
+	// PhonePrefix restricts payouts to phone numbers starting with the prefix
+	// (eg. "+1"). All phone numbers are eligible when empty.
+	PhonePrefix string
+
+	// MaxAccountAge restricts payouts to users created within the duration. All
+	// users are eligible when zero.
+	MaxAccountAge time.Duration
+
+	// MaxPayoutsPerPhoneNumber is the maximum number of payouts a single phone
+	// number can receive across all of its owner accounts
+	MaxPayoutsPerPhoneNumber uint64
+
+	// MaxPayoutsPerOwner is the maximum number of payouts a single owner account
+	// can receive, including failed payouts. It's enforced atomically by the
+	// store when payouts are created. Welcome bonuses are paid once per owner,
+	// whereas referral bonuses are paid once per referred user.
+	MaxPayoutsPerOwner uint64
+}
+
+// IsActive determines whether the campaign is accepting new payouts at the
+// provided time
+func (r *Record) IsActive(at time.Time) bool {
+	return r.State == StateEnabled && !at.Before(r.StartsAt) && at.Before(r.EndsAt)
+}
+
+// GetAmount gets the native amount paid out in the provided currency, falling
+// back to USD when the campaign doesn't define one
+func (r *Record) GetAmount(preferred currency.Code) (currency.Code, float64) {
+	amount, ok := r.Amounts[preferred]
+	if ok {
+		return preferred, amount
+	}
+	return currency.USD, r.Amounts[currency.USD]
+}
+
+func (r *Record) Validate() error {
+	if len(strings.TrimSpace(r.Name)) == 0 {
+		return errors.New("name is required")
+	}
+
+	if r.Type == UnknownType {
+		return errors.New("type is required")
+	}
+
+	if _, ok := r.Amounts[currency.USD]; !ok {
+		return errors.New("usd amount is required")
+	}
+
+	for code, amount := range r.Amounts {
+		if len(code) == 0 {
+			return errors.New("currency is required")
+		}
+
+		if amount <= 0 {
+			return errors.New("amount must be positive")
+		}
+	}
+
+	if r.BudgetUsd <= 0 {
+		return errors.New("budget must be positive")
+	}
+
+	if r.EligibilityRules.PhonePrefix != "" && r.EligibilityRules.PhonePrefix[0] != '+' {
+		return errors.New("phone prefix must start with +")
+	}
+
+	if r.EligibilityRules.MaxAccountAge < 0 {
+		return errors.New("max account age cannot be negative")
+	}
+
+	if r.EligibilityRules.MaxPayoutsPerPhoneNumber == 0 {
+		return errors.New("max payouts per phone number must be positive")
+	}
+
+	if r.EligibilityRules.MaxPayoutsPerOwner == 0 {
+		return errors.New("max payouts per owner must be positive")
+	}
+
+	if r.StartsAt.IsZero() || r.EndsAt.IsZero() {
+		return errors.New("date window is required")
+	}
+
+	if !r.EndsAt.After(r.StartsAt) {
+		return errors.New("campaign must end after it starts")
+	}
+
+	if r.State == StateUnknown {
+		return errors.New("state is required")
+	}
+
+	return nil
+}
+
+func (r *Record) Clone() Record {
+	amounts := make(map[currency.Code]float64, len(r.Amounts))
+	for code, amount := range r.Amounts {
+		amounts[code] = amount
+	}
+
+	return Record{
+		Id: r.Id,
+
+		Name: r.Name,
+
+		Type: r.Type,
+
+		Amounts: amounts,
+
+		BudgetUsd: r.BudgetUsd,
+
+		EligibilityRules: r.EligibilityRules,
+
+		StartsAt: r.StartsAt,
+		EndsAt:   r.EndsAt,
+
+		State: r.State,
+
+		CreatedAt: r.CreatedAt,
+	}
+}
+
+func (r *Record) CopyTo(dst *Record) {
+	cloned := r.Clone()
+
+	dst.Id = cloned.Id
+
+	dst.Name = cloned.Name
+
+	dst.Type = cloned.Type
+
+	dst.Amounts = cloned.Amounts
+
+	dst.BudgetUsd = cloned.BudgetUsd
+
+	dst.EligibilityRules = cloned.EligibilityRules
+
+	dst.StartsAt = cloned.StartsAt
+	dst.EndsAt = cloned.EndsAt
+
+	dst.State = cloned.State
+
+	dst.CreatedAt = cloned.CreatedAt
+}
+
+func (t Type) String() string {
+	switch t {
+	case WelcomeBonusType:
+		return "welcome_bonus"
+	case ReferralBonusType:
+		return "referral_bonus"
+	case PromotionType:
+		return "promotion"
+	}
+	return "unknown"
+}
+
+func (s State) String() string {
+	switch s {
+	case StateEnabled:
+		return "enabled"
+	case StateDisabled:
+		return "disabled"
+	}
+	return "unknown"
+}
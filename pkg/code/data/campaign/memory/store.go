@@ -0,0 +1,270 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/code-payments/code-server/pkg/code/data/campaign"
+)
+
+type store struct {
+	mu sync.Mutex
+
+	campaigns    []*campaign.Record
+	lastCampaign uint64
+
+	payouts    []*campaign.Payout
+	lastPayout uint64
+}
+
+func New() campaign.Store {
+	return &store{
+		campaigns: make([]*campaign.Record, 0),
+		payouts:   make([]*campaign.Payout, 0),
+	}
+}
+
+func (s *store) reset() {
+	s.mu.Lock()
+	s.campaigns = make([]*campaign.Record, 0)
+	s.lastCampaign = 0
+	s.payouts = make([]*campaign.Payout, 0)
+	s.lastPayout = 0
+	s.mu.Unlock()
+}
+
+// PutCampaign implements campaign.Store.PutCampaign
+func (s *store) PutCampaign(_ context.Context, data *campaign.Record) error {
+	if err := data.Validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastCampaign++
+	if item := s.findCampaign(data.Name); item != nil {
+		cloned := data.Clone()
+		item.Type = cloned.Type
+		item.Amounts = cloned.Amounts
+		item.BudgetUsd = cloned.BudgetUsd
+		item.EligibilityRules = cloned.EligibilityRules
+		item.StartsAt = cloned.StartsAt
+		item.EndsAt = cloned.EndsAt
+		item.State = cloned.State
+
+		item.CopyTo(data)
+	} else {
+		if data.Id == 0 {
+			data.Id = s.lastCampaign
+		}
+		if data.CreatedAt.IsZero() {
+			data.CreatedAt = time.Now()
+		}
+		c := data.Clone()
+		s.campaigns = append(s.campaigns, &c)
+	}
+
+	return nil
+}
+
+// GetCampaign implements campaign.Store.GetCampaign
+func (s *store) GetCampaign(_ context.Context, name string) (*campaign.Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item := s.findCampaign(name)
+	if item == nil {
+		return nil, campaign.ErrCampaignNotFound
+	}
+
+	cloned := item.Clone()
+	return &cloned, nil
+}
+
+// GetAllCampaigns implements campaign.Store.GetAllCampaigns
+func (s *store) GetAllCampaigns(_ context.Context) ([]*campaign.Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.campaigns) == 0 {
+		return nil, campaign.ErrCampaignNotFound
+	}
+
+	res := make([]*campaign.Record, len(s.campaigns))
+	for i, item := range s.campaigns {
+		cloned := item.Clone()
+		res[i] = &cloned
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Id < res[j].Id
+	})
+	return res, nil
+}
+
+// CreatePayout implements campaign.Store.CreatePayout
+func (s *store) CreatePayout(_ context.Context, data *campaign.Payout) error {
+	if err := data.Validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	campaignRecord := s.findCampaign(data.Campaign)
+	if campaignRecord == nil {
+		return campaign.ErrCampaignNotFound
+	}
+
+	var ownerPayouts uint64
+	for _, item := range s.payouts {
+		if item.IntentId == data.IntentId {
+			return campaign.ErrPayoutExists
+		}
+
+		if item.Campaign == data.Campaign && item.OwnerAccount == data.OwnerAccount {
+			ownerPayouts++
+		}
+	}
+
+	if ownerPayouts >= campaignRecord.EligibilityRules.MaxPayoutsPerOwner {
+		return campaign.ErrPayoutExists
+	}
+
+	spend := s.getSpend(data.Campaign)
+	if spend.UsdValue+data.UsdValue > campaignRecord.BudgetUsd {
+		return campaign.ErrBudgetExceeded
+	}
+
+	s.lastPayout++
+	data.Id = s.lastPayout
+	if data.CreatedAt.IsZero() {
+		data.CreatedAt = time.Now()
+	}
+
+	c := data.Clone()
+	s.payouts = append(s.payouts, &c)
+
+	return nil
+}
+
+// UpdatePayoutState implements campaign.Store.UpdatePayoutState
+func (s *store) UpdatePayoutState(_ context.Context, intentId string, state campaign.PayoutState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, item := range s.payouts {
+		if item.IntentId != intentId {
+			continue
+		}
+
+		if item.State != campaign.PayoutStatePending {
+			return campaign.ErrInvalidStateTransition
+		}
+
+		item.State = state
+		return nil
+	}
+	return campaign.ErrPayoutNotFound
+}
+
+// GetPayout implements campaign.Store.GetPayout
+func (s *store) GetPayout(_ context.Context, campaignName, owner string) (*campaign.Payout, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, item := range s.payouts {
+		if item.Campaign == campaignName && item.OwnerAccount == owner {
+			cloned := item.Clone()
+			return &cloned, nil
+		}
+	}
+	return nil, campaign.ErrPayoutNotFound
+}
+
+// GetPayoutByIntentId implements campaign.Store.GetPayoutByIntentId
+func (s *store) GetPayoutByIntentId(_ context.Context, intentId string) (*campaign.Payout, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, item := range s.payouts {
+		if item.IntentId == intentId {
+			cloned := item.Clone()
+			return &cloned, nil
+		}
+	}
+	return nil, campaign.ErrPayoutNotFound
+}
+
+// GetAllPayoutsByState implements campaign.Store.GetAllPayoutsByState
+func (s *store) GetAllPayoutsByState(_ context.Context, state campaign.PayoutState, limit uint64) ([]*campaign.Payout, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var res []*campaign.Payout
+	for _, item := range s.payouts {
+		if item.State == state {
+			cloned := item.Clone()
+			res = append(res, &cloned)
+		}
+	}
+
+	if len(res) == 0 {
+		return nil, campaign.ErrPayoutNotFound
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Id < res[j].Id
+	})
+
+	if uint64(len(res)) > limit {
+		res = res[:limit]
+	}
+	return res, nil
+}
+
+// GetPayoutCountByPhoneNumber implements campaign.Store.GetPayoutCountByPhoneNumber
+func (s *store) GetPayoutCountByPhoneNumber(_ context.Context, campaignName, phoneNumber string) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var count uint64
+	for _, item := range s.payouts {
+		if item.Campaign == campaignName && item.PhoneNumber == phoneNumber && item.State != campaign.PayoutStateFailed {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// GetSpend implements campaign.Store.GetSpend
+func (s *store) GetSpend(_ context.Context, campaignName string) (*campaign.Spend, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.getSpend(campaignName), nil
+}
+
+func (s *store) getSpend(campaignName string) *campaign.Spend {
+	res := &campaign.Spend{}
+	for _, item := range s.payouts {
+		if item.Campaign == campaignName && item.State != campaign.PayoutStateFailed {
+			res.Payouts++
+			res.UsdValue += item.UsdValue
+			res.Quantity += item.Quantity
+		}
+	}
+	return res
+}
+
+func (s *store) findCampaign(name string) *campaign.Record {
+	for _, item := range s.campaigns {
+		if item.Name == name {
+			return item
+		}
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package campaign
+
+import (
+	"errors"
+	"time"
+
+	"github.com/code-payments/code-server/pkg/currency"
+)
+
+type PayoutState uint8
+
+const (
+	PayoutStateUnknown PayoutState = iota
+	PayoutStatePending
+	PayoutStateSubmitted
+	PayoutStateFailed
+)
+
+type Payout struct {
+	Id uint64
+
+	Campaign string
+
+	// IntentId is the deterministic ID of the intent that fulfills the payout
+	IntentId string
+
+	OwnerAccount string
+	PhoneNumber  string
+
+	ExchangeCurrency currency.Code
+	ExchangeRate     float64
+	NativeAmount     float64
+	UsdValue         float64
+	Quantity         uint64
+
+	State PayoutState
+
+	CreatedAt time.Time
+}
+
+// Spend is the aggregate of all non-failed payouts for a campaign
+type Spend struct {
+	Payouts  uint64
+	UsdValue float64
+	Quantity uint64
+}
+
+func (p *Payout) Validate() error {
+	if len(p.Campaign) == 0 {
+		return errors.New("campaign is required")
+	}
+
+	if len(p.IntentId) == 0 {
+		return errors.New("intent id is required")
+	}
+
+	if len(p.OwnerAccount) == 0 {
+		return errors.New("owner account is required")
+	}
+
+	if len(p.PhoneNumber) == 0 {
+		return errors.New("phone number is required")
+	}
+
+	if len(p.ExchangeCurrency) == 0 {
+		return errors.New("exchange currency is required")
+	}
+
+	if p.ExchangeRate <= 0 {
+		return errors.New("exchange rate must be positive")
+	}
+
+	if p.NativeAmount <= 0 {
+		return errors.New("native amount must be positive")
+	}
+
+	if p.UsdValue <= 0 {
+		return errors.New("usd value must be positive")
+	}
+
+	if p.Quantity == 0 {
+		return errors.New("quantity must be positive")
+	}
+
+	if p.State == PayoutStateUnknown {
+		return errors.New("state is required")
+	}
+
+	return nil
+}
+
+func (p *Payout) Clone() Payout {
+	return Payout{
+		Id: p.Id,
+
+		Campaign: p.Campaign,
+
+		IntentId: p.IntentId,
+
+		OwnerAccount: p.OwnerAccount,
+		PhoneNumber:  p.PhoneNumber,
+
+		ExchangeCurrency: p.ExchangeCurrency,
+		ExchangeRate:     p.ExchangeRate,
+		NativeAmount:     p.NativeAmount,
+		UsdValue:         p.UsdValue,
+		Quantity:         p.Quantity,
+
+		State: p.State,
+
+		CreatedAt: p.CreatedAt,
+	}
+}
+
+func (p *Payout) CopyTo(dst *Payout) {
+	dst.Id = p.Id
+
+	dst.Campaign = p.Campaign
+
+	dst.IntentId = p.IntentId
+
+	dst.OwnerAccount = p.OwnerAccount
+	dst.PhoneNumber = p.PhoneNumber
+
+	dst.ExchangeCurrency = p.ExchangeCurrency
+	dst.ExchangeRate = p.ExchangeRate
+	dst.NativeAmount = p.NativeAmount
+	dst.UsdValue = p.UsdValue
+	dst.Quantity = p.Quantity
+
+	dst.State = p.State
+
+	dst.CreatedAt = p.CreatedAt
+}
+
+func (s PayoutState) String() string {
+	switch s {
+	case PayoutStatePending:
+		return "pending"
+	case PayoutStateSubmitted:
+		return "submitted"
+	case PayoutStateFailed:
+		return "failed"
+	}
+	return "unknown"
+}
@@ -0,0 +1,459 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	pgutil "github.com/code-payments/code-server/pkg/database/postgres"
+	"github.com/code-payments/code-server/pkg/code/data/campaign"
+	"github.com/code-payments/code-server/pkg/currency"
+)
+
+const (
+	campaignTableName = "codewallet__core_campaign"
+	amountTableName   = "codewallet__core_campaignamount"
+	payoutTableName   = "codewallet__core_campaignpayout"
+)
+
+type campaignModel struct {
+	Id sql.NullInt64 `db:"id"`
+
+	Name string `db:"name"`
+
+	CampaignType uint8 `db:"campaign_type"`
+
+	BudgetUsd float64 `db:"budget_usd"`
+
+	PhonePrefix              string `db:"phone_prefix"`
+	MaxAccountAgeSeconds     int64  `db:"max_account_age_seconds"`
+	MaxPayoutsPerPhoneNumber int64  `db:"max_payouts_per_phone_number"`
+	MaxPayoutsPerOwner       int64  `db:"max_payouts_per_owner"`
+
+	StartsAt time.Time `db:"starts_at"`
+	EndsAt   time.Time `db:"ends_at"`
+
+	State uint8 `db:"state"`
+
+	CreatedAt time.Time `db:"created_at"`
+
+	Amounts []*amountModel
+}
+
+type amountModel struct {
+	Id           sql.NullInt64 `db:"id"`
+	Campaign     string        `db:"campaign"`
+	Currency     string        `db:"currency"`
+	NativeAmount float64       `db:"native_amount"`
+}
+
+type payoutModel struct {
+	Id sql.NullInt64 `db:"id"`
+
+	Campaign string `db:"campaign"`
+
+	IntentId string `db:"intent_id"`
+
+	OwnerAccount string `db:"owner_account"`
+	PhoneNumber  string `db:"phone_number"`
+
+	ExchangeCurrency string  `db:"exchange_currency"`
+	ExchangeRate     float64 `db:"exchange_rate"`
+	NativeAmount     float64 `db:"native_amount"`
+	UsdValue         float64 `db:"usd_value"`
+	Quantity         uint64  `db:"quantity"`
+
+	State uint8 `db:"state"`
+
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type spendModel struct {
+	Payouts  uint64  `db:"payouts"`
+	UsdValue float64 `db:"usd_value"`
+	Quantity uint64  `db:"quantity"`
+}
+
+func toCampaignModel(obj *campaign.Record) (*campaignModel, error) {
+	if err := obj.Validate(); err != nil {
+		return nil, err
+	}
+
+	if obj.CreatedAt.IsZero() {
+		obj.CreatedAt = time.Now().UTC()
+	}
+
+	amounts := make([]*amountModel, 0, len(obj.Amounts))
+	for code, nativeAmount := range obj.Amounts {
+		amounts = append(amounts, &amountModel{
+			Campaign:     obj.Name,
+			Currency:     string(code),
+			NativeAmount: nativeAmount,
+		})
+	}
+
+	return &campaignModel{
+		Id:                       sql.NullInt64{Int64: int64(obj.Id), Valid: true},
+		Name:                     obj.Name,
+		CampaignType:             uint8(obj.Type),
+		BudgetUsd:                obj.BudgetUsd,
+		PhonePrefix:              obj.EligibilityRules.PhonePrefix,
+		MaxAccountAgeSeconds:     int64(obj.EligibilityRules.MaxAccountAge / time.Second),
+		MaxPayoutsPerPhoneNumber: int64(obj.EligibilityRules.MaxPayoutsPerPhoneNumber),
+		MaxPayoutsPerOwner:       int64(obj.EligibilityRules.MaxPayoutsPerOwner),
+		StartsAt:                 obj.StartsAt.UTC(),
+		EndsAt:                   obj.EndsAt.UTC(),
+		State:                    uint8(obj.State),
+		CreatedAt:                obj.CreatedAt,
+		Amounts:                  amounts,
+	}, nil
+}
+
+func fromCampaignModel(obj *campaignModel) *campaign.Record {
+	amounts := make(map[currency.Code]float64, len(obj.Amounts))
+	for _, amount := range obj.Amounts {
+		amounts[currency.Code(amount.Currency)] = amount.NativeAmount
+	}
+
+	return &campaign.Record{
+		Id:        uint64(obj.Id.Int64),
+		Name:      obj.Name,
+		Type:      campaign.Type(obj.CampaignType),
+		Amounts:   amounts,
+		BudgetUsd: obj.BudgetUsd,
+		EligibilityRules: campaign.EligibilityRules{
+			PhonePrefix:              obj.PhonePrefix,
+			MaxAccountAge:            time.Duration(obj.MaxAccountAgeSeconds) * time.Second,
+			MaxPayoutsPerPhoneNumber: uint64(obj.MaxPayoutsPerPhoneNumber),
+			MaxPayoutsPerOwner:       uint64(obj.MaxPayoutsPerOwner),
+		},
+		StartsAt:  obj.StartsAt,
+		EndsAt:    obj.EndsAt,
+		State:     campaign.State(obj.State),
+		CreatedAt: obj.CreatedAt,
+	}
+}
+
+func toPayoutModel(obj *campaign.Payout) (*payoutModel, error) {
+	if err := obj.Validate(); err != nil {
+		return nil, err
+	}
+
+	if obj.CreatedAt.IsZero() {
+		obj.CreatedAt = time.Now().UTC()
+	}
+
+	return &payoutModel{
+		Campaign:         obj.Campaign,
+		IntentId:         obj.IntentId,
+		OwnerAccount:     obj.OwnerAccount,
+		PhoneNumber:      obj.PhoneNumber,
+		ExchangeCurrency: string(obj.ExchangeCurrency),
+		ExchangeRate:     obj.ExchangeRate,
+		NativeAmount:     obj.NativeAmount,
+		UsdValue:         obj.UsdValue,
+		Quantity:         obj.Quantity,
+		State:            uint8(obj.State),
+		CreatedAt:        obj.CreatedAt,
+	}, nil
+}
+
+func fromPayoutModel(obj *payoutModel) *campaign.Payout {
+	return &campaign.Payout{
+		Id:               uint64(obj.Id.Int64),
+		Campaign:         obj.Campaign,
+		IntentId:         obj.IntentId,
+		OwnerAccount:     obj.OwnerAccount,
+		PhoneNumber:      obj.PhoneNumber,
+		ExchangeCurrency: currency.Code(obj.ExchangeCurrency),
+		ExchangeRate:     obj.ExchangeRate,
+		NativeAmount:     obj.NativeAmount,
+		UsdValue:         obj.UsdValue,
+		Quantity:         obj.Quantity,
+		State:            campaign.PayoutState(obj.State),
+		CreatedAt:        obj.CreatedAt,
+	}
+}
+
+func (m *campaignModel) dbPut(ctx context.Context, db *sqlx.DB) error {
+	return pgutil.ExecuteInTx(ctx, db, sql.LevelDefault, func(tx *sqlx.Tx) error {
+		query := `INSERT INTO ` + campaignTableName + `
+			(name, campaign_type, budget_usd, phone_prefix, max_account_age_seconds, max_payouts_per_phone_number, max_payouts_per_owner, starts_at, ends_at, state, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+
+			ON CONFLICT (name)
+			DO UPDATE
+				SET campaign_type = $2, budget_usd = $3, phone_prefix = $4, max_account_age_seconds = $5, max_payouts_per_phone_number = $6, max_payouts_per_owner = $7, starts_at = $8, ends_at = $9, state = $10
+				WHERE ` + campaignTableName + `.name = $1
+
+			RETURNING id, name, campaign_type, budget_usd, phone_prefix, max_account_age_seconds, max_payouts_per_phone_number, max_payouts_per_owner, starts_at, ends_at, state, created_at`
+
+		err := tx.QueryRowxContext(
+			ctx,
+			query,
+			m.Name,
+			m.CampaignType,
+			m.BudgetUsd,
+			m.PhonePrefix,
+			m.MaxAccountAgeSeconds,
+			m.MaxPayoutsPerPhoneNumber,
+			m.MaxPayoutsPerOwner,
+			m.StartsAt,
+			m.EndsAt,
+			m.State,
+			m.CreatedAt,
+		).StructScan(m)
+		if err != nil {
+			return err
+		}
+
+		query = `DELETE FROM ` + amountTableName + `
+			WHERE campaign = $1`
+
+		_, err = tx.ExecContext(ctx, query, m.Name)
+		if err != nil {
+			return err
+		}
+
+		for _, amount := range m.Amounts {
+			query = `INSERT INTO ` + amountTableName + `
+				(campaign, currency, native_amount)
+				VALUES ($1, $2, $3)
+				RETURNING id, campaign, currency, native_amount`
+
+			err = tx.QueryRowxContext(
+				ctx,
+				query,
+				amount.Campaign,
+				amount.Currency,
+				amount.NativeAmount,
+			).StructScan(amount)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func dbGetCampaign(ctx context.Context, db *sqlx.DB, name string) (*campaignModel, error) {
+	res := &campaignModel{}
+
+	query := `SELECT id, name, campaign_type, budget_usd, phone_prefix, max_account_age_seconds, max_payouts_per_phone_number, max_payouts_per_owner, starts_at, ends_at, state, created_at FROM ` + campaignTableName + `
+		WHERE name = $1`
+
+	err := db.GetContext(ctx, res, query, name)
+	if err != nil {
+		return nil, pgutil.CheckNoRows(err, campaign.ErrCampaignNotFound)
+	}
+
+	err = res.dbLoadAmounts(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func dbGetAllCampaigns(ctx context.Context, db *sqlx.DB) ([]*campaignModel, error) {
+	res := []*campaignModel{}
+
+	query := `SELECT id, name, campaign_type, budget_usd, phone_prefix, max_account_age_seconds, max_payouts_per_phone_number, max_payouts_per_owner, starts_at, ends_at, state, created_at FROM ` + campaignTableName + `
+		ORDER BY id ASC`
+
+	err := db.SelectContext(ctx, &res, query)
+	if err != nil {
+		return nil, pgutil.CheckNoRows(err, campaign.ErrCampaignNotFound)
+	}
+
+	if len(res) == 0 {
+		return nil, campaign.ErrCampaignNotFound
+	}
+
+	for _, m := range res {
+		err = m.dbLoadAmounts(ctx, db)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func (m *campaignModel) dbLoadAmounts(ctx context.Context, db *sqlx.DB) error {
+	m.Amounts = []*amountModel{}
+
+	query := `SELECT id, campaign, currency, native_amount FROM ` + amountTableName + `
+		WHERE campaign = $1`
+
+	return db.SelectContext(ctx, &m.Amounts, query, m.Name)
+}
+
+func (m *payoutModel) dbCreate(ctx context.Context, db *sqlx.DB) error {
+	return pgutil.ExecuteInTx(ctx, db, sql.LevelDefault, func(tx *sqlx.Tx) error {
+		// Lock the campaign, so concurrent payouts observe a consistent spend and
+		// per owner payout count
+		var limits struct {
+			BudgetUsd          float64 `db:"budget_usd"`
+			MaxPayoutsPerOwner int64   `db:"max_payouts_per_owner"`
+		}
+		query := `SELECT budget_usd, max_payouts_per_owner FROM ` + campaignTableName + `
+			WHERE name = $1
+			FOR UPDATE`
+
+		err := tx.GetContext(ctx, &limits, query, m.Campaign)
+		if err != nil {
+			return pgutil.CheckNoRows(err, campaign.ErrCampaignNotFound)
+		}
+
+		var ownerPayouts int64
+		query = `SELECT COUNT(*) FROM ` + payoutTableName + `
+			WHERE campaign = $1 AND owner_account = $2`
+
+		err = tx.GetContext(ctx, &ownerPayouts, query, m.Campaign, m.OwnerAccount)
+		if err != nil {
+			return err
+		}
+
+		if ownerPayouts >= limits.MaxPayoutsPerOwner {
+			return campaign.ErrPayoutExists
+		}
+
+		var spentUsd float64
+		query = `SELECT COALESCE(SUM(usd_value), 0) FROM ` + payoutTableName + `
+			WHERE campaign = $1 AND state != $2`
+
+		err = tx.GetContext(ctx, &spentUsd, query, m.Campaign, campaign.PayoutStateFailed)
+		if err != nil {
+			return err
+		}
+
+		if spentUsd+m.UsdValue > limits.BudgetUsd {
+			return campaign.ErrBudgetExceeded
+		}
+
+		query = `INSERT INTO ` + payoutTableName + `
+			(campaign, intent_id, owner_account, phone_number, exchange_currency, exchange_rate, native_amount, usd_value, quantity, state, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+
+			ON CONFLICT DO NOTHING
+
+			RETURNING id, campaign, intent_id, owner_account, phone_number, exchange_currency, exchange_rate, native_amount, usd_value, quantity, state, created_at`
+
+		err = tx.QueryRowxContext(
+			ctx,
+			query,
+			m.Campaign,
+			m.IntentId,
+			m.OwnerAccount,
+			m.PhoneNumber,
+			m.ExchangeCurrency,
+			m.ExchangeRate,
+			m.NativeAmount,
+			m.UsdValue,
+			m.Quantity,
+			m.State,
+			m.CreatedAt,
+		).StructScan(m)
+		return pgutil.CheckNoRows(err, campaign.ErrPayoutExists)
+	})
+}
+
+func dbUpdatePayoutState(ctx context.Context, db *sqlx.DB, intentId string, state campaign.PayoutState) error {
+	return pgutil.ExecuteInTx(ctx, db, sql.LevelDefault, func(tx *sqlx.Tx) error {
+		var currentState uint8
+		query := `SELECT state FROM ` + payoutTableName + `
+			WHERE intent_id = $1
+			FOR UPDATE`
+
+		err := tx.GetContext(ctx, &currentState, query, intentId)
+		if err != nil {
+			return pgutil.CheckNoRows(err, campaign.ErrPayoutNotFound)
+		}
+
+		if campaign.PayoutState(currentState) != campaign.PayoutStatePending {
+			return campaign.ErrInvalidStateTransition
+		}
+
+		query = `UPDATE ` + payoutTableName + `
+			SET state = $2
+			WHERE intent_id = $1`
+
+		_, err = tx.ExecContext(ctx, query, intentId, state)
+		return err
+	})
+}
+
+func dbGetPayout(ctx context.Context, db *sqlx.DB, campaignName, owner string) (*payoutModel, error) {
+	res := &payoutModel{}
+
+	query := `SELECT id, campaign, intent_id, owner_account, phone_number, exchange_currency, exchange_rate, native_amount, usd_value, quantity, state, created_at FROM ` + payoutTableName + `
+		WHERE campaign = $1 AND owner_account = $2
+		ORDER BY id ASC
+		LIMIT 1`
+
+	err := db.GetContext(ctx, res, query, campaignName, owner)
+	if err != nil {
+		return nil, pgutil.CheckNoRows(err, campaign.ErrPayoutNotFound)
+	}
+	return res, nil
+}
+
+func dbGetPayoutByIntentId(ctx context.Context, db *sqlx.DB, intentId string) (*payoutModel, error) {
+	res := &payoutModel{}
+
+	query := `SELECT id, campaign, intent_id, owner_account, phone_number, exchange_currency, exchange_rate, native_amount, usd_value, quantity, state, created_at FROM ` + payoutTableName + `
+		WHERE intent_id = $1`
+
+	err := db.GetContext(ctx, res, query, intentId)
+	if err != nil {
+		return nil, pgutil.CheckNoRows(err, campaign.ErrPayoutNotFound)
+	}
+	return res, nil
+}
+
+func dbGetAllPayoutsByState(ctx context.Context, db *sqlx.DB, state campaign.PayoutState, limit uint64) ([]*payoutModel, error) {
+	res := []*payoutModel{}
+
+	query := `SELECT id, campaign, intent_id, owner_account, phone_number, exchange_currency, exchange_rate, native_amount, usd_value, quantity, state, created_at FROM ` + payoutTableName + `
+		WHERE state = $1
+		ORDER BY id ASC
+		LIMIT $2`
+
+	err := db.SelectContext(ctx, &res, query, state, limit)
+	if err != nil {
+		return nil, pgutil.CheckNoRows(err, campaign.ErrPayoutNotFound)
+	}
+
+	if len(res) == 0 {
+		return nil, campaign.ErrPayoutNotFound
+	}
+	return res, nil
+}
+
+func dbGetPayoutCountByPhoneNumber(ctx context.Context, db *sqlx.DB, campaignName, phoneNumber string) (uint64, error) {
+	var res uint64
+
+	query := `SELECT COUNT(*) FROM ` + payoutTableName + `
+		WHERE campaign = $1 AND phone_number = $2 AND state != $3`
+
+	err := db.GetContext(ctx, &res, query, campaignName, phoneNumber, campaign.PayoutStateFailed)
+	if err != nil {
+		return 0, err
+	}
+	return res, nil
+}
+
+func dbGetSpend(ctx context.Context, db *sqlx.DB, campaignName string) (*spendModel, error) {
+	res := &spendModel{}
+
+	query := `SELECT COUNT(*) AS payouts, COALESCE(SUM(usd_value), 0) AS usd_value, COALESCE(SUM(quantity), 0) AS quantity FROM ` + payoutTableName + `
+		WHERE campaign = $1 AND state != $2`
+
+	err := db.GetContext(ctx, res, query, campaignName, campaign.PayoutStateFailed)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,135 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/code-payments/code-server/pkg/code/data/campaign"
+)
+
+type store struct {
+	db *sqlx.DB
+}
+
+func New(db *sql.DB) campaign.Store {
+	return &store{
+		db: sqlx.NewDb(db, "pgx"),
+	}
+}
+
+// PutCampaign implements campaign.Store.PutCampaign
+func (s *store) PutCampaign(ctx context.Context, record *campaign.Record) error {
+	m, err := toCampaignModel(record)
+	if err != nil {
+		return err
+	}
+
+	err = m.dbPut(ctx, s.db)
+	if err != nil {
+		return err
+	}
+
+	res := fromCampaignModel(m)
+	res.CopyTo(record)
+
+	return nil
+}
+
+// GetCampaign implements campaign.Store.GetCampaign
+func (s *store) GetCampaign(ctx context.Context, name string) (*campaign.Record, error) {
+	m, err := dbGetCampaign(ctx, s.db, name)
+	if err != nil {
+		return nil, err
+	}
+	return fromCampaignModel(m), nil
+}
+
+// GetAllCampaigns implements campaign.Store.GetAllCampaigns
+func (s *store) GetAllCampaigns(ctx context.Context) ([]*campaign.Record, error) {
+	models, err := dbGetAllCampaigns(ctx, s.db)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*campaign.Record, len(models))
+	for i, m := range models {
+		res[i] = fromCampaignModel(m)
+	}
+	return res, nil
+}
+
+// CreatePayout implements campaign.Store.CreatePayout
+func (s *store) CreatePayout(ctx context.Context, record *campaign.Payout) error {
+	m, err := toPayoutModel(record)
+	if err != nil {
+		return err
+	}
+
+	err = m.dbCreate(ctx, s.db)
+	if err != nil {
+		return err
+	}
+
+	res := fromPayoutModel(m)
+	res.CopyTo(record)
+
+	return nil
+}
+
+// UpdatePayoutState implements campaign.Store.UpdatePayoutState
+func (s *store) UpdatePayoutState(ctx context.Context, intentId string, state campaign.PayoutState) error {
+	return dbUpdatePayoutState(ctx, s.db, intentId, state)
+}
+
+// GetPayout implements campaign.Store.GetPayout
+func (s *store) GetPayout(ctx context.Context, campaignName, owner string) (*campaign.Payout, error) {
+	m, err := dbGetPayout(ctx, s.db, campaignName, owner)
+	if err != nil {
+		return nil, err
+	}
+	return fromPayoutModel(m), nil
+}
+
+// GetPayoutByIntentId implements campaign.Store.GetPayoutByIntentId
+func (s *store) GetPayoutByIntentId(ctx context.Context, intentId string) (*campaign.Payout, error) {
+	m, err := dbGetPayoutByIntentId(ctx, s.db, intentId)
+	if err != nil {
+		return nil, err
+	}
+	return fromPayoutModel(m), nil
+}
+
+// GetAllPayoutsByState implements campaign.Store.GetAllPayoutsByState
+func (s *store) GetAllPayoutsByState(ctx context.Context, state campaign.PayoutState, limit uint64) ([]*campaign.Payout, error) {
+	models, err := dbGetAllPayoutsByState(ctx, s.db, state, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*campaign.Payout, len(models))
+	for i, m := range models {
+		res[i] = fromPayoutModel(m)
+	}
+	return res, nil
+}
+
+// GetPayoutCountByPhoneNumber implements campaign.Store.GetPayoutCountByPhoneNumber
+func (s *store) GetPayoutCountByPhoneNumber(ctx context.Context, campaignName, phoneNumber string) (uint64, error) {
+	return dbGetPayoutCountByPhoneNumber(ctx, s.db, campaignName, phoneNumber)
+}
+
+// GetSpend implements campaign.Store.GetSpend
+func (s *store) GetSpend(ctx context.Context, campaignName string) (*campaign.Spend, error) {
+	m, err := dbGetSpend(ctx, s.db, campaignName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &campaign.Spend{
+		Payouts:  m.Payouts,
+		UsdValue: m.UsdValue,
+		Quantity: m.Quantity,
+	}, nil
+}
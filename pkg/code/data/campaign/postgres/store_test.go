@@ -0,0 +1,158 @@
+package postgres
+
+import (
+	"database/sql"
+	"os"
+	"testing"
+
+	"github.com/ory/dockertest/v3"
+	"github.com/sirupsen/logrus"
+
+	"github.com/code-payments/code-server/pkg/code/data/campaign"
+	"github.com/code-payments/code-server/pkg/code/data/campaign/tests"
+
+	postgrestest "github.com/code-payments/code-server/pkg/database/postgres/test"
+
+	_ "github.com/jackc/pgx/v4/stdlib"
+)
+
+const (
+	// Used for testing ONLY, the table and migrations are external to this repository
+	tableCreate = `
+		CREATE TABLE codewallet__core_campaign(
+			id SERIAL NOT NULL PRIMARY KEY,
+
+			name TEXT NOT NULL,
+
+			campaign_type INTEGER NOT NULL,
+
+			budget_usd NUMERIC(18, 9) NOT NULL,
+
+			phone_prefix TEXT NOT NULL,
+			max_account_age_seconds BIGINT NOT NULL,
+			max_payouts_per_phone_number BIGINT NOT NULL,
+			max_payouts_per_owner BIGINT NOT NULL,
+
+			starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			ends_at TIMESTAMP WITH TIME ZONE NOT NULL,
+
+			state INTEGER NOT NULL,
+
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+
+			CONSTRAINT codewallet__core_campaign__uniq__name UNIQUE (name)
+		);
+
+		CREATE TABLE codewallet__core_campaignamount(
+			id SERIAL NOT NULL PRIMARY KEY,
+
+			campaign TEXT NOT NULL,
+			currency VARCHAR(3) NOT NULL,
+			native_amount NUMERIC(18, 9) NOT NULL,
+
+			CONSTRAINT codewallet__core_campaignamount__uniq__campaign__and__currency UNIQUE (campaign, currency)
+		);
+
+		CREATE TABLE codewallet__core_campaignpayout(
+			id SERIAL NOT NULL PRIMARY KEY,
+
+			campaign TEXT NOT NULL,
+
+			intent_id TEXT NOT NULL,
+
+			owner_account TEXT NOT NULL,
+			phone_number TEXT NOT NULL,
+
+			exchange_currency VARCHAR(3) NOT NULL,
+			exchange_rate NUMERIC(18, 9) NOT NULL,
+			native_amount NUMERIC(18, 9) NOT NULL,
+			usd_value NUMERIC(18, 9) NOT NULL,
+			quantity BIGINT NOT NULL CHECK (quantity > 0),
+
+			state INTEGER NOT NULL,
+
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+
+			CONSTRAINT codewallet__core_campaignpayout__uniq__intent_id UNIQUE (intent_id)
+		);
+
+		CREATE INDEX codewallet__core_campaignpayout__idx__campaign__and__owner_account ON codewallet__core_campaignpayout (campaign, owner_account);
+	`
+
+	// Used for testing ONLY, the table and migrations are external to this repository
+	tableDestroy = `
+		DROP TABLE codewallet__core_campaign;
+		DROP TABLE codewallet__core_campaignamount;
+		DROP TABLE codewallet__core_campaignpayout;
+	`
+)
+
+var (
+	testStore campaign.Store
+	teardown  func()
+)
+
+func TestMain(m *testing.M) {
+	log := logrus.StandardLogger()
+
+	testPool, err := dockertest.NewPool("")
+	if err != nil {
+		log.WithError(err).Error("Error creating docker pool")
+		os.Exit(1)
+	}
+
+	var cleanUpFunc func()
+	db, cleanUpFunc, err := postgrestest.StartPostgresDB(testPool)
+	if err != nil {
+		log.WithError(err).Error("Error starting postgres image")
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	if err := createTestTables(db); err != nil {
+		logrus.StandardLogger().WithError(err).Error("Error creating test tables")
+		cleanUpFunc()
+		os.Exit(1)
+	}
+
+	testStore = New(db)
+	teardown = func() {
+		if pc := recover(); pc != nil {
+			cleanUpFunc()
+			panic(pc)
+		}
+
+		if err := resetTestTables(db); err != nil {
+			logrus.StandardLogger().WithError(err).Error("Error resetting test tables")
+			cleanUpFunc()
+			os.Exit(1)
+		}
+	}
+
+	code := m.Run()
+	cleanUpFunc()
+	os.Exit(code)
+}
+
+func TestCampaignPostgresStore(t *testing.T) {
+	tests.RunTests(t, testStore, teardown)
+}
+
+func createTestTables(db *sql.DB) error {
+	_, err := db.Exec(tableCreate)
+	if err != nil {
+		logrus.StandardLogger().WithError(err).Error("could not create test tables")
+		return err
+	}
+	return nil
+}
+
+func resetTestTables(db *sql.DB) error {
+	_, err := db.Exec(tableDestroy)
+	if err != nil {
+		logrus.StandardLogger().WithError(err).Error("could not drop test tables")
+		return err
+	}
+
+	return createTestTables(db)
+}
@@ -0,0 +1,51 @@
+package campaign
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrCampaignNotFound       = errors.New("campaign not found")
+	ErrPayoutNotFound         = errors.New("campaign payout not found")
+	ErrPayoutExists           = errors.New("campaign payout already exists")
+	ErrBudgetExceeded         = errors.New("campaign budget exceeded")
+	ErrInvalidStateTransition = errors.New("invalid campaign payout state transition")
+)
+
+type Store interface {
+	// PutCampaign creates or updates a campaign, including its amounts, by name
+	PutCampaign(ctx context.Context, record *Record) error
+
+	// GetCampaign gets a campaign by name
+	GetCampaign(ctx context.Context, name string) (*Record, error)
+
+	// GetAllCampaigns gets all campaigns in ascending order of creation
+	GetAllCampaigns(ctx context.Context) ([]*Record, error)
+
+	// CreatePayout creates a payout for a campaign. ErrPayoutExists is returned
+	// when a payout exists for the intent, or the owner has reached the campaign's
+	// maximum number of payouts per owner. ErrBudgetExceeded is returned when the
+	// payout would take the campaign's spend over its budget.
+	CreatePayout(ctx context.Context, record *Payout) error
+
+	// UpdatePayoutState transitions a pending payout to the provided state
+	UpdatePayoutState(ctx context.Context, intentId string, state PayoutState) error
+
+	// GetPayout gets the earliest payout for an owner account in a campaign
+	GetPayout(ctx context.Context, campaign, owner string) (*Payout, error)
+
+	// GetPayoutByIntentId gets a payout by the intent that fulfills it
+	GetPayoutByIntentId(ctx context.Context, intentId string) (*Payout, error)
+
+	// GetAllPayoutsByState gets payouts in the provided state in ascending order
+	// of creation
+	GetAllPayoutsByState(ctx context.Context, state PayoutState, limit uint64) ([]*Payout, error)
+
+	// GetPayoutCountByPhoneNumber gets the number of non-failed payouts for a
+	// phone number in a campaign
+	GetPayoutCountByPhoneNumber(ctx context.Context, campaign, phoneNumber string) (uint64, error)
+
+	// GetSpend gets the aggregate of all non-failed payouts for a campaign
+	GetSpend(ctx context.Context, campaign string) (*Spend, error)
+}
@@ -0,0 +1,435 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/code-payments/code-server/pkg/code/data/campaign"
+	"github.com/code-payments/code-server/pkg/currency"
+)
+
+func RunTests(t *testing.T, s campaign.Store, teardown func()) {
+	for _, tf := range []func(t *testing.T, s campaign.Store){
+		testCampaignRoundTrip,
+		testCampaignUpdate,
+		testCampaignValidation,
+		testPayoutRoundTrip,
+		testPayoutsPerOwner,
+		testPayoutBudget,
+		testPayoutStateTransitions,
+		testPayoutQueries,
+	} {
+		tf(t, s)
+		teardown()
+	}
+}
+
+func testCampaignRoundTrip(t *testing.T, s campaign.Store) {
+	t.Run("testCampaignRoundTrip", func(t *testing.T) {
+		ctx := context.Background()
+
+		_, err := s.GetCampaign(ctx, "welcome_bonus")
+		assert.Equal(t, campaign.ErrCampaignNotFound, err)
+
+		_, err = s.GetAllCampaigns(ctx)
+		assert.Equal(t, campaign.ErrCampaignNotFound, err)
+
+		var expected []*campaign.Record
+		for i, campaignType := range []campaign.Type{
+			campaign.WelcomeBonusType,
+			campaign.ReferralBonusType,
+			campaign.PromotionType,
+		} {
+			record := newTestCampaign(fmt.Sprintf("campaign%d", i))
+			record.Type = campaignType
+			cloned := record.Clone()
+
+			require.NoError(t, s.PutCampaign(ctx, record))
+			assert.True(t, record.Id > 0)
+
+			actual, err := s.GetCampaign(ctx, record.Name)
+			require.NoError(t, err)
+			assertEquivalentCampaigns(t, &cloned, actual)
+			assert.Equal(t, record.Id, actual.Id)
+
+			expected = append(expected, record)
+		}
+
+		actual, err := s.GetAllCampaigns(ctx)
+		require.NoError(t, err)
+		require.Len(t, actual, len(expected))
+		for i := range expected {
+			assertEquivalentCampaigns(t, expected[i], actual[i])
+		}
+	})
+}
+
+func testCampaignUpdate(t *testing.T, s campaign.Store) {
+	t.Run("testCampaignUpdate", func(t *testing.T) {
+		ctx := context.Background()
+
+		record := newTestCampaign("welcome_bonus")
+		require.NoError(t, s.PutCampaign(ctx, record))
+		id := record.Id
+
+		updated := newTestCampaign("welcome_bonus")
+		updated.Amounts = map[currency.Code]float64{
+			currency.USD: 2.0,
+			currency.EUR: 1.75,
+		}
+		updated.BudgetUsd = 500
+		updated.EligibilityRules = campaign.EligibilityRules{
+			PhonePrefix:              "+44",
+			MaxAccountAge:            48 * time.Hour,
+			MaxPayoutsPerPhoneNumber: 2,
+			MaxPayoutsPerOwner:       3,
+		}
+		updated.EndsAt = updated.EndsAt.Add(24 * time.Hour)
+		updated.State = campaign.StateDisabled
+		updated.CreatedAt = time.Now().Add(time.Minute)
+		require.NoError(t, s.PutCampaign(ctx, updated))
+		assert.Equal(t, id, updated.Id)
+		assert.Equal(t, record.CreatedAt.Unix(), updated.CreatedAt.Unix())
+
+		actual, err := s.GetCampaign(ctx, "welcome_bonus")
+		require.NoError(t, err)
+		assert.Equal(t, id, actual.Id)
+		assert.Equal(t, record.CreatedAt.Unix(), actual.CreatedAt.Unix())
+		updated.CreatedAt = record.CreatedAt
+		assertEquivalentCampaigns(t, updated, actual)
+
+		// Removing a currency removes its amount
+		updated.Amounts = map[currency.Code]float64{
+			currency.USD: 3.0,
+		}
+		require.NoError(t, s.PutCampaign(ctx, updated))
+
+		actual, err = s.GetCampaign(ctx, "welcome_bonus")
+		require.NoError(t, err)
+		assert.Equal(t, updated.Amounts, actual.Amounts)
+	})
+}
+
+func testCampaignValidation(t *testing.T, s campaign.Store) {
+	t.Run("testCampaignValidation", func(t *testing.T) {
+		ctx := context.Background()
+
+		for _, mutate := range []func(r *campaign.Record){
+			func(r *campaign.Record) { r.Name = "" },
+			func(r *campaign.Record) { r.Type = campaign.UnknownType },
+			func(r *campaign.Record) { delete(r.Amounts, currency.USD) },
+			func(r *campaign.Record) { r.Amounts[currency.CAD] = 0 },
+			func(r *campaign.Record) { r.BudgetUsd = 0 },
+			func(r *campaign.Record) { r.EligibilityRules.PhonePrefix = "1" },
+			func(r *campaign.Record) { r.EligibilityRules.MaxPayoutsPerPhoneNumber = 0 },
+			func(r *campaign.Record) { r.EligibilityRules.MaxPayoutsPerOwner = 0 },
+			func(r *campaign.Record) { r.EndsAt = r.StartsAt },
+			func(r *campaign.Record) { r.State = campaign.StateUnknown },
+		} {
+			record := newTestCampaign("invalid")
+			mutate(record)
+			assert.Error(t, s.PutCampaign(ctx, record))
+		}
+
+		_, err := s.GetAllCampaigns(ctx)
+		assert.Equal(t, campaign.ErrCampaignNotFound, err)
+	})
+}
+
+func testPayoutRoundTrip(t *testing.T, s campaign.Store) {
+	t.Run("testPayoutRoundTrip", func(t *testing.T) {
+		ctx := context.Background()
+
+		payout := newTestPayout("welcome_bonus", "owner1", "+12223334444")
+
+		assert.Equal(t, campaign.ErrCampaignNotFound, s.CreatePayout(ctx, payout))
+
+		require.NoError(t, s.PutCampaign(ctx, newTestCampaign("welcome_bonus")))
+
+		_, err := s.GetPayout(ctx, "welcome_bonus", "owner1")
+		assert.Equal(t, campaign.ErrPayoutNotFound, err)
+
+		cloned := payout.Clone()
+		require.NoError(t, s.CreatePayout(ctx, payout))
+		assert.True(t, payout.Id > 0)
+
+		actual, err := s.GetPayout(ctx, "welcome_bonus", "owner1")
+		require.NoError(t, err)
+		assertEquivalentPayouts(t, &cloned, actual)
+		assert.Equal(t, payout.Id, actual.Id)
+
+		_, err = s.GetPayoutByIntentId(ctx, "intent")
+		assert.Equal(t, campaign.ErrPayoutNotFound, err)
+
+		actual, err = s.GetPayoutByIntentId(ctx, payout.IntentId)
+		require.NoError(t, err)
+		assertEquivalentPayouts(t, &cloned, actual)
+		assert.Equal(t, payout.Id, actual.Id)
+
+		// Payouts are unique per owner within a campaign
+		duplicate := newTestPayout("welcome_bonus", "owner1", "+12223334444")
+		assert.Equal(t, campaign.ErrPayoutExists, s.CreatePayout(ctx, duplicate))
+
+		// Payouts are unique by intent
+		duplicate = newTestPayout("welcome_bonus", "owner2", "+12223334444")
+		duplicate.IntentId = payout.IntentId
+		assert.Equal(t, campaign.ErrPayoutExists, s.CreatePayout(ctx, duplicate))
+
+		// The same owner can receive payouts from other campaigns
+		require.NoError(t, s.PutCampaign(ctx, newTestCampaign("promotion")))
+		require.NoError(t, s.CreatePayout(ctx, newTestPayout("promotion", "owner1", "+12223334444")))
+	})
+}
+
+func testPayoutsPerOwner(t *testing.T, s campaign.Store) {
+	t.Run("testPayoutsPerOwner", func(t *testing.T) {
+		ctx := context.Background()
+
+		record := newTestCampaign("referral_bonus")
+		record.Type = campaign.ReferralBonusType
+		record.EligibilityRules.MaxPayoutsPerOwner = 3
+		require.NoError(t, s.PutCampaign(ctx, record))
+
+		// Each payout is for a different triggering intent
+		var payouts []*campaign.Payout
+		for i := 0; i < 3; i++ {
+			payout := newTestPayout("referral_bonus", "owner1", "+12223334444")
+			payout.IntentId = fmt.Sprintf("referral_bonus:owner1:%d", i)
+			require.NoError(t, s.CreatePayout(ctx, payout))
+			payouts = append(payouts, payout)
+		}
+
+		exceeding := newTestPayout("referral_bonus", "owner1", "+12223334444")
+		exceeding.IntentId = "referral_bonus:owner1:3"
+		assert.Equal(t, campaign.ErrPayoutExists, s.CreatePayout(ctx, exceeding))
+
+		_, err := s.GetPayoutByIntentId(ctx, exceeding.IntentId)
+		assert.Equal(t, campaign.ErrPayoutNotFound, err)
+
+		// Failed payouts still count towards the owner's maximum
+		require.NoError(t, s.UpdatePayoutState(ctx, payouts[0].IntentId, campaign.PayoutStateFailed))
+		assert.Equal(t, campaign.ErrPayoutExists, s.CreatePayout(ctx, exceeding))
+
+		// The earliest payout is returned for the owner
+		actual, err := s.GetPayout(ctx, "referral_bonus", "owner1")
+		require.NoError(t, err)
+		assert.Equal(t, payouts[0].IntentId, actual.IntentId)
+
+		for _, payout := range payouts {
+			actual, err = s.GetPayoutByIntentId(ctx, payout.IntentId)
+			require.NoError(t, err)
+			assert.Equal(t, payout.Id, actual.Id)
+		}
+
+		// Other owners aren't affected
+		require.NoError(t, s.CreatePayout(ctx, newTestPayout("referral_bonus", "owner2", "+12223334444")))
+
+		// Increasing the maximum applies to new payouts
+		record.EligibilityRules.MaxPayoutsPerOwner = 4
+		require.NoError(t, s.PutCampaign(ctx, record))
+		require.NoError(t, s.CreatePayout(ctx, exceeding))
+	})
+}
+
+func testPayoutBudget(t *testing.T, s campaign.Store) {
+	t.Run("testPayoutBudget", func(t *testing.T) {
+		ctx := context.Background()
+
+		record := newTestCampaign("welcome_bonus")
+		record.BudgetUsd = 3
+		require.NoError(t, s.PutCampaign(ctx, record))
+
+		var payouts []*campaign.Payout
+		for i := 0; i < 3; i++ {
+			payout := newTestPayout("welcome_bonus", fmt.Sprintf("owner%d", i), "+12223334444")
+			require.NoError(t, s.CreatePayout(ctx, payout))
+			payouts = append(payouts, payout)
+		}
+
+		exceeding := newTestPayout("welcome_bonus", "owner3", "+12223334444")
+		assert.Equal(t, campaign.ErrBudgetExceeded, s.CreatePayout(ctx, exceeding))
+
+		_, err := s.GetPayout(ctx, "welcome_bonus", "owner3")
+		assert.Equal(t, campaign.ErrPayoutNotFound, err)
+
+		// Failed payouts release their budget
+		require.NoError(t, s.UpdatePayoutState(ctx, payouts[0].IntentId, campaign.PayoutStateFailed))
+		require.NoError(t, s.CreatePayout(ctx, exceeding))
+
+		// Budget increases apply to new payouts
+		exceeding = newTestPayout("welcome_bonus", "owner4", "+12223334444")
+		assert.Equal(t, campaign.ErrBudgetExceeded, s.CreatePayout(ctx, exceeding))
+
+		record.BudgetUsd = 4
+		require.NoError(t, s.PutCampaign(ctx, record))
+		require.NoError(t, s.CreatePayout(ctx, exceeding))
+	})
+}
+
+func testPayoutStateTransitions(t *testing.T, s campaign.Store) {
+	t.Run("testPayoutStateTransitions", func(t *testing.T) {
+		ctx := context.Background()
+
+		assert.Equal(t, campaign.ErrPayoutNotFound, s.UpdatePayoutState(ctx, "intent", campaign.PayoutStateSubmitted))
+
+		require.NoError(t, s.PutCampaign(ctx, newTestCampaign("welcome_bonus")))
+
+		payout := newTestPayout("welcome_bonus", "owner1", "+12223334444")
+		require.NoError(t, s.CreatePayout(ctx, payout))
+
+		require.NoError(t, s.UpdatePayoutState(ctx, payout.IntentId, campaign.PayoutStateSubmitted))
+
+		actual, err := s.GetPayout(ctx, "welcome_bonus", "owner1")
+		require.NoError(t, err)
+		assert.Equal(t, campaign.PayoutStateSubmitted, actual.State)
+
+		// Only pending payouts can transition
+		for _, state := range []campaign.PayoutState{
+			campaign.PayoutStatePending,
+			campaign.PayoutStateSubmitted,
+			campaign.PayoutStateFailed,
+		} {
+			assert.Equal(t, campaign.ErrInvalidStateTransition, s.UpdatePayoutState(ctx, payout.IntentId, state))
+		}
+	})
+}
+
+func testPayoutQueries(t *testing.T, s campaign.Store) {
+	t.Run("testPayoutQueries", func(t *testing.T) {
+		ctx := context.Background()
+
+		_, err := s.GetAllPayoutsByState(ctx, campaign.PayoutStatePending, 10)
+		assert.Equal(t, campaign.ErrPayoutNotFound, err)
+
+		count, err := s.GetPayoutCountByPhoneNumber(ctx, "welcome_bonus", "+12223334444")
+		require.NoError(t, err)
+		assert.EqualValues(t, 0, count)
+
+		spend, err := s.GetSpend(ctx, "welcome_bonus")
+		require.NoError(t, err)
+		assert.EqualValues(t, 0, spend.Payouts)
+		assert.EqualValues(t, 0, spend.UsdValue)
+		assert.EqualValues(t, 0, spend.Quantity)
+
+		require.NoError(t, s.PutCampaign(ctx, newTestCampaign("welcome_bonus")))
+		require.NoError(t, s.PutCampaign(ctx, newTestCampaign("promotion")))
+
+		var payouts []*campaign.Payout
+		for i := 0; i < 5; i++ {
+			payout := newTestPayout("welcome_bonus", fmt.Sprintf("owner%d", i), "+12223334444")
+			require.NoError(t, s.CreatePayout(ctx, payout))
+			payouts = append(payouts, payout)
+		}
+		require.NoError(t, s.CreatePayout(ctx, newTestPayout("promotion", "owner0", "+12223334444")))
+		require.NoError(t, s.CreatePayout(ctx, newTestPayout("welcome_bonus", "owner5", "+15556667777")))
+
+		require.NoError(t, s.UpdatePayoutState(ctx, payouts[1].IntentId, campaign.PayoutStateSubmitted))
+		require.NoError(t, s.UpdatePayoutState(ctx, payouts[3].IntentId, campaign.PayoutStateFailed))
+
+		actual, err := s.GetAllPayoutsByState(ctx, campaign.PayoutStatePending, 10)
+		require.NoError(t, err)
+		require.Len(t, actual, 5)
+		assertEquivalentPayouts(t, payouts[0], actual[0])
+		assertEquivalentPayouts(t, payouts[2], actual[1])
+		assertEquivalentPayouts(t, payouts[4], actual[2])
+
+		actual, err = s.GetAllPayoutsByState(ctx, campaign.PayoutStatePending, 2)
+		require.NoError(t, err)
+		require.Len(t, actual, 2)
+		assertEquivalentPayouts(t, payouts[0], actual[0])
+		assertEquivalentPayouts(t, payouts[2], actual[1])
+
+		actual, err = s.GetAllPayoutsByState(ctx, campaign.PayoutStateSubmitted, 10)
+		require.NoError(t, err)
+		require.Len(t, actual, 1)
+		assert.Equal(t, payouts[1].IntentId, actual[0].IntentId)
+
+		count, err = s.GetPayoutCountByPhoneNumber(ctx, "welcome_bonus", "+12223334444")
+		require.NoError(t, err)
+		assert.EqualValues(t, 4, count)
+
+		count, err = s.GetPayoutCountByPhoneNumber(ctx, "welcome_bonus", "+15556667777")
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, count)
+
+		count, err = s.GetPayoutCountByPhoneNumber(ctx, "promotion", "+12223334444")
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, count)
+
+		spend, err = s.GetSpend(ctx, "welcome_bonus")
+		require.NoError(t, err)
+		assert.EqualValues(t, 5, spend.Payouts)
+		assert.EqualValues(t, 5, spend.UsdValue)
+		assert.EqualValues(t, 5*payouts[0].Quantity, spend.Quantity)
+	})
+}
+
+func newTestCampaign(name string) *campaign.Record {
+	now := time.Now()
+	return &campaign.Record{
+		Name: name,
+		Type: campaign.WelcomeBonusType,
+		Amounts: map[currency.Code]float64{
+			currency.USD: 1.0,
+			currency.CAD: 1.5,
+		},
+		BudgetUsd: 1_000,
+		EligibilityRules: campaign.EligibilityRules{
+			PhonePrefix:              "+1",
+			MaxAccountAge:            24 * time.Hour,
+			MaxPayoutsPerPhoneNumber: 1,
+			MaxPayoutsPerOwner:       1,
+		},
+		StartsAt:  now.Add(-time.Hour),
+		EndsAt:    now.Add(time.Hour),
+		State:     campaign.StateEnabled,
+		CreatedAt: now,
+	}
+}
+
+func newTestPayout(campaignName, owner, phoneNumber string) *campaign.Payout {
+	return &campaign.Payout{
+		Campaign:         campaignName,
+		IntentId:         fmt.Sprintf("%s:%s", campaignName, owner),
+		OwnerAccount:     owner,
+		PhoneNumber:      phoneNumber,
+		ExchangeCurrency: currency.USD,
+		ExchangeRate:     0.1,
+		NativeAmount:     1.0,
+		UsdValue:         1.0,
+		Quantity:         1_100_000,
+		State:            campaign.PayoutStatePending,
+		CreatedAt:        time.Now(),
+	}
+}
+
+func assertEquivalentCampaigns(t *testing.T, obj1, obj2 *campaign.Record) {
+	assert.Equal(t, obj1.Name, obj2.Name)
+	assert.Equal(t, obj1.Type, obj2.Type)
+	assert.Equal(t, obj1.Amounts, obj2.Amounts)
+	assert.Equal(t, obj1.BudgetUsd, obj2.BudgetUsd)
+	assert.Equal(t, obj1.EligibilityRules, obj2.EligibilityRules)
+	assert.Equal(t, obj1.StartsAt.Unix(), obj2.StartsAt.Unix())
+	assert.Equal(t, obj1.EndsAt.Unix(), obj2.EndsAt.Unix())
+	assert.Equal(t, obj1.State, obj2.State)
+	assert.Equal(t, obj1.CreatedAt.Unix(), obj2.CreatedAt.Unix())
+}
+
+func assertEquivalentPayouts(t *testing.T, obj1, obj2 *campaign.Payout) {
+	assert.Equal(t, obj1.Campaign, obj2.Campaign)
+	assert.Equal(t, obj1.IntentId, obj2.IntentId)
+	assert.Equal(t, obj1.OwnerAccount, obj2.OwnerAccount)
+	assert.Equal(t, obj1.PhoneNumber, obj2.PhoneNumber)
+	assert.Equal(t, obj1.ExchangeCurrency, obj2.ExchangeCurrency)
+	assert.Equal(t, obj1.ExchangeRate, obj2.ExchangeRate)
+	assert.Equal(t, obj1.NativeAmount, obj2.NativeAmount)
+	assert.Equal(t, obj1.UsdValue, obj2.UsdValue)
+	assert.Equal(t, obj1.Quantity, obj2.Quantity)
+	assert.Equal(t, obj1.State, obj2.State)
+	assert.Equal(t, obj1.CreatedAt.Unix(), obj2.CreatedAt.Unix())
+}
@@ -1,18 +1,45 @@
 package data
 
 import (
+	"context"
+	"encoding/hex"
+
+	"github.com/pkg/errors"
+
 	"github.com/code-payments/code-server/pkg/config"
 	"github.com/code-payments/code-server/pkg/config/env"
+	phoneutil "github.com/code-payments/code-server/pkg/phone"
 )
 
 const (
 	FixerApiKeyConfigEnvName = "FIXER_API_KEY"
 	defaultFixerApiKey       = ""
+
+	MaxMindCityDbPathConfigEnvName = "MAXMIND_CITY_DB_PATH"
+	defaultMaxMindCityDbPath       = ""
+
+	MaxMindAsnDbPathConfigEnvName = "MAXMIND_ASN_DB_PATH"
+	defaultMaxMindAsnDbPath       = ""
+
+	MaxMindAnonymousIpDbPathConfigEnvName = "MAXMIND_ANONYMOUS_IP_DB_PATH"
+	defaultMaxMindAnonymousIpDbPath       = ""
+
+	// Hex encoded salt that contact discovery hashes are computed with. It's
+	// published to clients, so it isn't secret. Phone verifications aren't added
+	// to the hashed index until it's set.
+	ContactDiscoverySaltConfigEnvName = "CONTACT_DISCOVERY_SALT"
+	defaultContactDiscoverySalt       = ""
 )
 
 // todo: Add other data store configs here (eg. postgres, solana, etc).
 type conf struct {
 	fixerApiKey config.String
+
+	maxMindCityDbPath        config.String
+	maxMindAsnDbPath         config.String
+	maxMindAnonymousIpDbPath config.String
+
+	contactDiscoverySalt config.String
 }
 
 // ConfigProvider defines how config values are pulled
@@ -25,6 +52,25 @@ func WithEnvConfigs() ConfigProvider {
 	return func() *conf {
 		return &conf{
 			fixerApiKey: env.NewStringConfig(FixerApiKeyConfigEnvName, defaultFixerApiKey),
+
+			maxMindCityDbPath:        env.NewStringConfig(MaxMindCityDbPathConfigEnvName, defaultMaxMindCityDbPath),
+			maxMindAsnDbPath:         env.NewStringConfig(MaxMindAsnDbPathConfigEnvName, defaultMaxMindAsnDbPath),
+			maxMindAnonymousIpDbPath: env.NewStringConfig(MaxMindAnonymousIpDbPathConfigEnvName, defaultMaxMindAnonymousIpDbPath),
+
+			contactDiscoverySalt: env.NewStringConfig(ContactDiscoverySaltConfigEnvName, defaultContactDiscoverySalt),
 		}
 	}
 }
+
+func loadContactDiscoverySalt(configProvider ConfigProvider) error {
+	encoded := configProvider().contactDiscoverySalt.Get(context.Background())
+	if len(encoded) == 0 {
+		return nil
+	}
+
+	salt, err := hex.DecodeString(encoded)
+	if err != nil {
+		return errors.Wrap(err, "contact discovery salt is not hex encoded")
+	}
+	return phoneutil.LoadContactDiscoverySalt(salt)
+}
@@ -5,20 +5,28 @@ import (
 	"encoding/binary"
 	"errors"
 	"sync"
+	"time"
 
 	"github.com/code-payments/code-server/pkg/code/data/contact"
 	"github.com/code-payments/code-server/pkg/code/data/user"
 )
 
+type discoveryLookup struct {
+	count uint32
+	at    time.Time
+}
+
 type store struct {
-	mu              sync.RWMutex
-	contactsByOwner map[string][]string
+	mu                      sync.RWMutex
+	contactsByOwner         map[string][]string
+	discoveryLookupsByOwner map[string][]*discoveryLookup
 }
 
 // New returns a new postgres backed contact.Store
 func New() contact.Store {
 	return &store{
-		contactsByOwner: make(map[string][]string),
+		contactsByOwner:         make(map[string][]string),
+		discoveryLookupsByOwner: make(map[string][]*discoveryLookup),
 	}
 }
 
@@ -78,6 +86,16 @@ func (s *store) BatchRemove(ctx context.Context, owner *user.DataContainerID, co
 	return nil
 }
 
+// RemoveAll implements contact.Store.RemoveAll
+func (s *store) RemoveAll(ctx context.Context, owner *user.DataContainerID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.contactsByOwner, owner.String())
+
+	return nil
+}
+
 // Get implements contact.Store.Get
 func (s *store) Get(ctx context.Context, owner *user.DataContainerID, limit uint32, pageToken []byte) ([]string, []byte, error) {
 	s.mu.RLock()
@@ -113,9 +131,38 @@ func (s *store) Get(ctx context.Context, owner *user.DataContainerID, limit uint
 	return contacts[lowerBound:upperBound], nextPageToken, nil
 }
 
+// RecordDiscoveryLookups implements contact.Store.RecordDiscoveryLookups
+func (s *store) RecordDiscoveryLookups(ctx context.Context, ownerAccount string, count uint32, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.discoveryLookupsByOwner[ownerAccount] = append(s.discoveryLookupsByOwner[ownerAccount], &discoveryLookup{
+		count: count,
+		at:    at,
+	})
+
+	return nil
+}
+
+// CountDiscoveryLookupsSince implements contact.Store.CountDiscoveryLookupsSince
+func (s *store) CountDiscoveryLookupsSince(ctx context.Context, ownerAccount string, since time.Time) (uint64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var res uint64
+	for _, lookup := range s.discoveryLookupsByOwner[ownerAccount] {
+		if lookup.at.After(since) {
+			res += uint64(lookup.count)
+		}
+	}
+
+	return res, nil
+}
+
 func (s *store) reset() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	s.contactsByOwner = make(map[string][]string)
+	s.discoveryLookupsByOwner = make(map[string][]*discoveryLookup)
 }
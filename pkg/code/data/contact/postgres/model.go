@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 
@@ -16,7 +17,8 @@ import (
 )
 
 const (
-	tableName = "codewallet__core_contactlist"
+	tableName                = "codewallet__core_contactlist"
+	discoveryLookupTableName = "codewallet__core_contactdiscoverylookup"
 )
 
 type model struct {
@@ -129,6 +131,18 @@ func dbBatchRemove(ctx context.Context, db *sqlx.DB, owner *user.DataContainerID
 	return err
 }
 
+func dbRemoveAll(ctx context.Context, db *sqlx.DB, owner *user.DataContainerID) error {
+	if err := owner.Validate(); err != nil {
+		return err
+	}
+
+	query := `DELETE FROM ` + tableName + `
+		WHERE owner_id = $1`
+
+	_, err := db.ExecContext(ctx, query, owner.String())
+	return err
+}
+
 func dbGetByOwner(ctx context.Context, db *sqlx.DB, owner *user.DataContainerID, exclusiveLowerBoundID uint64, limit uint32) ([]*model, bool, error) {
 	var res []*model
 	var isLastPage bool
@@ -154,3 +168,33 @@ func dbGetByOwner(ctx context.Context, db *sqlx.DB, owner *user.DataContainerID,
 
 	return res, isLastPage, nil
 }
+
+func dbRecordDiscoveryLookups(ctx context.Context, db *sqlx.DB, ownerAccount string, count uint32, at time.Time) error {
+	if len(ownerAccount) == 0 {
+		return errors.New("owner account is required")
+	}
+
+	if at.IsZero() {
+		return errors.New("lookup time is zero")
+	}
+
+	query := `INSERT INTO ` + discoveryLookupTableName + `
+		(owner_account, lookup_count, created_at)
+		VALUES ($1, $2, $3)`
+
+	_, err := db.ExecContext(ctx, query, ownerAccount, count, at.UTC())
+	return err
+}
+
+func dbCountDiscoveryLookupsSince(ctx context.Context, db *sqlx.DB, ownerAccount string, since time.Time) (uint64, error) {
+	var res uint64
+
+	query := `SELECT COALESCE(SUM(lookup_count), 0) FROM ` + discoveryLookupTableName + `
+		WHERE owner_account = $1 AND created_at > $2`
+
+	err := db.GetContext(ctx, &res, query, ownerAccount, since.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return res, nil
+}
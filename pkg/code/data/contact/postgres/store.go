@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"encoding/binary"
 	"errors"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 
@@ -53,6 +54,11 @@ func (s *store) BatchRemove(ctx context.Context, owner *user.DataContainerID, co
 	return dbBatchRemove(ctx, s.db, owner, contacts)
 }
 
+// RemoveAll implements contact.Store.RemoveAll
+func (s *store) RemoveAll(ctx context.Context, owner *user.DataContainerID) error {
+	return dbRemoveAll(ctx, s.db, owner)
+}
+
 // Get implements contact.Store.Get
 func (s *store) Get(ctx context.Context, owner *user.DataContainerID, limit uint32, pageToken []byte) ([]string, []byte, error) {
 	var exclusiveLowerBoundID uint64
@@ -80,3 +86,13 @@ func (s *store) Get(ctx context.Context, owner *user.DataContainerID, limit uint
 
 	return contacts, nextPageToken, nil
 }
+
+// RecordDiscoveryLookups implements contact.Store.RecordDiscoveryLookups
+func (s *store) RecordDiscoveryLookups(ctx context.Context, ownerAccount string, count uint32, at time.Time) error {
+	return dbRecordDiscoveryLookups(ctx, s.db, ownerAccount, count, at)
+}
+
+// CountDiscoveryLookupsSince implements contact.Store.CountDiscoveryLookupsSince
+func (s *store) CountDiscoveryLookupsSince(ctx context.Context, ownerAccount string, since time.Time) (uint64, error) {
+	return dbCountDiscoveryLookupsSince(ctx, s.db, ownerAccount, since)
+}
@@ -37,10 +37,21 @@ var defaultSchema = Schema{
 
 			CONSTRAINT codewallet__core_contactlist__uniq__owner_id__and__contact UNIQUE (owner_id, contact)
 		);
+
+		CREATE TABLE codewallet__core_contactdiscoverylookup(
+			id SERIAL NOT NULL PRIMARY KEY,
+
+			owner_account TEXT NOT NULL,
+			lookup_count INTEGER NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL
+		);
+
+		CREATE INDEX codewallet__core_contactdiscoverylookup__owner_account__created_at ON codewallet__core_contactdiscoverylookup(owner_account, created_at);
 	`,
 	// Used for testing ONLY, the table and migrations are external to this repository
 	drop: `
 		DROP TABLE codewallet__core_contactlist;
+		DROP TABLE codewallet__core_contactdiscoverylookup;
 	`,
 }
 
@@ -2,6 +2,7 @@ package contact
 
 import (
 	"context"
+	"time"
 
 	"github.com/code-payments/code-server/pkg/code/data/user"
 )
@@ -23,6 +24,18 @@ type Store interface {
 	// call is idempotent and will not fail on duplicate insertion.
 	BatchRemove(ctx context.Context, owner *user.DataContainerID, contacts []string) error
 
+	// RemoveAll removes the owner's entire contact list. This call is idempotent
+	// and will not fail when the contact list is empty.
+	RemoveAll(ctx context.Context, owner *user.DataContainerID) error
+
 	// Get gets a page of contacts from an owner's contact list.
 	Get(ctx context.Context, owner *user.DataContainerID, limit uint32, pageToken []byte) (contacts []string, nextPageToken []byte, err error)
+
+	// RecordDiscoveryLookups records that an owner account looked up a number of
+	// contact discovery hash prefixes. The prefixes themselves are never stored.
+	RecordDiscoveryLookups(ctx context.Context, ownerAccount string, count uint32, at time.Time) error
+
+	// CountDiscoveryLookupsSince counts the contact discovery hash prefixes an owner
+	// account has looked up since a timestamp.
+	CountDiscoveryLookupsSince(ctx context.Context, ownerAccount string, since time.Time) (uint64, error)
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -16,7 +17,9 @@ func RunTests(t *testing.T, s contact.Store, teardown func()) {
 	for _, tf := range []func(t *testing.T, s contact.Store){
 		testHappyPath,
 		testHappyPathForBatchCalls,
+		testRemoveAll,
 		testContractRetrievalPaging,
+		testDiscoveryLookups,
 	} {
 		tf(t, s)
 		teardown()
@@ -82,6 +85,35 @@ func testHappyPathForBatchCalls(t *testing.T, s contact.Store) {
 	})
 }
 
+func testRemoveAll(t *testing.T, s contact.Store) {
+	t.Run("testRemoveAll", func(t *testing.T) {
+		ctx := context.Background()
+
+		owner := user.NewDataContainerID()
+		otherOwner := user.NewDataContainerID()
+		contacts := make([]string, 0)
+		for i := 0; i < 10; i++ {
+			contacts = append(contacts, fmt.Sprintf("+1800555000%d", i))
+		}
+
+		require.NoError(t, s.RemoveAll(ctx, owner))
+
+		require.NoError(t, s.BatchAdd(ctx, owner, contacts))
+		require.NoError(t, s.BatchAdd(ctx, otherOwner, contacts))
+
+		require.NoError(t, s.RemoveAll(ctx, owner))
+		require.NoError(t, s.RemoveAll(ctx, owner))
+
+		actual, _, err := s.Get(ctx, owner, 100, nil)
+		require.NoError(t, err)
+		assert.Empty(t, actual)
+
+		actual, _, err = s.Get(ctx, otherOwner, 100, nil)
+		require.NoError(t, err)
+		assert.Equal(t, contacts, actual)
+	})
+}
+
 func testContractRetrievalPaging(t *testing.T, s contact.Store) {
 	t.Run("testContractRetrievalPaging", func(t *testing.T) {
 		ctx := context.Background()
@@ -126,3 +158,34 @@ func testContractRetrievalPaging(t *testing.T, s contact.Store) {
 		}
 	})
 }
+
+func testDiscoveryLookups(t *testing.T, s contact.Store) {
+	t.Run("testDiscoveryLookups", func(t *testing.T) {
+		ctx := context.Background()
+
+		owner := "owner"
+		otherOwner := "other_owner"
+		start := time.Now()
+
+		count, err := s.CountDiscoveryLookupsSince(ctx, owner, start.Add(-time.Hour))
+		require.NoError(t, err)
+		assert.EqualValues(t, 0, count)
+
+		require.NoError(t, s.RecordDiscoveryLookups(ctx, owner, 10, start.Add(-2*time.Hour)))
+		require.NoError(t, s.RecordDiscoveryLookups(ctx, owner, 20, start.Add(-30*time.Minute)))
+		require.NoError(t, s.RecordDiscoveryLookups(ctx, owner, 30, start))
+		require.NoError(t, s.RecordDiscoveryLookups(ctx, otherOwner, 40, start))
+
+		count, err = s.CountDiscoveryLookupsSince(ctx, owner, start.Add(-3*time.Hour))
+		require.NoError(t, err)
+		assert.EqualValues(t, 60, count)
+
+		count, err = s.CountDiscoveryLookupsSince(ctx, owner, start.Add(-time.Hour))
+		require.NoError(t, err)
+		assert.EqualValues(t, 50, count)
+
+		count, err = s.CountDiscoveryLookupsSince(ctx, otherOwner, start.Add(-time.Hour))
+		require.NoError(t, err)
+		assert.EqualValues(t, 40, count)
+	})
+}
@@ -0,0 +1,151 @@
+package deadletter
+
+import (
+	"errors"
+	"time"
+
+	"github.com/code-payments/code-server/pkg/code/data/fulfillment"
+)
+
+type State uint8
+
+const (
+	StateUnknown State = iota
+	StateRetried
+	StateQuarantined
+	StateReset // an operator acknowledged a retried failure
+)
+
+// Category classifies why a fulfillment's transaction failed on the blockchain
+type Category uint8
+
+const (
+	CategoryUnknown Category = iota
+	CategoryBlockhashOrNonce
+	CategoryTransient
+	CategoryInsufficientFunds
+	CategoryProgramError
+)
+
+// Record tracks the remediation of a failed fulfillment. A fulfillment has at
+// most one record, which reflects its most recent failure. Fulfillments that
+// failed in a way that's safe to retry are scheduled with a new transaction,
+// while everything else is quarantined for a human to resolve. Retried failures
+// count towards the global circuit breaker until an operator resets them.
+type Record struct {
+	Id uint64
+
+	FulfillmentId   uint64
+	Intent          string
+	FulfillmentType fulfillment.Type
+
+	// Signature of the most recently failed transaction
+	Signature string
+
+	Category Category
+	Reason   string
+
+	// Retries is the number of times the fulfillment was automatically retried
+	Retries uint32
+
+	State State
+
+	CreatedAt     time.Time
+	LastUpdatedAt time.Time
+}
+
+func (r *Record) Validate() error {
+	if r.FulfillmentId == 0 {
+		return errors.New("fulfillment id is required")
+	}
+
+	if len(r.Intent) == 0 {
+		return errors.New("intent is required")
+	}
+
+	if r.FulfillmentType == fulfillment.UnknownType {
+		return errors.New("fulfillment type is required")
+	}
+
+	if len(r.Signature) == 0 {
+		return errors.New("signature is required")
+	}
+
+	if len(r.Reason) == 0 {
+		return errors.New("reason is required")
+	}
+
+	if r.State == StateUnknown {
+		return errors.New("state is required")
+	}
+
+	return nil
+}
+
+func (r *Record) Clone() Record {
+	return Record{
+		Id: r.Id,
+
+		FulfillmentId:   r.FulfillmentId,
+		Intent:          r.Intent,
+		FulfillmentType: r.FulfillmentType,
+
+		Signature: r.Signature,
+
+		Category: r.Category,
+		Reason:   r.Reason,
+
+		Retries: r.Retries,
+
+		State: r.State,
+
+		CreatedAt:     r.CreatedAt,
+		LastUpdatedAt: r.LastUpdatedAt,
+	}
+}
+
+func (r *Record) CopyTo(dst *Record) {
+	dst.Id = r.Id
+
+	dst.FulfillmentId = r.FulfillmentId
+	dst.Intent = r.Intent
+	dst.FulfillmentType = r.FulfillmentType
+
+	dst.Signature = r.Signature
+
+	dst.Category = r.Category
+	dst.Reason = r.Reason
+
+	dst.Retries = r.Retries
+
+	dst.State = r.State
+
+	dst.CreatedAt = r.CreatedAt
+	dst.LastUpdatedAt = r.LastUpdatedAt
+}
+
+func (s State) String() string {
+	switch s {
+	case StateRetried:
+		return "retried"
+	case StateQuarantined:
+		return "quarantined"
+	case StateReset:
+		return "reset"
+	}
+	return "unknown"
+}
+
+func (c Category) String() string {
+	switch c {
+	case CategoryBlockhashOrNonce:
+		return "blockhash_or_nonce"
+	case CategoryTransient:
+		return "transient"
+	case CategoryInsufficientFunds:
+		return "insufficient_funds"
+	case CategoryProgramError:
+		return "program_error"
+	}
+	return "unknown"
+}
@@ -0,0 +1,102 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/code-payments/code-server/pkg/code/data/deadletter"
+)
+
+type store struct {
+	mu      sync.Mutex
+	records []*deadletter.Record
+	last    uint64
+}
+
+func New() deadletter.Store {
+	return &store{
+		records: make([]*deadletter.Record, 0),
+	}
+}
+
+func (s *store) reset() {
+	s.mu.Lock()
+	s.records = make([]*deadletter.Record, 0)
+	s.last = 0
+	s.mu.Unlock()
+}
+
+// Save implements deadletter.Store.Save
+func (s *store) Save(_ context.Context, data *deadletter.Record) error {
+	if err := data.Validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+
+	if item := s.find(data.FulfillmentId); item != nil {
+		item.Signature = data.Signature
+		item.Category = data.Category
+		item.Reason = data.Reason
+		item.Retries = data.Retries
+		item.State = data.State
+		item.LastUpdatedAt = now
+
+		item.CopyTo(data)
+		return nil
+	}
+
+	s.last++
+
+	data.Id = s.last
+	if data.CreatedAt.IsZero() {
+		data.CreatedAt = now
+	}
+	data.LastUpdatedAt = now
+
+	cloned := data.Clone()
+	s.records = append(s.records, &cloned)
+
+	return nil
+}
+
+// Get implements deadletter.Store.Get
+func (s *store) Get(_ context.Context, fulfillmentId uint64) (*deadletter.Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item := s.find(fulfillmentId)
+	if item == nil {
+		return nil, deadletter.ErrDeadLetterNotFound
+	}
+
+	cloned := item.Clone()
+	return &cloned, nil
+}
+
+// GetCountByStateGroupedByCategory implements deadletter.Store.GetCountByStateGroupedByCategory
+func (s *store) GetCountByStateGroupedByCategory(_ context.Context, state deadletter.State) (map[deadletter.Category]uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := make(map[deadletter.Category]uint64)
+	for _, item := range s.records {
+		if item.State == state {
+			res[item.Category]++
+		}
+	}
+	return res, nil
+}
+
+func (s *store) find(fulfillmentId uint64) *deadletter.Record {
+	for _, item := range s.records {
+		if item.FulfillmentId == fulfillmentId {
+			return item
+		}
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	pgutil "github.com/code-payments/code-server/pkg/database/postgres"
+	"github.com/code-payments/code-server/pkg/code/data/deadletter"
+	"github.com/code-payments/code-server/pkg/code/data/fulfillment"
+)
+
+const (
+	tableName = "codewallet__core_fulfillmentdeadletter"
+
+	allColumns = `id, fulfillment_id, intent, fulfillment_type, signature, category, reason, retries, state, created_at, last_updated_at`
+)
+
+type model struct {
+	Id sql.NullInt64 `db:"id"`
+
+	FulfillmentId   uint64 `db:"fulfillment_id"`
+	Intent          string `db:"intent"`
+	FulfillmentType uint8  `db:"fulfillment_type"`
+
+	Signature string `db:"signature"`
+
+	Category uint8  `db:"category"`
+	Reason   string `db:"reason"`
+
+	Retries uint32 `db:"retries"`
+
+	State uint8 `db:"state"`
+
+	CreatedAt     time.Time `db:"created_at"`
+	LastUpdatedAt time.Time `db:"last_updated_at"`
+}
+
+func toModel(obj *deadletter.Record) (*model, error) {
+	if err := obj.Validate(); err != nil {
+		return nil, err
+	}
+
+	if obj.CreatedAt.IsZero() {
+		obj.CreatedAt = time.Now().UTC()
+	}
+
+	return &model{
+		Id:              sql.NullInt64{Int64: int64(obj.Id), Valid: true},
+		FulfillmentId:   obj.FulfillmentId,
+		Intent:          obj.Intent,
+		FulfillmentType: uint8(obj.FulfillmentType),
+		Signature:       obj.Signature,
+		Category:        uint8(obj.Category),
+		Reason:          obj.Reason,
+		Retries:         obj.Retries,
+		State:           uint8(obj.State),
+		CreatedAt:       obj.CreatedAt,
+		LastUpdatedAt:   obj.LastUpdatedAt,
+	}, nil
+}
+
+func fromModel(obj *model) *deadletter.Record {
+	return &deadletter.Record{
+		Id:              uint64(obj.Id.Int64),
+		FulfillmentId:   obj.FulfillmentId,
+		Intent:          obj.Intent,
+		FulfillmentType: fulfillment.Type(obj.FulfillmentType),
+		Signature:       obj.Signature,
+		Category:        deadletter.Category(obj.Category),
+		Reason:          obj.Reason,
+		Retries:         obj.Retries,
+		State:           deadletter.State(obj.State),
+		CreatedAt:       obj.CreatedAt,
+		LastUpdatedAt:   obj.LastUpdatedAt,
+	}
+}
+
+func (m *model) dbSave(ctx context.Context, db *sqlx.DB) error {
+	return pgutil.ExecuteInTx(ctx, db, sql.LevelDefault, func(tx *sqlx.Tx) error {
+		query := `INSERT INTO ` + tableName + `
+			(fulfillment_id, intent, fulfillment_type, signature, category, reason, retries, state, created_at, last_updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+
+			ON CONFLICT (fulfillment_id)
+			DO UPDATE
+				SET signature = $4, category = $5, reason = $6, retries = $7, state = $8, last_updated_at = $10
+				WHERE ` + tableName + `.fulfillment_id = $1
+
+			RETURNING ` + allColumns
+
+		return tx.QueryRowxContext(
+			ctx,
+			query,
+			m.FulfillmentId,
+			m.Intent,
+			m.FulfillmentType,
+			m.Signature,
+			m.Category,
+			m.Reason,
+			m.Retries,
+			m.State,
+			m.CreatedAt,
+			time.Now().UTC(),
+		).StructScan(m)
+	})
+}
+
+func dbGet(ctx context.Context, db *sqlx.DB, fulfillmentId uint64) (*model, error) {
+	res := &model{}
+
+	query := `SELECT ` + allColumns + ` FROM ` + tableName + `
+		WHERE fulfillment_id = $1`
+
+	err := db.GetContext(ctx, res, query, fulfillmentId)
+	if err != nil {
+		return nil, pgutil.CheckNoRows(err, deadletter.ErrDeadLetterNotFound)
+	}
+	return res, nil
+}
+
+func dbGetCountByStateGroupedByCategory(ctx context.Context, db *sqlx.DB, state deadletter.State) (map[deadletter.Category]uint64, error) {
+	type countedCategory struct {
+		Category deadletter.Category `db:"category"`
+		Count    uint64              `db:"count"`
+	}
+
+	var countedCategories []countedCategory
+	query := `SELECT category, COUNT(*) as count FROM ` + tableName + `
+		WHERE state = $1
+		GROUP BY category
+	`
+	err := db.SelectContext(ctx, &countedCategories, query, state)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[deadletter.Category]uint64)
+	for _, countedCategory := range countedCategories {
+		res[countedCategory.Category] = countedCategory.Count
+	}
+	return res, nil
+}
@@ -0,0 +1,52 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/code-payments/code-server/pkg/code/data/deadletter"
+)
+
+type store struct {
+	db *sqlx.DB
+}
+
+func New(db *sql.DB) deadletter.Store {
+	return &store{
+		db: sqlx.NewDb(db, "pgx"),
+	}
+}
+
+// Save implements deadletter.Store.Save
+func (s *store) Save(ctx context.Context, record *deadletter.Record) error {
+	m, err := toModel(record)
+	if err != nil {
+		return err
+	}
+
+	err = m.dbSave(ctx, s.db)
+	if err != nil {
+		return err
+	}
+
+	res := fromModel(m)
+	res.CopyTo(record)
+
+	return nil
+}
+
+// Get implements deadletter.Store.Get
+func (s *store) Get(ctx context.Context, fulfillmentId uint64) (*deadletter.Record, error) {
+	m, err := dbGet(ctx, s.db, fulfillmentId)
+	if err != nil {
+		return nil, err
+	}
+	return fromModel(m), nil
+}
+
+// GetCountByStateGroupedByCategory implements deadletter.Store.GetCountByStateGroupedByCategory
+func (s *store) GetCountByStateGroupedByCategory(ctx context.Context, state deadletter.State) (map[deadletter.Category]uint64, error) {
+	return dbGetCountByStateGroupedByCategory(ctx, s.db, state)
+}
@@ -0,0 +1,22 @@
+package deadletter
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrDeadLetterNotFound = errors.New("dead letter not found")
+)
+
+type Store interface {
+	// Save creates or updates the dead letter for a fulfillment
+	Save(ctx context.Context, record *Record) error
+
+	// Get gets the dead letter for a fulfillment
+	Get(ctx context.Context, fulfillmentId uint64) (*Record, error)
+
+	// GetCountByStateGroupedByCategory gets the number of dead letters in the
+	// provided state for each category
+	GetCountByStateGroupedByCategory(ctx context.Context, state State) (map[Category]uint64, error)
+}
@@ -0,0 +1,152 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/code-payments/code-server/pkg/code/data/deadletter"
+	"github.com/code-payments/code-server/pkg/code/data/fulfillment"
+)
+
+func RunTests(t *testing.T, s deadletter.Store, teardown func()) {
+	for _, tf := range []func(t *testing.T, s deadletter.Store){
+		testRoundTrip,
+		testValidation,
+		testGetCountByStateGroupedByCategory,
+	} {
+		tf(t, s)
+		teardown()
+	}
+}
+
+func testRoundTrip(t *testing.T, s deadletter.Store) {
+	t.Run("testRoundTrip", func(t *testing.T) {
+		ctx := context.Background()
+
+		_, err := s.Get(ctx, 1)
+		assert.Equal(t, deadletter.ErrDeadLetterNotFound, err)
+
+		record := newTestRecord(1)
+		cloned := record.Clone()
+
+		require.NoError(t, s.Save(ctx, record))
+		assert.True(t, record.Id > 0)
+		assert.False(t, record.CreatedAt.IsZero())
+		assert.False(t, record.LastUpdatedAt.IsZero())
+
+		actual, err := s.Get(ctx, 1)
+		require.NoError(t, err)
+		assertEquivalentRecords(t, &cloned, actual)
+		assert.Equal(t, record.Id, actual.Id)
+
+		// Subsequent failures update the existing record
+		record.Signature = "signature2"
+		record.Category = deadletter.CategoryProgramError
+		record.Reason = "Error processing Instruction 1: custom program error: 1771"
+		record.Retries = 1
+		record.State = deadletter.StateQuarantined
+		cloned = record.Clone()
+
+		require.NoError(t, s.Save(ctx, record))
+
+		actual, err = s.Get(ctx, 1)
+		require.NoError(t, err)
+		assertEquivalentRecords(t, &cloned, actual)
+		assert.Equal(t, record.Id, actual.Id)
+		assert.Equal(t, record.CreatedAt.Unix(), actual.CreatedAt.Unix())
+
+		_, err = s.Get(ctx, 2)
+		assert.Equal(t, deadletter.ErrDeadLetterNotFound, err)
+	})
+}
+
+func testValidation(t *testing.T, s deadletter.Store) {
+	t.Run("testValidation", func(t *testing.T) {
+		ctx := context.Background()
+
+		for _, invalid := range []func(r *deadletter.Record){
+			func(r *deadletter.Record) { r.FulfillmentId = 0 },
+			func(r *deadletter.Record) { r.Intent = "" },
+			func(r *deadletter.Record) { r.FulfillmentType = fulfillment.UnknownType },
+			func(r *deadletter.Record) { r.Signature = "" },
+			func(r *deadletter.Record) { r.Reason = "" },
+			func(r *deadletter.Record) { r.State = deadletter.StateUnknown },
+		} {
+			record := newTestRecord(1)
+			invalid(record)
+			assert.Error(t, s.Save(ctx, record))
+		}
+
+		_, err := s.Get(ctx, 1)
+		assert.Equal(t, deadletter.ErrDeadLetterNotFound, err)
+	})
+}
+
+func testGetCountByStateGroupedByCategory(t *testing.T, s deadletter.Store) {
+	t.Run("testGetCountByStateGroupedByCategory", func(t *testing.T) {
+		ctx := context.Background()
+
+		counts, err := s.GetCountByStateGroupedByCategory(ctx, deadletter.StateQuarantined)
+		require.NoError(t, err)
+		assert.Empty(t, counts)
+
+		for i, category := range []deadletter.Category{
+			deadletter.CategoryBlockhashOrNonce,
+			deadletter.CategoryInsufficientFunds,
+			deadletter.CategoryInsufficientFunds,
+			deadletter.CategoryProgramError,
+			deadletter.CategoryUnknown,
+		} {
+			record := newTestRecord(uint64(i + 1))
+			record.Category = category
+			record.State = deadletter.StateQuarantined
+			if category == deadletter.CategoryBlockhashOrNonce {
+				record.State = deadletter.StateRetried
+			}
+			require.NoError(t, s.Save(ctx, record))
+		}
+
+		counts, err = s.GetCountByStateGroupedByCategory(ctx, deadletter.StateQuarantined)
+		require.NoError(t, err)
+		assert.Equal(t, map[deadletter.Category]uint64{
+			deadletter.CategoryInsufficientFunds: 2,
+			deadletter.CategoryProgramError:      1,
+			deadletter.CategoryUnknown:           1,
+		}, counts)
+
+		counts, err = s.GetCountByStateGroupedByCategory(ctx, deadletter.StateRetried)
+		require.NoError(t, err)
+		assert.Equal(t, map[deadletter.Category]uint64{
+			deadletter.CategoryBlockhashOrNonce: 1,
+		}, counts)
+	})
+}
+
+func newTestRecord(fulfillmentId uint64) *deadletter.Record {
+	return &deadletter.Record{
+		FulfillmentId:   fulfillmentId,
+		Intent:          "intent",
+		FulfillmentType: fulfillment.TransferWithCommitment,
+		Signature:       "signature1",
+		Category:        deadletter.CategoryBlockhashOrNonce,
+		Reason:          "BlockhashNotFound",
+		State:           deadletter.StateRetried,
+		CreatedAt:       time.Now(),
+	}
+}
+
+func assertEquivalentRecords(t *testing.T, obj1, obj2 *deadletter.Record) {
+	assert.Equal(t, obj1.FulfillmentId, obj2.FulfillmentId)
+	assert.Equal(t, obj1.Intent, obj2.Intent)
+	assert.Equal(t, obj1.FulfillmentType, obj2.FulfillmentType)
+	assert.Equal(t, obj1.Signature, obj2.Signature)
+	assert.Equal(t, obj1.Category, obj2.Category)
+	assert.Equal(t, obj1.Reason, obj2.Reason)
+	assert.Equal(t, obj1.Retries, obj2.Retries)
+	assert.Equal(t, obj1.State, obj2.State)
+	assert.Equal(t, obj1.CreatedAt.Unix(), obj2.CreatedAt.Unix())
+}
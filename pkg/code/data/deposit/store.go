@@ -34,8 +34,9 @@ type Store interface {
 	// Get gets a deposit record for a signature and account
 	Get(ctx context.Context, signature, account string) (*Record, error)
 
-	// GetKinAmount gets the total deposited Kin amount in quarks to an account
-	// for finalized transactions
+	// GetKinAmount gets the total deposited amount in quarks to an account
+	// for finalized transactions. Despite the name, quarks are for the account's
+	// mint, which is Kin unless the account is for another supported mint.
 	GetKinAmount(ctx context.Context, account string) (uint64, error)
 
 	// GetKinAmountBatch is like GetKinAmount but for a batch of accounts
@@ -70,6 +70,48 @@ func (s *store) Get(_ context.Context, id string) (*event.Record, error) {
 	return &cloned, nil
 }
 
+// CountBySourceIdentityAndTypeSince implements event.Store.CountBySourceIdentityAndTypeSince
+func (s *store) CountBySourceIdentityAndTypeSince(_ context.Context, identity string, eventType event.EventType, since time.Time) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var count uint64
+	for _, item := range s.records {
+		if item.SourceIdentity == identity && item.EventType == eventType && item.CreatedAt.After(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// CountBySourceClientIpAndTypeSince implements event.Store.CountBySourceClientIpAndTypeSince
+func (s *store) CountBySourceClientIpAndTypeSince(_ context.Context, ip string, eventType event.EventType, since time.Time) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var count uint64
+	for _, item := range s.records {
+		if item.SourceClientIp != nil && *item.SourceClientIp == ip && item.EventType == eventType && item.CreatedAt.After(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// CountBySourceIdentityAndMinSpamConfidenceSince implements event.Store.CountBySourceIdentityAndMinSpamConfidenceSince
+func (s *store) CountBySourceIdentityAndMinSpamConfidenceSince(_ context.Context, identity string, minSpamConfidence float64, since time.Time) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var count uint64
+	for _, item := range s.records {
+		if item.SourceIdentity == identity && item.SpamConfidence >= minSpamConfidence && item.CreatedAt.After(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
 func (s *store) find(data *event.Record) *event.Record {
 	for _, item := range s.records {
 		if item.Id == data.Id {
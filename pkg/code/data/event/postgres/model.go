@@ -191,3 +191,45 @@ func dbGet(ctx context.Context, db *sqlx.DB, id string) (*model, error) {
 	}
 	return &res, nil
 }
+
+func dbCountBySourceIdentityAndTypeSince(ctx context.Context, db *sqlx.DB, identity string, eventType event.EventType, since time.Time) (uint64, error) {
+	var res uint64
+
+	query := `SELECT COUNT(*) FROM ` + tableName + `
+		WHERE source_identity = $1 AND event_type = $2 AND created_at > $3
+	`
+
+	err := db.GetContext(ctx, &res, query, identity, eventType, since)
+	if err != nil {
+		return 0, err
+	}
+	return res, nil
+}
+
+func dbCountBySourceClientIpAndTypeSince(ctx context.Context, db *sqlx.DB, ip string, eventType event.EventType, since time.Time) (uint64, error) {
+	var res uint64
+
+	query := `SELECT COUNT(*) FROM ` + tableName + `
+		WHERE source_client_ip = $1 AND event_type = $2 AND created_at > $3
+	`
+
+	err := db.GetContext(ctx, &res, query, ip, eventType, since)
+	if err != nil {
+		return 0, err
+	}
+	return res, nil
+}
+
+func dbCountBySourceIdentityAndMinSpamConfidenceSince(ctx context.Context, db *sqlx.DB, identity string, minSpamConfidence float64, since time.Time) (uint64, error) {
+	var res uint64
+
+	query := `SELECT COUNT(*) FROM ` + tableName + `
+		WHERE source_identity = $1 AND spam_confidence >= $2 AND created_at > $3
+	`
+
+	err := db.GetContext(ctx, &res, query, identity, minSpamConfidence, since)
+	if err != nil {
+		return 0, err
+	}
+	return res, nil
+}
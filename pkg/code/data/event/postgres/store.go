@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 
@@ -44,3 +45,18 @@ func (s *store) Get(ctx context.Context, id string) (*event.Record, error) {
 	}
 	return fromModel(model), nil
 }
+
+// CountBySourceIdentityAndTypeSince implements event.Store.CountBySourceIdentityAndTypeSince
+func (s *store) CountBySourceIdentityAndTypeSince(ctx context.Context, identity string, eventType event.EventType, since time.Time) (uint64, error) {
+	return dbCountBySourceIdentityAndTypeSince(ctx, s.db, identity, eventType, since)
+}
+
+// CountBySourceClientIpAndTypeSince implements event.Store.CountBySourceClientIpAndTypeSince
+func (s *store) CountBySourceClientIpAndTypeSince(ctx context.Context, ip string, eventType event.EventType, since time.Time) (uint64, error) {
+	return dbCountBySourceClientIpAndTypeSince(ctx, s.db, ip, eventType, since)
+}
+
+// CountBySourceIdentityAndMinSpamConfidenceSince implements event.Store.CountBySourceIdentityAndMinSpamConfidenceSince
+func (s *store) CountBySourceIdentityAndMinSpamConfidenceSince(ctx context.Context, identity string, minSpamConfidence float64, since time.Time) (uint64, error) {
+	return dbCountBySourceIdentityAndMinSpamConfidenceSince(ctx, s.db, identity, minSpamConfidence, since)
+}
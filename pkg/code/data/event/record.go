@@ -58,6 +58,24 @@ type Record struct {
 	CreatedAt time.Time
 }
 
+func (t EventType) String() string {
+	switch t {
+	case AccountCreated:
+		return "account_created"
+	case WelcomeBonusClaimed:
+		return "welcome_bonus_claimed"
+	case InPersonGrab:
+		return "in_person_grab"
+	case RemoteSend:
+		return "remote_send"
+	case MicroPayment:
+		return "micro_payment"
+	case Withdrawal:
+		return "withdrawal"
+	}
+	return "unknown"
+}
+
 // todo: Per-event type validation than just the basic stuff defined here
 func (r *Record) Validate() error {
 	if len(r.EventId) == 0 {
@@ -3,6 +3,7 @@ package event
 import (
 	"context"
 	"errors"
+	"time"
 )
 
 var (
@@ -17,5 +18,18 @@ type Store interface {
 	// Get gets an event record by its event ID
 	Get(ctx context.Context, id string) (*Record, error)
 
+	// CountBySourceIdentityAndTypeSince counts the number of events of the provided
+	// type originating from a source identity since the provided time
+	CountBySourceIdentityAndTypeSince(ctx context.Context, identity string, eventType EventType, since time.Time) (uint64, error)
+
+	// CountBySourceClientIpAndTypeSince counts the number of events of the provided
+	// type originating from a source client IP since the provided time
+	CountBySourceClientIpAndTypeSince(ctx context.Context, ip string, eventType EventType, since time.Time) (uint64, error)
+
+	// CountBySourceIdentityAndMinSpamConfidenceSince counts the number of events
+	// originating from a source identity with a spam confidence at or above the
+	// provided value since the provided time
+	CountBySourceIdentityAndMinSpamConfidenceSince(ctx context.Context, identity string, minSpamConfidence float64, since time.Time) (uint64, error)
+
 	// todo: Various other methods that can help us with product or spam tracking
 }
@@ -2,6 +2,7 @@ package tests
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
@@ -15,6 +16,7 @@ import (
 func RunTests(t *testing.T, s event.Store, teardown func()) {
 	for _, tf := range []func(t *testing.T, s event.Store){
 		testHappyPath,
+		testCountQueries,
 	} {
 		tf(t, s)
 		teardown()
@@ -85,6 +87,73 @@ func testHappyPath(t *testing.T, s event.Store) {
 	})
 }
 
+func testCountQueries(t *testing.T, s event.Store) {
+	t.Run("testCountQueries", func(t *testing.T) {
+		ctx := context.Background()
+
+		start := time.Now()
+
+		for i, spamConfidence := range []float64{0.1, 0.5, 0.9} {
+			for _, eventType := range []event.EventType{event.RemoteSend, event.Withdrawal} {
+				require.NoError(t, s.Save(ctx, &event.Record{
+					EventId:   fmt.Sprintf("event_id_%d_%d", i, eventType),
+					EventType: eventType,
+
+					SourceCodeAccount: "source_code_account",
+
+					SourceIdentity: "source_identity",
+
+					SourceClientIp: pointer.String("source_client_ip"),
+
+					SpamConfidence: spamConfidence,
+
+					CreatedAt: start.Add(time.Duration(i) * time.Minute),
+				}))
+			}
+		}
+
+		count, err := s.CountBySourceIdentityAndTypeSince(ctx, "source_identity", event.RemoteSend, start.Add(-time.Minute))
+		require.NoError(t, err)
+		assert.EqualValues(t, 3, count)
+
+		count, err = s.CountBySourceIdentityAndTypeSince(ctx, "source_identity", event.RemoteSend, start)
+		require.NoError(t, err)
+		assert.EqualValues(t, 2, count)
+
+		count, err = s.CountBySourceIdentityAndTypeSince(ctx, "source_identity", event.MicroPayment, start.Add(-time.Minute))
+		require.NoError(t, err)
+		assert.EqualValues(t, 0, count)
+
+		count, err = s.CountBySourceIdentityAndTypeSince(ctx, "other_identity", event.RemoteSend, start.Add(-time.Minute))
+		require.NoError(t, err)
+		assert.EqualValues(t, 0, count)
+
+		count, err = s.CountBySourceClientIpAndTypeSince(ctx, "source_client_ip", event.Withdrawal, start.Add(-time.Minute))
+		require.NoError(t, err)
+		assert.EqualValues(t, 3, count)
+
+		count, err = s.CountBySourceClientIpAndTypeSince(ctx, "source_client_ip", event.Withdrawal, start.Add(90*time.Second))
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, count)
+
+		count, err = s.CountBySourceClientIpAndTypeSince(ctx, "other_ip", event.Withdrawal, start.Add(-time.Minute))
+		require.NoError(t, err)
+		assert.EqualValues(t, 0, count)
+
+		count, err = s.CountBySourceIdentityAndMinSpamConfidenceSince(ctx, "source_identity", 0.5, start.Add(-time.Minute))
+		require.NoError(t, err)
+		assert.EqualValues(t, 4, count)
+
+		count, err = s.CountBySourceIdentityAndMinSpamConfidenceSince(ctx, "source_identity", 0.5, start.Add(90*time.Second))
+		require.NoError(t, err)
+		assert.EqualValues(t, 2, count)
+
+		count, err = s.CountBySourceIdentityAndMinSpamConfidenceSince(ctx, "source_identity", 0.95, start.Add(-time.Minute))
+		require.NoError(t, err)
+		assert.EqualValues(t, 0, count)
+	})
+}
+
 func assertEquivalentRecords(t *testing.T, obj1, obj2 *event.Record) {
 	assert.Equal(t, obj1.EventId, obj2.EventId)
 	assert.Equal(t, obj1.EventType, obj2.EventType)
@@ -0,0 +1,120 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/code-payments/code-server/pkg/code/data/featureflag"
+)
+
+type store struct {
+	mu      sync.Mutex
+	records []*featureflag.Record
+	last    uint64
+}
+
+func New() featureflag.Store {
+	return &store{
+		records: make([]*featureflag.Record, 0),
+		last:    0,
+	}
+}
+
+func (s *store) reset() {
+	s.mu.Lock()
+	s.records = make([]*featureflag.Record, 0)
+	s.last = 0
+	s.mu.Unlock()
+}
+
+// Put implements featureflag.Store.Put
+func (s *store) Put(_ context.Context, data *featureflag.Record) error {
+	if err := data.Validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.last++
+	data.LastUpdatedAt = time.Now()
+	if item := s.find(data.Name); item != nil {
+		cloned := data.Clone()
+		cloned.Id = item.Id
+		cloned.CreatedAt = item.CreatedAt
+		cloned.CopyTo(item)
+
+		item.CopyTo(data)
+	} else {
+		if data.Id == 0 {
+			data.Id = s.last
+		}
+		if data.CreatedAt.IsZero() {
+			data.CreatedAt = time.Now()
+		}
+		c := data.Clone()
+		s.records = append(s.records, &c)
+	}
+
+	return nil
+}
+
+// Get implements featureflag.Store.Get
+func (s *store) Get(_ context.Context, name string) (*featureflag.Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item := s.find(name)
+	if item == nil {
+		return nil, featureflag.ErrFlagNotFound
+	}
+
+	cloned := item.Clone()
+	return &cloned, nil
+}
+
+// Delete implements featureflag.Store.Delete
+func (s *store) Delete(_ context.Context, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, item := range s.records {
+		if item.Name == name {
+			s.records = append(s.records[:i], s.records[i+1:]...)
+			return nil
+		}
+	}
+	return featureflag.ErrFlagNotFound
+}
+
+// GetAll implements featureflag.Store.GetAll
+func (s *store) GetAll(_ context.Context) ([]*featureflag.Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.records) == 0 {
+		return nil, featureflag.ErrFlagNotFound
+	}
+
+	res := make([]*featureflag.Record, len(s.records))
+	for i, item := range s.records {
+		cloned := item.Clone()
+		res[i] = &cloned
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Name < res[j].Name
+	})
+	return res, nil
+}
+
+func (s *store) find(name string) *featureflag.Record {
+	for _, item := range s.records {
+		if item.Name == name {
+			return item
+		}
+	}
+	return nil
+}
@@ -0,0 +1,214 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+
+	pgutil "github.com/code-payments/code-server/pkg/database/postgres"
+	"github.com/code-payments/code-server/pkg/grpc/client"
+	"github.com/code-payments/code-server/pkg/code/data/featureflag"
+)
+
+const (
+	tableName = "codewallet__core_featureflag"
+
+	listSeparator = ","
+)
+
+type model struct {
+	Id sql.NullInt64 `db:"id"`
+
+	Name string `db:"name"`
+
+	IsEnabled bool `db:"is_enabled"`
+
+	DeviceTypes string         `db:"device_types"`
+	MinVersion  sql.NullString `db:"min_version"`
+	MaxVersion  sql.NullString `db:"max_version"`
+
+	RolloutPercentage uint8 `db:"rollout_percentage"`
+
+	Countries string `db:"countries"`
+
+	CreatedAt     time.Time `db:"created_at"`
+	LastUpdatedAt time.Time `db:"last_updated_at"`
+}
+
+func toModel(obj *featureflag.Record) (*model, error) {
+	if err := obj.Validate(); err != nil {
+		return nil, err
+	}
+
+	if obj.CreatedAt.IsZero() {
+		obj.CreatedAt = time.Now().UTC()
+	}
+
+	deviceTypes := make([]string, len(obj.DeviceTypes))
+	for i, deviceType := range obj.DeviceTypes {
+		deviceTypes[i] = strconv.Itoa(int(deviceType))
+	}
+
+	var minVersion, maxVersion sql.NullString
+	if obj.MinVersion != nil {
+		minVersion.Valid = true
+		minVersion.String = obj.MinVersion.String()
+	}
+	if obj.MaxVersion != nil {
+		maxVersion.Valid = true
+		maxVersion.String = obj.MaxVersion.String()
+	}
+
+	return &model{
+		Id: sql.NullInt64{Int64: int64(obj.Id), Valid: true},
+
+		Name: obj.Name,
+
+		IsEnabled: obj.IsEnabled,
+
+		DeviceTypes: strings.Join(deviceTypes, listSeparator),
+		MinVersion:  minVersion,
+		MaxVersion:  maxVersion,
+
+		RolloutPercentage: obj.RolloutPercentage,
+
+		Countries: strings.Join(obj.Countries, listSeparator),
+
+		CreatedAt:     obj.CreatedAt,
+		LastUpdatedAt: time.Now().UTC(),
+	}, nil
+}
+
+func fromModel(obj *model) (*featureflag.Record, error) {
+	var deviceTypes []client.DeviceType
+	for _, value := range splitList(obj.DeviceTypes) {
+		deviceType, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid device type")
+		}
+		deviceTypes = append(deviceTypes, client.DeviceType(deviceType))
+	}
+
+	var minVersion, maxVersion *client.Version
+	var err error
+	if obj.MinVersion.Valid {
+		minVersion, err = client.ParseVersion(obj.MinVersion.String)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid min version")
+		}
+	}
+	if obj.MaxVersion.Valid {
+		maxVersion, err = client.ParseVersion(obj.MaxVersion.String)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid max version")
+		}
+	}
+
+	return &featureflag.Record{
+		Id: uint64(obj.Id.Int64),
+
+		Name: obj.Name,
+
+		IsEnabled: obj.IsEnabled,
+
+		DeviceTypes: deviceTypes,
+		MinVersion:  minVersion,
+		MaxVersion:  maxVersion,
+
+		RolloutPercentage: obj.RolloutPercentage,
+
+		Countries: splitList(obj.Countries),
+
+		CreatedAt:     obj.CreatedAt,
+		LastUpdatedAt: obj.LastUpdatedAt,
+	}, nil
+}
+
+func (m *model) dbPut(ctx context.Context, db *sqlx.DB) error {
+	return pgutil.ExecuteInTx(ctx, db, sql.LevelDefault, func(tx *sqlx.Tx) error {
+		query := `INSERT INTO ` + tableName + `
+			(name, is_enabled, device_types, min_version, max_version, rollout_percentage, countries, created_at, last_updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+
+			ON CONFLICT (name)
+			DO UPDATE
+				SET is_enabled = $2, device_types = $3, min_version = $4, max_version = $5, rollout_percentage = $6, countries = $7, last_updated_at = $9
+				WHERE ` + tableName + `.name = $1
+
+			RETURNING id, name, is_enabled, device_types, min_version, max_version, rollout_percentage, countries, created_at, last_updated_at`
+
+		return tx.QueryRowxContext(
+			ctx,
+			query,
+			m.Name,
+			m.IsEnabled,
+			m.DeviceTypes,
+			m.MinVersion,
+			m.MaxVersion,
+			m.RolloutPercentage,
+			m.Countries,
+			m.CreatedAt,
+			m.LastUpdatedAt,
+		).StructScan(m)
+	})
+}
+
+func dbGet(ctx context.Context, db *sqlx.DB, name string) (*model, error) {
+	res := &model{}
+
+	query := `SELECT id, name, is_enabled, device_types, min_version, max_version, rollout_percentage, countries, created_at, last_updated_at FROM ` + tableName + `
+		WHERE name = $1`
+
+	err := db.GetContext(ctx, res, query, name)
+	if err != nil {
+		return nil, pgutil.CheckNoRows(err, featureflag.ErrFlagNotFound)
+	}
+	return res, nil
+}
+
+func dbDelete(ctx context.Context, db *sqlx.DB, name string) error {
+	query := `DELETE FROM ` + tableName + `
+		WHERE name = $1`
+
+	res, err := db.ExecContext(ctx, query, name)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	} else if rowsAffected == 0 {
+		return featureflag.ErrFlagNotFound
+	}
+	return nil
+}
+
+func dbGetAll(ctx context.Context, db *sqlx.DB) ([]*model, error) {
+	res := []*model{}
+
+	query := `SELECT id, name, is_enabled, device_types, min_version, max_version, rollout_percentage, countries, created_at, last_updated_at FROM ` + tableName + `
+		ORDER BY name ASC`
+
+	err := db.SelectContext(ctx, &res, query)
+	if err != nil {
+		return nil, pgutil.CheckNoRows(err, featureflag.ErrFlagNotFound)
+	}
+
+	if len(res) == 0 {
+		return nil, featureflag.ErrFlagNotFound
+	}
+	return res, nil
+}
+
+func splitList(value string) []string {
+	if len(value) == 0 {
+		return nil
+	}
+	return strings.Split(value, listSeparator)
+}
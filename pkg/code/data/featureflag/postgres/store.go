@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/code-payments/code-server/pkg/code/data/featureflag"
+)
+
+type store struct {
+	db *sqlx.DB
+}
+
+func New(db *sql.DB) featureflag.Store {
+	return &store{
+		db: sqlx.NewDb(db, "pgx"),
+	}
+}
+
+// Put implements featureflag.Store.Put
+func (s *store) Put(ctx context.Context, record *featureflag.Record) error {
+	m, err := toModel(record)
+	if err != nil {
+		return err
+	}
+
+	err = m.dbPut(ctx, s.db)
+	if err != nil {
+		return err
+	}
+
+	res, err := fromModel(m)
+	if err != nil {
+		return err
+	}
+	res.CopyTo(record)
+
+	return nil
+}
+
+// Get implements featureflag.Store.Get
+func (s *store) Get(ctx context.Context, name string) (*featureflag.Record, error) {
+	m, err := dbGet(ctx, s.db, name)
+	if err != nil {
+		return nil, err
+	}
+	return fromModel(m)
+}
+
+// Delete implements featureflag.Store.Delete
+func (s *store) Delete(ctx context.Context, name string) error {
+	return dbDelete(ctx, s.db, name)
+}
+
+// GetAll implements featureflag.Store.GetAll
+func (s *store) GetAll(ctx context.Context) ([]*featureflag.Record, error) {
+	models, err := dbGetAll(ctx, s.db)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*featureflag.Record, len(models))
+	for i, m := range models {
+		res[i], err = fromModel(m)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
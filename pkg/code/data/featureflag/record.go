@@ -0,0 +1,147 @@
+package featureflag
+
+import (
+	"errors"
+	"regexp"
+	"time"
+
+	"github.com/code-payments/code-server/pkg/grpc/client"
+)
+
+var (
+	nameRegex    = regexp.MustCompile("^[a-z0-9_]+$")
+	countryRegex = regexp.MustCompile("^[A-Z]{2}$")
+)
+
+// Record is a feature flag with rules that target a subset of clients. A flag
+// is on for a client when it's enabled and the client matches every rule.
+type Record struct {
+	Id uint64
+
+	Name string
+
+	// IsEnabled is a kill switch. Disabled flags are off for all clients,
+	// regardless of their rules.
+	IsEnabled bool
+
+	// DeviceTypes restricts the flag to clients on one of the device types.
+	// All device types are targeted when empty.
+	DeviceTypes []client.DeviceType
+
+	// MinVersion and MaxVersion restrict the flag to client versions within
+	// [MinVersion, MaxVersion). The range is unbounded on either side when nil.
+	MinVersion *client.Version
+	MaxVersion *client.Version
+
+	// RolloutPercentage is the percentage of owner accounts, from 0 to 100, that
+	// the flag is rolled out to
+	RolloutPercentage uint8
+
+	// Countries restricts the flag to clients whose IP is located in one of the
+	// ISO 3166-1 alpha-2 country codes. All countries are targeted when empty.
+	Countries []string
+
+	CreatedAt     time.Time
+	LastUpdatedAt time.Time
+}
+
+func (r *Record) Validate() error {
+	if !nameRegex.MatchString(r.Name) {
+		return errors.New("name must be non-empty snake case")
+	}
+
+	seenDeviceTypes := make(map[client.DeviceType]struct{})
+	for _, deviceType := range r.DeviceTypes {
+		if !deviceType.IsMobile() {
+			return errors.New("device type must be a mobile device type")
+		}
+
+		if _, ok := seenDeviceTypes[deviceType]; ok {
+			return errors.New("duplicate device type")
+		}
+		seenDeviceTypes[deviceType] = struct{}{}
+	}
+
+	if r.MinVersion != nil && r.MaxVersion != nil && !r.MinVersion.Before(r.MaxVersion) {
+		return errors.New("min version must be before max version")
+	}
+
+	if r.RolloutPercentage > 100 {
+		return errors.New("rollout percentage cannot exceed 100")
+	}
+
+	seenCountries := make(map[string]struct{})
+	for _, country := range r.Countries {
+		if !countryRegex.MatchString(country) {
+			return errors.New("country must be an upper case iso 3166-1 alpha-2 code")
+		}
+
+		if _, ok := seenCountries[country]; ok {
+			return errors.New("duplicate country")
+		}
+		seenCountries[country] = struct{}{}
+	}
+
+	return nil
+}
+
+func (r *Record) Clone() Record {
+	var deviceTypes []client.DeviceType
+	if len(r.DeviceTypes) > 0 {
+		deviceTypes = append(deviceTypes, r.DeviceTypes...)
+	}
+
+	var countries []string
+	if len(r.Countries) > 0 {
+		countries = append(countries, r.Countries...)
+	}
+
+	return Record{
+		Id: r.Id,
+
+		Name: r.Name,
+
+		IsEnabled: r.IsEnabled,
+
+		DeviceTypes: deviceTypes,
+		MinVersion:  cloneVersion(r.MinVersion),
+		MaxVersion:  cloneVersion(r.MaxVersion),
+
+		RolloutPercentage: r.RolloutPercentage,
+
+		Countries: countries,
+
+		CreatedAt:     r.CreatedAt,
+		LastUpdatedAt: r.LastUpdatedAt,
+	}
+}
+
+func (r *Record) CopyTo(dst *Record) {
+	cloned := r.Clone()
+
+	dst.Id = cloned.Id
+
+	dst.Name = cloned.Name
+
+	dst.IsEnabled = cloned.IsEnabled
+
+	dst.DeviceTypes = cloned.DeviceTypes
+	dst.MinVersion = cloned.MinVersion
+	dst.MaxVersion = cloned.MaxVersion
+
+	dst.RolloutPercentage = cloned.RolloutPercentage
+
+	dst.Countries = cloned.Countries
+
+	dst.CreatedAt = cloned.CreatedAt
+	dst.LastUpdatedAt = cloned.LastUpdatedAt
+}
+
+func cloneVersion(version *client.Version) *client.Version {
+	if version == nil {
+		return nil
+	}
+
+	cloned := *version
+	return &cloned
+}
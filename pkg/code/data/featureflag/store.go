@@ -0,0 +1,26 @@
+package featureflag
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrFlagNotFound = errors.New("feature flag not found")
+)
+
+type Store interface {
+	// Put creates or updates the feature flag with the record's name. All rules
+	// are replaced for existing flags.
+	Put(ctx context.Context, record *Record) error
+
+	// Get gets a feature flag by name
+	Get(ctx context.Context, name string) (*Record, error)
+
+	// Delete deletes a feature flag by name
+	Delete(ctx context.Context, name string) error
+
+	// GetAll gets all feature flags, including disabled ones, in ascending order
+	// of name
+	GetAll(ctx context.Context) ([]*Record, error)
+}
@@ -0,0 +1,181 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/code-payments/code-server/pkg/grpc/client"
+	"github.com/code-payments/code-server/pkg/code/data/featureflag"
+)
+
+func RunTests(t *testing.T, s featureflag.Store, teardown func()) {
+	for _, tf := range []func(t *testing.T, s featureflag.Store){
+		testRoundTrip,
+		testUpdate,
+		testDelete,
+		testGetAll,
+		testValidation,
+	} {
+		tf(t, s)
+		teardown()
+	}
+}
+
+func testRoundTrip(t *testing.T, s featureflag.Store) {
+	t.Run("testRoundTrip", func(t *testing.T) {
+		ctx := context.Background()
+
+		_, err := s.Get(ctx, "new_feature")
+		assert.Equal(t, featureflag.ErrFlagNotFound, err)
+
+		for _, expected := range []*featureflag.Record{
+			{
+				Name:      "minimal_feature",
+				IsEnabled: true,
+				CreatedAt: time.Now(),
+			},
+			{
+				Name:              "new_feature",
+				IsEnabled:         true,
+				DeviceTypes:       []client.DeviceType{client.DeviceTypeIOS, client.DeviceTypeAndroid},
+				MinVersion:        &client.Version{Major: 1, Minor: 2, Patch: 3},
+				MaxVersion:        &client.Version{Major: 2, Minor: 0, Patch: 0},
+				RolloutPercentage: 25,
+				Countries:         []string{"CA", "US"},
+				CreatedAt:         time.Now(),
+			},
+		} {
+			cloned := expected.Clone()
+			require.NoError(t, s.Put(ctx, expected))
+			assert.True(t, expected.Id > 0)
+			assert.False(t, expected.LastUpdatedAt.IsZero())
+
+			actual, err := s.Get(ctx, expected.Name)
+			require.NoError(t, err)
+			assertEquivalentRecords(t, &cloned, actual)
+			assert.Equal(t, expected.Id, actual.Id)
+		}
+	})
+}
+
+func testUpdate(t *testing.T, s featureflag.Store) {
+	t.Run("testUpdate", func(t *testing.T) {
+		ctx := context.Background()
+
+		record := &featureflag.Record{
+			Name:              "new_feature",
+			IsEnabled:         true,
+			DeviceTypes:       []client.DeviceType{client.DeviceTypeIOS},
+			MinVersion:        &client.Version{Major: 1, Minor: 0, Patch: 0},
+			RolloutPercentage: 10,
+			Countries:         []string{"US"},
+			CreatedAt:         time.Now(),
+		}
+		require.NoError(t, s.Put(ctx, record))
+		id := record.Id
+		createdAt := record.CreatedAt
+
+		updated := &featureflag.Record{
+			Name:              "new_feature",
+			IsEnabled:         false,
+			MaxVersion:        &client.Version{Major: 3, Minor: 0, Patch: 0},
+			RolloutPercentage: 100,
+			CreatedAt:         time.Now().Add(time.Hour),
+		}
+		require.NoError(t, s.Put(ctx, updated))
+		assert.Equal(t, id, updated.Id)
+		assert.Equal(t, createdAt.Unix(), updated.CreatedAt.Unix())
+
+		actual, err := s.Get(ctx, "new_feature")
+		require.NoError(t, err)
+		assert.Equal(t, id, actual.Id)
+		assert.False(t, actual.IsEnabled)
+		assert.Empty(t, actual.DeviceTypes)
+		assert.Nil(t, actual.MinVersion)
+		require.NotNil(t, actual.MaxVersion)
+		assert.Equal(t, "3.0.0", actual.MaxVersion.String())
+		assert.EqualValues(t, 100, actual.RolloutPercentage)
+		assert.Empty(t, actual.Countries)
+		assert.Equal(t, createdAt.Unix(), actual.CreatedAt.Unix())
+	})
+}
+
+func testDelete(t *testing.T, s featureflag.Store) {
+	t.Run("testDelete", func(t *testing.T) {
+		ctx := context.Background()
+
+		assert.Equal(t, featureflag.ErrFlagNotFound, s.Delete(ctx, "new_feature"))
+
+		require.NoError(t, s.Put(ctx, &featureflag.Record{Name: "new_feature", IsEnabled: true}))
+		require.NoError(t, s.Put(ctx, &featureflag.Record{Name: "other_feature", IsEnabled: true}))
+
+		require.NoError(t, s.Delete(ctx, "new_feature"))
+		assert.Equal(t, featureflag.ErrFlagNotFound, s.Delete(ctx, "new_feature"))
+
+		_, err := s.Get(ctx, "new_feature")
+		assert.Equal(t, featureflag.ErrFlagNotFound, err)
+
+		_, err = s.Get(ctx, "other_feature")
+		assert.NoError(t, err)
+	})
+}
+
+func testGetAll(t *testing.T, s featureflag.Store) {
+	t.Run("testGetAll", func(t *testing.T) {
+		ctx := context.Background()
+
+		_, err := s.GetAll(ctx)
+		assert.Equal(t, featureflag.ErrFlagNotFound, err)
+
+		for _, name := range []string{"feature_c", "feature_a", "feature_b"} {
+			require.NoError(t, s.Put(ctx, &featureflag.Record{Name: name, IsEnabled: name != "feature_b"}))
+		}
+
+		actual, err := s.GetAll(ctx)
+		require.NoError(t, err)
+		require.Len(t, actual, 3)
+		assert.Equal(t, "feature_a", actual[0].Name)
+		assert.Equal(t, "feature_b", actual[1].Name)
+		assert.Equal(t, "feature_c", actual[2].Name)
+		assert.False(t, actual[1].IsEnabled)
+	})
+}
+
+func testValidation(t *testing.T, s featureflag.Store) {
+	t.Run("testValidation", func(t *testing.T) {
+		ctx := context.Background()
+
+		for _, invalid := range []*featureflag.Record{
+			{},
+			{Name: "Invalid Name"},
+			{Name: "new_feature", DeviceTypes: []client.DeviceType{client.DeviceTypeUnknown}},
+			{Name: "new_feature", DeviceTypes: []client.DeviceType{client.DeviceTypeIOS, client.DeviceTypeIOS}},
+			{Name: "new_feature", MinVersion: &client.Version{Major: 2}, MaxVersion: &client.Version{Major: 1}},
+			{Name: "new_feature", MinVersion: &client.Version{Major: 2}, MaxVersion: &client.Version{Major: 2}},
+			{Name: "new_feature", RolloutPercentage: 101},
+			{Name: "new_feature", Countries: []string{"us"}},
+			{Name: "new_feature", Countries: []string{"USA"}},
+			{Name: "new_feature", Countries: []string{"US", "US"}},
+		} {
+			assert.Error(t, s.Put(ctx, invalid))
+		}
+
+		_, err := s.GetAll(ctx)
+		assert.Equal(t, featureflag.ErrFlagNotFound, err)
+	})
+}
+
+func assertEquivalentRecords(t *testing.T, obj1, obj2 *featureflag.Record) {
+	assert.Equal(t, obj1.Name, obj2.Name)
+	assert.Equal(t, obj1.IsEnabled, obj2.IsEnabled)
+	assert.Equal(t, obj1.DeviceTypes, obj2.DeviceTypes)
+	assert.Equal(t, obj1.MinVersion, obj2.MinVersion)
+	assert.Equal(t, obj1.MaxVersion, obj2.MaxVersion)
+	assert.Equal(t, obj1.RolloutPercentage, obj2.RolloutPercentage)
+	assert.Equal(t, obj1.Countries, obj2.Countries)
+	assert.Equal(t, obj1.CreatedAt.Unix(), obj2.CreatedAt.Unix())
+}
@@ -0,0 +1,43 @@
+package freeaccount
+
+import (
+	"errors"
+	"time"
+)
+
+// Record tracks a device that has been used to create a free account. It's
+// used for platforms that don't provide a device-level storage mechanism, like
+// Apple's DeviceCheck bits.
+type Record struct {
+	Id uint64
+
+	DeviceId string
+
+	CreatedAt time.Time
+}
+
+func (r *Record) Validate() error {
+	if len(r.DeviceId) == 0 {
+		return errors.New("device id is required")
+	}
+
+	return nil
+}
+
+func (r *Record) Clone() Record {
+	return Record{
+		Id: r.Id,
+
+		DeviceId: r.DeviceId,
+
+		CreatedAt: r.CreatedAt,
+	}
+}
+
+func (r *Record) CopyTo(dst *Record) {
+	dst.Id = r.Id
+
+	dst.DeviceId = r.DeviceId
+
+	dst.CreatedAt = r.CreatedAt
+}
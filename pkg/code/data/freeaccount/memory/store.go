@@ -0,0 +1,77 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/code-payments/code-server/pkg/code/data/freeaccount"
+)
+
+type store struct {
+	mu      sync.Mutex
+	records []*freeaccount.Record
+	last    uint64
+}
+
+// New returns a new in memory freeaccount.Store
+func New() freeaccount.Store {
+	return &store{}
+}
+
+// Put implements freeaccount.Store.Put
+func (s *store) Put(_ context.Context, record *freeaccount.Record) error {
+	if err := record.Validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if item := s.findByDeviceId(record.DeviceId); item != nil {
+		item.CopyTo(record)
+		return nil
+	}
+
+	s.last++
+	record.Id = s.last
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = time.Now()
+	}
+
+	cloned := record.Clone()
+	s.records = append(s.records, &cloned)
+
+	return nil
+}
+
+// Get implements freeaccount.Store.Get
+func (s *store) Get(_ context.Context, deviceId string) (*freeaccount.Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item := s.findByDeviceId(deviceId)
+	if item == nil {
+		return nil, freeaccount.ErrFreeAccountNotFound
+	}
+
+	cloned := item.Clone()
+	return &cloned, nil
+}
+
+func (s *store) findByDeviceId(deviceId string) *freeaccount.Record {
+	for _, item := range s.records {
+		if item.DeviceId == deviceId {
+			return item
+		}
+	}
+	return nil
+}
+
+func (s *store) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.records = nil
+	s.last = 0
+}
@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	pgutil "github.com/code-payments/code-server/pkg/database/postgres"
+	"github.com/code-payments/code-server/pkg/code/data/freeaccount"
+)
+
+const (
+	tableName = "codewallet__core_freeaccountdevice"
+)
+
+type model struct {
+	Id sql.NullInt64 `db:"id"`
+
+	DeviceId string `db:"device_id"`
+
+	CreatedAt time.Time `db:"created_at"`
+}
+
+func toModel(obj *freeaccount.Record) (*model, error) {
+	if err := obj.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &model{
+		DeviceId:  obj.DeviceId,
+		CreatedAt: obj.CreatedAt,
+	}, nil
+}
+
+func fromModel(obj *model) *freeaccount.Record {
+	return &freeaccount.Record{
+		Id: uint64(obj.Id.Int64),
+
+		DeviceId: obj.DeviceId,
+
+		CreatedAt: obj.CreatedAt,
+	}
+}
+
+func (m *model) dbPut(ctx context.Context, db *sqlx.DB) error {
+	return pgutil.ExecuteInTx(ctx, db, sql.LevelDefault, func(tx *sqlx.Tx) error {
+		if m.CreatedAt.IsZero() {
+			m.CreatedAt = time.Now()
+		}
+
+		// The no-op update allows the existing row to be returned on conflict
+		query := `INSERT INTO ` + tableName + `
+			(device_id, created_at)
+			VALUES ($1, $2)
+
+			ON CONFLICT (device_id)
+			DO UPDATE
+				SET device_id = ` + tableName + `.device_id
+				WHERE ` + tableName + `.device_id = $1
+
+			RETURNING
+				id, device_id, created_at`
+
+		return tx.QueryRowxContext(
+			ctx,
+			query,
+			m.DeviceId,
+			m.CreatedAt,
+		).StructScan(m)
+	})
+}
+
+func dbGet(ctx context.Context, db *sqlx.DB, deviceId string) (*model, error) {
+	res := &model{}
+
+	query := `SELECT id, device_id, created_at FROM ` + tableName + `
+		WHERE device_id = $1
+	`
+
+	err := db.QueryRowxContext(
+		ctx,
+		query,
+		deviceId,
+	).StructScan(res)
+	if err != nil {
+		return nil, pgutil.CheckNoRows(err, freeaccount.ErrFreeAccountNotFound)
+	}
+	return res, nil
+}
@@ -0,0 +1,47 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/code-payments/code-server/pkg/code/data/freeaccount"
+)
+
+type store struct {
+	db *sqlx.DB
+}
+
+// New returns a new postgres freeaccount.Store
+func New(db *sql.DB) freeaccount.Store {
+	return &store{
+		db: sqlx.NewDb(db, "pgx"),
+	}
+}
+
+// Put implements freeaccount.Store.Put
+func (s *store) Put(ctx context.Context, record *freeaccount.Record) error {
+	model, err := toModel(record)
+	if err != nil {
+		return err
+	}
+
+	if err := model.dbPut(ctx, s.db); err != nil {
+		return err
+	}
+
+	res := fromModel(model)
+	res.CopyTo(record)
+
+	return nil
+}
+
+// Get implements freeaccount.Store.Get
+func (s *store) Get(ctx context.Context, deviceId string) (*freeaccount.Record, error) {
+	model, err := dbGet(ctx, s.db, deviceId)
+	if err != nil {
+		return nil, err
+	}
+	return fromModel(model), nil
+}
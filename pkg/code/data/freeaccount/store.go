@@ -0,0 +1,19 @@
+package freeaccount
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrFreeAccountNotFound = errors.New("free account record not found")
+)
+
+type Store interface {
+	// Put saves a record for a device that created a free account. It's a no-op
+	// if the device already has a record.
+	Put(ctx context.Context, record *Record) error
+
+	// Get gets the free account record for a device
+	Get(ctx context.Context, deviceId string) (*Record, error)
+}
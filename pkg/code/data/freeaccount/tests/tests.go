@@ -0,0 +1,73 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/code-payments/code-server/pkg/code/data/freeaccount"
+)
+
+func RunTests(t *testing.T, s freeaccount.Store, teardown func()) {
+	for _, tf := range []func(t *testing.T, s freeaccount.Store){
+		testHappyPath,
+	} {
+		tf(t, s)
+		teardown()
+	}
+}
+
+func testHappyPath(t *testing.T, s freeaccount.Store) {
+	t.Run("testHappyPath", func(t *testing.T) {
+		ctx := context.Background()
+
+		start := time.Now()
+		time.Sleep(time.Millisecond)
+
+		_, err := s.Get(ctx, "device1")
+		assert.Equal(t, freeaccount.ErrFreeAccountNotFound, err)
+
+		assert.Error(t, s.Put(ctx, &freeaccount.Record{}))
+
+		expected := &freeaccount.Record{
+			DeviceId: "device1",
+		}
+		require.NoError(t, s.Put(ctx, expected))
+		assert.True(t, expected.Id > 0)
+		assert.True(t, expected.CreatedAt.After(start))
+
+		actual, err := s.Get(ctx, "device1")
+		require.NoError(t, err)
+		assertEquivalentRecords(t, expected, actual)
+
+		duplicate := &freeaccount.Record{
+			DeviceId:  "device1",
+			CreatedAt: time.Now().Add(time.Hour),
+		}
+		require.NoError(t, s.Put(ctx, duplicate))
+		assertEquivalentRecords(t, expected, duplicate)
+
+		actual, err = s.Get(ctx, "device1")
+		require.NoError(t, err)
+		assertEquivalentRecords(t, expected, actual)
+
+		_, err = s.Get(ctx, "device2")
+		assert.Equal(t, freeaccount.ErrFreeAccountNotFound, err)
+
+		require.NoError(t, s.Put(ctx, &freeaccount.Record{DeviceId: "device2"}))
+
+		actual, err = s.Get(ctx, "device2")
+		require.NoError(t, err)
+		assert.Equal(t, "device2", actual.DeviceId)
+		assert.NotEqual(t, expected.Id, actual.Id)
+	})
+}
+
+func assertEquivalentRecords(t *testing.T, obj1, obj2 *freeaccount.Record) {
+	assert.Equal(t, obj1.Id, obj2.Id)
+	assert.Equal(t, obj1.DeviceId, obj2.DeviceId)
+	assert.Equal(t, obj1.CreatedAt.Unix(), obj2.CreatedAt.Unix())
+}
@@ -35,6 +35,7 @@ const (
 	VerifyCommitmentProof // Deprecated, since we bundle verification with OpenCommitmentVault
 	OpenCommitmentVault
 	CloseCommitmentVault
+	TransferToTreasuryPool
 )
 
 type State uint8
@@ -290,6 +291,8 @@ func (s Type) String() string {
 		return "open_commitment_vault"
 	case CloseCommitmentVault:
 		return "close_commitment_vault"
+	case TransferToTreasuryPool:
+		return "transfer_to_treasury_pool"
 	}
 
 	return "unknown"
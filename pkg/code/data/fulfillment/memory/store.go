@@ -523,6 +523,20 @@ func (s *store) MarkAsActivelyScheduled(ctx context.Context, id uint64) error {
 	return nil
 }
 
+func (s *store) MarkAsNotActivelyScheduled(ctx context.Context, id uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item := s.findById(id)
+	if item == nil {
+		return fulfillment.ErrFulfillmentNotFound
+	}
+
+	item.DisableActiveScheduling = true
+
+	return nil
+}
+
 func (s *store) ActivelyScheduleTreasuryAdvances(ctx context.Context, treasury string, intentOrderingIndex uint64, limit int) (uint64, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -466,6 +466,28 @@ func dbMarkAsActivelyScheduled(ctx context.Context, db *sqlx.DB, id uint64) erro
 	return nil
 }
 
+func dbMarkAsNotActivelyScheduled(ctx context.Context, db *sqlx.DB, id uint64) error {
+	if id == 0 {
+		return fulfillment.ErrFulfillmentNotFound
+	}
+
+	query := `UPDATE ` + fulfillmentTableName + ` SET disable_active_scheduling = true WHERE id = $1`
+	res, err := db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return fulfillment.ErrFulfillmentNotFound
+	}
+	return nil
+}
+
 func dbActivelyScheduleTreasuryAdvances(ctx context.Context, db *sqlx.DB, treasury string, intentOrderingIndex uint64, limit int) (uint64, error) {
 	query := `UPDATE ` + fulfillmentTableName + `
 		SET disable_active_scheduling = false
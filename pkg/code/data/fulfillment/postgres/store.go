@@ -131,6 +131,11 @@ func (s *store) MarkAsActivelyScheduled(ctx context.Context, id uint64) error {
 	return dbMarkAsActivelyScheduled(ctx, s.db, id)
 }
 
+// MarkAsNotActivelyScheduled implements fulfillment.Store.MarkAsNotActivelyScheduled
+func (s *store) MarkAsNotActivelyScheduled(ctx context.Context, id uint64) error {
+	return dbMarkAsNotActivelyScheduled(ctx, s.db, id)
+}
+
 // ActivelyScheduleTreasuryAdvances implements fulfillment.Store.ActivelyScheduleTreasuryAdvances
 func (s *store) ActivelyScheduleTreasuryAdvances(ctx context.Context, treasury string, intentOrderingIndex uint64, limit int) (uint64, error) {
 	return dbActivelyScheduleTreasuryAdvances(ctx, s.db, treasury, intentOrderingIndex, limit)
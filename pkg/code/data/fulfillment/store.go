@@ -50,7 +50,7 @@ type Store interface {
 	// Update updates an existing fulfillment record
 	//
 	// Note 1: Updating pre-sorting metadata is allowed but limited to certain fulfillment types
-	// Note 2: Updating DisableActiveScheduling is done in MarkAsActivelyScheduled, due to no distributed locks existing
+	// Note 2: Updating DisableActiveScheduling is done in MarkAsActivelyScheduled and MarkAsNotActivelyScheduled, due to no distributed locks existing
 	Update(ctx context.Context, record *Record) error
 
 	// GetById find the fulfillment recofd for a given ID
@@ -62,6 +62,9 @@ type Store interface {
 	// MarkAsActivelyScheduled marks a fulfillment as actively scheduled
 	MarkAsActivelyScheduled(ctx context.Context, id uint64) error
 
+	// MarkAsNotActivelyScheduled marks a fulfillment as not actively scheduled
+	MarkAsNotActivelyScheduled(ctx context.Context, id uint64) error
+
 	// ActivelyScheduleTreasuryAdvances is a specialized MarkAsActivelyScheduled variant
 	// to batch enable active scheduling for treasury advances at a particular point in time
 	// defined by the intent ordering index.
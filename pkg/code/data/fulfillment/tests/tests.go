@@ -214,6 +214,7 @@ func testUpdate(t *testing.T, s fulfillment.Store) {
 		ctx := context.Background()
 
 		assert.Equal(t, fulfillment.ErrFulfillmentNotFound, s.MarkAsActivelyScheduled(ctx, 1))
+		assert.Equal(t, fulfillment.ErrFulfillmentNotFound, s.MarkAsNotActivelyScheduled(ctx, 1))
 
 		expected := fulfillment.Record{
 			Intent:                   "test_intent",
@@ -246,6 +247,13 @@ func testUpdate(t *testing.T, s fulfillment.Store) {
 		actual, err := s.GetById(ctx, 1)
 		require.NoError(t, err)
 		assert.False(t, actual.DisableActiveScheduling)
+
+		require.NoError(t, s.MarkAsNotActivelyScheduled(ctx, 1))
+		actual, err = s.GetById(ctx, 1)
+		require.NoError(t, err)
+		assert.True(t, actual.DisableActiveScheduling)
+
+		require.NoError(t, s.MarkAsActivelyScheduled(ctx, 1))
 		expected.DisableActiveScheduling = false
 
 		expected.State = fulfillment.StatePending
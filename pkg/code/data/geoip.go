@@ -0,0 +1,51 @@
+package data
+
+import (
+	"context"
+
+	"github.com/code-payments/code-server/pkg/geoip"
+	"github.com/code-payments/code-server/pkg/geoip/maxmind"
+	memory_geoip "github.com/code-payments/code-server/pkg/geoip/memory"
+)
+
+type GeoIPData interface {
+	// GeoIP
+	// --------------------------------------------------------------------------------
+	GetIpMetadata(ctx context.Context, ip string) (*geoip.Metadata, error)
+}
+
+type GeoIPProvider struct {
+	geoIP geoip.GeoIP
+}
+
+func NewGeoIPProvider(configProvider ConfigProvider) (GeoIPData, error) {
+	ctx := context.Background()
+	conf := configProvider()
+
+	cityPath := conf.maxMindCityDbPath.Get(ctx)
+	asnPath := conf.maxMindAsnDbPath.Get(ctx)
+	anonymousIpPath := conf.maxMindAnonymousIpDbPath.Get(ctx)
+
+	// Without any databases, nothing is known about any IP
+	if len(cityPath) == 0 && len(asnPath) == 0 && len(anonymousIpPath) == 0 {
+		return NewGeoIPProviderWithClient(memory_geoip.NewGeoIP())
+	}
+
+	geoIP, err := maxmind.Open(cityPath, asnPath, anonymousIpPath)
+	if err != nil {
+		return nil, err
+	}
+	return NewGeoIPProviderWithClient(geoIP)
+}
+
+func NewGeoIPProviderWithClient(geoIP geoip.GeoIP) (GeoIPData, error) {
+	return &GeoIPProvider{
+		geoIP: geoIP,
+	}, nil
+}
+
+// GeoIP
+// --------------------------------------------------------------------------------
+func (dp *GeoIPProvider) GetIpMetadata(ctx context.Context, ip string) (*geoip.Metadata, error) {
+	return dp.geoIP.Lookup(ctx, ip)
+}
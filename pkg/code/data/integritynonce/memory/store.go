@@ -0,0 +1,62 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/code-payments/code-server/pkg/code/data/integritynonce"
+)
+
+type store struct {
+	mu      sync.Mutex
+	records []*integritynonce.Record
+	last    uint64
+}
+
+// New returns a new in memory integritynonce.Store
+func New() integritynonce.Store {
+	return &store{}
+}
+
+// Put implements integritynonce.Store.Put
+func (s *store) Put(_ context.Context, record *integritynonce.Record) error {
+	if err := record.Validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if item := s.findByNonce(record.Nonce); item != nil {
+		return integritynonce.ErrNonceAlreadyUsed
+	}
+
+	s.last++
+	record.Id = s.last
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = time.Now()
+	}
+
+	cloned := record.Clone()
+	s.records = append(s.records, &cloned)
+
+	return nil
+}
+
+func (s *store) findByNonce(nonce string) *integritynonce.Record {
+	for _, item := range s.records {
+		if item.Nonce == nonce {
+			return item
+		}
+	}
+	return nil
+}
+
+func (s *store) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.records = nil
+	s.last = 0
+}
@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	pgutil "github.com/code-payments/code-server/pkg/database/postgres"
+	"github.com/code-payments/code-server/pkg/code/data/integritynonce"
+)
+
+const (
+	tableName = "codewallet__core_integritynonce"
+)
+
+type model struct {
+	Id sql.NullInt64 `db:"id"`
+
+	Nonce    string `db:"nonce"`
+	DeviceId string `db:"device_id"`
+
+	CreatedAt time.Time `db:"created_at"`
+}
+
+func toModel(obj *integritynonce.Record) (*model, error) {
+	if err := obj.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &model{
+		Nonce:     obj.Nonce,
+		DeviceId:  obj.DeviceId,
+		CreatedAt: obj.CreatedAt,
+	}, nil
+}
+
+func fromModel(obj *model) *integritynonce.Record {
+	return &integritynonce.Record{
+		Id: uint64(obj.Id.Int64),
+
+		Nonce:    obj.Nonce,
+		DeviceId: obj.DeviceId,
+
+		CreatedAt: obj.CreatedAt,
+	}
+}
+
+func (m *model) dbPut(ctx context.Context, db *sqlx.DB) error {
+	if m.CreatedAt.IsZero() {
+		m.CreatedAt = time.Now()
+	}
+
+	query := `INSERT INTO ` + tableName + `
+		(nonce, device_id, created_at)
+		VALUES ($1, $2, $3)
+
+		ON CONFLICT (nonce)
+		DO NOTHING
+
+		RETURNING
+			id, nonce, device_id, created_at`
+
+	err := db.QueryRowxContext(
+		ctx,
+		query,
+		m.Nonce,
+		m.DeviceId,
+		m.CreatedAt,
+	).StructScan(m)
+	return pgutil.CheckNoRows(err, integritynonce.ErrNonceAlreadyUsed)
+}
@@ -0,0 +1,38 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/code-payments/code-server/pkg/code/data/integritynonce"
+)
+
+type store struct {
+	db *sqlx.DB
+}
+
+// New returns a new postgres integritynonce.Store
+func New(db *sql.DB) integritynonce.Store {
+	return &store{
+		db: sqlx.NewDb(db, "pgx"),
+	}
+}
+
+// Put implements integritynonce.Store.Put
+func (s *store) Put(ctx context.Context, record *integritynonce.Record) error {
+	model, err := toModel(record)
+	if err != nil {
+		return err
+	}
+
+	if err := model.dbPut(ctx, s.db); err != nil {
+		return err
+	}
+
+	res := fromModel(model)
+	res.CopyTo(record)
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package integritynonce
+
+import (
+	"errors"
+	"time"
+)
+
+// Record tracks a server-issued device integrity nonce that has been used. Nonces
+// are single use, so a captured integrity token can't be replayed.
+type Record struct {
+	Id uint64
+
+	Nonce    string
+	DeviceId string
+
+	CreatedAt time.Time
+}
+
+func (r *Record) Validate() error {
+	if len(r.Nonce) == 0 {
+		return errors.New("nonce is required")
+	}
+
+	if len(r.DeviceId) == 0 {
+		return errors.New("device id is required")
+	}
+
+	return nil
+}
+
+func (r *Record) Clone() Record {
+	return Record{
+		Id: r.Id,
+
+		Nonce:    r.Nonce,
+		DeviceId: r.DeviceId,
+
+		CreatedAt: r.CreatedAt,
+	}
+}
+
+func (r *Record) CopyTo(dst *Record) {
+	dst.Id = r.Id
+
+	dst.Nonce = r.Nonce
+	dst.DeviceId = r.DeviceId
+
+	dst.CreatedAt = r.CreatedAt
+}
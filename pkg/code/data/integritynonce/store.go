@@ -0,0 +1,16 @@
+package integritynonce
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrNonceAlreadyUsed = errors.New("integrity nonce already used")
+)
+
+type Store interface {
+	// Put saves a record for a nonce that's been used. ErrNonceAlreadyUsed is
+	// returned if the nonce already has a record.
+	Put(ctx context.Context, record *Record) error
+}
@@ -0,0 +1,57 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/code-payments/code-server/pkg/code/data/integritynonce"
+)
+
+func RunTests(t *testing.T, s integritynonce.Store, teardown func()) {
+	for _, tf := range []func(t *testing.T, s integritynonce.Store){
+		testHappyPath,
+	} {
+		tf(t, s)
+		teardown()
+	}
+}
+
+func testHappyPath(t *testing.T, s integritynonce.Store) {
+	t.Run("testHappyPath", func(t *testing.T) {
+		ctx := context.Background()
+
+		start := time.Now()
+		time.Sleep(time.Millisecond)
+
+		assert.Error(t, s.Put(ctx, &integritynonce.Record{}))
+		assert.Error(t, s.Put(ctx, &integritynonce.Record{Nonce: "nonce1"}))
+		assert.Error(t, s.Put(ctx, &integritynonce.Record{DeviceId: "device1"}))
+
+		record := &integritynonce.Record{
+			Nonce:    "nonce1",
+			DeviceId: "device1",
+		}
+		require.NoError(t, s.Put(ctx, record))
+		assert.True(t, record.Id > 0)
+		assert.True(t, record.CreatedAt.After(start))
+
+		for _, deviceId := range []string{"device1", "device2"} {
+			err := s.Put(ctx, &integritynonce.Record{
+				Nonce:    "nonce1",
+				DeviceId: deviceId,
+			})
+			assert.Equal(t, integritynonce.ErrNonceAlreadyUsed, err)
+		}
+
+		other := &integritynonce.Record{
+			Nonce:    "nonce2",
+			DeviceId: "device1",
+		}
+		require.NoError(t, s.Put(ctx, other))
+		assert.NotEqual(t, record.Id, other.Id)
+	})
+}
@@ -39,6 +39,7 @@ const (
 	SendPublicPayment
 	ReceivePaymentsPublicly
 	EstablishRelationship
+	TreasuryPoolFunding
 )
 
 type Record struct {
@@ -50,6 +51,10 @@ type Record struct {
 	InitiatorOwnerAccount string
 	InitiatorPhoneNumber  *string
 
+	// Mint of the tokens moved by the intent. Empty for intents created before
+	// multi-mint support, which are always Kin.
+	Mint string
+
 	// Intents v2 metadatum
 	OpenAccountsMetadata             *OpenAccountsMetadata
 	SendPrivatePaymentMetadata       *SendPrivatePaymentMetadata
@@ -60,6 +65,7 @@ type Record struct {
 	SendPublicPaymentMetadata        *SendPublicPaymentMetadata
 	ReceivePaymentsPubliclyMetadata  *ReceivePaymentsPubliclyMetadata
 	EstablishRelationshipMetadata    *EstablishRelationshipMetadata
+	TreasuryPoolFundingMetadata      *TreasuryPoolFundingMetadata
 
 	// Deprecated intents v1 metadatum
 	MoneyTransferMetadata     *MoneyTransferMetadata
@@ -167,6 +173,12 @@ type EstablishRelationshipMetadata struct {
 	RelationshipTo string
 }
 
+type TreasuryPoolFundingMetadata struct {
+	TreasuryPool string
+	Source       string
+	Quantity     uint64
+}
+
 func (r *Record) IsCompleted() bool {
 	return r.State == StateConfirmed
 }
@@ -238,6 +250,12 @@ func (r *Record) Clone() Record {
 		establishRelationshipMetadata = &cloned
 	}
 
+	var treasuryPoolFundingMetadata *TreasuryPoolFundingMetadata
+	if r.TreasuryPoolFundingMetadata != nil {
+		cloned := r.TreasuryPoolFundingMetadata.Clone()
+		treasuryPoolFundingMetadata = &cloned
+	}
+
 	var initiatorPhoneNumber *string
 	if r.InitiatorPhoneNumber != nil {
 		value := *r.InitiatorPhoneNumber
@@ -253,6 +271,8 @@ func (r *Record) Clone() Record {
 		InitiatorOwnerAccount: r.InitiatorOwnerAccount,
 		InitiatorPhoneNumber:  initiatorPhoneNumber,
 
+		Mint: r.Mint,
+
 		OpenAccountsMetadata:             openAccountsMetadata,
 		SendPrivatePaymentMetadata:       sendPrivatePaymentMetadata,
 		ReceivePaymentsPrivatelyMetadata: receivePaymentsPrivatelyMetadata,
@@ -262,6 +282,7 @@ func (r *Record) Clone() Record {
 		SendPublicPaymentMetadata:        sendPublicPaymentMetadata,
 		ReceivePaymentsPubliclyMetadata:  receivePaymentsPubliclyMetadata,
 		EstablishRelationshipMetadata:    establishRelationshipMetadata,
+		TreasuryPoolFundingMetadata:      treasuryPoolFundingMetadata,
 
 		MoneyTransferMetadata:     moneyTransferMetadata,
 		AccountManagementMetadata: accountManagementMetadata,
@@ -281,6 +302,8 @@ func (r *Record) CopyTo(dst *Record) {
 	dst.InitiatorOwnerAccount = r.InitiatorOwnerAccount
 	dst.InitiatorPhoneNumber = r.InitiatorPhoneNumber
 
+	dst.Mint = r.Mint
+
 	dst.OpenAccountsMetadata = r.OpenAccountsMetadata
 	dst.SendPrivatePaymentMetadata = r.SendPrivatePaymentMetadata
 	dst.ReceivePaymentsPrivatelyMetadata = r.ReceivePaymentsPrivatelyMetadata
@@ -288,6 +311,7 @@ func (r *Record) CopyTo(dst *Record) {
 	dst.MigrateToPrivacy2022Metadata = r.MigrateToPrivacy2022Metadata
 	dst.SendPublicPaymentMetadata = r.SendPublicPaymentMetadata
 	dst.ReceivePaymentsPubliclyMetadata = r.ReceivePaymentsPubliclyMetadata
+	dst.TreasuryPoolFundingMetadata = r.TreasuryPoolFundingMetadata
 
 	dst.MoneyTransferMetadata = r.MoneyTransferMetadata
 	dst.AccountManagementMetadata = r.AccountManagementMetadata
@@ -435,6 +459,17 @@ func (r *Record) Validate() error {
 		}
 	}
 
+	if r.IntentType == TreasuryPoolFunding {
+		if r.TreasuryPoolFundingMetadata == nil {
+			return errors.New("treasury pool funding metadata must be present")
+		}
+
+		err := r.TreasuryPoolFundingMetadata.Validate()
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -633,6 +668,36 @@ func (m *SaveRecentRootMetadata) Validate() error {
 	return nil
 }
 
+func (m *TreasuryPoolFundingMetadata) Clone() TreasuryPoolFundingMetadata {
+	return TreasuryPoolFundingMetadata{
+		TreasuryPool: m.TreasuryPool,
+		Source:       m.Source,
+		Quantity:     m.Quantity,
+	}
+}
+
+func (m *TreasuryPoolFundingMetadata) CopyTo(dst *TreasuryPoolFundingMetadata) {
+	dst.TreasuryPool = m.TreasuryPool
+	dst.Source = m.Source
+	dst.Quantity = m.Quantity
+}
+
+func (m *TreasuryPoolFundingMetadata) Validate() error {
+	if len(m.TreasuryPool) == 0 {
+		return errors.New("treasury pool is required")
+	}
+
+	if len(m.Source) == 0 {
+		return errors.New("source is required")
+	}
+
+	if m.Quantity == 0 {
+		return errors.New("quantity is required")
+	}
+
+	return nil
+}
+
 func (m *MigrateToPrivacy2022Metadata) Clone() MigrateToPrivacy2022Metadata {
 	return MigrateToPrivacy2022Metadata{
 		Quantity: m.Quantity,
@@ -862,6 +927,8 @@ func (t Type) String() string {
 		return "receive_payments_publicly"
 	case EstablishRelationship:
 		return "establish_relationship"
+	case TreasuryPoolFunding:
+		return "treasury_pool_funding"
 	}
 
 	return "unknown"
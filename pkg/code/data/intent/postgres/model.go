@@ -46,6 +46,7 @@ type intentModel struct {
 	IsMicroPayment          bool           `db:"is_micro_payment"`
 	RelationshipTo          sql.NullString `db:"relationship_to"`
 	InitiatorPhoneNumber    sql.NullString `db:"phone_number"` // todo: rename the DB field to initiator_phone_number
+	Mint                    string         `db:"mint"`
 	State                   uint           `db:"state"`
 	CreatedAt               time.Time      `db:"created_at"`
 }
@@ -64,6 +65,7 @@ func toIntentModel(obj *intent.Record) (*intentModel, error) {
 		IntentId:       obj.IntentId,
 		IntentType:     uint(obj.IntentType),
 		InitiatorOwner: obj.InitiatorOwnerAccount,
+		Mint:           obj.Mint,
 		State:          uint(obj.State),
 		CreatedAt:      obj.CreatedAt,
 	}
@@ -113,6 +115,11 @@ func toIntentModel(obj *intent.Record) (*intentModel, error) {
 		m.RecentRoot.String = obj.SaveRecentRootMetadata.PreviousMostRecentRoot
 	case intent.MigrateToPrivacy2022:
 		m.Quantity = obj.MigrateToPrivacy2022Metadata.Quantity
+	case intent.TreasuryPoolFunding:
+		m.TreasuryPool.Valid = true
+		m.TreasuryPool.String = obj.TreasuryPoolFundingMetadata.TreasuryPool
+		m.Source = obj.TreasuryPoolFundingMetadata.Source
+		m.Quantity = obj.TreasuryPoolFundingMetadata.Quantity
 	case intent.ExternalDeposit:
 		m.DestinationOwnerAccount = obj.ExternalDepositMetadata.DestinationOwnerAccount
 		m.DestinationTokenAccount = obj.ExternalDepositMetadata.DestinationTokenAccount
@@ -159,6 +166,7 @@ func fromIntentModel(obj *intentModel) *intent.Record {
 		IntentId:              obj.IntentId,
 		IntentType:            intent.Type(obj.IntentType),
 		InitiatorOwnerAccount: obj.InitiatorOwner,
+		Mint:                  obj.Mint,
 		State:                 intent.State(obj.State),
 		CreatedAt:             obj.CreatedAt.UTC(),
 	}
@@ -218,6 +226,12 @@ func fromIntentModel(obj *intentModel) *intent.Record {
 		record.MigrateToPrivacy2022Metadata = &intent.MigrateToPrivacy2022Metadata{
 			Quantity: obj.Quantity,
 		}
+	case intent.TreasuryPoolFunding:
+		record.TreasuryPoolFundingMetadata = &intent.TreasuryPoolFundingMetadata{
+			TreasuryPool: obj.TreasuryPool.String,
+			Source:       obj.Source,
+			Quantity:     obj.Quantity,
+		}
 	case intent.ExternalDeposit:
 		record.ExternalDepositMetadata = &intent.ExternalDepositMetadata{
 			DestinationOwnerAccount: obj.DestinationOwnerAccount,
@@ -264,8 +278,8 @@ func fromIntentModel(obj *intentModel) *intent.Record {
 func (m *intentModel) dbSave(ctx context.Context, db *sqlx.DB) error {
 	return pgutil.ExecuteInTx(ctx, db, sql.LevelDefault, func(tx *sqlx.Tx) error {
 		query := `INSERT INTO ` + intentTableName + `
-			(intent_id, intent_type, owner, source, destination_owner, destination, quantity, treasury_pool, recent_root, exchange_currency, exchange_rate, native_amount, usd_market_value, is_withdraw, is_deposit, is_remote_send, is_returned, is_issuer_voiding_gift_card, is_micro_payment, relationship_to, phone_number, state, created_at)
-			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23)
+			(intent_id, intent_type, owner, source, destination_owner, destination, quantity, treasury_pool, recent_root, exchange_currency, exchange_rate, native_amount, usd_market_value, is_withdraw, is_deposit, is_remote_send, is_returned, is_issuer_voiding_gift_card, is_micro_payment, relationship_to, phone_number, state, created_at, mint)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24)
 
 			ON CONFLICT (intent_id)
 			DO UPDATE
@@ -273,7 +287,7 @@ func (m *intentModel) dbSave(ctx context.Context, db *sqlx.DB) error {
 				WHERE ` + intentTableName + `.intent_id = $1 
 
 			RETURNING
-				id, intent_id, intent_type, source, destination_owner, destination, quantity, treasury_pool, recent_root, exchange_currency, exchange_rate, native_amount, usd_market_value, is_withdraw, is_deposit, is_remote_send, is_returned, is_issuer_voiding_gift_card, is_micro_payment, relationship_to, phone_number, state, created_at`
+				id, intent_id, intent_type, source, destination_owner, destination, quantity, treasury_pool, recent_root, exchange_currency, exchange_rate, native_amount, usd_market_value, is_withdraw, is_deposit, is_remote_send, is_returned, is_issuer_voiding_gift_card, is_micro_payment, relationship_to, phone_number, state, created_at, mint`
 
 		err := tx.QueryRowxContext(
 			ctx,
@@ -301,6 +315,7 @@ func (m *intentModel) dbSave(ctx context.Context, db *sqlx.DB) error {
 			m.InitiatorPhoneNumber,
 			m.State,
 			m.CreatedAt,
+			m.Mint,
 		).StructScan(m)
 
 		return pgutil.CheckNoRows(err, intent.ErrInvalidIntent)
@@ -310,7 +325,7 @@ func (m *intentModel) dbSave(ctx context.Context, db *sqlx.DB) error {
 func dbGetIntent(ctx context.Context, db *sqlx.DB, intentID string) (*intentModel, error) {
 	res := &intentModel{}
 
-	query := `SELECT id, intent_id, intent_type, owner, source, destination_owner, destination, quantity, treasury_pool, recent_root, exchange_currency, exchange_rate, native_amount, usd_market_value, is_withdraw, is_deposit, is_remote_send, is_returned, is_issuer_voiding_gift_card, is_micro_payment, relationship_to, phone_number, state, created_at
+	query := `SELECT id, intent_id, intent_type, owner, source, destination_owner, destination, quantity, treasury_pool, recent_root, exchange_currency, exchange_rate, native_amount, usd_market_value, is_withdraw, is_deposit, is_remote_send, is_returned, is_issuer_voiding_gift_card, is_micro_payment, relationship_to, phone_number, state, created_at, mint
 		FROM ` + intentTableName + `
 		WHERE intent_id = $1
 		LIMIT 1`
@@ -325,7 +340,7 @@ func dbGetIntent(ctx context.Context, db *sqlx.DB, intentID string) (*intentMode
 func dbGetLatestByInitiatorAndType(ctx context.Context, db *sqlx.DB, intentType intent.Type, owner string) (*intentModel, error) {
 	res := &intentModel{}
 
-	query := `SELECT id, intent_id, intent_type, owner, source, destination_owner, destination, quantity, treasury_pool, recent_root, exchange_currency, exchange_rate, native_amount, usd_market_value, is_withdraw, is_deposit, is_remote_send, is_returned, is_issuer_voiding_gift_card, is_micro_payment, relationship_to, phone_number, state, created_at
+	query := `SELECT id, intent_id, intent_type, owner, source, destination_owner, destination, quantity, treasury_pool, recent_root, exchange_currency, exchange_rate, native_amount, usd_market_value, is_withdraw, is_deposit, is_remote_send, is_returned, is_issuer_voiding_gift_card, is_micro_payment, relationship_to, phone_number, state, created_at, mint
 		FROM ` + intentTableName + `
 		WHERE owner = $1 AND intent_type = $2
 		ORDER BY created_at DESC
@@ -342,7 +357,7 @@ func dbGetLatestByInitiatorAndType(ctx context.Context, db *sqlx.DB, intentType
 func dbGetAllByOwner(ctx context.Context, db *sqlx.DB, owner string, cursor q.Cursor, limit uint64, direction q.Ordering) ([]*intentModel, error) {
 	res := []*intentModel{}
 
-	query := `SELECT id, intent_id, intent_type, owner, source, destination_owner, destination, quantity, treasury_pool, recent_root, exchange_currency, exchange_rate, native_amount, usd_market_value, is_withdraw, is_deposit, is_remote_send, is_returned, is_issuer_voiding_gift_card, is_micro_payment, relationship_to, phone_number, state, created_at
+	query := `SELECT id, intent_id, intent_type, owner, source, destination_owner, destination, quantity, treasury_pool, recent_root, exchange_currency, exchange_rate, native_amount, usd_market_value, is_withdraw, is_deposit, is_remote_send, is_returned, is_issuer_voiding_gift_card, is_micro_payment, relationship_to, phone_number, state, created_at, mint
 		FROM ` + intentTableName + `
 		WHERE (owner = $1 OR destination_owner = $1) AND (intent_type != $2 AND intent_type != $3)
 	`
@@ -503,7 +518,7 @@ func dbGetNetBalanceFromPrePrivacy2022Intents(ctx context.Context, db *sqlx.DB,
 func dbGetLatestSaveRecentRootIntentForTreasury(ctx context.Context, db *sqlx.DB, treasury string) (*intentModel, error) {
 	res := &intentModel{}
 
-	query := `SELECT id, intent_id, intent_type, owner, source, destination_owner, destination, quantity, treasury_pool, recent_root, exchange_currency, exchange_rate, native_amount, usd_market_value, is_withdraw, is_deposit, is_remote_send, is_returned, is_issuer_voiding_gift_card, is_micro_payment, relationship_to, phone_number, state, created_at
+	query := `SELECT id, intent_id, intent_type, owner, source, destination_owner, destination, quantity, treasury_pool, recent_root, exchange_currency, exchange_rate, native_amount, usd_market_value, is_withdraw, is_deposit, is_remote_send, is_returned, is_issuer_voiding_gift_card, is_micro_payment, relationship_to, phone_number, state, created_at, mint
 		FROM ` + intentTableName + `
 		WHERE treasury_pool = $1 and intent_type = $2
 		ORDER BY id DESC
@@ -520,7 +535,7 @@ func dbGetLatestSaveRecentRootIntentForTreasury(ctx context.Context, db *sqlx.DB
 func dbGetOriginalGiftCardIssuedIntent(ctx context.Context, db *sqlx.DB, giftCardVault string) (*intentModel, error) {
 	res := []*intentModel{}
 
-	query := `SELECT id, intent_id, intent_type, owner, source, destination_owner, destination, quantity, treasury_pool, recent_root, exchange_currency, exchange_rate, native_amount, usd_market_value, is_withdraw, is_deposit, is_remote_send, is_returned, is_issuer_voiding_gift_card, is_micro_payment, relationship_to, phone_number, state, created_at
+	query := `SELECT id, intent_id, intent_type, owner, source, destination_owner, destination, quantity, treasury_pool, recent_root, exchange_currency, exchange_rate, native_amount, usd_market_value, is_withdraw, is_deposit, is_remote_send, is_returned, is_issuer_voiding_gift_card, is_micro_payment, relationship_to, phone_number, state, created_at, mint
 		FROM ` + intentTableName + `
 		WHERE destination = $1 and intent_type = $2 AND state != $3 AND is_remote_send IS TRUE
 		LIMIT 2
@@ -552,7 +567,7 @@ func dbGetOriginalGiftCardIssuedIntent(ctx context.Context, db *sqlx.DB, giftCar
 func dbGetGiftCardClaimedIntent(ctx context.Context, db *sqlx.DB, giftCardVault string) (*intentModel, error) {
 	res := []*intentModel{}
 
-	query := `SELECT id, intent_id, intent_type, owner, source, destination_owner, destination, quantity, treasury_pool, recent_root, exchange_currency, exchange_rate, native_amount, usd_market_value, is_withdraw, is_deposit, is_remote_send, is_returned, is_issuer_voiding_gift_card, is_micro_payment, relationship_to, phone_number, state, created_at
+	query := `SELECT id, intent_id, intent_type, owner, source, destination_owner, destination, quantity, treasury_pool, recent_root, exchange_currency, exchange_rate, native_amount, usd_market_value, is_withdraw, is_deposit, is_remote_send, is_returned, is_issuer_voiding_gift_card, is_micro_payment, relationship_to, phone_number, state, created_at, mint
 		FROM ` + intentTableName + `
 		WHERE source = $1 and intent_type = $2 AND state != $3 AND is_remote_send IS TRUE
 		LIMIT 2
@@ -53,7 +53,9 @@ const (
 
 			state integer NOT NULL,
 
-			created_at timestamp with time zone NOT NULL
+			created_at timestamp with time zone NOT NULL,
+
+			mint TEXT NOT NULL DEFAULT ''
 		);
 	`
 
@@ -25,6 +25,7 @@ func RunTests(t *testing.T, s intent.Store, teardown func()) {
 		testSendPublicPaymentRoundTrip,
 		testReceivePaymentsPubliclyRoundTrip,
 		testEstablishRelationshipRoundTrip,
+		testTreasuryPoolFundingRoundTrip,
 		testUpdate,
 		testGetLatestByInitiatorAndType,
 		testGetCountForAntispam,
@@ -309,6 +310,47 @@ func testSaveRecentRootRoundTrip(t *testing.T, s intent.Store) {
 	})
 }
 
+func testTreasuryPoolFundingRoundTrip(t *testing.T, s intent.Store) {
+	t.Run("testTreasuryPoolFundingRoundTrip", func(t *testing.T) {
+		ctx := context.Background()
+
+		actual, err := s.Get(ctx, "test_intent_id")
+		require.Error(t, err)
+		assert.Equal(t, intent.ErrIntentNotFound, err)
+		assert.Nil(t, actual)
+
+		expected := intent.Record{
+			IntentId:              "test_intent_id",
+			IntentType:            intent.TreasuryPoolFunding,
+			InitiatorOwnerAccount: "test_owner",
+			TreasuryPoolFundingMetadata: &intent.TreasuryPoolFundingMetadata{
+				TreasuryPool: "test_treasury_pool",
+				Source:       "test_source",
+				Quantity:     12345,
+			},
+			State:     intent.StateUnknown,
+			CreatedAt: time.Now(),
+		}
+		cloned := expected.Clone()
+		err = s.Save(ctx, &expected)
+		require.NoError(t, err)
+
+		actual, err = s.Get(ctx, "test_intent_id")
+		require.NoError(t, err)
+		assert.Equal(t, cloned.IntentId, actual.IntentId)
+		assert.Equal(t, cloned.IntentType, actual.IntentType)
+		assert.Equal(t, cloned.InitiatorOwnerAccount, actual.InitiatorOwnerAccount)
+		assert.Nil(t, actual.InitiatorPhoneNumber)
+		require.NotNil(t, actual.TreasuryPoolFundingMetadata)
+		assert.Equal(t, cloned.TreasuryPoolFundingMetadata.TreasuryPool, actual.TreasuryPoolFundingMetadata.TreasuryPool)
+		assert.Equal(t, cloned.TreasuryPoolFundingMetadata.Source, actual.TreasuryPoolFundingMetadata.Source)
+		assert.Equal(t, cloned.TreasuryPoolFundingMetadata.Quantity, actual.TreasuryPoolFundingMetadata.Quantity)
+		assert.Equal(t, cloned.State, actual.State)
+		assert.Equal(t, cloned.CreatedAt.Unix(), actual.CreatedAt.Unix())
+		assert.EqualValues(t, 1, actual.Id)
+	})
+}
+
 func testMigrateToPrivacy2022RoundTrip(t *testing.T, s intent.Store) {
 	t.Run("testMigrateToPrivacy2022RoundTrip", func(t *testing.T) {
 		ctx := context.Background()
@@ -361,6 +403,7 @@ func testExternalDepositRoundTrip(t *testing.T, s intent.Store) {
 			IntentId:              "test_intent_id",
 			IntentType:            intent.ExternalDeposit,
 			InitiatorOwnerAccount: "test_owner",
+			Mint:                  "test_mint",
 			ExternalDepositMetadata: &intent.ExternalDepositMetadata{
 				DestinationOwnerAccount: "test_destination_owner",
 				DestinationTokenAccount: "test_destination_token",
@@ -380,6 +423,7 @@ func testExternalDepositRoundTrip(t *testing.T, s intent.Store) {
 		assert.Equal(t, cloned.IntentType, actual.IntentType)
 		assert.Equal(t, cloned.InitiatorOwnerAccount, actual.InitiatorOwnerAccount)
 		assert.Nil(t, actual.InitiatorPhoneNumber)
+		assert.Equal(t, cloned.Mint, actual.Mint)
 		require.NotNil(t, actual.ExternalDepositMetadata)
 		assert.Equal(t, cloned.ExternalDepositMetadata.DestinationOwnerAccount, actual.ExternalDepositMetadata.DestinationOwnerAccount)
 		assert.Equal(t, cloned.ExternalDepositMetadata.DestinationTokenAccount, actual.ExternalDepositMetadata.DestinationTokenAccount)
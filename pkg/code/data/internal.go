@@ -20,16 +20,26 @@ import (
 
 	"github.com/code-payments/code-server/pkg/code/data/account"
 	"github.com/code-payments/code-server/pkg/code/data/action"
+	"github.com/code-payments/code-server/pkg/code/data/adminaudit"
 	"github.com/code-payments/code-server/pkg/code/data/badgecount"
+	"github.com/code-payments/code-server/pkg/code/data/banlist"
+	"github.com/code-payments/code-server/pkg/code/data/campaign"
 	"github.com/code-payments/code-server/pkg/code/data/chat"
 	"github.com/code-payments/code-server/pkg/code/data/commitment"
 	"github.com/code-payments/code-server/pkg/code/data/contact"
 	"github.com/code-payments/code-server/pkg/code/data/currency"
+	"github.com/code-payments/code-server/pkg/code/data/deadletter"
 	"github.com/code-payments/code-server/pkg/code/data/deposit"
 	"github.com/code-payments/code-server/pkg/code/data/event"
+	"github.com/code-payments/code-server/pkg/code/data/featureflag"
+	"github.com/code-payments/code-server/pkg/code/data/freeaccount"
 	"github.com/code-payments/code-server/pkg/code/data/fulfillment"
+	"github.com/code-payments/code-server/pkg/code/data/integritynonce"
 	"github.com/code-payments/code-server/pkg/code/data/intent"
+	"github.com/code-payments/code-server/pkg/code/data/limit"
 	"github.com/code-payments/code-server/pkg/code/data/login"
+	"github.com/code-payments/code-server/pkg/code/data/mandate"
+	"github.com/code-payments/code-server/pkg/code/data/membership"
 	"github.com/code-payments/code-server/pkg/code/data/merkletree"
 	"github.com/code-payments/code-server/pkg/code/data/nonce"
 	"github.com/code-payments/code-server/pkg/code/data/payment"
@@ -47,16 +57,26 @@ import (
 
 	account_memory_client "github.com/code-payments/code-server/pkg/code/data/account/memory"
 	action_memory_client "github.com/code-payments/code-server/pkg/code/data/action/memory"
+	adminaudit_memory_client "github.com/code-payments/code-server/pkg/code/data/adminaudit/memory"
 	badgecount_memory_client "github.com/code-payments/code-server/pkg/code/data/badgecount/memory"
+	banlist_memory_client "github.com/code-payments/code-server/pkg/code/data/banlist/memory"
+	campaign_memory_client "github.com/code-payments/code-server/pkg/code/data/campaign/memory"
 	chat_memory_client "github.com/code-payments/code-server/pkg/code/data/chat/memory"
 	commitment_memory_client "github.com/code-payments/code-server/pkg/code/data/commitment/memory"
 	contact_memory_client "github.com/code-payments/code-server/pkg/code/data/contact/memory"
 	currency_memory_client "github.com/code-payments/code-server/pkg/code/data/currency/memory"
+	deadletter_memory_client "github.com/code-payments/code-server/pkg/code/data/deadletter/memory"
 	deposit_memory_client "github.com/code-payments/code-server/pkg/code/data/deposit/memory"
 	event_memory_client "github.com/code-payments/code-server/pkg/code/data/event/memory"
+	featureflag_memory_client "github.com/code-payments/code-server/pkg/code/data/featureflag/memory"
+	freeaccount_memory_client "github.com/code-payments/code-server/pkg/code/data/freeaccount/memory"
 	fulfillment_memory_client "github.com/code-payments/code-server/pkg/code/data/fulfillment/memory"
+	integritynonce_memory_client "github.com/code-payments/code-server/pkg/code/data/integritynonce/memory"
 	intent_memory_client "github.com/code-payments/code-server/pkg/code/data/intent/memory"
+	limit_memory_client "github.com/code-payments/code-server/pkg/code/data/limit/memory"
 	login_memory_client "github.com/code-payments/code-server/pkg/code/data/login/memory"
+	mandate_memory_client "github.com/code-payments/code-server/pkg/code/data/mandate/memory"
+	membership_memory_client "github.com/code-payments/code-server/pkg/code/data/membership/memory"
 	merkletree_memory_client "github.com/code-payments/code-server/pkg/code/data/merkletree/memory"
 	messaging "github.com/code-payments/code-server/pkg/code/data/messaging"
 	messaging_memory_client "github.com/code-payments/code-server/pkg/code/data/messaging/memory"
@@ -79,16 +99,26 @@ import (
 
 	account_postgres_client "github.com/code-payments/code-server/pkg/code/data/account/postgres"
 	action_postgres_client "github.com/code-payments/code-server/pkg/code/data/action/postgres"
+	adminaudit_postgres_client "github.com/code-payments/code-server/pkg/code/data/adminaudit/postgres"
 	badgecount_postgres_client "github.com/code-payments/code-server/pkg/code/data/badgecount/postgres"
+	banlist_postgres_client "github.com/code-payments/code-server/pkg/code/data/banlist/postgres"
+	campaign_postgres_client "github.com/code-payments/code-server/pkg/code/data/campaign/postgres"
 	chat_postgres_client "github.com/code-payments/code-server/pkg/code/data/chat/postgres"
 	commitment_postgres_client "github.com/code-payments/code-server/pkg/code/data/commitment/postgres"
 	contact_postgres_client "github.com/code-payments/code-server/pkg/code/data/contact/postgres"
 	currency_postgres_client "github.com/code-payments/code-server/pkg/code/data/currency/postgres"
+	deadletter_postgres_client "github.com/code-payments/code-server/pkg/code/data/deadletter/postgres"
 	deposit_postgres_client "github.com/code-payments/code-server/pkg/code/data/deposit/postgres"
 	event_postgres_client "github.com/code-payments/code-server/pkg/code/data/event/postgres"
+	featureflag_postgres_client "github.com/code-payments/code-server/pkg/code/data/featureflag/postgres"
+	freeaccount_postgres_client "github.com/code-payments/code-server/pkg/code/data/freeaccount/postgres"
 	fulfillment_postgres_client "github.com/code-payments/code-server/pkg/code/data/fulfillment/postgres"
+	integritynonce_postgres_client "github.com/code-payments/code-server/pkg/code/data/integritynonce/postgres"
 	intent_postgres_client "github.com/code-payments/code-server/pkg/code/data/intent/postgres"
+	limit_postgres_client "github.com/code-payments/code-server/pkg/code/data/limit/postgres"
 	login_postgres_client "github.com/code-payments/code-server/pkg/code/data/login/postgres"
+	mandate_postgres_client "github.com/code-payments/code-server/pkg/code/data/mandate/postgres"
+	membership_postgres_client "github.com/code-payments/code-server/pkg/code/data/membership/postgres"
 	merkletree_postgres_client "github.com/code-payments/code-server/pkg/code/data/merkletree/postgres"
 	messaging_postgres_client "github.com/code-payments/code-server/pkg/code/data/messaging/postgres"
 	nonce_postgres_client "github.com/code-payments/code-server/pkg/code/data/nonce/postgres"
@@ -159,6 +189,7 @@ type DatabaseData interface {
 	GetNonceCount(ctx context.Context) (uint64, error)
 	GetNonceCountByState(ctx context.Context, state nonce.State) (uint64, error)
 	GetNonceCountByStateAndPurpose(ctx context.Context, state nonce.State, purpose nonce.Purpose) (uint64, error)
+	GetNonceCountByStateAndFeePayer(ctx context.Context, state nonce.State, feePayer string) (uint64, error)
 	GetAllNonceByState(ctx context.Context, state nonce.State, opts ...query.Option) ([]*nonce.Record, error)
 	GetRandomAvailableNonceByPurpose(ctx context.Context, purpose nonce.Purpose) (*nonce.Record, error)
 	SaveNonce(ctx context.Context, record *nonce.Record) error
@@ -189,6 +220,7 @@ type DatabaseData interface {
 	PutAllFulfillments(ctx context.Context, records ...*fulfillment.Record) error
 	UpdateFulfillment(ctx context.Context, record *fulfillment.Record) error
 	MarkFulfillmentAsActivelyScheduled(ctx context.Context, id uint64) error
+	MarkFulfillmentAsNotActivelyScheduled(ctx context.Context, id uint64) error
 	ActivelyScheduleTreasuryAdvanceFulfillments(ctx context.Context, treasury string, intentOrderingIndex uint64, limit int) (uint64, error)
 
 	// Intent
@@ -249,6 +281,8 @@ type DatabaseData interface {
 	SavePhoneLinkingToken(ctx context.Context, token *phone.LinkingToken) error
 	UsePhoneLinkingToken(ctx context.Context, phoneNumber, code string) error
 	FilterVerifiedPhoneNumbers(ctx context.Context, phoneNumbers []string) ([]string, error)
+	GetVerifiedPhoneNumberHashesByPrefix(ctx context.Context, prefixes [][]byte) ([][]byte, error)
+	BackfillPhoneNumberHashes(ctx context.Context, limit uint64) (uint64, error)
 	SaveOwnerAccountPhoneSetting(ctx context.Context, phoneNumber string, newSettings *phone.OwnerAccountSetting) error
 	IsPhoneNumberLinkedToAccount(ctx context.Context, phoneNumber string, tokenAccount string) (bool, error)
 	IsPhoneNumberEnabledForRemoteSendToAccount(ctx context.Context, phoneNumber string, tokenAccount string) (bool, error)
@@ -264,6 +298,9 @@ type DatabaseData interface {
 	BatchAddContacts(ctx context.Context, owner *user.DataContainerID, contacts []string) error
 	RemoveContact(ctx context.Context, owner *user.DataContainerID, contact string) error
 	BatchRemoveContacts(ctx context.Context, owner *user.DataContainerID, contacts []string) error
+	RemoveAllContacts(ctx context.Context, owner *user.DataContainerID) error
+	RecordContactDiscoveryLookups(ctx context.Context, ownerAccount string, count uint32, at time.Time) error
+	GetContactDiscoveryLookupCountSinceTimestamp(ctx context.Context, ownerAccount string, since time.Time) (uint64, error)
 	GetContacts(ctx context.Context, owner *user.DataContainerID, limit uint32, pageToken []byte) (contacts []string, nextPageToken []byte, err error)
 
 	// User Identity
@@ -316,6 +353,8 @@ type DatabaseData interface {
 	GetAllTreasuryPoolsByState(ctx context.Context, state treasury.TreasuryPoolState, opts ...query.Option) ([]*treasury.Record, error)
 	SaveTreasuryPoolFunding(ctx context.Context, record *treasury.FundingHistoryRecord) error
 	GetTotalAvailableTreasuryPoolFunds(ctx context.Context, vault string) (uint64, error)
+	GetTreasuryPoolFundingCountByVaultAndState(ctx context.Context, vault string, state treasury.FundingState) (uint64, error)
+	GetAllTreasuryPoolFundingCreatedAfter(ctx context.Context, createdAfter time.Time) ([]*treasury.FundingHistoryRecord, error)
 
 	// Merkle Tree
 	// --------------------------------------------------------------------------------
@@ -350,6 +389,9 @@ type DatabaseData interface {
 	// --------------------------------------------------------------------------------
 	SaveEvent(ctx context.Context, record *event.Record) error
 	GetEvent(ctx context.Context, id string) (*event.Record, error)
+	CountEventsBySourceIdentityAndTypeSince(ctx context.Context, identity string, eventType event.EventType, since time.Time) (uint64, error)
+	CountEventsBySourceClientIpAndTypeSince(ctx context.Context, ip string, eventType event.EventType, since time.Time) (uint64, error)
+	CountEventsBySourceIdentityAndMinSpamConfidenceSince(ctx context.Context, identity string, minSpamConfidence float64, since time.Time) (uint64, error)
 
 	// Webhook
 	// --------------------------------------------------------------------------------
@@ -385,6 +427,78 @@ type DatabaseData interface {
 	GetLoginsByAppInstall(ctx context.Context, appInstallId string) (*login.MultiRecord, error)
 	GetLatestLoginByOwner(ctx context.Context, owner string) (*login.Record, error)
 
+	// Ban List
+	// --------------------------------------------------------------------------------
+	PutBanListEntry(ctx context.Context, record *banlist.Record) error
+	GetBanListEntry(ctx context.Context, entryType banlist.EntryType, value string) (*banlist.Record, error)
+	DeleteBanListEntry(ctx context.Context, entryType banlist.EntryType, value string) error
+	GetAllBanListEntriesByType(ctx context.Context, entryType banlist.EntryType) ([]*banlist.Record, error)
+	GetAllActiveBanListEntries(ctx context.Context, at time.Time) ([]*banlist.Record, error)
+
+	// Campaign
+	// --------------------------------------------------------------------------------
+	PutCampaign(ctx context.Context, record *campaign.Record) error
+	GetCampaign(ctx context.Context, name string) (*campaign.Record, error)
+	GetAllCampaigns(ctx context.Context) ([]*campaign.Record, error)
+	CreateCampaignPayout(ctx context.Context, record *campaign.Payout) error
+	UpdateCampaignPayoutState(ctx context.Context, intentId string, state campaign.PayoutState) error
+	GetCampaignPayout(ctx context.Context, campaignName, owner string) (*campaign.Payout, error)
+	GetCampaignPayoutByIntentId(ctx context.Context, intentId string) (*campaign.Payout, error)
+	GetAllCampaignPayoutsByState(ctx context.Context, state campaign.PayoutState, limit uint64) ([]*campaign.Payout, error)
+	GetCampaignPayoutCountByPhoneNumber(ctx context.Context, campaignName, phoneNumber string) (uint64, error)
+	GetCampaignSpend(ctx context.Context, campaignName string) (*campaign.Spend, error)
+
+	// Limit
+	// --------------------------------------------------------------------------------
+	PutLimits(ctx context.Context, record *limit.Record) error
+	GetAllLimitsByTier(ctx context.Context, tier limit.Tier) ([]*limit.Record, error)
+	GetAllLimitOverridesByOwner(ctx context.Context, owner string) ([]*limit.Record, error)
+	PutLimitTierAssignment(ctx context.Context, record *limit.TierAssignment) error
+	GetLimitTierAssignment(ctx context.Context, owner string) (*limit.TierAssignment, error)
+
+	// Mandate
+	// --------------------------------------------------------------------------------
+	CreateMandate(ctx context.Context, record *mandate.Record) error
+	UpdateMandate(ctx context.Context, record *mandate.Record) error
+	GetMandate(ctx context.Context, mandateId string) (*mandate.Record, error)
+	GetAllMandatesByOwner(ctx context.Context, owner string) ([]*mandate.Record, error)
+	GetAllDueMandates(ctx context.Context, at time.Time, limit uint64) ([]*mandate.Record, error)
+	GetAllMandatesByStateCreatedBefore(ctx context.Context, state mandate.State, before time.Time, limit uint64) ([]*mandate.Record, error)
+
+	// Fulfillment Dead Letter
+	// --------------------------------------------------------------------------------
+	SaveFulfillmentDeadLetter(ctx context.Context, record *deadletter.Record) error
+	GetFulfillmentDeadLetter(ctx context.Context, fulfillmentId uint64) (*deadletter.Record, error)
+	GetFulfillmentDeadLetterCountByStateGroupedByCategory(ctx context.Context, state deadletter.State) (map[deadletter.Category]uint64, error)
+
+	// Admin Audit
+	// --------------------------------------------------------------------------------
+	PutAdminAuditRecord(ctx context.Context, record *adminaudit.Record) error
+	GetAllAdminAuditRecordsByIntent(ctx context.Context, intent string) ([]*adminaudit.Record, error)
+	GetAllAdminAuditRecordsByFulfillment(ctx context.Context, fulfillmentId uint64) ([]*adminaudit.Record, error)
+
+	// Free Account Devices
+	// --------------------------------------------------------------------------------
+	MarkDeviceCreatedFreeAccount(ctx context.Context, deviceId string) error
+	HasDeviceCreatedFreeAccount(ctx context.Context, deviceId string) (bool, error)
+
+	// Device Integrity Nonces
+	// --------------------------------------------------------------------------------
+	MarkIntegrityNonceUsed(ctx context.Context, nonce, deviceId string) error
+
+	// Worker Membership
+	// --------------------------------------------------------------------------------
+	HeartbeatWorkerMember(ctx context.Context, group, nodeId string) error
+	GetAllActiveWorkerMembers(ctx context.Context, group string, since time.Time) ([]*membership.Record, error)
+	RemoveWorkerMember(ctx context.Context, group, nodeId string) error
+
+	// Feature Flags
+	// --------------------------------------------------------------------------------
+	PutFeatureFlag(ctx context.Context, record *featureflag.Record) error
+	GetFeatureFlag(ctx context.Context, name string) (*featureflag.Record, error)
+	DeleteFeatureFlag(ctx context.Context, name string) error
+	GetAllFeatureFlags(ctx context.Context) ([]*featureflag.Record, error)
+
 	// ExecuteInTx executes fn with a single DB transaction that is scoped to the call.
 	// This enables more complex transactions that can span many calls across the provider.
 	//
@@ -423,6 +537,16 @@ type DatabaseProvider struct {
 	chat           chat.Store
 	badgecount     badgecount.Store
 	login          login.Store
+	banlist        banlist.Store
+	campaign       campaign.Store
+	limit          limit.Store
+	mandate        mandate.Store
+	deadletter     deadletter.Store
+	adminaudit     adminaudit.Store
+	freeaccount    freeaccount.Store
+	integritynonce integritynonce.Store
+	membership     membership.Store
+	featureflag    featureflag.Store
 
 	exchangeCache cache.Cache
 	timelockCache cache.Cache
@@ -480,6 +604,16 @@ func NewDatabaseProvider(dbConfig *pg.Config) (DatabaseData, error) {
 		chat:           chat_postgres_client.New(db),
 		badgecount:     badgecount_postgres_client.New(db),
 		login:          login_postgres_client.New(db),
+		banlist:        banlist_postgres_client.New(db),
+		campaign:       campaign_postgres_client.New(db),
+		limit:          limit_postgres_client.New(db),
+		mandate:        mandate_postgres_client.New(db),
+		deadletter:     deadletter_postgres_client.New(db),
+		adminaudit:     adminaudit_postgres_client.New(db),
+		freeaccount:    freeaccount_postgres_client.New(db),
+		integritynonce: integritynonce_postgres_client.New(db),
+		membership:     membership_postgres_client.New(db),
+		featureflag:    featureflag_postgres_client.New(db),
 
 		exchangeCache: cache.NewCache(maxExchangeRateCacheBudget),
 		timelockCache: cache.NewCache(maxTimelockCacheBudget),
@@ -518,6 +652,16 @@ func NewTestDatabaseProvider() DatabaseData {
 		chat:           chat_memory_client.New(),
 		badgecount:     badgecount_memory_client.New(),
 		login:          login_memory_client.New(),
+		banlist:        banlist_memory_client.New(),
+		campaign:       campaign_memory_client.New(),
+		limit:          limit_memory_client.New(),
+		mandate:        mandate_memory_client.New(),
+		deadletter:     deadletter_memory_client.New(),
+		adminaudit:     adminaudit_memory_client.New(),
+		freeaccount:    freeaccount_memory_client.New(),
+		integritynonce: integritynonce_memory_client.New(),
+		membership:     membership_memory_client.New(),
+		featureflag:    featureflag_memory_client.New(),
 
 		exchangeCache: cache.NewCache(maxExchangeRateCacheBudget),
 		timelockCache: nil, // Shouldn't be used for tests
@@ -661,6 +805,9 @@ func (dp *DatabaseProvider) GetNonceCountByState(ctx context.Context, state nonc
 func (dp *DatabaseProvider) GetNonceCountByStateAndPurpose(ctx context.Context, state nonce.State, purpose nonce.Purpose) (uint64, error) {
 	return dp.nonces.CountByStateAndPurpose(ctx, state, purpose)
 }
+func (dp *DatabaseProvider) GetNonceCountByStateAndFeePayer(ctx context.Context, state nonce.State, feePayer string) (uint64, error) {
+	return dp.nonces.CountByStateAndFeePayer(ctx, state, feePayer)
+}
 func (dp *DatabaseProvider) GetAllNonceByState(ctx context.Context, state nonce.State, opts ...query.Option) ([]*nonce.Record, error) {
 	req, err := query.DefaultPaginationHandler(opts...)
 	if err != nil {
@@ -760,6 +907,9 @@ func (dp *DatabaseProvider) UpdateFulfillment(ctx context.Context, record *fulfi
 func (dp *DatabaseProvider) MarkFulfillmentAsActivelyScheduled(ctx context.Context, id uint64) error {
 	return dp.fulfillments.MarkAsActivelyScheduled(ctx, id)
 }
+func (dp *DatabaseProvider) MarkFulfillmentAsNotActivelyScheduled(ctx context.Context, id uint64) error {
+	return dp.fulfillments.MarkAsNotActivelyScheduled(ctx, id)
+}
 func (dp *DatabaseProvider) ActivelyScheduleTreasuryAdvanceFulfillments(ctx context.Context, treasury string, intentOrderingIndex uint64, limit int) (uint64, error) {
 	return dp.fulfillments.ActivelyScheduleTreasuryAdvances(ctx, treasury, intentOrderingIndex, limit)
 }
@@ -968,6 +1118,12 @@ func (dp *DatabaseProvider) UsePhoneLinkingToken(ctx context.Context, phoneNumbe
 func (dp *DatabaseProvider) FilterVerifiedPhoneNumbers(ctx context.Context, phoneNumbers []string) ([]string, error) {
 	return dp.phone.FilterVerifiedNumbers(ctx, phoneNumbers)
 }
+func (dp *DatabaseProvider) GetVerifiedPhoneNumberHashesByPrefix(ctx context.Context, prefixes [][]byte) ([][]byte, error) {
+	return dp.phone.GetVerifiedNumberHashesByPrefix(ctx, prefixes)
+}
+func (dp *DatabaseProvider) BackfillPhoneNumberHashes(ctx context.Context, limit uint64) (uint64, error) {
+	return dp.phone.BackfillNumberHashes(ctx, limit)
+}
 func (dp *DatabaseProvider) SaveOwnerAccountPhoneSetting(ctx context.Context, phoneNumber string, newSettings *phone.OwnerAccountSetting) error {
 	return dp.phone.SaveOwnerAccountSetting(ctx, phoneNumber, newSettings)
 }
@@ -1032,6 +1188,15 @@ func (dp *DatabaseProvider) GetContacts(ctx context.Context, owner *user.DataCon
 func (dp *DatabaseProvider) BatchRemoveContacts(ctx context.Context, owner *user.DataContainerID, contacts []string) error {
 	return dp.contact.BatchRemove(ctx, owner, contacts)
 }
+func (dp *DatabaseProvider) RemoveAllContacts(ctx context.Context, owner *user.DataContainerID) error {
+	return dp.contact.RemoveAll(ctx, owner)
+}
+func (dp *DatabaseProvider) RecordContactDiscoveryLookups(ctx context.Context, ownerAccount string, count uint32, at time.Time) error {
+	return dp.contact.RecordDiscoveryLookups(ctx, ownerAccount, count, at)
+}
+func (dp *DatabaseProvider) GetContactDiscoveryLookupCountSinceTimestamp(ctx context.Context, ownerAccount string, since time.Time) (uint64, error) {
+	return dp.contact.CountDiscoveryLookupsSince(ctx, ownerAccount, since)
+}
 
 // User Identity
 // --------------------------------------------------------------------------------
@@ -1244,6 +1409,12 @@ func (dp *DatabaseProvider) SaveTreasuryPoolFunding(ctx context.Context, record
 func (dp *DatabaseProvider) GetTotalAvailableTreasuryPoolFunds(ctx context.Context, vault string) (uint64, error) {
 	return dp.treasury.GetTotalAvailableFunds(ctx, vault)
 }
+func (dp *DatabaseProvider) GetTreasuryPoolFundingCountByVaultAndState(ctx context.Context, vault string, state treasury.FundingState) (uint64, error) {
+	return dp.treasury.GetFundingCountByVaultAndState(ctx, vault, state)
+}
+func (dp *DatabaseProvider) GetAllTreasuryPoolFundingCreatedAfter(ctx context.Context, createdAfter time.Time) ([]*treasury.FundingHistoryRecord, error) {
+	return dp.treasury.GetAllFundingCreatedAfter(ctx, createdAfter)
+}
 
 // Merkle Tree
 func (dp *DatabaseProvider) InitializeNewMerkleTree(ctx context.Context, name string, levels uint8, seeds []merkletree.Seed, readOnly bool) (*merkletree.MerkleTree, error) {
@@ -1309,6 +1480,15 @@ func (dp *DatabaseProvider) SaveEvent(ctx context.Context, record *event.Record)
 func (dp *DatabaseProvider) GetEvent(ctx context.Context, id string) (*event.Record, error) {
 	return dp.event.Get(ctx, id)
 }
+func (dp *DatabaseProvider) CountEventsBySourceIdentityAndTypeSince(ctx context.Context, identity string, eventType event.EventType, since time.Time) (uint64, error) {
+	return dp.event.CountBySourceIdentityAndTypeSince(ctx, identity, eventType, since)
+}
+func (dp *DatabaseProvider) CountEventsBySourceClientIpAndTypeSince(ctx context.Context, ip string, eventType event.EventType, since time.Time) (uint64, error) {
+	return dp.event.CountBySourceClientIpAndTypeSince(ctx, ip, eventType, since)
+}
+func (dp *DatabaseProvider) CountEventsBySourceIdentityAndMinSpamConfidenceSince(ctx context.Context, identity string, minSpamConfidence float64, since time.Time) (uint64, error) {
+	return dp.event.CountBySourceIdentityAndMinSpamConfidenceSince(ctx, identity, minSpamConfidence, since)
+}
 
 // Webhook
 // --------------------------------------------------------------------------------
@@ -1395,3 +1575,176 @@ func (dp *DatabaseProvider) GetLoginsByAppInstall(ctx context.Context, appInstal
 func (dp *DatabaseProvider) GetLatestLoginByOwner(ctx context.Context, owner string) (*login.Record, error) {
 	return dp.login.GetLatestByOwner(ctx, owner)
 }
+
+// Ban List
+// --------------------------------------------------------------------------------
+func (dp *DatabaseProvider) PutBanListEntry(ctx context.Context, record *banlist.Record) error {
+	return dp.banlist.Put(ctx, record)
+}
+func (dp *DatabaseProvider) GetBanListEntry(ctx context.Context, entryType banlist.EntryType, value string) (*banlist.Record, error) {
+	return dp.banlist.Get(ctx, entryType, value)
+}
+func (dp *DatabaseProvider) DeleteBanListEntry(ctx context.Context, entryType banlist.EntryType, value string) error {
+	return dp.banlist.Delete(ctx, entryType, value)
+}
+func (dp *DatabaseProvider) GetAllBanListEntriesByType(ctx context.Context, entryType banlist.EntryType) ([]*banlist.Record, error) {
+	return dp.banlist.GetAllByType(ctx, entryType)
+}
+func (dp *DatabaseProvider) GetAllActiveBanListEntries(ctx context.Context, at time.Time) ([]*banlist.Record, error) {
+	return dp.banlist.GetAllActive(ctx, at)
+}
+
+// Campaign
+// --------------------------------------------------------------------------------
+func (dp *DatabaseProvider) PutCampaign(ctx context.Context, record *campaign.Record) error {
+	return dp.campaign.PutCampaign(ctx, record)
+}
+func (dp *DatabaseProvider) GetCampaign(ctx context.Context, name string) (*campaign.Record, error) {
+	return dp.campaign.GetCampaign(ctx, name)
+}
+func (dp *DatabaseProvider) GetAllCampaigns(ctx context.Context) ([]*campaign.Record, error) {
+	return dp.campaign.GetAllCampaigns(ctx)
+}
+func (dp *DatabaseProvider) CreateCampaignPayout(ctx context.Context, record *campaign.Payout) error {
+	return dp.campaign.CreatePayout(ctx, record)
+}
+func (dp *DatabaseProvider) UpdateCampaignPayoutState(ctx context.Context, intentId string, state campaign.PayoutState) error {
+	return dp.campaign.UpdatePayoutState(ctx, intentId, state)
+}
+func (dp *DatabaseProvider) GetCampaignPayout(ctx context.Context, campaignName, owner string) (*campaign.Payout, error) {
+	return dp.campaign.GetPayout(ctx, campaignName, owner)
+}
+func (dp *DatabaseProvider) GetCampaignPayoutByIntentId(ctx context.Context, intentId string) (*campaign.Payout, error) {
+	return dp.campaign.GetPayoutByIntentId(ctx, intentId)
+}
+func (dp *DatabaseProvider) GetAllCampaignPayoutsByState(ctx context.Context, state campaign.PayoutState, limit uint64) ([]*campaign.Payout, error) {
+	return dp.campaign.GetAllPayoutsByState(ctx, state, limit)
+}
+func (dp *DatabaseProvider) GetCampaignPayoutCountByPhoneNumber(ctx context.Context, campaignName, phoneNumber string) (uint64, error) {
+	return dp.campaign.GetPayoutCountByPhoneNumber(ctx, campaignName, phoneNumber)
+}
+func (dp *DatabaseProvider) GetCampaignSpend(ctx context.Context, campaignName string) (*campaign.Spend, error) {
+	return dp.campaign.GetSpend(ctx, campaignName)
+}
+
+// Limit
+// --------------------------------------------------------------------------------
+func (dp *DatabaseProvider) PutLimits(ctx context.Context, record *limit.Record) error {
+	return dp.limit.Put(ctx, record)
+}
+func (dp *DatabaseProvider) GetAllLimitsByTier(ctx context.Context, tier limit.Tier) ([]*limit.Record, error) {
+	return dp.limit.GetAllByTier(ctx, tier)
+}
+func (dp *DatabaseProvider) GetAllLimitOverridesByOwner(ctx context.Context, owner string) ([]*limit.Record, error) {
+	return dp.limit.GetAllByOwner(ctx, owner)
+}
+func (dp *DatabaseProvider) PutLimitTierAssignment(ctx context.Context, record *limit.TierAssignment) error {
+	return dp.limit.PutTierAssignment(ctx, record)
+}
+func (dp *DatabaseProvider) GetLimitTierAssignment(ctx context.Context, owner string) (*limit.TierAssignment, error) {
+	return dp.limit.GetTierAssignment(ctx, owner)
+}
+
+// Mandate
+// --------------------------------------------------------------------------------
+func (dp *DatabaseProvider) CreateMandate(ctx context.Context, record *mandate.Record) error {
+	return dp.mandate.Create(ctx, record)
+}
+func (dp *DatabaseProvider) UpdateMandate(ctx context.Context, record *mandate.Record) error {
+	return dp.mandate.Update(ctx, record)
+}
+func (dp *DatabaseProvider) GetMandate(ctx context.Context, mandateId string) (*mandate.Record, error) {
+	return dp.mandate.Get(ctx, mandateId)
+}
+func (dp *DatabaseProvider) GetAllMandatesByOwner(ctx context.Context, owner string) ([]*mandate.Record, error) {
+	return dp.mandate.GetAllByOwner(ctx, owner)
+}
+func (dp *DatabaseProvider) GetAllDueMandates(ctx context.Context, at time.Time, limit uint64) ([]*mandate.Record, error) {
+	return dp.mandate.GetAllDue(ctx, at, limit)
+}
+func (dp *DatabaseProvider) GetAllMandatesByStateCreatedBefore(ctx context.Context, state mandate.State, before time.Time, limit uint64) ([]*mandate.Record, error) {
+	return dp.mandate.GetAllByStateCreatedBefore(ctx, state, before, limit)
+}
+
+// Fulfillment Dead Letter
+// --------------------------------------------------------------------------------
+func (dp *DatabaseProvider) SaveFulfillmentDeadLetter(ctx context.Context, record *deadletter.Record) error {
+	return dp.deadletter.Save(ctx, record)
+}
+func (dp *DatabaseProvider) GetFulfillmentDeadLetter(ctx context.Context, fulfillmentId uint64) (*deadletter.Record, error) {
+	return dp.deadletter.Get(ctx, fulfillmentId)
+}
+func (dp *DatabaseProvider) GetFulfillmentDeadLetterCountByStateGroupedByCategory(ctx context.Context, state deadletter.State) (map[deadletter.Category]uint64, error) {
+	return dp.deadletter.GetCountByStateGroupedByCategory(ctx, state)
+}
+
+// Admin Audit
+// --------------------------------------------------------------------------------
+func (dp *DatabaseProvider) PutAdminAuditRecord(ctx context.Context, record *adminaudit.Record) error {
+	return dp.adminaudit.Put(ctx, record)
+}
+func (dp *DatabaseProvider) GetAllAdminAuditRecordsByIntent(ctx context.Context, intent string) ([]*adminaudit.Record, error) {
+	return dp.adminaudit.GetAllByIntent(ctx, intent)
+}
+func (dp *DatabaseProvider) GetAllAdminAuditRecordsByFulfillment(ctx context.Context, fulfillmentId uint64) ([]*adminaudit.Record, error) {
+	return dp.adminaudit.GetAllByFulfillment(ctx, fulfillmentId)
+}
+
+// Free Account Devices
+// --------------------------------------------------------------------------------
+func (dp *DatabaseProvider) MarkDeviceCreatedFreeAccount(ctx context.Context, deviceId string) error {
+	return dp.freeaccount.Put(ctx, &freeaccount.Record{
+		DeviceId:  deviceId,
+		CreatedAt: time.Now(),
+	})
+}
+func (dp *DatabaseProvider) HasDeviceCreatedFreeAccount(ctx context.Context, deviceId string) (bool, error) {
+	_, err := dp.freeaccount.Get(ctx, deviceId)
+	if err == freeaccount.ErrFreeAccountNotFound {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Device Integrity Nonces
+// --------------------------------------------------------------------------------
+func (dp *DatabaseProvider) MarkIntegrityNonceUsed(ctx context.Context, nonce, deviceId string) error {
+	return dp.integritynonce.Put(ctx, &integritynonce.Record{
+		Nonce:     nonce,
+		DeviceId:  deviceId,
+		CreatedAt: time.Now(),
+	})
+}
+
+// Worker Membership
+// --------------------------------------------------------------------------------
+func (dp *DatabaseProvider) HeartbeatWorkerMember(ctx context.Context, group, nodeId string) error {
+	return dp.membership.Heartbeat(ctx, &membership.Record{
+		Group:           group,
+		NodeId:          nodeId,
+		LastHeartbeatAt: time.Now(),
+	})
+}
+func (dp *DatabaseProvider) GetAllActiveWorkerMembers(ctx context.Context, group string, since time.Time) ([]*membership.Record, error) {
+	return dp.membership.GetAllActive(ctx, group, since)
+}
+func (dp *DatabaseProvider) RemoveWorkerMember(ctx context.Context, group, nodeId string) error {
+	return dp.membership.Delete(ctx, group, nodeId)
+}
+
+// Feature Flags
+// --------------------------------------------------------------------------------
+func (dp *DatabaseProvider) PutFeatureFlag(ctx context.Context, record *featureflag.Record) error {
+	return dp.featureflag.Put(ctx, record)
+}
+func (dp *DatabaseProvider) GetFeatureFlag(ctx context.Context, name string) (*featureflag.Record, error) {
+	return dp.featureflag.Get(ctx, name)
+}
+func (dp *DatabaseProvider) DeleteFeatureFlag(ctx context.Context, name string) error {
+	return dp.featureflag.Delete(ctx, name)
+}
+func (dp *DatabaseProvider) GetAllFeatureFlags(ctx context.Context) ([]*featureflag.Record, error) {
+	return dp.featureflag.GetAll(ctx)
+}
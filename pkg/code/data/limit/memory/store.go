@@ -0,0 +1,173 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/code-payments/code-server/pkg/code/data/limit"
+)
+
+type store struct {
+	mu              sync.Mutex
+	records         []*limit.Record
+	tierAssignments []*limit.TierAssignment
+	last            uint64
+}
+
+func New() limit.Store {
+	return &store{
+		records:         make([]*limit.Record, 0),
+		tierAssignments: make([]*limit.TierAssignment, 0),
+		last:            0,
+	}
+}
+
+func (s *store) reset() {
+	s.mu.Lock()
+	s.records = make([]*limit.Record, 0)
+	s.tierAssignments = make([]*limit.TierAssignment, 0)
+	s.last = 0
+	s.mu.Unlock()
+}
+
+// Put implements limit.Store.Put
+func (s *store) Put(_ context.Context, data *limit.Record) error {
+	if err := data.Validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.last++
+	if item := s.find(data); item != nil {
+		cloned := data.Clone()
+		item.SendPerTransaction = cloned.SendPerTransaction
+		item.SendDaily = cloned.SendDaily
+		item.MicroPaymentMin = cloned.MicroPaymentMin
+		item.MicroPaymentMax = cloned.MicroPaymentMax
+		item.ExpiresAt = cloned.ExpiresAt
+
+		item.CopyTo(data)
+	} else {
+		if data.Id == 0 {
+			data.Id = s.last
+		}
+		if data.CreatedAt.IsZero() {
+			data.CreatedAt = time.Now()
+		}
+		c := data.Clone()
+		s.records = append(s.records, &c)
+	}
+
+	return nil
+}
+
+// GetAllByTier implements limit.Store.GetAllByTier
+func (s *store) GetAllByTier(_ context.Context, tier limit.Tier) ([]*limit.Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var res []*limit.Record
+	for _, item := range s.records {
+		if !item.IsOverride() && item.Tier == tier {
+			cloned := item.Clone()
+			res = append(res, &cloned)
+		}
+	}
+	return sortAndCheckEmpty(res)
+}
+
+// GetAllByOwner implements limit.Store.GetAllByOwner
+func (s *store) GetAllByOwner(_ context.Context, owner string) ([]*limit.Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var res []*limit.Record
+	for _, item := range s.records {
+		if item.IsOverride() && *item.OwnerAccount == owner {
+			cloned := item.Clone()
+			res = append(res, &cloned)
+		}
+	}
+	return sortAndCheckEmpty(res)
+}
+
+// PutTierAssignment implements limit.Store.PutTierAssignment
+func (s *store) PutTierAssignment(_ context.Context, data *limit.TierAssignment) error {
+	if err := data.Validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.last++
+	if item := s.findTierAssignment(data.OwnerAccount); item != nil {
+		item.Tier = data.Tier
+
+		item.CopyTo(data)
+	} else {
+		if data.Id == 0 {
+			data.Id = s.last
+		}
+		if data.CreatedAt.IsZero() {
+			data.CreatedAt = time.Now()
+		}
+		c := data.Clone()
+		s.tierAssignments = append(s.tierAssignments, &c)
+	}
+
+	return nil
+}
+
+// GetTierAssignment implements limit.Store.GetTierAssignment
+func (s *store) GetTierAssignment(_ context.Context, owner string) (*limit.TierAssignment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item := s.findTierAssignment(owner)
+	if item == nil {
+		return nil, limit.ErrTierAssignmentNotFound
+	}
+
+	cloned := item.Clone()
+	return &cloned, nil
+}
+
+func (s *store) find(data *limit.Record) *limit.Record {
+	for _, item := range s.records {
+		if item.Tier != data.Tier || item.IsOverride() != data.IsOverride() {
+			continue
+		}
+		if item.IsOverride() && *item.OwnerAccount != *data.OwnerAccount {
+			continue
+		}
+		if item.Currency == data.Currency && item.EffectiveAt.Equal(data.EffectiveAt) {
+			return item
+		}
+	}
+	return nil
+}
+
+func (s *store) findTierAssignment(owner string) *limit.TierAssignment {
+	for _, item := range s.tierAssignments {
+		if item.OwnerAccount == owner {
+			return item
+		}
+	}
+	return nil
+}
+
+func sortAndCheckEmpty(res []*limit.Record) ([]*limit.Record, error) {
+	if len(res) == 0 {
+		return nil, limit.ErrLimitsNotFound
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Id < res[j].Id
+	})
+	return res, nil
+}
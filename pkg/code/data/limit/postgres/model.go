@@ -0,0 +1,248 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	currency_lib "github.com/code-payments/code-server/pkg/currency"
+	pgutil "github.com/code-payments/code-server/pkg/database/postgres"
+	"github.com/code-payments/code-server/pkg/code/data/limit"
+)
+
+const (
+	limitTableName          = "codewallet__core_limit"
+	tierAssignmentTableName = "codewallet__core_limittierassignment"
+
+	allLimitFields = `id, tier, owner_account, currency, send_per_transaction, send_daily, micro_payment_min, micro_payment_max, effective_at, expires_at, created_at`
+)
+
+type model struct {
+	Id sql.NullInt64 `db:"id"`
+
+	// Tier limits have an empty owner account, so the unique constraint also
+	// applies to them
+	Tier         uint8  `db:"tier"`
+	OwnerAccount string `db:"owner_account"`
+
+	Currency string `db:"currency"`
+
+	SendPerTransaction float64 `db:"send_per_transaction"`
+	SendDaily          float64 `db:"send_daily"`
+
+	MicroPaymentMin float64 `db:"micro_payment_min"`
+	MicroPaymentMax float64 `db:"micro_payment_max"`
+
+	EffectiveAt time.Time    `db:"effective_at"`
+	ExpiresAt   sql.NullTime `db:"expires_at"`
+
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type tierAssignmentModel struct {
+	Id sql.NullInt64 `db:"id"`
+
+	OwnerAccount string `db:"owner_account"`
+	Tier         uint8  `db:"tier"`
+
+	CreatedAt time.Time `db:"created_at"`
+}
+
+func toModel(obj *limit.Record) (*model, error) {
+	if err := obj.Validate(); err != nil {
+		return nil, err
+	}
+
+	if obj.CreatedAt.IsZero() {
+		obj.CreatedAt = time.Now().UTC()
+	}
+
+	var ownerAccount string
+	if obj.OwnerAccount != nil {
+		ownerAccount = *obj.OwnerAccount
+	}
+
+	var expiresAt sql.NullTime
+	if obj.ExpiresAt != nil {
+		expiresAt.Valid = true
+		expiresAt.Time = obj.ExpiresAt.UTC()
+	}
+
+	return &model{
+		Id:                 sql.NullInt64{Int64: int64(obj.Id), Valid: true},
+		Tier:               uint8(obj.Tier),
+		OwnerAccount:       ownerAccount,
+		Currency:           string(obj.Currency),
+		SendPerTransaction: obj.SendPerTransaction,
+		SendDaily:          obj.SendDaily,
+		MicroPaymentMin:    obj.MicroPaymentMin,
+		MicroPaymentMax:    obj.MicroPaymentMax,
+		EffectiveAt:        obj.EffectiveAt.UTC(),
+		ExpiresAt:          expiresAt,
+		CreatedAt:          obj.CreatedAt,
+	}, nil
+}
+
+func fromModel(obj *model) *limit.Record {
+	var ownerAccount *string
+	if len(obj.OwnerAccount) > 0 {
+		value := obj.OwnerAccount
+		ownerAccount = &value
+	}
+
+	var expiresAt *time.Time
+	if obj.ExpiresAt.Valid {
+		value := obj.ExpiresAt.Time
+		expiresAt = &value
+	}
+
+	return &limit.Record{
+		Id:                 uint64(obj.Id.Int64),
+		Tier:               limit.Tier(obj.Tier),
+		OwnerAccount:       ownerAccount,
+		Currency:           currency_lib.Code(obj.Currency),
+		SendPerTransaction: obj.SendPerTransaction,
+		SendDaily:          obj.SendDaily,
+		MicroPaymentMin:    obj.MicroPaymentMin,
+		MicroPaymentMax:    obj.MicroPaymentMax,
+		EffectiveAt:        obj.EffectiveAt,
+		ExpiresAt:          expiresAt,
+		CreatedAt:          obj.CreatedAt,
+	}
+}
+
+func toTierAssignmentModel(obj *limit.TierAssignment) (*tierAssignmentModel, error) {
+	if err := obj.Validate(); err != nil {
+		return nil, err
+	}
+
+	if obj.CreatedAt.IsZero() {
+		obj.CreatedAt = time.Now().UTC()
+	}
+
+	return &tierAssignmentModel{
+		Id:           sql.NullInt64{Int64: int64(obj.Id), Valid: true},
+		OwnerAccount: obj.OwnerAccount,
+		Tier:         uint8(obj.Tier),
+		CreatedAt:    obj.CreatedAt,
+	}, nil
+}
+
+func fromTierAssignmentModel(obj *tierAssignmentModel) *limit.TierAssignment {
+	return &limit.TierAssignment{
+		Id:           uint64(obj.Id.Int64),
+		OwnerAccount: obj.OwnerAccount,
+		Tier:         limit.Tier(obj.Tier),
+		CreatedAt:    obj.CreatedAt,
+	}
+}
+
+func (m *model) dbPut(ctx context.Context, db *sqlx.DB) error {
+	return pgutil.ExecuteInTx(ctx, db, sql.LevelDefault, func(tx *sqlx.Tx) error {
+		query := `INSERT INTO ` + limitTableName + `
+			(tier, owner_account, currency, send_per_transaction, send_daily, micro_payment_min, micro_payment_max, effective_at, expires_at, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+
+			ON CONFLICT (tier, owner_account, currency, effective_at)
+			DO UPDATE
+				SET send_per_transaction = $4, send_daily = $5, micro_payment_min = $6, micro_payment_max = $7, expires_at = $9
+				WHERE ` + limitTableName + `.tier = $1 AND ` + limitTableName + `.owner_account = $2 AND ` + limitTableName + `.currency = $3 AND ` + limitTableName + `.effective_at = $8
+
+			RETURNING ` + allLimitFields
+
+		return tx.QueryRowxContext(
+			ctx,
+			query,
+			m.Tier,
+			m.OwnerAccount,
+			m.Currency,
+			m.SendPerTransaction,
+			m.SendDaily,
+			m.MicroPaymentMin,
+			m.MicroPaymentMax,
+			m.EffectiveAt,
+			m.ExpiresAt,
+			m.CreatedAt,
+		).StructScan(m)
+	})
+}
+
+func dbGetAllByTier(ctx context.Context, db *sqlx.DB, tier limit.Tier) ([]*model, error) {
+	res := []*model{}
+
+	query := `SELECT ` + allLimitFields + ` FROM ` + limitTableName + `
+		WHERE tier = $1 AND owner_account = ''
+		ORDER BY id ASC`
+
+	err := db.SelectContext(ctx, &res, query, tier)
+	if err != nil {
+		return nil, pgutil.CheckNoRows(err, limit.ErrLimitsNotFound)
+	}
+
+	if len(res) == 0 {
+		return nil, limit.ErrLimitsNotFound
+	}
+	return res, nil
+}
+
+func dbGetAllByOwner(ctx context.Context, db *sqlx.DB, owner string) ([]*model, error) {
+	res := []*model{}
+
+	// Overrides are never stored for an empty owner account, so an empty input
+	// can't match tier limits
+	if len(owner) == 0 {
+		return nil, limit.ErrLimitsNotFound
+	}
+
+	query := `SELECT ` + allLimitFields + ` FROM ` + limitTableName + `
+		WHERE owner_account = $1
+		ORDER BY id ASC`
+
+	err := db.SelectContext(ctx, &res, query, owner)
+	if err != nil {
+		return nil, pgutil.CheckNoRows(err, limit.ErrLimitsNotFound)
+	}
+
+	if len(res) == 0 {
+		return nil, limit.ErrLimitsNotFound
+	}
+	return res, nil
+}
+
+func (m *tierAssignmentModel) dbPut(ctx context.Context, db *sqlx.DB) error {
+	return pgutil.ExecuteInTx(ctx, db, sql.LevelDefault, func(tx *sqlx.Tx) error {
+		query := `INSERT INTO ` + tierAssignmentTableName + `
+			(owner_account, tier, created_at)
+			VALUES ($1, $2, $3)
+
+			ON CONFLICT (owner_account)
+			DO UPDATE
+				SET tier = $2
+				WHERE ` + tierAssignmentTableName + `.owner_account = $1
+
+			RETURNING id, owner_account, tier, created_at`
+
+		return tx.QueryRowxContext(
+			ctx,
+			query,
+			m.OwnerAccount,
+			m.Tier,
+			m.CreatedAt,
+		).StructScan(m)
+	})
+}
+
+func dbGetTierAssignment(ctx context.Context, db *sqlx.DB, owner string) (*tierAssignmentModel, error) {
+	res := &tierAssignmentModel{}
+
+	query := `SELECT id, owner_account, tier, created_at FROM ` + tierAssignmentTableName + `
+		WHERE owner_account = $1`
+
+	err := db.GetContext(ctx, res, query, owner)
+	if err != nil {
+		return nil, pgutil.CheckNoRows(err, limit.ErrTierAssignmentNotFound)
+	}
+	return res, nil
+}
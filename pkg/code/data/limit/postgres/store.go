@@ -0,0 +1,93 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/code-payments/code-server/pkg/code/data/limit"
+)
+
+type store struct {
+	db *sqlx.DB
+}
+
+func New(db *sql.DB) limit.Store {
+	return &store{
+		db: sqlx.NewDb(db, "pgx"),
+	}
+}
+
+// Put implements limit.Store.Put
+func (s *store) Put(ctx context.Context, record *limit.Record) error {
+	m, err := toModel(record)
+	if err != nil {
+		return err
+	}
+
+	err = m.dbPut(ctx, s.db)
+	if err != nil {
+		return err
+	}
+
+	res := fromModel(m)
+	res.CopyTo(record)
+
+	return nil
+}
+
+// GetAllByTier implements limit.Store.GetAllByTier
+func (s *store) GetAllByTier(ctx context.Context, tier limit.Tier) ([]*limit.Record, error) {
+	models, err := dbGetAllByTier(ctx, s.db, tier)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*limit.Record, len(models))
+	for i, m := range models {
+		res[i] = fromModel(m)
+	}
+	return res, nil
+}
+
+// GetAllByOwner implements limit.Store.GetAllByOwner
+func (s *store) GetAllByOwner(ctx context.Context, owner string) ([]*limit.Record, error) {
+	models, err := dbGetAllByOwner(ctx, s.db, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*limit.Record, len(models))
+	for i, m := range models {
+		res[i] = fromModel(m)
+	}
+	return res, nil
+}
+
+// PutTierAssignment implements limit.Store.PutTierAssignment
+func (s *store) PutTierAssignment(ctx context.Context, record *limit.TierAssignment) error {
+	m, err := toTierAssignmentModel(record)
+	if err != nil {
+		return err
+	}
+
+	err = m.dbPut(ctx, s.db)
+	if err != nil {
+		return err
+	}
+
+	res := fromTierAssignmentModel(m)
+	res.CopyTo(record)
+
+	return nil
+}
+
+// GetTierAssignment implements limit.Store.GetTierAssignment
+func (s *store) GetTierAssignment(ctx context.Context, owner string) (*limit.TierAssignment, error) {
+	m, err := dbGetTierAssignment(ctx, s.db, owner)
+	if err != nil {
+		return nil, err
+	}
+	return fromTierAssignmentModel(m), nil
+}
@@ -0,0 +1,238 @@
+package limit
+
+import (
+	"errors"
+	"time"
+
+	"github.com/mr-tron/base58"
+
+	currency_lib "github.com/code-payments/code-server/pkg/currency"
+)
+
+type Tier uint8
+
+const (
+	UnknownTier Tier = iota
+	DefaultTier
+	VerifiedMerchantTier
+	StaffTier
+)
+
+// Record configures limits in a single currency. A record either applies to
+// all users within a tier, or overrides limits for a single user when the
+// owner account is set.
+type Record struct {
+	Id uint64
+
+	// Exactly one of Tier or OwnerAccount must be set
+	Tier         Tier
+	OwnerAccount *string
+
+	Currency currency_lib.Code
+
+	SendPerTransaction float64
+	SendDaily          float64
+
+	MicroPaymentMin float64
+	MicroPaymentMax float64
+
+	// EffectiveAt is when the limits start to apply. The limits apply
+	// indefinitely when ExpiresAt is nil.
+	EffectiveAt time.Time
+	ExpiresAt   *time.Time
+
+	CreatedAt time.Time
+}
+
+// TierAssignment explicitly assigns a limit tier to an owner account
+type TierAssignment struct {
+	Id uint64
+
+	OwnerAccount string
+	Tier         Tier
+
+	CreatedAt time.Time
+}
+
+// IsOverride returns whether the record is a per-user override
+func (r *Record) IsOverride() bool {
+	return r.OwnerAccount != nil
+}
+
+// IsEffective returns whether the limits apply at the provided time
+func (r *Record) IsEffective(at time.Time) bool {
+	if at.Before(r.EffectiveAt) {
+		return false
+	}
+	return r.ExpiresAt == nil || at.Before(*r.ExpiresAt)
+}
+
+func (r *Record) Validate() error {
+	if r.OwnerAccount != nil {
+		if r.Tier != UnknownTier {
+			return errors.New("tier cannot be set for a per-user override")
+		}
+
+		if err := validateOwnerAccount(*r.OwnerAccount); err != nil {
+			return err
+		}
+	} else if !r.Tier.IsValid() {
+		return errors.New("tier or owner account is required")
+	}
+
+	if len(r.Currency) == 0 {
+		return errors.New("currency is required")
+	}
+
+	if r.SendPerTransaction <= 0 {
+		return errors.New("send per-transaction limit must be positive")
+	}
+
+	if r.SendDaily < r.SendPerTransaction {
+		return errors.New("send daily limit must be at least the per-transaction limit")
+	}
+
+	if r.MicroPaymentMin <= 0 {
+		return errors.New("micro payment minimum must be positive")
+	}
+
+	if r.MicroPaymentMax < r.MicroPaymentMin {
+		return errors.New("micro payment maximum must be at least the minimum")
+	}
+
+	if r.EffectiveAt.IsZero() {
+		return errors.New("effective date is required")
+	}
+
+	if r.ExpiresAt != nil && !r.ExpiresAt.After(r.EffectiveAt) {
+		return errors.New("expiry must be after the effective date")
+	}
+
+	return nil
+}
+
+func (r *Record) Clone() Record {
+	var ownerAccount *string
+	if r.OwnerAccount != nil {
+		value := *r.OwnerAccount
+		ownerAccount = &value
+	}
+
+	var expiresAt *time.Time
+	if r.ExpiresAt != nil {
+		value := *r.ExpiresAt
+		expiresAt = &value
+	}
+
+	return Record{
+		Id: r.Id,
+
+		Tier:         r.Tier,
+		OwnerAccount: ownerAccount,
+
+		Currency: r.Currency,
+
+		SendPerTransaction: r.SendPerTransaction,
+		SendDaily:          r.SendDaily,
+
+		MicroPaymentMin: r.MicroPaymentMin,
+		MicroPaymentMax: r.MicroPaymentMax,
+
+		EffectiveAt: r.EffectiveAt,
+		ExpiresAt:   expiresAt,
+
+		CreatedAt: r.CreatedAt,
+	}
+}
+
+func (r *Record) CopyTo(dst *Record) {
+	cloned := r.Clone()
+
+	dst.Id = cloned.Id
+
+	dst.Tier = cloned.Tier
+	dst.OwnerAccount = cloned.OwnerAccount
+
+	dst.Currency = cloned.Currency
+
+	dst.SendPerTransaction = cloned.SendPerTransaction
+	dst.SendDaily = cloned.SendDaily
+
+	dst.MicroPaymentMin = cloned.MicroPaymentMin
+	dst.MicroPaymentMax = cloned.MicroPaymentMax
+
+	dst.EffectiveAt = cloned.EffectiveAt
+	dst.ExpiresAt = cloned.ExpiresAt
+
+	dst.CreatedAt = cloned.CreatedAt
+}
+
+func (r *TierAssignment) Validate() error {
+	if err := validateOwnerAccount(r.OwnerAccount); err != nil {
+		return err
+	}
+
+	if !r.Tier.IsValid() {
+		return errors.New("tier is required")
+	}
+
+	return nil
+}
+
+func (r *TierAssignment) Clone() TierAssignment {
+	return TierAssignment{
+		Id: r.Id,
+
+		OwnerAccount: r.OwnerAccount,
+		Tier:         r.Tier,
+
+		CreatedAt: r.CreatedAt,
+	}
+}
+
+func (r *TierAssignment) CopyTo(dst *TierAssignment) {
+	dst.Id = r.Id
+
+	dst.OwnerAccount = r.OwnerAccount
+	dst.Tier = r.Tier
+
+	dst.CreatedAt = r.CreatedAt
+}
+
+func validateOwnerAccount(ownerAccount string) error {
+	decoded, err := base58.Decode(ownerAccount)
+	if err != nil || len(decoded) != 32 {
+		return errors.New("owner account must be a base58 encoded public key")
+	}
+	return nil
+}
+
+func (t Tier) IsValid() bool {
+	switch t {
+	case DefaultTier, VerifiedMerchantTier, StaffTier:
+		return true
+	}
+	return false
+}
+
+func (t Tier) String() string {
+	switch t {
+	case DefaultTier:
+		return "default"
+	case VerifiedMerchantTier:
+		return "verified_merchant"
+	case StaffTier:
+		return "staff"
+	}
+	return "unknown"
+}
+
+// ParseTier parses a tier from its string representation
+func ParseTier(value string) (Tier, bool) {
+	for _, tier := range []Tier{DefaultTier, VerifiedMerchantTier, StaffTier} {
+		if tier.String() == value {
+			return tier, true
+		}
+	}
+	return UnknownTier, false
+}
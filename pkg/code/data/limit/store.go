@@ -0,0 +1,32 @@
+package limit
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrLimitsNotFound         = errors.New("limits not found")
+	ErrTierAssignmentNotFound = errors.New("tier assignment not found")
+)
+
+type Store interface {
+	// Put creates or updates limits for the record's tier or owner account,
+	// currency and effective date. Limit values and the expiry are updated for
+	// existing records.
+	Put(ctx context.Context, record *Record) error
+
+	// GetAllByTier gets all limits, including ones that aren't effective, for
+	// a tier in ascending order of creation
+	GetAllByTier(ctx context.Context, tier Tier) ([]*Record, error)
+
+	// GetAllByOwner gets all per-user limit overrides, including ones that aren't
+	// effective, for an owner account in ascending order of creation
+	GetAllByOwner(ctx context.Context, owner string) ([]*Record, error)
+
+	// PutTierAssignment creates or updates the tier assigned to an owner account
+	PutTierAssignment(ctx context.Context, record *TierAssignment) error
+
+	// GetTierAssignment gets the tier explicitly assigned to an owner account
+	GetTierAssignment(ctx context.Context, owner string) (*TierAssignment, error)
+}
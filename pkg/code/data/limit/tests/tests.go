@@ -0,0 +1,274 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	currency_lib "github.com/code-payments/code-server/pkg/currency"
+	"github.com/code-payments/code-server/pkg/pointer"
+	"github.com/code-payments/code-server/pkg/code/data/limit"
+)
+
+const (
+	testOwner1 = "codeHy87wGD5oMRLG75qKqsSi1vWE3oxNyYmXo5F9YR"
+	testOwner2 = "11111111111111111111111111111111"
+)
+
+func RunTests(t *testing.T, s limit.Store, teardown func()) {
+	for _, tf := range []func(t *testing.T, s limit.Store){
+		testTierRoundTrip,
+		testOverrideRoundTrip,
+		testUpdate,
+		testTierAssignment,
+		testValidation,
+	} {
+		tf(t, s)
+		teardown()
+	}
+}
+
+func testTierRoundTrip(t *testing.T, s limit.Store) {
+	t.Run("testTierRoundTrip", func(t *testing.T) {
+		ctx := context.Background()
+
+		_, err := s.GetAllByTier(ctx, limit.DefaultTier)
+		assert.Equal(t, limit.ErrLimitsNotFound, err)
+
+		effectiveAt := time.Now().Add(-time.Hour)
+		expiresAt := time.Now().Add(time.Hour)
+
+		var expected []*limit.Record
+		for _, record := range []*limit.Record{
+			newTestRecord(limit.DefaultTier, nil, currency_lib.USD, effectiveAt, nil),
+			newTestRecord(limit.DefaultTier, nil, currency_lib.CAD, effectiveAt, nil),
+			newTestRecord(limit.DefaultTier, nil, currency_lib.USD, effectiveAt.Add(time.Minute), &expiresAt),
+			newTestRecord(limit.StaffTier, nil, currency_lib.USD, effectiveAt, nil),
+		} {
+			cloned := record.Clone()
+			require.NoError(t, s.Put(ctx, record))
+			assert.True(t, record.Id > 0)
+
+			cloned.Id = record.Id
+			expected = append(expected, &cloned)
+		}
+
+		actual, err := s.GetAllByTier(ctx, limit.DefaultTier)
+		require.NoError(t, err)
+		require.Len(t, actual, 3)
+		for i := range actual {
+			assertEquivalentRecords(t, expected[i], actual[i])
+			assert.Equal(t, expected[i].Id, actual[i].Id)
+		}
+
+		actual, err = s.GetAllByTier(ctx, limit.StaffTier)
+		require.NoError(t, err)
+		require.Len(t, actual, 1)
+		assertEquivalentRecords(t, expected[3], actual[0])
+
+		_, err = s.GetAllByTier(ctx, limit.VerifiedMerchantTier)
+		assert.Equal(t, limit.ErrLimitsNotFound, err)
+
+		// Tier limits are never returned as overrides
+		_, err = s.GetAllByOwner(ctx, testOwner1)
+		assert.Equal(t, limit.ErrLimitsNotFound, err)
+	})
+}
+
+func testOverrideRoundTrip(t *testing.T, s limit.Store) {
+	t.Run("testOverrideRoundTrip", func(t *testing.T) {
+		ctx := context.Background()
+
+		_, err := s.GetAllByOwner(ctx, testOwner1)
+		assert.Equal(t, limit.ErrLimitsNotFound, err)
+
+		effectiveAt := time.Now()
+
+		var expected []*limit.Record
+		for _, record := range []*limit.Record{
+			newTestRecord(limit.UnknownTier, pointer.String(testOwner1), currency_lib.USD, effectiveAt, nil),
+			newTestRecord(limit.UnknownTier, pointer.String(testOwner2), currency_lib.USD, effectiveAt, nil),
+			newTestRecord(limit.UnknownTier, pointer.String(testOwner1), currency_lib.EUR, effectiveAt, nil),
+		} {
+			cloned := record.Clone()
+			require.NoError(t, s.Put(ctx, record))
+
+			cloned.Id = record.Id
+			expected = append(expected, &cloned)
+		}
+
+		actual, err := s.GetAllByOwner(ctx, testOwner1)
+		require.NoError(t, err)
+		require.Len(t, actual, 2)
+		assertEquivalentRecords(t, expected[0], actual[0])
+		assertEquivalentRecords(t, expected[2], actual[1])
+
+		actual, err = s.GetAllByOwner(ctx, testOwner2)
+		require.NoError(t, err)
+		require.Len(t, actual, 1)
+		assertEquivalentRecords(t, expected[1], actual[0])
+
+		// Overrides are never returned as tier limits
+		_, err = s.GetAllByTier(ctx, limit.UnknownTier)
+		assert.Equal(t, limit.ErrLimitsNotFound, err)
+	})
+}
+
+func testUpdate(t *testing.T, s limit.Store) {
+	t.Run("testUpdate", func(t *testing.T) {
+		ctx := context.Background()
+
+		effectiveAt := time.Now()
+
+		for _, record := range []*limit.Record{
+			newTestRecord(limit.DefaultTier, nil, currency_lib.USD, effectiveAt, nil),
+			newTestRecord(limit.UnknownTier, pointer.String(testOwner1), currency_lib.USD, effectiveAt, nil),
+		} {
+			require.NoError(t, s.Put(ctx, record))
+
+			expiresAt := effectiveAt.Add(24 * time.Hour)
+			update := record.Clone()
+			update.Id = 0
+			update.SendPerTransaction *= 2
+			update.SendDaily *= 2
+			update.MicroPaymentMin *= 2
+			update.MicroPaymentMax *= 2
+			update.ExpiresAt = &expiresAt
+			update.CreatedAt = time.Now().Add(time.Hour)
+			require.NoError(t, s.Put(ctx, &update))
+			assert.Equal(t, record.Id, update.Id)
+			assert.Equal(t, record.CreatedAt.Unix(), update.CreatedAt.Unix())
+
+			var actual []*limit.Record
+			var err error
+			if record.IsOverride() {
+				actual, err = s.GetAllByOwner(ctx, *record.OwnerAccount)
+			} else {
+				actual, err = s.GetAllByTier(ctx, record.Tier)
+			}
+			require.NoError(t, err)
+			require.Len(t, actual, 1)
+			assertEquivalentRecords(t, &update, actual[0])
+		}
+	})
+}
+
+func testTierAssignment(t *testing.T, s limit.Store) {
+	t.Run("testTierAssignment", func(t *testing.T) {
+		ctx := context.Background()
+
+		_, err := s.GetTierAssignment(ctx, testOwner1)
+		assert.Equal(t, limit.ErrTierAssignmentNotFound, err)
+
+		expected := &limit.TierAssignment{
+			OwnerAccount: testOwner1,
+			Tier:         limit.VerifiedMerchantTier,
+			CreatedAt:    time.Now(),
+		}
+		require.NoError(t, s.PutTierAssignment(ctx, expected))
+		assert.True(t, expected.Id > 0)
+
+		actual, err := s.GetTierAssignment(ctx, testOwner1)
+		require.NoError(t, err)
+		assert.Equal(t, expected.Id, actual.Id)
+		assert.Equal(t, expected.OwnerAccount, actual.OwnerAccount)
+		assert.Equal(t, expected.Tier, actual.Tier)
+		assert.Equal(t, expected.CreatedAt.Unix(), actual.CreatedAt.Unix())
+
+		update := &limit.TierAssignment{
+			OwnerAccount: testOwner1,
+			Tier:         limit.StaffTier,
+			CreatedAt:    time.Now().Add(time.Hour),
+		}
+		require.NoError(t, s.PutTierAssignment(ctx, update))
+		assert.Equal(t, expected.Id, update.Id)
+
+		actual, err = s.GetTierAssignment(ctx, testOwner1)
+		require.NoError(t, err)
+		assert.Equal(t, limit.StaffTier, actual.Tier)
+		assert.Equal(t, expected.CreatedAt.Unix(), actual.CreatedAt.Unix())
+
+		_, err = s.GetTierAssignment(ctx, testOwner2)
+		assert.Equal(t, limit.ErrTierAssignmentNotFound, err)
+	})
+}
+
+func testValidation(t *testing.T, s limit.Store) {
+	t.Run("testValidation", func(t *testing.T) {
+		ctx := context.Background()
+
+		effectiveAt := time.Now()
+		expiresAt := effectiveAt.Add(-time.Minute)
+
+		for _, invalid := range []func(r *limit.Record){
+			func(r *limit.Record) { r.Tier = limit.UnknownTier },
+			func(r *limit.Record) { r.OwnerAccount = pointer.String(testOwner1) },
+			func(r *limit.Record) { r.Tier = limit.UnknownTier; r.OwnerAccount = pointer.String("invalid") },
+			func(r *limit.Record) { r.Currency = "" },
+			func(r *limit.Record) { r.SendPerTransaction = 0 },
+			func(r *limit.Record) { r.SendDaily = r.SendPerTransaction / 2 },
+			func(r *limit.Record) { r.MicroPaymentMin = 0 },
+			func(r *limit.Record) { r.MicroPaymentMax = r.MicroPaymentMin / 2 },
+			func(r *limit.Record) { r.EffectiveAt = time.Time{} },
+			func(r *limit.Record) { r.ExpiresAt = &expiresAt },
+		} {
+			record := newTestRecord(limit.DefaultTier, nil, currency_lib.USD, effectiveAt, nil)
+			invalid(record)
+			assert.Error(t, s.Put(ctx, record))
+		}
+
+		_, err := s.GetAllByTier(ctx, limit.DefaultTier)
+		assert.Equal(t, limit.ErrLimitsNotFound, err)
+
+		for _, invalid := range []*limit.TierAssignment{
+			{OwnerAccount: "invalid", Tier: limit.StaffTier},
+			{OwnerAccount: testOwner1, Tier: limit.UnknownTier},
+		} {
+			assert.Error(t, s.PutTierAssignment(ctx, invalid))
+		}
+
+		_, err = s.GetTierAssignment(ctx, testOwner1)
+		assert.Equal(t, limit.ErrTierAssignmentNotFound, err)
+	})
+}
+
+func newTestRecord(tier limit.Tier, owner *string, currency currency_lib.Code, effectiveAt time.Time, expiresAt *time.Time) *limit.Record {
+	return &limit.Record{
+		Tier:         tier,
+		OwnerAccount: owner,
+
+		Currency: currency,
+
+		SendPerTransaction: 250,
+		SendDaily:          1_000,
+
+		MicroPaymentMin: 0.05,
+		MicroPaymentMax: 1,
+
+		EffectiveAt: effectiveAt,
+		ExpiresAt:   expiresAt,
+
+		CreatedAt: time.Now(),
+	}
+}
+
+func assertEquivalentRecords(t *testing.T, obj1, obj2 *limit.Record) {
+	assert.Equal(t, obj1.Tier, obj2.Tier)
+	assert.EqualValues(t, obj1.OwnerAccount, obj2.OwnerAccount)
+	assert.Equal(t, obj1.Currency, obj2.Currency)
+	assert.Equal(t, obj1.SendPerTransaction, obj2.SendPerTransaction)
+	assert.Equal(t, obj1.SendDaily, obj2.SendDaily)
+	assert.Equal(t, obj1.MicroPaymentMin, obj2.MicroPaymentMin)
+	assert.Equal(t, obj1.MicroPaymentMax, obj2.MicroPaymentMax)
+	assert.Equal(t, obj1.EffectiveAt.Unix(), obj2.EffectiveAt.Unix())
+	if obj1.ExpiresAt == nil {
+		assert.Nil(t, obj2.ExpiresAt)
+	} else {
+		require.NotNil(t, obj2.ExpiresAt)
+		assert.Equal(t, obj1.ExpiresAt.Unix(), obj2.ExpiresAt.Unix())
+	}
+	assert.Equal(t, obj1.CreatedAt.Unix(), obj2.CreatedAt.Unix())
+}
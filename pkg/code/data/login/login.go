@@ -2,9 +2,18 @@ package login
 
 import (
 	"errors"
+	"fmt"
 	"time"
 )
 
+const (
+	// MaxOwnersPerAppInstall is the maximum number of owners that can be logged
+	// into a single app install
+	MaxOwnersPerAppInstall = 10
+)
+
+// MultiRecord is the ordered set of owners logged into an app install. An owner
+// can only be logged into a single app install at a time.
 type MultiRecord struct {
 	AppInstallId  string
 	Owners        []string
@@ -22,15 +31,20 @@ func (r *MultiRecord) Validate() error {
 		return errors.New("app install id is required")
 	}
 
-	// todo: If we upgrade this, ensure to update tests to verify implementations
-	if len(r.Owners) > 1 {
-		return errors.New("at most one owner can be associated to an app install")
+	if len(r.Owners) > MaxOwnersPerAppInstall {
+		return fmt.Errorf("at most %d owners can be associated to an app install", MaxOwnersPerAppInstall)
 	}
 
+	seen := make(map[string]struct{})
 	for _, owner := range r.Owners {
 		if len(owner) == 0 {
 			return errors.New("owner is required when set")
 		}
+
+		if _, ok := seen[owner]; ok {
+			return errors.New("owners must be unique")
+		}
+		seen[owner] = struct{}{}
 	}
 
 	return nil
@@ -57,7 +71,7 @@ func (r *Record) Validate() error {
 		return errors.New("app install id is required")
 	}
 
-	if len(r.Owner) > 1 {
+	if len(r.Owner) == 0 {
 		return errors.New("owner is required")
 	}
 
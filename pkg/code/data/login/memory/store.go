@@ -20,6 +20,10 @@ func New() login.Store {
 
 // Save implements login.Store.Save
 func (s *store) Save(_ context.Context, data *login.MultiRecord) error {
+	if err := data.Validate(); err != nil {
+		return err
+	}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
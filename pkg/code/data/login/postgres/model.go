@@ -21,6 +21,7 @@ type model struct {
 
 	AppInstallId string `db:"app_install_id"`
 	Owner        string `db:"owner"`
+	Position     int    `db:"position"`
 
 	LastUpdatedAt time.Time `db:"last_updated_at"`
 }
@@ -31,10 +32,11 @@ func toModels(item *login.MultiRecord) ([]*model, error) {
 	}
 
 	var res []*model
-	for _, owner := range item.Owners {
+	for i, owner := range item.Owners {
 		res = append(res, &model{
 			AppInstallId:  item.AppInstallId,
 			Owner:         owner,
+			Position:      i,
 			LastUpdatedAt: item.LastUpdatedAt,
 		})
 	}
@@ -75,28 +77,27 @@ func fromModels(appInstallId string, models []*model) (*login.MultiRecord, error
 	return res, nil
 }
 
+// dbSaveInTx inserts the login, and must be called after all existing logins for
+// the app install have been deleted
 func (m *model) dbSaveInTx(ctx context.Context, tx *sqlx.Tx) error {
 	m.LastUpdatedAt = time.Now()
 
-	err := dbDeleteAllByInstallIdInTx(ctx, tx, m.AppInstallId)
-	if err != nil {
-		return err
-	}
-
-	err = dbDeleteAllByOwnerInTx(ctx, tx, m.Owner)
+	// An owner can only be logged into a single app install
+	err := dbDeleteAllByOwnerInTx(ctx, tx, m.Owner)
 	if err != nil {
 		return err
 	}
 
 	query := `INSERT INTO ` + tableName + `
-		(app_install_id, owner, last_updated_at)
-		VALUES ($1, $2, $3)
-		RETURNING id, app_install_id, owner, last_updated_at`
+		(app_install_id, owner, position, last_updated_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, app_install_id, owner, position, last_updated_at`
 	_, err = tx.ExecContext(
 		ctx,
 		query,
 		m.AppInstallId,
 		m.Owner,
+		m.Position,
 		m.LastUpdatedAt,
 	)
 	return err
@@ -127,8 +128,9 @@ func dbDeleteAllByOwnerInTx(ctx context.Context, tx *sqlx.Tx, owner string) erro
 func dbGetAllByInstallId(ctx context.Context, db *sqlx.DB, appInstallId string) ([]*model, error) {
 	var res []*model
 
-	query := `SELECT id, app_install_id, owner, last_updated_at FROM ` + tableName + `
-		WHERE app_install_id = $1`
+	query := `SELECT id, app_install_id, owner, position, last_updated_at FROM ` + tableName + `
+		WHERE app_install_id = $1
+		ORDER BY position ASC`
 	err := db.SelectContext(ctx, &res, query, appInstallId)
 	if err != nil {
 		return nil, pgutil.CheckNoRows(err, login.ErrLoginNotFound)
@@ -142,7 +144,7 @@ func dbGetAllByInstallId(ctx context.Context, db *sqlx.DB, appInstallId string)
 func dbGetLatestByOwner(ctx context.Context, db *sqlx.DB, owner string) (*model, error) {
 	var res model
 
-	query := `SELECT id, app_install_id, owner, last_updated_at FROM ` + tableName + `
+	query := `SELECT id, app_install_id, owner, position, last_updated_at FROM ` + tableName + `
 		WHERE owner = $1`
 	err := db.GetContext(ctx, &res, query, owner)
 	if err != nil {
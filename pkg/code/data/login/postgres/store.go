@@ -22,9 +22,6 @@ func New(db *sql.DB) login.Store {
 }
 
 // Save implements login.Store.Save
-//
-// todo: An awkward implementation, but enables a quick migration to multi-device
-// logins if that every becomes a thing.
 func (s *store) Save(ctx context.Context, record *login.MultiRecord) error {
 	models, err := toModels(record)
 	if err != nil {
@@ -33,15 +30,13 @@ func (s *store) Save(ctx context.Context, record *login.MultiRecord) error {
 
 	var newRecord *login.MultiRecord
 	err = pgutil.ExecuteInTx(ctx, s.db, sql.LevelDefault, func(tx *sqlx.Tx) error {
-		for _, model := range models {
-			err := model.dbSaveInTx(ctx, tx)
-			if err != nil {
-				return err
-			}
+		err := dbDeleteAllByInstallIdInTx(ctx, tx, record.AppInstallId)
+		if err != nil {
+			return err
 		}
 
-		if len(models) == 0 {
-			err = dbDeleteAllByInstallIdInTx(ctx, tx, record.AppInstallId)
+		for _, model := range models {
+			err := model.dbSaveInTx(ctx, tx)
 			if err != nil {
 				return err
 			}
@@ -29,10 +29,11 @@ const (
 
 		app_install_id TEXT NOT NULL,
 		owner TEXT NOT NULL,
+		position INTEGER NOT NULL DEFAULT 0,
 
 		last_updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
 
-		CONSTRAINT codewallet__core_applogin__uniq__app_install_id UNIQUE (app_install_id),
+		CONSTRAINT codewallet__core_applogin__uniq__app_install_id__and__position UNIQUE (app_install_id, position),
 		CONSTRAINT codewallet__core_applogin__uniq__owner UNIQUE (owner)
 	);
 	`
@@ -2,6 +2,7 @@ package tests
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
@@ -14,6 +15,8 @@ import (
 func RunTests(t *testing.T, s login.Store, teardown func()) {
 	for _, tf := range []func(t *testing.T, s login.Store){
 		testHappyPath,
+		testMultipleOwners,
+		testInvalidRecords,
 	} {
 		tf(t, s)
 		teardown()
@@ -140,3 +143,92 @@ func testHappyPath(t *testing.T, s login.Store) {
 		assert.Equal(t, login.ErrLoginNotFound, err)
 	})
 }
+
+func testMultipleOwners(t *testing.T, s login.Store) {
+	t.Run("testMultipleOwners", func(t *testing.T) {
+		ctx := context.Background()
+
+		require.NoError(t, s.Save(ctx, &login.MultiRecord{
+			AppInstallId: "app-install-1",
+			Owners:       []string{"owner3", "owner1", "owner2"},
+		}))
+
+		multiActual, err := s.GetAllByInstallId(ctx, "app-install-1")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"owner3", "owner1", "owner2"}, multiActual.Owners)
+
+		for _, owner := range []string{"owner1", "owner2", "owner3"} {
+			singleActual, err := s.GetLatestByOwner(ctx, owner)
+			require.NoError(t, err)
+			assert.Equal(t, "app-install-1", singleActual.AppInstallId)
+			assert.Equal(t, owner, singleActual.Owner)
+		}
+
+		// Reordering and removing owners
+		require.NoError(t, s.Save(ctx, &login.MultiRecord{
+			AppInstallId: "app-install-1",
+			Owners:       []string{"owner2", "owner3"},
+		}))
+
+		multiActual, err = s.GetAllByInstallId(ctx, "app-install-1")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"owner2", "owner3"}, multiActual.Owners)
+
+		_, err = s.GetLatestByOwner(ctx, "owner1")
+		assert.Equal(t, login.ErrLoginNotFound, err)
+
+		// Logging an owner into another app install removes it from the previous one
+		require.NoError(t, s.Save(ctx, &login.MultiRecord{
+			AppInstallId: "app-install-2",
+			Owners:       []string{"owner1", "owner3"},
+		}))
+
+		multiActual, err = s.GetAllByInstallId(ctx, "app-install-1")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"owner2"}, multiActual.Owners)
+
+		multiActual, err = s.GetAllByInstallId(ctx, "app-install-2")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"owner1", "owner3"}, multiActual.Owners)
+
+		singleActual, err := s.GetLatestByOwner(ctx, "owner3")
+		require.NoError(t, err)
+		assert.Equal(t, "app-install-2", singleActual.AppInstallId)
+
+		singleActual, err = s.GetLatestByOwner(ctx, "owner2")
+		require.NoError(t, err)
+		assert.Equal(t, "app-install-1", singleActual.AppInstallId)
+	})
+}
+
+func testInvalidRecords(t *testing.T, s login.Store) {
+	t.Run("testInvalidRecords", func(t *testing.T) {
+		ctx := context.Background()
+
+		var tooManyOwners []string
+		for i := 0; i < login.MaxOwnersPerAppInstall+1; i++ {
+			tooManyOwners = append(tooManyOwners, fmt.Sprintf("owner%d", i))
+		}
+
+		for _, invalid := range []*login.MultiRecord{
+			{AppInstallId: "", Owners: []string{"owner1"}},
+			{AppInstallId: "app-install-1", Owners: []string{"owner1", ""}},
+			{AppInstallId: "app-install-1", Owners: []string{"owner1", "owner2", "owner1"}},
+			{AppInstallId: "app-install-1", Owners: tooManyOwners},
+		} {
+			assert.Error(t, s.Save(ctx, invalid))
+		}
+
+		_, err := s.GetAllByInstallId(ctx, "app-install-1")
+		assert.Equal(t, login.ErrLoginNotFound, err)
+
+		require.NoError(t, s.Save(ctx, &login.MultiRecord{
+			AppInstallId: "app-install-1",
+			Owners:       tooManyOwners[:login.MaxOwnersPerAppInstall],
+		}))
+
+		multiActual, err := s.GetAllByInstallId(ctx, "app-install-1")
+		require.NoError(t, err)
+		assert.Equal(t, tooManyOwners[:login.MaxOwnersPerAppInstall], multiActual.Owners)
+	})
+}
@@ -0,0 +1,287 @@
+package mandate
+
+import (
+	"errors"
+	"time"
+
+	"github.com/code-payments/code-server/pkg/currency"
+)
+
+type State uint8
+
+const (
+	StateUnknown State = iota
+	StatePendingAuthorization
+	StateActive
+	StateCompleted
+	StateCancelled
+	StateFailed
+)
+
+type Cadence uint8
+
+const (
+	CadenceUnknown Cadence = iota
+	CadenceDaily
+	CadenceWeekly
+	CadenceMonthly
+)
+
+// Record is a recurring payment mandate from an owner's primary account to a
+// destination. Each payment is pre-signed by the owner when the mandate is
+// authorized, so the Kin quantity is locked in at creation.
+type Record struct {
+	Id uint64
+
+	MandateId string
+
+	OwnerAccount       string
+	SourceTokenAccount string
+
+	DestinationOwnerAccount string
+	DestinationTokenAccount string
+
+	// Amount of each payment
+	ExchangeCurrency currency.Code
+	ExchangeRate     float64
+	NativeAmount     float64
+	Quantity         uint64
+
+	Cadence Cadence
+
+	// MaxPayments caps the number of payments that can be made
+	MaxPayments uint32
+
+	// PaymentsProcessed is the number of payments that have been attempted. It
+	// is also the index of the next payment.
+	PaymentsProcessed   uint32
+	PaymentsFailed      uint32
+	ConsecutiveFailures uint32
+
+	FirstPaymentAt time.Time
+	NextPaymentAt  time.Time
+
+	// Signature is the owner's signature over the mandate's terms
+	Signature string
+
+	State State
+
+	// Version is incremented on every update to guard against concurrent
+	// modifications
+	Version uint64
+
+	CreatedAt     time.Time
+	LastUpdatedAt time.Time
+}
+
+// GetPaymentTime gets the time the payment at the provided index is due
+func (r *Record) GetPaymentTime(index uint32) time.Time {
+	return r.Cadence.Add(r.FirstPaymentAt, int(index))
+}
+
+// HasRemainingPayments determines whether the mandate has payments left to
+// process
+func (r *Record) HasRemainingPayments() bool {
+	return r.PaymentsProcessed < r.MaxPayments
+}
+
+func (r *Record) Validate() error {
+	if len(r.MandateId) == 0 {
+		return errors.New("mandate id is required")
+	}
+
+	if len(r.OwnerAccount) == 0 {
+		return errors.New("owner account is required")
+	}
+
+	if len(r.SourceTokenAccount) == 0 {
+		return errors.New("source token account is required")
+	}
+
+	if len(r.DestinationOwnerAccount) == 0 {
+		return errors.New("destination owner account is required")
+	}
+
+	if len(r.DestinationTokenAccount) == 0 {
+		return errors.New("destination token account is required")
+	}
+
+	if r.SourceTokenAccount == r.DestinationTokenAccount {
+		return errors.New("source and destination token accounts must be different")
+	}
+
+	if len(r.ExchangeCurrency) == 0 {
+		return errors.New("exchange currency is required")
+	}
+
+	if r.ExchangeRate <= 0 {
+		return errors.New("exchange rate must be positive")
+	}
+
+	if r.NativeAmount <= 0 {
+		return errors.New("native amount must be positive")
+	}
+
+	if r.Quantity == 0 {
+		return errors.New("quantity must be positive")
+	}
+
+	if r.Cadence == CadenceUnknown {
+		return errors.New("cadence is required")
+	}
+
+	if r.MaxPayments == 0 {
+		return errors.New("max payments must be positive")
+	}
+
+	if r.PaymentsProcessed > r.MaxPayments {
+		return errors.New("payments processed exceeds max payments")
+	}
+
+	if r.PaymentsFailed > r.PaymentsProcessed {
+		return errors.New("payments failed exceeds payments processed")
+	}
+
+	if r.ConsecutiveFailures > r.PaymentsFailed {
+		return errors.New("consecutive failures exceeds payments failed")
+	}
+
+	if r.FirstPaymentAt.IsZero() || r.NextPaymentAt.IsZero() {
+		return errors.New("payment times are required")
+	}
+
+	if len(r.Signature) == 0 {
+		return errors.New("signature is required")
+	}
+
+	if r.State == StateUnknown {
+		return errors.New("state is required")
+	}
+
+	return nil
+}
+
+func (r *Record) Clone() Record {
+	return Record{
+		Id: r.Id,
+
+		MandateId: r.MandateId,
+
+		OwnerAccount:       r.OwnerAccount,
+		SourceTokenAccount: r.SourceTokenAccount,
+
+		DestinationOwnerAccount: r.DestinationOwnerAccount,
+		DestinationTokenAccount: r.DestinationTokenAccount,
+
+		ExchangeCurrency: r.ExchangeCurrency,
+		ExchangeRate:     r.ExchangeRate,
+		NativeAmount:     r.NativeAmount,
+		Quantity:         r.Quantity,
+
+		Cadence: r.Cadence,
+
+		MaxPayments: r.MaxPayments,
+
+		PaymentsProcessed:   r.PaymentsProcessed,
+		PaymentsFailed:      r.PaymentsFailed,
+		ConsecutiveFailures: r.ConsecutiveFailures,
+
+		FirstPaymentAt: r.FirstPaymentAt,
+		NextPaymentAt:  r.NextPaymentAt,
+
+		Signature: r.Signature,
+
+		State: r.State,
+
+		Version: r.Version,
+
+		CreatedAt:     r.CreatedAt,
+		LastUpdatedAt: r.LastUpdatedAt,
+	}
+}
+
+func (r *Record) CopyTo(dst *Record) {
+	dst.Id = r.Id
+
+	dst.MandateId = r.MandateId
+
+	dst.OwnerAccount = r.OwnerAccount
+	dst.SourceTokenAccount = r.SourceTokenAccount
+
+	dst.DestinationOwnerAccount = r.DestinationOwnerAccount
+	dst.DestinationTokenAccount = r.DestinationTokenAccount
+
+	dst.ExchangeCurrency = r.ExchangeCurrency
+	dst.ExchangeRate = r.ExchangeRate
+	dst.NativeAmount = r.NativeAmount
+	dst.Quantity = r.Quantity
+
+	dst.Cadence = r.Cadence
+
+	dst.MaxPayments = r.MaxPayments
+
+	dst.PaymentsProcessed = r.PaymentsProcessed
+	dst.PaymentsFailed = r.PaymentsFailed
+	dst.ConsecutiveFailures = r.ConsecutiveFailures
+
+	dst.FirstPaymentAt = r.FirstPaymentAt
+	dst.NextPaymentAt = r.NextPaymentAt
+
+	dst.Signature = r.Signature
+
+	dst.State = r.State
+
+	dst.Version = r.Version
+
+	dst.CreatedAt = r.CreatedAt
+	dst.LastUpdatedAt = r.LastUpdatedAt
+}
+
+// Add adds n periods of the cadence to t
+func (c Cadence) Add(t time.Time, n int) time.Time {
+	switch c {
+	case CadenceDaily:
+		return t.AddDate(0, 0, n)
+	case CadenceWeekly:
+		return t.AddDate(0, 0, 7*n)
+	case CadenceMonthly:
+		return t.AddDate(0, n, 0)
+	}
+	return t
+}
+
+func (c Cadence) String() string {
+	switch c {
+	case CadenceDaily:
+		return "daily"
+	case CadenceWeekly:
+		return "weekly"
+	case CadenceMonthly:
+		return "monthly"
+	}
+	return "unknown"
+}
+
+func (s State) IsTerminal() bool {
+	switch s {
+	case StateCompleted, StateCancelled, StateFailed:
+		return true
+	}
+	return false
+}
+
+func (s State) String() string {
+	switch s {
+	case StatePendingAuthorization:
+		return "pending_authorization"
+	case StateActive:
+		return "active"
+	case StateCompleted:
+		return "completed"
+	case StateCancelled:
+		return "cancelled"
+	case StateFailed:
+		return "failed"
+	}
+	return "unknown"
+}
@@ -0,0 +1,188 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/code-payments/code-server/pkg/code/data/mandate"
+)
+
+type store struct {
+	mu      sync.Mutex
+	records []*mandate.Record
+	last    uint64
+}
+
+func New() mandate.Store {
+	return &store{
+		records: make([]*mandate.Record, 0),
+	}
+}
+
+func (s *store) reset() {
+	s.mu.Lock()
+	s.records = make([]*mandate.Record, 0)
+	s.last = 0
+	s.mu.Unlock()
+}
+
+// Create implements mandate.Store.Create
+func (s *store) Create(_ context.Context, data *mandate.Record) error {
+	if err := data.Validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if item := s.find(data.MandateId); item != nil {
+		return mandate.ErrMandateExists
+	}
+
+	s.last++
+
+	data.Id = s.last
+	data.Version = 1
+	if data.CreatedAt.IsZero() {
+		data.CreatedAt = time.Now()
+	}
+	data.LastUpdatedAt = data.CreatedAt
+
+	cloned := data.Clone()
+	s.records = append(s.records, &cloned)
+
+	return nil
+}
+
+// Update implements mandate.Store.Update
+func (s *store) Update(_ context.Context, data *mandate.Record) error {
+	if err := data.Validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item := s.find(data.MandateId)
+	if item == nil {
+		return mandate.ErrMandateNotFound
+	}
+
+	if item.Version != data.Version {
+		return mandate.ErrStaleVersion
+	}
+
+	item.PaymentsProcessed = data.PaymentsProcessed
+	item.PaymentsFailed = data.PaymentsFailed
+	item.ConsecutiveFailures = data.ConsecutiveFailures
+	item.NextPaymentAt = data.NextPaymentAt
+	item.State = data.State
+	item.Version++
+	item.LastUpdatedAt = time.Now()
+
+	item.CopyTo(data)
+
+	return nil
+}
+
+// Get implements mandate.Store.Get
+func (s *store) Get(_ context.Context, mandateId string) (*mandate.Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item := s.find(mandateId)
+	if item == nil {
+		return nil, mandate.ErrMandateNotFound
+	}
+
+	cloned := item.Clone()
+	return &cloned, nil
+}
+
+// GetAllByOwner implements mandate.Store.GetAllByOwner
+func (s *store) GetAllByOwner(_ context.Context, owner string) ([]*mandate.Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var res []*mandate.Record
+	for _, item := range s.records {
+		if item.OwnerAccount == owner {
+			cloned := item.Clone()
+			res = append(res, &cloned)
+		}
+	}
+
+	if len(res) == 0 {
+		return nil, mandate.ErrMandateNotFound
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Id < res[j].Id
+	})
+	return res, nil
+}
+
+// GetAllDue implements mandate.Store.GetAllDue
+func (s *store) GetAllDue(_ context.Context, at time.Time, limit uint64) ([]*mandate.Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var res []*mandate.Record
+	for _, item := range s.records {
+		if item.State == mandate.StateActive && !item.NextPaymentAt.After(at) {
+			cloned := item.Clone()
+			res = append(res, &cloned)
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].NextPaymentAt.Equal(res[j].NextPaymentAt) {
+			return res[i].Id < res[j].Id
+		}
+		return res[i].NextPaymentAt.Before(res[j].NextPaymentAt)
+	})
+
+	return limitResults(res, limit)
+}
+
+// GetAllByStateCreatedBefore implements mandate.Store.GetAllByStateCreatedBefore
+func (s *store) GetAllByStateCreatedBefore(_ context.Context, state mandate.State, before time.Time, limit uint64) ([]*mandate.Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var res []*mandate.Record
+	for _, item := range s.records {
+		if item.State == state && item.CreatedAt.Before(before) {
+			cloned := item.Clone()
+			res = append(res, &cloned)
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Id < res[j].Id
+	})
+
+	return limitResults(res, limit)
+}
+
+func (s *store) find(mandateId string) *mandate.Record {
+	for _, item := range s.records {
+		if item.MandateId == mandateId {
+			return item
+		}
+	}
+	return nil
+}
+
+func limitResults(res []*mandate.Record, limit uint64) ([]*mandate.Record, error) {
+	if len(res) == 0 {
+		return nil, mandate.ErrMandateNotFound
+	}
+
+	if uint64(len(res)) > limit {
+		res = res[:limit]
+	}
+	return res, nil
+}
@@ -0,0 +1,262 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	pgutil "github.com/code-payments/code-server/pkg/database/postgres"
+	"github.com/code-payments/code-server/pkg/code/data/mandate"
+	"github.com/code-payments/code-server/pkg/currency"
+)
+
+const (
+	tableName = "codewallet__core_mandate"
+
+	allColumns = `id, mandate_id, owner_account, source_token_account, destination_owner_account, destination_token_account, exchange_currency, exchange_rate, native_amount, quantity, cadence, max_payments, payments_processed, payments_failed, consecutive_failures, first_payment_at, next_payment_at, signature, state, version, created_at, last_updated_at`
+)
+
+type model struct {
+	Id sql.NullInt64 `db:"id"`
+
+	MandateId string `db:"mandate_id"`
+
+	OwnerAccount       string `db:"owner_account"`
+	SourceTokenAccount string `db:"source_token_account"`
+
+	DestinationOwnerAccount string `db:"destination_owner_account"`
+	DestinationTokenAccount string `db:"destination_token_account"`
+
+	ExchangeCurrency string  `db:"exchange_currency"`
+	ExchangeRate     float64 `db:"exchange_rate"`
+	NativeAmount     float64 `db:"native_amount"`
+	Quantity         uint64  `db:"quantity"`
+
+	Cadence uint8 `db:"cadence"`
+
+	MaxPayments uint32 `db:"max_payments"`
+
+	PaymentsProcessed   uint32 `db:"payments_processed"`
+	PaymentsFailed      uint32 `db:"payments_failed"`
+	ConsecutiveFailures uint32 `db:"consecutive_failures"`
+
+	FirstPaymentAt time.Time `db:"first_payment_at"`
+	NextPaymentAt  time.Time `db:"next_payment_at"`
+
+	Signature string `db:"signature"`
+
+	State uint8 `db:"state"`
+
+	Version uint64 `db:"version"`
+
+	CreatedAt     time.Time `db:"created_at"`
+	LastUpdatedAt time.Time `db:"last_updated_at"`
+}
+
+func toModel(obj *mandate.Record) (*model, error) {
+	if err := obj.Validate(); err != nil {
+		return nil, err
+	}
+
+	if obj.CreatedAt.IsZero() {
+		obj.CreatedAt = time.Now().UTC()
+	}
+
+	return &model{
+		Id:                      sql.NullInt64{Int64: int64(obj.Id), Valid: true},
+		MandateId:               obj.MandateId,
+		OwnerAccount:            obj.OwnerAccount,
+		SourceTokenAccount:      obj.SourceTokenAccount,
+		DestinationOwnerAccount: obj.DestinationOwnerAccount,
+		DestinationTokenAccount: obj.DestinationTokenAccount,
+		ExchangeCurrency:        string(obj.ExchangeCurrency),
+		ExchangeRate:            obj.ExchangeRate,
+		NativeAmount:            obj.NativeAmount,
+		Quantity:                obj.Quantity,
+		Cadence:                 uint8(obj.Cadence),
+		MaxPayments:             obj.MaxPayments,
+		PaymentsProcessed:       obj.PaymentsProcessed,
+		PaymentsFailed:          obj.PaymentsFailed,
+		ConsecutiveFailures:     obj.ConsecutiveFailures,
+		FirstPaymentAt:          obj.FirstPaymentAt.UTC(),
+		NextPaymentAt:           obj.NextPaymentAt.UTC(),
+		Signature:               obj.Signature,
+		State:                   uint8(obj.State),
+		Version:                 obj.Version,
+		CreatedAt:               obj.CreatedAt,
+		LastUpdatedAt:           obj.LastUpdatedAt,
+	}, nil
+}
+
+func fromModel(obj *model) *mandate.Record {
+	return &mandate.Record{
+		Id:                      uint64(obj.Id.Int64),
+		MandateId:               obj.MandateId,
+		OwnerAccount:            obj.OwnerAccount,
+		SourceTokenAccount:      obj.SourceTokenAccount,
+		DestinationOwnerAccount: obj.DestinationOwnerAccount,
+		DestinationTokenAccount: obj.DestinationTokenAccount,
+		ExchangeCurrency:        currency.Code(obj.ExchangeCurrency),
+		ExchangeRate:            obj.ExchangeRate,
+		NativeAmount:            obj.NativeAmount,
+		Quantity:                obj.Quantity,
+		Cadence:                 mandate.Cadence(obj.Cadence),
+		MaxPayments:             obj.MaxPayments,
+		PaymentsProcessed:       obj.PaymentsProcessed,
+		PaymentsFailed:          obj.PaymentsFailed,
+		ConsecutiveFailures:     obj.ConsecutiveFailures,
+		FirstPaymentAt:          obj.FirstPaymentAt,
+		NextPaymentAt:           obj.NextPaymentAt,
+		Signature:               obj.Signature,
+		State:                   mandate.State(obj.State),
+		Version:                 obj.Version,
+		CreatedAt:               obj.CreatedAt,
+		LastUpdatedAt:           obj.LastUpdatedAt,
+	}
+}
+
+func (m *model) dbCreate(ctx context.Context, db *sqlx.DB) error {
+	return pgutil.ExecuteInTx(ctx, db, sql.LevelDefault, func(tx *sqlx.Tx) error {
+		query := `INSERT INTO ` + tableName + `
+			(mandate_id, owner_account, source_token_account, destination_owner_account, destination_token_account, exchange_currency, exchange_rate, native_amount, quantity, cadence, max_payments, payments_processed, payments_failed, consecutive_failures, first_payment_at, next_payment_at, signature, state, version, created_at, last_updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, 1, $19, $19)
+
+			ON CONFLICT DO NOTHING
+
+			RETURNING ` + allColumns
+
+		err := tx.QueryRowxContext(
+			ctx,
+			query,
+			m.MandateId,
+			m.OwnerAccount,
+			m.SourceTokenAccount,
+			m.DestinationOwnerAccount,
+			m.DestinationTokenAccount,
+			m.ExchangeCurrency,
+			m.ExchangeRate,
+			m.NativeAmount,
+			m.Quantity,
+			m.Cadence,
+			m.MaxPayments,
+			m.PaymentsProcessed,
+			m.PaymentsFailed,
+			m.ConsecutiveFailures,
+			m.FirstPaymentAt,
+			m.NextPaymentAt,
+			m.Signature,
+			m.State,
+			m.CreatedAt,
+		).StructScan(m)
+		return pgutil.CheckNoRows(err, mandate.ErrMandateExists)
+	})
+}
+
+func (m *model) dbUpdate(ctx context.Context, db *sqlx.DB) error {
+	return pgutil.ExecuteInTx(ctx, db, sql.LevelDefault, func(tx *sqlx.Tx) error {
+		query := `UPDATE ` + tableName + `
+			SET payments_processed = $3, payments_failed = $4, consecutive_failures = $5, next_payment_at = $6, state = $7, version = version + 1, last_updated_at = $8
+			WHERE mandate_id = $1 AND version = $2
+
+			RETURNING ` + allColumns
+
+		err := tx.QueryRowxContext(
+			ctx,
+			query,
+			m.MandateId,
+			m.Version,
+			m.PaymentsProcessed,
+			m.PaymentsFailed,
+			m.ConsecutiveFailures,
+			m.NextPaymentAt,
+			m.State,
+			time.Now().UTC(),
+		).StructScan(m)
+		if err == nil {
+			return nil
+		}
+
+		err = pgutil.CheckNoRows(err, mandate.ErrStaleVersion)
+		if err != mandate.ErrStaleVersion {
+			return err
+		}
+
+		// Differentiate between a stale version and a missing mandate
+		_, err = dbGet(ctx, db, m.MandateId)
+		if err != nil {
+			return err
+		}
+		return mandate.ErrStaleVersion
+	})
+}
+
+func dbGet(ctx context.Context, db *sqlx.DB, mandateId string) (*model, error) {
+	res := &model{}
+
+	query := `SELECT ` + allColumns + ` FROM ` + tableName + `
+		WHERE mandate_id = $1`
+
+	err := db.GetContext(ctx, res, query, mandateId)
+	if err != nil {
+		return nil, pgutil.CheckNoRows(err, mandate.ErrMandateNotFound)
+	}
+	return res, nil
+}
+
+func dbGetAllByOwner(ctx context.Context, db *sqlx.DB, owner string) ([]*model, error) {
+	res := []*model{}
+
+	query := `SELECT ` + allColumns + ` FROM ` + tableName + `
+		WHERE owner_account = $1
+		ORDER BY id ASC`
+
+	err := db.SelectContext(ctx, &res, query, owner)
+	if err != nil {
+		return nil, pgutil.CheckNoRows(err, mandate.ErrMandateNotFound)
+	}
+
+	if len(res) == 0 {
+		return nil, mandate.ErrMandateNotFound
+	}
+	return res, nil
+}
+
+func dbGetAllDue(ctx context.Context, db *sqlx.DB, at time.Time, limit uint64) ([]*model, error) {
+	res := []*model{}
+
+	query := `SELECT ` + allColumns + ` FROM ` + tableName + `
+		WHERE state = $1 AND next_payment_at <= $2
+		ORDER BY next_payment_at ASC, id ASC
+		LIMIT $3`
+
+	err := db.SelectContext(ctx, &res, query, mandate.StateActive, at.UTC(), limit)
+	if err != nil {
+		return nil, pgutil.CheckNoRows(err, mandate.ErrMandateNotFound)
+	}
+
+	if len(res) == 0 {
+		return nil, mandate.ErrMandateNotFound
+	}
+	return res, nil
+}
+
+func dbGetAllByStateCreatedBefore(ctx context.Context, db *sqlx.DB, state mandate.State, before time.Time, limit uint64) ([]*model, error) {
+	res := []*model{}
+
+	query := `SELECT ` + allColumns + ` FROM ` + tableName + `
+		WHERE state = $1 AND created_at < $2
+		ORDER BY id ASC
+		LIMIT $3`
+
+	err := db.SelectContext(ctx, &res, query, state, before.UTC(), limit)
+	if err != nil {
+		return nil, pgutil.CheckNoRows(err, mandate.ErrMandateNotFound)
+	}
+
+	if len(res) == 0 {
+		return nil, mandate.ErrMandateNotFound
+	}
+	return res, nil
+}
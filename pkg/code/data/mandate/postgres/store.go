@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/code-payments/code-server/pkg/code/data/mandate"
+)
+
+type store struct {
+	db *sqlx.DB
+}
+
+func New(db *sql.DB) mandate.Store {
+	return &store{
+		db: sqlx.NewDb(db, "pgx"),
+	}
+}
+
+// Create implements mandate.Store.Create
+func (s *store) Create(ctx context.Context, record *mandate.Record) error {
+	m, err := toModel(record)
+	if err != nil {
+		return err
+	}
+
+	err = m.dbCreate(ctx, s.db)
+	if err != nil {
+		return err
+	}
+
+	res := fromModel(m)
+	res.CopyTo(record)
+
+	return nil
+}
+
+// Update implements mandate.Store.Update
+func (s *store) Update(ctx context.Context, record *mandate.Record) error {
+	m, err := toModel(record)
+	if err != nil {
+		return err
+	}
+
+	err = m.dbUpdate(ctx, s.db)
+	if err != nil {
+		return err
+	}
+
+	res := fromModel(m)
+	res.CopyTo(record)
+
+	return nil
+}
+
+// Get implements mandate.Store.Get
+func (s *store) Get(ctx context.Context, mandateId string) (*mandate.Record, error) {
+	m, err := dbGet(ctx, s.db, mandateId)
+	if err != nil {
+		return nil, err
+	}
+	return fromModel(m), nil
+}
+
+// GetAllByOwner implements mandate.Store.GetAllByOwner
+func (s *store) GetAllByOwner(ctx context.Context, owner string) ([]*mandate.Record, error) {
+	models, err := dbGetAllByOwner(ctx, s.db, owner)
+	if err != nil {
+		return nil, err
+	}
+	return fromModels(models), nil
+}
+
+// GetAllDue implements mandate.Store.GetAllDue
+func (s *store) GetAllDue(ctx context.Context, at time.Time, limit uint64) ([]*mandate.Record, error) {
+	models, err := dbGetAllDue(ctx, s.db, at, limit)
+	if err != nil {
+		return nil, err
+	}
+	return fromModels(models), nil
+}
+
+// GetAllByStateCreatedBefore implements mandate.Store.GetAllByStateCreatedBefore
+func (s *store) GetAllByStateCreatedBefore(ctx context.Context, state mandate.State, before time.Time, limit uint64) ([]*mandate.Record, error) {
+	models, err := dbGetAllByStateCreatedBefore(ctx, s.db, state, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	return fromModels(models), nil
+}
+
+func fromModels(models []*model) []*mandate.Record {
+	res := make([]*mandate.Record, len(models))
+	for i, m := range models {
+		res[i] = fromModel(m)
+	}
+	return res
+}
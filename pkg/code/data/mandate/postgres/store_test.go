@@ -0,0 +1,136 @@
+package postgres
+
+import (
+	"database/sql"
+	"os"
+	"testing"
+
+	"github.com/ory/dockertest/v3"
+	"github.com/sirupsen/logrus"
+
+	"github.com/code-payments/code-server/pkg/code/data/mandate"
+	"github.com/code-payments/code-server/pkg/code/data/mandate/tests"
+
+	postgrestest "github.com/code-payments/code-server/pkg/database/postgres/test"
+
+	_ "github.com/jackc/pgx/v4/stdlib"
+)
+
+const (
+	// Used for testing ONLY, the table and migrations are external to this repository
+	tableCreate = `
+		CREATE TABLE codewallet__core_mandate(
+			id SERIAL NOT NULL PRIMARY KEY,
+
+			mandate_id TEXT NOT NULL,
+
+			owner_account TEXT NOT NULL,
+			source_token_account TEXT NOT NULL,
+
+			destination_owner_account TEXT NOT NULL,
+			destination_token_account TEXT NOT NULL,
+
+			exchange_currency VARCHAR(3) NOT NULL,
+			exchange_rate NUMERIC(18, 9) NOT NULL,
+			native_amount NUMERIC(18, 9) NOT NULL,
+			quantity BIGINT NOT NULL CHECK (quantity > 0),
+
+			cadence INTEGER NOT NULL,
+
+			max_payments INTEGER NOT NULL CHECK (max_payments > 0),
+
+			payments_processed INTEGER NOT NULL,
+			payments_failed INTEGER NOT NULL,
+			consecutive_failures INTEGER NOT NULL,
+
+			first_payment_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			next_payment_at TIMESTAMP WITH TIME ZONE NOT NULL,
+
+			signature TEXT NOT NULL,
+
+			state INTEGER NOT NULL,
+
+			version BIGINT NOT NULL,
+
+			created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			last_updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+
+			CONSTRAINT codewallet__core_mandate__uniq__mandate_id UNIQUE (mandate_id)
+		);
+	`
+
+	// Used for testing ONLY, the table and migrations are external to this repository
+	tableDestroy = `
+		DROP TABLE codewallet__core_mandate;
+	`
+)
+
+var (
+	testStore mandate.Store
+	teardown  func()
+)
+
+func TestMain(m *testing.M) {
+	log := logrus.StandardLogger()
+
+	testPool, err := dockertest.NewPool("")
+	if err != nil {
+		log.WithError(err).Error("Error creating docker pool")
+		os.Exit(1)
+	}
+
+	var cleanUpFunc func()
+	db, cleanUpFunc, err := postgrestest.StartPostgresDB(testPool)
+	if err != nil {
+		log.WithError(err).Error("Error starting postgres image")
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	if err := createTestTables(db); err != nil {
+		logrus.StandardLogger().WithError(err).Error("Error creating test tables")
+		cleanUpFunc()
+		os.Exit(1)
+	}
+
+	testStore = New(db)
+	teardown = func() {
+		if pc := recover(); pc != nil {
+			cleanUpFunc()
+			panic(pc)
+		}
+
+		if err := resetTestTables(db); err != nil {
+			logrus.StandardLogger().WithError(err).Error("Error resetting test tables")
+			cleanUpFunc()
+			os.Exit(1)
+		}
+	}
+
+	code := m.Run()
+	cleanUpFunc()
+	os.Exit(code)
+}
+
+func TestMandatePostgresStore(t *testing.T) {
+	tests.RunTests(t, testStore, teardown)
+}
+
+func createTestTables(db *sql.DB) error {
+	_, err := db.Exec(tableCreate)
+	if err != nil {
+		logrus.StandardLogger().WithError(err).Error("could not create test tables")
+		return err
+	}
+	return nil
+}
+
+func resetTestTables(db *sql.DB) error {
+	_, err := db.Exec(tableDestroy)
+	if err != nil {
+		logrus.StandardLogger().WithError(err).Error("could not drop test tables")
+		return err
+	}
+
+	return createTestTables(db)
+}
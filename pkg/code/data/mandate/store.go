@@ -0,0 +1,37 @@
+package mandate
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrMandateNotFound = errors.New("mandate not found")
+	ErrMandateExists   = errors.New("mandate already exists")
+	ErrStaleVersion    = errors.New("mandate version is stale")
+)
+
+type Store interface {
+	// Create creates a new mandate
+	Create(ctx context.Context, record *Record) error
+
+	// Update updates a mandate's state and payment progress. ErrStaleVersion is
+	// returned when the record's version doesn't match the stored version. The
+	// version is incremented on success.
+	Update(ctx context.Context, record *Record) error
+
+	// Get gets a mandate by its ID
+	Get(ctx context.Context, mandateId string) (*Record, error)
+
+	// GetAllByOwner gets all mandates for an owner in ascending order of creation
+	GetAllByOwner(ctx context.Context, owner string) ([]*Record, error)
+
+	// GetAllDue gets active mandates with a payment due at or before the provided
+	// time, in ascending order of the next payment time
+	GetAllDue(ctx context.Context, at time.Time, limit uint64) ([]*Record, error)
+
+	// GetAllByStateCreatedBefore gets mandates in the provided state that were
+	// created before the provided time, in ascending order of creation
+	GetAllByStateCreatedBefore(ctx context.Context, state State, before time.Time, limit uint64) ([]*Record, error)
+}
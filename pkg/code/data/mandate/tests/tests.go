@@ -0,0 +1,298 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/code-payments/code-server/pkg/code/data/mandate"
+	"github.com/code-payments/code-server/pkg/currency"
+)
+
+func RunTests(t *testing.T, s mandate.Store, teardown func()) {
+	for _, tf := range []func(t *testing.T, s mandate.Store){
+		testRoundTrip,
+		testUpdate,
+		testValidation,
+		testGetAllDue,
+		testGetAllByStateCreatedBefore,
+	} {
+		tf(t, s)
+		teardown()
+	}
+}
+
+func testRoundTrip(t *testing.T, s mandate.Store) {
+	t.Run("testRoundTrip", func(t *testing.T) {
+		ctx := context.Background()
+
+		_, err := s.Get(ctx, "mandate1")
+		assert.Equal(t, mandate.ErrMandateNotFound, err)
+
+		_, err = s.GetAllByOwner(ctx, "owner")
+		assert.Equal(t, mandate.ErrMandateNotFound, err)
+
+		var expected []*mandate.Record
+		for i := 0; i < 3; i++ {
+			record := newTestMandate(fmt.Sprintf("mandate%d", i))
+			cloned := record.Clone()
+
+			require.NoError(t, s.Create(ctx, record))
+			assert.True(t, record.Id > 0)
+			assert.EqualValues(t, 1, record.Version)
+			assert.False(t, record.CreatedAt.IsZero())
+
+			actual, err := s.Get(ctx, record.MandateId)
+			require.NoError(t, err)
+			assertEquivalentRecords(t, &cloned, actual)
+			assert.Equal(t, record.Id, actual.Id)
+			assert.EqualValues(t, 1, actual.Version)
+
+			expected = append(expected, record)
+		}
+
+		assert.Equal(t, mandate.ErrMandateExists, s.Create(ctx, newTestMandate("mandate0")))
+
+		other := newTestMandate("other")
+		other.OwnerAccount = "other_owner"
+		require.NoError(t, s.Create(ctx, other))
+
+		actual, err := s.GetAllByOwner(ctx, "owner")
+		require.NoError(t, err)
+		require.Len(t, actual, len(expected))
+		for i := range expected {
+			assertEquivalentRecords(t, expected[i], actual[i])
+		}
+	})
+}
+
+func testUpdate(t *testing.T, s mandate.Store) {
+	t.Run("testUpdate", func(t *testing.T) {
+		ctx := context.Background()
+
+		record := newTestMandate("mandate")
+		assert.Equal(t, mandate.ErrMandateNotFound, s.Update(ctx, record))
+
+		require.NoError(t, s.Create(ctx, record))
+
+		stale := record.Clone()
+
+		record.State = mandate.StateActive
+		record.PaymentsProcessed = 2
+		record.PaymentsFailed = 1
+		record.ConsecutiveFailures = 1
+		record.NextPaymentAt = record.GetPaymentTime(2)
+
+		// Immutable fields are not updated
+		record.Quantity = 1
+		record.DestinationTokenAccount = "updated"
+
+		require.NoError(t, s.Update(ctx, record))
+		assert.EqualValues(t, 2, record.Version)
+		assert.EqualValues(t, newTestMandate("mandate").Quantity, record.Quantity)
+		assert.Equal(t, "destination_token", record.DestinationTokenAccount)
+
+		actual, err := s.Get(ctx, "mandate")
+		require.NoError(t, err)
+		assertEquivalentRecords(t, record, actual)
+		assert.EqualValues(t, 2, actual.Version)
+
+		stale.State = mandate.StateCancelled
+		assert.Equal(t, mandate.ErrStaleVersion, s.Update(ctx, &stale))
+
+		actual, err = s.Get(ctx, "mandate")
+		require.NoError(t, err)
+		assert.Equal(t, mandate.StateActive, actual.State)
+	})
+}
+
+func testValidation(t *testing.T, s mandate.Store) {
+	t.Run("testValidation", func(t *testing.T) {
+		ctx := context.Background()
+
+		for _, invalidate := range []func(r *mandate.Record){
+			func(r *mandate.Record) { r.MandateId = "" },
+			func(r *mandate.Record) { r.OwnerAccount = "" },
+			func(r *mandate.Record) { r.SourceTokenAccount = "" },
+			func(r *mandate.Record) { r.DestinationOwnerAccount = "" },
+			func(r *mandate.Record) { r.DestinationTokenAccount = "" },
+			func(r *mandate.Record) { r.DestinationTokenAccount = r.SourceTokenAccount },
+			func(r *mandate.Record) { r.ExchangeCurrency = "" },
+			func(r *mandate.Record) { r.ExchangeRate = 0 },
+			func(r *mandate.Record) { r.NativeAmount = 0 },
+			func(r *mandate.Record) { r.Quantity = 0 },
+			func(r *mandate.Record) { r.Cadence = mandate.CadenceUnknown },
+			func(r *mandate.Record) { r.MaxPayments = 0 },
+			func(r *mandate.Record) { r.PaymentsProcessed = r.MaxPayments + 1 },
+			func(r *mandate.Record) { r.PaymentsFailed = 1 },
+			func(r *mandate.Record) { r.PaymentsProcessed = 1; r.ConsecutiveFailures = 1 },
+			func(r *mandate.Record) { r.FirstPaymentAt = time.Time{} },
+			func(r *mandate.Record) { r.NextPaymentAt = time.Time{} },
+			func(r *mandate.Record) { r.Signature = "" },
+			func(r *mandate.Record) { r.State = mandate.StateUnknown },
+		} {
+			record := newTestMandate("mandate")
+			invalidate(record)
+			assert.Error(t, s.Create(ctx, record))
+		}
+
+		_, err := s.Get(ctx, "mandate")
+		assert.Equal(t, mandate.ErrMandateNotFound, err)
+	})
+}
+
+func testGetAllDue(t *testing.T, s mandate.Store) {
+	t.Run("testGetAllDue", func(t *testing.T) {
+		ctx := context.Background()
+
+		now := time.Now()
+
+		_, err := s.GetAllDue(ctx, now, 10)
+		assert.Equal(t, mandate.ErrMandateNotFound, err)
+
+		for i, nextPaymentAt := range []time.Time{
+			now.Add(-time.Minute),
+			now.Add(-time.Hour),
+			now.Add(time.Hour),
+			now.Add(-2 * time.Hour),
+		} {
+			record := newTestMandate(fmt.Sprintf("mandate%d", i))
+			record.State = mandate.StateActive
+			record.FirstPaymentAt = nextPaymentAt
+			record.NextPaymentAt = nextPaymentAt
+			require.NoError(t, s.Create(ctx, record))
+		}
+
+		for i, state := range []mandate.State{
+			mandate.StatePendingAuthorization,
+			mandate.StateCompleted,
+			mandate.StateCancelled,
+			mandate.StateFailed,
+		} {
+			record := newTestMandate(fmt.Sprintf("inactive%d", i))
+			record.State = state
+			record.FirstPaymentAt = now.Add(-24 * time.Hour)
+			record.NextPaymentAt = now.Add(-24 * time.Hour)
+			require.NoError(t, s.Create(ctx, record))
+		}
+
+		actual, err := s.GetAllDue(ctx, now, 10)
+		require.NoError(t, err)
+		require.Len(t, actual, 3)
+		assert.Equal(t, "mandate3", actual[0].MandateId)
+		assert.Equal(t, "mandate1", actual[1].MandateId)
+		assert.Equal(t, "mandate0", actual[2].MandateId)
+
+		actual, err = s.GetAllDue(ctx, now, 1)
+		require.NoError(t, err)
+		require.Len(t, actual, 1)
+		assert.Equal(t, "mandate3", actual[0].MandateId)
+
+		actual, err = s.GetAllDue(ctx, now.Add(2*time.Hour), 10)
+		require.NoError(t, err)
+		assert.Len(t, actual, 4)
+
+		_, err = s.GetAllDue(ctx, now.Add(-3*time.Hour), 10)
+		assert.Equal(t, mandate.ErrMandateNotFound, err)
+	})
+}
+
+func testGetAllByStateCreatedBefore(t *testing.T, s mandate.Store) {
+	t.Run("testGetAllByStateCreatedBefore", func(t *testing.T) {
+		ctx := context.Background()
+
+		now := time.Now()
+
+		_, err := s.GetAllByStateCreatedBefore(ctx, mandate.StatePendingAuthorization, now, 10)
+		assert.Equal(t, mandate.ErrMandateNotFound, err)
+
+		for i, createdAt := range []time.Time{
+			now.Add(-2 * time.Hour),
+			now.Add(-time.Hour),
+			now.Add(time.Hour),
+		} {
+			record := newTestMandate(fmt.Sprintf("mandate%d", i))
+			record.CreatedAt = createdAt
+			require.NoError(t, s.Create(ctx, record))
+		}
+
+		active := newTestMandate("active")
+		active.State = mandate.StateActive
+		active.CreatedAt = now.Add(-3 * time.Hour)
+		require.NoError(t, s.Create(ctx, active))
+
+		actual, err := s.GetAllByStateCreatedBefore(ctx, mandate.StatePendingAuthorization, now, 10)
+		require.NoError(t, err)
+		require.Len(t, actual, 2)
+		assert.Equal(t, "mandate0", actual[0].MandateId)
+		assert.Equal(t, "mandate1", actual[1].MandateId)
+
+		actual, err = s.GetAllByStateCreatedBefore(ctx, mandate.StatePendingAuthorization, now, 1)
+		require.NoError(t, err)
+		require.Len(t, actual, 1)
+		assert.Equal(t, "mandate0", actual[0].MandateId)
+
+		actual, err = s.GetAllByStateCreatedBefore(ctx, mandate.StateActive, now, 10)
+		require.NoError(t, err)
+		require.Len(t, actual, 1)
+		assert.Equal(t, "active", actual[0].MandateId)
+
+		_, err = s.GetAllByStateCreatedBefore(ctx, mandate.StateCancelled, now, 10)
+		assert.Equal(t, mandate.ErrMandateNotFound, err)
+	})
+}
+
+func newTestMandate(mandateId string) *mandate.Record {
+	firstPaymentAt := time.Date(2024, time.January, 31, 12, 0, 0, 0, time.UTC)
+
+	return &mandate.Record{
+		MandateId: mandateId,
+
+		OwnerAccount:       "owner",
+		SourceTokenAccount: "source_token",
+
+		DestinationOwnerAccount: "destination_owner",
+		DestinationTokenAccount: "destination_token",
+
+		ExchangeCurrency: currency.USD,
+		ExchangeRate:     0.00001,
+		NativeAmount:     9.99,
+		Quantity:         99900000000,
+
+		Cadence: mandate.CadenceMonthly,
+
+		MaxPayments: 12,
+
+		FirstPaymentAt: firstPaymentAt,
+		NextPaymentAt:  firstPaymentAt,
+
+		Signature: "signature",
+
+		State: mandate.StatePendingAuthorization,
+	}
+}
+
+func assertEquivalentRecords(t *testing.T, obj1, obj2 *mandate.Record) {
+	assert.Equal(t, obj1.MandateId, obj2.MandateId)
+	assert.Equal(t, obj1.OwnerAccount, obj2.OwnerAccount)
+	assert.Equal(t, obj1.SourceTokenAccount, obj2.SourceTokenAccount)
+	assert.Equal(t, obj1.DestinationOwnerAccount, obj2.DestinationOwnerAccount)
+	assert.Equal(t, obj1.DestinationTokenAccount, obj2.DestinationTokenAccount)
+	assert.Equal(t, obj1.ExchangeCurrency, obj2.ExchangeCurrency)
+	assert.Equal(t, obj1.ExchangeRate, obj2.ExchangeRate)
+	assert.Equal(t, obj1.NativeAmount, obj2.NativeAmount)
+	assert.Equal(t, obj1.Quantity, obj2.Quantity)
+	assert.Equal(t, obj1.Cadence, obj2.Cadence)
+	assert.Equal(t, obj1.MaxPayments, obj2.MaxPayments)
+	assert.Equal(t, obj1.PaymentsProcessed, obj2.PaymentsProcessed)
+	assert.Equal(t, obj1.PaymentsFailed, obj2.PaymentsFailed)
+	assert.Equal(t, obj1.ConsecutiveFailures, obj2.ConsecutiveFailures)
+	assert.Equal(t, obj1.FirstPaymentAt.Unix(), obj2.FirstPaymentAt.Unix())
+	assert.Equal(t, obj1.NextPaymentAt.Unix(), obj2.NextPaymentAt.Unix())
+	assert.Equal(t, obj1.Signature, obj2.Signature)
+	assert.Equal(t, obj1.State, obj2.State)
+}
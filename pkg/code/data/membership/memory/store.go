@@ -0,0 +1,106 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/code-payments/code-server/pkg/code/data/membership"
+)
+
+type store struct {
+	mu      sync.Mutex
+	records []*membership.Record
+	last    uint64
+}
+
+// New returns a new in memory membership.Store
+func New() membership.Store {
+	return &store{}
+}
+
+// Heartbeat implements membership.Store.Heartbeat
+func (s *store) Heartbeat(_ context.Context, record *membership.Record) error {
+	if err := record.Validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if record.LastHeartbeatAt.IsZero() {
+		record.LastHeartbeatAt = time.Now()
+	}
+
+	if item := s.find(record.Group, record.NodeId); item != nil {
+		item.LastHeartbeatAt = record.LastHeartbeatAt
+		item.CopyTo(record)
+		return nil
+	}
+
+	s.last++
+	record.Id = s.last
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = time.Now()
+	}
+
+	cloned := record.Clone()
+	s.records = append(s.records, &cloned)
+
+	return nil
+}
+
+// GetAllActive implements membership.Store.GetAllActive
+func (s *store) GetAllActive(_ context.Context, group string, since time.Time) ([]*membership.Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var res []*membership.Record
+	for _, item := range s.records {
+		if item.Group == group && item.LastHeartbeatAt.After(since) {
+			cloned := item.Clone()
+			res = append(res, &cloned)
+		}
+	}
+
+	if len(res) == 0 {
+		return nil, membership.ErrMemberNotFound
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].NodeId < res[j].NodeId
+	})
+	return res, nil
+}
+
+// Delete implements membership.Store.Delete
+func (s *store) Delete(_ context.Context, group, nodeId string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, item := range s.records {
+		if item.Group == group && item.NodeId == nodeId {
+			s.records = append(s.records[:i], s.records[i+1:]...)
+			return nil
+		}
+	}
+	return membership.ErrMemberNotFound
+}
+
+func (s *store) find(group, nodeId string) *membership.Record {
+	for _, item := range s.records {
+		if item.Group == group && item.NodeId == nodeId {
+			return item
+		}
+	}
+	return nil
+}
+
+func (s *store) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.records = nil
+	s.last = 0
+}
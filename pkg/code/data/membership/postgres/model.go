@@ -0,0 +1,119 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	pgutil "github.com/code-payments/code-server/pkg/database/postgres"
+	"github.com/code-payments/code-server/pkg/code/data/membership"
+)
+
+const (
+	tableName = "codewallet__core_workermembership"
+)
+
+type model struct {
+	Id sql.NullInt64 `db:"id"`
+
+	Group  string `db:"worker_group"`
+	NodeId string `db:"node_id"`
+
+	LastHeartbeatAt time.Time `db:"last_heartbeat_at"`
+	CreatedAt       time.Time `db:"created_at"`
+}
+
+func toModel(obj *membership.Record) (*model, error) {
+	if err := obj.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &model{
+		Group:  obj.Group,
+		NodeId: obj.NodeId,
+
+		LastHeartbeatAt: obj.LastHeartbeatAt,
+		CreatedAt:       obj.CreatedAt,
+	}, nil
+}
+
+func fromModel(obj *model) *membership.Record {
+	return &membership.Record{
+		Id: uint64(obj.Id.Int64),
+
+		Group:  obj.Group,
+		NodeId: obj.NodeId,
+
+		LastHeartbeatAt: obj.LastHeartbeatAt,
+		CreatedAt:       obj.CreatedAt,
+	}
+}
+
+func (m *model) dbHeartbeat(ctx context.Context, db *sqlx.DB) error {
+	return pgutil.ExecuteInTx(ctx, db, sql.LevelDefault, func(tx *sqlx.Tx) error {
+		if m.LastHeartbeatAt.IsZero() {
+			m.LastHeartbeatAt = time.Now()
+		}
+		if m.CreatedAt.IsZero() {
+			m.CreatedAt = time.Now()
+		}
+
+		query := `INSERT INTO ` + tableName + `
+			(worker_group, node_id, last_heartbeat_at, created_at)
+			VALUES ($1, $2, $3, $4)
+
+			ON CONFLICT (worker_group, node_id)
+			DO UPDATE
+				SET last_heartbeat_at = $3
+				WHERE ` + tableName + `.worker_group = $1 AND ` + tableName + `.node_id = $2
+
+			RETURNING id, worker_group, node_id, last_heartbeat_at, created_at`
+
+		return tx.QueryRowxContext(
+			ctx,
+			query,
+			m.Group,
+			m.NodeId,
+			m.LastHeartbeatAt.UTC(),
+			m.CreatedAt.UTC(),
+		).StructScan(m)
+	})
+}
+
+func dbGetAllActive(ctx context.Context, db *sqlx.DB, group string, since time.Time) ([]*model, error) {
+	res := []*model{}
+
+	query := `SELECT id, worker_group, node_id, last_heartbeat_at, created_at FROM ` + tableName + `
+		WHERE worker_group = $1 AND last_heartbeat_at > $2
+		ORDER BY node_id ASC`
+
+	err := db.SelectContext(ctx, &res, query, group, since.UTC())
+	if err != nil {
+		return nil, pgutil.CheckNoRows(err, membership.ErrMemberNotFound)
+	}
+
+	if len(res) == 0 {
+		return nil, membership.ErrMemberNotFound
+	}
+	return res, nil
+}
+
+func dbDelete(ctx context.Context, db *sqlx.DB, group, nodeId string) error {
+	query := `DELETE FROM ` + tableName + `
+		WHERE worker_group = $1 AND node_id = $2`
+
+	res, err := db.ExecContext(ctx, query, group, nodeId)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	} else if rowsAffected == 0 {
+		return membership.ErrMemberNotFound
+	}
+	return nil
+}
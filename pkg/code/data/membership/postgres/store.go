@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/code-payments/code-server/pkg/code/data/membership"
+)
+
+type store struct {
+	db *sqlx.DB
+}
+
+// New returns a new postgres membership.Store
+func New(db *sql.DB) membership.Store {
+	return &store{
+		db: sqlx.NewDb(db, "pgx"),
+	}
+}
+
+// Heartbeat implements membership.Store.Heartbeat
+func (s *store) Heartbeat(ctx context.Context, record *membership.Record) error {
+	m, err := toModel(record)
+	if err != nil {
+		return err
+	}
+
+	if err := m.dbHeartbeat(ctx, s.db); err != nil {
+		return err
+	}
+
+	res := fromModel(m)
+	res.CopyTo(record)
+
+	return nil
+}
+
+// GetAllActive implements membership.Store.GetAllActive
+func (s *store) GetAllActive(ctx context.Context, group string, since time.Time) ([]*membership.Record, error) {
+	models, err := dbGetAllActive(ctx, s.db, group, since)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*membership.Record, len(models))
+	for i, m := range models {
+		res[i] = fromModel(m)
+	}
+	return res, nil
+}
+
+// Delete implements membership.Store.Delete
+func (s *store) Delete(ctx context.Context, group, nodeId string) error {
+	return dbDelete(ctx, s.db, group, nodeId)
+}
@@ -0,0 +1,53 @@
+package membership
+
+import (
+	"errors"
+	"time"
+)
+
+// Record is a node that's a member of a group of workers that shard their
+// processing. Members remain part of the group as long as they continue to
+// heartbeat.
+type Record struct {
+	Id uint64
+
+	Group  string
+	NodeId string
+
+	LastHeartbeatAt time.Time
+	CreatedAt       time.Time
+}
+
+func (r *Record) Validate() error {
+	if len(r.Group) == 0 {
+		return errors.New("group is required")
+	}
+
+	if len(r.NodeId) == 0 {
+		return errors.New("node id is required")
+	}
+
+	return nil
+}
+
+func (r *Record) Clone() Record {
+	return Record{
+		Id: r.Id,
+
+		Group:  r.Group,
+		NodeId: r.NodeId,
+
+		LastHeartbeatAt: r.LastHeartbeatAt,
+		CreatedAt:       r.CreatedAt,
+	}
+}
+
+func (r *Record) CopyTo(dst *Record) {
+	dst.Id = r.Id
+
+	dst.Group = r.Group
+	dst.NodeId = r.NodeId
+
+	dst.LastHeartbeatAt = r.LastHeartbeatAt
+	dst.CreatedAt = r.CreatedAt
+}
@@ -0,0 +1,25 @@
+package membership
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrMemberNotFound = errors.New("member not found")
+)
+
+type Store interface {
+	// Heartbeat creates a member of a group, or updates the last heartbeat of an
+	// existing member. The last heartbeat is set to the current time when it's
+	// not provided.
+	Heartbeat(ctx context.Context, record *Record) error
+
+	// GetAllActive gets all members of a group whose last heartbeat is after the
+	// provided time, in ascending order of node ID
+	GetAllActive(ctx context.Context, group string, since time.Time) ([]*Record, error)
+
+	// Delete removes a member from a group
+	Delete(ctx context.Context, group, nodeId string) error
+}
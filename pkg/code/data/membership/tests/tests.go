@@ -0,0 +1,150 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/code-payments/code-server/pkg/code/data/membership"
+)
+
+func RunTests(t *testing.T, s membership.Store, teardown func()) {
+	for _, tf := range []func(t *testing.T, s membership.Store){
+		testHappyPath,
+		testGroupIsolation,
+		testValidation,
+	} {
+		tf(t, s)
+		teardown()
+	}
+}
+
+func testHappyPath(t *testing.T, s membership.Store) {
+	t.Run("testHappyPath", func(t *testing.T) {
+		ctx := context.Background()
+
+		start := time.Now()
+		time.Sleep(time.Millisecond)
+
+		_, err := s.GetAllActive(ctx, "sequencer", start.Add(-time.Hour))
+		assert.Equal(t, membership.ErrMemberNotFound, err)
+
+		node2 := &membership.Record{
+			Group:  "sequencer",
+			NodeId: "node2",
+		}
+		require.NoError(t, s.Heartbeat(ctx, node2))
+		assert.True(t, node2.Id > 0)
+		assert.True(t, node2.LastHeartbeatAt.After(start))
+		assert.True(t, node2.CreatedAt.After(start))
+
+		node1 := &membership.Record{
+			Group:           "sequencer",
+			NodeId:          "node1",
+			LastHeartbeatAt: start.Add(-time.Minute),
+		}
+		require.NoError(t, s.Heartbeat(ctx, node1))
+
+		actual, err := s.GetAllActive(ctx, "sequencer", start.Add(-time.Hour))
+		require.NoError(t, err)
+		require.Len(t, actual, 2)
+		assertEquivalentRecords(t, node1, actual[0])
+		assertEquivalentRecords(t, node2, actual[1])
+
+		actual, err = s.GetAllActive(ctx, "sequencer", start)
+		require.NoError(t, err)
+		require.Len(t, actual, 1)
+		assertEquivalentRecords(t, node2, actual[0])
+
+		// Heartbeats update the existing member
+		heartbeat := &membership.Record{
+			Group:  "sequencer",
+			NodeId: "node1",
+		}
+		require.NoError(t, s.Heartbeat(ctx, heartbeat))
+		assert.Equal(t, node1.Id, heartbeat.Id)
+		assert.Equal(t, node1.CreatedAt.Unix(), heartbeat.CreatedAt.Unix())
+		assert.True(t, heartbeat.LastHeartbeatAt.After(start))
+
+		actual, err = s.GetAllActive(ctx, "sequencer", start)
+		require.NoError(t, err)
+		require.Len(t, actual, 2)
+		assertEquivalentRecords(t, heartbeat, actual[0])
+		assertEquivalentRecords(t, node2, actual[1])
+
+		require.NoError(t, s.Delete(ctx, "sequencer", "node1"))
+		assert.Equal(t, membership.ErrMemberNotFound, s.Delete(ctx, "sequencer", "node1"))
+
+		actual, err = s.GetAllActive(ctx, "sequencer", start)
+		require.NoError(t, err)
+		require.Len(t, actual, 1)
+		assertEquivalentRecords(t, node2, actual[0])
+
+		require.NoError(t, s.Delete(ctx, "sequencer", "node2"))
+
+		_, err = s.GetAllActive(ctx, "sequencer", start)
+		assert.Equal(t, membership.ErrMemberNotFound, err)
+	})
+}
+
+func testGroupIsolation(t *testing.T, s membership.Store) {
+	t.Run("testGroupIsolation", func(t *testing.T) {
+		ctx := context.Background()
+
+		start := time.Now().Add(-time.Minute)
+
+		for _, group := range []string{"sequencer", "nonce"} {
+			require.NoError(t, s.Heartbeat(ctx, &membership.Record{
+				Group:  group,
+				NodeId: "node1",
+			}))
+		}
+		require.NoError(t, s.Heartbeat(ctx, &membership.Record{
+			Group:  "nonce",
+			NodeId: "node2",
+		}))
+
+		actual, err := s.GetAllActive(ctx, "sequencer", start)
+		require.NoError(t, err)
+		require.Len(t, actual, 1)
+		assert.Equal(t, "node1", actual[0].NodeId)
+
+		actual, err = s.GetAllActive(ctx, "nonce", start)
+		require.NoError(t, err)
+		require.Len(t, actual, 2)
+
+		require.NoError(t, s.Delete(ctx, "sequencer", "node1"))
+
+		_, err = s.GetAllActive(ctx, "sequencer", start)
+		assert.Equal(t, membership.ErrMemberNotFound, err)
+
+		actual, err = s.GetAllActive(ctx, "nonce", start)
+		require.NoError(t, err)
+		require.Len(t, actual, 2)
+	})
+}
+
+func testValidation(t *testing.T, s membership.Store) {
+	t.Run("testValidation", func(t *testing.T) {
+		ctx := context.Background()
+
+		for _, invalid := range []*membership.Record{
+			{},
+			{Group: "sequencer"},
+			{NodeId: "node1"},
+		} {
+			assert.Error(t, s.Heartbeat(ctx, invalid))
+		}
+	})
+}
+
+func assertEquivalentRecords(t *testing.T, obj1, obj2 *membership.Record) {
+	assert.Equal(t, obj1.Id, obj2.Id)
+	assert.Equal(t, obj1.Group, obj2.Group)
+	assert.Equal(t, obj1.NodeId, obj2.NodeId)
+	assert.Equal(t, obj1.LastHeartbeatAt.Unix(), obj2.LastHeartbeatAt.Unix())
+	assert.Equal(t, obj1.CreatedAt.Unix(), obj2.CreatedAt.Unix())
+}
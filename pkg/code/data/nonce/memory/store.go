@@ -83,6 +83,22 @@ func (s *store) findByStateAndPurpose(state nonce.State, purpose nonce.Purpose)
 	return res
 }
 
+func (s *store) findByStateAndFeePayer(state nonce.State, feePayer string) []*nonce.Record {
+	res := make([]*nonce.Record, 0)
+	for _, item := range s.records {
+		if item.State != state {
+			continue
+		}
+
+		if item.FeePayer != feePayer {
+			continue
+		}
+
+		res = append(res, item)
+	}
+	return res
+}
+
 func (s *store) filter(items []*nonce.Record, cursor query.Cursor, limit uint64, direction query.Ordering) []*nonce.Record {
 	var start uint64
 
@@ -138,6 +154,14 @@ func (s *store) CountByStateAndPurpose(ctx context.Context, state nonce.State, p
 	return uint64(len(res)), nil
 }
 
+func (s *store) CountByStateAndFeePayer(ctx context.Context, state nonce.State, feePayer string) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := s.findByStateAndFeePayer(state, feePayer)
+	return uint64(len(res)), nil
+}
+
 func (s *store) Save(ctx context.Context, data *nonce.Record) error {
 	if err := data.Validate(); err != nil {
 		return err
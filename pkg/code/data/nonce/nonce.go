@@ -35,6 +35,7 @@ const (
 	PurposeClientTransaction
 	PurposeInternalServerProcess
 	PurposeOnDemandTransaction
+	PurposeMandatePayment
 )
 
 type Record struct {
@@ -46,6 +47,11 @@ type Record struct {
 	Purpose   Purpose
 	State     State
 
+	// FeePayer is the account that funded the nonce account's rent. Empty for
+	// nonces created before it was tracked, which were always funded by the
+	// subsidizer.
+	FeePayer string
+
 	Signature string
 }
 
@@ -61,6 +67,7 @@ func (r *Record) Clone() Record {
 		Blockhash: r.Blockhash,
 		Purpose:   r.Purpose,
 		State:     r.State,
+		FeePayer:  r.FeePayer,
 		Signature: r.Signature,
 	}
 }
@@ -72,6 +79,7 @@ func (r *Record) CopyTo(dst *Record) {
 	dst.Blockhash = r.Blockhash
 	dst.Purpose = r.Purpose
 	dst.State = r.State
+	dst.FeePayer = r.FeePayer
 	dst.Signature = r.Signature
 }
 
@@ -117,6 +125,8 @@ func (p Purpose) String() string {
 		return "internal_server_process"
 	case PurposeOnDemandTransaction:
 		return "on_demand_transaction"
+	case PurposeMandatePayment:
+		return "mandate_payment"
 	}
 
 	return "unknown"
@@ -17,13 +17,14 @@ const (
 )
 
 type nonceModel struct {
-	Id        sql.NullInt64 `db:"id"`
-	Address   string        `db:"address"`
-	Authority string        `db:"authority"`
-	Blockhash string        `db:"blockhash"`
-	Purpose   uint          `db:"purpose"`
-	State     uint          `db:"state"`
-	Signature string        `db:"signature"`
+	Id        sql.NullInt64  `db:"id"`
+	Address   string         `db:"address"`
+	Authority string         `db:"authority"`
+	Blockhash string         `db:"blockhash"`
+	Purpose   uint           `db:"purpose"`
+	State     uint           `db:"state"`
+	FeePayer  sql.NullString `db:"fee_payer"`
+	Signature string         `db:"signature"`
 }
 
 func toNonceModel(obj *nonce.Record) (*nonceModel, error) {
@@ -38,6 +39,7 @@ func toNonceModel(obj *nonce.Record) (*nonceModel, error) {
 		Blockhash: obj.Blockhash,
 		Purpose:   uint(obj.Purpose),
 		State:     uint(obj.State),
+		FeePayer:  sql.NullString{String: obj.FeePayer, Valid: len(obj.FeePayer) > 0},
 		Signature: obj.Signature,
 	}, nil
 }
@@ -50,6 +52,7 @@ func fromNonceModel(obj *nonceModel) *nonce.Record {
 		Blockhash: obj.Blockhash,
 		Purpose:   nonce.Purpose(obj.Purpose),
 		State:     nonce.State(obj.State),
+		FeePayer:  obj.FeePayer.String,
 		Signature: obj.Signature,
 	}
 }
@@ -57,14 +60,14 @@ func fromNonceModel(obj *nonceModel) *nonce.Record {
 func (m *nonceModel) dbSave(ctx context.Context, db *sqlx.DB) error {
 	return pgutil.ExecuteInTx(ctx, db, sql.LevelDefault, func(tx *sqlx.Tx) error {
 		query := `INSERT INTO ` + nonceTableName + `
-			(address, authority, blockhash, purpose, state, signature)
-			VALUES ($1,$2,$3,$4,$5,$6)
+			(address, authority, blockhash, purpose, state, fee_payer, signature)
+			VALUES ($1,$2,$3,$4,$5,$6,$7)
 			ON CONFLICT (address)
 			DO UPDATE
-				SET blockhash = $3, state = $5, signature = $6
+				SET blockhash = $3, state = $5, signature = $7
 				WHERE ` + nonceTableName + `.address = $1 
 			RETURNING
-				id, address, authority, blockhash, purpose, state, signature`
+				id, address, authority, blockhash, purpose, state, fee_payer, signature`
 
 		err := tx.QueryRowxContext(
 			ctx,
@@ -74,6 +77,7 @@ func (m *nonceModel) dbSave(ctx context.Context, db *sqlx.DB) error {
 			m.Blockhash,
 			m.Purpose,
 			m.State,
+			m.FeePayer,
 			m.Signature,
 		).StructScan(m)
 
@@ -117,11 +121,25 @@ func dbGetCountByStateAndPurpose(ctx context.Context, db *sqlx.DB, state nonce.S
 	return res, nil
 }
 
+// Nonces without a fee payer predate it being tracked, and were funded by the
+// subsidizer, so they're matched by an empty fee payer.
+func dbGetCountByStateAndFeePayer(ctx context.Context, db *sqlx.DB, state nonce.State, feePayer string) (uint64, error) {
+	var res uint64
+
+	query := `SELECT COUNT(*) FROM ` + nonceTableName + ` WHERE state = $1 and COALESCE(fee_payer, '') = $2`
+	err := db.GetContext(ctx, &res, query, state, feePayer)
+	if err != nil {
+		return 0, err
+	}
+
+	return res, nil
+}
+
 func dbGetNonce(ctx context.Context, db *sqlx.DB, address string) (*nonceModel, error) {
 	res := &nonceModel{}
 
 	query := `SELECT
-		id, address, authority, blockhash, purpose, state, signature
+		id, address, authority, blockhash, purpose, state, fee_payer, signature
 		FROM ` + nonceTableName + `
 		WHERE address = $1
 	`
@@ -142,7 +160,7 @@ func dbGetAllByState(ctx context.Context, db *sqlx.DB, state nonce.State, cursor
 	//
 	// todo: Fix said nonce records
 	query := `SELECT
-		id, address, authority, blockhash, purpose, state, signature
+		id, address, authority, blockhash, purpose, state, fee_payer, signature
 		FROM ` + nonceTableName + `
 		WHERE (state = $1 AND signature IS NOT NULL)
 	`
@@ -175,14 +193,14 @@ func dbGetRandomAvailableByPurpose(ctx context.Context, db *sqlx.DB, purpose non
 	//
 	// todo: Fix said nonce records
 	query := `SELECT
-		id, address, authority, blockhash, purpose, state, signature
+		id, address, authority, blockhash, purpose, state, fee_payer, signature
 		FROM ` + nonceTableName + `
 		WHERE state = $1 AND purpose = $2 AND signature IS NOT NULL
 		OFFSET FLOOR(RANDOM() * 100)
 		LIMIT 1
 	`
 	fallbackQuery := `SELECT
-		id, address, authority, blockhash, purpose, state, signature
+		id, address, authority, blockhash, purpose, state, fee_payer, signature
 		FROM ` + nonceTableName + `
 		WHERE state = $1 AND purpose = $2 AND signature IS NOT NULL
 		LIMIT 1
@@ -35,6 +35,12 @@ func (s *store) CountByStateAndPurpose(ctx context.Context, state nonce.State, p
 	return dbGetCountByStateAndPurpose(ctx, s.db, state, purpose)
 }
 
+// CountByStateAndFeePayer returns the total count of nonce accounts in the
+// provided state that were funded by the fee payer
+func (s *store) CountByStateAndFeePayer(ctx context.Context, state nonce.State, feePayer string) (uint64, error) {
+	return dbGetCountByStateAndFeePayer(ctx, s.db, state, feePayer)
+}
+
 // Put saves nonce metadata to the store.
 func (s *store) Save(ctx context.Context, record *nonce.Record) error {
 	obj, err := toNonceModel(record)
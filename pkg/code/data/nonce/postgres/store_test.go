@@ -28,6 +28,7 @@ const (
 
 			purpose integer NOT NULL,
 			state integer NOT NULL,
+			fee_payer text NULL,
 			signature text NULL
 		);
 	`
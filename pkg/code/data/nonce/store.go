@@ -17,6 +17,11 @@ type Store interface {
 	// state and use case
 	CountByStateAndPurpose(ctx context.Context, state State, purpose Purpose) (uint64, error)
 
+	// CountByStateAndFeePayer returns the total count of nonce accounts in the
+	// provided state that were funded by the fee payer. An empty fee payer
+	// matches nonce accounts created before the fee payer was tracked.
+	CountByStateAndFeePayer(ctx context.Context, state State, feePayer string) (uint64, error)
+
 	// Save creates or updates nonce metadata in the store.
 	Save(ctx context.Context, record *Record) error
 
@@ -17,6 +17,7 @@ func RunTests(t *testing.T, s nonce.Store, teardown func()) {
 		testUpdate,
 		testGetAllByState,
 		testGetCount,
+		testGetCountByStateAndFeePayer,
 		testGetRandomAvailableByPurpose,
 	} {
 		tf(t, s)
@@ -37,6 +38,7 @@ func testRoundTrip(t *testing.T, s nonce.Store) {
 		Authority: "test_authority",
 		Blockhash: "test_blockhash",
 		Purpose:   nonce.PurposeClientTransaction,
+		FeePayer:  "test_fee_payer",
 	}
 	err = s.Save(ctx, &expected)
 	require.NoError(t, err)
@@ -47,6 +49,7 @@ func testRoundTrip(t *testing.T, s nonce.Store) {
 	assert.Equal(t, expected.Authority, actual.Authority)
 	assert.Equal(t, expected.Blockhash, actual.Blockhash)
 	assert.Equal(t, expected.Purpose, actual.Purpose)
+	assert.Equal(t, expected.FeePayer, actual.FeePayer)
 	assert.EqualValues(t, 1, actual.Id)
 }
 
@@ -245,6 +248,39 @@ func testGetCount(t *testing.T, s nonce.Store) {
 	assert.EqualValues(t, 0, count)
 }
 
+func testGetCountByStateAndFeePayer(t *testing.T, s nonce.Store) {
+	ctx := context.Background()
+
+	records := []nonce.Record{
+		{Address: "t1", Authority: "a1", Blockhash: "b1", State: nonce.StateUnknown, Purpose: nonce.PurposeClientTransaction, Signature: "s1"},
+		{Address: "t2", Authority: "a1", Blockhash: "b1", State: nonce.StateUnknown, Purpose: nonce.PurposeClientTransaction, FeePayer: "f1", Signature: "s2"},
+		{Address: "t3", Authority: "a1", Blockhash: "b1", State: nonce.StateUnknown, Purpose: nonce.PurposeClientTransaction, FeePayer: "f2", Signature: "s3"},
+		{Address: "t4", Authority: "a1", Blockhash: "b2", State: nonce.StateUnknown, Purpose: nonce.PurposeInternalServerProcess, FeePayer: "f2", Signature: "s4"},
+		{Address: "t5", Authority: "a1", Blockhash: "b2", State: nonce.StateAvailable, Purpose: nonce.PurposeClientTransaction, FeePayer: "f1", Signature: "s5"},
+	}
+	for _, record := range records {
+		require.NoError(t, s.Save(ctx, &record))
+	}
+
+	for _, tc := range []struct {
+		state    nonce.State
+		feePayer string
+		expected uint64
+	}{
+		{nonce.StateUnknown, "", 1},
+		{nonce.StateUnknown, "f1", 1},
+		{nonce.StateUnknown, "f2", 2},
+		{nonce.StateUnknown, "f3", 0},
+		{nonce.StateAvailable, "f1", 1},
+		{nonce.StateAvailable, "f2", 0},
+		{nonce.StateReserved, "", 0},
+	} {
+		count, err := s.CountByStateAndFeePayer(ctx, tc.state, tc.feePayer)
+		require.NoError(t, err)
+		assert.Equal(t, tc.expected, count)
+	}
+}
+
 func testGetRandomAvailableByPurpose(t *testing.T, s nonce.Store) {
 	t.Run("testGetRandomAvailableByPurpose", func(t *testing.T) {
 		ctx := context.Background()
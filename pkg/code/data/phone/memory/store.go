@@ -1,11 +1,14 @@
 package memory
 
 import (
+	"bytes"
 	"context"
 	"sort"
 	"sync"
 	"time"
 
+	phoneutil "github.com/code-payments/code-server/pkg/phone"
+
 	"github.com/code-payments/code-server/pkg/code/data/phone"
 )
 
@@ -14,6 +17,7 @@ type store struct {
 
 	verificationsByAccount map[string][]*phone.Verification
 	verificationsByNumber  map[string][]*phone.Verification
+	verifiedNumbersByHash  map[string]string
 
 	linkingTokensByNumber map[string]*phone.LinkingToken
 
@@ -28,6 +32,7 @@ func New() phone.Store {
 	return &store{
 		verificationsByAccount: make(map[string][]*phone.Verification),
 		verificationsByNumber:  make(map[string][]*phone.Verification),
+		verifiedNumbersByHash:  make(map[string]string),
 
 		linkingTokensByNumber: make(map[string]*phone.LinkingToken),
 
@@ -72,6 +77,12 @@ func (s *store) SaveVerification(ctx context.Context, newVerification *phone.Ver
 		}
 		s.verificationsByAccount[copy.OwnerAccount] = append(s.verificationsByAccount[copy.OwnerAccount], copy)
 		s.verificationsByNumber[copy.PhoneNumber] = append(s.verificationsByNumber[copy.PhoneNumber], copy)
+
+		// Verifications saved before a salt is loaded are indexed by BackfillNumberHashes
+		hash, err := phoneutil.HashForContactDiscovery(copy.PhoneNumber)
+		if err == nil {
+			s.verifiedNumbersByHash[string(hash)] = copy.PhoneNumber
+		}
 	}
 
 	currentByAccount = s.verificationsByAccount[newVerification.OwnerAccount]
@@ -207,6 +218,60 @@ func (s *store) FilterVerifiedNumbers(ctx context.Context, phoneNumbers []string
 	return filtered, nil
 }
 
+// GetVerifiedNumberHashesByPrefix implements phone.Store.GetVerifiedNumberHashesByPrefix
+func (s *store) GetVerifiedNumberHashesByPrefix(ctx context.Context, prefixes [][]byte) ([][]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var res [][]byte
+	for hash := range s.verifiedNumbersByHash {
+		for _, prefix := range prefixes {
+			if len(prefix) > 0 && bytes.HasPrefix([]byte(hash), prefix) {
+				res = append(res, []byte(hash))
+				break
+			}
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return bytes.Compare(res[i], res[j]) < 0
+	})
+
+	return res, nil
+}
+
+// BackfillNumberHashes implements phone.Store.BackfillNumberHashes
+func (s *store) BackfillNumberHashes(ctx context.Context, limit uint64) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	indexed := make(map[string]struct{})
+	for _, phoneNumber := range s.verifiedNumbersByHash {
+		indexed[phoneNumber] = struct{}{}
+	}
+
+	var updated uint64
+	for phoneNumber := range s.verificationsByNumber {
+		if updated >= limit {
+			break
+		}
+
+		if _, ok := indexed[phoneNumber]; ok {
+			continue
+		}
+
+		hash, err := phoneutil.HashForContactDiscovery(phoneNumber)
+		if err != nil {
+			return updated, err
+		}
+
+		s.verifiedNumbersByHash[string(hash)] = phoneNumber
+		updated++
+	}
+
+	return updated, nil
+}
+
 // GetSettings implements phone.Store.GetSettings
 func (s *store) GetSettings(ctx context.Context, phoneNumber string) (*phone.Settings, error) {
 	s.mu.RLock()
@@ -380,6 +445,7 @@ func (s *store) reset() {
 
 	s.verificationsByAccount = make(map[string][]*phone.Verification)
 	s.verificationsByNumber = make(map[string][]*phone.Verification)
+	s.verifiedNumbersByHash = make(map[string]string)
 
 	s.linkingTokensByNumber = make(map[string]*phone.LinkingToken)
 
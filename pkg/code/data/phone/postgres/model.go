@@ -25,11 +25,12 @@ const (
 )
 
 type verificationModel struct {
-	Id             sql.NullInt64 `db:"id"`
-	PhoneNumber    string        `db:"phone_number"`
-	OwnerAccount   string        `db:"owner_account"`
-	CreatedAt      time.Time     `db:"created_at"`
-	LastVerifiedAt time.Time     `db:"last_verified_at"`
+	Id              sql.NullInt64 `db:"id"`
+	PhoneNumber     string        `db:"phone_number"`
+	PhoneNumberHash []byte        `db:"phone_number_hash"`
+	OwnerAccount    string        `db:"owner_account"`
+	CreatedAt       time.Time     `db:"created_at"`
+	LastVerifiedAt  time.Time     `db:"last_verified_at"`
 }
 
 func toVerificationModel(obj *phone.Verification) (*verificationModel, error) {
@@ -37,11 +38,18 @@ func toVerificationModel(obj *phone.Verification) (*verificationModel, error) {
 		return nil, err
 	}
 
+	// Verifications saved before a salt is loaded are indexed by dbBackfillNumberHashes
+	phoneNumberHash, err := phoneutil.HashForContactDiscovery(obj.PhoneNumber)
+	if err != nil && err != phoneutil.ErrContactDiscoverySaltNotLoaded {
+		return nil, err
+	}
+
 	return &verificationModel{
-		PhoneNumber:    obj.PhoneNumber,
-		OwnerAccount:   obj.OwnerAccount,
-		CreatedAt:      obj.CreatedAt,
-		LastVerifiedAt: obj.LastVerifiedAt,
+		PhoneNumber:     obj.PhoneNumber,
+		PhoneNumberHash: phoneNumberHash,
+		OwnerAccount:    obj.OwnerAccount,
+		CreatedAt:       obj.CreatedAt,
+		LastVerifiedAt:  obj.LastVerifiedAt,
 	}, nil
 }
 
@@ -57,12 +65,12 @@ func fromVerificationModel(obj *verificationModel) *phone.Verification {
 func (m *verificationModel) dbSave(ctx context.Context, db *sqlx.DB) error {
 	query := `INSERT INTO ` + verificationTableName + `
 		(
-			phone_number, owner_account, created_at, last_verified_at
+			phone_number, owner_account, created_at, last_verified_at, phone_number_hash
 		)
-		VALUES ($1,$2,$3,$4)
+		VALUES ($1,$2,$3,$4,$5)
 		ON CONFLICT (phone_number, owner_account)
 		DO UPDATE
-			SET last_verified_at = $4
+			SET last_verified_at = $4, phone_number_hash = COALESCE($5, ` + verificationTableName + `.phone_number_hash)
 			WHERE ` + verificationTableName + `.phone_number = $1 AND ` + verificationTableName + `.owner_account = $2 AND ` + verificationTableName + `.last_verified_at < $4
 		RETURNING id, phone_number, owner_account, last_verified_at`
 
@@ -73,6 +81,7 @@ func (m *verificationModel) dbSave(ctx context.Context, db *sqlx.DB) error {
 		m.OwnerAccount,
 		m.CreatedAt.UTC(),
 		m.LastVerifiedAt.UTC(),
+		m.PhoneNumberHash,
 	).StructScan(m)
 	return pgutil.CheckNoRows(err, phone.ErrInvalidVerification)
 }
@@ -274,6 +283,76 @@ func dbFilterVerifiedNumbers(ctx context.Context, db *sqlx.DB, phoneNumbers []st
 	return result, nil
 }
 
+func dbGetVerifiedNumberHashesByPrefix(ctx context.Context, db *sqlx.DB, prefixes [][]byte) ([][]byte, error) {
+	var conditions []string
+	var args []any
+	for _, prefix := range prefixes {
+		if len(prefix) == 0 {
+			continue
+		}
+
+		args = append(args, prefix)
+		conditions = append(conditions, fmt.Sprintf("substring(phone_number_hash FROM 1 FOR %d) = $%d", len(prefix), len(args)))
+	}
+
+	if len(conditions) == 0 {
+		return nil, nil
+	}
+
+	query := fmt.Sprintf(
+		"SELECT DISTINCT phone_number_hash FROM %s WHERE %s ORDER BY phone_number_hash ASC",
+		verificationTableName,
+		strings.Join(conditions, " OR "),
+	)
+
+	var result [][]byte
+	err := db.SelectContext(ctx, &result, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func dbBackfillNumberHashes(ctx context.Context, db *sqlx.DB, limit uint64) (uint64, error) {
+	var models []*verificationModel
+
+	query := `SELECT id, phone_number FROM ` + verificationTableName + `
+		WHERE phone_number_hash IS NULL
+		LIMIT $1
+	`
+
+	err := db.SelectContext(ctx, &models, query, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	var updated uint64
+	for _, model := range models {
+		query := `UPDATE ` + verificationTableName + `
+			SET phone_number_hash = $2
+			WHERE id = $1 AND phone_number_hash IS NULL
+		`
+
+		phoneNumberHash, err := phoneutil.HashForContactDiscovery(model.PhoneNumber)
+		if err != nil {
+			return updated, err
+		}
+
+		res, err := db.ExecContext(ctx, query, model.Id.Int64, phoneNumberHash)
+		if err != nil {
+			return updated, err
+		}
+
+		rowsAffected, err := res.RowsAffected()
+		if err != nil {
+			return updated, err
+		}
+		updated += uint64(rowsAffected)
+	}
+
+	return updated, nil
+}
+
 type ownerAccountSettingModel struct {
 	Id            sql.NullInt64 `db:"id"`
 	PhoneNumber   string        `db:"phone_number"`
@@ -96,6 +96,16 @@ func (s *store) FilterVerifiedNumbers(ctx context.Context, phoneNumbers []string
 	return dbFilterVerifiedNumbers(ctx, s.db, phoneNumbers)
 }
 
+// GetVerifiedNumberHashesByPrefix implements phone.Store.GetVerifiedNumberHashesByPrefix
+func (s *store) GetVerifiedNumberHashesByPrefix(ctx context.Context, prefixes [][]byte) ([][]byte, error) {
+	return dbGetVerifiedNumberHashesByPrefix(ctx, s.db, prefixes)
+}
+
+// BackfillNumberHashes implements phone.Store.BackfillNumberHashes
+func (s *store) BackfillNumberHashes(ctx context.Context, limit uint64) (uint64, error) {
+	return dbBackfillNumberHashes(ctx, s.db, limit)
+}
+
 // GetSettings implements phone.Store.GetSettings
 func (s *store) GetSettings(ctx context.Context, phoneNumber string) (*phone.Settings, error) {
 	models, err := dbGetOwnerAccountSettings(ctx, s.db, phoneNumber)
@@ -23,6 +23,7 @@ const (
 			id SERIAL NOT NULL PRIMARY KEY,
 
 			phone_number TEXT NOT NULL,
+			phone_number_hash BYTEA NULL,
 			owner_account TEXT NOT NULL,
 			created_at TIMESTAMP WITH TIME ZONE NOT NULL,
 			last_verified_at TIMESTAMP WITH TIME ZONE NOT NULL,
@@ -30,6 +31,8 @@ const (
 			CONSTRAINT codewallet__core_phoneverification__uniq__owner_account__and__phone_number UNIQUE (owner_account, phone_number)
 		);
 
+		CREATE INDEX codewallet__core_phoneverification__phone_number_hash ON codewallet__core_phoneverification(phone_number_hash);
+
 		CREATE TABLE codewallet__core_phonelinkingtoken(
 			id SERIAL NOT NULL PRIMARY KEY,
 
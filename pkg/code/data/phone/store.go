@@ -116,6 +116,16 @@ type Store interface {
 	// FilterVerifiedNumbers filters phone numbers that have been verified.
 	FilterVerifiedNumbers(ctx context.Context, phoneNumbers []string) ([]string, error)
 
+	// GetVerifiedNumberHashesByPrefix gets the contact discovery hashes of verified
+	// phone numbers that start with any of the provided prefixes. See
+	// phone.HashForContactDiscovery.
+	GetVerifiedNumberHashesByPrefix(ctx context.Context, prefixes [][]byte) ([][]byte, error)
+
+	// BackfillNumberHashes computes contact discovery hashes for up to limit
+	// verifications that were saved before hashes were introduced. The number
+	// of updated verifications is returned, which is zero once complete.
+	BackfillNumberHashes(ctx context.Context, limit uint64) (uint64, error)
+
 	// GetSettings gets settings for a phone number. The implementation guarantee
 	// an empty setting is returned if the DB entry doesn't exist.
 	GetSettings(ctx context.Context, phoneNumber string) (*Settings, error)
@@ -1,8 +1,10 @@
 package tests
 
 import (
+	"bytes"
 	"context"
 	"crypto/ed25519"
+	"crypto/rand"
 	"fmt"
 	"testing"
 	"time"
@@ -25,6 +27,7 @@ func RunTests(t *testing.T, s phone.Store, teardown func()) {
 		testGetAllVerificationsForNumber,
 		testLinkingTokenHappyPath,
 		testFilterVerifiedNumbers,
+		testVerifiedNumberHashes,
 		testSettingsHappyPath,
 		testEventHappyPath,
 	} {
@@ -305,6 +308,70 @@ func testFilterVerifiedNumbers(t *testing.T, s phone.Store) {
 	})
 }
 
+func testVerifiedNumberHashes(t *testing.T, s phone.Store) {
+	t.Run("testVerifiedNumberHashes", func(t *testing.T) {
+		ctx := context.Background()
+
+		pub, _, err := ed25519.GenerateKey(nil)
+		require.NoError(t, err)
+
+		ownerAccount := base58.Encode(pub)
+
+		salt := make([]byte, phoneutil.MinContactDiscoverySaltSize)
+		_, err = rand.Read(salt)
+		require.NoError(t, err)
+		require.NoError(t, phoneutil.LoadContactDiscoverySalt(salt))
+
+		var phoneNumbers []string
+		var hashes [][]byte
+		for i := 0; i < 10; i++ {
+			phoneNumber := fmt.Sprintf("+1800555000%d", i)
+			phoneNumbers = append(phoneNumbers, phoneNumber)
+
+			hash, err := phoneutil.HashForContactDiscovery(phoneNumber)
+			require.NoError(t, err)
+			hashes = append(hashes, hash)
+		}
+
+		matched, err := s.GetVerifiedNumberHashesByPrefix(ctx, [][]byte{hashes[0][:3]})
+		require.NoError(t, err)
+		assert.Empty(t, matched)
+
+		for _, phoneNumber := range phoneNumbers[:len(phoneNumbers)/2] {
+			verification := &phone.Verification{
+				PhoneNumber:    phoneNumber,
+				OwnerAccount:   ownerAccount,
+				CreatedAt:      time.Now(),
+				LastVerifiedAt: time.Now(),
+			}
+			require.NoError(t, s.SaveVerification(ctx, verification))
+		}
+
+		updated, err := s.BackfillNumberHashes(ctx, 100)
+		require.NoError(t, err)
+		assert.EqualValues(t, 0, updated)
+
+		for i, hash := range hashes {
+			matched, err := s.GetVerifiedNumberHashesByPrefix(ctx, [][]byte{hash[:3], hash[:4]})
+			require.NoError(t, err)
+
+			if i < len(hashes)/2 {
+				assert.Contains(t, matched, hash)
+			} else {
+				assert.NotContains(t, matched, hash)
+			}
+
+			for _, match := range matched {
+				assert.True(t, bytes.HasPrefix(match, hash[:3]))
+			}
+		}
+
+		matched, err = s.GetVerifiedNumberHashesByPrefix(ctx, nil)
+		require.NoError(t, err)
+		assert.Empty(t, matched)
+	})
+}
+
 func testSettingsHappyPath(t *testing.T, s phone.Store) {
 	t.Run("testSettingsHappyPath", func(t *testing.T) {
 		ctx := context.Background()
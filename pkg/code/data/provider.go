@@ -2,6 +2,8 @@ package data
 
 import (
 	pg "github.com/code-payments/code-server/pkg/database/postgres"
+	"github.com/code-payments/code-server/pkg/geoip"
+	"github.com/code-payments/code-server/pkg/solana"
 )
 
 const (
@@ -19,11 +21,16 @@ type Provider interface {
 	DatabaseData
 	WebData
 	EstimatedData
+	GeoIPData
 
 	GetBlockchainDataProvider() BlockchainData
 	GetDatabaseDataProvider() DatabaseData
 	GetWebDataProvider() WebData
 	GetEstimatedDataProvider() EstimatedData
+	GetGeoIPDataProvider() GeoIPData
+
+	// Close releases resources held by the underlying providers
+	Close() error
 }
 
 type DataProvider struct {
@@ -31,16 +38,20 @@ type DataProvider struct {
 	*DatabaseProvider
 	*WebProvider
 	*EstimatedProvider
+	*GeoIPProvider
 }
 
-func NewDataProvider(dbConfig *pg.Config, solanaEnv string, configProvider ConfigProvider) (Provider, error) {
-	blockchain, err := NewBlockchainProvider(solanaEnv)
+// NewDataProvider returns a data provider. The pool options configure the RPC
+// pool used for blockchain data, and are passed to NewBlockchainProvider.
+func NewDataProvider(dbConfig *pg.Config, solanaEnv string, configProvider ConfigProvider, poolOpts ...solana.PoolOption) (Provider, error) {
+	blockchain, err := NewBlockchainProvider(solanaEnv, poolOpts...)
 	if err != nil {
 		return nil, err
 	}
 
 	p, err := NewDataProviderWithoutBlockchain(dbConfig, configProvider)
 	if err != nil {
+		blockchain.(*BlockchainProvider).Close()
 		return nil, err
 	}
 
@@ -66,20 +77,39 @@ func NewDataProviderWithoutBlockchain(dbConfig *pg.Config, configProvider Config
 		return nil, err
 	}
 
+	geoIP, err := NewGeoIPProvider(configProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	err = loadContactDiscoverySalt(configProvider)
+	if err != nil {
+		return nil, err
+	}
+
 	provider := &DataProvider{
 		DatabaseProvider:  db.(*DatabaseProvider),
 		WebProvider:       web.(*WebProvider),
 		EstimatedProvider: estimated.(*EstimatedProvider),
+		GeoIPProvider:     geoIP.(*GeoIPProvider),
 	}
 
 	return provider, nil
 }
 
-func NewTestDataProvider() Provider {
-	// todo: This currently only includes database data and should include the
-	//       other provider types.
+// NewTestDataProviderWithClients returns a data provider for tests, backed by
+// in-memory stores and the provided blockchain and GeoIP clients. Most tests
+// should use the simulated ledger backed providers in the data/test package.
+func NewTestDataProviderWithClients(sc solana.Client, geoIPClient geoip.GeoIP) Provider {
+	// todo: This currently only includes database, blockchain and geoip data,
+	//       and should include the other provider types.
 
-	blockchain, err := NewBlockchainProvider("https://api.testnet.solana.com")
+	blockchain, err := NewBlockchainProviderWithClient(sc)
+	if err != nil {
+		panic(err)
+	}
+
+	geoIP, err := NewGeoIPProviderWithClient(geoIPClient)
 	if err != nil {
 		panic(err)
 	}
@@ -87,6 +117,7 @@ func NewTestDataProvider() Provider {
 	return &DataProvider{
 		DatabaseProvider:   NewTestDatabaseProvider().(*DatabaseProvider),
 		BlockchainProvider: blockchain.(*BlockchainProvider),
+		GeoIPProvider:      geoIP.(*GeoIPProvider),
 	}
 }
 
@@ -102,3 +133,13 @@ func (p *DataProvider) GetDatabaseDataProvider() DatabaseData {
 func (p *DataProvider) GetEstimatedDataProvider() EstimatedData {
 	return p.EstimatedProvider
 }
+func (p *DataProvider) GetGeoIPDataProvider() GeoIPData {
+	return p.GeoIPProvider
+}
+
+func (p *DataProvider) Close() error {
+	if p.BlockchainProvider == nil {
+		return nil
+	}
+	return p.BlockchainProvider.Close()
+}
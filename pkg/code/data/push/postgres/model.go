@@ -24,6 +24,7 @@ type model struct {
 	TokenType       uint          `db:"token_type"`
 	IsValid         bool          `db:"is_valid"`
 	AppInstallId    string        `db:"app_install_id"` // Cannot be nullable, since it's a part of a unique constraint
+	Locale          string        `db:"locale"`
 	CreatedAt       time.Time     `db:"created_at"`
 }
 
@@ -38,6 +39,7 @@ func toModel(obj *push.Record) (*model, error) {
 		TokenType:       uint(obj.TokenType),
 		IsValid:         obj.IsValid,
 		AppInstallId:    *pointer.StringOrDefault(obj.AppInstallId, ""),
+		Locale:          *pointer.StringOrDefault(obj.Locale, ""),
 		CreatedAt:       obj.CreatedAt,
 	}, nil
 }
@@ -55,15 +57,16 @@ func fromModel(obj *model) (*push.Record, error) {
 		TokenType:       push.TokenType(obj.TokenType),
 		IsValid:         obj.IsValid,
 		AppInstallId:    pointer.StringIfValid(len(obj.AppInstallId) > 0, obj.AppInstallId),
+		Locale:          pointer.StringIfValid(len(obj.Locale) > 0, obj.Locale),
 		CreatedAt:       obj.CreatedAt,
 	}, nil
 }
 
 func (m *model) dbSave(ctx context.Context, db *sqlx.DB) error {
 	query := `INSERT INTO ` + tableName + `
-		(data_container_id, push_token, token_type, is_valid, app_install_id, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
-		RETURNING id, data_container_id, push_token, token_type, is_valid, app_install_id, created_at
+		(data_container_id, push_token, token_type, is_valid, app_install_id, locale, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, data_container_id, push_token, token_type, is_valid, app_install_id, locale, created_at
 	`
 
 	err := db.QueryRowxContext(
@@ -74,6 +77,7 @@ func (m *model) dbSave(ctx context.Context, db *sqlx.DB) error {
 		m.TokenType,
 		m.IsValid,
 		m.AppInstallId,
+		m.Locale,
 		m.CreatedAt,
 	).StructScan(m)
 
@@ -103,7 +107,7 @@ func dbGetAllValidByDataContainer(ctx context.Context, db *sqlx.DB, id *user.Dat
 	res := []*model{}
 
 	query := `SELECT
-		id, data_container_id, push_token, token_type, is_valid, app_install_id, created_at
+		id, data_container_id, push_token, token_type, is_valid, app_install_id, locale, created_at
 		FROM ` + tableName + `
 		WHERE data_container_id = $1 AND is_valid = true
 	`
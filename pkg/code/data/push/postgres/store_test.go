@@ -29,6 +29,7 @@ const (
 			is_valid BOOL NOT NULL,
 
 			app_install_id TEXT NOT NULL,
+			locale TEXT NOT NULL,
 
 			created_at TIMESTAMP WITH TIME ZONE NOT NULL,
 
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	"golang.org/x/text/language"
 
 	"github.com/code-payments/code-server/pkg/pointer"
 	"github.com/code-payments/code-server/pkg/code/data/user"
@@ -28,6 +29,10 @@ type Record struct {
 
 	AppInstallId *string
 
+	// Locale requested by the client when the token was registered, as a BCP 47
+	// language tag. When set, push text is rendered on the server.
+	Locale *string
+
 	CreatedAt time.Time
 }
 
@@ -39,6 +44,7 @@ func (r *Record) Clone() *Record {
 		TokenType:       r.TokenType,
 		IsValid:         r.IsValid,
 		AppInstallId:    pointer.StringCopy(r.AppInstallId),
+		Locale:          pointer.StringCopy(r.Locale),
 		CreatedAt:       r.CreatedAt,
 	}
 }
@@ -50,6 +56,7 @@ func (r *Record) CopyTo(dst *Record) {
 	dst.TokenType = r.TokenType
 	dst.IsValid = r.IsValid
 	dst.AppInstallId = pointer.StringCopy(r.AppInstallId)
+	dst.Locale = pointer.StringCopy(r.Locale)
 	dst.CreatedAt = r.CreatedAt
 }
 
@@ -70,6 +77,12 @@ func (r *Record) Validate() error {
 		return errors.New("app install id is required when set")
 	}
 
+	if r.Locale != nil {
+		if _, err := language.Parse(*r.Locale); err != nil {
+			return errors.Wrap(err, "invalid locale")
+		}
+	}
+
 	if r.CreatedAt.IsZero() {
 		return errors.New("creation timestamp is required")
 	}
@@ -38,9 +38,11 @@ func testHappyPath(t *testing.T, s push.Store) {
 		for i := 0; i < 5; i++ {
 			tokenType := push.TokenTypeFcmAndroid
 			appInstallId := pointer.String("test_app_install")
+			locale := pointer.String("es-MX")
 			if i%2 == 0 {
 				tokenType = push.TokenTypeFcmApns
 				appInstallId = nil
+				locale = nil
 			}
 
 			record := &push.Record{
@@ -51,6 +53,7 @@ func testHappyPath(t *testing.T, s push.Store) {
 				IsValid:   true,
 
 				AppInstallId: appInstallId,
+				Locale:       locale,
 
 				CreatedAt: time.Now(),
 			}
@@ -75,6 +78,7 @@ func testHappyPath(t *testing.T, s push.Store) {
 			assert.True(t, actual[i].IsValid)
 
 			assert.EqualValues(t, expected[i].AppInstallId, actual[i].AppInstallId)
+			assert.EqualValues(t, expected[i].Locale, actual[i].Locale)
 
 			assert.Equal(t, expected[i].CreatedAt.Unix(), actual[i].CreatedAt.Unix())
 		}
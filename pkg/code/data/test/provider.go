@@ -0,0 +1,26 @@
+package test
+
+import (
+	"github.com/code-payments/code-server/pkg/geoip"
+	memory_geoip "github.com/code-payments/code-server/pkg/geoip/memory"
+	"github.com/code-payments/code-server/pkg/solana/simulator"
+	code_data "github.com/code-payments/code-server/pkg/code/data"
+)
+
+// NewDataProvider returns a data provider for tests, backed by in-memory
+// stores and a simulated Solana ledger.
+func NewDataProvider() code_data.Provider {
+	return NewDataProviderWithGeoIP(memory_geoip.NewGeoIP())
+}
+
+// NewDataProviderWithGeoIP is NewDataProvider, but with the provided GeoIP
+// client, which is typically a pre-populated memory_geoip.GeoIP.
+func NewDataProviderWithGeoIP(geoIPClient geoip.GeoIP) code_data.Provider {
+	return code_data.NewTestDataProviderWithClients(simulator.NewLedger(), geoIPClient)
+}
+
+// NewDataProviderWithLedger is NewDataProvider, but backed by the provided
+// simulated ledger, so tests can set up and inspect on-chain state.
+func NewDataProviderWithLedger(ledger *simulator.Ledger) code_data.Provider {
+	return code_data.NewTestDataProviderWithClients(ledger, memory_geoip.NewGeoIP())
+}
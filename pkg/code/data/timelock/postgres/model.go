@@ -24,6 +24,8 @@ type model struct {
 
 	DataVersion uint `db:"data_version"`
 
+	Mint string `db:"mint"`
+
 	Address string `db:"address"`
 	Bump    uint   `db:"bump"`
 
@@ -57,6 +59,8 @@ func toModel(obj *timelock.Record) (*model, error) {
 	return &model{
 		DataVersion: uint(obj.DataVersion),
 
+		Mint: obj.Mint,
+
 		Address: obj.Address,
 		Bump:    uint(obj.Bump),
 
@@ -89,6 +93,8 @@ func fromModel(obj *model) *timelock.Record {
 
 		DataVersion: timelock_token.TimelockDataVersion(obj.DataVersion),
 
+		Mint: obj.Mint,
+
 		Address: obj.Address,
 		Bump:    uint8(obj.Bump),
 
@@ -112,8 +118,8 @@ func fromModel(obj *model) *timelock.Record {
 func (m *model) dbSave(ctx context.Context, db *sqlx.DB) error {
 	return pgutil.ExecuteInTx(ctx, db, sql.LevelDefault, func(tx *sqlx.Tx) error {
 		query := `INSERT INTO ` + tableName + `
-			(data_version, address, bump, vault_address, vault_bump, vault_owner, vault_state, time_authority, close_authority, num_days_locked, unlock_at, block, last_updated_at)
-			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+			(data_version, address, bump, vault_address, vault_bump, vault_owner, vault_state, time_authority, close_authority, num_days_locked, unlock_at, block, last_updated_at, mint)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 
 			ON CONFLICT (address)
 			DO UPDATE
@@ -121,7 +127,7 @@ func (m *model) dbSave(ctx context.Context, db *sqlx.DB) error {
 				WHERE ` + tableName + `.address = $2 AND ` + tableName + `.vault_address = $4 AND ` + tableName + `.block < $12
 
 			RETURNING
-				id, data_version, address, bump, vault_address, vault_bump, vault_owner, vault_state, time_authority, close_authority, num_days_locked, unlock_at, block, last_updated_at`
+				id, data_version, mint, address, bump, vault_address, vault_bump, vault_owner, vault_state, time_authority, close_authority, num_days_locked, unlock_at, block, last_updated_at`
 
 		m.LastUpdatedAt = time.Now()
 
@@ -148,6 +154,8 @@ func (m *model) dbSave(ctx context.Context, db *sqlx.DB) error {
 			m.Block,
 
 			m.LastUpdatedAt.UTC(),
+
+			m.Mint,
 		).StructScan(m)
 
 		return pgutil.CheckNoRows(err, timelock.ErrStaleTimelockState)
@@ -158,7 +166,7 @@ func dbGetByAddress(ctx context.Context, db *sqlx.DB, address string) (*model, e
 	res := &model{}
 
 	query := `SELECT
-		id, data_version, address, bump, vault_address, vault_bump, vault_owner, vault_state, time_authority, close_authority, num_days_locked, unlock_at, block, last_updated_at
+		id, data_version, mint, address, bump, vault_address, vault_bump, vault_owner, vault_state, time_authority, close_authority, num_days_locked, unlock_at, block, last_updated_at
 		FROM ` + tableName + `
 		WHERE address = $1
 		LIMIT 1`
@@ -174,7 +182,7 @@ func dbGetByVault(ctx context.Context, db *sqlx.DB, vault string) (*model, error
 	res := &model{}
 
 	query := `SELECT
-		id, data_version, address, bump, vault_address, vault_bump, vault_owner, vault_state, time_authority, close_authority, num_days_locked, unlock_at, block, last_updated_at
+		id, data_version, mint, address, bump, vault_address, vault_bump, vault_owner, vault_state, time_authority, close_authority, num_days_locked, unlock_at, block, last_updated_at
 		FROM ` + tableName + `
 		WHERE vault_address = $1
 		LIMIT 1`
@@ -195,7 +203,7 @@ func dbGetByVaultBatch(ctx context.Context, db *sqlx.DB, vaults ...string) ([]*m
 	}
 
 	query := fmt.Sprintf(
-		`SELECT id, data_version, address, bump, vault_address, vault_bump, vault_owner, vault_state, time_authority, close_authority, num_days_locked, unlock_at, block, last_updated_at
+		`SELECT id, data_version, mint, address, bump, vault_address, vault_bump, vault_owner, vault_state, time_authority, close_authority, num_days_locked, unlock_at, block, last_updated_at
 		FROM `+tableName+`
 		WHERE vault_address IN (%s)`,
 		strings.Join(individualFilters, ", "),
@@ -215,7 +223,7 @@ func dbGetAllByState(ctx context.Context, db *sqlx.DB, state timelock_token.Time
 	res := []*model{}
 
 	query := `SELECT
-		id, data_version, address, bump, vault_address, vault_bump, vault_owner, vault_state, time_authority, close_authority, num_days_locked, unlock_at, block, last_updated_at
+		id, data_version, mint, address, bump, vault_address, vault_bump, vault_owner, vault_state, time_authority, close_authority, num_days_locked, unlock_at, block, last_updated_at
 		FROM ` + tableName + `
 		WHERE (vault_state = $1)
 	`
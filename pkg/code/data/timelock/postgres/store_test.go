@@ -24,6 +24,8 @@ const (
 
 			data_version INTEGER NOT NULL,
 
+			mint TEXT NOT NULL DEFAULT '',
+
 			address TEXT NOT NULL,
 			bump INTEGER NOT NULL,
 
@@ -36,6 +36,8 @@ func testHappyPath(t *testing.T, s timelock.Store) {
 		expected := &timelock.Record{
 			DataVersion: timelock_token.DataVersion1,
 
+			Mint: "mint",
+
 			Address: "state",
 			Bump:    254,
 
@@ -347,6 +349,8 @@ func testGetCountByState(t *testing.T, s timelock.Store) {
 func assertEquivalentRecords(t *testing.T, obj1, obj2 *timelock.Record) {
 	assert.Equal(t, obj1.DataVersion, obj2.DataVersion)
 
+	assert.Equal(t, obj1.Mint, obj2.Mint)
+
 	assert.Equal(t, obj1.Address, obj2.Address)
 	assert.Equal(t, obj1.Bump, obj2.Bump)
 
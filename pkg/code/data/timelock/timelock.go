@@ -26,6 +26,10 @@ type Record struct {
 
 	DataVersion timelock_token_v1.TimelockDataVersion
 
+	// Mint is the token mint for the vault. It's empty for records created
+	// before multi-mint support, which are always Kin.
+	Mint string
+
 	Address string
 	Bump    uint8
 
@@ -164,6 +168,8 @@ func (r *Record) Clone() *Record {
 
 		DataVersion: r.DataVersion,
 
+		Mint: r.Mint,
+
 		Address: r.Address,
 		Bump:    r.Bump,
 
@@ -195,6 +201,8 @@ func (r *Record) CopyTo(dst *Record) {
 
 	dst.DataVersion = r.DataVersion
 
+	dst.Mint = r.Mint
+
 	dst.Address = r.Address
 	dst.Bump = r.Bump
 
@@ -34,6 +34,23 @@ const (
 	ConfirmationFailed
 )
 
+func (c Confirmation) String() string {
+	switch c {
+	case ConfirmationUnknown:
+		return "unknown"
+	case ConfirmationPending:
+		return "pending"
+	case ConfirmationConfirmed:
+		return "confirmed"
+	case ConfirmationFinalized:
+		return "finalized"
+	case ConfirmationFailed:
+		return "failed"
+	}
+
+	return "unknown"
+}
+
 // An atomic transaction that contains a set of digital signatures of a
 // serialized [`Message`], signed by the first `signatures.len()` keys of
 // [`account_keys`].
@@ -117,7 +134,12 @@ func FromBlockTransaction(tx *solana.BlockTransaction, slot uint64, t time.Time)
 		res.Fee = &tx.Meta.Fee
 	}
 
-	tokenBalances, err := getTokenBalanceSet(tx.Meta, tx.Transaction.Message.Accounts)
+	accounts, err := getAccountKeys(tx.Meta, tx.Transaction.Message)
+	if err != nil {
+		return nil, err
+	}
+
+	tokenBalances, err := getTokenBalanceSet(tx.Meta, accounts)
 	if err != nil {
 		return nil, err
 	}
@@ -146,7 +168,12 @@ func FromConfirmedTransaction(tx *solana.ConfirmedTransaction) (*Record, error)
 		res.BlockTime = *tx.BlockTime
 	}
 
-	tokenBalances, err := getTokenBalanceSet(tx.Meta, tx.Transaction.Message.Accounts)
+	accounts, err := getAccountKeys(tx.Meta, tx.Transaction.Message)
+	if err != nil {
+		return nil, err
+	}
+
+	tokenBalances, err := getTokenBalanceSet(tx.Meta, accounts)
 	if err != nil {
 		return nil, err
 	}
@@ -199,6 +226,25 @@ func FromLocalSimulation(tx *solana.Transaction, pre, post map[string]uint64) (*
 	return res, nil
 }
 
+// getAccountKeys returns the accounts indexed by the transaction's token balances,
+// which includes any accounts loaded from address lookup tables in versioned
+// transactions.
+func getAccountKeys(meta *solana.TransactionMeta, m solana.Message) ([]ed25519.PublicKey, error) {
+	if meta == nil || len(m.AddressTableLookups) == 0 {
+		return m.Accounts, nil
+	}
+
+	accounts := append([]ed25519.PublicKey{}, m.Accounts...)
+	for _, loaded := range append(meta.LoadedAddresses.Writable, meta.LoadedAddresses.Readonly...) {
+		decoded, err := base58.Decode(loaded)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, decoded)
+	}
+	return accounts, nil
+}
+
 func getTokenBalanceSet(meta *solana.TransactionMeta, accounts []ed25519.PublicKey) ([]*TokenBalance, error) {
 	txBalances := map[string]*TokenBalance{}
 
@@ -164,6 +164,42 @@ func (s *store) GetTotalAvailableFunds(_ context.Context, vault string) (uint64,
 	return uint64(res), nil
 }
 
+// GetFundingCountByVaultAndState implements treasury.Store.GetFundingCountByVaultAndState
+func (s *store) GetFundingCountByVaultAndState(_ context.Context, vault string, state treasury.FundingState) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var res uint64
+	for _, item := range s.findFundingByVault(vault) {
+		if item.State == state {
+			res++
+		}
+	}
+	return res, nil
+}
+
+// GetAllFundingCreatedAfter implements treasury.Store.GetAllFundingCreatedAfter
+func (s *store) GetAllFundingCreatedAfter(_ context.Context, createdAfter time.Time) ([]*treasury.FundingHistoryRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var res []*treasury.FundingHistoryRecord
+	for _, item := range s.fundingRecords {
+		if item.CreatedAt.After(createdAfter) {
+			res = append(res, item.Clone())
+		}
+	}
+
+	if len(res) == 0 {
+		return nil, treasury.ErrFundingNotFound
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Id < res[j].Id
+	})
+	return res, nil
+}
+
 func (s *store) findTreasuryPool(data *treasury.Record) *treasury.Record {
 	for _, item := range s.treasuryPoolRecords {
 		if item.Id == data.Id {
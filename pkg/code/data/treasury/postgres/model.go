@@ -372,3 +372,36 @@ func dbGetTotalAvailableFunds(ctx context.Context, db *sqlx.DB, vault string) (u
 	}
 	return uint64(res), nil
 }
+
+func dbGetFundingCountByVaultAndState(ctx context.Context, db *sqlx.DB, vault string, state treasury.FundingState) (uint64, error) {
+	var res uint64
+
+	query := `SELECT COUNT(*) FROM ` + fundingTableName + `
+		WHERE vault = $1 AND state = $2
+	`
+
+	err := db.GetContext(ctx, &res, query, vault, state)
+	if err != nil {
+		return 0, err
+	}
+	return res, nil
+}
+
+func dbGetAllFundingCreatedAfter(ctx context.Context, db *sqlx.DB, createdAfter time.Time) ([]*fundingModel, error) {
+	res := []*fundingModel{}
+
+	query := `SELECT id, vault, delta_quarks, transaction_id, state, created_at FROM ` + fundingTableName + `
+		WHERE created_at > $1
+		ORDER BY id ASC
+	`
+
+	err := db.SelectContext(ctx, &res, query, createdAfter)
+	if err != nil {
+		return nil, pgutil.CheckNoRows(err, treasury.ErrFundingNotFound)
+	}
+
+	if len(res) == 0 {
+		return nil, treasury.ErrFundingNotFound
+	}
+	return res, nil
+}
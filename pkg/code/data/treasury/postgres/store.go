@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 
@@ -103,3 +104,22 @@ func (s *store) SaveFunding(ctx context.Context, record *treasury.FundingHistory
 func (s *store) GetTotalAvailableFunds(ctx context.Context, vault string) (uint64, error) {
 	return dbGetTotalAvailableFunds(ctx, s.db, vault)
 }
+
+// GetFundingCountByVaultAndState implements treasury.Store.GetFundingCountByVaultAndState
+func (s *store) GetFundingCountByVaultAndState(ctx context.Context, vault string, state treasury.FundingState) (uint64, error) {
+	return dbGetFundingCountByVaultAndState(ctx, s.db, vault, state)
+}
+
+// GetAllFundingCreatedAfter implements treasury.Store.GetAllFundingCreatedAfter
+func (s *store) GetAllFundingCreatedAfter(ctx context.Context, createdAfter time.Time) ([]*treasury.FundingHistoryRecord, error) {
+	models, err := dbGetAllFundingCreatedAfter(ctx, s.db, createdAfter)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*treasury.FundingHistoryRecord, len(models))
+	for i, model := range models {
+		res[i] = fromFundingModel(model)
+	}
+	return res, nil
+}
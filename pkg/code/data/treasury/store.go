@@ -3,6 +3,7 @@ package treasury
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/code-payments/code-server/pkg/database/query"
 )
@@ -12,6 +13,7 @@ var (
 	ErrTreasuryPoolBlockhashNotFound = errors.New("treasury pool blockhash not found")
 	ErrStaleTreasuryPoolState        = errors.New("treasury pool state is stale")
 	ErrNegativeFunding               = errors.New("treasury pool has negative funding")
+	ErrFundingNotFound               = errors.New("no funding history records could be found")
 )
 
 type Store interface {
@@ -35,4 +37,12 @@ type Store interface {
 
 	// GetTotalAvailableFunds gets the total available funds for a treasury pool's vault
 	GetTotalAvailableFunds(ctx context.Context, vault string) (uint64, error)
+
+	// GetFundingCountByVaultAndState gets the count of funding history records for
+	// a treasury pool's vault in the provided state
+	GetFundingCountByVaultAndState(ctx context.Context, vault string, state FundingState) (uint64, error)
+
+	// GetAllFundingCreatedAfter gets all funding history records, across all treasury
+	// pools, that were created after the provided timestamp in ascending order
+	GetAllFundingCreatedAfter(ctx context.Context, createdAfter time.Time) ([]*FundingHistoryRecord, error)
 }
@@ -18,6 +18,7 @@ func RunTests(t *testing.T, s treasury.Store, teardown func()) {
 		testTreasuryPoolHappyPath,
 		testGetAllByState,
 		testFundingHappyPath,
+		testFundingHistoryQueries,
 	} {
 		tf(t, s)
 		teardown()
@@ -241,6 +242,53 @@ func testFundingHappyPath(t *testing.T, s treasury.Store) {
 	})
 }
 
+func testFundingHistoryQueries(t *testing.T, s treasury.Store) {
+	t.Run("testFundingHistoryQueries", func(t *testing.T) {
+		ctx := context.Background()
+
+		start := time.Now()
+
+		count, err := s.GetFundingCountByVaultAndState(ctx, "vault1", treasury.FundingStatePending)
+		require.NoError(t, err)
+		assert.EqualValues(t, 0, count)
+
+		_, err = s.GetAllFundingCreatedAfter(ctx, start.Add(-time.Hour))
+		assert.Equal(t, treasury.ErrFundingNotFound, err)
+
+		records := []*treasury.FundingHistoryRecord{
+			{Vault: "vault1", DeltaQuarks: 1, TransactionId: "txn1", State: treasury.FundingStateConfirmed, CreatedAt: start.Add(-2 * time.Hour)},
+			{Vault: "vault1", DeltaQuarks: 10, TransactionId: "txn2", State: treasury.FundingStatePending, CreatedAt: start.Add(-time.Minute)},
+			{Vault: "vault1", DeltaQuarks: 100, TransactionId: "txn3", State: treasury.FundingStatePending, CreatedAt: start},
+			{Vault: "vault2", DeltaQuarks: 1000, TransactionId: "txn4", State: treasury.FundingStatePending, CreatedAt: start},
+		}
+		for _, record := range records {
+			require.NoError(t, s.SaveFunding(ctx, record))
+		}
+
+		count, err = s.GetFundingCountByVaultAndState(ctx, "vault1", treasury.FundingStatePending)
+		require.NoError(t, err)
+		assert.EqualValues(t, 2, count)
+
+		count, err = s.GetFundingCountByVaultAndState(ctx, "vault1", treasury.FundingStateConfirmed)
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, count)
+
+		actual, err := s.GetAllFundingCreatedAfter(ctx, start.Add(-time.Hour))
+		require.NoError(t, err)
+		require.Len(t, actual, 3)
+		assert.Equal(t, "txn2", actual[0].TransactionId)
+		assert.Equal(t, "txn3", actual[1].TransactionId)
+		assert.Equal(t, "txn4", actual[2].TransactionId)
+
+		records[1].State = treasury.FundingStateConfirmed
+		require.NoError(t, s.SaveFunding(ctx, records[1]))
+
+		count, err = s.GetFundingCountByVaultAndState(ctx, "vault1", treasury.FundingStatePending)
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, count)
+	})
+}
+
 func assertEquivalentTreasuryPoolRecords(t *testing.T, obj1, obj2 *treasury.Record) {
 	assert.Equal(t, obj1.DataVersion, obj2.DataVersion)
 
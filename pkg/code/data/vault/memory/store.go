@@ -172,7 +172,20 @@ func (s *store) GetAllByState(ctx context.Context, state vault.State, cursor que
 			return nil, vault.ErrKeyNotFound
 		}
 
-		return res, nil
+		keys := make([]*vault.Record, len(res))
+		for i, item := range res {
+			val, err := vault.Decrypt(item.PrivateKey, item.PublicKey)
+			if err != nil {
+				return nil, err
+			}
+
+			cloned := item.Clone()
+			cloned.PrivateKey = val
+
+			keys[i] = &cloned
+		}
+
+		return keys, nil
 	}
 
 	return nil, vault.ErrKeyNotFound
@@ -0,0 +1,112 @@
+package risk
+
+import (
+	"bytes"
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/code-payments/code-server/pkg/config"
+	"github.com/code-payments/code-server/pkg/config/dynamic"
+	"github.com/code-payments/code-server/pkg/metrics"
+	code_data "github.com/code-payments/code-server/pkg/code/data"
+	"github.com/code-payments/code-server/pkg/code/data/event"
+)
+
+const (
+	// RulesetConfigKey is the dynamic config key for the JSON-encoded ruleset
+	RulesetConfigKey = "EVENT_RISK_RULESET"
+
+	metricsStructName = "risk.engine"
+)
+
+// Engine scores events against a ruleset to produce a spam confidence. Each
+// triggered rule contributes its score, and scores are combined such that
+// the result is the probability at least one rule is a true positive, which
+// keeps the result in the range [0, 1].
+//
+// The ruleset is reloaded whenever its config value changes. Invalid rulesets
+// are logged and ignored in favour of the last valid one.
+type Engine struct {
+	log     *logrus.Entry
+	data    code_data.Provider
+	ruleset config.Bytes
+
+	mu       sync.Mutex
+	lastRaw  []byte
+	compiled []*compiledRule
+}
+
+// NewEngine returns a new Engine using the ruleset provided by the config
+func NewEngine(data code_data.Provider, ruleset config.Bytes) *Engine {
+	return &Engine{
+		log:     logrus.StandardLogger().WithField("type", "event/risk/engine"),
+		data:    data,
+		ruleset: ruleset,
+	}
+}
+
+// NewEngineFromSource returns a new Engine using the ruleset stored under
+// RulesetConfigKey in the dynamic config source
+func NewEngineFromSource(data code_data.Provider, source config.Source) *Engine {
+	return NewEngine(data, dynamic.NewBytesConfig(source, RulesetConfigKey, nil))
+}
+
+// Score returns the spam confidence for the event, in the range [0, 1]
+func (e *Engine) Score(ctx context.Context, record *event.Record) (float64, error) {
+	tracer := metrics.TraceMethodCall(ctx, metricsStructName, "Score")
+	defer tracer.End()
+
+	rules := e.getRules(ctx)
+
+	notSpam := 1.0
+	for _, rule := range rules {
+		if !rule.appliesTo(record.EventType) {
+			continue
+		}
+
+		triggered, err := rule.rule.Evaluate(ctx, e.data, record)
+		if err != nil {
+			tracer.OnError(err)
+			return 0, errors.Wrapf(err, "error evaluating %s rule", rule.ruleType)
+		}
+
+		if triggered {
+			notSpam *= 1 - rule.score
+		}
+	}
+
+	return 1 - notSpam, nil
+}
+
+func (e *Engine) getRules(ctx context.Context) []*compiledRule {
+	raw := e.ruleset.Get(ctx)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.lastRaw != nil && bytes.Equal(raw, e.lastRaw) {
+		return e.compiled
+	}
+
+	// Avoid retrying the same invalid ruleset on every call
+	e.lastRaw = append([]byte{}, raw...)
+
+	ruleset, err := ParseRuleset(raw)
+	if err != nil {
+		e.log.WithError(err).Warn("invalid ruleset, using last valid ruleset")
+		return e.compiled
+	}
+
+	compiled, err := compileRuleset(ruleset)
+	if err != nil {
+		e.log.WithError(err).Warn("invalid ruleset, using last valid ruleset")
+		return e.compiled
+	}
+
+	e.log.Infof("loaded ruleset with %d rules", len(compiled))
+	e.compiled = compiled
+	return e.compiled
+}
@@ -0,0 +1,210 @@
+package risk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/code-payments/code-server/pkg/config/memory"
+	"github.com/code-payments/code-server/pkg/config/wrapper"
+	"github.com/code-payments/code-server/pkg/pointer"
+	code_data "github.com/code-payments/code-server/pkg/code/data"
+	datatest "github.com/code-payments/code-server/pkg/code/data/test"
+	"github.com/code-payments/code-server/pkg/code/data/event"
+	"github.com/code-payments/code-server/pkg/code/data/intent"
+)
+
+func TestEngine_VelocityRule(t *testing.T) {
+	env := setup(t, `{
+		"rules": [
+			{"type": "velocity", "score": 0.5, "event_types": ["remote_send"], "params": {"window": "1h", "max_events": 2}},
+			{"type": "velocity", "score": 0.5, "event_types": ["remote_send"], "params": {"window": "1h", "max_events": 2, "by": "ip"}}
+		]
+	}`)
+
+	for i := 0; i < 2; i++ {
+		env.assertScore(t, env.newEvent(event.RemoteSend, "+12223334444", "1.1.1.1"), 0, true)
+	}
+
+	// Identity velocity exceeded
+	env.assertScore(t, env.newEvent(event.RemoteSend, "+12223334444", "2.2.2.2"), 0.5, true)
+
+	// Different event types aren't counted
+	env.assertScore(t, env.newEvent(event.Withdrawal, "+12223334444", "1.1.1.1"), 0, true)
+
+	// IP velocity exceeded, but not identity velocity
+	env.assertScore(t, env.newEvent(event.RemoteSend, "+15556667777", "1.1.1.1"), 0.5, true)
+
+	// Both exceeded
+	env.assertScore(t, env.newEvent(event.RemoteSend, "+12223334444", "1.1.1.1"), 0.75, true)
+
+	// Rescoring a saved event doesn't count the event itself
+	record, err := env.data.GetEvent(env.ctx, "event0")
+	require.NoError(t, err)
+	env.assertScore(t, record, 0.75, false)
+}
+
+func TestEngine_GeoMismatchRule(t *testing.T) {
+	env := setup(t, `{"rules": [{"type": "geo_mismatch", "score": 0.4}]}`)
+
+	record := env.newEvent(event.RemoteSend, "+12223334444", "1.1.1.1")
+	record.SourceClientCountry = pointer.String("CA")
+	env.assertScore(t, record, 0, true)
+
+	record.DestinationClientCountry = pointer.String("ca")
+	env.assertScore(t, record, 0, true)
+
+	record.DestinationClientCountry = pointer.String("US")
+	env.assertScore(t, record, 0.4, true)
+}
+
+func TestEngine_NewAccountLargeWithdrawalRule(t *testing.T) {
+	env := setup(t, `{"rules": [{"type": "new_account_large_withdrawal", "score": 0.9, "params": {"max_account_age": "24h", "min_usd_value": 100}}]}`)
+
+	record := env.newEvent(event.Withdrawal, "+12223334444", "1.1.1.1")
+	record.UsdValue = pointer.Float64(250)
+
+	// Account created prior to intents
+	env.assertScore(t, record, 0, false)
+
+	openAccountsRecord := &intent.Record{
+		IntentId:   "open-accounts",
+		IntentType: intent.OpenAccounts,
+
+		InitiatorOwnerAccount: record.SourceCodeAccount,
+		InitiatorPhoneNumber:  pointer.String(record.SourceIdentity),
+
+		OpenAccountsMetadata: &intent.OpenAccountsMetadata{},
+
+		State: intent.StateConfirmed,
+
+		CreatedAt: time.Now().Add(-time.Hour),
+	}
+	require.NoError(t, env.data.SaveIntent(env.ctx, openAccountsRecord))
+	env.assertScore(t, record, 0.9, false)
+
+	// Small withdrawal
+	record.UsdValue = pointer.Float64(50)
+	env.assertScore(t, record, 0, false)
+
+	// Other event types
+	record.EventType = event.RemoteSend
+	record.UsdValue = pointer.Float64(250)
+	env.assertScore(t, record, 0, false)
+
+	// Older account
+	record.EventType = event.Withdrawal
+	record.SourceCodeAccount = "older-owner"
+	cloned := openAccountsRecord.Clone()
+	openAccountsRecord = &cloned
+	openAccountsRecord.Id = 0
+	openAccountsRecord.IntentId = "older-open-accounts"
+	openAccountsRecord.InitiatorOwnerAccount = record.SourceCodeAccount
+	openAccountsRecord.CreatedAt = time.Now().Add(-48 * time.Hour)
+	require.NoError(t, env.data.SaveIntent(env.ctx, openAccountsRecord))
+	env.assertScore(t, record, 0, false)
+}
+
+func TestEngine_RulesetReloading(t *testing.T) {
+	env := setup(t, "")
+
+	record := env.newEvent(event.RemoteSend, "+12223334444", "1.1.1.1")
+	record.SourceClientCountry = pointer.String("CA")
+	record.DestinationClientCountry = pointer.String("US")
+
+	// No ruleset
+	env.assertScore(t, record, 0, false)
+
+	env.rulesetConfig.SetValue([]byte(`{"rules": [{"type": "geo_mismatch", "score": 0.4}]}`))
+	env.assertScore(t, record, 0.4, false)
+
+	// Invalid rulesets are ignored
+	for _, invalid := range []string{
+		`not json`,
+		`{"rules": [{"type": "unknown", "score": 0.4}]}`,
+		`{"rules": [{"type": "geo_mismatch", "score": 1.5}]}`,
+		`{"rules": [{"type": "geo_mismatch", "score": 0.4, "event_types": ["unknown"]}]}`,
+		`{"rules": [{"type": "velocity", "score": 0.4, "params": {"window": "1h"}}]}`,
+		`{"rules": [{"type": "velocity", "score": 0.4, "params": {"window": "1h", "max_events": 1, "by": "device"}}]}`,
+		`{"rules": [{"type": "geo_mismatch", "score": 0.4, "params": {"unknown": true}}]}`,
+	} {
+		env.rulesetConfig.SetValue([]byte(invalid))
+		env.assertScore(t, record, 0.4, false)
+	}
+
+	// Rules apply only to configured event types
+	env.rulesetConfig.SetValue([]byte(`{"rules": [{"type": "geo_mismatch", "score": 0.8, "event_types": ["withdrawal"]}]}`))
+	env.assertScore(t, record, 0, false)
+
+	record.EventType = event.Withdrawal
+	env.assertScore(t, record, 0.8, false)
+
+	// Custom rules can be registered
+	RegisterRule("test_always", func(_ json.RawMessage) (Rule, error) {
+		return &alwaysRule{}, nil
+	})
+	env.rulesetConfig.SetValue([]byte(`{"rules": [{"type": "test_always", "score": 0.2}]}`))
+	env.assertScore(t, record, 0.2, false)
+}
+
+type alwaysRule struct{}
+
+func (r *alwaysRule) Evaluate(_ context.Context, _ code_data.Provider, _ *event.Record) (bool, error) {
+	return true, nil
+}
+
+type testEnv struct {
+	ctx           context.Context
+	data          code_data.Provider
+	rulesetConfig *memory.Config
+	engine        *Engine
+	nextEventId   int
+}
+
+func setup(t *testing.T, ruleset string) *testEnv {
+	data := datatest.NewDataProvider()
+
+	var value interface{}
+	if len(ruleset) > 0 {
+		value = []byte(ruleset)
+	}
+	rulesetConfig := memory.NewConfig(value)
+
+	return &testEnv{
+		ctx:           context.Background(),
+		data:          data,
+		rulesetConfig: rulesetConfig,
+		engine:        NewEngine(data, wrapper.NewBytesConfig(rulesetConfig, nil)),
+	}
+}
+
+func (e *testEnv) newEvent(eventType event.EventType, identity, ip string) *event.Record {
+	record := &event.Record{
+		EventId:   fmt.Sprintf("event%d", e.nextEventId),
+		EventType: eventType,
+
+		SourceCodeAccount: "owner",
+		SourceIdentity:    identity,
+		SourceClientIp:    pointer.String(ip),
+
+		CreatedAt: time.Now(),
+	}
+	e.nextEventId++
+	return record
+}
+
+func (e *testEnv) assertScore(t *testing.T, record *event.Record, expected float64, save bool) {
+	actual, err := e.engine.Score(e.ctx, record)
+	require.NoError(t, err)
+	assert.InDelta(t, expected, actual, 0.0001)
+
+	if save {
+		record.SpamConfidence = actual
+		require.NoError(t, e.data.SaveEvent(e.ctx, record))
+	}
+}
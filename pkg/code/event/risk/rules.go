@@ -0,0 +1,175 @@
+package risk
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	code_data "github.com/code-payments/code-server/pkg/code/data"
+	"github.com/code-payments/code-server/pkg/code/data/event"
+	"github.com/code-payments/code-server/pkg/code/data/intent"
+)
+
+const (
+	VelocityRuleType                  = "velocity"
+	GeoMismatchRuleType               = "geo_mismatch"
+	NewAccountLargeWithdrawalRuleType = "new_account_large_withdrawal"
+)
+
+func init() {
+	RegisterRule(VelocityRuleType, newVelocityRule)
+	RegisterRule(GeoMismatchRuleType, newGeoMismatchRule)
+	RegisterRule(NewAccountLargeWithdrawalRuleType, newNewAccountLargeWithdrawalRule)
+}
+
+// duration is a time.Duration that's JSON-encoded as a string (eg. "1h")
+type duration time.Duration
+
+func (d *duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+
+	*d = duration(parsed)
+	return nil
+}
+
+// velocityRule is triggered when the source of an event has produced too many
+// events of the same type within a time window
+type velocityRule struct {
+	Window    duration `json:"window"`
+	MaxEvents uint64   `json:"max_events"`
+
+	// By is the source attribute to group events by: "identity" (default) or "ip"
+	By string `json:"by"`
+}
+
+func newVelocityRule(params json.RawMessage) (Rule, error) {
+	var rule velocityRule
+	if err := unmarshalParams(params, &rule); err != nil {
+		return nil, err
+	}
+
+	if rule.Window <= 0 {
+		return nil, errors.New("window must be positive")
+	}
+
+	if rule.MaxEvents == 0 {
+		return nil, errors.New("max events must be positive")
+	}
+
+	switch rule.By {
+	case "":
+		rule.By = "identity"
+	case "identity", "ip":
+	default:
+		return nil, errors.Errorf("unsupported grouping %q", rule.By)
+	}
+
+	return &rule, nil
+}
+
+func (r *velocityRule) Evaluate(ctx context.Context, data code_data.Provider, record *event.Record) (bool, error) {
+	since := time.Now().Add(-time.Duration(r.Window))
+
+	var count uint64
+	var err error
+	switch r.By {
+	case "ip":
+		if record.SourceClientIp == nil {
+			return false, nil
+		}
+		count, err = data.CountEventsBySourceClientIpAndTypeSince(ctx, *record.SourceClientIp, record.EventType, since)
+	default:
+		count, err = data.CountEventsBySourceIdentityAndTypeSince(ctx, record.SourceIdentity, record.EventType, since)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	// Don't count the event itself when it's being rescored after an update
+	if record.Id != 0 && record.CreatedAt.After(since) && count > 0 {
+		count--
+	}
+
+	return count >= r.MaxEvents, nil
+}
+
+// geoMismatchRule is triggered when the source and destination of an event are
+// in different countries
+type geoMismatchRule struct{}
+
+func newGeoMismatchRule(params json.RawMessage) (Rule, error) {
+	var rule geoMismatchRule
+	if err := unmarshalParams(params, &rule); err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (r *geoMismatchRule) Evaluate(_ context.Context, _ code_data.Provider, record *event.Record) (bool, error) {
+	if record.SourceClientCountry == nil || record.DestinationClientCountry == nil {
+		return false, nil
+	}
+
+	return !strings.EqualFold(*record.SourceClientCountry, *record.DestinationClientCountry), nil
+}
+
+// newAccountLargeWithdrawalRule is triggered when a recently created account
+// withdraws a large amount
+type newAccountLargeWithdrawalRule struct {
+	MaxAccountAge duration `json:"max_account_age"`
+	MinUsdValue   float64  `json:"min_usd_value"`
+}
+
+func newNewAccountLargeWithdrawalRule(params json.RawMessage) (Rule, error) {
+	var rule newAccountLargeWithdrawalRule
+	if err := unmarshalParams(params, &rule); err != nil {
+		return nil, err
+	}
+
+	if rule.MaxAccountAge <= 0 {
+		return nil, errors.New("max account age must be positive")
+	}
+
+	return &rule, nil
+}
+
+func (r *newAccountLargeWithdrawalRule) Evaluate(ctx context.Context, data code_data.Provider, record *event.Record) (bool, error) {
+	if record.EventType != event.Withdrawal {
+		return false, nil
+	}
+
+	if record.UsdValue == nil || *record.UsdValue < r.MinUsdValue {
+		return false, nil
+	}
+
+	openAccountsRecord, err := data.GetLatestIntentByInitiatorAndType(ctx, intent.OpenAccounts, record.SourceCodeAccount)
+	if err == intent.ErrIntentNotFound {
+		// Accounts created prior to intents can't be new
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return time.Since(openAccountsRecord.CreatedAt) < time.Duration(r.MaxAccountAge), nil
+}
+
+func unmarshalParams(params json.RawMessage, dst interface{}) error {
+	if len(params) == 0 {
+		return nil
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(string(params)))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(dst)
+}
@@ -0,0 +1,149 @@
+package risk
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	code_data "github.com/code-payments/code-server/pkg/code/data"
+	"github.com/code-payments/code-server/pkg/code/data/event"
+)
+
+// Rule evaluates whether an event exhibits a particular risk signal
+type Rule interface {
+	// Evaluate returns whether the rule is triggered by the event. Events are
+	// scored before they're saved, and again when updated with details that
+	// are provided later (eg. destination metadata), so rules that query
+	// historical data must account for the event itself possibly being saved.
+	Evaluate(ctx context.Context, data code_data.Provider, record *event.Record) (bool, error)
+}
+
+// RuleFactory creates a Rule from its JSON-encoded parameters
+type RuleFactory func(params json.RawMessage) (Rule, error)
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = make(map[string]RuleFactory)
+)
+
+// RegisterRule registers a rule type, so it can be referenced in rulesets.
+// Registering the same type twice replaces the previous factory.
+func RegisterRule(ruleType string, factory RuleFactory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+
+	factories[ruleType] = factory
+}
+
+func getRuleFactory(ruleType string) (RuleFactory, bool) {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+
+	factory, ok := factories[ruleType]
+	return factory, ok
+}
+
+// RuleConfig is the configuration for a single rule within a ruleset
+type RuleConfig struct {
+	// Type is the registered rule type
+	Type string `json:"type"`
+
+	// Score is the spam confidence contributed when the rule is triggered,
+	// in the range (0, 1]
+	Score float64 `json:"score"`
+
+	// EventTypes limits the rule to a set of event types (eg. "withdrawal").
+	// The rule applies to all event types when empty.
+	EventTypes []string `json:"event_types,omitempty"`
+
+	// Params are the rule type specific parameters
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+// Ruleset is the set of rules used to score events. It's expected to be
+// provided as JSON via a file or database backed dynamic config source.
+type Ruleset struct {
+	Rules []*RuleConfig `json:"rules"`
+}
+
+type compiledRule struct {
+	ruleType   string
+	score      float64
+	eventTypes map[event.EventType]struct{}
+	rule       Rule
+}
+
+func (r *compiledRule) appliesTo(eventType event.EventType) bool {
+	if len(r.eventTypes) == 0 {
+		return true
+	}
+
+	_, ok := r.eventTypes[eventType]
+	return ok
+}
+
+// ParseRuleset parses a JSON-encoded ruleset
+func ParseRuleset(raw []byte) (*Ruleset, error) {
+	var ruleset Ruleset
+	if len(strings.TrimSpace(string(raw))) == 0 {
+		return &ruleset, nil
+	}
+
+	err := json.Unmarshal(raw, &ruleset)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid ruleset")
+	}
+	return &ruleset, nil
+}
+
+func compileRuleset(ruleset *Ruleset) ([]*compiledRule, error) {
+	var res []*compiledRule
+	for i, config := range ruleset.Rules {
+		if config == nil {
+			return nil, errors.Errorf("rule %d is empty", i)
+		}
+
+		factory, ok := getRuleFactory(config.Type)
+		if !ok {
+			return nil, errors.Errorf("rule %d has unknown type %q", i, config.Type)
+		}
+
+		if config.Score <= 0 || config.Score > 1 {
+			return nil, errors.Errorf("rule %d score must be in the range (0, 1]", i)
+		}
+
+		eventTypes := make(map[event.EventType]struct{})
+		for _, name := range config.EventTypes {
+			eventType, ok := parseEventType(name)
+			if !ok {
+				return nil, errors.Errorf("rule %d has unknown event type %q", i, name)
+			}
+			eventTypes[eventType] = struct{}{}
+		}
+
+		rule, err := factory(config.Params)
+		if err != nil {
+			return nil, errors.Wrapf(err, "rule %d has invalid params", i)
+		}
+
+		res = append(res, &compiledRule{
+			ruleType:   config.Type,
+			score:      config.Score,
+			eventTypes: eventTypes,
+			rule:       rule,
+		})
+	}
+	return res, nil
+}
+
+func parseEventType(name string) (event.EventType, bool) {
+	for eventType := event.AccountCreated; eventType <= event.Withdrawal; eventType++ {
+		if eventType.String() == strings.ToLower(strings.TrimSpace(name)) {
+			return eventType, true
+		}
+	}
+	return event.UnknownEvent, false
+}
@@ -0,0 +1,64 @@
+package exchangerate
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	currency_lib "github.com/code-payments/code-server/pkg/currency"
+	"github.com/code-payments/code-server/pkg/mint"
+	code_data "github.com/code-payments/code-server/pkg/code/data"
+)
+
+// GetExchangeRateForMint gets the exchange rate from one unit of the mint to the
+// provided currency at the given time.
+//
+// Exchange rates are only ingested for Kin, so rates for mints pegged to a fiat
+// currency are derived by crossing the Kin rates. Mints that are neither Kin nor
+// pegged to a fiat currency don't have exchange rates.
+func GetExchangeRateForMint(ctx context.Context, data code_data.Provider, m *mint.Mint, code currency_lib.Code, t time.Time) (float64, error) {
+	if code == m.NativeCurrency() {
+		return 1.0, nil
+	}
+
+	if m.IsKin() {
+		exchangeRecord, err := data.GetExchangeRate(ctx, code, t)
+		if err != nil {
+			return 0, err
+		}
+		return exchangeRecord.Rate, nil
+	}
+
+	if !m.IsPegged() {
+		return 0, errors.Errorf("no exchange rates available for %s mint", m.Symbol)
+	}
+
+	peggedExchangeRecord, err := data.GetExchangeRate(ctx, m.PeggedCurrency, t)
+	if err != nil {
+		return 0, err
+	}
+	if peggedExchangeRecord.Rate == 0 {
+		return 0, errors.Errorf("invalid %s exchange rate", m.PeggedCurrency)
+	}
+
+	if code == currency_lib.KIN {
+		return 1.0 / peggedExchangeRecord.Rate, nil
+	}
+
+	exchangeRecord, err := data.GetExchangeRate(ctx, code, t)
+	if err != nil {
+		return 0, err
+	}
+	return exchangeRecord.Rate / peggedExchangeRecord.Rate, nil
+}
+
+// GetUsdMarketValue gets the USD market value of an amount of quarks of the
+// mint at the given time
+func GetUsdMarketValue(ctx context.Context, data code_data.Provider, m *mint.Mint, quarks uint64, t time.Time) (float64, error) {
+	usdRate, err := GetExchangeRateForMint(ctx, data, m, currency_lib.USD, t)
+	if err != nil {
+		return 0, err
+	}
+	return usdRate * m.FromQuarks(quarks), nil
+}
@@ -0,0 +1,69 @@
+package exchangerate
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	currency_lib "github.com/code-payments/code-server/pkg/currency"
+	"github.com/code-payments/code-server/pkg/kin"
+	"github.com/code-payments/code-server/pkg/mint"
+	datatest "github.com/code-payments/code-server/pkg/code/data/test"
+	"github.com/code-payments/code-server/pkg/code/data/currency"
+)
+
+func TestGetExchangeRateForMint(t *testing.T) {
+	ctx := context.Background()
+	data := datatest.NewDataProvider()
+
+	now := time.Now()
+	require.NoError(t, data.ImportExchangeRates(ctx, &currency.MultiRateRecord{
+		Time: now,
+		Rates: map[string]float64{
+			string(currency_lib.USD): 0.00002,
+			string(currency_lib.CAD): 0.00003,
+		},
+	}))
+
+	rate, err := GetExchangeRateForMint(ctx, data, mint.Kin, currency_lib.KIN, now)
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, rate)
+
+	rate, err = GetExchangeRateForMint(ctx, data, mint.Kin, currency_lib.USD, now)
+	require.NoError(t, err)
+	assert.Equal(t, 0.00002, rate)
+
+	rate, err = GetExchangeRateForMint(ctx, data, mint.Usdc, currency_lib.USD, now)
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, rate)
+
+	rate, err = GetExchangeRateForMint(ctx, data, mint.Usdc, currency_lib.CAD, now)
+	require.NoError(t, err)
+	assert.InDelta(t, 1.5, rate, 0.000001)
+
+	rate, err = GetExchangeRateForMint(ctx, data, mint.Usdc, currency_lib.KIN, now)
+	require.NoError(t, err)
+	assert.InDelta(t, 50_000, rate, 0.000001)
+
+	_, err = GetExchangeRateForMint(ctx, data, mint.Usdc, currency_lib.EUR, now)
+	assert.Error(t, err)
+
+	unpegged := &mint.Mint{
+		Address:  mint.Usdc.Address,
+		Symbol:   "unpegged",
+		Decimals: 6,
+	}
+	_, err = GetExchangeRateForMint(ctx, data, unpegged, currency_lib.USD, now)
+	assert.Error(t, err)
+
+	usdValue, err := GetUsdMarketValue(ctx, data, mint.Kin, kin.ToQuarks(100_000), now)
+	require.NoError(t, err)
+	assert.InDelta(t, 2.0, usdValue, 0.000001)
+
+	usdValue, err = GetUsdMarketValue(ctx, data, mint.Usdc, mint.Usdc.ToQuarks(12), now)
+	require.NoError(t, err)
+	assert.Equal(t, 12.0, usdValue)
+}
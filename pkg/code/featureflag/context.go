@@ -0,0 +1,63 @@
+package featureflag
+
+import (
+	"context"
+
+	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+
+	"github.com/code-payments/code-server/pkg/code/common"
+)
+
+type evaluatorContextKey struct{}
+
+// NewContext returns a new context that carries the evaluator
+func NewContext(ctx context.Context, evaluator *Evaluator) context.Context {
+	return context.WithValue(ctx, evaluatorContextKey{}, evaluator)
+}
+
+// FromContext gets the evaluator carried by the context, if any
+func FromContext(ctx context.Context) (*Evaluator, bool) {
+	evaluator, ok := ctx.Value(evaluatorContextKey{}).(*Evaluator)
+	return evaluator, ok && evaluator != nil
+}
+
+// IsEnabled determines whether a flag is on for the client making the request,
+// using the evaluator carried by the context. Flags are off when the context
+// doesn't carry an evaluator, or the flag can't be evaluated, so new behaviour
+// should always be guarded by a flag being on.
+func IsEnabled(ctx context.Context, name string, owner *common.Account) bool {
+	evaluator, ok := FromContext(ctx)
+	if !ok {
+		return false
+	}
+
+	enabled, err := evaluator.IsEnabled(ctx, name, owner)
+	if err != nil {
+		logrus.StandardLogger().WithFields(logrus.Fields{
+			"type": "featureflag",
+			"flag": name,
+		}).WithError(err).Warn("failure evaluating feature flag")
+		return false
+	}
+	return enabled
+}
+
+// UnaryServerInterceptor injects the evaluator into the context of requests, so
+// handlers can use IsEnabled
+func UnaryServerInterceptor(evaluator *Evaluator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(NewContext(ctx, evaluator), req)
+	}
+}
+
+// StreamServerInterceptor injects the evaluator into the context of streams, so
+// handlers can use IsEnabled
+func StreamServerInterceptor(evaluator *Evaluator) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		wrapped := grpc_middleware.WrapServerStream(ss)
+		wrapped.WrappedContext = NewContext(ss.Context(), evaluator)
+		return handler(srv, wrapped)
+	}
+}
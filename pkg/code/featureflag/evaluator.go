@@ -0,0 +1,282 @@
+package featureflag
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/code-payments/code-server/pkg/grpc/client"
+	"github.com/code-payments/code-server/pkg/metrics"
+	"github.com/code-payments/code-server/pkg/code/common"
+	code_data "github.com/code-payments/code-server/pkg/code/data"
+	"github.com/code-payments/code-server/pkg/code/data/featureflag"
+)
+
+const (
+	metricsStructName = "featureflag.evaluator"
+
+	defaultRefreshInterval = 30 * time.Second
+)
+
+// Evaluator determines which feature flags are on for a client. A flag is on
+// when it's enabled and the client matches all of its rules, which target
+// clients by device type, version range, percentage of owner accounts and the
+// country of their IP.
+//
+// Flags are consulted on many requests, so they're cached in full and reloaded
+// periodically.
+type Evaluator struct {
+	log             *logrus.Entry
+	data            code_data.Provider
+	refreshInterval time.Duration
+
+	mu          sync.Mutex
+	lastRefresh time.Time
+	loaded      bool
+	refreshing  bool
+	flags       []*featureflag.Record
+}
+
+// Option configures an Evaluator
+type Option func(e *Evaluator)
+
+// WithRefreshInterval overrides the default interval at which flags are reloaded
+func WithRefreshInterval(interval time.Duration) Option {
+	return func(e *Evaluator) {
+		e.refreshInterval = interval
+	}
+}
+
+func NewEvaluator(data code_data.Provider, opts ...Option) *Evaluator {
+	e := &Evaluator{
+		log:             logrus.StandardLogger().WithField("type", "featureflag/evaluator"),
+		data:            data,
+		refreshInterval: defaultRefreshInterval,
+	}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	return e
+}
+
+// IsEnabled determines whether a flag is on for the client making the request.
+// The owner account is optional, but flags that aren't fully rolled out are off
+// without one. Unknown flags are off.
+func (e *Evaluator) IsEnabled(ctx context.Context, name string, owner *common.Account) (bool, error) {
+	tracer := metrics.TraceMethodCall(ctx, metricsStructName, "IsEnabled")
+	defer tracer.End()
+
+	flags, err := e.getFlags(ctx)
+	if err != nil {
+		tracer.OnError(err)
+		return false, err
+	}
+
+	target := e.getTarget(ctx, owner)
+	for _, flag := range flags {
+		if flag.Name == name {
+			return target.matches(ctx, flag), nil
+		}
+	}
+	return false, nil
+}
+
+// GetEnabledFlags gets the names of all flags that are on for the client making
+// the request, in ascending order. The owner account is optional, but flags that
+// aren't fully rolled out are off without one.
+func (e *Evaluator) GetEnabledFlags(ctx context.Context, owner *common.Account) ([]string, error) {
+	tracer := metrics.TraceMethodCall(ctx, metricsStructName, "GetEnabledFlags")
+	defer tracer.End()
+
+	flags, err := e.getFlags(ctx)
+	if err != nil {
+		tracer.OnError(err)
+		return nil, err
+	}
+
+	target := e.getTarget(ctx, owner)
+
+	var res []string
+	for _, flag := range flags {
+		if target.matches(ctx, flag) {
+			res = append(res, flag.Name)
+		}
+	}
+	return res, nil
+}
+
+// getFlags gets all flags, reloading them when the refresh interval has elapsed.
+// Flags are reloaded outside the lock, and callers use the previously loaded
+// flags while another caller is reloading them. On failure, the previously
+// loaded flags continue to be used, and an error is only returned when nothing
+// has been loaded.
+func (e *Evaluator) getFlags(ctx context.Context) ([]*featureflag.Record, error) {
+	e.mu.Lock()
+	if e.loaded && (e.refreshing || time.Since(e.lastRefresh) < e.refreshInterval) {
+		flags := e.flags
+		e.mu.Unlock()
+		return flags, nil
+	}
+	e.refreshing = true
+	e.mu.Unlock()
+
+	flags, err := e.data.GetAllFeatureFlags(ctx)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.refreshing = false
+
+	if err != nil && err != featureflag.ErrFlagNotFound {
+		if !e.loaded {
+			return nil, err
+		}
+
+		// Avoid retrying on every call
+		e.lastRefresh = time.Now()
+		e.log.WithError(err).Warn("failure refreshing feature flags, using stale flags")
+		return e.flags, nil
+	}
+
+	e.flags = flags
+	e.lastRefresh = time.Now()
+	e.loaded = true
+	return e.flags, nil
+}
+
+// target is the client that flags are evaluated against
+type target struct {
+	log  *logrus.Entry
+	data code_data.Provider
+
+	userAgent *client.UserAgent
+	owner     *common.Account
+
+	// The country is only looked up when a flag targets countries
+	countryLoaded bool
+	country       *string
+}
+
+func (e *Evaluator) getTarget(ctx context.Context, owner *common.Account) *target {
+	userAgent, err := client.GetUserAgent(ctx)
+	if err != nil {
+		userAgent = nil
+	}
+
+	return &target{
+		log:       e.log,
+		data:      e.data,
+		userAgent: userAgent,
+		owner:     owner,
+	}
+}
+
+func (t *target) matches(ctx context.Context, flag *featureflag.Record) bool {
+	if !flag.IsEnabled {
+		return false
+	}
+
+	if len(flag.DeviceTypes) > 0 {
+		if t.userAgent == nil {
+			return false
+		}
+
+		var found bool
+		for _, deviceType := range flag.DeviceTypes {
+			if deviceType == t.userAgent.DeviceType {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if flag.MinVersion != nil || flag.MaxVersion != nil {
+		if t.userAgent == nil {
+			return false
+		}
+
+		if flag.MinVersion != nil && t.userAgent.Version.Before(flag.MinVersion) {
+			return false
+		}
+
+		if flag.MaxVersion != nil && !t.userAgent.Version.Before(flag.MaxVersion) {
+			return false
+		}
+	}
+
+	if len(flag.Countries) > 0 {
+		country := t.getCountry(ctx)
+		if country == nil {
+			return false
+		}
+
+		var found bool
+		for _, targetedCountry := range flag.Countries {
+			if strings.EqualFold(targetedCountry, *country) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if flag.RolloutPercentage < 100 {
+		if t.owner == nil {
+			return false
+		}
+
+		if getRolloutBucket(flag.Name, t.owner) >= uint64(flag.RolloutPercentage) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (t *target) getCountry(ctx context.Context) *string {
+	if t.countryLoaded {
+		return t.country
+	}
+	t.countryLoaded = true
+
+	ipAddr, err := client.GetIPAddr(ctx)
+	if err != nil {
+		return nil
+	}
+
+	// The header may contain a list of proxies, where the first is the client
+	ip := net.ParseIP(strings.TrimSpace(strings.Split(ipAddr, ",")[0]))
+	if ip == nil {
+		return nil
+	}
+
+	metadata, err := t.data.GetIpMetadata(ctx, ip.String())
+	if err != nil {
+		t.log.WithError(err).Warn("failure getting ip metadata")
+		return nil
+	}
+
+	t.country = metadata.Country
+	return t.country
+}
+
+// getRolloutBucket deterministically assigns an owner to a bucket in [0, 100)
+// for a flag. Buckets are independent across flags, so the same owners aren't
+// always first to get new features.
+func getRolloutBucket(name string, owner *common.Account) uint64 {
+	h := sha256.Sum256([]byte(name + ":" + owner.PublicKey().ToBase58()))
+	return binary.BigEndian.Uint64(h[:8]) % 100
+}
@@ -0,0 +1,253 @@
+package featureflag
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/code-payments/code-server/pkg/geoip"
+	memory_geoip "github.com/code-payments/code-server/pkg/geoip/memory"
+	"github.com/code-payments/code-server/pkg/grpc/client"
+	"github.com/code-payments/code-server/pkg/grpc/headers"
+	"github.com/code-payments/code-server/pkg/pointer"
+	"github.com/code-payments/code-server/pkg/testutil"
+	"github.com/code-payments/code-server/pkg/code/common"
+	code_data "github.com/code-payments/code-server/pkg/code/data"
+	datatest "github.com/code-payments/code-server/pkg/code/data/test"
+	"github.com/code-payments/code-server/pkg/code/data/featureflag"
+)
+
+const (
+	usIp = "1.1.1.1"
+	caIp = "2.2.2.2"
+)
+
+type testEnv struct {
+	data      code_data.Provider
+	evaluator *Evaluator
+}
+
+func setup(t *testing.T) *testEnv {
+	geoIP := memory_geoip.NewGeoIP()
+	require.NoError(t, geoIP.Set(usIp, &geoip.Metadata{Country: pointer.String("US")}))
+	require.NoError(t, geoIP.Set(caIp, &geoip.Metadata{Country: pointer.String("CA")}))
+
+	data := datatest.NewDataProviderWithGeoIP(geoIP)
+	return &testEnv{
+		data:      data,
+		evaluator: NewEvaluator(data),
+	}
+}
+
+func newClientContext(t *testing.T, userAgent, ip string) context.Context {
+	ctx := context.Background()
+	if len(ip) > 0 {
+		ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("x-forwarded-for", ip))
+	}
+
+	ctx, err := headers.ContextWithHeaders(ctx)
+	require.NoError(t, err)
+	if len(userAgent) > 0 {
+		require.NoError(t, headers.SetASCIIHeader(ctx, client.UserAgentHeaderName, userAgent))
+	}
+	return ctx
+}
+
+func TestEvaluator_Rules(t *testing.T) {
+	env := setup(t)
+
+	for _, record := range []*featureflag.Record{
+		{Name: "disabled", IsEnabled: false, RolloutPercentage: 100},
+		{Name: "everyone", IsEnabled: true, RolloutPercentage: 100},
+		{Name: "nobody", IsEnabled: true, RolloutPercentage: 0},
+		{Name: "ios_only", IsEnabled: true, DeviceTypes: []client.DeviceType{client.DeviceTypeIOS}, RolloutPercentage: 100},
+		{
+			Name:              "version_range",
+			IsEnabled:         true,
+			MinVersion:        &client.Version{Major: 1, Minor: 2, Patch: 0},
+			MaxVersion:        &client.Version{Major: 2, Minor: 0, Patch: 0},
+			RolloutPercentage: 100,
+		},
+		{Name: "canada_only", IsEnabled: true, Countries: []string{"CA"}, RolloutPercentage: 100},
+	} {
+		require.NoError(t, env.data.PutFeatureFlag(context.Background(), record))
+	}
+
+	owner := testutil.NewRandomAccount(t)
+
+	for _, tc := range []struct {
+		userAgent string
+		ip        string
+		expected  []string
+	}{
+		{"Code/iOS/1.2.0", usIp, []string{"everyone", "ios_only", "version_range"}},
+		{"Code/iOS/1.1.9", usIp, []string{"everyone", "ios_only"}},
+		{"Code/Android/1.9.9", usIp, []string{"everyone", "version_range"}},
+		{"Code/Android/2.0.0", caIp, []string{"canada_only", "everyone"}},
+		{"", caIp, []string{"canada_only", "everyone"}},
+		{"Code/iOS/1.5.0", "", []string{"everyone", "ios_only", "version_range"}},
+		{"Code/iOS/1.5.0", "2.2.2.2, 10.0.0.1", []string{"canada_only", "everyone", "ios_only", "version_range"}},
+		{"", "", []string{"everyone"}},
+	} {
+		ctx := newClientContext(t, tc.userAgent, tc.ip)
+
+		actual, err := env.evaluator.GetEnabledFlags(ctx, owner)
+		require.NoError(t, err)
+		assert.Equal(t, tc.expected, actual, "user agent %q, ip %q", tc.userAgent, tc.ip)
+
+		for _, name := range []string{"disabled", "everyone", "nobody", "ios_only", "version_range", "canada_only", "unknown"} {
+			enabled, err := env.evaluator.IsEnabled(ctx, name, owner)
+			require.NoError(t, err)
+			assert.Equal(t, contains(tc.expected, name), enabled)
+		}
+	}
+}
+
+func TestEvaluator_PercentageRollout(t *testing.T) {
+	env := setup(t)
+	ctx := newClientContext(t, "Code/iOS/1.0.0", usIp)
+
+	require.NoError(t, env.data.PutFeatureFlag(ctx, &featureflag.Record{
+		Name:              "partial_rollout",
+		IsEnabled:         true,
+		RolloutPercentage: 30,
+	}))
+
+	// Flags that aren't fully rolled out require an owner
+	enabled, err := env.evaluator.IsEnabled(ctx, "partial_rollout", nil)
+	require.NoError(t, err)
+	assert.False(t, enabled)
+
+	var owners []*common.Account
+	var enabledCount int
+	for i := 0; i < 1000; i++ {
+		owner := testutil.NewRandomAccount(t)
+		owners = append(owners, owner)
+
+		enabled, err := env.evaluator.IsEnabled(ctx, "partial_rollout", owner)
+		require.NoError(t, err)
+		if enabled {
+			enabledCount++
+		}
+
+		// Evaluation is stable for an owner
+		enabledAgain, err := env.evaluator.IsEnabled(ctx, "partial_rollout", owner)
+		require.NoError(t, err)
+		assert.Equal(t, enabled, enabledAgain)
+	}
+	assert.InDelta(t, 300, enabledCount, 75)
+
+	// Increasing the rollout keeps owners that already had the flag
+	previouslyEnabled := make(map[string]bool)
+	for _, owner := range owners {
+		previouslyEnabled[owner.PublicKey().ToBase58()] = getRolloutBucket("partial_rollout", owner) < 30
+	}
+
+	require.NoError(t, env.data.PutFeatureFlag(ctx, &featureflag.Record{
+		Name:              "partial_rollout",
+		IsEnabled:         true,
+		RolloutPercentage: 60,
+	}))
+	env.evaluator.lastRefresh = time.Now().Add(-defaultRefreshInterval)
+
+	for _, owner := range owners {
+		enabled, err := env.evaluator.IsEnabled(ctx, "partial_rollout", owner)
+		require.NoError(t, err)
+		if previouslyEnabled[owner.PublicKey().ToBase58()] {
+			assert.True(t, enabled)
+		}
+	}
+}
+
+func TestEvaluator_Refresh(t *testing.T) {
+	env := setup(t)
+	ctx := newClientContext(t, "Code/iOS/1.0.0", usIp)
+	owner := testutil.NewRandomAccount(t)
+
+	flags, err := env.evaluator.GetEnabledFlags(ctx, owner)
+	require.NoError(t, err)
+	assert.Empty(t, flags)
+
+	require.NoError(t, env.data.PutFeatureFlag(ctx, &featureflag.Record{
+		Name:              "new_feature",
+		IsEnabled:         true,
+		RolloutPercentage: 100,
+	}))
+
+	// Cached until the refresh interval elapses
+	enabled, err := env.evaluator.IsEnabled(ctx, "new_feature", owner)
+	require.NoError(t, err)
+	assert.False(t, enabled)
+
+	env.evaluator.lastRefresh = time.Now().Add(-defaultRefreshInterval)
+
+	enabled, err = env.evaluator.IsEnabled(ctx, "new_feature", owner)
+	require.NoError(t, err)
+	assert.True(t, enabled)
+}
+
+func TestEvaluator_ConcurrentRefresh(t *testing.T) {
+	env := setup(t)
+	ctx := newClientContext(t, "Code/iOS/1.0.0", usIp)
+	owner := testutil.NewRandomAccount(t)
+
+	flags, err := env.evaluator.GetEnabledFlags(ctx, owner)
+	require.NoError(t, err)
+	assert.Empty(t, flags)
+
+	require.NoError(t, env.data.PutFeatureFlag(ctx, &featureflag.Record{
+		Name:              "new_feature",
+		IsEnabled:         true,
+		RolloutPercentage: 100,
+	}))
+
+	// Stale flags are used while another caller is reloading them
+	env.evaluator.lastRefresh = time.Now().Add(-defaultRefreshInterval)
+	env.evaluator.refreshing = true
+
+	enabled, err := env.evaluator.IsEnabled(ctx, "new_feature", owner)
+	require.NoError(t, err)
+	assert.False(t, enabled)
+
+	env.evaluator.refreshing = false
+
+	enabled, err = env.evaluator.IsEnabled(ctx, "new_feature", owner)
+	require.NoError(t, err)
+	assert.True(t, enabled)
+}
+
+func TestContextHelper(t *testing.T) {
+	env := setup(t)
+	ctx := newClientContext(t, "Code/iOS/1.0.0", usIp)
+	owner := testutil.NewRandomAccount(t)
+
+	require.NoError(t, env.data.PutFeatureFlag(ctx, &featureflag.Record{
+		Name:              "new_feature",
+		IsEnabled:         true,
+		RolloutPercentage: 100,
+	}))
+
+	// Flags are off without an evaluator
+	assert.False(t, IsEnabled(ctx, "new_feature", owner))
+
+	interceptor := UnaryServerInterceptor(env.evaluator)
+	_, err := interceptor(ctx, nil, nil, func(ctx context.Context, req interface{}) (interface{}, error) {
+		assert.True(t, IsEnabled(ctx, "new_feature", owner))
+		assert.False(t, IsEnabled(ctx, "unknown", owner))
+		return nil, nil
+	})
+	require.NoError(t, err)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,365 @@
+package history
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	currency_lib "github.com/code-payments/code-server/pkg/currency"
+	"github.com/code-payments/code-server/pkg/database/query"
+	"github.com/code-payments/code-server/pkg/metrics"
+	"github.com/code-payments/code-server/pkg/code/common"
+	code_data "github.com/code-payments/code-server/pkg/code/data"
+	"github.com/code-payments/code-server/pkg/code/data/fulfillment"
+	"github.com/code-payments/code-server/pkg/code/data/intent"
+	exchange_rate_util "github.com/code-payments/code-server/pkg/code/exchangerate"
+)
+
+const (
+	metricsStructName = "history.exporter"
+
+	exportPageSize = 100
+
+	// MaxExportRange is the maximum time range that can be exported at once
+	MaxExportRange = 366 * 24 * time.Hour
+)
+
+type Format uint8
+
+const (
+	FormatUnknown Format = iota
+	FormatCSV
+	FormatJSON
+)
+
+// Statement is a payment history export for an owner over a time range
+type Statement struct {
+	Owner string    `json:"owner"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+
+	Entries []*StatementEntry `json:"entries"`
+}
+
+// StatementEntry is a payment history item with its fiat valuation at the time
+// of the payment and the on-chain transactions that fulfilled it
+type StatementEntry struct {
+	Timestamp    time.Time `json:"timestamp"`
+	IntentId     string    `json:"intent_id"`
+	PaymentType  string    `json:"payment_type"`
+	Category     string    `json:"category"`
+	Counterparty string    `json:"counterparty"`
+
+	Currency     string  `json:"currency"`
+	NativeAmount float64 `json:"native_amount"`
+	ExchangeRate float64 `json:"exchange_rate"`
+
+	Mint   string  `json:"mint"`
+	Amount float64 `json:"amount"`
+	Quarks uint64  `json:"quarks"`
+
+	UsdExchangeRate float64 `json:"usd_exchange_rate"`
+	UsdValue        float64 `json:"usd_value"`
+
+	Signatures []string `json:"signatures"`
+}
+
+var csvHeader = []string{
+	"timestamp",
+	"intent_id",
+	"payment_type",
+	"category",
+	"counterparty",
+	"currency",
+	"native_amount",
+	"exchange_rate",
+	"mint",
+	"amount",
+	"quarks",
+	"usd_exchange_rate",
+	"usd_value",
+	"signatures",
+}
+
+// Exporter exports payment history for an owner as a statement
+type Exporter struct {
+	log        *logrus.Entry
+	data       code_data.Provider
+	airdropper *common.Account
+}
+
+// NewExporter returns a new Exporter. The airdropper is optional, and used to
+// identify airdrops.
+func NewExporter(data code_data.Provider, airdropper *common.Account) *Exporter {
+	return &Exporter{
+		log:        logrus.StandardLogger().WithField("type", "history/exporter"),
+		data:       data,
+		airdropper: airdropper,
+	}
+}
+
+// Export gets the statement of payments for the owner made within [start, end)
+func (e *Exporter) Export(ctx context.Context, owner *common.Account, start, end time.Time) (*Statement, error) {
+	tracer := metrics.TraceMethodCall(ctx, metricsStructName, "Export")
+	defer tracer.End()
+
+	log := e.log.WithFields(logrus.Fields{
+		"method": "Export",
+		"owner":  owner.PublicKey().ToBase58(),
+		"start":  start,
+		"end":    end,
+	})
+
+	if !start.Before(end) {
+		return nil, errors.New("start must be before end")
+	} else if end.Sub(start) > MaxExportRange {
+		return nil, errors.New("time range exceeds maximum")
+	}
+
+	res := &Statement{
+		Owner:   owner.PublicKey().ToBase58(),
+		Start:   start.UTC(),
+		End:     end.UTC(),
+		Entries: make([]*StatementEntry, 0),
+	}
+
+	// Intents are ordered by ID and not creation time, so the entire history
+	// needs to be walked
+	cursor := query.ToCursor(0)
+	for {
+		intentRecords, err := e.data.GetAllIntentsByOwner(
+			ctx,
+			owner.PublicKey().ToBase58(),
+			query.WithLimit(exportPageSize),
+			query.WithDirection(query.Ascending),
+			query.WithCursor(cursor),
+		)
+		if err == intent.ErrIntentNotFound {
+			break
+		} else if err != nil {
+			log.WithError(err).Warn("failure querying intent records")
+			tracer.OnError(err)
+			return nil, err
+		}
+
+		for _, intentRecord := range intentRecords {
+			if intentRecord.CreatedAt.Before(start) || !intentRecord.CreatedAt.Before(end) {
+				continue
+			}
+
+			item, ok, err := GetItem(ctx, e.data, owner, e.airdropper, intentRecord)
+			if err != nil {
+				log.WithError(err).Warn("failure getting history item")
+				tracer.OnError(err)
+				return nil, err
+			} else if !ok {
+				continue
+			}
+
+			entry, err := e.toStatementEntry(ctx, item)
+			if err != nil {
+				log.WithError(err).Warn("failure getting statement entry")
+				tracer.OnError(err)
+				return nil, err
+			}
+			res.Entries = append(res.Entries, entry)
+		}
+
+		if len(intentRecords) < exportPageSize {
+			break
+		}
+		cursor = query.ToCursor(intentRecords[len(intentRecords)-1].Id)
+	}
+
+	return res, nil
+}
+
+func (e *Exporter) toStatementEntry(ctx context.Context, item *Item) (*StatementEntry, error) {
+	usdExchangeRate, err := exchange_rate_util.GetExchangeRateForMint(ctx, e.data, item.Mint, currency_lib.USD, item.CreatedAt)
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting usd exchange rate")
+	}
+
+	signatures, err := e.getSignatures(ctx, item.IntentId)
+	if err != nil {
+		return nil, err
+	}
+
+	amount := item.Mint.FromQuarks(item.Quarks)
+
+	return &StatementEntry{
+		Timestamp:    item.CreatedAt.UTC(),
+		IntentId:     item.IntentId,
+		PaymentType:  item.PaymentType.String(),
+		Category:     getCategory(item),
+		Counterparty: item.Counterparty,
+
+		Currency:     string(item.Currency),
+		NativeAmount: item.NativeAmount,
+		ExchangeRate: item.ExchangeRate,
+
+		Mint:   item.Mint.Symbol,
+		Amount: amount,
+		Quarks: item.Quarks,
+
+		UsdExchangeRate: usdExchangeRate,
+		UsdValue:        usdExchangeRate * amount,
+
+		Signatures: signatures,
+	}, nil
+}
+
+// getSignatures gets the signatures of confirmed on-chain transactions for an
+// intent
+func (e *Exporter) getSignatures(ctx context.Context, intentId string) ([]string, error) {
+	// External deposits are observed on chain, so there are no fulfillments. The
+	// signature is embedded in the intent ID.
+	if parts := strings.Split(intentId, "-"); len(parts) == 2 {
+		return []string{parts[0]}, nil
+	}
+
+	res := make([]string, 0)
+	seen := make(map[string]struct{})
+
+	cursor := query.ToCursor(0)
+	for {
+		fulfillmentRecords, err := e.data.GetAllFulfillmentsByIntent(
+			ctx,
+			intentId,
+			query.WithLimit(exportPageSize),
+			query.WithDirection(query.Ascending),
+			query.WithCursor(cursor),
+		)
+		if err == fulfillment.ErrFulfillmentNotFound {
+			break
+		} else if err != nil {
+			return nil, errors.Wrap(err, "error getting fulfillment records")
+		}
+
+		for _, fulfillmentRecord := range fulfillmentRecords {
+			if fulfillmentRecord.State != fulfillment.StateConfirmed || fulfillmentRecord.Signature == nil {
+				continue
+			}
+
+			if _, ok := seen[*fulfillmentRecord.Signature]; ok {
+				continue
+			}
+
+			res = append(res, *fulfillmentRecord.Signature)
+			seen[*fulfillmentRecord.Signature] = struct{}{}
+		}
+
+		if len(fulfillmentRecords) < exportPageSize {
+			break
+		}
+		cursor = query.ToCursor(fulfillmentRecords[len(fulfillmentRecords)-1].Id)
+	}
+
+	return res, nil
+}
+
+// Write writes the statement in the provided format
+func (s *Statement) Write(w io.Writer, format Format) error {
+	switch format {
+	case FormatCSV:
+		return s.writeCSV(w)
+	case FormatJSON:
+		return json.NewEncoder(w).Encode(s)
+	}
+	return errors.New("unsupported format")
+}
+
+func (s *Statement) writeCSV(w io.Writer) error {
+	csvWriter := csv.NewWriter(w)
+
+	if err := csvWriter.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, entry := range s.Entries {
+		err := csvWriter.Write([]string{
+			entry.Timestamp.Format(time.RFC3339),
+			entry.IntentId,
+			entry.PaymentType,
+			entry.Category,
+			entry.Counterparty,
+			entry.Currency,
+			formatFloat(entry.NativeAmount),
+			formatFloat(entry.ExchangeRate),
+			entry.Mint,
+			formatFloat(entry.Amount),
+			strconv.FormatUint(entry.Quarks, 10),
+			formatFloat(entry.UsdExchangeRate),
+			fmt.Sprintf("%.2f", entry.UsdValue),
+			strings.Join(entry.Signatures, " "),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+func getCategory(item *Item) string {
+	switch {
+	case item.IsAirdrop:
+		return "airdrop"
+	case item.IsReturned:
+		return "returned"
+	case item.IsRemoteSend:
+		return "remote_send"
+	case item.IsMicroPayment:
+		return "micro_payment"
+	case item.IsDeposit:
+		return "deposit"
+	case item.IsWithdrawal:
+		return "withdrawal"
+	}
+	return "payment"
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// ParseFormat parses a format from its string value
+func ParseFormat(value string) (Format, error) {
+	switch strings.ToLower(value) {
+	case "csv":
+		return FormatCSV, nil
+	case "json":
+		return FormatJSON, nil
+	}
+	return FormatUnknown, errors.Errorf("unsupported format %s", value)
+}
+
+// ContentType gets the HTTP content type for the format
+func (f Format) ContentType() string {
+	switch f {
+	case FormatCSV:
+		return "text/csv"
+	case FormatJSON:
+		return "application/json"
+	}
+	return "application/octet-stream"
+}
+
+func (f Format) String() string {
+	switch f {
+	case FormatCSV:
+		return "csv"
+	case FormatJSON:
+		return "json"
+	}
+	return "unknown"
+}
@@ -0,0 +1,330 @@
+package history
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	currency_lib "github.com/code-payments/code-server/pkg/currency"
+	"github.com/code-payments/code-server/pkg/kin"
+	"github.com/code-payments/code-server/pkg/mint"
+	"github.com/code-payments/code-server/pkg/pointer"
+	"github.com/code-payments/code-server/pkg/code/common"
+	code_data "github.com/code-payments/code-server/pkg/code/data"
+	datatest "github.com/code-payments/code-server/pkg/code/data/test"
+	"github.com/code-payments/code-server/pkg/code/data/action"
+	"github.com/code-payments/code-server/pkg/code/data/currency"
+	"github.com/code-payments/code-server/pkg/code/data/fulfillment"
+	"github.com/code-payments/code-server/pkg/code/data/intent"
+)
+
+func TestExport_HappyPath(t *testing.T) {
+	env := setupExportTest(t)
+
+	start := time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(30 * 24 * time.Hour)
+
+	env.importUsdRate(t, start.Add(-time.Hour), 0.00002)
+	env.importUsdRate(t, start.Add(10*24*time.Hour), 0.00003)
+
+	other := testutilNewRandomAccount(t)
+
+	// Outside of the time range
+	env.savePublicPayment(t, "before", env.owner, other, 10, start.Add(-time.Minute), false)
+	env.savePublicPayment(t, "after", env.owner, other, 10, end, false)
+
+	// Within the time range
+	env.savePublicPayment(t, "sent", env.owner, other, 100, start.Add(time.Hour), false)
+	env.savePublicPayment(t, "received", other, env.owner, 200, start.Add(11*24*time.Hour), false)
+	env.savePublicPayment(t, "airdrop", env.airdropper, env.owner, 50, start.Add(12*24*time.Hour), false)
+	env.savePublicPayment(t, "withdrawal", env.owner, other, 25, start.Add(13*24*time.Hour), true)
+	env.saveExternalDeposit(t, "depositsig-vault", env.owner, 300, start.Add(14*24*time.Hour))
+
+	env.saveFulfillment(t, "sent", "sig1", fulfillment.StateConfirmed)
+	env.saveFulfillment(t, "sent", "sig2", fulfillment.StateConfirmed)
+	env.saveFulfillment(t, "sent", "sig3", fulfillment.StatePending)
+
+	statement, err := env.exporter.Export(env.ctx, env.owner, start, end)
+	require.NoError(t, err)
+
+	assert.Equal(t, env.owner.PublicKey().ToBase58(), statement.Owner)
+	assert.Equal(t, start, statement.Start)
+	assert.Equal(t, end, statement.End)
+	require.Len(t, statement.Entries, 5)
+
+	entry := statement.Entries[0]
+	assert.Equal(t, "sent", entry.IntentId)
+	assert.Equal(t, "send", entry.PaymentType)
+	assert.Equal(t, "payment", entry.Category)
+	assert.Equal(t, other.PublicKey().ToBase58(), entry.Counterparty)
+	assert.EqualValues(t, currency_lib.KIN, entry.Currency)
+	assert.EqualValues(t, 100, entry.NativeAmount)
+	assert.Equal(t, "kin", entry.Mint)
+	assert.EqualValues(t, 100, entry.Amount)
+	assert.Equal(t, kin.ToQuarks(100), entry.Quarks)
+	assert.Equal(t, 0.00002, entry.UsdExchangeRate)
+	assert.InDelta(t, 0.002, entry.UsdValue, 0.0000001)
+	assert.Equal(t, []string{"sig1", "sig2"}, entry.Signatures)
+
+	entry = statement.Entries[1]
+	assert.Equal(t, "received", entry.IntentId)
+	assert.Equal(t, "receive", entry.PaymentType)
+	assert.Equal(t, "payment", entry.Category)
+	assert.Equal(t, other.PublicKey().ToBase58(), entry.Counterparty)
+	assert.Equal(t, 0.00003, entry.UsdExchangeRate)
+	assert.InDelta(t, 0.006, entry.UsdValue, 0.0000001)
+	assert.Empty(t, entry.Signatures)
+
+	assert.Equal(t, "airdrop", statement.Entries[2].Category)
+	assert.Equal(t, "withdrawal", statement.Entries[3].Category)
+
+	entry = statement.Entries[4]
+	assert.Equal(t, "depositsig-vault", entry.IntentId)
+	assert.Equal(t, "deposit", entry.Category)
+	assert.Equal(t, "kin", entry.Mint)
+	assert.EqualValues(t, 300, entry.Amount)
+	assert.Equal(t, []string{"depositsig"}, entry.Signatures)
+
+	statement, err = env.exporter.Export(env.ctx, other, start, end)
+	require.NoError(t, err)
+	assert.Len(t, statement.Entries, 3)
+}
+
+func TestExport_SecondMint(t *testing.T) {
+	env := setupExportTest(t)
+	require.NoError(t, mint.Register(mint.Usdc))
+
+	start := time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(30 * 24 * time.Hour)
+
+	env.importUsdRate(t, start.Add(-time.Hour), 0.00002)
+
+	env.saveExternalDepositForMint(t, "kinsig-vault", env.owner, mint.Kin, kin.ToQuarks(100), start.Add(time.Hour))
+	env.saveExternalDepositForMint(t, "usdcsig-vault", env.owner, mint.Usdc, 12_500_000, start.Add(2*time.Hour))
+
+	// Dust filtering only applies to Kin
+	env.saveExternalDepositForMint(t, "dustsig-vault", env.owner, mint.Kin, 1, start.Add(3*time.Hour))
+	env.saveExternalDepositForMint(t, "smallsig-vault", env.owner, mint.Usdc, 1, start.Add(4*time.Hour))
+
+	statement, err := env.exporter.Export(env.ctx, env.owner, start, end)
+	require.NoError(t, err)
+	require.Len(t, statement.Entries, 3)
+
+	entry := statement.Entries[0]
+	assert.Equal(t, "kinsig-vault", entry.IntentId)
+	assert.EqualValues(t, currency_lib.KIN, entry.Currency)
+	assert.EqualValues(t, 100, entry.NativeAmount)
+	assert.Equal(t, "kin", entry.Mint)
+	assert.EqualValues(t, 100, entry.Amount)
+	assert.Equal(t, 0.00002, entry.UsdExchangeRate)
+	assert.InDelta(t, 0.002, entry.UsdValue, 0.0000001)
+
+	entry = statement.Entries[1]
+	assert.Equal(t, "usdcsig-vault", entry.IntentId)
+	assert.Equal(t, "deposit", entry.Category)
+	assert.EqualValues(t, currency_lib.USD, entry.Currency)
+	assert.Equal(t, 12.5, entry.NativeAmount)
+	assert.Equal(t, 1.0, entry.ExchangeRate)
+	assert.Equal(t, "usdc", entry.Mint)
+	assert.Equal(t, 12.5, entry.Amount)
+	assert.EqualValues(t, 12_500_000, entry.Quarks)
+	assert.Equal(t, 1.0, entry.UsdExchangeRate)
+	assert.Equal(t, 12.5, entry.UsdValue)
+
+	entry = statement.Entries[2]
+	assert.Equal(t, "smallsig-vault", entry.IntentId)
+	assert.Equal(t, 0.000001, entry.Amount)
+}
+
+func TestExport_InvalidTimeRange(t *testing.T) {
+	env := setupExportTest(t)
+
+	now := time.Now()
+
+	_, err := env.exporter.Export(env.ctx, env.owner, now, now)
+	assert.Error(t, err)
+
+	_, err = env.exporter.Export(env.ctx, env.owner, now, now.Add(-time.Hour))
+	assert.Error(t, err)
+
+	_, err = env.exporter.Export(env.ctx, env.owner, now, now.Add(MaxExportRange+time.Second))
+	assert.Error(t, err)
+}
+
+func TestStatement_Write(t *testing.T) {
+	statement := &Statement{
+		Owner: "owner",
+		Start: time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+		Entries: []*StatementEntry{
+			{
+				Timestamp:       time.Date(2023, time.June, 1, 12, 30, 0, 0, time.UTC),
+				IntentId:        "intent",
+				PaymentType:     "send",
+				Category:        "payment",
+				Counterparty:    "counterparty",
+				Currency:        "usd",
+				NativeAmount:    1.5,
+				ExchangeRate:    0.00001,
+				Mint:            "kin",
+				Amount:          150000,
+				Quarks:          kin.ToQuarks(150000),
+				UsdExchangeRate: 0.00001,
+				UsdValue:        1.5,
+				Signatures:      []string{"sig1", "sig2"},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, statement.Write(&buf, FormatCSV))
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, csvHeader, rows[0])
+	assert.Equal(t, []string{
+		"2023-06-01T12:30:00Z",
+		"intent",
+		"send",
+		"payment",
+		"counterparty",
+		"usd",
+		"1.5",
+		"0.00001",
+		"kin",
+		"150000",
+		"15000000000",
+		"0.00001",
+		"1.50",
+		"sig1 sig2",
+	}, rows[1])
+
+	buf.Reset()
+	require.NoError(t, statement.Write(&buf, FormatJSON))
+
+	var decoded Statement
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, *statement, decoded)
+
+	assert.Error(t, statement.Write(&buf, FormatUnknown))
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, format := range []Format{FormatCSV, FormatJSON} {
+		parsed, err := ParseFormat(format.String())
+		require.NoError(t, err)
+		assert.Equal(t, format, parsed)
+	}
+
+	_, err := ParseFormat("xml")
+	assert.Error(t, err)
+}
+
+type exportTestEnv struct {
+	ctx        context.Context
+	data       code_data.Provider
+	exporter   *Exporter
+	owner      *common.Account
+	airdropper *common.Account
+}
+
+func setupExportTest(t *testing.T) *exportTestEnv {
+	data := datatest.NewDataProvider()
+	airdropper := testutilNewRandomAccount(t)
+	return &exportTestEnv{
+		ctx:        context.Background(),
+		data:       data,
+		exporter:   NewExporter(data, airdropper),
+		owner:      testutilNewRandomAccount(t),
+		airdropper: airdropper,
+	}
+}
+
+func (e *exportTestEnv) importUsdRate(t *testing.T, at time.Time, rate float64) {
+	require.NoError(t, e.data.ImportExchangeRates(e.ctx, &currency.MultiRateRecord{
+		Time:  at,
+		Rates: map[string]float64{string(currency_lib.USD): rate},
+	}))
+}
+
+func (e *exportTestEnv) savePublicPayment(t *testing.T, intentId string, source, destination *common.Account, kinAmount uint64, createdAt time.Time, isWithdrawal bool) {
+	require.NoError(t, e.data.SaveIntent(e.ctx, &intent.Record{
+		IntentId:              intentId,
+		IntentType:            intent.SendPublicPayment,
+		InitiatorOwnerAccount: source.PublicKey().ToBase58(),
+		SendPublicPaymentMetadata: &intent.SendPublicPaymentMetadata{
+			DestinationOwnerAccount: destination.PublicKey().ToBase58(),
+			DestinationTokenAccount: fmt.Sprintf("%s-token", destination.PublicKey().ToBase58()),
+			Quantity:                kin.ToQuarks(kinAmount),
+			ExchangeCurrency:        currency_lib.KIN,
+			ExchangeRate:            1.0,
+			NativeAmount:            float64(kinAmount),
+			UsdMarketValue:          1.0,
+			IsWithdrawal:            isWithdrawal,
+		},
+		State:     intent.StateConfirmed,
+		CreatedAt: createdAt,
+	}))
+}
+
+func (e *exportTestEnv) saveExternalDeposit(t *testing.T, intentId string, destination *common.Account, kinAmount uint64, createdAt time.Time) {
+	require.NoError(t, e.data.SaveIntent(e.ctx, &intent.Record{
+		IntentId:              intentId,
+		IntentType:            intent.ExternalDeposit,
+		InitiatorOwnerAccount: destination.PublicKey().ToBase58(),
+		ExternalDepositMetadata: &intent.ExternalDepositMetadata{
+			DestinationOwnerAccount: destination.PublicKey().ToBase58(),
+			DestinationTokenAccount: fmt.Sprintf("%s-token", destination.PublicKey().ToBase58()),
+			Quantity:                kin.ToQuarks(kinAmount),
+			UsdMarketValue:          1.0,
+		},
+		State:     intent.StateConfirmed,
+		CreatedAt: createdAt,
+	}))
+}
+
+func (e *exportTestEnv) saveExternalDepositForMint(t *testing.T, intentId string, destination *common.Account, m *mint.Mint, quarks uint64, createdAt time.Time) {
+	require.NoError(t, e.data.SaveIntent(e.ctx, &intent.Record{
+		IntentId:              intentId,
+		IntentType:            intent.ExternalDeposit,
+		InitiatorOwnerAccount: destination.PublicKey().ToBase58(),
+		Mint:                  m.ToBase58(),
+		ExternalDepositMetadata: &intent.ExternalDepositMetadata{
+			DestinationOwnerAccount: destination.PublicKey().ToBase58(),
+			DestinationTokenAccount: fmt.Sprintf("%s-%s-token", destination.PublicKey().ToBase58(), m.Symbol),
+			Quantity:                quarks,
+			UsdMarketValue:          1.0,
+		},
+		State:     intent.StateConfirmed,
+		CreatedAt: createdAt,
+	}))
+}
+
+func (e *exportTestEnv) saveFulfillment(t *testing.T, intentId, signature string, state fulfillment.State) {
+	require.NoError(t, e.data.PutAllFulfillments(e.ctx, &fulfillment.Record{
+		Intent:          intentId,
+		IntentType:      intent.SendPublicPayment,
+		ActionType:      action.NoPrivacyTransfer,
+		FulfillmentType: fulfillment.NoPrivacyTransferWithAuthority,
+		Data:            []byte("data"),
+		Signature:       pointer.String(signature),
+		Nonce:           pointer.String(fmt.Sprintf("%s-nonce", signature)),
+		Blockhash:       pointer.String(fmt.Sprintf("%s-blockhash", signature)),
+		Source:          "source",
+		State:           state,
+	}))
+}
+
+func testutilNewRandomAccount(t *testing.T) *common.Account {
+	account, err := common.NewRandomAccount()
+	require.NoError(t, err)
+	return account
+}
@@ -0,0 +1,222 @@
+package history
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/code-payments/code-server/pkg/currency"
+	"github.com/code-payments/code-server/pkg/kin"
+	"github.com/code-payments/code-server/pkg/mint"
+	"github.com/code-payments/code-server/pkg/code/common"
+	code_data "github.com/code-payments/code-server/pkg/code/data"
+	"github.com/code-payments/code-server/pkg/code/data/intent"
+)
+
+type PaymentType uint8
+
+const (
+	PaymentTypeUnknown PaymentType = iota
+	PaymentTypeSend
+	PaymentTypeReceive
+)
+
+// Item is a payment history item, which is a view of an intent that makes
+// sense for a user
+type Item struct {
+	IntentId string
+
+	PaymentType PaymentType
+
+	IsDeposit      bool
+	IsWithdrawal   bool
+	IsRemoteSend   bool
+	IsReturned     bool
+	IsAirdrop      bool
+	IsMicroPayment bool
+
+	// The other party in the payment, which is an owner account when it's a
+	// Code user, or a token account otherwise. Empty when there's no meaningful
+	// counterparty (eg. a migration).
+	Counterparty string
+
+	Currency     currency.Code
+	ExchangeRate float64
+	NativeAmount float64
+
+	// Mint of the tokens moved by the payment, which determines the unit of
+	// the quark amount
+	Mint   *mint.Mint
+	Quarks uint64
+
+	CreatedAt time.Time
+}
+
+// GetItem gets the payment history item for an intent from the perspective of
+// the owner. False is returned when the intent doesn't have a history item. The
+// airdropper is optional, and used to identify airdrops.
+func GetItem(ctx context.Context, data code_data.Provider, owner, airdropper *common.Account, intentRecord *intent.Record) (*Item, bool, error) {
+	intentMint, err := mint.Get(intentRecord.Mint)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "error getting intent mint")
+	}
+
+	item := &Item{
+		IntentId:  intentRecord.IntentId,
+		Mint:      intentMint,
+		CreatedAt: intentRecord.CreatedAt,
+	}
+
+	// Extract payment details from intents, where applicable, into a view
+	// that makes sense for a user.
+	switch intentRecord.IntentType {
+	case intent.SendPrivatePayment:
+		metadata := intentRecord.SendPrivatePaymentMetadata
+
+		item.PaymentType = PaymentTypeSend
+		item.IsRemoteSend = metadata.IsRemoteSend
+		item.IsWithdrawal = metadata.IsWithdrawal
+		item.IsDeposit = false
+		item.IsMicroPayment = metadata.IsMicroPayment
+		item.Counterparty = getDestination(metadata.DestinationOwnerAccount, metadata.DestinationTokenAccount)
+		if intentRecord.InitiatorOwnerAccount != owner.PublicKey().ToBase58() {
+			item.PaymentType = PaymentTypeReceive
+			item.IsWithdrawal = false
+			item.IsDeposit = metadata.IsWithdrawal
+			item.Counterparty = intentRecord.InitiatorOwnerAccount
+		}
+
+		// Funds moving within the same owner don't get populated when they're
+		// used to support another payment flow that represents the history item
+		// (eg. public withdrawals with private top ups)
+		if item.IsWithdrawal && intentRecord.InitiatorOwnerAccount == metadata.DestinationOwnerAccount && !item.IsMicroPayment {
+			return nil, false, nil
+		}
+
+		// Don't show history items where the user voids the gift card.
+		if item.IsRemoteSend {
+			// The gift card is claimed by an unknown party at a later time
+			item.Counterparty = ""
+
+			claimedIntent, err := data.GetGiftCardClaimedIntent(ctx, metadata.DestinationTokenAccount)
+			if err == nil && claimedIntent.ReceivePaymentsPubliclyMetadata.IsIssuerVoidingGiftCard {
+				return nil, false, nil
+			} else if err != nil && err != intent.ErrIntentNotFound {
+				return nil, false, errors.Wrap(err, "error getting gift card claimed intent")
+			}
+		}
+
+		item.Currency = metadata.ExchangeCurrency
+		item.ExchangeRate = metadata.ExchangeRate
+		item.NativeAmount = metadata.NativeAmount
+		item.Quarks = metadata.Quantity
+	case intent.SendPublicPayment:
+		metadata := intentRecord.SendPublicPaymentMetadata
+
+		item.PaymentType = PaymentTypeSend
+		item.IsRemoteSend = false
+		item.IsWithdrawal = metadata.IsWithdrawal
+		item.IsDeposit = false
+		item.IsMicroPayment = false
+		item.Counterparty = getDestination(metadata.DestinationOwnerAccount, metadata.DestinationTokenAccount)
+		if intentRecord.InitiatorOwnerAccount != owner.PublicKey().ToBase58() {
+			item.PaymentType = PaymentTypeReceive
+			item.IsWithdrawal = false
+			item.IsDeposit = metadata.IsWithdrawal
+			item.Counterparty = intentRecord.InitiatorOwnerAccount
+		}
+
+		// Bonus airdrops only occur within Code->Code withdrawal flows
+		if airdropper != nil {
+			item.IsAirdrop = (intentRecord.InitiatorOwnerAccount == airdropper.PublicKey().ToBase58())
+		}
+
+		item.Currency = metadata.ExchangeCurrency
+		item.ExchangeRate = metadata.ExchangeRate
+		item.NativeAmount = metadata.NativeAmount
+		item.Quarks = metadata.Quantity
+	case intent.ReceivePaymentsPrivately:
+		// Other intents account for history items
+		return nil, false, nil
+	case intent.MigrateToPrivacy2022:
+		metadata := intentRecord.MigrateToPrivacy2022Metadata
+
+		// Don't show migrations for dust
+		if metadata.Quantity < kin.ToQuarks(1) {
+			return nil, false, nil
+		}
+
+		item.PaymentType = PaymentTypeReceive
+		item.IsDeposit = true
+		item.Currency = currency.KIN
+		item.ExchangeRate = 1.0
+		item.NativeAmount = float64(metadata.Quantity) / kin.QuarksPerKin
+		item.Quarks = metadata.Quantity
+	case intent.ExternalDeposit:
+		metadata := intentRecord.ExternalDepositMetadata
+
+		if metadata.DestinationOwnerAccount != owner.PublicKey().ToBase58() {
+			return nil, false, nil
+		}
+
+		// Don't show deposits for Kin dust
+		if intentMint.IsKin() && metadata.Quantity < kin.ToQuarks(1) {
+			return nil, false, nil
+		}
+
+		item.PaymentType = PaymentTypeReceive
+		item.IsDeposit = true
+		item.Currency = intentMint.NativeCurrency()
+		item.ExchangeRate = 1.0
+		item.NativeAmount = intentMint.FromQuarks(metadata.Quantity)
+		item.Quarks = metadata.Quantity
+	case intent.ReceivePaymentsPublicly:
+		metadata := intentRecord.ReceivePaymentsPubliclyMetadata
+
+		// The intent to create the remote send gift card has no knowledge of the
+		// destination owner since it's claimed at a later time, so the history item
+		// must come from the intent receiving it.
+		if !metadata.IsRemoteSend {
+			return nil, false, nil
+		}
+
+		// Don't show history items where the user voids the gift card.
+		if metadata.IsIssuerVoidingGiftCard {
+			return nil, false, nil
+		}
+
+		item.PaymentType = PaymentTypeReceive
+		item.IsRemoteSend = metadata.IsRemoteSend
+		item.IsReturned = metadata.IsReturned
+		item.IsWithdrawal = false
+		item.IsDeposit = false
+		item.Counterparty = metadata.Source
+
+		item.Currency = metadata.OriginalExchangeCurrency
+		item.ExchangeRate = metadata.OriginalExchangeRate
+		item.NativeAmount = metadata.OriginalNativeAmount
+		item.Quarks = metadata.Quantity
+	default:
+		return nil, false, nil
+	}
+
+	return item, true, nil
+}
+
+func (t PaymentType) String() string {
+	switch t {
+	case PaymentTypeSend:
+		return "send"
+	case PaymentTypeReceive:
+		return "receive"
+	}
+	return "unknown"
+}
+
+func getDestination(ownerAccount, tokenAccount string) string {
+	if len(ownerAccount) > 0 {
+		return ownerAccount
+	}
+	return tokenAccount
+}
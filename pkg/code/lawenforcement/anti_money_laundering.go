@@ -14,29 +14,30 @@ import (
 	"github.com/code-payments/code-server/pkg/code/common"
 	code_data "github.com/code-payments/code-server/pkg/code/data"
 	"github.com/code-payments/code-server/pkg/code/data/intent"
+	limit_util "github.com/code-payments/code-server/pkg/code/limit"
 )
 
 const (
-	// These limits are intentionally higher than that enforced on clients,
-	// so we can do better rounding on limits per currency.
-	//
-	// todo: configurable
-	maxUsdPrivateBalance   = 500.00  // 2x the 250 USD limit
-	maxUsdTransactionValue = 500.00  // 2x the 250 USD limit
-	maxDailyUsdLimit       = 1500.00 // 1.5x the 1000 USD limit
+	// These limits are intentionally higher than the USD send limits enforced
+	// on clients, so we can do better rounding on limits per currency.
+	privateBalanceMultiplier   = 2.0 // 500 USD for the default 250 USD limit
+	transactionValueMultiplier = 2.0 // 500 USD for the default 250 USD limit
+	dailyLimitMultiplier       = 1.5 // 1500 USD for the default 1000 USD limit
 )
 
 // AntiMoneyLaunderingGuard gates money movement by applying rules on operations
 // of interest to discourage money laundering through Code.
 type AntiMoneyLaunderingGuard struct {
-	log  *logrus.Entry
-	data code_data.Provider
+	log    *logrus.Entry
+	data   code_data.Provider
+	limits *limit_util.Resolver
 }
 
 func NewAntiMoneyLaunderingGuard(data code_data.Provider) *AntiMoneyLaunderingGuard {
 	return &AntiMoneyLaunderingGuard{
-		log:  logrus.StandardLogger().WithField("type", "aml/guard"),
-		data: data,
+		log:    logrus.StandardLogger().WithField("type", "aml/guard"),
+		data:   data,
+		limits: limit_util.NewResolver(data),
 	}
 }
 
@@ -46,6 +47,7 @@ func (g *AntiMoneyLaunderingGuard) AllowMoneyMovement(ctx context.Context, inten
 	tracer := metrics.TraceMethodCall(ctx, metricsStructName, "AllowMoneyMovement")
 	defer tracer.End()
 
+	var limits *limit_util.Limits
 	var usdMarketValue float64
 	var consumptionCalculator func(ctx context.Context, phoneNumber string, since time.Time) (uint64, float64, error)
 	switch intentRecord.IntentType {
@@ -69,6 +71,13 @@ func (g *AntiMoneyLaunderingGuard) AllowMoneyMovement(ctx context.Context, inten
 			return false, err
 		}
 
+		limits, err = g.limits.GetLimits(ctx, owner)
+		if err != nil {
+			tracer.OnError(err)
+			return false, err
+		}
+		maxUsdPrivateBalance := privateBalanceMultiplier * limits.Send[currency_lib.USD].PerTransaction
+
 		totalPrivateBalance, err := balance.GetPrivateBalance(ctx, g.data, owner)
 		if err != nil {
 			tracer.OnError(err)
@@ -113,6 +122,24 @@ func (g *AntiMoneyLaunderingGuard) AllowMoneyMovement(ctx context.Context, inten
 
 	phoneNumber := *intentRecord.InitiatorPhoneNumber
 
+	if limits == nil {
+		owner, err := common.NewAccountFromPublicKeyString(intentRecord.InitiatorOwnerAccount)
+		if err != nil {
+			tracer.OnError(err)
+			return false, err
+		}
+
+		limits, err = g.limits.GetLimits(ctx, owner)
+		if err != nil {
+			log.WithError(err).Warn("failure resolving limits")
+			tracer.OnError(err)
+			return false, err
+		}
+	}
+	usdSendLimit := limits.Send[currency_lib.USD]
+	maxUsdTransactionValue := transactionValueMultiplier * usdSendLimit.PerTransaction
+	maxDailyUsdLimit := dailyLimitMultiplier * usdSendLimit.Daily
+
 	// Bound the maximum dollar value of a payment
 	if usdMarketValue > maxUsdTransactionValue {
 		log.Info("denying intent that exceeds per-transaction usd value")
@@ -13,16 +13,27 @@ import (
 
 	currency_lib "github.com/code-payments/code-server/pkg/currency"
 	"github.com/code-payments/code-server/pkg/kin"
+	"github.com/code-payments/code-server/pkg/pointer"
 	timelock_token "github.com/code-payments/code-server/pkg/solana/timelock/v1"
 	"github.com/code-payments/code-server/pkg/testutil"
 	"github.com/code-payments/code-server/pkg/code/common"
 	code_data "github.com/code-payments/code-server/pkg/code/data"
+	datatest "github.com/code-payments/code-server/pkg/code/data/test"
 	"github.com/code-payments/code-server/pkg/code/data/account"
 	"github.com/code-payments/code-server/pkg/code/data/action"
 	"github.com/code-payments/code-server/pkg/code/data/currency"
 	"github.com/code-payments/code-server/pkg/code/data/intent"
+	"github.com/code-payments/code-server/pkg/code/data/limit"
 	"github.com/code-payments/code-server/pkg/code/data/user"
 	"github.com/code-payments/code-server/pkg/code/data/user/identity"
+	limit_util "github.com/code-payments/code-server/pkg/code/limit"
+)
+
+var (
+	// Thresholds for users without any configured limits
+	maxUsdPrivateBalance   = privateBalanceMultiplier * limit_util.SendLimits[currency_lib.USD].PerTransaction
+	maxUsdTransactionValue = transactionValueMultiplier * limit_util.SendLimits[currency_lib.USD].PerTransaction
+	maxDailyUsdLimit       = dailyLimitMultiplier * limit_util.SendLimits[currency_lib.USD].Daily
 )
 
 func TestAntiMoneyLaunderingGuard_SendPrivatePayment_TransactionValue(t *testing.T) {
@@ -327,6 +338,42 @@ func TestAntiMoneyLaunderingGuard_ReceivePaymentsPublicly(t *testing.T) {
 	}
 }
 
+func TestAntiMoneyLaunderingGuard_SendPrivatePayment_LimitOverride(t *testing.T) {
+	env := setupAmlTest(t)
+
+	phoneNumber := "+12223334444"
+	owner := testutil.NewRandomAccount(t)
+	otherOwner := testutil.NewRandomAccount(t)
+	setupPhoneUser(t, env, phoneNumber)
+
+	require.NoError(t, env.data.PutLimits(env.ctx, &limit.Record{
+		OwnerAccount: pointer.String(owner.PublicKey().ToBase58()),
+
+		Currency: currency_lib.USD,
+
+		SendPerTransaction: 10 * limit_util.SendLimits[currency_lib.USD].PerTransaction,
+		SendDaily:          10 * limit_util.SendLimits[currency_lib.USD].Daily,
+
+		MicroPaymentMin: 0.05,
+		MicroPaymentMax: 1.00,
+
+		EffectiveAt: time.Now().Add(-time.Minute),
+	}))
+
+	allow, err := env.guard.AllowMoneyMovement(env.ctx, makeSendPrivatePaymentIntent(t, phoneNumber, owner, maxUsdTransactionValue+1, time.Now()))
+	require.NoError(t, err)
+	assert.True(t, allow)
+
+	allow, err = env.guard.AllowMoneyMovement(env.ctx, makeSendPrivatePaymentIntent(t, phoneNumber, owner, 10*maxUsdTransactionValue+1, time.Now()))
+	require.NoError(t, err)
+	assert.False(t, allow)
+
+	// Overrides only apply to the owner they're configured for
+	allow, err = env.guard.AllowMoneyMovement(env.ctx, makeSendPrivatePaymentIntent(t, phoneNumber, otherOwner, maxUsdTransactionValue+1, time.Now()))
+	require.NoError(t, err)
+	assert.False(t, allow)
+}
+
 type amlTestEnv struct {
 	ctx   context.Context
 	data  code_data.Provider
@@ -335,7 +382,7 @@ type amlTestEnv struct {
 
 func setupAmlTest(t *testing.T) (env amlTestEnv) {
 	env.ctx = context.Background()
-	env.data = code_data.NewTestDataProvider()
+	env.data = datatest.NewDataProvider()
 	env.guard = NewAntiMoneyLaunderingGuard(env.data)
 
 	testutil.SetupRandomSubsidizer(t, env.data)
@@ -14,7 +14,19 @@ type MicroPaymentLimit struct {
 	Max float64
 }
 
-// todo: Better way of managing all of this
+// DepositLimit is a USD limit on deposits into a user's private balance
+type DepositLimit struct {
+	PerDeposit float64
+	Daily      float64
+}
+
+const (
+	perDepositMultiplier   = 1.2
+	dailyDepositMultiplier = 1.5
+)
+
+// Built-in per-currency defaults, which apply when no limits are configured
+// for a currency. Use a Resolver to get the limits that apply to a user.
 var (
 	SendLimits = map[currency_lib.Code]SendLimit{
 		"aed": {PerTransaction: 1_000.00, Daily: 3_500.00},
@@ -348,7 +360,4 @@ var (
 		"zmw": {Min: 0.50, Max: 10.00},
 		"zwl": {Min: 10.00, Max: 200.00},
 	}
-
-	MaxDailyDepositUsdAmount = 1.5 * SendLimits[currency_lib.USD].Daily
-	MaxPerDepositUsdAmount   = 1.2 * SendLimits[currency_lib.USD].PerTransaction
 )
@@ -0,0 +1,231 @@
+package limit
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	currency_lib "github.com/code-payments/code-server/pkg/currency"
+	"github.com/code-payments/code-server/pkg/metrics"
+	"github.com/code-payments/code-server/pkg/code/common"
+	code_data "github.com/code-payments/code-server/pkg/code/data"
+	"github.com/code-payments/code-server/pkg/code/data/limit"
+	"github.com/code-payments/code-server/pkg/code/data/phone"
+	"github.com/code-payments/code-server/pkg/code/data/user/identity"
+)
+
+const (
+	metricsStructName = "limit.resolver"
+)
+
+// Limits are the limits that apply to a single user, keyed by currency
+type Limits struct {
+	Tier limit.Tier
+
+	Send         map[currency_lib.Code]SendLimit
+	MicroPayment map[currency_lib.Code]MicroPaymentLimit
+}
+
+// GetDepositLimit gets the USD deposit limits, which are derived from the
+// USD send limit
+func (l *Limits) GetDepositLimit() DepositLimit {
+	usdSendLimit := l.Send[currency_lib.USD]
+	return DepositLimit{
+		PerDeposit: perDepositMultiplier * usdSendLimit.PerTransaction,
+		Daily:      dailyDepositMultiplier * usdSendLimit.Daily,
+	}
+}
+
+// Resolver resolves the limits that apply to a user. In order of precedence,
+// limits come from per-user overrides, the user's tier and then the default
+// tier. Built-in defaults apply to currencies without any configured limits.
+type Resolver struct {
+	log  *logrus.Entry
+	data code_data.Provider
+}
+
+func NewResolver(data code_data.Provider) *Resolver {
+	return &Resolver{
+		log:  logrus.StandardLogger().WithField("type", "limit/resolver"),
+		data: data,
+	}
+}
+
+// GetTier gets the limit tier for an owner account. An explicit tier assignment
+// takes precedence over staff status.
+func (r *Resolver) GetTier(ctx context.Context, owner *common.Account) (limit.Tier, error) {
+	tracer := metrics.TraceMethodCall(ctx, metricsStructName, "GetTier")
+	defer tracer.End()
+
+	tier, err := r.getTier(ctx, owner)
+	if err != nil {
+		tracer.OnError(err)
+	}
+	return tier, err
+}
+
+// GetLimits gets all limits that currently apply to an owner account
+func (r *Resolver) GetLimits(ctx context.Context, owner *common.Account) (*Limits, error) {
+	tracer := metrics.TraceMethodCall(ctx, metricsStructName, "GetLimits")
+	defer tracer.End()
+
+	log := r.log.WithFields(logrus.Fields{
+		"method": "GetLimits",
+		"owner":  owner.PublicKey().ToBase58(),
+	})
+
+	tier, err := r.getTier(ctx, owner)
+	if err != nil {
+		log.WithError(err).Warn("failure getting tier")
+		tracer.OnError(err)
+		return nil, err
+	}
+
+	res, err := r.getLimits(ctx, tier, owner)
+	if err != nil {
+		log.WithError(err).Warn("failure getting limits")
+		tracer.OnError(err)
+		return nil, err
+	}
+	return res, nil
+}
+
+// GetDefaultLimits gets the default tier limits, which apply to accounts that
+// aren't owned by a Code user (eg. external accounts)
+func (r *Resolver) GetDefaultLimits(ctx context.Context) (*Limits, error) {
+	tracer := metrics.TraceMethodCall(ctx, metricsStructName, "GetDefaultLimits")
+	defer tracer.End()
+
+	res, err := r.getLimits(ctx, limit.DefaultTier, nil)
+	if err != nil {
+		r.log.WithField("method", "GetDefaultLimits").WithError(err).Warn("failure getting limits")
+		tracer.OnError(err)
+		return nil, err
+	}
+	return res, nil
+}
+
+// GetMicroPaymentLimit gets the micro payment limit that currently applies to
+// an owner account in the provided currency. False is returned when the currency
+// isn't supported.
+func (r *Resolver) GetMicroPaymentLimit(ctx context.Context, owner *common.Account, currency currency_lib.Code) (MicroPaymentLimit, bool, error) {
+	limits, err := r.GetLimits(ctx, owner)
+	if err != nil {
+		return MicroPaymentLimit{}, false, err
+	}
+
+	microPaymentLimit, ok := limits.MicroPayment[currency]
+	return microPaymentLimit, ok, nil
+}
+
+// GetDefaultMicroPaymentLimit is GetMicroPaymentLimit for the default tier
+func (r *Resolver) GetDefaultMicroPaymentLimit(ctx context.Context, currency currency_lib.Code) (MicroPaymentLimit, bool, error) {
+	limits, err := r.GetDefaultLimits(ctx)
+	if err != nil {
+		return MicroPaymentLimit{}, false, err
+	}
+
+	microPaymentLimit, ok := limits.MicroPayment[currency]
+	return microPaymentLimit, ok, nil
+}
+
+// getLimits layers the configured limits for the tier, and the owner's
+// overrides when an owner is provided, on top of the built-in defaults
+func (r *Resolver) getLimits(ctx context.Context, tier limit.Tier, owner *common.Account) (*Limits, error) {
+	res := &Limits{
+		Tier:         tier,
+		Send:         make(map[currency_lib.Code]SendLimit),
+		MicroPayment: make(map[currency_lib.Code]MicroPaymentLimit),
+	}
+	for currency, sendLimit := range SendLimits {
+		res.Send[currency] = sendLimit
+	}
+	for currency, microPaymentLimit := range MicroPaymentLimits {
+		res.MicroPayment[currency] = microPaymentLimit
+	}
+
+	tiers := []limit.Tier{limit.DefaultTier}
+	if tier != limit.DefaultTier {
+		tiers = append(tiers, tier)
+	}
+
+	var layers [][]*limit.Record
+	for _, tier := range tiers {
+		records, err := r.data.GetAllLimitsByTier(ctx, tier)
+		if err != nil && err != limit.ErrLimitsNotFound {
+			return nil, errors.Wrapf(err, "error getting %s tier limits", tier.String())
+		}
+		layers = append(layers, records)
+	}
+
+	if owner != nil {
+		overrides, err := r.data.GetAllLimitOverridesByOwner(ctx, owner.PublicKey().ToBase58())
+		if err != nil && err != limit.ErrLimitsNotFound {
+			return nil, errors.Wrap(err, "error getting limit overrides")
+		}
+		layers = append(layers, overrides)
+	}
+
+	now := time.Now()
+	for _, records := range layers {
+		for currency, record := range getEffectiveRecords(records, now) {
+			res.Send[currency] = SendLimit{
+				PerTransaction: record.SendPerTransaction,
+				Daily:          record.SendDaily,
+			}
+			res.MicroPayment[currency] = MicroPaymentLimit{
+				Min: record.MicroPaymentMin,
+				Max: record.MicroPaymentMax,
+			}
+		}
+	}
+
+	return res, nil
+}
+
+func (r *Resolver) getTier(ctx context.Context, owner *common.Account) (limit.Tier, error) {
+	tierAssignmentRecord, err := r.data.GetLimitTierAssignment(ctx, owner.PublicKey().ToBase58())
+	if err == nil {
+		return tierAssignmentRecord.Tier, nil
+	} else if err != limit.ErrTierAssignmentNotFound {
+		return limit.UnknownTier, err
+	}
+
+	verificationRecord, err := r.data.GetLatestPhoneVerificationForAccount(ctx, owner.PublicKey().ToBase58())
+	if err == phone.ErrVerificationNotFound {
+		return limit.DefaultTier, nil
+	} else if err != nil {
+		return limit.UnknownTier, err
+	}
+
+	userIdentityRecord, err := r.data.GetUserByPhoneView(ctx, verificationRecord.PhoneNumber)
+	if err == identity.ErrNotFound {
+		return limit.DefaultTier, nil
+	} else if err != nil {
+		return limit.UnknownTier, err
+	}
+
+	if userIdentityRecord.IsStaffUser {
+		return limit.StaffTier, nil
+	}
+	return limit.DefaultTier, nil
+}
+
+// getEffectiveRecords gets the effective record per currency. When multiple
+// records are effective, the one with the latest effective date applies.
+func getEffectiveRecords(records []*limit.Record, at time.Time) map[currency_lib.Code]*limit.Record {
+	res := make(map[currency_lib.Code]*limit.Record)
+	for _, record := range records {
+		if !record.IsEffective(at) {
+			continue
+		}
+
+		existing, ok := res[record.Currency]
+		if !ok || !record.EffectiveAt.Before(existing.EffectiveAt) {
+			res[record.Currency] = record
+		}
+	}
+	return res
+}
@@ -0,0 +1,255 @@
+package limit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	currency_lib "github.com/code-payments/code-server/pkg/currency"
+	"github.com/code-payments/code-server/pkg/pointer"
+	"github.com/code-payments/code-server/pkg/testutil"
+	"github.com/code-payments/code-server/pkg/code/common"
+	code_data "github.com/code-payments/code-server/pkg/code/data"
+	datatest "github.com/code-payments/code-server/pkg/code/data/test"
+	"github.com/code-payments/code-server/pkg/code/data/limit"
+	"github.com/code-payments/code-server/pkg/code/data/phone"
+	"github.com/code-payments/code-server/pkg/code/data/user"
+	"github.com/code-payments/code-server/pkg/code/data/user/identity"
+)
+
+type testEnv struct {
+	ctx      context.Context
+	data     code_data.Provider
+	resolver *Resolver
+}
+
+func setup(t *testing.T) (env testEnv) {
+	env.ctx = context.Background()
+	env.data = datatest.NewDataProvider()
+	env.resolver = NewResolver(env.data)
+	return env
+}
+
+func TestGetLimits_BuiltInDefaults(t *testing.T) {
+	env := setup(t)
+
+	owner := testutil.NewRandomAccount(t)
+
+	limits, err := env.resolver.GetLimits(env.ctx, owner)
+	require.NoError(t, err)
+	assert.Equal(t, limit.DefaultTier, limits.Tier)
+	assert.Equal(t, SendLimits, limits.Send)
+	assert.Equal(t, MicroPaymentLimits, limits.MicroPayment)
+
+	depositLimit := limits.GetDepositLimit()
+	assert.Equal(t, perDepositMultiplier*SendLimits[currency_lib.USD].PerTransaction, depositLimit.PerDeposit)
+	assert.Equal(t, dailyDepositMultiplier*SendLimits[currency_lib.USD].Daily, depositLimit.Daily)
+}
+
+func TestGetLimits_DefaultTier(t *testing.T) {
+	env := setup(t)
+
+	owner := testutil.NewRandomAccount(t)
+
+	env.putLimits(t, newTestRecord(limit.DefaultTier, nil, currency_lib.USD, 100, time.Now().Add(-time.Hour), nil))
+
+	limits, err := env.resolver.GetLimits(env.ctx, owner)
+	require.NoError(t, err)
+	assert.Equal(t, limit.DefaultTier, limits.Tier)
+	assertLimits(t, limits, currency_lib.USD, 100)
+
+	// Currencies without configured limits fall back to built-in defaults
+	assert.Equal(t, SendLimits[currency_lib.CAD], limits.Send[currency_lib.CAD])
+	assert.Equal(t, MicroPaymentLimits[currency_lib.CAD], limits.MicroPayment[currency_lib.CAD])
+}
+
+func TestGetLimits_StaffTier(t *testing.T) {
+	env := setup(t)
+
+	phoneNumber := "+12223334444"
+	staffOwner := testutil.NewRandomAccount(t)
+	otherOwner := testutil.NewRandomAccount(t)
+
+	env.setupStaffUser(t, phoneNumber, staffOwner)
+
+	env.putLimits(t, newTestRecord(limit.DefaultTier, nil, currency_lib.USD, 100, time.Now().Add(-time.Hour), nil))
+	env.putLimits(t, newTestRecord(limit.DefaultTier, nil, currency_lib.CAD, 100, time.Now().Add(-time.Hour), nil))
+	env.putLimits(t, newTestRecord(limit.StaffTier, nil, currency_lib.USD, 500, time.Now().Add(-time.Hour), nil))
+
+	limits, err := env.resolver.GetLimits(env.ctx, staffOwner)
+	require.NoError(t, err)
+	assert.Equal(t, limit.StaffTier, limits.Tier)
+	assertLimits(t, limits, currency_lib.USD, 500)
+	assertLimits(t, limits, currency_lib.CAD, 100)
+
+	limits, err = env.resolver.GetLimits(env.ctx, otherOwner)
+	require.NoError(t, err)
+	assert.Equal(t, limit.DefaultTier, limits.Tier)
+	assertLimits(t, limits, currency_lib.USD, 100)
+	assertLimits(t, limits, currency_lib.CAD, 100)
+}
+
+func TestGetLimits_TierAssignment(t *testing.T) {
+	env := setup(t)
+
+	phoneNumber := "+12223334444"
+	owner := testutil.NewRandomAccount(t)
+
+	env.setupStaffUser(t, phoneNumber, owner)
+
+	env.putLimits(t, newTestRecord(limit.StaffTier, nil, currency_lib.USD, 500, time.Now().Add(-time.Hour), nil))
+	env.putLimits(t, newTestRecord(limit.VerifiedMerchantTier, nil, currency_lib.USD, 750, time.Now().Add(-time.Hour), nil))
+
+	// Explicit tier assignments take precedence over staff status
+	require.NoError(t, env.data.PutLimitTierAssignment(env.ctx, &limit.TierAssignment{
+		OwnerAccount: owner.PublicKey().ToBase58(),
+		Tier:         limit.VerifiedMerchantTier,
+		CreatedAt:    time.Now(),
+	}))
+
+	tier, err := env.resolver.GetTier(env.ctx, owner)
+	require.NoError(t, err)
+	assert.Equal(t, limit.VerifiedMerchantTier, tier)
+
+	limits, err := env.resolver.GetLimits(env.ctx, owner)
+	require.NoError(t, err)
+	assert.Equal(t, limit.VerifiedMerchantTier, limits.Tier)
+	assertLimits(t, limits, currency_lib.USD, 750)
+}
+
+func TestGetLimits_Override(t *testing.T) {
+	env := setup(t)
+
+	owner := testutil.NewRandomAccount(t)
+	otherOwner := testutil.NewRandomAccount(t)
+
+	require.NoError(t, env.data.PutLimitTierAssignment(env.ctx, &limit.TierAssignment{
+		OwnerAccount: owner.PublicKey().ToBase58(),
+		Tier:         limit.VerifiedMerchantTier,
+		CreatedAt:    time.Now(),
+	}))
+
+	env.putLimits(t, newTestRecord(limit.DefaultTier, nil, currency_lib.USD, 100, time.Now().Add(-time.Hour), nil))
+	env.putLimits(t, newTestRecord(limit.VerifiedMerchantTier, nil, currency_lib.USD, 750, time.Now().Add(-time.Hour), nil))
+	env.putLimits(t, newTestRecord(limit.UnknownTier, pointer.String(owner.PublicKey().ToBase58()), currency_lib.USD, 25, time.Now().Add(-time.Hour), nil))
+
+	// Overrides take precedence over all tiers, including when they're lower
+	limits, err := env.resolver.GetLimits(env.ctx, owner)
+	require.NoError(t, err)
+	assert.Equal(t, limit.VerifiedMerchantTier, limits.Tier)
+	assertLimits(t, limits, currency_lib.USD, 25)
+
+	microPaymentLimit, ok, err := env.resolver.GetMicroPaymentLimit(env.ctx, owner, currency_lib.USD)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, limits.MicroPayment[currency_lib.USD], microPaymentLimit)
+
+	limits, err = env.resolver.GetLimits(env.ctx, otherOwner)
+	require.NoError(t, err)
+	assertLimits(t, limits, currency_lib.USD, 100)
+}
+
+func TestGetLimits_EffectiveDates(t *testing.T) {
+	env := setup(t)
+
+	owner := testutil.NewRandomAccount(t)
+	ownerAccount := pointer.String(owner.PublicKey().ToBase58())
+
+	expired := time.Now().Add(-time.Minute)
+
+	env.putLimits(t, newTestRecord(limit.DefaultTier, nil, currency_lib.USD, 100, time.Now().Add(-2*time.Hour), nil))
+	env.putLimits(t, newTestRecord(limit.DefaultTier, nil, currency_lib.USD, 200, time.Now().Add(-time.Hour), nil))
+	env.putLimits(t, newTestRecord(limit.DefaultTier, nil, currency_lib.USD, 300, time.Now().Add(time.Hour), nil))
+	env.putLimits(t, newTestRecord(limit.UnknownTier, ownerAccount, currency_lib.USD, 400, time.Now().Add(-time.Hour), &expired))
+	env.putLimits(t, newTestRecord(limit.UnknownTier, ownerAccount, currency_lib.USD, 500, time.Now().Add(time.Hour), nil))
+
+	// The latest effective tier limit applies, while future and expired limits
+	// are ignored
+	limits, err := env.resolver.GetLimits(env.ctx, owner)
+	require.NoError(t, err)
+	assertLimits(t, limits, currency_lib.USD, 200)
+}
+
+func TestGetDefaultLimits(t *testing.T) {
+	env := setup(t)
+
+	owner := testutil.NewRandomAccount(t)
+
+	env.putLimits(t, newTestRecord(limit.DefaultTier, nil, currency_lib.USD, 100, time.Now().Add(-time.Hour), nil))
+	env.putLimits(t, newTestRecord(limit.StaffTier, nil, currency_lib.USD, 500, time.Now().Add(-time.Hour), nil))
+	env.putLimits(t, newTestRecord(limit.UnknownTier, pointer.String(owner.PublicKey().ToBase58()), currency_lib.USD, 25, time.Now().Add(-time.Hour), nil))
+
+	// Only the default tier applies, without any overrides
+	limits, err := env.resolver.GetDefaultLimits(env.ctx)
+	require.NoError(t, err)
+	assert.Equal(t, limit.DefaultTier, limits.Tier)
+	assertLimits(t, limits, currency_lib.USD, 100)
+	assert.Equal(t, MicroPaymentLimits[currency_lib.CAD], limits.MicroPayment[currency_lib.CAD])
+
+	microPaymentLimit, ok, err := env.resolver.GetDefaultMicroPaymentLimit(env.ctx, currency_lib.USD)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, limits.MicroPayment[currency_lib.USD], microPaymentLimit)
+
+	_, ok, err = env.resolver.GetDefaultMicroPaymentLimit(env.ctx, currency_lib.Code("xyz"))
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestGetMicroPaymentLimit_UnsupportedCurrency(t *testing.T) {
+	env := setup(t)
+
+	_, ok, err := env.resolver.GetMicroPaymentLimit(env.ctx, testutil.NewRandomAccount(t), currency_lib.Code("xyz"))
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func (e *testEnv) putLimits(t *testing.T, record *limit.Record) {
+	require.NoError(t, e.data.PutLimits(e.ctx, record))
+}
+
+func (e *testEnv) setupStaffUser(t *testing.T, phoneNumber string, owner *common.Account) {
+	require.NoError(t, e.data.SavePhoneVerification(e.ctx, &phone.Verification{
+		PhoneNumber:    phoneNumber,
+		OwnerAccount:   owner.PublicKey().ToBase58(),
+		CreatedAt:      time.Now(),
+		LastVerifiedAt: time.Now(),
+	}))
+
+	require.NoError(t, e.data.PutUser(e.ctx, &identity.Record{
+		ID: user.NewUserID(),
+		View: &user.View{
+			PhoneNumber: &phoneNumber,
+		},
+		IsStaffUser: true,
+		CreatedAt:   time.Now(),
+	}))
+}
+
+func newTestRecord(tier limit.Tier, owner *string, currency currency_lib.Code, sendPerTransaction float64, effectiveAt time.Time, expiresAt *time.Time) *limit.Record {
+	return &limit.Record{
+		Tier:         tier,
+		OwnerAccount: owner,
+
+		Currency: currency,
+
+		SendPerTransaction: sendPerTransaction,
+		SendDaily:          4 * sendPerTransaction,
+
+		MicroPaymentMin: sendPerTransaction / 1000,
+		MicroPaymentMax: sendPerTransaction / 10,
+
+		EffectiveAt: effectiveAt,
+		ExpiresAt:   expiresAt,
+
+		CreatedAt: time.Now(),
+	}
+}
+
+func assertLimits(t *testing.T, limits *Limits, currency currency_lib.Code, sendPerTransaction float64) {
+	assert.Equal(t, SendLimit{PerTransaction: sendPerTransaction, Daily: 4 * sendPerTransaction}, limits.Send[currency])
+	assert.Equal(t, MicroPaymentLimit{Min: sendPerTransaction / 1000, Max: sendPerTransaction / 10}, limits.MicroPayment[currency])
+}
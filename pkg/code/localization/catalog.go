@@ -0,0 +1,240 @@
+package localization
+
+import (
+	"embed"
+	"encoding/json"
+	"io/fs"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// DefaultLocale is the locale used when a translation isn't available in the
+// requested locale
+var DefaultLocale = language.English
+
+//go:embed translations/*.json
+var translations embed.FS
+
+var defaultCatalog = mustLoadDefaultCatalog()
+
+var (
+	argPattern = regexp.MustCompile(`\{(\d+|count)\}`)
+
+	pluralFormsByName = map[string]plural.Form{
+		"zero":  plural.Zero,
+		"one":   plural.One,
+		"two":   plural.Two,
+		"few":   plural.Few,
+		"many":  plural.Many,
+		"other": plural.Other,
+	}
+)
+
+// Catalog is a set of translations keyed by locale and localization key.
+//
+// Translations are loaded from JSON files named after their locale (eg. en.json,
+// es-MX.json). Each value is either a string, or an object of CLDR plural forms
+// (zero, one, two, few, many, other) where other is required. Arguments are
+// referenced positionally as {0}, {1}, etc., and the plural count as {count}.
+type Catalog struct {
+	defaultLocale    language.Tag
+	messagesByLocale map[language.Tag]map[string]*translation
+}
+
+type translation struct {
+	forms map[plural.Form]string
+}
+
+// NewCatalog loads a catalog from all JSON translation files in the root of the
+// provided file system
+func NewCatalog(fsys fs.FS, defaultLocale language.Tag) (*Catalog, error) {
+	fileNames, err := fs.Glob(fsys, "*.json")
+	if err != nil {
+		return nil, errors.Wrap(err, "error listing translation files")
+	}
+
+	c := &Catalog{
+		defaultLocale:    defaultLocale,
+		messagesByLocale: make(map[language.Tag]map[string]*translation),
+	}
+
+	for _, fileName := range fileNames {
+		locale, err := language.Parse(strings.TrimSuffix(fileName, path.Ext(fileName)))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid locale for translation file %s", fileName)
+		}
+
+		data, err := fs.ReadFile(fsys, fileName)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error reading translation file %s", fileName)
+		}
+
+		messages, err := parseTranslations(data)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error parsing translation file %s", fileName)
+		}
+
+		c.messagesByLocale[locale] = messages
+	}
+
+	if _, ok := c.messagesByLocale[defaultLocale]; !ok {
+		return nil, errors.Errorf("translations for default locale %s not found", defaultLocale.String())
+	}
+
+	return c, nil
+}
+
+// Localize renders the text for a key in the provided locale. False is returned
+// when no translation exists in the locale, its parents or the default locale.
+func (c *Catalog) Localize(locale language.Tag, key string, args ...interface{}) (string, bool) {
+	matched, translation, ok := c.find(locale, key)
+	if !ok {
+		return "", false
+	}
+
+	return format(matched, translation.forms[plural.Other], nil, args), true
+}
+
+// LocalizePlural is like Localize, but selects the plural form of the translation
+// that matches count in the provided locale.
+func (c *Catalog) LocalizePlural(locale language.Tag, key string, count int, args ...interface{}) (string, bool) {
+	matched, translation, ok := c.find(locale, key)
+	if !ok {
+		return "", false
+	}
+
+	text, ok := translation.forms[plural.Cardinal.MatchPlural(matched, abs(count), 0, 0, 0, 0)]
+	if !ok {
+		text = translation.forms[plural.Other]
+	}
+
+	return format(matched, text, &count, args), true
+}
+
+// find finds the translation for a key by walking up the requested locale's
+// parents before falling back to the default locale
+func (c *Catalog) find(locale language.Tag, key string) (language.Tag, *translation, bool) {
+	key = normalizeKey(key)
+
+	for current := locale; ; current = current.Parent() {
+		if messages, ok := c.messagesByLocale[current]; ok {
+			if translation, ok := messages[key]; ok {
+				return current, translation, true
+			}
+		}
+
+		if current.IsRoot() {
+			break
+		}
+	}
+
+	translation, ok := c.messagesByLocale[c.defaultLocale][key]
+	return c.defaultLocale, translation, ok
+}
+
+func parseTranslations(data []byte) (map[string]*translation, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	res := make(map[string]*translation)
+	for key, value := range raw {
+		var text string
+		if err := json.Unmarshal(value, &text); err == nil {
+			res[normalizeKey(key)] = &translation{
+				forms: map[plural.Form]string{plural.Other: text},
+			}
+			continue
+		}
+
+		var pluralForms map[string]string
+		if err := json.Unmarshal(value, &pluralForms); err != nil {
+			return nil, errors.Errorf("%s must be a string or plural forms object", key)
+		}
+
+		forms := make(map[plural.Form]string)
+		for name, text := range pluralForms {
+			form, ok := pluralFormsByName[name]
+			if !ok {
+				return nil, errors.Errorf("%s has unknown plural form %s", key, name)
+			}
+			forms[form] = text
+		}
+
+		if _, ok := forms[plural.Other]; !ok {
+			return nil, errors.Errorf("%s is missing the other plural form", key)
+		}
+
+		res[normalizeKey(key)] = &translation{forms: forms}
+	}
+	return res, nil
+}
+
+// format substitutes arguments into text, formatting each for the locale.
+// Placeholders without a corresponding argument are left as is.
+func format(locale language.Tag, text string, count *int, args []interface{}) string {
+	printer := message.NewPrinter(locale)
+
+	return argPattern.ReplaceAllStringFunc(text, func(placeholder string) string {
+		name := placeholder[1 : len(placeholder)-1]
+
+		if name == "count" {
+			if count == nil {
+				return placeholder
+			}
+			return printer.Sprint(*count)
+		}
+
+		index, err := strconv.Atoi(name)
+		if err != nil || index >= len(args) {
+			return placeholder
+		}
+		return printer.Sprint(args[index])
+	})
+}
+
+// normalizeKey normalizes keys to the iOS format, so keys in either device
+// format resolve to the same translation
+func normalizeKey(key string) string {
+	return GetIosLocalizationKey(key)
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func mustLoadDefaultCatalog() *Catalog {
+	fsys, err := fs.Sub(translations, "translations")
+	if err != nil {
+		panic(err)
+	}
+
+	c, err := NewCatalog(fsys, DefaultLocale)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// Localize renders the text for a key in the provided locale using the catalog
+// of translations embedded in the server
+func Localize(locale language.Tag, key string, args ...interface{}) (string, bool) {
+	return defaultCatalog.Localize(locale, key, args...)
+}
+
+// LocalizePlural renders the plural text for a key in the provided locale using
+// the catalog of translations embedded in the server
+func LocalizePlural(locale language.Tag, key string, count int, args ...interface{}) (string, bool) {
+	return defaultCatalog.LocalizePlural(locale, key, count, args...)
+}
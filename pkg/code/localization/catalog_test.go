@@ -0,0 +1,175 @@
+package localization
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestCatalog_LocaleFallback(t *testing.T) {
+	catalog := newTestCatalog(t)
+
+	for _, tc := range []struct {
+		locale   string
+		key      string
+		expected string
+	}{
+		{"en", "greeting", "Hello"},
+		{"en-GB", "greeting", "Hello"},
+		{"es", "greeting", "Hola"},
+		{"es-MX", "greeting", "Qué onda"},
+		{"es-AR", "greeting", "Hola"},
+		{"es-MX", "farewell", "Adiós"},
+		{"es", "only.english", "Only in English"},
+		{"ja", "greeting", "Hello"},
+
+		// Keys in either device format resolve to the same translation
+		{"en", "only_english", "Only in English"},
+	} {
+		actual, ok := catalog.Localize(language.MustParse(tc.locale), tc.key)
+		require.True(t, ok, "%s %s", tc.locale, tc.key)
+		assert.Equal(t, tc.expected, actual, "%s %s", tc.locale, tc.key)
+	}
+
+	_, ok := catalog.Localize(language.English, "missing")
+	assert.False(t, ok)
+
+	_, ok = catalog.LocalizePlural(language.English, "missing", 1)
+	assert.False(t, ok)
+}
+
+func TestCatalog_Pluralization(t *testing.T) {
+	catalog := newTestCatalog(t)
+
+	for _, tc := range []struct {
+		locale   string
+		count    int
+		expected string
+	}{
+		{"en", 0, "0 new messages"},
+		{"en", 1, "1 new message"},
+		{"en", 2, "2 new messages"},
+		{"en", 1000, "1,000 new messages"},
+		{"ru", 1, "1 новое сообщение"},
+		{"ru", 3, "3 новых сообщения"},
+		{"ru", 5, "5 новых сообщений"},
+		{"ru", 21, "21 новое сообщение"},
+
+		// Missing plural forms for the locale use the other form
+		{"es", 1, "1 mensajes nuevos"},
+	} {
+		actual, ok := catalog.LocalizePlural(language.MustParse(tc.locale), "messages.new", tc.count)
+		require.True(t, ok)
+		assert.Equal(t, tc.expected, actual, "%s %d", tc.locale, tc.count)
+	}
+
+	// Without a count, plural translations use the other form
+	actual, ok := catalog.Localize(language.English, "messages.new")
+	require.True(t, ok)
+	assert.Equal(t, "{count} new messages", actual)
+}
+
+func TestCatalog_ArgumentFormatting(t *testing.T) {
+	catalog := newTestCatalog(t)
+
+	actual, ok := catalog.Localize(language.English, "payment.received", "Alice", 1234567)
+	require.True(t, ok)
+	assert.Equal(t, "Alice sent you 1,234,567 Kin", actual)
+
+	actual, ok = catalog.Localize(language.Spanish, "payment.received", "Alice", 1234567)
+	require.True(t, ok)
+	assert.Equal(t, "Alice te envió 1.234.567 Kin", actual)
+
+	// Placeholders without an argument are left as is
+	actual, ok = catalog.Localize(language.English, "payment.received", "Alice")
+	require.True(t, ok)
+	assert.Equal(t, "Alice sent you {1} Kin", actual)
+}
+
+func TestNewCatalog_Validation(t *testing.T) {
+	for _, fsys := range []fstest.MapFS{
+		// No translations for the default locale
+		{
+			"es.json": {Data: []byte(`{"greeting": "Hola"}`)},
+		},
+		// Invalid locale file name
+		{
+			"en.json":            {Data: []byte(`{"greeting": "Hello"}`)},
+			"not-a-locale!.json": {Data: []byte(`{"greeting": "Hello"}`)},
+		},
+		// Malformed JSON
+		{
+			"en.json": {Data: []byte(`{"greeting": `)},
+		},
+		// Unsupported value type
+		{
+			"en.json": {Data: []byte(`{"greeting": 1}`)},
+		},
+		// Unknown plural form
+		{
+			"en.json": {Data: []byte(`{"greeting": {"one": "Hello", "other": "Hellos", "some": "Hellos"}}`)},
+		},
+		// Missing other plural form
+		{
+			"en.json": {Data: []byte(`{"greeting": {"one": "Hello"}}`)},
+		},
+	} {
+		_, err := NewCatalog(fsys, language.English)
+		assert.Error(t, err)
+	}
+}
+
+func TestDefaultCatalog_AllKeysTranslated(t *testing.T) {
+	for _, locale := range []language.Tag{language.English, language.Spanish} {
+		for _, key := range []string{
+			PushTitleDepositReceived,
+			PushSubtitleDepositReceived,
+			PushTitleKinReturned,
+			PushSubtitleKinReturned,
+			PushTitleMicroPaymentReceived,
+			PushSubtitleMicroPaymentReceived,
+			ChatTitleCashTransactions,
+			ChatTitleCodeTeam,
+			ChatTitlePayments,
+			ChatMessagePromotionBonus,
+			ChatMessageRecurringPaymentFailed,
+			ChatMessageRecurringPaymentCancelled,
+			ChatMessageReferralBonus,
+			ChatMessageWelcomeBonus,
+		} {
+			_, ok := defaultCatalog.messagesByLocale[locale][normalizeKey(key)]
+			assert.True(t, ok, "%s missing %s translation", key, locale.String())
+		}
+	}
+}
+
+func newTestCatalog(t *testing.T) *Catalog {
+	fsys := fstest.MapFS{
+		"en.json": {Data: []byte(`{
+			"greeting": "Hello",
+			"farewell": "Goodbye",
+			"only.english": "Only in English",
+			"messages.new": {"one": "{count} new message", "other": "{count} new messages"},
+			"payment.received": "{0} sent you {1} Kin"
+		}`)},
+		"es.json": {Data: []byte(`{
+			"greeting": "Hola",
+			"farewell": "Adiós",
+			"messages.new": {"other": "{count} mensajes nuevos"},
+			"payment.received": "{0} te envió {1} Kin"
+		}`)},
+		"es-MX.json": {Data: []byte(`{
+			"greeting": "Qué onda"
+		}`)},
+		"ru.json": {Data: []byte(`{
+			"messages.new": {"one": "{count} новое сообщение", "few": "{count} новых сообщения", "many": "{count} новых сообщений", "other": "{count} новых сообщения"}
+		}`)},
+	}
+
+	catalog, err := NewCatalog(fsys, language.English)
+	require.NoError(t, err)
+	return catalog
+}
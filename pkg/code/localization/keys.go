@@ -11,6 +11,9 @@ const (
 	PushTitleKinReturned    = "push.title.kinReturned"
 	PushSubtitleKinReturned = "push.subtitle.kinReturned"
 
+	PushTitleMicroPaymentReceived    = "push.title.microPaymentReceived"
+	PushSubtitleMicroPaymentReceived = "push.subtitle.microPaymentReceived"
+
 	//
 	// Section: Chats
 	//
@@ -23,6 +26,9 @@ const (
 
 	// Message Bodies
 
-	ChatMessageReferralBonus = "subtitle.chat.referralBonus"
-	ChatMessageWelcomeBonus  = "subtitle.chat.welcomeBonus"
+	ChatMessagePromotionBonus            = "subtitle.chat.promotionBonus"
+	ChatMessageRecurringPaymentFailed    = "subtitle.chat.recurringPaymentFailed"
+	ChatMessageRecurringPaymentCancelled = "subtitle.chat.recurringPaymentCancelled"
+	ChatMessageReferralBonus             = "subtitle.chat.referralBonus"
+	ChatMessageWelcomeBonus              = "subtitle.chat.welcomeBonus"
 )
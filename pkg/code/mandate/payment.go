@@ -0,0 +1,398 @@
+package mandate
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"database/sql"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/mr-tron/base58"
+	"github.com/pkg/errors"
+
+	"github.com/code-payments/code-server/pkg/pointer"
+	"github.com/code-payments/code-server/pkg/solana"
+	timelock_token_v1 "github.com/code-payments/code-server/pkg/solana/timelock/v1"
+	"github.com/code-payments/code-server/pkg/code/common"
+	code_data "github.com/code-payments/code-server/pkg/code/data"
+	"github.com/code-payments/code-server/pkg/code/data/action"
+	"github.com/code-payments/code-server/pkg/code/data/fulfillment"
+	"github.com/code-payments/code-server/pkg/code/data/intent"
+	"github.com/code-payments/code-server/pkg/code/data/mandate"
+	"github.com/code-payments/code-server/pkg/code/data/nonce"
+	"github.com/code-payments/code-server/pkg/code/transaction"
+)
+
+const (
+	paymentIntentPrefix = "mandate-payment-"
+
+	// Assumes the owner signature index is consistent across all payment
+	// transactions, where the subsidizer is the fee payer.
+	ownerSignatureIndex = 1
+)
+
+var (
+	ErrInvalidSignature       = errors.New("invalid payment signature")
+	ErrUnexpectedMandateState = errors.New("unexpected mandate state")
+)
+
+// GetPaymentIntentId gets the deterministic intent ID for the payment at the
+// provided index of a mandate
+func GetPaymentIntentId(mandateId string, index uint32) string {
+	hashed := sha256.Sum256([]byte(fmt.Sprintf("%s%s-%d", paymentIntentPrefix, mandateId, index)))
+	return base58.Encode(hashed[:])
+}
+
+// ReservePayments creates a mandate pending authorization along with a dormant
+// action and fulfillment for each of its payments. Every payment is assigned
+// its own nonce, so it can be submitted independently of all others. Nonces are
+// held until the payment is made, so they're drawn from a dedicated pool that
+// can't starve client transactions. The
+// returned transactions are signed by the subsidizer and must also be signed
+// by the owner to authorize the mandate.
+func ReservePayments(ctx context.Context, data code_data.Provider, record *mandate.Record) ([]solana.Transaction, error) {
+	if record.State != mandate.StatePendingAuthorization {
+		return nil, ErrUnexpectedMandateState
+	}
+
+	owner, err := common.NewAccountFromPublicKeyString(record.OwnerAccount)
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := owner.ResolveTimelockAccounts(ctx, data, timelock_token_v1.DataVersion1)
+	if err != nil {
+		return nil, err
+	}
+
+	if source.Vault.PublicKey().ToBase58() != record.SourceTokenAccount {
+		return nil, errors.New("source isn't the owner's primary account")
+	}
+
+	destination, err := common.NewAccountFromPublicKeyString(record.DestinationTokenAccount)
+	if err != nil {
+		return nil, err
+	}
+
+	var selectedNonces []*transaction.SelectedNonce
+	defer func() {
+		for _, selectedNonce := range selectedNonces {
+			selectedNonce.ReleaseIfNotReserved()
+			selectedNonce.Unlock()
+		}
+	}()
+
+	txns := make([]solana.Transaction, record.MaxPayments)
+	paymentNonces := make([]*transaction.SelectedNonce, record.MaxPayments)
+	actionRecords := make([]*action.Record, record.MaxPayments)
+	fulfillmentRecords := make([]*fulfillment.Record, record.MaxPayments)
+	for i := uint32(0); i < record.MaxPayments; i++ {
+		var selectedNonce *transaction.SelectedNonce
+		var txn solana.Transaction
+		for {
+			selectedNonce, err = transaction.SelectAvailableNonce(ctx, data, nonce.PurposeMandatePayment)
+			if err != nil {
+				return nil, errors.Wrap(err, "error selecting available nonce")
+			}
+			selectedNonces = append(selectedNonces, selectedNonce)
+
+			txn, err = transaction.MakeTransferWithAuthorityTransaction(
+				selectedNonce.Account,
+				selectedNonce.Blockhash,
+				source,
+				destination,
+				record.Quantity,
+			)
+			if err != nil {
+				return nil, errors.Wrap(err, "error making payment transaction")
+			}
+
+			err = txn.Sign(common.GetSubsidizer().PrivateKey().ToBytes())
+			if err != nil {
+				return nil, errors.Wrap(err, "error signing payment transaction")
+			}
+
+			// Revoked payments make their nonce available again without
+			// advancing it, so a payment with the same terms can end up with an
+			// identical transaction. Signatures must be unique, so skip to the
+			// next nonce.
+			_, err = data.GetFulfillmentBySignature(ctx, base58.Encode(txn.Signature()))
+			if err == fulfillment.ErrFulfillmentNotFound {
+				break
+			} else if err != nil {
+				return nil, err
+			}
+		}
+		paymentNonces[i] = selectedNonce
+
+		intentId := GetPaymentIntentId(record.MandateId, i)
+
+		// The action is conditional, and has no quantity until the payment is
+		// made, so it doesn't affect the owner's balance.
+		actionRecords[i] = &action.Record{
+			Intent:     intentId,
+			IntentType: intent.SendPublicPayment,
+
+			ActionId:   0,
+			ActionType: action.NoPrivacyTransfer,
+
+			Source:      source.Vault.PublicKey().ToBase58(),
+			Destination: pointer.String(destination.PublicKey().ToBase58()),
+
+			State: action.StateUnknown,
+
+			CreatedAt: time.Now(),
+		}
+
+		fulfillmentRecords[i] = &fulfillment.Record{
+			Intent:     intentId,
+			IntentType: intent.SendPublicPayment,
+
+			ActionId:   0,
+			ActionType: action.NoPrivacyTransfer,
+
+			FulfillmentType: fulfillment.NoPrivacyTransferWithAuthority,
+			Data:            txn.Marshal(),
+			Signature:       pointer.String(base58.Encode(txn.Signature())),
+
+			Nonce:     pointer.String(selectedNonce.Account.PublicKey().ToBase58()),
+			Blockhash: pointer.String(base58.Encode(selectedNonce.Blockhash[:])),
+
+			Source:      source.Vault.PublicKey().ToBase58(),
+			Destination: pointer.String(destination.PublicKey().ToBase58()),
+
+			// Payments are only scheduled when they're due
+			DisableActiveScheduling: true,
+
+			// Similar to gift card auto-returns, payments are ordered last since
+			// they can happen at any point in the future.
+			IntentOrderingIndex:      uint64(math.MaxInt64),
+			ActionOrderingIndex:      0,
+			FulfillmentOrderingIndex: 0,
+
+			State: fulfillment.StateUnknown,
+
+			CreatedAt: time.Now(),
+		}
+
+		txns[i] = txn
+	}
+
+	err = data.ExecuteInTx(ctx, sql.LevelDefault, func(ctx context.Context) error {
+		err := data.CreateMandate(ctx, record)
+		if err != nil {
+			return err
+		}
+
+		err = data.PutAllActions(ctx, actionRecords...)
+		if err != nil {
+			return err
+		}
+
+		err = data.PutAllFulfillments(ctx, fulfillmentRecords...)
+		if err != nil {
+			return err
+		}
+
+		for i, selectedNonce := range paymentNonces {
+			err = selectedNonce.MarkReservedWithSignature(ctx, *fulfillmentRecords[i].Signature)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return txns, nil
+}
+
+// GetPaymentTransactions gets the transactions for each of a mandate's payments,
+// in payment order
+func GetPaymentTransactions(ctx context.Context, data code_data.Provider, record *mandate.Record) ([]solana.Transaction, error) {
+	fulfillmentRecords, err := getPaymentFulfillments(ctx, data, record, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	txns := make([]solana.Transaction, len(fulfillmentRecords))
+	for i, fulfillmentRecord := range fulfillmentRecords {
+		if len(fulfillmentRecord.Data) == 0 {
+			return nil, errors.Errorf("payment %d has no transaction", i)
+		}
+
+		err = txns[i].Unmarshal(fulfillmentRecord.Data)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return txns, nil
+}
+
+// AuthorizePayments applies the owner's signatures to every payment and
+// activates the mandate. Signatures are provided in payment order.
+func AuthorizePayments(ctx context.Context, data code_data.Provider, record *mandate.Record, signatures [][]byte) error {
+	if record.State != mandate.StatePendingAuthorization {
+		return ErrUnexpectedMandateState
+	}
+
+	if len(signatures) != int(record.MaxPayments) {
+		return errors.Errorf("expected %d signatures", record.MaxPayments)
+	}
+
+	owner, err := common.NewAccountFromPublicKeyString(record.OwnerAccount)
+	if err != nil {
+		return err
+	}
+
+	fulfillmentRecords, err := getPaymentFulfillments(ctx, data, record, 0)
+	if err != nil {
+		return err
+	}
+
+	for i, fulfillmentRecord := range fulfillmentRecords {
+		var txn solana.Transaction
+		err = txn.Unmarshal(fulfillmentRecord.Data)
+		if err != nil {
+			return err
+		}
+
+		if len(txn.Signatures) <= ownerSignatureIndex || !bytes.Equal(txn.Message.Accounts[ownerSignatureIndex], owner.PublicKey().ToBytes()) {
+			return errors.Errorf("payment %d isn't signed by the owner", i)
+		}
+
+		if len(signatures[i]) != ed25519.SignatureSize || !ed25519.Verify(owner.PublicKey().ToBytes(), txn.Message.Marshal(), signatures[i]) {
+			return ErrInvalidSignature
+		}
+
+		copy(txn.Signatures[ownerSignatureIndex][:], signatures[i])
+		fulfillmentRecord.Data = txn.Marshal()
+	}
+
+	return data.ExecuteInTx(ctx, sql.LevelDefault, func(ctx context.Context) error {
+		record.State = mandate.StateActive
+		err := data.UpdateMandate(ctx, record)
+		if err != nil {
+			return err
+		}
+
+		for _, fulfillmentRecord := range fulfillmentRecords {
+			err := data.UpdateFulfillment(ctx, fulfillmentRecord)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// CancelMandate cancels a mandate that's pending authorization or active, and
+// revokes all of its remaining payments
+func CancelMandate(ctx context.Context, data code_data.Provider, record *mandate.Record) error {
+	switch record.State {
+	case mandate.StatePendingAuthorization, mandate.StateActive:
+	default:
+		return ErrUnexpectedMandateState
+	}
+
+	return data.ExecuteInTx(ctx, sql.LevelDefault, func(ctx context.Context) error {
+		record.State = mandate.StateCancelled
+		err := data.UpdateMandate(ctx, record)
+		if err != nil {
+			return err
+		}
+
+		return RevokePayments(ctx, data, record, record.PaymentsProcessed)
+	})
+}
+
+// RevokePayments revokes the payments of a mandate starting at the provided
+// index. The revoked payments' nonces are made available again, since their
+// transactions were never submitted.
+//
+// Note: This should be called within a transaction alongside the mandate
+// update that requires it.
+func RevokePayments(ctx context.Context, data code_data.Provider, record *mandate.Record, fromIndex uint32) error {
+	for i := fromIndex; i < record.MaxPayments; i++ {
+		err := RevokePayment(ctx, data, record, i)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RevokePayment revokes a single dormant payment of a mandate
+func RevokePayment(ctx context.Context, data code_data.Provider, record *mandate.Record, index uint32) error {
+	intentId := GetPaymentIntentId(record.MandateId, index)
+
+	actionRecord, err := data.GetActionById(ctx, intentId, 0)
+	if err != nil {
+		return err
+	}
+
+	fulfillmentRecords, err := data.GetAllFulfillmentsByAction(ctx, intentId, 0)
+	if err != nil {
+		return err
+	} else if len(fulfillmentRecords) != 1 {
+		return errors.Errorf("expected exactly one fulfillment for payment %d", index)
+	}
+	fulfillmentRecord := fulfillmentRecords[0]
+
+	// Already revoked
+	if actionRecord.State == action.StateRevoked && fulfillmentRecord.State == fulfillment.StateRevoked {
+		return nil
+	}
+
+	// Only dormant payments can be safely revoked. Anything else may have been
+	// submitted to the blockchain.
+	if actionRecord.State != action.StateUnknown || fulfillmentRecord.State != fulfillment.StateUnknown {
+		return errors.Errorf("payment %d is in a dangerous state to revoke", index)
+	}
+
+	nonceRecord, err := data.GetNonce(ctx, *fulfillmentRecord.Nonce)
+	if err != nil {
+		return err
+	}
+
+	if nonceRecord.State != nonce.StateReserved || nonceRecord.Signature != *fulfillmentRecord.Signature || nonceRecord.Blockhash != *fulfillmentRecord.Blockhash {
+		return errors.Errorf("nonce for payment %d isn't reserved for it", index)
+	}
+
+	actionRecord.State = action.StateRevoked
+	err = data.UpdateAction(ctx, actionRecord)
+	if err != nil {
+		return err
+	}
+
+	fulfillmentRecord.State = fulfillment.StateRevoked
+	fulfillmentRecord.Data = nil
+	err = data.UpdateFulfillment(ctx, fulfillmentRecord)
+	if err != nil {
+		return err
+	}
+
+	nonceRecord.State = nonce.StateAvailable
+	nonceRecord.Signature = ""
+	return data.SaveNonce(ctx, nonceRecord)
+}
+
+func getPaymentFulfillments(ctx context.Context, data code_data.Provider, record *mandate.Record, fromIndex uint32) ([]*fulfillment.Record, error) {
+	var res []*fulfillment.Record
+	for i := fromIndex; i < record.MaxPayments; i++ {
+		fulfillmentRecords, err := data.GetAllFulfillmentsByAction(ctx, GetPaymentIntentId(record.MandateId, i), 0)
+		if err != nil {
+			return nil, err
+		} else if len(fulfillmentRecords) != 1 {
+			return nil, errors.Errorf("expected exactly one fulfillment for payment %d", i)
+		}
+		res = append(res, fulfillmentRecords[0])
+	}
+	return res, nil
+}
@@ -13,7 +13,8 @@ import (
 type dataPushType string
 
 const (
-	dataPushTypeKey = "code_notification_type"
+	dataPushTypeKey  = "code_notification_type"
+	dataPushOwnerKey = "code_owner"
 
 	chatMessageDataPush dataPushType = "ChatMessage"
 )
@@ -37,6 +38,9 @@ func sendDataPushNotificationToOwner(
 
 	kvs[dataPushTypeKey] = string(notificationType)
 
+	// Identifies the account on devices with multiple logged in owners
+	kvs[dataPushOwnerKey] = owner.PublicKey().ToBase58()
+
 	pushTokenRecords, err := getPushTokensForOwner(ctx, data, owner)
 	if err != nil {
 		log.WithError(err).Warn("failure getting push tokens for owner")
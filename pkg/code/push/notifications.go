@@ -162,18 +162,19 @@ func SendMicroPaymentReceivedPushNotification(
 		return nil
 	}
 
+	// Clients don't have translations for these keys, so the text is always
+	// rendered on the server
+	titleKey := localization.PushTitleMicroPaymentReceived
+	bodyKey := localization.PushSubtitleMicroPaymentReceived
 	amountArg := getAmountArg(nativeAmount, currency)
-
-	// todo: localized keys
-	title := "Payment Received"
-	body := fmt.Sprintf("Someone bought your content for %s", amountArg)
-	return sendBasicPushNotificationToOwner(
+	return sendRenderedPushNotificationToOwner(
 		ctx,
 		data,
 		pusher,
 		destinationOwnerAccount,
-		title,
-		body,
+		titleKey,
+		bodyKey,
+		amountArg,
 	)
 }
 
@@ -3,6 +3,7 @@ package push
 import (
 	"context"
 
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
 	"github.com/code-payments/code-server/pkg/code/common"
@@ -14,7 +15,8 @@ import (
 
 // sendLocalizedPushNotificationToOwner is a generic utility for sending a localized
 // push notification to the devices linked to an owner account. Keys can be provided
-// in either the iOS or Android format, and will be translated accordingly.
+// in either the iOS or Android format, and will be translated accordingly. Devices
+// that registered with a locale receive the final text rendered on the server.
 //
 // todo: Duplicated code with other send push utitilies
 func sendLocalizedPushNotificationToOwner(
@@ -47,24 +49,33 @@ func sendLocalizedPushNotificationToOwner(
 
 		// Try push
 		var err error
-		switch pushTokenRecord.TokenType {
-		case push_data.TokenTypeFcmApns:
-			err = pusher.SendLocalizedAPNSPush(
+		if title, body, ok := renderPushText(pushTokenRecord, false, titleKey, bodyKey, bodyArgs...); ok {
+			err = pusher.SendPush(
 				ctx,
 				pushTokenRecord.PushToken,
-				localization.GetIosLocalizationKey(titleKey),
-				localization.GetIosLocalizationKey(bodyKey),
-				bodyArgs...,
+				title,
+				body,
 			)
-		case push_data.TokenTypeFcmAndroid:
-			err = pusher.SendLocalizedAndroidPush(
-				ctx,
-				pushTokenRecord.PushToken,
-				localization.GetAndroidLocalizationKey(titleKey),
-				localization.GetAndroidLocalizationKey(bodyKey),
-				bodyArgs...,
-			)
-		default:
+		} else {
+			switch pushTokenRecord.TokenType {
+			case push_data.TokenTypeFcmApns:
+				err = pusher.SendLocalizedAPNSPush(
+					ctx,
+					pushTokenRecord.PushToken,
+					localization.GetIosLocalizationKey(titleKey),
+					localization.GetIosLocalizationKey(bodyKey),
+					bodyArgs...,
+				)
+			case push_data.TokenTypeFcmAndroid:
+				err = pusher.SendLocalizedAndroidPush(
+					ctx,
+					pushTokenRecord.PushToken,
+					localization.GetAndroidLocalizationKey(titleKey),
+					localization.GetAndroidLocalizationKey(bodyKey),
+					bodyArgs...,
+				)
+			default:
+			}
 		}
 
 		if err != nil {
@@ -77,6 +88,63 @@ func sendLocalizedPushNotificationToOwner(
 	return nil
 }
 
+// sendRenderedPushNotificationToOwner is a generic utility for sending a push
+// notification with text rendered on the server to the devices linked to an owner
+// account. This should be used for keys that clients don't have translations for.
+// Devices that didn't register with a locale receive text in the default locale.
+//
+// todo: Duplicated code with other send push utitilies
+func sendRenderedPushNotificationToOwner(
+	ctx context.Context,
+	data code_data.Provider,
+	pusher push_lib.Provider,
+	owner *common.Account,
+	titleKey, bodyKey string,
+	bodyArgs ...string,
+) error {
+	log := logrus.StandardLogger().WithFields(logrus.Fields{
+		"method": "sendRenderedPushNotificationToOwner",
+		"owner":  owner.PublicKey().ToBase58(),
+	})
+
+	pushTokenRecords, err := getPushTokensForOwner(ctx, data, owner)
+	if err != nil {
+		log.WithError(err).Warn("failure getting push tokens for owner")
+		return err
+	}
+
+	seenPushTokens := make(map[string]struct{})
+	for _, pushTokenRecord := range pushTokenRecords {
+		// Dedup push tokens, since they may appear more than once per app install
+		if _, ok := seenPushTokens[pushTokenRecord.PushToken]; ok {
+			continue
+		}
+
+		log := log.WithField("push_token", pushTokenRecord.PushToken)
+
+		title, body, ok := renderPushText(pushTokenRecord, true, titleKey, bodyKey, bodyArgs...)
+		if !ok {
+			return errors.Errorf("translations for %s and %s are required", titleKey, bodyKey)
+		}
+
+		// Try push
+		err := pusher.SendPush(
+			ctx,
+			pushTokenRecord.PushToken,
+			title,
+			body,
+		)
+
+		if err != nil {
+			log.WithError(err).Warn("failure sending push notification")
+			onPushError(ctx, data, pusher, pushTokenRecord)
+		}
+
+		seenPushTokens[pushTokenRecord.PushToken] = struct{}{}
+	}
+	return nil
+}
+
 // sendBasicPushNotificationToOwner is a generic utility for sending push notification
 // to the devices linked to an owner account. This should be used early in features that
 // don't have localization, since titles & body are the direct English text.
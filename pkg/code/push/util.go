@@ -6,10 +6,13 @@ import (
 	"strings"
 
 	"github.com/pkg/errors"
+	"golang.org/x/text/language"
 
 	"github.com/code-payments/code-server/pkg/code/common"
 	code_data "github.com/code-payments/code-server/pkg/code/data"
+	"github.com/code-payments/code-server/pkg/code/data/login"
 	push_data "github.com/code-payments/code-server/pkg/code/data/push"
+	"github.com/code-payments/code-server/pkg/code/localization"
 	currency_lib "github.com/code-payments/code-server/pkg/currency"
 	push_lib "github.com/code-payments/code-server/pkg/push"
 )
@@ -31,7 +34,29 @@ func getPushTokensForOwner(ctx context.Context, data code_data.Provider, owner *
 	} else if err != nil {
 		return nil, errors.Wrap(err, "error getting push token records")
 	}
-	return pushTokenRecords, nil
+
+	// Multiple owners can be logged into the same app install, and each registers
+	// the device's push token against its own data container. An owner can only
+	// be logged into a single app install, so route to that install and avoid
+	// pushing to devices the owner has since logged out of. Owners that have never
+	// registered a login are routed to all devices.
+	loginRecord, err := data.GetLatestLoginByOwner(ctx, owner.PublicKey().ToBase58())
+	if err == login.ErrLoginNotFound {
+		return pushTokenRecords, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "error getting login record")
+	}
+
+	var routed []*push_data.Record
+	for _, pushTokenRecord := range pushTokenRecords {
+		// Legacy push tokens that don't map to an app install can't be routed
+		if pushTokenRecord.AppInstallId != nil && *pushTokenRecord.AppInstallId != loginRecord.AppInstallId {
+			continue
+		}
+
+		routed = append(routed, pushTokenRecord)
+	}
+	return routed, nil
 }
 
 func onPushError(ctx context.Context, data code_data.Provider, pusher push_lib.Provider, pushTokenRecord *push_data.Record) (bool, error) {
@@ -43,6 +68,39 @@ func onPushError(ctx context.Context, data code_data.Provider, pusher push_lib.P
 	return isValid, err
 }
 
+// renderPushText renders the title and body text for a push token using the
+// locale it was registered with. When useDefaultLocale is set, tokens without
+// a locale are rendered in the default locale.
+func renderPushText(pushTokenRecord *push_data.Record, useDefaultLocale bool, titleKey, bodyKey string, bodyArgs ...string) (string, string, bool) {
+	locale := localization.DefaultLocale
+	if pushTokenRecord.Locale != nil {
+		parsed, err := language.Parse(*pushTokenRecord.Locale)
+		if err != nil {
+			return "", "", false
+		}
+		locale = parsed
+	} else if !useDefaultLocale {
+		return "", "", false
+	}
+
+	args := make([]interface{}, len(bodyArgs))
+	for i, arg := range bodyArgs {
+		args[i] = arg
+	}
+
+	title, ok := localization.Localize(locale, titleKey)
+	if !ok {
+		return "", "", false
+	}
+
+	body, ok := localization.Localize(locale, bodyKey, args...)
+	if !ok {
+		return "", "", false
+	}
+
+	return title, body, true
+}
+
 func getAmountArg(nativeAmount float64, currency currency_lib.Code) string {
 	amountArg := fmt.Sprintf(
 		"%d Kin",
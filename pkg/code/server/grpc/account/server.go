@@ -276,13 +276,13 @@ func (s *server) getProtoAccountInfo(ctx context.Context, records *common.Accoun
 
 	// Should never happen and is a precautionary check. We can't manage timelock
 	// accounts where we aren't the time authority.
-	if records.Timelock.TimeAuthority != common.GetSubsidizer().PublicKey().ToBase58() {
+	if !common.IsSubsidizer(records.Timelock.TimeAuthority) {
 		managementState = accountpb.TokenAccountInfo_MANAGEMENT_STATE_NONE
 	}
 
 	// Should never happen and is a precautionary check. We can't manage timelock
 	// accounts where we aren't the close authority.
-	if records.Timelock.CloseAuthority != common.GetSubsidizer().PublicKey().ToBase58() {
+	if !common.IsSubsidizer(records.Timelock.CloseAuthority) {
 		managementState = accountpb.TokenAccountInfo_MANAGEMENT_STATE_NONE
 	}
 
@@ -25,6 +25,7 @@ import (
 	"github.com/code-payments/code-server/pkg/code/balance"
 	"github.com/code-payments/code-server/pkg/code/common"
 	code_data "github.com/code-payments/code-server/pkg/code/data"
+	datatest "github.com/code-payments/code-server/pkg/code/data/test"
 	"github.com/code-payments/code-server/pkg/code/data/account"
 	"github.com/code-payments/code-server/pkg/code/data/action"
 	"github.com/code-payments/code-server/pkg/code/data/deposit"
@@ -49,7 +50,7 @@ func setup(t *testing.T) (env testEnv, cleanup func()) {
 
 	env.ctx = context.Background()
 	env.client = accountpb.NewAccountClient(conn)
-	env.data = code_data.NewTestDataProvider()
+	env.data = datatest.NewDataProvider()
 	env.subsidizer = testutil.SetupRandomSubsidizer(t, env.data)
 
 	s := NewAccountServer(env.data)
@@ -0,0 +1,125 @@
+package admin
+
+import (
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/code-payments/code-server/pkg/solana"
+	"github.com/code-payments/code-server/pkg/code/data/account"
+	"github.com/code-payments/code-server/pkg/code/data/action"
+	"github.com/code-payments/code-server/pkg/code/data/commitment"
+	"github.com/code-payments/code-server/pkg/code/data/fulfillment"
+	"github.com/code-payments/code-server/pkg/code/data/intent"
+	"github.com/code-payments/code-server/pkg/code/data/nonce"
+	"github.com/code-payments/code-server/pkg/code/data/transaction"
+	adminpb "github.com/code-payments/code-server/pkg/code/server/grpc/admin/api/gen"
+)
+
+func toProtoIntent(record *intent.Record) *adminpb.Intent {
+	return &adminpb.Intent{
+		Id:             record.IntentId,
+		Type:           record.IntentType.String(),
+		State:          record.State.String(),
+		InitiatorOwner: record.InitiatorOwnerAccount,
+		CreatedAt:      timestamppb.New(record.CreatedAt),
+	}
+}
+
+func toProtoAction(record *action.Record, commitmentRecord *commitment.Record) *adminpb.Action {
+	res := &adminpb.Action{
+		Id:     record.ActionId,
+		Type:   record.ActionType.String(),
+		State:  record.State.String(),
+		Source: record.Source,
+	}
+
+	if record.Destination != nil {
+		res.Destination = *record.Destination
+	}
+
+	if record.Quantity != nil {
+		res.Quantity = &adminpb.OptionalQuantity{
+			Quarks: *record.Quantity,
+		}
+	}
+
+	if commitmentRecord != nil {
+		res.Commitment = &adminpb.Commitment{
+			Address:        commitmentRecord.Address,
+			Vault:          commitmentRecord.Vault,
+			State:          commitmentRecord.State.String(),
+			TreasuryRepaid: commitmentRecord.TreasuryRepaid,
+		}
+		if commitmentRecord.RepaymentDivertedTo != nil {
+			res.Commitment.RepaymentDivertedTo = *commitmentRecord.RepaymentDivertedTo
+		}
+	}
+
+	return res
+}
+
+func toProtoFulfillment(record *fulfillment.Record) *adminpb.Fulfillment {
+	res := &adminpb.Fulfillment{
+		Id:                       record.Id,
+		Type:                     record.FulfillmentType.String(),
+		State:                    record.State.String(),
+		DisableActiveScheduling:  record.DisableActiveScheduling,
+		IntentOrderingIndex:      record.IntentOrderingIndex,
+		ActionOrderingIndex:      record.ActionOrderingIndex,
+		FulfillmentOrderingIndex: record.FulfillmentOrderingIndex,
+		CreatedAt:                timestamppb.New(record.CreatedAt),
+	}
+
+	if record.Signature != nil {
+		res.Signature = *record.Signature
+	}
+
+	return res
+}
+
+func toProtoNonce(record *nonce.Record) *adminpb.Nonce {
+	return &adminpb.Nonce{
+		Address:   record.Address,
+		Purpose:   record.Purpose.String(),
+		State:     record.State.String(),
+		Blockhash: record.Blockhash,
+		Signature: record.Signature,
+	}
+}
+
+func toProtoTransaction(record *transaction.Record) *adminpb.Transaction {
+	return &adminpb.Transaction{
+		Slot:              record.Slot,
+		ConfirmationState: record.ConfirmationState.String(),
+		HasErrors:         record.HasErrors,
+	}
+}
+
+func toProtoOnChainStatus(signatureStatus *solana.SignatureStatus) *adminpb.OnChainStatus {
+	if signatureStatus == nil {
+		return &adminpb.OnChainStatus{
+			Found: false,
+		}
+	}
+
+	res := &adminpb.OnChainStatus{
+		Found:              true,
+		Slot:               signatureStatus.Slot,
+		ConfirmationStatus: signatureStatus.ConfirmationStatus,
+	}
+	if signatureStatus.ErrorResult != nil {
+		res.Error = signatureStatus.ErrorResult.Error()
+	}
+	return res
+}
+
+func toProtoAccount(record *account.Record) *adminpb.GetAccountResponse {
+	return &adminpb.GetAccountResponse{
+		Result:       adminpb.GetAccountResponse_OK,
+		Owner:        record.OwnerAccount,
+		Authority:    record.AuthorityAccount,
+		TokenAccount: record.TokenAccount,
+		AccountType:  record.AccountType.String(),
+		Index:        record.Index,
+		CreatedAt:    timestamppb.New(record.CreatedAt),
+	}
+}
@@ -0,0 +1,775 @@
+package admin
+
+import (
+	"context"
+	"crypto/subtle"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mr-tron/base58"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/code-payments/code-server/pkg/solana"
+	async_sequencer "github.com/code-payments/code-server/pkg/code/async/sequencer"
+	"github.com/code-payments/code-server/pkg/code/balance"
+	"github.com/code-payments/code-server/pkg/code/common"
+	code_data "github.com/code-payments/code-server/pkg/code/data"
+	"github.com/code-payments/code-server/pkg/code/data/account"
+	"github.com/code-payments/code-server/pkg/code/data/adminaudit"
+	"github.com/code-payments/code-server/pkg/code/data/action"
+	"github.com/code-payments/code-server/pkg/code/data/commitment"
+	"github.com/code-payments/code-server/pkg/code/data/deadletter"
+	"github.com/code-payments/code-server/pkg/code/data/fulfillment"
+	"github.com/code-payments/code-server/pkg/code/data/intent"
+	"github.com/code-payments/code-server/pkg/code/data/nonce"
+	"github.com/code-payments/code-server/pkg/code/data/timelock"
+	"github.com/code-payments/code-server/pkg/code/data/transaction"
+	adminpb "github.com/code-payments/code-server/pkg/code/server/grpc/admin/api/gen"
+)
+
+const (
+	authorizationHeaderName = "authorization"
+	bearerPrefix            = "Bearer "
+)
+
+type server struct {
+	log      *logrus.Entry
+	auditLog *logrus.Entry
+	data     code_data.Provider
+
+	// Operator names keyed by their token
+	operators map[string]string
+
+	adminpb.UnimplementedAdminServer
+}
+
+// NewAdminServer returns a new internal admin server. Operator tokens are keyed
+// by operator name, which is recorded in the audit log for every attempted change.
+func NewAdminServer(data code_data.Provider, operatorTokens map[string]string) adminpb.AdminServer {
+	operators := make(map[string]string)
+	for operator, token := range operatorTokens {
+		if len(token) > 0 {
+			operators[token] = operator
+		}
+	}
+
+	return &server{
+		log:       logrus.StandardLogger().WithField("type", "admin/server"),
+		auditLog:  logrus.StandardLogger().WithField("type", "admin/audit"),
+		data:      data,
+		operators: operators,
+	}
+}
+
+func (s *server) GetIntent(ctx context.Context, req *adminpb.GetIntentRequest) (*adminpb.GetIntentResponse, error) {
+	log := s.log.WithFields(logrus.Fields{
+		"method": "GetIntent",
+		"intent": req.IntentId,
+	})
+
+	_, err := s.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	intentRecord, err := s.data.GetIntent(ctx, req.IntentId)
+	if err == intent.ErrIntentNotFound {
+		return &adminpb.GetIntentResponse{
+			Result: adminpb.GetIntentResponse_NOT_FOUND,
+		}, nil
+	} else if err != nil {
+		log.WithError(err).Warn("failure getting intent record")
+		return nil, status.Error(codes.Internal, "")
+	}
+
+	actionRecords, fulfillmentRecords, err := s.getActionsAndFulfillments(ctx, req.IntentId)
+	if err != nil {
+		log.WithError(err).Warn("failure getting action and fulfillment records")
+		return nil, status.Error(codes.Internal, "")
+	}
+
+	// Blockchain statuses are best effort, since the point of this call is often
+	// to debug issues that may involve the blockchain
+	onChainStatuses, err := s.getOnChainStatuses(ctx, fulfillmentRecords)
+	if err != nil {
+		log.WithError(err).Warn("failure getting on chain statuses")
+	}
+
+	fulfillmentsByAction := make(map[uint32][]*adminpb.Fulfillment)
+	for _, fulfillmentRecord := range fulfillmentRecords {
+		protoFulfillment, err := s.getProtoFulfillment(ctx, fulfillmentRecord, onChainStatuses)
+		if err != nil {
+			log.WithError(err).Warn("failure getting fulfillment state")
+			return nil, status.Error(codes.Internal, "")
+		}
+		fulfillmentsByAction[fulfillmentRecord.ActionId] = append(fulfillmentsByAction[fulfillmentRecord.ActionId], protoFulfillment)
+	}
+
+	resp := &adminpb.GetIntentResponse{
+		Result: adminpb.GetIntentResponse_OK,
+		Intent: toProtoIntent(intentRecord),
+	}
+	for _, actionRecord := range actionRecords {
+		commitmentRecord, err := s.data.GetCommitmentByAction(ctx, actionRecord.Intent, actionRecord.ActionId)
+		if err != nil && err != commitment.ErrCommitmentNotFound {
+			log.WithError(err).Warn("failure getting commitment record")
+			return nil, status.Error(codes.Internal, "")
+		}
+
+		protoAction := toProtoAction(actionRecord, commitmentRecord)
+		protoAction.Fulfillments = fulfillmentsByAction[actionRecord.ActionId]
+		resp.Actions = append(resp.Actions, protoAction)
+	}
+	return resp, nil
+}
+
+func (s *server) GetAccount(ctx context.Context, req *adminpb.GetAccountRequest) (*adminpb.GetAccountResponse, error) {
+	log := s.log.WithFields(logrus.Fields{
+		"method":  "GetAccount",
+		"account": req.Address,
+	})
+
+	_, err := s.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tokenAccount, err := common.NewAccountFromPublicKeyString(req.Address)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "address is not a public key")
+	}
+
+	accountInfoRecord, err := s.data.GetAccountInfoByTokenAddress(ctx, tokenAccount.PublicKey().ToBase58())
+	if err == account.ErrAccountInfoNotFound {
+		return &adminpb.GetAccountResponse{
+			Result: adminpb.GetAccountResponse_NOT_FOUND,
+		}, nil
+	} else if err != nil {
+		log.WithError(err).Warn("failure getting account info record")
+		return nil, status.Error(codes.Internal, "")
+	}
+
+	resp := toProtoAccount(accountInfoRecord)
+
+	timelockRecord, err := s.data.GetTimelockByVault(ctx, tokenAccount.PublicKey().ToBase58())
+	switch err {
+	case nil:
+		resp.TimelockState = timelockRecord.VaultState.String()
+	case timelock.ErrTimelockNotFound:
+	default:
+		log.WithError(err).Warn("failure getting timelock record")
+		return nil, status.Error(codes.Internal, "")
+	}
+
+	resp.CachedBalance, err = balance.DefaultCalculation(ctx, s.data, tokenAccount)
+	if err != nil {
+		log.WithError(err).Warn("failure calculating cached balance")
+		return nil, status.Error(codes.Internal, "")
+	}
+
+	tokenAccountInfo, err := s.data.GetBlockchainTokenAccountInfo(ctx, tokenAccount.PublicKey().ToBase58(), solana.CommitmentFinalized)
+	switch err {
+	case nil:
+		resp.OnChainBalance = &adminpb.OnChainBalance{
+			Quarks: tokenAccountInfo.Amount,
+		}
+	case solana.ErrNoAccountInfo:
+	default:
+		log.WithError(err).Warn("failure getting on chain balance")
+	}
+
+	return resp, nil
+}
+
+func (s *server) RetryFulfillment(ctx context.Context, req *adminpb.RetryFulfillmentRequest) (*adminpb.RetryFulfillmentResponse, error) {
+	log := s.log.WithFields(logrus.Fields{
+		"method":      "RetryFulfillment",
+		"fulfillment": req.FulfillmentId,
+	})
+
+	operator, err := s.authenticateOperatorAction(ctx, req.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	auditRecord := newAuditRecord(operator, "retry_fulfillment", req.Reason)
+	auditRecord.FulfillmentId = req.FulfillmentId
+
+	fulfillmentRecord, err := s.data.GetFulfillmentById(ctx, req.FulfillmentId)
+	if err == fulfillment.ErrFulfillmentNotFound {
+		err = s.saveRejectedAuditRecord(ctx, auditRecord, adminaudit.ResultNotFound, "fulfillment not found")
+		if err != nil {
+			log.WithError(err).Warn("failure saving audit record")
+			return nil, status.Error(codes.Internal, "")
+		}
+		return &adminpb.RetryFulfillmentResponse{
+			Result: adminpb.RetryFulfillmentResponse_NOT_FOUND,
+		}, nil
+	} else if err != nil {
+		log.WithError(err).Warn("failure getting fulfillment record")
+		return nil, status.Error(codes.Internal, "")
+	}
+
+	auditRecord.Intent = fulfillmentRecord.Intent
+
+	if fulfillmentRecord.State != fulfillment.StateFailed {
+		detail := "fulfillment is " + fulfillmentRecord.State.String()
+		err = s.saveRejectedAuditRecord(ctx, auditRecord, adminaudit.ResultInvalidState, detail)
+		if err != nil {
+			log.WithError(err).Warn("failure saving audit record")
+			return nil, status.Error(codes.Internal, "")
+		}
+		return &adminpb.RetryFulfillmentResponse{
+			Result: adminpb.RetryFulfillmentResponse_INVALID_STATE,
+			Detail: detail,
+		}, nil
+	}
+
+	if !async_sequencer.SupportsFulfillmentRetry(s.data, fulfillmentRecord.FulfillmentType) {
+		detail := fulfillmentRecord.FulfillmentType.String() + " transactions aren't created on demand"
+		err = s.saveRejectedAuditRecord(ctx, auditRecord, adminaudit.ResultUnsupported, detail)
+		if err != nil {
+			log.WithError(err).Warn("failure saving audit record")
+			return nil, status.Error(codes.Internal, "")
+		}
+		return &adminpb.RetryFulfillmentResponse{
+			Result: adminpb.RetryFulfillmentResponse_UNSUPPORTED,
+			Detail: detail,
+		}, nil
+	}
+
+	// An operator retry is tracked like any automatic one, so the failure keeps
+	// counting towards the sequencer's circuit breaker
+	deadLetterRecord, err := s.data.GetFulfillmentDeadLetter(ctx, fulfillmentRecord.Id)
+	switch err {
+	case nil:
+		deadLetterRecord.Retries++
+		deadLetterRecord.State = deadletter.StateRetried
+	case deadletter.ErrDeadLetterNotFound:
+		deadLetterRecord = nil
+	default:
+		log.WithError(err).Warn("failure getting dead letter record")
+		return nil, status.Error(codes.Internal, "")
+	}
+
+	detail := "fulfillment scheduled for retry"
+	if fulfillmentRecord.Signature != nil {
+		detail += ", previous signature " + *fulfillmentRecord.Signature
+	}
+
+	err = s.data.ExecuteInTx(ctx, sql.LevelDefault, func(ctx context.Context) error {
+		err := async_sequencer.RetryFailedFulfillment(ctx, s.data, fulfillmentRecord, deadLetterRecord)
+		if err != nil {
+			return err
+		}
+
+		return s.putAuditRecord(ctx, auditRecord, adminaudit.ResultOk, detail)
+	})
+	if err != nil {
+		log.WithError(err).Warn("failure retrying fulfillment")
+		return nil, status.Error(codes.Internal, "")
+	}
+	s.logAuditRecord(auditRecord)
+
+	return &adminpb.RetryFulfillmentResponse{
+		Result: adminpb.RetryFulfillmentResponse_OK,
+	}, nil
+}
+
+func (s *server) SetActiveScheduling(ctx context.Context, req *adminpb.SetActiveSchedulingRequest) (*adminpb.SetActiveSchedulingResponse, error) {
+	log := s.log.WithFields(logrus.Fields{
+		"method":      "SetActiveScheduling",
+		"fulfillment": req.FulfillmentId,
+		"enabled":     req.Enabled,
+	})
+
+	operator, err := s.authenticateOperatorAction(ctx, req.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	auditRecord := newAuditRecord(operator, "set_active_scheduling", req.Reason)
+	auditRecord.FulfillmentId = req.FulfillmentId
+
+	fulfillmentRecord, err := s.data.GetFulfillmentById(ctx, req.FulfillmentId)
+	if err == fulfillment.ErrFulfillmentNotFound {
+		err = s.saveRejectedAuditRecord(ctx, auditRecord, adminaudit.ResultNotFound, "fulfillment not found")
+		if err != nil {
+			log.WithError(err).Warn("failure saving audit record")
+			return nil, status.Error(codes.Internal, "")
+		}
+		return &adminpb.SetActiveSchedulingResponse{
+			Result: adminpb.SetActiveSchedulingResponse_NOT_FOUND,
+		}, nil
+	} else if err != nil {
+		log.WithError(err).Warn("failure getting fulfillment record")
+		return nil, status.Error(codes.Internal, "")
+	}
+
+	auditRecord.Intent = fulfillmentRecord.Intent
+
+	if fulfillmentRecord.State.IsTerminal() {
+		detail := "fulfillment is " + fulfillmentRecord.State.String()
+		err = s.saveRejectedAuditRecord(ctx, auditRecord, adminaudit.ResultInvalidState, detail)
+		if err != nil {
+			log.WithError(err).Warn("failure saving audit record")
+			return nil, status.Error(codes.Internal, "")
+		}
+		return &adminpb.SetActiveSchedulingResponse{
+			Result: adminpb.SetActiveSchedulingResponse_INVALID_STATE,
+			Detail: detail,
+		}, nil
+	}
+
+	detail := fmt.Sprintf("active scheduling changed from %t to %t", !fulfillmentRecord.DisableActiveScheduling, req.Enabled)
+
+	err = s.data.ExecuteInTx(ctx, sql.LevelDefault, func(ctx context.Context) error {
+		var err error
+		if req.Enabled {
+			err = s.data.MarkFulfillmentAsActivelyScheduled(ctx, fulfillmentRecord.Id)
+		} else {
+			err = s.data.MarkFulfillmentAsNotActivelyScheduled(ctx, fulfillmentRecord.Id)
+		}
+		if err != nil {
+			return err
+		}
+
+		return s.putAuditRecord(ctx, auditRecord, adminaudit.ResultOk, detail)
+	})
+	if err != nil {
+		log.WithError(err).Warn("failure updating active scheduling")
+		return nil, status.Error(codes.Internal, "")
+	}
+	s.logAuditRecord(auditRecord)
+
+	return &adminpb.SetActiveSchedulingResponse{
+		Result: adminpb.SetActiveSchedulingResponse_OK,
+	}, nil
+}
+
+func (s *server) RevokeIntent(ctx context.Context, req *adminpb.RevokeIntentRequest) (*adminpb.RevokeIntentResponse, error) {
+	log := s.log.WithFields(logrus.Fields{
+		"method": "RevokeIntent",
+		"intent": req.IntentId,
+	})
+
+	operator, err := s.authenticateOperatorAction(ctx, req.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	auditRecord := newAuditRecord(operator, "revoke_intent", req.Reason)
+	auditRecord.Intent = req.IntentId
+
+	intentRecord, err := s.data.GetIntent(ctx, req.IntentId)
+	if err == intent.ErrIntentNotFound {
+		err = s.saveRejectedAuditRecord(ctx, auditRecord, adminaudit.ResultNotFound, "intent not found")
+		if err != nil {
+			log.WithError(err).Warn("failure saving audit record")
+			return nil, status.Error(codes.Internal, "")
+		}
+		return &adminpb.RevokeIntentResponse{
+			Result: adminpb.RevokeIntentResponse_NOT_FOUND,
+		}, nil
+	} else if err != nil {
+		log.WithError(err).Warn("failure getting intent record")
+		return nil, status.Error(codes.Internal, "")
+	}
+
+	actionRecords, fulfillmentRecords, err := s.getActionsAndFulfillments(ctx, req.IntentId)
+	if err != nil {
+		log.WithError(err).Warn("failure getting action and fulfillment records")
+		return nil, status.Error(codes.Internal, "")
+	}
+
+	nonceRecords, detail, err := s.checkIntentCanBeRevoked(ctx, intentRecord, actionRecords, fulfillmentRecords)
+	if err != nil {
+		log.WithError(err).Warn("failure checking whether intent can be revoked")
+		return nil, status.Error(codes.Internal, "")
+	} else if len(detail) > 0 {
+		err = s.saveRejectedAuditRecord(ctx, auditRecord, adminaudit.ResultInvalidState, detail)
+		if err != nil {
+			log.WithError(err).Warn("failure saving audit record")
+			return nil, status.Error(codes.Internal, "")
+		}
+		return &adminpb.RevokeIntentResponse{
+			Result: adminpb.RevokeIntentResponse_INVALID_STATE,
+			Detail: detail,
+		}, nil
+	}
+
+	detail = fmt.Sprintf("intent revoked with %d actions, %d fulfillments and %d released nonces", len(actionRecords), len(fulfillmentRecords), len(nonceRecords))
+
+	err = s.data.ExecuteInTx(ctx, sql.LevelDefault, func(ctx context.Context) error {
+		for _, fulfillmentRecord := range fulfillmentRecords {
+			if fulfillmentRecord.State == fulfillment.StateRevoked {
+				continue
+			}
+
+			fulfillmentRecord.State = fulfillment.StateRevoked
+			fulfillmentRecord.Data = nil
+			err := s.data.UpdateFulfillment(ctx, fulfillmentRecord)
+			if err != nil {
+				return err
+			}
+		}
+
+		// The transactions were never submitted, so their nonces can be reused
+		for _, nonceRecord := range nonceRecords {
+			nonceRecord.State = nonce.StateAvailable
+			nonceRecord.Signature = ""
+			err := s.data.SaveNonce(ctx, nonceRecord)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, actionRecord := range actionRecords {
+			if actionRecord.State == action.StateRevoked {
+				continue
+			}
+
+			actionRecord.State = action.StateRevoked
+			err := s.data.UpdateAction(ctx, actionRecord)
+			if err != nil {
+				return err
+			}
+		}
+
+		intentRecord.State = intent.StateRevoked
+		err := s.data.SaveIntent(ctx, intentRecord)
+		if err != nil {
+			return err
+		}
+
+		return s.putAuditRecord(ctx, auditRecord, adminaudit.ResultOk, detail)
+	})
+	if err != nil {
+		log.WithError(err).Warn("failure revoking intent")
+		return nil, status.Error(codes.Internal, "")
+	}
+	s.logAuditRecord(auditRecord)
+
+	return &adminpb.RevokeIntentResponse{
+		Result: adminpb.RevokeIntentResponse_OK,
+	}, nil
+}
+
+func (s *server) ResetFailedFulfillment(ctx context.Context, req *adminpb.ResetFailedFulfillmentRequest) (*adminpb.ResetFailedFulfillmentResponse, error) {
+	log := s.log.WithFields(logrus.Fields{
+		"method":      "ResetFailedFulfillment",
+		"fulfillment": req.FulfillmentId,
+	})
+
+	operator, err := s.authenticateOperatorAction(ctx, req.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	auditRecord := newAuditRecord(operator, "reset_failed_fulfillment", req.Reason)
+	auditRecord.FulfillmentId = req.FulfillmentId
+
+	deadLetterRecord, err := s.data.GetFulfillmentDeadLetter(ctx, req.FulfillmentId)
+	if err == deadletter.ErrDeadLetterNotFound {
+		err = s.saveRejectedAuditRecord(ctx, auditRecord, adminaudit.ResultNotFound, "fulfillment has no remediated failure")
+		if err != nil {
+			log.WithError(err).Warn("failure saving audit record")
+			return nil, status.Error(codes.Internal, "")
+		}
+		return &adminpb.ResetFailedFulfillmentResponse{
+			Result: adminpb.ResetFailedFulfillmentResponse_NOT_FOUND,
+		}, nil
+	} else if err != nil {
+		log.WithError(err).Warn("failure getting dead letter record")
+		return nil, status.Error(codes.Internal, "")
+	}
+
+	auditRecord.Intent = deadLetterRecord.Intent
+
+	// Quarantined failures are resolved by retrying or revoking the fulfillment
+	if deadLetterRecord.State != deadletter.StateRetried {
+		detail := "failure is " + deadLetterRecord.State.String()
+		err = s.saveRejectedAuditRecord(ctx, auditRecord, adminaudit.ResultInvalidState, detail)
+		if err != nil {
+			log.WithError(err).Warn("failure saving audit record")
+			return nil, status.Error(codes.Internal, "")
+		}
+		return &adminpb.ResetFailedFulfillmentResponse{
+			Result: adminpb.ResetFailedFulfillmentResponse_INVALID_STATE,
+			Detail: detail,
+		}, nil
+	}
+
+	detail := fmt.Sprintf("failure reset after %d retries, last signature %s", deadLetterRecord.Retries, deadLetterRecord.Signature)
+
+	err = s.data.ExecuteInTx(ctx, sql.LevelDefault, func(ctx context.Context) error {
+		deadLetterRecord.State = deadletter.StateReset
+		err := s.data.SaveFulfillmentDeadLetter(ctx, deadLetterRecord)
+		if err != nil {
+			return err
+		}
+
+		return s.putAuditRecord(ctx, auditRecord, adminaudit.ResultOk, detail)
+	})
+	if err != nil {
+		log.WithError(err).Warn("failure resetting failed fulfillment")
+		return nil, status.Error(codes.Internal, "")
+	}
+	s.logAuditRecord(auditRecord)
+
+	return &adminpb.ResetFailedFulfillmentResponse{
+		Result: adminpb.ResetFailedFulfillmentResponse_OK,
+	}, nil
+}
+
+// checkIntentCanBeRevoked ensures nothing for the intent could have been
+// submitted to the blockchain. When the intent can't be revoked, a reason is
+// provided. Otherwise, the nonces reserved for the intent's transactions are
+// returned.
+func (s *server) checkIntentCanBeRevoked(ctx context.Context, intentRecord *intent.Record, actionRecords []*action.Record, fulfillmentRecords []*fulfillment.Record) ([]*nonce.Record, string, error) {
+	if intentRecord.State != intent.StatePending {
+		return nil, "intent is " + intentRecord.State.String(), nil
+	}
+
+	for _, actionRecord := range actionRecords {
+		switch actionRecord.State {
+		case action.StateUnknown, action.StatePending, action.StateRevoked:
+		default:
+			return nil, "action " + actionRecord.ActionType.String() + " is " + actionRecord.State.String(), nil
+		}
+
+		// Commitments are managed by their own worker, which can't be aware of
+		// a revoked intent
+		_, err := s.data.GetCommitmentByAction(ctx, actionRecord.Intent, actionRecord.ActionId)
+		if err == nil {
+			return nil, "action " + actionRecord.ActionType.String() + " has a commitment", nil
+		} else if err != commitment.ErrCommitmentNotFound {
+			return nil, "", err
+		}
+	}
+
+	var submittable []*fulfillment.Record
+	for _, fulfillmentRecord := range fulfillmentRecords {
+		switch fulfillmentRecord.State {
+		case fulfillment.StateRevoked:
+			continue
+		case fulfillment.StateUnknown:
+		default:
+			return nil, "fulfillment " + fulfillmentRecord.FulfillmentType.String() + " is " + fulfillmentRecord.State.String(), nil
+		}
+
+		// Otherwise, the sequencer could submit the fulfillment concurrently
+		if !fulfillmentRecord.DisableActiveScheduling {
+			return nil, "fulfillment " + fulfillmentRecord.FulfillmentType.String() + " is actively scheduled", nil
+		}
+
+		if fulfillmentRecord.Signature != nil {
+			submittable = append(submittable, fulfillmentRecord)
+		}
+	}
+
+	// Double check the blockchain has never seen any of the transactions. Unlike
+	// when inspecting an intent, the blockchain must be reachable.
+	onChainStatuses, err := s.getOnChainStatuses(ctx, submittable)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var nonceRecords []*nonce.Record
+	for _, fulfillmentRecord := range submittable {
+		if onChainStatuses[*fulfillmentRecord.Signature] != nil {
+			return nil, "fulfillment " + fulfillmentRecord.FulfillmentType.String() + " transaction was submitted", nil
+		}
+
+		if fulfillmentRecord.Nonce == nil {
+			continue
+		}
+
+		nonceRecord, err := s.data.GetNonce(ctx, *fulfillmentRecord.Nonce)
+		if err != nil {
+			return nil, "", err
+		}
+
+		if nonceRecord.State != nonce.StateReserved || nonceRecord.Signature != *fulfillmentRecord.Signature || nonceRecord.Blockhash != *fulfillmentRecord.Blockhash {
+			return nil, "nonce " + nonceRecord.Address + " isn't reserved for fulfillment " + fulfillmentRecord.FulfillmentType.String(), nil
+		}
+		nonceRecords = append(nonceRecords, nonceRecord)
+	}
+
+	return nonceRecords, "", nil
+}
+
+func (s *server) getActionsAndFulfillments(ctx context.Context, intentId string) ([]*action.Record, []*fulfillment.Record, error) {
+	actionRecords, err := s.data.GetAllActionsByIntent(ctx, intentId)
+	if err != nil && err != action.ErrActionNotFound {
+		return nil, nil, err
+	}
+	sort.Sort(action.ByActionId(actionRecords))
+
+	fulfillmentRecords, err := s.data.GetAllFulfillmentsByIntent(ctx, intentId)
+	if err != nil && err != fulfillment.ErrFulfillmentNotFound {
+		return nil, nil, err
+	}
+	sort.Slice(fulfillmentRecords, func(i, j int) bool {
+		if fulfillmentRecords[i].FulfillmentOrderingIndex != fulfillmentRecords[j].FulfillmentOrderingIndex {
+			return fulfillmentRecords[i].FulfillmentOrderingIndex < fulfillmentRecords[j].FulfillmentOrderingIndex
+		}
+		return fulfillmentRecords[i].Id < fulfillmentRecords[j].Id
+	})
+
+	return actionRecords, fulfillmentRecords, nil
+}
+
+// getOnChainStatuses gets the blockchain status for the transactions of the
+// provided fulfillments, keyed by signature. Transactions the blockchain
+// doesn't know about are omitted.
+func (s *server) getOnChainStatuses(ctx context.Context, fulfillmentRecords []*fulfillment.Record) (map[string]*solana.SignatureStatus, error) {
+	var encoded []string
+	var signatures []solana.Signature
+	for _, fulfillmentRecord := range fulfillmentRecords {
+		if fulfillmentRecord.Signature == nil {
+			continue
+		}
+
+		decoded, err := base58.Decode(*fulfillmentRecord.Signature)
+		if err != nil || len(decoded) != len(solana.Signature{}) {
+			continue
+		}
+
+		var signature solana.Signature
+		copy(signature[:], decoded)
+		signatures = append(signatures, signature)
+		encoded = append(encoded, *fulfillmentRecord.Signature)
+	}
+
+	res := make(map[string]*solana.SignatureStatus)
+	if len(signatures) == 0 {
+		return res, nil
+	}
+
+	statuses, err := s.data.GetBlockchainSignatureStatuses(ctx, signatures)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, status := range statuses {
+		if status != nil && i < len(encoded) {
+			res[encoded[i]] = status
+		}
+	}
+	return res, nil
+}
+
+func (s *server) getProtoFulfillment(ctx context.Context, fulfillmentRecord *fulfillment.Record, onChainStatuses map[string]*solana.SignatureStatus) (*adminpb.Fulfillment, error) {
+	res := toProtoFulfillment(fulfillmentRecord)
+
+	if fulfillmentRecord.Nonce != nil {
+		nonceRecord, err := s.data.GetNonce(ctx, *fulfillmentRecord.Nonce)
+		if err != nil && err != nonce.ErrNonceNotFound {
+			return nil, err
+		} else if err == nil {
+			res.Nonce = toProtoNonce(nonceRecord)
+		}
+	}
+
+	if fulfillmentRecord.Signature != nil {
+		transactionRecord, err := s.data.GetTransaction(ctx, *fulfillmentRecord.Signature)
+		if err != nil && err != transaction.ErrNotFound {
+			return nil, err
+		} else if err == nil {
+			res.Transaction = toProtoTransaction(transactionRecord)
+		}
+
+		// A nil map means the blockchain couldn't be reached, so the status is
+		// unknown rather than not found
+		if onChainStatuses != nil {
+			res.OnChainStatus = toProtoOnChainStatus(onChainStatuses[*fulfillmentRecord.Signature])
+		}
+	}
+
+	return res, nil
+}
+
+// authenticate checks the request has a valid operator token, and returns the
+// operator's name
+func (s *server) authenticate(ctx context.Context) (string, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", status.Error(codes.Unauthenticated, "")
+	}
+
+	values := md.Get(authorizationHeaderName)
+	if len(values) != 1 || !strings.HasPrefix(values[0], bearerPrefix) {
+		return "", status.Error(codes.Unauthenticated, "")
+	}
+	provided := []byte(strings.TrimPrefix(values[0], bearerPrefix))
+
+	var operator string
+	for token, name := range s.operators {
+		if subtle.ConstantTimeCompare(provided, []byte(token)) == 1 {
+			operator = name
+		}
+	}
+	if len(operator) == 0 {
+		return "", status.Error(codes.Unauthenticated, "")
+	}
+	return operator, nil
+}
+
+// authenticateOperatorAction authenticates a request to change state, which
+// must provide a reason for the audit log
+func (s *server) authenticateOperatorAction(ctx context.Context, reason string) (string, error) {
+	operator, err := s.authenticate(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if len(strings.TrimSpace(reason)) == 0 {
+		return "", status.Error(codes.InvalidArgument, "reason is required")
+	}
+
+	return operator, nil
+}
+
+func newAuditRecord(operator, action, reason string) *adminaudit.Record {
+	return &adminaudit.Record{
+		Operator: operator,
+		Action:   action,
+		Reason:   reason,
+	}
+}
+
+// putAuditRecord persists the outcome of an operator action. Successful changes
+// must be persisted within the same DB transaction as the change itself.
+func (s *server) putAuditRecord(ctx context.Context, record *adminaudit.Record, result adminaudit.Result, detail string) error {
+	record.Result = result
+	record.Detail = detail
+	return s.data.PutAdminAuditRecord(ctx, record)
+}
+
+// saveRejectedAuditRecord persists and logs an operator action that was
+// rejected without changing any state
+func (s *server) saveRejectedAuditRecord(ctx context.Context, record *adminaudit.Record, result adminaudit.Result, detail string) error {
+	err := s.putAuditRecord(ctx, record, result, detail)
+	if err != nil {
+		return err
+	}
+	s.logAuditRecord(record)
+	return nil
+}
+
+func (s *server) logAuditRecord(record *adminaudit.Record) {
+	s.auditLog.WithFields(logrus.Fields{
+		"operator":    record.Operator,
+		"action":      record.Action,
+		"reason":      record.Reason,
+		"intent":      record.Intent,
+		"fulfillment": record.FulfillmentId,
+		"result":      record.Result.String(),
+	}).Info(record.Detail)
+}
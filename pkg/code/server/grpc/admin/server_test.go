@@ -0,0 +1,447 @@
+package admin
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mr-tron/base58"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/code-payments/code-server/pkg/pointer"
+	"github.com/code-payments/code-server/pkg/testutil"
+	code_data "github.com/code-payments/code-server/pkg/code/data"
+	datatest "github.com/code-payments/code-server/pkg/code/data/test"
+	"github.com/code-payments/code-server/pkg/code/data/action"
+	"github.com/code-payments/code-server/pkg/code/data/adminaudit"
+	"github.com/code-payments/code-server/pkg/code/data/deadletter"
+	"github.com/code-payments/code-server/pkg/code/data/fulfillment"
+	"github.com/code-payments/code-server/pkg/code/data/intent"
+	"github.com/code-payments/code-server/pkg/code/data/nonce"
+	adminpb "github.com/code-payments/code-server/pkg/code/server/grpc/admin/api/gen"
+)
+
+const (
+	testOperator = "alice"
+	testToken    = "test-operator-token"
+)
+
+func TestUnauthenticated(t *testing.T) {
+	env, cleanup := setup(t)
+	defer cleanup()
+
+	for _, ctx := range []context.Context{
+		context.Background(),
+		metadata.AppendToOutgoingContext(context.Background(), authorizationHeaderName, "Bearer invalid-token"),
+		metadata.AppendToOutgoingContext(context.Background(), authorizationHeaderName, testToken),
+	} {
+		_, err := env.client.GetIntent(ctx, &adminpb.GetIntentRequest{IntentId: "intent"})
+		testutil.AssertStatusErrorWithCode(t, err, codes.Unauthenticated)
+
+		_, err = env.client.RevokeIntent(ctx, &adminpb.RevokeIntentRequest{IntentId: "intent", Reason: "reason"})
+		testutil.AssertStatusErrorWithCode(t, err, codes.Unauthenticated)
+	}
+}
+
+func TestGetIntent(t *testing.T) {
+	env, cleanup := setup(t)
+	defer cleanup()
+
+	resp, err := env.client.GetIntent(env.ctx, &adminpb.GetIntentRequest{IntentId: "unknown"})
+	require.NoError(t, err)
+	assert.Equal(t, adminpb.GetIntentResponse_NOT_FOUND, resp.Result)
+
+	intentRecord, actionRecord, fulfillmentRecord, nonceRecord := env.setupIntent(t, fulfillment.StatePending, true)
+
+	resp, err = env.client.GetIntent(env.ctx, &adminpb.GetIntentRequest{IntentId: intentRecord.IntentId})
+	require.NoError(t, err)
+	assert.Equal(t, adminpb.GetIntentResponse_OK, resp.Result)
+	assert.Equal(t, intentRecord.IntentId, resp.Intent.Id)
+	assert.Equal(t, intent.StatePending.String(), resp.Intent.State)
+
+	require.Len(t, resp.Actions, 1)
+	assert.Equal(t, actionRecord.ActionId, resp.Actions[0].Id)
+	assert.Equal(t, action.PrivateTransfer.String(), resp.Actions[0].Type)
+	assert.Equal(t, *actionRecord.Quantity, resp.Actions[0].Quantity.Quarks)
+	assert.Nil(t, resp.Actions[0].Commitment)
+
+	require.Len(t, resp.Actions[0].Fulfillments, 1)
+	protoFulfillment := resp.Actions[0].Fulfillments[0]
+	assert.Equal(t, fulfillmentRecord.Id, protoFulfillment.Id)
+	assert.Equal(t, *fulfillmentRecord.Signature, protoFulfillment.Signature)
+	assert.Equal(t, nonceRecord.Address, protoFulfillment.Nonce.Address)
+	assert.Equal(t, nonce.StateReserved.String(), protoFulfillment.Nonce.State)
+	assert.Nil(t, protoFulfillment.Transaction)
+	assert.False(t, protoFulfillment.OnChainStatus.Found)
+}
+
+func TestRetryFulfillment(t *testing.T) {
+	env, cleanup := setup(t)
+	defer cleanup()
+
+	intentRecord, actionRecord, fulfillmentRecord, _ := env.setupIntent(t, fulfillment.StatePending, false)
+
+	_, err := env.client.RetryFulfillment(env.ctx, &adminpb.RetryFulfillmentRequest{FulfillmentId: fulfillmentRecord.Id})
+	testutil.AssertStatusErrorWithCode(t, err, codes.InvalidArgument)
+
+	resp, err := env.client.RetryFulfillment(env.ctx, &adminpb.RetryFulfillmentRequest{FulfillmentId: fulfillmentRecord.Id + 100, Reason: "reason"})
+	require.NoError(t, err)
+	assert.Equal(t, adminpb.RetryFulfillmentResponse_NOT_FOUND, resp.Result)
+
+	resp, err = env.client.RetryFulfillment(env.ctx, &adminpb.RetryFulfillmentRequest{FulfillmentId: fulfillmentRecord.Id, Reason: "reason"})
+	require.NoError(t, err)
+	assert.Equal(t, adminpb.RetryFulfillmentResponse_INVALID_STATE, resp.Result)
+
+	fulfillmentRecord.State = fulfillment.StateFailed
+	require.NoError(t, env.data.UpdateFulfillment(env.ctx, fulfillmentRecord))
+	actionRecord.State = action.StateFailed
+	require.NoError(t, env.data.UpdateAction(env.ctx, actionRecord))
+	intentRecord.State = intent.StateFailed
+	require.NoError(t, env.data.SaveIntent(env.ctx, intentRecord))
+
+	deadLetterRecord := &deadletter.Record{
+		FulfillmentId:   fulfillmentRecord.Id,
+		Intent:          fulfillmentRecord.Intent,
+		FulfillmentType: fulfillmentRecord.FulfillmentType,
+		Signature:       *fulfillmentRecord.Signature,
+		Category:        deadletter.CategoryUnknown,
+		Reason:          "transaction not found on the blockchain",
+		State:           deadletter.StateQuarantined,
+	}
+	require.NoError(t, env.data.SaveFulfillmentDeadLetter(env.ctx, deadLetterRecord))
+
+	resp, err = env.client.RetryFulfillment(env.ctx, &adminpb.RetryFulfillmentRequest{FulfillmentId: fulfillmentRecord.Id, Reason: "reason"})
+	require.NoError(t, err)
+	assert.Equal(t, adminpb.RetryFulfillmentResponse_OK, resp.Result)
+
+	updatedFulfillmentRecord, err := env.data.GetFulfillmentById(env.ctx, fulfillmentRecord.Id)
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.StatePending, updatedFulfillmentRecord.State)
+	assert.Nil(t, updatedFulfillmentRecord.Signature)
+	assert.Nil(t, updatedFulfillmentRecord.Nonce)
+	assert.Nil(t, updatedFulfillmentRecord.Blockhash)
+	assert.Nil(t, updatedFulfillmentRecord.Data)
+
+	updatedActionRecord, err := env.data.GetActionById(env.ctx, intentRecord.IntentId, actionRecord.ActionId)
+	require.NoError(t, err)
+	assert.Equal(t, action.StatePending, updatedActionRecord.State)
+
+	updatedIntentRecord, err := env.data.GetIntent(env.ctx, intentRecord.IntentId)
+	require.NoError(t, err)
+	assert.Equal(t, intent.StatePending, updatedIntentRecord.State)
+
+	updatedDeadLetterRecord, err := env.data.GetFulfillmentDeadLetter(env.ctx, fulfillmentRecord.Id)
+	require.NoError(t, err)
+	assert.Equal(t, deadletter.StateRetried, updatedDeadLetterRecord.State)
+	assert.EqualValues(t, 1, updatedDeadLetterRecord.Retries)
+
+	env.assertAuditRecords(t, fulfillmentRecord.Id, "retry_fulfillment", adminaudit.ResultInvalidState, adminaudit.ResultOk)
+	env.assertAuditRecords(t, fulfillmentRecord.Id+100, "retry_fulfillment", adminaudit.ResultNotFound)
+}
+
+func TestRetryFulfillment_Unsupported(t *testing.T) {
+	env, cleanup := setup(t)
+	defer cleanup()
+
+	_, _, fulfillmentRecord, _ := env.setupIntent(t, fulfillment.StatePending, false)
+
+	fulfillmentRecord.State = fulfillment.StateFailed
+	require.NoError(t, env.data.UpdateFulfillment(env.ctx, fulfillmentRecord))
+
+	// Same record, but of a type signed by the client
+	fulfillmentRecord.Id = 0
+	fulfillmentRecord.FulfillmentType = fulfillment.TemporaryPrivacyTransferWithAuthority
+	fulfillmentRecord.FulfillmentOrderingIndex = 1
+	fulfillmentRecord.Signature = pointer.String("client-signature")
+	fulfillmentRecord.Nonce = nil
+	fulfillmentRecord.Blockhash = nil
+	require.NoError(t, env.data.PutAllFulfillments(env.ctx, fulfillmentRecord))
+
+	resp, err := env.client.RetryFulfillment(env.ctx, &adminpb.RetryFulfillmentRequest{FulfillmentId: fulfillmentRecord.Id, Reason: "reason"})
+	require.NoError(t, err)
+	assert.Equal(t, adminpb.RetryFulfillmentResponse_UNSUPPORTED, resp.Result)
+
+	env.assertAuditRecords(t, fulfillmentRecord.Id, "retry_fulfillment", adminaudit.ResultUnsupported)
+}
+
+func TestResetFailedFulfillment(t *testing.T) {
+	env, cleanup := setup(t)
+	defer cleanup()
+
+	_, _, fulfillmentRecord, _ := env.setupIntent(t, fulfillment.StateFailed, false)
+
+	_, err := env.client.ResetFailedFulfillment(env.ctx, &adminpb.ResetFailedFulfillmentRequest{FulfillmentId: fulfillmentRecord.Id})
+	testutil.AssertStatusErrorWithCode(t, err, codes.InvalidArgument)
+
+	resp, err := env.client.ResetFailedFulfillment(env.ctx, &adminpb.ResetFailedFulfillmentRequest{FulfillmentId: fulfillmentRecord.Id, Reason: "reason"})
+	require.NoError(t, err)
+	assert.Equal(t, adminpb.ResetFailedFulfillmentResponse_NOT_FOUND, resp.Result)
+
+	deadLetterRecord := &deadletter.Record{
+		FulfillmentId:   fulfillmentRecord.Id,
+		Intent:          fulfillmentRecord.Intent,
+		FulfillmentType: fulfillmentRecord.FulfillmentType,
+		Signature:       *fulfillmentRecord.Signature,
+		Category:        deadletter.CategoryProgramError,
+		Reason:          "Error processing Instruction 1: custom program error: 1771",
+		State:           deadletter.StateQuarantined,
+	}
+	require.NoError(t, env.data.SaveFulfillmentDeadLetter(env.ctx, deadLetterRecord))
+
+	// Quarantined failures must be resolved instead
+	resp, err = env.client.ResetFailedFulfillment(env.ctx, &adminpb.ResetFailedFulfillmentRequest{FulfillmentId: fulfillmentRecord.Id, Reason: "reason"})
+	require.NoError(t, err)
+	assert.Equal(t, adminpb.ResetFailedFulfillmentResponse_INVALID_STATE, resp.Result)
+
+	deadLetterRecord.Category = deadletter.CategoryBlockhashOrNonce
+	deadLetterRecord.Retries = 2
+	deadLetterRecord.State = deadletter.StateRetried
+	require.NoError(t, env.data.SaveFulfillmentDeadLetter(env.ctx, deadLetterRecord))
+
+	countByCategory, err := env.data.GetFulfillmentDeadLetterCountByStateGroupedByCategory(env.ctx, deadletter.StateRetried)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, countByCategory[deadletter.CategoryBlockhashOrNonce])
+
+	resp, err = env.client.ResetFailedFulfillment(env.ctx, &adminpb.ResetFailedFulfillmentRequest{FulfillmentId: fulfillmentRecord.Id, Reason: "reason"})
+	require.NoError(t, err)
+	assert.Equal(t, adminpb.ResetFailedFulfillmentResponse_OK, resp.Result)
+
+	updatedDeadLetterRecord, err := env.data.GetFulfillmentDeadLetter(env.ctx, fulfillmentRecord.Id)
+	require.NoError(t, err)
+	assert.Equal(t, deadletter.StateReset, updatedDeadLetterRecord.State)
+	assert.EqualValues(t, 2, updatedDeadLetterRecord.Retries)
+
+	countByCategory, err = env.data.GetFulfillmentDeadLetterCountByStateGroupedByCategory(env.ctx, deadletter.StateRetried)
+	require.NoError(t, err)
+	assert.Empty(t, countByCategory)
+
+	resp, err = env.client.ResetFailedFulfillment(env.ctx, &adminpb.ResetFailedFulfillmentRequest{FulfillmentId: fulfillmentRecord.Id, Reason: "reason"})
+	require.NoError(t, err)
+	assert.Equal(t, adminpb.ResetFailedFulfillmentResponse_INVALID_STATE, resp.Result)
+
+	env.assertAuditRecords(
+		t,
+		fulfillmentRecord.Id,
+		"reset_failed_fulfillment",
+		adminaudit.ResultNotFound,
+		adminaudit.ResultInvalidState,
+		adminaudit.ResultOk,
+		adminaudit.ResultInvalidState,
+	)
+}
+
+func TestSetActiveScheduling(t *testing.T) {
+	env, cleanup := setup(t)
+	defer cleanup()
+
+	_, _, fulfillmentRecord, _ := env.setupIntent(t, fulfillment.StatePending, false)
+
+	for _, enabled := range []bool{false, true} {
+		resp, err := env.client.SetActiveScheduling(env.ctx, &adminpb.SetActiveSchedulingRequest{FulfillmentId: fulfillmentRecord.Id, Enabled: enabled, Reason: "reason"})
+		require.NoError(t, err)
+		assert.Equal(t, adminpb.SetActiveSchedulingResponse_OK, resp.Result)
+
+		updated, err := env.data.GetFulfillmentById(env.ctx, fulfillmentRecord.Id)
+		require.NoError(t, err)
+		assert.Equal(t, !enabled, updated.DisableActiveScheduling)
+	}
+
+	fulfillmentRecord.State = fulfillment.StateConfirmed
+	require.NoError(t, env.data.UpdateFulfillment(env.ctx, fulfillmentRecord))
+
+	resp, err := env.client.SetActiveScheduling(env.ctx, &adminpb.SetActiveSchedulingRequest{FulfillmentId: fulfillmentRecord.Id, Enabled: false, Reason: "reason"})
+	require.NoError(t, err)
+	assert.Equal(t, adminpb.SetActiveSchedulingResponse_INVALID_STATE, resp.Result)
+
+	env.assertAuditRecords(t, fulfillmentRecord.Id, "set_active_scheduling", adminaudit.ResultOk, adminaudit.ResultOk, adminaudit.ResultInvalidState)
+}
+
+func TestRevokeIntent(t *testing.T) {
+	env, cleanup := setup(t)
+	defer cleanup()
+
+	intentRecord, actionRecord, fulfillmentRecord, nonceRecord := env.setupIntent(t, fulfillment.StateUnknown, false)
+
+	_, err := env.client.RevokeIntent(env.ctx, &adminpb.RevokeIntentRequest{IntentId: intentRecord.IntentId})
+	testutil.AssertStatusErrorWithCode(t, err, codes.InvalidArgument)
+
+	// Actively scheduled fulfillments could be submitted at any time
+	resp, err := env.client.RevokeIntent(env.ctx, &adminpb.RevokeIntentRequest{IntentId: intentRecord.IntentId, Reason: "reason"})
+	require.NoError(t, err)
+	assert.Equal(t, adminpb.RevokeIntentResponse_INVALID_STATE, resp.Result)
+	assert.Contains(t, resp.Detail, "actively scheduled")
+
+	require.NoError(t, env.data.MarkFulfillmentAsNotActivelyScheduled(env.ctx, fulfillmentRecord.Id))
+
+	resp, err = env.client.RevokeIntent(env.ctx, &adminpb.RevokeIntentRequest{IntentId: intentRecord.IntentId, Reason: "reason"})
+	require.NoError(t, err)
+	assert.Equal(t, adminpb.RevokeIntentResponse_OK, resp.Result)
+
+	updatedIntentRecord, err := env.data.GetIntent(env.ctx, intentRecord.IntentId)
+	require.NoError(t, err)
+	assert.Equal(t, intent.StateRevoked, updatedIntentRecord.State)
+
+	updatedActionRecord, err := env.data.GetActionById(env.ctx, intentRecord.IntentId, actionRecord.ActionId)
+	require.NoError(t, err)
+	assert.Equal(t, action.StateRevoked, updatedActionRecord.State)
+
+	updatedFulfillmentRecord, err := env.data.GetFulfillmentById(env.ctx, fulfillmentRecord.Id)
+	require.NoError(t, err)
+	assert.Equal(t, fulfillment.StateRevoked, updatedFulfillmentRecord.State)
+	assert.Nil(t, updatedFulfillmentRecord.Data)
+
+	updatedNonceRecord, err := env.data.GetNonce(env.ctx, nonceRecord.Address)
+	require.NoError(t, err)
+	assert.Equal(t, nonce.StateAvailable, updatedNonceRecord.State)
+	assert.Empty(t, updatedNonceRecord.Signature)
+
+	// Revoked intents are no longer pending
+	resp, err = env.client.RevokeIntent(env.ctx, &adminpb.RevokeIntentRequest{IntentId: intentRecord.IntentId, Reason: "reason"})
+	require.NoError(t, err)
+	assert.Equal(t, adminpb.RevokeIntentResponse_INVALID_STATE, resp.Result)
+
+	auditRecords, err := env.data.GetAllAdminAuditRecordsByIntent(env.ctx, intentRecord.IntentId)
+	require.NoError(t, err)
+	require.Len(t, auditRecords, 3)
+	for i, expected := range []adminaudit.Result{adminaudit.ResultInvalidState, adminaudit.ResultOk, adminaudit.ResultInvalidState} {
+		assert.Equal(t, testOperator, auditRecords[i].Operator)
+		assert.Equal(t, "revoke_intent", auditRecords[i].Action)
+		assert.Equal(t, "reason", auditRecords[i].Reason)
+		assert.Equal(t, expected, auditRecords[i].Result)
+	}
+}
+
+func TestRevokeIntent_SubmittedFulfillment(t *testing.T) {
+	env, cleanup := setup(t)
+	defer cleanup()
+
+	intentRecord, _, _, _ := env.setupIntent(t, fulfillment.StatePending, true)
+
+	resp, err := env.client.RevokeIntent(env.ctx, &adminpb.RevokeIntentRequest{IntentId: intentRecord.IntentId, Reason: "reason"})
+	require.NoError(t, err)
+	assert.Equal(t, adminpb.RevokeIntentResponse_INVALID_STATE, resp.Result)
+
+	updatedIntentRecord, err := env.data.GetIntent(env.ctx, intentRecord.IntentId)
+	require.NoError(t, err)
+	assert.Equal(t, intent.StatePending, updatedIntentRecord.State)
+}
+
+func (e *testEnv) assertAuditRecords(t *testing.T, fulfillmentId uint64, action string, expected ...adminaudit.Result) {
+	auditRecords, err := e.data.GetAllAdminAuditRecordsByFulfillment(e.ctx, fulfillmentId)
+	require.NoError(t, err)
+	require.Len(t, auditRecords, len(expected))
+	for i, result := range expected {
+		assert.Equal(t, testOperator, auditRecords[i].Operator)
+		assert.Equal(t, action, auditRecords[i].Action)
+		assert.Equal(t, "reason", auditRecords[i].Reason)
+		assert.Equal(t, result, auditRecords[i].Result)
+		assert.NotEmpty(t, auditRecords[i].Detail)
+	}
+}
+
+type testEnv struct {
+	ctx    context.Context
+	client adminpb.AdminClient
+	data   code_data.Provider
+}
+
+func setup(t *testing.T) (env *testEnv, cleanup func()) {
+	conn, serv, err := testutil.NewServer()
+	require.NoError(t, err)
+
+	env = &testEnv{
+		ctx:    metadata.AppendToOutgoingContext(context.Background(), authorizationHeaderName, bearerPrefix+testToken),
+		client: adminpb.NewAdminClient(conn),
+		data:   datatest.NewDataProvider(),
+	}
+
+	s := NewAdminServer(env.data, map[string]string{
+		testOperator: testToken,
+	})
+
+	serv.RegisterService(func(server *grpc.Server) {
+		adminpb.RegisterAdminServer(server, s)
+	})
+
+	cleanup, err = serv.Serve()
+	require.NoError(t, err)
+	return env, cleanup
+}
+
+// setupIntent creates a pending intent with a single private transfer, whose
+// transaction uses a reserved nonce
+func (e *testEnv) setupIntent(t *testing.T, state fulfillment.State, disableActiveScheduling bool) (*intent.Record, *action.Record, *fulfillment.Record, *nonce.Record) {
+	owner := testutil.NewRandomAccount(t)
+	source := testutil.NewRandomAccount(t)
+	destination := testutil.NewRandomAccount(t)
+	nonceAccount := testutil.NewRandomAccount(t)
+
+	intentRecord := &intent.Record{
+		IntentId:              fmt.Sprintf("intent%d", time.Now().UnixNano()),
+		IntentType:            intent.SendPrivatePayment,
+		InitiatorOwnerAccount: owner.PublicKey().ToBase58(),
+		SendPrivatePaymentMetadata: &intent.SendPrivatePaymentMetadata{
+			DestinationOwnerAccount: destination.PublicKey().ToBase58(),
+			DestinationTokenAccount: destination.PublicKey().ToBase58(),
+			Quantity:                42,
+			ExchangeCurrency:        "usd",
+			ExchangeRate:            1.0,
+			NativeAmount:            42,
+			UsdMarketValue:          42,
+		},
+		State: intent.StatePending,
+	}
+	require.NoError(t, e.data.SaveIntent(e.ctx, intentRecord))
+
+	actionRecord := &action.Record{
+		Intent:      intentRecord.IntentId,
+		IntentType:  intentRecord.IntentType,
+		ActionId:    0,
+		ActionType:  action.PrivateTransfer,
+		Source:      source.PublicKey().ToBase58(),
+		Destination: pointer.String(destination.PublicKey().ToBase58()),
+		Quantity:    pointer.Uint64(42),
+		State:       action.StatePending,
+	}
+	require.NoError(t, e.data.PutAllActions(e.ctx, actionRecord))
+
+	signature := make([]byte, 64)
+	rand.Read(signature)
+	blockhash := make([]byte, 32)
+	rand.Read(blockhash)
+
+	nonceRecord := &nonce.Record{
+		Address:   nonceAccount.PublicKey().ToBase58(),
+		Authority: owner.PublicKey().ToBase58(),
+		Blockhash: base58.Encode(blockhash),
+		Purpose:   nonce.PurposeOnDemandTransaction,
+		State:     nonce.StateReserved,
+		Signature: base58.Encode(signature),
+	}
+	require.NoError(t, e.data.SaveNonce(e.ctx, nonceRecord))
+
+	fulfillmentRecord := &fulfillment.Record{
+		Intent:                  intentRecord.IntentId,
+		IntentType:              intentRecord.IntentType,
+		ActionId:                actionRecord.ActionId,
+		ActionType:              actionRecord.ActionType,
+		FulfillmentType:         fulfillment.TransferWithCommitment,
+		Data:                    []byte("transaction"),
+		Signature:               pointer.String(nonceRecord.Signature),
+		Nonce:                   pointer.String(nonceRecord.Address),
+		Blockhash:               pointer.String(nonceRecord.Blockhash),
+		Source:                  source.PublicKey().ToBase58(),
+		Destination:             pointer.String(destination.PublicKey().ToBase58()),
+		DisableActiveScheduling: disableActiveScheduling,
+		State:                   state,
+	}
+	require.NoError(t, e.data.PutAllFulfillments(e.ctx, fulfillmentRecord))
+
+	return intentRecord, actionRecord, fulfillmentRecord, nonceRecord
+}
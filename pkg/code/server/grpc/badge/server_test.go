@@ -19,6 +19,7 @@ import (
 	auth_util "github.com/code-payments/code-server/pkg/code/auth"
 	"github.com/code-payments/code-server/pkg/code/common"
 	code_data "github.com/code-payments/code-server/pkg/code/data"
+	datatest "github.com/code-payments/code-server/pkg/code/data/test"
 	"github.com/code-payments/code-server/pkg/code/data/badgecount"
 	"github.com/code-payments/code-server/pkg/code/data/phone"
 	"github.com/code-payments/code-server/pkg/code/data/push"
@@ -83,7 +84,7 @@ func setup(t *testing.T) (env *testEnv, cleanup func()) {
 	env = &testEnv{
 		ctx:    context.Background(),
 		client: badgepb.NewBadgeClient(conn),
-		data:   code_data.NewTestDataProvider(),
+		data:   datatest.NewDataProvider(),
 	}
 
 	s := NewBadgeServer(env.data, memory_push.NewPushProvider(), auth_util.NewRPCSignatureVerifier(env.data))
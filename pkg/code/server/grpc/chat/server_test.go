@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	chatpb "github.com/code-payments/code-protobuf-api/generated/go/chat/v1"
@@ -22,6 +23,7 @@ import (
 	chat_util "github.com/code-payments/code-server/pkg/code/chat"
 	"github.com/code-payments/code-server/pkg/code/common"
 	code_data "github.com/code-payments/code-server/pkg/code/data"
+	datatest "github.com/code-payments/code-server/pkg/code/data/test"
 	"github.com/code-payments/code-server/pkg/code/data/chat"
 	"github.com/code-payments/code-server/pkg/code/localization"
 )
@@ -254,6 +256,72 @@ func TestGetChatsAndMessages_HappyPath(t *testing.T) {
 	assert.True(t, proto.Equal(expectedUnverifiedExternalAppMessage, getMessagesResp.Messages[0]))
 }
 
+func TestGetChatsAndMessages_LocalizationKeysIgnoreLocale(t *testing.T) {
+	env, cleanup := setup(t)
+	defer cleanup()
+
+	owner := testutil.NewRandomAccount(t)
+
+	codeTeamChatId := chat.GetChatId(chat_util.CodeTeamName, owner.PublicKey().ToBase58(), true)
+
+	getChatsReq := &chatpb.GetChatsRequest{
+		Owner: owner.ToProto(),
+	}
+	getChatsReq.Signature = signProtoMessage(t, getChatsReq, owner, false)
+
+	getMessagesReq := &chatpb.GetMessagesRequest{
+		ChatId: codeTeamChatId.ToProto(),
+		Owner:  owner.ToProto(),
+	}
+	getMessagesReq.Signature = signProtoMessage(t, getMessagesReq, owner, false)
+
+	env.sendInternalChatMessage(t, &chatpb.ChatMessage{
+		MessageId: &chatpb.ChatMessageId{
+			Value: testutil.NewRandomAccount(t).ToProto().Value,
+		},
+		Ts: timestamppb.Now(),
+		Content: []*chatpb.Content{
+			{
+				Type: &chatpb.Content_Localized{
+					Localized: &chatpb.LocalizedContent{
+						Key: localization.ChatMessageWelcomeBonus,
+					},
+				},
+			},
+			{
+				Type: &chatpb.Content_Localized{
+					Localized: &chatpb.LocalizedContent{
+						Key: "msg.body.key",
+					},
+				},
+			},
+		},
+	}, chat_util.CodeTeamName, owner)
+
+	// Chat content only has a field for the localization key, so clients
+	// always localize it, regardless of the locale they request.
+	for _, locale := range []string{"", "es-MX", "en-US", "ja"} {
+		ctx := env.ctx
+		if len(locale) > 0 {
+			ctx = metadata.AppendToOutgoingContext(ctx, "accept-language", locale)
+		}
+
+		getChatsResp, err := env.client.GetChats(ctx, getChatsReq)
+		require.NoError(t, err)
+		assert.Equal(t, chatpb.GetChatsResponse_OK, getChatsResp.Result)
+		require.Len(t, getChatsResp.Chats, 1)
+		assert.Equal(t, localization.ChatTitleCodeTeam, getChatsResp.Chats[0].GetLocalized().Key)
+
+		getMessagesResp, err := env.client.GetMessages(ctx, getMessagesReq)
+		require.NoError(t, err)
+		assert.Equal(t, chatpb.GetMessagesResponse_OK, getMessagesResp.Result)
+		require.Len(t, getMessagesResp.Messages, 1)
+		require.Len(t, getMessagesResp.Messages[0].Content, 2)
+		assert.Equal(t, localization.ChatMessageWelcomeBonus, getMessagesResp.Messages[0].Content[0].GetLocalized().Key)
+		assert.Equal(t, "msg.body.key", getMessagesResp.Messages[0].Content[1].GetLocalized().Key)
+	}
+}
+
 func TestChatHistoryReadState_HappyPath(t *testing.T) {
 	env, cleanup := setup(t)
 	defer cleanup()
@@ -852,7 +920,7 @@ func setup(t *testing.T) (env *testEnv, cleanup func()) {
 	env = &testEnv{
 		ctx:    context.Background(),
 		client: chatpb.NewChatClient(conn),
-		data:   code_data.NewTestDataProvider(),
+		data:   datatest.NewDataProvider(),
 	}
 
 	s := NewChatServer(env.data, auth_util.NewRPCSignatureVerifier(env.data))
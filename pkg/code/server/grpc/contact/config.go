@@ -0,0 +1,47 @@
+package contact
+
+import (
+	"github.com/code-payments/code-server/pkg/config"
+	"github.com/code-payments/code-server/pkg/config/env"
+	"github.com/code-payments/code-server/pkg/config/memory"
+	"github.com/code-payments/code-server/pkg/config/wrapper"
+)
+
+const (
+	envConfigPrefix = "CONTACT_SERVICE_"
+
+	// When disabled, uploaded address books are only used to compute contact
+	// status and are never persisted. Existing contact lists are purged once
+	// they've been fully read by the client, which migrates users towards
+	// hashed contact discovery.
+	RetainContactListsConfigEnvName = envConfigPrefix + "RETAIN_CONTACT_LISTS"
+	defaultRetainContactLists       = true
+)
+
+type conf struct {
+	retainContactLists config.Bool
+}
+
+// ConfigProvider defines how config values are pulled
+type ConfigProvider func() *conf
+
+// WithEnvConfigs returns configuration pulled from environment variables
+func WithEnvConfigs() ConfigProvider {
+	return func() *conf {
+		return &conf{
+			retainContactLists: env.NewBoolConfig(RetainContactListsConfigEnvName, defaultRetainContactLists),
+		}
+	}
+}
+
+type testOverrides struct {
+	retainContactLists bool
+}
+
+func withManualTestOverrides(overrides *testOverrides) ConfigProvider {
+	return func() *conf {
+		return &conf{
+			retainContactLists: wrapper.NewBoolConfig(memory.NewConfig(overrides.retainContactLists), overrides.retainContactLists),
+		}
+	}
+}
@@ -24,6 +24,7 @@ const (
 
 type contactListServer struct {
 	log  *logrus.Entry
+	conf *conf
 	data code_data.Provider
 	auth *auth_util.RPCSignatureVerifier
 
@@ -33,9 +34,11 @@ type contactListServer struct {
 func NewContactListServer(
 	data code_data.Provider,
 	auth *auth_util.RPCSignatureVerifier,
+	configProvider ConfigProvider,
 ) contactpb.ContactListServer {
 	return &contactListServer{
 		log:  logrus.StandardLogger().WithField("type", "contact/server"),
+		conf: configProvider(),
 		data: data,
 		auth: auth,
 	}
@@ -70,10 +73,14 @@ func (s *contactListServer) AddContacts(ctx context.Context, req *contactpb.AddC
 		contacts[i] = contact.Value
 	}
 
-	err = s.data.BatchAddContacts(ctx, containerID, contacts)
-	if err != nil {
-		log.WithError(err).Warn("failure adding contacts")
-		return nil, status.Error(codes.Internal, "")
+	// Clients that have migrated to hashed contact discovery still use this RPC
+	// to get contact status, but we avoid retaining their raw address book.
+	if s.conf.retainContactLists.Get(ctx) {
+		err = s.data.BatchAddContacts(ctx, containerID, contacts)
+		if err != nil {
+			log.WithError(err).Warn("failure adding contacts")
+			return nil, status.Error(codes.Internal, "")
+		}
 	}
 
 	contactStatusByNumber, err := s.batchGetContactStatus(ctx, contacts)
@@ -200,6 +207,17 @@ func (s *contactListServer) GetContacts(ctx context.Context, req *contactpb.GetC
 		} else {
 			// Stop processing when we've reached the end of the contact list.
 			nextPageToken = nil
+
+			// The client has now seen its entire contact list, so we can purge
+			// anything retained prior to disabling contact list storage.
+			if !s.conf.retainContactLists.Get(ctx) {
+				err = s.data.RemoveAllContacts(ctx, containerID)
+				if err != nil {
+					log.WithError(err).Warn("failure purging contact list")
+					return nil, status.Error(codes.Internal, "")
+				}
+			}
+
 			break
 		}
 
@@ -19,6 +19,7 @@ import (
 
 	"github.com/code-payments/code-server/pkg/code/auth"
 	code_data "github.com/code-payments/code-server/pkg/code/data"
+	datatest "github.com/code-payments/code-server/pkg/code/data/test"
 	"github.com/code-payments/code-server/pkg/code/data/phone"
 	"github.com/code-payments/code-server/pkg/code/data/user"
 	"github.com/code-payments/code-server/pkg/code/data/user/storage"
@@ -33,14 +34,20 @@ type testEnv struct {
 }
 
 func setup(t *testing.T) (env testEnv, cleanup func()) {
+	return setupWithOverrides(t, &testOverrides{
+		retainContactLists: true,
+	})
+}
+
+func setupWithOverrides(t *testing.T, overrides *testOverrides) (env testEnv, cleanup func()) {
 	conn, serv, err := testutil.NewServer()
 	require.NoError(t, err)
 
 	env.ctx = context.Background()
 	env.client = contactpb.NewContactListClient(conn)
-	env.data = code_data.NewTestDataProvider()
+	env.data = datatest.NewDataProvider()
 
-	s := NewContactListServer(env.data, auth.NewRPCSignatureVerifier(env.data))
+	s := NewContactListServer(env.data, auth.NewRPCSignatureVerifier(env.data), withManualTestOverrides(overrides))
 	env.server = s.(*contactListServer)
 
 	serv.RegisterService(func(server *grpc.Server) {
@@ -497,3 +504,79 @@ func generateNewDataContainer(t *testing.T, env testEnv, ownerAccount string) *u
 	require.NoError(t, env.data.PutUserDataContainer(env.ctx, container))
 	return container.ID
 }
+
+func TestContactListsNotRetained(t *testing.T) {
+	env, cleanup := setupWithOverrides(t, &testOverrides{
+		retainContactLists: false,
+	})
+	defer cleanup()
+
+	ownerAccount := testutil.NewRandomAccount(t)
+
+	containerID := generateNewDataContainer(t, env, ownerAccount.PublicKey().ToBase58())
+
+	// Simulate a contact list stored prior to disabling retention
+	existingPhoneNumber := "+18005550001"
+	require.NoError(t, env.data.AddContact(env.ctx, containerID, existingPhoneNumber))
+
+	newPhoneNumber := "+18005550002"
+	require.NoError(t, env.data.SavePhoneVerification(env.ctx, &phone.Verification{
+		PhoneNumber:    newPhoneNumber,
+		OwnerAccount:   testutil.NewRandomAccount(t).PublicKey().ToBase58(),
+		CreatedAt:      time.Now(),
+		LastVerifiedAt: time.Now(),
+	}))
+
+	addReq := &contactpb.AddContactsRequest{
+		OwnerAccountId: ownerAccount.ToProto(),
+		ContainerId:    containerID.Proto(),
+		Contacts: []*commonpb.PhoneNumber{
+			{
+				Value: newPhoneNumber,
+			},
+		},
+	}
+
+	getReq := &contactpb.GetContactsRequest{
+		OwnerAccountId: ownerAccount.ToProto(),
+		ContainerId:    containerID.Proto(),
+	}
+
+	reqBytes, err := proto.Marshal(addReq)
+	require.NoError(t, err)
+	signature, err := ownerAccount.Sign(reqBytes)
+	require.NoError(t, err)
+	addReq.Signature = &commonpb.Signature{
+		Value: signature,
+	}
+
+	reqBytes, err = proto.Marshal(getReq)
+	require.NoError(t, err)
+	signature, err = ownerAccount.Sign(reqBytes)
+	require.NoError(t, err)
+	getReq.Signature = &commonpb.Signature{
+		Value: signature,
+	}
+
+	addResp, err := env.client.AddContacts(env.ctx, addReq)
+	require.NoError(t, err)
+	assert.Equal(t, contactpb.AddContactsResponse_OK, addResp.Result)
+	require.Len(t, addResp.ContactStatus, 1)
+	assert.True(t, addResp.ContactStatus[newPhoneNumber].IsRegistered)
+
+	getResp, err := env.client.GetContacts(env.ctx, getReq)
+	require.NoError(t, err)
+	assert.Equal(t, contactpb.GetContactsResponse_OK, getResp.Result)
+	require.Len(t, getResp.Contacts, 1)
+	assert.Equal(t, existingPhoneNumber, getResp.Contacts[0].PhoneNumber.Value)
+	assert.Nil(t, getResp.NextPageToken)
+
+	getResp, err = env.client.GetContacts(env.ctx, getReq)
+	require.NoError(t, err)
+	assert.Equal(t, contactpb.GetContactsResponse_OK, getResp.Result)
+	assert.Empty(t, getResp.Contacts)
+
+	contacts, _, err := env.data.GetContacts(env.ctx, containerID, 100, nil)
+	require.NoError(t, err)
+	assert.Empty(t, contacts)
+}
@@ -45,11 +45,17 @@ func (s *server) RegisterLoggedInAccounts(ctx context.Context, req *devicepb.Reg
 		return nil, status.Error(codes.InvalidArgument, "")
 	}
 
+	if len(req.Owners) > login.MaxOwnersPerAppInstall {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d owners can be logged in", login.MaxOwnersPerAppInstall)
+	}
+
 	signatures := req.Signatures
 	req.Signatures = nil
 
+	// Owners are an ordered set, which is preserved for GetLoggedInAccounts
 	var validOwners []string
 	var invalidOwners []*commonpb.SolanaAccountId
+	seenOwners := make(map[string]struct{})
 
 	for i, protoOwner := range req.Owners {
 		owner, err := common.NewAccountFromProto(protoOwner)
@@ -58,6 +64,11 @@ func (s *server) RegisterLoggedInAccounts(ctx context.Context, req *devicepb.Reg
 			return nil, status.Error(codes.Internal, "")
 		}
 
+		if _, ok := seenOwners[owner.PublicKey().ToBase58()]; ok {
+			return nil, status.Error(codes.InvalidArgument, "duplicate owner")
+		}
+		seenOwners[owner.PublicKey().ToBase58()] = struct{}{}
+
 		if err := s.auth.Authenticate(ctx, owner, req, signatures[i]); err != nil {
 			return nil, err
 		}
@@ -122,11 +133,6 @@ func (s *server) GetLoggedInAccounts(ctx context.Context, req *devicepb.GetLogge
 		return nil, status.Error(codes.Internal, "")
 	}
 
-	if len(protoOwners) > 1 {
-		log.Warn("unexpectedly have more than 1 owner logged into same app install")
-		return nil, status.Error(codes.Internal, "")
-	}
-
 	return &devicepb.GetLoggedInAccountsResponse{
 		Result: devicepb.GetLoggedInAccountsResponse_OK,
 		Owners: protoOwners,
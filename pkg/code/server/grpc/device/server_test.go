@@ -18,6 +18,7 @@ import (
 	auth_util "github.com/code-payments/code-server/pkg/code/auth"
 	"github.com/code-payments/code-server/pkg/code/common"
 	code_data "github.com/code-payments/code-server/pkg/code/data"
+	datatest "github.com/code-payments/code-server/pkg/code/data/test"
 	"github.com/code-payments/code-server/pkg/code/data/phone"
 )
 
@@ -79,6 +80,78 @@ func TestHappyPath(t *testing.T) {
 	assert.Empty(t, getResp.Owners)
 }
 
+func TestMultipleOwners(t *testing.T) {
+	env, cleanup := setup(t)
+	defer cleanup()
+
+	appInstallId := "app-install-id"
+	owners := []*common.Account{
+		testutil.NewRandomAccount(t),
+		testutil.NewRandomAccount(t),
+		testutil.NewRandomAccount(t),
+	}
+	for _, owner := range owners {
+		env.setupUser(t, owner)
+	}
+
+	// The published API validates that at most one owner is provided, so the
+	// server is called directly until the limit is lifted
+	getReq := &devicepb.GetLoggedInAccountsRequest{
+		AppInstall: &commonpb.AppInstallId{
+			Value: appInstallId,
+		},
+	}
+
+	for _, ordered := range [][]*common.Account{
+		{owners[0], owners[1], owners[2]},
+		{owners[2], owners[0]},
+		{owners[1]},
+	} {
+		registerReq := &devicepb.RegisterLoggedInAccountsRequest{
+			AppInstall: &commonpb.AppInstallId{
+				Value: appInstallId,
+			},
+		}
+		for _, owner := range ordered {
+			registerReq.Owners = append(registerReq.Owners, owner.ToProto())
+		}
+		var signatures []*commonpb.Signature
+		for _, owner := range ordered {
+			signatures = append(signatures, signProtoMessage(t, registerReq, owner, false))
+		}
+		registerReq.Signatures = signatures
+
+		registerResp, err := env.server.RegisterLoggedInAccounts(env.ctx, registerReq)
+		require.NoError(t, err)
+		assert.Equal(t, devicepb.RegisterLoggedInAccountsResponse_OK, registerResp.Result)
+
+		getResp, err := env.server.GetLoggedInAccounts(env.ctx, getReq)
+		require.NoError(t, err)
+		assert.Equal(t, devicepb.GetLoggedInAccountsResponse_OK, getResp.Result)
+		require.Len(t, getResp.Owners, len(ordered))
+		for i, owner := range ordered {
+			assert.Equal(t, owner.PublicKey().ToBytes(), getResp.Owners[i].Value)
+		}
+	}
+
+	registerReq := &devicepb.RegisterLoggedInAccountsRequest{
+		AppInstall: &commonpb.AppInstallId{
+			Value: appInstallId,
+		},
+		Owners: []*commonpb.SolanaAccountId{
+			owners[0].ToProto(),
+			owners[0].ToProto(),
+		},
+	}
+	registerReq.Signatures = []*commonpb.Signature{
+		signProtoMessage(t, registerReq, owners[0], false),
+		signProtoMessage(t, registerReq, owners[0], false),
+	}
+
+	_, err := env.server.RegisterLoggedInAccounts(env.ctx, registerReq)
+	testutil.AssertStatusErrorWithCode(t, err, codes.InvalidArgument)
+}
+
 func TestInvalidOwner(t *testing.T) {
 	env, cleanup := setup(t)
 	defer cleanup()
@@ -151,7 +224,7 @@ func setup(t *testing.T) (env *testEnv, cleanup func()) {
 	env = &testEnv{
 		ctx:    context.Background(),
 		client: devicepb.NewDeviceClient(conn),
-		data:   code_data.NewTestDataProvider(),
+		data:   datatest.NewDataProvider(),
 	}
 
 	s := NewDeviceServer(env.data, auth_util.NewRPCSignatureVerifier(env.data))
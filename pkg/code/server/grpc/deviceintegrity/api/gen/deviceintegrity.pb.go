@@ -0,0 +1,306 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.1
+// 	protoc        v3.12.4
+// source: deviceintegrity.proto
+
+package deviceintegrity
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GetIntegrityNonceResponse_Result int32
+
+const (
+	GetIntegrityNonceResponse_OK GetIntegrityNonceResponse_Result = 0
+	// The device id is missing or malformed
+	GetIntegrityNonceResponse_INVALID_DEVICE_ID GetIntegrityNonceResponse_Result = 1
+)
+
+// Enum value maps for GetIntegrityNonceResponse_Result.
+var (
+	GetIntegrityNonceResponse_Result_name = map[int32]string{
+		0: "OK",
+		1: "INVALID_DEVICE_ID",
+	}
+	GetIntegrityNonceResponse_Result_value = map[string]int32{
+		"OK":                0,
+		"INVALID_DEVICE_ID": 1,
+	}
+)
+
+func (x GetIntegrityNonceResponse_Result) Enum() *GetIntegrityNonceResponse_Result {
+	p := new(GetIntegrityNonceResponse_Result)
+	*p = x
+	return p
+}
+
+func (x GetIntegrityNonceResponse_Result) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (GetIntegrityNonceResponse_Result) Descriptor() protoreflect.EnumDescriptor {
+	return file_deviceintegrity_proto_enumTypes[0].Descriptor()
+}
+
+func (GetIntegrityNonceResponse_Result) Type() protoreflect.EnumType {
+	return &file_deviceintegrity_proto_enumTypes[0]
+}
+
+func (x GetIntegrityNonceResponse_Result) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use GetIntegrityNonceResponse_Result.Descriptor instead.
+func (GetIntegrityNonceResponse_Result) EnumDescriptor() ([]byte, []int) {
+	return file_deviceintegrity_proto_rawDescGZIP(), []int{1, 0}
+}
+
+type GetIntegrityNonceRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The app's ANDROID_ID, which identifies the device
+	DeviceId string `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+}
+
+func (x *GetIntegrityNonceRequest) Reset() {
+	*x = GetIntegrityNonceRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_deviceintegrity_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetIntegrityNonceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetIntegrityNonceRequest) ProtoMessage() {}
+
+func (x *GetIntegrityNonceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_deviceintegrity_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetIntegrityNonceRequest.ProtoReflect.Descriptor instead.
+func (*GetIntegrityNonceRequest) Descriptor() ([]byte, []int) {
+	return file_deviceintegrity_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetIntegrityNonceRequest) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+type GetIntegrityNonceResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Result GetIntegrityNonceResponse_Result `protobuf:"varint,1,opt,name=result,proto3,enum=code.deviceintegrity.v1.GetIntegrityNonceResponse_Result" json:"result,omitempty"`
+	// The nonce to pass to Play Integrity, which is base64 web-safe encoded
+	Nonce string `protobuf:"bytes,2,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	// The time after which the nonce can no longer be used
+	ExpiresAt *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+}
+
+func (x *GetIntegrityNonceResponse) Reset() {
+	*x = GetIntegrityNonceResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_deviceintegrity_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetIntegrityNonceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetIntegrityNonceResponse) ProtoMessage() {}
+
+func (x *GetIntegrityNonceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_deviceintegrity_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetIntegrityNonceResponse.ProtoReflect.Descriptor instead.
+func (*GetIntegrityNonceResponse) Descriptor() ([]byte, []int) {
+	return file_deviceintegrity_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetIntegrityNonceResponse) GetResult() GetIntegrityNonceResponse_Result {
+	if x != nil {
+		return x.Result
+	}
+	return GetIntegrityNonceResponse_OK
+}
+
+func (x *GetIntegrityNonceResponse) GetNonce() string {
+	if x != nil {
+		return x.Nonce
+	}
+	return ""
+}
+
+func (x *GetIntegrityNonceResponse) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+var File_deviceintegrity_proto protoreflect.FileDescriptor
+
+var file_deviceintegrity_proto_rawDesc = []byte{
+	0x0a, 0x15, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x69, 0x6e, 0x74, 0x65, 0x67, 0x72, 0x69, 0x74,
+	0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x17, 0x63, 0x6f, 0x64, 0x65, 0x2e, 0x64, 0x65,
+	0x76, 0x69, 0x63, 0x65, 0x69, 0x6e, 0x74, 0x65, 0x67, 0x72, 0x69, 0x74, 0x79, 0x2e, 0x76, 0x31,
+	0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x22, 0x37, 0x0a, 0x18, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x74, 0x65, 0x67, 0x72, 0x69, 0x74,
+	0x79, 0x4e, 0x6f, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a,
+	0x09, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x49, 0x64, 0x22, 0xe8, 0x01, 0x0a, 0x19, 0x47,
+	0x65, 0x74, 0x49, 0x6e, 0x74, 0x65, 0x67, 0x72, 0x69, 0x74, 0x79, 0x4e, 0x6f, 0x6e, 0x63, 0x65,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x51, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x39, 0x2e, 0x63, 0x6f, 0x64, 0x65, 0x2e,
+	0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x69, 0x6e, 0x74, 0x65, 0x67, 0x72, 0x69, 0x74, 0x79, 0x2e,
+	0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x74, 0x65, 0x67, 0x72, 0x69, 0x74, 0x79, 0x4e,
+	0x6f, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x52, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6e,
+	0x6f, 0x6e, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6e, 0x6f, 0x6e, 0x63,
+	0x65, 0x12, 0x39, 0x0a, 0x0a, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x5f, 0x61, 0x74, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x52, 0x09, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x41, 0x74, 0x22, 0x27, 0x0a, 0x06,
+	0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x06, 0x0a, 0x02, 0x4f, 0x4b, 0x10, 0x00, 0x12, 0x15,
+	0x0a, 0x11, 0x49, 0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x5f, 0x44, 0x45, 0x56, 0x49, 0x43, 0x45,
+	0x5f, 0x49, 0x44, 0x10, 0x01, 0x32, 0x8d, 0x01, 0x0a, 0x0f, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65,
+	0x49, 0x6e, 0x74, 0x65, 0x67, 0x72, 0x69, 0x74, 0x79, 0x12, 0x7a, 0x0a, 0x11, 0x47, 0x65, 0x74,
+	0x49, 0x6e, 0x74, 0x65, 0x67, 0x72, 0x69, 0x74, 0x79, 0x4e, 0x6f, 0x6e, 0x63, 0x65, 0x12, 0x31,
+	0x2e, 0x63, 0x6f, 0x64, 0x65, 0x2e, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x69, 0x6e, 0x74, 0x65,
+	0x67, 0x72, 0x69, 0x74, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x49, 0x6e, 0x74, 0x65,
+	0x67, 0x72, 0x69, 0x74, 0x79, 0x4e, 0x6f, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x32, 0x2e, 0x63, 0x6f, 0x64, 0x65, 0x2e, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x69,
+	0x6e, 0x74, 0x65, 0x67, 0x72, 0x69, 0x74, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x49,
+	0x6e, 0x74, 0x65, 0x67, 0x72, 0x69, 0x74, 0x79, 0x4e, 0x6f, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x13, 0x5a, 0x11, 0x2e, 0x3b, 0x64, 0x65, 0x76, 0x69, 0x63,
+	0x65, 0x69, 0x6e, 0x74, 0x65, 0x67, 0x72, 0x69, 0x74, 0x79, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
+}
+
+var (
+	file_deviceintegrity_proto_rawDescOnce sync.Once
+	file_deviceintegrity_proto_rawDescData = file_deviceintegrity_proto_rawDesc
+)
+
+func file_deviceintegrity_proto_rawDescGZIP() []byte {
+	file_deviceintegrity_proto_rawDescOnce.Do(func() {
+		file_deviceintegrity_proto_rawDescData = protoimpl.X.CompressGZIP(file_deviceintegrity_proto_rawDescData)
+	})
+	return file_deviceintegrity_proto_rawDescData
+}
+
+var file_deviceintegrity_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_deviceintegrity_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_deviceintegrity_proto_goTypes = []interface{}{
+	(GetIntegrityNonceResponse_Result)(0), // 0: code.deviceintegrity.v1.GetIntegrityNonceResponse.Result
+	(*GetIntegrityNonceRequest)(nil),      // 1: code.deviceintegrity.v1.GetIntegrityNonceRequest
+	(*GetIntegrityNonceResponse)(nil),     // 2: code.deviceintegrity.v1.GetIntegrityNonceResponse
+	(*timestamppb.Timestamp)(nil),         // 3: google.protobuf.Timestamp
+}
+var file_deviceintegrity_proto_depIdxs = []int32{
+	0, // 0: code.deviceintegrity.v1.GetIntegrityNonceResponse.result:type_name -> code.deviceintegrity.v1.GetIntegrityNonceResponse.Result
+	3, // 1: code.deviceintegrity.v1.GetIntegrityNonceResponse.expires_at:type_name -> google.protobuf.Timestamp
+	1, // 2: code.deviceintegrity.v1.DeviceIntegrity.GetIntegrityNonce:input_type -> code.deviceintegrity.v1.GetIntegrityNonceRequest
+	2, // 3: code.deviceintegrity.v1.DeviceIntegrity.GetIntegrityNonce:output_type -> code.deviceintegrity.v1.GetIntegrityNonceResponse
+	3, // [3:4] is the sub-list for method output_type
+	2, // [2:3] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_deviceintegrity_proto_init() }
+func file_deviceintegrity_proto_init() {
+	if File_deviceintegrity_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_deviceintegrity_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetIntegrityNonceRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_deviceintegrity_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetIntegrityNonceResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_deviceintegrity_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_deviceintegrity_proto_goTypes,
+		DependencyIndexes: file_deviceintegrity_proto_depIdxs,
+		EnumInfos:         file_deviceintegrity_proto_enumTypes,
+		MessageInfos:      file_deviceintegrity_proto_msgTypes,
+	}.Build()
+	File_deviceintegrity_proto = out.File
+	file_deviceintegrity_proto_rawDesc = nil
+	file_deviceintegrity_proto_goTypes = nil
+	file_deviceintegrity_proto_depIdxs = nil
+}
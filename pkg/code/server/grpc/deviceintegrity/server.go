@@ -0,0 +1,52 @@
+package deviceintegrity
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/code-payments/code-server/pkg/device/android"
+	"github.com/code-payments/code-server/pkg/grpc/client"
+	deviceintegritypb "github.com/code-payments/code-server/pkg/code/server/grpc/deviceintegrity/api/gen"
+)
+
+type server struct {
+	log         *logrus.Entry
+	nonceIssuer *android.NonceIssuer
+
+	deviceintegritypb.UnimplementedDeviceIntegrityServer
+}
+
+// NewDeviceIntegrityServer returns a new server that issues nonces for Play
+// Integrity requests. Nonces are stateless, so issuing them doesn't write
+// anything.
+func NewDeviceIntegrityServer(nonceIssuer *android.NonceIssuer) deviceintegritypb.DeviceIntegrityServer {
+	return &server{
+		log:         logrus.StandardLogger().WithField("type", "deviceintegrity/server"),
+		nonceIssuer: nonceIssuer,
+	}
+}
+
+func (s *server) GetIntegrityNonce(ctx context.Context, req *deviceintegritypb.GetIntegrityNonceRequest) (*deviceintegritypb.GetIntegrityNonceResponse, error) {
+	log := s.log.WithField("method", "GetIntegrityNonce")
+	log = client.InjectLoggingMetadata(ctx, log)
+
+	nonce, expiresAt, err := s.nonceIssuer.Issue(req.DeviceId)
+	if err == android.ErrInvalidDeviceId {
+		return &deviceintegritypb.GetIntegrityNonceResponse{
+			Result: deviceintegritypb.GetIntegrityNonceResponse_INVALID_DEVICE_ID,
+		}, nil
+	} else if err != nil {
+		log.WithError(err).Warn("failure issuing integrity nonce")
+		return nil, status.Error(codes.Internal, "")
+	}
+
+	return &deviceintegritypb.GetIntegrityNonceResponse{
+		Result:    deviceintegritypb.GetIntegrityNonceResponse_OK,
+		Nonce:     nonce,
+		ExpiresAt: timestamppb.New(expiresAt),
+	}, nil
+}
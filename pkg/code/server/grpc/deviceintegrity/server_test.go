@@ -0,0 +1,84 @@
+package deviceintegrity
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/code-payments/code-server/pkg/device/android"
+	"github.com/code-payments/code-server/pkg/testutil"
+	deviceintegritypb "github.com/code-payments/code-server/pkg/code/server/grpc/deviceintegrity/api/gen"
+)
+
+func TestGetIntegrityNonce_HappyPath(t *testing.T) {
+	env, cleanup := setup(t)
+	defer cleanup()
+
+	start := time.Now()
+
+	req := &deviceintegritypb.GetIntegrityNonceRequest{
+		DeviceId: "8f3a2c1d9e7b6a54",
+	}
+
+	resp1, err := env.client.GetIntegrityNonce(env.ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, deviceintegritypb.GetIntegrityNonceResponse_OK, resp1.Result)
+	assert.NotEmpty(t, resp1.Nonce)
+	assert.True(t, resp1.ExpiresAt.AsTime().After(start.Add(android.IntegrityNonceTtl-time.Second)))
+
+	resp2, err := env.client.GetIntegrityNonce(env.ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, deviceintegritypb.GetIntegrityNonceResponse_OK, resp2.Result)
+	assert.NotEqual(t, resp1.Nonce, resp2.Nonce)
+}
+
+func TestGetIntegrityNonce_InvalidDeviceId(t *testing.T) {
+	env, cleanup := setup(t)
+	defer cleanup()
+
+	for _, deviceId := range []string{
+		"",
+		strings.Repeat("a", android.MinDeviceIdSize-1),
+		strings.Repeat("a", android.MaxDeviceIdSize+1),
+	} {
+		resp, err := env.client.GetIntegrityNonce(env.ctx, &deviceintegritypb.GetIntegrityNonceRequest{
+			DeviceId: deviceId,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, deviceintegritypb.GetIntegrityNonceResponse_INVALID_DEVICE_ID, resp.Result)
+		assert.Empty(t, resp.Nonce)
+	}
+}
+
+type testEnv struct {
+	ctx    context.Context
+	client deviceintegritypb.DeviceIntegrityClient
+}
+
+func setup(t *testing.T) (env *testEnv, cleanup func()) {
+	conn, serv, err := testutil.NewServer()
+	require.NoError(t, err)
+
+	env = &testEnv{
+		ctx:    context.Background(),
+		client: deviceintegritypb.NewDeviceIntegrityClient(conn),
+	}
+
+	nonceIssuer, err := android.NewNonceIssuer(bytes.Repeat([]byte{1}, android.MinIntegrityNonceKeySize))
+	require.NoError(t, err)
+
+	s := NewDeviceIntegrityServer(nonceIssuer)
+	serv.RegisterService(func(server *grpc.Server) {
+		deviceintegritypb.RegisterDeviceIntegrityServer(server, s)
+	})
+
+	cleanup, err = serv.Serve()
+	require.NoError(t, err)
+	return env, cleanup
+}
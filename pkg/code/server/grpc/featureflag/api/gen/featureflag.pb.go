@@ -0,0 +1,303 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.1
+// 	protoc        v3.12.4
+// source: featureflag.proto
+
+package featureflag
+
+import (
+	v1 "github.com/code-payments/code-protobuf-api/generated/go/common/v1"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GetFeatureFlagsResponse_Result int32
+
+const (
+	GetFeatureFlagsResponse_OK GetFeatureFlagsResponse_Result = 0
+)
+
+// Enum value maps for GetFeatureFlagsResponse_Result.
+var (
+	GetFeatureFlagsResponse_Result_name = map[int32]string{
+		0: "OK",
+	}
+	GetFeatureFlagsResponse_Result_value = map[string]int32{
+		"OK": 0,
+	}
+)
+
+func (x GetFeatureFlagsResponse_Result) Enum() *GetFeatureFlagsResponse_Result {
+	p := new(GetFeatureFlagsResponse_Result)
+	*p = x
+	return p
+}
+
+func (x GetFeatureFlagsResponse_Result) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (GetFeatureFlagsResponse_Result) Descriptor() protoreflect.EnumDescriptor {
+	return file_featureflag_proto_enumTypes[0].Descriptor()
+}
+
+func (GetFeatureFlagsResponse_Result) Type() protoreflect.EnumType {
+	return &file_featureflag_proto_enumTypes[0]
+}
+
+func (x GetFeatureFlagsResponse_Result) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use GetFeatureFlagsResponse_Result.Descriptor instead.
+func (GetFeatureFlagsResponse_Result) EnumDescriptor() ([]byte, []int) {
+	return file_featureflag_proto_rawDescGZIP(), []int{1, 0}
+}
+
+type GetFeatureFlagsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The owner account that flags are evaluated for
+	Owner *v1.SolanaAccountId `protobuf:"bytes,1,opt,name=owner,proto3" json:"owner,omitempty"`
+	// The signature is of serialize(GetFeatureFlagsRequest) without this field set
+	// using the private key of the owner account. This provides an authentication
+	// mechanism to the RPC.
+	Signature *v1.Signature `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (x *GetFeatureFlagsRequest) Reset() {
+	*x = GetFeatureFlagsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_featureflag_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetFeatureFlagsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFeatureFlagsRequest) ProtoMessage() {}
+
+func (x *GetFeatureFlagsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_featureflag_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFeatureFlagsRequest.ProtoReflect.Descriptor instead.
+func (*GetFeatureFlagsRequest) Descriptor() ([]byte, []int) {
+	return file_featureflag_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetFeatureFlagsRequest) GetOwner() *v1.SolanaAccountId {
+	if x != nil {
+		return x.Owner
+	}
+	return nil
+}
+
+func (x *GetFeatureFlagsRequest) GetSignature() *v1.Signature {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+type GetFeatureFlagsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Result GetFeatureFlagsResponse_Result `protobuf:"varint,1,opt,name=result,proto3,enum=code.featureflag.v1.GetFeatureFlagsResponse_Result" json:"result,omitempty"`
+	// Names of the feature flags that are on, in ascending order
+	EnabledFlags []string `protobuf:"bytes,2,rep,name=enabled_flags,json=enabledFlags,proto3" json:"enabled_flags,omitempty"`
+}
+
+func (x *GetFeatureFlagsResponse) Reset() {
+	*x = GetFeatureFlagsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_featureflag_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetFeatureFlagsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFeatureFlagsResponse) ProtoMessage() {}
+
+func (x *GetFeatureFlagsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_featureflag_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFeatureFlagsResponse.ProtoReflect.Descriptor instead.
+func (*GetFeatureFlagsResponse) Descriptor() ([]byte, []int) {
+	return file_featureflag_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetFeatureFlagsResponse) GetResult() GetFeatureFlagsResponse_Result {
+	if x != nil {
+		return x.Result
+	}
+	return GetFeatureFlagsResponse_OK
+}
+
+func (x *GetFeatureFlagsResponse) GetEnabledFlags() []string {
+	if x != nil {
+		return x.EnabledFlags
+	}
+	return nil
+}
+
+var File_featureflag_proto protoreflect.FileDescriptor
+
+var file_featureflag_proto_rawDesc = []byte{
+	0x0a, 0x11, 0x66, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x66, 0x6c, 0x61, 0x67, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x12, 0x13, 0x63, 0x6f, 0x64, 0x65, 0x2e, 0x66, 0x65, 0x61, 0x74, 0x75, 0x72,
+	0x65, 0x66, 0x6c, 0x61, 0x67, 0x2e, 0x76, 0x31, 0x1a, 0x15, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e,
+	0x2f, 0x76, 0x31, 0x2f, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22,
+	0x88, 0x01, 0x0a, 0x16, 0x47, 0x65, 0x74, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x46, 0x6c,
+	0x61, 0x67, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x35, 0x0a, 0x05, 0x6f, 0x77,
+	0x6e, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x63, 0x6f, 0x64, 0x65,
+	0x2e, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x6f, 0x6c, 0x61, 0x6e,
+	0x61, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x49, 0x64, 0x52, 0x05, 0x6f, 0x77, 0x6e, 0x65,
+	0x72, 0x12, 0x37, 0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x63, 0x6f, 0x64, 0x65, 0x2e, 0x63, 0x6f, 0x6d, 0x6d,
+	0x6f, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x52,
+	0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x22, 0x9d, 0x01, 0x0a, 0x17, 0x47,
+	0x65, 0x74, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x46, 0x6c, 0x61, 0x67, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4b, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x33, 0x2e, 0x63, 0x6f, 0x64, 0x65, 0x2e, 0x66, 0x65,
+	0x61, 0x74, 0x75, 0x72, 0x65, 0x66, 0x6c, 0x61, 0x67, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74,
+	0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x46, 0x6c, 0x61, 0x67, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x06, 0x72, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x12, 0x23, 0x0a, 0x0d, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x5f, 0x66,
+	0x6c, 0x61, 0x67, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x65, 0x6e, 0x61, 0x62,
+	0x6c, 0x65, 0x64, 0x46, 0x6c, 0x61, 0x67, 0x73, 0x22, 0x10, 0x0a, 0x06, 0x52, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x12, 0x06, 0x0a, 0x02, 0x4f, 0x4b, 0x10, 0x00, 0x32, 0x7b, 0x0a, 0x0b, 0x46, 0x65,
+	0x61, 0x74, 0x75, 0x72, 0x65, 0x46, 0x6c, 0x61, 0x67, 0x12, 0x6c, 0x0a, 0x0f, 0x47, 0x65, 0x74,
+	0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x46, 0x6c, 0x61, 0x67, 0x73, 0x12, 0x2b, 0x2e, 0x63,
+	0x6f, 0x64, 0x65, 0x2e, 0x66, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x66, 0x6c, 0x61, 0x67, 0x2e,
+	0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x46, 0x6c, 0x61,
+	0x67, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2c, 0x2e, 0x63, 0x6f, 0x64, 0x65,
+	0x2e, 0x66, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x66, 0x6c, 0x61, 0x67, 0x2e, 0x76, 0x31, 0x2e,
+	0x47, 0x65, 0x74, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x46, 0x6c, 0x61, 0x67, 0x73, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x0f, 0x5a, 0x0d, 0x2e, 0x3b, 0x66, 0x65, 0x61,
+	0x74, 0x75, 0x72, 0x65, 0x66, 0x6c, 0x61, 0x67, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_featureflag_proto_rawDescOnce sync.Once
+	file_featureflag_proto_rawDescData = file_featureflag_proto_rawDesc
+)
+
+func file_featureflag_proto_rawDescGZIP() []byte {
+	file_featureflag_proto_rawDescOnce.Do(func() {
+		file_featureflag_proto_rawDescData = protoimpl.X.CompressGZIP(file_featureflag_proto_rawDescData)
+	})
+	return file_featureflag_proto_rawDescData
+}
+
+var file_featureflag_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_featureflag_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_featureflag_proto_goTypes = []interface{}{
+	(GetFeatureFlagsResponse_Result)(0), // 0: code.featureflag.v1.GetFeatureFlagsResponse.Result
+	(*GetFeatureFlagsRequest)(nil),      // 1: code.featureflag.v1.GetFeatureFlagsRequest
+	(*GetFeatureFlagsResponse)(nil),     // 2: code.featureflag.v1.GetFeatureFlagsResponse
+	(*v1.SolanaAccountId)(nil),          // 3: code.common.v1.SolanaAccountId
+	(*v1.Signature)(nil),                // 4: code.common.v1.Signature
+}
+var file_featureflag_proto_depIdxs = []int32{
+	3, // 0: code.featureflag.v1.GetFeatureFlagsRequest.owner:type_name -> code.common.v1.SolanaAccountId
+	4, // 1: code.featureflag.v1.GetFeatureFlagsRequest.signature:type_name -> code.common.v1.Signature
+	0, // 2: code.featureflag.v1.GetFeatureFlagsResponse.result:type_name -> code.featureflag.v1.GetFeatureFlagsResponse.Result
+	1, // 3: code.featureflag.v1.FeatureFlag.GetFeatureFlags:input_type -> code.featureflag.v1.GetFeatureFlagsRequest
+	2, // 4: code.featureflag.v1.FeatureFlag.GetFeatureFlags:output_type -> code.featureflag.v1.GetFeatureFlagsResponse
+	4, // [4:5] is the sub-list for method output_type
+	3, // [3:4] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_featureflag_proto_init() }
+func file_featureflag_proto_init() {
+	if File_featureflag_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_featureflag_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetFeatureFlagsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_featureflag_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetFeatureFlagsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_featureflag_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_featureflag_proto_goTypes,
+		DependencyIndexes: file_featureflag_proto_depIdxs,
+		EnumInfos:         file_featureflag_proto_enumTypes,
+		MessageInfos:      file_featureflag_proto_msgTypes,
+	}.Build()
+	File_featureflag_proto = out.File
+	file_featureflag_proto_rawDesc = nil
+	file_featureflag_proto_goTypes = nil
+	file_featureflag_proto_depIdxs = nil
+}
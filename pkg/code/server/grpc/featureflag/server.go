@@ -0,0 +1,63 @@
+package featureflag
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/code-payments/code-server/pkg/grpc/client"
+	auth_util "github.com/code-payments/code-server/pkg/code/auth"
+	"github.com/code-payments/code-server/pkg/code/common"
+	code_data "github.com/code-payments/code-server/pkg/code/data"
+	"github.com/code-payments/code-server/pkg/code/featureflag"
+	featureflagpb "github.com/code-payments/code-server/pkg/code/server/grpc/featureflag/api/gen"
+)
+
+type server struct {
+	log       *logrus.Entry
+	data      code_data.Provider
+	auth      *auth_util.RPCSignatureVerifier
+	evaluator *featureflag.Evaluator
+
+	featureflagpb.UnimplementedFeatureFlagServer
+}
+
+func NewFeatureFlagServer(data code_data.Provider, evaluator *featureflag.Evaluator, auth *auth_util.RPCSignatureVerifier) featureflagpb.FeatureFlagServer {
+	return &server{
+		log:       logrus.StandardLogger().WithField("type", "featureflag/server"),
+		data:      data,
+		auth:      auth,
+		evaluator: evaluator,
+	}
+}
+
+func (s *server) GetFeatureFlags(ctx context.Context, req *featureflagpb.GetFeatureFlagsRequest) (*featureflagpb.GetFeatureFlagsResponse, error) {
+	log := s.log.WithField("method", "GetFeatureFlags")
+	log = client.InjectLoggingMetadata(ctx, log)
+
+	owner, err := common.NewAccountFromProto(req.Owner)
+	if err != nil {
+		log.WithError(err).Warn("invalid owner account")
+		return nil, status.Error(codes.Internal, "")
+	}
+	log = log.WithField("owner_account", owner.PublicKey().ToBase58())
+
+	signature := req.Signature
+	req.Signature = nil
+	if err := s.auth.Authenticate(ctx, owner, req, signature); err != nil {
+		return nil, err
+	}
+
+	enabledFlags, err := s.evaluator.GetEnabledFlags(ctx, owner)
+	if err != nil {
+		log.WithError(err).Warn("failure evaluating feature flags")
+		return nil, status.Error(codes.Internal, "")
+	}
+
+	return &featureflagpb.GetFeatureFlagsResponse{
+		Result:       featureflagpb.GetFeatureFlagsResponse_OK,
+		EnabledFlags: enabledFlags,
+	}, nil
+}
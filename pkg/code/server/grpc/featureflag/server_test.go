@@ -0,0 +1,116 @@
+package featureflag
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+
+	commonpb "github.com/code-payments/code-protobuf-api/generated/go/common/v1"
+
+	"github.com/code-payments/code-server/pkg/testutil"
+	auth_util "github.com/code-payments/code-server/pkg/code/auth"
+	"github.com/code-payments/code-server/pkg/code/common"
+	code_data "github.com/code-payments/code-server/pkg/code/data"
+	datatest "github.com/code-payments/code-server/pkg/code/data/test"
+	featureflag_data "github.com/code-payments/code-server/pkg/code/data/featureflag"
+	"github.com/code-payments/code-server/pkg/code/featureflag"
+	featureflagpb "github.com/code-payments/code-server/pkg/code/server/grpc/featureflag/api/gen"
+)
+
+func TestGetFeatureFlags_HappyPath(t *testing.T) {
+	env, cleanup := setup(t)
+	defer cleanup()
+
+	owner := testutil.NewRandomAccount(t)
+
+	req := &featureflagpb.GetFeatureFlagsRequest{
+		Owner: owner.ToProto(),
+	}
+	req.Signature = signProtoMessage(t, req, owner, false)
+
+	resp, err := env.client.GetFeatureFlags(env.ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, featureflagpb.GetFeatureFlagsResponse_OK, resp.Result)
+	assert.Empty(t, resp.EnabledFlags)
+
+	for _, record := range []*featureflag_data.Record{
+		{Name: "flag_b", IsEnabled: true, RolloutPercentage: 100},
+		{Name: "flag_a", IsEnabled: true, RolloutPercentage: 100},
+		{Name: "disabled", IsEnabled: false, RolloutPercentage: 100},
+		{Name: "not_rolled_out", IsEnabled: true, RolloutPercentage: 0},
+	} {
+		require.NoError(t, env.data.PutFeatureFlag(env.ctx, record))
+	}
+
+	env.server.evaluator = featureflag.NewEvaluator(env.data)
+
+	resp, err = env.client.GetFeatureFlags(env.ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, featureflagpb.GetFeatureFlagsResponse_OK, resp.Result)
+	assert.Equal(t, []string{"flag_a", "flag_b"}, resp.EnabledFlags)
+}
+
+func TestUnauthorizedAccess(t *testing.T) {
+	env, cleanup := setup(t)
+	defer cleanup()
+
+	owner := testutil.NewRandomAccount(t)
+
+	req := &featureflagpb.GetFeatureFlagsRequest{
+		Owner: owner.ToProto(),
+	}
+	req.Signature = signProtoMessage(t, req, owner, true)
+
+	_, err := env.client.GetFeatureFlags(env.ctx, req)
+	testutil.AssertStatusErrorWithCode(t, err, codes.Unauthenticated)
+}
+
+type testEnv struct {
+	ctx    context.Context
+	client featureflagpb.FeatureFlagClient
+	server *server
+	data   code_data.Provider
+}
+
+func setup(t *testing.T) (env *testEnv, cleanup func()) {
+	conn, serv, err := testutil.NewServer()
+	require.NoError(t, err)
+
+	env = &testEnv{
+		ctx:    context.Background(),
+		client: featureflagpb.NewFeatureFlagClient(conn),
+		data:   datatest.NewDataProvider(),
+	}
+
+	s := NewFeatureFlagServer(env.data, featureflag.NewEvaluator(env.data), auth_util.NewRPCSignatureVerifier(env.data))
+	env.server = s.(*server)
+
+	serv.RegisterService(func(server *grpc.Server) {
+		featureflagpb.RegisterFeatureFlagServer(server, s)
+	})
+
+	cleanup, err = serv.Serve()
+	require.NoError(t, err)
+	return env, cleanup
+}
+
+func signProtoMessage(t *testing.T, msg proto.Message, signer *common.Account, simulateInvalidSignature bool) *commonpb.Signature {
+	msgBytes, err := proto.Marshal(msg)
+	require.NoError(t, err)
+
+	if simulateInvalidSignature {
+		signer = testutil.NewRandomAccount(t)
+	}
+
+	signature, err := signer.Sign(msgBytes)
+	require.NoError(t, err)
+
+	return &commonpb.Signature{
+		Value: signature,
+	}
+}
@@ -97,6 +97,7 @@ type RequestToReceiveBillMessageHandler struct {
 	data                 code_data.Provider
 	rpcSignatureVerifier *auth.RPCSignatureVerifier
 	domainVerifier       thirdparty.DomainVerifier
+	limits               *limit.Resolver
 
 	recordAlreadyExists       bool
 	paymentRecordRecordToSave *paymentrequest.Record
@@ -106,12 +107,14 @@ func NewRequestToReceiveBillMessageHandler(
 	conf *conf, data code_data.Provider,
 	rpcSignatureVerifier *auth.RPCSignatureVerifier,
 	domainVerifier thirdparty.DomainVerifier,
+	limits *limit.Resolver,
 ) MessageHandler {
 	return &RequestToReceiveBillMessageHandler{
 		conf:                 conf,
 		data:                 data,
 		rpcSignatureVerifier: rpcSignatureVerifier,
 		domainVerifier:       domainVerifier,
+		limits:               limits,
 	}
 }
 
@@ -227,12 +230,18 @@ func (h *RequestToReceiveBillMessageHandler) Validate(ctx context.Context, rende
 		//           or an external account (for real production use cases)
 		//
 
+		var requestorOwner *common.Account
 		accountInfoRecord, err := h.data.GetAccountInfoByTokenAddress(ctx, requestorAccount.PublicKey().ToBase58())
 		switch err {
 		case nil:
 			if accountInfoRecord.AccountType != commonpb.AccountType_PRIMARY {
 				return newMessageValidationError("requestor account must be a primary account for trials using a code account")
 			}
+
+			requestorOwner, err = common.NewAccountFromPublicKeyString(accountInfoRecord.OwnerAccount)
+			if err != nil {
+				return err
+			}
 		case account.ErrAccountInfoNotFound:
 			if !h.conf.disableBlockchainChecks.Get(ctx) {
 				err := validateExternalKinTokenAccountWithinMessage(ctx, h.data, requestorAccount)
@@ -248,8 +257,18 @@ func (h *RequestToReceiveBillMessageHandler) Validate(ctx context.Context, rende
 		// Part 2.2: Exchange data validation
 		//
 
-		limits, ok := limit.MicroPaymentLimits[currency]
-		if !ok {
+		// External accounts have no owner to assign a tier or override to, so
+		// they always get the default tier limits.
+		var limits limit.MicroPaymentLimit
+		var ok bool
+		if requestorOwner != nil {
+			limits, ok, err = h.limits.GetMicroPaymentLimit(ctx, requestorOwner, currency)
+		} else {
+			limits, ok, err = h.limits.GetDefaultMicroPaymentLimit(ctx, currency)
+		}
+		if err != nil {
+			return err
+		} else if !ok {
 			return newMessageValidationErrorf("%s currency is not currently supported", currency)
 		} else if nativeAmount > limits.Max {
 			return newMessageValidationErrorf("%s currency has a maximum amount of %.2f", currency, limits.Max)
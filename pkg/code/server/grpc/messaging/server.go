@@ -28,6 +28,7 @@ import (
 	"github.com/code-payments/code-server/pkg/code/auth"
 	"github.com/code-payments/code-server/pkg/code/common"
 	code_data "github.com/code-payments/code-server/pkg/code/data"
+	"github.com/code-payments/code-server/pkg/code/limit"
 	"github.com/code-payments/code-server/pkg/code/data/messaging"
 	"github.com/code-payments/code-server/pkg/code/data/rendezvous"
 	"github.com/code-payments/code-server/pkg/code/thirdparty"
@@ -59,6 +60,8 @@ type server struct {
 
 	rpcSignatureVerifier *auth.RPCSignatureVerifier
 
+	limits *limit.Resolver
+
 	broadcastAddress string
 
 	messagingpb.UnimplementedMessagingServer
@@ -103,6 +106,7 @@ func NewMessagingClientAndServer(
 		domainVerifier:             thirdparty.VerifyDomainNameOwnership,
 		rendezvousFirstSeenAtCache: cache.NewCache(100_000),
 		rpcSignatureVerifier:       rpcSignatureVerifier,
+		limits:                     limit.NewResolver(data),
 		broadcastAddress:           broadcastAddress,
 	}
 }
@@ -680,7 +684,7 @@ func (s *server) SendMessage(ctx context.Context, req *messagingpb.SendMessageRe
 
 	case *messagingpb.Message_RequestToReceiveBill:
 		log = log.WithField("message_type", "request_to_receive_bill")
-		messageHandler = NewRequestToReceiveBillMessageHandler(s.conf, s.data, s.rpcSignatureVerifier, s.domainVerifier)
+		messageHandler = NewRequestToReceiveBillMessageHandler(s.conf, s.data, s.rpcSignatureVerifier, s.domainVerifier, s.limits)
 	case *messagingpb.Message_ClientRejectedPayment:
 		log = log.WithField("message_type", "client_rejected_payment")
 		messageHandler = NewClientRejectedPaymentMessageHandler()
@@ -11,6 +11,7 @@ import (
 
 	messagingpb "github.com/code-payments/code-protobuf-api/generated/go/messaging/v1"
 
+	currency_lib "github.com/code-payments/code-server/pkg/currency"
 	"github.com/code-payments/code-server/pkg/testutil"
 )
 
@@ -498,6 +499,30 @@ func TestSendMessage_RequestToReceiveBill_FiatValue_Validation(t *testing.T) {
 	env.server1.assertPaymentRequestRecordNotSaved(t, rendezvousKey)
 }
 
+func TestSendMessage_RequestToReceiveBill_FiatValue_ResolvedLimits(t *testing.T) {
+	env, cleanup := setup(t, false)
+	defer cleanup()
+
+	env.server1.putDefaultTierMicroPaymentLimit(t, currency_lib.USD, 0.02, 2.00)
+
+	for _, usePrimaryAccount := range []bool{true, false} {
+		rendezvousKey := testutil.NewRandomAccount(t)
+		env.client1.resetConf()
+		env.client1.conf.simulateLargeNativeAmount = true
+		sendMessageCall := env.client1.sendRequestToReceiveFiatBillMessage(t, rendezvousKey, usePrimaryAccount, true)
+		sendMessageCall.requireSuccess(t)
+		env.server1.assertPaymentRequestRecordSaved(t, rendezvousKey, sendMessageCall.req.Message.GetRequestToReceiveBill())
+
+		rendezvousKey = testutil.NewRandomAccount(t)
+		env.client1.resetConf()
+		env.client1.conf.simulateSmallNativeAmount = true
+		sendMessageCall = env.client1.sendRequestToReceiveFiatBillMessage(t, rendezvousKey, usePrimaryAccount, true)
+		sendMessageCall.assertInvalidMessageError(t, "usd currency has a minimum amount of 0.02")
+		env.server1.assertNoMessages(t, rendezvousKey)
+		env.server1.assertPaymentRequestRecordNotSaved(t, rendezvousKey)
+	}
+}
+
 func TestSendMessage_RequestToLogin_HappyPath(t *testing.T) {
 	env, cleanup := setup(t, false)
 	defer cleanup()
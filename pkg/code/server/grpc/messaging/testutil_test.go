@@ -20,14 +20,17 @@ import (
 	messagingpb "github.com/code-payments/code-protobuf-api/generated/go/messaging/v1"
 	transactionpb "github.com/code-payments/code-protobuf-api/generated/go/transaction/v2"
 
+	currency_lib "github.com/code-payments/code-server/pkg/currency"
 	"github.com/code-payments/code-server/pkg/kin"
 	"github.com/code-payments/code-server/pkg/pointer"
 	"github.com/code-payments/code-server/pkg/testutil"
 	"github.com/code-payments/code-server/pkg/code/auth"
 	"github.com/code-payments/code-server/pkg/code/common"
 	code_data "github.com/code-payments/code-server/pkg/code/data"
+	datatest "github.com/code-payments/code-server/pkg/code/data/test"
 	"github.com/code-payments/code-server/pkg/code/data/account"
 	"github.com/code-payments/code-server/pkg/code/data/currency"
+	"github.com/code-payments/code-server/pkg/code/data/limit"
 	"github.com/code-payments/code-server/pkg/code/data/messaging"
 	"github.com/code-payments/code-server/pkg/code/data/paymentrequest"
 	"github.com/code-payments/code-server/pkg/code/data/rendezvous"
@@ -49,7 +52,7 @@ func setup(t *testing.T, enableMultiServer bool) (env testEnv, cleanup func()) {
 	conn2, serv2, err := testutil.NewServer()
 	require.NoError(t, err)
 
-	data := code_data.NewTestDataProvider()
+	data := datatest.NewDataProvider()
 
 	env.client1 = &clientEnv{
 		ctx:              context.Background(),
@@ -177,6 +180,22 @@ func (s *serverEnv) assertPaymentRequestRecordNotSaved(t *testing.T, rendezvousK
 	assert.Equal(t, paymentrequest.ErrPaymentRequestNotFound, err)
 }
 
+func (s *serverEnv) putDefaultTierMicroPaymentLimit(t *testing.T, currency currency_lib.Code, min, max float64) {
+	require.NoError(t, s.server.data.PutLimits(s.ctx, &limit.Record{
+		Tier: limit.DefaultTier,
+
+		Currency: currency,
+
+		SendPerTransaction: 250,
+		SendDaily:          1000,
+
+		MicroPaymentMin: min,
+		MicroPaymentMax: max,
+
+		EffectiveAt: time.Now().Add(-time.Minute),
+	}))
+}
+
 func (s *serverEnv) assertInitialRendezvousRecordSaved(t *testing.T, rendezvousKey *common.Account) {
 	start := time.Now()
 
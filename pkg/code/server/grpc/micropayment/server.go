@@ -30,7 +30,7 @@ import (
 	"github.com/code-payments/code-server/pkg/code/data/paymentrequest"
 	"github.com/code-payments/code-server/pkg/code/data/paywall"
 	"github.com/code-payments/code-server/pkg/code/data/webhook"
-	"github.com/code-payments/code-server/pkg/code/limit"
+	limit_util "github.com/code-payments/code-server/pkg/code/limit"
 )
 
 const (
@@ -44,6 +44,8 @@ type microPaymentServer struct {
 
 	auth *auth_util.RPCSignatureVerifier
 
+	limits *limit_util.Resolver
+
 	micropaymentpb.UnimplementedMicroPaymentServer
 }
 
@@ -52,9 +54,10 @@ func NewMicroPaymentServer(
 	auth *auth_util.RPCSignatureVerifier,
 ) micropaymentpb.MicroPaymentServer {
 	return &microPaymentServer{
-		log:  logrus.StandardLogger().WithField("type", "micropayment/v1/server"),
-		data: data,
-		auth: auth,
+		log:    logrus.StandardLogger().WithField("type", "micropayment/v1/server"),
+		data:   data,
+		auth:   auth,
+		limits: limit_util.NewResolver(data),
 	}
 }
 
@@ -227,8 +230,11 @@ func (s *microPaymentServer) Codify(ctx context.Context, req *micropaymentpb.Cod
 		}, nil
 	}
 
-	limits, ok := limit.MicroPaymentLimits[currency_lib.Code(req.Currency)]
-	if !ok {
+	limits, ok, err := s.limits.GetMicroPaymentLimit(ctx, owner, currency_lib.Code(req.Currency))
+	if err != nil {
+		log.WithError(err).Warn("failure resolving micro payment limits")
+		return nil, status.Error(codes.Internal, "")
+	} else if !ok {
 		return &micropaymentpb.CodifyResponse{
 			Result: micropaymentpb.CodifyResponse_UNSUPPORTED_CURRENCY,
 		}, nil
@@ -25,6 +25,7 @@ import (
 	"github.com/code-payments/code-server/pkg/testutil"
 	auth_util "github.com/code-payments/code-server/pkg/code/auth"
 	code_data "github.com/code-payments/code-server/pkg/code/data"
+	datatest "github.com/code-payments/code-server/pkg/code/data/test"
 	"github.com/code-payments/code-server/pkg/code/data/account"
 	"github.com/code-payments/code-server/pkg/code/data/intent"
 	"github.com/code-payments/code-server/pkg/code/data/messaging"
@@ -534,7 +535,7 @@ func setup(t *testing.T) (env testEnv, cleanup func()) {
 
 	env.ctx = context.Background()
 	env.client = micropaymentpb.NewMicroPaymentClient(conn)
-	env.data = code_data.NewTestDataProvider()
+	env.data = datatest.NewDataProvider()
 
 	s := NewMicroPaymentServer(env.data, auth_util.NewRPCSignatureVerifier(env.data))
 	env.server = s.(*microPaymentServer)
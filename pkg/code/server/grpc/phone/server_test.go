@@ -17,6 +17,7 @@ import (
 	"github.com/code-payments/code-server/pkg/code/antispam"
 	"github.com/code-payments/code-server/pkg/code/auth"
 	code_data "github.com/code-payments/code-server/pkg/code/data"
+	datatest "github.com/code-payments/code-server/pkg/code/data/test"
 	"github.com/code-payments/code-server/pkg/code/data/account"
 	"github.com/code-payments/code-server/pkg/code/data/phone"
 	memory_device_verifier "github.com/code-payments/code-server/pkg/device/memory"
@@ -40,13 +41,12 @@ func setup(t *testing.T) (env testEnv, cleanup func()) {
 
 	env.ctx = context.Background()
 	env.client = phonepb.NewPhoneVerificationClient(conn)
-	env.data = code_data.NewTestDataProvider()
+	env.data = datatest.NewDataProvider()
 	env.verifier = memory_phone_client.NewVerifier()
 
 	disabledAntispamGuard := antispam.NewGuard(
 		env.data,
 		memory_device_verifier.NewMemoryDeviceVerifier(),
-		nil,
 		antispam.WithPhoneVerificationsPerInterval(100),
 		antispam.WithTimePerSmsVerificationCodeSend(0),
 		antispam.WithTimePerSmsVerificationCheck(0),
@@ -173,7 +173,6 @@ func TestSendVerificationCode_AntispamGuard_TimePerSmsVerificationCodeSend(t *te
 	env.server.guard = antispam.NewGuard(
 		env.data,
 		memory_device_verifier.NewMemoryDeviceVerifier(),
-		nil,
 		antispam.WithPhoneVerificationsPerInterval(10),
 		antispam.WithTimePerSmsVerificationCodeSend(5*time.Second),
 	)
@@ -210,7 +209,6 @@ func TestSendVerificationCode_AntispamGuard_NewVerificationsLimit(t *testing.T)
 	env.server.guard = antispam.NewGuard(
 		env.data,
 		memory_device_verifier.NewMemoryDeviceVerifier(),
-		nil,
 		antispam.WithPhoneVerificationsPerInterval(1),
 		antispam.WithTimePerSmsVerificationCodeSend(0),
 	)
@@ -252,7 +250,6 @@ func TestSendVerificationCode_AntispamGuard_DeviceVerification(t *testing.T) {
 	env.server.guard = antispam.NewGuard(
 		env.data,
 		memory_device_verifier.NewMemoryDeviceVerifier(),
-		nil,
 		antispam.WithPhoneVerificationsPerInterval(1),
 		antispam.WithTimePerSmsVerificationCodeSend(0),
 	)
@@ -431,7 +428,6 @@ func TestCheckVerificationCode_AntispamGuard(t *testing.T) {
 	env.server.guard = antispam.NewGuard(
 		env.data,
 		memory_device_verifier.NewMemoryDeviceVerifier(),
-		nil,
 		antispam.WithTimePerSmsVerificationCheck(5*time.Second),
 	)
 
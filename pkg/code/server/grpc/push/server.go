@@ -16,6 +16,7 @@ import (
 	"github.com/code-payments/code-server/pkg/code/data/push"
 	"github.com/code-payments/code-server/pkg/code/data/user"
 	"github.com/code-payments/code-server/pkg/grpc/client"
+	"github.com/code-payments/code-server/pkg/pointer"
 	push_lib "github.com/code-payments/code-server/pkg/push"
 )
 
@@ -111,6 +112,9 @@ func (s *pushServer) AddToken(ctx context.Context, req *pushpb.AddTokenRequest)
 	if req.AppInstall != nil {
 		record.AppInstallId = &req.AppInstall.Value
 	}
+	if locale, err := client.GetLocale(ctx); err == nil {
+		record.Locale = pointer.String(locale.String())
+	}
 
 	err = s.data.PutPushToken(ctx, record)
 	if err != nil && err != push.ErrTokenExists {
@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/protobuf/proto"
 
 	commonpb "github.com/code-payments/code-protobuf-api/generated/go/common/v1"
@@ -18,6 +19,7 @@ import (
 	"github.com/code-payments/code-server/pkg/code/auth"
 	"github.com/code-payments/code-server/pkg/code/common"
 	code_data "github.com/code-payments/code-server/pkg/code/data"
+	datatest "github.com/code-payments/code-server/pkg/code/data/test"
 	"github.com/code-payments/code-server/pkg/code/data/push"
 	"github.com/code-payments/code-server/pkg/code/data/user"
 	"github.com/code-payments/code-server/pkg/code/data/user/storage"
@@ -50,6 +52,7 @@ func TestAddToken_HappyPath_AndroidToken(t *testing.T) {
 	assert.True(t, record.IsValid)
 	require.NotNil(t, record.AppInstallId)
 	assert.Equal(t, req.AppInstall.Value, *record.AppInstallId)
+	assert.Nil(t, record.Locale)
 }
 
 func TestAddToken_HappyPath_APNSToken(t *testing.T) {
@@ -79,6 +82,31 @@ func TestAddToken_HappyPath_APNSToken(t *testing.T) {
 	assert.Equal(t, req.AppInstall.Value, *record.AppInstallId)
 }
 
+func TestAddToken_HappyPath_Locale(t *testing.T) {
+	env, cleanup := setup(t)
+	defer cleanup()
+
+	client := newIOSClient(t, env)
+
+	ownerAccount := testutil.NewRandomAccount(t)
+
+	containerID := generateNewDataContainer(t, env, ownerAccount)
+
+	ctx := metadata.AppendToOutgoingContext(env.ctx, "accept-language", "es-MX,es;q=0.9,en;q=0.8")
+
+	req := makeAddFcmApnsTokenReq(t, ownerAccount, *containerID)
+	resp, err := client.AddToken(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, pushpb.AddTokenResponse_OK, resp.Result)
+
+	records, err := env.data.GetAllValidPushTokensdByDataContainer(env.ctx, containerID)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+
+	require.NotNil(t, records[0].Locale)
+	assert.Equal(t, "es-MX", *records[0].Locale)
+}
+
 func TestAddToken_HappyPath_MultipleTokens(t *testing.T) {
 	env, cleanup := setup(t)
 	defer cleanup()
@@ -265,7 +293,7 @@ func setup(t *testing.T) (env testEnv, cleanup func()) {
 
 	env.ctx = context.Background()
 	env.target = conn.Target()
-	env.data = code_data.NewTestDataProvider()
+	env.data = datatest.NewDataProvider()
 
 	s := NewPushServer(env.data, auth.NewRPCSignatureVerifier(env.data), memory_push.NewPushProvider())
 	env.server = s.(*pushServer)
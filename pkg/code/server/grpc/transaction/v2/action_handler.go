@@ -224,7 +224,7 @@ type CloseEmptyAccountActionHandler struct {
 	intentType       intent.Type
 }
 
-func NewCloseEmptyAccountActionHandler(intentType intent.Type, protoAction *transactionpb.CloseEmptyAccountAction) (CreateActionHandler, error) {
+func NewCloseEmptyAccountActionHandler(ctx context.Context, data code_data.Provider, intentType intent.Type, protoAction *transactionpb.CloseEmptyAccountAction) (CreateActionHandler, error) {
 	authority, err := common.NewAccountFromProto(protoAction.Authority)
 	if err != nil {
 		return nil, err
@@ -234,7 +234,7 @@ func NewCloseEmptyAccountActionHandler(intentType intent.Type, protoAction *tran
 	if intentType == intent.MigrateToPrivacy2022 {
 		dataVersion = timelock_token_v1.DataVersionLegacy
 	}
-	timelockAccounts, err := authority.GetTimelockAccounts(dataVersion)
+	timelockAccounts, err := authority.ResolveTimelockAccounts(ctx, data, dataVersion)
 	if err != nil {
 		return nil, err
 	}
@@ -309,13 +309,13 @@ type CloseDormantAccountActionHandler struct {
 	destination *common.Account
 }
 
-func NewCloseDormantAccountActionHandler(protoAction *transactionpb.CloseDormantAccountAction) (CreateActionHandler, error) {
+func NewCloseDormantAccountActionHandler(ctx context.Context, data code_data.Provider, protoAction *transactionpb.CloseDormantAccountAction) (CreateActionHandler, error) {
 	sourceAuthority, err := common.NewAccountFromProto(protoAction.Authority)
 	if err != nil {
 		return nil, err
 	}
 
-	source, err := sourceAuthority.GetTimelockAccounts(timelock_token_v1.DataVersion1)
+	source, err := sourceAuthority.ResolveTimelockAccounts(ctx, data, timelock_token_v1.DataVersion1)
 	if err != nil {
 		return nil, err
 	}
@@ -422,13 +422,13 @@ type NoPrivacyTransferActionHandler struct {
 	isFeePayment bool // Internally, the mechanics of a fee payment are exactly the same
 }
 
-func NewNoPrivacyTransferActionHandler(protoAction *transactionpb.NoPrivacyTransferAction) (CreateActionHandler, error) {
+func NewNoPrivacyTransferActionHandler(ctx context.Context, data code_data.Provider, protoAction *transactionpb.NoPrivacyTransferAction) (CreateActionHandler, error) {
 	sourceAuthority, err := common.NewAccountFromProto(protoAction.Authority)
 	if err != nil {
 		return nil, err
 	}
 
-	source, err := sourceAuthority.GetTimelockAccounts(timelock_token_v1.DataVersion1)
+	source, err := sourceAuthority.ResolveTimelockAccounts(ctx, data, timelock_token_v1.DataVersion1)
 	if err != nil {
 		return nil, err
 	}
@@ -446,13 +446,13 @@ func NewNoPrivacyTransferActionHandler(protoAction *transactionpb.NoPrivacyTrans
 	}, nil
 }
 
-func NewFeePaymentActionHandler(protoAction *transactionpb.FeePaymentAction, feeCollector *common.Account) (CreateActionHandler, error) {
+func NewFeePaymentActionHandler(ctx context.Context, data code_data.Provider, protoAction *transactionpb.FeePaymentAction, feeCollector *common.Account) (CreateActionHandler, error) {
 	sourceAuthority, err := common.NewAccountFromProto(protoAction.Authority)
 	if err != nil {
 		return nil, err
 	}
 
-	source, err := sourceAuthority.GetTimelockAccounts(timelock_token_v1.DataVersion1)
+	source, err := sourceAuthority.ResolveTimelockAccounts(ctx, data, timelock_token_v1.DataVersion1)
 	if err != nil {
 		return nil, err
 	}
@@ -546,7 +546,7 @@ type NoPrivacyWithdrawActionHandler struct {
 	intentType  intent.Type
 }
 
-func NewNoPrivacyWithdrawActionHandler(intentType intent.Type, protoAction *transactionpb.NoPrivacyWithdrawAction) (CreateActionHandler, error) {
+func NewNoPrivacyWithdrawActionHandler(ctx context.Context, data code_data.Provider, intentType intent.Type, protoAction *transactionpb.NoPrivacyWithdrawAction) (CreateActionHandler, error) {
 	sourceAuthority, err := common.NewAccountFromProto(protoAction.Authority)
 	if err != nil {
 		return nil, err
@@ -556,7 +556,7 @@ func NewNoPrivacyWithdrawActionHandler(intentType intent.Type, protoAction *tran
 	if intentType == intent.MigrateToPrivacy2022 {
 		dataVersion = timelock_token_v1.DataVersionLegacy
 	}
-	source, err := sourceAuthority.GetTimelockAccounts(dataVersion)
+	source, err := sourceAuthority.ResolveTimelockAccounts(ctx, data, dataVersion)
 	if err != nil {
 		return nil, err
 	}
@@ -714,7 +714,7 @@ func NewTemporaryPrivacyTransferActionHandler(
 		return nil, err
 	}
 
-	h.source, err = authority.GetTimelockAccounts(timelock_token_v1.DataVersion1)
+	h.source, err = authority.ResolveTimelockAccounts(ctx, data, timelock_token_v1.DataVersion1)
 	if err != nil {
 		return nil, err
 	}
@@ -945,7 +945,7 @@ func NewPermanentPrivacyUpgradeActionHandler(
 		return nil, err
 	}
 
-	h.source, err = authority.GetTimelockAccounts(timelock_token_v1.DataVersion1)
+	h.source, err = authority.ResolveTimelockAccounts(ctx, data, timelock_token_v1.DataVersion1)
 	if err != nil {
 		return nil, err
 	}
@@ -13,39 +13,27 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
-	chatpb "github.com/code-payments/code-protobuf-api/generated/go/chat/v1"
 	commonpb "github.com/code-payments/code-protobuf-api/generated/go/common/v1"
 	transactionpb "github.com/code-payments/code-protobuf-api/generated/go/transaction/v2"
 
 	"github.com/code-payments/code-server/pkg/cache"
-	"github.com/code-payments/code-server/pkg/code/balance"
-	chat_util "github.com/code-payments/code-server/pkg/code/chat"
 	"github.com/code-payments/code-server/pkg/code/common"
 	"github.com/code-payments/code-server/pkg/code/data/account"
-	"github.com/code-payments/code-server/pkg/code/data/action"
-	"github.com/code-payments/code-server/pkg/code/data/chat"
+	"github.com/code-payments/code-server/pkg/code/data/campaign"
 	"github.com/code-payments/code-server/pkg/code/data/event"
-	"github.com/code-payments/code-server/pkg/code/data/fulfillment"
 	"github.com/code-payments/code-server/pkg/code/data/intent"
-	"github.com/code-payments/code-server/pkg/code/data/nonce"
 	event_util "github.com/code-payments/code-server/pkg/code/event"
 	exchange_rate_util "github.com/code-payments/code-server/pkg/code/exchangerate"
-	push_util "github.com/code-payments/code-server/pkg/code/push"
-	"github.com/code-payments/code-server/pkg/code/transaction"
 	currency_lib "github.com/code-payments/code-server/pkg/currency"
 	"github.com/code-payments/code-server/pkg/database/query"
 	"github.com/code-payments/code-server/pkg/grpc/client"
 	"github.com/code-payments/code-server/pkg/kin"
-	"github.com/code-payments/code-server/pkg/pointer"
 	timelock_token_v1 "github.com/code-payments/code-server/pkg/solana/timelock/v1"
 )
 
-// This is a quick and dirty file to get an initial airdrop feature out
-//
-// Important Note: If this code is executed elsewhere (eg. a worker), we'll want to either
-// use an actual client that hits SubmitIntent directly, or update this code to for an
-// intenral server process (eg. by using the correct nonce pool to avoid race conditions
-// without distributed locks).
+// Airdrops are payouts from campaigns, which define eligibility rules, amounts,
+// budgets and date windows. The RPC path only validates eligibility and enqueues
+// a payout. The campaign worker creates the intent that moves the funds.
 //
 // Important Note: We generally assumes 1 account per phone number for simplicity
 
@@ -58,9 +46,7 @@ const (
 )
 
 var (
-	ErrInvalidAirdropTarget          = errors.New("invalid airdrop target owner account")
-	ErrInsufficientAirdropperBalance = errors.New("insufficient airdropper balance")
-	ErrIneligibleForAirdrop          = errors.New("user isn't eligible for airdrop")
+	ErrInvalidAirdropTarget = errors.New("invalid airdrop target owner account")
 )
 
 var (
@@ -130,8 +116,31 @@ func (s *transactionServer) Airdrop(ctx context.Context, req *transactionpb.Aird
 		}, nil
 	}
 
+	campaignRecord, err := s.data.GetCampaign(ctx, s.conf.welcomeBonusCampaign.Get(ctx))
+	if err == campaign.ErrCampaignNotFound {
+		return &transactionpb.AirdropResponse{
+			Result: transactionpb.AirdropResponse_UNAVAILABLE,
+		}, nil
+	} else if err != nil {
+		log.WithError(err).Warn("failure getting campaign")
+		return nil, status.Error(codes.Internal, "")
+	}
+	log = log.WithField("campaign", campaignRecord.Name)
+
+	// The payout may be queued, but not yet processed by the campaign worker
+	_, err = s.data.GetCampaignPayout(ctx, campaignRecord.Name, owner.PublicKey().ToBase58())
+	if err == nil {
+		cachedAirdropStatus.Insert(cacheKey, true, 1)
+		return &transactionpb.AirdropResponse{
+			Result: transactionpb.AirdropResponse_ALREADY_CLAIMED,
+		}, nil
+	} else if err != campaign.ErrPayoutNotFound {
+		log.WithError(err).Warn("failure checking if campaign payout was already queued")
+		return nil, status.Error(codes.Internal, "")
+	}
+
 	if !s.conf.disableAntispamChecks.Get(ctx) {
-		allow, err := s.antispamGuard.AllowWelcomeBonus(ctx, owner)
+		allow, err := s.antispamGuard.AllowCampaignPayout(ctx, owner, campaignRecord)
 		if err != nil {
 			log.WithError(err).Warn("failure performing antispam check")
 			return nil, status.Error(codes.Internal, "")
@@ -142,29 +151,34 @@ func (s *transactionServer) Airdrop(ctx context.Context, req *transactionpb.Aird
 		}
 	}
 
-	intentRecord, err := s.airdrop(ctx, newIntentId, owner, AirdropTypeGetFirstKin)
+	payoutRecord, err := s.enqueueCampaignPayout(ctx, campaignRecord, newIntentId, owner, AirdropTypeGetFirstKin, currency_lib.USD)
 	switch err {
 	case nil:
-	case ErrInsufficientAirdropperBalance, ErrInvalidAirdropTarget, ErrIneligibleForAirdrop:
+	case campaign.ErrPayoutExists:
+		cachedAirdropStatus.Insert(cacheKey, true, 1)
+		return &transactionpb.AirdropResponse{
+			Result: transactionpb.AirdropResponse_ALREADY_CLAIMED,
+		}, nil
+	case ErrInvalidAirdropTarget, campaign.ErrBudgetExceeded:
 		return &transactionpb.AirdropResponse{
 			Result: transactionpb.AirdropResponse_UNAVAILABLE,
 		}, nil
 	default:
-		log.WithError(err).Warn("failure airdropping account")
+		log.WithError(err).Warn("failure enqueueing campaign payout")
 		return nil, status.Error(codes.Internal, "")
 	}
 
-	log.Debug("airdropped")
+	log.Debug("airdrop payout enqueued")
 
 	cachedAirdropStatus.Insert(cacheKey, true, 1)
 
 	return &transactionpb.AirdropResponse{
 		Result: transactionpb.AirdropResponse_OK,
 		ExchangeData: &transactionpb.ExchangeData{
-			Currency:     string(intentRecord.SendPublicPaymentMetadata.ExchangeCurrency),
-			ExchangeRate: intentRecord.SendPublicPaymentMetadata.ExchangeRate,
-			NativeAmount: intentRecord.SendPublicPaymentMetadata.NativeAmount,
-			Quarks:       intentRecord.SendPublicPaymentMetadata.Quantity,
+			Currency:     string(payoutRecord.ExchangeCurrency),
+			ExchangeRate: payoutRecord.ExchangeRate,
+			NativeAmount: payoutRecord.NativeAmount,
+			Quarks:       payoutRecord.Quantity,
 		},
 	}, nil
 }
@@ -297,6 +311,14 @@ func (s *transactionServer) maybeAirdropForSendingUserTheirFirstKin(ctx context.
 		return nil
 	}
 
+	campaignRecord, err := s.data.GetCampaign(ctx, s.conf.referralBonusCampaign.Get(ctx))
+	if err == campaign.ErrCampaignNotFound {
+		return nil
+	} else if err != nil {
+		log.WithError(err).Warn("failure getting campaign")
+		return err
+	}
+
 	if !s.conf.disableAntispamChecks.Get(ctx) {
 		allow, err := s.antispamGuard.AllowReferralBonus(
 			ctx,
@@ -313,48 +335,55 @@ func (s *transactionServer) maybeAirdropForSendingUserTheirFirstKin(ctx context.
 		} else if !allow {
 			return nil
 		}
+
+		allow, err = s.antispamGuard.AllowCampaignPayout(ctx, ownerToAirdrop, campaignRecord)
+		if err != nil {
+			log.WithError(err).Warn("failure performing antispam check")
+			return err
+		} else if !allow {
+			return nil
+		}
 	}
 
 	intentId := GetNewAirdropIntentId(AirdropTypeGiveFirstKin, intentRecord.IntentId)
-	_, err = s.airdrop(ctx, intentId, ownerToAirdrop, AirdropTypeGiveFirstKin)
-	if err != nil {
-		log.Warn("failure giving airdrop")
+	_, err = s.enqueueCampaignPayout(ctx, campaignRecord, intentId, ownerToAirdrop, AirdropTypeGiveFirstKin, exchangedIn)
+	switch err {
+	case nil, campaign.ErrPayoutExists, campaign.ErrBudgetExceeded, ErrInvalidAirdropTarget:
+	default:
+		log.WithError(err).Warn("failure enqueueing campaign payout")
 		return err
 	}
 
 	return nil
 }
 
-// airdrop gives Kin airdrops denominated in USD for performing certain
-// actions in the Code app. This funciton is idempotent with the given
-// intent ID.
-func (s *transactionServer) airdrop(ctx context.Context, intentId string, owner *common.Account, airdropType AirdropType) (*intent.Record, error) {
+// enqueueCampaignPayout queues a payout for the campaign worker, which creates the
+// intent with the provided ID. The amount is in the preferred currency when the
+// campaign defines one, and USD otherwise. Eligibility must be checked beforehand.
+func (s *transactionServer) enqueueCampaignPayout(
+	ctx context.Context,
+	campaignRecord *campaign.Record,
+	intentId string,
+	owner *common.Account,
+	airdropType AirdropType,
+	preferredCurrency currency_lib.Code,
+) (*campaign.Payout, error) {
 	log := s.log.WithFields(logrus.Fields{
-		"method":       "airdrop",
+		"method":       "enqueueCampaignPayout",
 		"owner":        owner.PublicKey().ToBase58(),
 		"intent":       intentId,
+		"campaign":     campaignRecord.Name,
 		"airdrop_type": airdropType.String(),
 	})
 
-	// Check whether the phone number allowed to receive an airdrop broadly
 	verificationRecord, err := s.data.GetLatestPhoneVerificationForAccount(ctx, owner.PublicKey().ToBase58())
 	if err != nil {
 		log.WithError(err).Warn("failure getting phone verification record")
 		return nil, err
 	}
 
-	var usdValue float64
-	switch airdropType {
-	case AirdropTypeGiveFirstKin:
-		usdValue = 5.0
-	case AirdropTypeGetFirstKin:
-		usdValue = 1.0
-	default:
-		return nil, errors.New("unhandled airdrop type")
-	}
-
-	// Find the destination account, which will be the user's primary account
-	primaryAccountInfoRecord, err := s.data.GetLatestAccountInfoByOwnerAddressAndType(ctx, owner.PublicKey().ToBase58(), commonpb.AccountType_PRIMARY)
+	// The payout is sent to the user's primary account, so it must exist
+	_, err = s.data.GetLatestAccountInfoByOwnerAddressAndType(ctx, owner.PublicKey().ToBase58(), commonpb.AccountType_PRIMARY)
 	if err == account.ErrAccountInfoNotFound {
 		log.Trace("owner cannot receive airdrop")
 		return nil, ErrInvalidAirdropTarget
@@ -362,257 +391,93 @@ func (s *transactionServer) airdrop(ctx context.Context, intentId string, owner
 		log.WithError(err).Warn("failure getting primary account info record")
 		return nil, err
 	}
-	destination, err := common.NewAccountFromPublicKeyString(primaryAccountInfoRecord.TokenAccount)
-	if err != nil {
-		log.WithError(err).Warn("invalid destination account")
-		return nil, err
-	}
+
+	exchangeCurrency, nativeAmount := campaignRecord.GetAmount(preferredCurrency)
 
 	// Calculate the amount of quarks to send
-	usdRateRecord, err := s.data.GetExchangeRate(ctx, currency_lib.USD, exchange_rate_util.GetLatestExchangeRateTime())
+	exchangeRateTime := exchange_rate_util.GetLatestExchangeRateTime()
+	rateRecord, err := s.data.GetExchangeRate(ctx, exchangeCurrency, exchangeRateTime)
 	if err != nil {
-		log.WithError(err).Warn("failure getting usd rate")
+		log.WithError(err).Warn("failure getting exchange rate")
 		return nil, err
 	}
-	quarks := kin.ToQuarks(uint64(usdValue / usdRateRecord.Rate))
+	quarks := kin.ToQuarks(uint64(nativeAmount / rateRecord.Rate))
 
 	// Add an additional Kin, so we can always have enough to send the full fiat amount
 	quarks += kin.ToQuarks(1)
 
-	var isAirdropperManuallyUnlocked bool
-	s.airdropperLock.Lock()
-	defer func() {
-		if !isAirdropperManuallyUnlocked {
-			s.airdropperLock.Unlock()
+	usdValue := nativeAmount
+	if exchangeCurrency != currency_lib.USD {
+		usdRateRecord, err := s.data.GetExchangeRate(ctx, currency_lib.USD, exchangeRateTime)
+		if err != nil {
+			log.WithError(err).Warn("failure getting usd rate")
+			return nil, err
 		}
-	}()
-
-	// Does the intent already exist?
-	existingIntentRecord, err := s.data.GetIntent(ctx, intentId)
-	if err == nil {
-		return existingIntentRecord, nil
-	} else if err != intent.ErrIntentNotFound {
-		log.WithError(err).Warn("failure checking for existing airdrop intent")
-		return nil, err
-	}
-
-	// Do a balance check. If there's insufficient balance, the feature is considered
-	// to be over with until we get more funding.
-	balance, err := balance.DefaultCalculation(ctx, s.data, s.airdropper.Vault)
-	if err != nil {
-		log.WithError(err).Warn("failure getting airdropper balance")
-		return nil, err
-	} else if balance < quarks {
-		log.WithFields(logrus.Fields{
-			"balance":  balance,
-			"required": quarks,
-		}).Warn("airdropper has insufficient balance")
-		return nil, ErrInsufficientAirdropperBalance
-	}
-
-	// Make the intent to transfer the funds. We accomplish this by having the
-	// sender setup and act like an internal Code account who always publicly
-	// transfers the airdrop like a Code->Code withdrawal.
-	//
-	// todo: This is an interesting concept we could consider expanding further.
-	//       Instead of constructing and validating everything manually, we could
-	//       have a proper client call SubmitIntent in a worker.
-
-	selectedNonce, err := transaction.SelectAvailableNonce(ctx, s.data, nonce.PurposeClientTransaction)
-	if err != nil {
-		log.WithError(err).Warn("failure selecting available nonce")
-		return nil, err
-	}
-	defer func() {
-		selectedNonce.ReleaseIfNotReserved()
-		selectedNonce.Unlock()
-	}()
-
-	txn, err := transaction.MakeTransferWithAuthorityTransaction(
-		selectedNonce.Account,
-		selectedNonce.Blockhash,
-		s.airdropper,
-		destination,
-		quarks,
-	)
-	if err != nil {
-		log.WithError(err).Warn("failure making solana transaction")
-		return nil, err
-	}
-
-	err = txn.Sign(common.GetSubsidizer().PrivateKey().ToBytes(), s.airdropper.VaultOwner.PrivateKey().ToBytes())
-	if err != nil {
-		log.WithError(err).Warn("failure signing solana transaction")
-		return nil, err
+		usdValue = usdRateRecord.Rate * nativeAmount / rateRecord.Rate
 	}
 
-	intentRecord := &intent.Record{
-		IntentId:   intentId,
-		IntentType: intent.SendPublicPayment,
+	payoutRecord := &campaign.Payout{
+		Campaign: campaignRecord.Name,
 
-		SendPublicPaymentMetadata: &intent.SendPublicPaymentMetadata{
-			DestinationOwnerAccount: owner.PublicKey().ToBase58(),
-			DestinationTokenAccount: destination.PublicKey().ToBase58(),
-			Quantity:                quarks,
+		IntentId: intentId,
 
-			ExchangeCurrency: currency_lib.USD,
-			ExchangeRate:     usdRateRecord.Rate,
-			NativeAmount:     usdValue,
-			UsdMarketValue:   usdValue,
+		OwnerAccount: owner.PublicKey().ToBase58(),
+		PhoneNumber:  verificationRecord.PhoneNumber,
 
-			IsWithdrawal: true,
-		},
-
-		InitiatorOwnerAccount: s.airdropper.VaultOwner.PublicKey().ToBase58(),
-
-		State: intent.StateUnknown,
-
-		CreatedAt: time.Now(),
-	}
-
-	actionRecord := &action.Record{
-		Intent:     intentRecord.IntentId,
-		IntentType: intentRecord.IntentType,
-
-		ActionId:   0,
-		ActionType: action.NoPrivacyTransfer,
-
-		Source:      s.airdropper.Vault.PublicKey().ToBase58(),
-		Destination: pointer.String(destination.PublicKey().ToBase58()),
-
-		Quantity: pointer.Uint64(quarks),
-
-		State: action.StatePending,
-
-		CreatedAt: time.Now(),
-	}
-
-	fulfillmentRecord := &fulfillment.Record{
-		Intent:     intentRecord.IntentId,
-		IntentType: intentRecord.IntentType,
-
-		ActionId:   actionRecord.ActionId,
-		ActionType: actionRecord.ActionType,
-
-		FulfillmentType: fulfillment.NoPrivacyTransferWithAuthority,
-		Data:            txn.Marshal(),
-		Signature:       pointer.String(base58.Encode(txn.Signature())),
+		ExchangeCurrency: exchangeCurrency,
+		ExchangeRate:     rateRecord.Rate,
+		NativeAmount:     nativeAmount,
+		UsdValue:         usdValue,
+		Quantity:         quarks,
 
-		Nonce:     pointer.String(selectedNonce.Account.PublicKey().ToBase58()),
-		Blockhash: pointer.String(base58.Encode(selectedNonce.Blockhash[:])),
-
-		Source:      actionRecord.Source,
-		Destination: pointer.StringCopy(actionRecord.Destination),
-
-		DisableActiveScheduling: false,
-
-		// IntentOrderingIndex unknown until intent record is saved
-		ActionOrderingIndex:      0,
-		FulfillmentOrderingIndex: 0,
-
-		State: fulfillment.StateUnknown,
+		State: campaign.PayoutStatePending,
 
 		CreatedAt: time.Now(),
 	}
 
-	var eventType event.EventType
-	switch airdropType {
-	case AirdropTypeGetFirstKin:
-		eventType = event.WelcomeBonusClaimed
-	default:
-		return nil, errors.Errorf("no event type defined for %s airdrop", airdropType.String())
-	}
+	var eventRecord *event.Record
+	if campaignRecord.Type == campaign.WelcomeBonusType {
+		eventRecord = &event.Record{
+			EventId:   intentId,
+			EventType: event.WelcomeBonusClaimed,
 
-	eventRecord := &event.Record{
-		EventId:   intentRecord.IntentId,
-		EventType: eventType,
+			SourceCodeAccount: owner.PublicKey().ToBase58(),
+			SourceIdentity:    verificationRecord.PhoneNumber,
 
-		SourceCodeAccount: owner.PublicKey().ToBase58(),
-		SourceIdentity:    verificationRecord.PhoneNumber,
+			SpamConfidence: 0,
 
-		SpamConfidence: 0,
-
-		CreatedAt: time.Now(),
-	}
-	event_util.InjectClientDetails(ctx, s.maxmind, eventRecord, true)
-
-	var chatMessage *chatpb.ChatMessage
-	switch airdropType {
-	case AirdropTypeGetFirstKin:
-		chatMessage, err = chat_util.ToWelcomeBonusMessage(intentRecord)
-		if err != nil {
-			return nil, err
+			CreatedAt: time.Now(),
 		}
-	case AirdropTypeGiveFirstKin:
-		chatMessage, err = chat_util.ToReferralBonusMessage(intentRecord)
-		if err != nil {
-			return nil, err
-		}
-	default:
-		return nil, errors.Errorf("no chat message defined for %s airdrop", airdropType.String())
+		event_util.InjectClientDetails(ctx, s.data, eventRecord, true)
+		s.antispamGuard.ScoreEvent(ctx, eventRecord)
 	}
 
-	var canPushChatMessage bool
 	err = s.data.ExecuteInTx(ctx, sql.LevelDefault, func(ctx context.Context) error {
-		err := s.data.SaveIntent(ctx, intentRecord)
+		err := s.data.CreateCampaignPayout(ctx, payoutRecord)
 		if err != nil {
 			return err
 		}
 
-		err = s.data.PutAllActions(ctx, actionRecord)
-		if err != nil {
-			return err
+		if eventRecord != nil {
+			return s.data.SaveEvent(ctx, eventRecord)
 		}
-
-		fulfillmentRecord.IntentOrderingIndex = intentRecord.Id
-		err = s.data.PutAllFulfillments(ctx, fulfillmentRecord)
-		if err != nil {
-			return err
-		}
-
-		err = selectedNonce.MarkReservedWithSignature(ctx, *fulfillmentRecord.Signature)
-		if err != nil {
-			return err
-		}
-
-		err = s.data.SaveEvent(ctx, eventRecord)
-		if err != nil {
-			return err
-		}
-
-		canPushChatMessage, err = chat_util.SendChatMessage(ctx, s.data, chat_util.CodeTeamName, chat.ChatTypeInternal, true, owner, chatMessage, false)
-		if err != nil {
-			return err
-		}
-
-		// Intent is pending only after everything's been saved.
-		intentRecord.State = intent.StatePending
-		return s.data.SaveIntent(ctx, intentRecord)
+		return nil
 	})
-	if err != nil {
-		log.WithError(err).Warn("failure creating airdrop intent")
+	switch err {
+	case nil:
+	case campaign.ErrPayoutExists:
+		return nil, err
+	case campaign.ErrBudgetExceeded:
+		log.Info("campaign budget exceeded")
+		return nil, err
+	default:
+		log.WithError(err).Warn("failure creating campaign payout")
 		return nil, err
 	}
 
-	// Avoid blocking the airdropper for inessential processing
-	s.airdropperLock.Unlock()
-	isAirdropperManuallyUnlocked = true
-
-	if canPushChatMessage {
-		// Best-effort send a push
-		push_util.SendChatMessagePushNotification(
-			ctx,
-			s.data,
-			s.pusher,
-			chat_util.CodeTeamName,
-			owner,
-			chatMessage,
-		)
-	}
-
-	recordAirdropEvent(ctx, owner, airdropType, usdValue)
+	recordAirdropEvent(ctx, owner, airdropType, campaignRecord.Name, usdValue)
 
-	return intentRecord, nil
+	return payoutRecord, nil
 }
 
 func (s *transactionServer) isFirstReceiveFromOtherCodeUser(ctx context.Context, intentToCheck string, owner *common.Account) (bool, error) {
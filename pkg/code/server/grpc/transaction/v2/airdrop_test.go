@@ -22,6 +22,7 @@ func TestAirdrop_GetFirstKin_HappyPath(t *testing.T) {
 		defer cleanup()
 
 		server.setupAirdropper(t, kin.ToQuarks(1_5000_000_000))
+		server.setupWelcomeBonusCampaign(t, 100)
 		server.generateAvailableNonces(t, 1000)
 
 		phone.openAccounts(t).requireSuccess(t)
@@ -42,7 +43,18 @@ func TestAirdrop_GetFirstKin_HappyPath(t *testing.T) {
 			resp = phone.requestAirdrop(t, transactionpb.AirdropType_GET_FIRST_KIN)
 			assert.Equal(t, transactionpb.AirdropResponse_ALREADY_CLAIMED, resp.Result)
 		}
+
+		spend, err := server.data.GetCampaignSpend(server.ctx, defaultWelcomeBonusCampaign)
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, spend.Payouts)
+		assert.Equal(t, 1.0, spend.UsdValue)
+
+		server.simulateCampaignPayoutHistoryItem(t, phone, defaultWelcomeBonusCampaign)
 		phone.assertAirdropCount(t, 1)
+
+		cachedAirdropStatus.Clear()
+		resp = phone.requestAirdrop(t, transactionpb.AirdropType_GET_FIRST_KIN)
+		assert.Equal(t, transactionpb.AirdropResponse_ALREADY_CLAIMED, resp.Result)
 	}
 }
 
@@ -53,6 +65,7 @@ func TestAirdrop_GetFirstKin_InvalidOwner(t *testing.T) {
 	defer cleanup()
 
 	server.setupAirdropper(t, kin.ToQuarks(1_5000_000_000))
+	server.setupWelcomeBonusCampaign(t, 100)
 	server.generateAvailableNonces(t, 1000)
 
 	resp := phone.requestAirdrop(t, transactionpb.AirdropType_GET_FIRST_KIN)
@@ -60,13 +73,13 @@ func TestAirdrop_GetFirstKin_InvalidOwner(t *testing.T) {
 	server.assertNotAirdroppedFirstKin(t, phone)
 }
 
-func TestAirdrop_GetFirstKin_InsufficientBalance(t *testing.T) {
+func TestAirdrop_GetFirstKin_CampaignNotFound(t *testing.T) {
 	server, phone, _, cleanup := setupTestEnv(t, &testOverrides{
 		enableAirdrops: true,
 	})
 	defer cleanup()
 
-	server.setupAirdropper(t, kin.ToQuarks(1))
+	server.setupAirdropper(t, kin.ToQuarks(1_5000_000_000))
 	server.generateAvailableNonces(t, 1000)
 
 	phone.openAccounts(t).requireSuccess(t)
@@ -76,6 +89,28 @@ func TestAirdrop_GetFirstKin_InsufficientBalance(t *testing.T) {
 	server.assertNotAirdroppedFirstKin(t, phone)
 }
 
+func TestAirdrop_GetFirstKin_CampaignBudgetExceeded(t *testing.T) {
+	server, phone, otherPhone, cleanup := setupTestEnv(t, &testOverrides{
+		enableAirdrops: true,
+	})
+	defer cleanup()
+
+	server.setupAirdropper(t, kin.ToQuarks(1_5000_000_000))
+	server.setupWelcomeBonusCampaign(t, 1.5)
+	server.generateAvailableNonces(t, 1000)
+
+	phone.openAccounts(t).requireSuccess(t)
+	otherPhone.openAccounts(t).requireSuccess(t)
+
+	resp := phone.requestAirdrop(t, transactionpb.AirdropType_GET_FIRST_KIN)
+	assert.Equal(t, transactionpb.AirdropResponse_OK, resp.Result)
+	server.assertAirdroppedFirstKin(t, phone)
+
+	resp = otherPhone.requestAirdrop(t, transactionpb.AirdropType_GET_FIRST_KIN)
+	assert.Equal(t, transactionpb.AirdropResponse_UNAVAILABLE, resp.Result)
+	server.assertNotAirdroppedFirstKin(t, otherPhone)
+}
+
 /*
 func TestAirdrop_GiveFirstKin_CodeToCodePayment(t *testing.T) {
 	for _, clearCache := range []bool{true, false} {
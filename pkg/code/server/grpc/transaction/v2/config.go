@@ -33,6 +33,12 @@ const (
 	AirdropperOwnerPublicKeyEnvName = envConfigPrefix + "AIRDROPPER_OWNER_PUBLIC_KEY"
 	defaultAirdropperOwnerPublicKey = "invalid" // Ensure something valid is set
 
+	WelcomeBonusCampaignConfigEnvName = envConfigPrefix + "WELCOME_BONUS_CAMPAIGN"
+	defaultWelcomeBonusCampaign       = "welcome_bonus"
+
+	ReferralBonusCampaignConfigEnvName = envConfigPrefix + "REFERRAL_BONUS_CAMPAIGN"
+	defaultReferralBonusCampaign       = "referral_bonus"
+
 	TreasuryPoolOneKinBucketConfigEnvName             = envConfigPrefix + "TREASURY_POOL_1_KIN_BUCKET"
 	TreasuryPoolTenKinBucketConfigEnvName             = envConfigPrefix + "TREASURY_POOL_10_KIN_BUCKET"
 	TreasuryPoolHundredKinBucketConfigEnvName         = envConfigPrefix + "TREASURY_POOL_100_KIN_BUCKET"
@@ -60,6 +66,8 @@ type conf struct {
 	enableAirdrops                       config.Bool
 	enableAsyncAirdropProcessing         config.Bool
 	airdropperOwnerPublicKey             config.String
+	welcomeBonusCampaign                 config.String
+	referralBonusCampaign                config.String
 	treasuryPoolOneKinBucket             config.String
 	treasuryPoolTenKinBucket             config.String
 	treasuryPoolHundredKinBucket         config.String
@@ -89,6 +97,8 @@ func WithEnvConfigs() ConfigProvider {
 			enableAirdrops:                       env.NewBoolConfig(EnableAirdropsConfigEnvName, defaultEnableAirdrops),
 			enableAsyncAirdropProcessing:         wrapper.NewBoolConfig(memory.NewConfig(true), true),
 			airdropperOwnerPublicKey:             env.NewStringConfig(AirdropperOwnerPublicKeyEnvName, defaultAirdropperOwnerPublicKey),
+			welcomeBonusCampaign:                 env.NewStringConfig(WelcomeBonusCampaignConfigEnvName, defaultWelcomeBonusCampaign),
+			referralBonusCampaign:                env.NewStringConfig(ReferralBonusCampaignConfigEnvName, defaultReferralBonusCampaign),
 			treasuryPoolOneKinBucket:             env.NewStringConfig(TreasuryPoolOneKinBucketConfigEnvName, defaultTreasuryPoolName),
 			treasuryPoolTenKinBucket:             env.NewStringConfig(TreasuryPoolTenKinBucketConfigEnvName, defaultTreasuryPoolName),
 			treasuryPoolHundredKinBucket:         env.NewStringConfig(TreasuryPoolHundredKinBucketConfigEnvName, defaultTreasuryPoolName),
@@ -132,6 +142,8 @@ func withManualTestOverrides(overrides *testOverrides) ConfigProvider {
 			enableAirdrops:                       wrapper.NewBoolConfig(memory.NewConfig(overrides.enableAirdrops), false),
 			enableAsyncAirdropProcessing:         wrapper.NewBoolConfig(memory.NewConfig(false), false),
 			airdropperOwnerPublicKey:             wrapper.NewStringConfig(memory.NewConfig(defaultAirdropperOwnerPublicKey), defaultAirdropperOwnerPublicKey),
+			welcomeBonusCampaign:                 wrapper.NewStringConfig(memory.NewConfig(defaultWelcomeBonusCampaign), defaultWelcomeBonusCampaign),
+			referralBonusCampaign:                wrapper.NewStringConfig(memory.NewConfig(defaultReferralBonusCampaign), defaultReferralBonusCampaign),
 			treasuryPoolOneKinBucket:             wrapper.NewStringConfig(memory.NewConfig(overrides.treasuryPoolOneKinBucket), defaultTreasuryPoolName),
 			treasuryPoolTenKinBucket:             wrapper.NewStringConfig(memory.NewConfig(overrides.treasuryPoolTenKinBucket), defaultTreasuryPoolName),
 			treasuryPoolHundredKinBucket:         wrapper.NewStringConfig(memory.NewConfig(overrides.treasuryPoolHundredKinBucket), defaultTreasuryPoolName),
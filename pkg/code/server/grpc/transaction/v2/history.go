@@ -10,12 +10,11 @@ import (
 
 	transactionpb "github.com/code-payments/code-protobuf-api/generated/go/transaction/v2"
 
-	"github.com/code-payments/code-server/pkg/currency"
 	"github.com/code-payments/code-server/pkg/database/query"
 	"github.com/code-payments/code-server/pkg/grpc/client"
-	"github.com/code-payments/code-server/pkg/kin"
 	"github.com/code-payments/code-server/pkg/code/common"
 	"github.com/code-payments/code-server/pkg/code/data/intent"
+	"github.com/code-payments/code-server/pkg/code/history"
 )
 
 const maxHistoryPageSize = 100
@@ -86,146 +85,34 @@ func (s *transactionServer) GetPaymentHistory(ctx context.Context, req *transact
 		return nil, status.Error(codes.Internal, "")
 	}
 
+	var airdropper *common.Account
+	if s.airdropper != nil {
+		airdropper = s.airdropper.VaultOwner
+	}
+
 	var items []*transactionpb.PaymentHistoryItem
 	for _, intentRecord := range intentRecords {
-		var paymentType transactionpb.PaymentHistoryItem_PaymentType
-		var isDeposit, isWithdrawal, isRemoteSend, isReturned, isAirdrop, isMicroPayment bool
-		var exchangeData *transactionpb.ExchangeData
-		var airdropType transactionpb.AirdropType
-
-		// Extract payment details from intents, where applicable, into a view
-		// that makes sense for a user.
-		switch intentRecord.IntentType {
-		case intent.SendPrivatePayment:
-			paymentType = transactionpb.PaymentHistoryItem_SEND
-			isRemoteSend = intentRecord.SendPrivatePaymentMetadata.IsRemoteSend
-			isWithdrawal = intentRecord.SendPrivatePaymentMetadata.IsWithdrawal
-			isDeposit = false
-			isMicroPayment = intentRecord.SendPrivatePaymentMetadata.IsMicroPayment
-			if intentRecord.InitiatorOwnerAccount != owner.PublicKey().ToBase58() {
-				paymentType = transactionpb.PaymentHistoryItem_RECEIVE
-				isWithdrawal = false
-				isDeposit = intentRecord.SendPrivatePaymentMetadata.IsWithdrawal
-			}
-
-			// Funds moving within the same owner don't get populated when they're
-			// used to support another payment flow that represents the history item
-			// (eg. public withdrawals with private top ups)
-			if isWithdrawal && intentRecord.InitiatorOwnerAccount == intentRecord.SendPrivatePaymentMetadata.DestinationOwnerAccount && !isMicroPayment {
-				continue
-			}
-
-			// Don't show history items where the user voids the gift card.
-			if isRemoteSend {
-				claimedIntent, err := s.data.GetGiftCardClaimedIntent(ctx, intentRecord.SendPrivatePaymentMetadata.DestinationTokenAccount)
-				if err == nil && claimedIntent.ReceivePaymentsPubliclyMetadata.IsIssuerVoidingGiftCard {
-					continue
-				} else if err != nil && err != intent.ErrIntentNotFound {
-					log.WithError(err).Warn("failure getting gift card claimed intent")
-					return nil, status.Error(codes.Internal, "")
-				}
-			}
-
-			exchangeData = &transactionpb.ExchangeData{
-				Currency:     string(intentRecord.SendPrivatePaymentMetadata.ExchangeCurrency),
-				ExchangeRate: intentRecord.SendPrivatePaymentMetadata.ExchangeRate,
-				NativeAmount: intentRecord.SendPrivatePaymentMetadata.NativeAmount,
-				Quarks:       intentRecord.SendPrivatePaymentMetadata.Quantity,
-			}
-		case intent.SendPublicPayment:
-			paymentType = transactionpb.PaymentHistoryItem_SEND
-			isRemoteSend = false
-			isWithdrawal = intentRecord.SendPublicPaymentMetadata.IsWithdrawal
-			isDeposit = false
-			isMicroPayment = false
-			if intentRecord.InitiatorOwnerAccount != owner.PublicKey().ToBase58() {
-				paymentType = transactionpb.PaymentHistoryItem_RECEIVE
-				isWithdrawal = false
-				isDeposit = intentRecord.SendPublicPaymentMetadata.IsWithdrawal
-			}
-
-			// Bonus airdrops only occur within Code->Code withdrawal flows
-			if s.airdropper != nil {
-				isAirdrop = (intentRecord.InitiatorOwnerAccount == s.airdropper.VaultOwner.PublicKey().ToBase58())
-			}
-
-			if isAirdrop {
-				// todo: something less hacky
-				if intentRecord.SendPublicPaymentMetadata.NativeAmount == 5.0 {
-					airdropType = transactionpb.AirdropType_GIVE_FIRST_KIN
-				} else if intentRecord.SendPublicPaymentMetadata.NativeAmount == 1.0 {
-					airdropType = transactionpb.AirdropType_GET_FIRST_KIN
-				}
-			}
-
-			exchangeData = &transactionpb.ExchangeData{
-				Currency:     string(intentRecord.SendPublicPaymentMetadata.ExchangeCurrency),
-				ExchangeRate: intentRecord.SendPublicPaymentMetadata.ExchangeRate,
-				NativeAmount: intentRecord.SendPublicPaymentMetadata.NativeAmount,
-				Quarks:       intentRecord.SendPublicPaymentMetadata.Quantity,
-			}
-		case intent.ReceivePaymentsPrivately:
-			// Other intents account for history items
+		historyItem, ok, err := history.GetItem(ctx, s.data, owner, airdropper, intentRecord)
+		if err != nil {
+			log.WithError(err).Warn("failure getting history item")
+			return nil, status.Error(codes.Internal, "")
+		} else if !ok {
 			continue
-		case intent.MigrateToPrivacy2022:
-			// Don't show migrations for dust
-			if intentRecord.MigrateToPrivacy2022Metadata.Quantity < kin.ToQuarks(1) {
-				continue
-			}
-
-			paymentType = transactionpb.PaymentHistoryItem_RECEIVE
-			isDeposit = true
-			exchangeData = &transactionpb.ExchangeData{
-				Currency:     string(currency.KIN),
-				ExchangeRate: 1.0,
-				NativeAmount: float64(intentRecord.MigrateToPrivacy2022Metadata.Quantity) / kin.QuarksPerKin,
-				Quarks:       intentRecord.MigrateToPrivacy2022Metadata.Quantity,
-			}
-		case intent.ExternalDeposit:
-			if intentRecord.ExternalDepositMetadata.DestinationOwnerAccount != owner.PublicKey().ToBase58() {
-				continue
-			}
-
-			// Don't show deposits for dust
-			if intentRecord.ExternalDepositMetadata.Quantity < kin.ToQuarks(1) {
-				continue
-			}
-
-			paymentType = transactionpb.PaymentHistoryItem_RECEIVE
-			isDeposit = true
-			exchangeData = &transactionpb.ExchangeData{
-				Currency:     string(currency.KIN),
-				ExchangeRate: 1.0,
-				NativeAmount: float64(intentRecord.ExternalDepositMetadata.Quantity) / kin.QuarksPerKin,
-				Quarks:       intentRecord.ExternalDepositMetadata.Quantity,
-			}
-		case intent.ReceivePaymentsPublicly:
-			// The intent to create the remote send gift card has no knowledge of the
-			// destination owner since it's claimed at a later time, so the history item
-			// must come from the intent receiving it.
-			if !intentRecord.ReceivePaymentsPubliclyMetadata.IsRemoteSend {
-				continue
-			}
-
-			// Don't show history items where the user voids the gift card.
-			if intentRecord.ReceivePaymentsPubliclyMetadata.IsIssuerVoidingGiftCard {
-				continue
-			}
+		}
 
+		paymentType := transactionpb.PaymentHistoryItem_SEND
+		if historyItem.PaymentType == history.PaymentTypeReceive {
 			paymentType = transactionpb.PaymentHistoryItem_RECEIVE
-			isRemoteSend = intentRecord.ReceivePaymentsPubliclyMetadata.IsRemoteSend
-			isReturned = intentRecord.ReceivePaymentsPubliclyMetadata.IsReturned
-			isWithdrawal = false
-			isDeposit = false
+		}
 
-			exchangeData = &transactionpb.ExchangeData{
-				Currency:     string(intentRecord.ReceivePaymentsPubliclyMetadata.OriginalExchangeCurrency),
-				ExchangeRate: intentRecord.ReceivePaymentsPubliclyMetadata.OriginalExchangeRate,
-				NativeAmount: intentRecord.ReceivePaymentsPubliclyMetadata.OriginalNativeAmount,
-				Quarks:       intentRecord.ReceivePaymentsPubliclyMetadata.Quantity,
+		var airdropType transactionpb.AirdropType
+		if historyItem.IsAirdrop {
+			// todo: something less hacky
+			if historyItem.NativeAmount == 5.0 {
+				airdropType = transactionpb.AirdropType_GIVE_FIRST_KIN
+			} else if historyItem.NativeAmount == 1.0 {
+				airdropType = transactionpb.AirdropType_GET_FIRST_KIN
 			}
-		default:
-			continue
 		}
 
 		item := &transactionpb.PaymentHistoryItem{
@@ -233,20 +120,25 @@ func (s *transactionServer) GetPaymentHistory(ctx context.Context, req *transact
 				Value: query.ToCursor(intentRecord.Id),
 			},
 
-			ExchangeData: exchangeData,
+			ExchangeData: &transactionpb.ExchangeData{
+				Currency:     string(historyItem.Currency),
+				ExchangeRate: historyItem.ExchangeRate,
+				NativeAmount: historyItem.NativeAmount,
+				Quarks:       historyItem.Quarks,
+			},
 
 			PaymentType: paymentType,
 
-			IsWithdraw:     isWithdrawal,
-			IsDeposit:      isDeposit,
-			IsRemoteSend:   isRemoteSend,
-			IsReturned:     isReturned,
-			IsAirdrop:      isAirdrop,
-			IsMicroPayment: isMicroPayment,
+			IsWithdraw:     historyItem.IsWithdrawal,
+			IsDeposit:      historyItem.IsDeposit,
+			IsRemoteSend:   historyItem.IsRemoteSend,
+			IsReturned:     historyItem.IsReturned,
+			IsAirdrop:      historyItem.IsAirdrop,
+			IsMicroPayment: historyItem.IsMicroPayment,
 
 			AirdropType: airdropType,
 
-			Timestamp: timestamppb.New(intentRecord.CreatedAt),
+			Timestamp: timestamppb.New(historyItem.CreatedAt),
 		}
 
 		items = append(items, item)
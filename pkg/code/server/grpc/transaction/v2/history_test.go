@@ -29,6 +29,7 @@ func TestPaymentHistory_HappyPath(t *testing.T) {
 
 	server.generateAvailableNonces(t, 1000)
 	server.setupAirdropper(t, kin.ToQuarks(1_500_000_000))
+	server.setupWelcomeBonusCampaign(t, 100)
 
 	amountForPrivacyMigration := kin.ToQuarks(23)
 	legacyTimelockVault, err := sendingPhone.parentAccount.ToTimelockVault(timelock_token_v1.DataVersionLegacy)
@@ -93,6 +94,7 @@ func TestPaymentHistory_HappyPath(t *testing.T) {
 	sendingPhone.receive42KinPrivatelyIntoOrganizer(t).requireSuccess(t)
 
 	assert.Equal(t, transactionpb.AirdropResponse_OK, receivingPhone.requestAirdrop(t, transactionpb.AirdropType_GET_FIRST_KIN).Result)
+	server.simulateCampaignPayoutHistoryItem(t, receivingPhone, defaultWelcomeBonusCampaign)
 
 	sendingPhone.resetConfig()
 	sendingPhone.conf.simulatePaymentRequest = true
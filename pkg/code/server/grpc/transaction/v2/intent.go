@@ -43,11 +43,6 @@ import (
 	"github.com/code-payments/code-server/pkg/solana/token"
 )
 
-const (
-	// Assumes the client signature index is consistent across all transactions.
-	clientSignatureIndex = 1
-)
-
 func (s *transactionServer) SubmitIntent(streamer transactionpb.Transaction_SubmitIntentServer) error {
 	// Bound the total RPC. Keeping the timeout higher to see where we land because
 	// there's a lot of stuff happening in this method.
@@ -113,10 +108,10 @@ func (s *transactionServer) SubmitIntent(streamer transactionpb.Transaction_Subm
 	switch submitActionsReq.Metadata.Type.(type) {
 	case *transactionpb.Metadata_OpenAccounts:
 		log = log.WithField("intent_type", "open_accounts")
-		intentHandler = NewOpenAccountsIntentHandler(s.conf, s.data, s.antispamGuard, s.maxmind)
+		intentHandler = NewOpenAccountsIntentHandler(s.conf, s.data, s.antispamGuard)
 	case *transactionpb.Metadata_SendPrivatePayment:
 		log = log.WithField("intent_type", "send_private_payment")
-		intentHandler = NewSendPrivatePaymentIntentHandler(s.conf, s.data, s.pusher, s.antispamGuard, s.amlGuard, s.maxmind)
+		intentHandler = NewSendPrivatePaymentIntentHandler(s.conf, s.data, s.pusher, s.antispamGuard, s.amlGuard)
 		intentRequiresNewTreasuryPoolFunds = true
 	case *transactionpb.Metadata_ReceivePaymentsPrivately:
 		log = log.WithField("intent_type", "receive_payments_privately")
@@ -130,10 +125,10 @@ func (s *transactionServer) SubmitIntent(streamer transactionpb.Transaction_Subm
 		intentHandler = NewMigrateToPrivacy2022IntentHandler(s.conf, s.data)
 	case *transactionpb.Metadata_SendPublicPayment:
 		log = log.WithField("intent_type", "send_public_payment")
-		intentHandler = NewSendPublicPaymentIntentHandler(s.conf, s.data, s.pusher, s.antispamGuard, s.maxmind)
+		intentHandler = NewSendPublicPaymentIntentHandler(s.conf, s.data, s.pusher, s.antispamGuard)
 	case *transactionpb.Metadata_ReceivePaymentsPublicly:
 		log = log.WithField("intent_type", "receive_payments_publicly")
-		intentHandler = NewReceivePaymentsPubliclyIntentHandler(s.conf, s.data, s.antispamGuard, s.maxmind)
+		intentHandler = NewReceivePaymentsPubliclyIntentHandler(s.conf, s.data, s.antispamGuard)
 	case *transactionpb.Metadata_EstablishRelationship:
 		log = log.WithField("intent_type", "establish_relationship")
 		intentHandler = NewEstablishRelationshipIntentHandler(s.conf, s.data, s.antispamGuard)
@@ -265,8 +260,12 @@ func (s *transactionServer) SubmitIntent(streamer transactionpb.Transaction_Subm
 		IntentId:              intentId,
 		InitiatorOwnerAccount: initiatorOwnerAccount.PublicKey().ToBase58(),
 		InitiatorPhoneNumber:  initiatorPhoneNumber,
-		State:                 intent.StateUnknown,
-		CreatedAt:             time.Now(),
+
+		// Intents submitted by clients only operate on Kin timelock accounts
+		Mint: kin.Mint,
+
+		State:     intent.StateUnknown,
+		CreatedAt: time.Now(),
 	}
 
 	// Distributed locking. This is a partial view, since additional locking
@@ -441,23 +440,23 @@ func (s *transactionServer) SubmitIntent(streamer transactionpb.Transaction_Subm
 		case *transactionpb.Action_CloseEmptyAccount:
 			log = log.WithField("action_type", "close_empty_account")
 			actionType = action.CloseEmptyAccount
-			actionHandler, err = NewCloseEmptyAccountActionHandler(intentRecord.IntentType, typed.CloseEmptyAccount)
+			actionHandler, err = NewCloseEmptyAccountActionHandler(ctx, s.data, intentRecord.IntentType, typed.CloseEmptyAccount)
 		case *transactionpb.Action_CloseDormantAccount:
 			log = log.WithField("action_type", "close_dormant_account")
 			actionType = action.CloseDormantAccount
-			actionHandler, err = NewCloseDormantAccountActionHandler(typed.CloseDormantAccount)
+			actionHandler, err = NewCloseDormantAccountActionHandler(ctx, s.data, typed.CloseDormantAccount)
 		case *transactionpb.Action_NoPrivacyTransfer:
 			log = log.WithField("action_type", "no_privacy_transfer")
 			actionType = action.NoPrivacyTransfer
-			actionHandler, err = NewNoPrivacyTransferActionHandler(typed.NoPrivacyTransfer)
+			actionHandler, err = NewNoPrivacyTransferActionHandler(ctx, s.data, typed.NoPrivacyTransfer)
 		case *transactionpb.Action_FeePayment:
 			log = log.WithField("action_type", "fee_payment")
 			actionType = action.NoPrivacyTransfer
-			actionHandler, err = NewFeePaymentActionHandler(typed.FeePayment, s.feeCollector)
+			actionHandler, err = NewFeePaymentActionHandler(ctx, s.data, typed.FeePayment, s.feeCollector)
 		case *transactionpb.Action_NoPrivacyWithdraw:
 			log = log.WithField("action_type", "no_privacy_withdraw")
 			actionType = action.NoPrivacyWithdraw
-			actionHandler, err = NewNoPrivacyWithdrawActionHandler(intentRecord.IntentType, typed.NoPrivacyWithdraw)
+			actionHandler, err = NewNoPrivacyWithdrawActionHandler(ctx, s.data, intentRecord.IntentType, typed.NoPrivacyWithdraw)
 		case *transactionpb.Action_TemporaryPrivacyTransfer:
 			log = log.WithField("action_type", "temporary_privacy_transfer")
 			actionType = action.PrivateTransfer
@@ -630,7 +629,7 @@ func (s *transactionServer) SubmitIntent(streamer transactionpb.Transaction_Subm
 
 			// Sign the Solana transaction
 			if !makeTxnResult.isCreatedOnDemand {
-				err = makeTxnResult.txn.Sign(common.GetSubsidizer().PrivateKey().ToBytes())
+				err = common.SignWithSubsidizers(makeTxnResult.txn)
 				if err != nil {
 					log.WithError(err).Warn("failure signing solana transaction")
 					return handleSubmitIntentError(streamer, err)
@@ -679,7 +678,7 @@ func (s *transactionServer) SubmitIntent(streamer transactionpb.Transaction_Subm
 
 			// Transaction requires a client signature
 			var requiresClientSignature bool
-			if !makeTxnResult.isCreatedOnDemand && makeTxnResult.txn.Message.Header.NumSignatures > clientSignatureIndex {
+			if !makeTxnResult.isCreatedOnDemand && hasClientSignature(makeTxnResult.txn) {
 				// Upgraded transactions always use the same nonce, so there's no
 				// need to provide it.
 				if !isUpgradeActionOperation {
@@ -776,6 +775,7 @@ func (s *transactionServer) SubmitIntent(streamer transactionpb.Transaction_Subm
 		var signatureErrorDetails []*transactionpb.ErrorDetails
 		for i, signature := range submitSignaturesReq.Signatures {
 			unsignedFulfillment := unsignedFulfillments[i]
+			clientSignatureIndex, _ := getClientSignatureIndex(unsignedFulfillment.txn)
 
 			if !ed25519.Verify(
 				unsignedFulfillment.txn.Message.Accounts[clientSignatureIndex],
@@ -1487,6 +1487,10 @@ func toUpgradeableIntentProto(ctx context.Context, data code_data.Provider, inte
 			return nil, err
 		}
 
+		clientSignatureIndex, ok := getClientSignatureIndex(&txn)
+		if !ok {
+			return nil, errors.New("fulfillment to upgrade doesn't have a client signature")
+		}
 		clientSignature := txn.Signatures[clientSignatureIndex]
 
 		// Clear out all signatures, so clients have no way of submitting this transaction
@@ -1543,3 +1547,21 @@ func toUpgradeableIntentProto(ctx context.Context, data code_data.Provider, inte
 		Actions: actions,
 	}, nil
 }
+
+// getClientSignatureIndex gets the index of the client's signature, which is the
+// first required signature that doesn't belong to a subsidizer. Transactions for
+// timelock accounts whose time authority is a retired subsidizer require signatures
+// from both the current and retired subsidizer.
+func getClientSignatureIndex(txn *solana.Transaction) (int, bool) {
+	for i := 0; i < int(txn.Message.Header.NumSignatures); i++ {
+		if !common.IsSubsidizer(base58.Encode(txn.Message.Accounts[i])) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func hasClientSignature(txn *solana.Transaction) bool {
+	_, ok := getClientSignatureIndex(txn)
+	return ok
+}
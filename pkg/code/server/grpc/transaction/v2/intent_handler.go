@@ -8,7 +8,6 @@ import (
 	"time"
 
 	"github.com/mr-tron/base58/base58"
-	"github.com/oschwald/maxminddb-golang"
 	"github.com/pkg/errors"
 
 	commonpb "github.com/code-payments/code-protobuf-api/generated/go/common/v1"
@@ -127,15 +126,13 @@ type OpenAccountsIntentHandler struct {
 	data                    code_data.Provider
 	antispamGuard           *antispam.Guard
 	antispamSuccessCallback func() error
-	maxmind                 *maxminddb.Reader
 }
 
-func NewOpenAccountsIntentHandler(conf *conf, data code_data.Provider, antispamGuard *antispam.Guard, maxmind *maxminddb.Reader) CreateIntentHandler {
+func NewOpenAccountsIntentHandler(conf *conf, data code_data.Provider, antispamGuard *antispam.Guard) CreateIntentHandler {
 	return &OpenAccountsIntentHandler{
 		conf:          conf,
 		data:          data,
 		antispamGuard: antispamGuard,
-		maxmind:       maxmind,
 	}
 }
 
@@ -360,7 +357,8 @@ func (h *OpenAccountsIntentHandler) OnSaveToDB(ctx context.Context, intentRecord
 
 		CreatedAt: time.Now(),
 	}
-	event_util.InjectClientDetails(ctx, h.maxmind, eventRecord, true)
+	event_util.InjectClientDetails(ctx, h.data, eventRecord, true)
+	h.antispamGuard.ScoreEvent(ctx, eventRecord)
 
 	return h.data.SaveEvent(ctx, eventRecord)
 }
@@ -381,7 +379,6 @@ type SendPrivatePaymentIntentHandler struct {
 	pusher        push_lib.Provider
 	antispamGuard *antispam.Guard
 	amlGuard      *lawenforcement.AntiMoneyLaunderingGuard
-	maxmind       *maxminddb.Reader
 }
 
 func NewSendPrivatePaymentIntentHandler(
@@ -390,7 +387,6 @@ func NewSendPrivatePaymentIntentHandler(
 	pusher push_lib.Provider,
 	antispamGuard *antispam.Guard,
 	amlGuard *lawenforcement.AntiMoneyLaunderingGuard,
-	maxmind *maxminddb.Reader,
 ) CreateIntentHandler {
 	return &SendPrivatePaymentIntentHandler{
 		conf:          conf,
@@ -398,7 +394,6 @@ func NewSendPrivatePaymentIntentHandler(
 		pusher:        pusher,
 		antispamGuard: antispamGuard,
 		amlGuard:      amlGuard,
-		maxmind:       maxmind,
 	}
 }
 
@@ -858,7 +853,7 @@ func (h *SendPrivatePaymentIntentHandler) validateActions(
 		}
 	}
 
-	err = validateMoneyMovementActionUserAccounts(intent.SendPrivatePayment, initiatorAccountsByVault, actions)
+	err = validateMoneyMovementActionUserAccounts(ctx, h.data, intent.SendPrivatePayment, initiatorAccountsByVault, actions)
 	if err != nil {
 		return err
 	}
@@ -928,7 +923,7 @@ func (h *SendPrivatePaymentIntentHandler) OnSaveToDB(ctx context.Context, intent
 	}
 
 	if eventRecord != nil {
-		event_util.InjectClientDetails(ctx, h.maxmind, eventRecord, true)
+		event_util.InjectClientDetails(ctx, h.data, eventRecord, true)
 
 		if eventRecord.DestinationCodeAccount != nil {
 			destinationVerificationRecord, err := h.data.GetLatestPhoneVerificationForAccount(ctx, *eventRecord.DestinationCodeAccount)
@@ -938,6 +933,8 @@ func (h *SendPrivatePaymentIntentHandler) OnSaveToDB(ctx context.Context, intent
 			eventRecord.DestinationIdentity = &destinationVerificationRecord.PhoneNumber
 		}
 
+		h.antispamGuard.ScoreEvent(ctx, eventRecord)
+
 		err := h.data.SaveEvent(ctx, eventRecord)
 		if err != nil {
 			return err
@@ -1288,7 +1285,7 @@ func (h *ReceivePaymentsPrivatelyIntentHandler) validateActions(
 		}
 	}
 
-	err = validateMoneyMovementActionUserAccounts(intent.ReceivePaymentsPrivately, initiatorAccountsByVault, actions)
+	err = validateMoneyMovementActionUserAccounts(ctx, h.data, intent.ReceivePaymentsPrivately, initiatorAccountsByVault, actions)
 	if err != nil {
 		return err
 	}
@@ -1584,7 +1581,6 @@ type SendPublicPaymentIntentHandler struct {
 	data          code_data.Provider
 	pusher        push_lib.Provider
 	antispamGuard *antispam.Guard
-	maxmind       *maxminddb.Reader
 }
 
 func NewSendPublicPaymentIntentHandler(
@@ -1592,14 +1588,12 @@ func NewSendPublicPaymentIntentHandler(
 	data code_data.Provider,
 	pusher push_lib.Provider,
 	antispamGuard *antispam.Guard,
-	maxmind *maxminddb.Reader,
 ) CreateIntentHandler {
 	return &SendPublicPaymentIntentHandler{
 		conf:          conf,
 		data:          data,
 		pusher:        pusher,
 		antispamGuard: antispamGuard,
-		maxmind:       maxmind,
 	}
 }
 
@@ -1857,7 +1851,7 @@ func (h *SendPublicPaymentIntentHandler) validateActions(
 
 	// Part 3: Generic validation of actions that move money
 
-	err = validateMoneyMovementActionUserAccounts(intent.SendPublicPayment, initiatorAccountsByVault, actions)
+	err = validateMoneyMovementActionUserAccounts(ctx, h.data, intent.SendPublicPayment, initiatorAccountsByVault, actions)
 	if err != nil {
 		return err
 	}
@@ -1892,7 +1886,8 @@ func (h *SendPublicPaymentIntentHandler) OnSaveToDB(ctx context.Context, intentR
 
 			CreatedAt: time.Now(),
 		}
-		event_util.InjectClientDetails(ctx, h.maxmind, eventRecord, true)
+		event_util.InjectClientDetails(ctx, h.data, eventRecord, true)
+		h.antispamGuard.ScoreEvent(ctx, eventRecord)
 
 		err := h.data.SaveEvent(ctx, eventRecord)
 		if err != nil {
@@ -1911,17 +1906,15 @@ type ReceivePaymentsPubliclyIntentHandler struct {
 	conf          *conf
 	data          code_data.Provider
 	antispamGuard *antispam.Guard
-	maxmind       *maxminddb.Reader
 
 	cachedGiftCardIssuedIntentRecord *intent.Record
 }
 
-func NewReceivePaymentsPubliclyIntentHandler(conf *conf, data code_data.Provider, antispamGuard *antispam.Guard, maxmind *maxminddb.Reader) CreateIntentHandler {
+func NewReceivePaymentsPubliclyIntentHandler(conf *conf, data code_data.Provider, antispamGuard *antispam.Guard) CreateIntentHandler {
 	return &ReceivePaymentsPubliclyIntentHandler{
 		conf:          conf,
 		data:          data,
 		antispamGuard: antispamGuard,
-		maxmind:       maxmind,
 	}
 }
 
@@ -2183,7 +2176,7 @@ func (h *ReceivePaymentsPubliclyIntentHandler) validateActions(
 	// Part 4: Generic validation of actions that move money
 	//
 
-	return validateMoneyMovementActionUserAccounts(intent.ReceivePaymentsPublicly, initiatorAccountsByVault, actions)
+	return validateMoneyMovementActionUserAccounts(ctx, h.data, intent.ReceivePaymentsPublicly, initiatorAccountsByVault, actions)
 }
 
 func (h *ReceivePaymentsPubliclyIntentHandler) OnSaveToDB(ctx context.Context, intentRecord *intent.Record) error {
@@ -2192,7 +2185,10 @@ func (h *ReceivePaymentsPubliclyIntentHandler) OnSaveToDB(ctx context.Context, i
 		if err == nil {
 			eventRecord.DestinationCodeAccount = &intentRecord.InitiatorOwnerAccount
 			eventRecord.DestinationIdentity = pointer.StringCopy(intentRecord.InitiatorPhoneNumber)
-			event_util.InjectClientDetails(ctx, h.maxmind, eventRecord, false) // Will be AWS if desktop
+			event_util.InjectClientDetails(ctx, h.data, eventRecord, false) // Will be AWS if desktop
+
+			// Rescore now that destination details are known
+			h.antispamGuard.ScoreEvent(ctx, eventRecord)
 
 			err = h.data.SaveEvent(ctx, eventRecord)
 			if err != nil {
@@ -2464,6 +2460,8 @@ func validateMoneyMovementActionCount(actions []*transactionpb.Action) error {
 // Other account types (eg. gift cards, external wallets, etc) and intent-specific
 // complex nuances should be handled elsewhere.
 func validateMoneyMovementActionUserAccounts(
+	ctx context.Context,
+	data code_data.Provider,
 	intentType intent.Type,
 	initiatorAccountsByVault map[string]*common.AccountRecords,
 	actions []*transactionpb.Action,
@@ -2599,10 +2597,10 @@ func validateMoneyMovementActionUserAccounts(
 			continue
 		}
 
-		expectedTimelockVault, err := getExpectedTimelockVaultFromProtoAccount(authority.ToProto())
+		expectedTimelockAccounts, err := authority.ResolveTimelockAccounts(ctx, data, timelock_token_v1.DataVersion1)
 		if err != nil {
 			return err
-		} else if !bytes.Equal(expectedTimelockVault.PublicKey().ToBytes(), source.PublicKey().ToBytes()) {
+		} else if !bytes.Equal(expectedTimelockAccounts.Vault.PublicKey().ToBytes(), source.PublicKey().ToBytes()) {
 			return newActionValidationErrorf(action, "authority is invalid")
 		}
 	}
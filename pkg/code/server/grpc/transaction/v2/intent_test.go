@@ -1,6 +1,8 @@
 package transaction_v2
 
 import (
+	"bytes"
+	"crypto/ed25519"
 	"encoding/hex"
 	"fmt"
 	"math"
@@ -19,6 +21,7 @@ import (
 	"github.com/code-payments/code-server/pkg/code/common"
 	"github.com/code-payments/code-server/pkg/currency"
 	"github.com/code-payments/code-server/pkg/kin"
+	"github.com/code-payments/code-server/pkg/solana"
 	timelock_token_v1 "github.com/code-payments/code-server/pkg/solana/timelock/v1"
 	"github.com/code-payments/code-server/pkg/testutil"
 )
@@ -964,6 +967,59 @@ func TestSubmitIntent_SendPublicPayment_WithdrawToCodeUser_HappyPath(t *testing.
 	server.assertIntentSubmitted(t, submitIntentCall.intentId, submitIntentCall.protoMetadata, submitIntentCall.protoActions, sendingPhone, &receivingPhone)
 }
 
+func TestSubmitIntent_SendPublicPayment_RotatedSubsidizer(t *testing.T) {
+	server, sendingPhone, receivingPhone, cleanup := setupTestEnv(t, &testOverrides{})
+	defer cleanup()
+
+	server.generateAvailableNonces(t, 100)
+
+	sendingPhone.openAccounts(t).requireSuccess(t)
+	sendingVault := sendingPhone.getTimelockVault(t, commonpb.AccountType_PRIMARY, 0)
+
+	// Rotate the subsidizer, retiring the one used to open the sender's accounts
+	retiredSubsidizer := server.subsidizer
+	server.subsidizer = testutil.SetupRandomSubsidizer(t, server.data)
+	require.NoError(t, common.InjectTestRetiredSubsidizer(server.ctx, server.data, retiredSubsidizer))
+
+	server.generateAvailableNonces(t, 100)
+
+	// New accounts use the current subsidizer
+	receivingPhone.openAccounts(t).requireSuccess(t)
+
+	receivingTimelockRecord, err := server.data.GetTimelockByVault(server.ctx, receivingPhone.getTimelockVault(t, commonpb.AccountType_PRIMARY, 0).PublicKey().ToBase58())
+	require.NoError(t, err)
+	assert.Equal(t, server.subsidizer.PublicKey().ToBase58(), receivingTimelockRecord.TimeAuthority)
+
+	// Existing accounts continue to use the retired subsidizer
+	assert.Equal(t, sendingVault.PublicKey().ToBase58(), sendingPhone.getTimelockVault(t, commonpb.AccountType_PRIMARY, 0).PublicKey().ToBase58())
+
+	sendingTimelockRecord, err := server.data.GetTimelockByVault(server.ctx, sendingVault.PublicKey().ToBase58())
+	require.NoError(t, err)
+	assert.Equal(t, retiredSubsidizer.PublicKey().ToBase58(), sendingTimelockRecord.TimeAuthority)
+
+	submitIntentCall := sendingPhone.publiclyWithdraw777KinToCodeUser(t, receivingPhone)
+	submitIntentCall.requireSuccess(t)
+	server.assertIntentSubmitted(t, submitIntentCall.intentId, submitIntentCall.protoMetadata, submitIntentCall.protoActions, sendingPhone, &receivingPhone)
+
+	fulfillmentRecords, err := server.data.GetAllFulfillmentsByIntent(server.ctx, submitIntentCall.intentId)
+	require.NoError(t, err)
+	require.Len(t, fulfillmentRecords, 1)
+	assert.Equal(t, sendingVault.PublicKey().ToBase58(), fulfillmentRecords[0].Source)
+
+	var txn solana.Transaction
+	require.NoError(t, txn.Unmarshal(fulfillmentRecords[0].Data))
+	require.EqualValues(t, 3, txn.Message.Header.NumSignatures)
+	for _, signer := range []*common.Account{server.subsidizer, retiredSubsidizer, sendingPhone.parentAccount} {
+		var signed bool
+		for i := 0; i < int(txn.Message.Header.NumSignatures); i++ {
+			if bytes.Equal(txn.Message.Accounts[i], signer.PublicKey().ToBytes()) {
+				signed = ed25519.Verify(txn.Message.Accounts[i], txn.Message.Marshal(), txn.Signatures[i][:])
+			}
+		}
+		assert.True(t, signed)
+	}
+}
+
 func TestSubmitIntent_SendPublicPayment_WithdrawToExternalWallet_HappyPath(t *testing.T) {
 	server, sendingPhone, _, cleanup := setupTestEnv(t, &testOverrides{})
 	defer cleanup()
@@ -3453,7 +3509,7 @@ func TestSubmitIntent_AdditionalAccountsToLock(t *testing.T) {
 	submitIntentCall.requireSuccess(t)
 	intentRecord, err := server.data.GetIntent(server.ctx, submitIntentCall.intentId)
 	require.NoError(t, err)
-	accountsToLock, err := NewOpenAccountsIntentHandler(server.service.conf, server.data, server.service.antispamGuard, nil).GetAdditionalAccountsToLock(server.ctx, intentRecord)
+	accountsToLock, err := NewOpenAccountsIntentHandler(server.service.conf, server.data, server.service.antispamGuard).GetAdditionalAccountsToLock(server.ctx, intentRecord)
 	require.NoError(t, err)
 	assert.Nil(t, accountsToLock.DestinationOwner)
 	assert.Nil(t, accountsToLock.RemoteSendGiftCardVault)
@@ -3462,7 +3518,7 @@ func TestSubmitIntent_AdditionalAccountsToLock(t *testing.T) {
 	submitIntentCall.requireSuccess(t)
 	intentRecord, err = server.data.GetIntent(server.ctx, submitIntentCall.intentId)
 	require.NoError(t, err)
-	accountsToLock, err = NewOpenAccountsIntentHandler(server.service.conf, server.data, server.service.antispamGuard, nil).GetAdditionalAccountsToLock(server.ctx, intentRecord)
+	accountsToLock, err = NewOpenAccountsIntentHandler(server.service.conf, server.data, server.service.antispamGuard).GetAdditionalAccountsToLock(server.ctx, intentRecord)
 	require.NoError(t, err)
 	assert.Nil(t, accountsToLock.DestinationOwner)
 	assert.Nil(t, accountsToLock.RemoteSendGiftCardVault)
@@ -3475,7 +3531,7 @@ func TestSubmitIntent_AdditionalAccountsToLock(t *testing.T) {
 	submitIntentCall.requireSuccess(t)
 	intentRecord, err = server.data.GetIntent(server.ctx, submitIntentCall.intentId)
 	require.NoError(t, err)
-	accountsToLock, err = NewSendPrivatePaymentIntentHandler(server.service.conf, server.data, server.service.pusher, server.service.antispamGuard, server.service.amlGuard, nil).GetAdditionalAccountsToLock(server.ctx, intentRecord)
+	accountsToLock, err = NewSendPrivatePaymentIntentHandler(server.service.conf, server.data, server.service.pusher, server.service.antispamGuard, server.service.amlGuard).GetAdditionalAccountsToLock(server.ctx, intentRecord)
 	require.NoError(t, err)
 	require.NotNil(t, accountsToLock.DestinationOwner)
 	assert.Nil(t, accountsToLock.RemoteSendGiftCardVault)
@@ -3498,7 +3554,7 @@ func TestSubmitIntent_AdditionalAccountsToLock(t *testing.T) {
 	submitIntentCall.requireSuccess(t)
 	intentRecord, err = server.data.GetIntent(server.ctx, submitIntentCall.intentId)
 	require.NoError(t, err)
-	accountsToLock, err = NewSendPrivatePaymentIntentHandler(server.service.conf, server.data, server.service.pusher, server.service.antispamGuard, server.service.amlGuard, nil).GetAdditionalAccountsToLock(server.ctx, intentRecord)
+	accountsToLock, err = NewSendPrivatePaymentIntentHandler(server.service.conf, server.data, server.service.pusher, server.service.antispamGuard, server.service.amlGuard).GetAdditionalAccountsToLock(server.ctx, intentRecord)
 	require.NoError(t, err)
 	assert.Nil(t, accountsToLock.DestinationOwner)
 	assert.Nil(t, accountsToLock.RemoteSendGiftCardVault)
@@ -3507,7 +3563,7 @@ func TestSubmitIntent_AdditionalAccountsToLock(t *testing.T) {
 	submitIntentCall.requireSuccess(t)
 	intentRecord, err = server.data.GetIntent(server.ctx, submitIntentCall.intentId)
 	require.NoError(t, err)
-	accountsToLock, err = NewSendPrivatePaymentIntentHandler(server.service.conf, server.data, server.service.pusher, server.service.antispamGuard, server.service.amlGuard, nil).GetAdditionalAccountsToLock(server.ctx, intentRecord)
+	accountsToLock, err = NewSendPrivatePaymentIntentHandler(server.service.conf, server.data, server.service.pusher, server.service.antispamGuard, server.service.amlGuard).GetAdditionalAccountsToLock(server.ctx, intentRecord)
 	require.NoError(t, err)
 	require.NotNil(t, accountsToLock.DestinationOwner)
 	assert.Nil(t, accountsToLock.RemoteSendGiftCardVault)
@@ -3533,7 +3589,7 @@ func TestSubmitIntent_AdditionalAccountsToLock(t *testing.T) {
 	submitIntentCall.requireSuccess(t)
 	intentRecord, err = server.data.GetIntent(server.ctx, submitIntentCall.intentId)
 	require.NoError(t, err)
-	accountsToLock, err = NewSendPrivatePaymentIntentHandler(server.service.conf, server.data, server.service.pusher, server.service.antispamGuard, server.service.amlGuard, nil).GetAdditionalAccountsToLock(server.ctx, intentRecord)
+	accountsToLock, err = NewSendPrivatePaymentIntentHandler(server.service.conf, server.data, server.service.pusher, server.service.antispamGuard, server.service.amlGuard).GetAdditionalAccountsToLock(server.ctx, intentRecord)
 	require.NoError(t, err)
 	assert.Nil(t, accountsToLock.DestinationOwner)
 	require.NotNil(t, accountsToLock.RemoteSendGiftCardVault)
@@ -3543,7 +3599,7 @@ func TestSubmitIntent_AdditionalAccountsToLock(t *testing.T) {
 	submitIntentCall.requireSuccess(t)
 	intentRecord, err = server.data.GetIntent(server.ctx, submitIntentCall.intentId)
 	require.NoError(t, err)
-	accountsToLock, err = NewReceivePaymentsPubliclyIntentHandler(server.service.conf, server.data, server.service.antispamGuard, nil).GetAdditionalAccountsToLock(server.ctx, intentRecord)
+	accountsToLock, err = NewReceivePaymentsPubliclyIntentHandler(server.service.conf, server.data, server.service.antispamGuard).GetAdditionalAccountsToLock(server.ctx, intentRecord)
 	require.NoError(t, err)
 	assert.Nil(t, accountsToLock.DestinationOwner)
 	require.NotNil(t, accountsToLock.RemoteSendGiftCardVault)
@@ -15,7 +15,6 @@ import (
 	"github.com/code-payments/code-server/pkg/code/common"
 	"github.com/code-payments/code-server/pkg/code/data/phone"
 	exchange_rate_util "github.com/code-payments/code-server/pkg/code/exchangerate"
-	"github.com/code-payments/code-server/pkg/code/limit"
 )
 
 func (s *transactionServer) GetLimits(ctx context.Context, req *transactionpb.GetLimitsRequest) (*transactionpb.GetLimitsResponse, error) {
@@ -35,12 +34,21 @@ func (s *transactionServer) GetLimits(ctx context.Context, req *transactionpb.Ge
 		return nil, err
 	}
 
+	limits, err := s.limits.GetLimits(ctx, ownerAccount)
+	if err != nil {
+		log.WithError(err).Warn("failure resolving limits")
+		return nil, status.Error(codes.Internal, "")
+	}
+	log = log.WithField("tier", limits.Tier.String())
+
 	zeroSendLimits := make(map[string]*transactionpb.RemainingSendLimit)
 	zeroMicroPaymentLimits := make(map[string]*transactionpb.MicroPaymentLimit)
-	for currency := range limit.SendLimits {
+	for currency := range limits.Send {
 		zeroSendLimits[string(currency)] = &transactionpb.RemainingSendLimit{
 			NextTransaction: 0,
 		}
+	}
+	for currency := range limits.MicroPayment {
 		zeroMicroPaymentLimits[string(currency)] = &transactionpb.MicroPaymentLimit{
 			MaxPerTransaction: 0,
 			MinPerTransaction: 0,
@@ -109,7 +117,7 @@ func (s *transactionServer) GetLimits(ctx context.Context, req *transactionpb.Ge
 	//
 
 	remainingSendLimits := make(map[string]*transactionpb.RemainingSendLimit)
-	for currency, sendLimit := range limit.SendLimits {
+	for currency, sendLimit := range limits.Send {
 		otherRate, ok := multiRateRecord.Rates[string(currency)]
 		if !ok {
 			log.WithError(err).Warnf("%s rate is missing", currency)
@@ -149,17 +157,18 @@ func (s *transactionServer) GetLimits(ctx context.Context, req *transactionpb.Ge
 	// Part 2: Calculate deposit limits
 	//
 
-	usdForNextDeposit := limit.MaxPerDepositUsdAmount
+	depositLimit := limits.GetDepositLimit()
+	usdForNextDeposit := depositLimit.PerDeposit
 
 	// Does the user already have sufficient balance in their organizer? If so,
 	// then the limit is completely nullified.
-	maxPerDepositQuarkAmount := kin.ToQuarks(uint64(limit.MaxPerDepositUsdAmount / usdRate))
+	maxPerDepositQuarkAmount := kin.ToQuarks(uint64(depositLimit.PerDeposit / usdRate))
 	if privateBalance >= maxPerDepositQuarkAmount {
 		usdForNextDeposit = 0
 	}
 
 	// How much of the daily limit is remaining?
-	remainingUsdForDeposits := limit.MaxDailyDepositUsdAmount - consumedUsdForDeposits
+	remainingUsdForDeposits := depositLimit.Daily - consumedUsdForDeposits
 
 	// The per-transaction limit applies up until our remaining daily limit is below it.
 	if remainingUsdForDeposits < usdForNextDeposit {
@@ -176,10 +185,10 @@ func (s *transactionServer) GetLimits(ctx context.Context, req *transactionpb.Ge
 	//
 
 	convertedMicroPaymentLimits := make(map[string]*transactionpb.MicroPaymentLimit)
-	for currency, limits := range limit.MicroPaymentLimits {
+	for currency, microPaymentLimit := range limits.MicroPayment {
 		convertedMicroPaymentLimits[string(currency)] = &transactionpb.MicroPaymentLimit{
-			MaxPerTransaction: float32(limits.Max),
-			MinPerTransaction: float32(limits.Min),
+			MaxPerTransaction: float32(microPaymentLimit.Max),
+			MinPerTransaction: float32(microPaymentLimit.Min),
 		}
 	}
 
@@ -92,13 +92,15 @@ func TestGetLimits_DepositLimits_HappyPath_DailyConsumed(t *testing.T) {
 	server.phoneVerifyUser(t, phone)
 	server.fundAccount(t, phone.getTimelockVault(t, commonpb.AccountType_PRIMARY, 0), kin.ToQuarks(10_000))
 
-	limit.MaxDailyDepositUsdAmount = 200 // Needs to be < 2x maxPerDepositUsdAmount so we don't trigger total balance checks
-	limit.MaxPerDepositUsdAmount = 150
+	// Daily deposit limit needs to be < 2x the per-deposit limit so we don't
+	// trigger total balance checks
+	server.setupUsdSendLimitOverride(t, phone, 125, 140)
+	depositLimit := limit.DepositLimit{PerDeposit: 150, Daily: 210}
 
 	usdRate, err := server.data.GetExchangeRate(server.ctx, currency_lib.USD, time.Now())
 	require.NoError(t, err)
 
-	maxPerDepositKinAmount := uint64(limit.MaxPerDepositUsdAmount / usdRate.Rate)
+	maxPerDepositKinAmount := uint64(depositLimit.PerDeposit / usdRate.Rate)
 
 	server.generateAvailableNonces(t, 10000)
 
@@ -109,7 +111,7 @@ func TestGetLimits_DepositLimits_HappyPath_DailyConsumed(t *testing.T) {
 
 	var usdSent float64
 	for {
-		if usdSent > limit.MaxDailyDepositUsdAmount-limit.MaxPerDepositUsdAmount {
+		if usdSent > depositLimit.Daily-depositLimit.PerDeposit {
 			break
 		}
 
@@ -122,7 +124,7 @@ func TestGetLimits_DepositLimits_HappyPath_DailyConsumed(t *testing.T) {
 	}
 
 	depositLimits = phone.getDepositLimit(t)
-	assert.EqualValues(t, kin.ToQuarks(uint64((limit.MaxDailyDepositUsdAmount-usdSent)/usdRate.Rate)), depositLimits.MaxQuarks)
+	assert.EqualValues(t, kin.ToQuarks(uint64((depositLimit.Daily-usdSent)/usdRate.Rate)), depositLimits.MaxQuarks)
 }
 
 func TestGetLimits_DepositLimits_HappyPath_ExistingPrivateBalance(t *testing.T) {
@@ -134,13 +136,13 @@ func TestGetLimits_DepositLimits_HappyPath_ExistingPrivateBalance(t *testing.T)
 	server.phoneVerifyUser(t, phone)
 	server.fundAccount(t, phone.getTimelockVault(t, commonpb.AccountType_PRIMARY, 0), kin.ToQuarks(10_000))
 
-	limit.MaxDailyDepositUsdAmount = 300
-	limit.MaxPerDepositUsdAmount = 150
+	server.setupUsdSendLimitOverride(t, phone, 125, 200)
+	depositLimit := limit.DepositLimit{PerDeposit: 150, Daily: 300}
 
 	usdRate, err := server.data.GetExchangeRate(server.ctx, currency_lib.USD, time.Now())
 	require.NoError(t, err)
 
-	maxPerDepositKinAmount := uint64(limit.MaxPerDepositUsdAmount / usdRate.Rate)
+	maxPerDepositKinAmount := uint64(depositLimit.PerDeposit / usdRate.Rate)
 
 	server.generateAvailableNonces(t, 10000)
 
@@ -160,6 +162,32 @@ func TestGetLimits_DepositLimits_HappyPath_ExistingPrivateBalance(t *testing.T)
 	assert.EqualValues(t, 0, depositLimits.MaxQuarks)
 }
 
+func TestGetLimits_SendLimits_Override(t *testing.T) {
+	server, phone, _, cleanup := setupTestEnv(t, &testOverrides{})
+	defer cleanup()
+
+	server.generateAvailableNonces(t, 10000)
+
+	phone.openAccounts(t).requireSuccess(t)
+
+	usdRate, err := server.data.GetExchangeRate(server.ctx, currency_lib.USD, time.Now())
+	require.NoError(t, err)
+
+	defaultUsdLimits := limit.SendLimits[currency_lib.USD]
+	actual := phone.getSendLimits(t)[string(currency_lib.USD)]
+	assert.EqualValues(t, defaultUsdLimits.PerTransaction, actual.NextTransaction)
+
+	server.setupUsdSendLimitOverride(t, phone, 2*defaultUsdLimits.PerTransaction, 2*defaultUsdLimits.Daily)
+
+	limitsByCurrency := phone.getSendLimits(t)
+
+	actual = limitsByCurrency[string(currency_lib.USD)]
+	assert.EqualValues(t, 2*defaultUsdLimits.PerTransaction, actual.NextTransaction)
+
+	actual = limitsByCurrency[string(currency_lib.KIN)]
+	assert.EqualValues(t, 2*defaultUsdLimits.PerTransaction/usdRate.Rate, actual.NextTransaction)
+}
+
 func TestGetLimits_MicroPaymentLimits_HappyPath(t *testing.T) {
 	server, phone, _, cleanup := setupTestEnv(t, &testOverrides{})
 	defer cleanup()
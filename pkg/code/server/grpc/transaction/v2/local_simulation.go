@@ -353,7 +353,7 @@ func LocalSimulation(ctx context.Context, data code_data.Provider, actions []*tr
 		}
 
 		// Validate authorities and respective derived timelock vault accounts match.
-		timelockAccounts, err := authority.GetTimelockAccounts(timelock_token_v1.DataVersion1)
+		timelockAccounts, err := authority.ResolveTimelockAccounts(ctx, data, timelock_token_v1.DataVersion1)
 		if err != nil {
 			return nil, err
 		}
@@ -16,6 +16,7 @@ import (
 	"github.com/code-payments/code-server/pkg/testutil"
 	"github.com/code-payments/code-server/pkg/code/common"
 	code_data "github.com/code-payments/code-server/pkg/code/data"
+	datatest "github.com/code-payments/code-server/pkg/code/data/test"
 	"github.com/code-payments/code-server/pkg/code/data/deposit"
 	"github.com/code-payments/code-server/pkg/code/data/transaction"
 )
@@ -612,7 +613,7 @@ type localSimulationTestEnv struct {
 }
 
 func setupLocalSimulationTestEnv(t *testing.T) localSimulationTestEnv {
-	data := code_data.NewTestDataProvider()
+	data := datatest.NewDataProvider()
 	testutil.SetupRandomSubsidizer(t, data)
 	return localSimulationTestEnv{
 		ctx:  context.Background(),
@@ -42,10 +42,11 @@ func recordSubmitIntentLatencyBreakdownEvent(ctx context.Context, section string
 	})
 }
 
-func recordAirdropEvent(ctx context.Context, owner *common.Account, airdropType AirdropType, usdValue float64) {
+func recordAirdropEvent(ctx context.Context, owner *common.Account, airdropType AirdropType, campaignName string, usdValue float64) {
 	metrics.RecordEvent(ctx, airdropEventName, map[string]interface{}{
 		"owner":        owner.PublicKey().ToBase58(),
 		"airdrop_type": airdropType.String(),
+		"campaign":     campaignName,
 		"usd_value":    usdValue,
 	})
 }
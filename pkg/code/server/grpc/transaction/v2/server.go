@@ -4,7 +4,6 @@ import (
 	"context"
 	"sync"
 
-	"github.com/oschwald/maxminddb-golang"
 	"github.com/sirupsen/logrus"
 
 	transactionpb "github.com/code-payments/code-protobuf-api/generated/go/transaction/v2"
@@ -14,6 +13,7 @@ import (
 	"github.com/code-payments/code-server/pkg/code/common"
 	code_data "github.com/code-payments/code-server/pkg/code/data"
 	"github.com/code-payments/code-server/pkg/code/lawenforcement"
+	limit_util "github.com/code-payments/code-server/pkg/code/limit"
 	"github.com/code-payments/code-server/pkg/code/server/grpc/messaging"
 	"github.com/code-payments/code-server/pkg/kin"
 	push_lib "github.com/code-payments/code-server/pkg/push"
@@ -30,13 +30,13 @@ type transactionServer struct {
 
 	pusher push_lib.Provider
 
-	maxmind *maxminddb.Reader
-
 	messagingClient messaging.InternalMessageClient
 
 	antispamGuard *antispam.Guard
 	amlGuard      *lawenforcement.AntiMoneyLaunderingGuard
 
+	limits *limit_util.Resolver
+
 	// todo: A better way of managing this if/when we do a treasury per individual transaction amount
 	treasuryPoolNameByBaseAmount map[uint64]string
 
@@ -50,8 +50,7 @@ type transactionServer struct {
 	giftCardLocks *sync_util.StripedLock
 	phoneLocks    *sync_util.StripedLock
 
-	airdropperLock sync.Mutex
-	airdropper     *common.TimelockAccounts
+	airdropper *common.TimelockAccounts
 
 	feeCollector *common.Account
 
@@ -62,7 +61,6 @@ func NewTransactionServer(
 	data code_data.Provider,
 	pusher push_lib.Provider,
 	antispamGuard *antispam.Guard,
-	maxmind *maxminddb.Reader,
 	messagingClient messaging.InternalMessageClient,
 	configProvider ConfigProvider,
 ) transactionpb.TransactionServer {
@@ -82,13 +80,13 @@ func NewTransactionServer(
 
 		pusher: pusher,
 
-		maxmind: maxmind,
-
 		messagingClient: messagingClient,
 
 		antispamGuard: antispamGuard,
 		amlGuard:      lawenforcement.NewAntiMoneyLaunderingGuard(data),
 
+		limits: limit_util.NewResolver(data),
+
 		intentLocks:   sync_util.NewStripedLock(stripedLockParallelization),
 		ownerLocks:    sync_util.NewStripedLock(stripedLockParallelization),
 		giftCardLocks: sync_util.NewStripedLock(stripedLockParallelization),
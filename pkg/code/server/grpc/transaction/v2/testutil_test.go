@@ -46,14 +46,17 @@ import (
 	chat_util "github.com/code-payments/code-server/pkg/code/chat"
 	"github.com/code-payments/code-server/pkg/code/common"
 	code_data "github.com/code-payments/code-server/pkg/code/data"
+	datatest "github.com/code-payments/code-server/pkg/code/data/test"
 	"github.com/code-payments/code-server/pkg/code/data/account"
 	"github.com/code-payments/code-server/pkg/code/data/action"
+	"github.com/code-payments/code-server/pkg/code/data/campaign"
 	"github.com/code-payments/code-server/pkg/code/data/chat"
 	"github.com/code-payments/code-server/pkg/code/data/commitment"
 	"github.com/code-payments/code-server/pkg/code/data/currency"
 	"github.com/code-payments/code-server/pkg/code/data/deposit"
 	"github.com/code-payments/code-server/pkg/code/data/fulfillment"
 	"github.com/code-payments/code-server/pkg/code/data/intent"
+	"github.com/code-payments/code-server/pkg/code/data/limit"
 	"github.com/code-payments/code-server/pkg/code/data/merkletree"
 	"github.com/code-payments/code-server/pkg/code/data/nonce"
 	"github.com/code-payments/code-server/pkg/code/data/payment"
@@ -66,6 +69,7 @@ import (
 	user_identity "github.com/code-payments/code-server/pkg/code/data/user/identity"
 	"github.com/code-payments/code-server/pkg/code/data/vault"
 	exchange_rate_util "github.com/code-payments/code-server/pkg/code/exchangerate"
+	limit_util "github.com/code-payments/code-server/pkg/code/limit"
 	"github.com/code-payments/code-server/pkg/code/server/grpc/messaging"
 	transaction_util "github.com/code-payments/code-server/pkg/code/transaction"
 )
@@ -79,7 +83,7 @@ func setupTestEnv(t *testing.T, serverOverrides *testOverrides) (serverTestEnv,
 		serverOverrides.submitIntentReceiveTimeout = defaultSubmitIntentReceiveTimeout
 	}
 
-	db := code_data.NewTestDataProvider()
+	db := datatest.NewDataProvider()
 
 	serverEnv := serverTestEnv{
 		ctx:                   context.Background(),
@@ -178,8 +182,7 @@ func setupTestEnv(t *testing.T, serverOverrides *testOverrides) (serverTestEnv,
 	testService := NewTransactionServer(
 		db,
 		memory_push.NewPushProvider(),
-		antispam.NewGuard(db, memory_device_verifier.NewMemoryDeviceVerifier(), nil),
-		nil,
+		antispam.NewGuard(db, memory_device_verifier.NewMemoryDeviceVerifier()),
 		messaging.NewMessagingClient(db),
 		withManualTestOverrides(serverOverrides),
 	)
@@ -616,6 +619,7 @@ func (s serverTestEnv) assertIntentRecordSaved(t *testing.T, intentId string, pr
 	assert.Equal(t, intentId, intentRecord.IntentId)
 	assert.Equal(t, sourcePhone.parentAccount.PublicKey().ToBase58(), intentRecord.InitiatorOwnerAccount)
 	assert.Equal(t, sourcePhone.verifiedPhoneNumber, *intentRecord.InitiatorPhoneNumber)
+	assert.Equal(t, kin.Mint, intentRecord.Mint)
 	assert.Equal(t, intent.StatePending, intentRecord.State)
 
 	switch typed := protoMetadata.Type.(type) {
@@ -1032,20 +1036,70 @@ func (s *serverTestEnv) setupAirdropper(t *testing.T, initialFunds uint64) *comm
 	return owner
 }
 
+func (s *serverTestEnv) setupUsdSendLimitOverride(t *testing.T, phone phoneTestEnv, perTransaction, daily float64) {
+	usdMicroPaymentLimits := limit_util.MicroPaymentLimits[currency_lib.USD]
+	require.NoError(t, s.data.PutLimits(s.ctx, &limit.Record{
+		OwnerAccount: pointer.String(phone.parentAccount.PublicKey().ToBase58()),
+
+		Currency: currency_lib.USD,
+
+		SendPerTransaction: perTransaction,
+		SendDaily:          daily,
+
+		MicroPaymentMin: usdMicroPaymentLimits.Min,
+		MicroPaymentMax: usdMicroPaymentLimits.Max,
+
+		EffectiveAt: time.Now().Add(-time.Minute),
+	}))
+}
+
+func (s *serverTestEnv) setupCampaign(t *testing.T, name string, campaignType campaign.Type, usdAmount, budgetUsd float64) *campaign.Record {
+	campaignRecord := &campaign.Record{
+		Name: name,
+		Type: campaignType,
+
+		Amounts: map[currency_lib.Code]float64{
+			currency_lib.USD: usdAmount,
+		},
+		BudgetUsd: budgetUsd,
+
+		EligibilityRules: campaign.EligibilityRules{
+			MaxPayoutsPerPhoneNumber: 1,
+			MaxPayoutsPerOwner:       1,
+		},
+
+		StartsAt: time.Now().Add(-time.Hour),
+		EndsAt:   time.Now().Add(time.Hour),
+
+		State: campaign.StateEnabled,
+
+		CreatedAt: time.Now(),
+	}
+	require.NoError(t, s.data.PutCampaign(s.ctx, campaignRecord))
+	return campaignRecord
+}
+
+func (s *serverTestEnv) setupWelcomeBonusCampaign(t *testing.T, budgetUsd float64) *campaign.Record {
+	return s.setupCampaign(t, defaultWelcomeBonusCampaign, campaign.WelcomeBonusType, 1.0, budgetUsd)
+}
+
 func (s *serverTestEnv) assertAirdroppedFirstKin(t *testing.T, phone phoneTestEnv) {
 	airdropIntentId := GetNewAirdropIntentId(AirdropTypeGetFirstKin, phone.parentAccount.PublicKey().ToBase58())
-	s.assertAirdropped(t, phone, AirdropTypeGetFirstKin, airdropIntentId, 1.0)
+	s.assertAirdropPayoutQueued(t, phone, defaultWelcomeBonusCampaign, airdropIntentId, 1.0)
 }
 
 func (s *serverTestEnv) assertNotAirdroppedFirstKin(t *testing.T, phone phoneTestEnv) {
+	_, err := s.data.GetCampaignPayout(s.ctx, defaultWelcomeBonusCampaign, phone.parentAccount.PublicKey().ToBase58())
+	assert.Equal(t, campaign.ErrPayoutNotFound, err)
+
 	airdropIntentId := GetNewAirdropIntentId(AirdropTypeGetFirstKin, phone.parentAccount.PublicKey().ToBase58())
-	_, err := s.data.GetIntent(s.ctx, airdropIntentId)
+	_, err = s.data.GetIntent(s.ctx, airdropIntentId)
 	assert.Equal(t, intent.ErrIntentNotFound, err)
 }
 
 func (s *serverTestEnv) assertAirdroppedForGivingFirstKin(t *testing.T, phone phoneTestEnv, intentId string) {
 	airdropIntentId := GetNewAirdropIntentId(AirdropTypeGiveFirstKin, intentId)
-	s.assertAirdropped(t, phone, AirdropTypeGiveFirstKin, airdropIntentId, 5.0)
+	s.assertAirdropPayoutQueued(t, phone, defaultReferralBonusCampaign, airdropIntentId, 5.0)
 }
 
 func (s *serverTestEnv) assertNotAirdroppedForGivingFirstKin(t *testing.T, intentId string) {
@@ -1054,90 +1108,59 @@ func (s *serverTestEnv) assertNotAirdroppedForGivingFirstKin(t *testing.T, inten
 	assert.Equal(t, intent.ErrIntentNotFound, err)
 }
 
-func (s serverTestEnv) assertAirdropped(t *testing.T, phone phoneTestEnv, airdropType AirdropType, intentId string, usdValue float64) {
-	airdropIntentRecord, err := s.data.GetIntent(s.ctx, intentId)
+func (s serverTestEnv) assertAirdropPayoutQueued(t *testing.T, phone phoneTestEnv, campaignName, intentId string, usdValue float64) {
+	payoutRecord, err := s.data.GetCampaignPayoutByIntentId(s.ctx, intentId)
 	require.NoError(t, err)
 
 	expectedQuarks := kin.ToQuarks(uint64(usdValue / 0.1))
 	expectedQuarks += kin.ToQuarks(1)
 
-	assert.Equal(t, airdropIntentRecord.IntentType, intent.SendPublicPayment)
-	assert.Equal(t, s.service.airdropper.VaultOwner.PublicKey().ToBase58(), airdropIntentRecord.InitiatorOwnerAccount)
-	assert.Nil(t, airdropIntentRecord.InitiatorPhoneNumber)
-	assert.Equal(t, phone.parentAccount.PublicKey().ToBase58(), airdropIntentRecord.SendPublicPaymentMetadata.DestinationOwnerAccount)
-	assert.Equal(t, phone.getTimelockVault(t, commonpb.AccountType_PRIMARY, 0).PublicKey().ToBase58(), airdropIntentRecord.SendPublicPaymentMetadata.DestinationTokenAccount)
-	assert.Equal(t, expectedQuarks, airdropIntentRecord.SendPublicPaymentMetadata.Quantity)
-	assert.Equal(t, currency_lib.USD, airdropIntentRecord.SendPublicPaymentMetadata.ExchangeCurrency)
-	assert.Equal(t, 0.1, airdropIntentRecord.SendPublicPaymentMetadata.ExchangeRate)
-	assert.Equal(t, usdValue, airdropIntentRecord.SendPublicPaymentMetadata.NativeAmount)
-	assert.Equal(t, usdValue, airdropIntentRecord.SendPublicPaymentMetadata.UsdMarketValue)
-	assert.True(t, airdropIntentRecord.SendPublicPaymentMetadata.IsWithdrawal)
-	assert.Equal(t, intent.StatePending, airdropIntentRecord.State)
-
-	airdropActionRecords, err := s.data.GetAllActionsByIntent(s.ctx, airdropIntentRecord.IntentId)
-	require.NoError(t, err)
-	require.Len(t, airdropActionRecords, 1)
-	airdropActionRecord := airdropActionRecords[0]
-
-	assert.Equal(t, airdropIntentRecord.IntentId, airdropActionRecord.Intent)
-	assert.Equal(t, airdropIntentRecord.IntentType, airdropActionRecord.IntentType)
-	assert.EqualValues(t, 0, airdropActionRecord.ActionId)
-	assert.Equal(t, action.NoPrivacyTransfer, airdropActionRecord.ActionType)
-	assert.Equal(t, s.service.airdropper.Vault.PublicKey().ToBase58(), airdropActionRecord.Source)
-	assert.Equal(t, phone.getTimelockVault(t, commonpb.AccountType_PRIMARY, 0).PublicKey().ToBase58(), *airdropActionRecord.Destination)
-	assert.Equal(t, expectedQuarks, *airdropActionRecord.Quantity)
-	assert.Nil(t, airdropActionRecord.InitiatorPhoneNumber)
-	assert.Equal(t, action.StatePending, airdropActionRecord.State)
-
-	airdropFulfillmentRecords, err := s.data.GetAllFulfillmentsByAction(s.ctx, airdropActionRecord.Intent, 0)
-	require.NoError(t, err)
-	require.Len(t, airdropFulfillmentRecords, 1)
-	airdropFulfillmentRecord := airdropFulfillmentRecords[0]
-
-	assert.Equal(t, airdropIntentRecord.IntentId, airdropFulfillmentRecord.Intent)
-	assert.Equal(t, airdropIntentRecord.IntentType, airdropFulfillmentRecord.IntentType)
-	assert.Equal(t, airdropFulfillmentRecord.ActionId, airdropFulfillmentRecord.ActionId)
-	assert.Equal(t, airdropFulfillmentRecord.ActionType, airdropFulfillmentRecord.ActionType)
-	assert.Equal(t, fulfillment.NoPrivacyTransferWithAuthority, airdropFulfillmentRecord.FulfillmentType)
-	assert.Equal(t, s.service.airdropper.Vault.PublicKey().ToBase58(), airdropFulfillmentRecord.Source)
-	assert.Equal(t, phone.getTimelockVault(t, commonpb.AccountType_PRIMARY, 0).PublicKey().ToBase58(), *airdropFulfillmentRecord.Destination)
-	assert.Nil(t, airdropFulfillmentRecord.InitiatorPhoneNumber)
-	assert.Equal(t, airdropIntentRecord.Id, airdropFulfillmentRecord.IntentOrderingIndex)
-	assert.EqualValues(t, 0, airdropFulfillmentRecord.ActionOrderingIndex)
-	assert.EqualValues(t, 0, airdropFulfillmentRecord.FulfillmentOrderingIndex)
-	assert.False(t, airdropFulfillmentRecord.DisableActiveScheduling)
-	assert.Equal(t, fulfillment.StateUnknown, airdropFulfillmentRecord.State)
-	s.assertSignedTransaction(t, airdropFulfillmentRecord)
-	s.assertExpectedTransferWithAuthorityTransaction(t, airdropFulfillmentRecord, s.service.airdropper.VaultOwner, phone.getTimelockVault(t, commonpb.AccountType_PRIMARY, 0), expectedQuarks)
-	s.assertNoncesAreReserved(t, airdropIntentRecord.IntentId)
-
-	messages, err := s.data.GetMessages(s.ctx, airdropIntentRecord.SendPublicPaymentMetadata.DestinationOwnerAccount)
+	assert.Equal(t, campaignName, payoutRecord.Campaign)
+	assert.Equal(t, intentId, payoutRecord.IntentId)
+	assert.Equal(t, phone.parentAccount.PublicKey().ToBase58(), payoutRecord.OwnerAccount)
+	assert.Equal(t, phone.verifiedPhoneNumber, payoutRecord.PhoneNumber)
+	assert.Equal(t, currency_lib.USD, payoutRecord.ExchangeCurrency)
+	assert.Equal(t, 0.1, payoutRecord.ExchangeRate)
+	assert.Equal(t, usdValue, payoutRecord.NativeAmount)
+	assert.Equal(t, usdValue, payoutRecord.UsdValue)
+	assert.Equal(t, expectedQuarks, payoutRecord.Quantity)
+	assert.Equal(t, campaign.PayoutStatePending, payoutRecord.State)
+
+	// Intents are created asynchronously by the campaign worker
+	_, err = s.data.GetIntent(s.ctx, intentId)
+	assert.Equal(t, intent.ErrIntentNotFound, err)
+}
+
+// simulateCampaignPayoutHistoryItem simulates the campaign worker having
+// submitted the intent for a queued payout
+func (s *serverTestEnv) simulateCampaignPayoutHistoryItem(t *testing.T, phone phoneTestEnv, campaignName string) {
+	payoutRecord, err := s.data.GetCampaignPayout(s.ctx, campaignName, phone.parentAccount.PublicKey().ToBase58())
 	require.NoError(t, err)
 
-	if airdropType == AirdropTypeGetFirstKin {
-		assert.Empty(t, messages)
-	} else {
-		require.Len(t, messages, 1) // todo: all tests currently do 1 incentive at a time
+	intentRecord := &intent.Record{
+		IntentId:   payoutRecord.IntentId,
+		IntentType: intent.SendPublicPayment,
 
-		message := messages[0]
-		idBytes, err := message.MessageID.MarshalBinary()
-		require.NoError(t, err)
+		InitiatorOwnerAccount: s.service.airdropper.VaultOwner.PublicKey().ToBase58(),
 
-		assert.Equal(t, airdropIntentRecord.SendPublicPaymentMetadata.DestinationOwnerAccount, message.Account)
+		SendPublicPaymentMetadata: &intent.SendPublicPaymentMetadata{
+			DestinationOwnerAccount: payoutRecord.OwnerAccount,
+			DestinationTokenAccount: phone.getTimelockVault(t, commonpb.AccountType_PRIMARY, 0).PublicKey().ToBase58(),
+			Quantity:                payoutRecord.Quantity,
 
-		var protoMessage messagingpb.Message
-		require.NoError(t, proto.Unmarshal(message.Message, &protoMessage))
+			ExchangeCurrency: payoutRecord.ExchangeCurrency,
+			ExchangeRate:     payoutRecord.ExchangeRate,
+			NativeAmount:     payoutRecord.NativeAmount,
+			UsdMarketValue:   payoutRecord.UsdValue,
 
-		assert.Equal(t, idBytes, protoMessage.Id.Value)
-		assert.Nil(t, protoMessage.SendMessageRequestSignature)
-		require.NotNil(t, protoMessage.GetAirdropReceived())
-		assert.EqualValues(t, airdropType, protoMessage.GetAirdropReceived().AirdropType)
-		assert.EqualValues(t, airdropIntentRecord.SendPublicPaymentMetadata.ExchangeCurrency, protoMessage.GetAirdropReceived().ExchangeData.Currency)
-		assert.Equal(t, airdropIntentRecord.SendPublicPaymentMetadata.ExchangeRate, protoMessage.GetAirdropReceived().ExchangeData.ExchangeRate)
-		assert.Equal(t, airdropIntentRecord.SendPublicPaymentMetadata.NativeAmount, protoMessage.GetAirdropReceived().ExchangeData.NativeAmount)
-		assert.Equal(t, airdropIntentRecord.SendPublicPaymentMetadata.Quantity, protoMessage.GetAirdropReceived().ExchangeData.Quarks)
-		assert.Equal(t, airdropIntentRecord.CreatedAt.Unix(), protoMessage.GetAirdropReceived().Timestamp.AsTime().Unix())
+			IsWithdrawal: true,
+		},
+
+		State:     intent.StatePending,
+		CreatedAt: time.Now(),
 	}
+	require.NoError(t, s.data.SaveIntent(s.ctx, intentRecord))
+	require.NoError(t, s.data.UpdateCampaignPayoutState(s.ctx, payoutRecord.IntentId, campaign.PayoutStateSubmitted))
 }
 
 func (s serverTestEnv) assertNoNoncesReserved(t *testing.T) {
@@ -1225,7 +1248,7 @@ func (s serverTestEnv) assertLatestAccountRecordsSaved(t *testing.T, phone phone
 
 		authorityAccount, index := phone.getAuthorityForLatestAccount(t, accountType)
 
-		timelockAccounts, err := authorityAccount.GetTimelockAccounts(timelock_token_v1.DataVersion1)
+		timelockAccounts, err := authorityAccount.ResolveTimelockAccounts(s.ctx, s.data, timelock_token_v1.DataVersion1)
 		require.NoError(t, err)
 
 		assert.Equal(t, accountType, accountRecords.General.AccountType)
@@ -1244,8 +1267,8 @@ func (s serverTestEnv) assertLatestAccountRecordsSaved(t *testing.T, phone phone
 		assert.Equal(t, timelockAccounts.VaultBump, accountRecords.Timelock.VaultBump)
 		assert.Equal(t, authorityAccount.PublicKey().ToBase58(), accountRecords.Timelock.VaultOwner)
 		assert.Equal(t, timelock_token_v1.StateUnknown, accountRecords.Timelock.VaultState)
-		assert.Equal(t, s.subsidizer.PublicKey().ToBase58(), accountRecords.Timelock.TimeAuthority)
-		assert.Equal(t, s.subsidizer.PublicKey().ToBase58(), accountRecords.Timelock.CloseAuthority)
+		assert.Equal(t, timelockAccounts.TimeAuthority.PublicKey().ToBase58(), accountRecords.Timelock.TimeAuthority)
+		assert.Equal(t, timelockAccounts.CloseAuthority.PublicKey().ToBase58(), accountRecords.Timelock.CloseAuthority)
 		assert.Equal(t, timelock_token_v1.DefaultNumDaysLocked, accountRecords.Timelock.NumDaysLocked)
 		assert.Nil(t, accountRecords.Timelock.UnlockAt)
 		assert.EqualValues(t, 0, accountRecords.Timelock.Block)
@@ -1254,7 +1277,7 @@ func (s serverTestEnv) assertLatestAccountRecordsSaved(t *testing.T, phone phone
 
 // todo: there's duplication of account record check code
 func (s serverTestEnv) assertRemoteSendGiftCardAccountRecordsSaved(t *testing.T, authorityAccount *common.Account) {
-	timelockAccounts, err := authorityAccount.GetTimelockAccounts(timelock_token_v1.DataVersion1)
+	timelockAccounts, err := authorityAccount.ResolveTimelockAccounts(s.ctx, s.data, timelock_token_v1.DataVersion1)
 	require.NoError(t, err)
 
 	accountInfoRecord, err := s.data.GetAccountInfoByTokenAddress(s.ctx, timelockAccounts.Vault.PublicKey().ToBase58())
@@ -1290,7 +1313,7 @@ func (s serverTestEnv) assertFirstRelationshipAccountRecordsSaved(t *testing.T,
 			continue
 		}
 
-		timelockAccounts, err := derivedAccount.value.GetTimelockAccounts(timelock_token_v1.DataVersion1)
+		timelockAccounts, err := derivedAccount.value.ResolveTimelockAccounts(s.ctx, s.data, timelock_token_v1.DataVersion1)
 		require.NoError(t, err)
 
 		accountInfoRecord, err := s.data.GetAccountInfoByAuthorityAddress(s.ctx, derivedAccount.value.PublicKey().ToBase58())
@@ -1333,7 +1356,7 @@ func (s serverTestEnv) assertNoRelationshipAccountRecordsSaved(t *testing.T, pho
 			continue
 		}
 
-		timelockAccounts, err := derivedAccount.value.GetTimelockAccounts(timelock_token_v1.DataVersion1)
+		timelockAccounts, err := derivedAccount.value.ResolveTimelockAccounts(s.ctx, s.data, timelock_token_v1.DataVersion1)
 		require.NoError(t, err)
 
 		_, err = s.data.GetAccountInfoByAuthorityAddress(s.ctx, derivedAccount.value.PublicKey().ToBase58())
@@ -1489,7 +1512,6 @@ func (s serverTestEnv) assertSignedTransaction(t *testing.T, fulfillmentRecord *
 	var txn solana.Transaction
 	require.NoError(t, txn.Unmarshal(fulfillmentRecord.Data))
 
-	assert.True(t, int(txn.Message.Header.NumSignatures) < 3)
 
 	var expectedSignatureCount int
 	switch fulfillmentRecord.FulfillmentType {
@@ -1503,14 +1525,22 @@ func (s serverTestEnv) assertSignedTransaction(t *testing.T, fulfillmentRecord *
 	default:
 		expectedSignatureCount = 1
 	}
+
+	// Timelock accounts opened with a retired subsidizer also require its signature
+	if fulfillmentRecord.Source != s.subsidizer.PublicKey().ToBase58() {
+		timelockRecord, err := s.data.GetTimelockByVault(s.ctx, fulfillmentRecord.Source)
+		if err == nil && timelockRecord.TimeAuthority != s.subsidizer.PublicKey().ToBase58() {
+			expectedSignatureCount++
+		}
+	}
 	assert.EqualValues(t, expectedSignatureCount, txn.Message.Header.NumSignatures)
 
 	transactionId := ed25519.Sign(s.subsidizer.PrivateKey().ToBytes(), txn.Message.Marshal())
 	assert.EqualValues(t, txn.Signatures[0][:], transactionId)
 	assert.Equal(t, base58.Encode(transactionId), *fulfillmentRecord.Signature)
 
-	if int(txn.Message.Header.NumSignatures) > 1 {
-		assert.True(t, ed25519.Verify(txn.Message.Accounts[1], txn.Message.Marshal(), txn.Signatures[1][:]))
+	for i := 1; i < int(txn.Message.Header.NumSignatures); i++ {
+		assert.True(t, ed25519.Verify(txn.Message.Accounts[i], txn.Message.Marshal(), txn.Signatures[i][:]))
 	}
 }
 
@@ -1535,7 +1565,7 @@ func (s serverTestEnv) assertExpectedCloseEmptyTimelockAccountTransaction(t *tes
 		dataVersion = timelock_token_v1.DataVersionLegacy
 	}
 
-	timelockAccounts, err := authority.GetTimelockAccounts(dataVersion)
+	timelockAccounts, err := authority.ResolveTimelockAccounts(s.ctx, s.data, dataVersion)
 	require.NoError(t, err)
 
 	if dataVersion == timelock_token_v1.DataVersion1 {
@@ -1548,7 +1578,7 @@ func (s serverTestEnv) assertExpectedCloseEmptyTimelockAccountTransaction(t *tes
 		assert.EqualValues(t, timelockAccounts.State.PublicKey().ToBytes(), burnIxnAccounts.Timelock)
 		assert.EqualValues(t, timelockAccounts.Vault.PublicKey().ToBytes(), burnIxnAccounts.Vault)
 		assert.EqualValues(t, timelockAccounts.VaultOwner.PublicKey().ToBytes(), burnIxnAccounts.VaultOwner)
-		assert.EqualValues(t, s.subsidizer.PublicKey().ToBytes(), burnIxnAccounts.TimeAuthority)
+		assert.EqualValues(t, timelockAccounts.TimeAuthority.PublicKey().ToBytes(), burnIxnAccounts.TimeAuthority)
 		assert.EqualValues(t, kin.TokenMint, burnIxnAccounts.Mint)
 		assert.EqualValues(t, s.subsidizer.PublicKey().ToBytes(), burnIxnAccounts.Payer)
 
@@ -1559,7 +1589,7 @@ func (s serverTestEnv) assertExpectedCloseEmptyTimelockAccountTransaction(t *tes
 
 		assert.EqualValues(t, timelockAccounts.State.PublicKey().ToBytes(), closeIxnAccounts.Timelock)
 		assert.EqualValues(t, timelockAccounts.Vault.PublicKey().ToBytes(), closeIxnAccounts.Vault)
-		assert.EqualValues(t, s.subsidizer.PublicKey().ToBytes(), closeIxnAccounts.CloseAuthority)
+		assert.EqualValues(t, timelockAccounts.CloseAuthority.PublicKey().ToBytes(), closeIxnAccounts.CloseAuthority)
 		assert.EqualValues(t, s.subsidizer.PublicKey().ToBytes(), closeIxnAccounts.Payer)
 	} else {
 		burnIxnArgs, burnIxnAccounts, err := timelock_token_legacy.BurnDustWithAuthorityInstructionFromLegacyInstruction(txn, 1)
@@ -1571,7 +1601,7 @@ func (s serverTestEnv) assertExpectedCloseEmptyTimelockAccountTransaction(t *tes
 		assert.EqualValues(t, timelockAccounts.State.PublicKey().ToBytes(), burnIxnAccounts.Timelock)
 		assert.EqualValues(t, timelockAccounts.Vault.PublicKey().ToBytes(), burnIxnAccounts.Vault)
 		assert.EqualValues(t, timelockAccounts.VaultOwner.PublicKey().ToBytes(), burnIxnAccounts.VaultOwner)
-		assert.EqualValues(t, s.subsidizer.PublicKey().ToBytes(), burnIxnAccounts.TimeAuthority)
+		assert.EqualValues(t, timelockAccounts.TimeAuthority.PublicKey().ToBytes(), burnIxnAccounts.TimeAuthority)
 		assert.EqualValues(t, kin.TokenMint, burnIxnAccounts.Mint)
 		assert.EqualValues(t, s.subsidizer.PublicKey().ToBytes(), burnIxnAccounts.Payer)
 
@@ -1582,7 +1612,7 @@ func (s serverTestEnv) assertExpectedCloseEmptyTimelockAccountTransaction(t *tes
 
 		assert.EqualValues(t, timelockAccounts.State.PublicKey().ToBytes(), closeIxnAccounts.Timelock)
 		assert.EqualValues(t, timelockAccounts.Vault.PublicKey().ToBytes(), closeIxnAccounts.Vault)
-		assert.EqualValues(t, s.subsidizer.PublicKey().ToBytes(), closeIxnAccounts.CloseAuthority)
+		assert.EqualValues(t, timelockAccounts.CloseAuthority.PublicKey().ToBytes(), closeIxnAccounts.CloseAuthority)
 		assert.EqualValues(t, s.subsidizer.PublicKey().ToBytes(), closeIxnAccounts.Payer)
 	}
 }
@@ -1603,7 +1633,7 @@ func (s serverTestEnv) assertExpectedCloseTimelockAccountWithBalanceTransaction(
 		dataVersion = timelock_token_v1.DataVersionLegacy
 	}
 
-	timelockAccounts, err := authority.GetTimelockAccounts(dataVersion)
+	timelockAccounts, err := authority.ResolveTimelockAccounts(s.ctx, s.data, dataVersion)
 	require.NoError(t, err)
 
 	if dataVersion == timelock_token_v1.DataVersion1 {
@@ -1614,7 +1644,7 @@ func (s serverTestEnv) assertExpectedCloseTimelockAccountWithBalanceTransaction(
 
 		assert.EqualValues(t, timelockAccounts.State.PublicKey().ToBytes(), revokeIxnAccounts.Timelock)
 		assert.EqualValues(t, timelockAccounts.Vault.PublicKey().ToBytes(), revokeIxnAccounts.Vault)
-		assert.EqualValues(t, s.subsidizer.PublicKey().ToBytes(), revokeIxnAccounts.TimeAuthority)
+		assert.EqualValues(t, timelockAccounts.TimeAuthority.PublicKey().ToBytes(), revokeIxnAccounts.TimeAuthority)
 		assert.EqualValues(t, s.subsidizer.PublicKey().ToBytes(), revokeIxnAccounts.Payer)
 
 		deactivateIxnArgs, deactivateIxnAccounts, err := timelock_token_v1.DeactivateInstructionFromLegacyInstruction(txn, 3)
@@ -1644,7 +1674,7 @@ func (s serverTestEnv) assertExpectedCloseTimelockAccountWithBalanceTransaction(
 
 		assert.EqualValues(t, timelockAccounts.State.PublicKey().ToBytes(), closeIxnAccounts.Timelock)
 		assert.EqualValues(t, timelockAccounts.Vault.PublicKey().ToBytes(), closeIxnAccounts.Vault)
-		assert.EqualValues(t, s.subsidizer.PublicKey().ToBytes(), closeIxnAccounts.CloseAuthority)
+		assert.EqualValues(t, timelockAccounts.CloseAuthority.PublicKey().ToBytes(), closeIxnAccounts.CloseAuthority)
 		assert.EqualValues(t, s.subsidizer.PublicKey().ToBytes(), closeIxnAccounts.Payer)
 	} else {
 		revokeIxnArgs, revokeIxnAccounts, err := timelock_token_legacy.RevokeLockWithAuthorityFromLegacyInstruction(txn, 2)
@@ -1654,7 +1684,7 @@ func (s serverTestEnv) assertExpectedCloseTimelockAccountWithBalanceTransaction(
 
 		assert.EqualValues(t, timelockAccounts.State.PublicKey().ToBytes(), revokeIxnAccounts.Timelock)
 		assert.EqualValues(t, timelockAccounts.Vault.PublicKey().ToBytes(), revokeIxnAccounts.Vault)
-		assert.EqualValues(t, s.subsidizer.PublicKey().ToBytes(), revokeIxnAccounts.TimeAuthority)
+		assert.EqualValues(t, timelockAccounts.TimeAuthority.PublicKey().ToBytes(), revokeIxnAccounts.TimeAuthority)
 		assert.EqualValues(t, s.subsidizer.PublicKey().ToBytes(), revokeIxnAccounts.Payer)
 
 		deactivateIxnArgs, deactivateIxnAccounts, err := timelock_token_legacy.DeactivateInstructionFromLegacyInstruction(txn, 3)
@@ -1684,7 +1714,7 @@ func (s serverTestEnv) assertExpectedCloseTimelockAccountWithBalanceTransaction(
 
 		assert.EqualValues(t, timelockAccounts.State.PublicKey().ToBytes(), closeIxnAccounts.Timelock)
 		assert.EqualValues(t, timelockAccounts.Vault.PublicKey().ToBytes(), closeIxnAccounts.Vault)
-		assert.EqualValues(t, s.subsidizer.PublicKey().ToBytes(), closeIxnAccounts.CloseAuthority)
+		assert.EqualValues(t, timelockAccounts.CloseAuthority.PublicKey().ToBytes(), closeIxnAccounts.CloseAuthority)
 		assert.EqualValues(t, s.subsidizer.PublicKey().ToBytes(), closeIxnAccounts.Payer)
 	}
 }
@@ -1700,7 +1730,7 @@ func (s serverTestEnv) assertExpectedTransferWithAuthorityTransaction(t *testing
 
 	assertExpectedKreMemoInstruction(t, txn, 1)
 
-	sourceTimelockAccounts, err := authority.GetTimelockAccounts(timelock_token_v1.DataVersion1)
+	sourceTimelockAccounts, err := authority.ResolveTimelockAccounts(s.ctx, s.data, timelock_token_v1.DataVersion1)
 	require.NoError(t, err)
 
 	transferIxnArgs, transferIxnAccounts, err := timelock_token_v1.TransferWithAuthorityInstructionFromLegacyInstruction(txn, 2)
@@ -1712,7 +1742,7 @@ func (s serverTestEnv) assertExpectedTransferWithAuthorityTransaction(t *testing
 	assert.EqualValues(t, sourceTimelockAccounts.State.PublicKey().ToBytes(), transferIxnAccounts.Timelock)
 	assert.EqualValues(t, sourceTimelockAccounts.Vault.PublicKey().ToBytes(), transferIxnAccounts.Vault)
 	assert.EqualValues(t, sourceTimelockAccounts.VaultOwner.PublicKey().ToBytes(), transferIxnAccounts.VaultOwner)
-	assert.EqualValues(t, s.subsidizer.PublicKey().ToBytes(), transferIxnAccounts.TimeAuthority)
+	assert.EqualValues(t, sourceTimelockAccounts.TimeAuthority.PublicKey().ToBytes(), transferIxnAccounts.TimeAuthority)
 	assert.EqualValues(t, destination.PublicKey().ToBytes(), transferIxnAccounts.Destination)
 	assert.EqualValues(t, s.subsidizer.PublicKey().ToBytes(), transferIxnAccounts.Payer)
 }
@@ -4675,6 +4705,8 @@ func (p *phoneTestEnv) validateUpgradeableIntents(t *testing.T, upgradeableInten
 			// local state.
 
 			assert.Equal(t, txnToUpgrade.originalTransactionBlob, action.TransactionBlob.Value)
+			clientSignatureIndex, ok := getClientSignatureIndex(&txn)
+			require.True(t, ok)
 			assert.True(t, ed25519.Verify(txn.Message.Accounts[clientSignatureIndex], txn.Message.Marshal(), action.ClientSignature.Value))
 
 			assert.Equal(t, txnToUpgrade.treasuryPool.PublicKey().ToBytes(), action.Treasury.Value)
@@ -4767,7 +4799,7 @@ func (p *phoneTestEnv) getCloseEmptyAccountTransactionToSign(
 	authority, err := common.NewAccountFromProto(action.Authority)
 	require.NoError(t, err)
 
-	timelockAccounts, err := authority.GetTimelockAccounts(dataVersion)
+	timelockAccounts, err := authority.ResolveTimelockAccounts(p.ctx, p.directServerAccess.data, dataVersion)
 	require.NoError(t, err)
 
 	txn, err := transaction_util.MakeCloseEmptyAccountTransaction(
@@ -4794,7 +4826,7 @@ func (p *phoneTestEnv) getCloseDormantAccountTransactionToSign(
 	authority, err := common.NewAccountFromProto(action.Authority)
 	require.NoError(t, err)
 
-	timelockAccounts, err := authority.GetTimelockAccounts(timelock_token_v1.DataVersion1)
+	timelockAccounts, err := authority.ResolveTimelockAccounts(p.ctx, p.directServerAccess.data, timelock_token_v1.DataVersion1)
 	require.NoError(t, err)
 
 	destination, err := common.NewAccountFromProto(action.Destination)
@@ -4832,7 +4864,7 @@ func (p *phoneTestEnv) getNoPrivacyTransferTransactionToSign(
 	authority, err := common.NewAccountFromProto(action.Authority)
 	require.NoError(t, err)
 
-	timelockAccounts, err := authority.GetTimelockAccounts(dataVersion)
+	timelockAccounts, err := authority.ResolveTimelockAccounts(p.ctx, p.directServerAccess.data, dataVersion)
 	require.NoError(t, err)
 
 	destination, err := common.NewAccountFromProto(action.Destination)
@@ -4871,7 +4903,7 @@ func (p *phoneTestEnv) getNoPrivacyWithdrawTransactionToSign(
 	authority, err := common.NewAccountFromProto(action.Authority)
 	require.NoError(t, err)
 
-	timelockAccounts, err := authority.GetTimelockAccounts(dataVersion)
+	timelockAccounts, err := authority.ResolveTimelockAccounts(p.ctx, p.directServerAccess.data, dataVersion)
 	require.NoError(t, err)
 
 	destination, err := common.NewAccountFromProto(action.Destination)
@@ -4908,7 +4940,7 @@ func (p *phoneTestEnv) getTemporaryPrivacyTransferTransactionToSign(
 	authority, err := common.NewAccountFromProto(action.Authority)
 	require.NoError(t, err)
 
-	timelockAccounts, err := authority.GetTimelockAccounts(timelock_token_v1.DataVersion1)
+	timelockAccounts, err := authority.ResolveTimelockAccounts(p.ctx, p.directServerAccess.data, timelock_token_v1.DataVersion1)
 	require.NoError(t, err)
 
 	destination, err := common.NewAccountFromProto(action.Destination)
@@ -4990,7 +5022,7 @@ func (p *phoneTestEnv) getTemporaryPrivacyExchangeTransactionToSign(
 	authority, err := common.NewAccountFromProto(action.Authority)
 	require.NoError(t, err)
 
-	timelockAccounts, err := authority.GetTimelockAccounts(timelock_token_v1.DataVersion1)
+	timelockAccounts, err := authority.ResolveTimelockAccounts(p.ctx, p.directServerAccess.data, timelock_token_v1.DataVersion1)
 	require.NoError(t, err)
 
 	destination, err := common.NewAccountFromProto(action.Destination)
@@ -5133,7 +5165,7 @@ func (p *phoneTestEnv) getFeePaymentTransactionToSign(
 	authority, err := common.NewAccountFromProto(action.Authority)
 	require.NoError(t, err)
 
-	timelockAccounts, err := authority.GetTimelockAccounts(timelock_token_v1.DataVersion1)
+	timelockAccounts, err := authority.ResolveTimelockAccounts(p.ctx, p.directServerAccess.data, timelock_token_v1.DataVersion1)
 	require.NoError(t, err)
 
 	destination, err := common.NewAccountFromProto(serverParameter.Destination)
@@ -5223,7 +5255,10 @@ func (p *phoneTestEnv) reset(t *testing.T) {
 }
 
 func (p *phoneTestEnv) getTimelockVault(t *testing.T, accountType commonpb.AccountType, index uint64) *common.Account {
-	return getTimelockVault(t, p.getAuthority(t, accountType, index))
+	// Like a real client, use the subsidizer the account was opened with
+	timelockAccounts, err := p.getAuthority(t, accountType, index).ResolveTimelockAccounts(p.ctx, p.directServerAccess.data, timelock_token_v1.DataVersion1)
+	require.NoError(t, err)
+	return timelockAccounts.Vault
 }
 
 func (p *phoneTestEnv) getAuthority(t *testing.T, accountType commonpb.AccountType, index uint64) *common.Account {
@@ -20,6 +20,7 @@ import (
 	"github.com/code-payments/code-server/pkg/code/antispam"
 	"github.com/code-payments/code-server/pkg/code/auth"
 	code_data "github.com/code-payments/code-server/pkg/code/data"
+	datatest "github.com/code-payments/code-server/pkg/code/data/test"
 	"github.com/code-payments/code-server/pkg/code/data/account"
 	"github.com/code-payments/code-server/pkg/code/data/intent"
 	"github.com/code-payments/code-server/pkg/code/data/phone"
@@ -48,9 +49,9 @@ func setup(t *testing.T) (env testEnv, cleanup func()) {
 
 	env.ctx = context.Background()
 	env.client = userpb.NewIdentityClient(conn)
-	env.data = code_data.NewTestDataProvider()
+	env.data = datatest.NewDataProvider()
 
-	antispamGuard := antispam.NewGuard(env.data, memory_device_verifier.NewMemoryDeviceVerifier(), nil)
+	antispamGuard := antispam.NewGuard(env.data, memory_device_verifier.NewMemoryDeviceVerifier())
 
 	s := NewIdentityServer(env.data, auth.NewRPCSignatureVerifier(env.data), antispamGuard)
 	env.server = s.(*identityServer)
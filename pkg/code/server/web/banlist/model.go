@@ -0,0 +1,99 @@
+package banlist
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/code-payments/code-server/pkg/code/data/banlist"
+)
+
+const (
+	successJsonKey = "success"
+	errorJsonKey   = "error"
+	entriesJsonKey = "entries"
+)
+
+type genericApiResponseBody map[string]any
+
+func newGenericApiSuccessResponseBody() genericApiResponseBody {
+	return map[string]any{
+		successJsonKey: true,
+	}
+}
+
+func newGenericApiFailureResponseBody(err error) genericApiResponseBody {
+	return map[string]any{
+		successJsonKey: false,
+		errorJsonKey:   err.Error(),
+	}
+}
+
+func (b *genericApiResponseBody) toString() string {
+	marshalled, _ := json.Marshal(b)
+	return string(marshalled)
+}
+
+// entry is the JSON representation of a ban list entry
+type entry struct {
+	Type      string     `json:"type"`
+	Value     string     `json:"value"`
+	Reason    string     `json:"reason,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
+
+func newEntryFromHttpContext(r *http.Request) (*entry, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestBodySize))
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading request body")
+	}
+
+	var res entry
+	err = json.Unmarshal(body, &res)
+	if err != nil {
+		return nil, errors.New("request body is not a valid entry")
+	}
+	return &res, nil
+}
+
+func (e *entry) getEntryType() (banlist.EntryType, error) {
+	entryType, ok := banlist.ParseEntryType(e.Type)
+	if !ok {
+		return banlist.UnknownEntryType, errors.Errorf("unsupported entry type %q", e.Type)
+	}
+	return entryType, nil
+}
+
+func (e *entry) toRecord() (*banlist.Record, error) {
+	entryType, err := e.getEntryType()
+	if err != nil {
+		return nil, err
+	}
+
+	record := &banlist.Record{
+		EntryType: entryType,
+		Value:     e.Value,
+		Reason:    e.Reason,
+		ExpiresAt: e.ExpiresAt,
+		CreatedAt: time.Now(),
+	}
+	if err := record.Validate(); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+func toEntry(record *banlist.Record) *entry {
+	createdAt := record.CreatedAt
+	return &entry{
+		Type:      record.EntryType.String(),
+		Value:     record.Value,
+		Reason:    record.Reason,
+		ExpiresAt: record.ExpiresAt,
+		CreatedAt: &createdAt,
+	}
+}
@@ -0,0 +1,183 @@
+package banlist
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	code_data "github.com/code-payments/code-server/pkg/code/data"
+	"github.com/code-payments/code-server/pkg/code/data/banlist"
+)
+
+const (
+	v1PathPrefix   = "/v1/banlist"
+	v1ListPath     = v1PathPrefix + "/list"
+	v1PutPath      = v1PathPrefix + "/put"
+	v1DeletePath   = v1PathPrefix + "/delete"
+	typeQueryParam = "type"
+
+	authorizationHeaderName = "authorization"
+	bearerPrefix            = "Bearer "
+
+	contentTypeHeaderName      = "content-type"
+	jsonContentTypeHeaderValue = "application/json"
+
+	maxRequestBodySize = 4096
+)
+
+// Server is an admin HTTP server to manage the antispam ban list. All requests
+// must provide the admin token as a bearer token.
+//
+// Changes are picked up by antispam guards when their cached ban list is next
+// refreshed.
+type Server struct {
+	log        *logrus.Entry
+	data       code_data.Provider
+	adminToken string
+}
+
+func NewBanListServer(data code_data.Provider, adminToken string) *Server {
+	return &Server{
+		log:        logrus.StandardLogger().WithField("type", "banlist/server"),
+		data:       data,
+		adminToken: adminToken,
+	}
+}
+
+func (s *Server) listHandler(path string) func(w http.ResponseWriter, r *http.Request) {
+	return s.withAuth(path, func(w http.ResponseWriter, r *http.Request, log *logrus.Entry) (int, genericApiResponseBody) {
+		if r.Method != http.MethodGet {
+			return http.StatusBadRequest, newGenericApiFailureResponseBody(errors.New("http get expected"))
+		}
+
+		queried := &entry{Type: r.URL.Query().Get(typeQueryParam)}
+		entryType, err := queried.getEntryType()
+		if err != nil {
+			return http.StatusBadRequest, newGenericApiFailureResponseBody(err)
+		}
+
+		records, err := s.data.GetAllBanListEntriesByType(r.Context(), entryType)
+		if err != nil && err != banlist.ErrEntryNotFound {
+			log.WithError(err).Warn("failure getting ban list entries")
+			return http.StatusInternalServerError, newGenericApiFailureResponseBody(errors.New("internal server error"))
+		}
+
+		entries := make([]*entry, len(records))
+		for i, record := range records {
+			entries[i] = toEntry(record)
+		}
+
+		respBody := newGenericApiSuccessResponseBody()
+		respBody[entriesJsonKey] = entries
+		return http.StatusOK, respBody
+	})
+}
+
+func (s *Server) putHandler(path string) func(w http.ResponseWriter, r *http.Request) {
+	return s.withAuth(path, func(w http.ResponseWriter, r *http.Request, log *logrus.Entry) (int, genericApiResponseBody) {
+		if r.Method != http.MethodPost {
+			return http.StatusBadRequest, newGenericApiFailureResponseBody(errors.New("http post expected"))
+		}
+
+		requested, err := newEntryFromHttpContext(r)
+		if err != nil {
+			return http.StatusBadRequest, newGenericApiFailureResponseBody(err)
+		}
+
+		record, err := requested.toRecord()
+		if err != nil {
+			return http.StatusBadRequest, newGenericApiFailureResponseBody(err)
+		}
+
+		log = log.WithFields(logrus.Fields{
+			"entry_type": record.EntryType.String(),
+			"value":      record.Value,
+		})
+
+		err = s.data.PutBanListEntry(r.Context(), record)
+		if err != nil {
+			log.WithError(err).Warn("failure putting ban list entry")
+			return http.StatusInternalServerError, newGenericApiFailureResponseBody(errors.New("internal server error"))
+		}
+
+		log.WithField("reason", record.Reason).Info("ban list entry saved")
+		return http.StatusOK, newGenericApiSuccessResponseBody()
+	})
+}
+
+func (s *Server) deleteHandler(path string) func(w http.ResponseWriter, r *http.Request) {
+	return s.withAuth(path, func(w http.ResponseWriter, r *http.Request, log *logrus.Entry) (int, genericApiResponseBody) {
+		if r.Method != http.MethodPost {
+			return http.StatusBadRequest, newGenericApiFailureResponseBody(errors.New("http post expected"))
+		}
+
+		requested, err := newEntryFromHttpContext(r)
+		if err != nil {
+			return http.StatusBadRequest, newGenericApiFailureResponseBody(err)
+		}
+
+		entryType, err := requested.getEntryType()
+		if err != nil {
+			return http.StatusBadRequest, newGenericApiFailureResponseBody(err)
+		}
+
+		log = log.WithFields(logrus.Fields{
+			"entry_type": entryType.String(),
+			"value":      requested.Value,
+		})
+
+		err = s.data.DeleteBanListEntry(r.Context(), entryType, requested.Value)
+		if err == banlist.ErrEntryNotFound {
+			return http.StatusNotFound, newGenericApiFailureResponseBody(err)
+		} else if err != nil {
+			log.WithError(err).Warn("failure deleting ban list entry")
+			return http.StatusInternalServerError, newGenericApiFailureResponseBody(errors.New("internal server error"))
+		}
+
+		log.Info("ban list entry deleted")
+		return http.StatusOK, newGenericApiSuccessResponseBody()
+	})
+}
+
+func (s *Server) withAuth(path string, handler func(w http.ResponseWriter, r *http.Request, log *logrus.Entry) (int, genericApiResponseBody)) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := s.log.WithField("path", path)
+
+		statusCode, body := func() (int, genericApiResponseBody) {
+			if !s.isAuthorized(r) {
+				return http.StatusUnauthorized, newGenericApiFailureResponseBody(errors.New("unauthorized"))
+			}
+			return handler(w, r, log)
+		}()
+
+		w.Header().Set(contentTypeHeaderName, jsonContentTypeHeaderValue)
+		w.WriteHeader(statusCode)
+		w.Write([]byte(body.toString()))
+	}
+}
+
+func (s *Server) isAuthorized(r *http.Request) bool {
+	// An unconfigured token disables the server, rather than allowing anyone
+	if len(s.adminToken) == 0 {
+		return false
+	}
+
+	header := r.Header.Get(authorizationHeaderName)
+	if !strings.HasPrefix(header, bearerPrefix) {
+		return false
+	}
+
+	provided := strings.TrimPrefix(header, bearerPrefix)
+	return subtle.ConstantTimeCompare([]byte(provided), []byte(s.adminToken)) == 1
+}
+
+func (s *Server) GetHandlers() map[string]http.HandlerFunc {
+	return map[string]http.HandlerFunc{
+		v1ListPath:   s.listHandler(v1ListPath),
+		v1PutPath:    s.putHandler(v1PutPath),
+		v1DeletePath: s.deleteHandler(v1DeletePath),
+	}
+}
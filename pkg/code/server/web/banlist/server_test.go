@@ -0,0 +1,141 @@
+package banlist
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	code_data "github.com/code-payments/code-server/pkg/code/data"
+	datatest "github.com/code-payments/code-server/pkg/code/data/test"
+	"github.com/code-payments/code-server/pkg/code/data/banlist"
+)
+
+const testAdminToken = "admin-token"
+
+func TestServer_HappyPath(t *testing.T) {
+	env := setup(t, testAdminToken)
+
+	statusCode, body := env.do(t, http.MethodGet, v1ListPath+"?type=phone_prefix", "", testAdminToken)
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Empty(t, body[entriesJsonKey])
+
+	statusCode, _ = env.do(t, http.MethodPost, v1PutPath, `{"type": "phone_prefix", "value": "+7", "reason": "sanctioned"}`, testAdminToken)
+	assert.Equal(t, http.StatusOK, statusCode)
+
+	statusCode, _ = env.do(t, http.MethodPost, v1PutPath, `{"type": "ip_range", "value": "10.0.0.0/8", "reason": "abuse", "expires_at": "2030-01-01T00:00:00Z"}`, testAdminToken)
+	assert.Equal(t, http.StatusOK, statusCode)
+
+	record, err := env.data.GetBanListEntry(env.ctx, banlist.IpRangeEntryType, "10.0.0.0/8")
+	require.NoError(t, err)
+	assert.Equal(t, "abuse", record.Reason)
+	require.NotNil(t, record.ExpiresAt)
+	assert.Equal(t, 2030, record.ExpiresAt.Year())
+
+	statusCode, body = env.do(t, http.MethodGet, v1ListPath+"?type=phone_prefix", "", testAdminToken)
+	assert.Equal(t, http.StatusOK, statusCode)
+	entries := body[entriesJsonKey].([]interface{})
+	require.Len(t, entries, 1)
+	assert.Equal(t, "+7", entries[0].(map[string]interface{})["value"])
+	assert.Equal(t, "sanctioned", entries[0].(map[string]interface{})["reason"])
+
+	statusCode, _ = env.do(t, http.MethodPost, v1DeletePath, `{"type": "phone_prefix", "value": "+7"}`, testAdminToken)
+	assert.Equal(t, http.StatusOK, statusCode)
+
+	_, err = env.data.GetBanListEntry(env.ctx, banlist.PhonePrefixEntryType, "+7")
+	assert.Equal(t, banlist.ErrEntryNotFound, err)
+
+	statusCode, _ = env.do(t, http.MethodPost, v1DeletePath, `{"type": "phone_prefix", "value": "+7"}`, testAdminToken)
+	assert.Equal(t, http.StatusNotFound, statusCode)
+}
+
+func TestServer_InvalidRequests(t *testing.T) {
+	env := setup(t, testAdminToken)
+
+	for _, tc := range []struct {
+		method string
+		path   string
+		body   string
+	}{
+		{http.MethodPost, v1ListPath + "?type=phone_prefix", ""},
+		{http.MethodGet, v1ListPath + "?type=unknown", ""},
+		{http.MethodGet, v1PutPath, ""},
+		{http.MethodPost, v1PutPath, `not json`},
+		{http.MethodPost, v1PutPath, `{"type": "unknown", "value": "+7", "reason": "reason"}`},
+		{http.MethodPost, v1PutPath, `{"type": "phone_prefix", "value": "7", "reason": "reason"}`},
+		{http.MethodPost, v1PutPath, `{"type": "phone_prefix", "value": "+7"}`},
+		{http.MethodPost, v1PutPath, `{"type": "ip_range", "value": "10.0.0.1", "reason": "reason"}`},
+		{http.MethodPost, v1PutPath, `{"type": "external_address", "value": "invalid", "reason": "reason"}`},
+		{http.MethodPost, v1DeletePath, `{"type": "unknown", "value": "+7"}`},
+	} {
+		statusCode, body := env.do(t, tc.method, tc.path, tc.body, testAdminToken)
+		assert.Equal(t, http.StatusBadRequest, statusCode, tc)
+		assert.Equal(t, false, body[successJsonKey])
+		assert.NotEmpty(t, body[errorJsonKey])
+	}
+
+	_, err := env.data.GetAllActiveBanListEntries(env.ctx, time.Now())
+	assert.Equal(t, banlist.ErrEntryNotFound, err)
+}
+
+func TestServer_Unauthorized(t *testing.T) {
+	for _, adminToken := range []string{testAdminToken, ""} {
+		env := setup(t, adminToken)
+
+		for _, providedToken := range []string{"", "invalid", adminToken} {
+			if len(adminToken) > 0 && providedToken == adminToken {
+				continue
+			}
+
+			statusCode, _ := env.do(t, http.MethodGet, v1ListPath+"?type=phone_prefix", "", providedToken)
+			assert.Equal(t, http.StatusUnauthorized, statusCode)
+
+			statusCode, _ = env.do(t, http.MethodPost, v1PutPath, `{"type": "phone_prefix", "value": "+7", "reason": "sanctioned"}`, providedToken)
+			assert.Equal(t, http.StatusUnauthorized, statusCode)
+
+			statusCode, _ = env.do(t, http.MethodPost, v1DeletePath, `{"type": "phone_prefix", "value": "+7"}`, providedToken)
+			assert.Equal(t, http.StatusUnauthorized, statusCode)
+		}
+
+		_, err := env.data.GetAllActiveBanListEntries(env.ctx, time.Now())
+		assert.Equal(t, banlist.ErrEntryNotFound, err)
+	}
+}
+
+type testEnv struct {
+	ctx      context.Context
+	data     code_data.Provider
+	handlers map[string]http.HandlerFunc
+}
+
+func setup(t *testing.T, adminToken string) *testEnv {
+	data := datatest.NewDataProvider()
+	return &testEnv{
+		ctx:      context.Background(),
+		data:     data,
+		handlers: NewBanListServer(data, adminToken).GetHandlers(),
+	}
+}
+
+func (e *testEnv) do(t *testing.T, method, path, body, token string) (int, map[string]interface{}) {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if len(token) > 0 {
+		req.Header.Set(authorizationHeaderName, bearerPrefix+token)
+	}
+
+	handler, ok := e.handlers[strings.Split(path, "?")[0]]
+	require.True(t, ok)
+
+	recorder := httptest.NewRecorder()
+	handler(recorder, req)
+
+	var res map[string]interface{}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+	return recorder.Code, res
+}
@@ -0,0 +1,149 @@
+package contact
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mr-tron/base58"
+	"github.com/pkg/errors"
+
+	"github.com/code-payments/code-server/pkg/code/common"
+	phone_util "github.com/code-payments/code-server/pkg/phone"
+)
+
+const (
+	successJsonKey    = "success"
+	errorJsonKey      = "error"
+	candidatesJsonKey = "candidates"
+	saltJsonKey       = "salt"
+)
+
+type genericApiResponseBody map[string]any
+
+func newGenericApiSuccessResponseBody() genericApiResponseBody {
+	return map[string]any{
+		successJsonKey: true,
+	}
+}
+
+func newGenericApiFailureResponseBody(err error) genericApiResponseBody {
+	return map[string]any{
+		successJsonKey: false,
+		errorJsonKey:   err.Error(),
+	}
+}
+
+func (b *genericApiResponseBody) toString() string {
+	marshalled, _ := json.Marshal(b)
+	return string(marshalled)
+}
+
+// discoverRequest is a request by an owner to discover which of their contacts
+// are registered. It must be signed by the owner account.
+//
+// Contacts are identified by truncated prefixes of their contact discovery
+// hash. Each prefix returns all verified hashes in the bucket, so the client
+// can match locally without revealing the contact to the server.
+type discoverRequest struct {
+	owner     *common.Account
+	prefixes  []string
+	timestamp time.Time
+}
+
+func newDiscoverRequestFromHttpContext(r *http.Request) (*discoverRequest, error) {
+	httpRequestBody := struct {
+		Owner     string   `json:"owner"`
+		Prefixes  []string `json:"prefixes"`
+		Timestamp int64    `json:"timestamp"`
+		Signature string   `json:"signature"`
+	}{}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestBodySize))
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(body, &httpRequestBody)
+	if err != nil {
+		return nil, errors.New("invalid json body")
+	}
+
+	owner, err := common.NewAccountFromPublicKeyString(httpRequestBody.Owner)
+	if err != nil {
+		return nil, errors.New("owner is not a public key")
+	}
+
+	signature, err := base58.Decode(httpRequestBody.Signature)
+	if err != nil || len(signature) != ed25519.SignatureSize {
+		return nil, errors.New("signature is invalid")
+	}
+
+	req := &discoverRequest{
+		owner:     owner,
+		prefixes:  httpRequestBody.Prefixes,
+		timestamp: time.Unix(httpRequestBody.Timestamp, 0),
+	}
+
+	if !ed25519.Verify(owner.PublicKey().ToBytes(), req.getMessageToSign(), signature) {
+		return nil, errUnauthenticated
+	}
+
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func (r *discoverRequest) validate() error {
+	if len(r.prefixes) == 0 {
+		return errors.New("prefixes are required")
+	}
+
+	if len(r.prefixes) > maxPrefixesPerRequest {
+		return errors.Errorf("at most %d prefixes can be provided", maxPrefixesPerRequest)
+	}
+
+	if _, err := r.getPrefixes(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *discoverRequest) getPrefixes() ([][]byte, error) {
+	prefixes := make([][]byte, len(r.prefixes))
+	for i, encoded := range r.prefixes {
+		decoded, err := hex.DecodeString(encoded)
+		if err != nil || !phone_util.IsValidContactDiscoveryPrefix(decoded) {
+			return nil, errors.Errorf("prefix at index %d is invalid", i)
+		}
+		prefixes[i] = decoded
+	}
+	return prefixes, nil
+}
+
+// getMessageToSign gets the message the owner signs to prove they're making the
+// discovery request
+func (r *discoverRequest) getMessageToSign() []byte {
+	return []byte(fmt.Sprintf(
+		"code-contact-discovery:%s:%s:%d",
+		r.owner.PublicKey().ToBase58(),
+		strings.Join(r.prefixes, ","),
+		r.timestamp.Unix(),
+	))
+}
+
+func toHexStrings(values [][]byte) []string {
+	res := make([]string, len(values))
+	for i, value := range values {
+		res[i] = hex.EncodeToString(value)
+	}
+	return res
+}
@@ -0,0 +1,149 @@
+package contact
+
+import (
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/code-payments/code-server/pkg/code/antispam"
+	code_data "github.com/code-payments/code-server/pkg/code/data"
+	phone_util "github.com/code-payments/code-server/pkg/phone"
+)
+
+const (
+	v1PathPrefix   = "/v1/contact"
+	v1SaltPath     = v1PathPrefix + "/salt"
+	v1DiscoverPath = v1PathPrefix + "/discover"
+
+	contentTypeHeaderName      = "content-type"
+	jsonContentTypeHeaderValue = "application/json"
+
+	maxPrefixesPerRequest = 256
+	maxRequestBodySize    = 128 * 1024
+
+	// Owner signed requests must be recent to limit replays
+	maxRequestAge = time.Minute
+)
+
+var (
+	errUnauthenticated = errors.New("authentication failed")
+	errDenied          = errors.New("request denied")
+	errInternalServer  = errors.New("internal server error")
+)
+
+// Server serves privacy-preserving contact discovery.
+//
+// Clients get the published contact discovery salt, hash each phone number in
+// their address book as SHA-256(salt || E.164 phone number), and upload truncated
+// prefixes of the hashes rather than raw phone numbers. They receive the matching
+// buckets of the hashed index of verified phone numbers, and match full hashes
+// locally. Only the number of prefixes looked up is persisted. Antispam limits discovery to phone verified owners and caps the
+// number of daily lookups, which limits the ability to enumerate the index.
+type Server struct {
+	log   *logrus.Entry
+	data  code_data.Provider
+	guard *antispam.Guard
+}
+
+func NewContactDiscoveryServer(data code_data.Provider, guard *antispam.Guard) *Server {
+	return &Server{
+		log:   logrus.StandardLogger().WithField("type", "contact/server"),
+		data:  data,
+		guard: guard,
+	}
+}
+
+func (s *Server) saltHandler(path string) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := s.log.WithField("path", path)
+
+		statusCode, body := func() (int, genericApiResponseBody) {
+			if r.Method != http.MethodGet {
+				return http.StatusBadRequest, newGenericApiFailureResponseBody(errors.New("http get expected"))
+			}
+
+			salt, err := phone_util.GetContactDiscoverySalt()
+			if err == phone_util.ErrContactDiscoverySaltNotLoaded {
+				return http.StatusServiceUnavailable, newGenericApiFailureResponseBody(errors.New("contact discovery is unavailable"))
+			} else if err != nil {
+				log.WithError(err).Warn("failure getting contact discovery salt")
+				return http.StatusInternalServerError, newGenericApiFailureResponseBody(errInternalServer)
+			}
+
+			respBody := newGenericApiSuccessResponseBody()
+			respBody[saltJsonKey] = hex.EncodeToString(salt)
+			return http.StatusOK, respBody
+		}()
+
+		w.Header().Set(contentTypeHeaderName, jsonContentTypeHeaderValue)
+		w.WriteHeader(statusCode)
+		w.Write([]byte(body.toString()))
+	}
+}
+
+func (s *Server) discoverHandler(path string) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := s.log.WithField("path", path)
+
+		statusCode, body := func() (int, genericApiResponseBody) {
+			if r.Method != http.MethodPost {
+				return http.StatusBadRequest, newGenericApiFailureResponseBody(errors.New("http post expected"))
+			}
+
+			req, err := newDiscoverRequestFromHttpContext(r)
+			if err == errUnauthenticated {
+				return http.StatusUnauthorized, newGenericApiFailureResponseBody(err)
+			} else if err != nil {
+				return http.StatusBadRequest, newGenericApiFailureResponseBody(err)
+			}
+
+			log = log.WithField("owner", req.owner.PublicKey().ToBase58())
+
+			if age := time.Since(req.timestamp); age > maxRequestAge || age < -maxRequestAge {
+				return http.StatusUnauthorized, newGenericApiFailureResponseBody(errors.New("request timestamp is stale"))
+			}
+
+			prefixes, _ := req.getPrefixes()
+
+			allow, err := s.guard.AllowContactDiscovery(r.Context(), req.owner, len(prefixes))
+			if err != nil {
+				log.WithError(err).Warn("failure performing antispam checks")
+				return http.StatusInternalServerError, newGenericApiFailureResponseBody(errInternalServer)
+			} else if !allow {
+				return http.StatusForbidden, newGenericApiFailureResponseBody(errDenied)
+			}
+
+			// Lookups are counted before they're made, so failed requests can't be
+			// used to get around the daily limit
+			err = s.data.RecordContactDiscoveryLookups(r.Context(), req.owner.PublicKey().ToBase58(), uint32(len(prefixes)), time.Now())
+			if err != nil {
+				log.WithError(err).Warn("failure recording contact discovery lookups")
+				return http.StatusInternalServerError, newGenericApiFailureResponseBody(errInternalServer)
+			}
+
+			candidates, err := s.data.GetVerifiedPhoneNumberHashesByPrefix(r.Context(), prefixes)
+			if err != nil {
+				log.WithError(err).Warn("failure getting verified phone number hashes by prefix")
+				return http.StatusInternalServerError, newGenericApiFailureResponseBody(errInternalServer)
+			}
+
+			respBody := newGenericApiSuccessResponseBody()
+			respBody[candidatesJsonKey] = toHexStrings(candidates)
+			return http.StatusOK, respBody
+		}()
+
+		w.Header().Set(contentTypeHeaderName, jsonContentTypeHeaderValue)
+		w.WriteHeader(statusCode)
+		w.Write([]byte(body.toString()))
+	}
+}
+
+func (s *Server) GetHandlers() map[string]http.HandlerFunc {
+	return map[string]http.HandlerFunc{
+		v1SaltPath:     s.saltHandler(v1SaltPath),
+		v1DiscoverPath: s.discoverHandler(v1DiscoverPath),
+	}
+}
@@ -0,0 +1,246 @@
+package contact
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mr-tron/base58"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/code-payments/code-server/pkg/code/antispam"
+	"github.com/code-payments/code-server/pkg/code/common"
+	code_data "github.com/code-payments/code-server/pkg/code/data"
+	datatest "github.com/code-payments/code-server/pkg/code/data/test"
+	"github.com/code-payments/code-server/pkg/code/data/phone"
+	memory_device_verifier "github.com/code-payments/code-server/pkg/device/memory"
+	phone_util "github.com/code-payments/code-server/pkg/phone"
+)
+
+func TestServer_HappyPath(t *testing.T) {
+	env := setup(t)
+
+	registered := "+18005550001"
+	unregistered := "+18005550002"
+	env.verify(t, registered, env.newOwner(t))
+
+	registeredHash := env.hash(t, registered)
+	unregisteredHash := env.hash(t, unregistered)
+
+	statusCode, body := env.discover(t, env.owner, [][]byte{registeredHash[:3], unregisteredHash[:4]}, time.Now())
+	require.Equal(t, http.StatusOK, statusCode, body)
+	assert.Equal(t, true, body[successJsonKey])
+	assert.Contains(t, body[candidatesJsonKey], hex.EncodeToString(registeredHash))
+	assert.NotContains(t, body[candidatesJsonKey], hex.EncodeToString(unregisteredHash))
+
+	count, err := env.data.GetContactDiscoveryLookupCountSinceTimestamp(env.ctx, env.owner.PublicKey().ToBase58(), time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, count)
+}
+
+func TestServer_Salt(t *testing.T) {
+	env := setup(t)
+
+	statusCode, _ := env.do(t, http.MethodPost, v1SaltPath, "")
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+
+	// The index is built with the same salt clients are given
+	hash, err := phone_util.HashForContactDiscovery("+18005550000")
+	require.NoError(t, err)
+	assert.Equal(t, hash, env.hash(t, "+18005550000"))
+}
+
+func TestServer_UnverifiedOwner(t *testing.T) {
+	env := setup(t)
+
+	statusCode, body := env.discover(t, env.newOwner(t), [][]byte{env.hash(t, "+18005550001")[:3]}, time.Now())
+	assert.Equal(t, http.StatusForbidden, statusCode)
+	assert.Equal(t, false, body[successJsonKey])
+}
+
+func TestServer_DailyLookupLimit(t *testing.T) {
+	env := setup(t)
+
+	prefixes := make([][]byte, maxPrefixesPerRequest)
+	for i := range prefixes {
+		prefixes[i] = env.hash(t, fmt.Sprintf("+1800555%04d", i))[:3]
+	}
+
+	for i := 0; i < testMaxLookupsPerDay/maxPrefixesPerRequest; i++ {
+		statusCode, body := env.discover(t, env.owner, prefixes, time.Now())
+		require.Equal(t, http.StatusOK, statusCode, body)
+	}
+
+	statusCode, body := env.discover(t, env.owner, prefixes[:1], time.Now())
+	assert.Equal(t, http.StatusForbidden, statusCode)
+	assert.Equal(t, false, body[successJsonKey])
+
+	// Other owners aren't affected
+	otherOwner := env.newOwner(t)
+	env.verify(t, "+18005550001", otherOwner)
+
+	statusCode, _ = env.discover(t, otherOwner, prefixes[:1], time.Now())
+	assert.Equal(t, http.StatusOK, statusCode)
+}
+
+func TestServer_InvalidRequests(t *testing.T) {
+	env := setup(t)
+
+	hash := env.hash(t, "+18005550001")
+
+	statusCode, _ := env.discover(t, env.owner, nil, time.Now())
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+
+	statusCode, _ = env.discover(t, env.owner, [][]byte{hash[:phone_util.MinContactDiscoveryPrefixSize-1]}, time.Now())
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+
+	statusCode, _ = env.discover(t, env.owner, [][]byte{hash[:phone_util.MaxContactDiscoveryPrefixSize+1]}, time.Now())
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+
+	// Full hashes are never matched
+	statusCode, _ = env.discover(t, env.owner, [][]byte{hash}, time.Now())
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+
+	tooManyPrefixes := make([][]byte, maxPrefixesPerRequest+1)
+	for i := range tooManyPrefixes {
+		tooManyPrefixes[i] = hash[:3]
+	}
+	statusCode, _ = env.discover(t, env.owner, tooManyPrefixes, time.Now())
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+
+	statusCode, _ = env.discover(t, env.owner, [][]byte{hash[:3]}, time.Now().Add(-2*maxRequestAge))
+	assert.Equal(t, http.StatusUnauthorized, statusCode)
+
+	reqBody := env.newDiscoverRequestBody(t, env.owner, [][]byte{hash[:3]}, time.Now())
+	reqBody["owner"] = env.newOwner(t).PublicKey().ToBase58()
+	marshalled, err := json.Marshal(reqBody)
+	require.NoError(t, err)
+	statusCode, _ = env.do(t, http.MethodPost, v1DiscoverPath, string(marshalled))
+	assert.Equal(t, http.StatusUnauthorized, statusCode)
+
+	statusCode, _ = env.do(t, http.MethodGet, v1DiscoverPath, "")
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+
+	statusCode, _ = env.do(t, http.MethodPost, v1DiscoverPath, "not json")
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+
+	// None of the invalid requests count towards the daily limit
+	count, err := env.data.GetContactDiscoveryLookupCountSinceTimestamp(env.ctx, env.owner.PublicKey().ToBase58(), time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.EqualValues(t, 0, count)
+}
+
+type testEnv struct {
+	ctx      context.Context
+	data     code_data.Provider
+	owner    *common.Account
+	salt     []byte
+	handlers map[string]http.HandlerFunc
+}
+
+const testMaxLookupsPerDay = 2 * maxPrefixesPerRequest
+
+func setup(t *testing.T) *testEnv {
+	data := datatest.NewDataProvider()
+
+	salt := make([]byte, phone_util.MinContactDiscoverySaltSize)
+	_, err := rand.Read(salt)
+	require.NoError(t, err)
+	require.NoError(t, phone_util.LoadContactDiscoverySalt(salt))
+
+	antispamGuard := antispam.NewGuard(
+		data,
+		memory_device_verifier.NewMemoryDeviceVerifier(),
+		antispam.WithMaxContactDiscoveryLookupsPerDay(testMaxLookupsPerDay),
+	)
+
+	env := &testEnv{
+		ctx:      context.Background(),
+		data:     data,
+		handlers: NewContactDiscoveryServer(data, antispamGuard).GetHandlers(),
+	}
+
+	env.owner = env.newOwner(t)
+	env.verify(t, "+18005550000", env.owner)
+
+	// Clients hash their contacts using the published salt
+	statusCode, body := env.do(t, http.MethodGet, v1SaltPath, "")
+	require.Equal(t, http.StatusOK, statusCode, body)
+	env.salt, err = hex.DecodeString(body[saltJsonKey].(string))
+	require.NoError(t, err)
+	require.Equal(t, salt, env.salt)
+
+	return env
+}
+
+func (e *testEnv) newOwner(t *testing.T) *common.Account {
+	owner, err := common.NewRandomAccount()
+	require.NoError(t, err)
+	return owner
+}
+
+func (e *testEnv) verify(t *testing.T, phoneNumber string, owner *common.Account) {
+	require.NoError(t, e.data.SavePhoneVerification(e.ctx, &phone.Verification{
+		PhoneNumber:    phoneNumber,
+		OwnerAccount:   owner.PublicKey().ToBase58(),
+		CreatedAt:      time.Now(),
+		LastVerifiedAt: time.Now(),
+	}))
+}
+
+// hash computes a contact discovery hash the way a client does
+func (e *testEnv) hash(t *testing.T, phoneNumber string) []byte {
+	hash := sha256.Sum256(append(append([]byte(nil), e.salt...), phoneNumber...))
+	return hash[:]
+}
+
+func (e *testEnv) newDiscoverRequestBody(t *testing.T, signer *common.Account, prefixes [][]byte, timestamp time.Time) map[string]any {
+	encodedPrefixes := toHexStrings(prefixes)
+
+	message := fmt.Sprintf(
+		"code-contact-discovery:%s:%s:%d",
+		signer.PublicKey().ToBase58(),
+		strings.Join(encodedPrefixes, ","),
+		timestamp.Unix(),
+	)
+
+	signature, err := signer.Sign([]byte(message))
+	require.NoError(t, err)
+
+	return map[string]any{
+		"owner":     signer.PublicKey().ToBase58(),
+		"prefixes":  encodedPrefixes,
+		"timestamp": timestamp.Unix(),
+		"signature": base58.Encode(signature),
+	}
+}
+
+func (e *testEnv) discover(t *testing.T, owner *common.Account, prefixes [][]byte, timestamp time.Time) (int, map[string]interface{}) {
+	marshalled, err := json.Marshal(e.newDiscoverRequestBody(t, owner, prefixes, timestamp))
+	require.NoError(t, err)
+
+	return e.do(t, http.MethodPost, v1DiscoverPath, string(marshalled))
+}
+
+func (e *testEnv) do(t *testing.T, method, path, body string) (int, map[string]interface{}) {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+
+	handler, ok := e.handlers[path]
+	require.True(t, ok)
+
+	recorder := httptest.NewRecorder()
+	handler(recorder, req)
+
+	var res map[string]interface{}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+	return recorder.Code, res
+}
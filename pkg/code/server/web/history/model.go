@@ -0,0 +1,233 @@
+package history
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/mr-tron/base58"
+	"github.com/pkg/errors"
+
+	"github.com/code-payments/code-server/pkg/code/common"
+	history_util "github.com/code-payments/code-server/pkg/code/history"
+)
+
+const (
+	successJsonKey   = "success"
+	errorJsonKey     = "error"
+	urlJsonKey       = "url"
+	expiresAtJsonKey = "expires_at"
+)
+
+type genericApiResponseBody map[string]any
+
+func newGenericApiSuccessResponseBody() genericApiResponseBody {
+	return map[string]any{
+		successJsonKey: true,
+	}
+}
+
+func newGenericApiFailureResponseBody(err error) genericApiResponseBody {
+	return map[string]any{
+		successJsonKey: false,
+		errorJsonKey:   err.Error(),
+	}
+}
+
+func (b *genericApiResponseBody) toString() string {
+	marshalled, _ := json.Marshal(b)
+	return string(marshalled)
+}
+
+// exportRequest is the set of parameters for an export, which are shared between
+// requests to create a download link and the signed download link itself
+type exportRequest struct {
+	owner  *common.Account
+	start  time.Time
+	end    time.Time
+	format history_util.Format
+}
+
+func (r *exportRequest) validate() error {
+	if !r.start.Before(r.end) {
+		return errors.New("start must be before end")
+	}
+
+	if r.end.Sub(r.start) > history_util.MaxExportRange {
+		return errors.Errorf("time range cannot exceed %d days", int(history_util.MaxExportRange.Hours()/24))
+	}
+
+	return nil
+}
+
+// createExportLinkRequest is a request by an owner to create a download link to
+// their payment history. It must be signed by the owner account.
+type createExportLinkRequest struct {
+	exportRequest
+
+	timestamp time.Time
+}
+
+func newCreateExportLinkRequestFromHttpContext(r *http.Request) (*createExportLinkRequest, error) {
+	httpRequestBody := struct {
+		Owner     string `json:"owner"`
+		Start     int64  `json:"start"`
+		End       int64  `json:"end"`
+		Format    string `json:"format"`
+		Timestamp int64  `json:"timestamp"`
+		Signature string `json:"signature"`
+	}{}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestBodySize))
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(body, &httpRequestBody)
+	if err != nil {
+		return nil, errors.New("invalid json body")
+	}
+
+	owner, err := common.NewAccountFromPublicKeyString(httpRequestBody.Owner)
+	if err != nil {
+		return nil, errors.New("owner is not a public key")
+	}
+
+	format, err := history_util.ParseFormat(httpRequestBody.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := base58.Decode(httpRequestBody.Signature)
+	if err != nil || len(signature) != ed25519.SignatureSize {
+		return nil, errors.New("signature is invalid")
+	}
+
+	req := &createExportLinkRequest{
+		exportRequest: exportRequest{
+			owner:  owner,
+			start:  time.Unix(httpRequestBody.Start, 0),
+			end:    time.Unix(httpRequestBody.End, 0),
+			format: format,
+		},
+		timestamp: time.Unix(httpRequestBody.Timestamp, 0),
+	}
+
+	if !ed25519.Verify(owner.PublicKey().ToBytes(), req.getMessageToSign(), signature) {
+		return nil, errUnauthenticated
+	}
+
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// getMessageToSign gets the message the owner signs to prove they're requesting
+// the download link
+func (r *createExportLinkRequest) getMessageToSign() []byte {
+	return []byte(fmt.Sprintf(
+		"code-history-export:%s:%d:%d:%s:%d",
+		r.owner.PublicKey().ToBase58(),
+		r.start.Unix(),
+		r.end.Unix(),
+		r.format.String(),
+		r.timestamp.Unix(),
+	))
+}
+
+// downloadRequest is a request to download an export using a link signed by the
+// server
+type downloadRequest struct {
+	exportRequest
+
+	expiresAt time.Time
+}
+
+func newDownloadRequestFromHttpContext(r *http.Request) (*downloadRequest, []byte, error) {
+	values := r.URL.Query()
+
+	owner, err := common.NewAccountFromPublicKeyString(values.Get(ownerQueryParam))
+	if err != nil {
+		return nil, nil, errors.New("owner is not a public key")
+	}
+
+	format, err := history_util.ParseFormat(values.Get(formatQueryParam))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var timestamps [3]int64
+	for i, name := range []string{startQueryParam, endQueryParam, expiresQueryParam} {
+		timestamps[i], err = strconv.ParseInt(values.Get(name), 10, 64)
+		if err != nil {
+			return nil, nil, errors.Errorf("%s query parameter is invalid", name)
+		}
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(values.Get(signatureQueryParam))
+	if err != nil || len(signature) != sha256.Size {
+		return nil, nil, errors.New("signature is invalid")
+	}
+
+	req := &downloadRequest{
+		exportRequest: exportRequest{
+			owner:  owner,
+			start:  time.Unix(timestamps[0], 0),
+			end:    time.Unix(timestamps[1], 0),
+			format: format,
+		},
+		expiresAt: time.Unix(timestamps[2], 0),
+	}
+
+	if err := req.validate(); err != nil {
+		return nil, nil, err
+	}
+
+	return req, signature, nil
+}
+
+// sign computes the signature over all download parameters with the server's
+// signing key
+func (r *downloadRequest) sign(signingKey []byte) []byte {
+	mac := hmac.New(sha256.New, signingKey)
+	mac.Write([]byte(fmt.Sprintf(
+		"%s\n%d\n%d\n%s\n%d",
+		r.owner.PublicKey().ToBase58(),
+		r.start.Unix(),
+		r.end.Unix(),
+		r.format.String(),
+		r.expiresAt.Unix(),
+	)))
+	return mac.Sum(nil)
+}
+
+// toQuery gets the query parameters for the download link
+func (r *downloadRequest) toQuery(signingKey []byte) url.Values {
+	values := url.Values{}
+	values.Set(ownerQueryParam, r.owner.PublicKey().ToBase58())
+	values.Set(startQueryParam, strconv.FormatInt(r.start.Unix(), 10))
+	values.Set(endQueryParam, strconv.FormatInt(r.end.Unix(), 10))
+	values.Set(formatQueryParam, r.format.String())
+	values.Set(expiresQueryParam, strconv.FormatInt(r.expiresAt.Unix(), 10))
+	values.Set(signatureQueryParam, base64.RawURLEncoding.EncodeToString(r.sign(signingKey)))
+	return values
+}
+
+func (r *downloadRequest) getFileName() string {
+	return fmt.Sprintf(
+		"code-statement-%s-%s.%s",
+		r.start.UTC().Format("20060102"),
+		r.end.UTC().Format("20060102"),
+		r.format.String(),
+	)
+}
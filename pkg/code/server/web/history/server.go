@@ -0,0 +1,178 @@
+package history
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	history_util "github.com/code-payments/code-server/pkg/code/history"
+)
+
+const (
+	v1PathPrefix           = "/v1/history"
+	v1CreateExportLinkPath = v1PathPrefix + "/createExportLink"
+	v1ExportPath           = v1PathPrefix + "/export"
+
+	ownerQueryParam     = "owner"
+	startQueryParam     = "start"
+	endQueryParam       = "end"
+	formatQueryParam    = "format"
+	expiresQueryParam   = "expires"
+	signatureQueryParam = "signature"
+
+	contentTypeHeaderName        = "content-type"
+	contentDispositionHeaderName = "content-disposition"
+	jsonContentTypeHeaderValue   = "application/json"
+
+	maxRequestBodySize = 4096
+
+	// Owner signed requests must be recent to limit replays
+	maxRequestAge = time.Minute
+)
+
+var (
+	errUnauthenticated = errors.New("authentication failed")
+)
+
+// Server serves payment history exports as signed, time-limited downloads.
+//
+// Owners request a download link by signing the export parameters. The link is
+// signed by the server, and can be used by anyone until it expires, so it can be
+// opened in a browser.
+type Server struct {
+	log        *logrus.Entry
+	exporter   *history_util.Exporter
+	baseUrl    string
+	signingKey []byte
+	linkTtl    time.Duration
+}
+
+func NewHistoryExportServer(exporter *history_util.Exporter, baseUrl string, signingKey []byte, linkTtl time.Duration) *Server {
+	return &Server{
+		log:        logrus.StandardLogger().WithField("type", "history/server"),
+		exporter:   exporter,
+		baseUrl:    baseUrl,
+		signingKey: signingKey,
+		linkTtl:    linkTtl,
+	}
+}
+
+func (s *Server) createExportLinkHandler(path string) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := s.log.WithField("path", path)
+
+		statusCode, body := func() (int, genericApiResponseBody) {
+			if r.Method != http.MethodPost {
+				return http.StatusBadRequest, newGenericApiFailureResponseBody(errors.New("http post expected"))
+			}
+
+			// An unconfigured signing key disables the server, since links could
+			// otherwise be forged
+			if len(s.signingKey) == 0 {
+				return http.StatusNotFound, newGenericApiFailureResponseBody(errors.New("exports are disabled"))
+			}
+
+			req, err := newCreateExportLinkRequestFromHttpContext(r)
+			if err == errUnauthenticated {
+				return http.StatusUnauthorized, newGenericApiFailureResponseBody(err)
+			} else if err != nil {
+				return http.StatusBadRequest, newGenericApiFailureResponseBody(err)
+			}
+
+			log = log.WithField("owner", req.owner.PublicKey().ToBase58())
+
+			if age := time.Since(req.timestamp); age > maxRequestAge || age < -maxRequestAge {
+				return http.StatusUnauthorized, newGenericApiFailureResponseBody(errors.New("request timestamp is stale"))
+			}
+
+			download := &downloadRequest{
+				exportRequest: req.exportRequest,
+				expiresAt:     time.Now().Add(s.linkTtl),
+			}
+
+			log.Debug("created export link")
+
+			respBody := newGenericApiSuccessResponseBody()
+			respBody[urlJsonKey] = fmt.Sprintf("%s%s?%s", s.baseUrl, v1ExportPath, download.toQuery(s.signingKey).Encode())
+			respBody[expiresAtJsonKey] = download.expiresAt.UTC()
+			return http.StatusOK, respBody
+		}()
+
+		w.Header().Set(contentTypeHeaderName, jsonContentTypeHeaderValue)
+		w.WriteHeader(statusCode)
+		w.Write([]byte(body.toString()))
+	}
+}
+
+func (s *Server) exportHandler(path string) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := s.log.WithField("path", path)
+
+		writeError := func(statusCode int, err error) {
+			body := newGenericApiFailureResponseBody(err)
+			w.Header().Set(contentTypeHeaderName, jsonContentTypeHeaderValue)
+			w.WriteHeader(statusCode)
+			w.Write([]byte(body.toString()))
+		}
+
+		if r.Method != http.MethodGet {
+			writeError(http.StatusBadRequest, errors.New("http get expected"))
+			return
+		}
+
+		if len(s.signingKey) == 0 {
+			writeError(http.StatusNotFound, errors.New("exports are disabled"))
+			return
+		}
+
+		req, signature, err := newDownloadRequestFromHttpContext(r)
+		if err != nil {
+			writeError(http.StatusBadRequest, err)
+			return
+		}
+
+		if !hmac.Equal(signature, req.sign(s.signingKey)) {
+			writeError(http.StatusUnauthorized, errUnauthenticated)
+			return
+		}
+
+		if time.Now().After(req.expiresAt) {
+			writeError(http.StatusGone, errors.New("download link expired"))
+			return
+		}
+
+		log = log.WithField("owner", req.owner.PublicKey().ToBase58())
+
+		statement, err := s.exporter.Export(r.Context(), req.owner, req.start, req.end)
+		if err != nil {
+			log.WithError(err).Warn("failure exporting payment history")
+			writeError(http.StatusInternalServerError, errors.New("internal server error"))
+			return
+		}
+
+		// Buffer the export, so failures can still result in an error response
+		var buf bytes.Buffer
+		if err := statement.Write(&buf, req.format); err != nil {
+			log.WithError(err).Warn("failure writing payment history export")
+			writeError(http.StatusInternalServerError, errors.New("internal server error"))
+			return
+		}
+
+		w.Header().Set(contentTypeHeaderName, req.format.ContentType())
+		w.Header().Set(contentDispositionHeaderName, fmt.Sprintf("attachment; filename=%q", req.getFileName()))
+		w.WriteHeader(http.StatusOK)
+		w.Write(buf.Bytes())
+	}
+}
+
+func (s *Server) GetHandlers() map[string]http.HandlerFunc {
+	return map[string]http.HandlerFunc{
+		v1CreateExportLinkPath: s.createExportLinkHandler(v1CreateExportLinkPath),
+		v1ExportPath:           s.exportHandler(v1ExportPath),
+	}
+}
@@ -0,0 +1,314 @@
+package history
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mr-tron/base58"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	currency_lib "github.com/code-payments/code-server/pkg/currency"
+	"github.com/code-payments/code-server/pkg/kin"
+	"github.com/code-payments/code-server/pkg/code/common"
+	code_data "github.com/code-payments/code-server/pkg/code/data"
+	datatest "github.com/code-payments/code-server/pkg/code/data/test"
+	"github.com/code-payments/code-server/pkg/code/data/currency"
+	"github.com/code-payments/code-server/pkg/code/data/intent"
+	history_util "github.com/code-payments/code-server/pkg/code/history"
+)
+
+const (
+	testBaseUrl = "https://example.com"
+)
+
+var testSigningKey = []byte("signing-key")
+
+func TestServer_HappyPath(t *testing.T) {
+	env := setup(t, testSigningKey, time.Hour)
+
+	start := time.Now().Add(-24 * time.Hour)
+	end := time.Now().Add(time.Minute)
+
+	env.savePayment(t, "intent1", 100, time.Now().Add(-time.Hour))
+	env.savePayment(t, "intent2", 200, time.Now().Add(-30*time.Minute))
+
+	for _, format := range []string{"csv", "json"} {
+		statusCode, body := env.createExportLink(t, env.owner, start, end, format, time.Now())
+		require.Equal(t, http.StatusOK, statusCode, body)
+		assert.Equal(t, true, body[successJsonKey])
+
+		link, ok := body[urlJsonKey].(string)
+		require.True(t, ok)
+		assert.True(t, strings.HasPrefix(link, testBaseUrl+v1ExportPath+"?"))
+		assert.NotEmpty(t, body[expiresAtJsonKey])
+
+		recorder := env.download(t, link)
+		require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+		assert.Contains(t, recorder.Header().Get(contentDispositionHeaderName), fmt.Sprintf(".%s\"", format))
+
+		switch format {
+		case "csv":
+			assert.Equal(t, "text/csv", recorder.Header().Get(contentTypeHeaderName))
+
+			rows, err := csv.NewReader(recorder.Body).ReadAll()
+			require.NoError(t, err)
+			require.Len(t, rows, 3)
+			assert.Equal(t, "intent1", rows[1][1])
+			assert.Equal(t, "intent2", rows[2][1])
+		case "json":
+			assert.Equal(t, "application/json", recorder.Header().Get(contentTypeHeaderName))
+
+			var statement history_util.Statement
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &statement))
+			assert.Equal(t, env.owner.PublicKey().ToBase58(), statement.Owner)
+			require.Len(t, statement.Entries, 2)
+			assert.Equal(t, "intent1", statement.Entries[0].IntentId)
+			assert.Equal(t, "intent2", statement.Entries[1].IntentId)
+		}
+	}
+}
+
+func TestServer_InvalidCreateExportLinkRequests(t *testing.T) {
+	env := setup(t, testSigningKey, time.Hour)
+
+	now := time.Now()
+
+	for _, tc := range []struct {
+		start  time.Time
+		end    time.Time
+		format string
+	}{
+		{now, now, "csv"},
+		{now, now.Add(-time.Hour), "csv"},
+		{now.Add(-history_util.MaxExportRange - time.Hour), now, "csv"},
+		{now.Add(-time.Hour), now, "xml"},
+	} {
+		statusCode, body := env.createExportLink(t, env.owner, tc.start, tc.end, tc.format, now)
+		assert.Equal(t, http.StatusBadRequest, statusCode)
+		assert.Equal(t, false, body[successJsonKey])
+		assert.NotEmpty(t, body[errorJsonKey])
+	}
+
+	statusCode, _ := env.do(t, http.MethodGet, v1CreateExportLinkPath, "")
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+
+	statusCode, _ = env.do(t, http.MethodPost, v1CreateExportLinkPath, "not json")
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+}
+
+func TestServer_UnauthenticatedCreateExportLinkRequests(t *testing.T) {
+	env := setup(t, testSigningKey, time.Hour)
+
+	start := time.Now().Add(-time.Hour)
+	end := time.Now()
+
+	// Stale timestamps
+	for _, timestamp := range []time.Time{time.Now().Add(-5 * time.Minute), time.Now().Add(5 * time.Minute)} {
+		statusCode, _ := env.createExportLink(t, env.owner, start, end, "csv", timestamp)
+		assert.Equal(t, http.StatusUnauthorized, statusCode)
+	}
+
+	// Signed by another account
+	other, err := common.NewRandomAccount()
+	require.NoError(t, err)
+
+	req := env.newCreateExportLinkRequestBody(t, other, start, end, "csv", time.Now())
+	req["owner"] = env.owner.PublicKey().ToBase58()
+	marshalled, err := json.Marshal(req)
+	require.NoError(t, err)
+
+	statusCode, _ := env.do(t, http.MethodPost, v1CreateExportLinkPath, string(marshalled))
+	assert.Equal(t, http.StatusUnauthorized, statusCode)
+
+	// Signed parameters don't match the request
+	req = env.newCreateExportLinkRequestBody(t, env.owner, start, end, "csv", time.Now())
+	req["format"] = "json"
+	marshalled, err = json.Marshal(req)
+	require.NoError(t, err)
+
+	statusCode, _ = env.do(t, http.MethodPost, v1CreateExportLinkPath, string(marshalled))
+	assert.Equal(t, http.StatusUnauthorized, statusCode)
+}
+
+func TestServer_InvalidDownloadLinks(t *testing.T) {
+	env := setup(t, testSigningKey, time.Hour)
+
+	statusCode, body := env.createExportLink(t, env.owner, time.Now().Add(-time.Hour), time.Now(), "csv", time.Now())
+	require.Equal(t, http.StatusOK, statusCode)
+
+	link, err := url.Parse(body[urlJsonKey].(string))
+	require.NoError(t, err)
+
+	other, err := common.NewRandomAccount()
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		param    string
+		value    string
+		expected int
+	}{
+		{ownerQueryParam, other.PublicKey().ToBase58(), http.StatusUnauthorized},
+		{startQueryParam, fmt.Sprintf("%d", time.Now().Add(-2*time.Hour).Unix()), http.StatusUnauthorized},
+		{formatQueryParam, "json", http.StatusUnauthorized},
+		{expiresQueryParam, fmt.Sprintf("%d", time.Now().Add(24*time.Hour).Unix()), http.StatusUnauthorized},
+		{signatureQueryParam, "invalid", http.StatusBadRequest},
+		{ownerQueryParam, "invalid", http.StatusBadRequest},
+		{endQueryParam, "invalid", http.StatusBadRequest},
+	} {
+		query := link.Query()
+		query.Set(tc.param, tc.value)
+
+		modified := *link
+		modified.RawQuery = query.Encode()
+
+		recorder := env.download(t, modified.String())
+		assert.Equal(t, tc.expected, recorder.Code, tc.param)
+	}
+
+	// Links signed with a different key are rejected
+	otherEnv := setup(t, []byte("other-signing-key"), time.Hour)
+	recorder := otherEnv.download(t, link.String())
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
+
+func TestServer_ExpiredDownloadLink(t *testing.T) {
+	env := setup(t, testSigningKey, -time.Second)
+
+	statusCode, body := env.createExportLink(t, env.owner, time.Now().Add(-time.Hour), time.Now(), "csv", time.Now())
+	require.Equal(t, http.StatusOK, statusCode)
+
+	recorder := env.download(t, body[urlJsonKey].(string))
+	assert.Equal(t, http.StatusGone, recorder.Code)
+}
+
+func TestServer_Disabled(t *testing.T) {
+	env := setup(t, nil, time.Hour)
+
+	statusCode, _ := env.createExportLink(t, env.owner, time.Now().Add(-time.Hour), time.Now(), "csv", time.Now())
+	assert.Equal(t, http.StatusNotFound, statusCode)
+
+	download := &downloadRequest{
+		exportRequest: exportRequest{
+			owner:  env.owner,
+			start:  time.Now().Add(-time.Hour),
+			end:    time.Now(),
+			format: history_util.FormatCSV,
+		},
+		expiresAt: time.Now().Add(time.Hour),
+	}
+	recorder := env.download(t, v1ExportPath+"?"+download.toQuery(nil).Encode())
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+type testEnv struct {
+	ctx      context.Context
+	data     code_data.Provider
+	owner    *common.Account
+	handlers map[string]http.HandlerFunc
+}
+
+func setup(t *testing.T, signingKey []byte, linkTtl time.Duration) *testEnv {
+	data := datatest.NewDataProvider()
+
+	owner, err := common.NewRandomAccount()
+	require.NoError(t, err)
+
+	require.NoError(t, data.ImportExchangeRates(context.Background(), &currency.MultiRateRecord{
+		Time:  time.Now().Add(-48 * time.Hour),
+		Rates: map[string]float64{string(currency_lib.USD): 0.00001},
+	}))
+
+	return &testEnv{
+		ctx:      context.Background(),
+		data:     data,
+		owner:    owner,
+		handlers: NewHistoryExportServer(history_util.NewExporter(data, nil), testBaseUrl, signingKey, linkTtl).GetHandlers(),
+	}
+}
+
+func (e *testEnv) savePayment(t *testing.T, intentId string, kinAmount uint64, createdAt time.Time) {
+	destination, err := common.NewRandomAccount()
+	require.NoError(t, err)
+
+	require.NoError(t, e.data.SaveIntent(e.ctx, &intent.Record{
+		IntentId:              intentId,
+		IntentType:            intent.SendPublicPayment,
+		InitiatorOwnerAccount: e.owner.PublicKey().ToBase58(),
+		SendPublicPaymentMetadata: &intent.SendPublicPaymentMetadata{
+			DestinationOwnerAccount: destination.PublicKey().ToBase58(),
+			DestinationTokenAccount: destination.PublicKey().ToBase58(),
+			Quantity:                kin.ToQuarks(kinAmount),
+			ExchangeCurrency:        currency_lib.KIN,
+			ExchangeRate:            1.0,
+			NativeAmount:            float64(kinAmount),
+			UsdMarketValue:          1.0,
+		},
+		State:     intent.StateConfirmed,
+		CreatedAt: createdAt,
+	}))
+}
+
+func (e *testEnv) newCreateExportLinkRequestBody(t *testing.T, signer *common.Account, start, end time.Time, format string, timestamp time.Time) map[string]any {
+	message := fmt.Sprintf(
+		"code-history-export:%s:%d:%d:%s:%d",
+		signer.PublicKey().ToBase58(),
+		start.Unix(),
+		end.Unix(),
+		format,
+		timestamp.Unix(),
+	)
+
+	signature, err := signer.Sign([]byte(message))
+	require.NoError(t, err)
+
+	return map[string]any{
+		"owner":     signer.PublicKey().ToBase58(),
+		"start":     start.Unix(),
+		"end":       end.Unix(),
+		"format":    format,
+		"timestamp": timestamp.Unix(),
+		"signature": base58.Encode(signature),
+	}
+}
+
+func (e *testEnv) createExportLink(t *testing.T, owner *common.Account, start, end time.Time, format string, timestamp time.Time) (int, map[string]interface{}) {
+	marshalled, err := json.Marshal(e.newCreateExportLinkRequestBody(t, owner, start, end, format, timestamp))
+	require.NoError(t, err)
+
+	return e.do(t, http.MethodPost, v1CreateExportLinkPath, string(marshalled))
+}
+
+func (e *testEnv) download(t *testing.T, link string) *httptest.ResponseRecorder {
+	parsed, err := url.Parse(link)
+	require.NoError(t, err)
+
+	handler, ok := e.handlers[parsed.Path]
+	require.True(t, ok)
+
+	recorder := httptest.NewRecorder()
+	handler(recorder, httptest.NewRequest(http.MethodGet, parsed.RequestURI(), nil))
+	return recorder
+}
+
+func (e *testEnv) do(t *testing.T, method, path, body string) (int, map[string]interface{}) {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+
+	handler, ok := e.handlers[strings.Split(path, "?")[0]]
+	require.True(t, ok)
+
+	recorder := httptest.NewRecorder()
+	handler(recorder, req)
+
+	var res map[string]interface{}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+	return recorder.Code, res
+}
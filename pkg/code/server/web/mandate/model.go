@@ -0,0 +1,339 @@
+package mandate
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mr-tron/base58"
+	"github.com/pkg/errors"
+
+	currency_lib "github.com/code-payments/code-server/pkg/currency"
+	"github.com/code-payments/code-server/pkg/solana"
+	"github.com/code-payments/code-server/pkg/code/common"
+	"github.com/code-payments/code-server/pkg/code/data/mandate"
+)
+
+const (
+	successJsonKey      = "success"
+	errorJsonKey        = "error"
+	mandateIdJsonKey    = "mandate_id"
+	quarksJsonKey       = "quarks"
+	exchangeRateJsonKey = "exchange_rate"
+	messagesJsonKey     = "messages"
+	mandatesJsonKey     = "mandates"
+)
+
+type genericApiResponseBody map[string]any
+
+func newGenericApiSuccessResponseBody() genericApiResponseBody {
+	return map[string]any{
+		successJsonKey: true,
+	}
+}
+
+func newGenericApiFailureResponseBody(err error) genericApiResponseBody {
+	return map[string]any{
+		successJsonKey: false,
+		errorJsonKey:   err.Error(),
+	}
+}
+
+func (b *genericApiResponseBody) toString() string {
+	marshalled, _ := json.Marshal(b)
+	return string(marshalled)
+}
+
+// createRequest is a request by an owner to create a recurring payment mandate.
+// It must be signed by the owner account.
+type createRequest struct {
+	owner          *common.Account
+	destination    *common.Account
+	currency       currency_lib.Code
+	amount         float64
+	cadence        mandate.Cadence
+	maxPayments    uint32
+	firstPaymentAt time.Time
+	timestamp      time.Time
+	signature      []byte
+}
+
+func newCreateRequestFromHttpContext(r *http.Request) (*createRequest, error) {
+	httpRequestBody := struct {
+		Owner          string  `json:"owner"`
+		Destination    string  `json:"destination"`
+		Currency       string  `json:"currency"`
+		Amount         float64 `json:"amount"`
+		Cadence        string  `json:"cadence"`
+		MaxPayments    uint32  `json:"max_payments"`
+		FirstPaymentAt int64   `json:"first_payment_at"`
+		Timestamp      int64   `json:"timestamp"`
+		Signature      string  `json:"signature"`
+	}{}
+
+	err := readJsonBody(r, &httpRequestBody)
+	if err != nil {
+		return nil, err
+	}
+
+	owner, err := common.NewAccountFromPublicKeyString(httpRequestBody.Owner)
+	if err != nil {
+		return nil, errors.New("owner is not a public key")
+	}
+
+	destination, err := common.NewAccountFromPublicKeyString(httpRequestBody.Destination)
+	if err != nil {
+		return nil, errors.New("destination is not a public key")
+	}
+
+	signature, err := decodeSignature(httpRequestBody.Signature)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &createRequest{
+		owner:          owner,
+		destination:    destination,
+		currency:       currency_lib.Code(strings.ToLower(httpRequestBody.Currency)),
+		amount:         httpRequestBody.Amount,
+		maxPayments:    httpRequestBody.MaxPayments,
+		firstPaymentAt: time.Unix(httpRequestBody.FirstPaymentAt, 0),
+		timestamp:      time.Unix(httpRequestBody.Timestamp, 0),
+		signature:      signature,
+	}
+
+	if !ed25519.Verify(owner.PublicKey().ToBytes(), req.getMessageToSign(httpRequestBody.Cadence), signature) {
+		return nil, errUnauthenticated
+	}
+
+	req.cadence, err = parseCadence(httpRequestBody.Cadence)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func (r *createRequest) validate() error {
+	if r.owner.PublicKey().ToBase58() == r.destination.PublicKey().ToBase58() {
+		return errors.New("destination cannot be the owner")
+	}
+
+	if r.amount <= 0 {
+		return errors.New("amount must be positive")
+	}
+
+	if r.maxPayments == 0 || r.maxPayments > maxPaymentsPerMandate {
+		return errors.Errorf("max payments must be between 1 and %d", maxPaymentsPerMandate)
+	}
+
+	return nil
+}
+
+// getMessageToSign gets the message the owner signs to agree to the terms of
+// the mandate
+func (r *createRequest) getMessageToSign(cadence string) []byte {
+	return []byte(fmt.Sprintf(
+		"code-mandate-create:%s:%s:%s:%s:%s:%d:%d:%d",
+		r.owner.PublicKey().ToBase58(),
+		r.destination.PublicKey().ToBase58(),
+		r.currency,
+		strconv.FormatFloat(r.amount, 'f', -1, 64),
+		cadence,
+		r.maxPayments,
+		r.firstPaymentAt.Unix(),
+		r.timestamp.Unix(),
+	))
+}
+
+// authorizeRequest provides the owner's signatures for every payment of a
+// mandate. The signatures themselves authenticate the request.
+type authorizeRequest struct {
+	owner      *common.Account
+	mandateId  string
+	signatures [][]byte
+}
+
+func newAuthorizeRequestFromHttpContext(r *http.Request) (*authorizeRequest, error) {
+	httpRequestBody := struct {
+		Owner      string   `json:"owner"`
+		MandateId  string   `json:"mandate_id"`
+		Signatures []string `json:"signatures"`
+	}{}
+
+	err := readJsonBody(r, &httpRequestBody)
+	if err != nil {
+		return nil, err
+	}
+
+	owner, err := common.NewAccountFromPublicKeyString(httpRequestBody.Owner)
+	if err != nil {
+		return nil, errors.New("owner is not a public key")
+	}
+
+	if len(httpRequestBody.MandateId) == 0 {
+		return nil, errors.New("mandate id is required")
+	}
+
+	if len(httpRequestBody.Signatures) == 0 || len(httpRequestBody.Signatures) > maxPaymentsPerMandate {
+		return nil, errors.New("signatures are invalid")
+	}
+
+	req := &authorizeRequest{
+		owner:     owner,
+		mandateId: httpRequestBody.MandateId,
+	}
+	for _, encoded := range httpRequestBody.Signatures {
+		signature, err := decodeSignature(encoded)
+		if err != nil {
+			return nil, err
+		}
+		req.signatures = append(req.signatures, signature)
+	}
+
+	return req, nil
+}
+
+// ownerRequest is a request signed by the owner that targets either a single
+// mandate, or all of the owner's mandates when no mandate ID is provided
+type ownerRequest struct {
+	owner     *common.Account
+	mandateId string
+	timestamp time.Time
+}
+
+func newOwnerRequestFromHttpContext(r *http.Request, action string, requireMandateId bool) (*ownerRequest, error) {
+	httpRequestBody := struct {
+		Owner     string `json:"owner"`
+		MandateId string `json:"mandate_id"`
+		Timestamp int64  `json:"timestamp"`
+		Signature string `json:"signature"`
+	}{}
+
+	err := readJsonBody(r, &httpRequestBody)
+	if err != nil {
+		return nil, err
+	}
+
+	owner, err := common.NewAccountFromPublicKeyString(httpRequestBody.Owner)
+	if err != nil {
+		return nil, errors.New("owner is not a public key")
+	}
+
+	if requireMandateId && len(httpRequestBody.MandateId) == 0 {
+		return nil, errors.New("mandate id is required")
+	}
+
+	signature, err := decodeSignature(httpRequestBody.Signature)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &ownerRequest{
+		owner:     owner,
+		mandateId: httpRequestBody.MandateId,
+		timestamp: time.Unix(httpRequestBody.Timestamp, 0),
+	}
+
+	if !ed25519.Verify(owner.PublicKey().ToBytes(), req.getMessageToSign(action), signature) {
+		return nil, errUnauthenticated
+	}
+
+	return req, nil
+}
+
+// getMessageToSign gets the message the owner signs to prove they're making
+// the request
+func (r *ownerRequest) getMessageToSign(action string) []byte {
+	if len(r.mandateId) == 0 {
+		return []byte(fmt.Sprintf("code-mandate-%s:%s:%d", action, r.owner.PublicKey().ToBase58(), r.timestamp.Unix()))
+	}
+	return []byte(fmt.Sprintf("code-mandate-%s:%s:%s:%d", action, r.owner.PublicKey().ToBase58(), r.mandateId, r.timestamp.Unix()))
+}
+
+// mandateView is the client-facing representation of a mandate
+type mandateView struct {
+	MandateId         string  `json:"mandate_id"`
+	Destination       string  `json:"destination"`
+	Currency          string  `json:"currency"`
+	Amount            float64 `json:"amount"`
+	ExchangeRate      float64 `json:"exchange_rate"`
+	Quarks            uint64  `json:"quarks"`
+	Cadence           string  `json:"cadence"`
+	MaxPayments       uint32  `json:"max_payments"`
+	PaymentsProcessed uint32  `json:"payments_processed"`
+	PaymentsFailed    uint32  `json:"payments_failed"`
+	NextPaymentAt     *int64  `json:"next_payment_at,omitempty"`
+	State             string  `json:"state"`
+	CreatedAt         int64   `json:"created_at"`
+}
+
+func newMandateView(record *mandate.Record) *mandateView {
+	view := &mandateView{
+		MandateId:         record.MandateId,
+		Destination:       record.DestinationOwnerAccount,
+		Currency:          string(record.ExchangeCurrency),
+		Amount:            record.NativeAmount,
+		ExchangeRate:      record.ExchangeRate,
+		Quarks:            record.Quantity,
+		Cadence:           record.Cadence.String(),
+		MaxPayments:       record.MaxPayments,
+		PaymentsProcessed: record.PaymentsProcessed,
+		PaymentsFailed:    record.PaymentsFailed,
+		State:             record.State.String(),
+		CreatedAt:         record.CreatedAt.Unix(),
+	}
+	if !record.State.IsTerminal() {
+		nextPaymentAt := record.NextPaymentAt.Unix()
+		view.NextPaymentAt = &nextPaymentAt
+	}
+	return view
+}
+
+func toMessagesJson(txns []solana.Transaction) []string {
+	res := make([]string, len(txns))
+	for i, txn := range txns {
+		res[i] = base64.StdEncoding.EncodeToString(txn.Message.Marshal())
+	}
+	return res
+}
+
+func parseCadence(value string) (mandate.Cadence, error) {
+	for _, cadence := range []mandate.Cadence{mandate.CadenceDaily, mandate.CadenceWeekly, mandate.CadenceMonthly} {
+		if value == cadence.String() {
+			return cadence, nil
+		}
+	}
+	return mandate.CadenceUnknown, errors.Errorf("cadence %q is not supported", value)
+}
+
+func decodeSignature(value string) ([]byte, error) {
+	signature, err := base58.Decode(value)
+	if err != nil || len(signature) != ed25519.SignatureSize {
+		return nil, errors.New("signature is invalid")
+	}
+	return signature, nil
+}
+
+func readJsonBody(r *http.Request, dst any) error {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestBodySize))
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(body, dst)
+	if err != nil {
+		return errors.New("invalid json body")
+	}
+	return nil
+}
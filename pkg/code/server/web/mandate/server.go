@@ -0,0 +1,388 @@
+package mandate
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/mr-tron/base58"
+	"github.com/sirupsen/logrus"
+
+	commonpb "github.com/code-payments/code-protobuf-api/generated/go/common/v1"
+
+	"github.com/code-payments/code-server/pkg/kin"
+	"github.com/code-payments/code-server/pkg/code/antispam"
+	"github.com/code-payments/code-server/pkg/code/common"
+	code_data "github.com/code-payments/code-server/pkg/code/data"
+	"github.com/code-payments/code-server/pkg/code/data/account"
+	"github.com/code-payments/code-server/pkg/code/data/currency"
+	"github.com/code-payments/code-server/pkg/code/data/mandate"
+	exchange_rate_util "github.com/code-payments/code-server/pkg/code/exchangerate"
+	limit_util "github.com/code-payments/code-server/pkg/code/limit"
+	mandate_util "github.com/code-payments/code-server/pkg/code/mandate"
+)
+
+const (
+	v1PathPrefix    = "/v1/mandate"
+	v1CreatePath    = v1PathPrefix + "/create"
+	v1AuthorizePath = v1PathPrefix + "/authorize"
+	v1CancelPath    = v1PathPrefix + "/cancel"
+	v1ListPath      = v1PathPrefix + "/list"
+
+	contentTypeHeaderName      = "content-type"
+	jsonContentTypeHeaderValue = "application/json"
+
+	maxRequestBodySize = 4096
+
+	// Owner signed requests must be recent to limit replays
+	maxRequestAge = time.Minute
+
+	// Every payment is pre-signed and holds a nonce until it's made, so the
+	// number of payments per mandate is kept small
+	maxPaymentsPerMandate = 12
+
+	// Mandates pending authorization hold nonces for all of their payments, so
+	// owners can only have a few of them at a time
+	maxPendingMandatesPerOwner = 3
+)
+
+var (
+	errUnauthenticated  = errors.New("authentication failed")
+	errDenied           = errors.New("request denied")
+	errMandateNotFound  = errors.New("mandate not found")
+	errInternalServer   = errors.New("internal server error")
+	errUnexpectedState  = errors.New("mandate is not in a valid state for this request")
+	errConcurrentUpdate = errors.New("mandate was concurrently updated")
+)
+
+// Server manages recurring payment mandates on behalf of owners.
+//
+// A mandate is created with the owner's signature over its terms, which returns
+// a transaction message for every payment. The owner signs each message to
+// authorize the mandate, after which payments are made by the mandate worker
+// as they become due.
+type Server struct {
+	log    *logrus.Entry
+	data   code_data.Provider
+	guard  *antispam.Guard
+	limits *limit_util.Resolver
+}
+
+func NewMandateServer(data code_data.Provider, guard *antispam.Guard) *Server {
+	return &Server{
+		log:    logrus.StandardLogger().WithField("type", "mandate/server"),
+		data:   data,
+		guard:  guard,
+		limits: limit_util.NewResolver(data),
+	}
+}
+
+func (s *Server) createHandler(path string) func(w http.ResponseWriter, r *http.Request) {
+	return s.handle(path, func(ctx context.Context, log *logrus.Entry, r *http.Request) (int, genericApiResponseBody) {
+		req, err := newCreateRequestFromHttpContext(r)
+		if err == errUnauthenticated {
+			return http.StatusUnauthorized, newGenericApiFailureResponseBody(err)
+		} else if err != nil {
+			return http.StatusBadRequest, newGenericApiFailureResponseBody(err)
+		}
+
+		log = log.WithField("owner", req.owner.PublicKey().ToBase58())
+
+		if isStale(req.timestamp) {
+			return http.StatusUnauthorized, newGenericApiFailureResponseBody(errors.New("request timestamp is stale"))
+		}
+
+		existingRecords, err := s.data.GetAllMandatesByOwner(ctx, req.owner.PublicKey().ToBase58())
+		if err != nil && err != mandate.ErrMandateNotFound {
+			log.WithError(err).Warn("failure getting existing mandates")
+			return http.StatusInternalServerError, newGenericApiFailureResponseBody(errInternalServer)
+		}
+
+		var pendingCount int
+		for _, existingRecord := range existingRecords {
+			if existingRecord.State == mandate.StatePendingAuthorization {
+				pendingCount++
+			}
+		}
+		if pendingCount >= maxPendingMandatesPerOwner {
+			return http.StatusTooManyRequests, newGenericApiFailureResponseBody(errors.New("too many mandates pending authorization"))
+		}
+
+		// A first payment time of zero means the first payment is made as soon
+		// as the mandate is authorized
+		firstPaymentAt := req.firstPaymentAt
+		if firstPaymentAt.Unix() == 0 {
+			firstPaymentAt = time.Now()
+		} else if firstPaymentAt.Before(time.Now().Add(-maxRequestAge)) {
+			return http.StatusBadRequest, newGenericApiFailureResponseBody(errors.New("first payment cannot be in the past"))
+		}
+
+		sourceAccountInfo, err := s.data.GetLatestAccountInfoByOwnerAddressAndType(ctx, req.owner.PublicKey().ToBase58(), commonpb.AccountType_PRIMARY)
+		if err == account.ErrAccountInfoNotFound {
+			return http.StatusBadRequest, newGenericApiFailureResponseBody(errors.New("owner has no primary account"))
+		} else if err != nil {
+			log.WithError(err).Warn("failure getting owner account info")
+			return http.StatusInternalServerError, newGenericApiFailureResponseBody(errInternalServer)
+		}
+
+		destinationAccountInfo, err := s.data.GetLatestAccountInfoByOwnerAddressAndType(ctx, req.destination.PublicKey().ToBase58(), commonpb.AccountType_PRIMARY)
+		if err == account.ErrAccountInfoNotFound {
+			return http.StatusBadRequest, newGenericApiFailureResponseBody(errors.New("destination has no primary account"))
+		} else if err != nil {
+			log.WithError(err).Warn("failure getting destination account info")
+			return http.StatusInternalServerError, newGenericApiFailureResponseBody(errInternalServer)
+		}
+
+		destinationTokenAccount, err := common.NewAccountFromPublicKeyString(destinationAccountInfo.TokenAccount)
+		if err != nil {
+			log.WithError(err).Warn("invalid destination token account")
+			return http.StatusInternalServerError, newGenericApiFailureResponseBody(errInternalServer)
+		}
+
+		// Every payment is also checked when it's made, but mandates hold nonces
+		// until then, so abusers are kept from creating them at all
+		allow, err := s.guard.AllowSendPayment(ctx, req.owner, true, destinationTokenAccount)
+		if err != nil {
+			log.WithError(err).Warn("failure performing antispam checks")
+			return http.StatusInternalServerError, newGenericApiFailureResponseBody(errInternalServer)
+		} else if !allow {
+			return http.StatusForbidden, newGenericApiFailureResponseBody(errDenied)
+		}
+
+		exchangeRecord, err := s.data.GetExchangeRate(ctx, req.currency, exchange_rate_util.GetLatestExchangeRateTime())
+		if err == currency.ErrNotFound {
+			return http.StatusBadRequest, newGenericApiFailureResponseBody(errors.New("currency is not supported"))
+		} else if err != nil {
+			log.WithError(err).Warn("failure getting exchange rate")
+			return http.StatusInternalServerError, newGenericApiFailureResponseBody(errInternalServer)
+		}
+
+		quarks := uint64(math.Round(req.amount / exchangeRecord.Rate * float64(kin.QuarksPerKin)))
+		if quarks == 0 {
+			return http.StatusBadRequest, newGenericApiFailureResponseBody(errors.New("amount is too small"))
+		}
+
+		limits, err := s.limits.GetLimits(ctx, req.owner)
+		if err != nil {
+			log.WithError(err).Warn("failure getting limits")
+			return http.StatusInternalServerError, newGenericApiFailureResponseBody(errInternalServer)
+		}
+
+		sendLimit, ok := limits.Send[req.currency]
+		if !ok || req.amount > sendLimit.PerTransaction {
+			return http.StatusBadRequest, newGenericApiFailureResponseBody(errors.New("amount exceeds the send limit"))
+		}
+
+		var rawMandateId [32]byte
+		rand.Read(rawMandateId[:])
+
+		record := &mandate.Record{
+			MandateId: base58.Encode(rawMandateId[:]),
+
+			OwnerAccount:       req.owner.PublicKey().ToBase58(),
+			SourceTokenAccount: sourceAccountInfo.TokenAccount,
+
+			DestinationOwnerAccount: req.destination.PublicKey().ToBase58(),
+			DestinationTokenAccount: destinationAccountInfo.TokenAccount,
+
+			ExchangeCurrency: req.currency,
+			ExchangeRate:     exchangeRecord.Rate,
+			NativeAmount:     req.amount,
+			Quantity:         quarks,
+
+			Cadence:     req.cadence,
+			MaxPayments: req.maxPayments,
+
+			FirstPaymentAt: firstPaymentAt,
+			NextPaymentAt:  firstPaymentAt,
+
+			Signature: base58.Encode(req.signature),
+
+			State: mandate.StatePendingAuthorization,
+
+			CreatedAt: time.Now(),
+		}
+
+		txns, err := mandate_util.ReservePayments(ctx, s.data, record)
+		if err != nil {
+			log.WithError(err).Warn("failure reserving mandate payments")
+			return http.StatusInternalServerError, newGenericApiFailureResponseBody(errInternalServer)
+		}
+
+		log.WithField("mandate", record.MandateId).Debug("created mandate")
+
+		respBody := newGenericApiSuccessResponseBody()
+		respBody[mandateIdJsonKey] = record.MandateId
+		respBody[quarksJsonKey] = record.Quantity
+		respBody[exchangeRateJsonKey] = record.ExchangeRate
+		respBody[messagesJsonKey] = toMessagesJson(txns)
+		return http.StatusOK, respBody
+	})
+}
+
+func (s *Server) authorizeHandler(path string) func(w http.ResponseWriter, r *http.Request) {
+	return s.handle(path, func(ctx context.Context, log *logrus.Entry, r *http.Request) (int, genericApiResponseBody) {
+		req, err := newAuthorizeRequestFromHttpContext(r)
+		if err != nil {
+			return http.StatusBadRequest, newGenericApiFailureResponseBody(err)
+		}
+
+		log = log.WithFields(logrus.Fields{
+			"owner":   req.owner.PublicKey().ToBase58(),
+			"mandate": req.mandateId,
+		})
+
+		record, statusCode, body := s.getOwnedMandate(ctx, log, req.owner.PublicKey().ToBase58(), req.mandateId)
+		if record == nil {
+			return statusCode, body
+		}
+
+		err = mandate_util.AuthorizePayments(ctx, s.data, record, req.signatures)
+		if statusCode, body, ok := toErrorResponse(err); ok {
+			return statusCode, body
+		} else if err != nil {
+			log.WithError(err).Warn("failure authorizing mandate payments")
+			return http.StatusBadRequest, newGenericApiFailureResponseBody(err)
+		}
+
+		log.Debug("authorized mandate")
+
+		return http.StatusOK, newGenericApiSuccessResponseBody()
+	})
+}
+
+func (s *Server) cancelHandler(path string) func(w http.ResponseWriter, r *http.Request) {
+	return s.handle(path, func(ctx context.Context, log *logrus.Entry, r *http.Request) (int, genericApiResponseBody) {
+		req, err := newOwnerRequestFromHttpContext(r, "cancel", true)
+		if err == errUnauthenticated {
+			return http.StatusUnauthorized, newGenericApiFailureResponseBody(err)
+		} else if err != nil {
+			return http.StatusBadRequest, newGenericApiFailureResponseBody(err)
+		}
+
+		log = log.WithFields(logrus.Fields{
+			"owner":   req.owner.PublicKey().ToBase58(),
+			"mandate": req.mandateId,
+		})
+
+		if isStale(req.timestamp) {
+			return http.StatusUnauthorized, newGenericApiFailureResponseBody(errors.New("request timestamp is stale"))
+		}
+
+		record, statusCode, body := s.getOwnedMandate(ctx, log, req.owner.PublicKey().ToBase58(), req.mandateId)
+		if record == nil {
+			return statusCode, body
+		}
+
+		err = mandate_util.CancelMandate(ctx, s.data, record)
+		if statusCode, body, ok := toErrorResponse(err); ok {
+			return statusCode, body
+		} else if err != nil {
+			log.WithError(err).Warn("failure cancelling mandate")
+			return http.StatusInternalServerError, newGenericApiFailureResponseBody(errInternalServer)
+		}
+
+		log.Debug("cancelled mandate")
+
+		return http.StatusOK, newGenericApiSuccessResponseBody()
+	})
+}
+
+func (s *Server) listHandler(path string) func(w http.ResponseWriter, r *http.Request) {
+	return s.handle(path, func(ctx context.Context, log *logrus.Entry, r *http.Request) (int, genericApiResponseBody) {
+		req, err := newOwnerRequestFromHttpContext(r, "list", false)
+		if err == errUnauthenticated {
+			return http.StatusUnauthorized, newGenericApiFailureResponseBody(err)
+		} else if err != nil {
+			return http.StatusBadRequest, newGenericApiFailureResponseBody(err)
+		}
+
+		log = log.WithField("owner", req.owner.PublicKey().ToBase58())
+
+		if isStale(req.timestamp) {
+			return http.StatusUnauthorized, newGenericApiFailureResponseBody(errors.New("request timestamp is stale"))
+		}
+
+		records, err := s.data.GetAllMandatesByOwner(ctx, req.owner.PublicKey().ToBase58())
+		if err != nil && err != mandate.ErrMandateNotFound {
+			log.WithError(err).Warn("failure getting mandates")
+			return http.StatusInternalServerError, newGenericApiFailureResponseBody(errInternalServer)
+		}
+
+		views := make([]*mandateView, 0, len(records))
+		for _, record := range records {
+			views = append(views, newMandateView(record))
+		}
+
+		respBody := newGenericApiSuccessResponseBody()
+		respBody[mandatesJsonKey] = views
+		return http.StatusOK, respBody
+	})
+}
+
+// getOwnedMandate gets a mandate, which must belong to the owner. When the
+// mandate can't be returned, a response is provided instead.
+func (s *Server) getOwnedMandate(ctx context.Context, log *logrus.Entry, owner, mandateId string) (*mandate.Record, int, genericApiResponseBody) {
+	record, err := s.data.GetMandate(ctx, mandateId)
+	if err == mandate.ErrMandateNotFound {
+		return nil, http.StatusNotFound, newGenericApiFailureResponseBody(errMandateNotFound)
+	} else if err != nil {
+		log.WithError(err).Warn("failure getting mandate")
+		return nil, http.StatusInternalServerError, newGenericApiFailureResponseBody(errInternalServer)
+	}
+
+	// Don't leak the existence of mandates owned by other accounts
+	if record.OwnerAccount != owner {
+		return nil, http.StatusNotFound, newGenericApiFailureResponseBody(errMandateNotFound)
+	}
+
+	return record, http.StatusOK, nil
+}
+
+func (s *Server) handle(path string, fn func(ctx context.Context, log *logrus.Entry, r *http.Request) (int, genericApiResponseBody)) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		log := s.log.WithField("path", path)
+
+		statusCode, body := func() (int, genericApiResponseBody) {
+			if r.Method != http.MethodPost {
+				return http.StatusBadRequest, newGenericApiFailureResponseBody(errors.New("http post expected"))
+			}
+
+			return fn(r.Context(), log, r)
+		}()
+
+		w.Header().Set(contentTypeHeaderName, jsonContentTypeHeaderValue)
+		w.WriteHeader(statusCode)
+		w.Write([]byte(body.toString()))
+	}
+}
+
+func (s *Server) GetHandlers() map[string]http.HandlerFunc {
+	return map[string]http.HandlerFunc{
+		v1CreatePath:    s.createHandler(v1CreatePath),
+		v1AuthorizePath: s.authorizeHandler(v1AuthorizePath),
+		v1CancelPath:    s.cancelHandler(v1CancelPath),
+		v1ListPath:      s.listHandler(v1ListPath),
+	}
+}
+
+// toErrorResponse maps well-known mandate errors to a response
+func toErrorResponse(err error) (int, genericApiResponseBody, bool) {
+	switch err {
+	case mandate_util.ErrInvalidSignature:
+		return http.StatusUnauthorized, newGenericApiFailureResponseBody(errUnauthenticated), true
+	case mandate_util.ErrUnexpectedMandateState:
+		return http.StatusConflict, newGenericApiFailureResponseBody(errUnexpectedState), true
+	case mandate.ErrStaleVersion:
+		return http.StatusConflict, newGenericApiFailureResponseBody(errConcurrentUpdate), true
+	}
+	return 0, nil, false
+}
+
+func isStale(timestamp time.Time) bool {
+	age := time.Since(timestamp)
+	return age > maxRequestAge || age < -maxRequestAge
+}
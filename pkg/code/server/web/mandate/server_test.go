@@ -0,0 +1,452 @@
+package mandate
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mr-tron/base58"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	commonpb "github.com/code-payments/code-protobuf-api/generated/go/common/v1"
+
+	memory_device_verifier "github.com/code-payments/code-server/pkg/device/memory"
+	currency_lib "github.com/code-payments/code-server/pkg/currency"
+	"github.com/code-payments/code-server/pkg/kin"
+	"github.com/code-payments/code-server/pkg/solana"
+	timelock_token_v1 "github.com/code-payments/code-server/pkg/solana/timelock/v1"
+	"github.com/code-payments/code-server/pkg/testutil"
+	"github.com/code-payments/code-server/pkg/code/antispam"
+	"github.com/code-payments/code-server/pkg/code/common"
+	code_data "github.com/code-payments/code-server/pkg/code/data"
+	datatest "github.com/code-payments/code-server/pkg/code/data/test"
+	"github.com/code-payments/code-server/pkg/code/data/account"
+	"github.com/code-payments/code-server/pkg/code/data/currency"
+	"github.com/code-payments/code-server/pkg/code/data/mandate"
+	"github.com/code-payments/code-server/pkg/code/data/nonce"
+	"github.com/code-payments/code-server/pkg/code/data/phone"
+	"github.com/code-payments/code-server/pkg/code/data/vault"
+	exchange_rate_util "github.com/code-payments/code-server/pkg/code/exchangerate"
+)
+
+func TestServer_HappyPath(t *testing.T) {
+	env := setup(t)
+
+	owner := env.setupUser(t)
+	destination := env.setupUser(t)
+
+	statusCode, body := env.create(t, owner, destination, 5.0, "weekly", 3, time.Now())
+	require.Equal(t, http.StatusOK, statusCode, body)
+	assert.Equal(t, true, body[successJsonKey])
+	assert.EqualValues(t, kin.ToQuarks(50), body[quarksJsonKey])
+	assert.EqualValues(t, 0.1, body[exchangeRateJsonKey])
+
+	mandateId := body[mandateIdJsonKey].(string)
+	messages := body[messagesJsonKey].([]any)
+	require.Len(t, messages, 3)
+
+	record, err := env.data.GetMandate(env.ctx, mandateId)
+	require.NoError(t, err)
+	assert.Equal(t, mandate.StatePendingAuthorization, record.State)
+	assert.Equal(t, owner.PublicKey().ToBase58(), record.OwnerAccount)
+	assert.Equal(t, destination.PublicKey().ToBase58(), record.DestinationOwnerAccount)
+	assert.Equal(t, currency_lib.USD, record.ExchangeCurrency)
+	assert.Equal(t, 5.0, record.NativeAmount)
+	assert.Equal(t, kin.ToQuarks(50), record.Quantity)
+	assert.Equal(t, mandate.CadenceWeekly, record.Cadence)
+	assert.EqualValues(t, 3, record.MaxPayments)
+
+	statusCode, body = env.authorize(t, owner, mandateId, signMessages(t, owner, messages))
+	require.Equal(t, http.StatusOK, statusCode, body)
+
+	record, err = env.data.GetMandate(env.ctx, mandateId)
+	require.NoError(t, err)
+	assert.Equal(t, mandate.StateActive, record.State)
+
+	// Mandates can only be authorized once
+	statusCode, _ = env.authorize(t, owner, mandateId, signMessages(t, owner, messages))
+	assert.Equal(t, http.StatusConflict, statusCode)
+
+	statusCode, body = env.list(t, owner)
+	require.Equal(t, http.StatusOK, statusCode, body)
+	mandates := body[mandatesJsonKey].([]any)
+	require.Len(t, mandates, 1)
+	view := mandates[0].(map[string]any)
+	assert.Equal(t, mandateId, view["mandate_id"])
+	assert.Equal(t, "weekly", view["cadence"])
+	assert.Equal(t, mandate.StateActive.String(), view["state"])
+	assert.NotNil(t, view["next_payment_at"])
+
+	statusCode, body = env.cancel(t, owner, mandateId, time.Now())
+	require.Equal(t, http.StatusOK, statusCode, body)
+
+	record, err = env.data.GetMandate(env.ctx, mandateId)
+	require.NoError(t, err)
+	assert.Equal(t, mandate.StateCancelled, record.State)
+
+	// Cancelled mandates can't be cancelled again
+	statusCode, _ = env.cancel(t, owner, mandateId, time.Now())
+	assert.Equal(t, http.StatusConflict, statusCode)
+}
+
+func TestServer_InvalidCreateRequests(t *testing.T) {
+	env := setup(t)
+
+	owner := env.setupUser(t)
+	destination := env.setupUser(t)
+	unknown := testutil.NewRandomAccount(t)
+
+	for _, tc := range []struct {
+		destination *common.Account
+		amount      float64
+		cadence     string
+		maxPayments uint32
+	}{
+		{owner, 1.0, "daily", 1},
+		{unknown, 1.0, "daily", 1},
+		{destination, 0, "daily", 1},
+		{destination, 1.0, "yearly", 1},
+		{destination, 1.0, "daily", 0},
+		{destination, 1.0, "daily", maxPaymentsPerMandate + 1},
+		{destination, 1_000_000, "daily", 1},
+	} {
+		statusCode, body := env.create(t, owner, tc.destination, tc.amount, tc.cadence, tc.maxPayments, time.Now())
+		assert.Equal(t, http.StatusBadRequest, statusCode)
+		assert.Equal(t, false, body[successJsonKey])
+		assert.NotEmpty(t, body[errorJsonKey])
+	}
+
+	statusCode, _ := env.do(t, http.MethodGet, v1CreatePath, "")
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+
+	statusCode, _ = env.do(t, http.MethodPost, v1CreatePath, "not json")
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+}
+
+func TestServer_UnauthenticatedRequests(t *testing.T) {
+	env := setup(t)
+
+	owner := env.setupUser(t)
+	destination := env.setupUser(t)
+	other := env.setupUser(t)
+
+	// Stale timestamps
+	for _, timestamp := range []time.Time{time.Now().Add(-5 * time.Minute), time.Now().Add(5 * time.Minute)} {
+		statusCode, _ := env.create(t, owner, destination, 1.0, "daily", 1, timestamp)
+		assert.Equal(t, http.StatusUnauthorized, statusCode)
+	}
+
+	// Signed terms don't match the request
+	req := env.newCreateRequestBody(t, owner, destination, 1.0, "daily", 1, time.Now())
+	req["amount"] = 2.0
+	statusCode, _ := env.doJson(t, v1CreatePath, req)
+	assert.Equal(t, http.StatusUnauthorized, statusCode)
+
+	statusCode, body := env.create(t, owner, destination, 1.0, "daily", 2, time.Now())
+	require.Equal(t, http.StatusOK, statusCode)
+	mandateId := body[mandateIdJsonKey].(string)
+	messages := body[messagesJsonKey].([]any)
+
+	// Payments signed by another account
+	statusCode, _ = env.authorize(t, owner, mandateId, signMessages(t, other, messages))
+	assert.Equal(t, http.StatusUnauthorized, statusCode)
+
+	// Mandates owned by other accounts are hidden
+	statusCode, _ = env.authorize(t, other, mandateId, signMessages(t, other, messages))
+	assert.Equal(t, http.StatusNotFound, statusCode)
+
+	statusCode, _ = env.cancel(t, other, mandateId, time.Now())
+	assert.Equal(t, http.StatusNotFound, statusCode)
+
+	statusCode, body = env.list(t, other)
+	require.Equal(t, http.StatusOK, statusCode)
+	assert.Empty(t, body[mandatesJsonKey])
+
+	// Cancel signed by another account
+	req = env.newOwnerRequestBody(t, other, "cancel", mandateId, time.Now())
+	req["owner"] = owner.PublicKey().ToBase58()
+	statusCode, _ = env.doJson(t, v1CancelPath, req)
+	assert.Equal(t, http.StatusUnauthorized, statusCode)
+
+	record, err := env.data.GetMandate(env.ctx, mandateId)
+	require.NoError(t, err)
+	assert.Equal(t, mandate.StatePendingAuthorization, record.State)
+}
+
+func TestServer_PendingMandateLimit(t *testing.T) {
+	env := setup(t)
+
+	owner := env.setupUser(t)
+	destination := env.setupUser(t)
+
+	var mandateIds []string
+	for i := 0; i < maxPendingMandatesPerOwner; i++ {
+		statusCode, body := env.create(t, owner, destination, 1.0, "daily", 1, time.Now())
+		require.Equal(t, http.StatusOK, statusCode, body)
+		mandateIds = append(mandateIds, body[mandateIdJsonKey].(string))
+	}
+
+	statusCode, body := env.create(t, owner, destination, 1.0, "daily", 1, time.Now())
+	assert.Equal(t, http.StatusTooManyRequests, statusCode)
+	assert.Equal(t, false, body[successJsonKey])
+
+	// Other owners aren't affected
+	statusCode, _ = env.create(t, destination, owner, 1.0, "daily", 1, time.Now())
+	assert.Equal(t, http.StatusOK, statusCode)
+
+	// Mandates that are no longer pending don't count towards the limit
+	statusCode, _ = env.cancel(t, owner, mandateIds[0], time.Now())
+	require.Equal(t, http.StatusOK, statusCode)
+
+	statusCode, _ = env.create(t, owner, destination, 1.0, "daily", 1, time.Now())
+	assert.Equal(t, http.StatusOK, statusCode)
+}
+
+func TestServer_RecreateCancelledMandate(t *testing.T) {
+	env := setup(t)
+
+	owner := env.setupUser(t)
+	destination := env.setupUser(t)
+
+	// Cancelled payments return their nonces to the pool, so mandates with the
+	// same terms must not reuse them for identical transactions
+	for i := 0; i < 10; i++ {
+		statusCode, body := env.create(t, owner, destination, 1.0, "daily", 2, time.Now())
+		require.Equal(t, http.StatusOK, statusCode, body)
+
+		statusCode, body = env.cancel(t, owner, body[mandateIdJsonKey].(string), time.Now())
+		require.Equal(t, http.StatusOK, statusCode, body)
+	}
+}
+
+func TestServer_AntispamDenied(t *testing.T) {
+	env := setup(t)
+
+	owner := env.setupUnverifiedUser(t)
+	destination := env.setupUser(t)
+
+	statusCode, body := env.create(t, owner, destination, 1.0, "daily", 1, time.Now())
+	assert.Equal(t, http.StatusForbidden, statusCode)
+	assert.Equal(t, false, body[successJsonKey])
+
+	records, err := env.data.GetAllMandatesByOwner(env.ctx, owner.PublicKey().ToBase58())
+	if err != mandate.ErrMandateNotFound {
+		require.NoError(t, err)
+	}
+	assert.Empty(t, records)
+}
+
+func TestServer_MandateNotFound(t *testing.T) {
+	env := setup(t)
+
+	owner := env.setupUser(t)
+
+	statusCode, _ := env.cancel(t, owner, "unknown", time.Now())
+	assert.Equal(t, http.StatusNotFound, statusCode)
+
+	signature := make([]byte, ed25519.SignatureSize)
+	statusCode, _ = env.authorize(t, owner, "unknown", [][]byte{signature})
+	assert.Equal(t, http.StatusNotFound, statusCode)
+}
+
+type testEnv struct {
+	ctx        context.Context
+	data       code_data.Provider
+	subsidizer *common.Account
+	handlers   map[string]http.HandlerFunc
+}
+
+func setup(t *testing.T) *testEnv {
+	ctx := context.Background()
+
+	data := datatest.NewDataProvider()
+
+	require.NoError(t, data.ImportExchangeRates(ctx, &currency.MultiRateRecord{
+		Time:  exchange_rate_util.GetLatestExchangeRateTime(),
+		Rates: map[string]float64{string(currency_lib.USD): 0.1},
+	}))
+
+	antispamGuard := antispam.NewGuard(
+		data,
+		memory_device_verifier.NewMemoryDeviceVerifier(),
+		antispam.WithPaymentRateLimit(time.Nanosecond),
+	)
+
+	env := &testEnv{
+		ctx:        ctx,
+		data:       data,
+		subsidizer: testutil.SetupRandomSubsidizer(t, data),
+		handlers:   NewMandateServer(data, antispamGuard).GetHandlers(),
+	}
+	env.generateAvailableNonces(t, 20)
+	return env
+}
+
+func (e *testEnv) generateAvailableNonces(t *testing.T, count int) {
+	for i := 0; i < count; i++ {
+		nonceAccount := testutil.NewRandomAccount(t)
+
+		var bh solana.Blockhash
+		rand.Read(bh[:])
+
+		require.NoError(t, e.data.SaveKey(e.ctx, &vault.Record{
+			PublicKey:  nonceAccount.PublicKey().ToBase58(),
+			PrivateKey: nonceAccount.PrivateKey().ToBase58(),
+			State:      vault.StateAvailable,
+			CreatedAt:  time.Now(),
+		}))
+		require.NoError(t, e.data.SaveNonce(e.ctx, &nonce.Record{
+			Address:   nonceAccount.PublicKey().ToBase58(),
+			Authority: e.subsidizer.PublicKey().ToBase58(),
+			Blockhash: base58.Encode(bh[:]),
+			Purpose:   nonce.PurposeMandatePayment,
+			State:     nonce.StateAvailable,
+		}))
+	}
+}
+
+func (e *testEnv) setupUser(t *testing.T) *common.Account {
+	owner := e.setupUnverifiedUser(t)
+
+	require.NoError(t, e.data.SavePhoneVerification(e.ctx, &phone.Verification{
+		PhoneNumber:    fmt.Sprintf("+1800555%04d", time.Now().UnixNano()%10000),
+		OwnerAccount:   owner.PublicKey().ToBase58(),
+		CreatedAt:      time.Now(),
+		LastVerifiedAt: time.Now(),
+	}))
+
+	return owner
+}
+
+func (e *testEnv) setupUnverifiedUser(t *testing.T) *common.Account {
+	owner := testutil.NewRandomAccount(t)
+
+	timelockAccounts, err := owner.GetTimelockAccounts(timelock_token_v1.DataVersion1)
+	require.NoError(t, err)
+	require.NoError(t, e.data.SaveTimelock(e.ctx, timelockAccounts.ToDBRecord()))
+
+	require.NoError(t, e.data.CreateAccountInfo(e.ctx, &account.Record{
+		OwnerAccount:     owner.PublicKey().ToBase58(),
+		AuthorityAccount: owner.PublicKey().ToBase58(),
+		TokenAccount:     timelockAccounts.Vault.PublicKey().ToBase58(),
+		AccountType:      commonpb.AccountType_PRIMARY,
+		CreatedAt:        time.Now(),
+	}))
+
+	return owner
+}
+
+func (e *testEnv) newCreateRequestBody(t *testing.T, owner, destination *common.Account, amount float64, cadence string, maxPayments uint32, timestamp time.Time) map[string]any {
+	message := fmt.Sprintf(
+		"code-mandate-create:%s:%s:%s:%s:%s:%d:%d:%d",
+		owner.PublicKey().ToBase58(),
+		destination.PublicKey().ToBase58(),
+		currency_lib.USD,
+		strconv.FormatFloat(amount, 'f', -1, 64),
+		cadence,
+		maxPayments,
+		0,
+		timestamp.Unix(),
+	)
+
+	signature, err := owner.Sign([]byte(message))
+	require.NoError(t, err)
+
+	return map[string]any{
+		"owner":            owner.PublicKey().ToBase58(),
+		"destination":      destination.PublicKey().ToBase58(),
+		"currency":         string(currency_lib.USD),
+		"amount":           amount,
+		"cadence":          cadence,
+		"max_payments":     maxPayments,
+		"first_payment_at": 0,
+		"timestamp":        timestamp.Unix(),
+		"signature":        base58.Encode(signature),
+	}
+}
+
+func (e *testEnv) newOwnerRequestBody(t *testing.T, signer *common.Account, action, mandateId string, timestamp time.Time) map[string]any {
+	message := fmt.Sprintf("code-mandate-%s:%s:%d", action, signer.PublicKey().ToBase58(), timestamp.Unix())
+	if len(mandateId) > 0 {
+		message = fmt.Sprintf("code-mandate-%s:%s:%s:%d", action, signer.PublicKey().ToBase58(), mandateId, timestamp.Unix())
+	}
+
+	signature, err := signer.Sign([]byte(message))
+	require.NoError(t, err)
+
+	return map[string]any{
+		"owner":      signer.PublicKey().ToBase58(),
+		"mandate_id": mandateId,
+		"timestamp":  timestamp.Unix(),
+		"signature":  base58.Encode(signature),
+	}
+}
+
+func (e *testEnv) create(t *testing.T, owner, destination *common.Account, amount float64, cadence string, maxPayments uint32, timestamp time.Time) (int, map[string]any) {
+	return e.doJson(t, v1CreatePath, e.newCreateRequestBody(t, owner, destination, amount, cadence, maxPayments, timestamp))
+}
+
+func (e *testEnv) authorize(t *testing.T, owner *common.Account, mandateId string, signatures [][]byte) (int, map[string]any) {
+	encoded := make([]string, len(signatures))
+	for i, signature := range signatures {
+		encoded[i] = base58.Encode(signature)
+	}
+
+	return e.doJson(t, v1AuthorizePath, map[string]any{
+		"owner":      owner.PublicKey().ToBase58(),
+		"mandate_id": mandateId,
+		"signatures": encoded,
+	})
+}
+
+func (e *testEnv) cancel(t *testing.T, owner *common.Account, mandateId string, timestamp time.Time) (int, map[string]any) {
+	return e.doJson(t, v1CancelPath, e.newOwnerRequestBody(t, owner, "cancel", mandateId, timestamp))
+}
+
+func (e *testEnv) list(t *testing.T, owner *common.Account) (int, map[string]any) {
+	return e.doJson(t, v1ListPath, e.newOwnerRequestBody(t, owner, "list", "", time.Now()))
+}
+
+func (e *testEnv) doJson(t *testing.T, path string, body map[string]any) (int, map[string]any) {
+	marshalled, err := json.Marshal(body)
+	require.NoError(t, err)
+
+	return e.do(t, http.MethodPost, path, string(marshalled))
+}
+
+func (e *testEnv) do(t *testing.T, method, path, body string) (int, map[string]any) {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+
+	handler, ok := e.handlers[path]
+	require.True(t, ok)
+
+	recorder := httptest.NewRecorder()
+	handler(recorder, req)
+
+	var res map[string]any
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+	return recorder.Code, res
+}
+
+func signMessages(t *testing.T, signer *common.Account, messages []any) [][]byte {
+	var signatures [][]byte
+	for _, message := range messages {
+		decoded, err := base64.StdEncoding.DecodeString(message.(string))
+		require.NoError(t, err)
+
+		signature, err := signer.Sign(decoded)
+		require.NoError(t, err)
+		signatures = append(signatures, signature)
+	}
+	return signatures
+}
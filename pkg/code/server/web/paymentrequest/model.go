@@ -21,7 +21,6 @@ import (
 	"github.com/code-payments/code-server/pkg/netutil"
 	"github.com/code-payments/code-server/pkg/solana"
 	"github.com/code-payments/code-server/pkg/code/common"
-	"github.com/code-payments/code-server/pkg/code/limit"
 )
 
 type trustedPaymentRequest struct {
@@ -83,18 +82,15 @@ func newTrustedPaymentRequestFromHttpContext(r *http.Request) (*trustedPaymentRe
 	}
 
 	currency := currency_lib.Code(strings.ToLower(currencyQueryParam[0]))
-	limits, ok := limit.MicroPaymentLimits[currency]
-	if !ok {
-		return nil, errors.Errorf("%s currency is not currently supported", currency)
-	}
 
+	// Currency support and amount limits depend on the destination owner's
+	// tier, so they're enforced by the messaging service when the request is
+	// sent.
 	amount, err := strconv.ParseFloat(amountQueryParam[0], 64)
 	if err != nil {
 		return nil, errors.New("amount is not a number")
-	} else if amount > limits.Max {
-		return nil, errors.Errorf("%s currency has a maximum amount of %.2f", currency, limits.Max)
-	} else if amount < limits.Min {
-		return nil, errors.Errorf("%s currency has a minimum amount of %.2f", currency, limits.Min)
+	} else if amount <= 0 {
+		return nil, errors.New("amount must be positive")
 	}
 
 	idempotencyKey := kikcode.GenerateRandomIdempotencyKey()
@@ -246,13 +242,11 @@ func newTrustlessPaymentRequestFromHttpContext(r *http.Request) (*trustlessPayme
 		return nil, errors.New("exchange data not provided")
 	}
 
-	limits, ok := limit.MicroPaymentLimits[currency]
-	if !ok {
-		return nil, errors.Errorf("%s currency is not currently supported", currency)
-	} else if amount > limits.Max {
-		return nil, errors.Errorf("%s currency has a maximum amount of %.2f", currency, limits.Max)
-	} else if amount < limits.Min {
-		return nil, errors.Errorf("%s currency has a minimum amount of %.2f", currency, limits.Min)
+	// Currency support and amount limits depend on the destination owner's
+	// tier, so they're enforced by the messaging service when the request is
+	// sent.
+	if amount <= 0 {
+		return nil, errors.New("amount must be positive")
 	}
 
 	if httpRequestBody.Webhook != nil {
@@ -0,0 +1,50 @@
+package transaction
+
+import (
+	"github.com/code-payments/code-server/pkg/solana"
+	compute_budget "github.com/code-payments/code-server/pkg/solana/computebudget"
+)
+
+// ComputeBudget defines the compute budget requested by a transaction
+type ComputeBudget struct {
+	// UnitLimit is the maximum number of compute units the transaction can
+	// consume. A value of zero leaves the runtime default in place.
+	UnitLimit uint32
+
+	// UnitPrice is the priority fee paid per compute unit, in micro-lamports.
+	// A value of zero results in no priority fee.
+	UnitPrice uint64
+}
+
+// GetPriorityFee returns the priority fee, in lamports, for a transaction with
+// the provided number of instructions, excluding compute budget instructions.
+func (b *ComputeBudget) GetPriorityFee(numInstructions int) uint64 {
+	if b == nil || b.UnitPrice == 0 {
+		return 0
+	}
+
+	unitLimit := b.UnitLimit
+	if unitLimit == 0 {
+		unitLimit = uint32(numInstructions) * compute_budget.DefaultInstructionComputeUnitLimit
+		if unitLimit > compute_budget.MaxComputeUnitLimit {
+			unitLimit = compute_budget.MaxComputeUnitLimit
+		}
+	}
+
+	return compute_budget.GetPriorityFee(unitLimit, b.UnitPrice)
+}
+
+func makeComputeBudgetInstructions(computeBudget *ComputeBudget) []solana.Instruction {
+	if computeBudget == nil {
+		return nil
+	}
+
+	var instructions []solana.Instruction
+	if computeBudget.UnitLimit > 0 {
+		instructions = append(instructions, compute_budget.SetComputeUnitLimit(computeBudget.UnitLimit))
+	}
+	if computeBudget.UnitPrice > 0 {
+		instructions = append(instructions, compute_budget.SetComputeUnitPrice(computeBudget.UnitPrice))
+	}
+	return instructions
+}
@@ -65,15 +65,28 @@ func SelectAvailableNonce(ctx context.Context, data code_data.Provider, useCase
 		globalNonceLock.Lock()
 		defer globalNonceLock.Unlock()
 
-		randomRecord, err := data.GetRandomAvailableNonceByPurpose(ctx, useCase)
-		if err == nonce.ErrNonceNotFound {
-			return ErrNoAvailableNonces
-		} else if err != nil {
-			return err
+		var err error
+		for {
+			var randomRecord *nonce.Record
+			randomRecord, err = data.GetRandomAvailableNonceByPurpose(ctx, useCase)
+			if err == nonce.ErrNonceNotFound {
+				return ErrNoAvailableNonces
+			} else if err != nil {
+				return err
+			}
+
+			// Nonces created by a retired subsidizer are removed from the pool
+			if randomRecord.Authority == common.GetSubsidizer().PublicKey().ToBase58() {
+				record = randomRecord
+				break
+			}
+
+			err = retireNonce(ctx, data, randomRecord.Address)
+			if err != nil {
+				return err
+			}
 		}
 
-		record = randomRecord
-
 		lock = getNonceLock(record.Address)
 		lock.Lock()
 
@@ -126,6 +139,26 @@ func SelectAvailableNonce(ctx context.Context, data code_data.Provider, useCase
 	}, nil
 }
 
+// retireNonce removes an available nonce created before the subsidizer was
+// rotated from the pool, since the current subsidizer can't advance it.
+func retireNonce(ctx context.Context, data code_data.Provider, address string) error {
+	lock := getNonceLock(address)
+	lock.Lock()
+	defer lock.Unlock()
+
+	record, err := data.GetNonce(ctx, address)
+	if err != nil {
+		return err
+	}
+
+	if record.State != nonce.StateAvailable {
+		return nil
+	}
+
+	record.State = nonce.StateInvalid
+	return data.SaveNonce(ctx, record)
+}
+
 // SelectNonceFromFulfillmentToUpgrade selects a nonce from a fulfillment that
 // is going to be upgraded.
 func SelectNonceFromFulfillmentToUpgrade(ctx context.Context, data code_data.Provider, fulfillmentRecord *fulfillment.Record) (*SelectedNonce, error) {
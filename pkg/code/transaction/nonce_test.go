@@ -15,6 +15,7 @@ import (
 	"github.com/code-payments/code-server/pkg/testutil"
 	"github.com/code-payments/code-server/pkg/code/common"
 	code_data "github.com/code-payments/code-server/pkg/code/data"
+	datatest "github.com/code-payments/code-server/pkg/code/data/test"
 	"github.com/code-payments/code-server/pkg/code/data/fulfillment"
 	"github.com/code-payments/code-server/pkg/code/data/nonce"
 	"github.com/code-payments/code-server/pkg/code/data/vault"
@@ -62,6 +63,31 @@ func TestNonce_SelectAvailableNonce(t *testing.T) {
 	assert.Equal(t, ErrNoAvailableNonces, err)
 }
 
+func TestNonce_SelectAvailableNonce_RotatedSubsidizer(t *testing.T) {
+	env := setupNonceTestEnv(t)
+
+	retiredNonces := generateAvailableNonces(t, env, nonce.PurposeClientTransaction, 10)
+
+	retiredSubsidizer := common.GetSubsidizer()
+	testutil.SetupRandomSubsidizer(t, env.data)
+	require.NoError(t, common.InjectTestRetiredSubsidizer(env.ctx, env.data, retiredSubsidizer))
+
+	_, err := SelectAvailableNonce(env.ctx, env.data, nonce.PurposeClientTransaction)
+	assert.Equal(t, ErrNoAvailableNonces, err)
+
+	for _, nonceRecord := range retiredNonces {
+		updatedRecord, err := env.data.GetNonce(env.ctx, nonceRecord.Address)
+		require.NoError(t, err)
+		assert.Equal(t, nonce.StateInvalid, updatedRecord.State)
+	}
+
+	currentNonce := generateAvailableNonce(t, env, nonce.PurposeClientTransaction)
+
+	selectedNonce, err := SelectAvailableNonce(env.ctx, env.data, nonce.PurposeClientTransaction)
+	require.NoError(t, err)
+	assert.Equal(t, currentNonce.Address, selectedNonce.Account.PublicKey().ToBase58())
+}
+
 func TestNonce_SelectNonceFromFulfillmentToUpgrade_HappyPath(t *testing.T) {
 	env := setupNonceTestEnv(t)
 
@@ -212,7 +238,7 @@ type nonceTestEnv struct {
 }
 
 func setupNonceTestEnv(t *testing.T) nonceTestEnv {
-	data := code_data.NewTestDataProvider()
+	data := datatest.NewDataProvider()
 
 	testutil.SetupRandomSubsidizer(t, data)
 
@@ -1,6 +1,7 @@
 package transaction
 
 import (
+	"bytes"
 	"errors"
 
 	"github.com/code-payments/code-server/pkg/kin"
@@ -33,20 +34,58 @@ func MakeNoncedTransaction(nonce *common.Account, bh solana.Blockhash, instructi
 	return txn, nil
 }
 
+// MakeNoncedVersionedTransaction makes a v0 transaction that's backed by a nonce,
+// and loads eligible accounts from the provided address lookup tables. The nonce
+// account is always included directly in the message. The returned transaction
+// is not signed.
+func MakeNoncedVersionedTransaction(nonce *common.Account, bh solana.Blockhash, lookupTables []solana.AddressLookupTable, instructions ...solana.Instruction) (solana.Transaction, error) {
+	if len(instructions) == 0 {
+		return solana.Transaction{}, errors.New("no instructions provided")
+	}
+
+	advanceNonceInstruction, err := makeAdvanceNonceInstruction(nonce)
+	if err != nil {
+		return solana.Transaction{}, err
+	}
+
+	instructions = append([]solana.Instruction{advanceNonceInstruction}, instructions...)
+
+	// The nonce account must be static for the runtime to recognize a durable
+	// nonce transaction.
+	filteredLookupTables := make([]solana.AddressLookupTable, len(lookupTables))
+	for i, lookupTable := range lookupTables {
+		filteredLookupTables[i].PublicKey = lookupTable.PublicKey
+		for _, address := range lookupTable.Addresses {
+			if bytes.Equal(address, nonce.PublicKey().ToBytes()) {
+				address = nil
+			}
+			filteredLookupTables[i].Addresses = append(filteredLookupTables[i].Addresses, address)
+		}
+	}
+
+	txn := solana.NewVersionedTransaction(common.GetSubsidizer().PublicKey().ToBytes(), filteredLookupTables, instructions...)
+	txn.SetBlockhash(bh)
+
+	return txn, nil
+}
+
 func MakeOpenAccountTransaction(
 	nonce *common.Account,
 	bh solana.Blockhash,
 
 	timelockAccounts *common.TimelockAccounts,
+
+	computeBudget *ComputeBudget,
 ) (solana.Transaction, error) {
 	initializeInstruction, err := timelockAccounts.GetInitializeInstruction()
 	if err != nil {
 		return solana.Transaction{}, err
 	}
 
-	instructions := []solana.Instruction{
+	instructions := append(
+		makeComputeBudgetInstructions(computeBudget),
 		initializeInstruction,
-	}
+	)
 	return MakeNoncedTransaction(nonce, bh, instructions...)
 }
 
@@ -151,6 +190,8 @@ func MakeTreasuryAdvanceTransaction(
 	kinAmountInQuarks uint64,
 	transcript []byte,
 	recentRoot []byte,
+
+	computeBudget *ComputeBudget,
 ) (solana.Transaction, error) {
 	memoInstruction, err := MakeKreMemoInstruction()
 	if err != nil {
@@ -171,9 +212,10 @@ func MakeTreasuryAdvanceTransaction(
 		return solana.Transaction{}, err
 	}
 
-	instructions := []solana.Instruction{
+	instructions := append(
+		makeComputeBudgetInstructions(computeBudget),
 		memoInstruction,
 		transferWithAuthorityInstruction,
-	}
+	)
 	return MakeNoncedTransaction(nonce, bh, instructions...)
 }
@@ -8,15 +8,17 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/code-payments/code-server/pkg/solana"
+	compute_budget "github.com/code-payments/code-server/pkg/solana/computebudget"
 	"github.com/code-payments/code-server/pkg/solana/system"
+	timelock_token "github.com/code-payments/code-server/pkg/solana/timelock/v1"
 	"github.com/code-payments/code-server/pkg/solana/token"
 	"github.com/code-payments/code-server/pkg/testutil"
 	"github.com/code-payments/code-server/pkg/code/common"
-	code_data "github.com/code-payments/code-server/pkg/code/data"
+	datatest "github.com/code-payments/code-server/pkg/code/data/test"
 )
 
 func TestTransaction_MakeNoncedTransaction_HappyPath(t *testing.T) {
-	subsidizer := testutil.SetupRandomSubsidizer(t, code_data.NewTestDataProvider())
+	subsidizer := testutil.SetupRandomSubsidizer(t, datatest.NewDataProvider())
 
 	nonceAccount, err := common.NewAccountFromPublicKeyString("non9MZDuwcTzNYfWFu18XT4MLi3Pf6vscuuMuKTbrTx")
 	require.NoError(t, err)
@@ -78,3 +80,43 @@ func TestTransaction_MakeNoncedTransaction_NoInstructions(t *testing.T) {
 	_, err = MakeNoncedTransaction(nonceAccount, typedBlockhash)
 	assert.Error(t, err)
 }
+
+func TestTransaction_MakeOpenAccountTransaction_ComputeBudget(t *testing.T) {
+	subsidizer := testutil.SetupRandomSubsidizer(t, datatest.NewDataProvider())
+
+	nonceAccount := testutil.NewRandomAccount(t)
+
+	var blockhash solana.Blockhash
+	copy(blockhash[:], testutil.NewRandomAccount(t).PublicKey().ToBytes())
+
+	timelockAccounts, err := testutil.NewRandomAccount(t).GetTimelockAccounts(timelock_token.DataVersion1)
+	require.NoError(t, err)
+
+	txn, err := MakeOpenAccountTransaction(nonceAccount, blockhash, timelockAccounts, nil)
+	require.NoError(t, err)
+	require.Len(t, txn.Message.Instructions, 2)
+
+	computeBudget := &ComputeBudget{
+		UnitLimit: 50_000,
+		UnitPrice: 10_000,
+	}
+	txn, err = MakeOpenAccountTransaction(nonceAccount, blockhash, timelockAccounts, computeBudget)
+	require.NoError(t, err)
+	require.Len(t, txn.Message.Instructions, 4)
+	assert.EqualValues(t, txn.Message.Accounts[0], subsidizer.PublicKey().ToBytes())
+
+	_, err = system.DecompileAdvanceNonce(txn.Message, 0)
+	require.NoError(t, err)
+
+	unitLimit, err := compute_budget.DecompileSetComputeUnitLimit(txn.Message, 1)
+	require.NoError(t, err)
+	assert.EqualValues(t, 50_000, unitLimit.Units)
+
+	unitPrice, err := compute_budget.DecompileSetComputeUnitPrice(txn.Message, 2)
+	require.NoError(t, err)
+	assert.EqualValues(t, 10_000, unitPrice.MicroLamports)
+
+	assert.EqualValues(t, 500, computeBudget.GetPriorityFee(2))
+	assert.EqualValues(t, 6_000, (&ComputeBudget{UnitPrice: 10_000}).GetPriorityFee(3))
+	assert.EqualValues(t, 0, (*ComputeBudget)(nil).GetPriorityFee(3))
+}
@@ -14,6 +14,7 @@ import (
 	messagingpb "github.com/code-payments/code-protobuf-api/generated/go/messaging/v1"
 
 	"github.com/code-payments/code-server/pkg/metrics"
+	"github.com/code-payments/code-server/pkg/retry"
 	"github.com/code-payments/code-server/pkg/code/common"
 	code_data "github.com/code-payments/code-server/pkg/code/data"
 	"github.com/code-payments/code-server/pkg/code/data/webhook"
@@ -25,13 +26,31 @@ const (
 
 	contentTypeHeaderName  = "Content-Type"
 	contentTypeHeaderValue = "application/jwt"
+
+	maxHostCircuitBreakers    = 10_000
+	hostCircuitBreakerIdleTTL = time.Hour
+)
+
+var (
+	// errUnexpectedStatusCode is a non-200 status code. The third party server
+	// responded, so it doesn't count against its circuit breaker.
+	errUnexpectedStatusCode = errors.New("unexpected status code")
+
+	// hostCircuitBreakers stops calls to third party servers that are
+	// consistently failing, keyed by host. Hosts are provided by third parties,
+	// so the set is bounded.
+	hostCircuitBreakers = retry.NewBreakerSet(maxHostCircuitBreakers, hostCircuitBreakerIdleTTL)
 )
 
 // Execute executes the provided webhook. It does not manage the DB record's state.
+//
+// The JWT request body is signed by the provided signing key, which should be
+// loaded via LoadSigningKey.
 func Execute(
 	ctx context.Context,
 	data code_data.Provider,
 	messagingClient messaging.InternalMessageClient,
+	signingKey *common.Account,
 	record *webhook.Record,
 	webhookTimeout time.Duration,
 ) error {
@@ -71,9 +90,8 @@ func Execute(
 			return errors.Wrap(err, "error getting webhook content")
 		}
 
-		signer := common.GetSubsidizer()
 		token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, jwt.MapClaims(kvs))
-		requestBody, err := token.SignedString(ed25519.PrivateKey(signer.PrivateKey().ToBytes()))
+		requestBody, err := token.SignedString(ed25519.PrivateKey(signingKey.PrivateKey().ToBytes()))
 		if err != nil {
 			return errors.Wrap(err, "error signing jwt")
 		}
@@ -92,11 +110,31 @@ func Execute(
 		webhookReq = webhookReq.WithContext(webhookCtx)
 		defer cancel()
 
-		resp, err := http.DefaultClient.Do(webhookReq)
+		// Retries are scheduled by the webhook worker, so the policy only guards
+		// against hammering third party servers that are down.
+		policy := retry.NewPolicy(
+			webhookReq.URL.Host,
+			retry.WithStrategies(retry.Limit(1)),
+			retry.WithCircuitBreaker(hostCircuitBreakers.Get(webhookReq.URL.Host)),
+			retry.WithFailureClassifier(func(err error) bool {
+				return !errors.Is(err, errUnexpectedStatusCode)
+			}),
+			retry.WithHooks(retry.MetricsHooks()),
+		)
+		_, err = policy.Do(ctx, func() error {
+			resp, err := http.DefaultClient.Do(webhookReq)
+			if err != nil {
+				return errors.Wrap(err, "error executing http post request")
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				return errors.Wrapf(errUnexpectedStatusCode, "%d status code returned", resp.StatusCode)
+			}
+			return nil
+		})
 		if err != nil {
-			return errors.Wrap(err, "error executing http post request")
-		} else if resp.StatusCode != http.StatusOK {
-			return errors.Errorf("%d status code returned", resp.StatusCode)
+			return err
 		}
 
 		//
@@ -20,6 +20,7 @@ import (
 	"github.com/code-payments/code-server/pkg/testutil"
 	"github.com/code-payments/code-server/pkg/code/common"
 	code_data "github.com/code-payments/code-server/pkg/code/data"
+	datatest "github.com/code-payments/code-server/pkg/code/data/test"
 	"github.com/code-payments/code-server/pkg/code/data/account"
 	"github.com/code-payments/code-server/pkg/code/data/action"
 	"github.com/code-payments/code-server/pkg/code/data/intent"
@@ -35,13 +36,13 @@ func TestWebhook_HappyPath_IntentSubmmitted(t *testing.T) {
 	intentRecord := env.setupIntentRecord(t, webhookRecord)
 	accountInfoRecord := env.setupRelationshipAccount(t, intentRecord.InitiatorOwnerAccount)
 
-	require.NoError(t, Execute(env.ctx, env.data, env.messagingClient, webhookRecord, time.Second))
+	require.NoError(t, Execute(env.ctx, env.data, env.messagingClient, env.signingKey, webhookRecord, time.Second))
 
 	requests := env.server.GetReceivedRequests()
 	require.Len(t, requests, 1)
 
 	parsed, err := jwt.ParseWithClaims(requests[0], jwt.MapClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return ed25519.PublicKey(common.GetSubsidizer().PublicKey().ToBytes()), nil
+		return ed25519.PublicKey(env.signingKey.PublicKey().ToBytes()), nil
 	})
 	require.NoError(t, err)
 
@@ -67,7 +68,7 @@ func TestWebhook_EndpointError(t *testing.T) {
 	webhookRecord := env.server.GetRandomWebhookRecord(t, webhook.TypeIntentSubmitted)
 	env.setupIntentRecord(t, webhookRecord)
 
-	assert.Error(t, Execute(env.ctx, env.data, env.messagingClient, webhookRecord, time.Second))
+	assert.Error(t, Execute(env.ctx, env.data, env.messagingClient, env.signingKey, webhookRecord, time.Second))
 
 	env.assertNoWebhookCalledMessagesSent(t, webhookRecord)
 }
@@ -79,7 +80,7 @@ func TestWebhook_Timeout(t *testing.T) {
 	webhookRecord := env.server.GetRandomWebhookRecord(t, webhook.TypeIntentSubmitted)
 	env.setupIntentRecord(t, webhookRecord)
 
-	assert.Error(t, Execute(env.ctx, env.data, env.messagingClient, webhookRecord, 100*time.Millisecond))
+	assert.Error(t, Execute(env.ctx, env.data, env.messagingClient, env.signingKey, webhookRecord, 100*time.Millisecond))
 
 	env.assertNoWebhookCalledMessagesSent(t, webhookRecord)
 }
@@ -95,19 +96,19 @@ func TestWebhook_Validation_Generic(t *testing.T) {
 		webhook.StateConfirmed,
 	} {
 		webhookRecord.State = invalidWebhookState
-		assert.Error(t, Execute(env.ctx, env.data, env.messagingClient, webhookRecord, time.Second))
+		assert.Error(t, Execute(env.ctx, env.data, env.messagingClient, env.signingKey, webhookRecord, time.Second))
 		assert.Empty(t, env.server.GetReceivedRequests())
 	}
 
 	webhookRecord = env.server.GetRandomWebhookRecord(t, webhook.TypeIntentSubmitted)
 	env.setupIntentRecord(t, webhookRecord)
 	webhookRecord.NextAttemptAt = pointer.Time(time.Now().Add(time.Second))
-	assert.Error(t, Execute(env.ctx, env.data, env.messagingClient, webhookRecord, time.Second))
+	assert.Error(t, Execute(env.ctx, env.data, env.messagingClient, env.signingKey, webhookRecord, time.Second))
 	assert.Empty(t, env.server.GetReceivedRequests())
 
 	webhookRecord = env.server.GetRandomWebhookRecord(t, webhook.TypeIntentSubmitted)
 	webhookRecord.WebhookId = "not-a-public-key"
-	assert.Error(t, Execute(env.ctx, env.data, env.messagingClient, webhookRecord, time.Second))
+	assert.Error(t, Execute(env.ctx, env.data, env.messagingClient, env.signingKey, webhookRecord, time.Second))
 	assert.Empty(t, env.server.GetReceivedRequests())
 }
 
@@ -115,14 +116,14 @@ func TestWebhook_Validation_IntentSubmitted(t *testing.T) {
 	env := setup(t)
 
 	webhookRecord := env.server.GetRandomWebhookRecord(t, webhook.TypeIntentSubmitted)
-	assert.Error(t, Execute(env.ctx, env.data, env.messagingClient, webhookRecord, time.Second))
+	assert.Error(t, Execute(env.ctx, env.data, env.messagingClient, env.signingKey, webhookRecord, time.Second))
 	assert.Empty(t, env.server.GetReceivedRequests())
 
 	webhookRecord = env.server.GetRandomWebhookRecord(t, webhook.TypeIntentSubmitted)
 	intentRecord := env.setupIntentRecord(t, webhookRecord)
 	intentRecord.State = intent.StateRevoked
 	require.NoError(t, env.data.SaveIntent(env.ctx, intentRecord))
-	assert.Error(t, Execute(env.ctx, env.data, env.messagingClient, webhookRecord, time.Second))
+	assert.Error(t, Execute(env.ctx, env.data, env.messagingClient, env.signingKey, webhookRecord, time.Second))
 	assert.Empty(t, env.server.GetReceivedRequests())
 }
 
@@ -130,16 +131,18 @@ type testEnv struct {
 	ctx             context.Context
 	data            code_data.Provider
 	messagingClient messaging.InternalMessageClient
+	signingKey      *common.Account
 	server          *TestWebhookEndpoint
 }
 
 func setup(t *testing.T) testEnv {
-	data := code_data.NewTestDataProvider()
+	data := datatest.NewDataProvider()
 	testutil.SetupRandomSubsidizer(t, data)
 	return testEnv{
 		ctx:             context.Background(),
 		data:            data,
 		messagingClient: messaging.NewMessagingClient(data),
+		signingKey:      testutil.NewRandomAccount(t),
 		server:          NewTestWebhookEndpoint(t),
 	}
 }
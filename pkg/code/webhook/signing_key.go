@@ -0,0 +1,35 @@
+package webhook
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/code-payments/code-server/pkg/code/common"
+	code_data "github.com/code-payments/code-server/pkg/code/data"
+)
+
+// LoadSigningKey loads the key used to sign webhook JWTs by it's public key over
+// the provided data provider. Third parties verify webhooks using this key, so
+// it's kept separate from subsidizers, which can be rotated independently.
+func LoadSigningKey(ctx context.Context, data code_data.Provider, publicKey string) (*common.Account, error) {
+	if common.IsSubsidizer(publicKey) {
+		return nil, errors.New("subsidizer cannot be used as a webhook signing key")
+	}
+
+	vaultRecord, err := data.GetKey(ctx, publicKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting signing key from vault")
+	}
+
+	signingKey, err := common.NewAccountFromPrivateKeyString(vaultRecord.PrivateKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid signing key")
+	}
+
+	if signingKey.PublicKey().ToBase58() != publicKey {
+		return nil, errors.New("signing key public key mismatch")
+	}
+
+	return signingKey, nil
+}
@@ -0,0 +1,42 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/code-payments/code-server/pkg/testutil"
+	datatest "github.com/code-payments/code-server/pkg/code/data/test"
+	"github.com/code-payments/code-server/pkg/code/data/vault"
+)
+
+func TestLoadSigningKey(t *testing.T) {
+	ctx := context.Background()
+	data := datatest.NewDataProvider()
+	subsidizer := testutil.SetupRandomSubsidizer(t, data)
+
+	key, err := vault.CreateKey()
+	require.NoError(t, err)
+
+	_, err = LoadSigningKey(ctx, data, key.PublicKey)
+	assert.Error(t, err)
+
+	require.NoError(t, data.SaveKey(ctx, key))
+
+	signingKey, err := LoadSigningKey(ctx, data, key.PublicKey)
+	require.NoError(t, err)
+	assert.Equal(t, key.PublicKey, signingKey.PublicKey().ToBase58())
+	assert.Equal(t, key.PrivateKey, signingKey.PrivateKey().ToBase58())
+
+	subsidizerKey := &vault.Record{
+		PublicKey:  subsidizer.PublicKey().ToBase58(),
+		PrivateKey: subsidizer.PrivateKey().ToBase58(),
+		State:      vault.StateAvailable,
+	}
+	require.NoError(t, data.SaveKey(ctx, subsidizerKey))
+
+	_, err = LoadSigningKey(ctx, data, subsidizerKey.PublicKey)
+	assert.Error(t, err)
+}
@@ -36,6 +36,43 @@ func (*noopConfig) Get(_ context.Context) (interface{}, error) {
 func (*noopConfig) Shutdown() {
 }
 
+// Change describes an update to a single key within a Source
+type Change struct {
+	Key string
+
+	// OldValue is nil when the key was newly added
+	OldValue []byte
+
+	// NewValue is nil when the key was removed
+	NewValue []byte
+
+	// Actor identifies who made the change
+	Actor string
+
+	// Source identifies where the change was observed
+	Source string
+
+	Timestamp time.Time
+}
+
+// ChangeHandler is invoked for every Change observed by a Source
+type ChangeHandler func(change *Change)
+
+// Source is a set of keyed configuration values that can change at runtime,
+// without requiring a restart
+type Source interface {
+	// Get returns the latest raw value for the key, or ErrNoValue if it
+	// isn't set
+	Get(ctx context.Context, key string) ([]byte, error)
+
+	// Subscribe registers a handler that's invoked on each change. The returned
+	// function removes the subscription.
+	Subscribe(handler ChangeHandler) (unsubscribe func())
+
+	// Shutdown signals the source to stop all underlying resources
+	Shutdown()
+}
+
 // Bool provides a boolean typed config.Config.
 type Bool interface {
 	Get(ctx context.Context) bool
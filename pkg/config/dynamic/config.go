@@ -0,0 +1,79 @@
+package dynamic
+
+import (
+	"context"
+	"time"
+
+	"github.com/code-payments/code-server/pkg/config"
+	"github.com/code-payments/code-server/pkg/config/env"
+	"github.com/code-payments/code-server/pkg/config/wrapper"
+)
+
+type conf struct {
+	source   config.Source
+	key      string
+	fallback config.Config
+}
+
+// NewConfig returns a config that reads the key from a dynamic source. Keys
+// that aren't set in the source fall back to the environment variable with the
+// same name, so dynamic sources can be layered on top of existing deployments
+// and only need to contain the values being actively overridden.
+func NewConfig(source config.Source, key string) config.Config {
+	return &conf{
+		source:   source,
+		key:      key,
+		fallback: env.NewConfig(key),
+	}
+}
+
+// Get implements Config.Get
+func (c *conf) Get(ctx context.Context) (interface{}, error) {
+	value, err := c.source.Get(ctx, c.key)
+	if err == config.ErrNoValue {
+		return c.fallback.Get(ctx)
+	} else if err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// Shutdown implements Config.Shutdown
+func (c *conf) Shutdown() {
+	// The source is shared across configs and owned by the caller
+}
+
+// NewBytesConfig creates a dynamic bytes config
+func NewBytesConfig(source config.Source, key string, defaultValue []byte) config.Bytes {
+	return wrapper.NewBytesConfig(NewConfig(source, key), defaultValue)
+}
+
+// NewInt64Config creates a dynamic int64 config
+func NewInt64Config(source config.Source, key string, defaultValue int64) config.Int64 {
+	return wrapper.NewInt64Config(NewConfig(source, key), defaultValue)
+}
+
+// NewUint64Config creates a dynamic uint64 config
+func NewUint64Config(source config.Source, key string, defaultValue uint64) config.Uint64 {
+	return wrapper.NewUint64Config(NewConfig(source, key), defaultValue)
+}
+
+// NewFloat64Config creates a dynamic float64 config
+func NewFloat64Config(source config.Source, key string, defaultValue float64) config.Float64 {
+	return wrapper.NewFloat64Config(NewConfig(source, key), defaultValue)
+}
+
+// NewStringConfig creates a dynamic string config
+func NewStringConfig(source config.Source, key string, defaultValue string) config.String {
+	return wrapper.NewStringConfig(NewConfig(source, key), defaultValue)
+}
+
+// NewBoolConfig creates a dynamic bool config
+func NewBoolConfig(source config.Source, key string, defaultValue bool) config.Bool {
+	return wrapper.NewBoolConfig(NewConfig(source, key), defaultValue)
+}
+
+// NewDurationConfig creates a dynamic duration config
+func NewDurationConfig(source config.Source, key string, defaultValue time.Duration) config.Duration {
+	return wrapper.NewDurationConfig(NewConfig(source, key), defaultValue)
+}
@@ -0,0 +1,160 @@
+package dynamic
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/code-payments/code-server/pkg/config"
+)
+
+// Entry is a single value within a snapshot provided to Values.Replace
+type Entry struct {
+	Value     []byte
+	UpdatedBy string
+}
+
+// Values is a thread-safe snapshot of keyed configuration values that
+// notifies subscribers and audit logs every change. It's intended to be
+// embedded by config.Source implementations, which are only responsible
+// for fetching new snapshots.
+type Values struct {
+	log    *logrus.Entry
+	source string
+
+	stateMu sync.RWMutex
+	values  map[string][]byte
+
+	subscribersMu    sync.RWMutex
+	nextSubscriberId uint64
+	subscribers      map[uint64]config.ChangeHandler
+}
+
+// NewValues returns a new empty set of values for the named source
+func NewValues(source string) *Values {
+	return &Values{
+		log: logrus.StandardLogger().WithFields(logrus.Fields{
+			"type":   "config/dynamic",
+			"source": source,
+		}),
+		source:      source,
+		values:      make(map[string][]byte),
+		subscribers: make(map[uint64]config.ChangeHandler),
+	}
+}
+
+// Get returns the latest value for a key, or config.ErrNoValue if it isn't set.
+// Keys are case insensitive to match environment based configs.
+func (v *Values) Get(key string) ([]byte, error) {
+	v.stateMu.RLock()
+	defer v.stateMu.RUnlock()
+
+	value, ok := v.values[normalizeKey(key)]
+	if !ok {
+		return nil, config.ErrNoValue
+	}
+	return append([]byte(nil), value...), nil
+}
+
+// Subscribe registers a handler that's invoked on each change. Handlers are
+// called synchronously, in the order changes are applied, and must not block.
+func (v *Values) Subscribe(handler config.ChangeHandler) func() {
+	v.subscribersMu.Lock()
+	defer v.subscribersMu.Unlock()
+
+	id := v.nextSubscriberId
+	v.nextSubscriberId++
+	v.subscribers[id] = handler
+
+	return func() {
+		v.subscribersMu.Lock()
+		delete(v.subscribers, id)
+		v.subscribersMu.Unlock()
+	}
+}
+
+// Replace swaps the current values for the provided snapshot. Keys missing
+// from the snapshot are considered removed, and are attributed to defaultActor.
+// The set of applied changes is returned.
+func (v *Values) Replace(snapshot map[string]*Entry, defaultActor string) []*config.Change {
+	now := time.Now()
+
+	normalized := make(map[string]*Entry, len(snapshot))
+	for key, entry := range snapshot {
+		normalized[normalizeKey(key)] = entry
+	}
+
+	var changes []*config.Change
+
+	v.stateMu.Lock()
+	for key, oldValue := range v.values {
+		if _, ok := normalized[key]; ok {
+			continue
+		}
+
+		changes = append(changes, &config.Change{
+			Key:       key,
+			OldValue:  oldValue,
+			Actor:     defaultActor,
+			Source:    v.source,
+			Timestamp: now,
+		})
+		delete(v.values, key)
+	}
+	for key, entry := range normalized {
+		oldValue, ok := v.values[key]
+		if ok && bytes.Equal(oldValue, entry.Value) {
+			continue
+		}
+
+		actor := entry.UpdatedBy
+		if len(actor) == 0 {
+			actor = defaultActor
+		}
+
+		newValue := append([]byte{}, entry.Value...)
+		changes = append(changes, &config.Change{
+			Key:       key,
+			OldValue:  oldValue,
+			NewValue:  newValue,
+			Actor:     actor,
+			Source:    v.source,
+			Timestamp: now,
+		})
+		v.values[key] = newValue
+	}
+	v.stateMu.Unlock()
+
+	for _, change := range changes {
+		v.notify(change)
+	}
+	return changes
+}
+
+func (v *Values) notify(change *config.Change) {
+	log := v.log.WithFields(logrus.Fields{
+		"key":   change.Key,
+		"actor": change.Actor,
+	})
+	if change.OldValue != nil {
+		log = log.WithField("old_value", string(change.OldValue))
+	}
+	if change.NewValue != nil {
+		log = log.WithField("new_value", string(change.NewValue))
+	}
+	log.Info("config value changed")
+
+	v.subscribersMu.RLock()
+	defer v.subscribersMu.RUnlock()
+
+	for _, handler := range v.subscribers {
+		handler(change)
+	}
+}
+
+func normalizeKey(key string) string {
+	return strings.ToUpper(strings.TrimSpace(key))
+}
@@ -0,0 +1,104 @@
+package dynamic
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/code-payments/code-server/pkg/config"
+)
+
+type testSource struct {
+	*Values
+}
+
+func (s *testSource) Get(_ context.Context, key string) ([]byte, error) {
+	return s.Values.Get(key)
+}
+
+func (s *testSource) Shutdown() {}
+
+func TestValues_ReplaceAndSubscribe(t *testing.T) {
+	values := NewValues("test")
+
+	var observed []*config.Change
+	unsubscribe := values.Subscribe(func(change *config.Change) {
+		observed = append(observed, change)
+	})
+
+	_, err := values.Get("key1")
+	assert.Equal(t, config.ErrNoValue, err)
+
+	changes := values.Replace(map[string]*Entry{
+		"key1": {Value: []byte("value1"), UpdatedBy: "alice"},
+		"KEY2": {Value: []byte("value2")},
+	}, "default")
+	require.Len(t, changes, 2)
+	assert.Equal(t, changes, observed)
+	for _, change := range changes {
+		assert.Nil(t, change.OldValue)
+		assert.Equal(t, "test", change.Source)
+		switch change.Key {
+		case "KEY1":
+			assert.Equal(t, "alice", change.Actor)
+		case "KEY2":
+			assert.Equal(t, "default", change.Actor)
+		default:
+			assert.Fail(t, "unexpected key")
+		}
+	}
+
+	value, err := values.Get("Key1")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value1"), value)
+
+	observed = nil
+	changes = values.Replace(map[string]*Entry{
+		"key1": {Value: []byte("value1"), UpdatedBy: "alice"},
+		"key2": {Value: []byte("updated"), UpdatedBy: "bob"},
+	}, "default")
+	require.Len(t, changes, 1)
+	assert.Equal(t, changes, observed)
+	assert.Equal(t, "KEY2", changes[0].Key)
+	assert.Equal(t, []byte("value2"), changes[0].OldValue)
+	assert.Equal(t, []byte("updated"), changes[0].NewValue)
+	assert.Equal(t, "bob", changes[0].Actor)
+
+	unsubscribe()
+
+	observed = nil
+	changes = values.Replace(map[string]*Entry{
+		"key2": {Value: []byte("updated"), UpdatedBy: "bob"},
+	}, "carol")
+	require.Len(t, changes, 1)
+	assert.Empty(t, observed)
+	assert.Equal(t, "KEY1", changes[0].Key)
+	assert.Equal(t, []byte("value1"), changes[0].OldValue)
+	assert.Nil(t, changes[0].NewValue)
+	assert.Equal(t, "carol", changes[0].Actor)
+
+	_, err = values.Get("key1")
+	assert.Equal(t, config.ErrNoValue, err)
+}
+
+func TestConfig_EnvFallback(t *testing.T) {
+	const key = "DYNAMIC_CONFIG_TEST_VAR"
+
+	source := &testSource{NewValues("test")}
+	assert.False(t, NewBoolConfig(source, key, false).Get(context.Background()))
+
+	os.Setenv(key, "true")
+	defer os.Unsetenv(key)
+
+	boolConfig := NewBoolConfig(source, key, false)
+	assert.True(t, boolConfig.Get(context.Background()))
+
+	source.Replace(map[string]*Entry{key: {Value: []byte("false")}}, "test")
+	assert.False(t, boolConfig.Get(context.Background()))
+
+	source.Replace(nil, "test")
+	assert.True(t, boolConfig.Get(context.Background()))
+}
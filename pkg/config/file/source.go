@@ -0,0 +1,212 @@
+package file
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+
+	"github.com/code-payments/code-server/pkg/config"
+	"github.com/code-payments/code-server/pkg/config/dynamic"
+)
+
+const (
+	// UpdatedByKey is a reserved top-level key that can be used to attribute
+	// the latest edit of the file for audit logging purposes.
+	UpdatedByKey = "_updated_by"
+
+	defaultPollInterval = 5 * time.Second
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("config file must be json or yaml")
+	ErrUnsupportedValue  = errors.New("config file values must be scalars")
+)
+
+// Source is a config.Source backed by a flat JSON or YAML file of key-value
+// pairs, which is hot reloaded when it changes on disk. The format is determined
+// by the file extension.
+//
+// If the file becomes unreadable or invalid, the last good set of values is
+// retained until it's fixed.
+type Source struct {
+	log  *logrus.Entry
+	path string
+
+	pollInterval time.Duration
+
+	values *dynamic.Values
+
+	stateMu  sync.Mutex
+	lastHash [sha256.Size]byte
+
+	shutdownOnce sync.Once
+	shutdownCh   chan struct{}
+}
+
+// Option configures a Source
+type Option func(s *Source)
+
+// WithPollInterval overrides the default interval at which the file is checked
+// for changes
+func WithPollInterval(interval time.Duration) Option {
+	return func(s *Source) {
+		s.pollInterval = interval
+	}
+}
+
+// NewSource returns a new file-based config.Source. The file must exist and be
+// valid at startup.
+func NewSource(path string, opts ...Option) (*Source, error) {
+	s := &Source{
+		log: logrus.StandardLogger().WithFields(logrus.Fields{
+			"type": "config/file",
+			"path": path,
+		}),
+		path:         path,
+		pollInterval: defaultPollInterval,
+		values:       dynamic.NewValues("file:" + path),
+		shutdownCh:   make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if _, err := s.Reload(); err != nil {
+		return nil, err
+	}
+
+	go s.watch()
+
+	return s, nil
+}
+
+// Get implements config.Source.Get
+func (s *Source) Get(_ context.Context, key string) ([]byte, error) {
+	select {
+	case <-s.shutdownCh:
+		return nil, config.ErrShutdown
+	default:
+	}
+
+	return s.values.Get(key)
+}
+
+// Subscribe implements config.Source.Subscribe
+func (s *Source) Subscribe(handler config.ChangeHandler) func() {
+	return s.values.Subscribe(handler)
+}
+
+// Shutdown implements config.Source.Shutdown
+func (s *Source) Shutdown() {
+	s.shutdownOnce.Do(func() {
+		close(s.shutdownCh)
+	})
+}
+
+// Reload forces the file to be read, and returns the set of changes that were
+// applied, if any
+func (s *Source) Reload() ([]*config.Change, error) {
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+
+	raw, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading config file")
+	}
+
+	hash := sha256.Sum256(raw)
+	if hash == s.lastHash {
+		return nil, nil
+	}
+
+	snapshot, err := parse(s.path, raw)
+	if err != nil {
+		return nil, err
+	}
+
+	actor := "file"
+	if updatedBy, ok := snapshot[UpdatedByKey]; ok {
+		actor = string(updatedBy.Value)
+		delete(snapshot, UpdatedByKey)
+	}
+	for _, entry := range snapshot {
+		entry.UpdatedBy = actor
+	}
+
+	s.lastHash = hash
+	return s.values.Replace(snapshot, actor), nil
+}
+
+func (s *Source) watch() {
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.shutdownCh:
+			return
+		case <-ticker.C:
+			if _, err := s.Reload(); err != nil {
+				s.log.WithError(err).Warn("failure reloading config file, keeping last good values")
+			}
+		}
+	}
+}
+
+func parse(path string, raw []byte) (map[string]*dynamic.Entry, error) {
+	var values map[string]interface{}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(raw))
+		decoder.UseNumber()
+		if err := decoder.Decode(&values); err != nil {
+			return nil, errors.Wrap(err, "error parsing json config file")
+		}
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(raw, &values); err != nil {
+			return nil, errors.Wrap(err, "error parsing yaml config file")
+		}
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+
+	snapshot := make(map[string]*dynamic.Entry, len(values))
+	for key, value := range values {
+		var encoded string
+		switch typed := value.(type) {
+		case nil:
+			continue
+		case string:
+			encoded = typed
+		case bool:
+			encoded = strconv.FormatBool(typed)
+		case json.Number:
+			encoded = typed.String()
+		case int, int64, uint64:
+			encoded = fmt.Sprintf("%d", typed)
+		case float64:
+			encoded = strconv.FormatFloat(typed, 'f', -1, 64)
+		default:
+			return nil, errors.Wrapf(ErrUnsupportedValue, "key %s", key)
+		}
+
+		snapshot[key] = &dynamic.Entry{
+			Value: []byte(encoded),
+		}
+	}
+	return snapshot, nil
+}
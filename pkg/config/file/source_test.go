@@ -0,0 +1,126 @@
+package file
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/code-payments/code-server/pkg/config"
+	"github.com/code-payments/code-server/pkg/config/dynamic"
+)
+
+func TestSource_Yaml(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, path, `
+SEQUENCER_SERVICE_DISABLE_TRANSACTION_SUBMISSION: false
+max_global_failed_fulfillments: 10
+percentile: 0.75
+timeout: 5s
+`)
+
+	source, err := NewSource(path, WithPollInterval(10*time.Millisecond))
+	require.NoError(t, err)
+	defer source.Shutdown()
+
+	ctx := context.Background()
+	disabled := dynamic.NewBoolConfig(source, "SEQUENCER_SERVICE_DISABLE_TRANSACTION_SUBMISSION", true)
+	assert.False(t, disabled.Get(ctx))
+	assert.EqualValues(t, 10, dynamic.NewUint64Config(source, "MAX_GLOBAL_FAILED_FULFILLMENTS", 0).Get(ctx))
+	assert.Equal(t, 0.75, dynamic.NewFloat64Config(source, "percentile", 0).Get(ctx))
+	assert.Equal(t, 5*time.Second, dynamic.NewDurationConfig(source, "timeout", 0).Get(ctx))
+
+	changes := make(chan *config.Change, 10)
+	source.Subscribe(func(change *config.Change) {
+		changes <- change
+	})
+
+	writeFile(t, path, `
+_updated_by: oncall@example.com
+SEQUENCER_SERVICE_DISABLE_TRANSACTION_SUBMISSION: true
+max_global_failed_fulfillments: 10
+percentile: 0.75
+timeout: 5s
+`)
+
+	select {
+	case change := <-changes:
+		assert.Equal(t, "SEQUENCER_SERVICE_DISABLE_TRANSACTION_SUBMISSION", change.Key)
+		assert.Equal(t, []byte("false"), change.OldValue)
+		assert.Equal(t, []byte("true"), change.NewValue)
+		assert.Equal(t, "oncall@example.com", change.Actor)
+	case <-time.After(time.Second):
+		require.Fail(t, "timed out waiting for change")
+	}
+	assert.True(t, disabled.Get(ctx))
+
+	// Invalid files are ignored, and the last good values retained
+	writeFile(t, path, "not: [valid")
+	time.Sleep(50 * time.Millisecond)
+	assert.True(t, disabled.Get(ctx))
+	assert.Empty(t, changes)
+
+	source.Shutdown()
+	_, err = source.Get(ctx, "timeout")
+	assert.Equal(t, config.ErrShutdown, err)
+}
+
+func TestSource_Json(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	writeFile(t, path, `{"key1": "value", "key2": 12345678901234, "key3": true}`)
+
+	source, err := NewSource(path)
+	require.NoError(t, err)
+	defer source.Shutdown()
+
+	ctx := context.Background()
+	for key, expected := range map[string]string{
+		"key1": "value",
+		"key2": "12345678901234",
+		"key3": "true",
+	} {
+		actual, err := source.Get(ctx, key)
+		require.NoError(t, err)
+		assert.Equal(t, expected, string(actual))
+	}
+
+	_, err = source.Get(ctx, "key4")
+	assert.Equal(t, config.ErrNoValue, err)
+
+	writeFile(t, path, `{"key1": "value", "key3": false}`)
+	changes, err := source.Reload()
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	for _, change := range changes {
+		assert.Equal(t, "file", change.Actor)
+	}
+
+	changes, err = source.Reload()
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestSource_InvalidFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := NewSource(filepath.Join(dir, "missing.yaml"))
+	assert.Error(t, err)
+
+	path := filepath.Join(dir, "config.toml")
+	writeFile(t, path, "key = 1")
+	_, err = NewSource(path)
+	assert.Equal(t, ErrUnsupportedFormat, err)
+
+	path = filepath.Join(dir, "config.json")
+	writeFile(t, path, `{"key": {"nested": true}}`)
+	_, err = NewSource(path)
+	assert.Error(t, err)
+}
+
+func writeFile(t *testing.T, path, contents string) {
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0600))
+}
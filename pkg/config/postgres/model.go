@@ -0,0 +1,136 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	pgutil "github.com/code-payments/code-server/pkg/database/postgres"
+)
+
+const (
+	valuesTableName   = "codewallet__core_dynamicconfig"
+	auditLogTableName = "codewallet__core_dynamicconfigauditlog"
+)
+
+type valueModel struct {
+	Id sql.NullInt64 `db:"id"`
+
+	Key   string `db:"key"`
+	Value string `db:"value"`
+
+	UpdatedBy string    `db:"updated_by"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+type auditLogModel struct {
+	Id sql.NullInt64 `db:"id"`
+
+	Key      string         `db:"key"`
+	OldValue sql.NullString `db:"old_value"`
+	NewValue sql.NullString `db:"new_value"`
+
+	ChangedBy string    `db:"changed_by"`
+	ChangedAt time.Time `db:"changed_at"`
+}
+
+func fromAuditLogModel(obj *auditLogModel) *AuditLogEntry {
+	entry := &AuditLogEntry{
+		Key:       obj.Key,
+		ChangedBy: obj.ChangedBy,
+		ChangedAt: obj.ChangedAt,
+	}
+	if obj.OldValue.Valid {
+		entry.OldValue = &obj.OldValue.String
+	}
+	if obj.NewValue.Valid {
+		entry.NewValue = &obj.NewValue.String
+	}
+	return entry
+}
+
+// dbPut upserts the value and records an audit log entry atomically. A nil
+// value deletes the key.
+func dbPut(ctx context.Context, db *sqlx.DB, key string, value *string, actor string) error {
+	return pgutil.ExecuteInTx(ctx, db, sql.LevelDefault, func(tx *sqlx.Tx) error {
+		now := time.Now()
+
+		var oldValue sql.NullString
+		query := `SELECT value FROM ` + valuesTableName + `
+			WHERE key = $1
+			FOR UPDATE
+		`
+		err := tx.QueryRowxContext(ctx, query, key).Scan(&oldValue)
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+
+		newValue := sql.NullString{}
+		if value != nil {
+			newValue.Valid = true
+			newValue.String = *value
+		}
+
+		if oldValue == newValue {
+			return nil
+		}
+
+		if value == nil {
+			query = `DELETE FROM ` + valuesTableName + `
+				WHERE key = $1
+			`
+			_, err = tx.ExecContext(ctx, query, key)
+		} else {
+			query = `INSERT INTO ` + valuesTableName + `
+				(key, value, updated_by, updated_at)
+				VALUES ($1, $2, $3, $4)
+
+				ON CONFLICT (key)
+				DO UPDATE
+					SET value = $2, updated_by = $3, updated_at = $4
+					WHERE ` + valuesTableName + `.key = $1
+			`
+			_, err = tx.ExecContext(ctx, query, key, *value, actor, now)
+		}
+		if err != nil {
+			return err
+		}
+
+		query = `INSERT INTO ` + auditLogTableName + `
+			(key, old_value, new_value, changed_by, changed_at)
+			VALUES ($1, $2, $3, $4, $5)
+		`
+		_, err = tx.ExecContext(ctx, query, key, oldValue, newValue, actor, now)
+		return err
+	})
+}
+
+func dbGetAll(ctx context.Context, db *sqlx.DB) ([]*valueModel, error) {
+	res := []*valueModel{}
+
+	query := `SELECT id, key, value, updated_by, updated_at FROM ` + valuesTableName
+
+	err := db.SelectContext(ctx, &res, query)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func dbGetAuditLog(ctx context.Context, db *sqlx.DB, key string, limit uint64) ([]*auditLogModel, error) {
+	res := []*auditLogModel{}
+
+	query := `SELECT id, key, old_value, new_value, changed_by, changed_at FROM ` + auditLogTableName + `
+		WHERE key = $1
+		ORDER BY id DESC
+		LIMIT $2
+	`
+
+	err := db.SelectContext(ctx, &res, query, key, limit)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,218 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/code-payments/code-server/pkg/config"
+	"github.com/code-payments/code-server/pkg/config/dynamic"
+)
+
+const (
+	defaultPollInterval = 10 * time.Second
+
+	// Removals observed while polling can't be attributed to anyone, since the
+	// row no longer exists. The audit log table has the full history.
+	unknownActor = "unknown"
+)
+
+var (
+	ErrInvalidKey   = errors.New("config key is invalid")
+	ErrInvalidActor = errors.New("config actor is required")
+)
+
+// AuditLogEntry records a single change made via Source.Set or Source.Delete
+type AuditLogEntry struct {
+	Key string
+
+	// OldValue is nil when the key was newly added
+	OldValue *string
+
+	// NewValue is nil when the key was deleted
+	NewValue *string
+
+	ChangedBy string
+	ChangedAt time.Time
+}
+
+// Source is a config.Source backed by a Postgres table. Values are polled
+// periodically, so changes made by any instance are picked up by all others.
+// Every change made via Set or Delete is recorded in an audit log table.
+type Source struct {
+	log *logrus.Entry
+	db  *sqlx.DB
+
+	pollInterval time.Duration
+
+	values *dynamic.Values
+
+	refreshMu sync.Mutex
+
+	shutdownOnce sync.Once
+	shutdownCh   chan struct{}
+}
+
+// Option configures a Source
+type Option func(s *Source)
+
+// WithPollInterval overrides the default interval at which values are refreshed
+func WithPollInterval(interval time.Duration) Option {
+	return func(s *Source) {
+		s.pollInterval = interval
+	}
+}
+
+// NewSource returns a new Postgres-backed config.Source
+func NewSource(ctx context.Context, db *sql.DB, opts ...Option) (*Source, error) {
+	s := &Source{
+		log:          logrus.StandardLogger().WithField("type", "config/postgres"),
+		db:           sqlx.NewDb(db, "pgx"),
+		pollInterval: defaultPollInterval,
+		values:       dynamic.NewValues("postgres"),
+		shutdownCh:   make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if _, err := s.Refresh(ctx); err != nil {
+		return nil, err
+	}
+
+	go s.poll()
+
+	return s, nil
+}
+
+// Get implements config.Source.Get
+func (s *Source) Get(_ context.Context, key string) ([]byte, error) {
+	select {
+	case <-s.shutdownCh:
+		return nil, config.ErrShutdown
+	default:
+	}
+
+	return s.values.Get(key)
+}
+
+// Subscribe implements config.Source.Subscribe
+func (s *Source) Subscribe(handler config.ChangeHandler) func() {
+	return s.values.Subscribe(handler)
+}
+
+// Shutdown implements config.Source.Shutdown
+func (s *Source) Shutdown() {
+	s.shutdownOnce.Do(func() {
+		close(s.shutdownCh)
+	})
+}
+
+// Set sets the value for a key on behalf of the actor. The change is visible
+// locally immediately, and to other instances on their next refresh.
+func (s *Source) Set(ctx context.Context, key, value, actor string) error {
+	key, err := validate(key, actor)
+	if err != nil {
+		return err
+	}
+
+	if err := dbPut(ctx, s.db, key, &value, actor); err != nil {
+		return errors.Wrap(err, "error setting config value")
+	}
+
+	_, err = s.refresh(ctx, actor)
+	return err
+}
+
+// Delete removes the value for a key on behalf of the actor, which results in
+// configs falling back to their defaults
+func (s *Source) Delete(ctx context.Context, key, actor string) error {
+	key, err := validate(key, actor)
+	if err != nil {
+		return err
+	}
+
+	if err := dbPut(ctx, s.db, key, nil, actor); err != nil {
+		return errors.Wrap(err, "error deleting config value")
+	}
+
+	_, err = s.refresh(ctx, actor)
+	return err
+}
+
+// GetAuditLog returns the most recent changes for a key, in descending order
+func (s *Source) GetAuditLog(ctx context.Context, key string, limit uint64) ([]*AuditLogEntry, error) {
+	models, err := dbGetAuditLog(ctx, s.db, normalizeKey(key), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*AuditLogEntry, len(models))
+	for i, model := range models {
+		res[i] = fromAuditLogModel(model)
+	}
+	return res, nil
+}
+
+// Refresh forces values to be reloaded from the database, and returns the set
+// of changes that were applied, if any
+func (s *Source) Refresh(ctx context.Context) ([]*config.Change, error) {
+	return s.refresh(ctx, unknownActor)
+}
+
+func (s *Source) refresh(ctx context.Context, removalActor string) ([]*config.Change, error) {
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+
+	models, err := dbGetAll(ctx, s.db)
+	if err != nil {
+		return nil, errors.Wrap(err, "error loading config values")
+	}
+
+	snapshot := make(map[string]*dynamic.Entry, len(models))
+	for _, model := range models {
+		snapshot[model.Key] = &dynamic.Entry{
+			Value:     []byte(model.Value),
+			UpdatedBy: model.UpdatedBy,
+		}
+	}
+	return s.values.Replace(snapshot, removalActor), nil
+}
+
+func (s *Source) poll() {
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.shutdownCh:
+			return
+		case <-ticker.C:
+			if _, err := s.Refresh(context.Background()); err != nil {
+				s.log.WithError(err).Warn("failure refreshing config values, keeping last good values")
+			}
+		}
+	}
+}
+
+func validate(key, actor string) (string, error) {
+	key = normalizeKey(key)
+	if len(key) == 0 {
+		return "", ErrInvalidKey
+	}
+	if len(strings.TrimSpace(actor)) == 0 {
+		return "", ErrInvalidActor
+	}
+	return key, nil
+}
+
+func normalizeKey(key string) string {
+	return strings.ToUpper(strings.TrimSpace(key))
+}
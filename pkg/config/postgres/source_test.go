@@ -0,0 +1,193 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ory/dockertest/v3"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/code-payments/code-server/pkg/config"
+
+	postgrestest "github.com/code-payments/code-server/pkg/database/postgres/test"
+
+	_ "github.com/jackc/pgx/v4/stdlib"
+)
+
+var (
+	testDB   *sql.DB
+	teardown func()
+)
+
+const (
+	// Used for testing ONLY, the table and migrations are external to this repository
+	tableCreate = `
+	CREATE TABLE codewallet__core_dynamicconfig (
+		id SERIAL NOT NULL PRIMARY KEY,
+
+		key TEXT NOT NULL,
+		value TEXT NOT NULL,
+
+		updated_by TEXT NOT NULL,
+		updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+
+		CONSTRAINT codewallet__core_dynamicconfig__uniq__key UNIQUE (key)
+	);
+
+	CREATE TABLE codewallet__core_dynamicconfigauditlog (
+		id SERIAL NOT NULL PRIMARY KEY,
+
+		key TEXT NOT NULL,
+		old_value TEXT NULL,
+		new_value TEXT NULL,
+
+		changed_by TEXT NOT NULL,
+		changed_at TIMESTAMP WITH TIME ZONE NOT NULL
+	);
+	`
+
+	// Used for testing ONLY, the table and migrations are external to this repository
+	tableDestroy = `
+		DROP TABLE codewallet__core_dynamicconfig;
+		DROP TABLE codewallet__core_dynamicconfigauditlog;
+	`
+)
+
+func TestMain(m *testing.M) {
+	log := logrus.StandardLogger()
+
+	testPool, err := dockertest.NewPool("")
+	if err != nil {
+		log.WithError(err).Error("Error creating docker pool")
+		os.Exit(1)
+	}
+
+	var cleanUpFunc func()
+	db, cleanUpFunc, err := postgrestest.StartPostgresDB(testPool)
+	if err != nil {
+		log.WithError(err).Error("Error starting postgres image")
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	if err := createTestTables(db); err != nil {
+		logrus.StandardLogger().WithError(err).Error("Error creating test tables")
+		cleanUpFunc()
+		os.Exit(1)
+	}
+
+	testDB = db
+	teardown = func() {
+		if pc := recover(); pc != nil {
+			cleanUpFunc()
+			panic(pc)
+		}
+
+		if err := resetTestTables(db); err != nil {
+			logrus.StandardLogger().WithError(err).Error("Error resetting test tables")
+			cleanUpFunc()
+			os.Exit(1)
+		}
+	}
+
+	code := m.Run()
+	cleanUpFunc()
+	os.Exit(code)
+}
+
+func TestPostgresSource(t *testing.T) {
+	defer teardown()
+
+	ctx := context.Background()
+
+	source1, err := NewSource(ctx, testDB, WithPollInterval(10*time.Millisecond))
+	require.NoError(t, err)
+	defer source1.Shutdown()
+
+	source2, err := NewSource(ctx, testDB, WithPollInterval(10*time.Millisecond))
+	require.NoError(t, err)
+	defer source2.Shutdown()
+
+	const key = "SEQUENCER_SERVICE_DISABLE_TRANSACTION_SUBMISSION"
+
+	_, err = source1.Get(ctx, key)
+	assert.Equal(t, config.ErrNoValue, err)
+
+	assert.Equal(t, ErrInvalidKey, source1.Set(ctx, " ", "true", "alice"))
+	assert.Equal(t, ErrInvalidActor, source1.Set(ctx, key, "true", ""))
+
+	changes := make(chan *config.Change, 10)
+	source2.Subscribe(func(change *config.Change) {
+		changes <- change
+	})
+
+	require.NoError(t, source1.Set(ctx, key, "true", "alice"))
+
+	value, err := source1.Get(ctx, key)
+	require.NoError(t, err)
+	assert.Equal(t, "true", string(value))
+
+	select {
+	case change := <-changes:
+		assert.Equal(t, key, change.Key)
+		assert.Nil(t, change.OldValue)
+		assert.Equal(t, "true", string(change.NewValue))
+		assert.Equal(t, "alice", change.Actor)
+	case <-time.After(time.Second):
+		require.Fail(t, "timed out waiting for change")
+	}
+
+	// No-op updates aren't audit logged
+	require.NoError(t, source1.Set(ctx, key, "true", "alice"))
+
+	require.NoError(t, source1.Set(ctx, key, "false", "bob"))
+	require.NoError(t, source1.Delete(ctx, key, "carol"))
+
+	_, err = source1.Get(ctx, key)
+	assert.Equal(t, config.ErrNoValue, err)
+
+	auditLog, err := source1.GetAuditLog(ctx, key, 10)
+	require.NoError(t, err)
+	require.Len(t, auditLog, 3)
+
+	assert.Equal(t, "carol", auditLog[0].ChangedBy)
+	assert.Equal(t, "false", *auditLog[0].OldValue)
+	assert.Nil(t, auditLog[0].NewValue)
+
+	assert.Equal(t, "bob", auditLog[1].ChangedBy)
+	assert.Equal(t, "true", *auditLog[1].OldValue)
+	assert.Equal(t, "false", *auditLog[1].NewValue)
+
+	assert.Equal(t, "alice", auditLog[2].ChangedBy)
+	assert.Nil(t, auditLog[2].OldValue)
+	assert.Equal(t, "true", *auditLog[2].NewValue)
+
+	auditLog, err = source1.GetAuditLog(ctx, key, 1)
+	require.NoError(t, err)
+	require.Len(t, auditLog, 1)
+	assert.Equal(t, "carol", auditLog[0].ChangedBy)
+}
+
+func createTestTables(db *sql.DB) error {
+	_, err := db.Exec(tableCreate)
+	if err != nil {
+		logrus.StandardLogger().WithError(err).Error("could not create test tables")
+		return err
+	}
+	return nil
+}
+
+func resetTestTables(db *sql.DB) error {
+	_, err := db.Exec(tableDestroy)
+	if err != nil {
+		logrus.StandardLogger().WithError(err).Error("could not drop test tables")
+		return err
+	}
+
+	return createTestTables(db)
+}
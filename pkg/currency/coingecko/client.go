@@ -21,6 +21,12 @@ const (
 	metricsStructName = "currency.coingecko.client"
 )
 
+const (
+	dependencyName        = "coingecko"
+	retryBudgetTokens     = 10
+	retryBudgetTokenRatio = 0.1
+)
+
 const (
 	baseUrl             = "https://api.coingecko.com/api"
 	latestUrlFormat     = baseUrl + "/v3/coins/%s?localization=false&tickers=false&community_data=false&developer_data=false&sparkline=false"
@@ -29,7 +35,7 @@ const (
 
 type client struct {
 	httpClient *http.Client
-	retrier    retry.Retrier
+	policy     *retry.Policy
 }
 
 func NewClient() currency.Client {
@@ -37,10 +43,16 @@ func NewClient() currency.Client {
 		httpClient: &http.Client{
 			Timeout: 15 * time.Second,
 		},
-		retrier: retry.NewRetrier(
-			retry.NonRetriableErrors(context.Canceled),
-			retry.Limit(3),
-			retry.BackoffWithJitter(backoff.BinaryExponential(time.Second), 10*time.Second, 0.1),
+		policy: retry.NewPolicy(
+			dependencyName,
+			retry.WithStrategies(
+				retry.NonRetriableErrors(context.Canceled),
+				retry.Limit(3),
+				retry.Backoff(backoff.DecorrelatedJitter(time.Second, 10*time.Second), 10*time.Second),
+			),
+			retry.WithCircuitBreaker(retry.NewCircuitBreaker(dependencyName)),
+			retry.WithBudget(retry.NewBudget(retryBudgetTokens, retryBudgetTokenRatio)),
+			retry.WithHooks(retry.MetricsHooks()),
 		),
 	}
 }
@@ -87,10 +99,21 @@ func (c *client) submitRequest(ctx context.Context, url string, body io.Reader,
 	}
 
 	var httpResp *http.Response
-	_, err = c.retrier.Retry(
+	_, err = c.policy.Do(
+		ctx,
 		func() error {
 			httpResp, err = c.httpClient.Do(req)
-			return err
+			if err != nil {
+				return err
+			}
+
+			// Server side errors are retried and count against the circuit breaker
+			if httpResp.StatusCode == http.StatusTooManyRequests || httpResp.StatusCode >= http.StatusInternalServerError {
+				httpResp.Body.Close()
+				return errors.Errorf("received %d status code", httpResp.StatusCode)
+			}
+
+			return nil
 		},
 	)
 	if err != nil {
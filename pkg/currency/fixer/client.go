@@ -20,6 +20,12 @@ const (
 	metricsStructName = "currency.fixer.client"
 )
 
+const (
+	dependencyName        = "fixer"
+	retryBudgetTokens     = 10
+	retryBudgetTokenRatio = 0.1
+)
+
 const (
 	baseUrl             = "https://api.apilayer.com/fixer"
 	latestUrlFormat     = baseUrl + "/latest?base=%s"
@@ -36,7 +42,7 @@ const (
 type client struct {
 	apiKey     string
 	httpClient *http.Client
-	retrier    retry.Retrier
+	policy     *retry.Policy
 }
 
 func NewClient(apiKey string) currency.Client {
@@ -45,10 +51,16 @@ func NewClient(apiKey string) currency.Client {
 		httpClient: &http.Client{
 			Timeout: 15 * time.Second,
 		},
-		retrier: retry.NewRetrier(
-			retry.NonRetriableErrors(context.Canceled),
-			retry.Limit(3),
-			retry.BackoffWithJitter(backoff.BinaryExponential(time.Second), 10*time.Second, 0.1),
+		policy: retry.NewPolicy(
+			dependencyName,
+			retry.WithStrategies(
+				retry.NonRetriableErrors(context.Canceled),
+				retry.Limit(3),
+				retry.Backoff(backoff.DecorrelatedJitter(time.Second, 10*time.Second), 10*time.Second),
+			),
+			retry.WithCircuitBreaker(retry.NewCircuitBreaker(dependencyName)),
+			retry.WithBudget(retry.NewBudget(retryBudgetTokens, retryBudgetTokenRatio)),
+			retry.WithHooks(retry.MetricsHooks()),
 		),
 	}
 }
@@ -111,10 +123,21 @@ func (c *client) submitRequest(ctx context.Context, url string, resp interface{}
 	req.Header.Set(apiKeyHeaderName, c.apiKey)
 
 	var httpResp *http.Response
-	_, err = c.retrier.Retry(
+	_, err = c.policy.Do(
+		ctx,
 		func() error {
 			httpResp, err = c.httpClient.Do(req)
-			return err
+			if err != nil {
+				return err
+			}
+
+			// Server side errors are retried and count against the circuit breaker
+			if httpResp.StatusCode == http.StatusTooManyRequests || httpResp.StatusCode >= http.StatusInternalServerError {
+				httpResp.Body.Close()
+				return errors.Errorf("received %d status code", httpResp.StatusCode)
+			}
+
+			return nil
 		},
 	)
 	if err != nil {
@@ -0,0 +1,154 @@
+package android
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	jose "github.com/dvsekhvalnov/jose2go"
+	"github.com/pkg/errors"
+)
+
+const (
+	// Google Play always uses these algorithms for integrity tokens
+	keyWrappingAlgorithm = jose.A256KW
+	encryptionAlgorithm  = jose.A256GCM
+	signingAlgorithm     = jose.ES256
+
+	// Integrity tokens are requested immediately before calling the server, so
+	// they should be relatively fresh.
+	maxVerdictAge  = 5 * time.Minute
+	maxVerdictSkew = time.Minute
+)
+
+const (
+	appRecognitionVerdictPlayRecognized     = "PLAY_RECOGNIZED"
+	deviceRecognitionVerdictDeviceIntegrity = "MEETS_DEVICE_INTEGRITY"
+)
+
+// integrityVerdict is the decoded payload of a Play Integrity token. Only the
+// fields used for verification are included.
+//
+// https://developer.android.com/google/play/integrity/verdicts
+type integrityVerdict struct {
+	RequestDetails struct {
+		RequestPackageName string `json:"requestPackageName"`
+		Nonce              string `json:"nonce"`
+		TimestampMillis    string `json:"timestampMillis"`
+	} `json:"requestDetails"`
+
+	AppIntegrity struct {
+		AppRecognitionVerdict   string   `json:"appRecognitionVerdict"`
+		PackageName             string   `json:"packageName"`
+		CertificateSha256Digest []string `json:"certificateSha256Digest"`
+	} `json:"appIntegrity"`
+
+	DeviceIntegrity struct {
+		DeviceRecognitionVerdict []string `json:"deviceRecognitionVerdict"`
+	} `json:"deviceIntegrity"`
+}
+
+// decodeIntegrityToken decrypts the JWE token and verifies the JWS token it
+// contains to get the verdict payload.
+func decodeIntegrityToken(token string, decryptionKey []byte, verificationKey *ecdsa.PublicKey) (*integrityVerdict, error) {
+	if strings.Count(token, ".") != 4 {
+		return nil, errors.New("token is not a compact jwe")
+	}
+
+	jws, headers, err := jose.Decode(token, decryptionKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "error decrypting token")
+	}
+	if headers["alg"] != keyWrappingAlgorithm || headers["enc"] != encryptionAlgorithm {
+		return nil, errors.New("unexpected token encryption algorithm")
+	}
+
+	if strings.Count(jws, ".") != 2 {
+		return nil, errors.New("decrypted token is not a compact jws")
+	}
+
+	payload, headers, err := jose.DecodeBytes(jws, verificationKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "error verifying token signature")
+	}
+	if headers["alg"] != signingAlgorithm {
+		return nil, errors.New("unexpected token signing algorithm")
+	}
+
+	var verdict integrityVerdict
+	if err := json.Unmarshal(payload, &verdict); err != nil {
+		return nil, errors.Wrap(err, "invalid verdict payload")
+	}
+	return &verdict, nil
+}
+
+// isValid checks the verdict is for a recent request from a genuine instance
+// of the app, installed from Google Play, running on a genuine Android device.
+func (v *integrityVerdict) isValid(packageName string, certificateDigests map[string]struct{}, at time.Time) bool {
+	if v.RequestDetails.RequestPackageName != packageName {
+		return false
+	}
+
+	timestampMillis, err := strconv.ParseInt(v.RequestDetails.TimestampMillis, 10, 64)
+	if err != nil {
+		return false
+	}
+	timestamp := time.UnixMilli(timestampMillis)
+	if at.Sub(timestamp) > maxVerdictAge || timestamp.Sub(at) > maxVerdictSkew {
+		return false
+	}
+
+	if v.AppIntegrity.AppRecognitionVerdict != appRecognitionVerdictPlayRecognized {
+		return false
+	}
+
+	if v.AppIntegrity.PackageName != packageName {
+		return false
+	}
+
+	if len(v.AppIntegrity.CertificateSha256Digest) == 0 {
+		return false
+	}
+	for _, certificateDigest := range v.AppIntegrity.CertificateSha256Digest {
+		decoded, err := decodeCertificateDigest(certificateDigest)
+		if err != nil {
+			return false
+		}
+
+		if _, ok := certificateDigests[string(decoded)]; !ok {
+			return false
+		}
+	}
+
+	for _, deviceRecognitionVerdict := range v.DeviceIntegrity.DeviceRecognitionVerdict {
+		if deviceRecognitionVerdict == deviceRecognitionVerdictDeviceIntegrity {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeCertificateDigest decodes a SHA-256 certificate digest, which is base64url
+// encoded in verdicts, but is typically displayed as colon-separated hex.
+func decodeCertificateDigest(value string) ([]byte, error) {
+	var decoded []byte
+	var err error
+	if strings.Contains(value, ":") || len(value) == 2*sha256.Size {
+		decoded, err = hex.DecodeString(strings.ReplaceAll(value, ":", ""))
+	} else {
+		decoded, err = base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(decoded) != sha256.Size {
+		return nil, errors.New("digest must be a sha256 hash")
+	}
+	return decoded, nil
+}
@@ -0,0 +1,120 @@
+package android
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// IntegrityNonceTtl is how long clients have to request an integrity token
+	// and submit it after being issued a nonce
+	IntegrityNonceTtl = 10 * time.Minute
+
+	// MinIntegrityNonceKeySize is the minimum size of the secret nonces are
+	// signed with
+	MinIntegrityNonceKeySize = 32
+
+	// MinDeviceIdSize and MaxDeviceIdSize bound the size of device ids, which
+	// are expected to be the app's ANDROID_ID
+	MinDeviceIdSize = 16
+	MaxDeviceIdSize = 64
+
+	nonceVersion    = 1
+	nonceRandomSize = 16
+	nonceHeaderSize = 1 + 8 + nonceRandomSize
+)
+
+var (
+	ErrInvalidDeviceId = errors.New("invalid device id")
+	errInvalidNonce    = errors.New("invalid integrity nonce")
+	errExpiredNonce    = errors.New("integrity nonce is expired")
+)
+
+// NonceIssuer issues nonces that clients pass to Play Integrity when requesting
+// an integrity token. The nonce is returned in the verdict, which binds it to a
+// single request from a genuine instance of the app.
+//
+// Nonces are stateless. Each one is signed with a server-side secret and embeds
+// the device id the client reported when requesting it, so the device id in a
+// valid verdict's nonce was reported by a genuine app. The device id is the
+// app's ANDROID_ID, which is stable across reinstalls for the same signing key
+// and user, and is used to track free accounts server-side.
+type NonceIssuer struct {
+	key []byte
+}
+
+// NewNonceIssuer returns a new NonceIssuer that signs nonces with the provided
+// secret
+func NewNonceIssuer(key []byte) (*NonceIssuer, error) {
+	if len(key) < MinIntegrityNonceKeySize {
+		return nil, errors.New("integrity nonce key is too short")
+	}
+
+	return &NonceIssuer{
+		key: append([]byte(nil), key...),
+	}, nil
+}
+
+// Issue issues a new nonce for a device, which expires after IntegrityNonceTtl
+func (i *NonceIssuer) Issue(deviceId string) (string, time.Time, error) {
+	if len(deviceId) < MinDeviceIdSize || len(deviceId) > MaxDeviceIdSize {
+		return "", time.Time{}, ErrInvalidDeviceId
+	}
+
+	expiresAt := time.Now().Add(IntegrityNonceTtl).Truncate(time.Second)
+
+	payload := make([]byte, nonceHeaderSize, nonceHeaderSize+len(deviceId)+sha256.Size)
+	payload[0] = nonceVersion
+	binary.BigEndian.PutUint64(payload[1:9], uint64(expiresAt.Unix()))
+	if _, err := rand.Read(payload[9:nonceHeaderSize]); err != nil {
+		return "", time.Time{}, err
+	}
+	payload = append(payload, deviceId...)
+	payload = append(payload, i.sign(payload)...)
+
+	return base64.RawURLEncoding.EncodeToString(payload), expiresAt, nil
+}
+
+// getDeviceId verifies a nonce was issued by the server, and gets the device id
+// it was issued for. Expiry is only checked when checkExpiry is set.
+func (i *NonceIssuer) getDeviceId(nonce string, at time.Time, checkExpiry bool) (string, error) {
+	// Play Integrity may return the nonce with padding
+	decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(nonce, "="))
+	if err != nil {
+		return "", errInvalidNonce
+	}
+
+	if len(decoded) < nonceHeaderSize+MinDeviceIdSize+sha256.Size || len(decoded) > nonceHeaderSize+MaxDeviceIdSize+sha256.Size {
+		return "", errInvalidNonce
+	}
+
+	payload := decoded[:len(decoded)-sha256.Size]
+	signature := decoded[len(decoded)-sha256.Size:]
+	if !hmac.Equal(signature, i.sign(payload)) {
+		return "", errInvalidNonce
+	}
+
+	if payload[0] != nonceVersion {
+		return "", errInvalidNonce
+	}
+
+	expiresAt := time.Unix(int64(binary.BigEndian.Uint64(payload[1:9])), 0)
+	if checkExpiry && !at.Before(expiresAt) {
+		return "", errExpiredNonce
+	}
+
+	return string(payload[nonceHeaderSize:]), nil
+}
+
+func (i *NonceIssuer) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, i.key)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
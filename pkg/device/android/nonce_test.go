@@ -0,0 +1,98 @@
+package android
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNonceIssuer_HappyPath(t *testing.T) {
+	issuer, err := NewNonceIssuer(bytes.Repeat([]byte{1}, MinIntegrityNonceKeySize))
+	require.NoError(t, err)
+
+	start := time.Now()
+
+	nonce1, expiresAt, err := issuer.Issue(testDeviceId)
+	require.NoError(t, err)
+	assert.True(t, expiresAt.After(start.Add(IntegrityNonceTtl-time.Second)))
+	assert.False(t, strings.ContainsAny(nonce1, "+/="))
+
+	nonce2, _, err := issuer.Issue(testDeviceId)
+	require.NoError(t, err)
+	assert.NotEqual(t, nonce1, nonce2)
+
+	for _, nonce := range []string{nonce1, nonce2, nonce1 + "=="} {
+		deviceId, err := issuer.getDeviceId(nonce, time.Now(), true)
+		require.NoError(t, err)
+		assert.Equal(t, testDeviceId, deviceId)
+	}
+
+	_, err = issuer.getDeviceId(nonce1, expiresAt, true)
+	assert.Equal(t, errExpiredNonce, err)
+
+	deviceId, err := issuer.getDeviceId(nonce1, expiresAt, false)
+	require.NoError(t, err)
+	assert.Equal(t, testDeviceId, deviceId)
+}
+
+func TestNonceIssuer_InvalidNonce(t *testing.T) {
+	issuer, err := NewNonceIssuer(bytes.Repeat([]byte{1}, MinIntegrityNonceKeySize))
+	require.NoError(t, err)
+
+	otherIssuer, err := NewNonceIssuer(bytes.Repeat([]byte{2}, MinIntegrityNonceKeySize))
+	require.NoError(t, err)
+
+	nonce, _, err := issuer.Issue(testDeviceId)
+	require.NoError(t, err)
+
+	otherNonce, _, err := otherIssuer.Issue(testDeviceId)
+	require.NoError(t, err)
+
+	decoded, err := base64.RawURLEncoding.DecodeString(nonce)
+	require.NoError(t, err)
+	decoded[len(decoded)-sha256.Size-1] ^= 1
+	tampered := base64.RawURLEncoding.EncodeToString(decoded)
+
+	for _, invalid := range []string{
+		"",
+		"not base64!",
+		base64.RawURLEncoding.EncodeToString([]byte(testDeviceId)),
+		otherNonce,
+		tampered,
+		nonce[:len(nonce)-4],
+	} {
+		_, err := issuer.getDeviceId(invalid, time.Now(), true)
+		assert.Equal(t, errInvalidNonce, err)
+	}
+}
+
+func TestNonceIssuer_Config(t *testing.T) {
+	_, err := NewNonceIssuer(bytes.Repeat([]byte{1}, MinIntegrityNonceKeySize-1))
+	assert.Error(t, err)
+
+	issuer, err := NewNonceIssuer(bytes.Repeat([]byte{1}, MinIntegrityNonceKeySize))
+	require.NoError(t, err)
+
+	for _, deviceId := range []string{
+		"",
+		strings.Repeat("a", MinDeviceIdSize-1),
+		strings.Repeat("a", MaxDeviceIdSize+1),
+	} {
+		_, _, err := issuer.Issue(deviceId)
+		assert.Equal(t, ErrInvalidDeviceId, err)
+	}
+
+	for _, deviceId := range []string{
+		strings.Repeat("a", MinDeviceIdSize),
+		strings.Repeat("a", MaxDeviceIdSize),
+	} {
+		_, _, err := issuer.Issue(deviceId)
+		assert.NoError(t, err)
+	}
+}
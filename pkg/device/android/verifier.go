@@ -2,32 +2,166 @@ package android
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/base64"
+	"time"
+
+	"github.com/pkg/errors"
 
 	"github.com/code-payments/code-server/pkg/device"
+	"github.com/code-payments/code-server/pkg/grpc/client"
 	"github.com/code-payments/code-server/pkg/metrics"
+	"github.com/code-payments/code-server/pkg/code/data/integritynonce"
 )
 
 const (
 	metricsStructName = "device.android.verifier"
 )
 
+// Store tracks server-side state for Android devices. Unlike iOS, Android has no
+// equivalent to DeviceCheck bits, so free accounts must be tracked by the server.
+type Store interface {
+	// MarkIntegrityNonceUsed marks an integrity nonce as used by a device.
+	// integritynonce.ErrNonceAlreadyUsed is returned if it was already used.
+	MarkIntegrityNonceUsed(ctx context.Context, nonce, deviceId string) error
+
+	// MarkDeviceCreatedFreeAccount marks a device as having created a free account
+	MarkDeviceCreatedFreeAccount(ctx context.Context, deviceId string) error
+
+	// HasDeviceCreatedFreeAccount checks whether a device has created a free account
+	HasDeviceCreatedFreeAccount(ctx context.Context, deviceId string) (bool, error)
+}
+
+// Device tokens are Play Integrity verdict tokens, which are JWS tokens that
+// are encrypted into a JWE token using keys managed by Google Play.
+//
+// Clients request tokens using a nonce issued by a NonceIssuer, which can only
+// be used once. The nonce identifies the device, which is used to track free
+// accounts server-side.
 type androidDeviceVerifier struct {
+	packageName        string
+	certificateDigests map[string]struct{}
+	decryptionKey      []byte
+	verificationKey    *ecdsa.PublicKey
+	nonceIssuer        *NonceIssuer
+	store              Store
+	minVersion         *client.Version
 }
 
 // NewAndroidDeviceVerifier returns a new device.Verifier for Android devices
-func NewAndroidDeviceVerifier() (device.Verifier, error) {
-	return &androidDeviceVerifier{}, nil
+//
+// The decryption and verification keys are the base64 encoded values provided
+// by the Google Play Console. Certificate digests are the SHA-256 digests of
+// the app's signing certificates, either hex or base64url encoded.
+func NewAndroidDeviceVerifier(
+	packageName string,
+	certificateDigests []string,
+	decryptionKey string,
+	verificationKey string,
+	nonceIssuer *NonceIssuer,
+	store Store,
+	minVersion *client.Version,
+) (device.Verifier, error) {
+	if len(packageName) == 0 {
+		return nil, errors.New("package name is required")
+	}
+
+	if len(certificateDigests) == 0 {
+		return nil, errors.New("at least one certificate digest is required")
+	}
+
+	if nonceIssuer == nil {
+		return nil, errors.New("nonce issuer is required")
+	}
+
+	if store == nil {
+		return nil, errors.New("store is required")
+	}
+
+	decodedCertificateDigests := make(map[string]struct{})
+	for _, certificateDigest := range certificateDigests {
+		decoded, err := decodeCertificateDigest(certificateDigest)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid certificate digest %s", certificateDigest)
+		}
+		decodedCertificateDigests[string(decoded)] = struct{}{}
+	}
+
+	decodedDecryptionKey, err := base64.StdEncoding.DecodeString(decryptionKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid decryption key encoding")
+	}
+	if len(decodedDecryptionKey) != 32 {
+		return nil, errors.New("decryption key must be a 256 bit aes key")
+	}
+
+	decodedVerificationKey, err := base64.StdEncoding.DecodeString(verificationKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid verification key encoding")
+	}
+	parsedVerificationKey, err := x509.ParsePKIXPublicKey(decodedVerificationKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid verification key")
+	}
+	ecdsaVerificationKey, ok := parsedVerificationKey.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, errors.New("verification key must be an ecdsa public key")
+	}
+
+	return &androidDeviceVerifier{
+		packageName:        packageName,
+		certificateDigests: decodedCertificateDigests,
+		decryptionKey:      decodedDecryptionKey,
+		verificationKey:    ecdsaVerificationKey,
+		nonceIssuer:        nonceIssuer,
+		store:              store,
+		minVersion:         minVersion,
+	}, nil
 }
 
 // IsValid implements device.Verifier.IsValid
-//
-// todo: implement me
 func (v *androidDeviceVerifier) IsValid(ctx context.Context, token string) (bool, error) {
 	tracer := metrics.TraceMethodCall(ctx, metricsStructName, "IsValid")
 	defer tracer.End()
 
 	isValid, err := func() (bool, error) {
-		return false, nil
+		userAgent, err := client.GetUserAgent(ctx)
+		if err != nil {
+			return false, nil
+		}
+
+		if userAgent.DeviceType != client.DeviceTypeAndroid {
+			return false, nil
+		}
+
+		if userAgent.Version.Before(v.minVersion) {
+			return false, nil
+		}
+
+		verdict, err := decodeIntegrityToken(token, v.decryptionKey, v.verificationKey)
+		if err != nil {
+			return false, nil
+		}
+
+		now := time.Now()
+		if !verdict.isValid(v.packageName, v.certificateDigests, now) {
+			return false, nil
+		}
+
+		deviceId, err := v.nonceIssuer.getDeviceId(verdict.RequestDetails.Nonce, now, true)
+		if err != nil {
+			return false, nil
+		}
+
+		// Nonces are single use, so a captured token can't be replayed
+		err = v.store.MarkIntegrityNonceUsed(ctx, verdict.RequestDetails.Nonce, deviceId)
+		if err == integritynonce.ErrNonceAlreadyUsed {
+			return false, nil
+		} else if err != nil {
+			return false, err
+		}
+		return true, nil
 	}()
 
 	if err != nil {
@@ -37,14 +171,17 @@ func (v *androidDeviceVerifier) IsValid(ctx context.Context, token string) (bool
 }
 
 // HasCreatedFreeAccount implements device.Verifier.HasCreatedFreeAccount
-//
-// todo: implement me
 func (v *androidDeviceVerifier) HasCreatedFreeAccount(ctx context.Context, token string) (bool, error) {
 	tracer := metrics.TraceMethodCall(ctx, metricsStructName, "HasCreatedFreeAccount")
 	defer tracer.End()
 
 	hasFreeCreatedAccount, err := func() (bool, error) {
-		return false, nil
+		deviceId, err := v.getDeviceId(token)
+		if err != nil {
+			return false, err
+		}
+
+		return v.store.HasDeviceCreatedFreeAccount(ctx, deviceId)
 	}()
 
 	if err != nil {
@@ -54,14 +191,17 @@ func (v *androidDeviceVerifier) HasCreatedFreeAccount(ctx context.Context, token
 }
 
 // MarkCreatedFreeAccount implements device.Verifier.MarkCreatedFreeAccount
-//
-// todo: implement me
 func (v *androidDeviceVerifier) MarkCreatedFreeAccount(ctx context.Context, token string) error {
 	tracer := metrics.TraceMethodCall(ctx, metricsStructName, "MarkCreatedFreeAccount")
 	defer tracer.End()
 
 	err := func() error {
-		return nil
+		deviceId, err := v.getDeviceId(token)
+		if err != nil {
+			return err
+		}
+
+		return v.store.MarkDeviceCreatedFreeAccount(ctx, deviceId)
 	}()
 
 	if err != nil {
@@ -69,3 +209,18 @@ func (v *androidDeviceVerifier) MarkCreatedFreeAccount(ctx context.Context, toke
 	}
 	return err
 }
+
+// getDeviceId gets the device id from a token that's already been validated
+// via IsValid. The nonce may have expired since, so expiry isn't checked.
+func (v *androidDeviceVerifier) getDeviceId(token string) (string, error) {
+	verdict, err := decodeIntegrityToken(token, v.decryptionKey, v.verificationKey)
+	if err != nil {
+		return "", errors.Wrap(err, "invalid device token")
+	}
+
+	deviceId, err := v.nonceIssuer.getDeviceId(verdict.RequestDetails.Nonce, time.Now(), false)
+	if err != nil {
+		return "", errors.Wrap(err, "invalid device token")
+	}
+	return deviceId, nil
+}
@@ -0,0 +1,505 @@
+package android
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	jose "github.com/dvsekhvalnov/jose2go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/code-payments/code-server/pkg/device"
+	"github.com/code-payments/code-server/pkg/grpc/client"
+	"github.com/code-payments/code-server/pkg/grpc/headers"
+	"github.com/code-payments/code-server/pkg/code/data/integritynonce"
+)
+
+const (
+	testPackageName = "com.getcode.test"
+	testDeviceId    = "8f3a2c1d9e7b6a54"
+)
+
+func TestAndroidDeviceVerifier_IsValid(t *testing.T) {
+	env := setup(t)
+
+	for _, tc := range []struct {
+		name     string
+		mutate   func(verdict map[string]any)
+		expected bool
+	}{
+		{
+			name:     "valid verdict",
+			mutate:   func(_ map[string]any) {},
+			expected: true,
+		},
+		{
+			name: "strong integrity also meets device integrity",
+			mutate: func(verdict map[string]any) {
+				getObject(verdict, "deviceIntegrity")["deviceRecognitionVerdict"] = []string{"MEETS_BASIC_INTEGRITY", "MEETS_DEVICE_INTEGRITY", "MEETS_STRONG_INTEGRITY"}
+			},
+			expected: true,
+		},
+		{
+			name: "request package name mismatch",
+			mutate: func(verdict map[string]any) {
+				getObject(verdict, "requestDetails")["requestPackageName"] = "com.example.other"
+			},
+		},
+		{
+			name: "app package name mismatch",
+			mutate: func(verdict map[string]any) {
+				getObject(verdict, "appIntegrity")["packageName"] = "com.example.other"
+			},
+		},
+		{
+			name: "unrecognized app",
+			mutate: func(verdict map[string]any) {
+				getObject(verdict, "appIntegrity")["appRecognitionVerdict"] = "UNRECOGNIZED_VERSION"
+			},
+		},
+		{
+			name: "unevaluated app",
+			mutate: func(verdict map[string]any) {
+				getObject(verdict, "appIntegrity")["appRecognitionVerdict"] = "UNEVALUATED"
+			},
+		},
+		{
+			name: "unknown certificate digest",
+			mutate: func(verdict map[string]any) {
+				getObject(verdict, "appIntegrity")["certificateSha256Digest"] = []string{encodeCertificateDigest("other-certificate")}
+			},
+		},
+		{
+			name: "missing certificate digest",
+			mutate: func(verdict map[string]any) {
+				delete(getObject(verdict, "appIntegrity"), "certificateSha256Digest")
+			},
+		},
+		{
+			name: "basic integrity only",
+			mutate: func(verdict map[string]any) {
+				getObject(verdict, "deviceIntegrity")["deviceRecognitionVerdict"] = []string{"MEETS_BASIC_INTEGRITY"}
+			},
+		},
+		{
+			name: "no device integrity",
+			mutate: func(verdict map[string]any) {
+				getObject(verdict, "deviceIntegrity")["deviceRecognitionVerdict"] = []string{}
+			},
+		},
+		{
+			name: "stale timestamp",
+			mutate: func(verdict map[string]any) {
+				getObject(verdict, "requestDetails")["timestampMillis"] = fmt.Sprintf("%d", time.Now().Add(-maxVerdictAge-time.Minute).UnixMilli())
+			},
+		},
+		{
+			name: "future timestamp",
+			mutate: func(verdict map[string]any) {
+				getObject(verdict, "requestDetails")["timestampMillis"] = fmt.Sprintf("%d", time.Now().Add(maxVerdictSkew+time.Minute).UnixMilli())
+			},
+		},
+		{
+			name: "missing nonce",
+			mutate: func(verdict map[string]any) {
+				delete(getObject(verdict, "requestDetails"), "nonce")
+			},
+		},
+		{
+			name: "client chosen nonce",
+			mutate: func(verdict map[string]any) {
+				getObject(verdict, "requestDetails")["nonce"] = base64.RawURLEncoding.EncodeToString([]byte("client-chosen-nonce-for-" + testDeviceId))
+			},
+		},
+		{
+			name: "nonce issued by another server",
+			mutate: func(verdict map[string]any) {
+				otherIssuer, err := NewNonceIssuer(make([]byte, MinIntegrityNonceKeySize))
+				require.NoError(t, err)
+
+				nonce, _, err := otherIssuer.Issue(testDeviceId)
+				require.NoError(t, err)
+
+				getObject(verdict, "requestDetails")["nonce"] = nonce
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			verdict := env.newVerdict()
+			tc.mutate(verdict)
+
+			isValid, err := env.verifier.IsValid(env.ctx, env.newToken(t, verdict))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, isValid)
+		})
+	}
+}
+
+func TestAndroidDeviceVerifier_IsValid_NonceReplay(t *testing.T) {
+	env := setup(t)
+
+	verdict := env.newVerdict()
+	token := env.newToken(t, verdict)
+
+	isValid, err := env.verifier.IsValid(env.ctx, token)
+	require.NoError(t, err)
+	assert.True(t, isValid)
+
+	// Neither the token nor a new token with the same nonce can be reused
+	for _, replayed := range []string{token, env.newToken(t, verdict)} {
+		isValid, err = env.verifier.IsValid(env.ctx, replayed)
+		require.NoError(t, err)
+		assert.False(t, isValid)
+	}
+
+	assert.Len(t, env.store.nonces, 1)
+	assert.Equal(t, testDeviceId, env.store.nonces[getObject(verdict, "requestDetails")["nonce"].(string)])
+}
+
+func TestAndroidDeviceVerifier_IsValid_ExpiredNonce(t *testing.T) {
+	env := setup(t)
+
+	verdict := env.newVerdict()
+	getObject(verdict, "requestDetails")["nonce"] = env.newNonce(t, testDeviceId, time.Now().Add(-time.Second))
+
+	isValid, err := env.verifier.IsValid(env.ctx, env.newToken(t, verdict))
+	require.NoError(t, err)
+	assert.False(t, isValid)
+	assert.Empty(t, env.store.nonces)
+}
+
+func TestAndroidDeviceVerifier_IsValid_InvalidToken(t *testing.T) {
+	env := setup(t)
+
+	otherSigningKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	otherEncryptionKey := make([]byte, 32)
+	_, err = rand.Read(otherEncryptionKey)
+	require.NoError(t, err)
+
+	payload, err := json.Marshal(env.newVerdict())
+	require.NoError(t, err)
+
+	validJws, err := jose.SignBytes(payload, jose.ES256, env.signingKey)
+	require.NoError(t, err)
+
+	wrongSignerJws, err := jose.SignBytes(payload, jose.ES256, otherSigningKey)
+	require.NoError(t, err)
+	wrongSignerToken, err := jose.Encrypt(wrongSignerJws, jose.A256KW, jose.A256GCM, env.encryptionKey)
+	require.NoError(t, err)
+
+	wrongEncryptionKeyToken, err := jose.Encrypt(validJws, jose.A256KW, jose.A256GCM, otherEncryptionKey)
+	require.NoError(t, err)
+
+	wrongEncryptionAlgorithmToken, err := jose.Encrypt(validJws, jose.A256KW, jose.A256CBC_HS512, env.encryptionKey)
+	require.NoError(t, err)
+
+	unsignedJws, err := jose.SignBytes(payload, jose.NONE, nil)
+	require.NoError(t, err)
+	unsignedToken, err := jose.Encrypt(unsignedJws, jose.A256KW, jose.A256GCM, env.encryptionKey)
+	require.NoError(t, err)
+
+	validToken, err := jose.Encrypt(validJws, jose.A256KW, jose.A256GCM, env.encryptionKey)
+	require.NoError(t, err)
+	parts := strings.Split(validToken, ".")
+	parts[3] = parts[3][:len(parts[3])-2] + "AA"
+	tamperedToken := strings.Join(parts, ".")
+
+	for _, token := range []string{
+		"",
+		"not-a-token",
+		validJws,
+		wrongSignerToken,
+		wrongEncryptionKeyToken,
+		wrongEncryptionAlgorithmToken,
+		unsignedToken,
+		tamperedToken,
+	} {
+		isValid, err := env.verifier.IsValid(env.ctx, token)
+		require.NoError(t, err)
+		assert.False(t, isValid)
+
+		_, err = env.verifier.HasCreatedFreeAccount(env.ctx, token)
+		assert.Error(t, err)
+
+		assert.Error(t, env.verifier.MarkCreatedFreeAccount(env.ctx, token))
+	}
+
+	assert.Empty(t, env.store.nonces)
+	assert.Empty(t, env.store.devices)
+}
+
+func TestAndroidDeviceVerifier_IsValid_UserAgent(t *testing.T) {
+	env := setup(t)
+
+	for _, tc := range []struct {
+		userAgent string
+		expected  bool
+	}{
+		{"Code/Android/1.2.3", true},
+		{"Code/Android/2.0.0", true},
+		{"Code/Android/1.2.2", false},
+		{"Code/iOS/1.2.3", false},
+		{"", false},
+	} {
+		ctx, err := headers.ContextWithHeaders(context.Background())
+		require.NoError(t, err)
+		if len(tc.userAgent) > 0 {
+			require.NoError(t, headers.SetASCIIHeader(ctx, client.UserAgentHeaderName, tc.userAgent))
+		}
+
+		isValid, err := env.verifier.IsValid(ctx, env.newToken(t, env.newVerdict()))
+		require.NoError(t, err)
+		assert.Equal(t, tc.expected, isValid)
+	}
+}
+
+func TestAndroidDeviceVerifier_FreeAccount(t *testing.T) {
+	env := setup(t)
+
+	token1 := env.newToken(t, env.newVerdict())
+	token2 := env.newToken(t, env.newVerdict())
+
+	otherDeviceVerdict := env.newVerdict()
+	getObject(otherDeviceVerdict, "requestDetails")["nonce"] = env.newNonce(t, "0123456789abcdef", time.Now().Add(IntegrityNonceTtl))
+	otherDeviceToken := env.newToken(t, otherDeviceVerdict)
+
+	for _, token := range []string{token1, token2, otherDeviceToken} {
+		isValid, err := env.verifier.IsValid(env.ctx, token)
+		require.NoError(t, err)
+		require.True(t, isValid)
+
+		hasCreatedFreeAccount, err := env.verifier.HasCreatedFreeAccount(env.ctx, token)
+		require.NoError(t, err)
+		assert.False(t, hasCreatedFreeAccount)
+	}
+
+	require.NoError(t, env.verifier.MarkCreatedFreeAccount(env.ctx, token1))
+	require.NoError(t, env.verifier.MarkCreatedFreeAccount(env.ctx, token1))
+
+	// A new token for the same device shares the same state
+	for _, token := range []string{token1, token2} {
+		hasCreatedFreeAccount, err := env.verifier.HasCreatedFreeAccount(env.ctx, token)
+		require.NoError(t, err)
+		assert.True(t, hasCreatedFreeAccount)
+	}
+
+	hasCreatedFreeAccount, err := env.verifier.HasCreatedFreeAccount(env.ctx, otherDeviceToken)
+	require.NoError(t, err)
+	assert.False(t, hasCreatedFreeAccount)
+
+	assert.True(t, env.store.devices[testDeviceId])
+	assert.Len(t, env.store.devices, 1)
+}
+
+func TestNewAndroidDeviceVerifier_Config(t *testing.T) {
+	env := setup(t)
+
+	_, err := NewAndroidDeviceVerifier(testPackageName, env.certificateDigests, env.encodedEncryptionKey, env.encodedVerificationKey, nil, env.store, nil)
+	assert.Error(t, err)
+
+	_, err = NewAndroidDeviceVerifier(testPackageName, env.certificateDigests, env.encodedEncryptionKey, env.encodedVerificationKey, env.nonceIssuer, nil, nil)
+	assert.Error(t, err)
+
+	_, err = NewAndroidDeviceVerifier("", env.certificateDigests, env.encodedEncryptionKey, env.encodedVerificationKey, env.nonceIssuer, env.store, nil)
+	assert.Error(t, err)
+
+	_, err = NewAndroidDeviceVerifier(testPackageName, nil, env.encodedEncryptionKey, env.encodedVerificationKey, env.nonceIssuer, env.store, nil)
+	assert.Error(t, err)
+
+	_, err = NewAndroidDeviceVerifier(testPackageName, []string{"abcd"}, env.encodedEncryptionKey, env.encodedVerificationKey, env.nonceIssuer, env.store, nil)
+	assert.Error(t, err)
+
+	_, err = NewAndroidDeviceVerifier(testPackageName, env.certificateDigests, base64.StdEncoding.EncodeToString(make([]byte, 16)), env.encodedVerificationKey, env.nonceIssuer, env.store, nil)
+	assert.Error(t, err)
+
+	_, err = NewAndroidDeviceVerifier(testPackageName, env.certificateDigests, env.encodedEncryptionKey, "invalid", env.nonceIssuer, env.store, nil)
+	assert.Error(t, err)
+
+	// Hex encoded digests, as displayed in the Google Play Console, are also supported
+	digest := sha256.Sum256([]byte("certificate"))
+	colonSeparated := strings.ToUpper(hex.EncodeToString(digest[:]))
+	var withColons []string
+	for i := 0; i < len(colonSeparated); i += 2 {
+		withColons = append(withColons, colonSeparated[i:i+2])
+	}
+	_, err = NewAndroidDeviceVerifier(testPackageName, []string{strings.Join(withColons, ":")}, env.encodedEncryptionKey, env.encodedVerificationKey, env.nonceIssuer, env.store, nil)
+	assert.NoError(t, err)
+}
+
+type testEnv struct {
+	ctx         context.Context
+	verifier    device.Verifier
+	nonceIssuer *NonceIssuer
+	store       *testStore
+
+	signingKey    *ecdsa.PrivateKey
+	encryptionKey []byte
+
+	encodedEncryptionKey   string
+	encodedVerificationKey string
+	certificateDigests     []string
+}
+
+func setup(t *testing.T) *testEnv {
+	signingKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	encodedVerificationKey, err := x509.MarshalPKIXPublicKey(&signingKey.PublicKey)
+	require.NoError(t, err)
+
+	encryptionKey := make([]byte, 32)
+	_, err = rand.Read(encryptionKey)
+	require.NoError(t, err)
+
+	nonceKey := make([]byte, MinIntegrityNonceKeySize)
+	_, err = rand.Read(nonceKey)
+	require.NoError(t, err)
+
+	nonceIssuer, err := NewNonceIssuer(nonceKey)
+	require.NoError(t, err)
+
+	env := &testEnv{
+		nonceIssuer: nonceIssuer,
+		store:       newTestStore(),
+
+		signingKey:    signingKey,
+		encryptionKey: encryptionKey,
+
+		encodedEncryptionKey:   base64.StdEncoding.EncodeToString(encryptionKey),
+		encodedVerificationKey: base64.StdEncoding.EncodeToString(encodedVerificationKey),
+		certificateDigests:     []string{encodeCertificateDigest("certificate")},
+	}
+
+	env.verifier, err = NewAndroidDeviceVerifier(
+		testPackageName,
+		env.certificateDigests,
+		env.encodedEncryptionKey,
+		env.encodedVerificationKey,
+		env.nonceIssuer,
+		env.store,
+		&client.Version{Major: 1, Minor: 2, Patch: 3},
+	)
+	require.NoError(t, err)
+
+	env.ctx, err = headers.ContextWithHeaders(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, headers.SetASCIIHeader(env.ctx, client.UserAgentHeaderName, "Code/Android/1.2.3"))
+
+	return env
+}
+
+func (e *testEnv) newVerdict() map[string]any {
+	nonce, _, err := e.nonceIssuer.Issue(testDeviceId)
+	if err != nil {
+		panic(err)
+	}
+
+	return map[string]any{
+		"requestDetails": map[string]any{
+			"requestPackageName": testPackageName,
+			"nonce":              nonce,
+			"timestampMillis":    fmt.Sprintf("%d", time.Now().UnixMilli()),
+		},
+		"appIntegrity": map[string]any{
+			"appRecognitionVerdict":   "PLAY_RECOGNIZED",
+			"packageName":             testPackageName,
+			"certificateSha256Digest": []string{encodeCertificateDigest("certificate")},
+			"versionCode":             "42",
+		},
+		"deviceIntegrity": map[string]any{
+			"deviceRecognitionVerdict": []string{"MEETS_DEVICE_INTEGRITY"},
+		},
+		"accountDetails": map[string]any{
+			"appLicensingVerdict": "LICENSED",
+		},
+	}
+}
+
+func (e *testEnv) newToken(t *testing.T, verdict map[string]any) string {
+	payload, err := json.Marshal(verdict)
+	require.NoError(t, err)
+
+	jws, err := jose.SignBytes(payload, jose.ES256, e.signingKey)
+	require.NoError(t, err)
+
+	jwe, err := jose.Encrypt(jws, jose.A256KW, jose.A256GCM, e.encryptionKey)
+	require.NoError(t, err)
+
+	return jwe
+}
+
+// newNonce issues a nonce with an arbitrary expiry, which can't be done via Issue
+func (e *testEnv) newNonce(t *testing.T, deviceId string, expiresAt time.Time) string {
+	nonce, _, err := e.nonceIssuer.Issue(deviceId)
+	require.NoError(t, err)
+
+	decoded, err := base64.RawURLEncoding.DecodeString(nonce)
+	require.NoError(t, err)
+
+	payload := decoded[:len(decoded)-sha256.Size]
+	binary.BigEndian.PutUint64(payload[1:9], uint64(expiresAt.Unix()))
+	return base64.RawURLEncoding.EncodeToString(append(payload, e.nonceIssuer.sign(payload)...))
+}
+
+type testStore struct {
+	mu      sync.Mutex
+	nonces  map[string]string
+	devices map[string]bool
+}
+
+func newTestStore() *testStore {
+	return &testStore{
+		nonces:  make(map[string]string),
+		devices: make(map[string]bool),
+	}
+}
+
+func (s *testStore) MarkIntegrityNonceUsed(_ context.Context, nonce, deviceId string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.nonces[nonce]; ok {
+		return integritynonce.ErrNonceAlreadyUsed
+	}
+	s.nonces[nonce] = deviceId
+	return nil
+}
+
+func (s *testStore) MarkDeviceCreatedFreeAccount(_ context.Context, deviceId string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.devices[deviceId] = true
+	return nil
+}
+
+func (s *testStore) HasDeviceCreatedFreeAccount(_ context.Context, deviceId string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.devices[deviceId], nil
+}
+
+func getObject(verdict map[string]any, key string) map[string]any {
+	return verdict[key].(map[string]any)
+}
+
+func encodeCertificateDigest(certificate string) string {
+	digest := sha256.Sum256([]byte(certificate))
+	return base64.RawURLEncoding.EncodeToString(digest[:])
+}
@@ -0,0 +1,32 @@
+package geoip
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrInvalidIp = errors.New("invalid ip address")
+)
+
+// Metadata is geographic and network metadata about an IP address. Fields are
+// nil when the information isn't known.
+type Metadata struct {
+	City    *string
+	Country *string
+
+	// ASN is the autonomous system number that owns the IP
+	ASN             *uint32
+	ASNOrganization *string
+
+	// IsHosting is whether the IP belongs to a hosting provider or datacenter,
+	// or is a known VPN, proxy or Tor exit node, rather than a consumer network
+	IsHosting bool
+}
+
+// GeoIP provides metadata about IP addresses
+type GeoIP interface {
+	// Lookup gets metadata about an IP. Information is provided on a best-effort
+	// basis, so an empty result is returned when nothing is known about the IP.
+	Lookup(ctx context.Context, ip string) (*Metadata, error)
+}
@@ -0,0 +1,147 @@
+package maxmind
+
+import (
+	"context"
+	"net"
+
+	"github.com/oschwald/maxminddb-golang"
+	"github.com/pkg/errors"
+
+	"github.com/code-payments/code-server/pkg/geoip"
+	"github.com/code-payments/code-server/pkg/metrics"
+	"github.com/code-payments/code-server/pkg/pointer"
+)
+
+const (
+	metricsStructName = "geoip.maxmind"
+)
+
+type cityRecord struct {
+	City struct {
+		Names struct {
+			En string `maxminddb:"en"`
+		} `maxminddb:"names"`
+	} `maxminddb:"city"`
+	Country struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+}
+
+type asnRecord struct {
+	AutonomousSystemNumber       uint32 `maxminddb:"autonomous_system_number"`
+	AutonomousSystemOrganization string `maxminddb:"autonomous_system_organization"`
+}
+
+type anonymousIpRecord struct {
+	IsAnonymous        bool `maxminddb:"is_anonymous"`
+	IsAnonymousVpn     bool `maxminddb:"is_anonymous_vpn"`
+	IsHostingProvider  bool `maxminddb:"is_hosting_provider"`
+	IsPublicProxy      bool `maxminddb:"is_public_proxy"`
+	IsResidentialProxy bool `maxminddb:"is_residential_proxy"`
+	IsTorExitNode      bool `maxminddb:"is_tor_exit_node"`
+}
+
+func (r *anonymousIpRecord) isHosting() bool {
+	return r.IsAnonymous ||
+		r.IsAnonymousVpn ||
+		r.IsHostingProvider ||
+		r.IsPublicProxy ||
+		r.IsResidentialProxy ||
+		r.IsTorExitNode
+}
+
+type maxMind struct {
+	city        *maxminddb.Reader
+	asn         *maxminddb.Reader
+	anonymousIp *maxminddb.Reader
+}
+
+// NewGeoIP returns a new geoip.GeoIP backed by MaxMind databases. Each database
+// is optional, and the metadata it provides is omitted when it's nil:
+//   - city: GeoIP2/GeoLite2 City, providing city and country
+//   - asn: GeoLite2 ASN, providing the ASN and its organization
+//   - anonymousIp: GeoIP2 Anonymous IP, providing the hosting flag
+func NewGeoIP(city, asn, anonymousIp *maxminddb.Reader) geoip.GeoIP {
+	return &maxMind{
+		city:        city,
+		asn:         asn,
+		anonymousIp: anonymousIp,
+	}
+}
+
+// Open opens the MaxMind databases at the provided paths and returns a
+// geoip.GeoIP backed by them. Empty paths are skipped.
+func Open(cityPath, asnPath, anonymousIpPath string) (geoip.GeoIP, error) {
+	var readers []*maxminddb.Reader
+	for _, path := range []string{cityPath, asnPath, anonymousIpPath} {
+		if len(path) == 0 {
+			readers = append(readers, nil)
+			continue
+		}
+
+		reader, err := maxminddb.Open(path)
+		if err != nil {
+			for _, opened := range readers {
+				if opened != nil {
+					opened.Close()
+				}
+			}
+			return nil, errors.Wrapf(err, "error opening maxmind database at %s", path)
+		}
+		readers = append(readers, reader)
+	}
+
+	return NewGeoIP(readers[0], readers[1], readers[2]), nil
+}
+
+// Lookup implements geoip.GeoIP.Lookup
+func (m *maxMind) Lookup(ctx context.Context, ip string) (*geoip.Metadata, error) {
+	tracer := metrics.TraceMethodCall(ctx, metricsStructName, "Lookup")
+	defer tracer.End()
+
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return nil, geoip.ErrInvalidIp
+	}
+
+	var res geoip.Metadata
+
+	if m.city != nil {
+		var record cityRecord
+		err := m.city.Lookup(parsed, &record)
+		if err != nil {
+			tracer.OnError(err)
+			return nil, errors.Wrap(err, "error looking up city metadata")
+		}
+
+		res.City = pointer.StringIfValid(len(record.City.Names.En) > 0, record.City.Names.En)
+		res.Country = pointer.StringIfValid(len(record.Country.ISOCode) > 0, record.Country.ISOCode)
+	}
+
+	if m.asn != nil {
+		var record asnRecord
+		err := m.asn.Lookup(parsed, &record)
+		if err != nil {
+			tracer.OnError(err)
+			return nil, errors.Wrap(err, "error looking up asn metadata")
+		}
+
+		if record.AutonomousSystemNumber > 0 {
+			res.ASN = &record.AutonomousSystemNumber
+		}
+		res.ASNOrganization = pointer.StringIfValid(len(record.AutonomousSystemOrganization) > 0, record.AutonomousSystemOrganization)
+	}
+
+	if m.anonymousIp != nil {
+		var record anonymousIpRecord
+		err := m.anonymousIp.Lookup(parsed, &record)
+		if err != nil {
+			tracer.OnError(err)
+			return nil, errors.Wrap(err, "error looking up anonymous ip metadata")
+		}
+
+		res.IsHosting = record.isHosting()
+	}
+
+	return &res, nil
+}
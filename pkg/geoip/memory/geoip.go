@@ -0,0 +1,80 @@
+package memory
+
+import (
+	"context"
+	"net"
+	"sync"
+
+	"github.com/code-payments/code-server/pkg/geoip"
+)
+
+type entry struct {
+	ipNet    *net.IPNet
+	metadata geoip.Metadata
+}
+
+// GeoIP is a static in-memory geoip.GeoIP, intended for tests
+type GeoIP struct {
+	mu      sync.RWMutex
+	entries []*entry
+}
+
+// NewGeoIP returns a new static in-memory GeoIP with no entries
+func NewGeoIP() *GeoIP {
+	return &GeoIP{}
+}
+
+// Set sets the metadata for an IP or CIDR range. Lookups use the most recently
+// set matching entry.
+func (g *GeoIP) Set(ipOrCidr string, metadata *geoip.Metadata) error {
+	_, ipNet, err := net.ParseCIDR(ipOrCidr)
+	if err != nil {
+		ip := net.ParseIP(ipOrCidr)
+		if ip == nil {
+			return geoip.ErrInvalidIp
+		}
+
+		bits := 8 * net.IPv6len
+		if ip.To4() != nil {
+			ip = ip.To4()
+			bits = 8 * net.IPv4len
+		}
+		ipNet = &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.entries = append(g.entries, &entry{
+		ipNet:    ipNet,
+		metadata: *metadata,
+	})
+	return nil
+}
+
+// Reset removes all entries
+func (g *GeoIP) Reset() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.entries = nil
+}
+
+// Lookup implements geoip.GeoIP.Lookup
+func (g *GeoIP) Lookup(_ context.Context, ip string) (*geoip.Metadata, error) {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return nil, geoip.ErrInvalidIp
+	}
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	for i := len(g.entries) - 1; i >= 0; i-- {
+		if g.entries[i].ipNet.Contains(parsed) {
+			cloned := g.entries[i].metadata
+			return &cloned, nil
+		}
+	}
+	return &geoip.Metadata{}, nil
+}
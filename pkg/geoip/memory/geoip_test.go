@@ -0,0 +1,57 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/code-payments/code-server/pkg/geoip"
+	"github.com/code-payments/code-server/pkg/pointer"
+)
+
+func TestGeoIP(t *testing.T) {
+	ctx := context.Background()
+	g := NewGeoIP()
+
+	_, err := g.Lookup(ctx, "invalid")
+	assert.Equal(t, geoip.ErrInvalidIp, err)
+
+	actual, err := g.Lookup(ctx, "1.2.3.4")
+	require.NoError(t, err)
+	assert.Equal(t, &geoip.Metadata{}, actual)
+
+	assert.Equal(t, geoip.ErrInvalidIp, g.Set("invalid", &geoip.Metadata{}))
+
+	asn := uint32(16509)
+	require.NoError(t, g.Set("1.2.0.0/16", &geoip.Metadata{
+		Country:         pointer.String("US"),
+		ASN:             &asn,
+		ASNOrganization: pointer.String("AMAZON-02"),
+		IsHosting:       true,
+	}))
+	require.NoError(t, g.Set("1.2.3.4", &geoip.Metadata{
+		City:    pointer.String("Toronto"),
+		Country: pointer.String("CA"),
+	}))
+
+	actual, err = g.Lookup(ctx, "1.2.3.4")
+	require.NoError(t, err)
+	assert.Equal(t, "Toronto", *actual.City)
+	assert.Equal(t, "CA", *actual.Country)
+	assert.False(t, actual.IsHosting)
+
+	actual, err = g.Lookup(ctx, "1.2.3.5")
+	require.NoError(t, err)
+	assert.Nil(t, actual.City)
+	assert.Equal(t, "US", *actual.Country)
+	assert.Equal(t, asn, *actual.ASN)
+	assert.True(t, actual.IsHosting)
+
+	g.Reset()
+
+	actual, err = g.Lookup(ctx, "1.2.3.5")
+	require.NoError(t, err)
+	assert.Equal(t, &geoip.Metadata{}, actual)
+}
@@ -222,15 +222,20 @@ func Run(app App, options ...Option) error {
 		}
 	}
 
+	var opts opts
+	for _, o := range options {
+		o(&opts)
+	}
+
 	defaultUnaryServerInterceptors := []grpc.UnaryServerInterceptor{
 		headers.UnaryServerInterceptor(),
 		validation.UnaryServerInterceptor(),
-		client.MinVersionUnaryServerInterceptor(),
+		client.MinVersionUnaryServerInterceptor(opts.minVersionProvider),
 	}
 	defaultStreamServerInterceptors := []grpc.StreamServerInterceptor{
 		headers.StreamServerInterceptor(),
 		validation.StreamServerInterceptor(),
-		client.MinVersionStreamServerInterceptor(),
+		client.MinVersionStreamServerInterceptor(opts.minVersionProvider),
 	}
 	if metricsProvider != nil {
 		// Metrics interceptor should be near the top of the chain, so we can
@@ -240,23 +245,19 @@ func Run(app App, options ...Option) error {
 			headers.UnaryServerInterceptor(),
 			metrics.CustomNewRelicUnaryServerInterceptor(metricsProvider),
 			validation.UnaryServerInterceptor(),
-			client.MinVersionUnaryServerInterceptor(),
+			client.MinVersionUnaryServerInterceptor(opts.minVersionProvider),
 		}
 		defaultStreamServerInterceptors = []grpc.StreamServerInterceptor{
 			headers.StreamServerInterceptor(),
 			metrics.CustomNewRelicStreamServerInterceptor(metricsProvider),
 			validation.StreamServerInterceptor(),
-			client.MinVersionStreamServerInterceptor(),
+			client.MinVersionStreamServerInterceptor(opts.minVersionProvider),
 		}
 	}
 
-	opts := opts{
-		unaryServerInterceptors:  defaultUnaryServerInterceptors,
-		streamServerInterceptors: defaultStreamServerInterceptors,
-	}
-	for _, o := range options {
-		o(&opts)
-	}
+	// Configured interceptors are executed after the default interceptors
+	opts.unaryServerInterceptors = append(defaultUnaryServerInterceptors, opts.unaryServerInterceptors...)
+	opts.streamServerInterceptors = append(defaultStreamServerInterceptors, opts.streamServerInterceptors...)
 
 	if err := app.Init(config.AppConfig, metricsProvider); err != nil {
 		logger.WithError(err).Error("failed to initialize application")
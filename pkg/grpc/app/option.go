@@ -2,6 +2,8 @@ package app
 
 import (
 	"google.golang.org/grpc"
+
+	"github.com/code-payments/code-server/pkg/grpc/client"
 )
 
 // Option configures the environment run by Run().
@@ -10,6 +12,7 @@ type Option func(o *opts)
 type opts struct {
 	unaryServerInterceptors  []grpc.UnaryServerInterceptor
 	streamServerInterceptors []grpc.StreamServerInterceptor
+	minVersionProvider       client.MinVersionProvider
 }
 
 // WithUnaryServerInterceptor configures the app's gRPC server to use the provided interceptor.
@@ -31,3 +34,14 @@ func WithStreamServerInterceptor(interceptor grpc.StreamServerInterceptor) Optio
 		o.streamServerInterceptors = append(o.streamServerInterceptors, interceptor)
 	}
 }
+
+// WithMinVersionProvider configures the minimum client versions that are allowed
+// to access the app's gRPC server. Clients below the minimum version are rejected
+// with an error asking them to upgrade.
+//
+// The default minimum versions are used when not configured.
+func WithMinVersionProvider(provider client.MinVersionProvider) Option {
+	return func(o *opts) {
+		o.minVersionProvider = provider
+	}
+}
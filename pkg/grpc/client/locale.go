@@ -0,0 +1,43 @@
+package client
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"golang.org/x/text/language"
+
+	"github.com/code-payments/code-server/pkg/grpc/headers"
+)
+
+const (
+	LocaleHeaderName = "accept-language"
+)
+
+var (
+	// The wildcard locale, which expresses no preference
+	anyLocale = language.MustParse("mul")
+)
+
+// GetLocale gets the client's most preferred locale from headers in the provided
+// context
+func GetLocale(ctx context.Context) (language.Tag, error) {
+	headerValue, err := headers.GetASCIIHeaderByName(ctx, LocaleHeaderName)
+	if err != nil {
+		return language.Und, errors.Wrap(err, "locale header not present")
+	}
+
+	if len(headerValue) == 0 {
+		return language.Und, errors.New("locale header not present")
+	}
+
+	tags, _, err := language.ParseAcceptLanguage(headerValue)
+	if err != nil {
+		return language.Und, errors.Wrap(err, "invalid locale header")
+	}
+
+	if len(tags) == 0 || tags[0] == language.Und || tags[0] == anyLocale {
+		return language.Und, errors.New("no locale present")
+	}
+
+	return tags[0], nil
+}
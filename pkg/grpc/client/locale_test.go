@@ -0,0 +1,51 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+
+	"github.com/code-payments/code-server/pkg/grpc/headers"
+)
+
+func TestGetLocale_HappyPath(t *testing.T) {
+	for headerValue, expected := range map[string]language.Tag{
+		"en":                      language.English,
+		"es-MX":                   language.MustParse("es-MX"),
+		"fr-CA,fr;q=0.9,en;q=0.8": language.MustParse("fr-CA"),
+		"en;q=0.5,es;q=0.9":       language.Spanish,
+	} {
+		ctx := context.Background()
+		ctx, err := headers.ContextWithHeaders(ctx)
+		require.NoError(t, err)
+		require.NoError(t, headers.SetASCIIHeader(ctx, LocaleHeaderName, headerValue))
+
+		actual, err := GetLocale(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, expected, actual)
+	}
+}
+
+func TestGetLocale_ParseError(t *testing.T) {
+	for _, headerValue := range []string{
+		"",
+		"*",
+		"not a locale!",
+	} {
+		ctx := context.Background()
+		ctx, err := headers.ContextWithHeaders(ctx)
+		require.NoError(t, err)
+		require.NoError(t, headers.SetASCIIHeader(ctx, LocaleHeaderName, headerValue))
+
+		_, err = GetLocale(ctx)
+		assert.Error(t, err)
+	}
+}
+
+func TestGetLocale_HeaderNotPresent(t *testing.T) {
+	_, err := GetLocale(context.Background())
+	assert.Error(t, err)
+}
@@ -8,19 +8,38 @@ import (
 	"strings"
 
 	"github.com/pkg/errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+
+	"github.com/code-payments/code-server/pkg/config"
 )
 
 const (
 	versionPattern = "\\d+.\\d+.\\d+"
+
+	// UpgradeRequiredReason is the reason in the ErrorInfo detail of errors
+	// returned to clients whose version is no longer supported
+	UpgradeRequiredReason = "CLIENT_UPGRADE_REQUIRED"
+	upgradeRequiredDomain = "getcode.com"
+
+	deviceTypeMetadataKey    = "device_type"
+	clientVersionMetadataKey = "client_version"
+	minVersionMetadataKey    = "min_version"
+)
+
+// Keys for minimum versions that can be set via NewConfigMinVersionProvider
+const (
+	MinIOSVersionConfigKey     = "CLIENT_MIN_IOS_VERSION"
+	MinAndroidVersionConfigKey = "CLIENT_MIN_ANDROID_VERSION"
 )
 
 var (
 	versionRegex = regexp.MustCompile(fmt.Sprintf("^%s$", versionPattern))
 
-	// todo: configurable
+	// Default minimum versions when no MinVersionProvider is configured, or it
+	// doesn't have a value for the device type
 	minVersionByDevice = map[DeviceType]*Version{
 		DeviceTypeIOS: {
 			Major: 0,
@@ -103,9 +122,55 @@ func (v *Version) String() string {
 	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
 }
 
+// MinVersionProvider provides the minimum client version that's supported for
+// a device type
+type MinVersionProvider interface {
+	// GetMinVersion gets the minimum supported version for the device type. A
+	// nil version is returned when there's no minimum.
+	GetMinVersion(ctx context.Context, deviceType DeviceType) (*Version, error)
+}
+
+type configMinVersionProvider struct {
+	source config.Source
+}
+
+// NewConfigMinVersionProvider returns a MinVersionProvider that reads minimum
+// versions from a config source, so upgrades can be forced without a deploy.
+// Device types without a valid value in the source use the default minimums.
+func NewConfigMinVersionProvider(source config.Source) MinVersionProvider {
+	return &configMinVersionProvider{
+		source: source,
+	}
+}
+
+// GetMinVersion implements MinVersionProvider.GetMinVersion
+func (p *configMinVersionProvider) GetMinVersion(ctx context.Context, deviceType DeviceType) (*Version, error) {
+	var key string
+	switch deviceType {
+	case DeviceTypeIOS:
+		key = MinIOSVersionConfigKey
+	case DeviceTypeAndroid:
+		key = MinAndroidVersionConfigKey
+	default:
+		return getDefaultMinVersion(deviceType)
+	}
+
+	raw, err := p.source.Get(ctx, key)
+	if err != nil {
+		return getDefaultMinVersion(deviceType)
+	}
+
+	minVersion, err := ParseVersion(string(raw))
+	if err != nil {
+		return getDefaultMinVersion(deviceType)
+	}
+	return minVersion, nil
+}
+
 // MinVersionUnaryServerInterceptor prevents versions below the minimum
-// version from accessing outdated APIs.
-func MinVersionUnaryServerInterceptor() grpc.UnaryServerInterceptor {
+// version from accessing outdated APIs. The default minimums are used when
+// the provider is nil.
+func MinVersionUnaryServerInterceptor(provider MinVersionProvider) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 		// Health checks are internal and don't have an external client user agent
 		//
@@ -122,7 +187,7 @@ func MinVersionUnaryServerInterceptor() grpc.UnaryServerInterceptor {
 			return handler(ctx, req)
 		}
 
-		if err := checkMinVersion(userAgent); err != nil {
+		if err := checkMinVersion(ctx, provider, userAgent); err != nil {
 			return nil, err
 		}
 
@@ -131,8 +196,9 @@ func MinVersionUnaryServerInterceptor() grpc.UnaryServerInterceptor {
 }
 
 // MinVersionStreamServerInterceptor prevents versions below the minimum
-// version from accessing lower version APIs.
-func MinVersionStreamServerInterceptor() grpc.StreamServerInterceptor {
+// version from accessing lower version APIs. The default minimums are used
+// when the provider is nil.
+func MinVersionStreamServerInterceptor(provider MinVersionProvider) grpc.StreamServerInterceptor {
 	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
 		userAgent, err := GetUserAgent(ss.Context())
 		if err != nil {
@@ -141,7 +207,7 @@ func MinVersionStreamServerInterceptor() grpc.StreamServerInterceptor {
 			return handler(srv, ss)
 		}
 
-		if err := checkMinVersion(userAgent); err != nil {
+		if err := checkMinVersion(ss.Context(), provider, userAgent); err != nil {
 			return err
 		}
 
@@ -149,15 +215,73 @@ func MinVersionStreamServerInterceptor() grpc.StreamServerInterceptor {
 	}
 }
 
-func checkMinVersion(userAgent *UserAgent) error {
-	minVersion, ok := minVersionByDevice[userAgent.DeviceType]
-	if !ok {
+// GetUpgradeRequiredDetails gets the minimum supported version from an error
+// returned by the min version interceptors, if the client must upgrade
+func GetUpgradeRequiredDetails(err error) (*Version, bool) {
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != codes.FailedPrecondition {
+		return nil, false
+	}
+
+	for _, detail := range st.Details() {
+		errorInfo, ok := detail.(*errdetails.ErrorInfo)
+		if !ok || errorInfo.Reason != UpgradeRequiredReason {
+			continue
+		}
+
+		minVersion, err := ParseVersion(errorInfo.Metadata[minVersionMetadataKey])
+		if err != nil {
+			return nil, false
+		}
+		return minVersion, true
+	}
+
+	return nil, false
+}
+
+func checkMinVersion(ctx context.Context, provider MinVersionProvider, userAgent *UserAgent) error {
+	var minVersion *Version
+	var err error
+	if provider != nil {
+		minVersion, err = provider.GetMinVersion(ctx, userAgent.DeviceType)
+	} else {
+		minVersion, err = getDefaultMinVersion(userAgent.DeviceType)
+	}
+	if err != nil {
 		return status.Error(codes.FailedPrecondition, "unsupported client type")
 	}
 
 	if userAgent.Version.Before(minVersion) {
-		return status.Error(codes.FailedPrecondition, "version too low")
+		return newUpgradeRequiredError(userAgent, minVersion)
 	}
 
 	return nil
 }
+
+func getDefaultMinVersion(deviceType DeviceType) (*Version, error) {
+	minVersion, ok := minVersionByDevice[deviceType]
+	if !ok {
+		return nil, errors.New("unsupported client type")
+	}
+	return minVersion, nil
+}
+
+// newUpgradeRequiredError returns an error with an ErrorInfo detail that
+// clients can use to prompt for an upgrade to the minimum version
+func newUpgradeRequiredError(userAgent *UserAgent, minVersion *Version) error {
+	st := status.New(codes.FailedPrecondition, "version too low")
+
+	withDetails, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason: UpgradeRequiredReason,
+		Domain: upgradeRequiredDomain,
+		Metadata: map[string]string{
+			deviceTypeMetadataKey:    userAgent.DeviceType.String(),
+			clientVersionMetadataKey: userAgent.Version.String(),
+			minVersionMetadataKey:    minVersion.String(),
+		},
+	})
+	if err != nil {
+		return st.Err()
+	}
+	return withDetails.Err()
+}
@@ -1,12 +1,17 @@
 package client
 
 import (
+	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
-	"github.com/code-payments/code-server/pkg/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	file_config "github.com/code-payments/code-server/pkg/config/file"
 )
 
 func TestVersion_ParseHappyPath(t *testing.T) {
@@ -157,7 +162,7 @@ func TestCheckMinVersion(t *testing.T) {
 			Patch: 0,
 		},
 	}
-	assert.NoError(t, checkMinVersion(userAgent))
+	assert.NoError(t, checkMinVersion(context.Background(), nil, userAgent))
 
 	// Version for device is the minimum
 	userAgent = &UserAgent{
@@ -168,7 +173,7 @@ func TestCheckMinVersion(t *testing.T) {
 			Patch: 0,
 		},
 	}
-	assert.NoError(t, checkMinVersion(userAgent))
+	assert.NoError(t, checkMinVersion(context.Background(), nil, userAgent))
 
 	// Version for device is below the minimum
 	userAgent = &UserAgent{
@@ -179,7 +184,12 @@ func TestCheckMinVersion(t *testing.T) {
 			Patch: 0,
 		},
 	}
-	testutil.AssertStatusErrorWithCode(t, checkMinVersion(userAgent), codes.FailedPrecondition)
+	err := checkMinVersion(context.Background(), nil, userAgent)
+	require.Error(t, err)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	minVersion, ok := GetUpgradeRequiredDetails(err)
+	require.True(t, ok)
+	assert.Equal(t, "2.0.0", minVersion.String())
 
 	// Unknown devices fails min version check
 	userAgent = &UserAgent{
@@ -190,5 +200,64 @@ func TestCheckMinVersion(t *testing.T) {
 			Patch: 0,
 		},
 	}
-	testutil.AssertStatusErrorWithCode(t, checkMinVersion(userAgent), codes.FailedPrecondition)
+	err = checkMinVersion(context.Background(), nil, userAgent)
+	require.Error(t, err)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	_, ok = GetUpgradeRequiredDetails(checkMinVersion(context.Background(), nil, userAgent))
+	assert.False(t, ok)
+}
+
+func TestConfigMinVersionProvider(t *testing.T) {
+	ctx := context.Background()
+
+	minVersionByDevice[DeviceTypeIOS] = &Version{
+		Major: 2,
+		Minor: 0,
+		Patch: 0,
+	}
+	minVersionByDevice[DeviceTypeAndroid] = &Version{
+		Major: 1,
+		Minor: 0,
+		Patch: 0,
+	}
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+CLIENT_MIN_IOS_VERSION: 3.1.0
+CLIENT_MIN_ANDROID_VERSION: invalid
+`), 0600))
+
+	source, err := file_config.NewSource(path)
+	require.NoError(t, err)
+	defer source.Shutdown()
+
+	provider := NewConfigMinVersionProvider(source)
+
+	// Minimums use the config value when it's set and valid
+	minVersion, err := provider.GetMinVersion(ctx, DeviceTypeIOS)
+	require.NoError(t, err)
+	assert.Equal(t, "3.1.0", minVersion.String())
+
+	minVersion, err = provider.GetMinVersion(ctx, DeviceTypeAndroid)
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", minVersion.String())
+
+	_, err = provider.GetMinVersion(ctx, DeviceTypeUnknown)
+	assert.Error(t, err)
+
+	userAgent := &UserAgent{
+		DeviceType: DeviceTypeIOS,
+		Version: Version{
+			Major: 3,
+			Minor: 0,
+			Patch: 5,
+		},
+	}
+	err = checkMinVersion(ctx, provider, userAgent)
+	minVersion, ok := GetUpgradeRequiredDetails(err)
+	require.True(t, ok)
+	assert.Equal(t, "3.1.0", minVersion.String())
+
+	userAgent.Version.Minor = 1
+	assert.NoError(t, checkMinVersion(ctx, provider, userAgent))
 }
@@ -0,0 +1,116 @@
+package mint
+
+import (
+	"crypto/ed25519"
+	"math"
+
+	"github.com/mr-tron/base58"
+	"github.com/pkg/errors"
+
+	"github.com/code-payments/code-server/pkg/currency"
+	"github.com/code-payments/code-server/pkg/kin"
+)
+
+const (
+	UsdcMint     = "EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v"
+	UsdcDecimals = 6
+)
+
+var (
+	// Kin is the default mint, which is always supported
+	Kin = &Mint{
+		Address:  kin.TokenMint,
+		Symbol:   "kin",
+		Decimals: kin.Decimals,
+	}
+
+	// Usdc is USD Coin. It must be registered to be supported.
+	Usdc = &Mint{
+		Address:        mustDecodeAddress(UsdcMint),
+		Symbol:         "usdc",
+		Decimals:       UsdcDecimals,
+		PeggedCurrency: currency.USD,
+	}
+)
+
+// Mint is an SPL token mint
+type Mint struct {
+	Address  ed25519.PublicKey
+	Symbol   string
+	Decimals uint8
+
+	// PeggedCurrency is the fiat currency a stablecoin's value is fixed to.
+	// Exchange rates for the mint are derived from the currency's exchange rate.
+	// It's empty for mints with a market value (eg. Kin).
+	PeggedCurrency currency.Code
+}
+
+// Validate validates the mint
+func (m *Mint) Validate() error {
+	if len(m.Address) != ed25519.PublicKeySize {
+		return errors.New("address is invalid")
+	}
+
+	if len(m.Symbol) == 0 {
+		return errors.New("symbol is required")
+	}
+
+	if m.Decimals > 18 {
+		return errors.New("decimals must be at most 18")
+	}
+
+	return nil
+}
+
+// ToBase58 gets the base58 encoded address of the mint
+func (m *Mint) ToBase58() string {
+	return base58.Encode(m.Address)
+}
+
+// IsKin returns whether the mint is Kin
+func (m *Mint) IsKin() bool {
+	return m.ToBase58() == kin.Mint
+}
+
+// IsPegged returns whether the mint is a stablecoin pegged to a fiat currency
+func (m *Mint) IsPegged() bool {
+	return len(m.PeggedCurrency) > 0
+}
+
+// QuarksPerUnit gets the number of quarks in a single whole unit of the token
+func (m *Mint) QuarksPerUnit() uint64 {
+	return uint64(math.Pow10(int(m.Decimals)))
+}
+
+// ToQuarks converts whole units of the token into quarks
+func (m *Mint) ToQuarks(units uint64) uint64 {
+	return units * m.QuarksPerUnit()
+}
+
+// FromQuarks converts quarks into whole units of the token, which may be
+// fractional
+func (m *Mint) FromQuarks(quarks uint64) float64 {
+	return float64(quarks) / float64(m.QuarksPerUnit())
+}
+
+// NativeCurrency gets the currency that naturally represents an amount of the
+// token. It's the pegged currency for stablecoins, and the token itself
+// otherwise.
+func (m *Mint) NativeCurrency() currency.Code {
+	if m.IsPegged() {
+		return m.PeggedCurrency
+	}
+	return currency.Code(m.Symbol)
+}
+
+func (m *Mint) String() string {
+	return m.Symbol
+}
+
+func mustDecodeAddress(address string) ed25519.PublicKey {
+	decoded, err := base58.Decode(address)
+	if err != nil || len(decoded) != ed25519.PublicKeySize {
+		panic("invalid mint address")
+	}
+	return decoded
+}
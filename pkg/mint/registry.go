@@ -0,0 +1,111 @@
+package mint
+
+import (
+	"crypto/ed25519"
+	"sort"
+	"sync"
+
+	"github.com/mr-tron/base58"
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrUnsupportedMint = errors.New("mint is not supported")
+)
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]*Mint{
+		Kin.ToBase58(): Kin,
+	}
+)
+
+// Register adds a mint to the set of supported mints. Kin is always supported
+// and doesn't need to be registered. Additional mints should be registered at
+// startup, before any services or workers are started. Registering the same
+// mint more than once is a no-op.
+func Register(m *Mint) error {
+	if err := m.Validate(); err != nil {
+		return err
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	for address, existing := range registry {
+		if address == m.ToBase58() {
+			if existing.Symbol != m.Symbol || existing.Decimals != m.Decimals || existing.PeggedCurrency != m.PeggedCurrency {
+				return errors.Errorf("mint %s is already registered with a different configuration", address)
+			}
+			return nil
+		}
+
+		if existing.Symbol == m.Symbol {
+			return errors.Errorf("symbol %s is already registered to mint %s", m.Symbol, address)
+		}
+	}
+
+	registry[m.ToBase58()] = m
+	return nil
+}
+
+// Get gets a supported mint by its base58 encoded address. For records that
+// predate multi-mint support, an empty address refers to Kin.
+func Get(address string) (*Mint, error) {
+	if len(address) == 0 {
+		return Kin, nil
+	}
+
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	m, ok := registry[address]
+	if !ok {
+		return nil, ErrUnsupportedMint
+	}
+	return m, nil
+}
+
+// GetByPublicKey gets a supported mint by its address
+func GetByPublicKey(address ed25519.PublicKey) (*Mint, error) {
+	if len(address) != ed25519.PublicKeySize {
+		return nil, ErrUnsupportedMint
+	}
+	return Get(base58.Encode(address))
+}
+
+// IsSupported returns whether the address is for a supported mint
+func IsSupported(address ed25519.PublicKey) bool {
+	_, err := GetByPublicKey(address)
+	return err == nil
+}
+
+// GetAll gets all supported mints, with Kin first, followed by the remaining
+// mints ordered by symbol
+func GetAll() []*Mint {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	res := make([]*Mint, 0, len(registry))
+	for _, m := range registry {
+		res = append(res, m)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].IsKin() != res[j].IsKin() {
+			return res[i].IsKin()
+		}
+		return res[i].Symbol < res[j].Symbol
+	})
+	return res
+}
+
+// unregister removes a mint from the registry, and is used for testing
+func unregister(m *Mint) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if !m.IsKin() {
+		delete(registry, m.ToBase58())
+	}
+}
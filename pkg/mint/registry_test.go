@@ -0,0 +1,93 @@
+package mint
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/code-payments/code-server/pkg/currency"
+	"github.com/code-payments/code-server/pkg/kin"
+)
+
+func TestRegistry_KinIsDefault(t *testing.T) {
+	actual, err := Get(kin.Mint)
+	require.NoError(t, err)
+	assert.Equal(t, Kin, actual)
+	assert.True(t, actual.IsKin())
+
+	actual, err = Get("")
+	require.NoError(t, err)
+	assert.Equal(t, Kin, actual)
+
+	actual, err = GetByPublicKey(kin.TokenMint)
+	require.NoError(t, err)
+	assert.Equal(t, Kin, actual)
+
+	assert.True(t, IsSupported(kin.TokenMint))
+	assert.False(t, IsSupported(Usdc.Address))
+
+	_, err = Get(UsdcMint)
+	assert.Equal(t, ErrUnsupportedMint, err)
+
+	assert.Equal(t, []*Mint{Kin}, GetAll())
+}
+
+func TestRegistry_SecondMint(t *testing.T) {
+	defer unregister(Usdc)
+
+	require.NoError(t, Register(Usdc))
+	require.NoError(t, Register(Usdc))
+
+	actual, err := Get(UsdcMint)
+	require.NoError(t, err)
+	assert.Equal(t, Usdc, actual)
+	assert.False(t, actual.IsKin())
+	assert.True(t, IsSupported(Usdc.Address))
+
+	assert.Equal(t, []*Mint{Kin, Usdc}, GetAll())
+
+	conflictingConfig := *Usdc
+	conflictingConfig.Decimals = 2
+	assert.Error(t, Register(&conflictingConfig))
+
+	conflictingSymbol := &Mint{
+		Address:  generateAddress(t),
+		Symbol:   Usdc.Symbol,
+		Decimals: 6,
+	}
+	assert.Error(t, Register(conflictingSymbol))
+
+	assert.Error(t, Register(&Mint{Address: generateAddress(t)}))
+	assert.Error(t, Register(&Mint{Symbol: "invalid"}))
+
+	unregister(Usdc)
+	assert.False(t, IsSupported(Usdc.Address))
+
+	// Kin can never be removed
+	unregister(Kin)
+	assert.True(t, IsSupported(Kin.Address))
+}
+
+func TestMint_Conversions(t *testing.T) {
+	assert.EqualValues(t, kin.QuarksPerKin, Kin.QuarksPerUnit())
+	assert.Equal(t, kin.ToQuarks(123), Kin.ToQuarks(123))
+	assert.Equal(t, 1.5, Kin.FromQuarks(150_000))
+	assert.Equal(t, currency.KIN, Kin.NativeCurrency())
+	assert.False(t, Kin.IsPegged())
+
+	assert.EqualValues(t, 1_000_000, Usdc.QuarksPerUnit())
+	assert.EqualValues(t, 42_000_000, Usdc.ToQuarks(42))
+	assert.Equal(t, 0.25, Usdc.FromQuarks(250_000))
+	assert.Equal(t, currency.USD, Usdc.NativeCurrency())
+	assert.True(t, Usdc.IsPegged())
+	assert.Equal(t, UsdcMint, Usdc.ToBase58())
+}
+
+func generateAddress(t *testing.T) ed25519.PublicKey {
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	return pub
+}
@@ -0,0 +1,82 @@
+package phone
+
+import (
+	"crypto/sha256"
+	"errors"
+	"sync"
+)
+
+const (
+	// ContactDiscoveryHashSize is the size of a full contact discovery hash
+	ContactDiscoveryHashSize = sha256.Size
+
+	// MinContactDiscoverySaltSize is the minimum size of the salt contact
+	// discovery hashes are computed with
+	MinContactDiscoverySaltSize = 16
+
+	// MinContactDiscoveryPrefixSize and MaxContactDiscoveryPrefixSize bound the
+	// size of truncated hash prefixes. Short prefixes match many verified numbers,
+	// so the server can't learn which number in the bucket the client has.
+	MinContactDiscoveryPrefixSize = 3
+	MaxContactDiscoveryPrefixSize = 4
+)
+
+var (
+	// ErrContactDiscoverySaltNotLoaded is returned when hashing before a salt has
+	// been loaded via LoadContactDiscoverySalt
+	ErrContactDiscoverySaltNotLoaded = errors.New("contact discovery salt not loaded")
+)
+
+var (
+	contactDiscoverySaltMu sync.RWMutex
+	contactDiscoverySalt   []byte
+)
+
+// LoadContactDiscoverySalt loads the salt contact discovery hashes are computed
+// with. The salt is published to clients, which compute hashes of their contacts
+// the same way, so it isn't secret. It prevents reuse of tables precomputed for
+// unsalted phone number hashes, while truncated prefixes and per-owner lookup
+// limits bound how much of the index can be enumerated. Changing the salt
+// invalidates all previously computed hashes.
+func LoadContactDiscoverySalt(salt []byte) error {
+	if len(salt) < MinContactDiscoverySaltSize {
+		return errors.New("contact discovery salt is too short")
+	}
+
+	contactDiscoverySaltMu.Lock()
+	defer contactDiscoverySaltMu.Unlock()
+
+	contactDiscoverySalt = append([]byte(nil), salt...)
+	return nil
+}
+
+// GetContactDiscoverySalt gets the salt loaded via LoadContactDiscoverySalt
+func GetContactDiscoverySalt() ([]byte, error) {
+	contactDiscoverySaltMu.RLock()
+	defer contactDiscoverySaltMu.RUnlock()
+
+	if len(contactDiscoverySalt) == 0 {
+		return nil, ErrContactDiscoverySaltNotLoaded
+	}
+	return append([]byte(nil), contactDiscoverySalt...), nil
+}
+
+// HashForContactDiscovery hashes an E.164 formatted phone number for contact
+// discovery, which is SHA-256(salt || phone number).
+func HashForContactDiscovery(phoneNumber string) ([]byte, error) {
+	salt, err := GetContactDiscoverySalt()
+	if err != nil {
+		return nil, err
+	}
+
+	h := sha256.New()
+	h.Write(salt)
+	h.Write([]byte(phoneNumber))
+	return h.Sum(nil), nil
+}
+
+// IsValidContactDiscoveryPrefix returns whether the value is an acceptably
+// truncated contact discovery hash prefix
+func IsValidContactDiscoveryPrefix(prefix []byte) bool {
+	return len(prefix) >= MinContactDiscoveryPrefixSize && len(prefix) <= MaxContactDiscoveryPrefixSize
+}
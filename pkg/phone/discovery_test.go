@@ -0,0 +1,60 @@
+package phone
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashForContactDiscovery(t *testing.T) {
+	contactDiscoverySalt = nil
+
+	_, err := HashForContactDiscovery("+12223334444")
+	assert.Equal(t, ErrContactDiscoverySaltNotLoaded, err)
+
+	_, err = GetContactDiscoverySalt()
+	assert.Equal(t, ErrContactDiscoverySaltNotLoaded, err)
+
+	assert.Error(t, LoadContactDiscoverySalt(bytes.Repeat([]byte{1}, MinContactDiscoverySaltSize-1)))
+
+	salt := bytes.Repeat([]byte{1}, MinContactDiscoverySaltSize)
+	require.NoError(t, LoadContactDiscoverySalt(salt))
+
+	loaded, err := GetContactDiscoverySalt()
+	require.NoError(t, err)
+	assert.Equal(t, salt, loaded)
+
+	hash, err := HashForContactDiscovery("+12223334444")
+	require.NoError(t, err)
+	assert.Len(t, hash, ContactDiscoveryHashSize)
+
+	// Clients compute the same hash from the published salt
+	expected := sha256.Sum256(append(append([]byte(nil), salt...), "+12223334444"...))
+	assert.Equal(t, expected[:], hash)
+
+	other, err := HashForContactDiscovery("+12223334445")
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, other)
+
+	// Hashes depend on the salt
+	require.NoError(t, LoadContactDiscoverySalt(bytes.Repeat([]byte{2}, MinContactDiscoverySaltSize)))
+
+	resalted, err := HashForContactDiscovery("+12223334444")
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, resalted)
+}
+
+func TestContactDiscoveryValidation(t *testing.T) {
+	require.NoError(t, LoadContactDiscoverySalt(bytes.Repeat([]byte{1}, MinContactDiscoverySaltSize)))
+
+	hash, err := HashForContactDiscovery("+12223334444")
+	require.NoError(t, err)
+
+	for i := 0; i <= ContactDiscoveryHashSize; i++ {
+		expected := i >= MinContactDiscoveryPrefixSize && i <= MaxContactDiscoveryPrefixSize
+		assert.Equal(t, expected, IsValidContactDiscoveryPrefix(hash[:i]))
+	}
+}
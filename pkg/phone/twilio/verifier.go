@@ -16,10 +16,13 @@ import (
 	grpc_client "github.com/code-payments/code-server/pkg/grpc/client"
 	"github.com/code-payments/code-server/pkg/metrics"
 	"github.com/code-payments/code-server/pkg/phone"
+	"github.com/code-payments/code-server/pkg/retry"
 )
 
 const (
 	metricsStructName = "phone.twilio.verifier"
+
+	dependencyName = "twilio"
 )
 
 var (
@@ -64,6 +67,7 @@ var (
 type verifier struct {
 	client     *twilio.RestClient
 	serviceSid string
+	policy     *retry.Policy
 }
 
 // NewVerifier returns a new phone verifier backed by Twilio
@@ -76,6 +80,15 @@ func NewVerifier(accountSid, serviceSid, authToken string) phone.Verifier {
 	return &verifier{
 		client:     client,
 		serviceSid: serviceSid,
+		// Verifications aren't idempotent, so calls are never retried and only
+		// protected by a circuit breaker.
+		policy: retry.NewPolicy(
+			dependencyName,
+			retry.WithStrategies(retry.Limit(1)),
+			retry.WithCircuitBreaker(retry.NewCircuitBreaker(dependencyName)),
+			retry.WithFailureClassifier(isUnhealthyError),
+			retry.WithHooks(retry.MetricsHooks()),
+		),
 	}
 }
 
@@ -84,7 +97,7 @@ func (v *verifier) SendCode(ctx context.Context, phoneNumber string) (string, *p
 	tracer := metrics.TraceMethodCall(ctx, metricsStructName, "SendCode")
 	defer tracer.End()
 
-	err := v.checkValidPhoneNumber(phoneNumber)
+	err := v.checkValidPhoneNumber(ctx, phoneNumber)
 	if err != nil {
 		tracer.OnError(err)
 		return "", nil, err
@@ -96,10 +109,14 @@ func (v *verifier) SendCode(ctx context.Context, phoneNumber string) (string, *p
 		appHash = &androidAppHash
 	}
 
-	resp, err := v.client.VerifyV2.CreateVerification(v.serviceSid, &verifyv2.CreateVerificationParams{
-		To:      &phoneNumber,
-		Channel: &defaultChannel,
-		AppHash: appHash,
+	var resp *verifyv2.VerifyV2Verification
+	_, err = v.policy.Do(ctx, func() error {
+		resp, err = v.client.VerifyV2.CreateVerification(v.serviceSid, &verifyv2.CreateVerificationParams{
+			To:      &phoneNumber,
+			Channel: &defaultChannel,
+			AppHash: appHash,
+		})
+		return err
 	})
 	if err != nil {
 		err = checkInvalidToParameterError(err, phone.ErrInvalidNumber)
@@ -131,9 +148,14 @@ func (v *verifier) Check(ctx context.Context, phoneNumber, code string) error {
 		return err
 	}
 
-	resp, err := v.client.VerifyV2.CreateVerificationCheck(v.serviceSid, &verifyv2.CreateVerificationCheckParams{
-		To:   &phoneNumber,
-		Code: &code,
+	var resp *verifyv2.VerifyV2VerificationCheck
+	_, err := v.policy.Do(ctx, func() error {
+		var err error
+		resp, err = v.client.VerifyV2.CreateVerificationCheck(v.serviceSid, &verifyv2.CreateVerificationCheckParams{
+			To:   &phoneNumber,
+			Code: &code,
+		})
+		return err
 	})
 	if err != nil {
 		err = check404Error(err, phone.ErrNoVerification)
@@ -165,8 +187,11 @@ func (v *verifier) Cancel(ctx context.Context, id string) error {
 	tracer := metrics.TraceMethodCall(ctx, metricsStructName, "Cancel")
 	defer tracer.End()
 
-	_, err := v.client.VerifyV2.UpdateVerification(v.serviceSid, id, &verifyv2.UpdateVerificationParams{
-		Status: &statusCanceled,
+	_, err := v.policy.Do(ctx, func() error {
+		_, err := v.client.VerifyV2.UpdateVerification(v.serviceSid, id, &verifyv2.UpdateVerificationParams{
+			Status: &statusCanceled,
+		})
+		return err
 	})
 
 	if err != nil {
@@ -183,7 +208,12 @@ func (v *verifier) IsVerificationActive(ctx context.Context, id string) (bool, e
 	tracer := metrics.TraceMethodCall(ctx, metricsStructName, "IsVerificationActive")
 	defer tracer.End()
 
-	resp, err := v.client.VerifyV2.FetchVerification(v.serviceSid, id)
+	var resp *verifyv2.VerifyV2Verification
+	_, err := v.policy.Do(ctx, func() error {
+		var err error
+		resp, err = v.client.VerifyV2.FetchVerification(v.serviceSid, id)
+		return err
+	})
 	if is404Error(err) {
 		return false, nil
 	} else if err != nil {
@@ -205,7 +235,7 @@ func (v *verifier) IsValidPhoneNumber(ctx context.Context, phoneNumber string) (
 	tracer := metrics.TraceMethodCall(ctx, metricsStructName, "IsValidPhoneNumber")
 	defer tracer.End()
 
-	err := v.checkValidPhoneNumber(phoneNumber)
+	err := v.checkValidPhoneNumber(ctx, phoneNumber)
 	if err == phone.ErrInvalidNumber || err == phone.ErrUnsupportedPhoneType {
 		return false, nil
 	} else if err != nil {
@@ -217,13 +247,18 @@ func (v *verifier) IsValidPhoneNumber(ctx context.Context, phoneNumber string) (
 
 // todo: Use the new V2 API when we get access. It has some cool features like
 // SIM swap detection.
-func (v *verifier) checkValidPhoneNumber(phoneNumber string) error {
+func (v *verifier) checkValidPhoneNumber(ctx context.Context, phoneNumber string) error {
 	if !phone.IsE164Format(phoneNumber) {
 		return errors.New("phone number is not in E.164 format")
 	}
 
-	resp, err := v.client.LookupsV1.FetchPhoneNumber(phoneNumber, &lookupsv1.FetchPhoneNumberParams{
-		Type: &[]string{carrierMapKey},
+	var resp *lookupsv1.LookupsV1PhoneNumber
+	_, err := v.policy.Do(ctx, func() error {
+		var err error
+		resp, err = v.client.LookupsV1.FetchPhoneNumber(phoneNumber, &lookupsv1.FetchPhoneNumberParams{
+			Type: &[]string{carrierMapKey},
+		})
+		return err
 	})
 	if err != nil {
 		return check404Error(err, phone.ErrInvalidNumber)
@@ -252,6 +287,17 @@ func (v *verifier) checkValidPhoneNumber(phoneNumber string) error {
 	return nil
 }
 
+// isUnhealthyError determines whether an error indicates Twilio is unhealthy, as
+// opposed to a client error for a specific request.
+func isUnhealthyError(err error) bool {
+	twilioError, ok := err.(*client.TwilioRestError)
+	if !ok {
+		return true
+	}
+
+	return twilioError.Status >= http.StatusInternalServerError
+}
+
 func check404Error(inError, outError error) error {
 	if is404Error(inError) {
 		return outError
@@ -16,6 +16,10 @@ import (
 
 const (
 	metricsStructName = "push.fcm.provider"
+
+	dependencyName        = "fcm"
+	retryBudgetTokens     = 10
+	retryBudgetTokenRatio = 0.1
 )
 
 type provider struct {
@@ -47,7 +51,7 @@ func (p *provider) IsValidPushToken(ctx context.Context, pushToken string) (bool
 	defer metrics.TraceMethodCall(ctx, metricsStructName, "IsValidPushToken").End()
 
 	var result bool
-	err := retrier(func() error {
+	err := retrier(ctx, func() error {
 		_, err := p.client.SendDryRun(ctx, &messaging.Message{
 			Token: pushToken,
 			Notification: &messaging.Notification{
@@ -76,7 +80,7 @@ func (p *provider) IsValidPushToken(ctx context.Context, pushToken string) (bool
 func (p *provider) SendPush(ctx context.Context, pushToken, title, body string) error {
 	defer metrics.TraceMethodCall(ctx, metricsStructName, "SendPush").End()
 
-	return retrier(func() error {
+	return retrier(ctx, func() error {
 		_, err := p.client.Send(ctx, &messaging.Message{
 			Token: pushToken,
 			Notification: &messaging.Notification{
@@ -92,7 +96,7 @@ func (p *provider) SendPush(ctx context.Context, pushToken, title, body string)
 func (p *provider) SendLocalizedAPNSPush(ctx context.Context, pushToken, titleKey, bodyKey string, bodyArgs ...string) error {
 	defer metrics.TraceMethodCall(ctx, metricsStructName, "SendLocalizedAPNSPush").End()
 
-	return retrier(func() error {
+	return retrier(ctx, func() error {
 		_, err := p.client.Send(ctx, &messaging.Message{
 			Token: pushToken,
 			APNS: &messaging.APNSConfig{
@@ -115,7 +119,7 @@ func (p *provider) SendLocalizedAPNSPush(ctx context.Context, pushToken, titleKe
 func (p *provider) SendLocalizedAndroidPush(ctx context.Context, pushToken, titleKey, bodyKey string, bodyArgs ...string) error {
 	defer metrics.TraceMethodCall(ctx, metricsStructName, "SendLocalizedAndroidPush").End()
 
-	return retrier(func() error {
+	return retrier(ctx, func() error {
 		_, err := p.client.Send(ctx, &messaging.Message{
 			Token: pushToken,
 			Android: &messaging.AndroidConfig{
@@ -134,7 +138,7 @@ func (p *provider) SendLocalizedAndroidPush(ctx context.Context, pushToken, titl
 func (p *provider) SendDataPush(ctx context.Context, pushToken string, kvs map[string]string) error {
 	defer metrics.TraceMethodCall(ctx, metricsStructName, "SendDataPush").End()
 
-	return retrier(func() error {
+	return retrier(ctx, func() error {
 		_, err := p.client.Send(ctx, &messaging.Message{
 			Token: pushToken,
 			Data:  kvs,
@@ -147,7 +151,7 @@ func (p *provider) SendDataPush(ctx context.Context, pushToken string, kvs map[s
 func (p *provider) SetAPNSBadgeCount(ctx context.Context, pushToken string, count int) error {
 	defer metrics.TraceMethodCall(ctx, metricsStructName, "SetAPNSBadgeCount").End()
 
-	return retrier(func() error {
+	return retrier(ctx, func() error {
 		_, err := p.client.Send(ctx, &messaging.Message{
 			Token: pushToken,
 			APNS: &messaging.APNSConfig{
@@ -163,14 +167,28 @@ func (p *provider) SetAPNSBadgeCount(ctx context.Context, pushToken string, coun
 }
 
 // retrier is a common retry strategy for FCM calls
-func retrier(action retry.Action) error {
-	_, err := retry.Retry(
-		action,
-		retry.Limit(3),
-		func(attempts uint, err error) bool {
-			return messaging.IsUnavailable(err) || messaging.IsInternal(err)
-		},
-		retry.Backoff(backoff.BinaryExponential(250*time.Millisecond), time.Second),
-	)
+func retrier(ctx context.Context, action retry.Action) error {
+	_, err := policy.Do(ctx, action)
 	return err
 }
+
+// policy is shared across all FCM calls. Only unavailable and internal errors
+// are retried, and they're the only errors that indicate FCM is unhealthy.
+var policy = retry.NewPolicy(
+	dependencyName,
+	retry.WithStrategies(
+		retry.Limit(3),
+		isUnhealthyError,
+		retry.Backoff(backoff.DecorrelatedJitter(250*time.Millisecond, time.Second), time.Second),
+	),
+	retry.WithCircuitBreaker(retry.NewCircuitBreaker(dependencyName)),
+	retry.WithBudget(retry.NewBudget(retryBudgetTokens, retryBudgetTokenRatio)),
+	retry.WithFailureClassifier(func(err error) bool {
+		return isUnhealthyError(0, err)
+	}),
+	retry.WithHooks(retry.MetricsHooks()),
+)
+
+func isUnhealthyError(_ uint, err error) bool {
+	return messaging.IsUnavailable(err) || messaging.IsInternal(err)
+}
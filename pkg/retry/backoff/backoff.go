@@ -3,6 +3,7 @@ package backoff
 
 import (
 	"math"
+	"math/rand"
 	"time"
 )
 
@@ -54,3 +55,30 @@ func Exponential(baseDelay time.Duration, base float64) Strategy {
 func BinaryExponential(baseDelay time.Duration) Strategy {
 	return Exponential(baseDelay, 2)
 }
+
+// DecorrelatedJitter returns a strategy that randomly picks a delay between the
+// base delay and a window that grows by a factor of 3 each attempt, capped at the
+// max delay. Spreading out delays avoids retry storms from many clients failing
+// at the same time.
+//
+// delay = random(baseDelay, min(maxDelay, baseDelay * 3^(attempts - 1)))
+//
+// Unlike the classic formulation, the window is derived from the attempt rather
+// than the previous delay, so the strategy is stateless and can be shared across
+// concurrent retriers.
+func DecorrelatedJitter(baseDelay, maxDelay time.Duration) Strategy {
+	window := Exponential(baseDelay, 3)
+
+	return func(attempts uint) time.Duration {
+		upper := window(attempts)
+		if upper > maxDelay {
+			upper = maxDelay
+		}
+
+		if upper <= baseDelay {
+			return upper
+		}
+
+		return baseDelay + time.Duration(rand.Int63n(int64(upper-baseDelay)+1))
+	}
+}
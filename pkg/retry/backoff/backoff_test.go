@@ -42,3 +42,27 @@ func TestBinaryExponential(t *testing.T) {
 		assert.Equal(t, exp(i), binExp(i))
 	}
 }
+
+func TestDecorrelatedJitter(t *testing.T) {
+	s := DecorrelatedJitter(time.Second, 20*time.Second)
+
+	for i := 0; i < 100; i++ {
+		assert.Equal(t, time.Second, s(1))
+
+		delay := s(2)
+		assert.True(t, delay >= time.Second && delay <= 3*time.Second)
+
+		delay = s(3)
+		assert.True(t, delay >= time.Second && delay <= 9*time.Second)
+
+		delay = s(10)
+		assert.True(t, delay >= time.Second && delay <= 20*time.Second)
+	}
+
+	// Delays must actually be spread out
+	seen := make(map[time.Duration]struct{})
+	for i := 0; i < 100; i++ {
+		seen[s(5)] = struct{}{}
+	}
+	assert.True(t, len(seen) > 1)
+}
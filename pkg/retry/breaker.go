@@ -0,0 +1,335 @@
+package retry
+
+import (
+	"container/list"
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	defaultBreakerFailureThreshold = 5
+	defaultBreakerOpenDuration     = 30 * time.Second
+	defaultBreakerHalfOpenProbes   = 1
+)
+
+// ErrCircuitOpen indicates a call was rejected because the circuit breaker for
+// the dependency is open.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// BreakerState is the state of a circuit breaker
+type BreakerState uint8
+
+const (
+	// BreakerClosed allows all calls through
+	BreakerClosed BreakerState = iota
+
+	// BreakerOpen rejects all calls until the open duration elapses
+	BreakerOpen
+
+	// BreakerHalfOpen allows a limited number of probe calls through to
+	// determine whether the dependency has recovered
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half_open"
+	}
+	return "unknown"
+}
+
+// BreakerOption configures a CircuitBreaker
+type BreakerOption func(*CircuitBreaker)
+
+// WithFailureThreshold configures the number of consecutive failures that opens
+// the circuit breaker.
+func WithFailureThreshold(threshold uint) BreakerOption {
+	return func(b *CircuitBreaker) {
+		b.failureThreshold = threshold
+	}
+}
+
+// WithOpenDuration configures how long the circuit breaker stays open before
+// allowing probe calls through.
+func WithOpenDuration(duration time.Duration) BreakerOption {
+	return func(b *CircuitBreaker) {
+		b.openDuration = duration
+	}
+}
+
+// WithHalfOpenProbes configures the number of concurrent probe calls allowed
+// while half open. The same number of successful probes closes the circuit
+// breaker.
+func WithHalfOpenProbes(probes uint) BreakerOption {
+	return func(b *CircuitBreaker) {
+		b.halfOpenProbes = probes
+	}
+}
+
+// WithStateChangeHook configures a function that's called whenever the circuit
+// breaker changes state. It's called while holding the breaker's lock, so it must
+// not call back into the breaker.
+func WithStateChangeHook(hook func(name string, from, to BreakerState)) BreakerOption {
+	return func(b *CircuitBreaker) {
+		b.onStateChange = hook
+	}
+}
+
+// CircuitBreaker stops calls to a dependency after consecutive failures, giving
+// it time to recover instead of piling on more load. After the open duration, a
+// limited number of probe calls are let through, and the breaker closes once
+// they succeed.
+//
+// Callers must report the outcome of every allowed call via OnSuccess, OnFailure
+// or OnAbandoned.
+type CircuitBreaker struct {
+	name             string
+	failureThreshold uint
+	openDuration     time.Duration
+	halfOpenProbes   uint
+	onStateChange    func(name string, from, to BreakerState)
+	now              func() time.Time
+
+	mu                  sync.Mutex
+	state               BreakerState
+	consecutiveFailures uint
+	openedAt            time.Time
+	inFlightProbes      uint
+	successfulProbes    uint
+}
+
+// NewCircuitBreaker returns a new circuit breaker for the named dependency.
+func NewCircuitBreaker(name string, opts ...BreakerOption) *CircuitBreaker {
+	b := &CircuitBreaker{
+		name:             name,
+		failureThreshold: defaultBreakerFailureThreshold,
+		openDuration:     defaultBreakerOpenDuration,
+		halfOpenProbes:   defaultBreakerHalfOpenProbes,
+		now:              time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	if b.failureThreshold == 0 {
+		b.failureThreshold = 1
+	}
+	if b.halfOpenProbes == 0 {
+		b.halfOpenProbes = 1
+	}
+
+	return b
+}
+
+// Name returns the name of the dependency protected by the circuit breaker
+func (b *CircuitBreaker) Name() string {
+	return b.name
+}
+
+// State returns the current state of the circuit breaker
+func (b *CircuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.maybeHalfOpen()
+	return b.state
+}
+
+// Allow returns ErrCircuitOpen if the call should not be made
+func (b *CircuitBreaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.maybeHalfOpen()
+
+	switch b.state {
+	case BreakerOpen:
+		return ErrCircuitOpen
+	case BreakerHalfOpen:
+		if b.inFlightProbes >= b.halfOpenProbes {
+			return ErrCircuitOpen
+		}
+		b.inFlightProbes++
+	}
+
+	return nil
+}
+
+// OnSuccess records an allowed call that succeeded
+func (b *CircuitBreaker) OnSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerClosed:
+		b.consecutiveFailures = 0
+	case BreakerHalfOpen:
+		b.releaseProbe()
+		b.successfulProbes++
+		if b.successfulProbes >= b.halfOpenProbes {
+			b.setState(BreakerClosed)
+		}
+	}
+}
+
+// OnFailure records an allowed call that failed
+func (b *CircuitBreaker) OnFailure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerClosed:
+		b.consecutiveFailures++
+		if b.consecutiveFailures >= b.failureThreshold {
+			b.setState(BreakerOpen)
+		}
+	case BreakerHalfOpen:
+		b.releaseProbe()
+		b.setState(BreakerOpen)
+	}
+}
+
+// OnAbandoned records an allowed call whose outcome says nothing about the
+// health of the dependency, like a call cancelled by the caller.
+func (b *CircuitBreaker) OnAbandoned() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == BreakerHalfOpen {
+		b.releaseProbe()
+	}
+}
+
+func (b *CircuitBreaker) maybeHalfOpen() {
+	if b.state == BreakerOpen && !b.now().Before(b.openedAt.Add(b.openDuration)) {
+		b.setState(BreakerHalfOpen)
+	}
+}
+
+func (b *CircuitBreaker) releaseProbe() {
+	if b.inFlightProbes > 0 {
+		b.inFlightProbes--
+	}
+}
+
+func (b *CircuitBreaker) setState(state BreakerState) {
+	if b.state == state {
+		return
+	}
+
+	from := b.state
+	b.state = state
+
+	b.consecutiveFailures = 0
+	b.inFlightProbes = 0
+	b.successfulProbes = 0
+	if state == BreakerOpen {
+		b.openedAt = b.now()
+	}
+
+	if b.onStateChange != nil {
+		b.onStateChange(b.name, from, state)
+	}
+}
+
+// BreakerSet lazily creates circuit breakers by key, for dependencies that are
+// made up of many independent endpoints, like third party webhook hosts.
+//
+// The set is bounded, since keys are often provided by third parties. Breakers
+// that haven't been used within the idle TTL are dropped, and the least recently
+// used breaker is evicted when the set is full. Dropped breakers are recreated
+// in the closed state.
+type BreakerSet struct {
+	opts        []BreakerOption
+	maxBreakers int
+	idleTTL     time.Duration
+	now         func() time.Time
+
+	mu       sync.Mutex
+	breakers map[string]*list.Element
+	lru      *list.List
+}
+
+type breakerSetEntry struct {
+	key        string
+	breaker    *CircuitBreaker
+	lastUsedAt time.Time
+}
+
+// NewBreakerSet returns a new BreakerSet holding at most maxBreakers circuit
+// breakers, where every circuit breaker is configured with the provided options.
+func NewBreakerSet(maxBreakers int, idleTTL time.Duration, opts ...BreakerOption) *BreakerSet {
+	if maxBreakers <= 0 {
+		maxBreakers = 1
+	}
+
+	return &BreakerSet{
+		opts:        opts,
+		maxBreakers: maxBreakers,
+		idleTTL:     idleTTL,
+		now:         time.Now,
+		breakers:    make(map[string]*list.Element),
+		lru:         list.New(),
+	}
+}
+
+// Get gets the circuit breaker for the key, creating it if it doesn't exist
+func (s *BreakerSet) Get(key string) *CircuitBreaker {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.evictIdle(now)
+
+	if element, ok := s.breakers[key]; ok {
+		entry := element.Value.(*breakerSetEntry)
+		entry.lastUsedAt = now
+		s.lru.MoveToFront(element)
+		return entry.breaker
+	}
+
+	for s.lru.Len() >= s.maxBreakers {
+		s.remove(s.lru.Back())
+	}
+
+	entry := &breakerSetEntry{
+		key:        key,
+		breaker:    NewCircuitBreaker(key, s.opts...),
+		lastUsedAt: now,
+	}
+	s.breakers[key] = s.lru.PushFront(entry)
+	return entry.breaker
+}
+
+// Len returns the number of circuit breakers in the set
+func (s *BreakerSet) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.lru.Len()
+}
+
+func (s *BreakerSet) evictIdle(now time.Time) {
+	if s.idleTTL <= 0 {
+		return
+	}
+
+	for element := s.lru.Back(); element != nil; element = s.lru.Back() {
+		if now.Sub(element.Value.(*breakerSetEntry).lastUsedAt) < s.idleTTL {
+			return
+		}
+		s.remove(element)
+	}
+}
+
+func (s *BreakerSet) remove(element *list.Element) {
+	entry := s.lru.Remove(element).(*breakerSetEntry)
+	delete(s.breakers, entry.key)
+}
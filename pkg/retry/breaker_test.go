@@ -0,0 +1,159 @@
+package retry
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCircuitBreaker_HappyPath(t *testing.T) {
+	clock := &testClock{now: time.Now()}
+
+	var transitions []BreakerState
+	b := NewCircuitBreaker(
+		"test",
+		WithFailureThreshold(3),
+		WithOpenDuration(time.Minute),
+		WithStateChangeHook(func(name string, from, to BreakerState) {
+			assert.Equal(t, "test", name)
+			transitions = append(transitions, to)
+		}),
+	)
+	b.now = clock.Now
+
+	assert.Equal(t, BreakerClosed, b.State())
+
+	// Successes reset the consecutive failure count
+	for i := 0; i < 2; i++ {
+		require.NoError(t, b.Allow())
+		b.OnFailure()
+	}
+	require.NoError(t, b.Allow())
+	b.OnSuccess()
+
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, BreakerClosed, b.State())
+		require.NoError(t, b.Allow())
+		b.OnFailure()
+	}
+	assert.Equal(t, BreakerOpen, b.State())
+	assert.Equal(t, ErrCircuitOpen, b.Allow())
+
+	clock.Advance(time.Minute - time.Second)
+	assert.Equal(t, ErrCircuitOpen, b.Allow())
+
+	// A failed probe re-opens the breaker
+	clock.Advance(time.Second)
+	assert.Equal(t, BreakerHalfOpen, b.State())
+	require.NoError(t, b.Allow())
+	assert.Equal(t, ErrCircuitOpen, b.Allow())
+	b.OnFailure()
+	assert.Equal(t, BreakerOpen, b.State())
+	assert.Equal(t, ErrCircuitOpen, b.Allow())
+
+	// An abandoned probe frees up the slot without closing the breaker
+	clock.Advance(time.Minute)
+	require.NoError(t, b.Allow())
+	b.OnAbandoned()
+	assert.Equal(t, BreakerHalfOpen, b.State())
+
+	// A successful probe closes the breaker
+	require.NoError(t, b.Allow())
+	b.OnSuccess()
+	assert.Equal(t, BreakerClosed, b.State())
+	require.NoError(t, b.Allow())
+
+	assert.Equal(t, []BreakerState{BreakerOpen, BreakerHalfOpen, BreakerOpen, BreakerHalfOpen, BreakerClosed}, transitions)
+}
+
+func TestCircuitBreaker_MultipleProbes(t *testing.T) {
+	clock := &testClock{now: time.Now()}
+
+	b := NewCircuitBreaker("test", WithFailureThreshold(1), WithOpenDuration(time.Minute), WithHalfOpenProbes(2))
+	b.now = clock.Now
+
+	require.NoError(t, b.Allow())
+	b.OnFailure()
+	assert.Equal(t, BreakerOpen, b.State())
+
+	clock.Advance(time.Minute)
+	require.NoError(t, b.Allow())
+	require.NoError(t, b.Allow())
+	assert.Equal(t, ErrCircuitOpen, b.Allow())
+
+	b.OnSuccess()
+	assert.Equal(t, BreakerHalfOpen, b.State())
+
+	require.NoError(t, b.Allow())
+	b.OnSuccess()
+	assert.Equal(t, BreakerClosed, b.State())
+}
+
+func TestBreakerSet(t *testing.T) {
+	s := NewBreakerSet(10, time.Minute, WithFailureThreshold(1))
+
+	b1 := s.Get("host1")
+	b2 := s.Get("host2")
+	assert.Equal(t, b1, s.Get("host1"))
+	assert.NotEqual(t, b1, b2)
+	assert.Equal(t, "host1", b1.Name())
+
+	require.NoError(t, b1.Allow())
+	b1.OnFailure()
+	assert.Equal(t, BreakerOpen, b1.State())
+	assert.Equal(t, BreakerClosed, b2.State())
+}
+
+func TestBreakerSet_Bounded(t *testing.T) {
+	clock := &testClock{now: time.Now()}
+
+	s := NewBreakerSet(2, time.Minute, WithFailureThreshold(1))
+	s.now = clock.Now
+
+	b1 := s.Get("host1")
+	b2 := s.Get("host2")
+
+	require.NoError(t, b1.Allow())
+	b1.OnFailure()
+	assert.Equal(t, BreakerOpen, b1.State())
+
+	// Using host1 makes host2 the least recently used breaker
+	clock.Advance(time.Second)
+	assert.Equal(t, b1, s.Get("host1"))
+
+	b3 := s.Get("host3")
+	assert.Equal(t, 2, s.Len())
+	assert.Equal(t, b1, s.Get("host1"))
+	assert.Equal(t, b3, s.Get("host3"))
+	assert.NotEqual(t, b2, s.Get("host2"))
+	assert.Equal(t, 2, s.Len())
+
+	// Idle breakers are dropped, and recreated in the closed state
+	clock.Advance(time.Minute)
+	recreated := s.Get("host1")
+	assert.NotEqual(t, b1, recreated)
+	assert.Equal(t, BreakerClosed, recreated.State())
+	assert.Equal(t, 1, s.Len())
+}
+
+type testClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *testClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
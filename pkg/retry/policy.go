@@ -0,0 +1,245 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/code-payments/code-server/pkg/metrics"
+)
+
+const (
+	outboundCallFailedEventName           = "OutboundCallFailed"
+	outboundRetryBudgetExhaustedEventName = "OutboundRetryBudgetExhausted"
+)
+
+// Budget limits retries to a dependency, so a struggling dependency doesn't see
+// its load multiplied by retries. Failed calls withdraw a token, successful calls
+// deposit a fraction of a token, and retries are only allowed while more than half
+// of the tokens remain. This mirrors gRPC retry throttling.
+//
+// A Budget should be shared by everything that calls the same dependency.
+type Budget struct {
+	maxTokens  float64
+	tokenRatio float64
+
+	mu     sync.Mutex
+	tokens float64
+}
+
+// NewBudget returns a new Budget that starts full
+func NewBudget(maxTokens, tokenRatio float64) *Budget {
+	return &Budget{
+		maxTokens:  maxTokens,
+		tokenRatio: tokenRatio,
+		tokens:     maxTokens,
+	}
+}
+
+func (b *Budget) onSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens += b.tokenRatio
+	if b.tokens > b.maxTokens {
+		b.tokens = b.maxTokens
+	}
+}
+
+func (b *Budget) onFailure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens--
+	if b.tokens < 0 {
+		b.tokens = 0
+	}
+}
+
+func (b *Budget) allowRetry() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.tokens > b.maxTokens/2
+}
+
+// Hooks are called as a Policy executes actions. Any hook may be nil.
+type Hooks struct {
+	// OnAttempt is called after every attempt, including successful ones and
+	// ones rejected by the circuit breaker.
+	OnAttempt func(ctx context.Context, dependency string, attempt uint, err error)
+
+	// OnBudgetExhausted is called when a retry is skipped because the budget
+	// is exhausted.
+	OnBudgetExhausted func(ctx context.Context, dependency string)
+}
+
+// MetricsHooks returns hooks that record failed calls and exhausted budgets as
+// metrics events.
+func MetricsHooks() Hooks {
+	return Hooks{
+		OnAttempt: func(ctx context.Context, dependency string, attempt uint, err error) {
+			if err == nil {
+				return
+			}
+
+			metrics.RecordEvent(ctx, outboundCallFailedEventName, map[string]interface{}{
+				"dependency":   dependency,
+				"attempt":      attempt,
+				"circuit_open": errors.Is(err, ErrCircuitOpen),
+				"error":        err.Error(),
+			})
+		},
+		OnBudgetExhausted: func(ctx context.Context, dependency string) {
+			metrics.RecordEvent(ctx, outboundRetryBudgetExhaustedEventName, map[string]interface{}{
+				"dependency": dependency,
+			})
+		},
+	}
+}
+
+// PolicyOption configures a Policy
+type PolicyOption func(*Policy)
+
+// WithStrategies configures the strategies that determine whether a failed
+// action is retried. They have the same semantics as in Retry.
+func WithStrategies(strategies ...Strategy) PolicyOption {
+	return func(p *Policy) {
+		p.strategies = append(p.strategies, strategies...)
+	}
+}
+
+// WithCircuitBreaker configures a circuit breaker that's consulted before every
+// attempt.
+func WithCircuitBreaker(breaker *CircuitBreaker) PolicyOption {
+	return func(p *Policy) {
+		p.breaker = breaker
+	}
+}
+
+// WithBudget configures a retry budget
+func WithBudget(budget *Budget) PolicyOption {
+	return func(p *Policy) {
+		p.budget = budget
+	}
+}
+
+// WithFailureClassifier configures which errors indicate the dependency is
+// unhealthy, and count against the circuit breaker and budget. By default, all
+// errors are failures. Errors wrapping context.Canceled are never failures.
+func WithFailureClassifier(isFailure func(err error) bool) PolicyOption {
+	return func(p *Policy) {
+		p.isFailure = isFailure
+	}
+}
+
+// WithHooks configures hooks, typically for metrics
+func WithHooks(hooks Hooks) PolicyOption {
+	return func(p *Policy) {
+		p.hooks = hooks
+	}
+}
+
+// Policy is a reusable resilience policy for calls to an outbound dependency. It
+// combines retry strategies with an optional circuit breaker, retry budget and
+// metrics hooks.
+type Policy struct {
+	dependency string
+	strategies []Strategy
+	breaker    *CircuitBreaker
+	budget     *Budget
+	isFailure  func(err error) bool
+	hooks      Hooks
+}
+
+// NewPolicy returns a new Policy for the named dependency. Without options, it
+// behaves like NewRetrier with no strategies.
+func NewPolicy(dependency string, opts ...PolicyOption) *Policy {
+	p := &Policy{
+		dependency: dependency,
+		isFailure: func(err error) bool {
+			return true
+		},
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// Retry implements Retrier.Retry
+func (p *Policy) Retry(action Action) (uint, error) {
+	return p.Do(context.Background(), action)
+}
+
+// Do executes the provided action under the policy. The context is only used for
+// hooks, and actions are expected to observe it themselves.
+func (p *Policy) Do(ctx context.Context, action Action) (uint, error) {
+	for i := uint(1); ; i++ {
+		if p.breaker != nil {
+			if err := p.breaker.Allow(); err != nil {
+				p.onAttempt(ctx, i, err)
+				return i, err
+			}
+		}
+
+		err := action()
+		p.record(err)
+		p.onAttempt(ctx, i, err)
+
+		if err == nil {
+			return i, nil
+		}
+
+		// Checked before strategies, so we don't back off only to give up
+		if p.budget != nil && !p.budget.allowRetry() {
+			if p.hooks.OnBudgetExhausted != nil {
+				p.hooks.OnBudgetExhausted(ctx, p.dependency)
+			}
+			return i, err
+		}
+
+		for _, s := range p.strategies {
+			if shouldRetry := s(i, err); !shouldRetry {
+				return i, err
+			}
+		}
+	}
+}
+
+func (p *Policy) record(err error) {
+	// A cancelled call says nothing about the health of the dependency
+	if errors.Is(err, context.Canceled) {
+		if p.breaker != nil {
+			p.breaker.OnAbandoned()
+		}
+		return
+	}
+
+	isFailure := err != nil && p.isFailure(err)
+
+	if p.breaker != nil {
+		if isFailure {
+			p.breaker.OnFailure()
+		} else {
+			// The dependency responded, even if the call resulted in an error
+			p.breaker.OnSuccess()
+		}
+	}
+
+	if p.budget != nil {
+		if isFailure {
+			p.budget.onFailure()
+		} else {
+			p.budget.onSuccess()
+		}
+	}
+}
+
+func (p *Policy) onAttempt(ctx context.Context, attempt uint, err error) {
+	if p.hooks.OnAttempt != nil {
+		p.hooks.OnAttempt(ctx, p.dependency, attempt, err)
+	}
+}
@@ -0,0 +1,130 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/code-payments/code-server/pkg/retry/backoff"
+)
+
+func TestPolicy_Strategies(t *testing.T) {
+	ts := &testSleeper{}
+	sleeperImpl = ts
+
+	var attempts []uint
+	p := NewPolicy(
+		"test",
+		WithStrategies(Limit(3), Backoff(backoff.Constant(time.Second), time.Second)),
+		WithHooks(Hooks{
+			OnAttempt: func(ctx context.Context, dependency string, attempt uint, err error) {
+				assert.Equal(t, "test", dependency)
+				attempts = append(attempts, attempt)
+			},
+		}),
+	)
+
+	n, err := p.Retry(func() error { return errors.New("err") })
+	assert.Error(t, err)
+	assert.EqualValues(t, 3, n)
+	assert.Equal(t, []uint{1, 2, 3}, attempts)
+	assert.Equal(t, []time.Duration{time.Second, time.Second}, ts.sleepTimes)
+
+	var i int
+	n, err = p.Retry(func() error {
+		i++
+		if i < 2 {
+			return errors.New("err")
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, n)
+}
+
+func TestPolicy_CircuitBreaker(t *testing.T) {
+	sleeperImpl = &testSleeper{}
+
+	clientErr := errors.New("client error")
+	serverErr := errors.New("server error")
+
+	breaker := NewCircuitBreaker("test", WithFailureThreshold(2), WithOpenDuration(time.Hour))
+	p := NewPolicy(
+		"test",
+		WithStrategies(Limit(5)),
+		WithCircuitBreaker(breaker),
+		WithFailureClassifier(func(err error) bool {
+			return err == serverErr
+		}),
+	)
+
+	// Errors that aren't failures don't trip the breaker
+	for i := 0; i < 5; i++ {
+		_, err := p.Retry(func() error { return clientErr })
+		assert.Equal(t, clientErr, err)
+	}
+	assert.Equal(t, BreakerClosed, breaker.State())
+
+	// Cancellations don't trip the breaker
+	_, err := p.Retry(func() error { return context.Canceled })
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, BreakerClosed, breaker.State())
+
+	var calls int
+	n, err := p.Retry(func() error {
+		calls++
+		return serverErr
+	})
+	assert.Equal(t, ErrCircuitOpen, err)
+	assert.EqualValues(t, 3, n)
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, BreakerOpen, breaker.State())
+
+	_, err = p.Retry(func() error {
+		require.Fail(t, "action called while circuit is open")
+		return nil
+	})
+	assert.Equal(t, ErrCircuitOpen, err)
+}
+
+func TestPolicy_Budget(t *testing.T) {
+	sleeperImpl = &testSleeper{}
+
+	var exhausted int
+	budget := NewBudget(4, 1)
+	p := NewPolicy(
+		"test",
+		WithStrategies(Limit(10)),
+		WithBudget(budget),
+		WithHooks(Hooks{
+			OnBudgetExhausted: func(ctx context.Context, dependency string) {
+				exhausted++
+			},
+		}),
+	)
+
+	// Retries stop once half the tokens are used
+	n, err := p.Retry(func() error { return errors.New("err") })
+	assert.Error(t, err)
+	assert.EqualValues(t, 2, n)
+	assert.Equal(t, 1, exhausted)
+
+	// No retries until successes replenish the budget
+	n, err = p.Retry(func() error { return errors.New("err") })
+	assert.Error(t, err)
+	assert.EqualValues(t, 1, n)
+	assert.Equal(t, 2, exhausted)
+
+	for i := 0; i < 4; i++ {
+		_, err = p.Retry(func() error { return nil })
+		require.NoError(t, err)
+	}
+
+	n, err = p.Retry(func() error { return errors.New("err") })
+	assert.Error(t, err)
+	assert.EqualValues(t, 2, n)
+}
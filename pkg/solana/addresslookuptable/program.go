@@ -0,0 +1,254 @@
+package address_lookup_table
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+
+	"github.com/code-payments/code-server/pkg/solana"
+	"github.com/code-payments/code-server/pkg/solana/system"
+)
+
+// ProgramKey is the address of the address lookup table program.
+//
+// Current key: AddressLookupTab1e1111111111111111111111111
+var ProgramKey = ed25519.PublicKey{2, 119, 166, 175, 151, 51, 155, 122, 200, 141, 24, 146, 201, 4, 70, 245, 0, 2, 48, 146, 102, 246, 46, 83, 193, 24, 36, 73, 130, 0, 0, 0}
+
+const (
+	// MaxAddresses is the maximum number of addresses that can be stored in a
+	// single lookup table.
+	MaxAddresses = 256
+)
+
+const (
+	commandCreateLookupTable uint32 = iota
+	// nolint:varcheck,deadcode,unused
+	commandFreezeLookupTable
+	commandExtendLookupTable
+	commandDeactivateLookupTable
+	commandCloseLookupTable
+)
+
+// GetTableAddress returns the address of the lookup table created by the
+// authority at the provided recent slot.
+//
+// Reference: https://github.com/solana-labs/solana/blob/master/sdk/program/src/address_lookup_table/instruction.rs
+func GetTableAddress(authority ed25519.PublicKey, recentSlot uint64) (ed25519.PublicKey, uint8, error) {
+	var slot [8]byte
+	binary.LittleEndian.PutUint64(slot[:], recentSlot)
+
+	return solana.FindProgramAddressAndBump(ProgramKey, authority, slot[:])
+}
+
+// Reference: https://github.com/solana-labs/solana/blob/master/sdk/program/src/address_lookup_table/instruction.rs
+func CreateLookupTable(authority, payer ed25519.PublicKey, recentSlot uint64) (solana.Instruction, ed25519.PublicKey, error) {
+	// # Account references
+	//   0. [WRITE] Uninitialized address lookup table account
+	//   1. [SIGNER] Account used to derive and control the new address lookup table
+	//   2. [SIGNER, WRITE] Account that will fund the new address lookup table
+	//   3. [] System program for CPI
+	//
+	// CreateLookupTable {
+	//   recent_slot: Slot,
+	//   bump_seed: u8,
+	// }
+	address, bump, err := GetTableAddress(authority, recentSlot)
+	if err != nil {
+		return solana.Instruction{}, nil, err
+	}
+
+	data := make([]byte, 4+8+1)
+	binary.LittleEndian.PutUint32(data, commandCreateLookupTable)
+	binary.LittleEndian.PutUint64(data[4:], recentSlot)
+	data[4+8] = bump
+
+	return solana.NewInstruction(
+		ProgramKey,
+		data,
+		solana.NewAccountMeta(address, false),
+		solana.NewReadonlyAccountMeta(authority, true),
+		solana.NewAccountMeta(payer, true),
+		solana.NewReadonlyAccountMeta(system.ProgramKey[:], false),
+	), address, nil
+}
+
+type DecompiledCreateLookupTable struct {
+	Address   ed25519.PublicKey
+	Authority ed25519.PublicKey
+	Payer     ed25519.PublicKey
+
+	RecentSlot uint64
+	Bump       uint8
+}
+
+func DecompileCreateLookupTable(m solana.Message, index int) (*DecompiledCreateLookupTable, error) {
+	i, err := getInstruction(m, index, commandCreateLookupTable)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(i.Accounts) != 4 {
+		return nil, errors.Errorf("invalid number of accounts: %d", len(i.Accounts))
+	}
+	if len(i.Data) != 4+8+1 {
+		return nil, errors.Errorf("invalid instruction data size: %d", len(i.Data))
+	}
+
+	return &DecompiledCreateLookupTable{
+		Address:    m.Accounts[i.Accounts[0]],
+		Authority:  m.Accounts[i.Accounts[1]],
+		Payer:      m.Accounts[i.Accounts[2]],
+		RecentSlot: binary.LittleEndian.Uint64(i.Data[4:]),
+		Bump:       i.Data[4+8],
+	}, nil
+}
+
+// Reference: https://github.com/solana-labs/solana/blob/master/sdk/program/src/address_lookup_table/instruction.rs
+func ExtendLookupTable(address, authority, payer ed25519.PublicKey, newAddresses []ed25519.PublicKey) solana.Instruction {
+	// # Account references
+	//   0. [WRITE] Address lookup table account to extend
+	//   1. [SIGNER] Current authority
+	//   2. [SIGNER, WRITE, OPTIONAL] Account that will fund the table reallocation
+	//   3. [OPTIONAL] System program for CPI.
+	//
+	// ExtendLookupTable {
+	//   new_addresses: Vec<Pubkey>,
+	// }
+	data := make([]byte, 4+8+len(newAddresses)*ed25519.PublicKeySize)
+	binary.LittleEndian.PutUint32(data, commandExtendLookupTable)
+	binary.LittleEndian.PutUint64(data[4:], uint64(len(newAddresses)))
+	for i, newAddress := range newAddresses {
+		copy(data[4+8+i*ed25519.PublicKeySize:], newAddress)
+	}
+
+	return solana.NewInstruction(
+		ProgramKey,
+		data,
+		solana.NewAccountMeta(address, false),
+		solana.NewReadonlyAccountMeta(authority, true),
+		solana.NewAccountMeta(payer, true),
+		solana.NewReadonlyAccountMeta(system.ProgramKey[:], false),
+	)
+}
+
+type DecompiledExtendLookupTable struct {
+	Address   ed25519.PublicKey
+	Authority ed25519.PublicKey
+	Payer     ed25519.PublicKey
+
+	NewAddresses []ed25519.PublicKey
+}
+
+func DecompileExtendLookupTable(m solana.Message, index int) (*DecompiledExtendLookupTable, error) {
+	i, err := getInstruction(m, index, commandExtendLookupTable)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(i.Accounts) != 4 {
+		return nil, errors.Errorf("invalid number of accounts: %d", len(i.Accounts))
+	}
+	if len(i.Data) < 4+8 {
+		return nil, errors.Errorf("invalid instruction data size: %d", len(i.Data))
+	}
+
+	numAddresses := binary.LittleEndian.Uint64(i.Data[4:])
+	if uint64(len(i.Data)) != 4+8+numAddresses*ed25519.PublicKeySize {
+		return nil, errors.Errorf("invalid instruction data size: %d", len(i.Data))
+	}
+
+	v := &DecompiledExtendLookupTable{
+		Address:   m.Accounts[i.Accounts[0]],
+		Authority: m.Accounts[i.Accounts[1]],
+		Payer:     m.Accounts[i.Accounts[2]],
+	}
+	for j := 0; j < int(numAddresses); j++ {
+		newAddress := make(ed25519.PublicKey, ed25519.PublicKeySize)
+		copy(newAddress, i.Data[4+8+j*ed25519.PublicKeySize:])
+		v.NewAddresses = append(v.NewAddresses, newAddress)
+	}
+
+	return v, nil
+}
+
+// Reference: https://github.com/solana-labs/solana/blob/master/sdk/program/src/address_lookup_table/instruction.rs
+func DeactivateLookupTable(address, authority ed25519.PublicKey) solana.Instruction {
+	// # Account references
+	//   0. [WRITE] Address lookup table account to deactivate
+	//   1. [SIGNER] Current authority
+	data := make([]byte, 4)
+	binary.LittleEndian.PutUint32(data, commandDeactivateLookupTable)
+
+	return solana.NewInstruction(
+		ProgramKey,
+		data,
+		solana.NewAccountMeta(address, false),
+		solana.NewReadonlyAccountMeta(authority, true),
+	)
+}
+
+type DecompiledDeactivateLookupTable struct {
+	Address   ed25519.PublicKey
+	Authority ed25519.PublicKey
+}
+
+func DecompileDeactivateLookupTable(m solana.Message, index int) (*DecompiledDeactivateLookupTable, error) {
+	i, err := getInstruction(m, index, commandDeactivateLookupTable)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(i.Accounts) != 2 {
+		return nil, errors.Errorf("invalid number of accounts: %d", len(i.Accounts))
+	}
+	if len(i.Data) != 4 {
+		return nil, errors.Errorf("invalid instruction data size: %d", len(i.Data))
+	}
+
+	return &DecompiledDeactivateLookupTable{
+		Address:   m.Accounts[i.Accounts[0]],
+		Authority: m.Accounts[i.Accounts[1]],
+	}, nil
+}
+
+// CloseLookupTable returns an instruction to close a deactivated lookup table,
+// which can only be done once the deactivation slot is no longer a recent slot.
+//
+// Reference: https://github.com/solana-labs/solana/blob/master/sdk/program/src/address_lookup_table/instruction.rs
+func CloseLookupTable(address, authority, recipient ed25519.PublicKey) solana.Instruction {
+	// # Account references
+	//   0. [WRITE] Address lookup table account to close
+	//   1. [SIGNER] Current authority
+	//   2. [WRITE] Recipient of closed account lamports
+	data := make([]byte, 4)
+	binary.LittleEndian.PutUint32(data, commandCloseLookupTable)
+
+	return solana.NewInstruction(
+		ProgramKey,
+		data,
+		solana.NewAccountMeta(address, false),
+		solana.NewReadonlyAccountMeta(authority, true),
+		solana.NewAccountMeta(recipient, false),
+	)
+}
+
+func getInstruction(m solana.Message, index int, command uint32) (solana.CompiledInstruction, error) {
+	if err := m.CheckInstructionAccounts(index); err != nil {
+		return solana.CompiledInstruction{}, err
+	}
+
+	var prefix [4]byte
+	binary.LittleEndian.PutUint32(prefix[:], command)
+	i := m.Instructions[index]
+
+	if !bytes.Equal(m.Accounts[i.ProgramIndex], ProgramKey) {
+		return solana.CompiledInstruction{}, solana.ErrIncorrectProgram
+	}
+	if !bytes.HasPrefix(i.Data, prefix[:]) {
+		return solana.CompiledInstruction{}, solana.ErrIncorrectInstruction
+	}
+
+	return i, nil
+}
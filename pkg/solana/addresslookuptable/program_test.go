@@ -0,0 +1,169 @@
+package address_lookup_table
+
+import (
+	"crypto/ed25519"
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/mr-tron/base58/base58"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/code-payments/code-server/pkg/solana"
+	"github.com/code-payments/code-server/pkg/solana/system"
+)
+
+func TestProgramKey(t *testing.T) {
+	assert.Equal(t, "AddressLookupTab1e1111111111111111111111111", base58.Encode(ProgramKey))
+}
+
+func TestCreateLookupTable(t *testing.T) {
+	keys := generateKeys(t, 2)
+
+	instruction, address, err := CreateLookupTable(keys[0], keys[1], 12345)
+	require.NoError(t, err)
+
+	expectedAddress, bump, err := GetTableAddress(keys[0], 12345)
+	require.NoError(t, err)
+	assert.Equal(t, expectedAddress, address)
+
+	assert.Equal(t, ProgramKey, instruction.Program)
+	require.Len(t, instruction.Data, 13)
+	assert.EqualValues(t, commandCreateLookupTable, binary.LittleEndian.Uint32(instruction.Data))
+	assert.EqualValues(t, 12345, binary.LittleEndian.Uint64(instruction.Data[4:]))
+	assert.Equal(t, bump, instruction.Data[12])
+
+	var tx solana.Transaction
+	require.NoError(t, tx.Unmarshal(solana.NewTransaction(keys[1], instruction).Marshal()))
+
+	decompiled, err := DecompileCreateLookupTable(tx.Message, 0)
+	require.NoError(t, err)
+	assert.Equal(t, address, decompiled.Address)
+	assert.Equal(t, keys[0], decompiled.Authority)
+	assert.Equal(t, keys[1], decompiled.Payer)
+	assert.EqualValues(t, 12345, decompiled.RecentSlot)
+	assert.Equal(t, bump, decompiled.Bump)
+
+	_, err = DecompileExtendLookupTable(tx.Message, 0)
+	assert.Equal(t, solana.ErrIncorrectInstruction, err)
+}
+
+func TestExtendLookupTable(t *testing.T) {
+	keys := generateKeys(t, 6)
+
+	instruction := ExtendLookupTable(keys[0], keys[1], keys[2], keys[3:])
+
+	assert.Equal(t, ProgramKey, instruction.Program)
+	require.Len(t, instruction.Accounts, 4)
+	assert.EqualValues(t, system.ProgramKey[:], instruction.Accounts[3].PublicKey)
+	require.Len(t, instruction.Data, 4+8+3*32)
+	assert.EqualValues(t, commandExtendLookupTable, binary.LittleEndian.Uint32(instruction.Data))
+	assert.EqualValues(t, 3, binary.LittleEndian.Uint64(instruction.Data[4:]))
+
+	var tx solana.Transaction
+	require.NoError(t, tx.Unmarshal(solana.NewTransaction(keys[2], instruction).Marshal()))
+
+	decompiled, err := DecompileExtendLookupTable(tx.Message, 0)
+	require.NoError(t, err)
+	assert.Equal(t, keys[0], decompiled.Address)
+	assert.Equal(t, keys[1], decompiled.Authority)
+	assert.Equal(t, keys[2], decompiled.Payer)
+	assert.Equal(t, keys[3:], decompiled.NewAddresses)
+
+	tx.Message.Instructions[0].Data = tx.Message.Instructions[0].Data[:4+8+32]
+	_, err = DecompileExtendLookupTable(tx.Message, 0)
+	assert.Error(t, err)
+}
+
+func TestDeactivateLookupTable(t *testing.T) {
+	keys := generateKeys(t, 3)
+
+	instruction := DeactivateLookupTable(keys[0], keys[1])
+
+	assert.Equal(t, ProgramKey, instruction.Program)
+	assert.Equal(t, []byte{3, 0, 0, 0}, instruction.Data)
+
+	var tx solana.Transaction
+	require.NoError(t, tx.Unmarshal(solana.NewTransaction(keys[1], instruction).Marshal()))
+
+	decompiled, err := DecompileDeactivateLookupTable(tx.Message, 0)
+	require.NoError(t, err)
+	assert.Equal(t, keys[0], decompiled.Address)
+	assert.Equal(t, keys[1], decompiled.Authority)
+
+	tx = solana.NewTransaction(keys[1], solana.NewInstruction(keys[2], instruction.Data, instruction.Accounts...))
+	_, err = DecompileDeactivateLookupTable(tx.Message, 0)
+	assert.Equal(t, solana.ErrIncorrectProgram, err)
+
+	_, err = DecompileDeactivateLookupTable(tx.Message, 1)
+	assert.Error(t, err)
+}
+
+func TestCloseLookupTable(t *testing.T) {
+	keys := generateKeys(t, 3)
+
+	instruction := CloseLookupTable(keys[0], keys[1], keys[2])
+
+	assert.Equal(t, ProgramKey, instruction.Program)
+	assert.Equal(t, []byte{4, 0, 0, 0}, instruction.Data)
+	require.Len(t, instruction.Accounts, 3)
+	assert.True(t, instruction.Accounts[2].IsWritable)
+}
+
+func TestLookupTableAccount(t *testing.T) {
+	keys := generateKeys(t, 4)
+
+	expected := LookupTableAccount{
+		DeactivationSlot:           math.MaxUint64,
+		LastExtendedSlot:           100,
+		LastExtendedSlotStartIndex: 2,
+		Authority:                  keys[0],
+		Addresses:                  keys[1:],
+	}
+	assert.True(t, expected.IsActive())
+
+	marshalled := expected.Marshal()
+	require.Len(t, marshalled, MetadataSize+3*32)
+
+	var actual LookupTableAccount
+	require.NoError(t, actual.Unmarshal(marshalled))
+	assert.Equal(t, expected, actual)
+
+	table, err := GetLookupTableFromAccount(keys[3], solana.AccountInfo{
+		Data:  marshalled,
+		Owner: ProgramKey,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, keys[3], table.PublicKey)
+	assert.Equal(t, keys[1:], table.Addresses)
+
+	_, err = GetLookupTableFromAccount(keys[3], solana.AccountInfo{
+		Data:  marshalled,
+		Owner: keys[0],
+	})
+	assert.Equal(t, ErrInvalidAccountOwner, err)
+
+	frozen := LookupTableAccount{DeactivationSlot: 5}
+	require.NoError(t, actual.Unmarshal(frozen.Marshal()))
+	assert.Nil(t, actual.Authority)
+	assert.Empty(t, actual.Addresses)
+	assert.False(t, actual.IsActive())
+
+	assert.Equal(t, ErrInvalidAccountSize, actual.Unmarshal(marshalled[:MetadataSize+1]))
+
+	marshalled[0] = 0
+	assert.Equal(t, ErrInvalidAccountType, actual.Unmarshal(marshalled))
+}
+
+func generateKeys(t *testing.T, amount int) []ed25519.PublicKey {
+	keys := make([]ed25519.PublicKey, amount)
+
+	for i := 0; i < amount; i++ {
+		pub, _, err := ed25519.GenerateKey(nil)
+		require.NoError(t, err)
+		keys[i] = pub
+	}
+
+	return keys
+}
@@ -0,0 +1,123 @@
+package address_lookup_table
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/binary"
+	"math"
+
+	"github.com/pkg/errors"
+
+	"github.com/code-payments/code-server/pkg/solana"
+)
+
+const (
+	// MetadataSize is the size of the lookup table metadata that precedes the
+	// stored addresses.
+	MetadataSize = 56
+
+	lookupTableAccountType uint32 = 1
+)
+
+var (
+	ErrInvalidAccountSize  = errors.New("invalid address lookup table account size")
+	ErrInvalidAccountType  = errors.New("invalid address lookup table account type")
+	ErrInvalidAccountOwner = errors.New("invalid address lookup table account owner")
+)
+
+// LookupTableAccount is the state of an on-chain address lookup table.
+//
+// Reference: https://github.com/solana-labs/solana/blob/master/sdk/program/src/address_lookup_table/state.rs
+type LookupTableAccount struct {
+	// DeactivationSlot is math.MaxUint64 while the table is active
+	DeactivationSlot           uint64
+	LastExtendedSlot           uint64
+	LastExtendedSlotStartIndex uint8
+	// Authority is nil when the table has been frozen
+	Authority ed25519.PublicKey
+	Addresses []ed25519.PublicKey
+}
+
+// IsActive returns whether the lookup table has not been deactivated
+func (obj *LookupTableAccount) IsActive() bool {
+	return obj.DeactivationSlot == math.MaxUint64
+}
+
+// ToAddressLookupTable converts the account state into a lookup table that can
+// be used to compile versioned transactions.
+func (obj *LookupTableAccount) ToAddressLookupTable(address ed25519.PublicKey) solana.AddressLookupTable {
+	return solana.AddressLookupTable{
+		PublicKey: address,
+		Addresses: obj.Addresses,
+	}
+}
+
+func (obj LookupTableAccount) Marshal() []byte {
+	res := make([]byte, MetadataSize+len(obj.Addresses)*ed25519.PublicKeySize)
+
+	// (4)               u32: account type
+	// (8)               u64: deactivation slot
+	// (8)               u64: last extended slot
+	// (1)                u8: last extended slot start index
+	// (1+32) Option<Pubkey>: authority
+	// (2)               u16: padding
+	binary.LittleEndian.PutUint32(res, lookupTableAccountType)
+	binary.LittleEndian.PutUint64(res[4:], obj.DeactivationSlot)
+	binary.LittleEndian.PutUint64(res[12:], obj.LastExtendedSlot)
+	res[20] = obj.LastExtendedSlotStartIndex
+	if len(obj.Authority) > 0 {
+		res[21] = 1
+		copy(res[22:], obj.Authority)
+	}
+
+	for i, address := range obj.Addresses {
+		copy(res[MetadataSize+i*ed25519.PublicKeySize:], address)
+	}
+
+	return res
+}
+
+func (obj *LookupTableAccount) Unmarshal(data []byte) error {
+	if len(data) < MetadataSize || (len(data)-MetadataSize)%ed25519.PublicKeySize != 0 {
+		return ErrInvalidAccountSize
+	}
+
+	if binary.LittleEndian.Uint32(data) != lookupTableAccountType {
+		return ErrInvalidAccountType
+	}
+
+	obj.DeactivationSlot = binary.LittleEndian.Uint64(data[4:])
+	obj.LastExtendedSlot = binary.LittleEndian.Uint64(data[12:])
+	obj.LastExtendedSlotStartIndex = data[20]
+
+	obj.Authority = nil
+	if data[21] == 1 {
+		obj.Authority = make(ed25519.PublicKey, ed25519.PublicKeySize)
+		copy(obj.Authority, data[22:])
+	}
+
+	numAddresses := (len(data) - MetadataSize) / ed25519.PublicKeySize
+	obj.Addresses = make([]ed25519.PublicKey, numAddresses)
+	for i := 0; i < numAddresses; i++ {
+		obj.Addresses[i] = make(ed25519.PublicKey, ed25519.PublicKeySize)
+		copy(obj.Addresses[i], data[MetadataSize+i*ed25519.PublicKeySize:])
+	}
+
+	return nil
+}
+
+// GetLookupTableFromAccount parses the address lookup table stored in the
+// provided account.
+func GetLookupTableFromAccount(address ed25519.PublicKey, info solana.AccountInfo) (*solana.AddressLookupTable, error) {
+	if !bytes.Equal(info.Owner, ProgramKey) {
+		return nil, ErrInvalidAccountOwner
+	}
+
+	var account LookupTableAccount
+	if err := account.Unmarshal(info.Data); err != nil {
+		return nil, err
+	}
+
+	table := account.ToAddressLookupTable(address)
+	return &table, nil
+}
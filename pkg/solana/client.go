@@ -20,6 +20,12 @@ import (
 	"github.com/code-payments/code-server/pkg/retry/backoff"
 )
 
+const (
+	rpcDependencyName        = "solana_rpc"
+	rpcRetryBudgetTokens     = 10
+	rpcRetryBudgetTokenRatio = 0.1
+)
+
 const (
 	// todo: we can retrieve these from the Syscall account
 	//       but they're unlikely to change.
@@ -156,6 +162,13 @@ type ConfirmedTransaction struct {
 	Meta        *TransactionMeta
 }
 
+// PrioritizationFee is the minimum compute unit price, in micro-lamports, paid
+// by a transaction that landed in the given slot.
+type PrioritizationFee struct {
+	Slot          uint64
+	MicroLamports uint64
+}
+
 type TransactionSignature struct {
 	Signature Signature
 	Slot      uint64
@@ -180,6 +193,7 @@ type Client interface {
 	GetConfirmedTransaction(Signature) (ConfirmedTransaction, error)
 	GetMinimumBalanceForRentExemption(size uint64) (lamports uint64, err error)
 	GetLatestBlockhash() (Blockhash, error)
+	GetRecentPrioritizationFees(writableAccounts []ed25519.PublicKey) ([]PrioritizationFee, error)
 	GetSignatureStatus(Signature, Commitment) (*SignatureStatus, error)
 	GetSignatureStatuses([]Signature) ([]*SignatureStatus, error)
 	GetSignaturesForAddress(owner ed25519.PublicKey, commitment Commitment, limit uint64, before, until string) ([]*TransactionSignature, error)
@@ -223,14 +237,28 @@ func New(endpoint string) Client {
 // NewWithRPCOptions returns a client configured with the specified RPC options.
 func NewWithRPCOptions(endpoint string, opts *jsonrpc.RPCClientOpts) Client {
 	return &client{
-		log:    logrus.StandardLogger().WithField("type", "solana/client"),
-		client: jsonrpc.NewClientWithOpts(endpoint, opts),
-		retrier: retry.NewRetrier(
+		log:     logrus.StandardLogger().WithField("type", "solana/client"),
+		client:  jsonrpc.NewClientWithOpts(endpoint, opts),
+		retrier: newRPCRetrier(),
+	}
+}
+
+// newRPCRetrier returns the resilience policy for RPC calls. Only rate limits
+// and service errors are retried. See isUnhealthyError for the errors that count
+// against the circuit breaker and retry budget.
+func newRPCRetrier() retry.Retrier {
+	return retry.NewPolicy(
+		rpcDependencyName,
+		retry.WithStrategies(
 			retry.RetriableErrors(errRateLimited, errServiceError),
 			retry.Limit(3),
-			retry.BackoffWithJitter(backoff.BinaryExponential(time.Second), 10*time.Second, 0.1),
+			retry.Backoff(backoff.DecorrelatedJitter(time.Second, 10*time.Second), 10*time.Second),
 		),
-	}
+		retry.WithCircuitBreaker(retry.NewCircuitBreaker(rpcDependencyName)),
+		retry.WithBudget(retry.NewBudget(rpcRetryBudgetTokens, rpcRetryBudgetTokenRatio)),
+		retry.WithFailureClassifier(isUnhealthyError),
+		retry.WithHooks(retry.MetricsHooks()),
+	)
 }
 
 func (c *client) call(out interface{}, method string, params ...interface{}) error {
@@ -272,6 +300,17 @@ func (c *client) callBatch(method string, requests jsonrpc.RPCRequests) (map[int
 }
 
 func (c *client) handleRpcError(method string, err error) error {
+	if httpErr, ok := err.(*jsonrpc.HTTPError); ok {
+		if httpErr.Code == 429 {
+			c.log.WithField("method", method).Error("rate limited")
+			return errRateLimited
+		}
+		if httpErr.Code >= 500 {
+			return errServiceError
+		}
+		return err
+	}
+
 	rpcErr, ok := err.(*jsonrpc.RPCError)
 	if !ok {
 		return err
@@ -287,6 +326,14 @@ func (c *client) handleRpcError(method string, err error) error {
 	return err
 }
 
+// isUnhealthyError determines whether an error indicates the RPC node is
+// unhealthy, as opposed to an error response for a specific request. Transport
+// errors, like an unreachable node, never produce an RPC error.
+func isUnhealthyError(err error) bool {
+	_, ok := err.(*jsonrpc.RPCError)
+	return !ok
+}
+
 func (c *client) GetMinimumBalanceForRentExemption(dataSize uint64) (lamports uint64, err error) {
 	if err := c.call(&lamports, "getMinimumBalanceForRentExemption", dataSize); err != nil {
 		return 0, errors.Wrapf(err, "getMinimumBalanceForRentExemption() failed to send request")
@@ -348,6 +395,30 @@ func (c *client) GetLatestBlockhash() (hash Blockhash, err error) {
 	return hash, nil
 }
 
+func (c *client) GetRecentPrioritizationFees(writableAccounts []ed25519.PublicKey) ([]PrioritizationFee, error) {
+	b58Accounts := make([]string, len(writableAccounts))
+	for i, account := range writableAccounts {
+		b58Accounts[i] = base58.Encode(account)
+	}
+
+	var resp []struct {
+		Slot              uint64 `json:"slot"`
+		PrioritizationFee uint64 `json:"prioritizationFee"`
+	}
+	if err := c.call(&resp, "getRecentPrioritizationFees", b58Accounts); err != nil {
+		return nil, errors.Wrapf(err, "getRecentPrioritizationFees() failed to send request")
+	}
+
+	fees := make([]PrioritizationFee, len(resp))
+	for i, fee := range resp {
+		fees[i] = PrioritizationFee{
+			Slot:          fee.Slot,
+			MicroLamports: fee.PrioritizationFee,
+		}
+	}
+	return fees, nil
+}
+
 func (c *client) GetBlockTime(slot uint64) (time.Time, error) {
 	var unixTs int64
 	if err := c.call(&unixTs, "getBlockTime", slot); err != nil {
@@ -577,11 +648,13 @@ func (c *client) GetTransaction(sig Signature, commitment Commitment) (Confirmed
 	}
 
 	config := struct {
-		Commitment string `json:"commitment"`
-		Encoding   string `json:"encoding"`
+		Commitment                     string `json:"commitment"`
+		Encoding                       string `json:"encoding"`
+		MaxSupportedTransactionVersion int    `json:"maxSupportedTransactionVersion"`
 	}{
-		Commitment: commitment.Commitment,
-		Encoding:   "base64",
+		Commitment:                     commitment.Commitment,
+		Encoding:                       "base64",
+		MaxSupportedTransactionVersion: 0,
 	}
 
 	var resp *rpcResponse
@@ -1,9 +1,15 @@
 package solana
 
 import (
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/code-payments/code-server/pkg/retry"
 )
 
 func TestSignatureStatus(t *testing.T) {
@@ -73,3 +79,37 @@ func TestSignatureStatus(t *testing.T) {
 		assert.Equal(t, tc.finalized, tc.s.Finalized())
 	}
 }
+
+func TestClient_UnreachableEndpointOpensCircuitBreaker(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	endpoint := "http://" + listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	c := New(endpoint)
+
+	for i := 0; i < 5; i++ {
+		_, err := c.GetSlot(CommitmentFinalized)
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, retry.ErrCircuitOpen)
+	}
+
+	_, err = c.GetSlot(CommitmentFinalized)
+	assert.ErrorIs(t, err, retry.ErrCircuitOpen)
+}
+
+func TestClient_RPCErrorsDontOpenCircuitBreaker(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"jsonrpc":"2.0","id":0,"error":{"code":-32602,"message":"invalid params"}}`))
+	}))
+	defer server.Close()
+
+	c := New(server.URL)
+
+	for i := 0; i < 10; i++ {
+		_, err := c.GetSlot(CommitmentFinalized)
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, retry.ErrCircuitOpen)
+	}
+}
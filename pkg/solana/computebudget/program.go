@@ -0,0 +1,152 @@
+package compute_budget
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/binary"
+	"math"
+
+	"github.com/pkg/errors"
+
+	"github.com/code-payments/code-server/pkg/solana"
+)
+
+// ProgramKey is the address of the compute budget program.
+//
+// Current key: ComputeBudget111111111111111111111111111111
+var ProgramKey = ed25519.PublicKey{3, 6, 70, 111, 229, 33, 23, 50, 255, 236, 173, 186, 114, 195, 155, 231, 188, 140, 229, 187, 197, 247, 18, 107, 44, 67, 155, 58, 64, 0, 0, 0}
+
+const (
+	// MaxComputeUnitLimit is the maximum number of compute units a single
+	// transaction can request.
+	MaxComputeUnitLimit = 1_400_000
+
+	// DefaultInstructionComputeUnitLimit is the number of compute units
+	// allocated to each instruction when no explicit limit is requested.
+	DefaultInstructionComputeUnitLimit = 200_000
+)
+
+type Command byte
+
+const (
+	// nolint:varcheck,deadcode,unused
+	CommandRequestUnitsDeprecated Command = iota
+	// nolint:varcheck,deadcode,unused
+	CommandRequestHeapFrame
+	CommandSetComputeUnitLimit
+	CommandSetComputeUnitPrice
+
+	CommandUnknown = Command(math.MaxUint8)
+)
+
+func GetCommand(m solana.Message, index int) (Command, error) {
+	if err := m.CheckInstructionAccounts(index); err != nil {
+		return CommandUnknown, err
+	}
+
+	i := m.Instructions[index]
+
+	if !bytes.Equal(m.Accounts[i.ProgramIndex], ProgramKey) {
+		return CommandUnknown, solana.ErrIncorrectProgram
+	}
+	if len(i.Data) == 0 {
+		return CommandUnknown, errors.New("compute budget instruction missing data")
+	}
+
+	return Command(i.Data[0]), nil
+}
+
+// Reference: https://github.com/solana-labs/solana/blob/master/sdk/src/compute_budget.rs
+func SetComputeUnitLimit(units uint32) solana.Instruction {
+	// SetComputeUnitLimit(u32)
+	//
+	// No accounts are required.
+	data := make([]byte, 1+4)
+	data[0] = byte(CommandSetComputeUnitLimit)
+	binary.LittleEndian.PutUint32(data[1:], units)
+
+	return solana.NewInstruction(
+		ProgramKey,
+		data,
+	)
+}
+
+type DecompiledSetComputeUnitLimit struct {
+	Units uint32
+}
+
+func DecompileSetComputeUnitLimit(m solana.Message, index int) (*DecompiledSetComputeUnitLimit, error) {
+	cmd, err := GetCommand(m, index)
+	if err != nil {
+		return nil, err
+	}
+	if cmd != CommandSetComputeUnitLimit {
+		return nil, solana.ErrIncorrectInstruction
+	}
+
+	i := m.Instructions[index]
+	if len(i.Data) != 1+4 {
+		return nil, errors.Errorf("invalid instruction data size: %d", len(i.Data))
+	}
+	if len(i.Accounts) != 0 {
+		return nil, errors.Errorf("invalid number of accounts: %d", len(i.Accounts))
+	}
+
+	return &DecompiledSetComputeUnitLimit{
+		Units: binary.LittleEndian.Uint32(i.Data[1:]),
+	}, nil
+}
+
+// Reference: https://github.com/solana-labs/solana/blob/master/sdk/src/compute_budget.rs
+func SetComputeUnitPrice(microLamports uint64) solana.Instruction {
+	// SetComputeUnitPrice(u64)
+	//
+	// The price is denominated in micro-lamports per compute unit. No accounts
+	// are required.
+	data := make([]byte, 1+8)
+	data[0] = byte(CommandSetComputeUnitPrice)
+	binary.LittleEndian.PutUint64(data[1:], microLamports)
+
+	return solana.NewInstruction(
+		ProgramKey,
+		data,
+	)
+}
+
+type DecompiledSetComputeUnitPrice struct {
+	MicroLamports uint64
+}
+
+func DecompileSetComputeUnitPrice(m solana.Message, index int) (*DecompiledSetComputeUnitPrice, error) {
+	cmd, err := GetCommand(m, index)
+	if err != nil {
+		return nil, err
+	}
+	if cmd != CommandSetComputeUnitPrice {
+		return nil, solana.ErrIncorrectInstruction
+	}
+
+	i := m.Instructions[index]
+	if len(i.Data) != 1+8 {
+		return nil, errors.Errorf("invalid instruction data size: %d", len(i.Data))
+	}
+	if len(i.Accounts) != 0 {
+		return nil, errors.Errorf("invalid number of accounts: %d", len(i.Accounts))
+	}
+
+	return &DecompiledSetComputeUnitPrice{
+		MicroLamports: binary.LittleEndian.Uint64(i.Data[1:]),
+	}, nil
+}
+
+// GetPriorityFee returns the priority fee, in lamports, paid by a transaction
+// requesting the provided compute unit limit and price.
+func GetPriorityFee(units uint32, microLamports uint64) uint64 {
+	// Round up to the nearest lamport, which is what the runtime does
+	product := uint64(units) * microLamports
+	fee := product / 1_000_000
+	if product%1_000_000 != 0 {
+		fee++
+	}
+	return fee
+}
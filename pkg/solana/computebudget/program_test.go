@@ -0,0 +1,82 @@
+package compute_budget
+
+import (
+	"crypto/ed25519"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/code-payments/code-server/pkg/solana"
+	"github.com/code-payments/code-server/pkg/solana/memo"
+)
+
+func TestSetComputeUnitLimit(t *testing.T) {
+	instruction := SetComputeUnitLimit(300_000)
+
+	assert.Equal(t, ProgramKey, instruction.Program)
+	assert.Empty(t, instruction.Accounts)
+	assert.Equal(t, []byte{2, 0xe0, 0x93, 0x04, 0x00}, instruction.Data)
+
+	txn := solana.NewTransaction(make([]byte, 32), instruction)
+
+	cmd, err := GetCommand(txn.Message, 0)
+	require.NoError(t, err)
+	assert.Equal(t, CommandSetComputeUnitLimit, cmd)
+
+	decompiled, err := DecompileSetComputeUnitLimit(txn.Message, 0)
+	require.NoError(t, err)
+	assert.EqualValues(t, 300_000, decompiled.Units)
+
+	_, err = DecompileSetComputeUnitPrice(txn.Message, 0)
+	assert.Equal(t, solana.ErrIncorrectInstruction, err)
+}
+
+func TestSetComputeUnitPrice(t *testing.T) {
+	instruction := SetComputeUnitPrice(12345)
+
+	assert.Equal(t, ProgramKey, instruction.Program)
+	assert.Empty(t, instruction.Accounts)
+	assert.Equal(t, []byte{3, 0x39, 0x30, 0, 0, 0, 0, 0, 0}, instruction.Data)
+
+	txn := solana.NewTransaction(make([]byte, 32), instruction)
+
+	cmd, err := GetCommand(txn.Message, 0)
+	require.NoError(t, err)
+	assert.Equal(t, CommandSetComputeUnitPrice, cmd)
+
+	decompiled, err := DecompileSetComputeUnitPrice(txn.Message, 0)
+	require.NoError(t, err)
+	assert.EqualValues(t, 12345, decompiled.MicroLamports)
+
+	_, err = DecompileSetComputeUnitLimit(txn.Message, 0)
+	assert.Equal(t, solana.ErrIncorrectInstruction, err)
+}
+
+func TestGetCommand_Error(t *testing.T) {
+	payer, _, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	txn := solana.NewTransaction(payer, memo.Instruction("hello, world"))
+
+	_, err = GetCommand(txn.Message, 1)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "instruction doesn't exist")
+
+	cmd, err := GetCommand(txn.Message, 0)
+	assert.Equal(t, CommandUnknown, cmd)
+	assert.Equal(t, solana.ErrIncorrectProgram, err)
+
+	txn = solana.NewTransaction(payer, solana.NewInstruction(ProgramKey, []byte{}))
+	cmd, err = GetCommand(txn.Message, 0)
+	assert.Equal(t, CommandUnknown, cmd)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "missing data")
+}
+
+func TestGetPriorityFee(t *testing.T) {
+	assert.EqualValues(t, 0, GetPriorityFee(200_000, 0))
+	assert.EqualValues(t, 1, GetPriorityFee(1, 1))
+	assert.EqualValues(t, 200, GetPriorityFee(200_000, 1_000))
+	assert.EqualValues(t, 201, GetPriorityFee(200_001, 1_000))
+}
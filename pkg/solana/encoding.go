@@ -51,6 +51,11 @@ func (t *Transaction) Unmarshal(b []byte) error {
 func (m Message) Marshal() []byte {
 	b := bytes.NewBuffer(nil)
 
+	// Version
+	if m.Version == MessageVersion0 {
+		_ = b.WriteByte(messageVersionPrefix)
+	}
+
 	// Header
 	_ = b.WriteByte(m.Header.NumSignatures)
 	_ = b.WriteByte(m.Header.NumReadonlySigned)
@@ -79,12 +84,39 @@ func (m Message) Marshal() []byte {
 		_, _ = b.Write(i.Data)
 	}
 
+	// Address Table Lookups
+	if m.Version == MessageVersion0 {
+		_, _ = shortvec.EncodeLen(b, len(m.AddressTableLookups))
+		for _, l := range m.AddressTableLookups {
+			_, _ = b.Write(l.PublicKey)
+
+			_, _ = shortvec.EncodeLen(b, len(l.WritableIndexes))
+			_, _ = b.Write(l.WritableIndexes)
+
+			_, _ = shortvec.EncodeLen(b, len(l.ReadonlyIndexes))
+			_, _ = b.Write(l.ReadonlyIndexes)
+		}
+	}
+
 	return b.Bytes()
 }
 
 func (m *Message) Unmarshal(b []byte) (err error) {
 	buf := bytes.NewBuffer(b)
 
+	// Version
+	//
+	// Legacy messages don't have a version prefix, and instead start with the
+	// header, whose first byte can never have the high bit set.
+	m.Version = MessageVersionLegacy
+	if len(b) > 0 && b[0]&messageVersionPrefix != 0 {
+		version, _ := buf.ReadByte()
+		if version&^messageVersionPrefix != 0 {
+			return errors.Errorf("unsupported message version: %d", version&^messageVersionPrefix)
+		}
+		m.Version = MessageVersion0
+	}
+
 	// Header
 	if m.Header.NumSignatures, err = buf.ReadByte(); err != nil {
 		return errors.Wrap(err, "failed to read num signatures")
@@ -127,9 +159,6 @@ func (m *Message) Unmarshal(b []byte) (err error) {
 		if c.ProgramIndex, err = buf.ReadByte(); err != nil {
 			return errors.Wrapf(err, "failed to read instruction[%d] program index", i)
 		}
-		if int(c.ProgramIndex) >= len(m.Accounts) {
-			return errors.Errorf("program index out of range: %d:%d", i, c.ProgramIndex)
-		}
 
 		// Account Indexes
 		accountLen, err = shortvec.DecodeLen(buf)
@@ -141,12 +170,6 @@ func (m *Message) Unmarshal(b []byte) (err error) {
 			return errors.Wrapf(err, "failed to read instruction[%d] accounts", i)
 		}
 
-		for _, index := range c.Accounts {
-			if int(index) >= len(m.Accounts) {
-				return errors.Errorf("account index out of range: %d:%d", i, index)
-			}
-		}
-
 		// Data
 		dataLen, err := shortvec.DecodeLen(buf)
 		if err != nil {
@@ -160,5 +183,58 @@ func (m *Message) Unmarshal(b []byte) (err error) {
 		m.Instructions[i] = c
 	}
 
+	// Address Table Lookups
+	m.AddressTableLookups = nil
+	var numLoadedAccounts int
+	if m.Version == MessageVersion0 {
+		lookupLen, err := shortvec.DecodeLen(buf)
+		if err != nil {
+			return errors.Wrap(err, "failed to read address table lookup len")
+		}
+		m.AddressTableLookups = make([]MessageAddressTableLookup, lookupLen)
+		for i := 0; i < lookupLen; i++ {
+			var l MessageAddressTableLookup
+
+			l.PublicKey = make([]byte, ed25519.PublicKeySize)
+			if _, err = io.ReadFull(buf, l.PublicKey); err != nil {
+				return errors.Wrapf(err, "failed to read address table lookup[%d] key", i)
+			}
+
+			writableLen, err := shortvec.DecodeLen(buf)
+			if err != nil {
+				return errors.Wrapf(err, "failed to read address table lookup[%d] writable len", i)
+			}
+			l.WritableIndexes = make([]byte, writableLen)
+			if _, err = io.ReadFull(buf, l.WritableIndexes); err != nil {
+				return errors.Wrapf(err, "failed to read address table lookup[%d] writable indexes", i)
+			}
+
+			readonlyLen, err := shortvec.DecodeLen(buf)
+			if err != nil {
+				return errors.Wrapf(err, "failed to read address table lookup[%d] readonly len", i)
+			}
+			l.ReadonlyIndexes = make([]byte, readonlyLen)
+			if _, err = io.ReadFull(buf, l.ReadonlyIndexes); err != nil {
+				return errors.Wrapf(err, "failed to read address table lookup[%d] readonly indexes", i)
+			}
+
+			numLoadedAccounts += writableLen + readonlyLen
+			m.AddressTableLookups[i] = l
+		}
+	}
+
+	// Validate instruction indexes against all static and loaded accounts
+	for i, c := range m.Instructions {
+		if int(c.ProgramIndex) >= len(m.Accounts)+numLoadedAccounts {
+			return errors.Errorf("program index out of range: %d:%d", i, c.ProgramIndex)
+		}
+
+		for _, index := range c.Accounts {
+			if int(index) >= len(m.Accounts)+numLoadedAccounts {
+				return errors.Errorf("account index out of range: %d:%d", i, index)
+			}
+		}
+	}
+
 	return nil
 }
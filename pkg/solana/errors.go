@@ -82,6 +82,8 @@ const (
 	InstructionErrorMaxSeedLengthExceeded          InstructionErrorKey = "MaxSeedLengthExceeded"
 	InstructionErrorInvalidSeeds                   InstructionErrorKey = "InvalidSeeds"
 	InstructionErrorInvalidRealloc                 InstructionErrorKey = "InvalidRealloc"
+	InstructionErrorImmutable                      InstructionErrorKey = "Immutable"
+	InstructionErrorIncorrectAuthority             InstructionErrorKey = "IncorrectAuthority"
 )
 
 // CustomError is the numerical error returned by a non-system program.
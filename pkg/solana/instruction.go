@@ -9,6 +9,7 @@ import (
 var (
 	ErrIncorrectProgram     = errors.New("incorrect program")
 	ErrIncorrectInstruction = errors.New("incorrect instruction")
+	ErrInvalidAccountIndex  = errors.New("invalid account index")
 )
 
 // AccountMeta represents the account information required
@@ -4,8 +4,6 @@ import (
 	"bytes"
 	"crypto/ed25519"
 
-	"github.com/pkg/errors"
-
 	"github.com/code-payments/code-server/pkg/solana"
 )
 
@@ -29,8 +27,8 @@ type DecompiledMemo struct {
 }
 
 func DecompileMemo(m solana.Message, index int) (*DecompiledMemo, error) {
-	if index >= len(m.Instructions) {
-		return nil, errors.Errorf("instruction doesn't exist at %d", index)
+	if err := m.CheckInstructionAccounts(index); err != nil {
+		return nil, err
 	}
 
 	i := m.Instructions[index]
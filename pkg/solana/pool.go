@@ -0,0 +1,490 @@
+package solana
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/ybbus/jsonrpc"
+	xrate "golang.org/x/time/rate"
+
+	"github.com/code-payments/code-server/pkg/rate"
+)
+
+const (
+	defaultHealthCheckInterval = 10 * time.Second
+	defaultUnhealthyCooldown   = 30 * time.Second
+	defaultEndpointLatency     = 250 * time.Millisecond
+
+	// latencySmoothingFactor is the weight given to the most recent latency
+	// observation when updating an endpoint's moving average.
+	latencySmoothingFactor = 0.2
+)
+
+// PoolOption configures a client backed by a pool of RPC endpoints.
+type PoolOption func(*poolOptions)
+
+type poolOptions struct {
+	rpcOpts             *jsonrpc.RPCClientOpts
+	healthCheckInterval time.Duration
+	unhealthyCooldown   time.Duration
+	defaultRateLimit    float64
+	methodRateLimits    map[string]float64
+}
+
+// WithPoolRPCOptions configures the RPC options used for every endpoint.
+func WithPoolRPCOptions(opts *jsonrpc.RPCClientOpts) PoolOption {
+	return func(o *poolOptions) {
+		o.rpcOpts = opts
+	}
+}
+
+// WithHealthCheckInterval configures how often endpoints are health checked.
+// A non-positive interval disables background health checks, in which case
+// endpoint health is determined solely by the outcome of calls.
+func WithHealthCheckInterval(interval time.Duration) PoolOption {
+	return func(o *poolOptions) {
+		o.healthCheckInterval = interval
+	}
+}
+
+// WithUnhealthyCooldown configures how long an endpoint is avoided after it
+// fails a call or health check.
+func WithUnhealthyCooldown(cooldown time.Duration) PoolOption {
+	return func(o *poolOptions) {
+		o.unhealthyCooldown = cooldown
+	}
+}
+
+// WithDefaultRateLimit configures the client-side rate limit, in requests per
+// second, for any method without an explicit limit. Zero disables the limit.
+func WithDefaultRateLimit(rps float64) PoolOption {
+	return func(o *poolOptions) {
+		o.defaultRateLimit = rps
+	}
+}
+
+// WithMethodRateLimit configures the client-side rate limit, in requests per
+// second, for a single RPC method.
+func WithMethodRateLimit(method string, rps float64) PoolOption {
+	return func(o *poolOptions) {
+		o.methodRateLimits[method] = rps
+	}
+}
+
+// PoolClient is a Client backed by a pool of RPC endpoints.
+type PoolClient interface {
+	Client
+
+	// Close stops the pool's background health checks, and waits for any in
+	// flight health check to complete.
+	Close() error
+}
+
+type poolClient struct {
+	*client
+
+	pool *rpcPool
+}
+
+// NewWithEndpoints returns a client that load balances requests across a pool
+// of RPC endpoints. Endpoints are selected randomly, weighted by their observed
+// latency, and requests fail over to other endpoints when an endpoint is rate
+// limited, unavailable, or otherwise unhealthy. Background health checks run
+// until the provided context is cancelled or the client is closed.
+func NewWithEndpoints(ctx context.Context, endpoints []string, opts ...PoolOption) (PoolClient, error) {
+	pool, err := newRPCPool(ctx, endpoints, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &poolClient{
+		client: &client{
+			log:     logrus.StandardLogger().WithField("type", "solana/client"),
+			client:  pool,
+			retrier: newRPCRetrier(),
+		},
+		pool: pool,
+	}, nil
+}
+
+func (c *poolClient) Close() error {
+	c.pool.close()
+	return nil
+}
+
+type poolEndpoint struct {
+	url    string
+	client jsonrpc.RPCClient
+
+	mu             sync.RWMutex
+	latency        time.Duration
+	unhealthyUntil time.Time
+}
+
+func (e *poolEndpoint) isHealthy(at time.Time) bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return !at.Before(e.unhealthyUntil)
+}
+
+func (e *poolEndpoint) getLatency() time.Duration {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.latency
+}
+
+func (e *poolEndpoint) onSuccess(latency time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.latency == 0 {
+		e.latency = latency
+	} else {
+		e.latency = time.Duration(latencySmoothingFactor*float64(latency) + (1-latencySmoothingFactor)*float64(e.latency))
+	}
+	e.unhealthyUntil = time.Time{}
+}
+
+func (e *poolEndpoint) onFailure(cooldown time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.unhealthyUntil = time.Now().Add(cooldown)
+}
+
+// rpcPool is a jsonrpc.RPCClient that distributes calls across a set of
+// endpoints, which allows the pooled client to reuse all of the request and
+// response handling of the single endpoint client.
+type rpcPool struct {
+	log  *logrus.Entry
+	opts *poolOptions
+
+	endpoints []*poolEndpoint
+
+	defaultLimiter rate.Limiter
+	methodLimiters map[string]rate.Limiter
+
+	randMu sync.Mutex
+	rand   *rand.Rand
+
+	cancel             context.CancelFunc
+	healthCheckStopped chan struct{}
+}
+
+func newRPCPool(ctx context.Context, endpoints []string, opts ...PoolOption) (*rpcPool, error) {
+	if len(endpoints) == 0 {
+		return nil, errors.New("at least one endpoint is required")
+	}
+
+	o := &poolOptions{
+		healthCheckInterval: defaultHealthCheckInterval,
+		unhealthyCooldown:   defaultUnhealthyCooldown,
+		methodRateLimits:    make(map[string]float64),
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	p := &rpcPool{
+		log:            logrus.StandardLogger().WithField("type", "solana/pool"),
+		opts:           o,
+		defaultLimiter: &rate.NoLimiter{},
+		methodLimiters: make(map[string]rate.Limiter),
+		rand:           rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+
+	for _, endpoint := range endpoints {
+		p.endpoints = append(p.endpoints, &poolEndpoint{
+			url:    endpoint,
+			client: jsonrpc.NewClientWithOpts(endpoint, o.rpcOpts),
+		})
+	}
+
+	// The local rate limiter uses the rate as its burst size, so anything below
+	// one request per second would never allow a request.
+	if o.defaultRateLimit > 0 {
+		if o.defaultRateLimit < 1 {
+			return nil, errors.New("default rate limit must be at least 1 rps")
+		}
+		p.defaultLimiter = rate.NewLocalRateLimiter(xrate.Limit(o.defaultRateLimit))
+	}
+	for method, rps := range o.methodRateLimits {
+		if rps <= 0 {
+			p.methodLimiters[method] = &rate.NoLimiter{}
+			continue
+		}
+		if rps < 1 {
+			return nil, errors.Errorf("rate limit for %s must be at least 1 rps", method)
+		}
+		p.methodLimiters[method] = rate.NewLocalRateLimiter(xrate.Limit(rps))
+	}
+
+	ctx, p.cancel = context.WithCancel(ctx)
+	p.healthCheckStopped = make(chan struct{})
+	if o.healthCheckInterval > 0 {
+		go func() {
+			defer close(p.healthCheckStopped)
+			p.healthCheckWorker(ctx)
+		}()
+	} else {
+		close(p.healthCheckStopped)
+	}
+
+	return p, nil
+}
+
+// close stops the background health check worker and waits for it to exit.
+// It's safe to call more than once.
+func (p *rpcPool) close() {
+	p.cancel()
+	<-p.healthCheckStopped
+}
+
+func (p *rpcPool) Call(method string, params ...interface{}) (*jsonrpc.RPCResponse, error) {
+	return p.CallRaw(jsonrpc.NewRequest(method, params...))
+}
+
+func (p *rpcPool) CallRaw(request *jsonrpc.RPCRequest) (*jsonrpc.RPCResponse, error) {
+	var resp *jsonrpc.RPCResponse
+	err := p.do(request.Method, func(rpc jsonrpc.RPCClient) error {
+		var err error
+		resp, err = rpc.CallRaw(request)
+		if err != nil {
+			return err
+		}
+
+		if resp.Error != nil && shouldFailover(resp.Error) {
+			return resp.Error
+		}
+		return nil
+	})
+	return resp, err
+}
+
+func (p *rpcPool) CallFor(out interface{}, method string, params ...interface{}) error {
+	resp, err := p.Call(method, params...)
+	if err != nil {
+		return err
+	}
+
+	if resp.Error != nil {
+		return resp.Error
+	}
+
+	return resp.GetObject(out)
+}
+
+func (p *rpcPool) CallBatch(requests jsonrpc.RPCRequests) (jsonrpc.RPCResponses, error) {
+	return p.callBatch(requests, func(rpc jsonrpc.RPCClient) (jsonrpc.RPCResponses, error) {
+		return rpc.CallBatch(requests)
+	})
+}
+
+func (p *rpcPool) CallBatchRaw(requests jsonrpc.RPCRequests) (jsonrpc.RPCResponses, error) {
+	return p.callBatch(requests, func(rpc jsonrpc.RPCClient) (jsonrpc.RPCResponses, error) {
+		return rpc.CallBatchRaw(requests)
+	})
+}
+
+func (p *rpcPool) callBatch(requests jsonrpc.RPCRequests, call func(jsonrpc.RPCClient) (jsonrpc.RPCResponses, error)) (jsonrpc.RPCResponses, error) {
+	if len(requests) == 0 {
+		return nil, errors.New("empty request list")
+	}
+
+	var responses jsonrpc.RPCResponses
+	err := p.do(requests[0].Method, func(rpc jsonrpc.RPCClient) error {
+		var err error
+		responses, err = call(rpc)
+		if err != nil {
+			return err
+		}
+
+		for _, resp := range responses {
+			if resp.Error != nil && shouldFailover(resp.Error) {
+				return resp.Error
+			}
+		}
+		return nil
+	})
+	return responses, err
+}
+
+// do executes the call against endpoints, in order of selection preference,
+// until one succeeds or returns an error that another endpoint wouldn't fix.
+func (p *rpcPool) do(method string, call func(jsonrpc.RPCClient) error) error {
+	allowed, err := p.getLimiter(method).Allow(method)
+	if err != nil {
+		return err
+	} else if !allowed {
+		p.log.WithField("method", method).Debug("client-side rate limit exceeded")
+		return errRateLimited
+	}
+
+	var lastErr error
+	for _, endpoint := range p.selectEndpoints() {
+		start := time.Now()
+		err := call(endpoint.client)
+		if err == nil {
+			endpoint.onSuccess(time.Since(start))
+			return nil
+		}
+
+		if !shouldFailover(err) {
+			// The endpoint responded, so it's still healthy
+			endpoint.onSuccess(time.Since(start))
+			return err
+		}
+
+		p.log.WithError(err).WithFields(logrus.Fields{
+			"method":   method,
+			"endpoint": endpoint.url,
+		}).Debug("failing over to another endpoint")
+
+		endpoint.onFailure(p.opts.unhealthyCooldown)
+		lastErr = err
+	}
+
+	return lastErr
+}
+
+func (p *rpcPool) getLimiter(method string) rate.Limiter {
+	if limiter, ok := p.methodLimiters[method]; ok {
+		return limiter
+	}
+	return p.defaultLimiter
+}
+
+// selectEndpoints returns all endpoints in the order they should be attempted.
+// Healthy endpoints are ordered by a random selection weighted by the inverse
+// of their latency, followed by unhealthy endpoints as a last resort.
+func (p *rpcPool) selectEndpoints() []*poolEndpoint {
+	now := time.Now()
+
+	var healthy, unhealthy []*poolEndpoint
+	for _, endpoint := range p.endpoints {
+		if endpoint.isHealthy(now) {
+			healthy = append(healthy, endpoint)
+		} else {
+			unhealthy = append(unhealthy, endpoint)
+		}
+	}
+
+	weights := make([]float64, len(healthy))
+	for i, endpoint := range healthy {
+		latency := endpoint.getLatency()
+		if latency <= 0 {
+			latency = defaultEndpointLatency
+		}
+		weights[i] = 1 / latency.Seconds()
+	}
+
+	ordered := make([]*poolEndpoint, 0, len(p.endpoints))
+	p.randMu.Lock()
+	for len(healthy) > 0 {
+		var total float64
+		for _, weight := range weights {
+			total += weight
+		}
+
+		selected := len(healthy) - 1
+		target := p.rand.Float64() * total
+		for i, weight := range weights {
+			if target < weight {
+				selected = i
+				break
+			}
+			target -= weight
+		}
+
+		ordered = append(ordered, healthy[selected])
+		healthy = append(healthy[:selected], healthy[selected+1:]...)
+		weights = append(weights[:selected], weights[selected+1:]...)
+	}
+	p.randMu.Unlock()
+
+	return append(ordered, unhealthy...)
+}
+
+func (p *rpcPool) healthCheckWorker(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(p.opts.healthCheckInterval):
+		}
+
+		var wg sync.WaitGroup
+		for _, endpoint := range p.endpoints {
+			wg.Add(1)
+			go func(endpoint *poolEndpoint) {
+				defer wg.Done()
+				p.checkHealth(endpoint)
+			}(endpoint)
+		}
+		wg.Wait()
+	}
+}
+
+// Reference: https://docs.solana.com/api/http#gethealth
+func (p *rpcPool) checkHealth(endpoint *poolEndpoint) {
+	var health string
+
+	start := time.Now()
+	err := endpoint.client.CallFor(&health, "getHealth")
+	if err == nil && health == "ok" {
+		endpoint.onSuccess(time.Since(start))
+		return
+	}
+
+	p.log.WithError(err).WithField("endpoint", endpoint.url).Debug("endpoint failed health check")
+	endpoint.onFailure(p.opts.unhealthyCooldown)
+}
+
+// Reference: https://www.jsonrpc.org/specification#error_object
+const (
+	rpcInternalErrorCode  = -32603
+	rpcServerErrorMinCode = -32099
+	rpcServerErrorMaxCode = -32000
+)
+
+// Solana server error codes that are caused by the request itself, rather than
+// the state of the node serving it, and so fail against every endpoint.
+//
+// Reference: https://github.com/solana-labs/solana/blob/71e9958e061493d7545bd28d4ac7a85aaed6ffbb/client/src/rpc_custom_error.rs
+var requestServerErrorCodes = map[int]struct{}{
+	-32002: {}, // SendTransactionPreflightFailure
+	-32003: {}, // TransactionSignatureVerificationFailure
+	-32006: {}, // TransactionPrecompileVerificationFailure
+	-32013: {}, // TransactionSignatureLenMismatch
+	-32015: {}, // UnsupportedTransactionVersion
+}
+
+// shouldFailover returns whether an error is specific to the endpoint that
+// returned it, and so might succeed against another endpoint.
+func shouldFailover(err error) bool {
+	switch typed := err.(type) {
+	case *jsonrpc.RPCError:
+		if typed.Code == rpcNodeUnhealthyCode || typed.Code == rpcInternalErrorCode {
+			return true
+		}
+
+		if typed.Code >= rpcServerErrorMinCode && typed.Code <= rpcServerErrorMaxCode {
+			_, ok := requestServerErrorCodes[typed.Code]
+			return !ok
+		}
+
+		return false
+	case *jsonrpc.HTTPError:
+		return typed.Code == http.StatusTooManyRequests || typed.Code >= 500
+	default:
+		// Transport level errors, like connection failures
+		return true
+	}
+}
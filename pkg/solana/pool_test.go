@@ -0,0 +1,302 @@
+package solana
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testRPCServer struct {
+	*httptest.Server
+
+	mu         sync.Mutex
+	calls      map[string]int
+	statusCode int
+	rpcError   *int
+	slot       uint64
+	health     string
+}
+
+func newTestRPCServer(t *testing.T, slot uint64) *testRPCServer {
+	s := &testRPCServer{
+		calls:      make(map[string]int),
+		statusCode: http.StatusOK,
+		slot:       slot,
+		health:     "ok",
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *testRPCServer) handle(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ID     int    `json:"id"`
+		Method string `json:"method"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.calls[req.Method]++
+
+	if s.statusCode != http.StatusOK {
+		w.WriteHeader(s.statusCode)
+		return
+	}
+
+	if s.rpcError != nil {
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"error":{"code":%d,"message":"test error"}}`, req.ID, *s.rpcError)
+		return
+	}
+
+	switch req.Method {
+	case "getSlot":
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"result":%d}`, req.ID, s.slot)
+	case "getHealth":
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"result":"%s"}`, req.ID, s.health)
+	default:
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"error":{"code":-32601,"message":"method not found"}}`, req.ID)
+	}
+}
+
+func (s *testRPCServer) getCalls(method string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.calls[method]
+}
+
+func (s *testRPCServer) setStatusCode(statusCode int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.statusCode = statusCode
+}
+
+func (s *testRPCServer) setRPCError(code *int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.rpcError = code
+}
+
+func (s *testRPCServer) setHealth(health string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.health = health
+}
+
+func TestPool_FailoverOnRateLimit(t *testing.T) {
+	limited := newTestRPCServer(t, 1)
+	healthy := newTestRPCServer(t, 2)
+	limited.setStatusCode(http.StatusTooManyRequests)
+
+	c, err := NewWithEndpoints(context.Background(), []string{limited.URL, healthy.URL}, WithHealthCheckInterval(0))
+	require.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		slot, err := c.GetSlot(CommitmentProcessed)
+		require.NoError(t, err)
+		assert.EqualValues(t, 2, slot)
+	}
+
+	// The rate limited endpoint is avoided after its first failure
+	assert.True(t, limited.getCalls("getSlot") <= 1)
+	assert.Equal(t, 10, healthy.getCalls("getSlot"))
+}
+
+func TestPool_FailoverOnServiceError(t *testing.T) {
+	for _, code := range []int{rpcNodeUnhealthyCode, rpcInternalErrorCode, blockNotAvailableCode, -32016} {
+		unhealthy := newTestRPCServer(t, 1)
+		healthy := newTestRPCServer(t, 2)
+		unhealthy.setRPCError(&code)
+
+		c, err := NewWithEndpoints(context.Background(), []string{unhealthy.URL, healthy.URL}, WithHealthCheckInterval(0))
+		require.NoError(t, err)
+
+		for i := 0; i < 10; i++ {
+			slot, err := c.GetSlot(CommitmentProcessed)
+			require.NoError(t, err)
+			assert.EqualValues(t, 2, slot)
+		}
+		assert.True(t, unhealthy.getCalls("getSlot") <= 1)
+	}
+
+	unavailable := newTestRPCServer(t, 1)
+	healthy := newTestRPCServer(t, 2)
+	unavailable.setStatusCode(http.StatusBadGateway)
+
+	c, err := NewWithEndpoints(context.Background(), []string{unavailable.URL, healthy.URL}, WithHealthCheckInterval(0))
+	require.NoError(t, err)
+
+	slot, err := c.GetSlot(CommitmentProcessed)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, slot)
+
+	// Connection failures also fail over
+	unavailable.Close()
+	c, err = NewWithEndpoints(context.Background(), []string{unavailable.URL, healthy.URL}, WithHealthCheckInterval(0))
+	require.NoError(t, err)
+
+	slot, err = c.GetSlot(CommitmentProcessed)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, slot)
+}
+
+func TestPool_NoFailoverOnRequestError(t *testing.T) {
+	for _, code := range []int{invalidParamCode, -32002, -32003, -32015} {
+		first := newTestRPCServer(t, 1)
+		second := newTestRPCServer(t, 2)
+
+		first.setRPCError(&code)
+		second.setRPCError(&code)
+
+		c, err := NewWithEndpoints(context.Background(), []string{first.URL, second.URL}, WithHealthCheckInterval(0))
+		require.NoError(t, err)
+
+		_, err = c.GetSlot(CommitmentProcessed)
+		assert.Error(t, err)
+		assert.Equal(t, 1, first.getCalls("getSlot")+second.getCalls("getSlot"))
+	}
+}
+
+func TestPool_AllEndpointsUnavailable(t *testing.T) {
+	first := newTestRPCServer(t, 1)
+	second := newTestRPCServer(t, 2)
+	first.setStatusCode(http.StatusServiceUnavailable)
+	second.setStatusCode(http.StatusServiceUnavailable)
+
+	pool, err := newRPCPool(context.Background(), []string{first.URL, second.URL}, WithHealthCheckInterval(0))
+	require.NoError(t, err)
+
+	_, err = pool.Call("getSlot")
+	assert.Error(t, err)
+	assert.Equal(t, 1, first.getCalls("getSlot"))
+	assert.Equal(t, 1, second.getCalls("getSlot"))
+
+	// Unhealthy endpoints are still used as a last resort
+	second.setStatusCode(http.StatusOK)
+	resp, err := pool.Call("getSlot")
+	require.NoError(t, err)
+	assert.Nil(t, resp.Error)
+}
+
+func TestPool_MethodRateLimits(t *testing.T) {
+	server := newTestRPCServer(t, 1)
+
+	pool, err := newRPCPool(
+		context.Background(),
+		[]string{server.URL},
+		WithHealthCheckInterval(0),
+		WithDefaultRateLimit(100),
+		WithMethodRateLimit("getSlot", 2),
+	)
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, err = pool.Call("getSlot")
+		require.NoError(t, err)
+	}
+
+	_, err = pool.Call("getSlot")
+	assert.Equal(t, errRateLimited, err)
+	assert.Equal(t, 2, server.getCalls("getSlot"))
+
+	// Other methods use the default limit
+	for i := 0; i < 10; i++ {
+		_, err = pool.Call("getHealth")
+		require.NoError(t, err)
+	}
+
+	_, err = newRPCPool(context.Background(), []string{server.URL}, WithMethodRateLimit("getSlot", 0.5))
+	assert.Error(t, err)
+
+	_, err = newRPCPool(context.Background(), nil)
+	assert.Error(t, err)
+}
+
+func TestPool_LatencyWeightedSelection(t *testing.T) {
+	pool, err := newRPCPool(context.Background(), []string{"http://fast", "http://slow", "http://down"}, WithHealthCheckInterval(0))
+	require.NoError(t, err)
+
+	fast, slow, down := pool.endpoints[0], pool.endpoints[1], pool.endpoints[2]
+	fast.onSuccess(10 * time.Millisecond)
+	slow.onSuccess(time.Second)
+	down.onFailure(time.Minute)
+
+	var fastFirst int
+	for i := 0; i < 1000; i++ {
+		ordered := pool.selectEndpoints()
+		require.Len(t, ordered, 3)
+		assert.Equal(t, down, ordered[2])
+
+		if ordered[0] == fast {
+			fastFirst++
+		}
+	}
+	assert.True(t, fastFirst > 900, fastFirst)
+}
+
+func TestPool_HealthChecks(t *testing.T) {
+	first := newTestRPCServer(t, 1)
+	second := newTestRPCServer(t, 2)
+	first.setHealth("behind")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pool, err := newRPCPool(ctx, []string{first.URL, second.URL}, WithHealthCheckInterval(10*time.Millisecond))
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		return !pool.endpoints[0].isHealthy(time.Now()) && pool.endpoints[1].isHealthy(time.Now())
+	}, time.Second, 10*time.Millisecond)
+	assert.True(t, pool.endpoints[1].getLatency() > 0)
+
+	first.setHealth("ok")
+	require.Eventually(t, func() bool {
+		return pool.endpoints[0].isHealthy(time.Now())
+	}, time.Second, 10*time.Millisecond)
+
+	cancel()
+	time.Sleep(50 * time.Millisecond)
+	calls := second.getCalls("getHealth")
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, calls, second.getCalls("getHealth"))
+}
+
+func TestPool_Close(t *testing.T) {
+	server := newTestRPCServer(t, 1)
+
+	c, err := NewWithEndpoints(context.Background(), []string{server.URL}, WithHealthCheckInterval(10*time.Millisecond))
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		return server.getCalls("getHealth") > 0
+	}, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, c.Close())
+	calls := server.getCalls("getHealth")
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, calls, server.getCalls("getHealth"))
+
+	// Closing is idempotent, and the client can still serve requests
+	require.NoError(t, c.Close())
+	slot, err := c.GetSlot(CommitmentProcessed)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, slot)
+}
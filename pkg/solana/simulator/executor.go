@@ -0,0 +1,360 @@
+package simulator
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+
+	"github.com/code-payments/code-server/pkg/solana"
+	address_lookup_table "github.com/code-payments/code-server/pkg/solana/addresslookuptable"
+	compute_budget "github.com/code-payments/code-server/pkg/solana/computebudget"
+	"github.com/code-payments/code-server/pkg/solana/memo"
+	splitter_token "github.com/code-payments/code-server/pkg/solana/splitter"
+	"github.com/code-payments/code-server/pkg/solana/system"
+	timelock_token "github.com/code-payments/code-server/pkg/solana/timelock/v1"
+	"github.com/code-payments/code-server/pkg/solana/token"
+)
+
+// Reference: https://github.com/solana-labs/solana/blob/master/sdk/program/src/system_instruction.rs
+const (
+	systemErrorAccountAlreadyInUse solana.CustomError = iota
+	systemErrorResultWithNegativeLamports
+)
+
+type programHandler func(e *executor, index int) error
+
+var programHandlers = map[string]programHandler{
+	string(system.ProgramKey[:]):                   executeSystemInstruction,
+	string(token.ProgramKey):                       executeTokenInstruction,
+	string(token.AssociatedTokenAccountProgramKey): executeAssociatedTokenAccountInstruction,
+	string(memo.ProgramKey):                        executeNoopInstruction,
+	string(compute_budget.ProgramKey):              executeNoopInstruction,
+	string(address_lookup_table.ProgramKey):        executeAddressLookupTableInstruction,
+	string(timelock_token.PROGRAM_ID):              executeTimelockInstruction,
+	string(splitter_token.PROGRAM_ID):              executeSplitterInstruction,
+}
+
+// executor applies the instructions of a single transaction to a copy of the
+// ledger state, which is only persisted via commit.
+type executor struct {
+	l      *Ledger
+	txn    *loadedTransaction
+	msg    solana.Message
+	slot   uint64
+	nonce  solana.Blockhash
+	loaded map[string]*account
+}
+
+func newExecutor(l *Ledger, txn *loadedTransaction, blockhash solana.Blockhash) *executor {
+	return &executor{
+		l:      l,
+		txn:    txn,
+		msg:    txn.resolved.Message,
+		slot:   l.slot + 1,
+		nonce:  getDurableNonce(blockhash),
+		loaded: make(map[string]*account),
+	}
+}
+
+func (e *executor) collectFee() *solana.TransactionError {
+	payer := e.msg.Accounts[0]
+
+	acc, ok := e.get(payer)
+	if !ok {
+		return solana.NewTransactionError(solana.TransactionErrorAccountNotFound)
+	}
+
+	if !bytes.Equal(acc.owner, system.SystemAccount) || len(acc.data) > 0 {
+		return solana.NewTransactionError(solana.TransactionErrorInvalidAccountForFee)
+	}
+
+	if acc.lamports < e.txn.fee {
+		return solana.NewTransactionError(solana.TransactionErrorInsufficientFundsForFee)
+	}
+
+	acc.lamports -= e.txn.fee
+	if err := e.put(payer, acc); err != nil {
+		return solana.NewTransactionError(solana.TransactionErrorInvalidAccountForFee)
+	}
+	return nil
+}
+
+func (e *executor) execute() *solana.TransactionError {
+	for i := range e.msg.Instructions {
+		if err := e.executeInstruction(i); err != nil {
+			txnErr, parseErr := solana.TransactionErrorFromInstructionError(&solana.InstructionError{
+				Index: i,
+				Err:   err,
+			})
+			if parseErr != nil {
+				return solana.NewTransactionError(solana.TransactionErrorInternal)
+			}
+			return txnErr
+		}
+	}
+	return nil
+}
+
+func (e *executor) executeInstruction(index int) error {
+	program := e.msg.Accounts[e.msg.Instructions[index].ProgramIndex]
+
+	handler, ok := programHandlers[string(program)]
+	if !ok {
+		return errUnsupportedProgram
+	}
+	return handler(e, index)
+}
+
+// commit persists all changes made by the executor to the ledger
+func (e *executor) commit() {
+	for address, acc := range e.loaded {
+		if acc == nil {
+			delete(e.l.accounts, address)
+			continue
+		}
+		e.l.accounts[address] = acc
+	}
+}
+
+// get returns a copy of an account, which must be passed to put for changes
+// to be observed.
+func (e *executor) get(address ed25519.PublicKey) (*account, bool) {
+	if acc, ok := e.loaded[string(address)]; ok {
+		if acc == nil {
+			return nil, false
+		}
+		return acc.clone(), true
+	}
+
+	acc, ok := e.l.accounts[string(address)]
+	if !ok {
+		return nil, false
+	}
+	return acc.clone(), true
+}
+
+func (e *executor) put(address ed25519.PublicKey, acc *account) error {
+	if !e.isWritable(address) {
+		return instructionError(solana.InstructionErrorReadonlyDataModified)
+	}
+
+	e.loaded[string(address)] = acc
+	return nil
+}
+
+func (e *executor) remove(address ed25519.PublicKey) error {
+	if !e.isWritable(address) {
+		return instructionError(solana.InstructionErrorReadonlyDataModified)
+	}
+
+	e.loaded[string(address)] = nil
+	return nil
+}
+
+func (e *executor) isWritable(address ed25519.PublicKey) bool {
+	_, ok := e.txn.writable[string(address)]
+	return ok
+}
+
+func (e *executor) requireSigner(address ed25519.PublicKey) error {
+	if _, ok := e.txn.signers[string(address)]; !ok {
+		return instructionError(solana.InstructionErrorMissingRequiredSignature)
+	}
+	return nil
+}
+
+func (e *executor) transferLamports(from, to ed25519.PublicKey, lamports uint64) error {
+	source, ok := e.get(from)
+	if !ok || source.lamports < lamports {
+		return systemErrorResultWithNegativeLamports
+	}
+	source.lamports -= lamports
+	if err := e.put(from, source); err != nil {
+		return err
+	}
+
+	destination, ok := e.get(to)
+	if !ok {
+		destination = &account{owner: system.SystemAccount}
+	}
+	destination.lamports += lamports
+	return e.put(to, destination)
+}
+
+// createAccount creates a rent exempt account funded by the payer
+func (e *executor) createAccount(payer, address, owner ed25519.PublicKey, data []byte) error {
+	if err := e.requireSigner(payer); err != nil {
+		return err
+	}
+
+	if existing, ok := e.get(address); ok && (existing.lamports > 0 || len(existing.data) > 0) {
+		return systemErrorAccountAlreadyInUse
+	}
+
+	if err := e.transferLamports(payer, address, getMinimumBalanceForRentExemption(uint64(len(data)))); err != nil {
+		return err
+	}
+
+	acc, _ := e.get(address)
+	acc.owner = owner
+	acc.data = data
+	return e.put(address, acc)
+}
+
+// closeAccount deletes an account, and transfers its lamports to the destination
+func (e *executor) closeAccount(address, destination ed25519.PublicKey) error {
+	acc, ok := e.get(address)
+	if !ok {
+		return instructionError(solana.InstructionErrorUninitializedAccount)
+	}
+
+	dest, ok := e.get(destination)
+	if !ok {
+		dest = &account{owner: system.SystemAccount}
+	}
+	dest.lamports += acc.lamports
+	if err := e.put(destination, dest); err != nil {
+		return err
+	}
+
+	return e.remove(address)
+}
+
+func (e *executor) getTokenAccount(address ed25519.PublicKey) (*account, *token.Account, error) {
+	acc, ok := e.get(address)
+	if !ok {
+		return nil, nil, instructionError(solana.InstructionErrorUninitializedAccount)
+	}
+
+	if !bytes.Equal(acc.owner, token.ProgramKey) {
+		return nil, nil, instructionError(solana.InstructionErrorIncorrectProgramID)
+	}
+
+	var tokenAccount token.Account
+	if !tokenAccount.Unmarshal(acc.data) {
+		return nil, nil, instructionError(solana.InstructionErrorInvalidAccountData)
+	}
+
+	if tokenAccount.State == token.AccountStateUninitialized {
+		return nil, nil, instructionError(solana.InstructionErrorUninitializedAccount)
+	}
+
+	return acc, &tokenAccount, nil
+}
+
+func (e *executor) putTokenAccount(address ed25519.PublicKey, acc *account, tokenAccount *token.Account) error {
+	acc.data = tokenAccount.Marshal()
+	return e.put(address, acc)
+}
+
+// createTokenAccount creates a rent exempt, initialized token account
+func (e *executor) createTokenAccount(payer, address, mint, owner ed25519.PublicKey) error {
+	if _, err := e.getMint(mint); err != nil {
+		return err
+	}
+
+	tokenAccount := token.Account{
+		Mint:  mint,
+		Owner: owner,
+		State: token.AccountStateInitialized,
+	}
+	return e.createAccount(payer, address, token.ProgramKey, tokenAccount.Marshal())
+}
+
+// transferTokens moves tokens between accounts. Authority checks are the
+// responsibility of the caller.
+func (e *executor) transferTokens(source, destination ed25519.PublicKey, amount uint64) error {
+	sourceAcc, sourceTokenAccount, err := e.getTokenAccount(source)
+	if err != nil {
+		return err
+	}
+
+	if sourceTokenAccount.State == token.AccountStateFrozen {
+		return token.ErrorAccountFrozen
+	}
+
+	if sourceTokenAccount.Amount < amount {
+		return token.ErrorInsufficientFunds
+	}
+
+	if bytes.Equal(source, destination) {
+		return nil
+	}
+
+	destinationAcc, destinationTokenAccount, err := e.getTokenAccount(destination)
+	if err != nil {
+		return err
+	}
+
+	if destinationTokenAccount.State == token.AccountStateFrozen {
+		return token.ErrorAccountFrozen
+	}
+
+	if !bytes.Equal(sourceTokenAccount.Mint, destinationTokenAccount.Mint) {
+		return token.ErrorMintMismatch
+	}
+
+	sourceTokenAccount.Amount -= amount
+	destinationTokenAccount.Amount += amount
+
+	if err := e.putTokenAccount(source, sourceAcc, sourceTokenAccount); err != nil {
+		return err
+	}
+	return e.putTokenAccount(destination, destinationAcc, destinationTokenAccount)
+}
+
+// burnTokens removes tokens from an account and the mint supply. Authority
+// checks are the responsibility of the caller.
+func (e *executor) burnTokens(address ed25519.PublicKey, amount uint64) error {
+	acc, tokenAccount, err := e.getTokenAccount(address)
+	if err != nil {
+		return err
+	}
+
+	if tokenAccount.Amount < amount {
+		return token.ErrorInsufficientFunds
+	}
+
+	mintAcc, err := e.getMint(tokenAccount.Mint)
+	if err != nil {
+		return err
+	}
+
+	var mint mintAccount
+	mint.unmarshal(mintAcc.data)
+	mint.supply -= amount
+	mintAcc.data = mint.marshal()
+
+	tokenAccount.Amount -= amount
+	if err := e.putTokenAccount(address, acc, tokenAccount); err != nil {
+		return err
+	}
+	return e.put(tokenAccount.Mint, mintAcc)
+}
+
+func (e *executor) getMint(address ed25519.PublicKey) (*account, error) {
+	acc, ok := e.get(address)
+	if !ok || !bytes.Equal(acc.owner, token.ProgramKey) {
+		return nil, token.ErrorInvalidMint
+	}
+
+	var mint mintAccount
+	if !mint.unmarshal(acc.data) {
+		return nil, token.ErrorInvalidMint
+	}
+	return acc, nil
+}
+
+func executeNoopInstruction(_ *executor, _ int) error {
+	return nil
+}
+
+func instructionError(key solana.InstructionErrorKey) error {
+	return errors.New(string(key))
+}
+
+func putUint64(dst []byte, v uint64) {
+	binary.LittleEndian.PutUint64(dst, v)
+}
@@ -0,0 +1,730 @@
+package simulator
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/binary"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mr-tron/base58/base58"
+	"github.com/pkg/errors"
+
+	"github.com/code-payments/code-server/pkg/solana"
+	splitter_token "github.com/code-payments/code-server/pkg/solana/splitter"
+	"github.com/code-payments/code-server/pkg/solana/system"
+	"github.com/code-payments/code-server/pkg/solana/token"
+)
+
+const (
+	// DefaultLamportsPerSignature is the base fee charged per transaction
+	// signature.
+	DefaultLamportsPerSignature = 5000
+
+	// maxRecentBlockhashes is the number of slots a blockhash remains valid for,
+	// which matches the mainnet cluster.
+	maxRecentBlockhashes = 150
+
+	// slotDuration is the amount of time between simulated block times
+	slotDuration = 400 * time.Millisecond
+
+	// Reference: https://github.com/solana-labs/solana/blob/master/sdk/program/src/rent.rs
+	accountStorageOverhead      = 128
+	lamportsPerByteYear         = 3480
+	rentExemptionThresholdYears = 2
+
+	faucetLamports = 500_000_000 * 1_000_000_000 // 500M SOL
+)
+
+type account struct {
+	lamports   uint64
+	owner      ed25519.PublicKey
+	data       []byte
+	executable bool
+}
+
+func (a *account) clone() *account {
+	return &account{
+		lamports:   a.lamports,
+		owner:      a.owner,
+		data:       append([]byte(nil), a.data...),
+		executable: a.executable,
+	}
+}
+
+func (a *account) toAccountInfo() solana.AccountInfo {
+	return solana.AccountInfo{
+		Data:       append([]byte(nil), a.data...),
+		Owner:      a.owner,
+		Lamports:   a.lamports,
+		Executable: a.executable,
+	}
+}
+
+type processedTransaction struct {
+	confirmed     solana.ConfirmedTransaction
+	accounts      []ed25519.PublicKey
+	writable      map[string]struct{}
+	microLamports uint64
+	memo          *string
+}
+
+// Ledger is a deterministic, in-memory implementation of solana.Client that
+// executes the subset of programs used by this repository:
+//
+//   - System (create account, transfer and durable nonce instructions)
+//   - SPL Token and Associated Token Account
+//   - Memo and Compute Budget
+//   - Address Lookup Table
+//   - Timelock v1 and Splitter
+//
+// Every submitted transaction is processed in its own slot and is immediately
+// finalized, so all commitment levels observe the same state. Transactions
+// are executed atomically: when an instruction fails, the fee is still charged
+// and durable nonces are still advanced, and the failure is surfaced through
+// the signature status, mirroring a client that skips preflight checks.
+type Ledger struct {
+	mu sync.RWMutex
+
+	lamportsPerSignature uint64
+	genesisTime          time.Time
+
+	faucet ed25519.PrivateKey
+
+	slot        uint64
+	blockhashes map[solana.Blockhash]uint64
+	blocks      map[uint64]*solana.Block
+
+	accounts     map[string]*account
+	transactions map[solana.Signature]*processedTransaction
+	history      map[string][]solana.Signature
+}
+
+// Option configures a Ledger
+type Option func(l *Ledger)
+
+// WithGenesisTime sets the block time of the first slot. Subsequent slots are
+// produced every 400ms.
+func WithGenesisTime(t time.Time) Option {
+	return func(l *Ledger) {
+		l.genesisTime = t
+	}
+}
+
+// WithLamportsPerSignature sets the base fee charged for each signature in a
+// transaction.
+func WithLamportsPerSignature(lamports uint64) Option {
+	return func(l *Ledger) {
+		l.lamportsPerSignature = lamports
+	}
+}
+
+var _ solana.Client = (*Ledger)(nil)
+
+// NewLedger returns a new Ledger
+func NewLedger(opts ...Option) *Ledger {
+	faucetSeed := sha256.Sum256([]byte("simulator:faucet"))
+
+	l := &Ledger{
+		lamportsPerSignature: DefaultLamportsPerSignature,
+		genesisTime:          time.Now(),
+
+		faucet: ed25519.NewKeyFromSeed(faucetSeed[:]),
+
+		blockhashes: make(map[solana.Blockhash]uint64),
+		blocks:      make(map[uint64]*solana.Block),
+
+		accounts:     make(map[string]*account),
+		transactions: make(map[solana.Signature]*processedTransaction),
+		history:      make(map[string][]solana.Signature),
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	l.accounts[string(l.faucet.Public().(ed25519.PublicKey))] = &account{
+		lamports: faucetLamports,
+		owner:    system.SystemAccount,
+	}
+	l.produceBlock(nil)
+
+	return l
+}
+
+// SetAccount creates or overwrites the account at the provided address
+func (l *Ledger) SetAccount(address ed25519.PublicKey, info solana.AccountInfo) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.accounts[string(address)] = &account{
+		lamports:   info.Lamports,
+		owner:      info.Owner,
+		data:       append([]byte(nil), info.Data...),
+		executable: info.Executable,
+	}
+}
+
+// RemoveAccount deletes the account at the provided address
+func (l *Ledger) RemoveAccount(address ed25519.PublicKey) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.accounts, string(address))
+}
+
+// CreateMint creates an initialized SPL token mint with no supply
+func (l *Ledger) CreateMint(address, authority ed25519.PublicKey, decimals uint8) {
+	mint := mintAccount{
+		authority:     authority,
+		decimals:      decimals,
+		isInitialized: true,
+	}
+
+	l.SetAccount(address, solana.AccountInfo{
+		Owner:    token.ProgramKey,
+		Lamports: getMinimumBalanceForRentExemption(mintAccountSize),
+		Data:     mint.marshal(),
+	})
+}
+
+// CreateTokenAccount creates an initialized SPL token account with the
+// provided balance, which is minted as part of creation.
+func (l *Ledger) CreateTokenAccount(address, mint, owner ed25519.PublicKey, amount uint64) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := l.mintTo(mint, amount); err != nil {
+		return err
+	}
+
+	tokenAccount := token.Account{
+		Mint:   mint,
+		Owner:  owner,
+		Amount: amount,
+		State:  token.AccountStateInitialized,
+	}
+	l.accounts[string(address)] = &account{
+		lamports: getMinimumBalanceForRentExemption(token.AccountSize),
+		owner:    token.ProgramKey,
+		data:     tokenAccount.Marshal(),
+	}
+
+	return nil
+}
+
+// CreateSplitterPool creates an initialized splitter pool account. The pool's
+// vault must be created separately.
+func (l *Ledger) CreateSplitterPool(address ed25519.PublicKey, pool *splitter_token.PoolAccount) {
+	data := marshalSplitterPool(pool)
+
+	l.SetAccount(address, solana.AccountInfo{
+		Owner:    splitter_token.PROGRAM_ID,
+		Lamports: getMinimumBalanceForRentExemption(uint64(len(data))),
+		Data:     data,
+	})
+}
+
+// MintTo mints tokens into an existing token account
+func (l *Ledger) MintTo(address ed25519.PublicKey, amount uint64) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	acc, ok := l.accounts[string(address)]
+	if !ok {
+		return solana.ErrNoAccountInfo
+	}
+
+	var tokenAccount token.Account
+	if !bytes.Equal(acc.owner, token.ProgramKey) || !tokenAccount.Unmarshal(acc.data) {
+		return token.ErrInvalidTokenAccount
+	}
+
+	if err := l.mintTo(tokenAccount.Mint, amount); err != nil {
+		return err
+	}
+
+	tokenAccount.Amount += amount
+	acc.data = tokenAccount.Marshal()
+
+	return nil
+}
+
+func (l *Ledger) mintTo(address ed25519.PublicKey, amount uint64) error {
+	acc, ok := l.accounts[string(address)]
+	if !ok {
+		return errors.New("mint doesn't exist")
+	}
+
+	var mint mintAccount
+	if !bytes.Equal(acc.owner, token.ProgramKey) || !mint.unmarshal(acc.data) {
+		return errors.New("invalid mint account")
+	}
+
+	mint.supply += amount
+	acc.data = mint.marshal()
+
+	return nil
+}
+
+// GetAccountInfo implements solana.Client.GetAccountInfo
+func (l *Ledger) GetAccountInfo(address ed25519.PublicKey, _ solana.Commitment) (solana.AccountInfo, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	acc, ok := l.accounts[string(address)]
+	if !ok {
+		return solana.AccountInfo{}, solana.ErrNoAccountInfo
+	}
+	return acc.toAccountInfo(), nil
+}
+
+// GetAccountDataAfterBlock implements solana.Client.GetAccountDataAfterBlock
+func (l *Ledger) GetAccountDataAfterBlock(address ed25519.PublicKey, slot uint64) ([]byte, uint64, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if slot > l.slot {
+		return nil, 0, solana.ErrStaleData
+	}
+
+	acc, ok := l.accounts[string(address)]
+	if !ok {
+		return nil, l.slot, solana.ErrNoAccountInfo
+	}
+	return append([]byte(nil), acc.data...), l.slot, nil
+}
+
+// GetBalance implements solana.Client.GetBalance
+func (l *Ledger) GetBalance(address ed25519.PublicKey) (uint64, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	acc, ok := l.accounts[string(address)]
+	if !ok {
+		return 0, nil
+	}
+	return acc.lamports, nil
+}
+
+// GetBlock implements solana.Client.GetBlock
+func (l *Ledger) GetBlock(slot uint64) (*solana.Block, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	block, ok := l.blocks[slot]
+	if !ok {
+		return nil, solana.ErrBlockNotAvailable
+	}
+
+	cloned := *block
+	cloned.Transactions = append([]solana.BlockTransaction(nil), block.Transactions...)
+	return &cloned, nil
+}
+
+// GetConfirmedBlock implements solana.Client.GetConfirmedBlock
+func (l *Ledger) GetConfirmedBlock(slot uint64) (*solana.Block, error) {
+	return l.GetBlock(slot)
+}
+
+// GetBlockSignatures implements solana.Client.GetBlockSignatures
+func (l *Ledger) GetBlockSignatures(slot uint64) ([]string, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	block, ok := l.blocks[slot]
+	if !ok {
+		return nil, nil
+	}
+
+	signatures := make([]string, len(block.Transactions))
+	for i, txn := range block.Transactions {
+		signatures[i] = base58.Encode(txn.Transaction.Signature())
+	}
+	return signatures, nil
+}
+
+// GetBlockTime implements solana.Client.GetBlockTime
+func (l *Ledger) GetBlockTime(slot uint64) (time.Time, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	block, ok := l.blocks[slot]
+	if !ok {
+		return time.Time{}, solana.ErrBlockNotAvailable
+	}
+	return *block.BlockTime, nil
+}
+
+// GetConfirmedBlocksWithLimit implements solana.Client.GetConfirmedBlocksWithLimit
+func (l *Ledger) GetConfirmedBlocksWithLimit(start, limit uint64) ([]uint64, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	var slots []uint64
+	for slot := start; slot <= l.slot && uint64(len(slots)) < limit; slot++ {
+		if _, ok := l.blocks[slot]; ok {
+			slots = append(slots, slot)
+		}
+	}
+	return slots, nil
+}
+
+// GetConfirmationStatus implements solana.Client.GetConfirmationStatus
+func (l *Ledger) GetConfirmationStatus(sig solana.Signature, _ solana.Commitment) (bool, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	_, ok := l.transactions[sig]
+	return ok, nil
+}
+
+// GetConfirmedTransaction implements solana.Client.GetConfirmedTransaction
+func (l *Ledger) GetConfirmedTransaction(sig solana.Signature) (solana.ConfirmedTransaction, error) {
+	return l.GetTransaction(sig, solana.CommitmentFinalized)
+}
+
+// GetTransaction implements solana.Client.GetTransaction
+func (l *Ledger) GetTransaction(sig solana.Signature, _ solana.Commitment) (solana.ConfirmedTransaction, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	processed, ok := l.transactions[sig]
+	if !ok {
+		return solana.ConfirmedTransaction{}, solana.ErrSignatureNotFound
+	}
+	return processed.confirmed, nil
+}
+
+// GetTransactionTokenBalances implements solana.Client.GetTransactionTokenBalances
+func (l *Ledger) GetTransactionTokenBalances(sig solana.Signature) (solana.TransactionTokenBalances, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	processed, ok := l.transactions[sig]
+	if !ok {
+		return solana.TransactionTokenBalances{}, solana.ErrSignatureNotFound
+	}
+
+	if processed.confirmed.Err != nil {
+		return solana.TransactionTokenBalances{}, errors.New("transaction has an error")
+	}
+
+	accounts := make([]string, len(processed.accounts))
+	for i, account := range processed.accounts {
+		accounts[i] = base58.Encode(account)
+	}
+
+	return solana.TransactionTokenBalances{
+		Accounts:          accounts,
+		PreTokenBalances:  processed.confirmed.Meta.PreTokenBalances,
+		PostTokenBalances: processed.confirmed.Meta.PostTokenBalances,
+		Slot:              processed.confirmed.Slot,
+	}, nil
+}
+
+// GetMinimumBalanceForRentExemption implements solana.Client.GetMinimumBalanceForRentExemption
+func (l *Ledger) GetMinimumBalanceForRentExemption(size uint64) (uint64, error) {
+	return getMinimumBalanceForRentExemption(size), nil
+}
+
+// GetLatestBlockhash implements solana.Client.GetLatestBlockhash
+func (l *Ledger) GetLatestBlockhash() (solana.Blockhash, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	var bh solana.Blockhash
+	copy(bh[:], l.blocks[l.slot].Hash)
+	return bh, nil
+}
+
+// GetRecentPrioritizationFees implements solana.Client.GetRecentPrioritizationFees
+func (l *Ledger) GetRecentPrioritizationFees(writableAccounts []ed25519.PublicKey) ([]solana.PrioritizationFee, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	var start uint64
+	if l.slot > maxRecentBlockhashes {
+		start = l.slot - maxRecentBlockhashes
+	}
+
+	var res []solana.PrioritizationFee
+	for slot := start; slot <= l.slot; slot++ {
+		block, ok := l.blocks[slot]
+		if !ok {
+			continue
+		}
+
+		fee := solana.PrioritizationFee{Slot: slot}
+		for _, txn := range block.Transactions {
+			processed := l.transactions[txn.Transaction.Signatures[0]]
+
+			locksAccount := len(writableAccounts) == 0
+			for _, account := range writableAccounts {
+				if _, ok := processed.writable[string(account)]; ok {
+					locksAccount = true
+					break
+				}
+			}
+
+			if locksAccount {
+				fee.MicroLamports = processed.microLamports
+			}
+		}
+		res = append(res, fee)
+	}
+	return res, nil
+}
+
+// GetSignatureStatus implements solana.Client.GetSignatureStatus
+func (l *Ledger) GetSignatureStatus(sig solana.Signature, _ solana.Commitment) (*solana.SignatureStatus, error) {
+	statuses, err := l.GetSignatureStatuses([]solana.Signature{sig})
+	if err != nil {
+		return nil, err
+	}
+
+	if statuses[0] == nil {
+		return nil, solana.ErrSignatureNotFound
+	}
+	return statuses[0], nil
+}
+
+// GetSignatureStatuses implements solana.Client.GetSignatureStatuses
+func (l *Ledger) GetSignatureStatuses(sigs []solana.Signature) ([]*solana.SignatureStatus, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	statuses := make([]*solana.SignatureStatus, len(sigs))
+	for i, sig := range sigs {
+		processed, ok := l.transactions[sig]
+		if !ok {
+			continue
+		}
+
+		statuses[i] = &solana.SignatureStatus{
+			Slot:               processed.confirmed.Slot,
+			ErrorResult:        processed.confirmed.Err,
+			ConfirmationStatus: "finalized",
+		}
+	}
+	return statuses, nil
+}
+
+// GetSignaturesForAddress implements solana.Client.GetSignaturesForAddress
+func (l *Ledger) GetSignaturesForAddress(address ed25519.PublicKey, _ solana.Commitment, limit uint64, before, until string) ([]*solana.TransactionSignature, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	// History is stored in ascending order, but is returned newest first
+	history := l.history[string(address)]
+
+	end := len(history)
+	if len(before) > 0 {
+		end = indexOfSignature(history, before)
+		if end < 0 {
+			return nil, nil
+		}
+	}
+
+	start := 0
+	if len(until) > 0 {
+		if idx := indexOfSignature(history, until); idx >= 0 {
+			start = idx + 1
+		}
+	}
+
+	var res []*solana.TransactionSignature
+	for i := end - 1; i >= start; i-- {
+		if limit > 0 && uint64(len(res)) >= limit {
+			break
+		}
+
+		processed := l.transactions[history[i]]
+		res = append(res, &solana.TransactionSignature{
+			Signature: history[i],
+			Slot:      processed.confirmed.Slot,
+			BlockTime: processed.confirmed.BlockTime,
+			Err:       processed.confirmed.Err,
+			Memo:      processed.memo,
+		})
+	}
+	return res, nil
+}
+
+// GetSlot implements solana.Client.GetSlot
+func (l *Ledger) GetSlot(_ solana.Commitment) (uint64, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.slot, nil
+}
+
+// GetTokenAccountBalance implements solana.Client.GetTokenAccountBalance
+func (l *Ledger) GetTokenAccountBalance(address ed25519.PublicKey) (uint64, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	acc, ok := l.accounts[string(address)]
+	if !ok {
+		return 0, solana.ErrNoBalance
+	}
+
+	var tokenAccount token.Account
+	if !bytes.Equal(acc.owner, token.ProgramKey) || !tokenAccount.Unmarshal(acc.data) {
+		return 0, solana.ErrNoBalance
+	}
+	return tokenAccount.Amount, nil
+}
+
+// GetTokenAccountsByOwner implements solana.Client.GetTokenAccountsByOwner
+func (l *Ledger) GetTokenAccountsByOwner(owner, mint ed25519.PublicKey) ([]ed25519.PublicKey, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	var res []ed25519.PublicKey
+	for address, acc := range l.accounts {
+		var tokenAccount token.Account
+		if !bytes.Equal(acc.owner, token.ProgramKey) || !tokenAccount.Unmarshal(acc.data) {
+			continue
+		}
+
+		if bytes.Equal(tokenAccount.Owner, owner) && bytes.Equal(tokenAccount.Mint, mint) {
+			res = append(res, ed25519.PublicKey(address))
+		}
+	}
+
+	sortPublicKeys(res)
+	return res, nil
+}
+
+// GetFilteredProgramAccounts implements solana.Client.GetFilteredProgramAccounts
+func (l *Ledger) GetFilteredProgramAccounts(program ed25519.PublicKey, offset uint, filterValue []byte) ([]string, uint64, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	var keys []ed25519.PublicKey
+	for address, acc := range l.accounts {
+		if !bytes.Equal(acc.owner, program) {
+			continue
+		}
+
+		if int(offset)+len(filterValue) > len(acc.data) {
+			continue
+		}
+
+		if bytes.Equal(acc.data[offset:int(offset)+len(filterValue)], filterValue) {
+			keys = append(keys, ed25519.PublicKey(address))
+		}
+	}
+
+	sortPublicKeys(keys)
+
+	var res []string
+	for _, key := range keys {
+		res = append(res, base58.Encode(key))
+	}
+	return res, l.slot, nil
+}
+
+// RequestAirdrop implements solana.Client.RequestAirdrop. Airdrops are funded
+// by a faucet account via a system transfer, so they're observable like any
+// other transaction.
+func (l *Ledger) RequestAirdrop(address ed25519.PublicKey, lamports uint64, commitment solana.Commitment) (solana.Signature, error) {
+	faucet := l.faucet.Public().(ed25519.PublicKey)
+
+	bh, err := l.GetLatestBlockhash()
+	if err != nil {
+		return solana.Signature{}, err
+	}
+
+	txn := solana.NewTransaction(faucet, transfer(faucet, address, lamports))
+	txn.SetBlockhash(bh)
+	if err := txn.Sign(l.faucet); err != nil {
+		return solana.Signature{}, err
+	}
+
+	return l.SubmitTransaction(txn, commitment)
+}
+
+// AdvanceSlots produces empty blocks, which is useful for expiring blockhashes
+// and lookup table deactivation periods.
+func (l *Ledger) AdvanceSlots(n uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for i := uint64(0); i < n; i++ {
+		l.produceBlock(nil)
+	}
+}
+
+// produceBlock creates the next slot with the provided transactions. It must
+// be called with the lock held.
+func (l *Ledger) produceBlock(txns []solana.BlockTransaction) *solana.Block {
+	var prevHash []byte
+	var parentSlot uint64
+	slot := l.slot
+	if prev, ok := l.blocks[l.slot]; ok {
+		prevHash = prev.Hash
+		parentSlot = l.slot
+		slot++
+	}
+
+	blockhash := getBlockhash(slot)
+	blockTime := l.genesisTime.Add(time.Duration(slot) * slotDuration)
+
+	block := &solana.Block{
+		Hash:         blockhash[:],
+		PrevHash:     prevHash,
+		ParentSlot:   parentSlot,
+		Slot:         slot,
+		BlockTime:    &blockTime,
+		Transactions: txns,
+	}
+
+	l.slot = slot
+	l.blocks[slot] = block
+	l.blockhashes[blockhash] = slot
+
+	return block
+}
+
+// isRecentBlockhash returns whether a blockhash can be used to submit a new
+// transaction. It must be called with the lock held.
+func (l *Ledger) isRecentBlockhash(bh solana.Blockhash) bool {
+	slot, ok := l.blockhashes[bh]
+	if !ok {
+		return false
+	}
+	return slot+maxRecentBlockhashes >= l.slot
+}
+
+func getBlockhash(slot uint64) solana.Blockhash {
+	var slotBytes [8]byte
+	binary.LittleEndian.PutUint64(slotBytes[:], slot)
+	return sha256.Sum256(append([]byte("simulator:blockhash:"), slotBytes[:]...))
+}
+
+func getDurableNonce(bh solana.Blockhash) solana.Blockhash {
+	return sha256.Sum256(append([]byte("DURABLE_NONCE"), bh[:]...))
+}
+
+func getMinimumBalanceForRentExemption(size uint64) uint64 {
+	return (accountStorageOverhead + size) * lamportsPerByteYear * rentExemptionThresholdYears
+}
+
+func indexOfSignature(signatures []solana.Signature, encoded string) int {
+	for i, sig := range signatures {
+		if base58.Encode(sig[:]) == encoded {
+			return i
+		}
+	}
+	return -1
+}
+
+func sortPublicKeys(keys []ed25519.PublicKey) {
+	sort.Slice(keys, func(i, j int) bool {
+		return strings.Compare(base58.Encode(keys[i]), base58.Encode(keys[j])) < 0
+	})
+}
@@ -0,0 +1,788 @@
+package simulator
+
+import (
+	"crypto/ed25519"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/code-payments/code-server/pkg/solana"
+	address_lookup_table "github.com/code-payments/code-server/pkg/solana/addresslookuptable"
+	compute_budget "github.com/code-payments/code-server/pkg/solana/computebudget"
+	"github.com/code-payments/code-server/pkg/solana/memo"
+	splitter_token "github.com/code-payments/code-server/pkg/solana/splitter"
+	"github.com/code-payments/code-server/pkg/solana/system"
+	timelock_token "github.com/code-payments/code-server/pkg/solana/timelock/v1"
+	"github.com/code-payments/code-server/pkg/solana/token"
+)
+
+const testAirdropAmount = 10_000_000_000
+
+func TestLedger_AirdropAndTransfer(t *testing.T) {
+	l := NewLedger()
+
+	sender := newFundedKey(t, l)
+	receiver := newKey(t)
+
+	balance, err := l.GetBalance(sender.Public().(ed25519.PublicKey))
+	require.NoError(t, err)
+	assert.EqualValues(t, testAirdropAmount, balance)
+
+	txn := newSignedTransaction(t, l, sender, []solana.Instruction{
+		memo.Instruction("hello"),
+		transfer(sender.Public().(ed25519.PublicKey), receiver.Public().(ed25519.PublicKey), 1_000),
+	})
+	sig, err := l.SubmitTransaction(txn, solana.CommitmentFinalized)
+	require.NoError(t, err)
+
+	balance, err = l.GetBalance(sender.Public().(ed25519.PublicKey))
+	require.NoError(t, err)
+	assert.EqualValues(t, testAirdropAmount-1_000-DefaultLamportsPerSignature, balance)
+
+	balance, err = l.GetBalance(receiver.Public().(ed25519.PublicKey))
+	require.NoError(t, err)
+	assert.EqualValues(t, 1_000, balance)
+
+	status, err := l.GetSignatureStatus(sig, solana.CommitmentFinalized)
+	require.NoError(t, err)
+	assert.Nil(t, status.ErrorResult)
+	assert.True(t, status.Finalized())
+
+	confirmed, err := l.GetTransaction(sig, solana.CommitmentFinalized)
+	require.NoError(t, err)
+	assert.Nil(t, confirmed.Err)
+	assert.EqualValues(t, DefaultLamportsPerSignature, confirmed.Meta.Fee)
+
+	history, err := l.GetSignaturesForAddress(receiver.Public().(ed25519.PublicKey), solana.CommitmentFinalized, 10, "", "")
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, sig, history[0].Signature)
+	require.NotNil(t, history[0].Memo)
+	assert.Equal(t, "[5] hello", *history[0].Memo)
+
+	// Resubmission is a no-op
+	_, err = l.SubmitTransaction(txn, solana.CommitmentFinalized)
+	require.NoError(t, err)
+	balance, err = l.GetBalance(receiver.Public().(ed25519.PublicKey))
+	require.NoError(t, err)
+	assert.EqualValues(t, 1_000, balance)
+
+	_, err = l.GetTransaction(solana.Signature{}, solana.CommitmentFinalized)
+	assert.Equal(t, solana.ErrSignatureNotFound, err)
+
+	statuses, err := l.GetSignatureStatuses([]solana.Signature{sig, {}})
+	require.NoError(t, err)
+	require.Len(t, statuses, 2)
+	assert.NotNil(t, statuses[0])
+	assert.Nil(t, statuses[1])
+}
+
+func TestLedger_RejectedTransactions(t *testing.T) {
+	l := NewLedger()
+
+	sender := newFundedKey(t, l)
+	unfunded := newKey(t)
+	receiver := newKey(t)
+
+	// Invalid signature
+	txn := newSignedTransaction(t, l, sender, []solana.Instruction{
+		transfer(sender.Public().(ed25519.PublicKey), receiver.Public().(ed25519.PublicKey), 1),
+	})
+	txn.Signatures[0][0] ^= 0xff
+	_, err := l.SubmitTransaction(txn, solana.CommitmentFinalized)
+	assertTransactionError(t, err, solana.TransactionErrorSignatureFailure)
+
+	// Unknown fee payer
+	txn = newSignedTransaction(t, l, unfunded, []solana.Instruction{
+		transfer(unfunded.Public().(ed25519.PublicKey), receiver.Public().(ed25519.PublicKey), 1),
+	})
+	_, err = l.SubmitTransaction(txn, solana.CommitmentFinalized)
+	assertTransactionError(t, err, solana.TransactionErrorAccountNotFound)
+
+	// Expired blockhash
+	txn = newSignedTransaction(t, l, sender, []solana.Instruction{
+		transfer(sender.Public().(ed25519.PublicKey), receiver.Public().(ed25519.PublicKey), 1),
+	})
+	l.AdvanceSlots(maxRecentBlockhashes + 1)
+	_, err = l.SubmitTransaction(txn, solana.CommitmentFinalized)
+	assertTransactionError(t, err, solana.TransactionErrorBlockhashNotFound)
+
+	balance, err := l.GetBalance(receiver.Public().(ed25519.PublicKey))
+	require.NoError(t, err)
+	assert.EqualValues(t, 0, balance)
+}
+
+func TestLedger_FailedTransactionChargesFee(t *testing.T) {
+	l := NewLedger()
+
+	sender := newFundedKey(t, l)
+	receiver := newKey(t)
+
+	txn := newSignedTransaction(t, l, sender, []solana.Instruction{
+		compute_budget.SetComputeUnitLimit(10_000),
+		compute_budget.SetComputeUnitPrice(1_000_000),
+		transfer(sender.Public().(ed25519.PublicKey), receiver.Public().(ed25519.PublicKey), 1),
+		transfer(sender.Public().(ed25519.PublicKey), receiver.Public().(ed25519.PublicKey), 2*testAirdropAmount),
+	})
+	sig, err := l.SubmitTransaction(txn, solana.CommitmentFinalized)
+	require.NoError(t, err)
+
+	status, err := l.GetSignatureStatus(sig, solana.CommitmentFinalized)
+	require.NoError(t, err)
+	require.NotNil(t, status.ErrorResult)
+	require.NotNil(t, status.ErrorResult.InstructionError())
+	assert.Equal(t, 3, status.ErrorResult.InstructionError().Index)
+
+	expectedFee := DefaultLamportsPerSignature + compute_budget.GetPriorityFee(10_000, 1_000_000)
+
+	balance, err := l.GetBalance(sender.Public().(ed25519.PublicKey))
+	require.NoError(t, err)
+	assert.EqualValues(t, testAirdropAmount-expectedFee, balance)
+
+	// Earlier successful instructions are rolled back
+	balance, err = l.GetBalance(receiver.Public().(ed25519.PublicKey))
+	require.NoError(t, err)
+	assert.EqualValues(t, 0, balance)
+
+	fees, err := l.GetRecentPrioritizationFees([]ed25519.PublicKey{receiver.Public().(ed25519.PublicKey)})
+	require.NoError(t, err)
+	var maxFee uint64
+	for _, fee := range fees {
+		if fee.MicroLamports > maxFee {
+			maxFee = fee.MicroLamports
+		}
+	}
+	assert.EqualValues(t, 1_000_000, maxFee)
+}
+
+func TestLedger_DurableNonce(t *testing.T) {
+	l := NewLedger()
+
+	authority := newFundedKey(t, l)
+	nonce := newKey(t)
+	receiver := newKey(t)
+
+	txn := newSignedTransaction(t, l, authority, []solana.Instruction{
+		system.CreateAccount(
+			authority.Public().(ed25519.PublicKey),
+			nonce.Public().(ed25519.PublicKey),
+			system.SystemAccount,
+			getMinimumBalanceForRentExemption(system.NonceAccountSize),
+			system.NonceAccountSize,
+		),
+		system.InitializeNonce(nonce.Public().(ed25519.PublicKey), authority.Public().(ed25519.PublicKey)),
+	}, nonce)
+	_, err := l.SubmitTransaction(txn, solana.CommitmentFinalized)
+	require.NoError(t, err)
+
+	info, err := l.GetAccountInfo(nonce.Public().(ed25519.PublicKey), solana.CommitmentFinalized)
+	require.NoError(t, err)
+	nonceValue, err := system.GetNonceValueFromAccount(info)
+	require.NoError(t, err)
+
+	// Durable nonces outlive recent blockhashes
+	l.AdvanceSlots(2 * maxRecentBlockhashes)
+
+	nonced := solana.NewTransaction(
+		authority.Public().(ed25519.PublicKey),
+		system.AdvanceNonce(nonce.Public().(ed25519.PublicKey), authority.Public().(ed25519.PublicKey)),
+		transfer(authority.Public().(ed25519.PublicKey), receiver.Public().(ed25519.PublicKey), 1_000),
+	)
+	nonced.SetBlockhash(nonceValue)
+	require.NoError(t, nonced.Sign(authority))
+
+	_, err = l.SubmitTransaction(nonced, solana.CommitmentFinalized)
+	require.NoError(t, err)
+
+	balance, err := l.GetBalance(receiver.Public().(ed25519.PublicKey))
+	require.NoError(t, err)
+	assert.EqualValues(t, 1_000, balance)
+
+	info, err = l.GetAccountInfo(nonce.Public().(ed25519.PublicKey), solana.CommitmentFinalized)
+	require.NoError(t, err)
+	advancedValue, err := system.GetNonceValueFromAccount(info)
+	require.NoError(t, err)
+	assert.NotEqual(t, nonceValue, advancedValue)
+
+	// The previous nonce value can no longer be used
+	replay := solana.NewTransaction(
+		authority.Public().(ed25519.PublicKey),
+		system.AdvanceNonce(nonce.Public().(ed25519.PublicKey), authority.Public().(ed25519.PublicKey)),
+		transfer(authority.Public().(ed25519.PublicKey), receiver.Public().(ed25519.PublicKey), 2_000),
+	)
+	replay.SetBlockhash(nonceValue)
+	require.NoError(t, replay.Sign(authority))
+	_, err = l.SubmitTransaction(replay, solana.CommitmentFinalized)
+	assertTransactionError(t, err, solana.TransactionErrorBlockhashNotFound)
+
+	// Failed nonced transactions still advance the nonce
+	failed := solana.NewTransaction(
+		authority.Public().(ed25519.PublicKey),
+		system.AdvanceNonce(nonce.Public().(ed25519.PublicKey), authority.Public().(ed25519.PublicKey)),
+		transfer(authority.Public().(ed25519.PublicKey), receiver.Public().(ed25519.PublicKey), 2*testAirdropAmount),
+	)
+	failed.SetBlockhash(advancedValue)
+	require.NoError(t, failed.Sign(authority))
+	sig, err := l.SubmitTransaction(failed, solana.CommitmentFinalized)
+	require.NoError(t, err)
+
+	status, err := l.GetSignatureStatus(sig, solana.CommitmentFinalized)
+	require.NoError(t, err)
+	assert.NotNil(t, status.ErrorResult)
+
+	info, err = l.GetAccountInfo(nonce.Public().(ed25519.PublicKey), solana.CommitmentFinalized)
+	require.NoError(t, err)
+	latestValue, err := system.GetNonceValueFromAccount(info)
+	require.NoError(t, err)
+	assert.NotEqual(t, advancedValue, latestValue)
+}
+
+func TestLedger_TokenAccounts(t *testing.T) {
+	l := NewLedger()
+
+	payer := newFundedKey(t, l)
+	owner := newKey(t)
+	mint := newKey(t).Public().(ed25519.PublicKey)
+
+	l.CreateMint(mint, payer.Public().(ed25519.PublicKey), 6)
+
+	source := newKey(t).Public().(ed25519.PublicKey)
+	require.NoError(t, l.CreateTokenAccount(source, mint, owner.Public().(ed25519.PublicKey), 100))
+
+	createAta, ata, err := token.CreateAssociatedTokenAccount(payer.Public().(ed25519.PublicKey), payer.Public().(ed25519.PublicKey), mint)
+	require.NoError(t, err)
+
+	txn := newSignedTransaction(t, l, payer, []solana.Instruction{
+		createAta,
+		token.Transfer(source, ata, owner.Public().(ed25519.PublicKey), 40),
+	}, owner)
+	sig, err := l.SubmitTransaction(txn, solana.CommitmentFinalized)
+	require.NoError(t, err)
+	assertSuccessful(t, l, sig)
+
+	balance, err := l.GetTokenAccountBalance(source)
+	require.NoError(t, err)
+	assert.EqualValues(t, 60, balance)
+
+	balance, err = l.GetTokenAccountBalance(ata)
+	require.NoError(t, err)
+	assert.EqualValues(t, 40, balance)
+
+	accounts, err := l.GetTokenAccountsByOwner(payer.Public().(ed25519.PublicKey), mint)
+	require.NoError(t, err)
+	require.Len(t, accounts, 1)
+	assert.EqualValues(t, ata, accounts[0])
+
+	tokenBalances, err := l.GetTransactionTokenBalances(sig)
+	require.NoError(t, err)
+	assert.Len(t, tokenBalances.PreTokenBalances, 1)
+	assert.Len(t, tokenBalances.PostTokenBalances, 2)
+
+	// Accounts with a balance can't be closed
+	txn = newSignedTransaction(t, l, payer, []solana.Instruction{
+		token.CloseAccount(ata, payer.Public().(ed25519.PublicKey), payer.Public().(ed25519.PublicKey)),
+	})
+	sig, err = l.SubmitTransaction(txn, solana.CommitmentFinalized)
+	require.NoError(t, err)
+	assertCustomError(t, l, sig, token.ErrorNonNativeHasBalance)
+
+	// Transfers require the owner's signature
+	txn = newSignedTransaction(t, l, payer, []solana.Instruction{
+		token.Transfer(source, ata, payer.Public().(ed25519.PublicKey), 1),
+	})
+	sig, err = l.SubmitTransaction(txn, solana.CommitmentFinalized)
+	require.NoError(t, err)
+	assertCustomError(t, l, sig, token.ErrorOwnerMismatch)
+
+	txn = newSignedTransaction(t, l, payer, []solana.Instruction{
+		token.Transfer(ata, source, payer.Public().(ed25519.PublicKey), 40),
+		token.CloseAccount(ata, payer.Public().(ed25519.PublicKey), payer.Public().(ed25519.PublicKey)),
+	})
+	sig, err = l.SubmitTransaction(txn, solana.CommitmentFinalized)
+	require.NoError(t, err)
+	assertSuccessful(t, l, sig)
+
+	_, err = l.GetAccountInfo(ata, solana.CommitmentFinalized)
+	assert.Equal(t, solana.ErrNoAccountInfo, err)
+}
+
+func TestLedger_Timelock(t *testing.T) {
+	l := NewLedger()
+
+	subsidizer := newFundedKey(t, l)
+	owner := newKey(t)
+	destinationOwner := newKey(t)
+	mint := newKey(t).Public().(ed25519.PublicKey)
+
+	l.CreateMint(mint, subsidizer.Public().(ed25519.PublicKey), 6)
+
+	destination := newKey(t).Public().(ed25519.PublicKey)
+	require.NoError(t, l.CreateTokenAccount(destination, mint, destinationOwner.Public().(ed25519.PublicKey), 0))
+
+	state, _, err := timelock_token.GetStateAddress(&timelock_token.GetStateAddressArgs{
+		Mint:          mint,
+		TimeAuthority: subsidizer.Public().(ed25519.PublicKey),
+		VaultOwner:    owner.Public().(ed25519.PublicKey),
+		NumDaysLocked: timelock_token.DefaultNumDaysLocked,
+	})
+	require.NoError(t, err)
+	vault, _, err := timelock_token.GetVaultAddress(&timelock_token.GetVaultAddressArgs{
+		State:       state,
+		DataVersion: timelock_token.DataVersion1,
+	})
+	require.NoError(t, err)
+
+	txn := newSignedTransaction(t, l, subsidizer, []solana.Instruction{
+		timelock_token.NewInitializeInstruction(
+			&timelock_token.InitializeInstructionAccounts{
+				Timelock:      state,
+				Vault:         vault,
+				VaultOwner:    owner.Public().(ed25519.PublicKey),
+				Mint:          mint,
+				TimeAuthority: subsidizer.Public().(ed25519.PublicKey),
+				Payer:         subsidizer.Public().(ed25519.PublicKey),
+			},
+			&timelock_token.InitializeInstructionArgs{
+				NumDaysLocked: timelock_token.DefaultNumDaysLocked,
+			},
+		).ToLegacyInstruction(),
+	})
+	sig, err := l.SubmitTransaction(txn, solana.CommitmentFinalized)
+	require.NoError(t, err)
+	assertSuccessful(t, l, sig)
+
+	require.NoError(t, l.MintTo(vault, 100))
+
+	transferWithAuthority := func(amount uint64) solana.Instruction {
+		return timelock_token.NewTransferWithAuthorityInstruction(
+			&timelock_token.TransferWithAuthorityInstructionAccounts{
+				Timelock:      state,
+				Vault:         vault,
+				VaultOwner:    owner.Public().(ed25519.PublicKey),
+				TimeAuthority: subsidizer.Public().(ed25519.PublicKey),
+				Destination:   destination,
+				Payer:         subsidizer.Public().(ed25519.PublicKey),
+			},
+			&timelock_token.TransferWithAuthorityInstructionArgs{
+				Amount: amount,
+			},
+		).ToLegacyInstruction()
+	}
+
+	txn = newSignedTransaction(t, l, subsidizer, []solana.Instruction{transferWithAuthority(30)}, owner)
+	sig, err = l.SubmitTransaction(txn, solana.CommitmentFinalized)
+	require.NoError(t, err)
+	assertSuccessful(t, l, sig)
+
+	txn = newSignedTransaction(t, l, subsidizer, []solana.Instruction{transferWithAuthority(1_000)}, owner)
+	sig, err = l.SubmitTransaction(txn, solana.CommitmentFinalized)
+	require.NoError(t, err)
+	assertCustomError(t, l, sig, solana.CustomError(timelock_token.ErrInsufficientVaultBalance))
+
+	balance, err := l.GetTokenAccountBalance(destination)
+	require.NoError(t, err)
+	assert.EqualValues(t, 30, balance)
+
+	txn = newSignedTransaction(t, l, subsidizer, []solana.Instruction{
+		timelock_token.NewRevokeLockWithAuthorityInstruction(
+			&timelock_token.RevokeLockWithAuthorityInstructionAccounts{
+				Timelock:      state,
+				Vault:         vault,
+				TimeAuthority: subsidizer.Public().(ed25519.PublicKey),
+				Payer:         subsidizer.Public().(ed25519.PublicKey),
+			},
+			&timelock_token.RevokeLockWithAuthorityInstructionArgs{},
+		).ToLegacyInstruction(),
+		timelock_token.NewDeactivateInstruction(
+			&timelock_token.DeactivateInstructionAccounts{
+				Timelock:   state,
+				VaultOwner: owner.Public().(ed25519.PublicKey),
+				Payer:      subsidizer.Public().(ed25519.PublicKey),
+			},
+			&timelock_token.DeactivateInstructionArgs{},
+		).ToLegacyInstruction(),
+		timelock_token.NewWithdrawInstruction(
+			&timelock_token.WithdrawInstructionAccounts{
+				Timelock:    state,
+				Vault:       vault,
+				VaultOwner:  owner.Public().(ed25519.PublicKey),
+				Destination: destination,
+				Payer:       subsidizer.Public().(ed25519.PublicKey),
+			},
+			&timelock_token.WithdrawInstructionArgs{},
+		).ToLegacyInstruction(),
+		timelock_token.NewCloseAccountsInstruction(
+			&timelock_token.CloseAccountsInstructionAccounts{
+				Timelock:       state,
+				Vault:          vault,
+				CloseAuthority: subsidizer.Public().(ed25519.PublicKey),
+				Payer:          subsidizer.Public().(ed25519.PublicKey),
+			},
+			&timelock_token.CloseAccountsInstructionArgs{},
+		).ToLegacyInstruction(),
+	}, owner)
+	sig, err = l.SubmitTransaction(txn, solana.CommitmentFinalized)
+	require.NoError(t, err)
+	assertSuccessful(t, l, sig)
+
+	balance, err = l.GetTokenAccountBalance(destination)
+	require.NoError(t, err)
+	assert.EqualValues(t, 100, balance)
+
+	_, err = l.GetAccountInfo(state, solana.CommitmentFinalized)
+	assert.Equal(t, solana.ErrNoAccountInfo, err)
+	_, err = l.GetAccountInfo(vault, solana.CommitmentFinalized)
+	assert.Equal(t, solana.ErrNoAccountInfo, err)
+}
+
+func TestLedger_Splitter(t *testing.T) {
+	l := NewLedger()
+
+	authority := newFundedKey(t, l)
+	destinationOwner := newKey(t)
+	mint := newKey(t).Public().(ed25519.PublicKey)
+	commitment := newKey(t).Public().(ed25519.PublicKey)
+
+	l.CreateMint(mint, authority.Public().(ed25519.PublicKey), 6)
+
+	pool, vault := createSplitterPool(t, l, authority.Public().(ed25519.PublicKey), mint, 2, 100)
+
+	destination := newKey(t).Public().(ed25519.PublicKey)
+	require.NoError(t, l.CreateTokenAccount(destination, mint, destinationOwner.Public().(ed25519.PublicKey), 0))
+
+	initialPool := getSplitterPool(t, l, pool)
+
+	txn := newSignedTransaction(t, l, authority, []solana.Instruction{
+		splitter_token.NewTransferWithCommitmentInstruction(
+			&splitter_token.TransferWithCommitmentInstructionAccounts{
+				Pool:        pool,
+				Vault:       vault,
+				Destination: destination,
+				Commitment:  commitment,
+				Authority:   authority.Public().(ed25519.PublicKey),
+				Payer:       authority.Public().(ed25519.PublicKey),
+			},
+			&splitter_token.TransferWithCommitmentInstructionArgs{
+				Amount:     40,
+				Transcript: make(splitter_token.Hash, splitter_token.HashSize),
+				RecentRoot: initialPool.HistoryList[initialPool.CurrentIndex],
+			},
+		).ToLegacyInstruction(),
+		splitter_token.NewSaveRecentRootInstruction(
+			&splitter_token.SaveRecentRootInstructionAccounts{
+				Pool:      pool,
+				Authority: authority.Public().(ed25519.PublicKey),
+				Payer:     authority.Public().(ed25519.PublicKey),
+			},
+			&splitter_token.SaveRecentRootInstructionArgs{},
+		).ToLegacyInstruction(),
+	})
+	sig, err := l.SubmitTransaction(txn, solana.CommitmentFinalized)
+	require.NoError(t, err)
+	assertSuccessful(t, l, sig)
+
+	balance, err := l.GetTokenAccountBalance(destination)
+	require.NoError(t, err)
+	assert.EqualValues(t, 40, balance)
+
+	updatedPool := getSplitterPool(t, l, pool)
+	assert.EqualValues(t, 1, updatedPool.MerkleTree.NextIndex)
+	assert.EqualValues(t, initialPool.CurrentIndex+1, updatedPool.CurrentIndex)
+	assert.Equal(t, updatedPool.MerkleTree.Root, updatedPool.HistoryList[updatedPool.CurrentIndex])
+
+	root := updatedPool.MerkleTree.Root
+	proofData := []splitter_token.Hash{updatedPool.MerkleTree.ZeroValues[0], updatedPool.MerkleTree.ZeroValues[1]}
+
+	proof, _, err := splitter_token.GetProofAddress(&splitter_token.GetProofAddressArgs{
+		Pool:       pool,
+		MerkleRoot: root,
+		Commitment: commitment,
+	})
+	require.NoError(t, err)
+	commitmentVault, _, err := splitter_token.GetCommitmentVaultAddress(&splitter_token.GetCommitmentVaultAddressArgs{
+		Pool:       pool,
+		Commitment: commitment,
+	})
+	require.NoError(t, err)
+
+	txn = newSignedTransaction(t, l, authority, []solana.Instruction{
+		splitter_token.NewInitializeProofInstruction(
+			&splitter_token.InitializeProofInstructionAccounts{
+				Pool:      pool,
+				Proof:     proof,
+				Authority: authority.Public().(ed25519.PublicKey),
+				Payer:     authority.Public().(ed25519.PublicKey),
+			},
+			&splitter_token.InitializeProofInstructionArgs{
+				MerkleRoot: root,
+				Commitment: commitment,
+			},
+		).ToLegacyInstruction(),
+		splitter_token.NewUploadProofInstruction(
+			&splitter_token.UploadProofInstructionAccounts{
+				Pool:      pool,
+				Proof:     proof,
+				Authority: authority.Public().(ed25519.PublicKey),
+				Payer:     authority.Public().(ed25519.PublicKey),
+			},
+			&splitter_token.UploadProofInstructionArgs{
+				CurrentSize: 0,
+				DataSize:    uint8(len(proofData)),
+				Data:        proofData,
+			},
+		).ToLegacyInstruction(),
+		splitter_token.NewVerifyProofInstruction(
+			&splitter_token.VerifyProofInstructionAccounts{
+				Pool:      pool,
+				Proof:     proof,
+				Authority: authority.Public().(ed25519.PublicKey),
+				Payer:     authority.Public().(ed25519.PublicKey),
+			},
+			&splitter_token.VerifyProofInstructionArgs{},
+		).ToLegacyInstruction(),
+		splitter_token.NewOpenTokenAccountInstruction(
+			&splitter_token.OpenTokenAccountInstructionAccounts{
+				Pool:            pool,
+				Proof:           proof,
+				CommitmentVault: commitmentVault,
+				Mint:            mint,
+				Authority:       authority.Public().(ed25519.PublicKey),
+				Payer:           authority.Public().(ed25519.PublicKey),
+			},
+			&splitter_token.OpenTokenAccountInstructionArgs{},
+		).ToLegacyInstruction(),
+	})
+	sig, err = l.SubmitTransaction(txn, solana.CommitmentFinalized)
+	require.NoError(t, err)
+	assertSuccessful(t, l, sig)
+
+	require.NoError(t, l.MintTo(commitmentVault, 5))
+
+	txn = newSignedTransaction(t, l, authority, []solana.Instruction{
+		splitter_token.NewCloseTokenAccountInstruction(
+			&splitter_token.CloseTokenAccountInstructionAccounts{
+				Pool:            pool,
+				Proof:           proof,
+				CommitmentVault: commitmentVault,
+				PoolVault:       vault,
+				Authority:       authority.Public().(ed25519.PublicKey),
+				Payer:           authority.Public().(ed25519.PublicKey),
+			},
+			&splitter_token.CloseTokenAccountInstructionArgs{},
+		).ToLegacyInstruction(),
+		splitter_token.NewCloseProofInstruction(
+			&splitter_token.CloseProofInstructionAccounts{
+				Pool:      pool,
+				Proof:     proof,
+				Authority: authority.Public().(ed25519.PublicKey),
+				Payer:     authority.Public().(ed25519.PublicKey),
+			},
+			&splitter_token.CloseProofInstructionArgs{},
+		).ToLegacyInstruction(),
+	})
+	sig, err = l.SubmitTransaction(txn, solana.CommitmentFinalized)
+	require.NoError(t, err)
+	assertSuccessful(t, l, sig)
+
+	balance, err = l.GetTokenAccountBalance(vault)
+	require.NoError(t, err)
+	assert.EqualValues(t, 65, balance)
+
+	_, err = l.GetAccountInfo(commitmentVault, solana.CommitmentFinalized)
+	assert.Equal(t, solana.ErrNoAccountInfo, err)
+	_, err = l.GetAccountInfo(proof, solana.CommitmentFinalized)
+	assert.Equal(t, solana.ErrNoAccountInfo, err)
+}
+
+func TestLedger_AddressLookupTables(t *testing.T) {
+	l := NewLedger()
+
+	authority := newFundedKey(t, l)
+	receivers := []ed25519.PublicKey{newKey(t).Public().(ed25519.PublicKey), newKey(t).Public().(ed25519.PublicKey)}
+
+	slot, err := l.GetSlot(solana.CommitmentFinalized)
+	require.NoError(t, err)
+
+	create, table, err := address_lookup_table.CreateLookupTable(authority.Public().(ed25519.PublicKey), authority.Public().(ed25519.PublicKey), slot)
+	require.NoError(t, err)
+
+	txn := newSignedTransaction(t, l, authority, []solana.Instruction{
+		create,
+		address_lookup_table.ExtendLookupTable(table, authority.Public().(ed25519.PublicKey), authority.Public().(ed25519.PublicKey), receivers),
+	})
+	sig, err := l.SubmitTransaction(txn, solana.CommitmentFinalized)
+	require.NoError(t, err)
+	assertSuccessful(t, l, sig)
+
+	info, err := l.GetAccountInfo(table, solana.CommitmentFinalized)
+	require.NoError(t, err)
+	lookupTable, err := address_lookup_table.GetLookupTableFromAccount(table, info)
+	require.NoError(t, err)
+	require.Len(t, lookupTable.Addresses, 2)
+
+	bh, err := l.GetLatestBlockhash()
+	require.NoError(t, err)
+
+	versioned := solana.NewVersionedTransaction(
+		authority.Public().(ed25519.PublicKey),
+		[]solana.AddressLookupTable{*lookupTable},
+		transfer(authority.Public().(ed25519.PublicKey), receivers[0], 1_000),
+		transfer(authority.Public().(ed25519.PublicKey), receivers[1], 2_000),
+	)
+	versioned.SetBlockhash(bh)
+	require.NoError(t, versioned.Sign(authority))
+
+	sig, err = l.SubmitTransaction(versioned, solana.CommitmentFinalized)
+	require.NoError(t, err)
+	assertSuccessful(t, l, sig)
+
+	for i, expected := range []uint64{1_000, 2_000} {
+		balance, err := l.GetBalance(receivers[i])
+		require.NoError(t, err)
+		assert.Equal(t, expected, balance)
+	}
+
+	confirmed, err := l.GetTransaction(sig, solana.CommitmentFinalized)
+	require.NoError(t, err)
+	assert.Len(t, confirmed.Meta.LoadedAddresses.Writable, 2)
+
+	closeTable := address_lookup_table.CloseLookupTable(table, authority.Public().(ed25519.PublicKey), authority.Public().(ed25519.PublicKey))
+
+	txn = newSignedTransaction(t, l, authority, []solana.Instruction{
+		address_lookup_table.DeactivateLookupTable(table, authority.Public().(ed25519.PublicKey)),
+	})
+	sig, err = l.SubmitTransaction(txn, solana.CommitmentFinalized)
+	require.NoError(t, err)
+	assertSuccessful(t, l, sig)
+
+	// Deactivated tables can't be closed until the deactivation slot is no
+	// longer recent
+	txn = newSignedTransaction(t, l, authority, []solana.Instruction{closeTable})
+	sig, err = l.SubmitTransaction(txn, solana.CommitmentFinalized)
+	require.NoError(t, err)
+	status, err := l.GetSignatureStatus(sig, solana.CommitmentFinalized)
+	require.NoError(t, err)
+	assert.NotNil(t, status.ErrorResult)
+
+	l.AdvanceSlots(maxRecentBlockhashes)
+
+	txn = newSignedTransaction(t, l, authority, []solana.Instruction{closeTable})
+	sig, err = l.SubmitTransaction(txn, solana.CommitmentFinalized)
+	require.NoError(t, err)
+	assertSuccessful(t, l, sig)
+
+	_, err = l.GetAccountInfo(table, solana.CommitmentFinalized)
+	assert.Equal(t, solana.ErrNoAccountInfo, err)
+}
+
+func createSplitterPool(t *testing.T, l *Ledger, authority, mint ed25519.PublicKey, levels uint8, balance uint64) (ed25519.PublicKey, ed25519.PublicKey) {
+	name := "test-pool"
+
+	pool, _, err := splitter_token.GetPoolStateAddress(&splitter_token.GetPoolStateAddressArgs{
+		Mint:      mint,
+		Authority: authority,
+		Name:      name,
+	})
+	require.NoError(t, err)
+	vault, vaultBump, err := splitter_token.GetPoolVaultAddress(&splitter_token.GetPoolVaultAddressArgs{
+		Pool: pool,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, l.CreateTokenAccount(vault, mint, pool, balance))
+
+	zeroValues := make([]splitter_token.Hash, levels)
+	current := merkleHash([]byte(name))
+	for i := range zeroValues {
+		current = merkleHashLeftRight(current, current)
+		zeroValues[i] = current
+	}
+
+	root := merkleHashLeftRight(zeroValues[levels-1], zeroValues[levels-1])
+	historyList := make([]splitter_token.Hash, splitter_token.MaxHistory)
+	for i := range historyList {
+		historyList[i] = make(splitter_token.Hash, splitter_token.HashSize)
+	}
+	historyList[0] = root
+
+	poolAccount := &splitter_token.PoolAccount{
+		DataVersion:  splitter_token.DataVersion1,
+		Authority:    authority,
+		Mint:         mint,
+		Vault:        vault,
+		VaultBump:    vaultBump,
+		Name:         name,
+		HistoryList:  historyList,
+		CurrentIndex: 0,
+		MerkleTree: &splitter_token.MerkleTree{
+			Levels:         levels,
+			Root:           root,
+			FilledSubtrees: append([]splitter_token.Hash(nil), zeroValues...),
+			ZeroValues:     zeroValues,
+		},
+	}
+	l.CreateSplitterPool(pool, poolAccount)
+
+	return pool, vault
+}
+
+func getSplitterPool(t *testing.T, l *Ledger, address ed25519.PublicKey) *splitter_token.PoolAccount {
+	info, err := l.GetAccountInfo(address, solana.CommitmentFinalized)
+	require.NoError(t, err)
+
+	var pool splitter_token.PoolAccount
+	require.NoError(t, pool.Unmarshal(info.Data))
+	return &pool
+}
+
+func newSignedTransaction(t *testing.T, l *Ledger, payer ed25519.PrivateKey, instructions []solana.Instruction, additionalSigners ...ed25519.PrivateKey) solana.Transaction {
+	bh, err := l.GetLatestBlockhash()
+	require.NoError(t, err)
+
+	txn := solana.NewTransaction(payer.Public().(ed25519.PublicKey), instructions...)
+	txn.SetBlockhash(bh)
+	require.NoError(t, txn.Sign(append([]ed25519.PrivateKey{payer}, additionalSigners...)...))
+	return txn
+}
+
+func newFundedKey(t *testing.T, l *Ledger) ed25519.PrivateKey {
+	key := newKey(t)
+
+	_, err := l.RequestAirdrop(key.Public().(ed25519.PublicKey), testAirdropAmount, solana.CommitmentFinalized)
+	require.NoError(t, err)
+
+	return key
+}
+
+func newKey(t *testing.T) ed25519.PrivateKey {
+	_, key, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	return key
+}
+
+func assertSuccessful(t *testing.T, l *Ledger, sig solana.Signature) {
+	status, err := l.GetSignatureStatus(sig, solana.CommitmentFinalized)
+	require.NoError(t, err)
+	if status.ErrorResult != nil {
+		require.FailNow(t, "transaction failed", status.ErrorResult.Error())
+	}
+}
+
+func assertCustomError(t *testing.T, l *Ledger, sig solana.Signature, expected solana.CustomError) {
+	status, err := l.GetSignatureStatus(sig, solana.CommitmentFinalized)
+	require.NoError(t, err)
+	require.NotNil(t, status.ErrorResult)
+	require.NotNil(t, status.ErrorResult.InstructionError())
+	require.NotNil(t, status.ErrorResult.InstructionError().CustomError())
+	assert.Equal(t, expected, *status.ErrorResult.InstructionError().CustomError())
+}
+
+func assertTransactionError(t *testing.T, err error, expected solana.TransactionErrorKey) {
+	require.Error(t, err)
+
+	txnErr, ok := err.(*solana.TransactionError)
+	require.True(t, ok)
+	assert.Equal(t, expected, txnErr.ErrorKey())
+}
+
@@ -0,0 +1,152 @@
+package simulator
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/binary"
+	"math"
+
+	"github.com/code-payments/code-server/pkg/solana"
+	address_lookup_table "github.com/code-payments/code-server/pkg/solana/addresslookuptable"
+)
+
+const (
+	lookupTableCommandClose = 4
+
+	// Reference: https://github.com/solana-labs/solana/blob/master/sdk/program/src/address_lookup_table/state.rs
+	lookupTableMaxAddresses = 256
+)
+
+func executeAddressLookupTableInstruction(e *executor, index int) error {
+	if create, err := address_lookup_table.DecompileCreateLookupTable(e.msg, index); err == nil {
+		return e.executeCreateLookupTable(create)
+	}
+
+	if extend, err := address_lookup_table.DecompileExtendLookupTable(e.msg, index); err == nil {
+		return e.executeExtendLookupTable(extend)
+	}
+
+	if deactivate, err := address_lookup_table.DecompileDeactivateLookupTable(e.msg, index); err == nil {
+		return e.executeDeactivateLookupTable(deactivate.Address, deactivate.Authority)
+	}
+
+	ixn := e.msg.Instructions[index]
+	accounts := e.getInstructionAccounts(index)
+	if len(ixn.Data) == 4 && binary.LittleEndian.Uint32(ixn.Data) == lookupTableCommandClose && len(accounts) == 3 {
+		return e.executeCloseLookupTable(accounts[0], accounts[1], accounts[2])
+	}
+
+	return instructionError(solana.InstructionErrorInvalidInstructionData)
+}
+
+func (e *executor) executeCreateLookupTable(decompiled *address_lookup_table.DecompiledCreateLookupTable) error {
+	if err := e.requireSigner(decompiled.Authority); err != nil {
+		return err
+	}
+
+	if decompiled.RecentSlot >= e.slot || decompiled.RecentSlot+maxRecentBlockhashes < e.slot {
+		return instructionError(solana.InstructionErrorInvalidInstructionData)
+	}
+
+	expected, bump, err := address_lookup_table.GetTableAddress(decompiled.Authority, decompiled.RecentSlot)
+	if err != nil || !bytes.Equal(expected, decompiled.Address) || bump != decompiled.Bump {
+		return instructionError(solana.InstructionErrorInvalidArgument)
+	}
+
+	table := address_lookup_table.LookupTableAccount{
+		DeactivationSlot: math.MaxUint64,
+		Authority:        decompiled.Authority,
+	}
+	return e.createAccount(decompiled.Payer, decompiled.Address, address_lookup_table.ProgramKey, table.Marshal())
+}
+
+func (e *executor) executeExtendLookupTable(decompiled *address_lookup_table.DecompiledExtendLookupTable) error {
+	acc, table, err := e.getLookupTable(decompiled.Address, decompiled.Authority)
+	if err != nil {
+		return err
+	}
+
+	if !table.IsActive() {
+		return instructionError(solana.InstructionErrorInvalidArgument)
+	}
+
+	if len(decompiled.NewAddresses) == 0 || len(table.Addresses)+len(decompiled.NewAddresses) > lookupTableMaxAddresses {
+		return instructionError(solana.InstructionErrorInvalidInstructionData)
+	}
+
+	if table.LastExtendedSlot != e.slot {
+		table.LastExtendedSlot = e.slot
+		table.LastExtendedSlotStartIndex = uint8(len(table.Addresses))
+	}
+	table.Addresses = append(table.Addresses, decompiled.NewAddresses...)
+
+	acc.data = table.Marshal()
+	if err := e.put(decompiled.Address, acc); err != nil {
+		return err
+	}
+
+	// The payer funds any additional rent required by the larger account
+	required := getMinimumBalanceForRentExemption(uint64(len(acc.data)))
+	if acc.lamports < required {
+		if err := e.requireSigner(decompiled.Payer); err != nil {
+			return err
+		}
+		return e.transferLamports(decompiled.Payer, decompiled.Address, required-acc.lamports)
+	}
+	return nil
+}
+
+func (e *executor) executeDeactivateLookupTable(address, authority ed25519.PublicKey) error {
+	acc, table, err := e.getLookupTable(address, authority)
+	if err != nil {
+		return err
+	}
+
+	if !table.IsActive() {
+		return instructionError(solana.InstructionErrorInvalidArgument)
+	}
+
+	table.DeactivationSlot = e.slot
+	acc.data = table.Marshal()
+	return e.put(address, acc)
+}
+
+func (e *executor) executeCloseLookupTable(address, authority, recipient ed25519.PublicKey) error {
+	_, table, err := e.getLookupTable(address, authority)
+	if err != nil {
+		return err
+	}
+
+	// Tables can only be closed once the deactivation slot is no longer a
+	// recent slot, which guarantees in-flight transactions can't reference it.
+	if table.IsActive() || table.DeactivationSlot+maxRecentBlockhashes >= e.slot {
+		return instructionError(solana.InstructionErrorInvalidArgument)
+	}
+
+	return e.closeAccount(address, recipient)
+}
+
+func (e *executor) getLookupTable(address, authority ed25519.PublicKey) (*account, *address_lookup_table.LookupTableAccount, error) {
+	acc, ok := e.get(address)
+	if !ok || !bytes.Equal(acc.owner, address_lookup_table.ProgramKey) {
+		return nil, nil, instructionError(solana.InstructionErrorInvalidAccountData)
+	}
+
+	var table address_lookup_table.LookupTableAccount
+	if err := table.Unmarshal(acc.data); err != nil {
+		return nil, nil, instructionError(solana.InstructionErrorInvalidAccountData)
+	}
+
+	// Frozen tables have no authority and can no longer be modified
+	if len(table.Authority) == 0 {
+		return nil, nil, instructionError(solana.InstructionErrorImmutable)
+	}
+	if !bytes.Equal(table.Authority, authority) {
+		return nil, nil, instructionError(solana.InstructionErrorIncorrectAuthority)
+	}
+	if err := e.requireSigner(authority); err != nil {
+		return nil, nil, err
+	}
+
+	return acc, &table, nil
+}
@@ -0,0 +1,57 @@
+package simulator
+
+import (
+	"crypto/ed25519"
+	"encoding/binary"
+)
+
+// Reference: https://github.com/solana-labs/solana-program-library/blob/master/token/program/src/state.rs
+const mintAccountSize = 82
+
+type mintAccount struct {
+	authority       ed25519.PublicKey
+	supply          uint64
+	decimals        uint8
+	isInitialized   bool
+	freezeAuthority ed25519.PublicKey
+}
+
+func (m *mintAccount) marshal() []byte {
+	b := make([]byte, mintAccountSize)
+
+	if len(m.authority) > 0 {
+		binary.LittleEndian.PutUint32(b[0:], 1)
+		copy(b[4:36], m.authority)
+	}
+	binary.LittleEndian.PutUint64(b[36:], m.supply)
+	b[44] = m.decimals
+	if m.isInitialized {
+		b[45] = 1
+	}
+	if len(m.freezeAuthority) > 0 {
+		binary.LittleEndian.PutUint32(b[46:], 1)
+		copy(b[50:82], m.freezeAuthority)
+	}
+
+	return b
+}
+
+func (m *mintAccount) unmarshal(b []byte) bool {
+	if len(b) != mintAccountSize {
+		return false
+	}
+
+	m.authority = nil
+	if binary.LittleEndian.Uint32(b[0:]) == 1 {
+		m.authority = append(ed25519.PublicKey(nil), b[4:36]...)
+	}
+	m.supply = binary.LittleEndian.Uint64(b[36:])
+	m.decimals = b[44]
+	m.isInitialized = b[45] == 1
+	m.freezeAuthority = nil
+	if binary.LittleEndian.Uint32(b[46:]) == 1 {
+		m.freezeAuthority = append(ed25519.PublicKey(nil), b[50:82]...)
+	}
+
+	return m.isInitialized
+}
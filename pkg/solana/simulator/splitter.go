@@ -0,0 +1,476 @@
+package simulator
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+
+	"github.com/code-payments/code-server/pkg/solana"
+	splitter_token "github.com/code-payments/code-server/pkg/solana/splitter"
+	"github.com/code-payments/code-server/pkg/solana/token"
+)
+
+func executeSplitterInstruction(e *executor, index int) error {
+	txn := e.txn.resolved
+
+	if args, accounts, err := splitter_token.TransferWithCommitmentInstructionFromLegacyInstruction(txn, index); err == nil {
+		return e.executeSplitterTransferWithCommitment(args, accounts)
+	}
+
+	if _, accounts, err := splitter_token.SaveRecentRootInstructionFromLegacyInstruction(txn, index); err == nil {
+		return e.executeSplitterSaveRecentRoot(accounts)
+	}
+
+	if args, accounts, err := splitter_token.InitializeProofInstructionFromLegacyInstruction(txn, index); err == nil {
+		return e.executeSplitterInitializeProof(args, accounts)
+	}
+
+	if args, accounts, err := splitter_token.UploadProofInstructionFromLegacyInstruction(txn, index); err == nil {
+		return e.executeSplitterUploadProof(args, accounts)
+	}
+
+	if _, accounts, err := splitter_token.VerifyProofInstructionFromLegacyInstruction(txn, index); err == nil {
+		return e.executeSplitterVerifyProof(accounts)
+	}
+
+	if _, accounts, err := splitter_token.OpenTokenAccountInstructionFromLegacyInstruction(txn, index); err == nil {
+		return e.executeSplitterOpenTokenAccount(accounts)
+	}
+
+	if _, accounts, err := splitter_token.CloseTokenAccountInstructionFromLegacyInstruction(txn, index); err == nil {
+		return e.executeSplitterCloseTokenAccount(accounts)
+	}
+
+	if _, accounts, err := splitter_token.CloseProofInstructionFromLegacyInstruction(txn, index); err == nil {
+		return e.executeSplitterCloseProof(accounts)
+	}
+
+	return instructionError(solana.InstructionErrorInvalidInstructionData)
+}
+
+func (e *executor) executeSplitterTransferWithCommitment(args *splitter_token.TransferWithCommitmentInstructionArgs, accounts *splitter_token.TransferWithCommitmentInstructionAccounts) error {
+	pool, err := e.getSplitterPool(accounts.Pool, accounts.Authority)
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(pool.Vault, accounts.Vault) {
+		return splitterError(splitter_token.ErrInvalidVaultAccount)
+	}
+
+	var isRecentRoot bool
+	for _, root := range pool.HistoryList {
+		if bytes.Equal(root, args.RecentRoot) {
+			isRecentRoot = true
+			break
+		}
+	}
+	if !isRecentRoot {
+		return splitterError(splitter_token.ErrInvalidRecentRoot)
+	}
+
+	_, vault, err := e.getTokenAccount(accounts.Vault)
+	if err != nil {
+		return err
+	}
+	if vault.Amount < args.Amount {
+		return splitterError(splitter_token.ErrInsufficientVaultBalance)
+	}
+
+	if err := e.transferTokens(accounts.Vault, accounts.Destination, args.Amount); err != nil {
+		return err
+	}
+
+	if err := insertMerkleTreeLeaf(pool.MerkleTree, accounts.Commitment); err != nil {
+		return err
+	}
+	return e.putSplitterPool(accounts.Pool, pool)
+}
+
+func (e *executor) executeSplitterSaveRecentRoot(accounts *splitter_token.SaveRecentRootInstructionAccounts) error {
+	pool, err := e.getSplitterPool(accounts.Pool, accounts.Authority)
+	if err != nil {
+		return err
+	}
+
+	if len(pool.HistoryList) == 0 {
+		return splitterError(splitter_token.ErrInvalidPoolState)
+	}
+
+	pool.CurrentIndex = uint8((int(pool.CurrentIndex) + 1) % len(pool.HistoryList))
+	pool.HistoryList[pool.CurrentIndex] = append(splitter_token.Hash(nil), pool.MerkleTree.Root...)
+	return e.putSplitterPool(accounts.Pool, pool)
+}
+
+func (e *executor) executeSplitterInitializeProof(args *splitter_token.InitializeProofInstructionArgs, accounts *splitter_token.InitializeProofInstructionAccounts) error {
+	pool, err := e.getSplitterPool(accounts.Pool, accounts.Authority)
+	if err != nil {
+		return err
+	}
+
+	address, bump, err := splitter_token.GetProofAddress(&splitter_token.GetProofAddressArgs{
+		Pool:       accounts.Pool,
+		MerkleRoot: args.MerkleRoot,
+		Commitment: args.Commitment,
+	})
+	if err != nil || !bytes.Equal(address, accounts.Proof) {
+		return instructionError(solana.InstructionErrorInvalidSeeds)
+	}
+
+	proof := &splitter_token.ProofAccount{
+		DataVersion: splitter_token.DataVersion1,
+		Pool:        accounts.Pool,
+		PoolBump:    bump,
+		MerkleRoot:  args.MerkleRoot,
+		Commitment:  args.Commitment,
+		Size:        pool.MerkleTree.Levels,
+		Data:        []splitter_token.Hash{},
+	}
+	return e.createAccount(accounts.Payer, accounts.Proof, splitter_token.PROGRAM_ID, marshalSplitterProof(proof))
+}
+
+func (e *executor) executeSplitterUploadProof(args *splitter_token.UploadProofInstructionArgs, accounts *splitter_token.UploadProofInstructionAccounts) error {
+	if _, err := e.getSplitterPool(accounts.Pool, accounts.Authority); err != nil {
+		return err
+	}
+
+	proof, err := e.getSplitterProof(accounts.Proof, accounts.Pool)
+	if err != nil {
+		return err
+	}
+
+	if proof.Verified {
+		return splitterError(splitter_token.ErrProofAlreadyVerified)
+	}
+
+	if int(args.CurrentSize) != len(proof.Data) || int(args.DataSize) != len(args.Data) || len(proof.Data)+len(args.Data) > int(proof.Size) {
+		return splitterError(splitter_token.ErrInvalidProofSize)
+	}
+
+	proof.Data = append(proof.Data, args.Data...)
+	return e.putSplitterProof(accounts.Proof, proof)
+}
+
+func (e *executor) executeSplitterVerifyProof(accounts *splitter_token.VerifyProofInstructionAccounts) error {
+	if _, err := e.getSplitterPool(accounts.Pool, accounts.Authority); err != nil {
+		return err
+	}
+
+	proof, err := e.getSplitterProof(accounts.Proof, accounts.Pool)
+	if err != nil {
+		return err
+	}
+
+	if proof.Verified {
+		return splitterError(splitter_token.ErrProofAlreadyVerified)
+	}
+
+	if len(proof.Data) != int(proof.Size) {
+		return splitterError(splitter_token.ErrInvalidProofSize)
+	}
+
+	if !verifyMerkleProof(proof.Data, proof.MerkleRoot, proof.Commitment) {
+		return splitterError(splitter_token.ErrInvalidProof)
+	}
+
+	proof.Verified = true
+	return e.putSplitterProof(accounts.Proof, proof)
+}
+
+func (e *executor) executeSplitterOpenTokenAccount(accounts *splitter_token.OpenTokenAccountInstructionAccounts) error {
+	pool, err := e.getSplitterPool(accounts.Pool, accounts.Authority)
+	if err != nil {
+		return err
+	}
+
+	proof, err := e.getSplitterProof(accounts.Proof, accounts.Pool)
+	if err != nil {
+		return err
+	}
+
+	if !proof.Verified {
+		return splitterError(splitter_token.ErrProofNotVerified)
+	}
+
+	if !bytes.Equal(pool.Mint, accounts.Mint) {
+		return token.ErrorMintMismatch
+	}
+
+	address, _, err := splitter_token.GetCommitmentVaultAddress(&splitter_token.GetCommitmentVaultAddressArgs{
+		Pool:       accounts.Pool,
+		Commitment: proof.Commitment,
+	})
+	if err != nil || !bytes.Equal(address, accounts.CommitmentVault) {
+		return instructionError(solana.InstructionErrorInvalidSeeds)
+	}
+
+	return e.createTokenAccount(accounts.Payer, accounts.CommitmentVault, accounts.Mint, accounts.Pool)
+}
+
+func (e *executor) executeSplitterCloseTokenAccount(accounts *splitter_token.CloseTokenAccountInstructionAccounts) error {
+	pool, err := e.getSplitterPool(accounts.Pool, accounts.Authority)
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(pool.Vault, accounts.PoolVault) {
+		return splitterError(splitter_token.ErrInvalidVaultAccount)
+	}
+
+	proof, err := e.getSplitterProof(accounts.Proof, accounts.Pool)
+	if err != nil {
+		return err
+	}
+
+	if !proof.Verified {
+		return splitterError(splitter_token.ErrProofNotVerified)
+	}
+
+	expected, _, err := splitter_token.GetCommitmentVaultAddress(&splitter_token.GetCommitmentVaultAddressArgs{
+		Pool:       accounts.Pool,
+		Commitment: proof.Commitment,
+	})
+	if err != nil || !bytes.Equal(expected, accounts.CommitmentVault) {
+		return splitterError(splitter_token.ErrInvalidVaultAccount)
+	}
+
+	_, commitmentVault, err := e.getTokenAccount(accounts.CommitmentVault)
+	if err != nil {
+		return err
+	}
+
+	if err := e.transferTokens(accounts.CommitmentVault, accounts.PoolVault, commitmentVault.Amount); err != nil {
+		return err
+	}
+	return e.closeAccount(accounts.CommitmentVault, accounts.Payer)
+}
+
+func (e *executor) executeSplitterCloseProof(accounts *splitter_token.CloseProofInstructionAccounts) error {
+	if _, err := e.getSplitterPool(accounts.Pool, accounts.Authority); err != nil {
+		return err
+	}
+
+	if _, err := e.getSplitterProof(accounts.Proof, accounts.Pool); err != nil {
+		return err
+	}
+
+	return e.closeAccount(accounts.Proof, accounts.Payer)
+}
+
+// getSplitterPool loads the pool state and validates the provided authority
+// against it.
+func (e *executor) getSplitterPool(address, authority ed25519.PublicKey) (*splitter_token.PoolAccount, error) {
+	acc, ok := e.get(address)
+	if !ok || !bytes.Equal(acc.owner, splitter_token.PROGRAM_ID) {
+		return nil, instructionError(solana.InstructionErrorInvalidAccountData)
+	}
+
+	var pool splitter_token.PoolAccount
+	if err := pool.Unmarshal(acc.data); err != nil {
+		return nil, instructionError(solana.InstructionErrorInvalidAccountData)
+	}
+
+	if !bytes.Equal(pool.Authority, authority) {
+		return nil, splitterError(splitter_token.ErrInvalidAuthority)
+	}
+	if err := e.requireSigner(authority); err != nil {
+		return nil, err
+	}
+
+	return &pool, nil
+}
+
+func (e *executor) putSplitterPool(address ed25519.PublicKey, pool *splitter_token.PoolAccount) error {
+	acc, ok := e.get(address)
+	if !ok {
+		return instructionError(solana.InstructionErrorUninitializedAccount)
+	}
+
+	acc.data = marshalSplitterPool(pool)
+	return e.put(address, acc)
+}
+
+func (e *executor) getSplitterProof(address, pool ed25519.PublicKey) (*splitter_token.ProofAccount, error) {
+	acc, ok := e.get(address)
+	if !ok || !bytes.Equal(acc.owner, splitter_token.PROGRAM_ID) {
+		return nil, instructionError(solana.InstructionErrorInvalidAccountData)
+	}
+
+	proof, err := unmarshalSplitterProof(acc.data)
+	if err != nil {
+		return nil, instructionError(solana.InstructionErrorInvalidAccountData)
+	}
+
+	if !bytes.Equal(proof.Pool, pool) {
+		return nil, splitterError(splitter_token.ErrInvalidCommitmentState)
+	}
+
+	return proof, nil
+}
+
+func (e *executor) putSplitterProof(address ed25519.PublicKey, proof *splitter_token.ProofAccount) error {
+	acc, ok := e.get(address)
+	if !ok {
+		return instructionError(solana.InstructionErrorUninitializedAccount)
+	}
+
+	acc.data = marshalSplitterProof(proof)
+	return e.put(address, acc)
+}
+
+// marshalSplitterPool encodes a pool account in its on-chain format, so it can be
+// read using splitter_token.PoolAccount.Unmarshal. PoolAccount.Marshal doesn't
+// encode the merkle tree, so it's appended separately.
+func marshalSplitterPool(pool *splitter_token.PoolAccount) []byte {
+	data := pool.Marshal()
+
+	treeOffset := 8 + // discriminator
+		1 + // data_version
+		3*ed25519.PublicKeySize + // authority, mint and vault
+		1 + // vault_bump
+		4 + len(pool.Name) + // name
+		4 + len(pool.HistoryList)*splitter_token.HashSize + // history_list
+		1 // current_index
+
+	tree := marshalMerkleTree(pool.MerkleTree)
+	if len(data) < treeOffset+len(tree) {
+		data = append(data, make([]byte, treeOffset+len(tree)-len(data))...)
+	}
+	copy(data[treeOffset:], tree)
+
+	return data
+}
+
+func marshalMerkleTree(tree *splitter_token.MerkleTree) []byte {
+	data := []byte{tree.Levels}
+	data = binary.LittleEndian.AppendUint64(data, tree.NextIndex)
+	data = appendSplitterHash(data, tree.Root)
+
+	data = binary.LittleEndian.AppendUint32(data, uint32(len(tree.FilledSubtrees)))
+	for _, subtree := range tree.FilledSubtrees {
+		data = appendSplitterHash(data, subtree)
+	}
+
+	data = binary.LittleEndian.AppendUint32(data, uint32(len(tree.ZeroValues)))
+	for _, zeroValue := range tree.ZeroValues {
+		data = appendSplitterHash(data, zeroValue)
+	}
+
+	return data
+}
+
+// marshalSplitterProof encodes a proof account using the on-chain header, followed
+// by the uploaded proof data. Proof accounts are only read by the simulator.
+func marshalSplitterProof(proof *splitter_token.ProofAccount) []byte {
+	header := *proof
+	header.Data = nil
+	data := header.Marshal()
+
+	binary.LittleEndian.PutUint32(data[splitter_token.ProofAccountSize-4:], uint32(len(proof.Data)))
+	for _, item := range proof.Data {
+		data = appendSplitterHash(data, item)
+	}
+
+	return data
+}
+
+func unmarshalSplitterProof(data []byte) (*splitter_token.ProofAccount, error) {
+	if len(data) < splitter_token.ProofAccountSize {
+		return nil, errors.New("invalid proof account size")
+	}
+
+	offset := 8 // discriminator
+	proof := &splitter_token.ProofAccount{
+		DataVersion: splitter_token.DataVersion(data[offset]),
+	}
+	offset++
+
+	proof.Pool = ed25519.PublicKey(append([]byte(nil), data[offset:offset+ed25519.PublicKeySize]...))
+	offset += ed25519.PublicKeySize
+	proof.PoolBump = data[offset]
+	offset++
+	proof.MerkleRoot = append(splitter_token.Hash(nil), data[offset:offset+splitter_token.HashSize]...)
+	offset += splitter_token.HashSize
+	proof.Commitment = ed25519.PublicKey(append([]byte(nil), data[offset:offset+ed25519.PublicKeySize]...))
+	offset += ed25519.PublicKeySize
+	proof.Verified = data[offset] == 1
+	offset++
+	proof.Size = data[offset]
+	offset++
+
+	length := int(binary.LittleEndian.Uint32(data[offset:]))
+	offset += 4
+	if len(data) != offset+length*splitter_token.HashSize {
+		return nil, errors.New("invalid proof data length")
+	}
+
+	proof.Data = make([]splitter_token.Hash, length)
+	for i := range proof.Data {
+		proof.Data[i] = append(splitter_token.Hash(nil), data[offset:offset+splitter_token.HashSize]...)
+		offset += splitter_token.HashSize
+	}
+
+	return proof, nil
+}
+
+func appendSplitterHash(data []byte, hash splitter_token.Hash) []byte {
+	padded := make([]byte, splitter_token.HashSize)
+	copy(padded, hash)
+	return append(data, padded...)
+}
+
+func splitterError(err splitter_token.SplitterTokenError) error {
+	return solana.CustomError(err)
+}
+
+// insertMerkleTreeLeaf appends a leaf to the on-chain merkle tree, which
+// mirrors the insertion algorithm in pkg/code/data/merkletree.
+func insertMerkleTreeLeaf(tree *splitter_token.MerkleTree, leaf []byte) error {
+	if tree.NextIndex >= uint64(1)<<tree.Levels {
+		return splitterError(splitter_token.ErrMerkleTreeFull)
+	}
+
+	var left, right splitter_token.Hash
+	currentLevelIndex := tree.NextIndex
+	currentLevelHash := merkleHash(leaf)
+	for level := uint8(0); level < tree.Levels; level++ {
+		if currentLevelIndex%2 == 0 {
+			left = currentLevelHash
+			right = tree.ZeroValues[level]
+			tree.FilledSubtrees[level] = currentLevelHash
+		} else {
+			left = tree.FilledSubtrees[level]
+			right = currentLevelHash
+		}
+
+		currentLevelHash = merkleHashLeftRight(left, right)
+		currentLevelIndex /= 2
+	}
+
+	tree.Root = currentLevelHash
+	tree.NextIndex++
+	return nil
+}
+
+func verifyMerkleProof(proof []splitter_token.Hash, root splitter_token.Hash, leaf []byte) bool {
+	computed := merkleHash(leaf)
+	for _, element := range proof {
+		computed = merkleHashLeftRight(computed, element)
+	}
+	return bytes.Equal(computed, root)
+}
+
+func merkleHash(value []byte) splitter_token.Hash {
+	h := sha256.Sum256(value)
+	return h[:]
+}
+
+func merkleHashLeftRight(left, right splitter_token.Hash) splitter_token.Hash {
+	if bytes.Compare(left, right) < 0 {
+		return merkleHash(append(append([]byte(nil), left...), right...))
+	}
+	return merkleHash(append(append([]byte(nil), right...), left...))
+}
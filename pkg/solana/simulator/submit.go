@@ -0,0 +1,369 @@
+package simulator
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mr-tron/base58/base58"
+	"github.com/pkg/errors"
+
+	"github.com/code-payments/code-server/pkg/solana"
+	address_lookup_table "github.com/code-payments/code-server/pkg/solana/addresslookuptable"
+	compute_budget "github.com/code-payments/code-server/pkg/solana/computebudget"
+	"github.com/code-payments/code-server/pkg/solana/memo"
+	"github.com/code-payments/code-server/pkg/solana/system"
+	"github.com/code-payments/code-server/pkg/solana/token"
+)
+
+const (
+	defaultComputeUnitsPerInstruction = 200_000
+	maxComputeUnits                   = 1_400_000
+)
+
+// loadedTransaction is a sanitized transaction whose address table lookups
+// have been resolved, so that Message.Accounts contains every account, in
+// order, referenced by the compiled instructions.
+type loadedTransaction struct {
+	original solana.Transaction
+	resolved solana.Transaction
+
+	loadedWritable []ed25519.PublicKey
+	loadedReadonly []ed25519.PublicKey
+
+	writable map[string]struct{}
+	signers  map[string]struct{}
+
+	isNonced bool
+	nonce    ed25519.PublicKey
+
+	fee           uint64
+	microLamports uint64
+}
+
+// SubmitTransaction implements solana.Client.SubmitTransaction.
+//
+// Transactions that fail sanitization, signature verification, blockhash
+// validation or fee collection are rejected with an error and are never
+// recorded. All other transactions are recorded, regardless of whether their
+// instructions succeeded. Resubmitting an already processed transaction is a
+// no-op.
+func (l *Ledger) SubmitTransaction(txn solana.Transaction, _ solana.Commitment) (solana.Signature, error) {
+	if len(txn.Signatures) == 0 {
+		return solana.Signature{}, solana.NewTransactionError(solana.TransactionErrorMissingSignatureForFee)
+	}
+	sig := txn.Signatures[0]
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.transactions[sig]; ok {
+		return sig, nil
+	}
+
+	loaded, txnErr := l.loadTransaction(txn)
+	if txnErr != nil {
+		return sig, txnErr
+	}
+
+	slot := l.slot + 1
+	blockhash := getBlockhash(slot)
+	accounts := loaded.resolved.Message.Accounts
+
+	preBalances, preTokenBalances := l.getBalances(accounts)
+
+	exec := newExecutor(l, loaded, blockhash)
+	txnErr = exec.collectFee()
+	if txnErr == nil {
+		txnErr = exec.execute()
+	}
+
+	if txnErr != nil {
+		// Failed transactions still pay fees and advance durable nonces
+		exec = newExecutor(l, loaded, blockhash)
+		if err := exec.collectFee(); err != nil {
+			return sig, err
+		}
+		if loaded.isNonced {
+			if err := exec.executeInstruction(0); err != nil {
+				return sig, solana.NewTransactionError(solana.TransactionErrorBlockhashNotFound)
+			}
+		}
+	}
+	exec.commit()
+
+	postBalances, postTokenBalances := l.getBalances(accounts)
+
+	meta := &solana.TransactionMeta{
+		Fee:               loaded.fee,
+		PreBalances:       preBalances,
+		PostBalances:      postBalances,
+		PreTokenBalances:  preTokenBalances,
+		PostTokenBalances: postTokenBalances,
+		LoadedAddresses: solana.LoadedAddresses{
+			Writable: encodePublicKeys(loaded.loadedWritable),
+			Readonly: encodePublicKeys(loaded.loadedReadonly),
+		},
+	}
+	if txnErr != nil {
+		raw, err := txnErr.JSONString()
+		if err != nil {
+			return sig, err
+		}
+
+		if err := json.Unmarshal([]byte(raw), &meta.Err); err != nil {
+			return sig, err
+		}
+	}
+
+	block := l.produceBlock([]solana.BlockTransaction{
+		{
+			Transaction: txn,
+			Err:         txnErr,
+			Meta:        meta,
+		},
+	})
+
+	l.transactions[sig] = &processedTransaction{
+		confirmed: solana.ConfirmedTransaction{
+			Slot:        block.Slot,
+			BlockTime:   block.BlockTime,
+			Transaction: txn,
+			Err:         txnErr,
+			Meta:        meta,
+		},
+		accounts:      accounts,
+		writable:      loaded.writable,
+		microLamports: loaded.microLamports,
+		memo:          getMemo(loaded.resolved),
+	}
+
+	for _, account := range accounts {
+		l.history[string(account)] = append(l.history[string(account)], sig)
+	}
+
+	return sig, nil
+}
+
+// loadTransaction validates the transaction and resolves its accounts. It must
+// be called with the lock held.
+func (l *Ledger) loadTransaction(txn solana.Transaction) (*loadedTransaction, *solana.TransactionError) {
+	m := txn.Message
+
+	if m.Version > solana.MessageVersion0 {
+		return nil, solana.NewTransactionError(solana.TransactionErrorUnsupportedVersion)
+	}
+
+	if int(m.Header.NumSignatures) != len(txn.Signatures) || len(m.Accounts) < int(m.Header.NumSignatures) {
+		return nil, solana.NewTransactionError(solana.TransactionErrorSanitizeFailure)
+	}
+
+	messageBytes := m.Marshal()
+	for i, sig := range txn.Signatures {
+		if !ed25519.Verify(m.Accounts[i], messageBytes, sig[:]) {
+			return nil, solana.NewTransactionError(solana.TransactionErrorSignatureFailure)
+		}
+	}
+
+	var lookupTables []solana.AddressLookupTable
+	for _, lookup := range m.AddressTableLookups {
+		acc, ok := l.accounts[string(lookup.PublicKey)]
+		if !ok {
+			return nil, solana.NewTransactionError(solana.TransactionErrorKey("AddressLookupTableNotFound"))
+		}
+
+		table, err := address_lookup_table.GetLookupTableFromAccount(lookup.PublicKey, acc.toAccountInfo())
+		if err != nil {
+			return nil, solana.NewTransactionError(solana.TransactionErrorKey("InvalidAddressLookupTableData"))
+		}
+		lookupTables = append(lookupTables, *table)
+	}
+
+	loadedWritable, loadedReadonly, err := m.GetLoadedAccounts(lookupTables...)
+	if err != nil {
+		return nil, solana.NewTransactionError(solana.TransactionErrorKey("InvalidAddressLookupTableIndex"))
+	}
+
+	accounts := append([]ed25519.PublicKey{}, m.Accounts...)
+	accounts = append(accounts, loadedWritable...)
+	accounts = append(accounts, loadedReadonly...)
+
+	loaded := &loadedTransaction{
+		original: txn,
+		resolved: solana.Transaction{
+			Signatures: txn.Signatures,
+			Message: solana.Message{
+				Version:         m.Version,
+				Header:          m.Header,
+				Accounts:        accounts,
+				RecentBlockhash: m.RecentBlockhash,
+				Instructions:    m.Instructions,
+			},
+		},
+		loadedWritable: loadedWritable,
+		loadedReadonly: loadedReadonly,
+		writable:       make(map[string]struct{}),
+		signers:        make(map[string]struct{}),
+	}
+
+	numSigners := int(m.Header.NumSignatures)
+	numStatic := len(m.Accounts)
+	seen := make(map[string]struct{})
+	for i, account := range accounts {
+		if _, ok := seen[string(account)]; ok {
+			return nil, solana.NewTransactionError(solana.TransactionErrorAccountLoadedTwice)
+		}
+		seen[string(account)] = struct{}{}
+
+		var isWritable bool
+		switch {
+		case i < numSigners:
+			loaded.signers[string(account)] = struct{}{}
+			isWritable = i < numSigners-int(m.Header.NumReadonlySigned)
+		case i < numStatic:
+			isWritable = i < numStatic-int(m.Header.NumReadOnly)
+		default:
+			isWritable = i < numStatic+len(loadedWritable)
+		}
+
+		if isWritable {
+			loaded.writable[string(account)] = struct{}{}
+		}
+	}
+
+	for _, ixn := range m.Instructions {
+		if int(ixn.ProgramIndex) >= len(accounts) {
+			return nil, solana.NewTransactionError(solana.TransactionErrorInvalidAccountIndex)
+		}
+		for _, idx := range ixn.Accounts {
+			if int(idx) >= len(accounts) {
+				return nil, solana.NewTransactionError(solana.TransactionErrorInvalidAccountIndex)
+			}
+		}
+	}
+
+	if !l.isRecentBlockhash(m.RecentBlockhash) {
+		advance, err := system.DecompileAdvanceNonce(loaded.resolved.Message, 0)
+		if err != nil {
+			return nil, solana.NewTransactionError(solana.TransactionErrorBlockhashNotFound)
+		}
+
+		acc, ok := l.accounts[string(advance.Nonce)]
+		if !ok {
+			return nil, solana.NewTransactionError(solana.TransactionErrorBlockhashNotFound)
+		}
+
+		nonceValue, err := system.GetNonceValueFromAccount(acc.toAccountInfo())
+		if err != nil || nonceValue != m.RecentBlockhash {
+			return nil, solana.NewTransactionError(solana.TransactionErrorBlockhashNotFound)
+		}
+
+		loaded.isNonced = true
+		loaded.nonce = advance.Nonce
+	}
+
+	computeUnits := uint32(0)
+	var hasComputeUnitLimit bool
+	for i := range m.Instructions {
+		if limit, err := compute_budget.DecompileSetComputeUnitLimit(loaded.resolved.Message, i); err == nil {
+			computeUnits = limit.Units
+			hasComputeUnitLimit = true
+		} else if price, err := compute_budget.DecompileSetComputeUnitPrice(loaded.resolved.Message, i); err == nil {
+			loaded.microLamports = price.MicroLamports
+		} else if !hasComputeUnitLimit && computeUnits < maxComputeUnits {
+			computeUnits += defaultComputeUnitsPerInstruction
+		}
+	}
+	if computeUnits > maxComputeUnits {
+		computeUnits = maxComputeUnits
+	}
+
+	loaded.fee = l.lamportsPerSignature*uint64(len(txn.Signatures)) + compute_budget.GetPriorityFee(computeUnits, loaded.microLamports)
+
+	return loaded, nil
+}
+
+// getBalances returns the lamport and token balances for the provided accounts.
+// It must be called with the lock held.
+func (l *Ledger) getBalances(accounts []ed25519.PublicKey) ([]uint64, []solana.TokenBalance) {
+	balances := make([]uint64, len(accounts))
+	tokenBalances := make([]solana.TokenBalance, 0)
+
+	for i, address := range accounts {
+		acc, ok := l.accounts[string(address)]
+		if !ok {
+			continue
+		}
+
+		balances[i] = acc.lamports
+
+		var tokenAccount token.Account
+		if !bytes.Equal(acc.owner, token.ProgramKey) || !tokenAccount.Unmarshal(acc.data) {
+			continue
+		}
+
+		var decimals uint8
+		if mintAcc, ok := l.accounts[string(tokenAccount.Mint)]; ok {
+			var mint mintAccount
+			if mint.unmarshal(mintAcc.data) {
+				decimals = mint.decimals
+			}
+		}
+
+		tokenBalances = append(tokenBalances, solana.TokenBalance{
+			AccountIndex: uint64(i),
+			Mint:         base58.Encode(tokenAccount.Mint),
+			TokenAmount: solana.TokenAmount{
+				Amount:   strconv.FormatUint(tokenAccount.Amount, 10),
+				Decimals: uint64(decimals),
+			},
+		})
+	}
+
+	return balances, tokenBalances
+}
+
+func getMemo(txn solana.Transaction) *string {
+	var memos []string
+	for i := range txn.Message.Instructions {
+		decompiled, err := memo.DecompileMemo(txn.Message, i)
+		if err != nil {
+			continue
+		}
+
+		memos = append(memos, fmt.Sprintf("[%d] %s", len(decompiled.Data), string(decompiled.Data)))
+	}
+
+	if len(memos) == 0 {
+		return nil
+	}
+
+	res := strings.Join(memos, "; ")
+	return &res
+}
+
+func encodePublicKeys(keys []ed25519.PublicKey) []string {
+	res := make([]string, len(keys))
+	for i, key := range keys {
+		res[i] = base58.Encode(key)
+	}
+	return res
+}
+
+func transfer(from, to ed25519.PublicKey, lamports uint64) solana.Instruction {
+	data := make([]byte, 4+8)
+	data[0] = systemCommandTransfer
+	putUint64(data[4:], lamports)
+
+	return solana.NewInstruction(
+		system.ProgramKey[:],
+		data,
+		solana.NewAccountMeta(from, true),
+		solana.NewAccountMeta(to, false),
+	)
+}
+
+var errUnsupportedProgram = errors.New(string(solana.InstructionErrorUnsupportedProgramID))
@@ -0,0 +1,251 @@
+package simulator
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/binary"
+
+	"github.com/code-payments/code-server/pkg/solana"
+	"github.com/code-payments/code-server/pkg/solana/system"
+)
+
+// Reference: https://github.com/solana-labs/solana/blob/master/sdk/program/src/system_instruction.rs
+const (
+	systemCommandCreateAccount          = 0
+	systemCommandTransfer               = 2
+	systemCommandAdvanceNonceAccount    = 4
+	systemCommandWithdrawNonceAccount   = 5
+	systemCommandInitializeNonceAccount = 6
+	systemCommandAuthorizeNonceAccount  = 7
+)
+
+// Reference: https://github.com/solana-labs/solana/blob/master/sdk/program/src/nonce/state/mod.rs
+const (
+	nonceStateUninitialized uint32 = iota
+	nonceStateInitialized
+)
+
+func executeSystemInstruction(e *executor, index int) error {
+	ixn := e.msg.Instructions[index]
+	if len(ixn.Data) < 4 {
+		return instructionError(solana.InstructionErrorInvalidInstructionData)
+	}
+
+	accounts := e.getInstructionAccounts(index)
+	data := ixn.Data[4:]
+
+	switch binary.LittleEndian.Uint32(ixn.Data) {
+	case systemCommandCreateAccount:
+		if len(accounts) < 2 || len(data) != 2*8+ed25519.PublicKeySize {
+			return instructionError(solana.InstructionErrorInvalidInstructionData)
+		}
+		return e.executeSystemCreateAccount(
+			accounts[0],
+			accounts[1],
+			binary.LittleEndian.Uint64(data),
+			binary.LittleEndian.Uint64(data[8:]),
+			data[16:],
+		)
+	case systemCommandTransfer:
+		if len(accounts) < 2 || len(data) != 8 {
+			return instructionError(solana.InstructionErrorInvalidInstructionData)
+		}
+		return e.executeSystemTransfer(accounts[0], accounts[1], binary.LittleEndian.Uint64(data))
+	case systemCommandAdvanceNonceAccount:
+		if len(accounts) < 3 {
+			return instructionError(solana.InstructionErrorNotEnoughAccountKeys)
+		}
+		return e.executeAdvanceNonce(accounts[0], accounts[2])
+	case systemCommandWithdrawNonceAccount:
+		if len(accounts) < 5 || len(data) != 8 {
+			return instructionError(solana.InstructionErrorInvalidInstructionData)
+		}
+		return e.executeWithdrawNonce(accounts[0], accounts[1], accounts[4], binary.LittleEndian.Uint64(data))
+	case systemCommandInitializeNonceAccount:
+		if len(accounts) < 1 || len(data) != ed25519.PublicKeySize {
+			return instructionError(solana.InstructionErrorInvalidInstructionData)
+		}
+		return e.executeInitializeNonce(accounts[0], data)
+	case systemCommandAuthorizeNonceAccount:
+		if len(accounts) < 1 || len(data) != ed25519.PublicKeySize {
+			return instructionError(solana.InstructionErrorInvalidInstructionData)
+		}
+
+		// The builder in pkg/solana/system only references the nonce account,
+		// in which case it is expected to be the current authority.
+		authority := accounts[0]
+		if len(accounts) > 1 {
+			authority = accounts[1]
+		}
+		return e.executeAuthorizeNonce(accounts[0], authority, data)
+	default:
+		return instructionError(solana.InstructionErrorInvalidInstructionData)
+	}
+}
+
+func (e *executor) executeSystemCreateAccount(funder, address ed25519.PublicKey, lamports, size uint64, owner ed25519.PublicKey) error {
+	if err := e.requireSigner(funder); err != nil {
+		return err
+	}
+	if err := e.requireSigner(address); err != nil {
+		return err
+	}
+
+	if existing, ok := e.get(address); ok && (existing.lamports > 0 || len(existing.data) > 0) {
+		return systemErrorAccountAlreadyInUse
+	}
+
+	if err := e.transferLamports(funder, address, lamports); err != nil {
+		return err
+	}
+
+	acc, _ := e.get(address)
+	acc.owner = append(ed25519.PublicKey(nil), owner...)
+	acc.data = make([]byte, size)
+	return e.put(address, acc)
+}
+
+func (e *executor) executeSystemTransfer(from, to ed25519.PublicKey, lamports uint64) error {
+	if err := e.requireSigner(from); err != nil {
+		return err
+	}
+
+	source, ok := e.get(from)
+	if ok && (len(source.data) > 0 || !bytes.Equal(source.owner, system.SystemAccount)) {
+		return instructionError(solana.InstructionErrorInvalidArgument)
+	}
+
+	return e.transferLamports(from, to, lamports)
+}
+
+func (e *executor) executeAdvanceNonce(address, authority ed25519.PublicKey) error {
+	acc, nonce, err := e.getNonceAccount(address)
+	if err != nil {
+		return err
+	}
+
+	if nonce.State != nonceStateInitialized {
+		return instructionError(solana.InstructionErrorInvalidAccountData)
+	}
+
+	if !bytes.Equal(nonce.Authority, authority) {
+		return instructionError(solana.InstructionErrorMissingRequiredSignature)
+	}
+	if err := e.requireSigner(authority); err != nil {
+		return err
+	}
+
+	// Mirrors the runtime's NonceBlockhashNotExpired check
+	if bytes.Equal(nonce.Blockhash, e.nonce[:]) {
+		return solana.CustomError(6)
+	}
+
+	nonce.Blockhash = append(ed25519.PublicKey(nil), e.nonce[:]...)
+	nonce.FeeCalculator.LamportsPerSignature = e.l.lamportsPerSignature
+	acc.data = nonce.Marshal()
+	return e.put(address, acc)
+}
+
+func (e *executor) executeWithdrawNonce(address, recipient, authority ed25519.PublicKey, lamports uint64) error {
+	acc, nonce, err := e.getNonceAccount(address)
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(nonce.Authority, authority) {
+		return instructionError(solana.InstructionErrorMissingRequiredSignature)
+	}
+	if err := e.requireSigner(authority); err != nil {
+		return err
+	}
+
+	if acc.lamports < lamports {
+		return instructionError(solana.InstructionErrorInsufficientFunds)
+	}
+
+	remaining := acc.lamports - lamports
+	if remaining > 0 && remaining < getMinimumBalanceForRentExemption(system.NonceAccountSize) {
+		return instructionError(solana.InstructionErrorInsufficientFunds)
+	}
+
+	if remaining == 0 {
+		if err := e.transferLamports(address, recipient, lamports); err != nil {
+			return err
+		}
+		return e.remove(address)
+	}
+
+	return e.transferLamports(address, recipient, lamports)
+}
+
+func (e *executor) executeInitializeNonce(address, authority ed25519.PublicKey) error {
+	acc, ok := e.get(address)
+	if !ok || !bytes.Equal(acc.owner, system.SystemAccount) || len(acc.data) != system.NonceAccountSize {
+		return instructionError(solana.InstructionErrorInvalidAccountData)
+	}
+
+	var existing system.NonceAccount
+	_ = existing.Unmarshal(acc.data)
+	if existing.State != nonceStateUninitialized {
+		return instructionError(solana.InstructionErrorInvalidAccountData)
+	}
+
+	if acc.lamports < getMinimumBalanceForRentExemption(system.NonceAccountSize) {
+		return instructionError(solana.InstructionErrorInsufficientFunds)
+	}
+
+	nonce := system.NonceAccount{
+		Version:   uint32(system.NonceVersion1),
+		State:     nonceStateInitialized,
+		Authority: append(ed25519.PublicKey(nil), authority...),
+		Blockhash: append(ed25519.PublicKey(nil), e.nonce[:]...),
+		FeeCalculator: system.FeeCalculator{
+			LamportsPerSignature: e.l.lamportsPerSignature,
+		},
+	}
+	acc.data = nonce.Marshal()
+	return e.put(address, acc)
+}
+
+func (e *executor) executeAuthorizeNonce(address, authority, newAuthority ed25519.PublicKey) error {
+	acc, nonce, err := e.getNonceAccount(address)
+	if err != nil {
+		return err
+	}
+
+	if nonce.State != nonceStateInitialized || !bytes.Equal(nonce.Authority, authority) {
+		return instructionError(solana.InstructionErrorMissingRequiredSignature)
+	}
+	if err := e.requireSigner(authority); err != nil {
+		return err
+	}
+
+	nonce.Authority = append(ed25519.PublicKey(nil), newAuthority...)
+	acc.data = nonce.Marshal()
+	return e.put(address, acc)
+}
+
+func (e *executor) getNonceAccount(address ed25519.PublicKey) (*account, *system.NonceAccount, error) {
+	acc, ok := e.get(address)
+	if !ok || !bytes.Equal(acc.owner, system.SystemAccount) {
+		return nil, nil, instructionError(solana.InstructionErrorInvalidAccountData)
+	}
+
+	var nonce system.NonceAccount
+	if err := nonce.Unmarshal(acc.data); err != nil {
+		return nil, nil, instructionError(solana.InstructionErrorInvalidAccountData)
+	}
+	return acc, &nonce, nil
+}
+
+// getInstructionAccounts returns the resolved accounts referenced by an
+// instruction, in order
+func (e *executor) getInstructionAccounts(index int) []ed25519.PublicKey {
+	ixn := e.msg.Instructions[index]
+
+	accounts := make([]ed25519.PublicKey, len(ixn.Accounts))
+	for i, idx := range ixn.Accounts {
+		accounts[i] = e.msg.Accounts[idx]
+	}
+	return accounts
+}
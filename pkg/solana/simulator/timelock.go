@@ -0,0 +1,267 @@
+package simulator
+
+import (
+	"bytes"
+	"crypto/ed25519"
+
+	"github.com/code-payments/code-server/pkg/solana"
+	timelock_token "github.com/code-payments/code-server/pkg/solana/timelock/v1"
+)
+
+func executeTimelockInstruction(e *executor, index int) error {
+	txn := e.txn.resolved
+
+	if args, accounts, err := timelock_token.InitializeInstructionFromLegacyInstruction(txn, index); err == nil {
+		return e.executeTimelockInitialize(args, accounts)
+	}
+
+	if args, accounts, err := timelock_token.TransferWithAuthorityInstructionFromLegacyInstruction(txn, index); err == nil {
+		return e.executeTimelockTransferWithAuthority(args, accounts)
+	}
+
+	if _, accounts, err := timelock_token.WithdrawInstructionFromLegacyInstruction(txn, index); err == nil {
+		return e.executeTimelockWithdraw(accounts)
+	}
+
+	if args, accounts, err := timelock_token.BurnDustWithAuthorityInstructionFromLegacyInstruction(txn, index); err == nil {
+		return e.executeTimelockBurnDustWithAuthority(args, accounts)
+	}
+
+	if _, accounts, err := timelock_token.RevokeLockWithAuthorityFromLegacyInstruction(txn, index); err == nil {
+		return e.executeTimelockRevokeLockWithAuthority(accounts)
+	}
+
+	if _, accounts, err := timelock_token.DeactivateInstructionFromLegacyInstruction(txn, index); err == nil {
+		return e.executeTimelockDeactivate(accounts)
+	}
+
+	if _, accounts, err := timelock_token.CloseAccountsInstructionFromLegacyInstruction(txn, index); err == nil {
+		return e.executeTimelockCloseAccounts(accounts)
+	}
+
+	return instructionError(solana.InstructionErrorInvalidInstructionData)
+}
+
+func (e *executor) executeTimelockInitialize(args *timelock_token.InitializeInstructionArgs, accounts *timelock_token.InitializeInstructionAccounts) error {
+	if err := e.requireSigner(accounts.TimeAuthority); err != nil {
+		return err
+	}
+
+	state, _, err := timelock_token.GetStateAddress(&timelock_token.GetStateAddressArgs{
+		Mint:          accounts.Mint,
+		TimeAuthority: accounts.TimeAuthority,
+		VaultOwner:    accounts.VaultOwner,
+		NumDaysLocked: args.NumDaysLocked,
+	})
+	if err != nil || !bytes.Equal(state, accounts.Timelock) {
+		return instructionError(solana.InstructionErrorInvalidSeeds)
+	}
+
+	vault, vaultBump, err := timelock_token.GetVaultAddress(&timelock_token.GetVaultAddressArgs{
+		State:       state,
+		DataVersion: timelock_token.DataVersion1,
+	})
+	if err != nil || !bytes.Equal(vault, accounts.Vault) {
+		return instructionError(solana.InstructionErrorInvalidSeeds)
+	}
+
+	timelockAccount := &timelock_token.TimelockAccount{
+		DataVersion:    timelock_token.DataVersion1,
+		TimeAuthority:  accounts.TimeAuthority,
+		CloseAuthority: accounts.TimeAuthority,
+		Mint:           accounts.Mint,
+		Vault:          vault,
+		VaultBump:      vaultBump,
+		VaultState:     timelock_token.StateLocked,
+		VaultOwner:     accounts.VaultOwner,
+		NumDaysLocked:  args.NumDaysLocked,
+	}
+	if err := e.createAccount(accounts.Payer, accounts.Timelock, timelock_token.PROGRAM_ID, timelockAccount.Marshal()); err != nil {
+		return err
+	}
+
+	return e.createTokenAccount(accounts.Payer, accounts.Vault, accounts.Mint, accounts.Timelock)
+}
+
+func (e *executor) executeTimelockTransferWithAuthority(args *timelock_token.TransferWithAuthorityInstructionArgs, accounts *timelock_token.TransferWithAuthorityInstructionAccounts) error {
+	timelockAccount, err := e.getTimelockAccount(accounts.Timelock, accounts.Vault)
+	if err != nil {
+		return err
+	}
+
+	if timelockAccount.VaultState != timelock_token.StateLocked {
+		return timelockError(timelock_token.ErrInvalidTimeLockState)
+	}
+	if err := e.requireTimelockOwner(timelockAccount, accounts.VaultOwner); err != nil {
+		return err
+	}
+	if err := e.requireTimelockAuthority(timelockAccount, accounts.TimeAuthority); err != nil {
+		return err
+	}
+
+	_, vault, err := e.getTokenAccount(accounts.Vault)
+	if err != nil {
+		return err
+	}
+	if vault.Amount < args.Amount {
+		return timelockError(timelock_token.ErrInsufficientVaultBalance)
+	}
+
+	return e.transferTokens(accounts.Vault, accounts.Destination, args.Amount)
+}
+
+func (e *executor) executeTimelockWithdraw(accounts *timelock_token.WithdrawInstructionAccounts) error {
+	timelockAccount, err := e.getTimelockAccount(accounts.Timelock, accounts.Vault)
+	if err != nil {
+		return err
+	}
+
+	if timelockAccount.VaultState != timelock_token.StateUnlocked {
+		return timelockError(timelock_token.ErrInvalidTimeLockState)
+	}
+	if err := e.requireTimelockOwner(timelockAccount, accounts.VaultOwner); err != nil {
+		return err
+	}
+
+	_, vault, err := e.getTokenAccount(accounts.Vault)
+	if err != nil {
+		return err
+	}
+
+	return e.transferTokens(accounts.Vault, accounts.Destination, vault.Amount)
+}
+
+func (e *executor) executeTimelockBurnDustWithAuthority(args *timelock_token.BurnDustWithAuthorityInstructionArgs, accounts *timelock_token.BurnDustWithAuthorityInstructionAccounts) error {
+	timelockAccount, err := e.getTimelockAccount(accounts.Timelock, accounts.Vault)
+	if err != nil {
+		return err
+	}
+
+	if timelockAccount.VaultState != timelock_token.StateLocked {
+		return timelockError(timelock_token.ErrInvalidTimeLockState)
+	}
+	if !bytes.Equal(timelockAccount.Mint, accounts.Mint) {
+		return timelockError(timelock_token.ErrInvalidTokenMint)
+	}
+	if err := e.requireTimelockOwner(timelockAccount, accounts.VaultOwner); err != nil {
+		return err
+	}
+	if err := e.requireTimelockAuthority(timelockAccount, accounts.TimeAuthority); err != nil {
+		return err
+	}
+
+	_, vault, err := e.getTokenAccount(accounts.Vault)
+	if err != nil {
+		return err
+	}
+
+	amount := vault.Amount
+	if amount > args.MaxAmount {
+		amount = args.MaxAmount
+	}
+	return e.burnTokens(accounts.Vault, amount)
+}
+
+func (e *executor) executeTimelockRevokeLockWithAuthority(accounts *timelock_token.RevokeLockWithAuthorityInstructionAccounts) error {
+	timelockAccount, err := e.getTimelockAccount(accounts.Timelock, accounts.Vault)
+	if err != nil {
+		return err
+	}
+
+	if timelockAccount.VaultState != timelock_token.StateLocked {
+		return timelockError(timelock_token.ErrInvalidTimeLockState)
+	}
+	if err := e.requireTimelockAuthority(timelockAccount, accounts.TimeAuthority); err != nil {
+		return err
+	}
+
+	timelockAccount.VaultState = timelock_token.StateUnlocked
+	return e.putTimelockAccount(accounts.Timelock, timelockAccount)
+}
+
+func (e *executor) executeTimelockDeactivate(accounts *timelock_token.DeactivateInstructionAccounts) error {
+	timelockAccount, err := e.getTimelockAccount(accounts.Timelock, nil)
+	if err != nil {
+		return err
+	}
+
+	if timelockAccount.VaultState != timelock_token.StateUnlocked {
+		return timelockError(timelock_token.ErrInvalidTimeLockState)
+	}
+	return e.requireTimelockOwner(timelockAccount, accounts.VaultOwner)
+}
+
+func (e *executor) executeTimelockCloseAccounts(accounts *timelock_token.CloseAccountsInstructionAccounts) error {
+	timelockAccount, err := e.getTimelockAccount(accounts.Timelock, accounts.Vault)
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(timelockAccount.CloseAuthority, accounts.CloseAuthority) {
+		return timelockError(timelock_token.ErrInvalidCloseAuthority)
+	}
+	if err := e.requireSigner(accounts.CloseAuthority); err != nil {
+		return err
+	}
+
+	_, vault, err := e.getTokenAccount(accounts.Vault)
+	if err != nil {
+		return err
+	}
+	if vault.Amount > 0 {
+		return timelockError(timelock_token.ErrNonZeroTokenBalance)
+	}
+
+	if err := e.closeAccount(accounts.Vault, accounts.Payer); err != nil {
+		return err
+	}
+	return e.closeAccount(accounts.Timelock, accounts.Payer)
+}
+
+// getTimelockAccount loads the timelock state, and optionally validates the
+// provided vault against it.
+func (e *executor) getTimelockAccount(address, vault ed25519.PublicKey) (*timelock_token.TimelockAccount, error) {
+	acc, ok := e.get(address)
+	if !ok || !bytes.Equal(acc.owner, timelock_token.PROGRAM_ID) {
+		return nil, instructionError(solana.InstructionErrorInvalidAccountData)
+	}
+
+	var timelockAccount timelock_token.TimelockAccount
+	if err := timelockAccount.Unmarshal(acc.data); err != nil {
+		return nil, instructionError(solana.InstructionErrorInvalidAccountData)
+	}
+
+	if vault != nil && !bytes.Equal(timelockAccount.Vault, vault) {
+		return nil, timelockError(timelock_token.ErrInvalidVaultAccount)
+	}
+
+	return &timelockAccount, nil
+}
+
+func (e *executor) putTimelockAccount(address ed25519.PublicKey, timelockAccount *timelock_token.TimelockAccount) error {
+	acc, ok := e.get(address)
+	if !ok {
+		return instructionError(solana.InstructionErrorUninitializedAccount)
+	}
+
+	acc.data = timelockAccount.Marshal()
+	return e.put(address, acc)
+}
+
+func (e *executor) requireTimelockOwner(timelockAccount *timelock_token.TimelockAccount, owner ed25519.PublicKey) error {
+	if !bytes.Equal(timelockAccount.VaultOwner, owner) {
+		return timelockError(timelock_token.ErrInvalidVaultOwner)
+	}
+	return e.requireSigner(owner)
+}
+
+func (e *executor) requireTimelockAuthority(timelockAccount *timelock_token.TimelockAccount, authority ed25519.PublicKey) error {
+	if !bytes.Equal(timelockAccount.TimeAuthority, authority) {
+		return timelockError(timelock_token.ErrInvalidTimeAuthority)
+	}
+	return e.requireSigner(authority)
+}
+
+func timelockError(err timelock_token.TimeLockTokenError) error {
+	return solana.CustomError(err)
+}
@@ -0,0 +1,193 @@
+package simulator
+
+import (
+	"bytes"
+	"crypto/ed25519"
+
+	"github.com/code-payments/code-server/pkg/solana"
+	"github.com/code-payments/code-server/pkg/solana/token"
+)
+
+func executeTokenInstruction(e *executor, index int) error {
+	command, err := token.GetCommand(e.msg, index)
+	if err != nil {
+		return token.ErrorInvalidInstruction
+	}
+
+	switch command {
+	case token.CommandInitializeAccount:
+		decompiled, err := token.DecompileInitializeAccount(e.msg, index)
+		if err != nil {
+			return token.ErrorInvalidInstruction
+		}
+		return e.executeTokenInitializeAccount(decompiled)
+	case token.CommandTransfer:
+		decompiled, err := token.DecompileTransfer(e.msg, index)
+		if err != nil {
+			return token.ErrorInvalidInstruction
+		}
+		return e.executeTokenTransfer(decompiled.Source, decompiled.Destination, decompiled.Owner, decompiled.Amount)
+	case token.CommandTransfer2:
+		decompiled, err := token.DecompileTransfer2(e.msg, index)
+		if err != nil {
+			return token.ErrorInvalidInstruction
+		}
+
+		mintAcc, err := e.getMint(decompiled.Mint)
+		if err != nil {
+			return err
+		}
+		var mint mintAccount
+		mint.unmarshal(mintAcc.data)
+		if mint.decimals != decompiled.Decimals {
+			return token.ErrorMintDecimalsMismatch
+		}
+
+		_, source, err := e.getTokenAccount(decompiled.Source)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(source.Mint, decompiled.Mint) {
+			return token.ErrorMintMismatch
+		}
+
+		return e.executeTokenTransfer(decompiled.Source, decompiled.Destination, decompiled.Owner, decompiled.Amount)
+	case token.CommandSetAuthority:
+		decompiled, err := token.DecompileSetAuthority(e.msg, index)
+		if err != nil {
+			return token.ErrorInvalidInstruction
+		}
+		return e.executeTokenSetAuthority(decompiled)
+	case token.CommandCloseAccount:
+		decompiled, err := token.DecompileCloseAccount(e.msg, index)
+		if err != nil {
+			return token.ErrorInvalidInstruction
+		}
+		return e.executeTokenCloseAccount(decompiled)
+	default:
+		return token.ErrorInvalidInstruction
+	}
+}
+
+func (e *executor) executeTokenInitializeAccount(decompiled *token.DecompiledInitializeAccount) error {
+	acc, ok := e.get(decompiled.Account)
+	if !ok || !bytes.Equal(acc.owner, token.ProgramKey) {
+		return instructionError(solana.InstructionErrorIncorrectProgramID)
+	}
+
+	var tokenAccount token.Account
+	if !tokenAccount.Unmarshal(acc.data) {
+		return instructionError(solana.InstructionErrorInvalidAccountData)
+	}
+	if tokenAccount.State != token.AccountStateUninitialized {
+		return token.ErrorAlreadyInUse
+	}
+
+	if acc.lamports < getMinimumBalanceForRentExemption(token.AccountSize) {
+		return token.ErrorNotRentExempt
+	}
+
+	if _, err := e.getMint(decompiled.Mint); err != nil {
+		return err
+	}
+
+	tokenAccount = token.Account{
+		Mint:  decompiled.Mint,
+		Owner: decompiled.Owner,
+		State: token.AccountStateInitialized,
+	}
+	return e.putTokenAccount(decompiled.Account, acc, &tokenAccount)
+}
+
+func (e *executor) executeTokenTransfer(source, destination, owner ed25519.PublicKey, amount uint64) error {
+	_, sourceTokenAccount, err := e.getTokenAccount(source)
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(sourceTokenAccount.Owner, owner) {
+		return token.ErrorOwnerMismatch
+	}
+	if err := e.requireSigner(owner); err != nil {
+		return err
+	}
+
+	return e.transferTokens(source, destination, amount)
+}
+
+func (e *executor) executeTokenSetAuthority(decompiled *token.DecompiledSetAuthority) error {
+	acc, tokenAccount, err := e.getTokenAccount(decompiled.Account)
+	if err != nil {
+		return err
+	}
+
+	if err := e.requireSigner(decompiled.CurrentAuthority); err != nil {
+		return err
+	}
+
+	switch decompiled.Type {
+	case token.AuthorityTypeAccountHolder:
+		if !bytes.Equal(tokenAccount.Owner, decompiled.CurrentAuthority) {
+			return token.ErrorOwnerMismatch
+		}
+		if len(decompiled.NewAuthority) == 0 {
+			return token.ErrorInvalidInstruction
+		}
+
+		tokenAccount.Owner = decompiled.NewAuthority
+		tokenAccount.Delegate = nil
+		tokenAccount.DelegatedAmount = 0
+	case token.AuthorityTypeCloseAccount:
+		authority := tokenAccount.CloseAuthority
+		if len(authority) == 0 {
+			authority = tokenAccount.Owner
+		}
+		if !bytes.Equal(authority, decompiled.CurrentAuthority) {
+			return token.ErrorOwnerMismatch
+		}
+
+		tokenAccount.CloseAuthority = decompiled.NewAuthority
+	default:
+		return token.ErrorAuthorityTypeNotSupported
+	}
+
+	return e.putTokenAccount(decompiled.Account, acc, tokenAccount)
+}
+
+func (e *executor) executeTokenCloseAccount(decompiled *token.DecompiledCloseAccount) error {
+	_, tokenAccount, err := e.getTokenAccount(decompiled.Account)
+	if err != nil {
+		return err
+	}
+
+	if tokenAccount.Amount > 0 {
+		return token.ErrorNonNativeHasBalance
+	}
+
+	authority := tokenAccount.CloseAuthority
+	if len(authority) == 0 {
+		authority = tokenAccount.Owner
+	}
+	if !bytes.Equal(authority, decompiled.Owner) {
+		return token.ErrorOwnerMismatch
+	}
+	if err := e.requireSigner(decompiled.Owner); err != nil {
+		return err
+	}
+
+	return e.closeAccount(decompiled.Account, decompiled.Destination)
+}
+
+func executeAssociatedTokenAccountInstruction(e *executor, index int) error {
+	decompiled, err := token.DecompileCreateAssociatedAccount(e.msg, index)
+	if err != nil {
+		return instructionError(solana.InstructionErrorInvalidInstructionData)
+	}
+
+	expected, err := token.GetAssociatedAccount(decompiled.Owner, decompiled.Mint)
+	if err != nil || !bytes.Equal(expected, decompiled.Address) {
+		return instructionError(solana.InstructionErrorInvalidSeeds)
+	}
+
+	return e.createTokenAccount(decompiled.Subsidizer, decompiled.Address, decompiled.Mint, decompiled.Owner)
+}
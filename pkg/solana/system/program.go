@@ -78,8 +78,8 @@ type DecompiledCreateAccount struct {
 }
 
 func DecompileCreateAccount(m solana.Message, index int) (*DecompiledCreateAccount, error) {
-	if index >= len(m.Instructions) {
-		return nil, errors.Errorf("instruction doesn't exist at %d", index)
+	if err := m.CheckInstructionAccounts(index); err != nil {
+		return nil, err
 	}
 
 	var prefix [4]byte
@@ -136,8 +136,8 @@ type DecompiledAdvanceNonce struct {
 }
 
 func DecompileAdvanceNonce(m solana.Message, index int) (*DecompiledAdvanceNonce, error) {
-	if index >= len(m.Instructions) {
-		return nil, errors.Errorf("instruction doesn't exist at %d", index)
+	if err := m.CheckInstructionAccounts(index); err != nil {
+		return nil, err
 	}
 
 	var prefix [4]byte
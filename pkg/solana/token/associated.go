@@ -55,8 +55,8 @@ type DecompiledCreateAssociatedAccount struct {
 }
 
 func DecompileCreateAssociatedAccount(m solana.Message, index int) (*DecompiledCreateAssociatedAccount, error) {
-	if index >= len(m.Instructions) {
-		return nil, errors.Errorf("instruction doesn't exist at %d", index)
+	if err := m.CheckInstructionAccounts(index); err != nil {
+		return nil, err
 	}
 
 	i := m.Instructions[index]
@@ -95,8 +95,8 @@ const (
 )
 
 func GetCommand(m solana.Message, index int) (Command, error) {
-	if index >= len(m.Instructions) {
-		return CommandUnknown, errors.Errorf("instruction doesn't exist at %d", index)
+	if err := m.CheckInstructionAccounts(index); err != nil {
+		return CommandUnknown, err
 	}
 
 	i := m.Instructions[index]
@@ -136,8 +136,8 @@ type DecompiledInitializeAccount struct {
 }
 
 func DecompileInitializeAccount(m solana.Message, index int) (*DecompiledInitializeAccount, error) {
-	if index >= len(m.Instructions) {
-		return nil, errors.Errorf("instruction doesn't exist at %d", index)
+	if err := m.CheckInstructionAccounts(index); err != nil {
+		return nil, err
 	}
 
 	i := m.Instructions[index]
@@ -261,8 +261,8 @@ type DecompiledSetAuthority struct {
 }
 
 func DecompileSetAuthority(m solana.Message, index int) (*DecompiledSetAuthority, error) {
-	if index >= len(m.Instructions) {
-		return nil, errors.Errorf("instruction doesn't exist at %d", index)
+	if err := m.CheckInstructionAccounts(index); err != nil {
+		return nil, err
 	}
 
 	i := m.Instructions[index]
@@ -397,8 +397,8 @@ type DecompiledTransfer struct {
 }
 
 func DecompileTransfer(m solana.Message, index int) (*DecompiledTransfer, error) {
-	if index >= len(m.Instructions) {
-		return nil, errors.Errorf("instruction doesn't exist at %d", index)
+	if err := m.CheckInstructionAccounts(index); err != nil {
+		return nil, err
 	}
 
 	i := m.Instructions[index]
@@ -436,8 +436,8 @@ type DecompiledTransfer2 struct {
 }
 
 func DecompileTransfer2(m solana.Message, index int) (*DecompiledTransfer2, error) {
-	if index >= len(m.Instructions) {
-		return nil, errors.Errorf("instruction doesn't exist at %d", index)
+	if err := m.CheckInstructionAccounts(index); err != nil {
+		return nil, err
 	}
 
 	i := m.Instructions[index]
@@ -500,8 +500,8 @@ type DecompiledCloseAccount struct {
 }
 
 func DecompileCloseAccount(m solana.Message, index int) (*DecompiledCloseAccount, error) {
-	if index >= len(m.Instructions) {
-		return nil, errors.Errorf("instruction doesn't exist at %d", index)
+	if err := m.CheckInstructionAccounts(index); err != nil {
+		return nil, err
 	}
 
 	i := m.Instructions[index]
@@ -273,6 +273,33 @@ func TestTransfer(t *testing.T) {
 	assert.Equal(t, solana.ErrIncorrectProgram, err)
 }
 
+func TestTransfer_LookupTableAccounts(t *testing.T) {
+	keys := generateKeys(t, 5)
+
+	lookupTable := solana.AddressLookupTable{
+		PublicKey: keys[4],
+		Addresses: []ed25519.PublicKey{keys[1]},
+	}
+
+	// The destination is loaded from the lookup table, so its account index is
+	// outside of the message's static accounts.
+	txn := solana.NewVersionedTransaction(keys[3], []solana.AddressLookupTable{lookupTable}, Transfer(keys[0], keys[1], keys[2], 123456789))
+
+	var decoded solana.Transaction
+	require.NoError(t, decoded.Unmarshal(txn.Marshal()))
+	require.Len(t, decoded.Message.AddressTableLookups, 1)
+
+	_, err := DecompileTransfer(decoded.Message, 0)
+	assert.Equal(t, solana.ErrInvalidAccountIndex, err)
+
+	// Out of range program indexes are rejected as well
+	decoded.Message.Instructions[0].ProgramIndex = byte(len(decoded.Message.Accounts))
+	_, err = GetCommand(decoded.Message, 0)
+	assert.Equal(t, solana.ErrInvalidAccountIndex, err)
+	_, err = DecompileTransfer(decoded.Message, 0)
+	assert.Equal(t, solana.ErrInvalidAccountIndex, err)
+}
+
 func TestTransfer2(t *testing.T) {
 	keys := generateKeys(t, 5)
 
@@ -5,6 +5,7 @@ import (
 	"crypto/ed25519"
 	"crypto/sha256"
 	"fmt"
+	"math"
 	"sort"
 	"strings"
 
@@ -26,11 +27,42 @@ type Header struct {
 	NumReadOnly       byte
 }
 
+// MessageVersion is the version of a transaction message
+type MessageVersion uint8
+
+const (
+	// MessageVersionLegacy is the original, unversioned, message format
+	MessageVersionLegacy MessageVersion = iota
+	// MessageVersion0 is the first versioned message format, which adds support
+	// for address lookup tables
+	MessageVersion0
+)
+
+// Reference: https://github.com/solana-labs/solana/blob/master/sdk/program/src/message/versions/mod.rs
+const messageVersionPrefix = byte(0x80)
+
 type Message struct {
-	Header          Header
-	Accounts        []ed25519.PublicKey
-	RecentBlockhash Blockhash
-	Instructions    []CompiledInstruction
+	Version             MessageVersion
+	Header              Header
+	Accounts            []ed25519.PublicKey
+	RecentBlockhash     Blockhash
+	Instructions        []CompiledInstruction
+	AddressTableLookups []MessageAddressTableLookup
+}
+
+// MessageAddressTableLookup references a set of accounts loaded from an
+// address lookup table by a versioned message.
+type MessageAddressTableLookup struct {
+	PublicKey       ed25519.PublicKey
+	WritableIndexes []byte
+	ReadonlyIndexes []byte
+}
+
+// AddressLookupTable is the set of addresses stored in an on-chain address
+// lookup table, which can be used to compile versioned transactions.
+type AddressLookupTable struct {
+	PublicKey ed25519.PublicKey
+	Addresses []ed25519.PublicKey
 }
 
 type Transaction struct {
@@ -107,6 +139,203 @@ func NewTransaction(payer ed25519.PublicKey, instructions ...Instruction) Transa
 	}
 }
 
+// NewVersionedTransaction creates a v0 transaction that loads any eligible
+// account from the provided address lookup tables. Signers and invoked programs
+// are never loaded from a table, and must be included in the message directly.
+func NewVersionedTransaction(payer ed25519.PublicKey, lookupTables []AddressLookupTable, instructions ...Instruction) Transaction {
+	accounts := []AccountMeta{
+		{
+			PublicKey:  payer,
+			IsSigner:   true,
+			IsWritable: true,
+			isPayer:    true,
+		},
+	}
+
+	var programs []ed25519.PublicKey
+	for _, i := range instructions {
+		accounts = append(accounts, AccountMeta{
+			PublicKey: i.Program,
+			isProgram: true,
+		})
+		accounts = append(accounts, i.Accounts...)
+		programs = append(programs, i.Program)
+	}
+
+	accounts = filterUnique(accounts)
+	sort.Sort(SortableAccountMeta(accounts))
+
+	m := Message{
+		Version: MessageVersion0,
+	}
+
+	// Split accounts into the ones included directly in the message, and the
+	// ones that can be loaded from one of the lookup tables.
+	lookups := make([]MessageAddressTableLookup, len(lookupTables))
+	var writableLoaded, readonlyLoaded []ed25519.PublicKey
+	for tableIndex, table := range lookupTables {
+		lookups[tableIndex].PublicKey = table.PublicKey
+	}
+	for _, account := range accounts {
+		if account.IsSigner || indexOf(programs, account.PublicKey) >= 0 || len(account.PublicKey) == 0 {
+			m.Accounts = append(m.Accounts, account.PublicKey)
+			continue
+		}
+
+		var isLoaded bool
+		for tableIndex, table := range lookupTables {
+			addressIndex := indexOf(table.Addresses, account.PublicKey)
+			if addressIndex < 0 || addressIndex > math.MaxUint8 {
+				continue
+			}
+
+			if account.IsWritable {
+				lookups[tableIndex].WritableIndexes = append(lookups[tableIndex].WritableIndexes, byte(addressIndex))
+			} else {
+				lookups[tableIndex].ReadonlyIndexes = append(lookups[tableIndex].ReadonlyIndexes, byte(addressIndex))
+			}
+
+			isLoaded = true
+			break
+		}
+
+		if !isLoaded {
+			m.Accounts = append(m.Accounts, account.PublicKey)
+		}
+	}
+
+	for _, account := range accounts {
+		if indexOf(m.Accounts, account.PublicKey) >= 0 {
+			if account.IsSigner {
+				m.Header.NumSignatures++
+
+				if !account.IsWritable {
+					m.Header.NumReadonlySigned++
+				}
+			} else if !account.IsWritable {
+				m.Header.NumReadOnly++
+			}
+		}
+	}
+
+	for tableIndex, lookup := range lookups {
+		if len(lookup.WritableIndexes) == 0 && len(lookup.ReadonlyIndexes) == 0 {
+			continue
+		}
+
+		for _, addressIndex := range lookup.WritableIndexes {
+			writableLoaded = append(writableLoaded, lookupTables[tableIndex].Addresses[addressIndex])
+		}
+		for _, addressIndex := range lookup.ReadonlyIndexes {
+			readonlyLoaded = append(readonlyLoaded, lookupTables[tableIndex].Addresses[addressIndex])
+		}
+
+		m.AddressTableLookups = append(m.AddressTableLookups, lookup)
+	}
+
+	// Loaded accounts are indexed after all static accounts, with writable
+	// accounts preceding read-only accounts.
+	allAccounts := append([]ed25519.PublicKey{}, m.Accounts...)
+	allAccounts = append(allAccounts, writableLoaded...)
+	allAccounts = append(allAccounts, readonlyLoaded...)
+
+	for _, i := range instructions {
+		c := CompiledInstruction{
+			ProgramIndex: byte(indexOf(allAccounts, i.Program)),
+			Data:         i.Data,
+		}
+
+		for _, a := range i.Accounts {
+			c.Accounts = append(c.Accounts, byte(indexOf(allAccounts, a.PublicKey)))
+		}
+
+		m.Instructions = append(m.Instructions, c)
+	}
+
+	for i := range m.Accounts {
+		if len(m.Accounts[i]) == 0 {
+			m.Accounts[i] = make([]byte, ed25519.PublicKeySize)
+		}
+	}
+
+	return Transaction{
+		Signatures: make([]Signature, m.Header.NumSignatures),
+		Message:    m,
+	}
+}
+
+// GetLoadedAccounts resolves the accounts loaded by the message's address table
+// lookups using the provided lookup tables. The returned accounts are ordered
+// as they're indexed by the message's compiled instructions, and follow directly
+// after the message's static accounts.
+func (m Message) GetLoadedAccounts(lookupTables ...AddressLookupTable) (writable, readonly []ed25519.PublicKey, err error) {
+	for _, lookup := range m.AddressTableLookups {
+		var table *AddressLookupTable
+		for i := range lookupTables {
+			if bytes.Equal(lookupTables[i].PublicKey, lookup.PublicKey) {
+				table = &lookupTables[i]
+				break
+			}
+		}
+		if table == nil {
+			return nil, nil, errors.Errorf("address lookup table %s not provided", base58.Encode(lookup.PublicKey))
+		}
+
+		for _, addressIndex := range lookup.WritableIndexes {
+			if int(addressIndex) >= len(table.Addresses) {
+				return nil, nil, errors.Errorf("address lookup table index out of range: %d", addressIndex)
+			}
+			writable = append(writable, table.Addresses[addressIndex])
+		}
+		for _, addressIndex := range lookup.ReadonlyIndexes {
+			if int(addressIndex) >= len(table.Addresses) {
+				return nil, nil, errors.Errorf("address lookup table index out of range: %d", addressIndex)
+			}
+			readonly = append(readonly, table.Addresses[addressIndex])
+		}
+	}
+
+	return writable, readonly, nil
+}
+
+// GetAccounts returns the full set of accounts referenced by the message's
+// compiled instructions, resolving any loaded accounts using the provided
+// lookup tables.
+func (m Message) GetAccounts(lookupTables ...AddressLookupTable) ([]ed25519.PublicKey, error) {
+	writable, readonly, err := m.GetLoadedAccounts(lookupTables...)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts := append([]ed25519.PublicKey{}, m.Accounts...)
+	accounts = append(accounts, writable...)
+	accounts = append(accounts, readonly...)
+	return accounts, nil
+}
+
+// CheckInstructionAccounts returns an error if the compiled instruction at the
+// provided index doesn't exist, or references an account outside of the message's
+// static accounts. Instructions in versioned messages can reference accounts loaded
+// from address lookup tables, which must be resolved with GetAccounts and can't be
+// read from Accounts directly.
+func (m Message) CheckInstructionAccounts(index int) error {
+	if index < 0 || index >= len(m.Instructions) {
+		return errors.Errorf("instruction doesn't exist at %d", index)
+	}
+
+	i := m.Instructions[index]
+	if int(i.ProgramIndex) >= len(m.Accounts) {
+		return ErrInvalidAccountIndex
+	}
+	for _, accountIndex := range i.Accounts {
+		if int(accountIndex) >= len(m.Accounts) {
+			return ErrInvalidAccountIndex
+		}
+	}
+
+	return nil
+}
+
 func (t *Transaction) Signature() []byte {
 	return t.Signatures[0][:]
 }
@@ -118,6 +347,9 @@ func (t *Transaction) String() string {
 		sb.WriteString(fmt.Sprintf("  %d: %s\n", i, base58.Encode(s[:])))
 	}
 	sb.WriteString("Message:\n")
+	if t.Message.Version == MessageVersion0 {
+		sb.WriteString("  Version: 0\n")
+	}
 	sb.WriteString("  Header:\n")
 	sb.WriteString(fmt.Sprintf("    NumSignatures: %d\n", t.Message.Header.NumSignatures))
 	sb.WriteString(fmt.Sprintf("    NumReadOnly: %d\n", t.Message.Header.NumReadOnly))
@@ -133,6 +365,15 @@ func (t *Transaction) String() string {
 		sb.WriteString(fmt.Sprintf("      Accounts: %v\n", t.Message.Instructions[i].Accounts))
 		sb.WriteString(fmt.Sprintf("      Data: %v\n", t.Message.Instructions[i].Data))
 	}
+	if len(t.Message.AddressTableLookups) > 0 {
+		sb.WriteString("  AddressTableLookups:\n")
+		for i, l := range t.Message.AddressTableLookups {
+			sb.WriteString(fmt.Sprintf("    %d:\n", i))
+			sb.WriteString(fmt.Sprintf("      PublicKey: %s\n", base58.Encode(l.PublicKey)))
+			sb.WriteString(fmt.Sprintf("      WritableIndexes: %v\n", l.WritableIndexes))
+			sb.WriteString(fmt.Sprintf("      ReadonlyIndexes: %v\n", l.ReadonlyIndexes))
+		}
+	}
 
 	return sb.String()
 }
@@ -349,3 +349,166 @@ func generateKeys(t *testing.T, amount int) []ed25519.PrivateKey {
 
 	return keys
 }
+
+func TestTransaction_Versioned(t *testing.T) {
+	keys := generateKeys(t, 7)
+	payer := keys[0]
+	program := keys[1]
+	signer := keys[2]
+	writable := keys[3]
+	readonly := keys[4]
+	notInTable := keys[5]
+	table := keys[6]
+
+	data := []byte{1, 2, 3}
+
+	lookupTable := AddressLookupTable{
+		PublicKey: public(table),
+		Addresses: []ed25519.PublicKey{
+			public(program),
+			public(signer),
+			public(readonly),
+			public(writable),
+		},
+	}
+
+	instruction := NewInstruction(
+		public(program),
+		data,
+		NewReadonlyAccountMeta(public(readonly), false),
+		NewAccountMeta(public(signer), true),
+		NewAccountMeta(public(writable), false),
+		NewReadonlyAccountMeta(public(notInTable), false),
+	)
+
+	tx := NewVersionedTransaction(public(payer), []AddressLookupTable{lookupTable}, instruction)
+	assert.NoError(t, tx.Sign(signer, payer))
+
+	assert.Equal(t, MessageVersion0, tx.Message.Version)
+	require.Len(t, tx.Signatures, 2)
+	require.Len(t, tx.Message.Accounts, 4)
+	assert.EqualValues(t, 2, tx.Message.Header.NumSignatures)
+	assert.EqualValues(t, 0, tx.Message.Header.NumReadonlySigned)
+	assert.EqualValues(t, 2, tx.Message.Header.NumReadOnly)
+
+	assert.Equal(t, public(payer), tx.Message.Accounts[0])
+	assert.Equal(t, public(signer), tx.Message.Accounts[1])
+	assert.Equal(t, public(notInTable), tx.Message.Accounts[2])
+	assert.Equal(t, public(program), tx.Message.Accounts[3])
+
+	require.Len(t, tx.Message.AddressTableLookups, 1)
+	assert.Equal(t, public(table), tx.Message.AddressTableLookups[0].PublicKey)
+	assert.Equal(t, []byte{3}, tx.Message.AddressTableLookups[0].WritableIndexes)
+	assert.Equal(t, []byte{2}, tx.Message.AddressTableLookups[0].ReadonlyIndexes)
+
+	assert.Equal(t, byte(3), tx.Message.Instructions[0].ProgramIndex)
+	assert.Equal(t, data, tx.Message.Instructions[0].Data)
+	assert.Equal(t, []byte{5, 1, 4, 2}, tx.Message.Instructions[0].Accounts)
+
+	message := tx.Message.Marshal()
+	assert.Equal(t, byte(0x80), message[0])
+	assert.True(t, ed25519.Verify(public(payer), message, tx.Signatures[0][:]))
+	assert.True(t, ed25519.Verify(public(signer), message, tx.Signatures[1][:]))
+
+	accounts, err := tx.Message.GetAccounts(lookupTable)
+	require.NoError(t, err)
+	assert.Equal(t, []ed25519.PublicKey{
+		public(payer),
+		public(signer),
+		public(notInTable),
+		public(program),
+		public(writable),
+		public(readonly),
+	}, accounts)
+
+	_, err = tx.Message.GetAccounts()
+	assert.Error(t, err)
+
+	var rtt Transaction
+	require.NoError(t, rtt.Unmarshal(tx.Marshal()))
+	assert.Equal(t, tx, rtt)
+
+	legacy := NewTransaction(public(payer), instruction)
+	assert.True(t, len(tx.Marshal()) < len(legacy.Marshal()))
+}
+
+func TestMessage_CheckInstructionAccounts(t *testing.T) {
+	keys := generateKeys(t, 5)
+	payer := keys[0]
+	program := keys[1]
+	static := keys[2]
+	loaded := keys[3]
+	table := keys[4]
+
+	lookupTable := AddressLookupTable{
+		PublicKey: public(table),
+		Addresses: []ed25519.PublicKey{public(loaded)},
+	}
+
+	tx := NewVersionedTransaction(
+		public(payer),
+		[]AddressLookupTable{lookupTable},
+		NewInstruction(public(program), nil, NewAccountMeta(public(static), false)),
+		NewInstruction(public(program), nil, NewAccountMeta(public(loaded), false)),
+	)
+
+	assert.NoError(t, tx.Message.CheckInstructionAccounts(0))
+	assert.Equal(t, ErrInvalidAccountIndex, tx.Message.CheckInstructionAccounts(1))
+	assert.Error(t, tx.Message.CheckInstructionAccounts(2))
+	assert.Error(t, tx.Message.CheckInstructionAccounts(-1))
+}
+
+func TestTransaction_VersionedWithoutLookups(t *testing.T) {
+	keys := generateKeys(t, 3)
+
+	tx := NewVersionedTransaction(
+		public(keys[0]),
+		nil,
+		NewInstruction(
+			public(keys[1]),
+			[]byte{1},
+			NewAccountMeta(public(keys[2]), false),
+		),
+	)
+	assert.NoError(t, tx.Sign(keys[0]))
+	assert.Empty(t, tx.Message.AddressTableLookups)
+
+	var rtt Transaction
+	require.NoError(t, rtt.Unmarshal(tx.Marshal()))
+	assert.Equal(t, MessageVersion0, rtt.Message.Version)
+	assert.Equal(t, tx.Message.Accounts, rtt.Message.Accounts)
+	assert.Empty(t, rtt.Message.AddressTableLookups)
+}
+
+func TestTransaction_VersionedInvalid(t *testing.T) {
+	keys := generateKeys(t, 4)
+
+	tx := NewVersionedTransaction(
+		public(keys[0]),
+		[]AddressLookupTable{
+			{
+				PublicKey: public(keys[3]),
+				Addresses: []ed25519.PublicKey{public(keys[2])},
+			},
+		},
+		NewInstruction(
+			public(keys[1]),
+			nil,
+			NewAccountMeta(public(keys[2]), false),
+		),
+	)
+
+	var rtt Transaction
+	require.NoError(t, rtt.Unmarshal(tx.Marshal()))
+
+	tx.Message.Instructions[0].Accounts = []byte{3}
+	assert.Error(t, rtt.Unmarshal(tx.Marshal()))
+
+	tx.Message.Instructions[0].Accounts = []byte{2}
+	tx.Message.Instructions[0].ProgramIndex = 3
+	assert.Error(t, rtt.Unmarshal(tx.Marshal()))
+
+	marshalled := tx.Marshal()
+	marshalled[1+ed25519.SignatureSize] = 0x81
+	assert.Error(t, rtt.Unmarshal(marshalled))
+}
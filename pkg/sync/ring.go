@@ -59,3 +59,34 @@ func (r *ring) shard(key []byte) interface{} {
 	}
 	return r.minEntryValue
 }
+
+// HashRing is a consistent hash ring that assigns keys to a set of named
+// members. Adding or removing a member only moves the keys owned by that
+// member, which makes it suitable for sharding work across a dynamic set of
+// nodes.
+type HashRing struct {
+	ring *ring
+}
+
+// NewHashRing returns a new HashRing over the provided members, each having
+// replicationFactor entries in the ring
+func NewHashRing(members []string, replicationFactor uint) *HashRing {
+	entries := make(map[string]interface{})
+	for _, member := range members {
+		entries[member] = member
+	}
+
+	return &HashRing{
+		ring: newRing(entries, replicationFactor),
+	}
+}
+
+// Owner returns the member that owns the key, or an empty string if the ring
+// has no members
+func (r *HashRing) Owner(key []byte) string {
+	owner, ok := r.ring.shard(key).(string)
+	if !ok {
+		return ""
+	}
+	return owner
+}
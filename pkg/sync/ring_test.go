@@ -51,3 +51,39 @@ func TestRing_Distribution(t *testing.T) {
 		assert.True(t, math.Abs(float64(hitCount-expectedFrequency)) <= marginOfError*float64(expectedFrequency))
 	}
 }
+
+func TestHashRing_MembershipChange(t *testing.T) {
+	members := []string{"node1", "node2", "node3"}
+	r := NewHashRing(members, 200)
+
+	owners := make(map[string]string)
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprintf("key%d", i)
+		owners[key] = r.Owner([]byte(key))
+		assert.Contains(t, members, owners[key])
+	}
+
+	// Only keys owned by the removed member move
+	r = NewHashRing([]string{"node1", "node3"}, 200)
+	for key, owner := range owners {
+		if owner == "node2" {
+			assert.NotEqual(t, "node2", r.Owner([]byte(key)))
+		} else {
+			assert.Equal(t, owner, r.Owner([]byte(key)))
+		}
+	}
+
+	// Only keys moving to the added member move
+	r = NewHashRing([]string{"node1", "node2", "node3", "node4"}, 200)
+	for key, owner := range owners {
+		newOwner := r.Owner([]byte(key))
+		if newOwner != "node4" {
+			assert.Equal(t, owner, newOwner)
+		}
+	}
+}
+
+func TestHashRing_NoMembers(t *testing.T) {
+	r := NewHashRing(nil, 200)
+	assert.Empty(t, r.Owner([]byte("key")))
+}
@@ -10,8 +10,15 @@ import (
 	code_data "github.com/code-payments/code-server/pkg/code/data"
 )
 
+// Enough SOL to comfortably stay above the minimum subsidizer balance
+const subsidizerAirdropAmount = 1_000_000_000_000 // 1000 SOL
+
 func SetupRandomSubsidizer(t *testing.T, data code_data.Provider) *common.Account {
 	account := NewRandomAccount(t)
 	require.NoError(t, common.InjectTestSubsidizer(context.Background(), data, account))
+
+	_, err := data.RequestBlockchainAirdrop(context.Background(), account.PublicKey().ToBase58(), subsidizerAirdropAmount)
+	require.NoError(t, err)
+
 	return account
 }